@@ -71,6 +71,36 @@ var unRecoverableTable = map[string]map[string]struct{}{
 		"tidb_mdl_view": {},
 
 		"tidb_pitr_id_map": {},
+
+		// the indicators history is keyed by table ID, which is not stable across restores.
+		"analyze_indicators_history": {},
+
+		// the pending global stats merge jobs are keyed by table ID, which is not stable across restores.
+		"stats_global_merge_jobs": {},
+
+		// the stats version migration progress is keyed by table ID, which is not stable across restores.
+		"stats_version_migration": {},
+
+		// the quick refresh baselines are keyed by table ID, which is not stable across restores.
+		"stats_quick_refresh": {},
+
+		// the injected stats are keyed by table ID, which is not stable across restores.
+		"stats_injected": {},
+
+		// the analyze checkpoints are keyed by table ID, which is not stable across restores.
+		"analyze_checkpoints": {},
+
+		// the auto analyze options are keyed by table ID, which is not stable across restores.
+		"auto_analyze_config": {},
+
+		// the stats alerts are the history of the cluster being backed up, which are keyed by table ID.
+		"stats_alerts": {},
+
+		// the quarantined analyze results are keyed by table ID, which is not stable across restores.
+		"analyze_quarantine": {},
+
+		// the global NDV sketches are keyed by table ID, which is not stable across restores.
+		"stats_global_ndv_sketch": {},
 	},
 	"sys": {
 		// replace into view is not supported now
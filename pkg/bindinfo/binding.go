@@ -48,6 +48,8 @@ const (
 	Builtin = "builtin"
 	// History indicate the binding is created from statement summary by plan digest
 	History = "history"
+	// Evolve indicates the binding is promoted from a candidate of the plan baseline after verification.
+	Evolve = "evolve"
 )
 
 // Binding stores the basic bind hint info.
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	utilparser "github.com/pingcap/tidb/pkg/util/parser"
+	"github.com/pingcap/tidb/pkg/util/stmtsummary"
 	stmtsummaryv2 "github.com/pingcap/tidb/pkg/util/stmtsummary/v2"
 	tablefilter "github.com/pingcap/tidb/pkg/util/table-filter"
 	"go.uber.org/zap"
@@ -60,6 +61,29 @@ func (*captureFilter) Leave(in ast.Node) (out ast.Node, ok bool) {
 	return in, true
 }
 
+// isFiltered returns whether the statement is filtered out by the capture blacklist, i.e. it accesses a table in
+// the blacklist, or it's only processed by the users in the blacklist.
+func (cf *captureFilter) isFiltered(stmt ast.StmtNode, bindableStmt *stmtsummary.BindableStmt) bool {
+	if cf.isEmpty() {
+		return false
+	}
+	cf.fail = false
+	cf.currentDB = bindableStmt.Schema
+	stmt.Accept(cf)
+	if cf.fail {
+		return true
+	}
+	if len(cf.users) == 0 {
+		return false
+	}
+	for user := range bindableStmt.Users {
+		if _, ok := cf.users[user]; !ok {
+			return false // some user not in the black-list has processed this stmt
+		}
+	}
+	return true
+}
+
 func (cf *captureFilter) isEmpty() bool {
 	return len(cf.tables) == 0 && len(cf.users) == 0
 }
@@ -136,7 +160,6 @@ func (h *globalBindingHandle) extractCaptureFilterFromStorage() (filter *capture
 func (h *globalBindingHandle) CaptureBaselines() {
 	parser4Capture := parser.New()
 	captureFilter := h.extractCaptureFilterFromStorage()
-	emptyCaptureFilter := captureFilter.isEmpty()
 	bindableStmts := stmtsummaryv2.GetMoreThanCntBindableStmt(captureFilter.frequency)
 	for _, bindableStmt := range bindableStmts {
 		stmt, err := parser4Capture.ParseOneStmt(bindableStmt.Query, bindableStmt.Charset, bindableStmt.Collation)
@@ -147,26 +170,8 @@ func (h *globalBindingHandle) CaptureBaselines() {
 		if insertStmt, ok := stmt.(*ast.InsertStmt); ok && insertStmt.Select == nil {
 			continue
 		}
-		if !emptyCaptureFilter {
-			captureFilter.fail = false
-			captureFilter.currentDB = bindableStmt.Schema
-			stmt.Accept(captureFilter)
-			if captureFilter.fail {
-				continue
-			}
-
-			if len(captureFilter.users) > 0 {
-				filteredByUser := true
-				for user := range bindableStmt.Users {
-					if _, ok := captureFilter.users[user]; !ok {
-						filteredByUser = false // some user not in the black-list has processed this stmt
-						break
-					}
-				}
-				if filteredByUser {
-					continue
-				}
-			}
+		if captureFilter.isFiltered(stmt, bindableStmt) {
+			continue
 		}
 		dbName := utilparser.GetDefaultDB(stmt, bindableStmt.Schema)
 		normalizedSQL, digest := parser.NormalizeDigest(utilparser.RestoreWithDefaultDB(stmt, dbName, bindableStmt.Query))
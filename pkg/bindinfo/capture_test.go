@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/pkg/bindinfo"
 	"github.com/pingcap/tidb/pkg/bindinfo/internal"
 	"github.com/pingcap/tidb/pkg/bindinfo/norm"
@@ -989,3 +990,45 @@ func TestCaptureHints(t *testing.T) {
 		require.Equal(t, res[0][9], sqlDigestWithDB.String())
 	}
 }
+
+func TestEvolveBindings(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, key idx_b(b), key idx_c(c))")
+
+	bindSQL := "SELECT /*+ use_index(@`sel_1` `test`.`t` `idx_c`)*/ * FROM `test`.`t` WHERE `b` = 1 AND `c` > 1"
+	query := "select * from t where b = 1 and c > 1"
+	stmt, err := parser.New().ParseOneStmt(query, "", "")
+	require.NoError(t, err)
+	originalSQL, sqlDigest := parser.NormalizeDigest(utilparser.RestoreWithDefaultDB(stmt, "test", query))
+	tk.MustExec(`insert into mysql.plan_baseline_evolve_tasks (sql_digest, plan_digest, regressed_plan_digest, original_sql,
+		bind_sql, default_db, charset, collation) values (?, 'p1', 'p2', ?, ?, 'test', 'utf8mb4', 'utf8mb4_bin')`,
+		sqlDigest.String(), originalSQL, bindSQL)
+	tk.MustExec(`insert into mysql.plan_baseline_evolve_tasks (sql_digest, plan_digest, regressed_plan_digest, original_sql,
+		bind_sql, default_db, charset, collation) values ('d1', 'p1', 'p2', 'delete from test . t where b = ?',
+		'DELETE /*+ use_index(@sel_1 test.t idx_c) */ FROM test.t WHERE b = 1', 'test', 'utf8mb4', 'utf8mb4_bin')`)
+
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/pkg/bindinfo/mockEvolveAccepted", "return"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/pkg/bindinfo/mockEvolveAccepted"))
+	}()
+	tk.MustExec("admin evolve bindings")
+	tk.MustQuery("select sql_digest, status from mysql.plan_baseline_evolve_tasks order by status").Check(testkit.Rows(
+		sqlDigest.String()+" accepted",
+		"d1 rejected",
+	))
+	rows := tk.MustQuery("show global bindings").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, originalSQL, rows[0][0])
+	require.Equal(t, bindSQL, rows[0][1])
+	require.Equal(t, bindinfo.Evolve, rows[0][8])
+	require.Equal(t, "p1", rows[0][10])
+	tk.MustUseIndex(query, "idx_c(c)")
+
+	// The verified tasks are not evolved again.
+	tk.MustExec("delete from mysql.bind_info where source = 'evolve'")
+	require.NoError(t, dom.BindHandle().LoadFromStorageToCache(true))
+	tk.MustExec("admin evolve bindings")
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+}
@@ -0,0 +1,379 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/bindinfo/internal/logutil"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/hint"
+	utilparser "github.com/pingcap/tidb/pkg/util/parser"
+	"github.com/pingcap/tidb/pkg/util/stmtsummary"
+	stmtsummaryv2 "github.com/pingcap/tidb/pkg/util/stmtsummary/v2"
+	"github.com/pingcap/tidb/pkg/util/timeutil"
+	"go.uber.org/zap"
+)
+
+// The statuses of the tasks in mysql.plan_baseline_evolve_tasks.
+const (
+	evolveTaskPending  = "pending"
+	evolveTaskAccepted = "accepted"
+	evolveTaskRejected = "rejected"
+)
+
+// evolveAcceptFactor is how many times the candidate plan must be faster than the current plan to be accepted,
+// which avoids flipping between the plans of similar latencies.
+const evolveAcceptFactor = 1.5
+
+// regressedStmt is a statement whose latest plan is much slower than one of its earlier plans, which is the
+// candidate of its plan baseline.
+type regressedStmt struct {
+	candidate *stmtsummary.BindableStmt
+	regressed *stmtsummary.BindableStmt
+}
+
+// findRegressedStmts groups the bindable statements by their SQL digests, and finds the ones whose latest plans are
+// slower than the fastest ones of their other plans by more than the ratio.
+func findRegressedStmts(stmts []*stmtsummary.BindableStmt, ratio float64) []regressedStmt {
+	plans := make(map[string][]*stmtsummary.BindableStmt)
+	keys := make([]string, 0, len(stmts))
+	for _, stmt := range stmts {
+		if stmt.SQLDigest == "" || stmt.PlanDigest == "" || stmt.ExecCount <= 0 {
+			continue
+		}
+		key := stmt.Schema + "." + stmt.SQLDigest
+		if _, ok := plans[key]; !ok {
+			keys = append(keys, key)
+		}
+		plans[key] = append(plans[key], stmt)
+	}
+	var result []regressedStmt
+	for _, key := range keys {
+		stmtPlans := plans[key]
+		if len(stmtPlans) < 2 {
+			continue
+		}
+		latest := stmtPlans[0]
+		for _, p := range stmtPlans[1:] {
+			if p.LastSeen.After(latest.LastSeen) {
+				latest = p
+			}
+		}
+		var best *stmtsummary.BindableStmt
+		for _, p := range stmtPlans {
+			// The same plan may be recorded more than once, e.g. in different resource groups.
+			if p.PlanDigest == latest.PlanDigest || p.PlanHint == "" {
+				continue
+			}
+			if best == nil || p.AvgLatency < best.AvgLatency {
+				best = p
+			}
+		}
+		if best == nil || float64(latest.AvgLatency) <= ratio*float64(best.AvgLatency) {
+			continue
+		}
+		result = append(result, regressedStmt{candidate: best, regressed: latest})
+	}
+	return result
+}
+
+// CaptureRegressedBaselines finds the statements whose plans regressed in the statement summary, and records their
+// faster plans as the candidates of the plan baselines in mysql.plan_baseline_evolve_tasks, which are verified and
+// promoted to the bindings by EvolveBaselines.
+func (h *globalBindingHandle) CaptureRegressedBaselines() {
+	ratio := variable.DefTiDBPlanBaselineRegressionRatio
+	_ = h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		val, err := sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBPlanBaselineRegressionRatio)
+		if err != nil {
+			return err
+		}
+		if r, err := strconv.ParseFloat(val, 64); err == nil {
+			ratio = r
+		}
+		return nil
+	})
+	parser4Capture := parser.New()
+	captureFilter := h.extractCaptureFilterFromStorage()
+	bindableStmts := stmtsummaryv2.GetMoreThanCntBindableStmt(captureFilter.frequency)
+	for _, regressed := range findRegressedStmts(bindableStmts, ratio) {
+		candidate := regressed.candidate
+		stmt, err := parser4Capture.ParseOneStmt(candidate.Query, candidate.Charset, candidate.Collation)
+		if err != nil {
+			logutil.BindLogger().Debug("parse SQL failed in regressed baseline capture", zap.String("SQL", candidate.Query), zap.Error(err))
+			continue
+		}
+		if insertStmt, ok := stmt.(*ast.InsertStmt); ok && insertStmt.Select == nil {
+			continue
+		}
+		if captureFilter.isFiltered(stmt, candidate) {
+			continue
+		}
+		dbName := utilparser.GetDefaultDB(stmt, candidate.Schema)
+		normalizedSQL, digest := parser.NormalizeDigest(utilparser.RestoreWithDefaultDB(stmt, dbName, candidate.Query))
+		if r := h.getCache().GetBinding(digest.String()); HasAvailableBinding(r) {
+			continue
+		}
+		bindSQL := GenerateBindingSQL(stmt, candidate.PlanHint, true, dbName)
+		if bindSQL == "" {
+			continue
+		}
+		err = h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+			// The rejected candidates are kept, so they are not verified again.
+			_, err := exec(sctx, `INSERT IGNORE INTO mysql.plan_baseline_evolve_tasks
+				(sql_digest, plan_digest, regressed_plan_digest, original_sql, bind_sql, default_db, charset, collation,
+				candidate_latency, regressed_latency) VALUES (%?, %?, %?, %?, %?, %?, %?, %?, %?, %?)`,
+				digest.String(), candidate.PlanDigest, regressed.regressed.PlanDigest, normalizedSQL, bindSQL, dbName,
+				candidate.Charset, candidate.Collation, candidate.AvgLatency.Nanoseconds(), regressed.regressed.AvgLatency.Nanoseconds())
+			return err
+		})
+		if err != nil {
+			logutil.BindLogger().Debug("record evolve task failed in regressed baseline capture", zap.String("SQL", candidate.Query), zap.Error(err))
+		}
+	}
+}
+
+type evolveTask struct {
+	sqlDigest   string
+	planDigest  string
+	originalSQL string
+	bindSQL     string
+	db          string
+	charset     string
+	collation   string
+}
+
+// EvolveBaselines verifies the pending candidates of the plan baselines by running their statements with the current
+// plans and the candidate plans, and promotes the candidates to the bindings if they are fast enough. Only the SELECT
+// statements are verified since the others may modify the data. The candidates are only verified between
+// tidb_evolve_plan_task_start_time and tidb_evolve_plan_task_end_time unless it's forced.
+func (h *globalBindingHandle) EvolveBaselines(force bool) error {
+	var tasks []evolveTask
+	var maxTime time.Duration
+	var startTime, endTime time.Time
+	err := h.callWithSCtx(false, func(sctx sessionctx.Context) (err error) {
+		maxTime, startTime, endTime, err = getEvolveParameters(sctx)
+		if err != nil {
+			return err
+		}
+		rows, _, err := execRows(sctx, `SELECT sql_digest, plan_digest, original_sql, bind_sql, default_db, charset, collation
+			FROM mysql.plan_baseline_evolve_tasks WHERE status = %? ORDER BY create_time`, evolveTaskPending)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			tasks = append(tasks, evolveTask{
+				sqlDigest:   row.GetString(0),
+				planDigest:  row.GetString(1),
+				originalSQL: row.GetString(2),
+				bindSQL:     row.GetString(3),
+				db:          row.GetString(4),
+				charset:     row.GetString(5),
+				collation:   row.GetString(6),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if !force && !timeutil.WithinDayTimePeriod(startTime, endTime, time.Now()) {
+			return nil
+		}
+		if err := h.evolveTask(task, maxTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evolveTask verifies the candidate of the task, creates the binding for it if it's accepted, and records the result.
+func (h *globalBindingHandle) evolveTask(task evolveTask, maxTime time.Duration) error {
+	var status, msg string
+	err := h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		status, msg = h.verifyCandidate(sctx, task, maxTime)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if status == evolveTaskAccepted {
+		binding := Binding{
+			OriginalSQL: task.originalSQL,
+			Db:          task.db,
+			BindSQL:     task.bindSQL,
+			Status:      Enabled,
+			Charset:     task.charset,
+			Collation:   task.collation,
+			Source:      Evolve,
+			SQLDigest:   task.sqlDigest,
+			PlanDigest:  task.planDigest,
+		}
+		// We don't need to pass the `sctx` because the BindSQL has been validated already.
+		if err := h.CreateGlobalBinding(nil, []*Binding{&binding}); err != nil {
+			status, msg = evolveTaskRejected, fmt.Sprintf("create binding failed: %v", err)
+		}
+	}
+	logutil.BindLogger().Info("plan baseline evolved", zap.String("sqlDigest", task.sqlDigest),
+		zap.String("planDigest", task.planDigest), zap.String("status", status), zap.String("message", msg))
+	return h.callWithSCtx(false, func(sctx sessionctx.Context) error {
+		_, err := exec(sctx, `UPDATE mysql.plan_baseline_evolve_tasks SET status = %?, message = %?, update_time = NOW(6)
+			WHERE sql_digest = %? AND plan_digest = %?`, status, msg, task.sqlDigest, task.planDigest)
+		return err
+	})
+}
+
+// verifyCandidate runs the statement with the current plan and the candidate plan, and returns whether the candidate
+// is accepted with the reason.
+func (*globalBindingHandle) verifyCandidate(sctx sessionctx.Context, task evolveTask, maxTime time.Duration) (status, msg string) {
+	stmt, err := parser.New().ParseOneStmt(task.bindSQL, task.charset, task.collation)
+	if err != nil {
+		return evolveTaskRejected, fmt.Sprintf("parse binding failed: %v", err)
+	}
+	if _, ok := stmt.(*ast.SelectStmt); !ok {
+		return evolveTaskRejected, "only the SELECT statements are verified"
+	}
+	// The binding SQL without the hints is run with the current plan.
+	hint.BindHint(stmt, &hint.HintsSet{})
+	currentSQL := utilparser.RestoreWithDefaultDB(stmt, task.db, "")
+
+	failpoint.Inject("mockEvolveAccepted", func() {
+		failpoint.Return(evolveTaskAccepted, "mock accepted")
+	})
+	currentTime, err := runWithTimeLimit(sctx, currentSQL, maxTime)
+	if err != nil {
+		return evolveTaskRejected, fmt.Sprintf("run the current plan failed: %v", err)
+	}
+	// The candidate is not accepted unless it's faster enough than the current plan, so it's not waited longer.
+	candidateMaxTime := maxTime
+	if currentTime >= 0 {
+		candidateMaxTime = time.Duration(float64(currentTime) / evolveAcceptFactor)
+	}
+	candidateTime, err := runWithTimeLimit(sctx, task.bindSQL, candidateMaxTime)
+	if err != nil {
+		return evolveTaskRejected, fmt.Sprintf("run the candidate plan failed: %v", err)
+	}
+	if candidateTime < 0 {
+		return evolveTaskRejected, fmt.Sprintf("the candidate plan is not faster than the current plan, current: %v", formatEvolveTime(currentTime))
+	}
+	return evolveTaskAccepted, fmt.Sprintf("current: %v, candidate: %v", formatEvolveTime(currentTime), formatEvolveTime(candidateTime))
+}
+
+func formatEvolveTime(d time.Duration) string {
+	if d < 0 {
+		return "timeout"
+	}
+	return d.String()
+}
+
+// getEvolveParameters returns the max time of running a statement in the verification and the time window of the
+// evolution.
+func getEvolveParameters(sctx sessionctx.Context) (maxTime time.Duration, startTime, endTime time.Time, err error) {
+	vars := sctx.GetSessionVars().GlobalVarsAccessor
+	val, err := vars.GetGlobalSysVar(variable.TiDBEvolvePlanTaskMaxTime)
+	if err != nil {
+		return 0, startTime, endTime, err
+	}
+	maxTimeSec, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, startTime, endTime, err
+	}
+	maxTime = time.Duration(math.MaxInt64)
+	// The non-positive max time means no limit.
+	if maxTimeSec > 0 && maxTimeSec < int64(math.MaxInt64/time.Second) {
+		maxTime = time.Duration(maxTimeSec) * time.Second
+	}
+	val, err = vars.GetGlobalSysVar(variable.TiDBEvolvePlanTaskStartTime)
+	if err != nil {
+		return 0, startTime, endTime, err
+	}
+	startTime, err = time.ParseInLocation(variable.FullDayTimeFormat, val, time.UTC)
+	if err != nil {
+		return 0, startTime, endTime, err
+	}
+	val, err = vars.GetGlobalSysVar(variable.TiDBEvolvePlanTaskEndTime)
+	if err != nil {
+		return 0, startTime, endTime, err
+	}
+	endTime, err = time.ParseInLocation(variable.FullDayTimeFormat, val, time.UTC)
+	return maxTime, startTime, endTime, err
+}
+
+// runWithTimeLimit runs the SQL and returns its running time, or -1 if it doesn't finish within the max time.
+func runWithTimeLimit(sctx sessionctx.Context, sql string, maxTime time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithCancel(kv.WithInternalSourceType(context.Background(), kv.InternalTxnBindInfo))
+	defer cancel()
+	timer := time.NewTimer(maxTime)
+	defer timer.Stop()
+	resultCh := make(chan error, 1)
+	startTime := time.Now()
+	go runSQL(ctx, sctx, sql, resultCh)
+	select {
+	case err := <-resultCh:
+		if err != nil {
+			return 0, err
+		}
+		return time.Since(startTime), nil
+	case <-timer.C:
+		cancel()
+	}
+	// Wait for the SQL to exit, so the session is not used by two goroutines.
+	<-resultCh
+	return -1, nil
+}
+
+func runSQL(ctx context.Context, sctx sessionctx.Context, sql string, resultCh chan<- error) {
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 4096)
+			stackSize := runtime.Stack(buf, false)
+			resultCh <- errors.Errorf("run sql panicked: %v, stack: %s", r, buf[:stackSize])
+		}
+	}()
+	rs, err := sctx.GetSQLExecutor().ExecuteInternal(ctx, sql)
+	if err != nil {
+		if rs != nil {
+			terror.Call(rs.Close)
+		}
+		resultCh <- err
+		return
+	}
+	if rs == nil {
+		resultCh <- nil
+		return
+	}
+	chk := rs.NewChunk(nil)
+	for {
+		err = rs.Next(ctx, chk)
+		if err != nil || chk.NumRows() == 0 {
+			break
+		}
+	}
+	terror.Call(rs.Close)
+	resultCh <- err
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/util/stmtsummary"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRegressedStmts(t *testing.T) {
+	now := time.Now()
+	newStmt := func(schema, sqlDigest, planDigest string, latency time.Duration, lastSeen time.Time) *stmtsummary.BindableStmt {
+		return &stmtsummary.BindableStmt{
+			Schema:     schema,
+			PlanHint:   "use_index(@`sel_1` `test`.`t` `" + planDigest + "`)",
+			SQLDigest:  sqlDigest,
+			PlanDigest: planDigest,
+			ExecCount:  10,
+			AvgLatency: latency,
+			LastSeen:   lastSeen,
+		}
+	}
+	stmts := []*stmtsummary.BindableStmt{
+		// The latest plan of s1 is 10 times slower than the fastest one.
+		newStmt("test", "s1", "p1", time.Millisecond, now.Add(-time.Hour)),
+		newStmt("test", "s1", "p2", 5*time.Millisecond, now.Add(-time.Minute)),
+		newStmt("test", "s1", "p3", 10*time.Millisecond, now),
+		// The latest plan of s2 is not slow enough.
+		newStmt("test", "s2", "p1", time.Millisecond, now.Add(-time.Hour)),
+		newStmt("test", "s2", "p2", 2*time.Millisecond, now),
+		// s3 has only one plan in the schema.
+		newStmt("test", "s3", "p1", time.Millisecond, now.Add(-time.Hour)),
+		newStmt("test2", "s3", "p2", 10*time.Millisecond, now),
+		// The same plan of s4 is recorded twice.
+		newStmt("test", "s4", "p1", 10*time.Millisecond, now.Add(-time.Hour)),
+		newStmt("test", "s4", "p1", time.Millisecond, now),
+	}
+	result := findRegressedStmts(stmts, 2)
+	require.Len(t, result, 1)
+	require.Equal(t, "s1", result[0].candidate.SQLDigest)
+	require.Equal(t, "p1", result[0].candidate.PlanDigest)
+	require.Equal(t, "p3", result[0].regressed.PlanDigest)
+
+	// The candidate without the plan hint can't be bound.
+	stmts[0].PlanHint = ""
+	result = findRegressedStmts(stmts, 2)
+	require.Len(t, result, 1)
+	require.Equal(t, "p2", result[0].candidate.PlanDigest)
+	require.Empty(t, findRegressedStmts(stmts, 3))
+}
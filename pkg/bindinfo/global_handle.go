@@ -105,6 +105,12 @@ type GlobalBindingHandle interface {
 	// CaptureBaselines is used to automatically capture plan baselines.
 	CaptureBaselines()
 
+	// CaptureRegressedBaselines records the faster plans of the regressed statements as the candidates of the plan baselines.
+	CaptureRegressedBaselines()
+
+	// EvolveBaselines verifies the candidates of the plan baselines and promotes the accepted ones to the bindings.
+	EvolveBaselines(force bool) error
+
 	variable.Statistics
 }
 
@@ -83,6 +83,10 @@ const (
 	DefStatsLoadQueueSizeLimit = 1
 	// DefMaxOfStatsLoadQueueSizeLimit is maximum limitation of the size of stats-load request queue
 	DefMaxOfStatsLoadQueueSizeLimit = 100000
+	// StatsCacheEvictionPolicyLFU evicts the least frequently used stats from the stats cache.
+	StatsCacheEvictionPolicyLFU = "lfu"
+	// StatsCacheEvictionPolicyLRU evicts the least recently used stats from the stats cache.
+	StatsCacheEvictionPolicyLRU = "lru"
 	// DefDDLSlowOprThreshold sets log DDL operations whose execution time exceeds the threshold value.
 	DefDDLSlowOprThreshold = 300
 	// DefExpensiveQueryTimeThreshold indicates the time threshold of expensive query.
@@ -723,6 +727,16 @@ type Performance struct {
 	AnalyzePartitionConcurrencyQuota  uint `toml:"analyze-partition-concurrency-quota" json:"analyze-partition-concurrency-quota"`
 	PlanReplayerDumpWorkerConcurrency uint `toml:"plan-replayer-dump-worker-concurrency" json:"plan-replayer-dump-worker-concurrency"`
 	EnableStatsCacheMemQuota          bool `toml:"enable-stats-cache-mem-quota" json:"enable-stats-cache-mem-quota"`
+	// StatsCacheEvictionPolicy is the policy to evict the stats from the stats cache when the memory quota is exceeded.
+	// It can be "lfu" or "lru", and only takes effect when enable-stats-cache-mem-quota is true.
+	StatsCacheEvictionPolicy string `toml:"stats-cache-eviction-policy" json:"stats-cache-eviction-policy"`
+	// StatsReadOnly indicates whether the instance only serves the stale reads. If it's true, the instance maintains
+	// its stats cache purely by loading the stats at the stale read timestamp, and never writes the stats, i.e. it
+	// doesn't dump the stats delta or the column stats usage, run analyze or become the stats owner.
+	StatsReadOnly bool `toml:"stats-read-only" json:"stats-read-only"`
+	// StatsReadStaleness is the staleness of the stats read by the stats read-only instance, e.g. "5s".
+	// The stats are read at the latest timestamp if it's 0.
+	StatsReadStaleness string `toml:"stats-read-staleness" json:"stats-read-staleness"`
 	// The following items are deprecated. We need to keep them here temporarily
 	// to support the upgrade process. They can be removed in future.
 
@@ -1004,6 +1018,8 @@ var defaultConf = Config{
 		AnalyzePartitionConcurrencyQuota:  16,
 		PlanReplayerDumpWorkerConcurrency: 1,
 		EnableStatsCacheMemQuota:          true,
+		StatsCacheEvictionPolicy:          StatsCacheEvictionPolicyLFU,
+		StatsReadStaleness:                "5s",
 		RunAutoAnalyze:                    true,
 		EnableLoadFMSketch:                false,
 		LiteInitStats:                     true,
@@ -1379,6 +1395,12 @@ func (c *Config) Valid() error {
 	if c.Performance.StatsLoadConcurrency < DefStatsLoadConcurrencyLimit || c.Performance.StatsLoadConcurrency > DefMaxOfStatsLoadConcurrencyLimit {
 		return fmt.Errorf("stats-load-concurrency should be [%d, %d]", DefStatsLoadConcurrencyLimit, DefMaxOfStatsLoadConcurrencyLimit)
 	}
+	if c.Performance.StatsCacheEvictionPolicy != StatsCacheEvictionPolicyLFU && c.Performance.StatsCacheEvictionPolicy != StatsCacheEvictionPolicyLRU {
+		return fmt.Errorf("stats-cache-eviction-policy should be %s or %s", StatsCacheEvictionPolicyLFU, StatsCacheEvictionPolicyLRU)
+	}
+	if staleness, err := time.ParseDuration(c.Performance.StatsReadStaleness); err != nil || staleness < 0 {
+		return fmt.Errorf("stats-read-staleness should be a non-negative duration, e.g. 5s")
+	}
 	if c.Performance.StatsLoadQueueSize < DefStatsLoadQueueSizeLimit || c.Performance.StatsLoadQueueSize > DefMaxOfStatsLoadQueueSizeLimit {
 		return fmt.Errorf("stats-load-queue-size should be [%d, %d]", DefStatsLoadQueueSizeLimit, DefMaxOfStatsLoadQueueSizeLimit)
 	}
@@ -1356,6 +1356,32 @@ func TestStatsLoadLimit(t *testing.T) {
 	checkQueueSizeValid(DefMaxOfStatsLoadQueueSizeLimit+1, false)
 }
 
+func TestStatsCacheEvictionPolicy(t *testing.T) {
+	conf := NewConfig()
+	require.Equal(t, StatsCacheEvictionPolicyLFU, conf.Performance.StatsCacheEvictionPolicy)
+	checkPolicyValid := func(policy string, shouldBeValid bool) {
+		conf.Performance.StatsCacheEvictionPolicy = policy
+		require.Equal(t, shouldBeValid, conf.Valid() == nil)
+	}
+	checkPolicyValid(StatsCacheEvictionPolicyLFU, true)
+	checkPolicyValid(StatsCacheEvictionPolicyLRU, true)
+	checkPolicyValid("fifo", false)
+}
+
+func TestStatsReadStaleness(t *testing.T) {
+	conf := NewConfig()
+	require.False(t, conf.Performance.StatsReadOnly)
+	require.Equal(t, "5s", conf.Performance.StatsReadStaleness)
+	checkStalenessValid := func(staleness string, shouldBeValid bool) {
+		conf.Performance.StatsReadStaleness = staleness
+		require.Equal(t, shouldBeValid, conf.Valid() == nil)
+	}
+	checkStalenessValid("0s", true)
+	checkStalenessValid("1m", true)
+	checkStalenessValid("-5s", false)
+	checkStalenessValid("5", false)
+}
+
 func TestGetGlobalKeyspaceName(t *testing.T) {
 	conf := NewConfig()
 	require.Empty(t, conf.KeyspaceName)
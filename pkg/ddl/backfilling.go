@@ -748,6 +748,13 @@ func (dc *ddlCtx) runAddIndexInLocalIngestMode(
 	defer sessPool.Put(sctx)
 	avgRowSize := estimateTableRowSize(ctx, dc.store, sctx.GetRestrictedSQLExecutor(), t)
 
+	// The stats of the indexes are built from the samples of the backfill, unless it's resumed from the checkpoint,
+	// in which case only a part of the rows are read.
+	var statsCollector *ingestIndexStatsCollector
+	if cpMgr == nil || len(cpMgr.NextKeyToProcess()) == 0 {
+		statsCollector = newIngestIndexStatsCollector(t.Meta(), indexInfos)
+	}
+
 	engines, err := bcCtx.Register(indexIDs, uniques, t)
 	if err != nil {
 		logutil.DDLIngestLogger().Error("cannot register new engine",
@@ -772,6 +779,7 @@ func (dc *ddlCtx) runAddIndexInLocalIngestMode(
 		importConc,
 		cpMgr,
 		rowCntListener,
+		statsCollector,
 	)
 	if err != nil {
 		return err
@@ -790,7 +798,14 @@ func (dc *ddlCtx) runAddIndexInLocalIngestMode(
 	if cpMgr != nil {
 		cpMgr.AdvanceWatermark(true, true)
 	}
-	return bcCtx.FinishAndUnregisterEngines(ingest.OptCleanData | ingest.OptCheckDup)
+	err = bcCtx.FinishAndUnregisterEngines(ingest.OptCleanData | ingest.OptCheckDup)
+	if err != nil {
+		return err
+	}
+	if statsCollector != nil {
+		dc.setIngestIndexStats(job.ID, statsCollector)
+	}
+	return nil
 }
 
 func adjustWorkerPoolSize(ctx context.Context, pipe *operator.AsyncPipeline, job *model.Job, avgRowSize int) {
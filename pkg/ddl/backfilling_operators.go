@@ -161,6 +161,7 @@ func NewAddIndexIngestPipeline(
 	concurrency int,
 	cpMgr *ingest.CheckpointManager,
 	rowCntListener RowCountListener,
+	statsCollector *ingestIndexStatsCollector,
 ) (*operator.AsyncPipeline, error) {
 	indexes := make([]table.Index, 0, len(idxInfos))
 	for _, idxInfo := range idxInfos {
@@ -184,7 +185,7 @@ func NewAddIndexIngestPipeline(
 	scanOp := NewTableScanOperator(ctx, sessPool, copCtx, srcChkPool, readerCnt, cpMgr,
 		reorgMeta.GetBatchSizeOrDefault(int(variable.GetDDLReorgBatchSize())), rm)
 	ingestOp := NewIndexIngestOperator(ctx, copCtx, backendCtx, sessPool,
-		tbl, indexes, engines, srcChkPool, writerCnt, reorgMeta, cpMgr, rowCntListener)
+		tbl, indexes, engines, srcChkPool, writerCnt, reorgMeta, cpMgr, rowCntListener, statsCollector)
 	sinkOp := newIndexWriteResultSink(ctx, backendCtx, tbl, indexes, cpMgr, rowCntListener)
 
 	operator.Compose[TableScanTask](srcOp, scanOp)
@@ -724,6 +725,7 @@ func NewIndexIngestOperator(
 	reorgMeta *model.DDLReorgMeta,
 	cpMgr *ingest.CheckpointManager,
 	rowCntListener RowCountListener,
+	statsCollector *ingestIndexStatsCollector,
 ) *IndexIngestOperator {
 	writerCfg := getLocalWriterConfig(len(indexes), concurrency)
 
@@ -761,6 +763,8 @@ func NewIndexIngestOperator(
 					writers:      writers,
 					srcChunkPool: srcChunkPool,
 					reorgMeta:    reorgMeta,
+
+					statsCollector: statsCollector,
 				},
 				indexIDs:       indexIDs,
 				backendCtx:     backendCtx,
@@ -845,6 +849,8 @@ type indexIngestBaseWorker struct {
 	srcChunkPool *sync.Pool
 	// only available in global sort
 	totalCount *atomic.Int64
+	// statsCollector samples the written index values, it's only available in local ingest mode.
+	statsCollector *ingestIndexStatsCollector
 }
 
 func (w *indexIngestBaseWorker) HandleTask(rs IndexRecordChunk) (IndexWriteResult, error) {
@@ -921,7 +927,7 @@ func (w *indexIngestBaseWorker) WriteChunk(rs *IndexRecordChunk) (count int, nex
 	oprStartTime := time.Now()
 	vars := w.se.GetSessionVars()
 	sc := vars.StmtCtx
-	cnt, lastHandle, err := writeChunkToLocal(w.ctx, w.writers, w.indexes, w.copCtx, sc.TimeZone(), sc.ErrCtx(), vars.GetWriteStmtBufs(), rs.Chunk)
+	cnt, lastHandle, err := writeChunkToLocal(w.ctx, w.writers, w.indexes, w.copCtx, sc.TimeZone(), sc.ErrCtx(), vars.GetWriteStmtBufs(), rs.Chunk, w.statsCollector)
 	if err != nil || cnt == 0 {
 		return 0, nil, err
 	}
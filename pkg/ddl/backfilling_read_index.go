@@ -246,6 +246,7 @@ func (r *readIndexExecutor) buildLocalStorePipeline(
 		concurrency,
 		nil,
 		rowCntListener,
+		nil,
 	)
 }
 
@@ -880,6 +880,17 @@ func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo) err
 				return errors.Trace(dbterror.ErrOptOnTemporaryTable.GenWithStackByArgs("pre split regions"))
 			}
 			tbInfo.PreSplitRegions = op.UintValue
+		case ast.TableOptionAutoAnalyzeCoolDown:
+			if tbInfo.StatsOptions == nil {
+				tbInfo.StatsOptions = model.NewStatsOptions()
+			}
+			tbInfo.StatsOptions.AutoAnalyzeCoolDown = min(op.UintValue, variable.MaxAutoAnalyzeCoolDown)
+		case ast.TableOptionStatsAutoRecalc:
+			if tbInfo.StatsOptions == nil {
+				tbInfo.StatsOptions = model.NewStatsOptions()
+			}
+			// STATS_AUTO_RECALC=1 and DEFAULT both follow tidb_enable_auto_analyze.
+			tbInfo.StatsOptions.AutoRecalc = op.Default || op.UintValue == 1
 		case ast.TableOptionCharset, ast.TableOptionCollate:
 			// We don't handle charset and collate here since they're handled in `GetCharsetAndCollateInTableOption`.
 		case ast.TableOptionPlacementPolicy:
@@ -865,3 +865,55 @@ func TestCreateConstraintForTable(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, tk.ResultSetToResult(rs, "").Rows()[0][0], "t1")
 }
+
+func TestDropRange(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustExec("create table t (id bigint primary key clustered, v int)")
+	for i := 1; i <= 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i))
+	}
+	tk.MustExec("alter table t drop range where id between 5 and 10")
+	tk.MustExec("alter table t drop range where id >= 15 and 18 > id")
+	tk.MustQuery("select id from t").Check(testkit.Rows("1", "2", "3", "4", "11", "12", "13", "14", "18", "19", "20"))
+	// The empty range is a no-op.
+	tk.MustExec("alter table t drop range where id > 3 and id < 4")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("11"))
+	tk.MustQuery("select count(*) from t where v between 1 and 20").Check(testkit.Rows("11"))
+
+	// The bounds must be converted to the type of the column exactly.
+	tk.MustGetErrCode("alter table t drop range where id between 1.5 and 3", errno.ErrUnsupportedDropRange)
+	tk.MustGetErrCode("alter table t drop range where v between 1 and 3", errno.ErrUnsupportedDropRange)
+	tk.MustGetErrCode("alter table t drop range where id between 1 and 3 or id = 5", errno.ErrUnsupportedDropRange)
+	tk.MustGetErrCode("alter table t drop range where id between 1 and 3, add column c int", errno.ErrUnsupportedDropRange)
+	tk.MustExec("alter table t add index idx(v)")
+	tk.MustGetErrCode("alter table t drop range where id between 1 and 3", errno.ErrUnsupportedDropRange)
+	tk.MustExec("create table t_nonclustered (id int primary key nonclustered)")
+	tk.MustGetErrCode("alter table t_nonclustered drop range where id between 1 and 3", errno.ErrUnsupportedDropRange)
+	tk.MustExec("create table t_child (id int primary key, pid bigint, foreign key (pid) references t(id))")
+	tk.MustExec("alter table t drop index idx")
+	tk.MustGetErrCode("alter table t drop range where id between 1 and 3", errno.ErrUnsupportedDropRange)
+
+	// The unsigned handles across math.MaxInt64.
+	tk.MustExec("create table t_unsigned (id bigint unsigned primary key clustered)")
+	tk.MustExec("insert into t_unsigned values (1), (9223372036854775806), (9223372036854775807), (9223372036854775808), (18446744073709551615)")
+	tk.MustExec("alter table t_unsigned drop range where id between 9223372036854775807 and 18446744073709551614")
+	tk.MustQuery("select id from t_unsigned order by id").Check(testkit.Rows("1", "9223372036854775806", "18446744073709551615"))
+
+	// The common handle.
+	tk.MustExec("create table t_date (d date primary key clustered, v int)")
+	tk.MustExec("insert into t_date values ('2024-01-01', 1), ('2024-01-02', 2), ('2024-01-03', 3), ('2024-01-04', 4)")
+	tk.MustExec("alter table t_date drop range where d >= '2024-01-02' and d < '2024-01-04'")
+	tk.MustQuery("select v from t_date order by d").Check(testkit.Rows("1", "4"))
+	tk.MustExec("create table t_str (s varchar(10) primary key clustered)")
+	tk.MustGetErrCode("alter table t_str drop range where s between 'a' and 'b'", errno.ErrUnsupportedDropRange)
+
+	// The rows are deleted in all the partitions.
+	tk.MustExec("create table t_part (id int primary key) partition by hash(id) partitions 3")
+	tk.MustExec("insert into t_part values (1), (2), (3), (4), (5), (6), (7), (8), (9)")
+	tk.MustExec("alter table t_part drop range where id between 2 and 4")
+	tk.MustQuery("select id from t_part order by id").Check(testkit.Rows("1", "5", "6", "7", "8", "9"))
+	tk.MustQuery("select job_type from information_schema.ddl_jobs where table_name = 't_part' order by job_id desc limit 1").Check(testkit.Rows("drop range"))
+}
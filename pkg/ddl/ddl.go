@@ -20,6 +20,7 @@ package ddl
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -360,6 +361,12 @@ type ddlCtx struct {
 	// reorgCtx is used for reorganization.
 	reorgCtx reorgContexts
 
+	ingestIndexStats struct {
+		sync.Mutex
+		// collectors maps job ID to the samples of the indexes collected by its ingest backfill.
+		collectors map[int64]*ingestIndexStatsCollector
+	}
+
 	jobCtx struct {
 		sync.RWMutex
 		// jobCtxMap maps job ID to job's ctx.
@@ -577,8 +584,8 @@ func (d *ddl) RegisterStatsHandle(h *handle.Handle) {
 
 const noSubJob int64 = -1 // noSubJob indicates the event is not a merged ddl.
 
-// asyncNotifyEvent will notify the ddl event to outside world, say statistic handle. When the channel is full, we may
-// give up notify and log it.
+// asyncNotifyEvent will notify the ddl event to outside world, say statistic handle. The event is persisted in the
+// same transaction of the DDL job, and delivered to the subscribers by the DDL notifier.
 // subJobID is used to identify the sub job in a merged ddl, such as create tables, should pass noSubJob(-1) if not a merged ddl.
 func asyncNotifyEvent(jobCtx *jobContext, e *notifier.SchemaChangeEvent, job *model.Job, subJobID int64, sctx *sess.Session) error {
 	// skip notify for system databases, system databases are expected to change at
@@ -588,8 +595,21 @@ func asyncNotifyEvent(jobCtx *jobContext, e *notifier.SchemaChangeEvent, job *mo
 		return nil
 	}
 
+	// The events of the sub-jobs of a multi-schema change are published together
+	// when the whole job is done, so the subscribers can handle them atomically.
+	if job.MultiSchemaInfo != nil && subJobID == noSubJob && e.GetType() != model.ActionMultiSchemaChange {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		job.MultiSchemaInfo.SchemaChangeEvents = append(job.MultiSchemaInfo.SchemaChangeEvents, data)
+		return nil
+	}
+
+	// The events are delivered to the subscribers by the DDL notifier, while the
+	// channel is only used by the tests to wait for the handling of the events.
 	ch := jobCtx.oldDDLCtx.ddlEventCh
-	if ch != nil {
+	if intest.InTest && ch != nil {
 	forLoop:
 		for i := 0; i < 10; i++ {
 			select {
@@ -606,9 +626,6 @@ func asyncNotifyEvent(jobCtx *jobContext, e *notifier.SchemaChangeEvent, job *mo
 	failpoint.Inject("asyncNotifyEventError", func() {
 		failpoint.Return(errors.New("mock publish event error"))
 	})
-	if subJobID == noSubJob && job.MultiSchemaInfo != nil {
-		subJobID = int64(job.MultiSchemaInfo.Seq)
-	}
 	err := notifier.PubSchemeChangeToStore(
 		jobCtx.stepCtx,
 		sctx,
@@ -683,6 +700,7 @@ func newDDL(ctx context.Context, options ...Option) (*ddl, *executor) {
 	}
 	ddlCtx.reorgCtx.reorgCtxMap = make(map[int64]*reorgCtx)
 	ddlCtx.jobCtx.jobCtxMap = make(map[int64]*ReorgContext)
+	ddlCtx.ingestIndexStats.collectors = make(map[int64]*ingestIndexStatsCollector)
 	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnDDL)
 	ddlCtx.ctx, ddlCtx.cancel = context.WithCancel(ctx)
 
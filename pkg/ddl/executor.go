@@ -1714,6 +1714,13 @@ func (e *executor) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt
 			return dbterror.ErrOptOnCacheTable.GenWithStackByArgs("Alter Table")
 		}
 	}
+	if len(validSpecs) > 1 {
+		for _, spec := range validSpecs {
+			if spec.Tp == ast.AlterTableDropRange {
+				return dbterror.ErrUnsupportedDropRange.GenWithStackByArgs("it can't be used with other ALTER TABLE specifications")
+			}
+		}
+	}
 	if isMultiSchemaChanges(validSpecs) && (sctx.GetSessionVars().EnableRowLevelChecksum || variable.EnableRowLevelChecksum.Load()) {
 		return dbterror.ErrRunMultiSchemaChanges.GenWithStack("Unsupported multi schema change when row level checksum is enabled")
 	}
@@ -1857,6 +1864,12 @@ func (e *executor) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt
 				case ast.TableOptionComment:
 					spec.Comment = opt.StrValue
 					err = e.AlterTableComment(sctx, ident, spec)
+				case ast.TableOptionAutoAnalyzeCoolDown:
+					coolDown := min(opt.UintValue, variable.MaxAutoAnalyzeCoolDown)
+					err = e.AlterTableStatsOptions(sctx, ident, &model.AlterTableStatsOptionsArgs{AutoAnalyzeCoolDown: &coolDown})
+				case ast.TableOptionStatsAutoRecalc:
+					autoRecalc := opt.Default || opt.UintValue == 1
+					err = e.AlterTableStatsOptions(sctx, ident, &model.AlterTableStatsOptionsArgs{AutoRecalc: &autoRecalc})
 				case ast.TableOptionCharset, ast.TableOptionCollate:
 					// GetCharsetAndCollateInTableOption will get the last charset and collate in the options,
 					// so it should be handled only once.
@@ -1946,6 +1959,8 @@ func (e *executor) AlterTable(ctx context.Context, sctx sessionctx.Context, stmt
 		case ast.AlterTableRemoveTTL:
 			// the parser makes sure we have only one `ast.AlterTableRemoveTTL` in an alter statement
 			err = e.AlterTableRemoveTTL(sctx, ident)
+		case ast.AlterTableDropRange:
+			err = e.DropRange(sctx, ident, spec)
 		default:
 			err = errors.Trace(dbterror.ErrUnsupportedAlterTableSpec)
 		}
@@ -3521,6 +3536,28 @@ func (e *executor) AlterTableComment(ctx sessionctx.Context, ident ast.Ident, sp
 	return errors.Trace(err)
 }
 
+// AlterTableStatsOptions updates the stats options of the table which are set in the args.
+func (e *executor) AlterTableStatsOptions(ctx sessionctx.Context, ident ast.Ident, args *model.AlterTableStatsOptionsArgs) error {
+	schema, tb, err := e.getSchemaAndTableByIdent(ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		Version:        model.GetJobVerInUse(),
+		SchemaID:       schema.ID,
+		TableID:        tb.Meta().ID,
+		SchemaName:     schema.Name.L,
+		TableName:      tb.Meta().Name.L,
+		Type:           model.ActionAlterTableStatsOptions,
+		BinlogInfo:     &model.HistoryInfo{},
+		CDCWriteSource: ctx.GetSessionVars().CDCWriteSource,
+		SQLMode:        ctx.GetSessionVars().SQLMode,
+	}
+	err = e.doDDLJob2(ctx, job, args)
+	return errors.Trace(err)
+}
+
 // AlterTableAutoIDCache updates the table comment information.
 func (e *executor) AlterTableAutoIDCache(ctx sessionctx.Context, ident ast.Ident, newCache int64) error {
 	schema, tb, err := e.getSchemaAndTableByIdent(ident)
@@ -3817,10 +3854,6 @@ func (e *executor) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ide
 	if !ctx.GetSessionVars().EnableExtendedStats {
 		return errors.New("Extended statistics feature is not generally available now, and tidb_enable_extended_stats is OFF")
 	}
-	// Not support Cardinality and Dependency statistics type for now.
-	if stats.StatsType == ast.StatsTypeCardinality || stats.StatsType == ast.StatsTypeDependency {
-		return errors.New("Cardinality and Dependency statistics types are not supported now")
-	}
 	_, tbl, err := e.getSchemaAndTableByIdent(ident)
 	if err != nil {
 		return err
@@ -3844,16 +3877,25 @@ func (e *executor) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ide
 		if _, exist := colIDSet[col.ID]; exist {
 			return errors.Errorf("Cannot create extended statistics on duplicate column names '%s'", colName.Name.L)
 		}
+		if stats.StatsType == ast.StatsTypeHistogram {
+			switch col.FieldType.EvalType() {
+			case types.ETInt, types.ETReal, types.ETDecimal, types.ETDatetime, types.ETTimestamp, types.ETDuration:
+			default:
+				return errors.Errorf("Only support Histogram statistics type on the columns of numeric and temporal types, but '%s' is not", colName.Name.L)
+			}
+		}
 		colIDSet[col.ID] = struct{}{}
 		colIDs = append(colIDs, col.ID)
 	}
 	if len(colIDs) != 2 && (stats.StatsType == ast.StatsTypeCorrelation || stats.StatsType == ast.StatsTypeDependency) {
 		return errors.New("Only support Correlation and Dependency statistics types on 2 columns")
 	}
-	if len(colIDs) < 1 && stats.StatsType == ast.StatsTypeCardinality {
+	if len(colIDs) != 2 && stats.StatsType == ast.StatsTypeHistogram {
+		return errors.New("Only support Histogram statistics type on 2 columns")
+	}
+	if len(colIDs) < 2 && stats.StatsType == ast.StatsTypeCardinality {
 		return errors.New("Only support Cardinality statistics type on at least 2 columns")
 	}
-	// TODO: check whether covering index exists for cardinality / dependency types.
 
 	// Call utilities of statistics.Handle to modify system tables instead of doing DML directly,
 	// because locking in Handle can guarantee the correctness of `version` in system tables.
@@ -4756,6 +4798,7 @@ func (e *executor) createVectorIndex(ctx sessionctx.Context, ti ast.Ident, index
 	job := buildAddIndexJobWithoutTypeAndArgs(ctx, schema, t)
 	job.Version = model.GetJobVerInUse()
 	job.Type = model.ActionAddVectorIndex
+	job.ReorgMeta = NewDDLReorgMeta(ctx)
 	indexPartSpecifications[0].Expr = nil
 
 	// TODO: support CDCWriteSource
@@ -885,6 +885,10 @@ func (w *worker) checkVectorIndexProcess(jobCtx *jobContext, tbl table.Table, jo
 		}
 		notAddedRowCnt = notAddedIndexCnt
 		job.RowCount = addedIndexCnt
+		if job.ReorgMeta != nil {
+			// Record the pending rows so that `admin show ddl jobs` can show the build progress.
+			job.ReorgMeta.VectorIndexPendingRowCount = notAddedIndexCnt
+		}
 
 		if isDone {
 			break
@@ -931,7 +935,7 @@ func (w *worker) checkVectorIndexProcessOnce(jobCtx *jobContext, tbl table.Table
 		return false, 0, 0, errors.Trace(err)
 	}
 	if notAddedIndexCnt != 0 {
-		return false, 0, 0, nil
+		return false, notAddedIndexCnt, addedIndexCnt, nil
 	}
 
 	return true, notAddedIndexCnt, addedIndexCnt, nil
@@ -1101,6 +1105,7 @@ SwitchIndexState:
 		if err2 != nil {
 			return ver, errors.Trace(err2)
 		}
+		w.installIngestIndexStats(job)
 
 		// Finish this job.
 		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
@@ -2132,6 +2137,7 @@ func writeChunkToLocal(
 	errCtx errctx.Context,
 	writeStmtBufs *variable.WriteStmtBufs,
 	copChunk *chunk.Chunk,
+	statsCollector *ingestIndexStatsCollector,
 ) (int, kv.Handle, error) {
 	iter := chunk.NewIterator4Chunk(copChunk)
 	c := copCtx.GetBase()
@@ -2188,6 +2194,11 @@ func writeChunkToLocal(
 			if needRestoreForIndexes[i] {
 				rsData = getRestoreData(c.TableInfo, copCtx.IndexInfo(idxID), c.PrimaryKeyInfo, restoreDataBuf)
 			}
+			if statsCollector != nil {
+				if err = statsCollector.collect(loc, index.Meta(), idxData); err != nil {
+					return 0, nil, errors.Trace(err)
+				}
+			}
 			err = writeOneKVToLocal(ctx, writers[i], index, loc, errCtx, writeStmtBufs, idxData, rsData, h)
 			if err != nil {
 				return 0, nil, errors.Trace(err)
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/ddl"
 	testddlutil "github.com/pingcap/tidb/pkg/ddl/testutil"
+	"github.com/pingcap/tidb/pkg/domain"
 	"github.com/pingcap/tidb/pkg/domain/infosync"
 	"github.com/pingcap/tidb/pkg/errno"
 	"github.com/pingcap/tidb/pkg/infoschema"
@@ -1423,6 +1424,30 @@ func TestAddVectorIndexRollback(t *testing.T) {
 	testfailpoint.Disable(t, "github.com/pingcap/tidb/pkg/ddl/MockCheckVectorIndexProcess")
 }
 
+func TestValidateVectorIndex(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomainWithSchemaLease(t, tiflashReplicaLease, mockstore.WithMockTiFlash(2))
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t;")
+
+	tk.MustExec("create table t (a int, b vector(3), c int, vector index idx((VEC_L2_DISTANCE(b))), index idx_c(c));")
+	tk.MustExec("insert into t values (1, '[1,2,3]', 1), (2, '[4,5,6]', 2), (3, '[7,8,9]', 3), (4, null, 4);")
+
+	tk.MustContainErrMsg("admin validate vector index t idx_x", "index idx_x not found")
+	tk.MustContainErrMsg("admin validate vector index t idx_c", "index idx_c is not a vector index")
+	err := tk.QueryToErr("admin validate vector index t idx")
+	require.ErrorContains(t, err, "the TiFlash replica of table t is not available")
+
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	err = domain.GetDomain(tk.Session()).DDLExecutor().UpdateTableReplicaInfo(tk.Session(), tbl.Meta().ID, true)
+	require.NoError(t, err)
+	tk.MustQuery("admin validate vector index t idx sample 2").Check(testkit.Rows("test t idx L2 2 10 1"))
+	tk.MustQuery("admin validate vector index t idx").Check(testkit.Rows("test t idx L2 3 10 1"))
+	tk.MustExec("delete from t where b is not null")
+	tk.MustQuery("admin validate vector index t idx").Check(testkit.Rows("test t idx L2 0 10 <nil>"))
+}
+
 func TestInsertDuplicateBeforeIndexMerge(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
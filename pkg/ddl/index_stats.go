@@ -0,0 +1,179 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/ddl/logutil"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle"
+	handleutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"go.uber.org/zap"
+)
+
+const (
+	// ingestIndexStatsSampleSize is the number of the samples of each index, which is the same as the default
+	// sample size of ANALYZE of version 1.
+	ingestIndexStatsSampleSize = 10000
+	// ingestIndexStatsNumBuckets and ingestIndexStatsNumTopN are the same as the defaults of ANALYZE of version 2.
+	ingestIndexStatsNumBuckets = 256
+	ingestIndexStatsNumTopN    = 100
+)
+
+// ingestIndexStatsCollector samples the index values written by the ingest backfill. The stats of the new indexes
+// are built from the samples when the indexes become public, so they don't need to be analyzed again.
+type ingestIndexStatsCollector struct {
+	tblInfo *model.TableInfo
+	sc      *stmtctx.StatementContext
+
+	mu sync.Mutex
+	// collectors maps the index ID to the samples of the index. It's not changed after the creation.
+	collectors map[int64]*statistics.SampleCollector
+}
+
+// newIngestIndexStatsCollector returns nil if the stats of the indexes can't be built from the backfill, e.g. the
+// stats of the partitioned table need to be merged from all the partitions.
+func newIngestIndexStatsCollector(tblInfo *model.TableInfo, idxInfos []*model.IndexInfo) *ingestIndexStatsCollector {
+	if tblInfo.Partition != nil {
+		return nil
+	}
+	c := &ingestIndexStatsCollector{
+		tblInfo:    tblInfo,
+		sc:         stmtctx.NewStmtCtx(),
+		collectors: make(map[int64]*statistics.SampleCollector, len(idxInfos)),
+	}
+	for _, idxInfo := range idxInfos {
+		// The multi-valued index may have more keys than the rows.
+		if idxInfo.MVIndex {
+			return nil
+		}
+		c.collectors[idxInfo.ID] = &statistics.SampleCollector{
+			FMSketch:      statistics.NewFMSketch(statistics.MaxSketchSize),
+			MaxSampleSize: ingestIndexStatsSampleSize,
+		}
+	}
+	return c
+}
+
+// collect samples the value of the index written for a row. The value is encoded in the same way as the samples
+// of the index collected by ANALYZE.
+func (c *ingestIndexStatsCollector) collect(loc *time.Location, idxInfo *model.IndexInfo, idxData []types.Datum) error {
+	collector, ok := c.collectors[idxInfo.ID]
+	if !ok {
+		return nil
+	}
+	var d types.Datum
+	if len(idxData) > 1 || !idxData[0].IsNull() {
+		var (
+			b   []byte
+			tmp types.Datum
+			err error
+		)
+		for i, idxCol := range idxInfo.Columns {
+			idxData[i].Copy(&tmp)
+			tablecodec.TruncateIndexValue(&tmp, idxCol, c.tblInfo.Columns[idxCol.Offset])
+			b, err = codec.EncodeKey(loc, b, tmp)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		d = types.NewBytesDatum(b)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return collector.Collect(c.sc, d)
+}
+
+// saveStats builds the stats of the indexes from the samples, and saves them without changing the row count of
+// the table, since the rows may be changed after they are read by the backfill.
+func (c *ingestIndexStatsCollector) saveStats(statsHandle *handle.Handle) error {
+	locked, err := statsHandle.GetLockedTables(c.tblInfo.ID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := locked[c.tblInfo.ID]; ok {
+		return nil
+	}
+	// The stats of version 1 are kept until the table is analyzed again.
+	if statsTbl := statsHandle.GetTableStats(c.tblInfo); statsTbl.StatsVer == statistics.Version1 {
+		return nil
+	}
+	results := &statistics.AnalyzeResults{
+		TableID:                 statistics.AnalyzeTableID{TableID: c.tblInfo.ID, PartitionID: statistics.NonPartitionTableID},
+		StatsVer:                statistics.Version2,
+		ForMVIndexOrGlobalIndex: true,
+	}
+	err = handleutil.CallWithSCtx(statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		for idxID, collector := range c.collectors {
+			hist, topn, err := statistics.BuildHistAndTopN(sctx, ingestIndexStatsNumBuckets, ingestIndexStatsNumTopN,
+				idxID, collector, types.NewFieldType(mysql.TypeBlob), false, nil, false)
+			if err != nil {
+				return err
+			}
+			results.Ars = append(results.Ars, &statistics.AnalyzeResult{
+				Hist:    []*statistics.Histogram{hist},
+				Cms:     []*statistics.CMSketch{nil},
+				TopNs:   []*statistics.TopN{topn},
+				Fms:     []*statistics.FMSketch{collector.FMSketch},
+				IsIndex: 1,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return statsHandle.SaveTableStatsToStorage(results, false, handleutil.StatsMetaHistorySourceSchemaChange)
+}
+
+// setIngestIndexStats records the samples collected by the ingest backfill of the job.
+func (dc *ddlCtx) setIngestIndexStats(jobID int64, c *ingestIndexStatsCollector) {
+	dc.ingestIndexStats.Lock()
+	defer dc.ingestIndexStats.Unlock()
+	dc.ingestIndexStats.collectors[jobID] = c
+}
+
+// removeIngestIndexStats removes the samples collected by the ingest backfill of the job.
+func (dc *ddlCtx) removeIngestIndexStats(jobID int64) *ingestIndexStatsCollector {
+	dc.ingestIndexStats.Lock()
+	defer dc.ingestIndexStats.Unlock()
+	c := dc.ingestIndexStats.collectors[jobID]
+	delete(dc.ingestIndexStats.collectors, jobID)
+	return c
+}
+
+// installIngestIndexStats saves the stats built from the samples collected by the ingest backfill of the job, once
+// the indexes become public.
+func (dc *ddlCtx) installIngestIndexStats(job *model.Job) {
+	c := dc.removeIngestIndexStats(job.ID)
+	if c == nil || dc.statsHandle == nil {
+		return
+	}
+	// The stats only save the follow-up analyze of the indexes, so the failure doesn't fail the job.
+	if err := c.saveStats(dc.statsHandle); err != nil {
+		logutil.DDLLogger().Warn("save the index stats built by the ingest backfill failed",
+			zap.Int64("jobID", job.ID), zap.Error(err))
+	}
+}
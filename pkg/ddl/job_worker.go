@@ -396,6 +396,8 @@ func (w *worker) finishDDLJob(jobCtx *jobContext, job *model.Job) (err error) {
 	}
 	job.SeqNum = w.seqAllocator.Add(1)
 	w.removeJobCtx(job)
+	// The samples of the rolled back or cancelled job are dropped.
+	w.removeIngestIndexStats(job.ID)
 	failpoint.InjectCall("afterFinishDDLJob", job)
 	err = AddHistoryDDLJob(w.workCtx, w.sess, metaMut, job, updateRawArgs)
 	return errors.Trace(err)
@@ -945,6 +947,10 @@ func (w *worker) runOneJobStep(
 		ver, err = onModifyTableComment(jobCtx, job)
 	case model.ActionModifyTableAutoIDCache:
 		ver, err = onModifyTableAutoIDCache(jobCtx, job)
+	case model.ActionAlterTableStatsOptions:
+		ver, err = onAlterTableStatsOptions(jobCtx, job)
+	case model.ActionDropRange:
+		ver, err = w.onDropRange(jobCtx, job)
 	case model.ActionAddTablePartition:
 		ver, err = w.onAddTablePartition(jobCtx, job)
 	case model.ActionModifyTableCharsetAndCollate:
@@ -15,7 +15,10 @@
 package ddl
 
 import (
-	"github.com/pingcap/tidb/pkg/meta"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/ddl/notifier"
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	pmodel "github.com/pingcap/tidb/pkg/parser/model"
@@ -140,7 +143,7 @@ func onMultiSchemaChange(w *worker, jobCtx *jobContext, job *model.Job) (ver int
 		sub.FromProxyJob(&proxyJob, ver)
 		return ver, err
 	}
-	return finishMultiSchemaJob(job, metaMut)
+	return finishMultiSchemaJob(w, jobCtx, job)
 }
 
 func handleRevertibleException(job *model.Job, subJob *model.SubJob, err *terror.Error) {
@@ -254,7 +257,8 @@ func fillMultiSchemaInfo(info *model.MultiSchemaInfo, job *JobWrapper) error {
 	case model.ActionAlterIndexVisibility:
 		idxName := job.JobArgs.(*model.AlterIndexVisibilityArgs).IndexName
 		info.AlterIndexes = append(info.AlterIndexes, idxName)
-	case model.ActionRebaseAutoID, model.ActionModifyTableComment, model.ActionModifyTableCharsetAndCollate:
+	case model.ActionRebaseAutoID, model.ActionModifyTableComment, model.ActionModifyTableCharsetAndCollate,
+		model.ActionAlterTableStatsOptions:
 	case model.ActionAddForeignKey:
 		fkInfo := job.JobArgs.(*model.AddForeignKeyArgs).FkInfo
 		info.AddForeignKeys = append(info.AddForeignKeys, model.AddForeignKeyInfo{
@@ -442,16 +446,31 @@ func rollingBackMultiSchemaChange(job *model.Job) error {
 	return dbterror.ErrCancelledDDLJob
 }
 
-func finishMultiSchemaJob(job *model.Job, t *meta.Mutator) (ver int64, err error) {
+func finishMultiSchemaJob(w *worker, jobCtx *jobContext, job *model.Job) (ver int64, err error) {
+	var subEvents []*notifier.SchemaChangeEvent
 	for _, sub := range job.MultiSchemaInfo.SubJobs {
 		if ver < sub.SchemaVer {
 			ver = sub.SchemaVer
 		}
+		for _, data := range sub.SchemaChangeEvents {
+			subEvent := &notifier.SchemaChangeEvent{}
+			if err = json.Unmarshal(data, subEvent); err != nil {
+				return 0, errors.Trace(err)
+			}
+			subEvents = append(subEvents, subEvent)
+		}
+		sub.SchemaChangeEvents = nil
 	}
-	tblInfo, err := t.GetTable(job.SchemaID, job.TableID)
+	tblInfo, err := jobCtx.metaMut.GetTable(job.SchemaID, job.TableID)
 	if err != nil {
 		return 0, err
 	}
+	if len(subEvents) > 0 {
+		multiSchemaChangeEvent := notifier.NewMultiSchemaChangeEvent(tblInfo, subEvents)
+		if err = asyncNotifyEvent(jobCtx, multiSchemaChangeEvent, job, noSubJob, w.sess); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
 	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
 	return 0, err
 }
@@ -68,9 +68,15 @@ func (s *SchemaChangeEvent) String() string {
 	for _, columnInfo := range s.inner.Columns {
 		_, _ = fmt.Fprintf(&sb, ", Column ID: %d, Column Name: %s", columnInfo.ID, columnInfo.Name)
 	}
+	for _, columnInfo := range s.inner.OldColumns {
+		_, _ = fmt.Fprintf(&sb, ", Old Column ID: %d, Old Column Name: %s", columnInfo.ID, columnInfo.Name)
+	}
 	for _, indexInfo := range s.inner.Indexes {
 		_, _ = fmt.Fprintf(&sb, ", Index ID: %d, Index Name: %s", indexInfo.ID, indexInfo.Name)
 	}
+	for _, subEvent := range s.inner.SubEvents {
+		_, _ = fmt.Fprintf(&sb, ", Sub Event: %s", subEvent)
+	}
 	sb.WriteString(")")
 
 	return sb.String()
@@ -148,6 +154,26 @@ func (s *SchemaChangeEvent) GetDropTableInfo() (droppedTableInfo *model.TableInf
 	return s.inner.OldTableInfo
 }
 
+// NewRecoverTableEvent creates a SchemaChangeEvent whose type is
+// ActionRecoverTable.
+func NewRecoverTableEvent(
+	recoveredTableInfo *model.TableInfo,
+) *SchemaChangeEvent {
+	return &SchemaChangeEvent{
+		inner: &jsonSchemaChangeEvent{
+			Tp:        model.ActionRecoverTable,
+			TableInfo: recoveredTableInfo,
+		},
+	}
+}
+
+// GetRecoverTableInfo returns the table info of the SchemaChangeEvent whose type
+// is ActionRecoverTable.
+func (s *SchemaChangeEvent) GetRecoverTableInfo() (recoveredTableInfo *model.TableInfo) {
+	intest.Assert(s.inner.Tp == model.ActionRecoverTable)
+	return s.inner.TableInfo
+}
+
 // NewAddColumnEvent creates a SchemaChangeEvent whose type is ActionAddColumn.
 func NewAddColumnEvent(
 	tableInfo *model.TableInfo,
@@ -173,28 +199,34 @@ func (s *SchemaChangeEvent) GetAddColumnInfo() (
 }
 
 // NewModifyColumnEvent creates a SchemaChangeEvent whose type is
-// ActionModifyColumn.
+// ActionModifyColumn. oldColumns are the columns before the modification,
+// which are in the same order as modifiedColumns.
 func NewModifyColumnEvent(
 	tableInfo *model.TableInfo,
 	modifiedColumns []*model.ColumnInfo,
+	oldColumns []*model.ColumnInfo,
 ) *SchemaChangeEvent {
 	return &SchemaChangeEvent{
 		inner: &jsonSchemaChangeEvent{
-			Tp:        model.ActionModifyColumn,
-			TableInfo: tableInfo,
-			Columns:   modifiedColumns,
+			Tp:         model.ActionModifyColumn,
+			TableInfo:  tableInfo,
+			Columns:    modifiedColumns,
+			OldColumns: oldColumns,
 		},
 	}
 }
 
-// GetModifyColumnInfo returns the table info and modified column info the
-// SchemaChangeEvent whose type is ActionModifyColumn.
+// GetModifyColumnInfo returns the table info, modified column info and the
+// column info before the modification of the SchemaChangeEvent whose type is
+// ActionModifyColumn. oldColumns may be empty for the events generated by the
+// older versions.
 func (s *SchemaChangeEvent) GetModifyColumnInfo() (
 	newTableInfo *model.TableInfo,
 	modifiedColumns []*model.ColumnInfo,
+	oldColumns []*model.ColumnInfo,
 ) {
 	intest.Assert(s.inner.Tp == model.ActionModifyColumn)
-	return s.inner.TableInfo, s.inner.Columns
+	return s.inner.TableInfo, s.inner.Columns, s.inner.OldColumns
 }
 
 // NewAddPartitionEvent creates a SchemaChangeEvent whose type is
@@ -427,6 +459,32 @@ func NewFlashbackClusterEvent() *SchemaChangeEvent {
 	}
 }
 
+// NewMultiSchemaChangeEvent creates a schema change event whose type is
+// ActionMultiSchemaChange. It combines the events of the sub-jobs, so the
+// subscribers can handle them atomically.
+func NewMultiSchemaChangeEvent(
+	tableInfo *model.TableInfo,
+	subEvents []*SchemaChangeEvent,
+) *SchemaChangeEvent {
+	return &SchemaChangeEvent{
+		inner: &jsonSchemaChangeEvent{
+			Tp:        model.ActionMultiSchemaChange,
+			TableInfo: tableInfo,
+			SubEvents: subEvents,
+		},
+	}
+}
+
+// GetMultiSchemaChangeInfo returns the final table info and the events of the
+// sub-jobs of the SchemaChangeEvent whose type is ActionMultiSchemaChange.
+func (s *SchemaChangeEvent) GetMultiSchemaChangeInfo() (
+	tableInfo *model.TableInfo,
+	subEvents []*SchemaChangeEvent,
+) {
+	intest.Assert(s.inner.Tp == model.ActionMultiSchemaChange)
+	return s.inner.TableInfo, s.inner.SubEvents
+}
+
 // NewDropSchemaEvent creates a schema change event whose type is ActionDropSchema.
 func NewDropSchemaEvent(dbInfo *model.DBInfo, tables []*model.TableInfo) *SchemaChangeEvent {
 	miniTables := make([]*MiniTableInfoForSchemaEvent, len(tables))
@@ -499,10 +557,13 @@ type jsonSchemaChangeEvent struct {
 	AddedPartInfo   *model.PartitionInfo      `json:"added_partition_info,omitempty"`
 	DroppedPartInfo *model.PartitionInfo      `json:"dropped_partition_info,omitempty"`
 	Columns         []*model.ColumnInfo       `json:"columns,omitempty"`
+	OldColumns      []*model.ColumnInfo       `json:"old_columns,omitempty"`
 	Indexes         []*model.IndexInfo        `json:"indexes,omitempty"`
 	// OldTableID4Partition is used to store the table ID when a table transitions from being partitioned to non-partitioned,
 	// or vice versa.
 	OldTableID4Partition int64 `json:"old_table_id_for_partition,omitempty"`
+	// SubEvents are the events of the sub-jobs of a multi-schema change.
+	SubEvents []*SchemaChangeEvent `json:"sub_events,omitempty"`
 
 	Tp model.ActionType `json:"type,omitempty"`
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	goerr "errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -77,6 +78,8 @@ func (id HandlerID) String() string {
 		return "TestHandler"
 	case StatsMetaHandlerID:
 		return "StatsMetaHandler"
+	case PriorityQueueHandlerID:
+		return "PriorityQueueHandler"
 	default:
 		return fmt.Sprintf("HandlerID(%d)", id)
 	}
@@ -103,8 +106,11 @@ type DDLNotifier struct {
 	handlers     map[HandlerID]SchemaChangeHandler
 	pollInterval time.Duration
 
-	// handlersBitMap is set to the full bitmap of all registered handlers in Start.
+	// handlersBitMap is the full bitmap of all registered handlers.
 	handlersBitMap uint64
+	// processMu serializes the processing of the background loop and ProcessEvents, so an event is not handled
+	// by the same handler concurrently.
+	processMu sync.Mutex
 }
 
 // NewDDLNotifier initializes the global DDLNotifier.
@@ -141,15 +147,12 @@ func (n *DDLNotifier) RegisterHandler(id HandlerID, handler SchemaChangeHandler)
 		return
 	}
 	n.handlers[id] = handler
+	n.handlersBitMap |= 1 << id
 }
 
 // start starts the DDLNotifier. It will block until the context is canceled.
 // Do not call this function directly. Use owner.Listener interface instead.
 func (n *DDLNotifier) start() {
-	for id := range n.handlers {
-		n.handlersBitMap |= 1 << id
-	}
-
 	ctx := kv.WithInternalSourceType(n.ctx, kv.InternalDDLNotifier)
 	ctx = logutil.WithCategory(ctx, "ddl-notifier")
 	ticker := time.NewTicker(n.pollInterval)
@@ -166,7 +169,19 @@ func (n *DDLNotifier) start() {
 	}
 }
 
+// ProcessEvents processes the pending events synchronously instead of waiting for
+// the background loop, e.g. the tests use it to wait for the handling of the
+// DDL statements. The events which have been processed by a handler are not
+// delivered to it again.
+func (n *DDLNotifier) ProcessEvents(ctx context.Context) error {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalDDLNotifier)
+	return n.processEvents(logutil.WithCategory(ctx, "ddl-notifier"))
+}
+
 func (n *DDLNotifier) processEvents(ctx context.Context) error {
+	n.processMu.Lock()
+	defer n.processMu.Unlock()
+
 	sysSession, err := n.sysSessionPool.Get()
 	if err != nil {
 		return errors.Trace(err)
@@ -253,13 +253,13 @@ func TestPubSub(t *testing.T) {
 	tk.MustExec("alter table t add column b int")                                                                                        // ActionAddColumn
 	tk.MustExec("alter table t add index(b)")
 	tk.MustExec("create table t1(a int, b int key, FOREIGN KEY (b) REFERENCES t(b) ON DELETE CASCADE);") // ActionCreateTable with foreign key
-	tk.MustExec("alter table t1 add column c int, add index idx_a(a)")                                   // ActionAddColumn
+	tk.MustExec("alter table t1 add column c int, add index idx_a(a)")                                   // ActionMultiSchemaChange
 	tk.MustExec("drop database test")                                                                    // ActionDropSchema
 
 	require.Eventually(t, func() bool {
 		eventsLock.Lock()
 		defer eventsLock.Unlock()
-		return len(events) == 18
+		return len(events) == 17
 	}, 5*time.Second, 500*time.Millisecond)
 
 	tps := make([]model.ActionType, len(events))
@@ -282,10 +282,15 @@ func TestPubSub(t *testing.T) {
 		model.ActionAddColumn,
 		model.ActionAddIndex,
 		model.ActionCreateTable,
-		model.ActionAddColumn,
-		model.ActionAddIndex,
+		model.ActionMultiSchemaChange,
 		model.ActionDropSchema,
 	}, tps)
+	// The events of the sub-jobs are combined into one event.
+	tableInfo, subEvents := events[15].GetMultiSchemaChangeInfo()
+	require.Equal(t, "t1", tableInfo.Name.L)
+	require.Len(t, subEvents, 2)
+	require.Equal(t, model.ActionAddColumn, subEvents[0].GetType())
+	require.Equal(t, model.ActionAddIndex, subEvents[1].GetType())
 }
 
 func TestPublishEventError(t *testing.T) {
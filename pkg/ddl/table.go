@@ -251,6 +251,11 @@ func (w *worker) onRecoverTable(jobCtx *jobContext, job *model.Job) (ver int64,
 		if err != nil {
 			return ver, errors.Trace(err)
 		}
+		recoverTableEvent := notifier.NewRecoverTableEvent(tableInfo)
+		err = asyncNotifyEvent(jobCtx, recoverTableEvent, job, noSubJob, w.sess)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
 		tblInfo.State = model.StatePublic
 		tblInfo.UpdateTS = metaMut.StartTS
 		// Finish this job.
@@ -697,6 +702,35 @@ func onModifyTableAutoIDCache(jobCtx *jobContext, job *model.Job) (int64, error)
 	return ver, nil
 }
 
+func onAlterTableStatsOptions(jobCtx *jobContext, job *model.Job) (int64, error) {
+	args, err := model.GetAlterTableStatsOptionsArgs(job)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return 0, errors.Trace(err)
+	}
+
+	tblInfo, err := GetTableInfoAndCancelFaultJob(jobCtx.metaMut, job, job.SchemaID)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	if tblInfo.StatsOptions == nil {
+		tblInfo.StatsOptions = model.NewStatsOptions()
+	}
+	if args.AutoAnalyzeCoolDown != nil {
+		tblInfo.StatsOptions.AutoAnalyzeCoolDown = *args.AutoAnalyzeCoolDown
+	}
+	if args.AutoRecalc != nil {
+		tblInfo.StatsOptions.AutoRecalc = *args.AutoRecalc
+	}
+	ver, err := updateVersionAndTableInfo(jobCtx, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
 func (w *worker) onShardRowID(jobCtx *jobContext, job *model.Job) (ver int64, _ error) {
 	args, err := model.GetShardRowIDArgs(job)
 	if err != nil {
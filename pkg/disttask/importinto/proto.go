@@ -184,4 +184,7 @@ type Checksum struct {
 type Result struct {
 	LoadedRowCnt uint64
 	ColSizeMap   map[int64]int64
+	// StatsSamples is the samples of the imported rows, which are merged from all the subtasks when the encode
+	// step finishes. It's nil if the stats of the table are left to the auto analyze.
+	StatsSamples *importer.TableStatsSamples `json:",omitempty"`
 }
@@ -578,13 +578,19 @@ func updateResult(handle storage.TaskHandle, task *proto.Task, taskMeta *TaskMet
 		subtaskMetas = append(subtaskMetas, &subtaskMeta)
 	}
 	columnSizeMap := make(map[int64]int64)
+	statsSamples := make([]*importer.TableStatsSamples, 0, len(subtaskMetas))
 	for _, subtaskMeta := range subtaskMetas {
 		taskMeta.Result.LoadedRowCnt += subtaskMeta.Result.LoadedRowCnt
 		for key, val := range subtaskMeta.Result.ColSizeMap {
 			columnSizeMap[key] += val
 		}
+		statsSamples = append(statsSamples, subtaskMeta.Result.StatsSamples)
 	}
 	taskMeta.Result.ColSizeMap = columnSizeMap
+	taskMeta.Result.StatsSamples, err = importer.MergeTableStatsSamples(statsSamples)
+	if err != nil {
+		return err
+	}
 
 	if globalSort {
 		taskMeta.Result.LoadedRowCnt, err = getLoadedRowCountOnGlobalSort(handle, task)
@@ -661,10 +667,11 @@ func (sch *ImportSchedulerExt) finishJob(ctx context.Context, logger *zap.Logger
 	return handle.RunWithRetry(ctx, scheduler.RetrySQLTimes, backoffer, logger,
 		func(ctx context.Context) (bool, error) {
 			return true, taskHandle.WithNewSession(func(se sessionctx.Context) error {
-				if err := importer.FlushTableStats(ctx, se, taskMeta.Plan.TableInfo.ID, &importer.JobImportResult{
-					Affected:   taskMeta.Result.LoadedRowCnt,
-					ColSizeMap: taskMeta.Result.ColSizeMap,
-				}); err != nil {
+				if err := importer.FlushTableStats(ctx, se, taskMeta.Plan.TableInfo, &importer.JobImportResult{
+					Affected:     taskMeta.Result.LoadedRowCnt,
+					ColSizeMap:   taskMeta.Result.ColSizeMap,
+					StatsSamples: taskMeta.Result.StatsSamples,
+				}, logger); err != nil {
 					logger.Warn("flush table stats failed", zap.Error(err))
 				}
 				exec := se.GetSQLExecutor()
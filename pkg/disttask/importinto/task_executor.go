@@ -249,9 +249,14 @@ func (s *importStepExecutor) OnFinished(ctx context.Context, subtask *proto.Subt
 			Size: c.SumSize(),
 		}
 	}
+	statsSamples, err := sharedVars.Progress.GetStatsSamples()
+	if err != nil {
+		return err
+	}
 	subtaskMeta.Result = Result{
 		LoadedRowCnt: dataKVCount,
 		ColSizeMap:   sharedVars.Progress.GetColSize(),
+		StatsSamples: statsSamples,
 	}
 	allocators := sharedVars.TableImporter.Allocators()
 	subtaskMeta.MaxIDs = map[autoid.AllocatorType]int64{
@@ -69,6 +69,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/pkg/sessionctx/sysproctrack"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics/asyncload"
 	"github.com/pingcap/tidb/pkg/statistics/handle"
 	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze"
 	"github.com/pingcap/tidb/pkg/statistics/handle/initstats"
@@ -154,7 +155,10 @@ type Domain struct {
 	SchemaValidator SchemaValidator
 	schemaLease     time.Duration
 	sysSessionPool  util.SessionPool
-	exit            chan struct{}
+	// statsSessionPool is the session pool dedicated to the stats operations, so that they don't compete with the
+	// other background jobs for the sessions, see tidb_stats_session_pool_size.
+	statsSessionPool util.SessionPool
+	exit             chan struct{}
 	// `etcdClient` must be used when keyspace is not set, or when the logic to each etcd path needs to be separated by keyspace.
 	etcdClient *clientv3.Client
 	// autoidClient is used when there are tables with AUTO_ID_CACHE=1, it is the client to the autoid service.
@@ -215,6 +219,8 @@ type Domain struct {
 	minJobIDRefresher *systable.MinJobIDRefresher
 
 	instancePlanCache sessionctx.InstancePlanCache // the instance level plan cache
+	// planCacheStatsBaselines maps the table ID to the stats snapshot which the cached plans of the table are built on.
+	planCacheStatsBaselines sync.Map
 
 	statsOwner owner.Manager
 	// deferFn is used to release infoschema object lazily during v1 and v2 switch
@@ -1246,6 +1252,7 @@ func (do *Domain) Close() {
 		terror.Log(errors.Trace(do.etcdClient.Close()))
 	}
 	do.sysSessionPool.Close()
+	do.statsSessionPool.Close()
 	variable.UnregisterStatistics(do.BindHandle())
 	if do.onClose != nil {
 		do.onClose()
@@ -1270,26 +1277,25 @@ const resourceIdleTimeout = 3 * time.Minute // resources in the ResourcePool wil
 func NewDomain(store kv.Storage, schemaLease time.Duration, statsLease time.Duration, dumpFileGcLease time.Duration, factory pools.Factory) *Domain {
 	intest.Assert(schemaLease > 0, "schema lease should be a positive duration")
 	capacity := 200 // capacity of the sysSessionPool size
+	getCallback := func(r pools.Resource) {
+		_, ok := r.(sessionctx.Context)
+		intest.Assert(ok)
+		infosync.StoreInternalSession(r)
+	}
+	putCallback := func(r pools.Resource) {
+		sctx, ok := r.(sessionctx.Context)
+		intest.Assert(ok)
+		intest.AssertFunc(func() bool {
+			txn, _ := sctx.Txn(false)
+			return txn == nil || !txn.Valid()
+		})
+		infosync.DeleteInternalSession(r)
+	}
 	do := &Domain{
-		store: store,
-		exit:  make(chan struct{}),
-		sysSessionPool: util.NewSessionPool(
-			capacity, factory,
-			func(r pools.Resource) {
-				_, ok := r.(sessionctx.Context)
-				intest.Assert(ok)
-				infosync.StoreInternalSession(r)
-			},
-			func(r pools.Resource) {
-				sctx, ok := r.(sessionctx.Context)
-				intest.Assert(ok)
-				intest.AssertFunc(func() bool {
-					txn, _ := sctx.Txn(false)
-					return txn == nil || !txn.Valid()
-				})
-				infosync.DeleteInternalSession(r)
-			},
-		),
+		store:             store,
+		exit:              make(chan struct{}),
+		sysSessionPool:    util.NewSessionPool(capacity, factory, getCallback, putCallback),
+		statsSessionPool:  util.NewSessionPool(variable.DefTiDBStatsSessionPoolSize, factory, getCallback, putCallback),
 		statsLease:        statsLease,
 		schemaLease:       schemaLease,
 		slowQuery:         newTopNSlowQueries(config.GetGlobalConfig().InMemSlowQueryTopNNum, time.Hour*24*7, config.GetGlobalConfig().InMemSlowQueryRecentNum),
@@ -1489,6 +1495,7 @@ func (do *Domain) Start(startMode ddl.StartMode) error {
 	do.wg.Run(do.globalConfigSyncerKeeper, "globalConfigSyncerKeeper")
 	do.wg.Run(do.runawayStartLoop, "runawayStartLoop")
 	do.wg.Run(do.requestUnitsWriterLoop, "requestUnitsWriterLoop")
+	do.wg.Run(do.internalTableGCLoop, "internalTableGCLoop")
 	skipRegisterToDashboard := gCfg.SkipRegisterToDashboard
 	if !skipRegisterToDashboard {
 		do.wg.Run(do.topologySyncerKeeper, "topologySyncerKeeper")
@@ -2016,6 +2023,7 @@ func (do *Domain) LoadBindInfoLoop(ctxForHandle sessionctx.Context, ctxForEvolve
 		return err
 	}
 	do.globalBindHandleWorkerLoop(owner)
+	do.globalBindHandleEvolveLoop(owner)
 	return nil
 }
 
@@ -2049,6 +2057,10 @@ func (do *Domain) globalBindHandleWorkerLoop(owner owner.Manager) {
 				if err == nil && variable.TiDBOptOn(optVal) {
 					bindHandle.CaptureBaselines()
 				}
+				optVal, err = do.GetGlobalVar(variable.TiDBCaptureRegressedPlanBaselines)
+				if err == nil && variable.TiDBOptOn(optVal) {
+					bindHandle.CaptureRegressedBaselines()
+				}
 			case <-gcBindTicker.C:
 				if !owner.IsOwner() {
 					continue
@@ -2062,6 +2074,37 @@ func (do *Domain) globalBindHandleWorkerLoop(owner owner.Manager) {
 	}, "globalBindHandleWorkerLoop")
 }
 
+// globalBindHandleEvolveLoop verifies the candidates of the plan baselines on the owner. It's separated from
+// globalBindHandleWorkerLoop since the verification may take a long time, which should not block loading bindings.
+func (do *Domain) globalBindHandleEvolveLoop(owner owner.Manager) {
+	do.wg.Run(func() {
+		defer func() {
+			logutil.BgLogger().Info("globalBindHandleEvolveLoop exited.")
+		}()
+		defer util.Recover(metrics.LabelDomain, "globalBindHandleEvolveLoop", nil, false)
+
+		evolveBindTicker := time.NewTicker(100 * bindinfo.Lease)
+		defer evolveBindTicker.Stop()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-evolveBindTicker.C:
+				if !owner.IsOwner() {
+					continue
+				}
+				optVal, err := do.GetGlobalVar(variable.TiDBEvolvePlanBaselines)
+				if err != nil || !variable.TiDBOptOn(optVal) {
+					continue
+				}
+				if err := do.BindHandle().EvolveBaselines(false); err != nil {
+					logutil.BgLogger().Warn("evolve plan baselines failed", zap.Error(err))
+				}
+			}
+		}
+	}, "globalBindHandleEvolveLoop")
+}
+
 // SetupPlanReplayerHandle setup plan replayer handle
 func (do *Domain) SetupPlanReplayerHandle(collectorSctx sessionctx.Context, workersSctxs []sessionctx.Context) {
 	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
@@ -2070,6 +2113,11 @@ func (do *Domain) SetupPlanReplayerHandle(collectorSctx sessionctx.Context, work
 		ctx:  ctx,
 		sctx: collectorSctx,
 	}
+	// The snapshots are captured in the same loop as the collector, so they share the session.
+	do.planReplayerHandle.planReplayerSnapshotHandle = &planReplayerSnapshotHandle{
+		ctx:  ctx,
+		sctx: collectorSctx,
+	}
 	taskCH := make(chan *PlanReplayerDumpTask, 16)
 	taskStatus := &planReplayerDumpTaskStatus{}
 	taskStatus.finishedTaskMu.finishedTask = map[replayer.PlanReplayerTaskKey]struct{}{}
@@ -2166,6 +2214,7 @@ func (do *Domain) StartPlanReplayerHandle() {
 				if err != nil {
 					logutil.BgLogger().Warn("plan replayer handle collect tasks failed", zap.Error(err))
 				}
+				do.planReplayerHandle.capturePlanReplayerSnapshotIfNeeded(do.ddl.OwnerManager().IsOwner())
 			}
 		}
 	}, "PlanReplayerTaskCollectHandle")
@@ -2272,7 +2321,7 @@ func (do *Domain) CreateStatsHandle(ctx, initStatsCtx sessionctx.Context) error
 		initStatsCtx,
 		do.statsLease,
 		do.InfoSchema(),
-		do.sysSessionPool,
+		do.statsSessionPool,
 		&do.sysProcesses,
 		do.ddlNotifier,
 		do.NextConnID,
@@ -2319,7 +2368,7 @@ func (do *Domain) UpdateTableStatsLoop(ctx, initStatsCtx sessionctx.Context) err
 		initStatsCtx,
 		do.statsLease,
 		do.InfoSchema(),
-		do.sysSessionPool,
+		do.statsSessionPool,
 		&do.sysProcesses,
 		do.ddlNotifier,
 		do.NextConnID,
@@ -2339,7 +2388,9 @@ func (do *Domain) UpdateTableStatsLoop(ctx, initStatsCtx sessionctx.Context) err
 	variable.DisableStatsOwner = do.disableStatsOwner
 	do.statsOwner = do.newOwnerManager(handle.StatsPrompt, handle.StatsOwnerKey)
 	do.statsOwner.SetListener(owner.NewListenersWrapper(statsHandle, do.ddlNotifier))
-	if config.GetGlobalConfig().Instance.TiDBEnableStatsOwner.Load() {
+	// The stats read-only instance never writes the stats, so it never becomes the stats owner to run the auto analyze
+	// and the stats GC.
+	if readOnly, _ := handleutil.StatsReadOnly(); !readOnly && config.GetGlobalConfig().Instance.TiDBEnableStatsOwner.Load() {
 		err := do.statsOwner.CampaignOwner()
 		if err != nil {
 			logutil.BgLogger().Warn("campaign owner failed", zap.Error(err))
@@ -2356,12 +2407,9 @@ func (do *Domain) UpdateTableStatsLoop(ctx, initStatsCtx sessionctx.Context) err
 	}
 	do.SetStatsUpdating(true)
 	// The stats updated worker doesn't require the stats initialization to be completed.
-	// This is because the updated worker's primary responsibilities are to update the change delta and handle DDL operations.
-	// These tasks do not interfere with or depend on the initialization process.
+	// This is because the updated worker's primary responsibility is to update the change delta, while the DDL events
+	// are handled by the subscribers of the DDL notifier. These tasks do not interfere with or depend on the initialization process.
 	do.wg.Run(func() { do.updateStatsWorker(ctx) }, "updateStatsWorker")
-	do.wg.Run(func() {
-		do.handleDDLEvent()
-	}, "handleDDLEvent")
 	// Wait for the stats worker to finish the initialization.
 	// Otherwise, we may start the auto analyze worker before the stats cache is initialized.
 	do.wg.Run(
@@ -2386,6 +2434,39 @@ func (do *Domain) UpdateTableStatsLoop(ctx, initStatsCtx sessionctx.Context) err
 		},
 		"analyzeJobsCleanupWorker",
 	)
+	do.wg.Run(
+		func() {
+			select {
+			case <-do.StatsHandle().InitStatsDone:
+			case <-do.exit: // It may happen that before initStatsDone, tidb receive Ctrl+C
+				return
+			}
+			do.globalStatsMergeWorker()
+		},
+		"globalStatsMergeWorker",
+	)
+	do.wg.Run(
+		func() {
+			select {
+			case <-do.StatsHandle().InitStatsDone:
+			case <-do.exit: // It may happen that before initStatsDone, tidb receive Ctrl+C
+				return
+			}
+			do.statsVersionMigrationWorker()
+		},
+		"statsVersionMigrationWorker",
+	)
+	do.wg.Run(
+		func() {
+			select {
+			case <-do.StatsHandle().InitStatsDone:
+			case <-do.exit: // It may happen that before initStatsDone, tidb receive Ctrl+C
+				return
+			}
+			do.statsAlertWorker()
+		},
+		"statsAlertWorker",
+	)
 	do.wg.Run(
 		func() {
 			// The initStatsCtx is used to store the internal session for initializing stats,
@@ -2416,6 +2497,9 @@ func (do *Domain) UpdateTableStatsLoop(ctx, initStatsCtx sessionctx.Context) err
 // Since ownerManager.CampaignOwner will start a new goroutine to run ownerManager.campaignLoop,
 // we should make sure that before invoking enableStatsOwner(), stats owner is DISABLE.
 func (do *Domain) enableStatsOwner() error {
+	if readOnly, _ := handleutil.StatsReadOnly(); readOnly {
+		return handleutil.ErrStatsReadOnly
+	}
 	if !do.statsOwner.IsOwner() {
 		err := do.statsOwner.CampaignOwner()
 		return errors.Trace(err)
@@ -2510,6 +2594,7 @@ func (do *Domain) loadStatsWorker() {
 			if err != nil {
 				logutil.BgLogger().Debug("update stats info failed", zap.Error(err))
 			}
+			asyncload.PlanCacheNeededItems.Prefetch(time.Now(), asyncload.PlanCacheItemsTTL)
 			err = statsHandle.LoadNeededHistograms(do.InfoSchema())
 			if err != nil {
 				logutil.BgLogger().Debug("load histograms failed", zap.Error(err))
@@ -2560,22 +2645,13 @@ func (do *Domain) updateStatsWorkerExitPreprocessing(statsHandle *handle.Handle)
 	}
 }
 
-func (do *Domain) handleDDLEvent() {
-	logutil.BgLogger().Info("handleDDLEvent started.")
-	defer util.Recover(metrics.LabelDomain, "handleDDLEvent", nil, false)
-	statsHandle := do.StatsHandle()
-	for {
-		select {
-		case <-do.exit:
-			return
-			// This channel is sent only by ddl owner.
-		case t := <-statsHandle.DDLEventCh():
-			err := statsHandle.HandleDDLEvent(t)
-			if err != nil {
-				logutil.BgLogger().Error("handle ddl event failed", zap.String("event", t.String()), zap.Error(err))
-			}
-		}
+// statsGCInterval returns the interval of the stats GC, which is tidb_stats_gc_interval or 100 times of the lease
+// if it's not set.
+func statsGCInterval(lease time.Duration) time.Duration {
+	if interval := variable.StatsGCInterval.Load(); interval > 0 {
+		return interval
 	}
+	return 100 * lease
 }
 
 func (do *Domain) updateStatsWorker(_ sessionctx.Context) {
@@ -2584,8 +2660,10 @@ func (do *Domain) updateStatsWorker(_ sessionctx.Context) {
 	lease := do.statsLease
 	// We need to have different nodes trigger tasks at different times to avoid the herd effect.
 	randDuration := time.Duration(rand.Int63n(int64(time.Minute)))
-	deltaUpdateTicker := time.NewTicker(20*lease + randDuration)
-	gcStatsTicker := time.NewTicker(100 * lease)
+	deltaUpdateInterval := 20*lease + randDuration
+	deltaUpdateTicker := time.NewTicker(deltaUpdateInterval)
+	gcStatsInterval := statsGCInterval(lease)
+	gcStatsTicker := time.NewTicker(gcStatsInterval)
 	dumpColStatsUsageTicker := time.NewTicker(100 * lease)
 	updateStatsHealthyTicker := time.NewTicker(20 * lease)
 	readMemTicker := time.NewTicker(memory.ReadMemInterval)
@@ -2611,7 +2689,12 @@ func (do *Domain) updateStatsWorker(_ sessionctx.Context) {
 			if err != nil {
 				logutil.BgLogger().Debug("dump stats delta failed", zap.Error(err))
 			}
+			deltaUpdateTicker.Reset(statsHandle.DumpStatsDeltaInterval(deltaUpdateInterval))
 		case <-gcStatsTicker.C:
+			if interval := statsGCInterval(lease); interval != gcStatsInterval {
+				gcStatsInterval = interval
+				gcStatsTicker.Reset(interval)
+			}
 			if !do.statsOwner.IsOwner() {
 				continue
 			}
@@ -2653,48 +2736,110 @@ func (do *Domain) autoAnalyzeWorker() {
 	}
 }
 
-// analyzeJobsCleanupWorker is a background worker that periodically performs two main tasks:
-//
-//  1. Garbage Collection: It removes outdated analyze jobs from the statistics handle.
-//     This operation is performed every hour and only if the current instance is the owner.
-//     Analyze jobs older than 7 days are considered outdated and are removed.
+// statsVersionMigrationInterval is the interval between migrating two tables, to reduce the impact on the cluster.
+const statsVersionMigrationInterval = time.Minute
+
+// statsVersionMigrationWorker re-analyzes the tables with the version 1 stats one by one when
+// tidb_enable_stats_version_migration is on. Only the stats owner migrates the tables.
+func (do *Domain) statsVersionMigrationWorker() {
+	defer util.Recover(metrics.LabelDomain, "statsVersionMigrationWorker", nil, false)
+	statsHandle := do.StatsHandle()
+	migrationTicker := time.NewTicker(statsVersionMigrationInterval)
+	defer func() {
+		migrationTicker.Stop()
+		logutil.BgLogger().Info("statsVersionMigrationWorker exited.")
+	}()
+	for {
+		select {
+		case <-migrationTicker.C:
+			if variable.EnableStatsVersionMigration.Load() && do.statsOwner.IsOwner() {
+				if err := statsHandle.MigrateStatsVersion(do.InfoSchema()); err != nil {
+					logutil.BgLogger().Warn("migrate stats version failed", zap.Error(err))
+				}
+			}
+		case <-do.exit:
+			return
+		}
+	}
+}
+
+// statsAlertInterval is the interval between checking the stats alerts.
+const statsAlertInterval = 5 * time.Minute
+
+// statsAlertWorker records the tables whose stats are stale or inaccurate although auto analyze is enabled
+// in mysql.stats_alerts. Only the stats owner checks the alerts, since the skip reasons are kept by its
+// priority queue.
+func (do *Domain) statsAlertWorker() {
+	defer util.Recover(metrics.LabelDomain, "statsAlertWorker", nil, false)
+	statsHandle := do.StatsHandle()
+	alertTicker := time.NewTicker(statsAlertInterval)
+	defer func() {
+		alertTicker.Stop()
+		logutil.BgLogger().Info("statsAlertWorker exited.")
+	}()
+	for {
+		select {
+		case <-alertTicker.C:
+			if do.statsOwner.IsOwner() {
+				if err := statsHandle.CheckStatsAlerts(); err != nil {
+					logutil.BgLogger().Warn("check stats alerts failed", zap.Error(err))
+				}
+			}
+		case <-do.exit:
+			return
+		}
+	}
+}
+
+// globalStatsMergeWorker merges the partition-level stats into the global-level stats for the jobs
+// enqueued by the analyze when tidb_enable_background_merge_global_stats is on.
+// Only the stats owner executes the jobs.
+func (do *Domain) globalStatsMergeWorker() {
+	defer util.Recover(metrics.LabelDomain, "globalStatsMergeWorker", nil, false)
+	statsHandle := do.StatsHandle()
+	mergeTicker := time.NewTicker(do.statsLease)
+	defer func() {
+		mergeTicker.Stop()
+		logutil.BgLogger().Info("globalStatsMergeWorker exited.")
+	}()
+	for {
+		select {
+		case <-mergeTicker.C:
+			if do.statsOwner.IsOwner() {
+				if err := statsHandle.MergePendingGlobalStats(do.InfoSchema()); err != nil {
+					logutil.BgLogger().Warn("merge pending global stats failed", zap.Error(err))
+				}
+			}
+		case <-do.exit:
+			return
+		}
+	}
+}
+
+// analyzeJobsCleanupWorker is a background worker that periodically cleans up corrupted analyze jobs.
+// The outdated analyze jobs are pruned by internalTableGCLoop according to tidb_analyze_jobs_max_age and
+// tidb_analyze_jobs_max_rows.
 //
-//  2. Cleanup: It cleans up corrupted analyze jobs.
-//     A corrupted analyze job is one that is in a 'pending' or 'running' state,
-//     but is associated with a TiDB instance that is either not currently running or has been restarted.
-//     Also, if the analyze job is killed by the user, it is considered corrupted.
-//     This operation is performed every 100 stats leases.
-//     It first retrieves the list of current analyze processes, then removes any analyze job
-//     that is not associated with a current process. Additionally, if the current instance is the owner,
-//     it also cleans up corrupted analyze jobs on dead instances.
+// A corrupted analyze job is one that is in a 'pending' or 'running' state,
+// but is associated with a TiDB instance that is either not currently running or has been restarted.
+// Also, if the analyze job is killed by the user, it is considered corrupted.
+// This operation is performed every 100 stats leases.
+// It first retrieves the list of current analyze processes, then removes any analyze job
+// that is not associated with a current process. Additionally, if the current instance is the owner,
+// it also cleans up corrupted analyze jobs on dead instances.
 func (do *Domain) analyzeJobsCleanupWorker() {
 	defer util.Recover(metrics.LabelDomain, "analyzeJobsCleanupWorker", nil, false)
-	// For GC.
-	const gcInterval = time.Hour
-	const daysToKeep = 7
-	gcTicker := time.NewTicker(gcInterval)
-	// For clean up.
 	// Default stats lease is 3 * time.Second.
 	// So cleanupInterval is 100 * 3 * time.Second = 5 * time.Minute.
 	var cleanupInterval = do.statsLease * 100
 	cleanupTicker := time.NewTicker(cleanupInterval)
 	defer func() {
-		gcTicker.Stop()
 		cleanupTicker.Stop()
 		logutil.BgLogger().Info("analyzeJobsCleanupWorker exited.")
 	}()
 	statsHandle := do.StatsHandle()
 	for {
 		select {
-		case <-gcTicker.C:
-			// Only the owner should perform this operation.
-			if do.statsOwner.IsOwner() {
-				updateTime := time.Now().AddDate(0, 0, -daysToKeep)
-				err := statsHandle.DeleteAnalyzeJobs(updateTime)
-				if err != nil {
-					logutil.BgLogger().Warn("gc analyze history failed", zap.Error(err))
-				}
-			}
 		case <-cleanupTicker.C:
 			sm := do.InfoSyncer().GetSessionManager()
 			if sm == nil {
@@ -3162,6 +3307,12 @@ func (do *Domain) GetInstancePlanCache() sessionctx.InstancePlanCache {
 	return do.instancePlanCache
 }
 
+// PlanCacheStatsBaselines returns the stats snapshots which the cached plans of the tables are built on, see
+// tidb_plan_cache_stats_invalidation_policy.
+func (do *Domain) PlanCacheStatsBaselines() *sync.Map {
+	return &do.planCacheStatsBaselines
+}
+
 // planCacheMetricsAndVars updates metrics and variables for Instance Plan Cache periodically.
 func (do *Domain) planCacheMetricsAndVars() {
 	defer util.Recover(metrics.LabelDomain, "planCacheMetricsAndVars", nil, false)
@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/logutil"
@@ -389,7 +390,7 @@ func (w *extractWorker) dumpExtractPlanPackage(task *ExtractTask, p *extractPlan
 	}
 	// Dump stats
 	if !task.SkipStats {
-		if _, err = dumpStats(zw, p.tables, GetDomain(w.sctx), 0); err != nil {
+		if _, err = dumpStats(zw, p.tables, GetDomain(w.sctx), 0, variable.PlanReplayerStatsFormatJSON); err != nil {
 			return "", err
 		}
 	}
@@ -0,0 +1,223 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/metrics"
+	"github.com/pingcap/tidb/pkg/resourcegroup/runaway"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	internalTableGCInterval = 10 * time.Minute
+	// internalTableGCBatchInterval is the interval between the delete batches, to reduce the impact on the cluster.
+	internalTableGCBatchInterval = 100 * time.Millisecond
+)
+
+// internalTableTimeColumn is the column used to order the rows of an internal table by their ages.
+type internalTableTimeColumn struct {
+	name string
+	// unixTime means the column stores the unix seconds instead of a timestamp.
+	unixTime bool
+}
+
+// internalTableTimeColumns is the time columns of the tables in variable.InternalTableRetentionTables.
+var internalTableTimeColumns = map[string]internalTableTimeColumn{
+	"analyze_jobs":                    {name: "update_time"},
+	"tidb_background_subtask_history": {name: "state_update_time", unixTime: true},
+	"tidb_ttl_job_history":            {name: "create_time"},
+	"tidb_runaway_queries":            {name: "start_time"},
+	"tidb_runaway_watch_done":         {name: "done_time"},
+}
+
+// internalTableGCLoop deletes the rows of the internal tables exceeding tidb_internal_table_retention, and the
+// analyze job history exceeding tidb_analyze_jobs_max_age and tidb_analyze_jobs_max_rows.
+// Only the DDL owner deletes the rows.
+func (do *Domain) internalTableGCLoop() {
+	defer util.Recover(metrics.LabelDomain, "internalTableGCLoop", nil, false)
+	ticker := time.NewTicker(internalTableGCInterval)
+	defer func() {
+		ticker.Stop()
+		logutil.BgLogger().Info("internalTableGCLoop exited.")
+	}()
+	for {
+		select {
+		case <-ticker.C:
+			if do.DDL().OwnerManager().IsOwner() {
+				do.GCInternalTables()
+			} else {
+				for _, table := range variable.InternalTableRetentionTables {
+					metrics.InternalTableGCBacklog.WithLabelValues(table).Set(0)
+				}
+			}
+		case <-do.exit:
+			return
+		}
+	}
+}
+
+// GCInternalTables deletes the rows of the internal tables exceeding tidb_internal_table_retention once.
+// It's exported for testing.
+func (do *Domain) GCInternalTables() {
+	retention := variable.InternalTableRetentionValue.Load()
+	for _, table := range variable.InternalTableRetentionTables {
+		tableCap := internalTableCap(retention, table)
+		if tableCap == nil {
+			metrics.InternalTableGCBacklog.WithLabelValues(table).Set(0)
+			continue
+		}
+		if err := do.gcInternalTable(table, tableCap); err != nil {
+			logutil.BgLogger().Warn("gc internal table failed", zap.String("table", table), zap.Error(err))
+		}
+	}
+}
+
+// internalTableCap returns the caps of the table, nil if there is none. The caps of mysql.analyze_jobs set by
+// tidb_analyze_jobs_max_age and tidb_analyze_jobs_max_rows are merged with its rule in tidb_internal_table_retention,
+// and the tighter ones win.
+func internalTableCap(retention *variable.InternalTableRetention, table string) *variable.InternalTableRetentionCap {
+	tableCap := retention.Cap(table)
+	if table != "analyze_jobs" {
+		return tableCap
+	}
+	maxAge, maxRows := variable.AnalyzeJobsMaxAge.Load(), uint64(max(variable.AnalyzeJobsMaxRows.Load(), 0))
+	if maxAge == 0 && maxRows == 0 {
+		return tableCap
+	}
+	merged := &variable.InternalTableRetentionCap{}
+	if tableCap != nil {
+		*merged = *tableCap
+	}
+	merged.MaxAge = tighterCap(merged.MaxAge, maxAge)
+	merged.MaxRows = tighterCap(merged.MaxRows, maxRows)
+	return merged
+}
+
+// tighterCap returns the tighter one of the two caps, where zero means unlimited.
+func tighterCap[T time.Duration | uint64](a, b T) T {
+	if a == 0 {
+		return b
+	}
+	if b == 0 {
+		return a
+	}
+	return min(a, b)
+}
+
+func (do *Domain) gcInternalTable(table string, tableCap *variable.InternalTableRetentionCap) error {
+	col := internalTableTimeColumns[table]
+	// The ages are compared in unix seconds.
+	valueExpr, boundExpr := "%n", "%?"
+	if !col.unixTime {
+		valueExpr, boundExpr = "CAST(FLOOR(UNIX_TIMESTAMP(%n)) AS SIGNED)", "FROM_UNIXTIME(%?)"
+	}
+
+	// The rows older than the cutoff are deleted.
+	cutoff := int64(0)
+	if tableCap.MaxAge > 0 {
+		cutoff = time.Now().Add(-tableCap.MaxAge).Unix()
+	}
+	maxRows, err := do.internalTableMaxRows(table, tableCap)
+	if err != nil {
+		return err
+	}
+	if maxRows >= 0 {
+		// Find the oldest row to keep. The rows in the same second as it are kept too, so the cap may be
+		// exceeded slightly.
+		rows, err := runaway.ExecRCRestrictedSQL(do.sysSessionPool,
+			"SELECT "+valueExpr+" FROM mysql.%n ORDER BY %n DESC LIMIT %?, 1",
+			[]any{col.name, table, col.name, max(maxRows-1, 0)})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(rows) > 0 && !rows[0].IsNull(0) {
+			oldest := rows[0].GetInt64(0)
+			if maxRows == 0 {
+				// Delete all the rows.
+				oldest++
+			}
+			cutoff = max(cutoff, oldest)
+		}
+	}
+	if cutoff <= 0 {
+		metrics.InternalTableGCBacklog.WithLabelValues(table).Set(0)
+		return nil
+	}
+
+	rows, err := runaway.ExecRCRestrictedSQL(do.sysSessionPool,
+		"SELECT COUNT(*) FROM mysql.%n WHERE %n < "+boundExpr, []any{table, col.name, cutoff})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	backlog := rows[0].GetInt64(0)
+	metrics.InternalTableGCBacklog.WithLabelValues(table).Set(float64(backlog))
+	for backlog > 0 {
+		_, err = runaway.ExecRCRestrictedSQL(do.sysSessionPool,
+			"DELETE LOW_PRIORITY FROM mysql.%n WHERE %n < "+boundExpr+" LIMIT %?",
+			[]any{table, col.name, cutoff, gcBatchSize})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		deleted := min(backlog, gcBatchSize)
+		backlog -= deleted
+		metrics.InternalTableGCDeletedRows.WithLabelValues(table).Add(float64(deleted))
+		metrics.InternalTableGCBacklog.WithLabelValues(table).Set(float64(backlog))
+		if backlog == 0 {
+			break
+		}
+		select {
+		case <-do.exit:
+			return nil
+		case <-time.After(internalTableGCBatchInterval):
+		}
+	}
+	return nil
+}
+
+// internalTableMaxRows returns the max number of the rows kept in the table, -1 if it's unlimited.
+// The size cap is converted to the number of the rows by the average row size in the statistics.
+func (do *Domain) internalTableMaxRows(table string, tableCap *variable.InternalTableRetentionCap) (int64, error) {
+	maxRows := int64(-1)
+	if tableCap.MaxRows > 0 {
+		maxRows = int64(min(tableCap.MaxRows, uint64(1<<62)))
+	}
+	if tableCap.MaxSize == 0 {
+		return maxRows, nil
+	}
+	rows, err := runaway.ExecRCRestrictedSQL(do.sysSessionPool,
+		`SELECT CAST(table_rows AS SIGNED), CAST(data_length + index_length AS SIGNED) FROM information_schema.tables
+		WHERE table_schema = 'mysql' AND table_name = %?`, []any{table})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(rows) == 0 || rows[0].IsNull(0) || rows[0].IsNull(1) {
+		return maxRows, nil
+	}
+	tableRows, size := rows[0].GetInt64(0), rows[0].GetInt64(1)
+	if tableRows <= 0 || size <= int64(tableCap.MaxSize) {
+		return maxRows, nil
+	}
+	sizeRows := int64(float64(tableRows) * float64(tableCap.MaxSize) / float64(size))
+	if maxRows < 0 || sizeRows < maxRows {
+		maxRows = sizeRows
+	}
+	return maxRows, nil
+}
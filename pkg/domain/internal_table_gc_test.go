@@ -0,0 +1,98 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/testkit"
+)
+
+func TestGCInternalTables(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := newTestKit(t, store)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		tk.MustExec(`INSERT INTO mysql.analyze_jobs (update_time, table_schema, table_name, job_info, state, instance)
+			VALUES (?, 'test', 't', 'analyze table', 'finished', '127.0.0.1:4000')`,
+			now.AddDate(0, 0, -i).Format(time.DateTime))
+		tk.MustExec(`INSERT INTO mysql.tidb_background_subtask_history (state, checkpoint, state_update_time)
+			VALUES ('succeed', '', ?)`, now.Unix()-int64(i*100))
+	}
+
+	// No retention is set.
+	dom.GCInternalTables()
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("5"))
+	tk.MustQuery("select count(*) from mysql.tidb_background_subtask_history").Check(testkit.Rows("5"))
+
+	tk.MustExec("set @@global.tidb_internal_table_retention = 'analyze_jobs: age=60h; tidb_background_subtask_history: rows=2'")
+	defer tk.MustExec("set @@global.tidb_internal_table_retention = ''")
+	dom.GCInternalTables()
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("3"))
+	tk.MustQuery("select state_update_time from mysql.tidb_background_subtask_history order by state_update_time desc").
+		Check(testkit.Rows(fmt.Sprint(now.Unix()), fmt.Sprint(now.Unix()-100)))
+
+	tk.MustExec("set @@global.tidb_internal_table_retention = 'tidb_background_subtask_history: rows=1'")
+	dom.GCInternalTables()
+	tk.MustQuery("select state_update_time from mysql.tidb_background_subtask_history").Check(testkit.Rows(fmt.Sprint(now.Unix())))
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("3"))
+
+	tk.MustGetErrMsg("set @@global.tidb_internal_table_retention = 'stats_meta: age=1h'",
+		"[variable:1231]Variable 'tidb_internal_table_retention' can't be set to the value of 'stats_meta: age=1h'")
+}
+
+func TestPruneAnalyzeJobs(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := newTestKit(t, store)
+
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		tk.MustExec(`INSERT INTO mysql.analyze_jobs (update_time, table_schema, table_name, job_info, state, instance)
+			VALUES (?, 'test', 't', 'analyze table', 'finished', '127.0.0.1:4000')`,
+			now.AddDate(0, 0, -i).Format(time.DateTime))
+	}
+
+	// The analyze jobs older than 7 days are pruned by default.
+	dom.GCInternalTables()
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("7"))
+
+	tk.MustExec("set @@global.tidb_analyze_jobs_max_age = '60h'")
+	defer tk.MustExec("set @@global.tidb_analyze_jobs_max_age = default")
+	dom.GCInternalTables()
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("3"))
+
+	tk.MustExec("set @@global.tidb_analyze_jobs_max_rows = 2")
+	defer tk.MustExec("set @@global.tidb_analyze_jobs_max_rows = default")
+	dom.GCInternalTables()
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("2"))
+
+	// The tighter cap in tidb_internal_table_retention wins.
+	tk.MustExec("set @@global.tidb_internal_table_retention = 'analyze_jobs: rows=1'")
+	defer tk.MustExec("set @@global.tidb_internal_table_retention = ''")
+	dom.GCInternalTables()
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("1"))
+
+	// The analyze jobs are kept if both caps are disabled.
+	tk.MustExec("set @@global.tidb_internal_table_retention = ''")
+	tk.MustExec("set @@global.tidb_analyze_jobs_max_age = 0")
+	tk.MustExec("set @@global.tidb_analyze_jobs_max_rows = 0")
+	tk.MustExec(`INSERT INTO mysql.analyze_jobs (update_time, table_schema, table_name, job_info, state, instance)
+		VALUES (?, 'test', 't', 'analyze table', 'finished', '127.0.0.1:4000')`, now.AddDate(-1, 0, 0).Format(time.DateTime))
+	dom.GCInternalTables()
+	tk.MustQuery("select count(*) from mysql.analyze_jobs").Check(testkit.Rows("2"))
+}
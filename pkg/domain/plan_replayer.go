@@ -219,6 +219,7 @@ func insertPlanReplayerSuccessStatusRecord(ctx context.Context, sctx sessionctx.
 type planReplayerHandle struct {
 	*planReplayerTaskCollectorHandle
 	*planReplayerTaskDumpHandle
+	*planReplayerSnapshotHandle
 }
 
 // SendTask send dumpTask in background task handler
@@ -244,7 +245,7 @@ func (h *planReplayerHandle) SendTask(task *PlanReplayerDumpTask) bool {
 type planReplayerTaskCollectorHandle struct {
 	taskMu struct {
 		sync.RWMutex
-		tasks map[replayer.PlanReplayerTaskKey]struct{}
+		tasks map[replayer.PlanReplayerTaskKey]replayer.PlanReplayerCaptureThresholds
 	}
 	ctx  context.Context
 	sctx sessionctx.Context
@@ -252,12 +253,12 @@ type planReplayerTaskCollectorHandle struct {
 
 // CollectPlanReplayerTask collects all unhandled plan replayer task
 func (h *planReplayerTaskCollectorHandle) CollectPlanReplayerTask() error {
-	allKeys, err := h.collectAllPlanReplayerTask(h.ctx)
+	allTasks, err := h.collectAllPlanReplayerTask(h.ctx)
 	if err != nil {
 		return err
 	}
-	tasks := make([]replayer.PlanReplayerTaskKey, 0)
-	for _, key := range allKeys {
+	tasks := make(map[replayer.PlanReplayerTaskKey]replayer.PlanReplayerCaptureThresholds)
+	for key, thresholds := range allTasks {
 		unhandled, err := checkUnHandledReplayerTask(h.ctx, h.sctx, key)
 		if err != nil {
 			logutil.BgLogger().Warn("collect plan replayer task failed", zap.String("category", "plan-replayer-task"), zap.Error(err))
@@ -267,7 +268,7 @@ func (h *planReplayerTaskCollectorHandle) CollectPlanReplayerTask() error {
 			logutil.BgLogger().Debug("collect plan replayer task success", zap.String("category", "plan-replayer-task"),
 				zap.String("sql-digest", key.SQLDigest),
 				zap.String("plan-digest", key.PlanDigest))
-			tasks = append(tasks, key)
+			tasks[key] = thresholds
 		}
 	}
 	h.setupTasks(tasks)
@@ -286,14 +287,18 @@ func (h *planReplayerTaskCollectorHandle) GetTasks() []replayer.PlanReplayerTask
 	return tasks
 }
 
-func (h *planReplayerTaskCollectorHandle) setupTasks(tasks []replayer.PlanReplayerTaskKey) {
-	r := make(map[replayer.PlanReplayerTaskKey]struct{})
-	for _, task := range tasks {
-		r[task] = struct{}{}
-	}
+// GetTaskThresholds gets the capture thresholds of the task.
+func (h *planReplayerTaskCollectorHandle) GetTaskThresholds(taskKey replayer.PlanReplayerTaskKey) (replayer.PlanReplayerCaptureThresholds, bool) {
+	h.taskMu.RLock()
+	defer h.taskMu.RUnlock()
+	thresholds, ok := h.taskMu.tasks[taskKey]
+	return thresholds, ok
+}
+
+func (h *planReplayerTaskCollectorHandle) setupTasks(tasks map[replayer.PlanReplayerTaskKey]replayer.PlanReplayerCaptureThresholds) {
 	h.taskMu.Lock()
 	defer h.taskMu.Unlock()
-	h.taskMu.tasks = r
+	h.taskMu.tasks = tasks
 }
 
 func (h *planReplayerTaskCollectorHandle) removeTask(taskKey replayer.PlanReplayerTaskKey) {
@@ -302,9 +307,9 @@ func (h *planReplayerTaskCollectorHandle) removeTask(taskKey replayer.PlanReplay
 	delete(h.taskMu.tasks, taskKey)
 }
 
-func (h *planReplayerTaskCollectorHandle) collectAllPlanReplayerTask(ctx context.Context) ([]replayer.PlanReplayerTaskKey, error) {
+func (h *planReplayerTaskCollectorHandle) collectAllPlanReplayerTask(ctx context.Context) (map[replayer.PlanReplayerTaskKey]replayer.PlanReplayerCaptureThresholds, error) {
 	exec := h.sctx.GetSQLExecutor()
-	rs, err := exec.ExecuteInternal(ctx, "select sql_digest, plan_digest from mysql.plan_replayer_task")
+	rs, err := exec.ExecuteInternal(ctx, "select sql_digest, plan_digest, est_error_threshold, latency_threshold from mysql.plan_replayer_task")
 	if err != nil {
 		return nil, err
 	}
@@ -316,15 +321,18 @@ func (h *planReplayerTaskCollectorHandle) collectAllPlanReplayerTask(ctx context
 	if rows, err = sqlexec.DrainRecordSet(ctx, rs, 8); err != nil {
 		return nil, errors.Trace(err)
 	}
-	allKeys := make([]replayer.PlanReplayerTaskKey, 0, len(rows))
+	allTasks := make(map[replayer.PlanReplayerTaskKey]replayer.PlanReplayerCaptureThresholds, len(rows))
 	for _, row := range rows {
 		sqlDigest, planDigest := row.GetString(0), row.GetString(1)
-		allKeys = append(allKeys, replayer.PlanReplayerTaskKey{
+		allTasks[replayer.PlanReplayerTaskKey{
 			SQLDigest:  sqlDigest,
 			PlanDigest: planDigest,
-		})
+		}] = replayer.PlanReplayerCaptureThresholds{
+			EstimationError: row.GetFloat64(2),
+			Latency:         time.Duration(row.GetFloat64(3) * float64(time.Second)),
+		}
 	}
-	return allKeys, nil
+	return allTasks, nil
 }
 
 type planReplayerDumpTaskStatus struct {
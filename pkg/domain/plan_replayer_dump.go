@@ -325,7 +325,7 @@ func DumpPlanReplayerInfo(ctx context.Context, sctx sessionctx.Context,
 	if task.IsCapture && task.IsContinuesCapture {
 		if !variable.EnableHistoricalStatsForCapture.Load() {
 			// Dump stats
-			fallbackMsg, err := dumpStats(zw, pairs, do, 0)
+			fallbackMsg, err := dumpStats(zw, pairs, do, 0, sessionVars.PlanReplayerStatsFormat)
 			if err != nil {
 				return err
 			}
@@ -341,7 +341,7 @@ func DumpPlanReplayerInfo(ctx context.Context, sctx sessionctx.Context,
 		}
 	} else {
 		// Dump stats
-		fallbackMsg, err := dumpStats(zw, pairs, do, task.HistoricalStatsTS)
+		fallbackMsg, err := dumpStats(zw, pairs, do, task.HistoricalStatsTS, sessionVars.PlanReplayerStatsFormat)
 		if err != nil {
 			return err
 		}
@@ -553,12 +553,19 @@ func dumpStatsMemStatus(zw *zip.Writer, pairs map[tableNamePair]struct{}, do *Do
 	return nil
 }
 
-func dumpStats(zw *zip.Writer, pairs map[tableNamePair]struct{}, do *Domain, historyStatsTS uint64) (string, error) {
+func dumpStats(zw *zip.Writer, pairs map[tableNamePair]struct{}, do *Domain, historyStatsTS uint64, statsFormat string) (string, error) {
 	allFallBackTbls := make([]string, 0)
 	for pair := range pairs {
 		if pair.IsView {
 			continue
 		}
+		// The historical stats are always dumped in json.
+		if historyStatsTS == 0 && statsFormat == variable.PlanReplayerStatsFormatBinary {
+			if err := dumpBinaryStats(zw, pair, do); err != nil {
+				return "", err
+			}
+			continue
+		}
 		jsonTbl, fallBackTbls, err := getStatsForTable(do, pair, historyStatsTS)
 		if err != nil {
 			return "", err
@@ -584,6 +591,20 @@ func dumpStats(zw *zip.Writer, pairs map[tableNamePair]struct{}, do *Domain, his
 	return msg, nil
 }
 
+// dumpBinaryStats streams the stats of the table into the zip file in the binary format,
+// so the stats of all the partitions are never held in memory at the same time.
+func dumpBinaryStats(zw *zip.Writer, pair tableNamePair, do *Domain) error {
+	tbl, err := do.InfoSchema().TableByName(context.Background(), model.NewCIStr(pair.DBName), model.NewCIStr(pair.TableName))
+	if err != nil {
+		return err
+	}
+	statsFw, err := zw.Create(fmt.Sprintf("stats/%v.%v.bin", pair.DBName, pair.TableName))
+	if err != nil {
+		return errors.AddStack(err)
+	}
+	return do.StatsHandle().DumpStatsToBinary(statsFw, pair.DBName, tbl.Meta(), 0, true)
+}
+
 func dumpSQLs(execStmts []ast.StmtNode, zw *zip.Writer) error {
 	for i, stmtExec := range execStmts {
 		zf, err := zw.Create(fmt.Sprintf("sql/sql%v.sql", i))
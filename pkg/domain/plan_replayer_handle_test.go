@@ -15,12 +15,14 @@
 package domain_test
 
 import (
+	"archive/zip"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/pingcap/tidb/pkg/domain"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/pingcap/tidb/pkg/util/replayer"
 	"github.com/stretchr/testify/require"
@@ -204,3 +206,48 @@ SELECT * from tableA where SUBSTRING_INDEX(tableA.columnC, '_', 1) = tableA.colu
 	).Rows()
 	require.Len(t, rows, 1)
 }
+
+func TestPlanReplayerSnapshot(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	prHandle := dom.GetPlanReplayerHandle()
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, index idx(a))")
+	tk.MustExec("create view v as select * from t")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk.MustExec("analyze table t")
+	tk.MustExec("create global binding for select * from t where a = 1 using select * from t use index(idx) where a = 1")
+
+	dir := t.TempDir()
+	tk.MustExec(fmt.Sprintf("set @@global.tidb_plan_replayer_snapshot_storage = '%s'", dir))
+	tk.MustExec("set @@global.tidb_plan_replayer_snapshot_max_count = 2")
+	defer func() {
+		tk.MustExec("set @@global.tidb_plan_replayer_snapshot_storage = default")
+		tk.MustExec("set @@global.tidb_plan_replayer_snapshot_max_count = default")
+	}()
+	fileNames := make([]string, 0, 3)
+	for i := 0; i < 3; i++ {
+		fileName, err := prHandle.CapturePlanReplayerSnapshot()
+		require.NoError(t, err)
+		fileNames = append(fileNames, fileName)
+	}
+
+	// Only the latest 2 snapshots are kept.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, fileNames[1:], []string{entries[0].Name(), entries[1].Name()})
+
+	z, err := zip.OpenReader(filepath.Join(dir, fileNames[2]))
+	require.NoError(t, err)
+	defer z.Close()
+	files := make(map[string]struct{}, len(z.File))
+	for _, f := range z.File {
+		files[f.Name] = struct{}{}
+	}
+	require.Contains(t, files, "schema/test.t.schema.txt")
+	require.Contains(t, files, "view/test.v.view.txt")
+	require.Contains(t, files, "stats/test.t.json")
+	require.Contains(t, files, domain.PlanReplayerGlobalBindingFile)
+	require.NotContains(t, files, "schema/mysql.user.schema.txt")
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/replayer"
+	"go.uber.org/zap"
+)
+
+const (
+	// planReplayerSnapshotPrefix is the prefix of the file names of the plan replayer snapshots.
+	planReplayerSnapshotPrefix = "snapshot_replayer_"
+	// planReplayerSnapshotMaxTables is the max number of the tables dumped in a plan replayer snapshot, which bounds
+	// the size of a snapshot.
+	planReplayerSnapshotMaxTables = 1000
+)
+
+// planReplayerSnapshotHandle captures the plan replayer snapshots periodically. A snapshot is a plan replayer file
+// containing the schemas, the stats and the global bindings of the user tables, which tells what the optimizer knew
+// at that time. The snapshots are written to tidb_plan_replayer_snapshot_storage, and only the latest
+// tidb_plan_replayer_snapshot_max_count ones are kept. The snapshots can be loaded by `PLAN REPLAYER LOAD`.
+type planReplayerSnapshotHandle struct {
+	ctx  context.Context
+	sctx sessionctx.Context
+
+	lastCaptureTime time.Time
+}
+
+// capturePlanReplayerSnapshotIfNeeded captures a snapshot if tidb_plan_replayer_snapshot_interval has elapsed since
+// the last one. Only the DDL owner captures the snapshots, since they are the same on all the instances.
+func (h *planReplayerSnapshotHandle) capturePlanReplayerSnapshotIfNeeded(isOwner bool) {
+	interval := variable.PlanReplayerSnapshotInterval.Load()
+	if interval <= 0 || !isOwner || time.Since(h.lastCaptureTime) < interval {
+		return
+	}
+	h.lastCaptureTime = time.Now()
+	if _, err := h.CapturePlanReplayerSnapshot(); err != nil {
+		logutil.BgLogger().Warn("capture plan replayer snapshot failed", zap.String("category", "plan-replayer-snapshot"), zap.Error(err))
+	}
+}
+
+// CapturePlanReplayerSnapshot captures a plan replayer snapshot and removes the oldest ones beyond
+// tidb_plan_replayer_snapshot_max_count. It returns the file name of the snapshot.
+func (h *planReplayerSnapshotHandle) CapturePlanReplayerSnapshot() (string, error) {
+	store, err := openPlanReplayerSnapshotStorage(h.ctx, variable.PlanReplayerSnapshotStorage.Load())
+	if err != nil {
+		return "", err
+	}
+	defer store.Close()
+
+	var buf bytes.Buffer
+	if err = dumpPlanReplayerSnapshot(h.ctx, h.sctx, &buf); err != nil {
+		return "", err
+	}
+	// The timestamps have the same number of digits, so the file names are ordered by the time.
+	fileName := fmt.Sprintf("%s%d.zip", planReplayerSnapshotPrefix, time.Now().UnixNano())
+	if err = store.WriteFile(h.ctx, fileName, buf.Bytes()); err != nil {
+		return "", errors.Trace(err)
+	}
+	logutil.BgLogger().Info("plan replayer snapshot captured", zap.String("category", "plan-replayer-snapshot"),
+		zap.String("filename", fileName), zap.Int("size", buf.Len()))
+	return fileName, rotatePlanReplayerSnapshots(h.ctx, store, int(variable.PlanReplayerSnapshotMaxCount.Load()))
+}
+
+func openPlanReplayerSnapshotStorage(ctx context.Context, uri string) (storage.ExternalStorage, error) {
+	if len(uri) == 0 {
+		uri = replayer.GetPlanReplayerSnapshotDirName()
+	}
+	backend, err := storage.ParseBackend(uri, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	store, err := storage.NewWithDefaultOpt(ctx, backend)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return store, nil
+}
+
+// dumpPlanReplayerSnapshot dumps the schemas, the stats and the global bindings of the user tables in the layout of
+// the plan replayer files.
+func dumpPlanReplayerSnapshot(ctx context.Context, sctx sessionctx.Context, buf *bytes.Buffer) (err error) {
+	zw := zip.NewWriter(buf)
+	defer func() {
+		if err1 := zw.Close(); err1 != nil && err == nil {
+			err = errors.AddStack(err1)
+		}
+	}()
+	do := GetDomain(sctx)
+	pairs, skipped, err := collectPlanReplayerSnapshotTables(ctx, do)
+	if err != nil {
+		return err
+	}
+	var errMsgs []string
+	if skipped > 0 {
+		errMsgs = append(errMsgs, fmt.Sprintf("%d tables are skipped since there are more than %d tables", skipped, planReplayerSnapshotMaxTables))
+	}
+	if err = dumpConfig(zw); err != nil {
+		return err
+	}
+	if err = dumpMeta(zw); err != nil {
+		return err
+	}
+	if err = dumpSchemas(sctx, zw, pairs); err != nil {
+		return err
+	}
+	if err = dumpTiFlashReplica(sctx, zw, pairs); err != nil {
+		return err
+	}
+	if _, err = dumpStats(zw, pairs, do, 0, variable.PlanReplayerStatsFormatJSON); err != nil {
+		return err
+	}
+	if err = dumpStatsMemStatus(zw, pairs, do); err != nil {
+		return err
+	}
+	if err = dumpGlobalBindings(sctx, zw); err != nil {
+		return err
+	}
+	if len(errMsgs) > 0 {
+		return dumpErrorMsgs(zw, errMsgs)
+	}
+	return nil
+}
+
+// collectPlanReplayerSnapshotTables returns the tables and views of the user schemas, and the number of the tables
+// skipped since there are more than planReplayerSnapshotMaxTables tables.
+func collectPlanReplayerSnapshotTables(ctx context.Context, do *Domain) (map[tableNamePair]struct{}, int, error) {
+	is := do.InfoSchema()
+	dbNames := is.AllSchemaNames()
+	slices.SortFunc(dbNames, func(a, b model.CIStr) int {
+		return strings.Compare(a.L, b.L)
+	})
+	pairs := make(map[tableNamePair]struct{})
+	skipped := 0
+	for _, dbName := range dbNames {
+		if util.IsMemOrSysDB(dbName.L) {
+			continue
+		}
+		tblInfos, err := is.SchemaTableInfos(ctx, dbName)
+		if err != nil {
+			return nil, 0, errors.Trace(err)
+		}
+		for _, tblInfo := range tblInfos {
+			if tblInfo.IsSequence() {
+				continue
+			}
+			if len(pairs) >= planReplayerSnapshotMaxTables {
+				skipped++
+				continue
+			}
+			pairs[tableNamePair{DBName: dbName.O, TableName: tblInfo.Name.O, IsView: tblInfo.IsView()}] = struct{}{}
+		}
+	}
+	return pairs, skipped, nil
+}
+
+// rotatePlanReplayerSnapshots removes the oldest snapshots in the storage until at most maxCount ones are left.
+func rotatePlanReplayerSnapshots(ctx context.Context, store storage.ExternalStorage, maxCount int) error {
+	var fileNames []string
+	err := store.WalkDir(ctx, &storage.WalkOption{SkipSubDir: true, ObjPrefix: planReplayerSnapshotPrefix}, func(path string, _ int64) error {
+		name := strings.TrimSuffix(path, ".zip")
+		if len(name) == len(path) || !strings.HasPrefix(name, planReplayerSnapshotPrefix) {
+			return nil
+		}
+		if _, err := strconv.ParseInt(name[len(planReplayerSnapshotPrefix):], 10, 64); err != nil {
+			return nil
+		}
+		fileNames = append(fileNames, path)
+		return nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(fileNames) <= maxCount {
+		return nil
+	}
+	slices.Sort(fileNames)
+	removed := fileNames[:len(fileNames)-maxCount]
+	if err = store.DeleteFiles(ctx, removed); err != nil {
+		return errors.Trace(err)
+	}
+	logutil.BgLogger().Info("plan replayer snapshots removed", zap.String("category", "plan-replayer-snapshot"),
+		zap.Strings("filenames", removed))
+	return nil
+}
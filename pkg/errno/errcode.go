@@ -1146,6 +1146,12 @@ const (
 
 	ErrWarnGlobalIndexNeedManuallyAnalyze = 8265
 
+	ErrInstanceReadOnly = 8266
+
+	ErrObjectHasDependents = 8267
+
+	ErrUnsupportedDropRange = 8268
+
 	// Resource group errors.
 	ErrResourceGroupExists                    = 8248
 	ErrResourceGroupNotExists                 = 8249
@@ -1179,4 +1179,10 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrGlobalIndexNotExplicitlySet: mysql.Message("Global Index is needed for index '%-.192s', since the unique index is not including all partitioning columns, and GLOBAL is not given as IndexOption", nil),
 
 	ErrWarnGlobalIndexNeedManuallyAnalyze: mysql.Message("Auto analyze is not effective for index '%-.192s', need analyze manually", nil),
+
+	ErrInstanceReadOnly: mysql.Message("The statement is rejected since this TiDB instance is in read-only mode, please retry on another instance", nil),
+
+	ErrObjectHasDependents: mysql.Message("%s '%-.192s' is referenced by %s '%-.192s' through %s", nil),
+
+	ErrUnsupportedDropRange: mysql.Message("Unsupported DROP RANGE: %s", nil),
 }
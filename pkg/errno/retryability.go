@@ -0,0 +1,88 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errno
+
+import "fmt"
+
+// ConflictClass classifies why a statement or transaction failed, so that clients
+// can choose a retry policy without parsing the error message.
+type ConflictClass string
+
+const (
+	// ConflictClassNone means the error is not caused by a conflict or a transient failure.
+	ConflictClassNone ConflictClass = "none"
+	// ConflictClassWriteConflict means the transaction conflicts with another transaction.
+	ConflictClassWriteConflict ConflictClass = "write_conflict"
+	// ConflictClassDeadlock means the transaction is rolled back to break a deadlock.
+	ConflictClassDeadlock ConflictClass = "deadlock"
+	// ConflictClassLockWait means the statement failed to acquire a lock in time.
+	ConflictClassLockWait ConflictClass = "lock_wait"
+	// ConflictClassSchemaChange means the schema is changed while the transaction is running.
+	ConflictClassSchemaChange ConflictClass = "schema_change"
+	// ConflictClassServerBusy means the storage or the server is temporarily unavailable or overloaded.
+	ConflictClassServerBusy ConflictClass = "server_busy"
+	// ConflictClassTimeout means a request to the storage or the PD timed out.
+	ConflictClassTimeout ConflictClass = "timeout"
+	// ConflictClassDuplicateKey means the statement violates a unique constraint.
+	ConflictClassDuplicateKey ConflictClass = "duplicate_key"
+)
+
+// ErrorAttributes is the machine-readable metadata of an error.
+type ErrorAttributes struct {
+	// Retryable indicates whether the whole transaction can be retried safely by the client.
+	Retryable bool
+	// ConflictClass classifies the cause of the error.
+	ConflictClass ConflictClass
+}
+
+// String implements the fmt.Stringer interface. It's the format appended to the error message.
+func (a ErrorAttributes) String() string {
+	return fmt.Sprintf("retryable=%t, conflict_class=%s", a.Retryable, a.ConflictClass)
+}
+
+// errorAttributes is the catalog of the errors which have known retryability.
+// Errors that are not listed here are not retryable.
+var errorAttributes = map[uint16]ErrorAttributes{
+	ErrWriteConflict:               {Retryable: true, ConflictClass: ConflictClassWriteConflict},
+	ErrWriteConflictInTiDB:         {Retryable: true, ConflictClass: ConflictClassWriteConflict},
+	ErrTxnRetryable:                {Retryable: true, ConflictClass: ConflictClassWriteConflict},
+	ErrLockDeadlock:                {Retryable: true, ConflictClass: ConflictClassDeadlock},
+	ErrLockWaitTimeout:             {Retryable: true, ConflictClass: ConflictClassLockWait},
+	ErrLockAcquireFailAndNoWaitSet: {Retryable: true, ConflictClass: ConflictClassLockWait},
+	ErrLockExpire:                  {Retryable: true, ConflictClass: ConflictClassLockWait},
+	ErrInfoSchemaExpired:           {Retryable: true, ConflictClass: ConflictClassSchemaChange},
+	ErrInfoSchemaChanged:           {Retryable: true, ConflictClass: ConflictClassSchemaChange},
+	ErrTiKVServerBusy:              {Retryable: true, ConflictClass: ConflictClassServerBusy},
+	ErrTiFlashServerBusy:           {Retryable: true, ConflictClass: ConflictClassServerBusy},
+	ErrRegionUnavailable:           {Retryable: true, ConflictClass: ConflictClassServerBusy},
+	ErrTiKVStoreLimit:              {Retryable: true, ConflictClass: ConflictClassServerBusy},
+	ErrTiKVStaleCommand:            {Retryable: true, ConflictClass: ConflictClassServerBusy},
+	ErrTiKVMaxTimestampNotSynced:   {Retryable: true, ConflictClass: ConflictClassServerBusy},
+	ErrResourceGroupThrottled:      {Retryable: true, ConflictClass: ConflictClassServerBusy},
+	ErrInstanceReadOnly:            {Retryable: true, ConflictClass: ConflictClassServerBusy},
+	ErrPDServerTimeout:             {Retryable: true, ConflictClass: ConflictClassTimeout},
+	ErrTiKVServerTimeout:           {Retryable: true, ConflictClass: ConflictClassTimeout},
+	ErrTiFlashServerTimeout:        {Retryable: true, ConflictClass: ConflictClassTimeout},
+	ErrResolveLockTimeout:          {Retryable: true, ConflictClass: ConflictClassTimeout},
+	ErrDupEntry:                    {Retryable: false, ConflictClass: ConflictClassDuplicateKey},
+}
+
+// GetErrorAttributes returns the attributes of the error code.
+func GetErrorAttributes(code uint16) ErrorAttributes {
+	if attrs, ok := errorAttributes[code]; ok {
+		return attrs
+	}
+	return ErrorAttributes{Retryable: false, ConflictClass: ConflictClassNone}
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errno
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetErrorAttributes(t *testing.T) {
+	attrs := GetErrorAttributes(ErrWriteConflict)
+	assert.True(t, attrs.Retryable)
+	assert.Equal(t, ConflictClassWriteConflict, attrs.ConflictClass)
+	assert.Equal(t, "retryable=true, conflict_class=write_conflict", attrs.String())
+
+	attrs = GetErrorAttributes(ErrLockDeadlock)
+	assert.True(t, attrs.Retryable)
+	assert.Equal(t, ConflictClassDeadlock, attrs.ConflictClass)
+
+	attrs = GetErrorAttributes(ErrDupEntry)
+	assert.False(t, attrs.Retryable)
+	assert.Equal(t, ConflictClassDuplicateKey, attrs.ConflictClass)
+
+	// The errors which are not in the catalog are not retryable.
+	attrs = GetErrorAttributes(ErrNoSuchTable)
+	assert.False(t, attrs.Retryable)
+	assert.Equal(t, ConflictClassNone, attrs.ConflictClass)
+	assert.Equal(t, "retryable=false, conflict_class=none", attrs.String())
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"runtime/trace"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -59,6 +60,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/sessiontxn"
 	"github.com/pingcap/tidb/pkg/sessiontxn/staleread"
+	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/types"
 	util2 "github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/breakpoint"
@@ -549,7 +551,10 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 		stmtCtx := sessionVars.StmtCtx
 		_, planDigest := GetPlanDigest(stmtCtx)
 		_, sqlDigest := stmtCtx.SQLDigest()
-		stmtCtx.RunawayChecker = rm.DeriveChecker(stmtCtx.ResourceGroupName, stmtCtx.OriginalSQL, sqlDigest.String(), planDigest.String(), sessionVars.StartTime)
+		// ANALYZE has no plan digest, but the manual ones are checked since they may scan the whole tables.
+		_, isAnalyze := a.StmtNode.(*ast.AnalyzeTableStmt)
+		checkAnalyze := isAnalyze && !sessionVars.InRestrictedSQL
+		stmtCtx.RunawayChecker = rm.DeriveChecker(stmtCtx.ResourceGroupName, stmtCtx.OriginalSQL, sqlDigest.String(), planDigest.String(), sessionVars.StartTime, checkAnalyze)
 		switchGroupName, err := stmtCtx.RunawayChecker.BeforeExecutor()
 		if err != nil {
 			return nil, err
@@ -1419,6 +1424,12 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults boo
 	a.LogSlowQuery(txnTS, succ, hasMoreResults)
 	a.SummaryStmt(succ)
 	a.observeStmtFinishedForTopSQL()
+	if succ && !sessVars.InRestrictedSQL && variable.EnableCardinalityFeedback.Load() {
+		recordCardinalityFeedback(sessVars.StmtCtx)
+	}
+	if succ && !sessVars.InRestrictedSQL {
+		recordPseudoStatsUsage(sessVars.StmtCtx)
+	}
 	if sessVars.StmtCtx.IsTiFlash.Load() {
 		if succ {
 			executor_metrics.TotalTiFlashQuerySuccCounter.Inc()
@@ -1535,7 +1546,7 @@ func (a *ExecStmt) checkPlanReplayerCapture(txnTS uint64) {
 				checkPlanReplayerContinuesCapture(se, stmtNode, txnTS)
 			}
 		} else {
-			checkPlanReplayerCaptureTask(se, stmtNode, txnTS)
+			checkPlanReplayerCaptureTask(se, stmtNode, a.Plan, txnTS)
 		}
 	}
 }
@@ -1801,6 +1812,166 @@ func GetResultRowsCount(stmtCtx *stmtctx.StatementContext, p base.Plan) int64 {
 	return rootStats.GetActRows()
 }
 
+// getIndexMergeIntersectionRows sums up the estimated and actual rows of the intersections of the intersection type
+// index merge readers in the plan, which are the rows scanned by the table side of the readers. It's the feedback of
+// the estimation of the intersection size.
+func getIndexMergeIntersectionRows(stmtCtx *stmtctx.StatementContext) (found bool, estRows float64, actRows int64) {
+	runtimeStatsColl := stmtCtx.RuntimeStatsColl
+	if runtimeStatsColl == nil {
+		return false, 0, 0
+	}
+	flat := getFlatPlan(stmtCtx)
+	if flat == nil {
+		return false, 0, 0
+	}
+	for _, op := range flat.Main {
+		reader, ok := op.Origin.(*plannercore.PhysicalIndexMergeReader)
+		if !ok || !reader.IsIntersectionType || len(reader.TablePlans) == 0 {
+			continue
+		}
+		ts := reader.TablePlans[0]
+		if !runtimeStatsColl.ExistsCopStats(ts.ID()) {
+			continue
+		}
+		found = true
+		estRows += ts.StatsCount()
+		actRows += runtimeStatsColl.GetCopStats(ts.ID()).GetActRows()
+	}
+	return found, estRows, actRows
+}
+
+// getMaxEstimationError returns the max q-error of the row count estimations of the operators in the plan, where the
+// q-error is max(est/act, act/est), and both the estimated and actual rows are regarded as at least 1. It returns 0 if
+// the estimations are not comparable with the actual rows.
+func getMaxEstimationError(stmtCtx *stmtctx.StatementContext) float64 {
+	runtimeStatsColl := stmtCtx.RuntimeStatsColl
+	if runtimeStatsColl == nil {
+		return 0
+	}
+	flat := getFlatPlan(stmtCtx)
+	if flat == nil {
+		return 0
+	}
+	var maxQError float64
+	for _, op := range flat.Main {
+		switch op.Origin.(type) {
+		case *plannercore.PhysicalLimit, *plannercore.PhysicalIndexJoin, *plannercore.PhysicalIndexHashJoin,
+			*plannercore.PhysicalIndexMergeJoin, *plannercore.PhysicalApply:
+			// The operators may stop early or be executed several times with different ranges,
+			// so the actual rows are not comparable with the estimated ones.
+			return 0
+		}
+		p, ok := op.Origin.(base.PhysicalPlan)
+		if !ok || p.StatsInfo() == nil {
+			continue
+		}
+		var actRows int64
+		if op.IsRoot {
+			if !runtimeStatsColl.ExistsRootStats(p.ID()) {
+				continue
+			}
+			actRows = runtimeStatsColl.GetRootStats(p.ID()).GetActRows()
+		} else {
+			if !runtimeStatsColl.ExistsCopStats(p.ID()) {
+				continue
+			}
+			actRows = runtimeStatsColl.GetCopStats(p.ID()).GetActRows()
+		}
+		est, act := max(p.StatsCount(), 1), max(float64(actRows), 1)
+		maxQError = max(maxQError, est/act, act/est)
+	}
+	return maxQError
+}
+
+// getUsedStatsQuality returns whether any table is estimated by the pseudo stats or the outdated stats, and the
+// versions of the used stats in the format of "table:version,...", where the version of the pseudo stats is "pseudo".
+func getUsedStatsQuality(stmtCtx *stmtctx.StatementContext) (pseudo, outdated bool, versions string) {
+	usedStats := stmtCtx.GetUsedStatsInfo(false)
+	keys := usedStats.Keys()
+	if len(keys) == 0 {
+		return false, false, ""
+	}
+	slices.Sort(keys)
+	var b strings.Builder
+	for _, id := range keys {
+		info := usedStats.GetUsedInfo(id)
+		if info == nil {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(info.Name)
+		b.WriteString(":")
+		if info.Version == statistics.PseudoVersion {
+			pseudo = true
+			b.WriteString("pseudo")
+		} else {
+			b.WriteString(strconv.FormatUint(info.Version, 10))
+		}
+		outdated = outdated || info.Outdated
+	}
+	return pseudo, outdated, b.String()
+}
+
+// recordCardinalityFeedback records the estimated and actual rows of the table scans and index scans in the plan to
+// statistics.CardinalityFeedback.
+func recordCardinalityFeedback(stmtCtx *stmtctx.StatementContext) {
+	runtimeStatsColl := stmtCtx.RuntimeStatsColl
+	if runtimeStatsColl == nil {
+		return
+	}
+	flat := getFlatPlan(stmtCtx)
+	if flat == nil {
+		return
+	}
+	usedStats := stmtCtx.GetUsedStatsInfo(false)
+	if usedStats == nil {
+		return
+	}
+	type scan struct {
+		physicalID int64
+		estRows    float64
+		planID     int
+	}
+	scans := make([]scan, 0, 2)
+	for _, op := range flat.Main {
+		switch x := op.Origin.(type) {
+		case *plannercore.PhysicalLimit, *plannercore.PhysicalIndexJoin, *plannercore.PhysicalIndexHashJoin,
+			*plannercore.PhysicalIndexMergeJoin, *plannercore.PhysicalApply:
+			// The scans may stop early or be executed several times with different ranges,
+			// so the actual rows are not comparable with the estimated ones.
+			return
+		case *plannercore.PhysicalTableScan:
+			scans = append(scans, scan{physicalID: x.PhysicalTableID(), estRows: x.StatsCount(), planID: x.ID()})
+		case *plannercore.PhysicalIndexScan:
+			scans = append(scans, scan{physicalID: x.PhysicalTableID(), estRows: x.StatsCount(), planID: x.ID()})
+		}
+	}
+	now := time.Now()
+	for _, s := range scans {
+		if !runtimeStatsColl.ExistsCopStats(s.planID) {
+			continue
+		}
+		// Only the estimations based on the real stats are meaningful.
+		if info := usedStats.GetUsedInfo(s.physicalID); info == nil || info.Version == statistics.PseudoVersion {
+			continue
+		}
+		statistics.CardinalityFeedback.Record(s.physicalID, s.estRows, runtimeStatsColl.GetCopStats(s.planID).GetActRows(), now)
+	}
+}
+
+// recordPseudoStatsUsage records the tables estimated by the pseudo stats in the statement to
+// statistics.PseudoStatsUsage.
+func recordPseudoStatsUsage(stmtCtx *stmtctx.StatementContext) {
+	usedStats := stmtCtx.GetUsedStatsInfo(false)
+	for _, id := range usedStats.Keys() {
+		if info := usedStats.GetUsedInfo(id); info != nil && info.Version == statistics.PseudoVersion {
+			statistics.PseudoStatsUsage.Record(id)
+		}
+	}
+}
+
 // getFlatPlan generates a FlatPhysicalPlan from the plan stored in stmtCtx.plan,
 // then stores it in stmtCtx.flatPlan.
 func getFlatPlan(stmtCtx *stmtctx.StatementContext) *plannercore.FlatPhysicalPlan {
@@ -2047,6 +2218,10 @@ func (a *ExecStmt) SummaryStmt(succ bool) {
 
 		PlanCacheUnqualified: sessVars.StmtCtx.PlanCacheUnqualified(),
 	}
+	stmtExecInfo.IndexMergeIntersection, stmtExecInfo.IndexMergeIntersectionEstRows, stmtExecInfo.IndexMergeIntersectionActRows =
+		getIndexMergeIntersectionRows(stmtCtx)
+	stmtExecInfo.UsedPseudoStats, stmtExecInfo.UsedOutdatedStats, stmtExecInfo.StatsVersions = getUsedStatsQuality(stmtCtx)
+	stmtExecInfo.MaxEstimationError = getMaxEstimationError(stmtCtx)
 	if a.retryCount > 0 {
 		stmtExecInfo.ExecRetryTime = costTime - sessVars.DurationParse - sessVars.DurationCompile - time.Since(a.retryStartTime)
 	}
@@ -2187,7 +2362,7 @@ func checkPlanReplayerContinuesCaptureValidStmt(stmtNode ast.StmtNode) bool {
 	}
 }
 
-func checkPlanReplayerCaptureTask(sctx sessionctx.Context, stmtNode ast.StmtNode, startTS uint64) {
+func checkPlanReplayerCaptureTask(sctx sessionctx.Context, stmtNode ast.StmtNode, p base.Plan, startTS uint64) {
 	dom := domain.GetDomain(sctx)
 	if dom == nil {
 		return
@@ -2212,6 +2387,9 @@ func checkPlanReplayerCaptureTask(sctx sessionctx.Context, stmtNode ast.StmtNode
 	for _, task := range tasks {
 		if task.SQLDigest == sqlDigest.String() {
 			if task.PlanDigest == "*" || task.PlanDigest == planDigest.String() {
+				if thresholds, ok := handle.GetTaskThresholds(task); ok && !exceedPlanReplayerCaptureThresholds(sctx, p, thresholds) {
+					continue
+				}
 				sendPlanReplayerDumpTask(key, sctx, stmtNode, startTS, false)
 				return
 			}
@@ -2219,6 +2397,17 @@ func checkPlanReplayerCaptureTask(sctx sessionctx.Context, stmtNode ast.StmtNode
 	}
 }
 
+// exceedPlanReplayerCaptureThresholds checks whether the execution exceeds any of the thresholds of the capture task.
+func exceedPlanReplayerCaptureThresholds(sctx sessionctx.Context, p base.Plan, thresholds replayer.PlanReplayerCaptureThresholds) bool {
+	if !thresholds.IsSet() {
+		return true
+	}
+	if thresholds.Latency > 0 && sctx.GetSessionVars().GetTotalCostDuration() > thresholds.Latency {
+		return true
+	}
+	return thresholds.EstimationError > 0 && p != nil && plannercore.GetMaxEstimationError(p) > thresholds.EstimationError
+}
+
 func checkPlanReplayerContinuesCapture(sctx sessionctx.Context, stmtNode ast.StmtNode, startTS uint64) {
 	dom := domain.GetDomain(sctx)
 	if dom == nil {
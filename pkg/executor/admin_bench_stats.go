@@ -0,0 +1,79 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+)
+
+// AdminBenchStatsLoadExec represents an admin bench stats load executor.
+// It loads the stats of the table from storage and reports the load time and memory usage
+// of every column and index, without touching the stats cache.
+type AdminBenchStatsLoadExec struct {
+	exec.BaseExecutor
+
+	dbName  string
+	tblInfo *model.TableInfo
+	done    bool
+}
+
+var _ exec.Executor = &AdminBenchStatsLoadExec{}
+
+// Next implements the Executor Next interface.
+func (e *AdminBenchStatsLoadExec) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	statsHandle := domain.GetDomain(e.Ctx()).StatsHandle()
+	physicalIDs := []int64{e.tblInfo.ID}
+	partitionNames := []string{""}
+	if pi := e.tblInfo.GetPartitionInfo(); pi != nil {
+		partitionNames[0] = "global"
+		for _, def := range pi.Definitions {
+			physicalIDs = append(physicalIDs, def.ID)
+			partitionNames = append(partitionNames, def.Name.O)
+		}
+	}
+	for i, physicalID := range physicalIDs {
+		items, err := statsHandle.BenchLoadStats(e.tblInfo, physicalID)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			req.AppendString(0, e.dbName)
+			req.AppendString(1, e.tblInfo.Name.O)
+			req.AppendString(2, partitionNames[i])
+			req.AppendString(3, item.Name)
+			if item.IsIndex {
+				req.AppendInt64(4, 1)
+			} else {
+				req.AppendInt64(4, 0)
+			}
+			req.AppendInt64(5, int64(item.Buckets))
+			req.AppendInt64(6, int64(item.TopNs))
+			req.AppendString(7, item.LoadTime.String())
+			req.AppendInt64(8, item.MemoryUsage)
+		}
+	}
+	return nil
+}
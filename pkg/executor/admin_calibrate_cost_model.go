@@ -0,0 +1,230 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/kv"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// calibrateCostModelTable is the table of the benchmarks, which is dropped after the calibration.
+	calibrateCostModelTable = "cost_model_calibration"
+	// calibrateCostModelRows is the number of the rows in the table of the benchmarks.
+	calibrateCostModelRows = 100000
+	// calibrateCostModelRuns is the number of the runs of each benchmark, whose fastest one is taken to reduce the noise.
+	calibrateCostModelRuns = 3
+	// calibrateRowSize and calibrateIndexRowSize are the sizes of the rows of the table and its index idx_k, which are
+	// the row sizes used by the cost model for the same plans.
+	calibrateRowSize      = 80.0
+	calibrateIndexRowSize = 16.0
+	// calibrateMaxDeviation bounds the calibrated factors around their defaults, so a noisy benchmark can't make the
+	// cost model choose absurd plans.
+	calibrateMaxDeviation = 100.0
+)
+
+// calibrateResult is the result of the benchmark of a factor.
+type calibrateResult struct {
+	factor    string
+	benchmark string
+	value     float64
+	msg       string
+}
+
+// AdminCalibrateCostModelExec represents an `ADMIN CALIBRATE COST MODEL` executor.
+// It runs a few micro-benchmarks on a scratch table in the mysql schema, and calibrates the factors of cost model v2 by
+// the running time of them on the current hardware:
+//   - scan: a table scan, whose time per unit is the reference of the other factors, so tikv_scan_factor keeps its
+//     default value and the other factors are calibrated relative to it.
+//   - exchange: transferring the rows of a table scan from TiKV to TiDB, which calibrates tidb_kv_net_factor.
+//   - lookup: an index lookup, whose extra time over the scans is the cost of the double read requests, which
+//     calibrates tidb_request_factor.
+//   - hash join: a hash join of two table scans, which calibrates tidb_cpu_factor.
+//
+// The factors are stored in tidb_cost_model_v2_calibrated_factors, which are used by cost model v2 on all the instances.
+// The factors of TiFlash are not calibrated since the table of the benchmarks has no TiFlash replica.
+type AdminCalibrateCostModelExec struct {
+	exec.BaseExecutor
+
+	done bool
+}
+
+var _ exec.Executor = &AdminCalibrateCostModelExec{}
+
+// Next implements the Executor Next interface.
+func (e *AdminCalibrateCostModelExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnAdmin)
+	rows := calibrateCostModelRows
+	failpoint.Inject("mockCalibrateCostModelRows", func(val failpoint.Value) {
+		rows = val.(int)
+	})
+	results, err := e.calibrate(ctx, rows)
+	if err != nil {
+		return err
+	}
+
+	factors := make(map[string]float64, len(results))
+	for _, r := range results {
+		defaultValue, _ := plannercore.DefaultCostModelV2Factor(r.factor)
+		if r.msg == "" {
+			factors[r.factor] = r.value
+		} else {
+			r.value = defaultValue
+		}
+		req.AppendString(0, r.factor)
+		req.AppendString(1, r.benchmark)
+		req.AppendFloat64(2, defaultValue)
+		req.AppendFloat64(3, r.value)
+		req.AppendString(4, r.msg)
+	}
+	value, err := json.Marshal(factors)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logutil.Logger(ctx).Info("cost model calibrated", zap.String("factors", string(value)))
+	return e.Ctx().GetSessionVars().GlobalVarsAccessor.SetGlobalSysVar(ctx, variable.TiDBCostModelV2CalibratedFactors, string(value))
+}
+
+// calibrate prepares the table of the benchmarks, runs them and returns the calibrated factors. The factors whose
+// benchmarks are not reliable are returned with the reasons in msg.
+func (e *AdminCalibrateCostModelExec) calibrate(ctx context.Context, rows int) (results []calibrateResult, err error) {
+	sqlExec := e.Ctx().GetRestrictedSQLExecutor()
+	if _, _, err = sqlExec.ExecRestrictedSQL(ctx, nil, "DROP TABLE IF EXISTS mysql.%n", calibrateCostModelTable); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, _, err = sqlExec.ExecRestrictedSQL(ctx, nil, `CREATE TABLE mysql.%n (id BIGINT PRIMARY KEY CLUSTERED, k BIGINT NOT NULL,
+		v VARCHAR(64) NOT NULL, KEY idx_k(k))`, calibrateCostModelTable); err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		if _, _, dropErr := sqlExec.ExecRestrictedSQL(ctx, nil, "DROP TABLE IF EXISTS mysql.%n", calibrateCostModelTable); dropErr != nil && err == nil {
+			err = errors.Trace(dropErr)
+		}
+	}()
+	// The rows are doubled by each INSERT, and the index values are a permutation of the handles, so the index lookup
+	// reads the rows of the table randomly.
+	if _, _, err = sqlExec.ExecRestrictedSQL(ctx, nil, "INSERT INTO mysql.%n VALUES (1, MOD(7919, %?), REPEAT('x', 64))",
+		calibrateCostModelTable, rows); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for n := 1; n < rows; n *= 2 {
+		if _, _, err = sqlExec.ExecRestrictedSQL(ctx, nil, `INSERT INTO mysql.%n SELECT id + %?, MOD((id + %?) * 7919, %?), v
+			FROM mysql.%n WHERE id + %? <= %?`, calibrateCostModelTable, n, n, rows, calibrateCostModelTable, n, rows); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	run := func(sql string, args ...any) (time.Duration, error) {
+		var fastest time.Duration
+		for i := 0; i < calibrateCostModelRuns; i++ {
+			start := time.Now()
+			if _, _, err := sqlExec.ExecRestrictedSQL(ctx, nil, sql, args...); err != nil {
+				return 0, errors.Trace(err)
+			}
+			if elapsed := time.Since(start); i == 0 || elapsed < fastest {
+				fastest = elapsed
+			}
+		}
+		return fastest, nil
+	}
+	scanTime, err := run("SELECT /*+ ignore_index(t, idx_k) */ COUNT(*) FROM mysql.%n t", calibrateCostModelTable)
+	if err != nil {
+		return nil, err
+	}
+	readTime, err := run("SELECT /*+ ignore_index(t, idx_k) */ * FROM mysql.%n t", calibrateCostModelTable)
+	if err != nil {
+		return nil, err
+	}
+	lookupTime, err := run("SELECT /*+ use_index(t, idx_k) */ * FROM mysql.%n t WHERE t.k >= 0", calibrateCostModelTable)
+	if err != nil {
+		return nil, err
+	}
+	joinTime, err := run(`SELECT /*+ hash_join(t1, t2), ignore_index(t1, idx_k), ignore_index(t2, idx_k) */ COUNT(*)
+		FROM mysql.%n t1 JOIN mysql.%n t2 ON t1.id = t2.k`, calibrateCostModelTable, calibrateCostModelTable)
+	if err != nil {
+		return nil, err
+	}
+	return calibrateFactors(float64(rows), scanTime, readTime, lookupTime, joinTime), nil
+}
+
+// calibrateFactors calibrates the factors by the running time of the benchmarks. The time per unit of each factor is
+// the time of its benchmark excluding the parts of the other factors, divided by the units in the cost formula of the
+// benchmark. The units of a factor are scaled by the ratio of the default scan factor to the time per unit of the scan.
+func calibrateFactors(rows float64, scanTime, readTime, lookupTime, joinTime time.Duration) []calibrateResult {
+	scanFactor, _ := plannercore.DefaultCostModelV2Factor("tikv_scan_factor")
+	results := []calibrateResult{{factor: "tikv_scan_factor", benchmark: "scan", value: scanFactor}}
+	scanUnit := float64(scanTime) / (rows * math.Log2(calibrateRowSize))
+	if scanUnit <= 0 {
+		// Nothing can be calibrated without the reference.
+		results[0].msg = "the benchmark is too fast to measure"
+		for _, r := range []calibrateResult{
+			{factor: "tidb_kv_net_factor", benchmark: "exchange"},
+			{factor: "tidb_request_factor", benchmark: "lookup"},
+			{factor: "tidb_cpu_factor", benchmark: "hash join"},
+		} {
+			r.msg = "the reference benchmark is too fast to measure"
+			results = append(results, r)
+		}
+		return results
+	}
+	scale := scanFactor / scanUnit
+	newResult := func(factor, benchmark string, elapsed float64, units float64) calibrateResult {
+		r := calibrateResult{factor: factor, benchmark: benchmark}
+		if elapsed <= 0 {
+			r.msg = "the benchmark is too fast to measure"
+			return r
+		}
+		defaultValue, _ := plannercore.DefaultCostModelV2Factor(factor)
+		value := elapsed / units * scale
+		value = min(max(value, defaultValue/calibrateMaxDeviation), defaultValue*calibrateMaxDeviation)
+		r.value = math.Round(value*10000) / 10000
+		return r
+	}
+
+	// exchange: rows * row-size * net.
+	netElapsed := float64(readTime - scanTime)
+	results = append(results, newResult("tidb_kv_net_factor", "exchange", netElapsed, rows*calibrateRowSize))
+	netUnit := max(netElapsed, 0) / (rows * calibrateRowSize)
+
+	// lookup: the scans and the transfers of the index and the table, and the double read tasks of the batch size.
+	lookupElapsed := float64(lookupTime) - scanUnit*rows*(math.Log2(calibrateIndexRowSize)+math.Log2(calibrateRowSize)) -
+		netUnit*rows*(calibrateIndexRowSize+calibrateRowSize)
+	tasks := math.Ceil(rows / variable.DefIndexLookupSize)
+	results = append(results, newResult("tidb_request_factor", "lookup", lookupElapsed, tasks))
+
+	// hash join: two table scans transferring the join keys, the hash keys and the build of the build side, the hash
+	// keys and the probe of the probe side, and the count of the joined rows.
+	joinElapsed := float64(joinTime) - 2*scanUnit*rows*math.Log2(calibrateRowSize) - 2*netUnit*rows*8
+	results = append(results, newResult("tidb_cpu_factor", "hash join", joinElapsed, 5*rows))
+	return results
+}
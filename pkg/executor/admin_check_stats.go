@@ -0,0 +1,252 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/collate"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+)
+
+// defaultCheckStatsSampleCount is the number of the sampled rows when SAMPLE is not specified.
+const defaultCheckStatsSampleCount = 10000
+
+// AdminCheckStatsExec represents an admin check stats executor.
+// It samples the rows of the table and compares the observed row count, NDV, null ratio and distribution of every
+// column with the stored stats, which are reported as the drift scores. The table is enqueued for auto analyze if
+// any of the drift scores exceeds the threshold, so that the suspicious stats can be validated without a re-analyze.
+type AdminCheckStatsExec struct {
+	exec.BaseExecutor
+
+	dbName      string
+	tblInfo     *model.TableInfo
+	sampleCount uint64
+	threshold   float64
+	done        bool
+}
+
+var _ exec.Executor = &AdminCheckStatsExec{}
+
+// columnStatsDrift is the drift of the stats of a column from the sampled rows.
+type columnStatsDrift struct {
+	storedNDV         int64
+	observedNDV       int64
+	storedNullRatio   float64
+	observedNullRatio float64
+	bucketDrift       float64
+}
+
+// score returns the drift score of the column, i.e. the max of the relative errors of the row count and the NDV,
+// the error of the null ratio and the bucket drift, all of which are in [0, 1].
+func (d *columnStatsDrift) score(storedRowCount, observedRowCount int64) float64 {
+	return max(
+		relativeStatsError(float64(storedRowCount), float64(observedRowCount)),
+		relativeStatsError(float64(d.storedNDV), float64(d.observedNDV)),
+		math.Abs(d.storedNullRatio-d.observedNullRatio),
+		d.bucketDrift,
+	)
+}
+
+// Next implements the Executor Next interface.
+func (e *AdminCheckStatsExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	h := domain.GetDomain(e.Ctx()).StatsHandle()
+	statsTbl, err := h.TableStatsFromStorage(e.tblInfo, e.tblInfo.ID, true, 0)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if statsTbl == nil || !statsTbl.IsAnalyzed() {
+		return errors.Errorf("the stats of table %s.%s are not analyzed", e.dbName, e.tblInfo.Name.O)
+	}
+	cols := make([]*statistics.Column, 0, statsTbl.ColNum())
+	for _, col := range statsTbl.StableOrderColSlice() {
+		if col.Info != nil && !col.Info.Hidden && col.IsAnalyzed() {
+			cols = append(cols, col)
+		}
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+	sampleCount := e.sampleCount
+	if sampleCount == 0 {
+		sampleCount = defaultCheckStatsSampleCount
+	}
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnAdmin)
+	sqlExec := e.Ctx().GetRestrictedSQLExecutor()
+	rows, _, err := sqlExec.ExecRestrictedSQL(ctx, nil, "SELECT COUNT(*) FROM %n.%n", e.dbName, e.tblInfo.Name.O)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	observedRowCount := rows[0].GetInt64(0)
+	sql := new(strings.Builder)
+	sqlescape.MustFormatSQL(sql, "SELECT ")
+	for i, col := range cols {
+		if i > 0 {
+			sqlescape.MustFormatSQL(sql, ", ")
+		}
+		sqlescape.MustFormatSQL(sql, "%n", col.Info.Name.O)
+	}
+	sqlescape.MustFormatSQL(sql, " FROM %n.%n ORDER BY RAND() LIMIT %?", e.dbName, e.tblInfo.Name.O, sampleCount)
+	samples, fields, err := sqlExec.ExecRestrictedSQL(ctx, nil, sql.String())
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	vars := e.Ctx().GetSessionVars()
+	var maxScore float64
+	values := make([]types.Datum, len(samples))
+	for i, col := range cols {
+		for j, row := range samples {
+			values[j] = row.GetDatum(i, &fields[i].Column.FieldType)
+		}
+		drift, err := checkColumnStatsDrift(vars, col, values, observedRowCount)
+		if err != nil {
+			return err
+		}
+		score := drift.score(statsTbl.RealtimeCount, observedRowCount)
+		maxScore = max(maxScore, score)
+		req.AppendString(0, e.dbName)
+		req.AppendString(1, e.tblInfo.Name.O)
+		req.AppendString(2, col.Info.Name.O)
+		req.AppendInt64(3, statsTbl.RealtimeCount)
+		req.AppendInt64(4, observedRowCount)
+		req.AppendInt64(5, drift.storedNDV)
+		req.AppendInt64(6, drift.observedNDV)
+		req.AppendFloat64(7, drift.storedNullRatio)
+		req.AppendFloat64(8, drift.observedNullRatio)
+		req.AppendFloat64(9, drift.bucketDrift)
+		req.AppendFloat64(10, score)
+	}
+	if e.threshold > 0 && maxScore > e.threshold {
+		if err := h.EnqueueAnalysisJob(e.tblInfo.ID, statstypes.WithInheritedAnalyzeDefaults(vars)); err != nil {
+			vars.StmtCtx.AppendWarning(errors.Annotatef(err, "failed to enqueue table %s.%s for auto analyze", e.dbName, e.tblInfo.Name.O))
+		} else {
+			vars.StmtCtx.AppendNote(errors.NewNoStackErrorf("table %s.%s is enqueued for auto analyze since its drift score %v exceeds the threshold %v",
+				e.dbName, e.tblInfo.Name.O, maxScore, e.threshold))
+		}
+	}
+	return nil
+}
+
+// checkColumnStatsDrift compares the stored stats of the column with the sampled values of it. The NDV of the column
+// is estimated from the samples in the same way as ANALYZE.
+func checkColumnStatsDrift(vars *variable.SessionVars, col *statistics.Column, values []types.Datum, rowCount int64) (*columnStatsDrift, error) {
+	drift := &columnStatsDrift{storedNDV: col.Histogram.NDV}
+	if total := col.TotalRowCount(); total > 0 {
+		drift.storedNullRatio = float64(col.Histogram.NullCount) / total
+	}
+	if len(values) == 0 {
+		return drift, nil
+	}
+	keys := make([][]byte, 0, len(values))
+	counts := make(map[string]uint64, len(values))
+	for _, d := range values {
+		if d.IsNull() {
+			continue
+		}
+		// The bounds of the histograms of the string columns are the collation keys of the values.
+		if d.Kind() == types.KindString {
+			d.SetBytes(collate.GetCollator(d.Collation()).Key(d.GetString()))
+		}
+		key, err := codec.EncodeKey(vars.Location(), nil, d)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		counts[string(key)]++
+	}
+	drift.observedNullRatio = float64(len(values)-len(keys)) / float64(len(values))
+	if len(keys) > 0 {
+		var onlyOnceItems uint64
+		for _, count := range counts {
+			if count == 1 {
+				onlyOnceItems++
+			}
+		}
+		notNullCount := max(uint64(float64(rowCount)*(1-drift.observedNullRatio)), uint64(len(keys)))
+		drift.observedNDV = int64(statistics.EstimateNDVBySample(uint64(len(keys)), uint64(len(counts)), onlyOnceItems, notNullCount))
+	}
+	points, err := statsDistributionPoints(vars, 0, &col.Histogram, col.TopN, col.StatsVer, []byte{col.Histogram.Tp.GetType()})
+	if err != nil {
+		return nil, err
+	}
+	// The min value is added as a point without rows, which is excluded so that the values equal to it fall in the
+	// first bucket.
+	points = slices.DeleteFunc(points, func(point statsDistributionPoint) bool {
+		return point.count == 0
+	})
+	drift.bucketDrift = statsDistributionDrift(points, keys)
+	return drift, nil
+}
+
+// statsDistributionDrift returns the total variation distance between the distribution of the rows over the points
+// of the stored stats and the one of the sampled keys, i.e. half of the sum of the absolute differences of the
+// fractions of the rows at every point. A key falls at the first point not less than it, and the keys larger than
+// the max value fall beyond the last point, where there are no rows in the stored stats.
+func statsDistributionDrift(points []statsDistributionPoint, keys [][]byte) float64 {
+	if len(keys) == 0 {
+		return 0
+	}
+	var total int64
+	for _, point := range points {
+		total += point.count
+	}
+	observed := make([]int, len(points)+1)
+	for _, key := range keys {
+		idx, _ := slices.BinarySearchFunc(points, key, func(point statsDistributionPoint, key []byte) int {
+			return bytes.Compare(point.key, key)
+		})
+		observed[idx]++
+	}
+	var drift float64
+	for i, count := range observed {
+		var expected float64
+		if i < len(points) && total > 0 {
+			expected = float64(points[i].count) / float64(total)
+		}
+		drift += math.Abs(expected - float64(count)/float64(len(keys)))
+	}
+	return drift / 2
+}
+
+// relativeStatsError returns the relative error between the stored and the observed values in [0, 1].
+func relativeStatsError(stored, observed float64) float64 {
+	if stored <= 0 && observed <= 0 {
+		return 0
+	}
+	return math.Abs(stored-observed) / max(stored, observed)
+}
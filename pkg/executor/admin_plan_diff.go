@@ -0,0 +1,145 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/planner"
+	plannercore "github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/planner/core/resolve"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/plancodec"
+)
+
+// defaultPlanDiffLimit is the number of the statements to compare if the limit is not specified.
+const defaultPlanDiffLimit = 100
+
+// planDiffSourceSQL loads the top statements by the total latency from the statement summary.
+// Only the non-prepared queries are replayed, because the sample text of a prepared statement has no arguments.
+const planDiffSourceSQL = `SELECT digest, any_value(ifnull(schema_name, '')), any_value(query_sample_text),
+		any_value(charset), any_value(collation), plan_digest, any_value(plan), cast(sum(exec_count) as signed)
+	FROM information_schema.%n
+	WHERE stmt_type IN ('Select', 'Update', 'Delete') AND prepared = 0 AND
+		upper(ifnull(schema_name, '')) NOT IN ('MYSQL', 'INFORMATION_SCHEMA', 'METRICS_SCHEMA', 'PERFORMANCE_SCHEMA')
+	GROUP BY digest, plan_digest
+	ORDER BY sum(sum_latency) DESC
+	LIMIT %?`
+
+// AdminShowPlanDiffExec represents an `ADMIN SHOW PLAN DIFF` executor.
+// It replays the top statements in the statement summary of the whole cluster through the optimizer of the
+// current instance in explain-only mode, and reports whether their plans differ from the recorded ones.
+// It's used on the first upgraded instance of a rolling upgrade to find out the plan changes before
+// upgrading the other instances.
+type AdminShowPlanDiffExec struct {
+	exec.BaseExecutor
+
+	limit uint64
+	done  bool
+}
+
+var _ exec.Executor = &AdminShowPlanDiffExec{}
+
+// Next implements the Executor Next interface.
+func (e *AdminShowPlanDiffExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	limit := e.limit
+	if limit == 0 {
+		limit = defaultPlanDiffLimit
+	}
+	summaryTable := "cluster_statements_summary_history"
+	failpoint.Inject("mockPlanDiffSummaryTable", func(val failpoint.Value) {
+		summaryTable = val.(string)
+	})
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnAdmin)
+	rows, _, err := e.Ctx().GetRestrictedSQLExecutor().ExecRestrictedSQL(ctx, nil, planDiffSourceSQL, summaryTable, limit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, row := range rows {
+		schemaName, sql := row.GetString(1), row.GetString(2)
+		oldPlanDigest := row.GetString(5)
+		newPlanDigest, newPlan, err := e.optimize(ctx, schemaName, sql, row.GetString(3), row.GetString(4))
+		req.AppendString(0, row.GetString(0))
+		req.AppendString(1, schemaName)
+		req.AppendString(2, sql)
+		req.AppendInt64(3, row.GetInt64(7))
+		req.AppendString(4, oldPlanDigest)
+		req.AppendString(5, newPlanDigest)
+		if err == nil && newPlanDigest != oldPlanDigest {
+			req.AppendInt64(6, 1)
+		} else {
+			req.AppendInt64(6, 0)
+		}
+		req.AppendString(7, row.GetString(6))
+		req.AppendString(8, newPlan)
+		if err != nil {
+			req.AppendString(9, err.Error())
+		} else {
+			req.AppendString(9, "")
+		}
+	}
+	return nil
+}
+
+// optimize builds the plan of the statement without executing it, and returns the plan digest and the decoded plan,
+// which are in the same format as the ones in the statement summary.
+func (e *AdminShowPlanDiffExec) optimize(ctx context.Context, schemaName, sql, charset, collation string) (planDigest, plan string, err error) {
+	stmt, err := parser.New().ParseOneStmt(sql, charset, collation)
+	if err != nil {
+		return "", "", err
+	}
+
+	sctx := e.Ctx()
+	sessVars := sctx.GetSessionVars()
+	originalDB := sessVars.CurrentDB
+	originalWarns := sessVars.StmtCtx.GetWarnings()
+	originalExtraWarns := sessVars.StmtCtx.GetExtraWarnings()
+	defer func() {
+		sessVars.CurrentDB = originalDB
+		sessVars.StmtCtx.SetWarnings(originalWarns)
+		sessVars.StmtCtx.SetExtraWarnings(originalExtraWarns)
+		sessVars.StmtCtx.InExplainStmt = false
+	}()
+	sessVars.CurrentDB = schemaName
+	sessVars.StmtCtx.InExplainStmt = true
+
+	nodeW := resolve.NewNodeW(stmt)
+	ret := &plannercore.PreprocessorReturn{}
+	if err := plannercore.Preprocess(ctx, sctx, nodeW, plannercore.WithPreprocessorReturn(ret)); err != nil {
+		return "", "", err
+	}
+	p, _, err := planner.Optimize(ctx, sctx, nodeW, ret.InfoSchema)
+	if err != nil {
+		return "", "", err
+	}
+	flat := plannercore.FlattenPhysicalPlan(p, false)
+	_, digest := plannercore.NormalizeFlatPlan(flat)
+	plan, err = plancodec.DecodePlan(plannercore.EncodeFlatPlan(flat))
+	if err != nil {
+		return "", "", err
+	}
+	return digest.String(), plan, nil
+}
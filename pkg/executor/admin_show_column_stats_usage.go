@@ -0,0 +1,106 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+)
+
+// AdminShowColumnStatsUsageExec represents an admin show column stats usage executor.
+// It reports the tracked usage of every column of the table and its partitions, and whether the column is still
+// treated as a predicate column by the predicate-column analyze, see tidb_predicate_column_retention_days.
+type AdminShowColumnStatsUsageExec struct {
+	exec.BaseExecutor
+
+	dbName  string
+	tblInfo *model.TableInfo
+	done    bool
+}
+
+var _ exec.Executor = &AdminShowColumnStatsUsageExec{}
+
+// Next implements the Executor Next interface.
+func (e *AdminShowColumnStatsUsageExec) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	statsHandle := domain.GetDomain(e.Ctx()).StatsHandle()
+	// The usage collected in memory is dumped first so that the result is up to date.
+	if err := statsHandle.DumpColStatsUsageToKV(); err != nil {
+		return err
+	}
+	physicalIDs := []int64{e.tblInfo.ID}
+	partitionNames := []string{""}
+	if pi := e.tblInfo.GetPartitionInfo(); pi != nil {
+		partitionNames[0] = "global"
+		for _, def := range pi.Definitions {
+			physicalIDs = append(physicalIDs, def.ID)
+			partitionNames = append(partitionNames, def.Name.O)
+		}
+	}
+	loc := e.Ctx().GetSessionVars().Location()
+	colStatsMap, err := statsHandle.LoadColumnStatsUsageForTables(loc, physicalIDs)
+	if err != nil {
+		return err
+	}
+	var expiredBefore time.Time
+	if retentionDays := variable.PredicateColumnRetentionDays.Load(); retentionDays > 0 {
+		expiredBefore = time.Now().AddDate(0, 0, -int(retentionDays))
+	}
+	for i, physicalID := range physicalIDs {
+		for _, col := range e.tblInfo.Columns {
+			usage, ok := colStatsMap[model.TableItemID{TableID: physicalID, ID: col.ID, IsIndex: false}]
+			if !ok {
+				continue
+			}
+			req.AppendString(0, e.dbName)
+			req.AppendString(1, e.tblInfo.Name.O)
+			req.AppendString(2, partitionNames[i])
+			req.AppendString(3, col.Name.O)
+			isPredicate := false
+			if usage.LastUsedAt != nil {
+				req.AppendTime(4, *usage.LastUsedAt)
+				lastUsedAt, err := usage.LastUsedAt.GoTime(loc)
+				if err != nil {
+					return err
+				}
+				isPredicate = expiredBefore.IsZero() || !lastUsedAt.Before(expiredBefore)
+			} else {
+				req.AppendNull(4)
+			}
+			if usage.LastAnalyzedAt != nil {
+				req.AppendTime(5, *usage.LastAnalyzedAt)
+			} else {
+				req.AppendNull(5)
+			}
+			if isPredicate {
+				req.AppendInt64(6, 1)
+			} else {
+				req.AppendInt64(6, 0)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+)
+
+const (
+	// defaultVectorIndexValidateSampleCount is the number of query vectors used when SAMPLE is not specified.
+	defaultVectorIndexValidateSampleCount = 1000
+	// vectorIndexValidateTopK is the K of the top-K queries used to measure the recall.
+	vectorIndexValidateTopK = 10
+	// vectorDistanceTolerance is used to tolerate the float error when comparing distances.
+	vectorDistanceTolerance = 1e-6
+)
+
+// AdminValidateVectorIndexExec represents an admin validate vector index executor.
+// It samples vectors from the table, uses them as query vectors and compares the top-K results
+// of the ANN search through the vector index with the brute force results to measure the recall.
+type AdminValidateVectorIndexExec struct {
+	exec.BaseExecutor
+
+	dbName      string
+	tblInfo     *model.TableInfo
+	indexInfo   *model.IndexInfo
+	sampleCount uint64
+	done        bool
+}
+
+var _ exec.Executor = &AdminValidateVectorIndexExec{}
+
+// Next implements the Executor Next interface.
+func (e *AdminValidateVectorIndexExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	if e.indexInfo.State != model.StatePublic {
+		return errors.Errorf("vector index %s is not public, current state: %s", e.indexInfo.Name.O, e.indexInfo.State)
+	}
+	if e.tblInfo.TiFlashReplica == nil || !e.tblInfo.TiFlashReplica.Available {
+		return errors.Errorf("the TiFlash replica of table %s is not available, vector index %s can't be used", e.tblInfo.Name.O, e.indexInfo.Name.O)
+	}
+	fnName, ok := model.IndexableDistanceMetricToFnName[e.indexInfo.VectorInfo.DistanceMetric]
+	if !ok {
+		return errors.Errorf("unsupported distance metric %s", e.indexInfo.VectorInfo.DistanceMetric)
+	}
+	sampleCount := e.sampleCount
+	if sampleCount == 0 {
+		sampleCount = defaultVectorIndexValidateSampleCount
+	}
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnAdmin)
+	sqlExec := e.Ctx().GetRestrictedSQLExecutor()
+	colName := e.tblInfo.Columns[e.indexInfo.Columns[0].Offset].Name.O
+	samples, _, err := sqlExec.ExecRestrictedSQL(ctx, nil, "SELECT %n FROM %n.%n WHERE %n IS NOT NULL ORDER BY RAND() LIMIT %?",
+		colName, e.dbName, e.tblInfo.Name.O, colName, sampleCount)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// The ANN query is expected to use the vector index, while the brute force query ignores it.
+	annSQL := "SELECT /*+ READ_FROM_STORAGE(TIFLASH[%n]) */ " + fnName + "(%n, %?) AS d FROM %n.%n ORDER BY d LIMIT %?"
+	exactSQL := "SELECT " + fnName + "(%n, %?) AS d FROM %n.%n IGNORE INDEX(%n) WHERE %n IS NOT NULL ORDER BY d LIMIT %?"
+	var recallSum float64
+	for _, sample := range samples {
+		query := sample.GetVectorFloat32(0).String()
+		annRows, _, err := sqlExec.ExecRestrictedSQL(ctx, nil, annSQL,
+			e.tblInfo.Name.O, colName, query, e.dbName, e.tblInfo.Name.O, vectorIndexValidateTopK)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		exactRows, _, err := sqlExec.ExecRestrictedSQL(ctx, nil, exactSQL,
+			colName, query, e.dbName, e.tblInfo.Name.O, e.indexInfo.Name.O, colName, vectorIndexValidateTopK)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		annDistances := make([]float64, 0, len(annRows))
+		for _, row := range annRows {
+			if !row.IsNull(0) {
+				annDistances = append(annDistances, row.GetFloat64(0))
+			}
+		}
+		exactDistances := make([]float64, 0, len(exactRows))
+		for _, row := range exactRows {
+			exactDistances = append(exactDistances, row.GetFloat64(0))
+		}
+		recallSum += vectorSearchRecall(annDistances, exactDistances)
+	}
+
+	req.AppendString(0, e.dbName)
+	req.AppendString(1, e.tblInfo.Name.O)
+	req.AppendString(2, e.indexInfo.Name.O)
+	req.AppendString(3, string(e.indexInfo.VectorInfo.DistanceMetric))
+	req.AppendInt64(4, int64(len(samples)))
+	req.AppendInt64(5, vectorIndexValidateTopK)
+	if len(samples) == 0 {
+		req.AppendNull(6)
+	} else {
+		req.AppendFloat64(6, recallSum/float64(len(samples)))
+	}
+	return nil
+}
+
+// vectorSearchRecall returns the recall of an ANN search given the distances of its results and
+// the distances of the exact top-K results, both in ascending order. Rows are compared by their
+// distances instead of their handles, so rows with the same distance are interchangeable.
+func vectorSearchRecall(annDistances, exactDistances []float64) float64 {
+	if len(exactDistances) == 0 {
+		return 1
+	}
+	threshold := exactDistances[len(exactDistances)-1] + vectorDistanceTolerance
+	hits := 0
+	for _, d := range annDistances {
+		if d <= threshold {
+			hits++
+		}
+	}
+	return float64(min(hits, len(exactDistances))) / float64(len(exactDistances))
+}
@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/ddl/placement"
+	"github.com/pingcap/tidb/pkg/distsql"
 	"github.com/pingcap/tidb/pkg/domain"
 	"github.com/pingcap/tidb/pkg/domain/infosync"
 	"github.com/pingcap/tidb/pkg/executor/internal/exec"
@@ -33,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/pkg/metrics"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/resourcegroup"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/sessiontxn"
@@ -45,8 +49,10 @@ import (
 	"github.com/pingcap/tidb/pkg/util/intest"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"github.com/pingcap/tidb/pkg/util/sqlescape"
+	"github.com/pingcap/tidb/pkg/util/sqlkiller"
 	"github.com/pingcap/tipb/go-tipb"
 	"github.com/tiancaiamao/gp"
+	clientutil "github.com/tikv/client-go/v2/util"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -60,9 +66,18 @@ type AnalyzeExec struct {
 	wg         *util.WaitGroupPool
 	opts       map[ast.AnalyzeOptionType]uint64
 	OptionsMap map[int64]core.V2AnalyzeOptions
-	gp         *gp.Pool
+	// tempTableTasks are analyzed in the session, see analyzeTemporaryTables.
+	tempTableTasks []core.AnalyzeTempTableTask
+	gp             *gp.Pool
 	// errExitCh is used to notice the worker that the whole analyze task is finished when to meet error.
 	errExitCh chan struct{}
+	// partitionScheduler limits the number of the partitions analyzed concurrently, it's nil if there is no limit.
+	partitionScheduler *analyzePartitionScheduler
+	// receivedJobs is the jobs whose results are received by the result handler, and the value is whether the job
+	// succeeded. It's only accessed by the result handler until the handler exits.
+	receivedJobs map[*statistics.AnalyzeJob]bool
+	// checkpoint records the analyzed partitions, so the statement can resume from them if it's interrupted.
+	checkpoint *analyzeCheckpoint
 }
 
 var (
@@ -85,16 +100,31 @@ const (
 // Next implements the Executor Next interface.
 // It will collect all the sample task and run them concurrently.
 func (e *AnalyzeExec) Next(ctx context.Context, _ *chunk.Chunk) error {
+	if readOnly, _ := handleutil.StatsReadOnly(); readOnly {
+		return handleutil.ErrStatsReadOnly
+	}
 	statsHandle := domain.GetDomain(e.Ctx()).StatsHandle()
 	infoSchema := sessiontxn.GetTxnManager(e.Ctx()).GetTxnInfoSchema()
 	sessionVars := e.Ctx().GetSessionVars()
 
+	if err := e.analyzeTemporaryTables(ctx); err != nil {
+		return err
+	}
+
 	// Filter the locked tables.
 	tasks, needAnalyzeTableCnt, skippedTables, err := filterAndCollectTasks(e.tasks, statsHandle, infoSchema)
 	if err != nil {
 		return err
 	}
 	warnLockedTableMsg(sessionVars, needAnalyzeTableCnt, skippedTables)
+	// Skip the tables which are being analyzed by other analyze statements, e.g. auto analyze.
+	tasks, unlockTables := lockAnalyzingTables(sessionVars, tasks)
+	defer unlockTables()
+	// Skip the partitions which have been analyzed by the interrupted run of the same statement.
+	e.checkpoint, tasks, err = newAnalyzeCheckpoint(e.Ctx(), tasks)
+	if err != nil {
+		return err
+	}
 
 	if len(tasks) == 0 {
 		return nil
@@ -106,6 +136,9 @@ func (e *AnalyzeExec) Next(ctx context.Context, _ *chunk.Chunk) error {
 		return err
 	}
 	buildStatsConcurrency = min(len(tasks), buildStatsConcurrency)
+	tasks, e.partitionScheduler = newAnalyzePartitionScheduler(tasks, sessionVars.AnalyzePartitionConcurrency)
+	defer e.reportRUConsumption(ctx, tasks)
+	e.receivedJobs = make(map[*statistics.AnalyzeJob]bool, len(tasks))
 
 	// Start workers with channel to collect results.
 	taskCh := make(chan *analyzeTask, buildStatsConcurrency)
@@ -133,6 +166,15 @@ func (e *AnalyzeExec) Next(ctx context.Context, _ *chunk.Chunk) error {
 	})
 TASKLOOP:
 	for _, task := range tasks {
+		if slots := e.partitionScheduler.acquire(task); slots != nil {
+			select {
+			case slots <- struct{}{}:
+			case <-e.errExitCh:
+				break TASKLOOP
+			case <-gctx.Done():
+				break TASKLOOP
+			}
+		}
 		select {
 		case taskCh <- task:
 		case <-e.errExitCh:
@@ -152,6 +194,9 @@ TASKLOOP:
 
 	err = e.waitFinish(ctx, g, resultsCh)
 	if err != nil {
+		if killErr := sessionVars.SQLKiller.HandleSignal(); killErr != nil {
+			e.finishInterruptedJobs(statsHandle, tasks, killErr)
+		}
 		return err
 	}
 
@@ -186,7 +231,14 @@ TASKLOOP:
 	if err != nil {
 		sessionVars.StmtCtx.AppendWarning(err)
 	}
-	return statsHandle.Update(ctx, infoSchema)
+	if err = e.checkpoint.clear(e.Ctx(), tasks); err != nil {
+		sessionVars.StmtCtx.AppendWarning(err)
+	}
+	if err = statsHandle.Update(ctx, infoSchema); err != nil {
+		return err
+	}
+	core.WarmUpPlanCacheAfterAnalyze(e.Ctx(), analyzedTableIDs(tasks))
+	return nil
 }
 
 func (e *AnalyzeExec) waitFinish(ctx context.Context, g *errgroup.Group, resultsCh chan *statistics.AnalyzeResults) error {
@@ -307,6 +359,60 @@ func warnLockedTableMsg(sessionVars *variable.SessionVars, needAnalyzeTableCnt u
 	}
 }
 
+// lockAnalyzingTables locks the tables of the tasks, so that a manual analyze and an auto analyze don't analyze
+// the same table concurrently. The tasks of the tables which are being analyzed are skipped with a warning.
+// It returns the remaining tasks and the function to unlock the tables after analyze.
+func lockAnalyzingTables(sessionVars *variable.SessionVars, tasks []*analyzeTask) ([]*analyzeTask, func()) {
+	autoAnalyze := sessionVars.InRestrictedSQL
+	filteredTasks := make([]*analyzeTask, 0, len(tasks))
+	lockedIDs := make(map[int64]struct{}, len(tasks))
+	skippedIDs := make(map[int64]struct{})
+	for _, task := range tasks {
+		tableID := getTableIDFromTask(task)
+		physicalID := tableID.GetStatisticsID()
+		// In stats v1, analyze for each index is a single task, and they have the same table id.
+		if _, ok := lockedIDs[physicalID]; ok {
+			filteredTasks = append(filteredTasks, task)
+			continue
+		}
+		if _, ok := skippedIDs[physicalID]; ok {
+			continue
+		}
+		locked, heldByAutoAnalyze := handleutil.GlobalAnalyzeLocks.TryLock(physicalID, autoAnalyze)
+		if !locked {
+			skippedIDs[physicalID] = struct{}{}
+			table := fmt.Sprintf("%s.%s", task.job.DBName, task.job.TableName)
+			if task.job.PartitionName != "" {
+				table = fmt.Sprintf("%s partition (%s)", table, task.job.PartitionName)
+			}
+			holder := "another analyze"
+			if heldByAutoAnalyze {
+				holder = "auto analyze"
+			}
+			sessionVars.StmtCtx.AppendWarning(errors.NewNoStackErrorf("skip analyze table %s, it is being analyzed by %s", table, holder))
+			continue
+		}
+		lockedIDs[physicalID] = struct{}{}
+		filteredTasks = append(filteredTasks, task)
+	}
+	return filteredTasks, func() {
+		for physicalID := range lockedIDs {
+			handleutil.GlobalAnalyzeLocks.Unlock(physicalID)
+		}
+	}
+}
+
+// analyzedTableIDs returns the IDs of the tables analyzed by the tasks, the partitions are mapped to their tables.
+func analyzedTableIDs(tasks []*analyzeTask) []int64 {
+	tableIDs := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		if id := getTableIDFromTask(task).TableID; !slices.Contains(tableIDs, id) {
+			tableIDs = append(tableIDs, id)
+		}
+	}
+	return tableIDs
+}
+
 func getTableIDFromTask(task *analyzeTask) statistics.AnalyzeTableID {
 	switch task.taskType {
 	case colTask:
@@ -428,7 +534,7 @@ func (e *AnalyzeExec) handleResultsErrorWithConcurrency(
 	errCh := make(chan error, saveStatsConcurrency)
 	enableAnalyzeSnapshot := e.Ctx().GetSessionVars().EnableAnalyzeSnapshot
 	for i := 0; i < saveStatsConcurrency; i++ {
-		worker := newAnalyzeSaveStatsWorker(saveResultsCh, errCh, &e.Ctx().GetSessionVars().SQLKiller)
+		worker := newAnalyzeSaveStatsWorker(saveResultsCh, errCh, e.checkpoint)
 		ctx1 := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
 		wg.Run(func() {
 			worker.run(ctx1, statsHandle, enableAnalyzeSnapshot)
@@ -436,17 +542,20 @@ func (e *AnalyzeExec) handleResultsErrorWithConcurrency(
 	}
 	tableIDs := map[int64]struct{}{}
 	panicCnt := 0
-	var err error
+	var err, killErr error
 	// Only if all the analyze workers exit can we close the saveResultsCh.
 	for panicCnt < buildStatsConcurrency {
-		if err := e.Ctx().GetSessionVars().SQLKiller.HandleSignal(); err != nil {
-			close(saveResultsCh)
-			return err
+		// If the analyze is killed, the results received so far are still saved, so a re-run only needs to analyze
+		// the remaining tables and partitions.
+		if killErr = e.Ctx().GetSessionVars().SQLKiller.HandleSignal(); killErr != nil {
+			break
 		}
 		results, ok := <-resultsCh
 		if !ok {
 			break
 		}
+		e.partitionScheduler.release(results.Job)
+		e.receivedJobs[results.Job] = results.Err == nil
 		if results.Err != nil {
 			err = results.Err
 			if isAnalyzeWorkerPanic(err) {
@@ -460,6 +569,9 @@ func (e *AnalyzeExec) handleResultsErrorWithConcurrency(
 		handleGlobalStats(needGlobalStats, globalStatsMap, results)
 		tableIDs[results.TableID.GetStatisticsID()] = struct{}{}
 		saveResultsCh <- results
+		failpoint.Inject("mockKillAfterAnalyzeResultReceived", func() {
+			e.Ctx().GetSessionVars().SQLKiller.SendKillSignal(sqlkiller.QueryInterrupted)
+		})
 	}
 	close(saveResultsCh)
 	wg.Wait()
@@ -477,14 +589,66 @@ func (e *AnalyzeExec) handleResultsErrorWithConcurrency(
 			logutil.BgLogger().Error("record historical stats failed", zap.Error(err))
 		}
 	}
+	if killErr != nil {
+		return killErr
+	}
 	return err
 }
 
+// reportRUConsumption reports the request units consumed by the analyze tasks to the resource control metrics, and
+// adds them to the RU details of the statement, so the cost of maintaining the stats can be told from the workload.
+func (e *AnalyzeExec) reportRUConsumption(ctx context.Context, tasks []*analyzeTask) {
+	ruDetails := clientutil.NewRUDetails()
+	for _, task := range tasks {
+		var taskRUDetails *clientutil.RUDetails
+		if task.colExec != nil {
+			taskRUDetails = task.colExec.ruDetails
+		} else if task.idxExec != nil {
+			taskRUDetails = task.idxExec.ruDetails
+		}
+		if taskRUDetails != nil {
+			ruDetails.Merge(taskRUDetails)
+		}
+	}
+	groupName := e.Ctx().GetSessionVars().StmtCtx.ResourceGroupName
+	if groupName == "" {
+		groupName = resourcegroup.DefaultResourceGroupName
+	}
+	metrics.AnalyzeRUCounter.WithLabelValues(groupName, "rru").Add(ruDetails.RRU())
+	metrics.AnalyzeRUCounter.WithLabelValues(groupName, "wru").Add(ruDetails.WRU())
+	if stmtRUDetails, ok := ctx.Value(clientutil.RUDetailsCtxKey).(*clientutil.RUDetails); ok {
+		stmtRUDetails.Merge(ruDetails)
+	}
+}
+
+// finishInterruptedJobs marks the jobs whose results are not received as failed when the analyze is killed or times
+// out. The stats of the finished jobs are kept, so the fail reason notes the table is partially analyzed if any job
+// finished.
+func (e *AnalyzeExec) finishInterruptedJobs(statsHandle *handle.Handle, tasks []*analyzeTask, killErr error) {
+	finishedCnt := 0
+	for _, succeeded := range e.receivedJobs {
+		if succeeded {
+			finishedCnt++
+		}
+	}
+	failReason := killErr
+	if finishedCnt > 0 {
+		failReason = errors.Annotatef(killErr, "partially analyzed, %d of %d jobs finished", finishedCnt, len(tasks))
+	}
+	for _, task := range tasks {
+		if _, ok := e.receivedJobs[task.job]; !ok {
+			finishJobWithLog(statsHandle, task.job, failReason)
+		}
+	}
+}
+
 func (e *AnalyzeExec) analyzeWorker(taskCh <-chan *analyzeTask, resultsCh chan<- *statistics.AnalyzeResults) {
 	var task *analyzeTask
+	release := func() {}
 	statsHandle := domain.GetDomain(e.Ctx()).StatsHandle()
 	defer func() {
 		if r := recover(); r != nil {
+			release()
 			logutil.BgLogger().Error("analyze worker panicked", zap.Any("recover", r), zap.Stack("stack"))
 			metrics.PanicCounter.WithLabelValues(metrics.LabelAnalyze).Inc()
 			// If errExitCh is closed, it means the whole analyze task is aborted. So we do not need to send the result to resultsCh.
@@ -506,22 +670,75 @@ func (e *AnalyzeExec) analyzeWorker(taskCh <-chan *analyzeTask, resultsCh chan<-
 			break
 		}
 		failpoint.Inject("handleAnalyzeWorkerPanic", nil)
-		statsHandle.StartAnalyzeJob(task.job)
-		switch task.taskType {
-		case colTask:
-			select {
-			case <-e.errExitCh:
+		// Wait for the memory budget before starting the job, so the job stays pending while it's queued.
+		releaseMem, err := e.acquireAnalyzeMemory(task)
+		if err != nil {
+			if errors.ErrorEqual(err, errAnalyzeMemoryPoolExit) {
 				return
-			case resultsCh <- analyzeColumnsPushDownEntry(e.gp, task.colExec):
 			}
-		case idxTask:
 			select {
 			case <-e.errExitCh:
 				return
-			case resultsCh <- analyzeIndexPushdown(task.idxExec):
+			case resultsCh <- &statistics.AnalyzeResults{Err: err, Job: task.job}:
 			}
+			continue
 		}
+		release = releaseMem
+		statsHandle.StartAnalyzeJob(task.job)
+		var result *statistics.AnalyzeResults
+		switch task.taskType {
+		case colTask:
+			result = e.analyzeColumnsWithDegrade(task.colExec)
+		case idxTask:
+			result = analyzeIndexPushdown(task.idxExec)
+		}
+		release()
+		select {
+		case <-e.errExitCh:
+			return
+		case resultsCh <- result:
+		}
+	}
+}
+
+// acquireAnalyzeMemory reserves the estimated memory of the task from the memory pool shared by all the analyze
+// executions of the instance. It waits until the budget is enough, the analyze is aborted or killed.
+func (e *AnalyzeExec) acquireAnalyzeMemory(task *analyzeTask) (func(), error) {
+	if task.taskType != colTask {
+		return func() {}, nil
 	}
+	bytes := task.colExec.estimateSampleMemory()
+	failpoint.Inject("mockAnalyzeSampleMemory", func(val failpoint.Value) {
+		bytes = int64(val.(int))
+	})
+	return globalAnalyzeMemoryPool.acquire(bytes, e.errExitCh, e.Ctx().GetSessionVars().SQLKiller.HandleSignal)
+}
+
+// maxAnalyzeDegradeTimes is the max times to reduce the samples of a task which exceeds tidb_mem_quota_analyze.
+const maxAnalyzeDegradeTimes = 3
+
+// analyzeColumnsWithDegrade analyzes the columns of the task. When the task exceeds tidb_mem_quota_analyze and
+// tidb_analyze_oom_action is DEGRADE, it's retried with half of the samples, so the analyze still collects the
+// stats, though less accurate, instead of failing.
+func (e *AnalyzeExec) analyzeColumnsWithDegrade(colExec *AnalyzeColumnsExec) *statistics.AnalyzeResults {
+	result := analyzeColumnsPushDownEntry(e.gp, colExec)
+	for i := 0; i < maxAnalyzeDegradeTimes && isAnalyzeOOM(result.Err); i++ {
+		if variable.AnalyzeOOMAction.Load() != variable.AnalyzeOOMActionDegrade {
+			break
+		}
+		samples, ok := colExec.reduceSamples()
+		if !ok {
+			break
+		}
+		// The memory of the failed run has been released, detach its tracker before starting a new one.
+		colExec.memTracker.Detach()
+		logutil.BgLogger().Warn("analyze exceeds the memory quota, retry with fewer samples",
+			zap.String("job", colExec.job.JobInfo), zap.String("samples", samples))
+		e.Ctx().GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf(
+			"%s exceeds tidb_mem_quota_analyze, retry with %s", colExec.job.JobInfo, samples))
+		result = analyzeColumnsPushDownEntry(e.gp, colExec)
+	}
+	return result
 }
 
 type analyzeTask struct {
@@ -539,6 +756,63 @@ type baseAnalyzeExec struct {
 	opts        map[ast.AnalyzeOptionType]uint64
 	job         *statistics.AnalyzeJob
 	snapshot    uint64
+	// staleReadTS is the stale snapshot at which the data is read, see tidb_analyze_snapshot_staleness.
+	staleReadTS uint64
+	// priority is the priority of the coprocessor requests, which decides how TiKV schedules the scan.
+	priority int
+	// ruDetails collects the request units consumed by the coprocessor requests.
+	ruDetails *clientutil.RUDetails
+	// readReplicaScope is the zone whose replicas are read by the coprocessor requests instead of the leaders,
+	// see tidb_analyze_read_nearby_replica. Empty means reading the leaders.
+	readReplicaScope string
+}
+
+// readSnapshot returns the snapshot and the isolation level at which the coprocessor requests read the data.
+func (e *baseAnalyzeExec) readSnapshot() (uint64, kv.IsoLevel) {
+	if e.ctx.GetSessionVars().EnableAnalyzeSnapshot {
+		return e.snapshot, kv.SI
+	}
+	if e.staleReadTS > 0 {
+		return e.staleReadTS, kv.SI
+	}
+	return math.MaxUint64, kv.RC
+}
+
+// setReplicaRead makes the coprocessor requests read the replicas in readReplicaScope if it's set.
+func (e *baseAnalyzeExec) setReplicaRead(builder *distsql.RequestBuilder) {
+	if e.readReplicaScope == "" {
+		return
+	}
+	// The requests read the leader or the followers in the zone, see RequestBuilder.Build.
+	builder.Request.ReplicaRead = kv.ReplicaReadClosest
+	builder.SetReadReplicaScope(e.readReplicaScope)
+}
+
+// checkAnalyzeReplicaRead checks whether the request reads the replicas in the scope, or the leaders if the scope is
+// empty. It's only used by the failpoint assertAnalyzeReplicaRead.
+func checkAnalyzeReplicaRead(kvReq *kv.Request, scope string) error {
+	if scope == "" {
+		if kvReq.ReplicaRead != kv.ReplicaReadLeader || len(kvReq.MatchStoreLabels) > 0 {
+			return errors.Errorf("unexpected replica read %v of the analyze request", kvReq.ReplicaRead)
+		}
+		return nil
+	}
+	if kvReq.ReplicaRead != kv.ReplicaReadClosest || kvReq.ReadReplicaScope != scope {
+		return errors.Errorf("unexpected replica read %v in %s of the analyze request", kvReq.ReplicaRead, kvReq.ReadReplicaScope)
+	}
+	if len(kvReq.MatchStoreLabels) != 1 || kvReq.MatchStoreLabels[0].Key != placement.DCLabelKey ||
+		kvReq.MatchStoreLabels[0].Value != scope {
+		return errors.Errorf("unexpected store labels %v of the analyze request", kvReq.MatchStoreLabels)
+	}
+	return nil
+}
+
+// withRUDetails returns the context to send the coprocessor requests, which collects the consumed request units.
+func (e *baseAnalyzeExec) withRUDetails(ctx context.Context) context.Context {
+	if e.ruDetails == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, clientutil.RUDetailsCtxKey, e.ruDetails)
 }
 
 // AddNewAnalyzeJob records the new analyze job.
@@ -0,0 +1,184 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle"
+	handleutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+	"go.uber.org/zap"
+)
+
+// analyzeCheckpoint records the partitions whose stats have been saved in mysql.analyze_checkpoints, so that the
+// same ANALYZE statement only analyzes the remaining partitions if it runs again after being interrupted, e.g. by
+// the restart of the TiDB instance. The checkpoints are removed once the statement finishes.
+type analyzeCheckpoint struct {
+	// analyzeSQL identifies the statement which the checkpoints belong to.
+	analyzeSQL string
+	ttl        time.Duration
+
+	mu sync.Mutex
+	// pending is the number of the tasks of each partition whose results haven't been saved.
+	pending map[int64]int
+}
+
+// newAnalyzeCheckpoint skips the tasks of the partitions which have been analyzed by the interrupted run of the
+// same statement, and returns the remaining tasks. It returns a nil checkpoint if the checkpoints are disabled by
+// tidb_analyze_checkpoint_ttl.
+func newAnalyzeCheckpoint(sctx sessionctx.Context, tasks []*analyzeTask) (*analyzeCheckpoint, []*analyzeTask, error) {
+	ttl := variable.AnalyzeCheckpointTTL.Load()
+	if ttl <= 0 {
+		return nil, tasks, nil
+	}
+	cp := &analyzeCheckpoint{
+		analyzeSQL: sctx.GetSessionVars().StmtCtx.OriginalSQL,
+		ttl:        ttl,
+		pending:    make(map[int64]int),
+	}
+	tableIDs := make(map[int64]struct{})
+	for _, task := range tasks {
+		if tableID := getTableIDFromTask(task); tableID.IsPartitionTable() {
+			tableIDs[tableID.TableID] = struct{}{}
+			cp.pending[tableID.PartitionID]++
+		}
+	}
+	if len(tableIDs) == 0 {
+		return cp, tasks, nil
+	}
+
+	var sql strings.Builder
+	sql.WriteString("select partition_id from mysql.analyze_checkpoints where table_id in ")
+	formatTableIDList(&sql, tableIDs)
+	sqlescape.MustFormatSQL(&sql, " and analyze_sql = %? and checkpoint_time > date_sub(now(6), interval %? second)",
+		cp.analyzeSQL, int64(cp.ttl.Seconds()))
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
+	rows, _, err := sctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(ctx, nil, sql.String())
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if len(rows) == 0 {
+		return cp, tasks, nil
+	}
+	analyzed := make(map[int64]struct{}, len(rows))
+	for _, row := range rows {
+		analyzed[row.GetInt64(0)] = struct{}{}
+	}
+	// At least one partition of each table is analyzed again, so the global stats can be merged from the results.
+	remainingTables := make(map[int64]struct{}, len(tableIDs))
+	for _, task := range tasks {
+		tableID := getTableIDFromTask(task)
+		if _, ok := analyzed[tableID.PartitionID]; tableID.IsPartitionTable() && !ok {
+			remainingTables[tableID.TableID] = struct{}{}
+		}
+	}
+	remaining := make([]*analyzeTask, 0, len(tasks))
+	skipped := make(map[int64]struct{}, len(analyzed))
+	for i := len(tasks) - 1; i >= 0; i-- {
+		task := tasks[i]
+		tableID := getTableIDFromTask(task)
+		if _, ok := analyzed[tableID.PartitionID]; ok && tableID.IsPartitionTable() {
+			if _, ok := remainingTables[tableID.TableID]; ok {
+				skipped[tableID.PartitionID] = struct{}{}
+				delete(cp.pending, tableID.PartitionID)
+				continue
+			}
+			// It's the last partition of the table, all the tasks of the partition are analyzed again.
+			remainingTables[tableID.TableID] = struct{}{}
+			delete(analyzed, tableID.PartitionID)
+		}
+		remaining = append(remaining, task)
+	}
+	for i, j := 0, len(remaining)-1; i < j; i, j = i+1, j-1 {
+		remaining[i], remaining[j] = remaining[j], remaining[i]
+	}
+	if len(skipped) > 0 {
+		sctx.GetSessionVars().StmtCtx.AppendNote(errors.NewNoStackErrorf(
+			"Analyze resumes from the checkpoints, %d partitions analyzed by the interrupted run are skipped", len(skipped)))
+	}
+	return cp, remaining, nil
+}
+
+// saved records the checkpoint of the partition once the results of all its tasks have been saved.
+func (cp *analyzeCheckpoint) saved(statsHandle *handle.Handle, tableID statistics.AnalyzeTableID) {
+	if cp == nil || !tableID.IsPartitionTable() {
+		return
+	}
+	cp.mu.Lock()
+	cp.pending[tableID.PartitionID]--
+	finished := cp.pending[tableID.PartitionID] == 0
+	cp.mu.Unlock()
+	if !finished {
+		return
+	}
+	err := handleutil.CallWithSCtx(statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		_, err := handleutil.Exec(sctx, "replace into mysql.analyze_checkpoints (table_id, partition_id, analyze_sql) values (%?, %?, %?)",
+			tableID.TableID, tableID.PartitionID, cp.analyzeSQL)
+		return err
+	})
+	if err != nil {
+		// The checkpoint only saves the work of a re-run, so the failure doesn't fail the analyze.
+		logutil.BgLogger().Warn("save analyze checkpoint failed", zap.Int64("partitionID", tableID.PartitionID), zap.Error(err))
+	}
+}
+
+// clear removes the checkpoints of the statement after it finishes, as well as the expired ones of the analyzed
+// tables. The checkpoints of the other statements are kept, since they are still valid for their re-runs.
+func (cp *analyzeCheckpoint) clear(sctx sessionctx.Context, tasks []*analyzeTask) error {
+	if cp == nil {
+		return nil
+	}
+	tableIDs := make(map[int64]struct{})
+	for _, task := range tasks {
+		if tableID := getTableIDFromTask(task); tableID.IsPartitionTable() {
+			tableIDs[tableID.TableID] = struct{}{}
+		}
+	}
+	if len(tableIDs) == 0 {
+		return nil
+	}
+	var sql strings.Builder
+	sql.WriteString("delete from mysql.analyze_checkpoints where table_id in ")
+	formatTableIDList(&sql, tableIDs)
+	sqlescape.MustFormatSQL(&sql, " and (analyze_sql = %? or checkpoint_time <= date_sub(now(6), interval %? second))",
+		cp.analyzeSQL, int64(cp.ttl.Seconds()))
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
+	_, _, err := sctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(ctx, nil, sql.String())
+	return err
+}
+
+func formatTableIDList(sql *strings.Builder, tableIDs map[int64]struct{}) {
+	sql.WriteString("(")
+	first := true
+	for tableID := range tableIDs {
+		if !first {
+			sql.WriteString(", ")
+		}
+		first = false
+		sql.WriteString(strconv.FormatInt(tableID, 10))
+	}
+	sql.WriteString(")")
+}
@@ -18,9 +18,12 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/pkg/distsql"
 	"github.com/pingcap/tidb/pkg/domain"
@@ -61,6 +64,20 @@ type AnalyzeColumnsExec struct {
 	baseCount               int64
 	baseModifyCnt           int64
 
+	// blockSampleRate is the fraction of the regions scanned by the analyze. 0 means all the regions are scanned.
+	blockSampleRate float64
+	// sampledRegions and totalRegions are the numbers of the scanned regions and all the regions when the block
+	// sampling is used. They are used to scale the collected statistics to the whole table.
+	sampledRegions int
+	totalRegions   int
+	// quickRowCount is the row count estimated by the region metadata for the quick analyze. 0 means it's unknown,
+	// and the statistics are scaled by the fraction of the scanned regions.
+	quickRowCount float64
+	// scannedRows is the number of the rows in the scanned regions.
+	scannedRows int64
+	// readFromTiFlash indicates the samples are collected by scanning the TiFlash replica instead of the TiKV coprocessor.
+	readFromTiFlash bool
+
 	memTracker *memory.Tracker
 }
 
@@ -71,6 +88,35 @@ func analyzeColumnsPushDownEntry(gp *gp.Pool, e *AnalyzeColumnsExec) *statistics
 	return e.toV1().analyzeColumnsPushDownV1()
 }
 
+// minAnalyzeDegradeSampleSize and minAnalyzeDegradeSampleRate are the lower bounds of the samples when the analyze
+// degrades for exceeding the memory quota. The stats built from fewer samples are too inaccurate to be useful.
+const (
+	minAnalyzeDegradeSampleSize = 1000
+	minAnalyzeDegradeSampleRate = 1e-5
+)
+
+// reduceSamples halves the sample size or the sample rate of the version 2 task, and returns the new one in text.
+// It returns false if the samples can't be reduced any more.
+func (e *AnalyzeColumnsExec) reduceSamples() (string, bool) {
+	if e.StatsVersion < statistics.Version2 || e.analyzePB.ColReq == nil {
+		return "", false
+	}
+	colReq := e.analyzePB.ColReq
+	if colReq.SampleRate != nil && *colReq.SampleRate > 0 {
+		sampleRate := *colReq.SampleRate / 2
+		if sampleRate < minAnalyzeDegradeSampleRate {
+			return "", false
+		}
+		colReq.SampleRate = &sampleRate
+		return fmt.Sprintf("samplerate %v", sampleRate), true
+	}
+	if colReq.SampleSize/2 < minAnalyzeDegradeSampleSize {
+		return "", false
+	}
+	colReq.SampleSize /= 2
+	return fmt.Sprintf("%d samples", colReq.SampleSize), true
+}
+
 func (e *AnalyzeColumnsExec) toV1() *AnalyzeColumnsExecV1 {
 	return &AnalyzeColumnsExecV1{
 		AnalyzeColumnsExec: e,
@@ -83,9 +129,13 @@ func (e *AnalyzeColumnsExec) toV2() *AnalyzeColumnsExecV2 {
 	}
 }
 
-func (e *AnalyzeColumnsExec) open(ranges []*ranger.Range) error {
+func (e *AnalyzeColumnsExec) openMemTracker() {
 	e.memTracker = memory.NewTracker(int(e.ctx.GetSessionVars().PlanID.Load()), -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+}
+
+func (e *AnalyzeColumnsExec) open(ranges []*ranger.Range) error {
+	e.openMemTracker()
 	e.resultHandler = &tableResultHandler{}
 	firstPartRanges, secondPartRanges := distsql.SplitRangesAcrossInt64Boundary(ranges, true, false, !hasPkHist(e.handleCols))
 	firstResult, err := e.buildResp(firstPartRanges)
@@ -110,12 +160,8 @@ func (e *AnalyzeColumnsExec) buildResp(ranges []*ranger.Range) (distsql.SelectRe
 	var builder distsql.RequestBuilder
 	reqBuilder := builder.SetHandleRangesForTables(e.ctx.GetDistSQLCtx(), []int64{e.TableID.GetStatisticsID()}, e.handleCols != nil && !e.handleCols.IsInt(), ranges)
 	builder.SetResourceGroupTagger(e.ctx.GetSessionVars().StmtCtx.GetResourceGroupTagger())
-	startTS := uint64(math.MaxUint64)
-	isoLevel := kv.RC
-	if e.ctx.GetSessionVars().EnableAnalyzeSnapshot {
-		startTS = e.snapshot
-		isoLevel = kv.SI
-	}
+	e.setReplicaRead(reqBuilder)
+	startTS, isoLevel := e.readSnapshot()
 	// Always set KeepOrder of the request to be true, in order to compute
 	// correct `correlation` of columns.
 	kvReq, err := reqBuilder.
@@ -130,7 +176,26 @@ func (e *AnalyzeColumnsExec) buildResp(ranges []*ranger.Range) (distsql.SelectRe
 	if err != nil {
 		return nil, err
 	}
-	ctx := context.TODO()
+	kvReq.Priority = e.priority
+	kvReq.RunawayChecker = e.ctx.GetSessionVars().StmtCtx.RunawayChecker
+	failpoint.Inject("assertAnalyzePriority", func(val failpoint.Value) {
+		if kvReq.Priority != val.(int) {
+			failpoint.Return(nil, errors.Errorf("unexpected priority %d of the analyze request", kvReq.Priority))
+		}
+	})
+	failpoint.Inject("assertAnalyzeReplicaRead", func(val failpoint.Value) {
+		if err := checkAnalyzeReplicaRead(kvReq, val.(string)); err != nil {
+			failpoint.Return(nil, err)
+		}
+	})
+	if e.blockSampleRate > 0 && e.blockSampleRate < 1 {
+		sampled, err := e.sampleRegionRanges(kvReq.KeyRanges.AppendSelfTo(nil))
+		if err != nil {
+			return nil, err
+		}
+		kvReq.KeyRanges = kv.NewNonPartitionedKeyRanges(sampled)
+	}
+	ctx := e.withRUDetails(context.TODO())
 	result, err := distsql.Analyze(ctx, e.ctx.GetClient(), kvReq, e.ctx.GetSessionVars().KVVars, e.ctx.GetSessionVars().InRestrictedSQL, e.ctx.GetDistSQLCtx())
 	if err != nil {
 		return nil, err
@@ -138,6 +203,51 @@ func (e *AnalyzeColumnsExec) buildResp(ranges []*ranger.Range) (distsql.SelectRe
 	return result, nil
 }
 
+// sampleRegionRanges splits the key ranges by regions and picks blockSampleRate of them randomly. All the rows in the
+// picked regions are scanned, so the coprocessor only reads a fraction of the table instead of sampling rows from
+// every region.
+func (e *AnalyzeColumnsExec) sampleRegionRanges(ranges []kv.KeyRange) ([]kv.KeyRange, error) {
+	regionRanges := make([]kv.KeyRange, 0, len(ranges))
+	for _, r := range ranges {
+		rs, err := splitIntoMultiRanges(e.ctx.GetStore(), r.StartKey, r.EndKey)
+		if err != nil {
+			return nil, err
+		}
+		regionRanges = append(regionRanges, rs...)
+	}
+	if len(regionRanges) == 0 {
+		return regionRanges, nil
+	}
+	sampledCnt := max(1, int(math.Round(float64(len(regionRanges))*e.blockSampleRate)))
+	picked := rand.Perm(len(regionRanges))[:sampledCnt]
+	// Keep the order of the ranges, because the request is sent with KeepOrder.
+	slices.Sort(picked)
+	sampled := make([]kv.KeyRange, 0, sampledCnt)
+	for _, i := range picked {
+		sampled = append(sampled, regionRanges[i])
+	}
+	e.sampledRegions += sampledCnt
+	e.totalRegions += len(regionRanges)
+	return sampled, nil
+}
+
+// quickAnalyzeBlockSampleRate is the fraction of the regions scanned by the quick analyze.
+const quickAnalyzeBlockSampleRate = 0.0001
+
+// blockSampleFraction returns the fraction of the scanned regions, or 1 if the block sampling is not used.
+func (e *AnalyzeColumnsExec) blockSampleFraction() float64 {
+	if e.totalRegions == 0 || e.sampledRegions >= e.totalRegions {
+		return 1
+	}
+	// The quick analyze scans too few regions to scale the statistics by the number of the regions accurately, since
+	// the sizes of the regions vary, so the fraction of the scanned rows in the row count from the region metadata is
+	// used instead.
+	if e.quickRowCount > 0 && e.scannedRows > 0 {
+		return min(1, float64(e.scannedRows)/e.quickRowCount)
+	}
+	return float64(e.sampledRegions) / float64(e.totalRegions)
+}
+
 func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats bool) (hists []*statistics.Histogram, cms []*statistics.CMSketch, topNs []*statistics.TopN, fms []*statistics.FMSketch, extStats *statistics.ExtendedStatsColl, err error) {
 	if err = e.open(ranges); err != nil {
 		return nil, nil, nil, nil, nil, err
@@ -283,7 +393,7 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 		fms = append(fms, collectors[i].FMSketch)
 	}
 	if needExtStats {
-		extStats, err = statistics.BuildExtendedStats(e.ctx, e.TableID.GetStatisticsID(), e.colsInfo, collectors)
+		extStats, err = statistics.BuildExtendedStats(e.ctx, e.TableID.GetStatisticsID(), e.colsInfo, collectors, nil)
 		if err != nil {
 			return nil, nil, nil, nil, nil, err
 		}
@@ -490,5 +600,16 @@ func prepareV2AnalyzeJobInfo(e *AnalyzeColumnsExec) {
 		}
 		b.WriteString(fmt.Sprintf("%v samplerate", sampleRate))
 	}
+	if e.Quick {
+		b.WriteString(", quick")
+	} else if e.blockSampleRate > 0 && e.blockSampleRate < 1 {
+		b.WriteString(fmt.Sprintf(", %v blocksample", e.blockSampleRate))
+	}
+	if e.readFromTiFlash {
+		b.WriteString(", tiflash")
+	}
+	if id := opts[ast.AnalyzeOptSampling]; id != statistics.DefaultSamplingStrategyID {
+		b.WriteString(fmt.Sprintf(", '%s' sampling", statistics.GetSamplingStrategy(id).Name()))
+	}
 	e.job.JobInfo = b.String()
 }
@@ -51,6 +51,11 @@ import (
 // AnalyzeColumnsExecV2 is used to maintain v2 analyze process
 type AnalyzeColumnsExecV2 struct {
 	*AnalyzeColumnsExec
+
+	// spillAction is triggered when the memory of the task exceeds the spill limit of tidb_mem_quota_analyze.
+	spillAction *handleutil.AnalyzeSpillAction
+	// spilledCollectors are the samples of the column collectors spilled to disk, indexed by the column offsets.
+	spilledCollectors []*chunk.DataInDiskByChunks
 }
 
 func (e *AnalyzeColumnsExecV2) analyzeColumnsPushDownV2(gp *gp.Pool) *statistics.AnalyzeResults {
@@ -87,6 +92,34 @@ func (e *AnalyzeColumnsExecV2) analyzeColumnsPushDownV2(gp *gp.Pool) *statistics
 			specialIndexes = append(specialIndexes, idx)
 		}
 	}
+	// For the incremental analyze, only the rows appended after the last analyze are scanned,
+	// and the stats built from them are merged with the existing stats.
+	var oldStats *statistics.Table
+	if e.Incremental {
+		var (
+			incrementalRanges []*ranger.Range
+			err               error
+		)
+		oldStats, incrementalRanges, err = e.prepareIncrementalAnalyze(specialIndexes)
+		if err != nil {
+			e.memTracker.Release(e.memTracker.BytesConsumed())
+			return &statistics.AnalyzeResults{Err: err, Job: e.job}
+		}
+		if oldStats != nil {
+			ranges = incrementalRanges
+		}
+	}
+	// The joint NDVs of the cardinality extended stats are collected from the whole table, so they are skipped
+	// in the incremental analyze.
+	var extStatsColGroups []statistics.ExtStatsColGroup
+	if collExtStats && oldStats == nil {
+		var err error
+		extStatsColGroups, err = e.appendExtStatsColGroups()
+		if err != nil {
+			e.memTracker.Release(e.memTracker.BytesConsumed())
+			return &statistics.AnalyzeResults{Err: err, Job: e.job}
+		}
+	}
 	samplingStatsConcurrency, err := getBuildSamplingStatsConcurrency(e.ctx)
 	if err != nil {
 		e.memTracker.Release(e.memTracker.BytesConsumed())
@@ -107,7 +140,10 @@ func (e *AnalyzeColumnsExecV2) analyzeColumnsPushDownV2(gp *gp.Pool) *statistics
 	})
 	defer wg.Wait()
 
-	count, hists, topNs, fmSketches, extStats, err := e.buildSamplingStats(gp, ranges, collExtStats, specialIndexesOffsets, idxNDVPushDownCh, samplingStatsConcurrency)
+	// The JSON path stats and the foreign key stats are built from the samples of the whole table, so they are
+	// skipped in the incremental analyze.
+	collSampleExtStats := oldStats == nil
+	count, hists, topNs, fmSketches, extStats, err := e.buildSamplingStats(gp, ranges, collExtStats, extStatsColGroups, collSampleExtStats, specialIndexesOffsets, idxNDVPushDownCh, samplingStatsConcurrency)
 	if err != nil {
 		e.memTracker.Release(e.memTracker.BytesConsumed())
 		return &statistics.AnalyzeResults{Err: err, Job: e.job}
@@ -133,7 +169,7 @@ func (e *AnalyzeColumnsExecV2) analyzeColumnsPushDownV2(gp *gp.Pool) *statistics
 		Fms:   fmSketches[:cLen],
 	}
 
-	return &statistics.AnalyzeResults{
+	results := &statistics.AnalyzeResults{
 		TableID:       e.tableID,
 		Ars:           []*statistics.AnalyzeResult{colResult, colGroupResult},
 		Job:           e.job,
@@ -144,6 +180,44 @@ func (e *AnalyzeColumnsExecV2) analyzeColumnsPushDownV2(gp *gp.Pool) *statistics
 		BaseCount:     e.baseCount,
 		BaseModifyCnt: e.baseModifyCnt,
 	}
+	if oldStats != nil {
+		if err := e.mergeIncrementalAnalyzeResult(oldStats, results); err != nil {
+			e.memTracker.Release(e.memTracker.BytesConsumed())
+			return &statistics.AnalyzeResults{Err: err, Job: e.job}
+		}
+	}
+	return results
+}
+
+// appendExtStatsColGroups appends the column groups of the cardinality extended stats to the analyze request
+// after the column groups of the indexes, so their joint NDVs are collected by composite FMSketches.
+func (e *AnalyzeColumnsExecV2) appendExtStatsColGroups() ([]statistics.ExtStatsColGroup, error) {
+	groups, err := statistics.CollectCardinalityColGroups(e.ctx, e.TableID.GetStatisticsID())
+	if err != nil {
+		return nil, err
+	}
+	appended := groups[:0]
+	for _, group := range groups {
+		colGroup := &tipb.AnalyzeColumnGroup{
+			ColumnOffsets: make([]int64, 0, len(group.ColIDs)),
+		}
+		for _, colID := range group.ColIDs {
+			for i, col := range e.colsInfo {
+				// The virtual generated columns can't be evaluated in TiKV.
+				if col.ID == colID && !col.IsVirtualGenerated() {
+					colGroup.ColumnOffsets = append(colGroup.ColumnOffsets, int64(i))
+					break
+				}
+			}
+		}
+		// The column may have been dropped, while the extended stats have not been removed by GC yet.
+		if len(colGroup.ColumnOffsets) != len(group.ColIDs) {
+			continue
+		}
+		e.analyzePB.ColReq.ColumnGroups = append(e.analyzePB.ColReq.ColumnGroups, colGroup)
+		appended = append(appended, group)
+	}
+	return appended, nil
 }
 
 // decodeSampleDataWithVirtualColumn constructs the virtual column by evaluating from the decoded normal columns.
@@ -207,6 +281,8 @@ func (e *AnalyzeColumnsExecV2) buildSamplingStats(
 	gp *gp.Pool,
 	ranges []*ranger.Range,
 	needExtStats bool,
+	extStatsColGroups []statistics.ExtStatsColGroup,
+	needSampleExtStats bool,
 	indexesWithVirtualColOffsets []int,
 	idxNDVPushDownCh chan analyzeIndexNDVTotalResult,
 	samplingStatsConcurrency int,
@@ -218,92 +294,52 @@ func (e *AnalyzeColumnsExecV2) buildSamplingStats(
 	extStats *statistics.ExtendedStatsColl,
 	err error,
 ) {
-	// Open memory tracker and resultHandler.
-	if err = e.open(ranges); err != nil {
-		return 0, nil, nil, nil, nil, err
-	}
-	defer func() {
-		if err1 := e.resultHandler.Close(); err1 != nil {
-			err = err1
+	if e.readFromTiFlash {
+		e.openMemTracker()
+	} else {
+		// Open memory tracker and resultHandler.
+		if err = e.open(ranges); err != nil {
+			return 0, nil, nil, nil, nil, err
 		}
-	}()
-
-	l := len(e.analyzePB.ColReq.ColumnsInfo) + len(e.analyzePB.ColReq.ColumnGroups)
-	rootRowCollector := statistics.NewRowSampleCollector(int(e.analyzePB.ColReq.SampleSize), e.analyzePB.ColReq.GetSampleRate(), l)
-	for i := 0; i < l; i++ {
-		rootRowCollector.Base().FMSketches = append(rootRowCollector.Base().FMSketches, statistics.NewFMSketch(statistics.MaxSketchSize))
-	}
-
-	sc := e.ctx.GetSessionVars().StmtCtx
-
-	// Start workers to merge the result from collectors.
-	mergeResultCh := make(chan *samplingMergeResult, 1)
-	mergeTaskCh := make(chan []byte, 1)
-	var taskEg errgroup.Group
-	// Start read data from resultHandler and send them to mergeTaskCh.
-	taskEg.Go(func() (err error) {
 		defer func() {
-			if r := recover(); r != nil {
-				err = getAnalyzePanicErr(r)
+			if err1 := e.resultHandler.Close(); err1 != nil {
+				err = err1
 			}
 		}()
-		return readDataAndSendTask(e.ctx, e.resultHandler, mergeTaskCh, e.memTracker)
-	})
-	e.samplingMergeWg = &util.WaitGroupWrapper{}
-	e.samplingMergeWg.Add(samplingStatsConcurrency)
-	for i := 0; i < samplingStatsConcurrency; i++ {
-		id := i
-		gp.Go(func() {
-			e.subMergeWorker(mergeResultCh, mergeTaskCh, l, id)
-		})
 	}
-	// Merge the result from collectors.
-	mergeWorkerPanicCnt := 0
-	mergeEg, mergeCtx := errgroup.WithContext(context.Background())
-	mergeEg.Go(func() (err error) {
-		defer func() {
-			if r := recover(); r != nil {
-				err = getAnalyzePanicErr(r)
-			}
-		}()
-		for mergeWorkerPanicCnt < samplingStatsConcurrency {
-			mergeResult, ok := <-mergeResultCh
-			if !ok {
-				break
-			}
-			if mergeResult.err != nil {
-				err = mergeResult.err
-				if isAnalyzeWorkerPanic(mergeResult.err) {
-					mergeWorkerPanicCnt++
-				}
-				continue
+	e.spillAction = handleutil.SetAnalyzeSpillAction(e.memTracker, GlobalAnalyzeMemoryTracker.GetBytesLimit())
+	e.spilledCollectors = make([]*chunk.DataInDiskByChunks, len(e.colsInfo))
+	defer func() {
+		for _, inDisk := range e.spilledCollectors {
+			if inDisk != nil {
+				inDisk.Close()
 			}
-			oldRootCollectorSize := rootRowCollector.Base().MemSize
-			oldRootCollectorCount := rootRowCollector.Base().Count
-			// Merge the result from sub-collectors.
-			rootRowCollector.MergeCollector(mergeResult.collector)
-			newRootCollectorCount := rootRowCollector.Base().Count
-			printAnalyzeMergeCollectorLog(oldRootCollectorCount, newRootCollectorCount,
-				mergeResult.collector.Base().Count, e.tableID.TableID, e.tableID.PartitionID, e.tableID.IsPartitionTable(),
-				"merge subMergeWorker in AnalyzeColumnsExecV2", -1)
-			e.memTracker.Consume(rootRowCollector.Base().MemSize - oldRootCollectorSize - mergeResult.collector.Base().MemSize)
-			mergeResult.collector.DestroyAndPutToPool()
-		}
-		return err
-	})
-	err = taskEg.Wait()
-	if err != nil {
-		mergeCtx.Done()
-		if err1 := mergeEg.Wait(); err1 != nil {
-			err = stderrors.Join(err, err1)
 		}
-		return 0, nil, nil, nil, nil, getAnalyzePanicErr(err)
+	}()
+
+	var rootRowCollector statistics.RowSampleCollector
+	if e.readFromTiFlash {
+		rootRowCollector, err = e.collectSamplesFromTiFlash()
+	} else {
+		rootRowCollector, err = e.mergeSamplesFromTiKV(gp, samplingStatsConcurrency)
 	}
-	err = mergeEg.Wait()
-	defer e.memTracker.Release(rootRowCollector.Base().MemSize)
 	if err != nil {
 		return 0, nil, nil, nil, nil, err
 	}
+	defer e.memTracker.Release(rootRowCollector.Base().MemSize)
+	sc := e.ctx.GetSessionVars().StmtCtx
+	e.scannedRows = rootRowCollector.Base().Count
+	// Only a fraction of the regions are scanned by the block sampling, so scale the counts to the whole table.
+	if fraction := e.blockSampleFraction(); fraction < 1 {
+		collectorBase := rootRowCollector.Base()
+		collectorBase.Count = int64(float64(collectorBase.Count) / fraction)
+		for i := range collectorBase.NullCount {
+			collectorBase.NullCount[i] = int64(float64(collectorBase.NullCount[i]) / fraction)
+		}
+		for i := range collectorBase.TotalSizes {
+			collectorBase.TotalSizes[i] = int64(float64(collectorBase.TotalSizes[i]) / fraction)
+		}
+	}
 
 	// Decode the data from sample collectors.
 	virtualColIdx := buildVirtualColumnIndex(e.schemaForVirtualColEval, e.colsInfo)
@@ -429,7 +465,52 @@ func (e *AnalyzeColumnsExecV2) buildSamplingStats(
 
 	count = rootRowCollector.Base().Count
 	if needExtStats {
-		extStats, err = statistics.BuildExtendedStats(e.ctx, e.TableID.GetStatisticsID(), e.colsInfo, sampleCollectors)
+		// The extended stats are built from the collectors, so the spilled samples are read back.
+		for i, inDisk := range e.spilledCollectors {
+			if inDisk == nil {
+				continue
+			}
+			if err = restoreSampleCollector(sampleCollectors[i], inDisk, e.memTracker); err != nil {
+				return 0, nil, nil, nil, nil, err
+			}
+		}
+		colGroupNDVs := make(map[string]int64, len(extStatsColGroups))
+		extStatsOffset := colLen + len(e.indexes)
+		for i, group := range extStatsColGroups {
+			colGroupNDVs[group.Key] = rootRowCollector.Base().FMSketches[extStatsOffset+i].NDV()
+		}
+		extStats, err = statistics.BuildExtendedStats(e.ctx, e.TableID.GetStatisticsID(), e.colsInfo, sampleCollectors, colGroupNDVs)
+		if err != nil {
+			return 0, nil, nil, nil, nil, err
+		}
+	}
+	// The JSON path stats, the trigram stats, the foreign key stats, the vector stats, the correlations between the
+	// columns of the composite indexes and the TopN of their prefixes are maintained for the analyzed columns like the
+	// column stats, so they are collected regardless of tidb_enable_extended_stats.
+	if needSampleExtStats {
+		extStats, err = statistics.AppendJSONPathStats(extStats, e.colsInfo, rootRowCollector.Base().Samples)
+		if err != nil {
+			return 0, nil, nil, nil, nil, err
+		}
+		if e.ctx.GetSessionVars().AnalyzeCollectTrigramStats {
+			extStats, err = statistics.AppendTrigramStats(extStats, e.colsInfo, rootRowCollector.Base().Samples)
+			if err != nil {
+				return 0, nil, nil, nil, nil, err
+			}
+		}
+		extStats, err = statistics.AppendIndexColCorrelations(e.ctx.GetSessionVars().StmtCtx, extStats, e.colsInfo, e.indexes, rootRowCollector.Base().Samples)
+		if err != nil {
+			return 0, nil, nil, nil, nil, err
+		}
+		extStats, err = statistics.AppendIndexPrefixTopNs(e.ctx.GetSessionVars().StmtCtx, extStats, e.colsInfo, e.indexes, rootRowCollector.Base().Samples)
+		if err != nil {
+			return 0, nil, nil, nil, nil, err
+		}
+		extStats, err = e.buildForeignKeyStats(extStats, rootRowCollector.Base().Samples)
+		if err != nil {
+			return 0, nil, nil, nil, nil, err
+		}
+		extStats, err = e.buildVectorStats(extStats)
 		if err != nil {
 			return 0, nil, nil, nil, nil, err
 		}
@@ -438,6 +519,95 @@ func (e *AnalyzeColumnsExecV2) buildSamplingStats(
 	return
 }
 
+// mergeSamplesFromTiKV merges the samples collected by the coprocessor of each region to the root collector.
+func (e *AnalyzeColumnsExecV2) mergeSamplesFromTiKV(gp *gp.Pool, samplingStatsConcurrency int) (statistics.RowSampleCollector, error) {
+	l := len(e.analyzePB.ColReq.ColumnsInfo) + len(e.analyzePB.ColReq.ColumnGroups)
+	rootRowCollector := e.newRowSampleCollector(l)
+	for i := 0; i < l; i++ {
+		rootRowCollector.Base().FMSketches = append(rootRowCollector.Base().FMSketches, statistics.NewFMSketch(statistics.MaxSketchSize))
+	}
+
+	// Start workers to merge the result from collectors.
+	mergeResultCh := make(chan *samplingMergeResult, 1)
+	mergeTaskCh := make(chan []byte, 1)
+	var taskEg errgroup.Group
+	// Start read data from resultHandler and send them to mergeTaskCh.
+	taskEg.Go(func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = getAnalyzePanicErr(r)
+			}
+		}()
+		return readDataAndSendTask(e.ctx, e.resultHandler, mergeTaskCh, e.memTracker)
+	})
+	e.samplingMergeWg = &util.WaitGroupWrapper{}
+	e.samplingMergeWg.Add(samplingStatsConcurrency)
+	for i := 0; i < samplingStatsConcurrency; i++ {
+		id := i
+		gp.Go(func() {
+			e.subMergeWorker(mergeResultCh, mergeTaskCh, l, id)
+		})
+	}
+	// Merge the result from collectors.
+	mergeWorkerPanicCnt := 0
+	mergeEg, mergeCtx := errgroup.WithContext(context.Background())
+	mergeEg.Go(func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = getAnalyzePanicErr(r)
+			}
+		}()
+		for mergeWorkerPanicCnt < samplingStatsConcurrency {
+			mergeResult, ok := <-mergeResultCh
+			if !ok {
+				break
+			}
+			if mergeResult.err != nil {
+				err = mergeResult.err
+				if isAnalyzeWorkerPanic(mergeResult.err) {
+					mergeWorkerPanicCnt++
+				}
+				continue
+			}
+			oldRootCollectorSize := rootRowCollector.Base().MemSize
+			oldRootCollectorCount := rootRowCollector.Base().Count
+			// Merge the result from sub-collectors.
+			rootRowCollector.MergeCollector(mergeResult.collector)
+			newRootCollectorCount := rootRowCollector.Base().Count
+			printAnalyzeMergeCollectorLog(oldRootCollectorCount, newRootCollectorCount,
+				mergeResult.collector.Base().Count, e.tableID.TableID, e.tableID.PartitionID, e.tableID.IsPartitionTable(),
+				"merge subMergeWorker in AnalyzeColumnsExecV2", -1)
+			e.memTracker.Consume(rootRowCollector.Base().MemSize - oldRootCollectorSize - mergeResult.collector.Base().MemSize)
+			mergeResult.collector.DestroyAndPutToPool()
+		}
+		return err
+	})
+	err := taskEg.Wait()
+	if err != nil {
+		mergeCtx.Done()
+		if err1 := mergeEg.Wait(); err1 != nil {
+			err = stderrors.Join(err, err1)
+		}
+		return nil, getAnalyzePanicErr(err)
+	}
+	err = mergeEg.Wait()
+	if err != nil {
+		e.memTracker.Release(rootRowCollector.Base().MemSize)
+		return nil, err
+	}
+	oldRootCollectorSize := rootRowCollector.Base().MemSize
+	rootRowCollector.Finalize()
+	e.memTracker.Consume(rootRowCollector.Base().MemSize - oldRootCollectorSize)
+	return rootRowCollector, nil
+}
+
+// newRowSampleCollector creates the collector which the samples of the regions are merged into by the sampling
+// strategy of the analyze options.
+func (e *AnalyzeColumnsExecV2) newRowSampleCollector(totalLen int) statistics.RowSampleCollector {
+	strategy := statistics.GetSamplingStrategy(e.opts[ast.AnalyzeOptSampling])
+	return strategy.NewCollector(int(e.analyzePB.ColReq.SampleSize), e.analyzePB.ColReq.GetSampleRate(), totalLen)
+}
+
 // handleNDVForSpecialIndexes deals with the logic to analyze the index containing the virtual column when the mode is full sampling.
 func (e *AnalyzeColumnsExecV2) handleNDVForSpecialIndexes(indexInfos []*model.IndexInfo, totalResultCh chan analyzeIndexNDVTotalResult, statsConcurrncy int) {
 	defer func() {
@@ -545,7 +715,12 @@ func (e *AnalyzeColumnsExecV2) buildSubIndexJobForSpecialIndex(indexInfos []*mod
 				Flags:          sc.PushDownFlags(),
 				TimeZoneOffset: offset,
 			},
-			snapshot: e.snapshot,
+			snapshot:    e.snapshot,
+			staleReadTS: e.staleReadTS,
+			priority:    e.priority,
+			ruDetails:   e.ruDetails,
+
+			readReplicaScope: e.readReplicaScope,
 		}
 		idxExec := &AnalyzeIndexExec{
 			baseAnalyzeExec: base,
@@ -625,7 +800,7 @@ func (e *AnalyzeColumnsExecV2) subMergeWorker(resultCh chan<- *samplingMergeResu
 			time.Sleep(100 * time.Millisecond)
 		}
 	})
-	retCollector := statistics.NewRowSampleCollector(int(e.analyzePB.ColReq.SampleSize), e.analyzePB.ColReq.GetSampleRate(), l)
+	retCollector := e.newRowSampleCollector(l)
 	for i := 0; i < l; i++ {
 		retCollector.Base().FMSketches = append(retCollector.Base().FMSketches, statistics.NewFMSketch(statistics.MaxSketchSize))
 	}
@@ -749,6 +924,8 @@ workLoop:
 					FMSketch:  task.rootRowCollector.Base().FMSketches[task.slicePos],
 					TotalSize: task.rootRowCollector.Base().TotalSizes[task.slicePos],
 					MemSize:   collectorMemSize,
+
+					BlockSampleFraction: e.blockSampleFraction(),
 				}
 			} else {
 				var tmpDatum types.Datum
@@ -762,19 +939,20 @@ workLoop:
 				e.memTracker.Consume(collectorMemSize)
 				errCtx := e.ctx.GetSessionVars().StmtCtx.ErrCtx()
 			indexSampleCollectLoop:
-				for _, row := range task.rootRowCollector.Base().Samples {
+				for j, row := range task.rootRowCollector.Base().Samples {
 					if len(idx.Columns) == 1 && row.Columns[idx.Columns[0].Offset].IsNull() {
 						continue
 					}
 					b := make([]byte, 0, 8)
 					for _, col := range idx.Columns {
-						// If the index value contains one value which is too long, we think that it's a value that doesn't occur many times.
-						if len(row.Columns[col.Offset].GetBytes()) > statistics.MaxSampleValueLength {
-							continue indexSampleCollectLoop
-						}
 						if col.Length != types.UnspecifiedLength {
 							row.Columns[col.Offset].Copy(&tmpDatum)
 							ranger.CutDatumByPrefixLen(&tmpDatum, col.Length, &e.colsInfo[col.Offset].FieldType)
+							// The prefix index only holds the truncated value, so a long value is still a useful sample
+							// of it as long as its prefix is not too long.
+							if len(tmpDatum.GetBytes()) > statistics.MaxSampleValueLength {
+								continue indexSampleCollectLoop
+							}
 							b, err = codec.EncodeKey(e.ctx.GetSessionVars().StmtCtx.TimeZone(), b, tmpDatum)
 							err = errCtx.HandleError(err)
 							if err != nil {
@@ -783,6 +961,10 @@ workLoop:
 							}
 							continue
 						}
+						// If the index value contains one value which is too long, we think that it's a value that doesn't occur many times.
+						if len(row.Columns[col.Offset].GetBytes()) > statistics.MaxSampleValueLength {
+							continue indexSampleCollectLoop
+						}
 						b, err = codec.EncodeKey(e.ctx.GetSessionVars().StmtCtx.TimeZone(), b, row.Columns[col.Offset])
 						err = errCtx.HandleError(err)
 						if err != nil {
@@ -791,7 +973,8 @@ workLoop:
 						}
 					}
 					sampleItems = append(sampleItems, &statistics.SampleItem{
-						Value: types.NewBytesDatum(b),
+						Value:   types.NewBytesDatum(b),
+						Ordinal: j,
 					})
 					// tmp memory usage
 					deltaSize := sampleItems[len(sampleItems)-1].Value.MemUsage()
@@ -805,6 +988,8 @@ workLoop:
 					FMSketch:  task.rootRowCollector.Base().FMSketches[task.slicePos],
 					TotalSize: task.rootRowCollector.Base().TotalSizes[task.slicePos],
 					MemSize:   collectorMemSize,
+
+					BlockSampleFraction: e.blockSampleFraction(),
 				}
 			}
 			if task.isColumn {
@@ -825,6 +1010,14 @@ workLoop:
 			e.memTracker.Consume(finalMemSize)
 			hists[task.slicePos] = hist
 			topns[task.slicePos] = topn
+			if task.isColumn && e.spillAction.Triggered() {
+				inDisk, err := spillSampleCollector(collector, e.memTracker, e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+				if err != nil {
+					resultCh <- err
+					continue
+				}
+				e.spilledCollectors[task.slicePos] = inDisk
+			}
 			resultCh <- nil
 			releaseCollectorMemory()
 		case <-exitCh:
@@ -0,0 +1,71 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+)
+
+var _ exec.Executor = &AnalyzeDatabaseExec{}
+
+// AnalyzeDatabaseExec represents ANALYZE DATABASE executor.
+// It feeds all tables of the database to the analysis jobs of the auto analyze priority queue,
+// and returns the result of every table.
+type AnalyzeDatabaseExec struct {
+	exec.BaseExecutor
+
+	dbName      pmodel.CIStr
+	concurrency int
+	budget      time.Duration
+
+	results []statstypes.AnalyzeDatabaseTableResult
+	cursor  int
+	done    bool
+}
+
+// Next implements the Executor Next interface.
+func (e *AnalyzeDatabaseExec) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if !e.done {
+		e.done = true
+		statsHandle := domain.GetDomain(e.Ctx()).StatsHandle()
+		if statsHandle == nil {
+			return errors.New("stats handle is not initialized")
+		}
+		results, err := statsHandle.AnalyzeDatabase(e.dbName.O, e.concurrency, e.budget)
+		if err != nil {
+			return err
+		}
+		e.results = results
+	}
+	for ; e.cursor < len(e.results) && req.NumRows() < req.Capacity(); e.cursor++ {
+		result := e.results[e.cursor]
+		req.AppendString(0, e.dbName.O)
+		req.AppendString(1, result.TableName)
+		req.AppendString(2, result.PartitionName)
+		req.AppendString(3, result.Status)
+		req.AppendString(4, result.Duration.Round(time.Millisecond).String())
+		req.AppendString(5, result.Message)
+	}
+	return nil
+}
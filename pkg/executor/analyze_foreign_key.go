@@ -0,0 +1,166 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+	"go.uber.org/zap"
+)
+
+// maxForeignKeyValidationValues is the max number of the distinct sampled foreign key values looked up in the
+// referenced table when validating a foreign key.
+const maxForeignKeyValidationValues = 256
+
+// buildForeignKeyStats builds the foreign key stats of the analyzed table from the sampled rows, and appends them to
+// the extended stats. The foreign keys whose columns are not all analyzed are skipped.
+func (e *AnalyzeColumnsExecV2) buildForeignKeyStats(extStats *statistics.ExtendedStatsColl, samples []*statistics.ReservoirRowSampleItem) (*statistics.ExtendedStatsColl, error) {
+	if len(e.tableInfo.ForeignKeys) == 0 || len(samples) == 0 {
+		return extStats, nil
+	}
+	is := e.ctx.GetInfoSchema()
+	for _, fk := range e.tableInfo.ForeignKeys {
+		if fk.State != model.StatePublic {
+			continue
+		}
+		colIDs, offsets := e.foreignKeyColumns(fk)
+		if colIDs == nil {
+			continue
+		}
+		refTbl, err := is.TableInfoByName(fk.RefSchema, fk.RefTable)
+		if err != nil {
+			// The referenced table may not exist when foreign_key_checks is disabled.
+			continue
+		}
+		refColIDs := make([]int64, 0, len(fk.RefCols))
+		for _, name := range fk.RefCols {
+			col := model.FindColumnInfo(refTbl.Columns, name.L)
+			if col == nil {
+				break
+			}
+			refColIDs = append(refColIDs, col.ID)
+		}
+		if len(refColIDs) != len(fk.RefCols) {
+			continue
+		}
+
+		fkStats := &statistics.ForeignKeyStats{
+			RefTableID: refTbl.ID,
+			RefColIDs:  refColIDs,
+			MatchRatio: 1,
+		}
+		var values [][]types.Datum
+		if e.ctx.GetSessionVars().AnalyzeValidateForeignKeys {
+			values = statistics.CollectForeignKeyValues(offsets, samples, maxForeignKeyValidationValues)
+		}
+		if len(values) > 0 {
+			matched, err := e.validateForeignKey(fk, values)
+			if err != nil {
+				// The validation is only an improvement of the stats, so its failure doesn't fail the analyze.
+				logutil.BgLogger().Warn("validate foreign key failed",
+					zap.String("table", e.tableInfo.Name.O), zap.String("foreignKey", fk.Name.O), zap.Error(err))
+			} else {
+				fkStats.MatchRatio = float64(matched) / float64(len(values))
+				fkStats.Validated = true
+			}
+		}
+		item, err := statistics.NewForeignKeyStatsItem(colIDs, fkStats)
+		if err != nil {
+			return nil, err
+		}
+		if extStats == nil {
+			extStats = statistics.NewExtendedStatsColl()
+		}
+		extStats.Stats[statistics.ForeignKeyStatsName(fk.ID)] = item
+	}
+	return extStats, nil
+}
+
+// foreignKeyColumns returns the IDs of the foreign key columns and their offsets in the analyzed columns.
+// It returns nil if any of them is not analyzed.
+func (e *AnalyzeColumnsExecV2) foreignKeyColumns(fk *model.FKInfo) ([]int64, []int) {
+	colIDs := make([]int64, 0, len(fk.Cols))
+	offsets := make([]int, 0, len(fk.Cols))
+	for _, name := range fk.Cols {
+		offset := -1
+		for i, col := range e.colsInfo {
+			if col.Name.L == name.L {
+				offset = i
+				break
+			}
+		}
+		if offset < 0 {
+			return nil, nil
+		}
+		colIDs = append(colIDs, e.colsInfo[offset].ID)
+		offsets = append(offsets, offset)
+	}
+	return colIDs, offsets
+}
+
+// validateForeignKey looks up the sampled foreign key values in the referenced table, and returns the number of the
+// values found.
+func (e *AnalyzeColumnsExecV2) validateForeignKey(fk *model.FKInfo, values [][]types.Datum) (int64, error) {
+	var sql strings.Builder
+	sqlescape.MustFormatSQL(&sql, "select count(1) from (select distinct ")
+	for i, col := range fk.RefCols {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sqlescape.MustFormatSQL(&sql, "%n", col.O)
+	}
+	sqlescape.MustFormatSQL(&sql, " from %n.%n where (", fk.RefSchema.O, fk.RefTable.O)
+	for i, col := range fk.RefCols {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sqlescape.MustFormatSQL(&sql, "%n", col.O)
+	}
+	sql.WriteString(") in (")
+	for i, value := range values {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString("(")
+		for j, d := range value {
+			if j > 0 {
+				sql.WriteString(", ")
+			}
+			// The values which can't be converted to strings have been skipped when they are collected.
+			str, _ := d.ToString()
+			sqlescape.MustFormatSQL(&sql, "%?", str)
+		}
+		sql.WriteString(")")
+	}
+	sql.WriteString(")) t")
+
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
+	rows, _, err := e.ctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(ctx, nil, sql.String())
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return rows[0].GetInt64(0), nil
+}
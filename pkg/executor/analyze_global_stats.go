@@ -18,8 +18,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/domain"
 	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
@@ -38,6 +41,9 @@ type globalStatsKey struct {
 type globalStatsMap map[globalStatsKey]statstypes.GlobalStatsInfo
 
 func (e *AnalyzeExec) handleGlobalStats(statsHandle *handle.Handle, globalStatsMap globalStatsMap) error {
+	if variable.EnableBackgroundMergeGlobalStats.Load() {
+		return e.enqueueGlobalStatsMergeJobs(statsHandle, globalStatsMap)
+	}
 	globalStatsTableIDs := make(map[int64]struct{}, len(globalStatsMap))
 	for globalStatsID := range globalStatsMap {
 		globalStatsTableIDs[globalStatsID.tableID] = struct{}{}
@@ -58,19 +64,23 @@ func (e *AnalyzeExec) handleGlobalStats(statsHandle *handle.Handle, globalStatsM
 			AddNewAnalyzeJob(e.Ctx(), job)
 			statsHandle.StartAnalyzeJob(job)
 
-			mergeStatsErr := func() error {
-				globalOpts := e.opts
-				if e.OptionsMap != nil {
-					if v2Options, ok := e.OptionsMap[globalStatsID.tableID]; ok {
-						globalOpts = v2Options.FilledOpts
+			mergeStatsErr := func() (err error) {
+				defer func() {
+					// The merge panics when it exceeds tidb_mem_quota_analyze.
+					if r := recover(); r != nil {
+						err = getAnalyzePanicErr(r)
 					}
-				}
-				err := statsHandle.MergePartitionStats2GlobalStatsByTableID(
+				}()
+				err = statsHandle.MergePartitionStats2GlobalStatsByTableID(
 					e.Ctx(),
-					globalOpts, e.Ctx().GetInfoSchema().(infoschema.InfoSchema),
+					e.globalStatsOptions(globalStatsID.tableID), e.Ctx().GetInfoSchema().(infoschema.InfoSchema),
 					&info,
 					globalStatsID.tableID,
 				)
+				if err != nil && errors.Cause(err).Error() == globalPanicAnalyzeMemoryExceed {
+					// The panic is recovered by the workers of the async merge and returned as an error.
+					err = errAnalyzeOOM
+				}
 				if err != nil {
 					logutil.BgLogger().Warn("merge global stats failed",
 						zap.String("info", job.JobInfo), zap.Error(err), zap.Int64("tableID", tableID))
@@ -91,6 +101,30 @@ func (e *AnalyzeExec) handleGlobalStats(statsHandle *handle.Handle, globalStatsM
 	return nil
 }
 
+// enqueueGlobalStatsMergeJobs persists the global stats merge jobs instead of merging the global stats synchronously.
+// The jobs are executed later by the background worker of the stats owner.
+func (e *AnalyzeExec) enqueueGlobalStatsMergeJobs(statsHandle *handle.Handle, globalStatsMap globalStatsMap) error {
+	for globalStatsID, info := range globalStatsMap {
+		err := statsHandle.EnqueueGlobalStatsMergeJob(globalStatsID.tableID, &info, e.globalStatsOptions(globalStatsID.tableID))
+		if err != nil {
+			return err
+		}
+	}
+	if len(globalStatsMap) > 0 {
+		e.Ctx().GetSessionVars().StmtCtx.AppendNote(errors.NewNoStackError("The global stats will be merged in the background"))
+	}
+	return nil
+}
+
+func (e *AnalyzeExec) globalStatsOptions(tableID int64) map[ast.AnalyzeOptionType]uint64 {
+	if e.OptionsMap != nil {
+		if v2Options, ok := e.OptionsMap[tableID]; ok {
+			return v2Options.FilledOpts
+		}
+	}
+	return e.opts
+}
+
 func (e *AnalyzeExec) newAnalyzeHandleGlobalStatsJob(key globalStatsKey) *statistics.AnalyzeJob {
 	dom := domain.GetDomain(e.Ctx())
 	is := dom.InfoSchema()
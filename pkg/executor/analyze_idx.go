@@ -16,14 +16,12 @@ package executor
 
 import (
 	"context"
-	"math"
 	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/pkg/distsql"
 	"github.com/pingcap/tidb/pkg/domain"
-	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
@@ -149,12 +147,8 @@ func (e *AnalyzeIndexExec) fetchAnalyzeResult(ranges []*ranger.Range, isNullRang
 		kvReqBuilder = builder.SetIndexRangesForTables(e.ctx.GetDistSQLCtx(), []int64{e.tableID.GetStatisticsID()}, e.idxInfo.ID, ranges)
 	}
 	kvReqBuilder.SetResourceGroupTagger(e.ctx.GetSessionVars().StmtCtx.GetResourceGroupTagger())
-	startTS := uint64(math.MaxUint64)
-	isoLevel := kv.RC
-	if e.ctx.GetSessionVars().EnableAnalyzeSnapshot {
-		startTS = e.snapshot
-		isoLevel = kv.SI
-	}
+	e.setReplicaRead(kvReqBuilder)
+	startTS, isoLevel := e.readSnapshot()
 	kvReq, err := kvReqBuilder.
 		SetAnalyzeRequest(e.analyzePB, isoLevel).
 		SetStartTS(startTS).
@@ -166,7 +160,19 @@ func (e *AnalyzeIndexExec) fetchAnalyzeResult(ranges []*ranger.Range, isNullRang
 	if err != nil {
 		return err
 	}
-	ctx := context.TODO()
+	kvReq.Priority = e.priority
+	kvReq.RunawayChecker = e.ctx.GetSessionVars().StmtCtx.RunawayChecker
+	failpoint.Inject("assertAnalyzePriority", func(val failpoint.Value) {
+		if kvReq.Priority != val.(int) {
+			failpoint.Return(errors.Errorf("unexpected priority %d of the analyze request", kvReq.Priority))
+		}
+	})
+	failpoint.Inject("assertAnalyzeReplicaRead", func(val failpoint.Value) {
+		if err := checkAnalyzeReplicaRead(kvReq, val.(string)); err != nil {
+			failpoint.Return(err)
+		}
+	})
+	ctx := e.withRUDetails(context.TODO())
 	result, err := distsql.Analyze(ctx, e.ctx.GetClient(), kvReq, e.ctx.GetSessionVars().KVVars, e.ctx.GetSessionVars().InRestrictedSQL, e.ctx.GetDistSQLCtx())
 	if err != nil {
 		return err
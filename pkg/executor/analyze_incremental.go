@@ -0,0 +1,314 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/globalstats"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/collate"
+	"github.com/pingcap/tidb/pkg/util/hack"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+)
+
+// prepareIncrementalAnalyze loads the existing stats of the table and builds the handle range which covers
+// the rows appended after the last analyze.
+// If the incremental analyze can't be applied to the table, a warning is appended and nil stats is returned,
+// then the whole table is analyzed instead.
+func (e *AnalyzeColumnsExecV2) prepareIncrementalAnalyze(specialIndexes []*model.IndexInfo) (*statistics.Table, []*ranger.Range, error) {
+	oldStats, ranges, reason, err := e.buildIncrementalAnalyzeRanges(specialIndexes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if reason != "" {
+		e.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf(
+			"The INCREMENTAL keyword is ignored and the whole table %s.%s is analyzed, reason: %s",
+			e.DBName, e.TableName, reason))
+		return nil, nil, nil
+	}
+	return oldStats, ranges, nil
+}
+
+func (e *AnalyzeColumnsExecV2) buildIncrementalAnalyzeRanges(specialIndexes []*model.IndexInfo) (
+	oldStats *statistics.Table,
+	ranges []*ranger.Range,
+	reason string,
+	err error,
+) {
+	pkCol := e.tableInfo.GetPkColInfo()
+	if !e.tableInfo.PKIsHandle || pkCol == nil {
+		return nil, nil, "the table is not organized by an integer primary key", nil
+	}
+	if len(specialIndexes) > 0 {
+		return nil, nil, fmt.Sprintf("index %s contains virtual or prefix columns", specialIndexes[0].Name.O), nil
+	}
+	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
+	oldStats, err = statsHandle.TableStatsFromStorage(e.tableInfo, e.tableInfo.ID, true, 0)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if oldStats == nil || !oldStats.IsAnalyzed() {
+		return nil, nil, "the table has not been analyzed", nil
+	}
+	// All the columns and indexes to be analyzed must have the version 2 stats to be merged with.
+	for _, col := range e.colsInfo {
+		if c := oldStats.GetCol(col.ID); c == nil || !c.IsAnalyzed() || c.StatsVer != statistics.Version2 {
+			return nil, nil, fmt.Sprintf("column %s has no version 2 stats", col.Name.O), nil
+		}
+	}
+	for _, idx := range e.indexes {
+		if i := oldStats.GetIdx(idx.ID); i == nil || !i.IsAnalyzed() || i.StatsVer != statistics.Version2 {
+			return nil, nil, fmt.Sprintf("index %s has no version 2 stats", idx.Name.O), nil
+		}
+	}
+	lastPos, ok, err := incrementalAnalyzeLastPos(e.ctx.GetSessionVars().StmtCtx.TypeCtx(), oldStats.GetCol(pkCol.ID))
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if !ok {
+		return nil, nil, "the table was empty when it was analyzed last time", nil
+	}
+	highVal := types.NewIntDatum(math.MaxInt64)
+	if mysql.HasUnsignedFlag(pkCol.GetFlag()) {
+		highVal = types.NewUintDatum(math.MaxUint64)
+	}
+	ranges = []*ranger.Range{{
+		LowVal:     []types.Datum{lastPos},
+		LowExclude: true,
+		HighVal:    []types.Datum{highVal},
+		Collators:  collate.GetBinaryCollatorSlice(1),
+	}}
+	return oldStats, ranges, "", nil
+}
+
+// incrementalAnalyzeLastPos returns the max handle recorded by the stats of the handle column.
+// Since the stats are built from the samples, the rows between the max sampled handle and the real max handle
+// at the last analyze are counted again, and the error is small enough for the stats.
+func incrementalAnalyzeLastPos(tc types.Context, pkStats *statistics.Column) (lastPos types.Datum, ok bool, err error) {
+	if pkStats == nil {
+		return lastPos, false, nil
+	}
+	if hg := &pkStats.Histogram; hg.Len() > 0 {
+		hg.GetUpper(hg.Len() - 1).Copy(&lastPos)
+		ok = true
+	}
+	if pkStats.TopN != nil {
+		for _, meta := range pkStats.TopN.TopN {
+			_, d, err := codec.DecodeOne(meta.Encoded)
+			if err != nil {
+				return lastPos, false, err
+			}
+			if ok {
+				res, err := d.Compare(tc, &lastPos, collate.GetBinaryCollator())
+				if err != nil {
+					return lastPos, false, err
+				}
+				if res <= 0 {
+					continue
+				}
+			}
+			lastPos = d
+			ok = true
+		}
+	}
+	return lastPos, ok, nil
+}
+
+// mergeIncrementalAnalyzeResult merges the stats built from the appended rows into the existing stats,
+// in the same way as merging the partition-level stats into the global-level stats.
+func (e *AnalyzeColumnsExecV2) mergeIncrementalAnalyzeResult(oldStats *statistics.Table, results *statistics.AnalyzeResults) error {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	killer := &e.ctx.GetSessionVars().SQLKiller
+	// For the append-only table, the row count at the last analyze is the current count minus the modify count.
+	oldCount := max(e.baseCount-e.baseModifyCnt, 0)
+	newCount := results.Count
+	for _, result := range results.Ars {
+		isIndex := result.IsIndex == 1
+		for i, newHist := range result.Hist {
+			// It's normal virtual column, skip it.
+			if newHist == nil {
+				continue
+			}
+			oldHist, _, oldTopN, oldFms, ok := oldStats.GetStatsInfo(newHist.ID, isIndex, true)
+			if !ok {
+				return errors.Errorf("the existing stats of %d is missing", newHist.ID)
+			}
+			// Calculate the ratio before merging, since the histograms are modified when merging the TopNs.
+			newValueRatio, err := incrementalNewValueRatio(sc, isIndex, oldHist, oldTopN, newHist, result.TopNs[i])
+			if err != nil {
+				return err
+			}
+			topN, poppedTopN, hists, err := globalstats.MergePartTopN2GlobalTopN(sc.TimeZone(), statistics.Version2,
+				[]*statistics.TopN{oldTopN, result.TopNs[i]}, uint32(e.opts[ast.AnalyzeOptNumTopN]),
+				[]*statistics.Histogram{oldHist, newHist}, isIndex, killer)
+			if err != nil {
+				return err
+			}
+			hist, err := statistics.MergePartitionHist2GlobalHist(sc, hists, poppedTopN, int64(e.opts[ast.AnalyzeOptNumBuckets]), isIndex)
+			if err != nil {
+				return err
+			}
+			// NOTICE: after merging bucket NDVs have the trend to be underestimated, so for safe we don't use them.
+			for j := range hist.Buckets {
+				hist.Buckets[j].NDV = 0
+			}
+			hist.NDV = e.mergeIncrementalNDV(newHist.ID, isIndex, oldHist.NDV, newHist.NDV, newValueRatio, oldFms, result.Fms[i])
+			if oldCount+newCount > 0 {
+				hist.Correlation = (oldHist.Correlation*float64(oldCount) + newHist.Correlation*float64(newCount)) / float64(oldCount+newCount)
+			}
+			result.Hist[i] = hist
+			result.TopNs[i] = topN
+		}
+	}
+	results.Count = oldCount + newCount
+	results.Incremental = true
+	return nil
+}
+
+// incrementalNewValueRatio returns the ratio of the appended rows whose values are larger than all the existing values.
+// For the columns whose values increase along with the handle, the values of these rows are never seen before.
+func incrementalNewValueRatio(
+	sc *stmtctx.StatementContext,
+	isIndex bool,
+	oldHist *statistics.Histogram,
+	oldTopN *statistics.TopN,
+	newHist *statistics.Histogram,
+	newTopN *statistics.TopN,
+) (float64, error) {
+	tp := oldHist.Tp.GetType()
+	datumMap := statistics.NewDatumMapCache()
+	var oldMax *types.Datum
+	if oldHist.Len() > 0 {
+		oldMax = oldHist.GetUpper(oldHist.Len() - 1)
+	}
+	var oldTopNMetas, newTopNMetas []statistics.TopNMeta
+	if oldTopN != nil {
+		oldTopNMetas = oldTopN.TopN
+	}
+	if newTopN != nil {
+		newTopNMetas = newTopN.TopN
+	}
+	for _, meta := range oldTopNMetas {
+		d, err := datumMap.Put(meta, hack.String(meta.Encoded), tp, isIndex, sc.TimeZone())
+		if err != nil {
+			return 0, err
+		}
+		if oldMax != nil {
+			res, err := d.Compare(sc.TypeCtx(), oldMax, collate.GetBinaryCollator())
+			if err != nil {
+				return 0, err
+			}
+			if res <= 0 {
+				continue
+			}
+		}
+		oldMax = d.Clone()
+	}
+	if oldMax == nil {
+		return 0, nil
+	}
+	var total, larger float64
+	for i := range newHist.Buckets {
+		count := float64(newHist.Buckets[i].Count)
+		if i > 0 {
+			count -= float64(newHist.Buckets[i-1].Count)
+		}
+		total += count
+		res, err := newHist.GetLower(i).Compare(sc.TypeCtx(), oldMax, collate.GetBinaryCollator())
+		if err != nil {
+			return 0, err
+		}
+		if res > 0 {
+			larger += count
+		}
+	}
+	for _, meta := range newTopNMetas {
+		d, err := datumMap.Put(meta, hack.String(meta.Encoded), tp, isIndex, sc.TimeZone())
+		if err != nil {
+			return 0, err
+		}
+		total += float64(meta.Count)
+		res, err := d.Compare(sc.TypeCtx(), oldMax, collate.GetBinaryCollator())
+		if err != nil {
+			return 0, err
+		}
+		if res > 0 {
+			larger += float64(meta.Count)
+		}
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	return larger / total, nil
+}
+
+// mergeIncrementalNDV merges the NDV of the existing stats and the stats of the appended rows.
+// For the handle and the unique columns or indexes, the NDV is the sum of both sides. For others, the FMSketches
+// are merged if the FMSketch of the existing stats is persisted, otherwise the NDV is estimated by the ratio of
+// the appended rows whose values are never seen before.
+func (e *AnalyzeColumnsExecV2) mergeIncrementalNDV(
+	id int64,
+	isIndex bool,
+	oldNDV, newNDV int64,
+	newValueRatio float64,
+	oldFms, newFms *statistics.FMSketch,
+) int64 {
+	mergedFms := oldFms != nil && newFms != nil
+	if mergedFms {
+		newFms.MergeFMSketch(oldFms)
+	}
+	if e.isUniqueForIncrementalAnalyze(id, isIndex) {
+		return oldNDV + newNDV
+	}
+	estimated := max(oldNDV+int64(float64(newNDV)*newValueRatio), oldNDV, newNDV)
+	if mergedFms {
+		// The persisted FMSketch may only cover the rows since the first incremental analyze.
+		return max(newFms.NDV(), estimated)
+	}
+	return estimated
+}
+
+func (e *AnalyzeColumnsExecV2) isUniqueForIncrementalAnalyze(id int64, isIndex bool) bool {
+	if isIndex {
+		for _, idx := range e.indexes {
+			if idx.ID == id {
+				return idx.Unique
+			}
+		}
+		return false
+	}
+	if e.tableInfo.PKIsHandle && e.tableInfo.GetPkColInfo().ID == id {
+		return true
+	}
+	for _, idx := range e.tableInfo.Indices {
+		if !idx.Unique || len(idx.Columns) != 1 || idx.Columns[0].Length != types.UnspecifiedLength {
+			continue
+		}
+		if e.tableInfo.Columns[idx.Columns[0].Offset].ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"go.uber.org/zap"
+)
+
+// analyzeMemoryPoolCheckInterval is the interval to check whether the waiting analyze task is killed.
+const analyzeMemoryPoolCheckInterval = 100 * time.Millisecond
+
+var errAnalyzeMemoryPoolExit = errors.New("the analyze is aborted while waiting for the memory budget")
+
+// analyzeMemoryPool is the memory budget shared by all the analyze tasks of the instance, including the manual and
+// the auto ones. The size of the budget is tidb_mem_quota_analyze. Every task reserves its estimated memory from the
+// pool before it starts, and waits in the FIFO queue if the budget is not enough, so that the concurrent tasks queue
+// for the budget instead of racing toward the quota and failing together.
+type analyzeMemoryPool struct {
+	mu       sync.Mutex
+	reserved int64
+	waiters  *list.List
+	// quota returns the size of the budget, there is no limit if it's not positive.
+	quota func() int64
+}
+
+type analyzeMemoryWaiter struct {
+	bytes int64
+	ready chan struct{}
+}
+
+var globalAnalyzeMemoryPool = newAnalyzeMemoryPool(func() int64 {
+	return GlobalAnalyzeMemoryTracker.GetBytesLimit()
+})
+
+func newAnalyzeMemoryPool(quota func() int64) *analyzeMemoryPool {
+	return &analyzeMemoryPool{waiters: list.New(), quota: quota}
+}
+
+// acquire reserves the bytes from the pool and returns the function to release them. It blocks until the budget is
+// enough, exitCh is closed or checkKilled returns an error. A task larger than the whole budget only waits until
+// all the other tasks finish, so that it runs alone rather than waiting forever.
+func (p *analyzeMemoryPool) acquire(bytes int64, exitCh <-chan struct{}, checkKilled func() error) (release func(), err error) {
+	p.mu.Lock()
+	quota := p.quota()
+	if quota <= 0 || bytes <= 0 {
+		p.mu.Unlock()
+		return func() {}, nil
+	}
+	bytes = min(bytes, quota)
+	if p.waiters.Len() == 0 && p.reserved+bytes <= quota {
+		p.reserved += bytes
+		p.mu.Unlock()
+		return p.releaseFunc(bytes), nil
+	}
+	w := &analyzeMemoryWaiter{bytes: bytes, ready: make(chan struct{})}
+	elem := p.waiters.PushBack(w)
+	logutil.BgLogger().Info("analyze task waits for the memory budget", zap.Int64("bytes", bytes),
+		zap.Int64("reserved", p.reserved), zap.Int64("quota", quota), zap.Int("waiting", p.waiters.Len()))
+	p.mu.Unlock()
+
+	ticker := time.NewTicker(analyzeMemoryPoolCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.ready:
+			return p.releaseFunc(bytes), nil
+		case <-exitCh:
+			err = errAnalyzeMemoryPoolExit
+		case <-ticker.C:
+			err = checkKilled()
+		}
+		if err != nil {
+			p.mu.Lock()
+			select {
+			case <-w.ready:
+				// The budget has been granted, return it to the pool.
+				p.reserved -= bytes
+			default:
+				p.waiters.Remove(elem)
+			}
+			p.grantLocked()
+			p.mu.Unlock()
+			return nil, err
+		}
+	}
+}
+
+func (p *analyzeMemoryPool) releaseFunc(bytes int64) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			p.reserved -= bytes
+			p.grantLocked()
+			p.mu.Unlock()
+		})
+	}
+}
+
+// grantLocked grants the budget to the waiting tasks in order. It must be called with the mutex held.
+func (p *analyzeMemoryPool) grantLocked() {
+	quota := p.quota()
+	for p.waiters.Len() > 0 {
+		elem := p.waiters.Front()
+		w := elem.Value.(*analyzeMemoryWaiter)
+		// The waiters are granted at once if the quota is removed, or the task larger than the current quota can
+		// run alone.
+		if quota > 0 && p.reserved > 0 && p.reserved+w.bytes > quota {
+			return
+		}
+		p.waiters.Remove(elem)
+		p.reserved += w.bytes
+		close(w.ready)
+	}
+}
+
+// estimateSampleMemory estimates the memory of the samples collected by the task, which dominates the memory used
+// by the analyze. Every sample row holds a datum for each column and each index.
+func (e *AnalyzeColumnsExec) estimateSampleMemory() int64 {
+	colReq := e.analyzePB.ColReq
+	if colReq == nil {
+		return 0
+	}
+	sampleCount := colReq.SampleSize
+	if colReq.SampleRate != nil && *colReq.SampleRate > 0 {
+		sampleCount = int64(float64(e.baseCount) * *colReq.SampleRate)
+	}
+	if e.baseCount > 0 {
+		sampleCount = min(sampleCount, e.baseCount)
+	}
+	rowSize := int64(len(e.indexes)) * types.EmptyDatumSize
+	for _, col := range e.colsInfo {
+		rowSize += types.EmptyDatumSize + int64(chunk.EstimateTypeWidth(&col.FieldType))
+	}
+	return sampleCount * rowSize
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func noKilled() error { return nil }
+
+func TestAnalyzeMemoryPool(t *testing.T) {
+	pool := newAnalyzeMemoryPool(func() int64 { return 100 })
+	release1, err := pool.acquire(60, nil, noKilled)
+	require.NoError(t, err)
+	release2, err := pool.acquire(40, nil, noKilled)
+	require.NoError(t, err)
+
+	// The budget is used up, so the following tasks queue in order.
+	granted := make(chan int, 2)
+	releases := make(chan func(), 2)
+	go func() {
+		release, err := pool.acquire(50, nil, noKilled)
+		require.NoError(t, err)
+		granted <- 3
+		releases <- release
+	}()
+	require.Eventually(t, func() bool {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		return pool.waiters.Len() == 1
+	}, time.Second, 10*time.Millisecond)
+	go func() {
+		// It's larger than the quota, so it runs alone after all the other tasks finish.
+		release, err := pool.acquire(1000, nil, noKilled)
+		require.NoError(t, err)
+		granted <- 4
+		releases <- release
+	}()
+	require.Eventually(t, func() bool {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		return pool.waiters.Len() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	release2()
+	// The release is idempotent.
+	release2()
+	select {
+	case <-granted:
+		require.FailNow(t, "the waiting task should not be granted")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release1()
+	require.Equal(t, 3, <-granted)
+	release3 := <-releases
+	select {
+	case <-granted:
+		require.FailNow(t, "the task larger than the quota should run alone")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release3()
+	require.Equal(t, 4, <-granted)
+	(<-releases)()
+	require.Equal(t, int64(0), pool.reserved)
+}
+
+func TestAnalyzeMemoryPoolCancel(t *testing.T) {
+	pool := newAnalyzeMemoryPool(func() int64 { return 100 })
+	release, err := pool.acquire(100, nil, noKilled)
+	require.NoError(t, err)
+
+	exitCh := make(chan struct{})
+	close(exitCh)
+	_, err = pool.acquire(10, exitCh, noKilled)
+	require.ErrorIs(t, err, errAnalyzeMemoryPoolExit)
+
+	killErr := errors.New("killed")
+	_, err = pool.acquire(10, nil, func() error { return killErr })
+	require.ErrorIs(t, err, killErr)
+
+	pool.mu.Lock()
+	require.Equal(t, 0, pool.waiters.Len())
+	pool.mu.Unlock()
+	release()
+	require.Equal(t, int64(0), pool.reserved)
+
+	// No limit if the quota is not positive.
+	unlimited := newAnalyzeMemoryPool(func() int64 { return 0 })
+	for range 3 {
+		_, err = unlimited.acquire(1<<40, nil, noKilled)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int64(0), unlimited.reserved)
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"github.com/pingcap/tidb/pkg/statistics"
+)
+
+// analyzePartitionScheduler limits the number of the partitions analyzed concurrently to
+// tidb_analyze_partition_concurrency, so the memory used to analyze the tables with thousands of partitions is
+// bounded. A partition holds a slot from dispatching its first task until the results of all its tasks are received.
+type analyzePartitionScheduler struct {
+	slots chan struct{}
+	// jobPartitions maps the jobs of the tasks to their partitions, it's read-only after the scheduler is built.
+	jobPartitions map[*statistics.AnalyzeJob]int64
+	// dispatched is the partitions holding a slot, it's only accessed by the goroutine dispatching the tasks.
+	dispatched map[int64]struct{}
+	// remaining is the number of the tasks of each partition whose results are not received yet, it's only accessed
+	// by the goroutine receiving the results.
+	remaining map[int64]int
+}
+
+// newAnalyzePartitionScheduler groups the tasks of the same partition together, so a partition holding a slot
+// doesn't wait for the slots held by the others. It returns a nil scheduler if the partitions are no more than
+// the concurrency.
+func newAnalyzePartitionScheduler(tasks []*analyzeTask, concurrency int) ([]*analyzeTask, *analyzePartitionScheduler) {
+	grouped := make(map[int64][]*analyzeTask)
+	order := make([]int64, 0, len(tasks))
+	partitionCnt := 0
+	for _, task := range tasks {
+		tableID := getTableIDFromTask(task)
+		physicalID := tableID.GetStatisticsID()
+		if _, ok := grouped[physicalID]; !ok {
+			order = append(order, physicalID)
+			if tableID.IsPartitionTable() {
+				partitionCnt++
+			}
+		}
+		grouped[physicalID] = append(grouped[physicalID], task)
+	}
+	if concurrency <= 0 || partitionCnt <= concurrency {
+		return tasks, nil
+	}
+	s := &analyzePartitionScheduler{
+		slots:         make(chan struct{}, concurrency),
+		jobPartitions: make(map[*statistics.AnalyzeJob]int64, len(tasks)),
+		dispatched:    make(map[int64]struct{}, concurrency),
+		remaining:     make(map[int64]int, partitionCnt),
+	}
+	ordered := make([]*analyzeTask, 0, len(tasks))
+	for _, physicalID := range order {
+		for _, task := range grouped[physicalID] {
+			ordered = append(ordered, task)
+			tableID := getTableIDFromTask(task)
+			if !tableID.IsPartitionTable() {
+				continue
+			}
+			s.jobPartitions[task.job] = physicalID
+			s.remaining[physicalID]++
+		}
+	}
+	return ordered, s
+}
+
+// acquire returns the channel to send to for a slot before dispatching the task. It returns nil if the task doesn't
+// need a slot, and a send on the nil channel blocks forever, so the caller must check it.
+func (s *analyzePartitionScheduler) acquire(task *analyzeTask) chan<- struct{} {
+	if s == nil {
+		return nil
+	}
+	physicalID, ok := s.jobPartitions[task.job]
+	if !ok {
+		return nil
+	}
+	if _, ok := s.dispatched[physicalID]; ok {
+		return nil
+	}
+	s.dispatched[physicalID] = struct{}{}
+	return s.slots
+}
+
+// release releases the slot of the partition once the results of all its tasks are received.
+func (s *analyzePartitionScheduler) release(job *statistics.AnalyzeJob) {
+	if s == nil {
+		return
+	}
+	physicalID, ok := s.jobPartitions[job]
+	if !ok {
+		return
+	}
+	s.remaining[physicalID]--
+	if s.remaining[physicalID] == 0 {
+		<-s.slots
+	}
+}
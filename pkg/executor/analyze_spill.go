@@ -0,0 +1,101 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/disk"
+	"github.com/pingcap/tidb/pkg/util/memory"
+)
+
+// spilledSampleFieldTypes are the field types of the spilled samples, which are the values encoded by
+// codec.EncodeValue and their ordinals.
+var spilledSampleFieldTypes = []*types.FieldType{types.NewFieldType(mysql.TypeBlob), types.NewFieldType(mysql.TypeLonglong)}
+
+// spillSampleCollector writes the samples of the collector to disk and releases their memory. The collectors of the
+// columns are only kept for the extended stats after their histograms are built, so the wide tables don't keep
+// another copy of the samples in memory.
+func spillSampleCollector(collector *statistics.SampleCollector, memTracker *memory.Tracker, diskTracker *disk.Tracker) (*chunk.DataInDiskByChunks, error) {
+	inDisk := chunk.NewDataInDiskByChunks(spilledSampleFieldTypes)
+	if diskTracker != nil {
+		inDisk.GetDiskTracker().AttachTo(diskTracker)
+	}
+	chk := chunk.NewChunkWithCapacity(spilledSampleFieldTypes, 1024)
+	var (
+		buf []byte
+		err error
+	)
+	for _, item := range collector.Samples {
+		// The time zone is not converted, since the values are decoded in the same time zone.
+		buf, err = codec.EncodeValue(time.UTC, buf[:0], item.Value)
+		if err != nil {
+			inDisk.Close()
+			return nil, errors.Trace(err)
+		}
+		chk.AppendBytes(0, buf)
+		chk.AppendInt64(1, int64(item.Ordinal))
+		if chk.IsFull() {
+			if err = inDisk.Add(chk); err != nil {
+				inDisk.Close()
+				return nil, err
+			}
+			chk.Reset()
+		}
+	}
+	if chk.NumRows() > 0 {
+		if err = inDisk.Add(chk); err != nil {
+			inDisk.Close()
+			return nil, err
+		}
+	}
+	memTracker.Release(collector.MemSize)
+	collector.Samples = nil
+	collector.MemSize = 0
+	return inDisk, nil
+}
+
+// restoreSampleCollector reads the samples spilled by spillSampleCollector back to the collector. The values are
+// restored in the form decoded by codec.DecodeOne, e.g. the time values are restored as the packed uint64, which keeps
+// their order and their keys encoded by codec.EncodeKey.
+func restoreSampleCollector(collector *statistics.SampleCollector, inDisk *chunk.DataInDiskByChunks, memTracker *memory.Tracker) error {
+	samples := make([]*statistics.SampleItem, 0, inDisk.NumRows())
+	memSize := inDisk.NumRows() * (8 + statistics.EmptySampleItemSize)
+	for i := 0; i < inDisk.NumChunks(); i++ {
+		chk, err := inDisk.GetChunk(i)
+		if err != nil {
+			return err
+		}
+		for j := 0; j < chk.NumRows(); j++ {
+			row := chk.GetRow(j)
+			_, d, err := codec.DecodeOne(row.GetBytes(0))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			memSize += d.MemUsage()
+			samples = append(samples, &statistics.SampleItem{Value: d, Ordinal: int(row.GetInt64(1))})
+		}
+	}
+	memTracker.Consume(memSize)
+	collector.Samples = samples
+	collector.MemSize = memSize
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+// analyzeStaleSnapshot is the stale snapshot shared by the analyze of the instance. It's only advanced once it's
+// older than twice the staleness, so the scans of the hot tables mostly read at the same snapshot, whose locks
+// have been resolved by the former scans, and the GC can proceed since the snapshot is never too old.
+var analyzeStaleSnapshot struct {
+	sync.Mutex
+	ts uint64
+	// advancedAt is the time when the snapshot is advanced.
+	advancedAt time.Time
+	staleness  time.Duration
+}
+
+// getAnalyzeStaleSnapshotTS returns the stale snapshot at which the analyze reads the tables. It returns 0 if the
+// stale snapshot is disabled by tidb_analyze_snapshot_staleness.
+func getAnalyzeStaleSnapshotTS(ctx context.Context, store kv.Storage) (uint64, error) {
+	staleness := variable.AnalyzeSnapshotStaleness.Load()
+	if staleness <= 0 {
+		return 0, nil
+	}
+	analyzeStaleSnapshot.Lock()
+	defer analyzeStaleSnapshot.Unlock()
+	if analyzeStaleSnapshot.ts > 0 && analyzeStaleSnapshot.staleness == staleness &&
+		time.Since(analyzeStaleSnapshot.advancedAt) < staleness {
+		return analyzeStaleSnapshot.ts, nil
+	}
+	prevSecond := uint64((staleness + time.Second - 1) / time.Second)
+	ts, err := store.GetOracle().GetStaleTimestamp(ctx, oracle.GlobalTxnScope, prevSecond)
+	if err != nil {
+		return 0, err
+	}
+	analyzeStaleSnapshot.ts = ts
+	analyzeStaleSnapshot.advancedAt = time.Now()
+	analyzeStaleSnapshot.staleness = staleness
+	return ts, nil
+}
+
+// getAnalyzeStaleReadTS returns the stale snapshot at which the analyze reads the table, or 0 if the analyze reads
+// the latest data.
+func (b *executorBuilder) getAnalyzeStaleReadTS(tblInfo *model.TableInfo) uint64 {
+	ts, err := getAnalyzeStaleSnapshotTS(context.Background(), b.ctx.GetStore())
+	if err != nil {
+		// The stale snapshot only reduces the overhead of the scan, so the analyze falls back to read the latest data.
+		logutil.BgLogger().Warn("get the stale snapshot of analyze failed", zap.Error(err))
+		return 0
+	}
+	// The table, its partitions or indexes may not be public at the stale snapshot if they are changed recently.
+	if tblInfo == nil || ts <= tblInfo.UpdateTS {
+		return 0
+	}
+	return ts
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/planner/core"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+)
+
+// analyzeTemporaryTables collects the stats of the temporary tables and keeps them in the session. The data of the
+// temporary tables is only visible to the session, so it can't be sampled by the coprocessor like the normal tables.
+// Instead, the row count, the NDVs and the NULL counts are collected by an aggregation in the current session.
+func (e *AnalyzeExec) analyzeTemporaryTables(ctx context.Context) error {
+	for _, task := range e.tempTableTasks {
+		if err := e.analyzeTemporaryTable(ctx, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *AnalyzeExec) analyzeTemporaryTable(ctx context.Context, task core.AnalyzeTempTableTask) error {
+	cols := make([]*model.ColumnInfo, 0, len(task.TblInfo.Columns))
+	for _, col := range task.TblInfo.Columns {
+		if col.State == model.StatePublic && !col.Hidden && col.FieldType.GetType() != mysql.TypeTiDBVectorFloat32 {
+			cols = append(cols, col)
+		}
+	}
+	var sql strings.Builder
+	sql.WriteString("select count(1)")
+	for _, col := range cols {
+		sqlescape.MustFormatSQL(&sql, ", approx_count_distinct(%n), count(%n)", col.Name.O, col.Name.O)
+	}
+	sqlescape.MustFormatSQL(&sql, " from %n.%n", task.DBName, task.TblInfo.Name.O)
+
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnStats)
+	exec := e.Ctx().GetRestrictedSQLExecutor()
+	rows, _, err := exec.ExecRestrictedSQL(ctx, []sqlexec.OptionFuncAlias{sqlexec.ExecOptionUseCurSession}, sql.String())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	row := rows[0]
+	rowCount := row.GetInt64(0)
+	s := &statistics.TemporaryTableStats{
+		ColNDVs:      make(map[int64]int64, len(cols)),
+		NullCounts:   make(map[int64]int64, len(cols)),
+		RowCount:     rowCount,
+		AnalyzeCount: rowCount,
+	}
+	for i, col := range cols {
+		s.ColNDVs[col.ID] = row.GetInt64(2*i + 1)
+		s.NullCounts[col.ID] = rowCount - row.GetInt64(2*i+2)
+	}
+	// The rows written by the current transaction are counted, so record its delta to avoid counting them twice.
+	sessVars := e.Ctx().GetSessionVars()
+	if txnCtx := sessVars.TxnCtx; txnCtx != nil {
+		s.StartTS = txnCtx.StartTS
+		s.TxnDelta = txnCtx.GetDeltaForTable(task.TblInfo.ID).Delta
+	}
+	statistics.SetTemporaryTableStats(sessVars, task.TblInfo.ID, s)
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/terror"
+	"github.com/pingcap/tidb/pkg/planner/core/resolve"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	handleutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/collate"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+)
+
+// hasAvailableTiFlashReplica checks whether the analyzed table or partition has an available TiFlash replica.
+func hasAvailableTiFlashReplica(tblInfo *model.TableInfo, tableID statistics.AnalyzeTableID) bool {
+	if tblInfo == nil || tblInfo.TiFlashReplica == nil {
+		return false
+	}
+	if tableID.IsPartitionTable() {
+		return tblInfo.TiFlashReplica.IsPartitionAvailable(tableID.PartitionID)
+	}
+	return tblInfo.TiFlashReplica.Available
+}
+
+// collectSamplesFromTiFlash collects the samples by scanning the TiFlash replica of the analyzed table.
+// TiFlash doesn't serve the analyze requests of the coprocessor, so the rows are read by an internal query which
+// is forced to read from TiFlash, and sampled in TiDB in the same way as the coprocessor of TiKV does.
+func (e *AnalyzeColumnsExecV2) collectSamplesFromTiFlash() (statistics.RowSampleCollector, error) {
+	var sql strings.Builder
+	sqlescape.MustFormatSQL(&sql, "select /*+ read_from_storage(tiflash[%n]) */ ", e.TableName)
+	for i, col := range e.colsInfo {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		switch {
+		case col.ID == model.ExtraHandleID:
+			sql.WriteString(model.ExtraHandleName.L)
+		case col.IsVirtualGenerated():
+			// The virtual generated columns are not stored, their samples are filled after the collection.
+			sql.WriteString("null")
+		default:
+			sqlescape.MustFormatSQL(&sql, "%n", col.Name.O)
+		}
+	}
+	sqlescape.MustFormatSQL(&sql, " from %n.%n", e.DBName, e.TableName)
+	if e.PartitionName != "" {
+		sqlescape.MustFormatSQL(&sql, " partition(%n)", e.PartitionName)
+	}
+
+	colReq := e.analyzePB.ColReq
+	fts := make([]*types.FieldType, len(e.colsInfo))
+	collators := make([]collate.Collator, len(e.colsInfo))
+	for i, col := range e.colsInfo {
+		fts[i] = &col.FieldType
+		if fts[i].EvalType() == types.ETString {
+			collators[i] = collate.GetCollator(fts[i].GetCollate())
+		}
+	}
+	colGroups := make([][]int64, 0, len(colReq.ColumnGroups))
+	for _, group := range colReq.ColumnGroups {
+		colGroups = append(colGroups, group.ColumnOffsets)
+	}
+
+	var collector statistics.RowSampleCollector
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
+	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
+	err := handleutil.CallWithSCtx(statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		rs, err := sctx.GetSQLExecutor().ExecuteInternal(ctx, sql.String())
+		if err != nil {
+			return err
+		}
+		recordSet := newTiFlashSampleRecordSet(ctx, e.ctx, sctx, rs)
+		defer terror.Call(recordSet.Close)
+		/* #nosec G404 */
+		builder := &statistics.RowSampleBuilder{
+			Sc:              e.ctx.GetSessionVars().StmtCtx,
+			RecordSet:       recordSet,
+			ColsFieldType:   fts,
+			Collators:       collators,
+			ColGroups:       colGroups,
+			MaxSampleSize:   int(colReq.SampleSize),
+			MaxFMSketchSize: int(colReq.SketchSize),
+			SampleRate:      colReq.GetSampleRate(),
+			Rng:             rand.New(rand.NewSource(time.Now().UnixNano())),
+		}
+		collector, err = builder.Collect()
+		return err
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	e.memTracker.Consume(collector.Base().MemSize)
+	statsHandle.UpdateAnalyzeJobProgress(e.job, collector.Base().Count)
+	return collector, nil
+}
+
+// tiFlashSampleRecordSet wraps the result of the internal query which reads the TiFlash replica, and encodes the
+// rows into the format of the samples returned by the coprocessor, so they can be fed to the RowSampleBuilder.
+type tiFlashSampleRecordSet struct {
+	// ctx is used to read the internal query, since the RowSampleBuilder doesn't pass its context.
+	ctx context.Context
+	// sctx is the session of the analyze, which is checked for the kill signal.
+	sctx sessionctx.Context
+	// innerLoc is the time zone of the internal session, in which the timestamps are read.
+	innerLoc *time.Location
+	inner    sqlexec.RecordSet
+	innerChk *chunk.Chunk
+	fields   []*resolve.ResultField
+}
+
+func newTiFlashSampleRecordSet(ctx context.Context, sctx, innerSctx sessionctx.Context, inner sqlexec.RecordSet) *tiFlashSampleRecordSet {
+	innerFields := inner.Fields()
+	fields := make([]*resolve.ResultField, len(innerFields))
+	for i := range fields {
+		rf := new(resolve.ResultField)
+		rf.Column = new(model.ColumnInfo)
+		ft := types.FieldType{}
+		ft.SetType(mysql.TypeBlob)
+		ft.SetFlen(mysql.MaxBlobWidth)
+		ft.SetCharset(charset.CharsetUTF8)
+		ft.SetCollate(charset.CollationUTF8)
+		rf.Column.FieldType = ft
+		fields[i] = rf
+	}
+	return &tiFlashSampleRecordSet{
+		ctx:      ctx,
+		sctx:     sctx,
+		innerLoc: innerSctx.GetSessionVars().Location(),
+		inner:    inner,
+		innerChk: inner.NewChunk(nil),
+		fields:   fields,
+	}
+}
+
+// Fields implements the sqlexec.RecordSet interface.
+func (s *tiFlashSampleRecordSet) Fields() []*resolve.ResultField {
+	return s.fields
+}
+
+// Next implements the sqlexec.RecordSet interface.
+func (s *tiFlashSampleRecordSet) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if err := s.sctx.GetSessionVars().SQLKiller.HandleSignal(); err != nil {
+		return err
+	}
+	if err := s.inner.Next(s.ctx, s.innerChk); err != nil {
+		return err
+	}
+	innerFields := s.inner.Fields()
+	var buf []byte
+	for i := range s.innerChk.NumRows() {
+		row := s.innerChk.GetRow(i)
+		for j, f := range innerFields {
+			d := row.GetDatum(j, &f.Column.FieldType)
+			if d.IsNull() {
+				req.AppendNull(j)
+				continue
+			}
+			var err error
+			buf, err = tablecodec.EncodeValue(s.innerLoc, buf[:0], d)
+			if err != nil {
+				return err
+			}
+			req.AppendBytes(j, buf)
+		}
+	}
+	return nil
+}
+
+// NewChunk implements the sqlexec.RecordSet interface.
+func (s *tiFlashSampleRecordSet) NewChunk(chunk.Allocator) *chunk.Chunk {
+	fts := make([]*types.FieldType, 0, len(s.fields))
+	for _, f := range s.fields {
+		fts = append(fts, &f.Column.FieldType)
+	}
+	return chunk.NewChunkWithCapacity(fts, s.innerChk.Capacity())
+}
+
+// Close implements the sqlexec.RecordSet interface.
+func (s *tiFlashSampleRecordSet) Close() error {
+	return s.inner.Close()
+}
@@ -93,6 +93,11 @@ func isAnalyzeWorkerPanic(err error) bool {
 	return err == errAnalyzeWorkerPanic || err == errAnalyzeOOM
 }
 
+// isAnalyzeOOM checks whether the analyze fails because it exceeds tidb_mem_quota_analyze.
+func isAnalyzeOOM(err error) bool {
+	return err != nil && errors.ErrorEqual(err, errAnalyzeOOM)
+}
+
 func getAnalyzePanicErr(r any) error {
 	if msg, ok := r.(string); ok {
 		if msg == globalPanicAnalyzeMemoryExceed {
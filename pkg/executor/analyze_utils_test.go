@@ -15,11 +15,23 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/metrics"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+	"github.com/pingcap/tidb/pkg/util/memory"
+	"github.com/pingcap/tidb/pkg/util/mock"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
+	clientutil "github.com/tikv/client-go/v2/util"
 )
 
 // https://github.com/pingcap/tidb/issues/45690
@@ -27,3 +39,98 @@ func TestGetAnalyzePanicErr(t *testing.T) {
 	errMsg := fmt.Sprintf("%s", getAnalyzePanicErr(exeerrors.ErrMemoryExceedForQuery.GenWithStackByArgs(123)))
 	require.NotContains(t, errMsg, `%!(EXTRA`)
 }
+
+func TestAnalyzePartitionScheduler(t *testing.T) {
+	newTask := func(tableID, partitionID int64) *analyzeTask {
+		idxExec := &AnalyzeIndexExec{}
+		idxExec.tableID = statistics.AnalyzeTableID{TableID: tableID, PartitionID: partitionID}
+		return &analyzeTask{taskType: idxTask, idxExec: idxExec, job: &statistics.AnalyzeJob{}}
+	}
+	tasks := []*analyzeTask{
+		newTask(1, 11), newTask(1, 12), newTask(1, 11), newTask(2, statistics.NonPartitionTableID), newTask(1, 13),
+	}
+	// There is no limit if the partitions are no more than the concurrency.
+	ordered, s := newAnalyzePartitionScheduler(tasks, 3)
+	require.Nil(t, s)
+	require.Equal(t, tasks, ordered)
+
+	// The tasks of the same partition are grouped together.
+	ordered, s = newAnalyzePartitionScheduler(tasks, 1)
+	require.NotNil(t, s)
+	require.Equal(t, []*analyzeTask{tasks[0], tasks[2], tasks[1], tasks[3], tasks[4]}, ordered)
+	// The first task of a partition acquires a slot, and the slot is released after the results of all its tasks are
+	// received. The tasks of the non-partitioned tables don't need a slot.
+	slots := s.acquire(ordered[0])
+	require.NotNil(t, slots)
+	slots <- struct{}{}
+	require.Nil(t, s.acquire(ordered[1]))
+	s.release(ordered[0].job)
+	require.Len(t, s.slots, 1)
+	s.release(ordered[1].job)
+	require.Len(t, s.slots, 0)
+	require.Nil(t, s.acquire(ordered[3]))
+	require.NotNil(t, s.acquire(ordered[2]))
+}
+
+func TestSpillSampleCollector(t *testing.T) {
+	values := []types.Datum{
+		types.NewIntDatum(-1),
+		types.NewBytesDatum([]byte("abc")),
+		types.NewDecimalDatum(types.NewDecFromStringForTest("1.25")),
+		types.NewTimeDatum(types.NewTime(types.FromDate(2026, 10, 16, 1, 2, 3, 0), mysql.TypeDatetime, 0)),
+	}
+	// More than a chunk of samples.
+	samples := make([]*statistics.SampleItem, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		samples = append(samples, &statistics.SampleItem{Value: values[i%len(values)], Ordinal: 2000 - i})
+	}
+	collector := &statistics.SampleCollector{Samples: samples, MemSize: 100, Count: 2000}
+	memTracker := memory.NewTracker(0, -1)
+	memTracker.Consume(100)
+	inDisk, err := spillSampleCollector(collector, memTracker, nil)
+	require.NoError(t, err)
+	defer inDisk.Close()
+	require.Nil(t, collector.Samples)
+	require.Zero(t, collector.MemSize)
+	require.Zero(t, memTracker.BytesConsumed())
+	require.Equal(t, int64(2000), inDisk.NumRows())
+
+	// The restored values keep their keys and ordinals.
+	require.NoError(t, restoreSampleCollector(collector, inDisk, memTracker))
+	require.Len(t, collector.Samples, len(samples))
+	require.Equal(t, collector.MemSize, memTracker.BytesConsumed())
+	for i, item := range collector.Samples {
+		require.Equal(t, samples[i].Ordinal, item.Ordinal)
+		expected, err := codec.EncodeKey(time.UTC, nil, samples[i].Value)
+		require.NoError(t, err)
+		actual, err := codec.EncodeKey(time.UTC, nil, item.Value)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	}
+}
+
+func TestReportAnalyzeRUConsumption(t *testing.T) {
+	sctx := mock.NewContext()
+	sctx.GetSessionVars().StmtCtx.ResourceGroupName = "rg1"
+	e := &AnalyzeExec{BaseExecutor: exec.NewBaseExecutor(sctx, nil, 0)}
+	colExec := &AnalyzeColumnsExec{}
+	colExec.ruDetails = clientutil.NewRUDetailsWith(1.5, 0.5, 0)
+	idxExec := &AnalyzeIndexExec{}
+	idxExec.ruDetails = clientutil.NewRUDetailsWith(2, 0, 0)
+	tasks := []*analyzeTask{{taskType: colTask, colExec: colExec}, {taskType: idxTask, idxExec: idxExec}}
+
+	readCounter := func(tp string) float64 {
+		m := &dto.Metric{}
+		require.NoError(t, metrics.AnalyzeRUCounter.WithLabelValues("rg1", tp).Write(m))
+		return m.GetCounter().GetValue()
+	}
+	oldRRU, oldWRU := readCounter("rru"), readCounter("wru")
+	stmtRUDetails := clientutil.NewRUDetailsWith(1, 1, 0)
+	ctx := context.WithValue(context.Background(), clientutil.RUDetailsCtxKey, stmtRUDetails)
+	e.reportRUConsumption(ctx, tasks)
+	require.Equal(t, 3.5, readCounter("rru")-oldRRU)
+	require.Equal(t, 0.5, readCounter("wru")-oldWRU)
+	// The request units of analyze are added to the statement.
+	require.Equal(t, 4.5, stmtRUDetails.RRU())
+	require.Equal(t, 1.5, stmtRUDetails.WRU())
+}
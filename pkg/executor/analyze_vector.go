@@ -0,0 +1,152 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+	"go.uber.org/zap"
+)
+
+// buildVectorStats builds the vector stats of the vector columns of the analyzed table, and appends them to the
+// extended stats. The vector columns are not sampled, so their stats are collected by an aggregation on the table.
+func (e *AnalyzeColumnsExecV2) buildVectorStats(extStats *statistics.ExtendedStatsColl) (*statistics.ExtendedStatsColl, error) {
+	var vecCols []*model.ColumnInfo
+	for _, col := range e.tableInfo.Columns {
+		if col.State == model.StatePublic && !col.Hidden && col.FieldType.GetType() == mysql.TypeTiDBVectorFloat32 {
+			vecCols = append(vecCols, col)
+		}
+	}
+	if len(vecCols) == 0 {
+		return extStats, nil
+	}
+	vecStats, err := e.collectVectorStats(vecCols)
+	if err != nil {
+		// The vector stats are only used to estimate the ANN searches, so the failure doesn't fail the analyze.
+		logutil.BgLogger().Warn("collect vector stats failed", zap.String("table", e.tableInfo.Name.O), zap.Error(err))
+		return extStats, nil
+	}
+	coverage, err := e.collectVectorIndexCoverage()
+	if err != nil {
+		// The coverage is unknown without TiFlash, in which case the vector indexes are regarded as fully built.
+		logutil.BgLogger().Warn("collect vector index coverage failed", zap.String("table", e.tableInfo.Name.O), zap.Error(err))
+	}
+	for i, col := range vecCols {
+		for _, idx := range e.tableInfo.Indices {
+			if idx.VectorInfo == nil || idx.State != model.StatePublic || idx.Columns[0].Offset != col.Offset {
+				continue
+			}
+			if ratio, ok := coverage[idx.ID]; ok {
+				if vecStats[i].IndexCoverage == nil {
+					vecStats[i].IndexCoverage = make(map[int64]float64)
+				}
+				vecStats[i].IndexCoverage[idx.ID] = ratio
+			}
+		}
+		item, err := statistics.NewVectorStatsItem(col.ID, vecStats[i])
+		if err != nil {
+			return nil, err
+		}
+		if extStats == nil {
+			extStats = statistics.NewExtendedStatsColl()
+		}
+		extStats.Stats[statistics.VectorStatsName(col.ID)] = item
+	}
+	return extStats, nil
+}
+
+// collectVectorStats counts the rows, the NULL vectors and the min and max dimensions of the vector columns.
+func (e *AnalyzeColumnsExecV2) collectVectorStats(vecCols []*model.ColumnInfo) ([]*statistics.VectorStats, error) {
+	dbInfo, ok := e.ctx.GetInfoSchema().SchemaByID(e.tableInfo.DBID)
+	if !ok {
+		return nil, errors.Errorf("schema of table %s not found", e.tableInfo.Name.O)
+	}
+	var sql strings.Builder
+	sql.WriteString("select count(1)")
+	for _, col := range vecCols {
+		sqlescape.MustFormatSQL(&sql, ", count(%n), min(vec_dims(%n)), max(vec_dims(%n))", col.Name.O, col.Name.O, col.Name.O)
+	}
+	sqlescape.MustFormatSQL(&sql, " from %n.%n", dbInfo.Name.O, e.tableInfo.Name.O)
+	if e.tableID.IsPartitionTable() {
+		if pi := e.tableInfo.GetPartitionInfo(); pi != nil {
+			for _, def := range pi.Definitions {
+				if def.ID == e.tableID.PartitionID {
+					sqlescape.MustFormatSQL(&sql, " partition(%n)", def.Name.O)
+					break
+				}
+			}
+		}
+	}
+
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
+	rows, _, err := e.ctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(ctx, nil, sql.String())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no result of the vector stats aggregation")
+	}
+	row := rows[0]
+	rowCount := row.GetInt64(0)
+	ret := make([]*statistics.VectorStats, 0, len(vecCols))
+	for i := range vecCols {
+		s := &statistics.VectorStats{
+			RowCount:  rowCount,
+			NullCount: rowCount - row.GetInt64(3*i+1),
+		}
+		// The min and max dimensions are NULL if all the vectors are NULL.
+		if !row.IsNull(3*i + 2) {
+			s.MinDimension = row.GetInt64(3*i + 2)
+			s.MaxDimension = row.GetInt64(3*i + 3)
+		}
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
+
+// collectVectorIndexCoverage returns the fractions of the rows covered by the vector indexes of the analyzed table,
+// which are reported by the TiFlash instances.
+func (e *AnalyzeColumnsExecV2) collectVectorIndexCoverage() (map[int64]float64, error) {
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
+	rows, _, err := e.ctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(ctx, nil,
+		"select index_id, rows_stable_indexed, rows_stable_not_indexed, rows_delta_indexed, rows_delta_not_indexed "+
+			"from information_schema.tiflash_indexes where table_id = %?", e.tableID.GetStatisticsID())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	indexed := make(map[int64]int64, len(rows))
+	total := make(map[int64]int64, len(rows))
+	// Each TiFlash instance reports the rows of its own replicas.
+	for _, row := range rows {
+		indexID := row.GetInt64(0)
+		indexed[indexID] += row.GetInt64(1) + row.GetInt64(3)
+		total[indexID] += row.GetInt64(1) + row.GetInt64(2) + row.GetInt64(3) + row.GetInt64(4)
+	}
+	coverage := make(map[int64]float64, len(total))
+	for indexID, cnt := range total {
+		if cnt > 0 {
+			coverage[indexID] = float64(indexed[indexID]) / float64(cnt)
+		}
+	}
+	return coverage, nil
+}
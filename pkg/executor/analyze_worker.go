@@ -21,24 +21,24 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics/handle"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/util/logutil"
-	"github.com/pingcap/tidb/pkg/util/sqlkiller"
 	"go.uber.org/zap"
 )
 
 type analyzeSaveStatsWorker struct {
 	resultsCh <-chan *statistics.AnalyzeResults
 	errCh     chan<- error
-	killer    *sqlkiller.SQLKiller
+	// checkpoint is nil if the analyze checkpoints are disabled.
+	checkpoint *analyzeCheckpoint
 }
 
 func newAnalyzeSaveStatsWorker(
 	resultsCh <-chan *statistics.AnalyzeResults,
 	errCh chan<- error,
-	killer *sqlkiller.SQLKiller) *analyzeSaveStatsWorker {
+	checkpoint *analyzeCheckpoint) *analyzeSaveStatsWorker {
 	worker := &analyzeSaveStatsWorker{
-		resultsCh: resultsCh,
-		errCh:     errCh,
-		killer:    killer,
+		resultsCh:  resultsCh,
+		errCh:      errCh,
+		checkpoint: checkpoint,
 	}
 	return worker
 }
@@ -51,12 +51,6 @@ func (worker *analyzeSaveStatsWorker) run(ctx context.Context, statsHandle *hand
 		}
 	}()
 	for results := range worker.resultsCh {
-		if err := worker.killer.HandleSignal(); err != nil {
-			finishJobWithLog(statsHandle, results.Job, err)
-			results.DestroyAndPutToPool()
-			worker.errCh <- err
-			return
-		}
 		err := statsHandle.SaveTableStatsToStorage(results, analyzeSnapshot, util.StatsMetaHistorySourceAnalyze)
 		if err != nil {
 			logutil.Logger(ctx).Error("save table stats to storage failed", zap.Error(err))
@@ -64,6 +58,7 @@ func (worker *analyzeSaveStatsWorker) run(ctx context.Context, statsHandle *hand
 			worker.errCh <- err
 		} else {
 			finishJobWithLog(statsHandle, results.Job, nil)
+			worker.checkpoint.saved(statsHandle, results.TableID)
 		}
 		results.DestroyAndPutToPool()
 		if err != nil {
@@ -0,0 +1,182 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/resourcegroup"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+)
+
+var _ exec.Executor = &AutoAnalyzeOptionsExec{}
+
+// autoAnalyzeConfigColumns maps the auto analyze options to the columns of mysql.auto_analyze_config.
+var autoAnalyzeConfigColumns = map[ast.AutoAnalyzeOptionType]string{
+	ast.AutoAnalyzeOptionPriority:               "priority",
+	ast.AutoAnalyzeOptionWindowStart:            "window_start",
+	ast.AutoAnalyzeOptionWindowEnd:              "window_end",
+	ast.AutoAnalyzeOptionSampleRate:             "sample_rate",
+	ast.AutoAnalyzeOptionColumnChoice:           "column_choice",
+	ast.AutoAnalyzeOptionDisabled:               "disabled",
+	ast.AutoAnalyzeOptionCoolDown:               "cool_down",
+	ast.AutoAnalyzeOptionResourceGroup:          "resource_group",
+	ast.AutoAnalyzeOptionTargetQError:           "target_qerror",
+	ast.AutoAnalyzeOptionTargetQErrorPercentile: "target_qerror_percentile",
+}
+
+// AutoAnalyzeOptionsExec represents an executor setting the auto analyze options of a table.
+type AutoAnalyzeOptionsExec struct {
+	exec.BaseExecutor
+	Table   *ast.TableName
+	Options []*ast.AutoAnalyzeOption
+}
+
+// Next implements the Executor Next interface.
+func (e *AutoAnalyzeOptionsExec) Next(ctx context.Context, _ *chunk.Chunk) error {
+	is := domain.GetDomain(e.Ctx()).InfoSchema()
+	tbl, err := is.TableByName(ctx, e.Table.Schema, e.Table.Name)
+	if err != nil {
+		return err
+	}
+	columns, values, err := e.buildConfig(is)
+	if err != nil {
+		return err
+	}
+	// The options which are not specified are kept as they are.
+	sql := new(strings.Builder)
+	sqlescape.MustFormatSQL(sql, "INSERT INTO mysql.auto_analyze_config (table_id")
+	for _, col := range columns {
+		sqlescape.MustFormatSQL(sql, ", %n", col)
+	}
+	sqlescape.MustFormatSQL(sql, ") VALUES (%?", tbl.Meta().ID)
+	for _, val := range values {
+		sqlescape.MustFormatSQL(sql, ", %?", val)
+	}
+	sqlescape.MustFormatSQL(sql, ") ON DUPLICATE KEY UPDATE update_time = CURRENT_TIMESTAMP(6)")
+	for _, col := range columns {
+		sqlescape.MustFormatSQL(sql, ", %n = VALUES(%n)", col, col)
+	}
+	internalCtx := kv.WithInternalSourceType(ctx, kv.InternalTxnStats)
+	_, _, err = e.Ctx().GetRestrictedSQLExecutor().ExecRestrictedSQL(internalCtx, nil, sql.String())
+	return err
+}
+
+// buildConfig validates the options and converts them to the columns and values of mysql.auto_analyze_config.
+// The options set to DEFAULT are converted to NULL.
+func (e *AutoAnalyzeOptionsExec) buildConfig(is infoschema.InfoSchema) ([]string, []any, error) {
+	columns := make([]string, 0, len(e.Options))
+	values := make([]any, 0, len(e.Options))
+	specified := make(map[ast.AutoAnalyzeOptionType]struct{}, len(e.Options))
+	typeCtx := e.Ctx().GetSessionVars().StmtCtx.TypeCtx()
+	for _, opt := range e.Options {
+		name := ast.AutoAnalyzeOptionString[opt.Tp]
+		if _, ok := specified[opt.Tp]; ok {
+			return nil, nil, errors.Errorf("Auto Analyze Options: %s is specified more than once", name)
+		}
+		specified[opt.Tp] = struct{}{}
+		columns = append(columns, autoAnalyzeConfigColumns[opt.Tp])
+		if opt.Value == nil {
+			values = append(values, nil)
+			continue
+		}
+		d := types.NewDatum(opt.Value.GetValue())
+		isString := d.Kind() == types.KindString
+		switch opt.Tp {
+		case ast.AutoAnalyzeOptionWindowStart, ast.AutoAnalyzeOptionWindowEnd:
+			if !isString {
+				return nil, nil, errors.Errorf("Auto Analyze Options: %s should be a string", name)
+			}
+			if _, err := time.ParseInLocation(variable.FullDayTimeFormat, d.GetString(), time.UTC); err != nil {
+				return nil, nil, errors.Errorf("Auto Analyze Options: %s should be in the format of '%s'", name, variable.FullDayTimeFormat)
+			}
+			values = append(values, d.GetString())
+		case ast.AutoAnalyzeOptionColumnChoice:
+			choice := ""
+			if isString {
+				choice = strings.ToUpper(d.GetString())
+			}
+			if choice != pmodel.AllColumns.String() && choice != pmodel.PredicateColumns.String() {
+				return nil, nil, errors.Errorf("Auto Analyze Options: %s should be 'ALL' or 'PREDICATE'", name)
+			}
+			values = append(values, choice)
+		case ast.AutoAnalyzeOptionResourceGroup:
+			if !isString {
+				return nil, nil, errors.Errorf("Auto Analyze Options: %s should be a string", name)
+			}
+			groupName := strings.ToLower(d.GetString())
+			if groupName != resourcegroup.DefaultResourceGroupName {
+				if _, ok := is.ResourceGroupByName(pmodel.NewCIStr(groupName)); !ok {
+					return nil, nil, infoschema.ErrResourceGroupNotExists.GenWithStackByArgs(groupName)
+				}
+			}
+			values = append(values, groupName)
+		case ast.AutoAnalyzeOptionPriority, ast.AutoAnalyzeOptionSampleRate,
+			ast.AutoAnalyzeOptionTargetQError, ast.AutoAnalyzeOptionTargetQErrorPercentile:
+			if isString {
+				return nil, nil, errors.Errorf("Auto Analyze Options: %s should be a number", name)
+			}
+			v, err := d.ToFloat64(typeCtx)
+			if err != nil {
+				return nil, nil, err
+			}
+			if opt.Tp == ast.AutoAnalyzeOptionSampleRate && (v <= 0 || v > 1) {
+				return nil, nil, errors.Errorf("Auto Analyze Options: %s should be in (0, 1]", name)
+			}
+			if opt.Tp == ast.AutoAnalyzeOptionTargetQError && v <= 1 {
+				return nil, nil, errors.Errorf("Auto Analyze Options: %s should be larger than 1", name)
+			}
+			if opt.Tp == ast.AutoAnalyzeOptionTargetQErrorPercentile && (v <= 0 || v >= 100) {
+				return nil, nil, errors.Errorf("Auto Analyze Options: %s should be in (0, 100)", name)
+			}
+			values = append(values, v)
+		case ast.AutoAnalyzeOptionDisabled, ast.AutoAnalyzeOptionCoolDown:
+			if d.Kind() != types.KindInt64 && d.Kind() != types.KindUint64 {
+				return nil, nil, errors.Errorf("Auto Analyze Options: %s should be an integer", name)
+			}
+			v, err := d.ToInt64(typeCtx)
+			if err != nil {
+				return nil, nil, err
+			}
+			if opt.Tp == ast.AutoAnalyzeOptionDisabled && v != 0 && v != 1 {
+				return nil, nil, errors.Errorf("Auto Analyze Options: %s should be 0 or 1", name)
+			}
+			values = append(values, v)
+		}
+	}
+	return columns, values, nil
+}
+
+// Close implements the Executor Close interface.
+func (*AutoAnalyzeOptionsExec) Close() error {
+	return nil
+}
+
+// Open implements the Executor Open interface.
+func (*AutoAnalyzeOptionsExec) Open(context.Context) error {
+	return nil
+}
@@ -50,7 +50,7 @@ func (e *SQLBindExec) Next(_ context.Context, req *chunk.Chunk) error {
 	case plannercore.OpCaptureBindings:
 		e.captureBindings()
 	case plannercore.OpEvolveBindings:
-		return nil // not support yet
+		return e.evolveBindings()
 	case plannercore.OpReloadBindings:
 		return e.reloadBindings()
 	case plannercore.OpSetBindingStatus:
@@ -163,7 +163,13 @@ func (e *SQLBindExec) flushBindings() error {
 }
 
 func (e *SQLBindExec) captureBindings() {
-	domain.GetDomain(e.Ctx()).BindHandle().CaptureBaselines()
+	bindHandle := domain.GetDomain(e.Ctx()).BindHandle()
+	bindHandle.CaptureBaselines()
+	bindHandle.CaptureRegressedBaselines()
+}
+
+func (e *SQLBindExec) evolveBindings() error {
+	return domain.GetDomain(e.Ctx()).BindHandle().EvolveBaselines(true)
 }
 
 func (e *SQLBindExec) reloadBindings() error {
@@ -86,6 +86,7 @@ import (
 	"github.com/pingcap/tidb/pkg/util/memory"
 	"github.com/pingcap/tidb/pkg/util/ranger"
 	rangerctx "github.com/pingcap/tidb/pkg/util/ranger/context"
+	"github.com/pingcap/tidb/pkg/util/replayer"
 	"github.com/pingcap/tidb/pkg/util/rowcodec"
 	"github.com/pingcap/tidb/pkg/util/tiflash"
 	"github.com/pingcap/tidb/pkg/util/timeutil"
@@ -94,6 +95,7 @@ import (
 	"github.com/tikv/client-go/v2/tikv"
 	"github.com/tikv/client-go/v2/txnkv"
 	"github.com/tikv/client-go/v2/txnkv/txnsnapshot"
+	clientutil "github.com/tikv/client-go/v2/util"
 )
 
 // executorBuilder builds an Executor from a Plan.
@@ -202,8 +204,14 @@ func (b *executorBuilder) build(p base.Plan) exec.Executor {
 		return b.buildImportInto(v)
 	case *plannercore.LoadData:
 		return b.buildLoadData(v)
+	case *plannercore.AnalyzeDatabase:
+		return b.buildAnalyzeDatabase(v)
 	case *plannercore.LoadStats:
 		return b.buildLoadStats(v)
+	case *plannercore.ModifyStats:
+		return b.buildModifyStats(v)
+	case *plannercore.AutoAnalyzeOptions:
+		return b.buildAutoAnalyzeOptions(v)
 	case *plannercore.LockStats:
 		return b.buildLockStats(v)
 	case *plannercore.UnlockStats:
@@ -314,6 +322,18 @@ func (b *executorBuilder) build(p base.Plan) exec.Executor {
 		return b.buildCompactTable(v)
 	case *plannercore.AdminShowBDRRole:
 		return b.buildAdminShowBDRRole(v)
+	case *plannercore.AdminBenchStatsLoad:
+		return b.buildAdminBenchStatsLoad(v)
+	case *plannercore.AdminShowColumnStatsUsage:
+		return b.buildAdminShowColumnStatsUsage(v)
+	case *plannercore.AdminValidateVectorIndex:
+		return b.buildAdminValidateVectorIndex(v)
+	case *plannercore.AdminCheckStats:
+		return b.buildAdminCheckStats(v)
+	case *plannercore.AdminShowPlanDiff:
+		return b.buildAdminShowPlanDiff(v)
+	case *plannercore.AdminCalibrateCostModel:
+		return b.buildAdminCalibrateCostModel(v)
 	case *plannercore.PhysicalExpand:
 		return b.buildExpand(v)
 	case *plannercore.RecommendIndexPlan:
@@ -722,6 +742,20 @@ func (b *executorBuilder) buildCheckIndexRange(v *plannercore.CheckIndexRange) e
 }
 
 func (b *executorBuilder) buildChecksumTable(v *plannercore.ChecksumTable) exec.Executor {
+	if v.CompareWith != "" {
+		startTs, err := b.getSnapshotTS()
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		return &ChecksumTableCompareExec{
+			BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+			dbName:       v.Tables[0].DBInfo.Name.O,
+			tblInfo:      v.Tables[0].TableInfo,
+			dsn:          v.CompareWith,
+			snapshot:     startTs,
+		}
+	}
 	e := &ChecksumTableExec{
 		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		tables:       make(map[int64]*checksumContext),
@@ -895,6 +929,9 @@ func (b *executorBuilder) buildShow(v *plannercore.PhysicalShow) exec.Executor {
 		Extended:              v.Extended,
 		Extractor:             v.Extractor,
 		ImportJobID:           v.ImportJobID,
+		StatsSnapshot:         v.StatsSnapshot,
+		AllAnalyzeJobs:        v.AllAnalyzeJobs,
+		StatsFilter:           v.StatsFilter,
 	}
 	if e.Tp == ast.ShowMasterStatus || e.Tp == ast.ShowBinlogStatus {
 		// show master status need start ts.
@@ -1073,10 +1110,37 @@ func (b *executorBuilder) buildLoadData(v *plannercore.LoadData) exec.Executor {
 	}
 }
 
+func (b *executorBuilder) buildAnalyzeDatabase(v *plannercore.AnalyzeDatabase) exec.Executor {
+	return &AnalyzeDatabaseExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		dbName:       v.DBName,
+		concurrency:  v.Concurrency,
+		budget:       v.Budget,
+	}
+}
+
 func (b *executorBuilder) buildLoadStats(v *plannercore.LoadStats) exec.Executor {
 	e := &LoadStatsExec{
 		BaseExecutor: exec.NewBaseExecutor(b.ctx, nil, v.ID()),
-		info:         &LoadStatsInfo{v.Path, b.ctx},
+		info:         &LoadStatsInfo{Path: v.Path, Ctx: b.ctx, Merge: v.Merge},
+	}
+	return e
+}
+
+func (b *executorBuilder) buildModifyStats(v *plannercore.ModifyStats) exec.Executor {
+	e := &ModifyStatsExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, nil, v.ID()),
+		Table:        v.Table,
+		Options:      v.Options,
+	}
+	return e
+}
+
+func (b *executorBuilder) buildAutoAnalyzeOptions(v *plannercore.AutoAnalyzeOptions) exec.Executor {
+	e := &AutoAnalyzeOptionsExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, nil, v.ID()),
+		Table:        v.Table,
+		Options:      v.Options,
 	}
 	return e
 }
@@ -1113,6 +1177,12 @@ func (b *executorBuilder) buildPlanReplayer(v *plannercore.PlanReplayer) exec.Ex
 				PlanDigest: v.PlanDigest,
 			},
 		}
+		if v.CaptureThresholds != nil {
+			e.CaptureInfo.Thresholds = replayer.PlanReplayerCaptureThresholds{
+				EstimationError: v.CaptureThresholds.EstimationError,
+				Latency:         v.CaptureThresholds.Latency,
+			}
+		}
 		return e
 	}
 	if v.Remove {
@@ -2278,7 +2348,14 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) exec.Ex
 			strings.ToLower(infoschema.TableTiDBCheckConstraints),
 			strings.ToLower(infoschema.TableKeywords),
 			strings.ToLower(infoschema.TableTiDBIndexUsage),
-			strings.ToLower(infoschema.ClusterTableTiDBIndexUsage):
+			strings.ToLower(infoschema.ClusterTableTiDBIndexUsage),
+			strings.ToLower(infoschema.TableTiDBTopSQLCPUUsage),
+			strings.ToLower(infoschema.ClusterTableTiDBTopSQLCPUUsage),
+			strings.ToLower(infoschema.TableTiDBStatsHealth),
+			strings.ToLower(infoschema.TableColumnStatistics),
+			strings.ToLower(infoschema.TableObjectDependencies),
+			strings.ToLower(infoschema.TableTiDBLongTxnEvents),
+			strings.ToLower(infoschema.TableTiDBPartitionStatsRollup):
 			memTracker := memory.NewTracker(v.ID(), -1)
 			memTracker.AttachTo(b.ctx.GetSessionVars().StmtCtx.MemTracker)
 			return &MemTableReaderExec{
@@ -2756,9 +2833,10 @@ func (b *executorBuilder) buildAnalyzeIndexPushdown(task plannercore.AnalyzeInde
 			Flags:          sc.PushDownFlags(),
 			TimeZoneOffset: offset,
 		},
-		opts:     opts,
-		job:      job,
-		snapshot: startTS,
+		opts:        opts,
+		job:         job,
+		snapshot:    startTS,
+		staleReadTS: b.getAnalyzeStaleReadTS(task.TblInfo),
 	}
 	e := &AnalyzeIndexExec{
 		baseAnalyzeExec: base,
@@ -2794,6 +2872,11 @@ func (b *executorBuilder) buildAnalyzeSamplingPushdown(
 	if task.V2Options != nil {
 		opts = task.V2Options.FilledOpts
 	}
+	samplingStrategy := statistics.GetSamplingStrategy(opts[ast.AnalyzeOptSampling])
+	if err := samplingStrategy.CheckOptions(opts[ast.AnalyzeOptNumSamples], math.Float64frombits(opts[ast.AnalyzeOptSampleRate])); err != nil {
+		b.err = err
+		return nil
+	}
 	availableIdx := make([]*model.IndexInfo, 0, len(task.Indexes))
 	colGroups := make([]*tipb.AnalyzeColumnGroup, 0, len(task.Indexes))
 	if len(task.Indexes) > 0 {
@@ -2831,12 +2914,43 @@ func (b *executorBuilder) buildAnalyzeSamplingPushdown(
 	failpoint.Inject("injectBaseModifyCount", func(val failpoint.Value) {
 		modifyCount = int64(val.(int))
 	})
+	readFromTiFlash := hasAvailableTiFlashReplica(task.TblInfo, task.TableID)
+	if opts[ast.AnalyzeOptTiFlash] == 1 {
+		if !readFromTiFlash {
+			b.err = errors.Errorf("the TiFlash replica of table %s.%s is not available", task.DBName, task.TableName)
+			return nil
+		}
+	} else {
+		readFromTiFlash = readFromTiFlash && b.ctx.GetSessionVars().AnalyzeAutoTiFlash
+	}
+	blockSampleRate := math.Float64frombits(opts[ast.AnalyzeOptBlockSampleRate])
+	if blockSampleRate == 0 {
+		blockSampleRate = b.ctx.GetSessionVars().AnalyzeBlockSampleRate
+	}
+	// The quick analyze only scans a tiny fraction of the regions of the TiKV replica, and estimates the row count
+	// by the region metadata from PD.
+	var quickRowCount float64
+	if task.Quick {
+		readFromTiFlash = false
+		blockSampleRate = quickAnalyzeBlockSampleRate
+		if approxCount, hasPD := b.getApproximateTableCountFromStorage(task.TableID.GetStatisticsID(), task); hasPD {
+			quickRowCount = approxCount
+		}
+	}
+	// The TiFlash replica is read by a full scan, so the block sampling doesn't apply to it.
+	if readFromTiFlash {
+		blockSampleRate = 0
+	}
 	sampleRate := new(float64)
 	var sampleRateReason string
 	if opts[ast.AnalyzeOptNumSamples] == 0 {
 		*sampleRate = math.Float64frombits(opts[ast.AnalyzeOptSampleRate])
 		if *sampleRate < 0 {
 			*sampleRate, sampleRateReason = b.getAdjustedSampleRate(task)
+			// Only a fraction of the regions are scanned, so sample more rows from them to keep the sample size.
+			if blockSampleRate > 0 && blockSampleRate < 1 {
+				*sampleRate = min(1, *sampleRate/blockSampleRate)
+			}
 			if task.PartitionName != "" {
 				sc.AppendNote(errors.NewNoStackErrorf(
 					`Analyze use auto adjusted sample rate %f for table %s.%s's partition %s, reason to use this rate is "%s"`,
@@ -2873,9 +2987,10 @@ func (b *executorBuilder) buildAnalyzeSamplingPushdown(
 			Flags:          sc.PushDownFlags(),
 			TimeZoneOffset: offset,
 		},
-		opts:     opts,
-		job:      job,
-		snapshot: startTS,
+		opts:        opts,
+		job:         job,
+		snapshot:    startTS,
+		staleReadTS: b.getAnalyzeStaleReadTS(task.TblInfo),
 	}
 	e := &AnalyzeColumnsExec{
 		baseAnalyzeExec:         base,
@@ -2887,6 +3002,9 @@ func (b *executorBuilder) buildAnalyzeSamplingPushdown(
 		schemaForVirtualColEval: schemaForVirtualColEval,
 		baseCount:               count,
 		baseModifyCnt:           modifyCount,
+		blockSampleRate:         blockSampleRate,
+		quickRowCount:           quickRowCount,
+		readFromTiFlash:         readFromTiFlash,
 	}
 	e.analyzePB.ColReq = &tipb.AnalyzeColumnsReq{
 		BucketSize:   int64(opts[ast.AnalyzeOptNumBuckets]),
@@ -3007,9 +3125,10 @@ func (b *executorBuilder) buildAnalyzeColumnsPushdown(
 			Flags:          sc.PushDownFlags(),
 			TimeZoneOffset: offset,
 		},
-		opts:     opts,
-		job:      job,
-		snapshot: startTS,
+		opts:        opts,
+		job:         job,
+		snapshot:    startTS,
+		staleReadTS: b.getAnalyzeStaleReadTS(task.TblInfo),
 	}
 	e := &AnalyzeColumnsExec{
 		baseAnalyzeExec: base,
@@ -3060,13 +3179,14 @@ func (b *executorBuilder) buildAnalyzeColumnsPushdown(
 func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) exec.Executor {
 	gp := domain.GetDomain(b.ctx).StatsHandle().GPool()
 	e := &AnalyzeExec{
-		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
-		tasks:        make([]*analyzeTask, 0, len(v.ColTasks)+len(v.IdxTasks)),
-		opts:         v.Opts,
-		OptionsMap:   v.OptionsMap,
-		wg:           util.NewWaitGroupPool(gp),
-		gp:           gp,
-		errExitCh:    make(chan struct{}),
+		BaseExecutor:   exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		tasks:          make([]*analyzeTask, 0, len(v.ColTasks)+len(v.IdxTasks)),
+		opts:           v.Opts,
+		OptionsMap:     v.OptionsMap,
+		tempTableTasks: v.TempTableTasks,
+		wg:             util.NewWaitGroupPool(gp),
+		gp:             gp,
+		errExitCh:      make(chan struct{}),
 	}
 	autoAnalyze := ""
 	if b.ctx.GetSessionVars().InRestrictedSQL {
@@ -3108,9 +3228,55 @@ func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) exec.Executor {
 			return nil
 		}
 	}
+	// The priorities of kv requests have the same values as the priorities in the statements.
+	priority := int(v.Priority)
+	if v.Priority == mysql.NoPriority || v.Priority == mysql.DelayedPriority {
+		priority = kv.PriorityNormal
+	}
+	for _, task := range e.tasks {
+		if task.colExec != nil {
+			task.colExec.priority = priority
+			task.colExec.readReplicaScope = b.getAnalyzeReadReplicaScope(task.colExec.tableID)
+			task.colExec.ruDetails = clientutil.NewRUDetails()
+		}
+		if task.idxExec != nil {
+			task.idxExec.priority = priority
+			task.idxExec.readReplicaScope = b.getAnalyzeReadReplicaScope(task.idxExec.tableID)
+			task.idxExec.ruDetails = clientutil.NewRUDetails()
+		}
+	}
 	return e
 }
 
+// getAnalyzeReadReplicaScope returns the zone of the TiDB instance if tidb_analyze_read_nearby_replica is on and the
+// leaders of the table or the partition are placed out of the zone or the region of the TiDB instance by the
+// placement rules, so that the analyze reads the nearby replicas instead of pulling the data across the regions.
+// It returns an empty string if the leaders should be read.
+func (b *executorBuilder) getAnalyzeReadReplicaScope(tableID statistics.AnalyzeTableID) string {
+	if !b.ctx.GetSessionVars().AnalyzeReadNearbyReplica {
+		return ""
+	}
+	labels := config.GetGlobalConfig().Labels
+	zone := labels[placement.DCLabelKey]
+	if zone == "" {
+		return ""
+	}
+	// The partitions without their own placement rules follow the rules of the table.
+	bundle, ok := b.is.PlacementBundleByPhysicalTableID(tableID.GetStatisticsID())
+	if !ok {
+		bundle, ok = b.is.PlacementBundleByPhysicalTableID(tableID.TableID)
+	}
+	if !ok {
+		return ""
+	}
+	for key, value := range labels {
+		if leader, ok := bundle.GetLeaderDC(key); ok && leader != value {
+			return zone
+		}
+	}
+	return ""
+}
+
 // markChildrenUsedCols compares each child with the output schema, and mark
 // each column of the child is used by output or not.
 func markChildrenUsedCols(outputCols []*expression.Column, childSchemas ...*expression.Schema) (childrenUsed [][]int) {
@@ -5683,11 +5849,61 @@ func (b *executorBuilder) buildAdminShowBDRRole(v *plannercore.AdminShowBDRRole)
 	return &AdminShowBDRRoleExec{BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID())}
 }
 
+func (b *executorBuilder) buildAdminBenchStatsLoad(v *plannercore.AdminBenchStatsLoad) exec.Executor {
+	return &AdminBenchStatsLoadExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		dbName:       v.Table.DBInfo.Name.O,
+		tblInfo:      v.Table.TableInfo,
+	}
+}
+
+func (b *executorBuilder) buildAdminShowColumnStatsUsage(v *plannercore.AdminShowColumnStatsUsage) exec.Executor {
+	return &AdminShowColumnStatsUsageExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		dbName:       v.Table.DBInfo.Name.O,
+		tblInfo:      v.Table.TableInfo,
+	}
+}
+
+func (b *executorBuilder) buildAdminValidateVectorIndex(v *plannercore.AdminValidateVectorIndex) exec.Executor {
+	return &AdminValidateVectorIndexExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		dbName:       v.Table.DBInfo.Name.O,
+		tblInfo:      v.Table.TableInfo,
+		indexInfo:    v.IndexInfo,
+		sampleCount:  v.SampleCount,
+	}
+}
+
+func (b *executorBuilder) buildAdminCheckStats(v *plannercore.AdminCheckStats) exec.Executor {
+	return &AdminCheckStatsExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		dbName:       v.Table.DBInfo.Name.O,
+		tblInfo:      v.Table.TableInfo,
+		sampleCount:  v.SampleCount,
+		threshold:    v.Threshold,
+	}
+}
+
+func (b *executorBuilder) buildAdminCalibrateCostModel(v *plannercore.AdminCalibrateCostModel) exec.Executor {
+	return &AdminCalibrateCostModelExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+	}
+}
+
+func (b *executorBuilder) buildAdminShowPlanDiff(v *plannercore.AdminShowPlanDiff) exec.Executor {
+	return &AdminShowPlanDiffExec{
+		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		limit:        v.Limit,
+	}
+}
+
 func (b *executorBuilder) buildRecommendIndex(v *plannercore.RecommendIndexPlan) exec.Executor {
 	return &RecommendIndexExec{
 		BaseExecutor: exec.NewBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		Action:       v.Action,
 		SQL:          v.SQL,
+		SQLDigest:    v.SQLDigest,
 		AdviseID:     v.AdviseID,
 		Options:      v.Options,
 	}
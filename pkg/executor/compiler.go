@@ -353,6 +353,8 @@ func getStmtDbLabel(stmtNode ast.StmtNode, resolveCtx *resolve.Context) map[stri
 				dbLabelSet[dbLabel] = struct{}{}
 			}
 		}
+	case *ast.AnalyzeDatabaseStmt:
+		dbLabelSet[x.DBName.O] = struct{}{}
 	case *ast.DropStatsStmt:
 		tables := x.Tables
 		for _, table := range tables {
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
 	pmodel "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/parser/terror"
@@ -36,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/sessiontxn"
 	"github.com/pingcap/tidb/pkg/sessiontxn/staleread"
+	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/table/temptable"
 	"github.com/pingcap/tidb/pkg/util/chunk"
@@ -44,6 +46,7 @@ import (
 	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
 	"github.com/pingcap/tidb/pkg/util/gcutil"
 	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
 	"go.uber.org/zap"
 )
 
@@ -169,9 +172,9 @@ func (e *DDLExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 		err = e.executeDropDatabase(x)
 	case *ast.DropTableStmt:
 		if x.IsView {
-			err = e.executeDropView(x)
+			err = e.executeDropView(ctx, x)
 		} else {
-			err = e.executeDropTable(x)
+			err = e.executeDropTable(ctx, x)
 			if err == nil {
 				err = e.dropLocalTemporaryTables(localTempTablesToDrop)
 			}
@@ -203,7 +206,7 @@ func (e *DDLExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 	case *ast.CreateSequenceStmt:
 		err = e.executeCreateSequence(x)
 	case *ast.DropSequenceStmt:
-		err = e.executeDropSequence(x)
+		err = e.executeDropSequence(ctx, x)
 	case *ast.AlterSequenceStmt:
 		err = e.executeAlterSequence(x)
 	case *ast.CreatePlacementPolicyStmt:
@@ -236,9 +239,27 @@ func (e *DDLExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 	txnCtx.InfoSchema = is
 	// DDL will force commit old transaction, after DDL, in transaction status should be false.
 	e.Ctx().GetSessionVars().SetInTxn(false)
+	if s, ok := e.stmt.(*ast.CreateTableStmt); ok && s.Datagen != nil {
+		return e.importDatagen(ctx, s)
+	}
 	return nil
 }
 
+// importDatagen imports the data generated by `CREATE TABLE ... AS DATAGEN(...)` into
+// the created table, it's the same as `IMPORT INTO ... FROM DATAGEN(...)`.
+func (e *DDLExec) importDatagen(ctx context.Context, s *ast.CreateTableStmt) error {
+	stmt := &ast.ImportIntoStmt{
+		Table:   &ast.TableName{Schema: s.Table.Schema, Name: s.Table.Name},
+		Datagen: s.Datagen,
+	}
+	var sb strings.Builder
+	if err := stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return err
+	}
+	_, err := sqlexec.ExecSQL(ctx, e.Ctx().GetSQLExecutor(), sb.String())
+	return err
+}
+
 func (e *DDLExec) executeTruncateTable(s *ast.TruncateTableStmt) error {
 	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	if _, exist := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); exist {
@@ -351,16 +372,37 @@ func (e *DDLExec) executeDropDatabase(s *ast.DropDatabaseStmt) error {
 	return err
 }
 
-func (e *DDLExec) executeDropTable(s *ast.DropTableStmt) error {
-	return e.ddlExecutor.DropTable(e.Ctx(), s)
+func (e *DDLExec) executeDropTable(ctx context.Context, s *ast.DropTableStmt) error {
+	reportFn, err := e.handleDropDependencies(ctx, s.Tables, s.Behavior)
+	if err != nil {
+		return err
+	}
+	if err = e.ddlExecutor.DropTable(e.Ctx(), s); err == nil {
+		reportFn()
+	}
+	return err
 }
 
-func (e *DDLExec) executeDropView(s *ast.DropTableStmt) error {
-	return e.ddlExecutor.DropView(e.Ctx(), s)
+func (e *DDLExec) executeDropView(ctx context.Context, s *ast.DropTableStmt) error {
+	reportFn, err := e.handleDropDependencies(ctx, s.Tables, s.Behavior)
+	if err != nil {
+		return err
+	}
+	if err = e.ddlExecutor.DropView(e.Ctx(), s); err == nil {
+		reportFn()
+	}
+	return err
 }
 
-func (e *DDLExec) executeDropSequence(s *ast.DropSequenceStmt) error {
-	return e.ddlExecutor.DropSequence(e.Ctx(), s)
+func (e *DDLExec) executeDropSequence(ctx context.Context, s *ast.DropSequenceStmt) error {
+	reportFn, err := e.handleDropDependencies(ctx, s.Sequences, s.Behavior)
+	if err != nil {
+		return err
+	}
+	if err = e.ddlExecutor.DropSequence(e.Ctx(), s); err == nil {
+		reportFn()
+	}
+	return err
 }
 
 func (e *DDLExec) dropLocalTemporaryTables(localTempTables []*ast.TableName) error {
@@ -369,10 +411,14 @@ func (e *DDLExec) dropLocalTemporaryTables(localTempTables []*ast.TableName) err
 	}
 
 	for _, tb := range localTempTables {
+		tbl, ok := e.getLocalTemporaryTable(tb.Schema, tb.Name)
 		err := e.tempTableDDL.DropLocalTemporaryTable(tb.Schema, tb.Name)
 		if err != nil {
 			return err
 		}
+		if ok {
+			statistics.DropTemporaryTableStats(e.Ctx().GetSessionVars(), tbl.Meta().ID)
+		}
 	}
 
 	return nil
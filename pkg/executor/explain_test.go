@@ -453,6 +453,7 @@ func TestExplainFormatInCtx(t *testing.T) {
 		types.ExplainFormatTiDBJSON,
 		types.ExplainFormatCostTrace,
 		types.ExplainFormatPlanCache,
+		types.ExplainFormatStats,
 	}
 
 	tk.MustExec("select * from t")
@@ -472,6 +473,53 @@ func TestExplainFormatInCtx(t *testing.T) {
 	}
 }
 
+func TestExplainFormatStats(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, key ia(a))")
+
+	// The stats info of the scans without stats shows the pseudo stats.
+	rows := tk.MustQuery("explain format = 'stats' select * from t use index(ia) where a > 1").Rows()
+	require.Len(t, rows[0], 6)
+	var statsInfo []string
+	for _, row := range rows {
+		if strings.Contains(row[0].(string), "Scan") {
+			statsInfo = append(statsInfo, row[4].(string))
+		}
+	}
+	require.Len(t, statsInfo, 2)
+	for _, info := range statsInfo {
+		require.True(t, strings.HasPrefix(info, "version:pseudo"), info)
+	}
+
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (3, 3)")
+	tk.MustExec("analyze table t all columns")
+	rows = tk.MustQuery("explain format = 'stats' select * from t use index(ia) where a > 1").Rows()
+	statsInfo = statsInfo[:0]
+	for _, row := range rows {
+		if strings.Contains(row[0].(string), "Scan") {
+			statsInfo = append(statsInfo, row[4].(string))
+		}
+	}
+	require.Len(t, statsInfo, 2)
+	for _, info := range statsInfo {
+		require.False(t, strings.HasPrefix(info, "version:pseudo"), info)
+		require.Contains(t, info, "realtime count:4")
+	}
+	require.Contains(t, strings.Join(statsInfo, ";"), "indexes:[ia:{buckets:")
+
+	// The stats info is shown before the execution info in EXPLAIN ANALYZE.
+	rows = tk.MustQuery("explain analyze format = 'stats' select * from t where b > 1").Rows()
+	require.Len(t, rows[0], 10)
+	for _, row := range rows {
+		if strings.Contains(row[0].(string), "TableFullScan") {
+			require.Contains(t, row[5].(string), "columns:[b:{buckets:")
+		}
+	}
+}
+
 func TestExplainImportFromSelect(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
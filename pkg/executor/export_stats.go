@@ -0,0 +1,290 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	brstorage "github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// exportStatsFileName is the name of the file exported by ADMIN EXPORT STATS if the URI is a directory.
+const exportStatsFileName = "stats"
+
+// exportStatsRow is the flattened stats of a column exported by ADMIN EXPORT STATS. The percentiles are the same as
+// the ones shown by SHOW STATS_DISTRIBUTION, i.e. statsDistributionPercentiles.
+type exportStatsRow struct {
+	DBName        string  `parquet:"name=db_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TableName     string  `parquet:"name=table_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PartitionName string  `parquet:"name=partition_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ColumnName    string  `parquet:"name=column_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	RowCount      int64   `parquet:"name=row_count, type=INT64"`
+	NDV           int64   `parquet:"name=ndv, type=INT64"`
+	NullCount     int64   `parquet:"name=null_count, type=INT64"`
+	NullRatio     float64 `parquet:"name=null_ratio, type=DOUBLE"`
+	Min           string  `parquet:"name=min, type=BYTE_ARRAY, convertedtype=UTF8"`
+	P1            string  `parquet:"name=p1, type=BYTE_ARRAY, convertedtype=UTF8"`
+	P5            string  `parquet:"name=p5, type=BYTE_ARRAY, convertedtype=UTF8"`
+	P10           string  `parquet:"name=p10, type=BYTE_ARRAY, convertedtype=UTF8"`
+	P25           string  `parquet:"name=p25, type=BYTE_ARRAY, convertedtype=UTF8"`
+	P50           string  `parquet:"name=p50, type=BYTE_ARRAY, convertedtype=UTF8"`
+	P75           string  `parquet:"name=p75, type=BYTE_ARRAY, convertedtype=UTF8"`
+	P90           string  `parquet:"name=p90, type=BYTE_ARRAY, convertedtype=UTF8"`
+	P95           string  `parquet:"name=p95, type=BYTE_ARRAY, convertedtype=UTF8"`
+	P99           string  `parquet:"name=p99, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Max           string  `parquet:"name=max, type=BYTE_ARRAY, convertedtype=UTF8"`
+	// TopN is the JSON array of the most frequent values and their counts, sorted by the counts.
+	TopN string `parquet:"name=topn, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// exportStatsHeader is the header of the exported CSV file, which is the same as the columns of the parquet file.
+var exportStatsHeader = []string{
+	"db_name", "table_name", "partition_name", "column_name", "row_count", "ndv", "null_count", "null_ratio",
+	"min", "p1", "p5", "p10", "p25", "p50", "p75", "p90", "p95", "p99", "max", "topn",
+}
+
+// percentiles returns the fields of the percentiles in the order of statsDistributionPercentiles.
+func (r *exportStatsRow) percentiles() []*string {
+	return []*string{&r.P1, &r.P5, &r.P10, &r.P25, &r.P50, &r.P75, &r.P90, &r.P95, &r.P99}
+}
+
+func (r *exportStatsRow) toCSVRecord() []string {
+	record := []string{
+		r.DBName, r.TableName, r.PartitionName, r.ColumnName,
+		strconv.FormatInt(r.RowCount, 10), strconv.FormatInt(r.NDV, 10), strconv.FormatInt(r.NullCount, 10),
+		strconv.FormatFloat(r.NullRatio, 'f', -1, 64), r.Min,
+	}
+	for _, p := range r.percentiles() {
+		record = append(record, *p)
+	}
+	return append(record, r.Max, r.TopN)
+}
+
+// exportStatsTopNItem is an item of the TopN exported by ADMIN EXPORT STATS.
+type exportStatsTopNItem struct {
+	Value string `json:"value"`
+	Count uint64 `json:"count"`
+}
+
+// exportStatsWriter writes the rows of the exported stats in a format.
+type exportStatsWriter interface {
+	write(row *exportStatsRow) error
+	// finish flushes the buffered rows, the underlying file is closed by the caller.
+	finish() error
+}
+
+type csvExportStatsWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvExportStatsWriter) write(row *exportStatsRow) error {
+	return w.w.Write(row.toCSVRecord())
+}
+
+func (w *csvExportStatsWriter) finish() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type parquetExportStatsWriter struct {
+	w *writer.ParquetWriter
+}
+
+func (w *parquetExportStatsWriter) write(row *exportStatsRow) error {
+	return w.w.Write(row)
+}
+
+func (w *parquetExportStatsWriter) finish() error {
+	return w.w.WriteStop()
+}
+
+// externalFileWriter adapts the writer of the file in the external storage to io.Writer.
+type externalFileWriter struct {
+	ctx context.Context
+	w   brstorage.ExternalFileWriter
+}
+
+func (w *externalFileWriter) Write(p []byte) (int, error) {
+	return w.w.Write(w.ctx, p)
+}
+
+func (e *SimpleExec) executeAdminExportStats(ctx context.Context, s *ast.AdminStmt) error {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnStats)
+	u, ok := parseExternalStatsURI(s.ExportURI)
+	if !ok {
+		return errors.Errorf("Export Stats: invalid URI %s", ast.RedactURL(s.ExportURI))
+	}
+	// The URI is treated as a directory unless it points to a file with the extension of the format.
+	ext := "." + strings.ToLower(s.ExportFormat)
+	fileName := exportStatsFileName + ext
+	if strings.HasSuffix(strings.ToLower(u.Path), ext) {
+		fileName = path.Base(u.Path)
+		u.Path = path.Dir(u.Path)
+	}
+	backend, err := brstorage.ParseBackendFromURL(u, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	store, err := brstorage.NewWithDefaultOpt(ctx, backend)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer store.Close()
+	fileWriter, err := store.Create(ctx, fileName, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, err := e.exportStats(ctx, &externalFileWriter{ctx: ctx, w: fileWriter}, s.ExportFormat)
+	if closeErr := fileWriter.Close(ctx); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Annotatef(err, "Export Stats: failed to export the stats to %s", fileName)
+	}
+	e.Ctx().GetSessionVars().StmtCtx.SetAffectedRows(rows)
+	return nil
+}
+
+// exportStats writes the stats of the columns of all the tables and partitions in the cluster, and returns the
+// number of the written rows. The stats are loaded from the storage, since the histograms may not be in the cache.
+func (e *SimpleExec) exportStats(ctx context.Context, w *externalFileWriter, format string) (uint64, error) {
+	var statsWriter exportStatsWriter
+	switch format {
+	case ast.ExportStatsFormatCSV:
+		csvWriter := csv.NewWriter(w)
+		if err := csvWriter.Write(exportStatsHeader); err != nil {
+			return 0, err
+		}
+		statsWriter = &csvExportStatsWriter{w: csvWriter}
+	case ast.ExportStatsFormatParquet:
+		parquetWriter, err := writer.NewParquetWriterFromWriter(w, new(exportStatsRow), 1)
+		if err != nil {
+			return 0, err
+		}
+		statsWriter = &parquetExportStatsWriter{w: parquetWriter}
+	default:
+		return 0, errors.Errorf("unsupported format %s", format)
+	}
+
+	do := domain.GetDomain(e.Ctx())
+	h := do.StatsHandle()
+	is := do.InfoSchema()
+	vars := e.Ctx().GetSessionVars()
+	var rows uint64
+	exportTable := func(dbName string, tblInfo *model.TableInfo, partitionName string, physicalID int64) error {
+		statsTbl, err := h.TableStatsFromStorage(tblInfo, physicalID, true, 0)
+		if err != nil || statsTbl == nil {
+			return err
+		}
+		for _, col := range statsTbl.StableOrderColSlice() {
+			row, err := exportStatsColumnRow(vars, statsTbl, col)
+			if err != nil {
+				return err
+			}
+			row.DBName, row.TableName, row.PartitionName = dbName, tblInfo.Name.O, partitionName
+			if err := statsWriter.write(row); err != nil {
+				return err
+			}
+			rows++
+		}
+		return nil
+	}
+	for _, db := range is.AllSchemaNames() {
+		if util.IsMemDB(db.L) {
+			continue
+		}
+		tables, err := is.SchemaTableInfos(ctx, db)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		for _, tbl := range tables {
+			if err := vars.SQLKiller.HandleSignal(); err != nil {
+				return 0, err
+			}
+			pi := tbl.GetPartitionInfo()
+			partitionName := ""
+			if pi != nil {
+				partitionName = "global"
+			}
+			// The global stats of the partitioned table are skipped if they don't exist.
+			if err := exportTable(db.O, tbl, partitionName, tbl.ID); err != nil {
+				return 0, errors.Trace(err)
+			}
+			if pi == nil {
+				continue
+			}
+			for _, def := range pi.Definitions {
+				if err := exportTable(db.O, tbl, def.Name.O, def.ID); err != nil {
+					return 0, errors.Trace(err)
+				}
+			}
+		}
+	}
+	return rows, statsWriter.finish()
+}
+
+// exportStatsColumnRow flattens the stats of the column, in which the values are decoded to be readable.
+func exportStatsColumnRow(vars *variable.SessionVars, statsTbl *statistics.Table, col *statistics.Column) (*exportStatsRow, error) {
+	row := &exportStatsRow{
+		ColumnName: col.Info.Name.O,
+		RowCount:   statsTbl.RealtimeCount,
+		NDV:        col.Histogram.NDV,
+		NullCount:  col.Histogram.NullCount,
+	}
+	if statsTbl.RealtimeCount > 0 {
+		row.NullRatio = float64(col.Histogram.NullCount) / float64(statsTbl.RealtimeCount)
+	}
+	columnTypes := []byte{col.Histogram.Tp.GetType()}
+	points, err := statsDistributionPoints(vars, 0, &col.Histogram, col.TopN, col.StatsVer, columnTypes)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) > 0 {
+		row.Min, row.Max = points[0].value, points[len(points)-1].value
+		fields := row.percentiles()
+		for i, point := range statsPercentiles(points, statsDistributionPercentiles) {
+			*fields[i] = point.value
+		}
+	}
+	topMetas := statsTopNByCount(col.TopN)
+	items := make([]exportStatsTopNItem, 0, len(topMetas))
+	for _, meta := range topMetas {
+		value, err := statsTopNValue(vars, 0, meta.Encoded, columnTypes)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, exportStatsTopNItem{Value: value, Count: meta.Count})
+	}
+	topN, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	row.TopN = string(topN)
+	return row, nil
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/errno"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
@@ -156,6 +157,31 @@ func TestRecordHistoryStatsMetaAfterAnalyze(t *testing.T) {
 		testkit.Rows("50 20"))
 }
 
+func TestRecordHistoryStatsMetaSources(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_enable_historical_stats = 1")
+	defer tk.MustExec("set global tidb_enable_historical_stats = 0")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, index idx(a, b))")
+	tk.MustExec("analyze table t")
+	tableInfo, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	h := dom.StatsHandle()
+
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("lock stats t")
+	tk.MustExec("insert into t values (4, 4)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("unlock stats t")
+
+	// Every change of the stats meta is recorded with its source.
+	tk.MustQuery(fmt.Sprintf("select source, count from mysql.stats_meta_history where table_id = %d order by version", tableInfo.Meta().ID)).Check(
+		testkit.Rows("analyze 0", "flush stats 3", "lock stats 3", "unlock stats 4"))
+}
+
 func TestGCHistoryStatsAfterDropTable(t *testing.T) {
 	failpoint.Enable("github.com/pingcap/tidb/pkg/domain/sendHistoricalStats", "return(true)")
 	defer failpoint.Disable("github.com/pingcap/tidb/pkg/domain/sendHistoricalStats")
@@ -403,3 +429,95 @@ PARTITION p0 VALUES LESS THAN (6)
 	require.NotNil(t, jt)
 	require.False(t, jt.IsHistoricalStats)
 }
+
+func TestPinHistoricalStats(t *testing.T) {
+	failpoint.Enable("github.com/pingcap/tidb/pkg/domain/sendHistoricalStats", "return(true)")
+	defer failpoint.Disable("github.com/pingcap/tidb/pkg/domain/sendHistoricalStats")
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_enable_historical_stats = 1")
+	defer tk.MustExec("set global tidb_enable_historical_stats = 0")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, index ia(a))")
+	tk.MustExec("insert into t values (1), (2), (3), (4), (5), (6), (7), (8), (9), (10)")
+
+	h := dom.StatsHandle()
+	hsWorker := dom.GetHistoricalStatsWorker()
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tblID := tbl.Meta().ID
+	analyzeAndDump := func() uint64 {
+		tk.MustExec("analyze table t")
+		require.NoError(t, hsWorker.DumpHistoricalStats(tblID, h))
+		rows := tk.MustQuery(fmt.Sprintf("select max(version) from mysql.stats_history where table_id = %d", tblID)).Rows()
+		version, err := strconv.ParseUint(rows[0][0].(string), 10, 64)
+		require.NoError(t, err)
+		return version
+	}
+	estRows := func() string {
+		return tk.MustQuery("explain format = 'brief' select * from t use index() where a > 0").Rows()[0][1].(string)
+	}
+
+	oldVersion := analyzeAndDump()
+	tk.MustExec("insert into t select a + 10 from t")
+	tk.MustExec("insert into t select a + 20 from t")
+	analyzeAndDump()
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	require.Equal(t, "40.00", estRows())
+
+	// The planner uses the pinned historical stats while the latest stats are kept in the stats cache.
+	tk.MustExec(fmt.Sprintf("set @@tidb_pin_stats = 'test.t@%d'", oldVersion))
+	require.Equal(t, "10.00", estRows())
+	tk.MustExec(fmt.Sprintf("set @@tidb_pin_stats = 'other.t@1, TEST.T@%d'", oldVersion))
+	require.Equal(t, "10.00", estRows())
+
+	// Fall back to the latest stats if there is no historical stats before the pinned version.
+	tk.MustExec("set @@tidb_pin_stats = 'test.t@1'")
+	require.Equal(t, "40.00", estRows())
+	require.Len(t, tk.Session().GetSessionVars().StmtCtx.GetWarnings(), 1)
+
+	tk.MustExec("set @@tidb_pin_stats = ''")
+	require.Equal(t, "40.00", estRows())
+
+	tk.MustGetErrCode("set @@tidb_pin_stats = 't@1'", errno.ErrWrongValueForVar)
+	tk.MustGetErrCode("set @@tidb_pin_stats = 'test.t'", errno.ErrWrongValueForVar)
+	tk.MustGetErrCode("set @@tidb_pin_stats = 'test.t@abc'", errno.ErrWrongValueForVar)
+	tk.MustGetErrCode("set global tidb_pin_stats = ''", errno.ErrLocalVariable)
+}
+
+func TestShowHistoricalStatsHistograms(t *testing.T) {
+	failpoint.Enable("github.com/pingcap/tidb/pkg/domain/sendHistoricalStats", "return(true)")
+	defer failpoint.Disable("github.com/pingcap/tidb/pkg/domain/sendHistoricalStats")
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_enable_historical_stats = 1")
+	defer tk.MustExec("set global tidb_enable_historical_stats = 0")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, index ia(a))")
+	tk.MustExec("insert into t values (1), (2), (3), (4), (5)")
+
+	h := dom.StatsHandle()
+	hsWorker := dom.GetHistoricalStatsWorker()
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tblID := tbl.Meta().ID
+	tk.MustExec("analyze table t")
+	require.NoError(t, hsWorker.DumpHistoricalStats(tblID, h))
+	rows := tk.MustQuery(fmt.Sprintf("select max(version) from mysql.stats_history where table_id = %d", tblID)).Rows()
+	oldVersion, err := strconv.ParseUint(rows[0][0].(string), 10, 64)
+	require.NoError(t, err)
+	// The AS OF TIMESTAMP is in milliseconds, so add one millisecond to make sure the version is included.
+	oldTime := oracle.GetTimeFromTS(oldVersion).Add(time.Millisecond).Format("2006-01-02 15:04:05.000")
+
+	time.Sleep(10 * time.Millisecond)
+	tk.MustExec("insert into t values (6), (7), (8), (9), (10)")
+	tk.MustExec("analyze table t")
+	require.NoError(t, hsWorker.DumpHistoricalStats(tblID, h))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+
+	tk.MustQuery("show stats_histograms where table_name = 't' and column_name = 'a'").CheckAt([]int{3, 6}, testkit.Rows("a 10"))
+	tk.MustQuery(fmt.Sprintf("show stats_histograms as of timestamp '%s' where table_name = 't'", oldTime)).
+		CheckAt([]int{3, 4, 6}, testkit.Rows("a 0 5", "ia 1 5"))
+	// Tables without historical stats before the timestamp are not shown.
+	tk.MustQuery("show stats_histograms as of timestamp '2000-01-01 00:00:00' where table_name = 't'").Check(testkit.Rows())
+}
@@ -0,0 +1,496 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/lightning/log"
+	"github.com/pingcap/tidb/pkg/lightning/mydump"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
+)
+
+const (
+	// DatagenPath is the data source path of IMPORT INTO ... FROM DATAGEN(...).
+	DatagenPath = "datagen://"
+	// datagenRowsPerFile is the number of rows of each virtual data file, it's
+	// the unit of the chunks distributed to the subtasks.
+	datagenRowsPerFile = 1_000_000
+	// datagenEstimatedColumnSize is used to estimate the data size of the generated rows.
+	datagenEstimatedColumnSize = 16
+	// datagenDefaultStringLen is the length of the strings generated for columns
+	// without a template.
+	datagenDefaultStringLen = 16
+)
+
+// datagenBaseTime is the start time of the time values generated for columns without a template.
+var datagenBaseTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// DatagenSpec describes the rows generated by IMPORT INTO ... FROM DATAGEN(...).
+type DatagenSpec struct {
+	Rows int64
+	// Templates maps the lower-case column name to its value template.
+	Templates map[string]string
+}
+
+// NewDatagenSpec creates a DatagenSpec from the DATAGEN clause.
+func NewDatagenSpec(spec *ast.DatagenSpec) *DatagenSpec {
+	templates := make(map[string]string, len(spec.Columns))
+	for _, col := range spec.Columns {
+		templates[col.Name.L] = col.Template
+	}
+	return &DatagenSpec{
+		Rows:      int64(spec.Rows),
+		Templates: templates,
+	}
+}
+
+// datagenColumn generates the values of a column, the row is passed in to support
+// templates which refer to the values of other columns.
+type datagenColumn interface {
+	generate(ordinal int64, row []types.Datum) types.Datum
+}
+
+// buildDatagenColumns builds the generators of the input fields according to the
+// templates, the fields without a template are generated according to the column type.
+func (e *LoadDataController) buildDatagenColumns() ([]datagenColumn, error) {
+	if e.Datagen.Rows <= 0 {
+		return nil, exeerrors.ErrLoadDataWrongFormatConfig.GenWithStack("DATAGEN requires a positive ROWS")
+	}
+	fieldOffsets := make(map[string]int, len(e.FieldMappings))
+	for i, field := range e.FieldMappings {
+		if field.Column == nil {
+			return nil, exeerrors.ErrLoadDataWrongFormatConfig.GenWithStack("user variables are not supported by DATAGEN")
+		}
+		fieldOffsets[field.Column.Name.L] = i
+	}
+	for name := range e.Datagen.Templates {
+		if _, ok := fieldOffsets[name]; !ok {
+			return nil, exeerrors.ErrLoadDataWrongFormatConfig.GenWithStack(
+				fmt.Sprintf("DATAGEN column '%s' is not in the field list", name))
+		}
+	}
+	columns := make([]datagenColumn, len(e.FieldMappings))
+	for i, field := range e.FieldMappings {
+		col := field.Column
+		tmpl, ok := e.Datagen.Templates[col.Name.L]
+		if !ok {
+			columns[i] = newDefaultDatagenColumn(col, i)
+			continue
+		}
+		gen, err := parseDatagenTemplate(tmpl, col, i, fieldOffsets)
+		if err != nil {
+			return nil, exeerrors.ErrLoadDataWrongFormatConfig.GenWithStack(
+				fmt.Sprintf("invalid DATAGEN template of column '%s': %s", col.Name.O, err.Error()))
+		}
+		columns[i] = gen
+	}
+	return columns, nil
+}
+
+// parseDatagenTemplate parses templates like `zipf(1.1, 1000)`.
+func parseDatagenTemplate(tmpl string, col *table.Column, offset int, fieldOffsets map[string]int) (datagenColumn, error) {
+	tmpl = strings.TrimSpace(tmpl)
+	name, argStr := tmpl, ""
+	if idx := strings.IndexByte(tmpl, '('); idx >= 0 {
+		if !strings.HasSuffix(tmpl, ")") {
+			return nil, errors.Errorf("unclosed parenthesis in '%s'", tmpl)
+		}
+		name, argStr = strings.TrimSpace(tmpl[:idx]), tmpl[idx+1:len(tmpl)-1]
+	}
+	var args []string
+	if strings.TrimSpace(argStr) != "" {
+		for _, arg := range strings.Split(argStr, ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+	}
+	floatArgs := func(minCnt, maxCnt int) ([]float64, error) {
+		if len(args) < minCnt || len(args) > maxCnt {
+			return nil, errors.Errorf("%s expects %d to %d arguments, but got %d", name, minCnt, maxCnt, len(args))
+		}
+		vals := make([]float64, 0, len(args))
+		for _, arg := range args {
+			v, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid argument '%s' of %s", arg, name)
+			}
+			vals = append(vals, v)
+		}
+		return vals, nil
+	}
+	switch strings.ToLower(name) {
+	case "seq":
+		vals, err := floatArgs(0, 2)
+		if err != nil {
+			return nil, err
+		}
+		gen := &seqDatagenColumn{step: 1}
+		if len(vals) > 0 {
+			gen.start = int64(vals[0])
+		}
+		if len(vals) > 1 {
+			gen.step = int64(vals[1])
+		}
+		return gen, nil
+	case "uniform":
+		vals, err := floatArgs(2, 2)
+		if err != nil {
+			return nil, err
+		}
+		if vals[0] > vals[1] {
+			return nil, errors.Errorf("the min value of uniform is larger than the max value")
+		}
+		return &uniformDatagenColumn{offset: offset, minVal: int64(vals[0]), maxVal: int64(vals[1])}, nil
+	case "zipf":
+		vals, err := floatArgs(2, 2)
+		if err != nil {
+			return nil, err
+		}
+		if vals[0] <= 0 || vals[1] < 1 {
+			return nil, errors.Errorf("zipf requires a positive exponent and at least one distinct value")
+		}
+		return &zipfDatagenColumn{offset: offset, s: vals[0], n: vals[1]}, nil
+	case "normal":
+		vals, err := floatArgs(2, 2)
+		if err != nil {
+			return nil, err
+		}
+		if vals[1] < 0 {
+			return nil, errors.Errorf("the stddev of normal can't be negative")
+		}
+		return &normalDatagenColumn{offset: offset, mean: vals[0], stddev: vals[1]}, nil
+	case "string":
+		vals, err := floatArgs(0, 1)
+		if err != nil {
+			return nil, err
+		}
+		length := datagenDefaultStringLen
+		if len(vals) > 0 {
+			length = int(vals[0])
+		}
+		if length <= 0 {
+			return nil, errors.Errorf("the length of string must be positive")
+		}
+		return &stringDatagenColumn{offset: offset, length: length}, nil
+	case "correlated":
+		if len(args) != 2 {
+			return nil, errors.Errorf("correlated expects 2 arguments, but got %d", len(args))
+		}
+		refName := strings.ToLower(strings.Trim(args[0], "`"))
+		refOffset, ok := fieldOffsets[refName]
+		if !ok {
+			return nil, errors.Errorf("column '%s' referred by correlated is not in the field list", args[0])
+		}
+		if refOffset >= offset {
+			return nil, errors.Errorf("correlated can only refer to a preceding column")
+		}
+		noise, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || noise < 0 || noise > 1 {
+			return nil, errors.Errorf("the noise of correlated must be in [0, 1]")
+		}
+		return &correlatedDatagenColumn{
+			offset:    offset,
+			refOffset: refOffset,
+			noise:     noise,
+			fallback:  newDefaultDatagenColumn(col, offset),
+		}, nil
+	}
+	return nil, errors.Errorf("unknown template '%s'", name)
+}
+
+// newDefaultDatagenColumn returns the generator of the column without a template.
+func newDefaultDatagenColumn(col *table.Column, offset int) datagenColumn {
+	if col.IsGenerated() {
+		return nullDatagenColumn{}
+	}
+	switch col.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear, mysql.TypeBit:
+		// use sequence numbers so the column can be used as the primary key.
+		return &seqDatagenColumn{step: 1}
+	case mysql.TypeFloat, mysql.TypeDouble, mysql.TypeNewDecimal:
+		return &normalDatagenColumn{offset: offset, mean: 0, stddev: 1}
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString, mysql.TypeBlob, mysql.TypeTinyBlob,
+		mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		length := datagenDefaultStringLen
+		if flen := col.GetFlen(); flen > 0 && flen < length {
+			length = flen
+		}
+		return &stringDatagenColumn{offset: offset, length: length}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		return timeDatagenColumn{}
+	case mysql.TypeEnum, mysql.TypeSet:
+		if elems := col.GetElems(); len(elems) > 0 {
+			return &enumDatagenColumn{offset: offset, elems: elems}
+		}
+	}
+	return nullDatagenColumn{}
+}
+
+// datagenHash returns a pseudo random number which is determined by the row ordinal
+// and the column offset, so the generated data is reproducible and the chunks can be
+// generated independently. It's the finalizer of splitmix64.
+func datagenHash(ordinal int64, offset int, salt uint64) uint64 {
+	x := uint64(ordinal)*0x9e3779b97f4a7c15 + uint64(offset+1)*0xbf58476d1ce4e5b9 + salt
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// datagenFloat returns a pseudo random float in (0, 1).
+func datagenFloat(ordinal int64, offset int, salt uint64) float64 {
+	return (float64(datagenHash(ordinal, offset, salt)>>11) + 0.5) / (1 << 53)
+}
+
+type nullDatagenColumn struct{}
+
+func (nullDatagenColumn) generate(int64, []types.Datum) types.Datum {
+	return types.NewDatum(nil)
+}
+
+type seqDatagenColumn struct {
+	start int64
+	step  int64
+}
+
+func (g *seqDatagenColumn) generate(ordinal int64, _ []types.Datum) types.Datum {
+	return types.NewIntDatum(g.start + ordinal*g.step)
+}
+
+type uniformDatagenColumn struct {
+	offset         int
+	minVal, maxVal int64
+}
+
+func (g *uniformDatagenColumn) generate(ordinal int64, _ []types.Datum) types.Datum {
+	span := uint64(g.maxVal-g.minVal) + 1
+	if span == 0 {
+		return types.NewIntDatum(int64(datagenHash(ordinal, g.offset, 0)))
+	}
+	return types.NewIntDatum(g.minVal + int64(datagenHash(ordinal, g.offset, 0)%span))
+}
+
+// zipfDatagenColumn generates the ranks in [1, n] following the zipf distribution
+// with exponent s, the rank 1 is the most frequent one. It uses the inverse of the
+// continuous approximation of the CDF, so it needs no precomputed tables.
+type zipfDatagenColumn struct {
+	offset int
+	s, n   float64
+}
+
+func (g *zipfDatagenColumn) generate(ordinal int64, _ []types.Datum) types.Datum {
+	u := datagenFloat(ordinal, g.offset, 0)
+	var x float64
+	if math.Abs(g.s-1) < 1e-9 {
+		x = math.Pow(g.n+1, u)
+	} else {
+		e := 1 - g.s
+		x = math.Pow(1+u*(math.Pow(g.n+1, e)-1), 1/e)
+	}
+	return types.NewIntDatum(int64(min(max(math.Floor(x), 1), g.n)))
+}
+
+// normalDatagenColumn generates the values following the normal distribution by the Box-Muller transform.
+type normalDatagenColumn struct {
+	offset       int
+	mean, stddev float64
+}
+
+func (g *normalDatagenColumn) generate(ordinal int64, _ []types.Datum) types.Datum {
+	u1 := datagenFloat(ordinal, g.offset, 0)
+	u2 := datagenFloat(ordinal, g.offset, 1)
+	z := math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+	return types.NewFloat64Datum(g.mean + z*g.stddev)
+}
+
+const datagenAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+type stringDatagenColumn struct {
+	offset int
+	length int
+}
+
+func (g *stringDatagenColumn) generate(ordinal int64, _ []types.Datum) types.Datum {
+	buf := make([]byte, g.length)
+	var h uint64
+	for i := range buf {
+		// each hash provides 10 characters.
+		if i%10 == 0 {
+			h = datagenHash(ordinal, g.offset, uint64(i))
+		}
+		buf[i] = datagenAlphabet[h%uint64(len(datagenAlphabet))]
+		h /= uint64(len(datagenAlphabet))
+	}
+	return types.NewStringDatum(string(buf))
+}
+
+type timeDatagenColumn struct{}
+
+func (timeDatagenColumn) generate(ordinal int64, _ []types.Datum) types.Datum {
+	return types.NewStringDatum(datagenBaseTime.Add(time.Duration(ordinal) * time.Second).Format(time.DateTime))
+}
+
+type enumDatagenColumn struct {
+	offset int
+	elems  []string
+}
+
+func (g *enumDatagenColumn) generate(ordinal int64, _ []types.Datum) types.Datum {
+	return types.NewStringDatum(g.elems[datagenHash(ordinal, g.offset, 0)%uint64(len(g.elems))])
+}
+
+// correlatedDatagenColumn copies the value of the referred column, and generates an
+// independent value by the fallback generator with the probability of noise.
+type correlatedDatagenColumn struct {
+	offset    int
+	refOffset int
+	noise     float64
+	fallback  datagenColumn
+}
+
+func (g *correlatedDatagenColumn) generate(ordinal int64, row []types.Datum) types.Datum {
+	if datagenFloat(ordinal, g.offset, 0) < g.noise {
+		return g.fallback.generate(ordinal, row)
+	}
+	var d types.Datum
+	row[g.refOffset].Copy(&d)
+	return d
+}
+
+// initDatagenFiles splits the rows to generate into virtual data files.
+func (e *LoadDataController) initDatagenFiles() {
+	rowSize := int64(len(e.FieldMappings) * datagenEstimatedColumnSize)
+	dataFiles := make([]*mydump.SourceFileMeta, 0, e.Datagen.Rows/datagenRowsPerFile+1)
+	var totalSize int64
+	for start := int64(0); start < e.Datagen.Rows; start += datagenRowsPerFile {
+		rows := min(datagenRowsPerFile, e.Datagen.Rows-start)
+		size := rows * rowSize
+		dataFiles = append(dataFiles, &mydump.SourceFileMeta{
+			Path:     fmt.Sprintf("%s%d", DatagenPath, start),
+			Type:     mydump.SourceTypeDatagen,
+			FileSize: size,
+			RealSize: size,
+			Rows:     rows,
+		})
+		totalSize += size
+	}
+	e.dataFiles = dataFiles
+	e.TotalFileSize = totalSize
+}
+
+// DatagenParser generates the rows of a virtual data file of DATAGEN.
+// It implements the mydump.Parser interface.
+type DatagenParser struct {
+	columns   []datagenColumn
+	names     []string
+	rows      int64
+	readRows  int64
+	lastRow   mydump.Row
+	rowLength int
+	logger    log.Logger
+}
+
+func (e *LoadDataController) newDatagenParser(fileMeta *mydump.SourceFileMeta) (*DatagenParser, error) {
+	columns, err := e.buildDatagenColumns()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(e.FieldMappings))
+	for _, field := range e.FieldMappings {
+		names = append(names, field.Column.Name.L)
+	}
+	return &DatagenParser{
+		columns:   columns,
+		names:     names,
+		rows:      fileMeta.Rows,
+		rowLength: len(columns) * datagenEstimatedColumnSize,
+	}, nil
+}
+
+// Pos implements the mydump.Parser interface, the position is the number of generated rows.
+func (p *DatagenParser) Pos() (pos int64, rowID int64) {
+	return p.readRows, p.lastRow.RowID
+}
+
+// SetPos implements the mydump.Parser interface.
+func (p *DatagenParser) SetPos(pos int64, rowID int64) error {
+	p.readRows = pos
+	p.lastRow.RowID = rowID
+	return nil
+}
+
+// ScannedPos implements the mydump.Parser interface.
+func (p *DatagenParser) ScannedPos() (int64, error) {
+	return p.readRows, nil
+}
+
+// Close implements the mydump.Parser interface.
+func (*DatagenParser) Close() error {
+	return nil
+}
+
+// ReadRow implements the mydump.Parser interface. The rows are generated by their
+// row IDs, which are unique across the virtual data files.
+func (p *DatagenParser) ReadRow() error {
+	if p.readRows >= p.rows {
+		return io.EOF
+	}
+	p.lastRow.RowID++
+	p.readRows++
+	// the row ID starts from 1.
+	ordinal := p.lastRow.RowID - 1
+	row := make([]types.Datum, len(p.columns))
+	for i, col := range p.columns {
+		row[i] = col.generate(ordinal, row)
+	}
+	p.lastRow.Row = row
+	p.lastRow.Length = p.rowLength
+	return nil
+}
+
+// LastRow implements the mydump.Parser interface.
+func (p *DatagenParser) LastRow() mydump.Row {
+	return p.lastRow
+}
+
+// RecycleRow implements the mydump.Parser interface.
+func (*DatagenParser) RecycleRow(mydump.Row) {}
+
+// Columns implements the mydump.Parser interface.
+func (p *DatagenParser) Columns() []string {
+	return p.names
+}
+
+// SetColumns implements the mydump.Parser interface.
+func (*DatagenParser) SetColumns([]string) {}
+
+// SetLogger implements the mydump.Parser interface.
+func (p *DatagenParser) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// SetRowID implements the mydump.Parser interface.
+func (p *DatagenParser) SetRowID(rowID int64) {
+	p.lastRow.RowID = rowID
+}
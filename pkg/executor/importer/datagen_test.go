@@ -0,0 +1,132 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"io"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/lightning/mydump"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/table"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newDatagenTestController(rows int64, templates map[string]string) *LoadDataController {
+	newCol := func(name string, tp byte) *table.Column {
+		ft := types.NewFieldType(tp)
+		ft.SetFlen(32)
+		return table.ToColumn(&model.ColumnInfo{Name: pmodel.NewCIStr(name), FieldType: *ft})
+	}
+	cols := []*table.Column{
+		newCol("id", mysql.TypeLonglong),
+		newCol("city", mysql.TypeLong),
+		newCol("province", mysql.TypeLong),
+		newCol("score", mysql.TypeDouble),
+		newCol("name", mysql.TypeVarchar),
+	}
+	e := &LoadDataController{
+		Plan: &Plan{
+			Format:  DataFormatDatagen,
+			Datagen: &DatagenSpec{Rows: rows, Templates: templates},
+		},
+	}
+	for _, col := range cols {
+		e.FieldMappings = append(e.FieldMappings, &FieldMapping{Column: col})
+	}
+	return e
+}
+
+func TestDatagenTemplates(t *testing.T) {
+	e := newDatagenTestController(1000, map[string]string{
+		"city":     "zipf(1.2, 100)",
+		"province": "correlated(city, 0)",
+		"score":    "normal(60, 10)",
+		"name":     "string(8)",
+	})
+	e.initDatagenFiles()
+	require.Len(t, e.dataFiles, 1)
+	require.Equal(t, int64(1000), e.dataFiles[0].Rows)
+	require.Equal(t, mydump.SourceTypeDatagen, e.dataFiles[0].Type)
+
+	parser, err := e.newDatagenParser(e.dataFiles[0])
+	require.NoError(t, err)
+	require.Equal(t, []string{"id", "city", "province", "score", "name"}, parser.Columns())
+	parser.SetRowID(0)
+	cityCnt := make(map[int64]int)
+	for i := int64(0); i < 1000; i++ {
+		require.NoError(t, parser.ReadRow())
+		row := parser.LastRow()
+		require.Equal(t, i+1, row.RowID)
+		require.Equal(t, i, row.Row[0].GetInt64())
+		city := row.Row[1].GetInt64()
+		require.True(t, city >= 1 && city <= 100)
+		cityCnt[city]++
+		require.Equal(t, city, row.Row[2].GetInt64())
+		require.Len(t, row.Row[4].GetString(), 8)
+	}
+	require.ErrorIs(t, parser.ReadRow(), io.EOF)
+	pos, _ := parser.Pos()
+	require.Equal(t, int64(1000), pos)
+	// the rank 1 is the most frequent one.
+	for city, cnt := range cityCnt {
+		require.LessOrEqual(t, cnt, cityCnt[1], city)
+	}
+
+	// the rows are determined by the row IDs.
+	parser2, err := e.newDatagenParser(e.dataFiles[0])
+	require.NoError(t, err)
+	parser2.SetRowID(10)
+	require.NoError(t, parser2.ReadRow())
+	require.NoError(t, parser.SetPos(0, 10))
+	require.NoError(t, parser.ReadRow())
+	require.Equal(t, parser.LastRow().Row, parser2.LastRow().Row)
+}
+
+func TestDatagenFiles(t *testing.T) {
+	e := newDatagenTestController(2*datagenRowsPerFile+1, nil)
+	e.initDatagenFiles()
+	require.Len(t, e.dataFiles, 3)
+	require.Equal(t, int64(1), e.dataFiles[2].Rows)
+	var totalSize int64
+	for _, f := range e.dataFiles {
+		totalSize += f.FileSize
+	}
+	require.Equal(t, totalSize, e.TotalFileSize)
+}
+
+func TestDatagenInvalidTemplates(t *testing.T) {
+	for tmpl, msg := range map[string]string{
+		"unknown(1)":          "unknown template",
+		"zipf(1.1)":           "zipf expects 2 to 2 arguments",
+		"uniform(10, 1)":      "the min value of uniform is larger than the max value",
+		"correlated(name, 0)": "correlated can only refer to a preceding column",
+		"correlated(x, 0)":    "column 'x' referred by correlated is not in the field list",
+		"normal(0, 1":         "unclosed parenthesis",
+	} {
+		e := newDatagenTestController(10, map[string]string{"city": tmpl})
+		_, err := e.buildDatagenColumns()
+		require.ErrorContains(t, err, msg, tmpl)
+	}
+	e := newDatagenTestController(10, map[string]string{"x": "seq"})
+	_, err := e.buildDatagenColumns()
+	require.ErrorContains(t, err, "DATAGEN column 'x' is not in the field list")
+	e = newDatagenTestController(0, nil)
+	_, err = e.buildDatagenColumns()
+	require.ErrorContains(t, err, "DATAGEN requires a positive ROWS")
+}
@@ -117,5 +117,6 @@ func ProcessChunkWithWriter(
 		return err
 	}
 	progress.AddColSize(encoder.GetColumnSize())
+	progress.AddStats(encoder.GetStatsCollector())
 	return nil
 }
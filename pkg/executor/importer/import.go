@@ -74,6 +74,8 @@ const (
 	DataFormatSQL = "sql"
 	// DataFormatParquet represents the data source file of IMPORT INTO is parquet.
 	DataFormatParquet = "parquet"
+	// DataFormatDatagen represents the data of IMPORT INTO is generated by DATAGEN(...).
+	DataFormatDatagen = "datagen"
 
 	// DefaultDiskQuota is the default disk quota for IMPORT INTO
 	DefaultDiskQuota = config.ByteSize(50 << 30) // 50GiB
@@ -204,6 +206,8 @@ type Plan struct {
 	Path string
 	// only effective when data source is file.
 	Format string
+	// Datagen is not nil when the data is generated by DATAGEN(...).
+	Datagen *DatagenSpec
 	// Data interpretation is restrictive if the SQL mode is restrictive and neither
 	// the IGNORE nor the LOCAL modifier is specified. Errors terminate the load
 	// operation.
@@ -389,6 +393,12 @@ func NewImportPlan(ctx context.Context, userSctx sessionctx.Context, plan *plann
 		// without FORMAT 'xxx' clause, default to CSV
 		format = DataFormatCSV
 	}
+	path := plan.Path
+	var datagen *DatagenSpec
+	if plan.Datagen != nil {
+		format, path = DataFormatDatagen, DatagenPath
+		datagen = NewDatagenSpec(plan.Datagen)
+	}
 	restrictive := userSctx.GetSessionVars().SQLMode.HasStrictMode()
 	// those are the default values for lightning CSV format too
 	lineFieldsInfo := plannercore.LineFieldsInfo{
@@ -407,8 +417,9 @@ func NewImportPlan(ctx context.Context, userSctx sessionctx.Context, plan *plann
 		DBName:           plan.Table.Schema.L,
 		DBID:             plan.Table.DBInfo.ID,
 
-		Path:           plan.Path,
+		Path:           path,
 		Format:         format,
+		Datagen:        datagen,
 		Restrictive:    restrictive,
 		FieldNullDef:   []string{`\N`},
 		LineFieldsInfo: lineFieldsInfo,
@@ -491,6 +502,12 @@ func NewLoadDataController(plan *Plan, tbl table.Table, astArgs *ASTArgs) (*Load
 	if err := c.initLoadColumns(columnNames); err != nil {
 		return nil, err
 	}
+	if c.Datagen != nil {
+		// check the templates in advance.
+		if _, err := c.buildDatagenColumns(); err != nil {
+			return nil, err
+		}
+	}
 	return c, nil
 }
 
@@ -509,7 +526,8 @@ func (e *LoadDataController) checkFieldParams() error {
 		return exeerrors.ErrLoadDataEmptyPath
 	}
 	if e.InImportInto {
-		if e.Format != DataFormatCSV && e.Format != DataFormatParquet && e.Format != DataFormatSQL {
+		if e.Format != DataFormatCSV && e.Format != DataFormatParquet && e.Format != DataFormatSQL &&
+			e.Format != DataFormatDatagen {
 			return exeerrors.ErrLoadDataUnsupportedFormat.GenWithStackByArgs(e.Format)
 		}
 	} else {
@@ -972,22 +990,25 @@ func (e *LoadDataController) GenerateCSVConfig() *config.CSVConfig {
 
 // InitDataStore initializes the data store.
 func (e *LoadDataController) InitDataStore(ctx context.Context) error {
-	u, err2 := storage.ParseRawURL(e.Path)
-	if err2 != nil {
-		return exeerrors.ErrLoadDataInvalidURI.GenWithStackByArgs(plannercore.ImportIntoDataSource,
-			err2.Error())
-	}
+	// there is no data store for the generated data.
+	if e.Datagen == nil {
+		u, err2 := storage.ParseRawURL(e.Path)
+		if err2 != nil {
+			return exeerrors.ErrLoadDataInvalidURI.GenWithStackByArgs(plannercore.ImportIntoDataSource,
+				err2.Error())
+		}
 
-	if storage.IsLocal(u) {
-		u.Path = filepath.Dir(e.Path)
-	} else {
-		u.Path = ""
-	}
-	s, err := e.initExternalStore(ctx, u, plannercore.ImportIntoDataSource)
-	if err != nil {
-		return err
+		if storage.IsLocal(u) {
+			u.Path = filepath.Dir(e.Path)
+		} else {
+			u.Path = ""
+		}
+		s, err := e.initExternalStore(ctx, u, plannercore.ImportIntoDataSource)
+		if err != nil {
+			return err
+		}
+		e.dataStore = s
 	}
-	e.dataStore = s
 
 	if e.IsGlobalSort() {
 		target := "cloud storage"
@@ -996,7 +1017,7 @@ func (e *LoadDataController) InitDataStore(ctx context.Context) error {
 			return exeerrors.ErrLoadDataInvalidURI.GenWithStackByArgs(target,
 				err3.Error())
 		}
-		s, err = e.initExternalStore(ctx, cloudStorageURL, target)
+		s, err := e.initExternalStore(ctx, cloudStorageURL, target)
 		if err != nil {
 			return err
 		}
@@ -1020,6 +1041,13 @@ func (*LoadDataController) initExternalStore(ctx context.Context, u *url.URL, ta
 // InitDataFiles initializes the data store and files.
 // it will call InitDataStore internally.
 func (e *LoadDataController) InitDataFiles(ctx context.Context) error {
+	if e.Datagen != nil {
+		if err := e.InitDataStore(ctx); err != nil {
+			return err
+		}
+		e.initDatagenFiles()
+		return nil
+	}
 	u, err2 := storage.ParseRawURL(e.Path)
 	if err2 != nil {
 		return exeerrors.ErrLoadDataInvalidURI.GenWithStackByArgs(plannercore.ImportIntoDataSource,
@@ -1155,6 +1183,8 @@ func (e *LoadDataController) getSourceType() mydump.SourceType {
 		return mydump.SourceTypeParquet
 	case DataFormatDelimitedData, DataFormatCSV:
 		return mydump.SourceTypeCSV
+	case DataFormatDatagen:
+		return mydump.SourceTypeDatagen
 	default:
 		// DataFormatSQL
 		return mydump.SourceTypeSQL
@@ -1398,6 +1428,8 @@ type JobImportResult struct {
 	Affected   uint64
 	Warnings   []contextutil.SQLWarn
 	ColSizeMap variable.DeltaColsMap
+	// StatsSamples is the samples of the imported rows, the stats of the table are built from them if it's not nil.
+	StatsSamples *TableStatsSamples
 }
 
 // GetMsgFromBRError get msg from BR error.
@@ -357,6 +357,67 @@ func TestProcessChunkWith(t *testing.T) {
 	})
 }
 
+func TestImportTableStats(t *testing.T) {
+	ctx := context.Background()
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b varchar(10), index idx(b))")
+	do, err := session.GetDomain(store)
+	require.NoError(t, err)
+	tbl, err := do.InfoSchema().TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+
+	keyspace := store.GetCodec().GetKeyspace()
+	chunkInfo := &checkpoints.ChunkCheckpoint{}
+	ti := getTableImporter(ctx, t, store, "t", "", nil)
+	defer ti.Backend().CloseEngineMgr()
+	kvWriter := mock.NewMockEngineWriter(ctrl)
+	kvWriter.EXPECT().AppendRows(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	// The rows are encoded by 2 subtasks, whose samples are merged.
+	values := []types.Datum{
+		types.NewStringDatum("x"), types.NewStringDatum("y"), types.NewStringDatum("x"),
+		types.NewStringDatum("y"), types.NewStringDatum("z"), types.NewDatum(nil),
+	}
+	allSamples := make([]*importer.TableStatsSamples, 0, 2)
+	for i := 0; i < 2; i++ {
+		rowsCh := make(chan importer.QueryRow, 3)
+		for j := 0; j < 3; j++ {
+			id := int64(i*3 + j + 1)
+			rowsCh <- importer.QueryRow{ID: id, Data: []types.Datum{types.NewIntDatum(id), values[id-1]}}
+		}
+		close(rowsCh)
+		ti.SetSelectedRowCh(rowsCh)
+		progress := importer.NewProgress()
+		checksum := verify.NewKVGroupChecksumWithKeyspace(keyspace)
+		require.NoError(t, importer.ProcessChunkWithWriter(ctx, chunkInfo, ti, kvWriter, kvWriter, progress, zap.NewExample(), checksum))
+		samples, err := progress.GetStatsSamples()
+		require.NoError(t, err)
+		require.NotNil(t, samples)
+		require.Equal(t, int64(3), samples.RowCount)
+		allSamples = append(allSamples, samples)
+	}
+	samples, err := importer.MergeTableStatsSamples(allSamples)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), samples.RowCount)
+	// The stats are left to the auto analyze if the samples of any subtask are missing.
+	missing, err := importer.MergeTableStatsSamples([]*importer.TableStatsSamples{allSamples[0], nil})
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	require.NoError(t, importer.FlushTableStats(ctx, tk.Session(), tbl.Meta(), &importer.JobImportResult{
+		Affected:     6,
+		StatsSamples: samples,
+	}, zap.NewExample()))
+	tk.MustQuery(fmt.Sprintf("select count, modify_count from mysql.stats_meta where table_id = %d", tbl.Meta().ID)).
+		Check(testkit.Rows("6 0"))
+	tk.MustQuery(fmt.Sprintf("select is_index, hist_id, distinct_count, null_count from mysql.stats_histograms where table_id = %d order by is_index, hist_id",
+		tbl.Meta().ID)).Check(testkit.Rows("0 1 6 0", "0 2 3 1", "1 1 3 1"))
+}
+
 func TestPopulateChunks(t *testing.T) {
 	ctx := context.Background()
 	store := testkit.CreateMockStore(t)
@@ -37,6 +37,9 @@ type KVEncoder interface {
 	Encode(row []types.Datum, rowID int64) (*kv.Pairs, error)
 	// GetColumnSize returns the size of each column in the current encoder.
 	GetColumnSize() map[int64]int64
+	// GetStatsCollector returns the samples of the rows encoded by the current encoder, it's nil if the stats of
+	// the table can't be built from the import.
+	GetStatsCollector() *TableStatsCollector
 	io.Closer
 }
 
@@ -48,6 +51,7 @@ type tableKVEncoder struct {
 	columnsAndUserVars []*ast.ColumnNameOrUserVar
 	fieldMappings      []*FieldMapping
 	insertColumns      []*table.Column
+	stats              *TableStatsCollector
 }
 
 var _ KVEncoder = &tableKVEncoder{}
@@ -73,6 +77,8 @@ func NewTableKVEncoder(
 		columnsAndUserVars: ti.ColumnsAndUserVars,
 		fieldMappings:      ti.FieldMappings,
 		insertColumns:      ti.InsertColumns,
+		stats: newTableStatsCollector(baseKVEncoder.TableMeta(),
+			baseKVEncoder.SessionCtx.GetExprCtx().GetEvalCtx().Location()),
 	}, nil
 }
 
@@ -87,6 +93,11 @@ func (en *tableKVEncoder) Encode(row []types.Datum, rowID int64) (*kv.Pairs, err
 	if err != nil {
 		return nil, err
 	}
+	if en.stats != nil {
+		if err = en.stats.collect(record); err != nil {
+			return nil, err
+		}
+	}
 
 	return en.Record2KV(record, row, rowID)
 }
@@ -95,6 +106,10 @@ func (en *tableKVEncoder) GetColumnSize() map[int64]int64 {
 	return en.SessionCtx.GetColumnSize(en.TableMeta().ID)
 }
 
+func (en *tableKVEncoder) GetStatsCollector() *TableStatsCollector {
+	return en.stats
+}
+
 // todo merge with code in load_data.go
 func (en *tableKVEncoder) parserData2TableData(parserData []types.Datum, rowID int64) ([]types.Datum, error) {
 	row := make([]types.Datum, 0, len(en.insertColumns))
@@ -23,6 +23,11 @@ import (
 type Progress struct {
 	colSizeMu  sync.Mutex
 	colSizeMap map[int64]int64
+
+	statsMu sync.Mutex
+	stats   *TableStatsCollector
+	// statsMissing is set if any chunk is encoded without collecting the stats.
+	statsMissing bool
 }
 
 // NewProgress creates a new Progress.
@@ -47,3 +52,29 @@ func (p *Progress) GetColSize() map[int64]int64 {
 	defer p.colSizeMu.Unlock()
 	return maps.Clone(p.colSizeMap)
 }
+
+// AddStats merges the samples collected by the encoder of a chunk.
+func (p *Progress) AddStats(stats *TableStatsCollector) {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	if stats == nil {
+		p.statsMissing = true
+		return
+	}
+	if p.stats == nil {
+		p.stats = stats
+		return
+	}
+	p.stats.merge(stats)
+}
+
+// GetStatsSamples returns the encoded samples of all the chunks. It returns nil if the stats of the table can't
+// be built from the samples.
+func (p *Progress) GetStatsSamples() (*TableStatsSamples, error) {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	if p.stats == nil || p.statsMissing {
+		return nil, nil
+	}
+	return p.stats.toSamples()
+}
@@ -47,6 +47,7 @@ import (
 	"github.com/pingcap/tidb/pkg/lightning/mydump"
 	verify "github.com/pingcap/tidb/pkg/lightning/verification"
 	"github.com/pingcap/tidb/pkg/meta/autoid"
+	"github.com/pingcap/tidb/pkg/meta/model"
 	tidbmetrics "github.com/pingcap/tidb/pkg/metrics"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
@@ -274,6 +275,15 @@ func (ti *TableImporter) GetKeySpace() []byte {
 }
 
 func (ti *TableImporter) getParser(ctx context.Context, chunk *checkpoints.ChunkCheckpoint) (mydump.Parser, error) {
+	if ti.Datagen != nil {
+		parser, err := ti.LoadDataController.newDatagenParser(&chunk.FileMeta)
+		if err != nil {
+			return nil, err
+		}
+		parser.SetLogger(log.Logger{Logger: ti.logger})
+		parser.SetRowID(chunk.Chunk.PrevRowIDMax)
+		return parser, nil
+	}
 	info := LoadDataReaderInfo{
 		Opener: func(ctx context.Context) (io.ReadSeekCloser, error) {
 			reader, err := mydump.OpenReader(ctx, &chunk.FileMeta, ti.dataStore, storage.DecompressConfig{
@@ -654,23 +664,20 @@ func (ti *TableImporter) ImportSelectedRows(ctx context.Context, se sessionctx.C
 	}
 
 	var (
-		mu         sync.Mutex
-		checksum   = verify.NewKVGroupChecksumWithKeyspace(ti.keyspace)
-		colSizeMap = make(map[int64]int64)
+		mu       sync.Mutex
+		checksum = verify.NewKVGroupChecksumWithKeyspace(ti.keyspace)
+		// progress is shared by all the threads, it's thread-safe.
+		progress = NewProgress()
 	)
 	eg, egCtx := tidbutil.NewErrorGroupWithRecoverWithCtx(ctx)
 	for i := 0; i < ti.ThreadCnt; i++ {
 		eg.Go(func() error {
 			chunkCheckpoint := checkpoints.ChunkCheckpoint{}
 			chunkChecksum := verify.NewKVGroupChecksumWithKeyspace(ti.keyspace)
-			progress := NewProgress()
 			defer func() {
 				mu.Lock()
 				defer mu.Unlock()
 				checksum.Add(chunkChecksum)
-				for k, v := range progress.GetColSize() {
-					colSizeMap[k] += v
-				}
 			}()
 			return ProcessChunk(egCtx, &chunkCheckpoint, ti, dataEngine, indexEngine, progress, ti.logger, chunkChecksum)
 		})
@@ -715,9 +722,14 @@ func (ti *TableImporter) ImportSelectedRows(ctx context.Context, se sessionctx.C
 		return nil, err
 	}
 
+	statsSamples, err := progress.GetStatsSamples()
+	if err != nil {
+		return nil, err
+	}
 	return &JobImportResult{
-		Affected:   uint64(dataKVCount),
-		ColSizeMap: colSizeMap,
+		Affected:     uint64(dataKVCount),
+		ColSizeMap:   progress.GetColSize(),
+		StatsSamples: statsSamples,
 	}, nil
 }
 
@@ -965,11 +977,22 @@ func GetImportRootDir(tidbCfg *tidb.Config) string {
 }
 
 // FlushTableStats flushes the stats of the table.
-// stats will be flushed in domain.updateStatsWorker, default interval is [1, 2) minutes,
+// if the samples of the imported rows are collected, the stats of the table are built from them
+// and saved directly, so the table doesn't need to be analyzed.
+// otherwise, stats will be flushed in domain.updateStatsWorker, default interval is [1, 2) minutes,
 // see DumpStatsDeltaToKV for more details. then the background analyzer will analyze
 // the table.
 // the stats stay in memory until the next flush, so it might be lost if the tidb-server restarts.
-func FlushTableStats(ctx context.Context, se sessionctx.Context, tableID int64, result *JobImportResult) error {
+func FlushTableStats(ctx context.Context, se sessionctx.Context, tblInfo *model.TableInfo, result *JobImportResult, logger *zap.Logger) error {
+	saved, err := SaveTableStats(se, tblInfo, result.StatsSamples)
+	if err != nil {
+		logger.Warn("save the stats built by the import failed, leave them to auto analyze", zap.Error(err))
+	}
+	if saved {
+		logger.Info("stats built by the import are saved", zap.Int64("row-count", result.StatsSamples.RowCount))
+		return nil
+	}
+	tableID := tblInfo.ID
 	if err := sessiontxn.NewTxn(ctx, se); err != nil {
 		return err
 	}
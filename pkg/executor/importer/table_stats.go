@@ -0,0 +1,338 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
+	handleutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/collate"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+const (
+	// importStatsSampleSize is the number of the samples of each column and index, which is the same as the
+	// default sample size of ANALYZE of version 1.
+	importStatsSampleSize = 10000
+	// importStatsNumBuckets and importStatsNumTopN are the same as the defaults of ANALYZE of version 2.
+	importStatsNumBuckets = 256
+	importStatsNumTopN    = 100
+	// maxTableStatsSamplesSize is the max size of the encoded samples, which are saved in the meta of the
+	// subtasks and the task. The stats are left to the auto analyze if the samples are larger.
+	maxTableStatsSamplesSize = 2 * 1024 * 1024
+)
+
+// TableStatsCollector samples the rows encoded by IMPORT INTO. The target table is empty before the import, so the
+// stats of the table are built from the samples when the import finishes, and the table doesn't need to be
+// analyzed again. It's not thread-safe, each encoder has its own collector.
+type TableStatsCollector struct {
+	tblInfo *model.TableInfo
+	sc      *stmtctx.StatementContext
+	// loc is the time zone in which the rows are encoded.
+	loc      *time.Location
+	rowCount int64
+	columns  map[int64]*statistics.SampleCollector
+	// collators are the collators of the string columns, the samples of which are their collation keys.
+	collators map[int64]collate.Collator
+	indexes   map[int64]*statistics.SampleCollector
+}
+
+// newTableStatsCollector returns nil if the stats of the table can't be built from the import, e.g. the stats of
+// the partitioned table need to be merged from all the partitions.
+func newTableStatsCollector(tblInfo *model.TableInfo, loc *time.Location) *TableStatsCollector {
+	if tblInfo.Partition != nil {
+		return nil
+	}
+	c := &TableStatsCollector{
+		tblInfo:   tblInfo,
+		sc:        stmtctx.NewStmtCtx(),
+		loc:       loc,
+		columns:   make(map[int64]*statistics.SampleCollector, len(tblInfo.Columns)),
+		collators: make(map[int64]collate.Collator),
+		indexes:   make(map[int64]*statistics.SampleCollector, len(tblInfo.Indices)),
+	}
+	for _, col := range tblInfo.Columns {
+		// The virtual generated columns are not analyzed.
+		if col.State != model.StatePublic || col.IsVirtualGenerated() {
+			continue
+		}
+		c.columns[col.ID] = newImportSampleCollector()
+		ft := col.FieldType
+		if ft.EvalType() == types.ETString && ft.GetType() != mysql.TypeEnum && ft.GetType() != mysql.TypeSet {
+			c.collators[col.ID] = collate.GetCollator(ft.GetCollate())
+		}
+	}
+	for _, idxInfo := range tblInfo.Indices {
+		// The multi-valued index may have more keys than the rows.
+		if idxInfo.MVIndex {
+			return nil
+		}
+		if idxInfo.State != model.StatePublic {
+			continue
+		}
+		c.indexes[idxInfo.ID] = newImportSampleCollector()
+	}
+	return c
+}
+
+func newImportSampleCollector() *statistics.SampleCollector {
+	return &statistics.SampleCollector{
+		FMSketch:      statistics.NewFMSketch(statistics.MaxSketchSize),
+		MaxSampleSize: importStatsSampleSize,
+	}
+}
+
+// collect samples the record of a row, whose datums are in the order of the columns of the table.
+func (c *TableStatsCollector) collect(record []types.Datum) error {
+	c.rowCount++
+	for _, col := range c.tblInfo.Columns {
+		collector, ok := c.columns[col.ID]
+		if !ok {
+			continue
+		}
+		var d types.Datum
+		if val := record[col.Offset]; !val.IsNull() {
+			if collator, ok := c.collators[col.ID]; ok {
+				// Only the collation key ensures the correct ordering, which is the same as ANALYZE.
+				val.SetBytesAsString(collator.Key(val.GetString()), val.Collation(), uint32(val.Length()))
+			}
+			b, err := tablecodec.EncodeValue(c.loc, nil, val)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			d = types.NewBytesDatum(b)
+		}
+		if err := collector.Collect(c.sc, d); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, idxInfo := range c.tblInfo.Indices {
+		collector, ok := c.indexes[idxInfo.ID]
+		if !ok {
+			continue
+		}
+		var d types.Datum
+		if len(idxInfo.Columns) > 1 || !record[idxInfo.Columns[0].Offset].IsNull() {
+			var (
+				b   []byte
+				tmp types.Datum
+				err error
+			)
+			for _, idxCol := range idxInfo.Columns {
+				record[idxCol.Offset].Copy(&tmp)
+				tablecodec.TruncateIndexValue(&tmp, idxCol, c.tblInfo.Columns[idxCol.Offset])
+				b, err = codec.EncodeKey(c.loc, b, tmp)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+			d = types.NewBytesDatum(b)
+		}
+		if err := collector.Collect(c.sc, d); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// merge merges the samples of another collector of the same table.
+func (c *TableStatsCollector) merge(other *TableStatsCollector) {
+	c.rowCount += other.rowCount
+	mergeSampleCollectors(c.sc, c.columns, other.columns)
+	mergeSampleCollectors(c.sc, c.indexes, other.indexes)
+}
+
+func mergeSampleCollectors(sc *stmtctx.StatementContext, collectors, others map[int64]*statistics.SampleCollector) {
+	for id, other := range others {
+		collector, ok := collectors[id]
+		if !ok {
+			collectors[id] = other
+			continue
+		}
+		// The samples of the other collector have been counted by it.
+		collector.IsMerger = true
+		collector.MergeSampleCollector(sc, other)
+	}
+}
+
+// TableStatsSamples is the encoded samples of the rows imported by IMPORT INTO, which are kept in the meta of the
+// subtasks and the task until the import finishes.
+type TableStatsSamples struct {
+	RowCount int64
+	// Columns and Indexes map the IDs of the columns and indexes to their samples, which are encoded from
+	// tipb.SampleCollector.
+	Columns map[int64][]byte
+	Indexes map[int64][]byte
+}
+
+// toSamples encodes the samples. It returns nil if the samples are too large to be kept in the meta.
+func (c *TableStatsCollector) toSamples() (*TableStatsSamples, error) {
+	samples := &TableStatsSamples{
+		RowCount: c.rowCount,
+		Columns:  make(map[int64][]byte, len(c.columns)),
+		Indexes:  make(map[int64][]byte, len(c.indexes)),
+	}
+	size := 0
+	encode := func(collectors map[int64]*statistics.SampleCollector, encoded map[int64][]byte) error {
+		for id, collector := range collectors {
+			b, err := statistics.SampleCollectorToProto(collector).Marshal()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			encoded[id] = b
+			size += len(b)
+		}
+		return nil
+	}
+	if err := encode(c.columns, samples.Columns); err != nil {
+		return nil, err
+	}
+	if err := encode(c.indexes, samples.Indexes); err != nil {
+		return nil, err
+	}
+	if size > maxTableStatsSamplesSize {
+		return nil, nil
+	}
+	return samples, nil
+}
+
+func decodeSampleCollectors(encoded map[int64][]byte) (map[int64]*statistics.SampleCollector, error) {
+	collectors := make(map[int64]*statistics.SampleCollector, len(encoded))
+	for id, b := range encoded {
+		pb := &tipb.SampleCollector{}
+		if err := pb.Unmarshal(b); err != nil {
+			return nil, errors.Trace(err)
+		}
+		collector := statistics.SampleCollectorFromProto(pb)
+		collector.MaxSampleSize = importStatsSampleSize
+		collectors[id] = collector
+	}
+	return collectors, nil
+}
+
+// MergeTableStatsSamples merges the samples collected by the subtasks. It returns nil if the samples of any
+// subtask are missing, so the stats are left to the auto analyze.
+func MergeTableStatsSamples(all []*TableStatsSamples) (*TableStatsSamples, error) {
+	if len(all) == 0 {
+		return nil, nil
+	}
+	merged := &TableStatsCollector{
+		sc:      stmtctx.NewStmtCtx(),
+		columns: make(map[int64]*statistics.SampleCollector),
+		indexes: make(map[int64]*statistics.SampleCollector),
+	}
+	for _, samples := range all {
+		if samples == nil {
+			return nil, nil
+		}
+		columns, err := decodeSampleCollectors(samples.Columns)
+		if err != nil {
+			return nil, err
+		}
+		indexes, err := decodeSampleCollectors(samples.Indexes)
+		if err != nil {
+			return nil, err
+		}
+		merged.merge(&TableStatsCollector{rowCount: samples.RowCount, columns: columns, indexes: indexes})
+	}
+	return merged.toSamples()
+}
+
+// SaveTableStats builds the stats of the table from the samples collected by the import, and saves them. It
+// returns false if the stats aren't saved, then the stats are left to the auto analyze.
+func SaveTableStats(se sessionctx.Context, tblInfo *model.TableInfo, samples *TableStatsSamples) (bool, error) {
+	if samples == nil || tblInfo.Partition != nil || se.GetSessionVars().AnalyzeVersion != statistics.Version2 {
+		return false, nil
+	}
+	columns, err := decodeSampleCollectors(samples.Columns)
+	if err != nil {
+		return false, err
+	}
+	indexes, err := decodeSampleCollectors(samples.Indexes)
+	if err != nil {
+		return false, err
+	}
+	results := &statistics.AnalyzeResults{
+		TableID:  statistics.AnalyzeTableID{TableID: tblInfo.ID, PartitionID: statistics.NonPartitionTableID},
+		Count:    samples.RowCount,
+		StatsVer: statistics.Version2,
+	}
+	colResult := &statistics.AnalyzeResult{}
+	for _, col := range tblInfo.Columns {
+		collector, ok := columns[col.ID]
+		if !ok {
+			continue
+		}
+		// The samples are decoded in the same way as the samples read by ANALYZE.
+		items := make([]*statistics.SampleItem, 0, len(collector.Samples))
+		for _, item := range collector.Samples {
+			d, err := tablecodec.DecodeColumnValue(item.Value.GetBytes(), &col.FieldType, se.GetSessionVars().StmtCtx.TimeZone())
+			if err != nil {
+				return false, errors.Trace(err)
+			}
+			items = append(items, &statistics.SampleItem{Value: d})
+		}
+		collector.Samples = items
+		hist, topn, err := statistics.BuildHistAndTopN(se, importStatsNumBuckets, importStatsNumTopN,
+			col.ID, collector, &col.FieldType, true, nil, false)
+		if err != nil {
+			return false, err
+		}
+		colResult.Hist = append(colResult.Hist, hist)
+		colResult.Cms = append(colResult.Cms, nil)
+		colResult.TopNs = append(colResult.TopNs, topn)
+		colResult.Fms = append(colResult.Fms, collector.FMSketch)
+	}
+	idxResult := &statistics.AnalyzeResult{IsIndex: 1}
+	for _, idxInfo := range tblInfo.Indices {
+		collector, ok := indexes[idxInfo.ID]
+		if !ok {
+			continue
+		}
+		hist, topn, err := statistics.BuildHistAndTopN(se, importStatsNumBuckets, importStatsNumTopN,
+			idxInfo.ID, collector, types.NewFieldType(mysql.TypeBlob), false, nil, false)
+		if err != nil {
+			return false, err
+		}
+		idxResult.Hist = append(idxResult.Hist, hist)
+		idxResult.Cms = append(idxResult.Cms, nil)
+		idxResult.TopNs = append(idxResult.TopNs, topn)
+		idxResult.Fms = append(idxResult.Fms, collector.FMSketch)
+	}
+	results.Ars = []*statistics.AnalyzeResult{colResult, idxResult}
+	err = handleutil.WrapTxn(se, func(sctx sessionctx.Context) error {
+		startTS, err := handleutil.GetStartTS(sctx)
+		if err != nil {
+			return err
+		}
+		results.Snapshot = startTS
+		_, err = storage.SaveTableStatsToStorage(sctx, results, false)
+		return err
+	})
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/deadlock"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
+	"github.com/pingcap/tidb/pkg/config"
 	"github.com/pingcap/tidb/pkg/ddl/label"
 	"github.com/pingcap/tidb/pkg/ddl/placement"
 	"github.com/pingcap/tidb/pkg/domain"
@@ -82,6 +83,8 @@ import (
 	"github.com/pingcap/tidb/pkg/util/set"
 	"github.com/pingcap/tidb/pkg/util/stringutil"
 	"github.com/pingcap/tidb/pkg/util/syncutil"
+	"github.com/pingcap/tidb/pkg/util/topsql"
+	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 	"github.com/tikv/client-go/v2/tikvrpc"
 	"github.com/tikv/client-go/v2/txnkv/txnlock"
@@ -210,6 +213,20 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataFromIndexUsage(ctx, sctx)
 		case infoschema.ClusterTableTiDBIndexUsage:
 			err = e.setDataFromClusterIndexUsage(ctx, sctx)
+		case infoschema.TableTiDBTopSQLCPUUsage:
+			err = e.setDataForTopSQLCPUUsage(sctx)
+		case infoschema.ClusterTableTiDBTopSQLCPUUsage:
+			err = e.setDataForClusterTopSQLCPUUsage(sctx)
+		case infoschema.TableTiDBStatsHealth:
+			err = e.setDataForStatsHealth(ctx, sctx)
+		case infoschema.TableColumnStatistics:
+			err = e.setDataForColumnStatistics(ctx, sctx)
+		case infoschema.TableObjectDependencies:
+			err = e.setDataForObjectDependencies(ctx, sctx)
+		case infoschema.TableTiDBLongTxnEvents:
+			e.setDataForLongTxnEvents(sctx)
+		case infoschema.TableTiDBPartitionStatsRollup:
+			err = e.setDataForPartitionStatsRollup(ctx, sctx)
 		}
 		if err != nil {
 			return nil, err
@@ -2391,13 +2408,22 @@ func (e *tableStorageStatsRetriever) setDataForTableStorageStats(ctx context.Con
 	return rows, nil
 }
 
-// dataForAnalyzeStatusHelper is a helper function which can be used in show_stats.go
-func dataForAnalyzeStatusHelper(ctx context.Context, sctx sessionctx.Context) (rows [][]types.Datum, err error) {
+// dataForAnalyzeStatusHelper is a helper function which can be used in show_stats.go.
+// The jobs of all the TiDB instances are recorded in mysql.analyze_jobs, only the latest maxAnalyzeJobs of them are
+// returned unless allJobs is set, which is used when the jobs are filtered or limited by the user.
+func dataForAnalyzeStatusHelper(ctx context.Context, sctx sessionctx.Context, allJobs bool) (rows [][]types.Datum, err error) {
 	const maxAnalyzeJobs = 30
-	const sql = "SELECT table_schema, table_name, partition_name, job_info, processed_rows, CONVERT_TZ(start_time, @@TIME_ZONE, '+00:00'), CONVERT_TZ(end_time, @@TIME_ZONE, '+00:00'), state, fail_reason, instance, process_id FROM mysql.analyze_jobs ORDER BY update_time DESC LIMIT %?"
+	// The running jobs are listed first, so the progress of the partitions being analyzed is shown even if there are
+	// many partitions finished or pending.
+	sql := "SELECT table_schema, table_name, partition_name, job_info, processed_rows, CONVERT_TZ(start_time, @@TIME_ZONE, '+00:00'), CONVERT_TZ(end_time, @@TIME_ZONE, '+00:00'), state, fail_reason, instance, process_id FROM mysql.analyze_jobs ORDER BY state = 'running' DESC, update_time DESC"
+	var args []any
+	if !allJobs {
+		sql += " LIMIT %?"
+		args = append(args, maxAnalyzeJobs)
+	}
 	exec := sctx.GetRestrictedSQLExecutor()
 	kctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
-	chunkRows, _, err := exec.ExecRestrictedSQL(kctx, nil, sql, maxAnalyzeJobs)
+	chunkRows, _, err := exec.ExecRestrictedSQL(kctx, nil, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -2538,7 +2564,8 @@ func calRemainInfoForAnalyzeStatus(ctx context.Context, totalCnt int64, processe
 	if totalCnt == 0 {
 		return 0, 100.0
 	}
-	remainLine := totalCnt - processedRows
+	// The total count is estimated, so the processed rows may exceed it.
+	remainLine := max(totalCnt-processedRows, 0)
 	if processedRows == 0 {
 		processedRows = 1
 	}
@@ -2546,13 +2573,13 @@ func calRemainInfoForAnalyzeStatus(ctx context.Context, totalCnt int64, processe
 		duration = 1 * time.Second
 	}
 	i := float64(remainLine) * duration.Seconds() / float64(processedRows)
-	persentage := float64(processedRows) / float64(totalCnt)
+	persentage := min(float64(processedRows)/float64(totalCnt), 1)
 	return time.Duration(i) * time.Second, persentage
 }
 
 // setDataForAnalyzeStatus gets all the analyze jobs.
 func (e *memtableRetriever) setDataForAnalyzeStatus(ctx context.Context, sctx sessionctx.Context) (err error) {
-	e.rows, err = dataForAnalyzeStatusHelper(ctx, sctx)
+	e.rows, err = dataForAnalyzeStatusHelper(ctx, sctx, false)
 	return
 }
 
@@ -2855,6 +2882,334 @@ func (e *memtableRetriever) setDataForClusterMemoryUsageOpsHistory(ctx sessionct
 	return nil
 }
 
+func (e *memtableRetriever) setDataForTopSQLCPUUsage(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return nil
+	}
+	records := topsql.CPUUsageRecords()
+	rows := make([][]types.Datum, 0, len(records))
+	for _, record := range records {
+		sqlDigest, planDigest, digestText := types.NewDatum(nil), types.NewDatum(nil), types.NewDatum(nil)
+		if len(record.SQLDigest) > 0 {
+			sqlDigest.SetString(hex.EncodeToString(record.SQLDigest), mysql.DefaultCollationName)
+			digestText.SetString(record.NormalizedSQL, mysql.DefaultCollationName)
+		}
+		if len(record.PlanDigest) > 0 {
+			planDigest.SetString(hex.EncodeToString(record.PlanDigest), mysql.DefaultCollationName)
+		}
+		isInternal := 0
+		if record.IsInternal {
+			isInternal = 1
+		}
+		rows = append(rows, []types.Datum{
+			types.NewTimeDatum(types.NewTime(types.FromGoTime(record.WindowStart), mysql.TypeDatetime, 0)), // WINDOW_START
+			types.NewTimeDatum(types.NewTime(types.FromGoTime(record.WindowEnd), mysql.TypeDatetime, 0)),   // WINDOW_END
+			sqlDigest,                            // SQL_DIGEST
+			planDigest,                           // PLAN_DIGEST
+			digestText,                           // DIGEST_TEXT
+			types.NewIntDatum(int64(isInternal)), // IS_INTERNAL
+			types.NewUintDatum(record.CPUTimeMs), // CPU_TIME_MS
+			types.NewUintDatum(record.ExecCount), // EXEC_COUNT
+		})
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataForClusterTopSQLCPUUsage(ctx sessionctx.Context) error {
+	err := e.setDataForTopSQLCPUUsage(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := infoschema.AppendHostInfoToRows(ctx, e.rows)
+	if err != nil {
+		return err
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataForStatsHealth(ctx context.Context, sctx sessionctx.Context) error {
+	h := domain.GetDomain(sctx).StatsHandle()
+	checker := privilege.GetPrivilegeManager(sctx)
+	rows := make([][]types.Datum, 0, 100)
+	appendRow := func(schema pmodel.CIStr, tbl *model.TableInfo, partitionName string, physicalID int64, statsTbl *statistics.Table) {
+		healthy, lastAnalyzeTime, modifyRatio := types.NewDatum(nil), types.NewDatum(nil), types.NewDatum(nil)
+		var rowCount, modifyCount int64
+		var statsVersion uint64
+		var analyzeVersion int
+		if statsTbl != nil && !statsTbl.Pseudo {
+			if score, ok := statsTbl.GetStatsHealthy(); ok {
+				healthy.SetInt64(score)
+			}
+			if statsTbl.IsAnalyzed() {
+				t := types.NewTime(types.FromGoTime(oracle.GetTimeFromTS(statsTbl.LastAnalyzeVersion).In(sctx.GetSessionVars().Location())), mysql.TypeDatetime, 0)
+				lastAnalyzeTime.SetMysqlTime(t)
+				modifyRatio.SetFloat64(statsTbl.GetModifyRatio())
+			}
+			rowCount, modifyCount = statsTbl.RealtimeCount, statsTbl.ModifyCount
+			statsVersion, analyzeVersion = statsTbl.Version, statsTbl.StatsVer
+		}
+		reason := types.NewDatum(nil)
+		if r := statsTbl.GetStatsUnusableReason(tbl); r != "" {
+			reason.SetString(r, mysql.DefaultCollationName)
+		}
+		rows = append(rows, []types.Datum{
+			types.NewStringDatum(schema.O),           // TABLE_SCHEMA
+			types.NewStringDatum(tbl.Name.O),         // TABLE_NAME
+			types.NewStringDatum(partitionName),      // PARTITION_NAME
+			types.NewIntDatum(physicalID),            // TABLE_ID
+			healthy,                                  // HEALTHY
+			types.NewIntDatum(rowCount),              // ROW_COUNT
+			types.NewIntDatum(modifyCount),           // MODIFY_COUNT
+			modifyRatio,                              // MODIFY_RATIO
+			lastAnalyzeTime,                          // LAST_ANALYZE_TIME
+			types.NewUintDatum(statsVersion),         // STATS_VERSION
+			types.NewIntDatum(int64(analyzeVersion)), // ANALYZE_VERSION
+			reason,                                   // UNUSABLE_REASON
+		})
+	}
+	for _, schema := range e.is.AllSchemaNames() {
+		if util.IsMemOrSysDB(schema.L) {
+			continue
+		}
+		tables, err := e.is.SchemaTableInfos(ctx, schema)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, tbl := range tables {
+			if tbl.IsView() || tbl.IsSequence() {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			pi := tbl.GetPartitionInfo()
+			if pi == nil {
+				appendRow(schema, tbl, "", tbl.ID, h.GetTableStats(tbl))
+				continue
+			}
+			appendRow(schema, tbl, "global", tbl.ID, h.GetTableStats(tbl))
+			for _, def := range pi.Definitions {
+				appendRow(schema, tbl, def.Name.O, def.ID, h.GetPartitionStats(tbl, def.ID))
+			}
+		}
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataForPartitionStatsRollup(ctx context.Context, sctx sessionctx.Context) error {
+	h := domain.GetDomain(sctx).StatsHandle()
+	checker := privilege.GetPrivilegeManager(sctx)
+	rows := make([][]types.Datum, 0, 100)
+	for _, schema := range e.is.AllSchemaNames() {
+		if util.IsMemOrSysDB(schema.L) {
+			continue
+		}
+		tables, err := e.is.SchemaTableInfos(ctx, schema)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, tbl := range tables {
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			lastAnalyzeTimes := make(map[int64]types.Datum)
+			rollups := statistics.BuildPartitionRollups(tbl, func(physicalID int64) *statistics.Table {
+				statsTbl := h.GetPartitionStats(tbl, physicalID)
+				lastAnalyzeTime := types.NewDatum(nil)
+				if statsTbl != nil && !statsTbl.Pseudo && statsTbl.IsAnalyzed() {
+					t := types.NewTime(types.FromGoTime(oracle.GetTimeFromTS(statsTbl.LastAnalyzeVersion).In(sctx.GetSessionVars().Location())), mysql.TypeDatetime, 0)
+					lastAnalyzeTime.SetMysqlTime(t)
+				}
+				lastAnalyzeTimes[physicalID] = lastAnalyzeTime
+				return statsTbl
+			})
+			for _, rollup := range rollups {
+				fullStats := 0
+				if rollup.FullStats {
+					fullStats = 1
+				}
+				rows = append(rows, []types.Datum{
+					types.NewStringDatum(schema.O),          // TABLE_SCHEMA
+					types.NewStringDatum(tbl.Name.O),        // TABLE_NAME
+					types.NewStringDatum(rollup.Name),       // PARTITION_NAME
+					types.NewIntDatum(rollup.PhysicalID),    // PARTITION_ID
+					types.NewStringDatum(rollup.LowerBound), // LOWER_BOUND
+					types.NewStringDatum(rollup.UpperBound), // UPPER_BOUND
+					types.NewIntDatum(rollup.RowCount),      // ROW_COUNT
+					types.NewIntDatum(rollup.ModifyCount),   // MODIFY_COUNT
+					types.NewIntDatum(rollup.Size),          // DATA_SIZE
+					types.NewIntDatum(int64(fullStats)),     // FULL_STATS
+					lastAnalyzeTimes[rollup.PhysicalID],     // LAST_ANALYZE_TIME
+				})
+			}
+		}
+	}
+	e.rows = rows
+	return nil
+}
+
+// defaultColumnStatisticsBuckets is the default number of the histogram buckets of ANALYZE, which is shown as the
+// number of buckets specified if the table has no persisted analyze options.
+const defaultColumnStatisticsBuckets = 256
+
+func (e *memtableRetriever) setDataForColumnStatistics(ctx context.Context, sctx sessionctx.Context) error {
+	h := domain.GetDomain(sctx).StatsHandle()
+	checker := privilege.GetPrivilegeManager(sctx)
+	// The persisted sample rates and numbers of buckets are the ones specified by the users.
+	chunkRows, _, err := sctx.GetRestrictedSQLExecutor().ExecRestrictedSQL(
+		kv.WithInternalSourceType(ctx, kv.InternalTxnStats), nil, "select table_id, sample_rate, buckets from mysql.analyze_options")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sampleRates := make(map[int64]float64, len(chunkRows))
+	numBuckets := make(map[int64]int64, len(chunkRows))
+	for _, row := range chunkRows {
+		sampleRates[row.GetInt64(0)] = row.GetFloat64(1)
+		numBuckets[row.GetInt64(0)] = row.GetInt64(2)
+	}
+	loc := sctx.GetSessionVars().StmtCtx.TimeZone()
+	rows := make([][]types.Datum, 0, 100)
+	for _, schema := range e.is.AllSchemaNames() {
+		if util.IsMemOrSysDB(schema.L) {
+			continue
+		}
+		tables, err := e.is.SchemaTableInfos(ctx, schema)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, tbl := range tables {
+			if tbl.IsView() || tbl.IsSequence() {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			// Like MySQL, only the stats of the whole table are shown for the partitioned tables.
+			statsTbl := h.GetTableStats(tbl)
+			if statsTbl.Pseudo {
+				continue
+			}
+			for _, colInfo := range tbl.Cols() {
+				col := statsTbl.GetCol(colInfo.ID)
+				if col == nil || !col.IsAnalyzed() || !col.IsFullLoad() {
+					continue
+				}
+				sampleRate := sampleRates[tbl.ID]
+				if sampleRate <= 0 {
+					// ANALYZE samples about config.DefRowsForSampleRate rows without the specified sample rate.
+					sampleRate = 1
+					if totalCount := col.TotalRowCount(); totalCount > config.DefRowsForSampleRate {
+						sampleRate = config.DefRowsForSampleRate / totalCount
+					}
+				}
+				buckets := numBuckets[tbl.ID]
+				if buckets <= 0 {
+					buckets = defaultColumnStatisticsBuckets
+				}
+				lastUpdated := oracle.GetTimeFromTS(col.LastUpdateVersion)
+				hist, err := col.MySQLHistogram(loc, lastUpdated, sampleRate, buckets)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				histJSON, err := types.CreateBinaryJSONWithCheck(hist)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				rows = append(rows, []types.Datum{
+					types.NewStringDatum(schema.O),       // SCHEMA_NAME
+					types.NewStringDatum(tbl.Name.O),     // TABLE_NAME
+					types.NewStringDatum(colInfo.Name.O), // COLUMN_NAME
+					types.NewJSONDatum(histJSON),         // HISTOGRAM
+				})
+			}
+		}
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataForLongTxnEvents(sctx sessionctx.Context) {
+	loginUser := sctx.GetSessionVars().User
+	hasProcessPriv := hasPriv(sctx, mysql.ProcessPriv)
+	events := domain.GetDomain(sctx).ExpensiveQueryHandle().LongTxnEvents()
+	rows := make([][]types.Datum, 0, len(events))
+	for _, event := range events {
+		// Like the process list, the users without the PROCESS privilege can only see their own transactions.
+		if !hasProcessPriv && loginUser != nil && event.User != loginUser.Username {
+			continue
+		}
+		eventTime := types.NewTime(types.FromGoTime(event.Time), mysql.TypeTimestamp, types.MaxFsp)
+		txnStartTime := types.NewTime(types.FromGoTime(event.TxnStartTime), mysql.TypeTimestamp, types.MaxFsp)
+		rows = append(rows, types.MakeDatums(
+			eventTime,                 // TIME
+			event.ConnID,              // SESSION_ID
+			event.User,                // USER
+			event.Host,                // HOST
+			event.DB,                  // DB
+			event.ResourceGroup,       // RESOURCE_GROUP
+			event.TxnStartTS,          // TRX_ID
+			txnStartTime,              // TRX_START_TIME
+			event.Violation,           // VIOLATION
+			event.Duration.Seconds(),  // DURATION
+			event.Threshold.Seconds(), // THRESHOLD
+			event.Action,              // ACTION
+		))
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataForObjectDependencies(ctx context.Context, sctx sessionctx.Context) error {
+	deps, err := collectObjectDependencies(ctx, sctx, e.is)
+	if err != nil {
+		return err
+	}
+	checker := privilege.GetPrivilegeManager(sctx)
+	rows := make([][]types.Datum, 0, len(deps))
+	for _, dep := range deps {
+		// The bindings are visible if the referenced tables are visible.
+		schema, name := dep.objectSchema, dep.objectName
+		switch dep.objectType {
+		case objectTypeSchema:
+			name = pmodel.CIStr{}
+		case objectTypeBinding:
+			schema, name = dep.refSchema, dep.refName
+		}
+		if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.L, name.L, "", mysql.AllPrivMask) {
+			continue
+		}
+		refSchema, refColumn, detail := types.NewDatum(nil), types.NewDatum(nil), types.NewDatum(nil)
+		if dep.refSchema.L != "" {
+			refSchema.SetString(dep.refSchema.O, mysql.DefaultCollationName)
+		}
+		if dep.refColumn != "" {
+			refColumn.SetString(dep.refColumn, mysql.DefaultCollationName)
+		}
+		if dep.detail != "" {
+			detail.SetString(dep.detail, mysql.DefaultCollationName)
+		}
+		objectName := types.NewDatum(nil)
+		if dep.objectType != objectTypeSchema {
+			objectName.SetString(dep.objectName.O, mysql.DefaultCollationName)
+		}
+		rows = append(rows, []types.Datum{
+			types.NewStringDatum(dep.objectSchema.O), // OBJECT_SCHEMA
+			objectName,                               // OBJECT_NAME
+			types.NewStringDatum(dep.objectType),     // OBJECT_TYPE
+			refSchema,                                // REFERENCED_SCHEMA
+			types.NewStringDatum(dep.refName.O),      // REFERENCED_NAME
+			refColumn,                                // REFERENCED_COLUMN
+			types.NewStringDatum(dep.refType),        // REFERENCED_TYPE
+			types.NewStringDatum(dep.depType),        // DEPENDENCY_TYPE
+			detail,                                   // DETAIL
+		})
+	}
+	e.rows = rows
+	return nil
+}
+
 // tidbTrxTableRetriever is the memtable retriever for the TIDB_TRX and CLUSTER_TIDB_TRX table.
 type tidbTrxTableRetriever struct {
 	dummyCloser
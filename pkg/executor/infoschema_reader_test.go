@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/pingcap/tidb/pkg/domain/infosync"
+	"github.com/pingcap/tidb/pkg/errno"
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/auth"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
@@ -208,6 +209,101 @@ func TestDataForTableStatsField(t *testing.T) {
 		testkit.Rows("3 18 54 6"))
 }
 
+func TestStatsHealthTable(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	h := dom.StatsHandle()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, index idx(a))")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	tk.MustExec("create table t2 (a int, b int)")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (3, 3)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	query := "select table_name, partition_name, healthy, row_count, modify_count, unusable_reason from information_schema.tidb_stats_health where table_schema = 'test' order by table_name"
+	tk.MustQuery(query).Check(testkit.Rows(
+		"t  0 3 3 never_analyzed",
+		"t2  0 3 3 never_analyzed"))
+
+	tk.MustExec("analyze table t all columns")
+	tk.MustExec("analyze table t2 columns a")
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	tk.MustQuery(query).Check(testkit.Rows(
+		"t  100 3 0 <nil>",
+		"t2  100 3 0 partial_columns"))
+	tk.MustQuery("select count(*) from information_schema.tidb_stats_health where table_name = 't' and last_analyze_time is not null and analyze_version = 2").Check(testkit.Rows("1"))
+
+	tk.MustExec("insert into t values (4, 4), (5, 5), (6, 6)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	tk.MustQuery("select healthy, modify_ratio, unusable_reason from information_schema.tidb_stats_health where table_schema = 'test' and table_name = 't'").Check(testkit.Rows(
+		"0 1 too_stale"))
+}
+
+func TestColumnStatisticsTable(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b varchar(10) collate utf8mb4_bin, c int)")
+	tk.MustExec("insert into t values (1, 'a', 1), (2, 'b', 1), (2, 'b', 1), (null, 'c', 1)")
+	tk.MustQuery("select count(*) from information_schema.column_statistics where schema_name = 'test'").Check(testkit.Rows("0"))
+
+	tk.MustExec("analyze table t columns a, b")
+	tk.MustQuery("select column_name from information_schema.column_statistics where schema_name = 'test' and table_name = 't' order by column_name").Check(
+		testkit.Rows("a", "b"))
+	tk.MustQuery(`select histogram->'$."histogram-type"', histogram->'$."data-type"', histogram->'$."null-values"', histogram->'$."sampling-rate"', histogram->'$."number-of-buckets-specified"', histogram->'$.buckets'
+		from information_schema.column_statistics where schema_name = 'test' and table_name = 't' and column_name = 'a'`).Check(
+		testkit.Rows(`"equi-height" "int" 0.25 1 256 [[1, 1, 0.25, 1], [2, 2, 0.75, 1]]`))
+	tk.MustQuery(`select histogram->'$."data-type"', histogram->'$."collation-id"', histogram->'$.buckets[0][0]'
+		from information_schema.column_statistics where schema_name = 'test' and table_name = 't' and column_name = 'b'`).Check(
+		testkit.Rows(`"string" 46 "base64:type254:YQ=="`))
+}
+
+func TestObjectDependencies(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table p (id int primary key)")
+	tk.MustExec("create table c (id int, pid int, constraint fk_p foreign key (pid) references p (id))")
+	tk.MustExec("create view v1 as select * from p")
+	tk.MustExec("create view v2 as select * from v1")
+	tk.MustExec("create sequence seq")
+	tk.MustExec("create table s (id int default next value for seq)")
+	tk.MustExec("create global binding for select * from p using select /*+ use_index(p) */ * from p")
+	query := "select object_name, object_type, referenced_name, referenced_column, referenced_type, dependency_type, detail " +
+		"from information_schema.object_dependencies where object_schema = 'test' and object_type != 'BINDING' order by object_name"
+	tk.MustQuery(query).Check(testkit.Rows(
+		"c TABLE p id TABLE FOREIGN KEY fk_p",
+		"s TABLE seq <nil> SEQUENCE DEFAULT VALUE id",
+		"v1 VIEW p <nil> TABLE VIEW <nil>",
+		"v2 VIEW v1 <nil> VIEW VIEW <nil>"))
+	tk.MustQuery("select object_schema, referenced_schema, referenced_name, dependency_type from information_schema.object_dependencies where object_type = 'BINDING'").Check(
+		testkit.Rows("test test p BINDING"))
+
+	tk.MustGetErrCode("drop table p restrict", errno.ErrObjectHasDependents)
+	tk.MustGetErrMsg("drop view v1 restrict", "[ddl:8267]VIEW 'test.v1' is referenced by VIEW 'test.v2' through VIEW")
+	tk.MustExec("drop view v2 restrict")
+	tk.MustExec("create view v2 as select * from v1")
+	// Without RESTRICT or CASCADE, the dependent objects are reported as warnings.
+	tk.MustExec("drop view v1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 8267 VIEW 'test.v1' is referenced by VIEW 'test.v2' through VIEW"))
+	tk.MustExec("create view v1 as select * from p")
+
+	// CASCADE drops the dependent views, foreign keys and bindings.
+	tk.MustExec("drop table p cascade")
+	tk.MustQuery("show full tables where table_type = 'VIEW'").Check(testkit.Rows())
+	tk.MustQuery("select count(*) from information_schema.referential_constraints where constraint_schema = 'test'").Check(testkit.Rows("0"))
+	tk.MustQuery("show global bindings").Check(testkit.Rows())
+	tk.MustQuery(query).Check(testkit.Rows("s TABLE seq <nil> SEQUENCE DEFAULT VALUE id"))
+	// CASCADE drops the default values using the dropped sequences.
+	tk.MustExec("drop sequence seq cascade")
+	tk.MustExec("insert into s values ()")
+	tk.MustQuery("select * from s").Check(testkit.Rows("<nil>"))
+	tk.MustQuery(query).Check(testkit.Rows())
+}
+
 func TestPartitionsTable(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	h := dom.StatsHandle()
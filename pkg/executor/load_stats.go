@@ -15,16 +15,25 @@
 package executor
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/pingcap/errors"
+	brstorage "github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/pkg/domain"
 	"github.com/pingcap/tidb/pkg/executor/internal/exec"
 	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/util/chunk"
+	"golang.org/x/sync/errgroup"
 )
 
 var _ exec.Executor = &LoadStatsExec{}
@@ -39,6 +48,8 @@ type LoadStatsExec struct {
 type LoadStatsInfo struct {
 	Path string
 	Ctx  sessionctx.Context
+	// Merge indicates the stats are merged into the existing stats of the table, see LOAD STATS ... MERGE.
+	Merge bool
 }
 
 // loadStatsVarKeyType is a dummy type to avoid naming collision in context.
@@ -52,12 +63,21 @@ func (loadStatsVarKeyType) String() string {
 // LoadStatsVarKey is a variable key for load statistic.
 const LoadStatsVarKey loadStatsVarKeyType = 0
 
+// loadStatsExternalConcurrency is the number of stats files loaded concurrently
+// when loading a directory from the external storage.
+const loadStatsExternalConcurrency = 8
+
 // Next implements the Executor Next interface.
-func (e *LoadStatsExec) Next(_ context.Context, req *chunk.Chunk) error {
+func (e *LoadStatsExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.GrowAndReset(e.MaxChunkSize())
 	if len(e.info.Path) == 0 {
 		return errors.New("Load Stats: file path is empty")
 	}
+	// The stats files in the external storage are read by the server directly,
+	// instead of being sent by the client.
+	if u, ok := parseExternalStatsURI(e.info.Path); ok {
+		return e.info.loadFromExternalStorage(ctx, u)
+	}
 	val := e.Ctx().Value(LoadStatsVarKey)
 	if val != nil {
 		e.Ctx().SetValue(LoadStatsVarKey, nil)
@@ -78,15 +98,102 @@ func (*LoadStatsExec) Open(context.Context) error {
 }
 
 // Update updates the stats of the corresponding table according to the data.
+// The data can be dumped in either the json or the binary format.
 func (e *LoadStatsInfo) Update(data []byte) error {
-	jsonTbl := &util.JSONTable{}
-	if err := json.Unmarshal(data, jsonTbl); err != nil {
-		return errors.Trace(err)
-	}
 	do := domain.GetDomain(e.Ctx)
 	h := do.StatsHandle()
 	if h == nil {
 		return errors.New("Load Stats: handle is nil")
 	}
-	return h.LoadStatsFromJSON(context.Background(), e.Ctx.GetInfoSchema().(infoschema.InfoSchema), jsonTbl, 0)
+	is := e.Ctx.GetInfoSchema().(infoschema.InfoSchema)
+	if br := bufio.NewReader(bytes.NewReader(data)); storage.IsBinaryStats(br) {
+		if e.Merge {
+			return errors.New("Load Stats: only the stats in the json format can be merged")
+		}
+		return h.LoadStatsFromBinary(context.Background(), is, br, 0)
+	}
+	jsonTbl := &util.JSONTable{}
+	if err := json.Unmarshal(data, jsonTbl); err != nil {
+		return errors.Trace(err)
+	}
+	if e.Merge {
+		return h.MergeStatsFromJSON(context.Background(), is, jsonTbl)
+	}
+	return h.LoadStatsFromJSON(context.Background(), is, jsonTbl, 0)
+}
+
+// parseExternalStatsURI parses the path of LOAD STATS, it returns false if the path
+// isn't an URI of the external storage, e.g. s3://bucket/stats, gcs://bucket/stats
+// or local:///tmp/stats. Other paths are treated as client side files.
+func parseExternalStatsURI(p string) (*url.URL, bool) {
+	if !strings.Contains(p, "://") {
+		return nil, false
+	}
+	u, err := brstorage.ParseRawURL(p)
+	if err != nil || u.Scheme == "" {
+		return nil, false
+	}
+	return u, true
+}
+
+// isStatsFileName checks whether the file is a stats file dumped in the json or the binary format.
+func isStatsFileName(name string) bool {
+	return strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".bin")
+}
+
+// loadFromExternalStorage loads the stats from the external storage. If the URI points to
+// a stats file, only that file is loaded. Otherwise, the URI is treated as a directory and
+// all the stats files under it are loaded concurrently.
+func (e *LoadStatsInfo) loadFromExternalStorage(ctx context.Context, u *url.URL) error {
+	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnStats)
+	var fileName string
+	if isStatsFileName(u.Path) {
+		fileName = path.Base(u.Path)
+		u.Path = path.Dir(u.Path)
+	}
+	backend, err := brstorage.ParseBackendFromURL(u, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	store, err := brstorage.NewWithDefaultOpt(ctx, backend)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer store.Close()
+	if fileName != "" {
+		return e.loadExternalStatsFile(ctx, store, fileName)
+	}
+
+	var fileNames []string
+	err = store.WalkDir(ctx, &brstorage.WalkOption{}, func(name string, _ int64) error {
+		if isStatsFileName(name) {
+			fileNames = append(fileNames, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(fileNames) == 0 {
+		return errors.Errorf("Load Stats: no stats file is found in %s", e.Path)
+	}
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(loadStatsExternalConcurrency)
+	for _, name := range fileNames {
+		eg.Go(func() error {
+			return e.loadExternalStatsFile(egCtx, store, name)
+		})
+	}
+	return eg.Wait()
+}
+
+func (e *LoadStatsInfo) loadExternalStatsFile(ctx context.Context, store brstorage.ExternalStorage, name string) error {
+	data, err := store.ReadFile(ctx, name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := e.Update(data); err != nil {
+		return errors.Annotatef(err, "Load Stats: failed to load %s", name)
+	}
+	return nil
 }
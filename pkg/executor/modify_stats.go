@@ -0,0 +1,114 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/executor/internal/exec"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+)
+
+var _ exec.Executor = &ModifyStatsExec{}
+
+// ModifyStatsExec represents an executor injecting the statistics of a table manually.
+type ModifyStatsExec struct {
+	exec.BaseExecutor
+	Table   *ast.TableName
+	Options []*ast.ModifyStatsOption
+}
+
+// Next implements the Executor Next interface.
+func (e *ModifyStatsExec) Next(ctx context.Context, _ *chunk.Chunk) error {
+	do := domain.GetDomain(e.Ctx())
+	h := do.StatsHandle()
+	if h == nil {
+		return errors.New("Modify Stats: handle is nil")
+	}
+	tbl, err := do.InfoSchema().TableByName(ctx, e.Table.Schema, e.Table.Name)
+	if err != nil {
+		return err
+	}
+	stats, err := e.buildInjectedStats(tbl.Meta())
+	if err != nil {
+		return err
+	}
+	return h.InjectStats(tbl.Meta().ID, stats)
+}
+
+// buildInjectedStats validates the options and converts them to the injected stats.
+func (e *ModifyStatsExec) buildInjectedStats(tblInfo *model.TableInfo) (*statstypes.InjectedStats, error) {
+	stats := &statstypes.InjectedStats{}
+	typeCtx := e.Ctx().GetSessionVars().StmtCtx.TypeCtx()
+	for _, opt := range e.Options {
+		name := ast.ModifyStatsOptionString[opt.Tp]
+		d := types.NewDatum(opt.Value.GetValue())
+		var colID int64
+		if opt.Tp != ast.ModifyStatsRowCount {
+			col := model.FindColumnInfo(tblInfo.Columns, opt.Column.L)
+			if col == nil || col.Hidden {
+				return nil, infoschema.ErrColumnNotExists.GenWithStackByArgs(opt.Column.O, tblInfo.Name.O)
+			}
+			colID = col.ID
+		}
+		if opt.Tp == ast.ModifyStatsNullRatio {
+			ratio, err := d.ToFloat64(typeCtx)
+			if err != nil {
+				return nil, err
+			}
+			if ratio < 0 || ratio > 1 {
+				return nil, errors.Errorf("Modify Stats: %s should be between 0 and 1", name)
+			}
+			if stats.NullRatios == nil {
+				stats.NullRatios = make(map[int64]float64)
+			}
+			stats.NullRatios[colID] = ratio
+			continue
+		}
+		if d.Kind() != types.KindInt64 && d.Kind() != types.KindUint64 {
+			return nil, errors.Errorf("Modify Stats: %s should be an integer", name)
+		}
+		v, err := d.ToInt64(typeCtx)
+		if err != nil {
+			return nil, err
+		}
+		if opt.Tp == ast.ModifyStatsRowCount {
+			stats.RowCount = &v
+			continue
+		}
+		if stats.ColNDVs == nil {
+			stats.ColNDVs = make(map[int64]int64)
+		}
+		stats.ColNDVs[colID] = v
+	}
+	return stats, nil
+}
+
+// Close implements the Executor Close interface.
+func (*ModifyStatsExec) Close() error {
+	return nil
+}
+
+// Open implements the Executor Open interface.
+func (*ModifyStatsExec) Open(context.Context) error {
+	return nil
+}
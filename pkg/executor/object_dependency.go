@@ -0,0 +1,405 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/bindinfo"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/privilege"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/dbterror"
+	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+)
+
+// The types of the objects in the dependency graph.
+const (
+	objectTypeSchema          = "SCHEMA"
+	objectTypeTable           = "TABLE"
+	objectTypeView            = "VIEW"
+	objectTypeSequence        = "SEQUENCE"
+	objectTypeColumn          = "COLUMN"
+	objectTypeBinding         = "BINDING"
+	objectTypePlacementPolicy = "PLACEMENT POLICY"
+)
+
+// The types of the dependencies, which tell how an object depends on the referenced object.
+const (
+	dependencyTypeView         = "VIEW"
+	dependencyTypeForeignKey   = "FOREIGN KEY"
+	dependencyTypeDefaultValue = "DEFAULT VALUE"
+	dependencyTypeBinding      = "BINDING"
+	dependencyTypeTTL          = "TTL"
+	dependencyTypePlacement    = "PLACEMENT"
+)
+
+// objectDependency is an edge of the dependency graph of the schema objects, which means the object depends on the
+// referenced object.
+type objectDependency struct {
+	objectSchema pmodel.CIStr
+	// objectName is the SQL digest if the object is a binding.
+	objectName pmodel.CIStr
+	objectType string
+	// refSchema is empty if the referenced object is a placement policy.
+	refSchema pmodel.CIStr
+	refName   pmodel.CIStr
+	refColumn string
+	refType   string
+	depType   string
+	// detail is the name of the foreign key, the column using the default value, the partition using the placement
+	// policy or the original SQL of the binding.
+	detail string
+}
+
+func (d *objectDependency) objectIdent() string {
+	if d.objectType == objectTypeSchema {
+		return d.objectSchema.O
+	}
+	return ast.Ident{Schema: d.objectSchema, Name: d.objectName}.String()
+}
+
+func (d *objectDependency) refIdent() string {
+	if d.refSchema.L == "" {
+		return d.refName.O
+	}
+	return ast.Ident{Schema: d.refSchema, Name: d.refName}.String()
+}
+
+// collectObjectDependencies collects the dependencies between the schema objects from the info schema and the global
+// bindings.
+func collectObjectDependencies(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema) ([]objectDependency, error) {
+	deps := make([]objectDependency, 0, 16)
+	for _, schema := range is.AllSchemaNames() {
+		if util.IsMemOrSysDB(schema.L) {
+			continue
+		}
+		if dbInfo, ok := is.SchemaByName(schema); ok && dbInfo.PlacementPolicyRef != nil {
+			deps = append(deps, objectDependency{
+				objectSchema: schema,
+				objectType:   objectTypeSchema,
+				refName:      dbInfo.PlacementPolicyRef.Name,
+				refType:      objectTypePlacementPolicy,
+				depType:      dependencyTypePlacement,
+			})
+		}
+		tables, err := is.SchemaTableInfos(ctx, schema)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, tbl := range tables {
+			deps = appendTableDependencies(deps, is, schema, tbl)
+		}
+	}
+	return appendBindingDependencies(deps, sctx, is), nil
+}
+
+func appendTableDependencies(deps []objectDependency, is infoschema.InfoSchema, schema pmodel.CIStr, tbl *model.TableInfo) []objectDependency {
+	objectType := getObjectType(tbl)
+	newDep := func(refSchema, refName pmodel.CIStr, refType, depType string) objectDependency {
+		return objectDependency{
+			objectSchema: schema,
+			objectName:   tbl.Name,
+			objectType:   objectType,
+			refSchema:    refSchema,
+			refName:      refName,
+			refType:      refType,
+			depType:      depType,
+		}
+	}
+	if tbl.IsView() {
+		stmt, err := parser.New().ParseOneStmt(tbl.View.SelectStmt, "", "")
+		// The view may be invalid, ignore it.
+		if err == nil {
+			for _, ref := range collectReferredObjects(is, schema, stmt) {
+				deps = append(deps, newDep(ref.schema, ref.tbl.Name, getObjectType(ref.tbl), dependencyTypeView))
+			}
+		}
+	}
+	for _, fk := range tbl.ForeignKeys {
+		refSchema := fk.RefSchema
+		if refSchema.L == "" {
+			refSchema = schema
+		}
+		dep := newDep(refSchema, fk.RefTable, objectTypeTable, dependencyTypeForeignKey)
+		refCols := make([]string, 0, len(fk.RefCols))
+		for _, col := range fk.RefCols {
+			refCols = append(refCols, col.O)
+		}
+		dep.refColumn = strings.Join(refCols, ",")
+		dep.detail = fk.Name.O
+		deps = append(deps, dep)
+	}
+	for _, col := range tbl.Columns {
+		expr, ok := col.DefaultValue.(string)
+		if !col.DefaultIsExpr || !ok || !strings.HasPrefix(strings.ToLower(expr), ast.NextVal) {
+			continue
+		}
+		stmt, err := parser.New().ParseOneStmt("select "+expr, "", "")
+		if err != nil {
+			continue
+		}
+		for _, ref := range collectReferredObjects(is, schema, stmt) {
+			dep := newDep(ref.schema, ref.tbl.Name, getObjectType(ref.tbl), dependencyTypeDefaultValue)
+			dep.detail = col.Name.O
+			deps = append(deps, dep)
+		}
+	}
+	if tbl.TTLInfo != nil {
+		dep := newDep(schema, tbl.Name, objectTypeColumn, dependencyTypeTTL)
+		dep.refColumn = tbl.TTLInfo.ColumnName.O
+		deps = append(deps, dep)
+	}
+	if tbl.PlacementPolicyRef != nil {
+		deps = append(deps, newDep(pmodel.CIStr{}, tbl.PlacementPolicyRef.Name, objectTypePlacementPolicy, dependencyTypePlacement))
+	}
+	if pi := tbl.GetPartitionInfo(); pi != nil {
+		for _, def := range pi.Definitions {
+			if def.PlacementPolicyRef == nil {
+				continue
+			}
+			dep := newDep(pmodel.CIStr{}, def.PlacementPolicyRef.Name, objectTypePlacementPolicy, dependencyTypePlacement)
+			dep.detail = def.Name.O
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+func appendBindingDependencies(deps []objectDependency, sctx sessionctx.Context, is infoschema.InfoSchema) []objectDependency {
+	dom := domain.GetDomain(sctx)
+	if dom == nil || dom.BindHandle() == nil {
+		return deps
+	}
+	for _, binding := range dom.BindHandle().GetAllGlobalBindings() {
+		if !binding.IsBindingAvailable() {
+			continue
+		}
+		seen := make(map[string]struct{}, len(binding.TableNames))
+		for _, tn := range binding.TableNames {
+			schema := tn.Schema
+			if schema.L == "" {
+				schema = pmodel.NewCIStr(binding.Db)
+			}
+			// The tables of the universal bindings can be in any schema.
+			if schema.L == "*" {
+				continue
+			}
+			key := schema.L + "." + tn.Name.L
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			tbl, err := is.TableInfoByName(schema, tn.Name)
+			if err != nil {
+				continue
+			}
+			deps = append(deps, objectDependency{
+				objectSchema: pmodel.NewCIStr(binding.Db),
+				objectName:   pmodel.NewCIStr(binding.SQLDigest),
+				objectType:   objectTypeBinding,
+				refSchema:    schema,
+				refName:      tbl.Name,
+				refType:      getObjectType(tbl),
+				depType:      dependencyTypeBinding,
+				detail:       binding.OriginalSQL,
+			})
+		}
+	}
+	return deps
+}
+
+type referredObject struct {
+	schema pmodel.CIStr
+	tbl    *model.TableInfo
+}
+
+// collectReferredObjects collects the existing tables, views and sequences referred by the statement. The names
+// without schema are resolved in the given schema.
+func collectReferredObjects(is infoschema.InfoSchema, schema pmodel.CIStr, stmt ast.Node) []referredObject {
+	tableNames := bindinfo.CollectTableNames(stmt)
+	refs := make([]referredObject, 0, len(tableNames))
+	for _, tn := range tableNames {
+		refSchema := tn.Schema
+		if refSchema.L == "" {
+			refSchema = schema
+		}
+		// The name may refer to a CTE rather than a table.
+		tbl, err := is.TableInfoByName(refSchema, tn.Name)
+		if err != nil {
+			continue
+		}
+		dup := false
+		for _, ref := range refs {
+			if ref.tbl.ID == tbl.ID {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			refs = append(refs, referredObject{schema: refSchema, tbl: tbl})
+		}
+	}
+	return refs
+}
+
+func getObjectType(tbl *model.TableInfo) string {
+	switch {
+	case tbl.IsView():
+		return objectTypeView
+	case tbl.IsSequence():
+		return objectTypeSequence
+	default:
+		return objectTypeTable
+	}
+}
+
+// handleDropDependencies handles the objects depending on the dropped tables, views or sequences according to the
+// drop behavior. RESTRICT refuses to drop the objects if any other object depends on them, and CASCADE drops the
+// dependent views, foreign keys, sequence default values and bindings before dropping the objects. By default, the
+// dependent objects are left as they are, and the returned function reports them as warnings after the objects are
+// dropped.
+func (e *DDLExec) handleDropDependencies(ctx context.Context, objs []*ast.TableName, behavior ast.DropBehavior) (reportFn func(), err error) {
+	deps, err := collectObjectDependencies(ctx, e.Ctx(), e.is)
+	if err != nil {
+		return nil, err
+	}
+	currentDB := pmodel.NewCIStr(e.Ctx().GetSessionVars().CurrentDB)
+	dropped := make(map[string]struct{}, len(objs))
+	for _, obj := range objs {
+		schema := obj.Schema
+		if schema.L == "" {
+			schema = currentDB
+		}
+		dropped[schema.L+"."+obj.Name.L] = struct{}{}
+	}
+	isDropped := func(schema, name pmodel.CIStr) bool {
+		_, ok := dropped[schema.L+"."+name.L]
+		return ok
+	}
+	// With CASCADE, the views depending on the dropped views are dropped as well, so collect them transitively.
+	var dependents []objectDependency
+	collected := make([]bool, len(deps))
+	for changed := true; changed; {
+		changed = false
+		for i, dep := range deps {
+			if collected[i] || dep.depType == dependencyTypeTTL || dep.depType == dependencyTypePlacement ||
+				!isDropped(dep.refSchema, dep.refName) || isDropped(dep.objectSchema, dep.objectName) {
+				continue
+			}
+			if dep.objectType == objectTypeView && behavior == ast.DropBehaviorCascade {
+				dropped[dep.objectSchema.L+"."+dep.objectName.L] = struct{}{}
+				changed = true
+			}
+			collected[i] = true
+			dependents = append(dependents, dep)
+		}
+	}
+	if len(dependents) == 0 {
+		return func() {}, nil
+	}
+	switch behavior {
+	case ast.DropBehaviorRestrict:
+		dep := &dependents[0]
+		return nil, dbterror.ErrObjectHasDependents.GenWithStackByArgs(dep.refType, dep.refIdent(), dep.objectType, dep.objectIdent(), dep.depType)
+	case ast.DropBehaviorCascade:
+		return func() {}, e.dropDependents(ctx, dependents)
+	}
+	return func() {
+		for _, dep := range dependents {
+			e.Ctx().GetSessionVars().StmtCtx.AppendWarning(dbterror.ErrObjectHasDependents.FastGenByArgs(
+				dep.refType, dep.refIdent(), dep.objectType, dep.objectIdent(), dep.depType))
+		}
+	}, nil
+}
+
+// dropDependents drops the dependent objects for DROP ... CASCADE. The privileges of the dependent objects are
+// checked since they are not covered by the privileges of the statement.
+func (e *DDLExec) dropDependents(ctx context.Context, dependents []objectDependency) error {
+	sessVars := e.Ctx().GetSessionVars()
+	checker := privilege.GetPrivilegeManager(e.Ctx())
+	checkPriv := func(schema, name pmodel.CIStr, priv mysql.PrivilegeType, privName string) error {
+		if checker == nil || checker.RequestVerification(sessVars.ActiveRoles, schema.L, name.L, "", priv) {
+			return nil
+		}
+		user := sessVars.User
+		if user == nil {
+			return plannererrors.ErrTableaccessDenied.GenWithStackByArgs(privName, "", "", name.O)
+		}
+		return plannererrors.ErrTableaccessDenied.GenWithStackByArgs(privName, user.AuthUsername, user.AuthHostname, name.O)
+	}
+	views := make([]*ast.TableName, 0, len(dependents))
+	alters := make([]*ast.AlterTableStmt, 0, len(dependents))
+	digests := make([]string, 0, len(dependents))
+	for _, dep := range dependents {
+		switch dep.depType {
+		case dependencyTypeView:
+			if err := checkPriv(dep.objectSchema, dep.objectName, mysql.DropPriv, "DROP"); err != nil {
+				return err
+			}
+			views = append(views, &ast.TableName{Schema: dep.objectSchema, Name: dep.objectName})
+		case dependencyTypeForeignKey, dependencyTypeDefaultValue:
+			if err := checkPriv(dep.objectSchema, dep.objectName, mysql.AlterPriv, "ALTER"); err != nil {
+				return err
+			}
+			spec := &ast.AlterTableSpec{Tp: ast.AlterTableDropForeignKey, Name: dep.detail}
+			if dep.depType == dependencyTypeDefaultValue {
+				// ALTER COLUMN without options drops the default value.
+				spec = &ast.AlterTableSpec{
+					Tp:         ast.AlterTableAlterColumn,
+					NewColumns: []*ast.ColumnDef{{Name: &ast.ColumnName{Name: pmodel.NewCIStr(dep.detail)}}},
+				}
+			}
+			alters = append(alters, &ast.AlterTableStmt{
+				Table: &ast.TableName{Schema: dep.objectSchema, Name: dep.objectName},
+				Specs: []*ast.AlterTableSpec{spec},
+			})
+		case dependencyTypeBinding:
+			if checker != nil && !checker.RequestVerification(sessVars.ActiveRoles, "", "", "", mysql.SuperPriv) {
+				return plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+			}
+			if !slices.Contains(digests, dep.objectName.O) {
+				digests = append(digests, dep.objectName.O)
+			}
+		}
+	}
+	if len(views) > 0 {
+		err := e.ddlExecutor.DropView(e.Ctx(), &ast.DropTableStmt{IfExists: true, Tables: views, IsView: true})
+		if err != nil {
+			return err
+		}
+	}
+	for _, stmt := range alters {
+		if err := e.ddlExecutor.AlterTable(ctx, e.Ctx(), stmt); err != nil {
+			return err
+		}
+	}
+	if len(digests) > 0 {
+		if _, err := domain.GetDomain(e.Ctx()).BindHandle().DropGlobalBinding(digests); err != nil {
+			return err
+		}
+	}
+	return nil
+}
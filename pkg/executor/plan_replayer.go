@@ -16,6 +16,7 @@ package executor
 
 import (
 	"archive/zip"
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -34,6 +35,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/sessiontxn"
+	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/util/chunk"
 	"github.com/pingcap/tidb/pkg/util/logutil"
@@ -57,6 +59,7 @@ type PlanReplayerCaptureInfo struct {
 	SQLDigest  string
 	PlanDigest string
 	Remove     bool
+	Thresholds replayer.PlanReplayerCaptureThresholds
 }
 
 // PlanReplayerDumpInfo indicates dump info
@@ -147,8 +150,8 @@ func (e *PlanReplayerExec) registerCaptureTask(ctx context.Context) error {
 		return errors.New("plan replayer capture task already exists")
 	}
 	exec := e.Ctx().GetRestrictedSQLExecutor()
-	_, _, err = exec.ExecRestrictedSQL(ctx1, nil, fmt.Sprintf("insert into mysql.plan_replayer_task (sql_digest, plan_digest) values ('%s','%s')",
-		e.CaptureInfo.SQLDigest, e.CaptureInfo.PlanDigest))
+	_, _, err = exec.ExecRestrictedSQL(ctx1, nil, fmt.Sprintf("insert into mysql.plan_replayer_task (sql_digest, plan_digest, est_error_threshold, latency_threshold) values ('%s','%s',%v,%v)",
+		e.CaptureInfo.SQLDigest, e.CaptureInfo.PlanDigest, e.CaptureInfo.Thresholds.EstimationError, e.CaptureInfo.Thresholds.Latency.Seconds()))
 	if err != nil {
 		logutil.BgLogger().Warn("insert mysql.plan_replayer_status record failed",
 			zap.Error(err))
@@ -451,20 +454,26 @@ func loadStats(ctx sessionctx.Context, f *zip.File) error {
 	}
 	//nolint: errcheck
 	defer r.Close()
+	do := domain.GetDomain(ctx)
+	h := do.StatsHandle()
+	if h == nil {
+		return errors.New("plan replayer: hanlde is nil")
+	}
+	is := ctx.GetInfoSchema().(infoschema.InfoSchema)
+	br := bufio.NewReader(r)
+	// The stats may be dumped in the binary format if tidb_plan_replayer_stats_format is binary.
+	if storage.IsBinaryStats(br) {
+		return h.LoadStatsFromBinary(context.Background(), is, br, 0)
+	}
 	buf := new(bytes.Buffer)
-	_, err = buf.ReadFrom(r)
+	_, err = buf.ReadFrom(br)
 	if err != nil {
 		return errors.AddStack(err)
 	}
 	if err := json.Unmarshal(buf.Bytes(), jsonTbl); err != nil {
 		return errors.AddStack(err)
 	}
-	do := domain.GetDomain(ctx)
-	h := do.StatsHandle()
-	if h == nil {
-		return errors.New("plan replayer: hanlde is nil")
-	}
-	return h.LoadStatsFromJSON(context.Background(), ctx.GetInfoSchema().(infoschema.InfoSchema), jsonTbl, 0)
+	return h.LoadStatsFromJSON(context.Background(), is, jsonTbl, 0)
 }
 
 // Update updates the data of the corresponding table.
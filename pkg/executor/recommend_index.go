@@ -31,11 +31,12 @@ import (
 type RecommendIndexExec struct {
 	exec.BaseExecutor
 
-	Action   string
-	SQL      string
-	AdviseID int64
-	Options  []ast.RecommendIndexOption
-	done     bool
+	Action    string
+	SQL       string
+	SQLDigest string
+	AdviseID  int64
+	Options   []ast.RecommendIndexOption
+	done      bool
 }
 
 var _ exec.Executor = &RecommendIndexExec{}
@@ -72,7 +73,13 @@ func (e *RecommendIndexExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			return errors.New("empty SQLs")
 		}
 	}
-	results, err := indexadvisor.AdviseIndexes(ctx, e.Ctx(), sqls, e.Options)
+	var results []*indexadvisor.Recommendation
+	var err error
+	if e.SQLDigest != "" {
+		results, err = indexadvisor.AdviseIndexesForDigest(ctx, e.Ctx(), e.SQLDigest, e.Options)
+	} else {
+		results, err = indexadvisor.AdviseIndexes(ctx, e.Ctx(), sqls, e.Options)
+	}
 
 	for _, r := range results {
 		req.AppendString(0, r.Database)
@@ -87,6 +94,8 @@ func (e *RecommendIndexExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			return err
 		}
 		req.AppendString(6, string(jData))
+		req.AppendFloat64(7, r.WorkloadImpact.WorkloadImprovement)
+		req.AppendString(8, r.CreateIndexStmt())
 	}
 	return err
 }
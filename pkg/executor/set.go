@@ -164,7 +164,7 @@ func (e *SetExecutor) setSysVariable(ctx context.Context, name string, v *expres
 			return nil
 		})
 		showValStr := valStr
-		if name == variable.TiDBCloudStorageURI {
+		if name == variable.TiDBCloudStorageURI || name == variable.TiDBPlanReplayerSnapshotStorage {
 			showValStr = ast.RedactURL(showValStr)
 		}
 		logutil.BgLogger().Info("set global var", zap.Uint64("conn", sessionVars.ConnectionID), zap.String("name", name), zap.String("val", showValStr))
@@ -54,6 +54,7 @@ import (
 	field_types "github.com/pingcap/tidb/pkg/parser/types"
 	plannercore "github.com/pingcap/tidb/pkg/planner/core"
 	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/planner/core/operator/logicalop"
 	"github.com/pingcap/tidb/pkg/planner/core/resolve"
 	"github.com/pingcap/tidb/pkg/plugin"
 	"github.com/pingcap/tidb/pkg/privilege"
@@ -114,6 +115,13 @@ type ShowExec struct {
 	Extended    bool // Used for `show extended columns from ...`
 
 	ImportJobID *int64
+
+	// StatsSnapshot is used by `show stats_histograms as of timestamp` to show the historical stats.
+	StatsSnapshot uint64
+	// AllAnalyzeJobs is used by `show analyze status` to fetch all the jobs instead of the latest ones.
+	AllAnalyzeJobs bool
+	// StatsFilter is used by `show stats_buckets` and `show stats_topn` to skip the stats not matched.
+	StatsFilter *logicalop.ShowStatsFilter
 }
 
 type showTableRegionRowItem struct {
@@ -235,6 +243,10 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowStatsBuckets(ctx)
 	case ast.ShowStatsTopN:
 		return e.fetchShowStatsTopN(ctx)
+	case ast.ShowStatsDistribution:
+		return e.fetchShowStatsDistribution(ctx)
+	case ast.ShowStatsIndexes:
+		return e.fetchShowStatsIndexes(ctx)
 	case ast.ShowStatsHealthy:
 		e.fetchShowStatsHealthy(ctx)
 		return nil
@@ -1368,6 +1380,14 @@ func constructResultOfShowCreateTable(ctx sessionctx.Context, dbName *pmodel.CIS
 		fmt.Fprintf(buf, " /*T! PRE_SPLIT_REGIONS=%d */", tableInfo.PreSplitRegions)
 	}
 
+	if tableInfo.StatsOptions != nil && !tableInfo.StatsOptions.AutoRecalc {
+		buf.WriteString(" STATS_AUTO_RECALC=0")
+	}
+
+	if tableInfo.StatsOptions != nil && tableInfo.StatsOptions.AutoAnalyzeCoolDown > 0 {
+		fmt.Fprintf(buf, " /*T![auto_analyze_cool_down] AUTO_ANALYZE_COOL_DOWN=%d */", tableInfo.StatsOptions.AutoAnalyzeCoolDown)
+	}
+
 	if len(tableInfo.Comment) > 0 {
 		fmt.Fprintf(buf, " COMMENT='%s'", format.OutputFormat(tableInfo.Comment))
 	}
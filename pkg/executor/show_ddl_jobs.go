@@ -314,6 +314,12 @@ func showCommentsFromJob(job *model.Job) string {
 			labels = append(labels, model.ReorgTypeTxnMerge.String())
 		}
 	}
+	if job.Type == model.ActionAddVectorIndex && job.SchemaState == model.StateWriteReorganization {
+		// The vector index is built by TiFlash, job.RowCount is the number of rows that have been indexed.
+		if total := job.GetRowCount() + m.VectorIndexPendingRowCount; total > 0 {
+			labels = append(labels, fmt.Sprintf("progress=%.2f%%", float64(job.GetRowCount())*100/float64(total)))
+		}
+	}
 	if job.MayNeedReorg() {
 		concurrency := m.GetConcurrencyOrDefault(int(variable.GetDDLReorgWorkerCounter()))
 		batchSize := m.GetBatchSizeOrDefault(int(variable.GetDDLReorgBatchSize()))
@@ -94,6 +94,21 @@ func TestShowCommentsFromJob(t *testing.T) {
 	}
 	res = showCommentsFromJob(job)
 	require.Equal(t, "ingest, DXF, cloud, service_scope=background", res)
+
+	job.Type = model.ActionAddVectorIndex
+	job.SchemaState = model.StateWriteReorganization
+	job.ReorgMeta = &model.DDLReorgMeta{}
+	res = showCommentsFromJob(job)
+	require.Equal(t, "", res) // TiFlash hasn't reported the progress yet
+
+	job.RowCount = 30
+	job.ReorgMeta.VectorIndexPendingRowCount = 70
+	res = showCommentsFromJob(job)
+	require.Equal(t, "progress=30.00%", res)
+
+	job.SchemaState = model.StatePublic
+	res = showCommentsFromJob(job)
+	require.Equal(t, "", res)
 }
 
 func TestShowCommentsFromSubJob(t *testing.T) {
@@ -15,9 +15,13 @@
 package executor
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"fmt"
+	"math"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/pingcap/errors"
@@ -27,9 +31,14 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/parser/terror"
 	"github.com/pingcap/tidb/pkg/planner/cardinality"
+	"github.com/pingcap/tidb/pkg/planner/core/operator/logicalop"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
 	statsStorage "github.com/pingcap/tidb/pkg/statistics/handle/storage"
+	handleutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tidb/pkg/util/collate"
 	"github.com/tikv/client-go/v2/oracle"
 )
@@ -92,9 +101,27 @@ func (e *ShowExec) appendTableForStatsExtended(dbName string, tbl *model.TableIn
 			statsVal = fmt.Sprintf("%f", item.ScalarVals)
 		case ast.StatsTypeDependency:
 			statsType = "dependency"
-			statsVal = item.StringVals
+			statsVal = fmt.Sprintf("%f", item.ScalarVals)
 		case ast.StatsTypeCardinality:
 			statsType = "cardinality"
+			statsVal = strconv.FormatInt(int64(item.ScalarVals), 10)
+		case ast.StatsTypeJSONPath:
+			statsType = "json_path"
+			statsVal = item.StringVals
+		case ast.StatsTypeForeignKey:
+			statsType = "foreign_key"
+			statsVal = item.StringVals
+		case ast.StatsTypeVector:
+			statsType = "vector"
+			statsVal = item.StringVals
+		case ast.StatsTypeTrigram:
+			statsType = "trigram"
+			statsVal = item.StringVals
+		case ast.StatsTypeHistogram:
+			statsType = "histogram"
+			statsVal = item.StringVals
+		case ast.StatsTypeIndexPrefixTopN:
+			statsType = "index_prefix_topn"
 			statsVal = item.StringVals
 		}
 		e.appendRow([]any{
@@ -241,6 +268,40 @@ func (e *ShowExec) fetchShowStatsLocked(ctx context.Context) error {
 func (e *ShowExec) fetchShowStatsHistogram(ctx context.Context) error {
 	do := domain.GetDomain(e.Ctx())
 	h := do.StatsHandle()
+	getStats := func(tbl *model.TableInfo, physicalID int64) (*statistics.Table, error) {
+		if e.StatsSnapshot == 0 {
+			if physicalID == tbl.ID {
+				return h.GetTableStats(tbl), nil
+			}
+			return h.GetPartitionStats(tbl, physicalID), nil
+		}
+		statsTbl, exist, err := h.HistoricalTableStats(tbl, physicalID, e.StatsSnapshot)
+		if err != nil || !exist {
+			// Tables which have no historical stats before the snapshot are skipped.
+			return nil, err
+		}
+		return statsTbl, nil
+	}
+	appendStats := func(dbName string, tbl *model.TableInfo, partitionName string, physicalID int64) error {
+		statsTbl, err := getStats(tbl, physicalID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if statsTbl != nil {
+			e.appendTableForStatsHistograms(dbName, tbl.Name.O, partitionName, statsTbl)
+		}
+		return nil
+	}
+	if e.StatsSnapshot != 0 {
+		enabled, err := h.CheckHistoricalStatsEnable()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !enabled {
+			e.Ctx().GetSessionVars().StmtCtx.AppendWarning(
+				errors.NewNoStackErrorf("%v is disabled, the historical stats may be missing", variable.TiDBEnableHistoricalStats))
+		}
+	}
 	dbs := do.InfoSchema().AllSchemaNames()
 	for _, db := range dbs {
 		tables, err := do.InfoSchema().SchemaTableInfos(ctx, db)
@@ -254,15 +315,21 @@ func (e *ShowExec) fetchShowStatsHistogram(ctx context.Context) error {
 				if pi != nil {
 					partitionName = "global"
 				}
-				e.appendTableForStatsHistograms(db.O, tbl.Name.O, partitionName, h.GetTableStats(tbl))
+				if err := appendStats(db.O, tbl, partitionName, tbl.ID); err != nil {
+					return err
+				}
 				if pi != nil {
 					for _, def := range pi.Definitions {
-						e.appendTableForStatsHistograms(db.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID))
+						if err := appendStats(db.O, tbl, def.Name.O, def.ID); err != nil {
+							return err
+						}
 					}
 				}
 			} else {
 				for _, def := range pi.Definitions {
-					e.appendTableForStatsHistograms(db.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID))
+					if err := appendStats(db.O, tbl, def.Name.O, def.ID); err != nil {
+						return err
+					}
 				}
 			}
 		}
@@ -318,62 +385,104 @@ func (*ShowExec) versionToTime(version uint64) types.Time {
 	return types.NewTime(types.FromGoTime(t), mysql.TypeDatetime, 0)
 }
 
-func (e *ShowExec) fetchShowStatsBuckets(ctx context.Context) error {
+// statsFilter returns the filter of `show stats_buckets` and `show stats_topn`, which is empty if no name is filtered.
+func (e *ShowExec) statsFilter() logicalop.ShowStatsFilter {
+	if e.StatsFilter == nil {
+		return logicalop.ShowStatsFilter{}
+	}
+	return *e.StatsFilter
+}
+
+// skipByStatsFilter returns whether the name is not matched by the name in the filter of `show stats_buckets` and
+// `show stats_topn`.
+func skipByStatsFilter(name, filtered string) bool {
+	return filtered != "" && strings.ToLower(name) != filtered
+}
+
+// statsColumnFieldTypes returns the field types of the columns of the index, which are used to decode its values.
+// It returns nil if any column is not found, then the values are decoded without the field types.
+func statsColumnFieldTypes(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) []*types.FieldType {
+	fts := make([]*types.FieldType, 0, len(idxInfo.Columns))
+	for _, idxCol := range idxInfo.Columns {
+		colInfo := model.FindColumnInfo(tblInfo.Columns, idxCol.Name.L)
+		if colInfo == nil {
+			return nil
+		}
+		fts = append(fts, &colInfo.FieldType)
+	}
+	return fts
+}
+
+// forEachFilteredStats calls fn with the stats of the tables and partitions matched by the filter of
+// `show stats_buckets` and `show stats_topn`.
+func (e *ShowExec) forEachFilteredStats(ctx context.Context, fn func(dbName string, tblInfo *model.TableInfo, partitionName string, statsTbl *statistics.Table) error) error {
 	do := domain.GetDomain(e.Ctx())
 	h := do.StatsHandle()
+	filter := e.statsFilter()
 	dbs := do.InfoSchema().AllSchemaNames()
 	for _, db := range dbs {
+		if skipByStatsFilter(db.L, filter.DBName) {
+			continue
+		}
 		tables, err := do.InfoSchema().SchemaTableInfos(ctx, db)
 		if err != nil {
 			return errors.Trace(err)
 		}
 		for _, tbl := range tables {
+			if skipByStatsFilter(tbl.Name.L, filter.TableName) {
+				continue
+			}
 			pi := tbl.GetPartitionInfo()
 			if pi == nil || e.Ctx().GetSessionVars().IsDynamicPartitionPruneEnabled() {
 				partitionName := ""
 				if pi != nil {
 					partitionName = "global"
 				}
-				if err := e.appendTableForStatsBuckets(db.O, tbl.Name.O, partitionName, h.GetTableStats(tbl)); err != nil {
-					return err
-				}
-				if pi != nil {
-					for _, def := range pi.Definitions {
-						if err := e.appendTableForStatsBuckets(db.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID)); err != nil {
-							return err
-						}
-					}
-				}
-			} else {
-				for _, def := range pi.Definitions {
-					if err := e.appendTableForStatsBuckets(db.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID)); err != nil {
+				if !skipByStatsFilter(partitionName, filter.PartitionName) {
+					if err := fn(db.O, tbl, partitionName, h.GetTableStats(tbl)); err != nil {
 						return err
 					}
 				}
+				if pi == nil {
+					continue
+				}
+			}
+			for _, def := range pi.Definitions {
+				if skipByStatsFilter(def.Name.L, filter.PartitionName) {
+					continue
+				}
+				if err := fn(db.O, tbl, def.Name.O, h.GetPartitionStats(tbl, def.ID)); err != nil {
+					return err
+				}
 			}
 		}
 	}
 	return nil
 }
 
-func (e *ShowExec) appendTableForStatsBuckets(dbName, tblName, partitionName string, statsTbl *statistics.Table) error {
+func (e *ShowExec) fetchShowStatsBuckets(ctx context.Context) error {
+	return e.forEachFilteredStats(ctx, e.appendTableForStatsBuckets)
+}
+
+func (e *ShowExec) appendTableForStatsBuckets(dbName string, tblInfo *model.TableInfo, partitionName string, statsTbl *statistics.Table) error {
 	if statsTbl.Pseudo {
 		return nil
 	}
-	colNameToType := make(map[string]byte, statsTbl.ColNum())
+	filter := e.statsFilter()
 	for _, col := range statsTbl.StableOrderColSlice() {
-		err := e.bucketsToRows(dbName, tblName, partitionName, col.Info.Name.O, 0, col.Histogram, nil)
+		if skipByStatsFilter(col.Info.Name.L, filter.ColumnName) {
+			continue
+		}
+		err := e.bucketsToRows(dbName, tblInfo.Name.O, partitionName, col.Info.Name.O, 0, col.Histogram, []*types.FieldType{&col.Info.FieldType})
 		if err != nil {
 			return errors.Trace(err)
 		}
-		colNameToType[col.Info.Name.O] = col.Histogram.Tp.GetType()
 	}
 	for _, idx := range statsTbl.StableOrderIdxSlice() {
-		idxColumnTypes := make([]byte, 0, len(idx.Info.Columns))
-		for i := 0; i < len(idx.Info.Columns); i++ {
-			idxColumnTypes = append(idxColumnTypes, colNameToType[idx.Info.Columns[i].Name.O])
+		if skipByStatsFilter(idx.Info.Name.L, filter.ColumnName) {
+			continue
 		}
-		err := e.bucketsToRows(dbName, tblName, partitionName, idx.Info.Name.O, len(idx.Info.Columns), idx.Histogram, idxColumnTypes)
+		err := e.bucketsToRows(dbName, tblInfo.Name.O, partitionName, idx.Info.Name.O, len(idx.Info.Columns), idx.Histogram, statsColumnFieldTypes(tblInfo, idx.Info))
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -382,6 +491,66 @@ func (e *ShowExec) appendTableForStatsBuckets(dbName, tblName, partitionName str
 }
 
 func (e *ShowExec) fetchShowStatsTopN(ctx context.Context) error {
+	return e.forEachFilteredStats(ctx, e.appendTableForStatsTopN)
+}
+
+func (e *ShowExec) appendTableForStatsTopN(dbName string, tblInfo *model.TableInfo, partitionName string, statsTbl *statistics.Table) error {
+	if statsTbl.Pseudo {
+		return nil
+	}
+	filter := e.statsFilter()
+	for _, col := range statsTbl.StableOrderColSlice() {
+		if skipByStatsFilter(col.Info.Name.L, filter.ColumnName) {
+			continue
+		}
+		err := e.topNToRows(dbName, tblInfo.Name.O, partitionName, col.Info.Name.O, 1, 0, col.TopN, []*types.FieldType{&col.Info.FieldType})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, idx := range statsTbl.StableOrderIdxSlice() {
+		if skipByStatsFilter(idx.Info.Name.L, filter.ColumnName) {
+			continue
+		}
+		err := e.topNToRows(dbName, tblInfo.Name.O, partitionName, idx.Info.Name.O, len(idx.Info.Columns), 1, idx.TopN, statsColumnFieldTypes(tblInfo, idx.Info))
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (e *ShowExec) topNToRows(dbName, tblName, partitionName, colName string, numOfCols int, isIndex int, topN *statistics.TopN, fts []*types.FieldType) error {
+	if topN == nil {
+		return nil
+	}
+	var tmpDatum types.Datum
+	for i := 0; i < len(topN.TopN); i++ {
+		tmpDatum.SetBytes(topN.TopN[i].Encoded)
+		valStr, err := statistics.ValueToDisplayString(e.Ctx().GetSessionVars(), &tmpDatum, numOfCols, fts)
+		if err != nil {
+			return err
+		}
+		e.appendRow([]any{
+			dbName,
+			tblName,
+			partitionName,
+			colName,
+			isIndex,
+			valStr,
+			topN.TopN[i].Count,
+		})
+	}
+	return nil
+}
+
+// statsDistributionPercentiles are the percentiles shown by SHOW STATS_DISTRIBUTION.
+var statsDistributionPercentiles = []int{1, 5, 10, 25, 50, 75, 90, 95, 99}
+
+// statsDistributionTopN is the max number of the most frequent values shown by SHOW STATS_DISTRIBUTION.
+const statsDistributionTopN = 10
+
+func (e *ShowExec) fetchShowStatsDistribution(ctx context.Context) error {
 	do := domain.GetDomain(e.Ctx())
 	h := do.StatsHandle()
 	dbs := do.InfoSchema().AllSchemaNames()
@@ -397,19 +566,19 @@ func (e *ShowExec) fetchShowStatsTopN(ctx context.Context) error {
 				if pi != nil {
 					partitionName = "global"
 				}
-				if err := e.appendTableForStatsTopN(db.O, tbl.Name.O, partitionName, h.GetTableStats(tbl)); err != nil {
+				if err := e.appendTableForStatsDistribution(db.O, tbl.Name.O, partitionName, h.GetTableStats(tbl)); err != nil {
 					return err
 				}
 				if pi != nil {
 					for _, def := range pi.Definitions {
-						if err := e.appendTableForStatsTopN(db.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID)); err != nil {
+						if err := e.appendTableForStatsDistribution(db.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID)); err != nil {
 							return err
 						}
 					}
 				}
 			} else {
 				for _, def := range pi.Definitions {
-					if err := e.appendTableForStatsTopN(db.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID)); err != nil {
+					if err := e.appendTableForStatsDistribution(db.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID)); err != nil {
 						return err
 					}
 				}
@@ -419,13 +588,14 @@ func (e *ShowExec) fetchShowStatsTopN(ctx context.Context) error {
 	return nil
 }
 
-func (e *ShowExec) appendTableForStatsTopN(dbName, tblName, partitionName string, statsTbl *statistics.Table) error {
+func (e *ShowExec) appendTableForStatsDistribution(dbName, tblName, partitionName string, statsTbl *statistics.Table) error {
 	if statsTbl.Pseudo {
 		return nil
 	}
 	colNameToType := make(map[string]byte, statsTbl.ColNum())
 	for _, col := range statsTbl.StableOrderColSlice() {
-		err := e.topNToRows(dbName, tblName, partitionName, col.Info.Name.O, 1, 0, col.TopN, []byte{col.Histogram.Tp.GetType()})
+		err := e.distributionToRows(dbName, tblName, partitionName, col.Info.Name.O, 0, &col.Histogram, col.TopN,
+			col.StatsVer, []byte{col.Histogram.Tp.GetType()})
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -436,7 +606,8 @@ func (e *ShowExec) appendTableForStatsTopN(dbName, tblName, partitionName string
 		for i := 0; i < len(idx.Info.Columns); i++ {
 			idxColumnTypes = append(idxColumnTypes, colNameToType[idx.Info.Columns[i].Name.O])
 		}
-		err := e.topNToRows(dbName, tblName, partitionName, idx.Info.Name.O, len(idx.Info.Columns), 1, idx.TopN, idxColumnTypes)
+		err := e.distributionToRows(dbName, tblName, partitionName, idx.Info.Name.O, 1, &idx.Histogram, idx.TopN,
+			idx.StatsVer, idxColumnTypes)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -444,43 +615,161 @@ func (e *ShowExec) appendTableForStatsTopN(dbName, tblName, partitionName string
 	return nil
 }
 
-func (e *ShowExec) topNToRows(dbName, tblName, partitionName, colName string, numOfCols int, isIndex int, topN *statistics.TopN, columnTypes []byte) error {
-	if topN == nil {
-		return nil
+// statsDistributionPoint is a value of the distribution and the number of the rows which are estimated to be
+// between the previous value and it.
+type statsDistributionPoint struct {
+	// key is the memcomparable encoding of the value, by which the values of the histogram and the TopN are sorted.
+	key   []byte
+	value string
+	count int64
+}
+
+// distributionToRows converts the histogram and the TopN of a column or an index to the rows of the min and max
+// values, the percentiles and the most frequent values, in which the values are decoded to be readable.
+func (e *ShowExec) distributionToRows(dbName, tblName, partitionName, colName string, isIndex int,
+	hist *statistics.Histogram, topN *statistics.TopN, statsVer int64, columnTypes []byte) error {
+	vars := e.Ctx().GetSessionVars()
+	points, err := statsDistributionPoints(vars, isIndex, hist, topN, statsVer, columnTypes)
+	if err != nil {
+		return errors.Trace(err)
 	}
-	var tmpDatum types.Datum
-	for i := 0; i < len(topN.TopN); i++ {
-		tmpDatum.SetBytes(topN.TopN[i].Encoded)
-		valStr, err := statistics.ValueToString(e.Ctx().GetSessionVars(), &tmpDatum, numOfCols, columnTypes)
+	if len(points) > 0 {
+		e.appendRow([]any{dbName, tblName, partitionName, colName, isIndex, "min", points[0].value, nil})
+		for i, point := range statsPercentiles(points, statsDistributionPercentiles) {
+			e.appendRow([]any{dbName, tblName, partitionName, colName, isIndex,
+				fmt.Sprintf("p%d", statsDistributionPercentiles[i]), point.value, point.count})
+		}
+		e.appendRow([]any{dbName, tblName, partitionName, colName, isIndex, "max", points[len(points)-1].value, nil})
+	}
+	if hist.NullCount > 0 {
+		e.appendRow([]any{dbName, tblName, partitionName, colName, isIndex, "null", nil, hist.NullCount})
+	}
+	topMetas := statsTopNByCount(topN)
+	for i, meta := range topMetas[:min(len(topMetas), statsDistributionTopN)] {
+		value, err := statsTopNValue(vars, isIndex, meta.Encoded, columnTypes)
 		if err != nil {
-			return err
+			return errors.Trace(err)
 		}
-		e.appendRow([]any{
-			dbName,
-			tblName,
-			partitionName,
-			colName,
-			isIndex,
-			valStr,
-			topN.TopN[i].Count,
-		})
+		e.appendRow([]any{dbName, tblName, partitionName, colName, isIndex, fmt.Sprintf("top%d", i+1), value, meta.Count})
 	}
 	return nil
 }
 
+// statsDistributionPoints converts the histogram and the TopN of a column or an index to the points of the
+// distribution sorted by the values, in which the values are decoded to be readable. The first point is the
+// min value without rows.
+func statsDistributionPoints(vars *variable.SessionVars, isIndex int, hist *statistics.Histogram,
+	topN *statistics.TopN, statsVer int64, columnTypes []byte) ([]statsDistributionPoint, error) {
+	// The bounds of the histogram of a column are the values of the column, while the others are encoded keys.
+	histNumOfCols, histColumnTypes := 0, []byte(nil)
+	if isIndex == 1 {
+		histNumOfCols, histColumnTypes = len(columnTypes), columnTypes
+	}
+	histPoint := func(bound *types.Datum, count int64) (statsDistributionPoint, error) {
+		key := bound.GetBytes()
+		if isIndex == 0 {
+			var err error
+			key, err = codec.EncodeKey(vars.Location(), nil, *bound)
+			if err != nil {
+				return statsDistributionPoint{}, err
+			}
+		}
+		value, err := statistics.ValueToString(vars, bound, histNumOfCols, histColumnTypes)
+		return statsDistributionPoint{key: key, value: value, count: count}, err
+	}
+
+	points := make([]statsDistributionPoint, 0, hist.Len()+topN.Num()+1)
+	if hist.Len() > 0 {
+		// The lower bound of the first bucket is added without rows, so it's shown as the min value.
+		point, err := histPoint(hist.GetLower(0), 0)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	for i := 0; i < hist.Len(); i++ {
+		count := hist.Buckets[i].Count
+		if i > 0 {
+			count -= hist.Buckets[i-1].Count
+		}
+		point, err := histPoint(hist.GetUpper(i), count)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	// The values of the TopN are excluded from the histogram since the stats of version 2.
+	if statsVer >= statistics.Version2 && topN != nil {
+		for _, meta := range topN.TopN {
+			value, err := statsTopNValue(vars, isIndex, meta.Encoded, columnTypes)
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, statsDistributionPoint{key: meta.Encoded, value: value, count: int64(meta.Count)})
+		}
+	}
+	slices.SortStableFunc(points, func(a, b statsDistributionPoint) int {
+		return bytes.Compare(a.key, b.key)
+	})
+	return points, nil
+}
+
+// statsPercentiles returns the points where the percentiles lie, whose counts are the number of the rows up to them.
+// The percentiles are estimated at the granularity of the buckets, i.e. the upper bound of the bucket where the
+// percentile lies is returned.
+func statsPercentiles(points []statsDistributionPoint, percentiles []int) []statsDistributionPoint {
+	var total int64
+	for _, point := range points {
+		total += point.count
+	}
+	result := make([]statsDistributionPoint, 0, len(percentiles))
+	var cumulative int64
+	i := 0
+	for _, percentile := range percentiles {
+		target := int64(math.Ceil(float64(total) * float64(percentile) / 100))
+		for ; i < len(points)-1 && cumulative+points[i].count < target; i++ {
+			cumulative += points[i].count
+		}
+		result = append(result, statsDistributionPoint{key: points[i].key, value: points[i].value, count: cumulative + points[i].count})
+	}
+	return result
+}
+
+// statsTopNValue decodes the value of the TopN to be readable.
+func statsTopNValue(vars *variable.SessionVars, isIndex int, encoded []byte, columnTypes []byte) (string, error) {
+	numOfCols := 1
+	if isIndex == 1 {
+		numOfCols = len(columnTypes)
+	}
+	d := types.NewBytesDatum(encoded)
+	return statistics.ValueToString(vars, &d, numOfCols, columnTypes)
+}
+
+// statsTopNByCount returns the values of the TopN sorted by their counts in descending order.
+func statsTopNByCount(topN *statistics.TopN) []statistics.TopNMeta {
+	if topN == nil {
+		return nil
+	}
+	topMetas := slices.Clone(topN.TopN)
+	slices.SortStableFunc(topMetas, func(a, b statistics.TopNMeta) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	return topMetas
+}
+
 // bucketsToRows converts histogram buckets to rows. If the histogram is built from index, then numOfCols equals to number
-// of index columns, else numOfCols is 0.
-func (e *ShowExec) bucketsToRows(dbName, tblName, partitionName, colName string, numOfCols int, hist statistics.Histogram, idxColumnTypes []byte) error {
+// of index columns, else numOfCols is 0. The bounds are decoded by the field types of the columns.
+func (e *ShowExec) bucketsToRows(dbName, tblName, partitionName, colName string, numOfCols int, hist statistics.Histogram, fts []*types.FieldType) error {
 	isIndex := 0
 	if numOfCols > 0 {
 		isIndex = 1
 	}
 	for i := 0; i < hist.Len(); i++ {
-		lowerBoundStr, err := statistics.ValueToString(e.Ctx().GetSessionVars(), hist.GetLower(i), numOfCols, idxColumnTypes)
+		lowerBoundStr, err := statistics.ValueToDisplayString(e.Ctx().GetSessionVars(), hist.GetLower(i), numOfCols, fts)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		upperBoundStr, err := statistics.ValueToString(e.Ctx().GetSessionVars(), hist.GetUpper(i), numOfCols, idxColumnTypes)
+		upperBoundStr, err := statistics.ValueToDisplayString(e.Ctx().GetSessionVars(), hist.GetUpper(i), numOfCols, fts)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -556,13 +845,70 @@ func (e *ShowExec) appendTableForStatsHealthy(dbName, tblName, partitionName str
 	})
 }
 
+// statsIndexesTable is a table or partition whose indexes are shown by `show stats_indexes`.
+type statsIndexesTable struct {
+	dbName        string
+	tblInfo       *model.TableInfo
+	partitionName string
+	statsTbl      *statistics.Table
+}
+
+func (e *ShowExec) fetchShowStatsIndexes(ctx context.Context) error {
+	var tables []statsIndexesTable
+	err := e.forEachFilteredStats(ctx, func(dbName string, tblInfo *model.TableInfo, partitionName string, statsTbl *statistics.Table) error {
+		if !statsTbl.Pseudo && len(tblInfo.Indices) > 0 {
+			tables = append(tables, statsIndexesTable{dbName: dbName, tblInfo: tblInfo, partitionName: partitionName, statsTbl: statsTbl})
+		}
+		return nil
+	})
+	if err != nil || len(tables) == 0 {
+		return err
+	}
+	h := domain.GetDomain(e.Ctx()).StatsHandle()
+	return handleutil.CallWithSCtx(h.SPool(), func(sctx sessionctx.Context) error {
+		for _, tbl := range tables {
+			metas, err := statsStorage.IndexStatsMetasFromStorage(sctx, tbl.statsTbl.PhysicalID)
+			if err != nil {
+				return err
+			}
+			e.appendTableForStatsIndexes(tbl, metas)
+		}
+		return nil
+	})
+}
+
+// appendTableForStatsIndexes appends the stats meta and the usage of the indexes of the table or partition. The usage
+// is collected on the logical tables, so the partitions of a table share the usage of its indexes.
+func (e *ShowExec) appendTableForStatsIndexes(tbl statsIndexesTable, metas map[int64]*statistics.IndexStatsMeta) {
+	h := domain.GetDomain(e.Ctx()).StatsHandle()
+	for _, idx := range tbl.tblInfo.Indices {
+		if idx.State != model.StatePublic {
+			continue
+		}
+		row := []any{tbl.dbName, tbl.tblInfo.Name.O, tbl.partitionName, idx.Name.O}
+		if meta, ok := metas[idx.ID]; ok && meta.IsAnalyzed() {
+			row = append(row, meta.Version, e.versionToTime(meta.Version), meta.ModifyCount(tbl.statsTbl), meta.Healthy(tbl.statsTbl))
+		} else {
+			row = append(row, nil, nil, nil, int64(0))
+		}
+		usage := h.GetIndexUsage(tbl.tblInfo.ID, idx.ID)
+		row = append(row, usage.QueryTotal)
+		if usage.LastUsedAt.IsZero() {
+			row = append(row, nil)
+		} else {
+			row = append(row, types.NewTime(types.FromGoTime(usage.LastUsedAt), mysql.TypeDatetime, 0))
+		}
+		e.appendRow(row)
+	}
+}
+
 func (e *ShowExec) fetchShowHistogramsInFlight() {
 	statsHandle := domain.GetDomain(e.Ctx()).StatsHandle()
 	e.appendRow([]any{statsStorage.CleanFakeItemsForShowHistInFlights(statsHandle)})
 }
 
 func (e *ShowExec) fetchShowAnalyzeStatus(ctx context.Context) error {
-	rows, err := dataForAnalyzeStatusHelper(ctx, e.BaseExecutor.Ctx())
+	rows, err := dataForAnalyzeStatusHelper(ctx, e.BaseExecutor.Ctx(), e.AllAnalyzeJobs)
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,13 @@
 package executor_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
@@ -147,6 +152,119 @@ func TestShowStatsBuckets(t *testing.T) {
 	result.Check(testkit.Rows("test t  idx 1 0 1 1 (2020-01-01 00:00:00, 1) (2020-01-01 00:00:00, 1) 0"))
 }
 
+func TestShowStatsBucketsAndTopNWithFilter(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version=1")
+	tk.MustExec("set @@tidb_partition_prune_mode='static'")
+	tk.MustExec("create table t (a int, b varchar(10) collate utf8mb4_general_ci, c datetime, key ib(b), key ic(c)) partition by hash(a) partitions 4")
+	tk.MustExec("insert into t values (0, 'abc', '2024-01-01'), (1, 'Def', '2024-01-02'), (1, 'def', '2024-01-02'), (2, 'ghi', '2024-01-03'), (3, 'jkl', '2024-01-04')")
+	tk.MustExec("analyze table t index ib, ic with 0 topn")
+	// The collation keys and the time values of the indexes are decoded, though the stats of the columns are not built.
+	tk.MustQuery("show stats_buckets where table_name = 't' and partition_name = 'p1'").Sort().Check(testkit.Rows(
+		"test t p1 ib 1 0 2 2 DEF DEF 0",
+		"test t p1 ic 1 0 2 2 2024-01-02 00:00:00 2024-01-02 00:00:00 0",
+	))
+	tk.MustQuery("show stats_buckets where db_name = 'test' and column_name = 'ib' and partition_name = 'p2'").Check(testkit.Rows(
+		"test t p2 ib 1 0 1 1 GHI GHI 0",
+	))
+	require.Len(t, tk.MustQuery("show stats_buckets where table_name = 't'").Rows(), 8)
+	require.Len(t, tk.MustQuery("show stats_buckets where table_name = 't' limit 3").Rows(), 3)
+	require.Len(t, tk.MustQuery("show stats_buckets where table_name = 't' limit 6, 10").Rows(), 2)
+	require.Len(t, tk.MustQuery("show stats_buckets where table_name = 't' and partition_name = 'global'").Rows(), 0)
+	require.Len(t, tk.MustQuery("show stats_buckets where table_name = 't' and column_name = 'ib' and column_name = 'ic'").Rows(), 0)
+
+	tk.MustExec("set @@tidb_analyze_version=2")
+	tk.MustExec("analyze table t all columns with 1 topn")
+	tk.MustQuery("show stats_topn where table_name = 't' and partition_name = 'p1' and column_name in ('b', 'c', 'ib')").Sort().Check(testkit.Rows(
+		"test t p1 b 0 DEF 2",
+		"test t p1 c 0 2024-01-02 00:00:00 2",
+		"test t p1 ib 1 DEF 2",
+	))
+	require.Len(t, tk.MustQuery("show stats_topn where table_name = 't' and partition_name = 'p1' limit 1").Rows(), 1)
+}
+
+func TestShowStatsDistribution(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version=2")
+	tk.MustExec("create table t (a int, b int, index ia(a))")
+	tk.MustExec("insert into t values (1, 1), (1, 1), (1, 1), (1, 1), (1, 1), (1, 1), (1, 1), (1, 1), (1, 1), (1, 1), (null, null)")
+	tk.MustExec("insert into t values (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8), (9, 9), (10, 10), (11, 11)")
+	tk.MustExec("analyze table t with 1 topn, 4 buckets")
+	// The value 1 is in the TopN, which is merged with the histogram to get the percentiles.
+	tk.MustQuery("show stats_distribution where table_name = 't' and column_name = 'a' and kind in ('min', 'p50', 'p99', 'max', 'null', 'top1')").Check(testkit.Rows(
+		"test t  a 0 min 1 <nil>",
+		"test t  a 0 p50 1 10",
+		"test t  a 0 p99 11 20",
+		"test t  a 0 max 11 <nil>",
+		"test t  a 0 null <nil> 1",
+		"test t  a 0 top1 1 10",
+	))
+	tk.MustQuery("show stats_distribution where table_name = 't' and column_name = 'ia' and kind in ('min', 'max')").Check(testkit.Rows(
+		"test t  ia 1 min 1 <nil>",
+		"test t  ia 1 max 11 <nil>",
+	))
+	require.Len(t, tk.MustQuery("show stats_distribution where table_name = 't' and column_name = 'b' and kind like 'p%'").Rows(), 9)
+}
+
+func TestShowStatsIndexes(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	h := dom.StatsHandle()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version=1")
+	tk.MustExec("create table t (a int, b int, index ia(a), index ib(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	// The indexes which have never been analyzed have no stats version.
+	tk.MustQuery("show stats_indexes where table_name = 't'").Check(testkit.Rows(
+		"test t  ia <nil> <nil> <nil> 0 0 <nil>",
+		"test t  ib <nil> <nil> <nil> 0 0 <nil>",
+	))
+	tk.MustExec("analyze table t")
+	tk.MustExec("insert into t values (5, 5), (6, 6)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	// Only ia is analyzed, so the modifications before the analysis are carried over to ib.
+	tk.MustExec("analyze table t index ia")
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	rows := tk.MustQuery("show stats_indexes where table_name = 't'").Rows()
+	require.Len(t, rows, 2)
+	require.Equal(t, "ia", rows[0][3])
+	require.Equal(t, "0", rows[0][6])
+	require.Equal(t, "100", rows[0][7])
+	require.Equal(t, "ib", rows[1][3])
+	require.Equal(t, "2", rows[1][6])
+	healthy, err := strconv.Atoi(rows[1][7].(string))
+	require.NoError(t, err)
+	require.Less(t, healthy, 100)
+	iaVersion, err := strconv.ParseUint(rows[0][4].(string), 10, 64)
+	require.NoError(t, err)
+	ibVersion, err := strconv.ParseUint(rows[1][4].(string), 10, 64)
+	require.NoError(t, err)
+	require.Greater(t, iaVersion, ibVersion)
+
+	// The usage of the indexes is collected from the queries.
+	tk.MustQuery("select * from t use index(ia) where a = 1").Check(testkit.Rows("1 1"))
+	require.Eventually(t, func() bool {
+		tk.Session().ReportUsageStats()
+		rows := tk.MustQuery("show stats_indexes where table_name = 't' and index_name = 'ia'").Rows()
+		return len(rows) == 1 && rows[0][8] == "1" && rows[0][9] != "<nil>"
+	}, 5*time.Second, 10*time.Millisecond)
+	tk.MustQuery("show stats_indexes where table_name = 't' and index_name = 'ib'").CheckAt([]int{8, 9}, testkit.Rows("0 <nil>"))
+
+	// The modifications are not carried over to the indexes covered by the analysis.
+	tk.MustExec("analyze table t")
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	tk.MustQuery("show stats_indexes where table_name = 't'").CheckAt([]int{3, 6, 7}, testkit.Rows("ia 0 100", "ib 0 100"))
+}
+
 func TestShowStatsBucketWithDateNullValue(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -468,3 +586,106 @@ func TestShowAnalyzeStatus(t *testing.T) {
 	require.Len(t, rows, 2)
 	require.Equal(t, "merge global stats for test.t2's index idx", rows[0][3])
 }
+
+func TestShowAnalyzeStatusWithFilter(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("delete from mysql.analyze_jobs")
+	// The failed jobs of another instance are older than the latest 30 jobs.
+	for i := 0; i < 5; i++ {
+		tk.MustExec(fmt.Sprintf("insert into mysql.analyze_jobs (update_time, table_schema, table_name, job_info, state, fail_reason, instance) "+
+			"values ('2024-01-01 00:00:%02d', 'test', 't%d', 'analyze table', 'failed', 'mock error', '10.0.0.2:4000')", i, i))
+	}
+	for i := 0; i < 40; i++ {
+		tk.MustExec(fmt.Sprintf("insert into mysql.analyze_jobs (update_time, table_schema, table_name, job_info, state, instance) "+
+			"values ('2024-01-02 00:00:%02d', 'test', 't%d', 'analyze table', 'finished', '10.0.0.1:4000')", i, i))
+	}
+	require.Len(t, tk.MustQuery("show analyze status").Rows(), 30)
+	// The filter and the limit apply to the jobs of all the instances.
+	rows := tk.MustQuery("show analyze status where instance = '10.0.0.2:4000'").Rows()
+	require.Len(t, rows, 5)
+	for _, row := range rows {
+		require.Equal(t, "failed", row[7])
+		require.Equal(t, "mock error", row[8])
+	}
+	require.Len(t, tk.MustQuery("show analyze status where state = 'failed' limit 2").Rows(), 2)
+	require.Len(t, tk.MustQuery("show analyze status like 'test'").Rows(), 45)
+	rows = tk.MustQuery("show analyze status limit 40, 10").Rows()
+	require.Len(t, rows, 5)
+	require.Equal(t, "10.0.0.2:4000", rows[0][9])
+}
+
+func TestAdminExportStats(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b varchar(10))")
+	tk.MustExec("insert into t values (1, 'x'), (2, 'x'), (3, null), (3, 'y'), (null, 'x')")
+	tk.MustExec("analyze table t all columns with 0 topn")
+
+	dir := t.TempDir()
+	tk.MustExec(fmt.Sprintf("admin export stats to 'local://%s' format csv", dir))
+	f, err := os.Open(filepath.Join(dir, "stats.csv"))
+	require.NoError(t, err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, "db_name", records[0][0])
+	require.Equal(t, "topn", records[0][len(records[0])-1])
+	require.Equal(t, uint64(len(records)-1), tk.Session().GetSessionVars().StmtCtx.AffectedRows())
+	var rows [][]string
+	for _, record := range records[1:] {
+		if record[0] == "test" && record[1] == "t" {
+			rows = append(rows, record)
+		}
+	}
+	require.Len(t, rows, 2)
+	// db_name, table_name, partition_name, column_name, row_count, ndv, null_count, null_ratio, min, ..., max, topn
+	require.Equal(t, []string{"test", "t", "", "a", "5", "3", "1", "0.2", "1"}, rows[0][:9])
+	require.Equal(t, "3", rows[0][len(rows[0])-2])
+	require.Equal(t, []string{"test", "t", "", "b", "5", "2", "1", "0.2", "x"}, rows[1][:9])
+	require.Equal(t, "y", rows[1][len(rows[1])-2])
+
+	file := filepath.Join(dir, "t.parquet")
+	tk.MustExec(fmt.Sprintf("admin export stats to 'local://%s' format parquet", file))
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(content, []byte("PAR1")))
+
+	tk.MustGetErrMsg("admin export stats to 'stats.csv' format csv", "Export Stats: invalid URI stats.csv")
+}
+
+func TestAdminCheckStats(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b varchar(10))")
+	tk.MustGetErrMsg("admin check stats t", "the stats of table test.t are not analyzed")
+	tk.MustExec("insert into t values (1, 'x'), (2, 'x'), (3, null), (3, 'y'), (null, 'X')")
+	tk.MustExec("analyze table t all columns with 0 topn")
+
+	// The whole table is sampled, so the stats don't drift.
+	tk.MustQuery("admin check stats t").Check(testkit.Rows(
+		"test t a 5 5 3 3 0.2 0.2 0 0",
+		"test t b 5 5 3 3 0.2 0.2 0 0",
+	))
+	tk.MustQuery("admin check stats t sample 2").CheckAt([]int{2, 4}, testkit.Rows("a 5", "b 5"))
+
+	// The new rows are not analyzed yet, so the row count and the distribution drift.
+	tk.MustExec("insert into t values (10, 'z'), (10, 'z'), (10, 'z'), (10, 'z'), (10, 'z')")
+	rows := tk.MustQuery("admin check stats t threshold 0.3").Rows()
+	require.Len(t, rows, 2)
+	for _, row := range rows {
+		require.Equal(t, []any{"5", "10"}, row[3:5])
+		score, err := strconv.ParseFloat(row[10].(string), 64)
+		require.NoError(t, err)
+		require.Greater(t, score, 0.5)
+	}
+	// The auto analyze priority queue isn't initialized in the test, so the table fails to be enqueued.
+	tk.MustQuery("show warnings").CheckContain("failed to enqueue table test.t for auto analyze")
+	// Initialize the priority queue.
+	dom.StatsHandle().HandleAutoAnalyze()
+	tk.MustQuery("admin check stats t threshold 0.3")
+	tk.MustQuery("show warnings").CheckContain("table test.t is enqueued for auto analyze")
+	tk.MustGetErrMsg("admin check stats t threshold 0", "THRESHOLD of ADMIN CHECK STATS should be positive")
+}
@@ -168,6 +168,8 @@ func (e *SimpleExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 		err = e.executeSetPwd(ctx, x)
 	case *ast.SetSessionStatesStmt:
 		err = e.executeSetSessionStates(ctx, x)
+	case *ast.ResetSessionStatesStmt:
+		err = e.Ctx().ResetSessionStates(ctx)
 	case *ast.KillStmt:
 		err = e.executeKillStmt(ctx, x)
 	case *ast.BinlogStmt:
@@ -175,6 +177,8 @@ func (e *SimpleExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 		return nil
 	case *ast.DropStatsStmt:
 		err = e.executeDropStats(ctx, x)
+	case *ast.AnalyzeTableStmt:
+		err = e.executeDropHistogram(ctx, x)
 	case *ast.SetRoleStmt:
 		err = e.executeSetRole(x)
 	case *ast.RevokeRoleStmt:
@@ -184,7 +188,7 @@ func (e *SimpleExec) Next(ctx context.Context, _ *chunk.Chunk) (err error) {
 	case *ast.ShutdownStmt:
 		err = e.executeShutdown()
 	case *ast.AdminStmt:
-		err = e.executeAdmin(x)
+		err = e.executeAdmin(ctx, x)
 	case *ast.SetResourceGroupStmt:
 		err = e.executeSetResourceGroupName(x)
 	case *ast.AlterRangeStmt:
@@ -2771,6 +2775,29 @@ func (e *SimpleExec) executeDropStats(ctx context.Context, s *ast.DropStatsStmt)
 	return h.Update(ctx, e.Ctx().GetInfoSchema().(infoschema.InfoSchema))
 }
 
+// executeDropHistogram deletes the stats of the columns for ANALYZE TABLE ... DROP HISTOGRAM ON ....
+func (e *SimpleExec) executeDropHistogram(ctx context.Context, s *ast.AnalyzeTableStmt) error {
+	tblInfo := e.ResolveCtx.GetTableName(s.TableNames[0]).TableInfo
+	statsIDs, _, err := core.GetPhysicalIDsAndPartitionNames(tblInfo, nil)
+	if err != nil {
+		return err
+	}
+	if tblInfo.GetPartitionInfo() != nil {
+		statsIDs = append(statsIDs, tblInfo.ID)
+	}
+	colIDs := make([]int64, 0, len(s.ColumnNames))
+	for _, colName := range s.ColumnNames {
+		if colInfo := tblInfo.FindPublicColumnByName(colName.L); colInfo != nil {
+			colIDs = append(colIDs, colInfo.ID)
+		}
+	}
+	h := domain.GetDomain(e.Ctx()).StatsHandle()
+	if err := h.DeleteColumnStatsFromKV(statsIDs, colIDs); err != nil {
+		return err
+	}
+	return h.Update(ctx, e.Ctx().GetInfoSchema().(infoschema.InfoSchema))
+}
+
 func (e *SimpleExec) autoNewTxn() bool {
 	// Some statements cause an implicit commit
 	// See https://dev.mysql.com/doc/refman/5.7/en/implicit-commit.html
@@ -2841,7 +2868,7 @@ func (e *SimpleExec) executeSetSessionStates(ctx context.Context, s *ast.SetSess
 	return e.Ctx().DecodeSessionStates(ctx, e.Ctx(), &sessionStates)
 }
 
-func (e *SimpleExec) executeAdmin(s *ast.AdminStmt) error {
+func (e *SimpleExec) executeAdmin(ctx context.Context, s *ast.AdminStmt) error {
 	switch s.Tp {
 	case ast.AdminReloadStatistics:
 		return e.executeAdminReloadStatistics(s)
@@ -2851,6 +2878,12 @@ func (e *SimpleExec) executeAdmin(s *ast.AdminStmt) error {
 		return e.executeAdminSetBDRRole(s)
 	case ast.AdminUnsetBDRRole:
 		return e.executeAdminUnsetBDRRole()
+	case ast.AdminRemoveAnalyzeJob:
+		return e.executeAdminRemoveAnalyzeJob(s)
+	case ast.AdminResetColumnStatsUsage:
+		return e.executeAdminResetColumnStatsUsage(s)
+	case ast.AdminExportStats:
+		return e.executeAdminExportStats(ctx, s)
 	}
 	return nil
 }
@@ -2907,6 +2940,38 @@ func (e *SimpleExec) executeAdminUnsetBDRRole() error {
 	return errors.Trace(meta.NewMutator(txn).ClearBDRRole())
 }
 
+func (e *SimpleExec) executeAdminRemoveAnalyzeJob(s *ast.AdminStmt) error {
+	tnW := e.ResolveCtx.GetTableName(s.Tables[0])
+	physicalIDs, _, err := core.GetPhysicalIDsAndPartitionNames(tnW.TableInfo, nil)
+	if err != nil {
+		return err
+	}
+	physicalIDs = append(physicalIDs, tnW.TableInfo.ID)
+	removed, err := domain.GetDomain(e.Ctx()).StatsHandle().RemoveAnalysisJobs(physicalIDs)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		e.Ctx().GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("no pending analyze job for table %s.%s", tnW.Schema.O, tnW.Name.O))
+	}
+	return nil
+}
+
+func (e *SimpleExec) executeAdminResetColumnStatsUsage(s *ast.AdminStmt) error {
+	tnW := e.ResolveCtx.GetTableName(s.Tables[0])
+	physicalIDs, _, err := core.GetPhysicalIDsAndPartitionNames(tnW.TableInfo, nil)
+	if err != nil {
+		return err
+	}
+	physicalIDs = append(physicalIDs, tnW.TableInfo.ID)
+	h := domain.GetDomain(e.Ctx()).StatsHandle()
+	// Dump the usage collected in memory first, otherwise it would be written back after the reset.
+	if err := h.DumpColStatsUsageToKV(); err != nil {
+		return err
+	}
+	return h.ResetColumnStatsUsage(physicalIDs)
+}
+
 func (e *SimpleExec) executeSetResourceGroupName(s *ast.SetResourceGroupStmt) error {
 	var name string
 	if s.Name.L != "" {
@@ -16,6 +16,7 @@ package admintest
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"regexp"
@@ -2140,3 +2141,69 @@ func TestAdminCheckGeneratedColumns(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestAdminShowPlanDiff(t *testing.T) {
+	testfailpoint.Enable(t, "github.com/pingcap/tidb/pkg/executor/mockPlanDiffSummaryTable", `return("statements_summary_history")`)
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database plan_diff")
+	tk.MustExec("use plan_diff")
+	tk.MustExec("create table t(a int, b int, key ia(a), key ib(b))")
+	tk.MustExec("select * from t where a = 1")
+	tk.MustExec("select * from t where b = 1")
+	// The plan of the first query changes after dropping the index.
+	tk.MustExec("alter table t drop index ia")
+
+	tk.MustExec("use test")
+	changed := make(map[string]any)
+	for _, row := range tk.MustQuery("admin show plan diff").Rows() {
+		if row[1] != "plan_diff" {
+			continue
+		}
+		require.Equal(t, "", row[9])
+		require.NotEmpty(t, row[8])
+		changed[row[2].(string)] = row[6]
+	}
+	require.Equal(t, map[string]any{
+		"select * from t where a = 1": "1",
+		"select * from t where b = 1": "0",
+	}, changed)
+	require.Len(t, tk.MustQuery("admin show plan diff limit 1").Rows(), 1)
+	// The current database is kept.
+	tk.MustQuery("select database()").Check(testkit.Rows("test"))
+}
+
+func TestAdminCalibrateCostModel(t *testing.T) {
+	testfailpoint.Enable(t, "github.com/pingcap/tidb/pkg/executor/mockCalibrateCostModelRows", `return(1000)`)
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	defer tk.MustExec("set global tidb_cost_model_v2_calibrated_factors = ''")
+
+	rows := tk.MustQuery("admin calibrate cost model").Rows()
+	benchmarks := make(map[string]any, len(rows))
+	calibrated := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		benchmarks[row[0].(string)] = row[1]
+		defaultValue, err := strconv.ParseFloat(row[2].(string), 64)
+		require.NoError(t, err)
+		value, err := strconv.ParseFloat(row[3].(string), 64)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, value, defaultValue/100)
+		require.LessOrEqual(t, value, defaultValue*100)
+		if row[4] == "" {
+			calibrated[row[0].(string)] = value
+		}
+	}
+	require.Equal(t, map[string]any{
+		"tikv_scan_factor":    "scan",
+		"tidb_kv_net_factor":  "exchange",
+		"tidb_request_factor": "lookup",
+		"tidb_cpu_factor":     "hash join",
+	}, benchmarks)
+
+	// The calibrated factors are stored, and the table of the benchmarks is dropped.
+	var stored map[string]float64
+	require.NoError(t, json.Unmarshal([]byte(tk.MustQuery("select @@global.tidb_cost_model_v2_calibrated_factors").Rows()[0][0].(string)), &stored))
+	require.Equal(t, calibrated, stored)
+	tk.MustQuery("show tables in mysql like 'cost_model_calibration'").Check(testkit.Rows())
+}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/config"
 	"github.com/pingcap/tidb/pkg/domain"
 	"github.com/pingcap/tidb/pkg/domain/infosync"
 	"github.com/pingcap/tidb/pkg/errno"
@@ -39,6 +40,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
+	handleutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/pingcap/tidb/pkg/testkit/analyzehelper"
 	"github.com/pingcap/tidb/pkg/util/dbterror/exeerrors"
@@ -556,16 +558,16 @@ func TestIssue20874(t *testing.T) {
 	tk.MustExec("set @@tidb_analyze_version=1")
 	tk.MustExec("analyze table t")
 	tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't'").Sort().Check(testkit.Rows(
-		"test t  a 0 0 1 1 \x02\xd2 \x02\xd2 0",
-		"test t  a 0 1 2 1 \x0e\x0f \x0e\x0f 0",
-		"test t  a 0 2 3 1 \x0e3 \x0e3 0",
-		"test t  b 0 0 1 1 \x00A \x00A 0",
-		"test t  b 0 1 3 2 \x00C \x00C 0",
-		"test t  idxa 1 0 1 1 \x02\xd2 \x02\xd2 0",
-		"test t  idxa 1 1 2 1 \x0e\x0f \x0e\x0f 0",
-		"test t  idxa 1 2 3 1 \x0e3 \x0e3 0",
-		"test t  idxb 1 0 1 1 \x00A \x00A 0",
-		"test t  idxb 1 1 3 2 \x00C \x00C 0",
+		"test t  a 0 0 1 1 0x02D2 0x02D2 0",
+		"test t  a 0 1 2 1 0x0E0F 0x0E0F 0",
+		"test t  a 0 2 3 1 0x0E33 0x0E33 0",
+		"test t  b 0 0 1 1 A A 0",
+		"test t  b 0 1 3 2 C C 0",
+		"test t  idxa 1 0 1 1 0x02D2 0x02D2 0",
+		"test t  idxa 1 1 2 1 0x0E0F 0x0E0F 0",
+		"test t  idxa 1 2 3 1 0x0E33 0x0E33 0",
+		"test t  idxb 1 0 1 1 A A 0",
+		"test t  idxb 1 1 3 2 C C 0",
 	))
 	tk.MustQuery("select is_index, hist_id, distinct_count, null_count, tot_col_size, stats_ver, correlation from mysql.stats_histograms").Sort().Check(testkit.Rows(
 		"0 1 3 0 9 1 1",
@@ -576,22 +578,22 @@ func TestIssue20874(t *testing.T) {
 	tk.MustExec("set @@tidb_analyze_version=2")
 	tk.MustExec("analyze table t")
 	tk.MustQuery("show stats_topn where db_name = 'test' and table_name = 't'").Sort().Check(testkit.Rows(
-		"test t  a 0 \x02\xd2 1",
-		"test t  a 0 \x0e\x0f 1",
-		"test t  a 0 \x0e3 1",
-		"test t  b 0 \x00A 1",
-		"test t  b 0 \x00C 2",
-		"test t  idxa 1 \x02\xd2 1",
-		"test t  idxa 1 \x0e\x0f 1",
-		"test t  idxa 1 \x0e3 1",
-		"test t  idxb 1 \x00A 1",
-		"test t  idxb 1 \x00C 2",
+		"test t  a 0 0x02D2 1",
+		"test t  a 0 0x0E0F 1",
+		"test t  a 0 0x0E33 1",
+		"test t  b 0 A 1",
+		"test t  b 0 C 2",
+		"test t  idxa 1 0x02D2 1",
+		"test t  idxa 1 0x0E0F 1",
+		"test t  idxa 1 0x0E33 1",
+		"test t  idxb 1 A 1",
+		"test t  idxb 1 C 2",
 	))
 	tk.MustQuery("select is_index, hist_id, distinct_count, null_count, tot_col_size, stats_ver, correlation from mysql.stats_histograms").Sort().Check(testkit.Rows(
 		"0 1 3 0 6 2 1",
 		"0 2 2 0 6 2 -0.5",
-		"1 1 3 0 6 2 0",
-		"1 2 2 0 6 2 0",
+		"1 1 3 0 6 2 1",
+		"1 2 2 0 6 2 -0.5",
 	))
 }
 
@@ -1262,7 +1264,7 @@ func TestAnalyzeColumnsWithIndex(t *testing.T) {
 					"0 2 5 0 9 2 1",
 					"0 3 4 1 8 2 -0.07",
 					"0 4 5 0 9 2 1",
-					"1 1 6 0 18 2 0"))
+					"1 1 6 0 18 2 1"))
 			tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't'").Sort().Check(
 				// db, tbl, part, col, is_index, bucket_id, count, repeats, lower, upper, ndv
 				testkit.Rows("test t  b 0 0 2 1 2 3 0",
@@ -1338,7 +1340,7 @@ func TestAnalyzeColumnsWithClusteredIndex(t *testing.T) {
 					"0 2 9 0 9 2 1",
 					"0 3 4 1 8 2 -0.07",
 					"0 4 9 0 9 2 1",
-					"1 1 9 0 18 2 0"))
+					"1 1 9 0 18 2 1"))
 			tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't'").Sort().Check(
 				// db, tbl, part, col, is_index, bucket_id, count, repeats, lower, upper, ndv
 				testkit.Rows("test t  PRIMARY 1 0 4 1 (3, 3) (6, 6) 0",
@@ -1466,11 +1468,11 @@ func TestAnalyzeColumnsWithDynamicPartitionTable(t *testing.T) {
 					fmt.Sprintf("%d 0 1 5 1 8 2 1", p0ID),    // p0, a
 					fmt.Sprintf("%d 0 2 0 0 8 0 0", p0ID),    // p0, b, not analyzed
 					fmt.Sprintf("%d 0 3 6 0 9 2 1", p0ID),    // p0, c
-					fmt.Sprintf("%d 1 1 6 0 9 2 0", p0ID),    // p0, idx
+					fmt.Sprintf("%d 1 1 6 0 9 2 1", p0ID),    // p0, idx
 					fmt.Sprintf("%d 0 1 7 0 11 2 1", p1ID),   // p1, a
 					fmt.Sprintf("%d 0 2 0 0 11 0 0", p1ID),   // p1, b, not analyzed
 					fmt.Sprintf("%d 0 3 8 0 11 2 1", p1ID),   // p1, c
-					fmt.Sprintf("%d 1 1 8 0 11 2 0", p1ID),   // p1, idx
+					fmt.Sprintf("%d 1 1 8 0 11 2 1", p1ID),   // p1, idx
 				))
 		}(val)
 	}
@@ -1571,11 +1573,11 @@ func TestAnalyzeColumnsWithStaticPartitionTable(t *testing.T) {
 				testkit.Rows(fmt.Sprintf("%d 0 1 5 1 8 2 1", p0ID), // p0, a
 					fmt.Sprintf("%d 0 2 0 0 8 0 0", p0ID),  // p0, b, not analyzed
 					fmt.Sprintf("%d 0 3 6 0 9 2 1", p0ID),  // p0, c
-					fmt.Sprintf("%d 1 1 6 0 9 2 0", p0ID),  // p0, idx
+					fmt.Sprintf("%d 1 1 6 0 9 2 1", p0ID),  // p0, idx
 					fmt.Sprintf("%d 0 1 7 0 11 2 1", p1ID), // p1, a
 					fmt.Sprintf("%d 0 2 0 0 11 0 0", p1ID), // p1, b, not analyzed
 					fmt.Sprintf("%d 0 3 8 0 11 2 1", p1ID), // p1, c
-					fmt.Sprintf("%d 1 1 8 0 11 2 0", p1ID), // p1, idx
+					fmt.Sprintf("%d 1 1 8 0 11 2 1", p1ID), // p1, idx
 				))
 		}(val)
 	}
@@ -1707,7 +1709,7 @@ func TestAnalyzeColumnsWithVirtualColumnIndex(t *testing.T) {
 				testkit.Rows("0 1 0 0 0 0", // column a is not analyzed
 					"0 2 5 1 2 1",
 					"0 3 0 0 0 0", // column c is not analyzed
-					"1 1 5 1 2 0"))
+					"1 1 5 1 2 1"))
 			tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't'").Sort().Check(
 				// db, tbl, part, col, is_index, bucket_id, count, repeats, lower, upper, ndv
 				testkit.Rows("test t  b 0 0 2 1 1 2 0",
@@ -2032,6 +2034,87 @@ func TestKillAutoAnalyzeIndex(t *testing.T) {
 	}
 }
 
+func TestKillAnalyzeKeepsFinishedPartitions(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("set @@tidb_build_stats_concurrency = 1")
+	tk.MustExec("set @@tidb_analyze_partition_concurrency = 1")
+	tk.MustExec("create table t (a int, b int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	tk.MustExec("insert into t values (1,1), (2,2), (11,11), (12,12)")
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	pi := tbl.Meta().GetPartitionInfo()
+
+	fp := "github.com/pingcap/tidb/pkg/executor/mockKillAfterAnalyzeResultReceived"
+	require.NoError(t, failpoint.Enable(fp, "1*return"))
+	defer func() {
+		require.NoError(t, failpoint.Disable(fp))
+	}()
+	err = tk.ExecToErr("analyze table t all columns")
+	require.True(t, exeerrors.ErrQueryInterrupted.Equal(err), err)
+	tk.Session().GetSessionVars().SQLKiller.Reset()
+
+	// The stats of the first partition are kept, and the job of the second one is marked as failed.
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_histograms where table_id = %d", pi.Definitions[0].ID)).Check(testkit.Rows("2"))
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_histograms where table_id = %d", pi.Definitions[1].ID)).Check(testkit.Rows("0"))
+	rows := tk.MustQuery("show analyze status where table_name = 't' and partition_name = 'p0'").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, statistics.AnalyzeFinished, rows[0][7])
+	rows = tk.MustQuery("show analyze status where table_name = 't' and partition_name = 'p1'").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, statistics.AnalyzeFailed, rows[0][7])
+	require.Contains(t, rows[0][8], "partially analyzed, 1 of 2 jobs finished")
+	require.Contains(t, rows[0][8], exeerrors.ErrQueryInterrupted.Error())
+}
+
+func TestAnalyzeResumeFromCheckpoint(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("set @@tidb_build_stats_concurrency = 1")
+	tk.MustExec("set @@tidb_analyze_partition_concurrency = 1")
+	tk.MustExec("create table t (a int, b int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20), partition p2 values less than (30))")
+	tk.MustExec("insert into t values (1,1), (2,2), (11,11), (12,12), (21,21)")
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	pi := tbl.Meta().GetPartitionInfo()
+
+	fp := "github.com/pingcap/tidb/pkg/executor/mockKillAfterAnalyzeResultReceived"
+	require.NoError(t, failpoint.Enable(fp, "1*return"))
+	err = tk.ExecToErr("analyze table t all columns")
+	require.NoError(t, failpoint.Disable(fp))
+	require.True(t, exeerrors.ErrQueryInterrupted.Equal(err), err)
+	tk.Session().GetSessionVars().SQLKiller.Reset()
+	tk.MustQuery("select partition_id from mysql.analyze_checkpoints").Check(testkit.Rows(strconv.FormatInt(pi.Definitions[0].ID, 10)))
+
+	// A different statement doesn't use the checkpoints of the interrupted one.
+	tk.MustExec("analyze table t partition p2 all columns")
+	tk.MustQuery("select count(*) from mysql.analyze_checkpoints").Check(testkit.Rows("1"))
+
+	tk.MustExec("delete from mysql.analyze_jobs")
+	tk.MustExec("analyze table t all columns")
+	require.Contains(t, tk.MustQuery("show warnings").Rows(),
+		[]any{"Note", "1105", "Analyze resumes from the checkpoints, 1 partitions analyzed by the interrupted run are skipped"})
+	tk.MustQuery("select partition_name from mysql.analyze_jobs where partition_name != '' order by partition_name").Check(testkit.Rows("p1", "p2"))
+	tk.MustQuery("select count(*) from mysql.analyze_checkpoints").Check(testkit.Rows("0"))
+	tk.MustQuery("show stats_meta where table_name = 't' and partition_name = 'global'").CheckAt([]int{5}, testkit.Rows("5"))
+
+	// The checkpoints are not recorded if tidb_analyze_checkpoint_ttl is 0.
+	tk.MustExec("set @@global.tidb_analyze_checkpoint_ttl = 0")
+	defer tk.MustExec("set @@global.tidb_analyze_checkpoint_ttl = default")
+	require.NoError(t, failpoint.Enable(fp, "1*return"))
+	err = tk.ExecToErr("analyze table t all columns")
+	require.NoError(t, failpoint.Disable(fp))
+	require.True(t, exeerrors.ErrQueryInterrupted.Equal(err), err)
+	tk.Session().GetSessionVars().SQLKiller.Reset()
+	tk.MustQuery("select count(*) from mysql.analyze_checkpoints").Check(testkit.Rows("0"))
+}
+
 func TestAnalyzeJob(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	for _, result := range []string{statistics.AnalyzeFinished, statistics.AnalyzeFailed} {
@@ -2085,27 +2168,21 @@ func TestAnalyzeJob(t *testing.T) {
 		job.Progress.SetLastDumpTime(lastDumpTime)
 		const smallCount int64 = 100
 		statsHandle.UpdateAnalyzeJobProgress(job, smallCount)
-		// Delta count doesn't reach threshold so we don't dump it to mysql.analyze_jobs
-		require.Equal(t, smallCount, job.Progress.GetDeltaCount())
-		require.Equal(t, lastDumpTime, job.Progress.GetLastDumpTime())
-		rows = tk.MustQuery("show analyze status").Rows()
-		require.Equal(t, "0", rows[0][4])
-
-		const largeCount int64 = 15000000
-		statsHandle.UpdateAnalyzeJobProgress(job, largeCount)
-		// Delta count reaches threshold so we dump it to mysql.analyze_jobs and update last dump time.
+		// The progress hasn't been dumped for 5 seconds, so we dump it to mysql.analyze_jobs and update last dump time
+		// even if the delta count is small.
 		require.Equal(t, int64(0), job.Progress.GetDeltaCount())
 		require.True(t, job.Progress.GetLastDumpTime().After(lastDumpTime))
 		lastDumpTime = job.Progress.GetLastDumpTime()
 		rows = tk.MustQuery("show analyze status").Rows()
-		require.Equal(t, strconv.FormatInt(smallCount+largeCount, 10), rows[0][4])
+		require.Equal(t, strconv.FormatInt(smallCount, 10), rows[0][4])
 
+		const largeCount int64 = 15000000
 		statsHandle.UpdateAnalyzeJobProgress(job, largeCount)
 		// We have just updated mysql.analyze_jobs in the previous step so we don't update it until 5 second passes or the analyze job is over.
 		require.Equal(t, largeCount, job.Progress.GetDeltaCount())
 		require.Equal(t, lastDumpTime, job.Progress.GetLastDumpTime())
 		rows = tk.MustQuery("show analyze status").Rows()
-		require.Equal(t, strconv.FormatInt(smallCount+largeCount, 10), rows[0][4])
+		require.Equal(t, strconv.FormatInt(smallCount, 10), rows[0][4])
 
 		var analyzeErr error
 		if result == statistics.AnalyzeFailed {
@@ -2113,7 +2190,7 @@ func TestAnalyzeJob(t *testing.T) {
 		}
 		statsHandle.FinishAnalyzeJob(job, analyzeErr, statistics.TableAnalysisJob)
 		rows = tk.MustQuery("show analyze status").Rows()
-		require.Equal(t, strconv.FormatInt(smallCount+2*largeCount, 10), rows[0][4])
+		require.Equal(t, strconv.FormatInt(smallCount+largeCount, 10), rows[0][4])
 		checkTime(rows[0][6])
 		require.Equal(t, result, rows[0][7])
 		if result == statistics.AnalyzeFailed {
@@ -3138,3 +3215,546 @@ func TestIssue55438(t *testing.T) {
 	tk.MustExec("CREATE INDEX i0 ON t0(c1);")
 	tk.MustExec("analyze table t0")
 }
+
+func TestAnalyzeDatabase(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database analyze_db")
+	tk.MustExec("use analyze_db")
+	tk.MustExec("create table t1 (a int, b int, index idx(a))")
+	tk.MustExec("create table t2 (a int, b int) partition by hash(a) partitions 2")
+	tk.MustExec("create table t3 (a int)")
+	tk.MustExec("create view v as select * from t1")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("lock stats t3")
+
+	tk.MustQuery("analyze database analyze_db concurrency 2").Sort().CheckAt([]int{0, 1, 2, 3, 5}, testkit.Rows(
+		"analyze_db t1  finished ",
+		"analyze_db t2  finished ",
+	))
+	tk.MustQuery("select table_name, count(*) from mysql.analyze_jobs where table_schema = 'analyze_db' and state = 'finished' group by table_name").
+		Sort().Check(testkit.Rows("t1 1", "t2 2"))
+	tk.MustQuery("show stats_meta where db_name = 'analyze_db' and table_name = 't1'").CheckAt([]int{5}, testkit.Rows("3"))
+
+	// Static partitions are analyzed separately.
+	tk.MustExec("set global tidb_partition_prune_mode = 'static'")
+	tk.MustQuery("analyze database analyze_db").Sort().CheckAt([]int{1, 2, 3}, testkit.Rows(
+		"t1  finished",
+		"t2 p0 finished",
+		"t2 p1 finished",
+	))
+	tk.MustExec("set global tidb_partition_prune_mode = default")
+
+	// No more tables are analyzed once the budget is used up.
+	tk.MustQuery("analyze database analyze_db budget '1ns'").Sort().CheckAt([]int{1, 3, 5}, testkit.RowsWithSep("|",
+		"t1|skipped|budget exhausted",
+		"t2|skipped|budget exhausted",
+	))
+
+	tk.MustGetErrMsg("analyze database analyze_db budget 'abc'", "[planner:1210]Incorrect arguments to ANALYZE DATABASE BUDGET")
+	tk.MustGetErrMsg("analyze database not_exist", "[schema:1049]Unknown database 'not_exist'")
+	tk.MustExec("drop database analyze_db")
+}
+
+func TestAnalyzeTableBeingAnalyzed(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, index idx(a))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tblInfo := dom.MustGetTableInfo(t, "test", "t")
+
+	// Mock that the table is being analyzed by auto analyze.
+	locked, _ := handleutil.GlobalAnalyzeLocks.TryLock(tblInfo.ID, true)
+	require.True(t, locked)
+	tk.MustExec("analyze table t")
+	tk.MustQuery("show warnings").CheckContain("skip analyze table test.t, it is being analyzed by auto analyze")
+	tk.MustQuery("select count(*) from mysql.analyze_jobs where table_schema = 'test' and table_name = 't'").Check(testkit.Rows("0"))
+	// Another analyze can't take the lock either.
+	locked, heldByAutoAnalyze := handleutil.GlobalAnalyzeLocks.TryLock(tblInfo.ID, false)
+	require.False(t, locked)
+	require.True(t, heldByAutoAnalyze)
+	handleutil.GlobalAnalyzeLocks.Unlock(tblInfo.ID)
+
+	tk.MustExec("analyze table t")
+	tk.MustQuery("show warnings").CheckNotContain("skip analyze table")
+	tk.MustQuery("select count(*) from mysql.analyze_jobs where table_schema = 'test' and table_name = 't' and state = 'finished'").Check(testkit.Rows("1"))
+	// The lock is released after analyze.
+	locked, _ = handleutil.GlobalAnalyzeLocks.TryLock(tblInfo.ID, false)
+	require.True(t, locked)
+	handleutil.GlobalAnalyzeLocks.Unlock(tblInfo.ID)
+}
+
+func TestAdminRemoveAnalyzeJob(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create table tp (a int) partition by hash(a) partitions 2")
+	h := dom.StatsHandle()
+
+	tk.MustGetErrMsg("admin remove analyze job for table t", "priority queue not initialized")
+	// Initialize the priority queue.
+	h.HandleAutoAnalyze()
+
+	tblInfo := dom.MustGetTableInfo(t, "test", "t")
+	require.NoError(t, h.EnqueueAnalysisJob(tblInfo.ID))
+	tk.MustExec("admin remove analyze job for table t")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustExec("admin remove analyze job for table test.t")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 no pending analyze job for table test.t"))
+
+	// The jobs of the partitions are removed as well.
+	tpInfo := dom.MustGetTableInfo(t, "test", "tp")
+	require.NoError(t, h.EnqueueAnalysisJob(tpInfo.GetPartitionInfo().Definitions[0].ID))
+	tk.MustExec("admin remove analyze job for table tp")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+
+	tk.MustGetErrMsg("admin remove analyze job for table not_exist", "[schema:1146]Table 'test.not_exist' doesn't exist")
+}
+
+func TestSessionAnalyzeDefaults(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("insert into t values (1), (1), (1), (2), (2), (3), (3), (4), (5), (6), (7)")
+	tblInfo := dom.MustGetTableInfo(t, "test", "t")
+	checkStats := func(buckets, topn int) {
+		tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_buckets where table_id = %d and is_index = 0", tblInfo.ID)).Check(testkit.Rows(strconv.Itoa(buckets)))
+		tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_top_n where table_id = %d and is_index = 0", tblInfo.ID)).Check(testkit.Rows(strconv.Itoa(topn)))
+	}
+
+	tk.MustGetErrCode("set global tidb_analyze_default_buckets = 2", errno.ErrLocalVariable)
+	tk.MustExec("set @@tidb_analyze_default_buckets = 2, @@tidb_analyze_default_topn = 1, @@tidb_analyze_default_column_choice = 'all'")
+	tk.MustQuery("select @@tidb_analyze_default_buckets, @@tidb_analyze_default_topn, @@tidb_analyze_default_column_choice").Check(testkit.Rows("2 1 ALL"))
+	tk.MustExec("analyze table t")
+	checkStats(2, 1)
+	// The session defaults are not persisted.
+	tk.MustQuery(fmt.Sprintf("select buckets, topn, column_choice from mysql.analyze_options where table_id = %d", tblInfo.ID)).Check(testkit.Rows("0 -1 DEFAULT"))
+
+	// The options specified in the statement take precedence over the session defaults.
+	tk.MustExec("analyze table t with 3 buckets")
+	checkStats(3, 1)
+	// So do the persisted options.
+	tk.MustExec("set @@tidb_analyze_default_buckets = 4")
+	tk.MustExec("analyze table t")
+	checkStats(3, 1)
+
+	tk.MustExec("set global tidb_persist_analyze_options = false")
+	defer tk.MustExec("set global tidb_persist_analyze_options = true")
+	tk.MustExec("analyze table t")
+	checkStats(4, 1)
+	tk.MustExec("analyze table t with 2 topn")
+	checkStats(4, 2)
+
+	// The built-in defaults are used once the session defaults are reset.
+	tk.MustExec("set @@tidb_analyze_default_buckets = default, @@tidb_analyze_default_topn = default")
+	tk.MustExec("analyze table t")
+	checkStats(4, 3)
+}
+
+func TestIncrementalAnalyze(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("create table t (a int primary key, b int, c varchar(10), index idx_b(b), unique index idx_c(c))")
+	for i := 1; i <= 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, 'v%d')", i, i%10, i))
+	}
+	tk.MustExec("analyze table t")
+	tblInfo := dom.MustGetTableInfo(t, "test", "t")
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_fm_sketch where table_id = %d", tblInfo.ID)).Check(testkit.Rows("0"))
+
+	// Only the appended rows are analyzed and merged with the existing stats.
+	for i := 101; i <= 150; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, 'v%d')", i, i%10+10, i))
+	}
+	tk.MustExec("analyze incremental table t")
+	tk.MustQuery("show warnings").CheckNotContain("INCREMENTAL keyword is ignored")
+	tk.MustQuery("select count from mysql.stats_meta where table_id = " + fmt.Sprint(tblInfo.ID)).Check(testkit.Rows("150"))
+	tk.MustQuery(fmt.Sprintf("select hist_id, distinct_count from mysql.stats_histograms where table_id = %d and is_index = 0 order by hist_id", tblInfo.ID)).
+		Check(testkit.Rows("1 150", "2 20", "3 150"))
+	tk.MustQuery(fmt.Sprintf("select hist_id, distinct_count from mysql.stats_histograms where table_id = %d and is_index = 1 order by hist_id", tblInfo.ID)).
+		Check(testkit.Rows("1 20", "2 150"))
+	// The FMSketches are persisted for the next incremental analyze.
+	tk.MustQuery(fmt.Sprintf("select count(*) > 0 from mysql.stats_fm_sketch where table_id = %d", tblInfo.ID)).Check(testkit.Rows("1"))
+
+	tk.MustExec("analyze incremental table t")
+	tk.MustQuery("select count from mysql.stats_meta where table_id = " + fmt.Sprint(tblInfo.ID)).Check(testkit.Rows("150"))
+
+	// Fall back to analyze the whole table if it has not been analyzed.
+	tk.MustExec("create table t1 (a int primary key, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2)")
+	tk.MustExec("analyze incremental table t1")
+	tk.MustQuery("show warnings").CheckContain("The INCREMENTAL keyword is ignored and the whole table test.t1 is analyzed, reason: the table has not been analyzed")
+	t1Info := dom.MustGetTableInfo(t, "test", "t1")
+	tk.MustQuery("select count from mysql.stats_meta where table_id = " + fmt.Sprint(t1Info.ID)).Check(testkit.Rows("2"))
+
+	tk.MustExec("create table t2 (a varchar(10) primary key clustered, b int)")
+	tk.MustGetErrMsg("analyze incremental table t2", "The incremental analyze only supports the table whose primary key is a clustered integer column, but table t2 is not")
+	tk.MustExec("create table t3 (a int primary key, b int) partition by hash(a) partitions 2")
+	tk.MustGetErrMsg("analyze incremental table t3", "The incremental analyze doesn't support the partitioned table t3")
+	tk.MustExec("set @@tidb_analyze_version = 1")
+	tk.MustGetErrMsg("analyze incremental table t", "Only the version 2 of analyze supports the incremental analyze")
+}
+
+func TestAdminBenchStatsLoad(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("create table t (a int, b varchar(10), index idx_b(b))")
+	tk.MustQuery("admin bench stats load t").Check(testkit.Rows())
+
+	tk.MustExec("insert into t values (1, 'a'), (2, 'b'), (3, 'c'), (3, 'c')")
+	tk.MustExec("analyze table t all columns with 2 buckets, 1 topn")
+	rows := tk.MustQuery("admin bench stats load t").Sort().Rows()
+	require.Len(t, rows, 3)
+	expected := [][]string{{"a", "0"}, {"b", "0"}, {"idx_b", "1"}}
+	for i, row := range rows {
+		require.Equal(t, "test", row[0])
+		require.Equal(t, "t", row[1])
+		require.Equal(t, "", row[2])
+		require.Equal(t, expected[i][0], row[3])
+		require.Equal(t, expected[i][1], row[4])
+		require.Equal(t, "1", row[5])
+		require.Equal(t, "1", row[6])
+		require.NotEmpty(t, row[7])
+		mem, err := strconv.ParseInt(row[8].(string), 10, 64)
+		require.NoError(t, err)
+		require.Greater(t, mem, int64(0))
+	}
+
+	tk.MustExec("create table pt (a int, b int) partition by hash(a) partitions 2")
+	tk.MustExec("insert into pt values (1, 1), (2, 2)")
+	tk.MustExec("analyze table pt all columns")
+	tk.MustQuery("admin bench stats load pt").Sort().CheckAt([]int{2, 3}, testkit.Rows(
+		"global a", "global b", "p0 a", "p0 b", "p1 a", "p1 b"))
+
+	tk.MustGetErrMsg("admin bench stats load not_exist", "[schema:1146]Table 'test.not_exist' doesn't exist")
+}
+
+func TestAnalyzeBlockSample(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("create table t(a int primary key, b int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d)", i, i%10))
+	}
+	tk.MustExec("split table t between (0) and (100) regions 10")
+	getCount := func() int {
+		rows := tk.MustQuery("select count from mysql.stats_meta where table_id = (select tidb_table_id from information_schema.tables where table_schema = 'test' and table_name = 't')").Rows()
+		require.Len(t, rows, 1)
+		count, err := strconv.Atoi(rows[0][0].(string))
+		require.NoError(t, err)
+		return count
+	}
+
+	// All the regions are scanned.
+	tk.MustExec("analyze table t with 1 blocksample")
+	require.Equal(t, 100, getCount())
+
+	// Only half of the regions are scanned, and the count is scaled to the whole table.
+	tk.MustExec("delete from mysql.analyze_jobs")
+	tk.MustExec("analyze table t with 0.5 blocksample")
+	count := getCount()
+	require.GreaterOrEqual(t, count, 60)
+	require.LessOrEqual(t, count, 150)
+	tk.MustQuery("select job_info from mysql.analyze_jobs").Check(testkit.Rows("analyze table all columns with 256 buckets, 100 topn, 1 samplerate, 0.5 blocksample"))
+
+	// The default block sample rate is used if the option is not specified.
+	tk.MustExec("delete from mysql.analyze_jobs")
+	tk.MustExec("set @@session.tidb_analyze_block_sample_rate = 0.3")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select job_info from mysql.analyze_jobs").Check(testkit.Rows("analyze table all columns with 256 buckets, 100 topn, 1 samplerate, 0.3 blocksample"))
+
+	tk.MustGetErrMsg("analyze table t with 0 blocksample", "Value of analyze option BLOCKSAMPLE should not larger than 1.000000, and should be greater than 0")
+	tk.MustExec("set @@session.tidb_analyze_version = 1")
+	tk.MustGetErrMsg("analyze table t with 0.5 blocksample", "Version 1's statistics doesn't support the BLOCKSAMPLE option, please set tidb_analyze_version to 2")
+}
+
+func TestAnalyzeQuick(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("create table t(a int primary key, b int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d)", i, i%10))
+	}
+	tk.MustExec("split table t by (10), (20), (30), (40), (50), (60), (70), (80), (90)")
+	// The row count from the region metadata is calculated by count(*) for the small table.
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/pkg/executor/internal/pdhelper/calcSampleRateByStorageCount", "return(1)"))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/pkg/executor/internal/pdhelper/calcSampleRateByStorageCount"))
+	}()
+
+	// Only one of the regions is scanned, and the row count comes from the region metadata.
+	tk.MustExec("analyze table t quick")
+	tk.MustQuery("select count from mysql.stats_meta where table_id = (select tidb_table_id from information_schema.tables where table_schema = 'test' and table_name = 't')").Check(testkit.Rows("100"))
+	tk.MustQuery("select job_info from mysql.analyze_jobs").Check(testkit.Rows("analyze table all columns with 256 buckets, 100 topn, 1 samplerate, quick"))
+	rows := tk.MustQuery("show stats_histograms where table_name = 't' and column_name = 'a'").Rows()
+	require.Len(t, rows, 1)
+	ndv, err := strconv.Atoi(rows[0][6].(string))
+	require.NoError(t, err)
+	require.Greater(t, ndv, 10)
+
+	tk.MustGetErrMsg("analyze table t quick with 0.5 blocksample", "The quick analyze doesn't support the BLOCKSAMPLE option")
+	tk.MustExec("set @@session.tidb_analyze_version = 1")
+	tk.MustGetErrMsg("analyze table t quick", "Only the version 2 of analyze supports the quick analyze")
+}
+
+func TestAnalyzeSamplingStrategy(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("create table t(a int primary key, b int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d)", i, i%10))
+	}
+	tk.MustExec("split table t by (10), (50)")
+
+	for _, strategy := range []string{"stratified", "reservoir", "default"} {
+		tk.MustExec(fmt.Sprintf("analyze table t with 30 samples, '%s' sampling", strategy))
+		tk.MustQuery("select count from mysql.stats_meta where table_id = (select tidb_table_id from information_schema.tables where table_schema = 'test' and table_name = 't')").Check(testkit.Rows("100"))
+		rows := tk.MustQuery("show stats_histograms where table_name = 't' and column_name = 'b'").Rows()
+		require.Len(t, rows, 1)
+	}
+	tk.MustExec("delete from mysql.analyze_jobs")
+	tk.MustExec("analyze table t with 'STRATIFIED' sampling")
+	tk.MustQuery("select job_info from mysql.analyze_jobs").Check(testkit.Rows("analyze table all columns with 256 buckets, 100 topn, 1 samplerate, 'stratified' sampling"))
+	tk.MustExec("analyze table t with 'bernoulli' sampling")
+
+	tk.MustGetErrMsg("analyze table t with 'foo' sampling", "Unknown sampling strategy foo")
+	tk.MustGetErrMsg("analyze table t with 'reservoir' sampling", "The reservoir sampling requires the SAMPLES option")
+	tk.MustGetErrMsg("analyze table t with 30 samples, 'bernoulli' sampling", "The bernoulli sampling doesn't support the SAMPLES option")
+	tk.MustExec("set @@session.tidb_analyze_version = 1")
+	tk.MustGetErrMsg("analyze table t with 'stratified' sampling", "Version 1's statistics doesn't support the SAMPLING option, please set tidb_analyze_version to 2")
+}
+
+func TestAnalyzeVerifyStats(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("create table t(a int, b int, index ib(b))")
+	values := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("set global tidb_analyze_verify_stats = on")
+	defer tk.MustExec("set global tidb_analyze_verify_stats = default")
+	tk.MustExec("analyze table t")
+	tblInfo := dom.MustGetTableInfo(t, "test", "t")
+	tk.MustQuery("select count(*) from mysql.analyze_quarantine").Check(testkit.Rows("0"))
+
+	// The row count is far from the one maintained by the DML deltas.
+	tk.MustExec(fmt.Sprintf("update mysql.stats_meta set count = 100000 where table_id = %d", tblInfo.ID))
+	err := tk.ExecToErr("analyze table t")
+	require.ErrorContains(t, err, "quarantined in mysql.analyze_quarantine")
+	tk.MustQuery("select table_schema, table_name, table_id, old_count, new_count, reason from mysql.analyze_quarantine").Check(testkit.Rows(
+		fmt.Sprintf("test t %d 100000 1000 the row count 1000 is out of the plausible bounds of the current row count 100000", tblInfo.ID)))
+	// The quarantined stats are not saved.
+	tk.MustQuery(fmt.Sprintf("select count from mysql.stats_meta where table_id = %d", tblInfo.ID)).Check(testkit.Rows("100000"))
+
+	// The NDV collapses to 1.
+	tk.MustExec(fmt.Sprintf("update mysql.stats_meta set count = 1000 where table_id = %d", tblInfo.ID))
+	tk.MustExec("update t set b = 1")
+	err = tk.ExecToErr("analyze table t")
+	require.ErrorContains(t, err, fmt.Sprintf("the NDV of the column %d collapses from", tblInfo.Columns[1].ID))
+	require.ErrorContains(t, err, fmt.Sprintf("the NDV of the index %d collapses from", tblInfo.Indices[0].ID))
+	tk.MustQuery("select count(*) from mysql.analyze_quarantine").Check(testkit.Rows("2"))
+
+	// The stats are saved after the verification is disabled.
+	tk.MustExec("set global tidb_analyze_verify_stats = off")
+	tk.MustExec("analyze table t")
+	tk.MustQuery(fmt.Sprintf("select distinct_count from mysql.stats_histograms where table_id = %d and is_index = 1", tblInfo.ID)).Check(testkit.Rows("1"))
+}
+
+func TestAnalyzePriority(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int, index idx(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+
+	fp := "github.com/pingcap/tidb/pkg/executor/assertAnalyzePriority"
+	require.NoError(t, failpoint.Enable(fp, fmt.Sprintf("return(%d)", kv.PriorityLow)))
+	defer func() {
+		require.NoError(t, failpoint.Disable(fp))
+	}()
+	tk.MustExec("analyze table t priority low")
+	tk.MustExec("analyze table t index idx priority low")
+	tk.MustExec("set @@session.tidb_analyze_version = 1")
+	tk.MustExec("analyze table t priority low")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	err := tk.ExecToErr("analyze table t")
+	require.ErrorContains(t, err, "unexpected priority 0 of the analyze request")
+
+	require.NoError(t, failpoint.Enable(fp, fmt.Sprintf("return(%d)", kv.PriorityHigh)))
+	tk.MustExec("analyze table t priority high")
+}
+
+func TestAnalyzeReadNearbyReplica(t *testing.T) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Labels = map[string]string{"zone": "z1"}
+	})
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec(`create placement policy p1 leader_constraints="[+zone=z2]" follower_constraints="[+zone=z1]"`)
+	tk.MustExec(`create placement policy p2 leader_constraints="[+zone=z1]" follower_constraints="[+zone=z2]"`)
+	tk.MustExec("create table t(a int primary key, b int, index idx(b)) placement policy p1")
+	tk.MustExec("create table t2(a int primary key, b int, index idx(b)) placement policy p2")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (3, 3)")
+
+	fp := "github.com/pingcap/tidb/pkg/executor/assertAnalyzeReplicaRead"
+	require.NoError(t, failpoint.Enable(fp, `return("")`))
+	defer func() {
+		require.NoError(t, failpoint.Disable(fp))
+	}()
+	// The leaders are read by default.
+	tk.MustExec("analyze table t")
+
+	// The replicas in the zone of the instance are read if the leaders are in the other zones.
+	tk.MustExec("set @@session.tidb_analyze_read_nearby_replica = on")
+	tk.MustExec("analyze table t2")
+	require.ErrorContains(t, tk.ExecToErr("analyze table t"), "unexpected replica read")
+	require.NoError(t, failpoint.Enable(fp, `return("z1")`))
+	tk.MustExec("analyze table t")
+	tk.MustExec("analyze table t index idx")
+	tk.MustExec("set @@session.tidb_analyze_version = 1")
+	tk.MustExec("analyze table t")
+}
+
+func TestAnalyzeVectorStats(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b vector, c vector(3))")
+	tk.MustExec("insert into t values (1, '[1, 2]', '[1, 2, 3]'), (2, '[1, 2, 3]', null), (3, null, '[4, 5, 6]'), (4, '[1]', null)")
+	tk.MustExec("analyze table t all columns")
+
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tblInfo := tbl.Meta()
+	rows := tk.MustQuery("show stats_extended where db_name = 'test' and table_name = 't' and stats_type = 'vector'").Sort().Rows()
+	require.Len(t, rows, 2)
+	require.Equal(t, []any{"test", "t", statistics.VectorStatsName(tblInfo.Columns[1].ID), "[b]", "vector",
+		`{"row_count":4,"null_count":1,"min_dimension":1,"max_dimension":3}`}, rows[0][:len(rows[0])-1])
+	require.Equal(t, []any{"test", "t", statistics.VectorStatsName(tblInfo.Columns[2].ID), "[c]", "vector",
+		`{"row_count":4,"null_count":2,"min_dimension":3,"max_dimension":3}`}, rows[1][:len(rows[1])-1])
+
+	// The vector stats are loaded into the stats cache.
+	h := dom.StatsHandle()
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	statsTbl := h.GetTableStats(tblInfo)
+	require.NotNil(t, statsTbl.ExtendedStats)
+	item := statsTbl.ExtendedStats.Stats[statistics.VectorStatsName(tblInfo.Columns[2].ID)]
+	require.NotNil(t, item)
+	require.NotNil(t, item.Vector)
+	require.True(t, item.Vector.IsDimensionUniform())
+	require.Equal(t, 0.5, item.Vector.NullRatio())
+}
+
+func TestAnalyzeTemporaryTable(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create temporary table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 1), (3, 1), (4, null)")
+	// The row count is maintained by the writes of the session.
+	rows := tk.MustQuery("explain format = 'brief' select * from t").Rows()
+	require.Equal(t, "4.00", rows[0][1])
+
+	// The NDVs are collected by ANALYZE in the session.
+	tk.MustExec("analyze table t")
+	rows = tk.MustQuery("explain format = 'brief' select distinct b from t").Rows()
+	require.Equal(t, "1.00", rows[0][1])
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (5, 2), (6, 2), (7, 2), (8, 2)")
+	rows = tk.MustQuery("explain format = 'brief' select * from t").Rows()
+	require.Equal(t, "8.00", rows[0][1])
+	tk.MustExec("commit")
+	rows = tk.MustQuery("explain format = 'brief' select * from t").Rows()
+	require.Equal(t, "8.00", rows[0][1])
+	tk.MustExec("drop temporary table t")
+	require.Empty(t, tk.Session().GetSessionVars().TemporaryTableStats)
+
+	// The rows of the global temporary table are deleted on commit.
+	tk.MustExec("create global temporary table g (a int) on commit delete rows")
+	tk.MustExec("begin")
+	tk.MustExec("insert into g values (1), (2), (3)")
+	rows = tk.MustQuery("explain format = 'brief' select * from g").Rows()
+	require.Equal(t, "3.00", rows[0][1])
+	tk.MustExec("commit")
+	rows = tk.MustQuery("explain format = 'brief' select * from g").Rows()
+	require.Equal(t, "10000.00", rows[0][1])
+}
+
+func TestAnalyzeUpdateAndDropHistogram(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("create table t (a int, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4)")
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tblID := tbl.Meta().ID
+
+	// UPDATE HISTOGRAM only analyzes the specified columns.
+	tk.MustExec("analyze table t update histogram on a, b with 0 topn, 2 buckets")
+	tk.MustQuery(fmt.Sprintf("select hist_id, stats_ver from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
+		testkit.Rows("1 2", "2 2", "3 0"))
+	tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't' and column_name = 'a'").Check(
+		testkit.Rows("test t  a 0 0 2 1 1 2 0", "test t  a 0 1 4 1 3 4 0"))
+
+	// DROP HISTOGRAM deletes the stats of the specified columns from both the storage and the cache.
+	tk.MustExec("analyze table t drop histogram on a")
+	tk.MustQuery(fmt.Sprintf("select hist_id from mysql.stats_histograms where table_id = %d", tblID)).Sort().Check(
+		testkit.Rows("2", "3"))
+	tk.MustQuery("show stats_buckets where db_name = 'test' and table_name = 't' and column_name = 'a'").Check(testkit.Rows())
+	statsTbl := dom.StatsHandle().GetTableStats(tbl.Meta())
+	require.Nil(t, statsTbl.GetCol(tbl.Meta().Columns[0].ID))
+
+	tk.MustGetErrMsg("analyze table t drop histogram on d", "[planner:8137]Column 'd' in ANALYZE column option does not exist in table 't'")
+}
+
+func TestAnalyzeStaleSnapshot(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	defer tk.MustExec("set @@global.tidb_analyze_snapshot_staleness = default")
+	tk.MustExec("set @@global.tidb_analyze_snapshot_staleness = '10m'")
+	tk.MustQuery("select @@global.tidb_analyze_snapshot_staleness").Check(testkit.Rows("5m0s"))
+	tk.MustExec("set @@global.tidb_analyze_snapshot_staleness = '2s'")
+
+	// The table created recently is analyzed at the latest data, since it doesn't exist at the stale snapshot.
+	tk.MustExec("create table t (a int, b int, index idx(b))")
+	tk.MustExec("insert into t values (1,1), (2,2), (3,3)")
+	tk.MustExec("analyze table t all columns")
+	tk.MustQuery("show stats_meta where table_name = 't'").CheckAt([]int{5}, testkit.Rows("3"))
+
+	// The rows written within the staleness are not read.
+	time.Sleep(3 * time.Second)
+	tk.MustExec("insert into t values (4,4), (5,5)")
+	tk.MustExec("analyze table t all columns")
+	tk.MustQuery("show stats_meta where table_name = 't'").CheckAt([]int{5}, testkit.Rows("3"))
+	tk.MustQuery("show stats_histograms where table_name = 't' and column_name = 'idx'").CheckAt([]int{7}, testkit.Rows("3"))
+
+	tk.MustExec("set @@global.tidb_analyze_snapshot_staleness = 0")
+	tk.MustExec("analyze table t all columns")
+	tk.MustQuery("show stats_meta where table_name = 't'").CheckAt([]int{5}, testkit.Rows("5"))
+}
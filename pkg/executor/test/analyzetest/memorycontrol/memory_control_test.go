@@ -212,3 +212,22 @@ func TestMemQuotaAnalyze2(t *testing.T) {
 	tk.MustExec("set global tidb_mem_quota_analyze=128;")
 	tk.MustExecToErr("analyze table tbl_2;")
 }
+
+func TestAnalyzeOOMAction(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select @@global.tidb_analyze_oom_action").Check(testkit.Rows("DEGRADE"))
+	tk.MustExec("set global tidb_analyze_oom_action = 'cancel'")
+	defer tk.MustExec("set global tidb_analyze_oom_action = default")
+	tk.MustQuery("select @@global.tidb_analyze_oom_action").Check(testkit.Rows("CANCEL"))
+	tk.MustGetErrMsg("set global tidb_analyze_oom_action = 'kill'", "[variable:1231]Variable 'tidb_analyze_oom_action' can't be set to the value of 'kill'")
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b varchar(64), index idx(a))")
+	tk.MustExec("insert into t values (1, 'a'), (2, 'b'), (3, 'c')")
+	tk.MustExec("set global tidb_mem_quota_analyze = 128")
+	defer tk.MustExec("set global tidb_mem_quota_analyze = default")
+	// The analyze fails without retrying with fewer samples.
+	tk.MustExecToErr("analyze table t")
+	require.Len(t, executor.GlobalAnalyzeMemoryTracker.GetChildrenForTest(), 0)
+}
@@ -16,7 +16,10 @@ package executor
 
 import (
 	"archive/zip"
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"os"
@@ -57,6 +60,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/sessiontxn"
+	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
 	"github.com/pingcap/tidb/pkg/store/mockstore"
 	"github.com/pingcap/tidb/pkg/table/tables"
 	"github.com/pingcap/tidb/pkg/tablecodec"
@@ -178,6 +182,31 @@ func TestPlanReplayerCapture(t *testing.T) {
 	require.NotNil(t, task)
 }
 
+func TestPlanReplayerCaptureThresholds(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int)")
+	tk.MustQuery("select * from t where id = 1")
+	_, sqlDigest := tk.Session().GetSessionVars().StmtCtx.SQLDigest()
+	tk.MustExec("SET @@tidb_enable_plan_replayer_capture = ON;")
+	handle := dom.GetPlanReplayerHandle()
+
+	tk.MustExec(fmt.Sprintf("plan replayer capture '%v' '*' when estimation_error > 1000000 or latency > '1h'", sqlDigest.String()))
+	tk.MustQuery("select est_error_threshold, latency_threshold from mysql.plan_replayer_task").Check(testkit.Rows("1000000 3600"))
+	require.NoError(t, handle.CollectPlanReplayerTask())
+	tk.MustQuery("select * from t where id = 1")
+	// The execution doesn't exceed the thresholds, so it's not captured.
+	require.Len(t, handle.GetTasks(), 1)
+
+	tk.MustExec(fmt.Sprintf("plan replayer capture remove '%v' '*'", sqlDigest.String()))
+	tk.MustExec(fmt.Sprintf("plan replayer capture '%v' '*' when latency > '1ns'", sqlDigest.String()))
+	require.NoError(t, handle.CollectPlanReplayerTask())
+	tk.MustQuery("select * from t where id = 1")
+	require.NotNil(t, handle.DrainTask())
+	require.Len(t, handle.GetTasks(), 0)
+}
+
 func TestPlanReplayerContinuesCapture(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	tk := testkit.NewTestKit(t, store)
@@ -223,6 +252,93 @@ func TestPlanReplayerDumpSingle(t *testing.T) {
 	}
 }
 
+func TestPlanReplayerDumpBinaryStats(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t_dump_binary(a int, b int, index idx_a(a)) partition by hash(a) partitions 4")
+	tk.MustExec("insert into t_dump_binary values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5)")
+	tk.MustExec("analyze table t_dump_binary")
+	tk.MustExec("set @@tidb_plan_replayer_stats_format = 'binary'")
+	res := tk.MustQuery("plan replayer dump explain select * from t_dump_binary where a = 1")
+	defer os.RemoveAll(replayer.GetPlanReplayerDirName())
+	path := testdata.ConvertRowsToStrings(res.Rows())
+
+	data, err := os.ReadFile(filepath.Join(replayer.GetPlanReplayerDirName(), path[0]))
+	require.NoError(t, err)
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	found := false
+	for _, file := range z.File {
+		require.NotEqual(t, "stats/test.t_dump_binary.json", file.Name)
+		if file.Name != "stats/test.t_dump_binary.bin" {
+			continue
+		}
+		found = true
+		r, err := file.Open()
+		require.NoError(t, err)
+		require.True(t, storage.IsBinaryStats(bufio.NewReader(r)))
+		require.NoError(t, r.Close())
+	}
+	require.True(t, found)
+
+	// The binary stats can be loaded by the plan replayer.
+	tk.MustExec("drop table t_dump_binary")
+	loadInfo := &executor.PlanReplayerLoadInfo{Ctx: tk.Session()}
+	require.NoError(t, loadInfo.Update(data))
+	tk.MustQuery("select count(*) from mysql.stats_meta m, information_schema.partitions p " +
+		"where m.table_id = p.tidb_partition_id and p.table_name = 't_dump_binary' and m.count > 0").Check(testkit.Rows("4"))
+}
+
+func TestLoadStatsFromExternalStorage(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int, index idx_a(a))")
+	tk.MustExec("create table t2(a int, index idx_a(a))")
+	tk.MustExec("insert into t1 values (1), (2), (3)")
+	tk.MustExec("insert into t2 values (1), (2), (3)")
+	tk.MustExec("analyze table t1, t2")
+
+	// Dump t1 in the json format and t2 in the binary format into a sub directory.
+	h := dom.StatsHandle()
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))
+	t1, err := dom.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t1"))
+	require.NoError(t, err)
+	jsonTbl, err := h.DumpStatsToJSON("test", t1.Meta(), nil, true)
+	require.NoError(t, err)
+	data, err := json.Marshal(jsonTbl)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test.t1.json"), data, 0o644))
+	t2, err := dom.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t2"))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, h.DumpStatsToBinary(&buf, "test", t2.Meta(), 0, true))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "test.t2.bin"), buf.Bytes(), 0o644))
+
+	checkStatsLoaded := func(tbl string, expected string) {
+		tk.MustQuery("select count(*) from mysql.stats_histograms h, information_schema.tables t " +
+			"where h.table_id = t.tidb_table_id and t.table_schema = 'test' and t.table_name = '" + tbl + "'").Check(testkit.Rows(expected))
+	}
+	tk.MustExec("drop stats t1, t2")
+	checkStatsLoaded("t1", "0")
+	checkStatsLoaded("t2", "0")
+
+	// Load the whole directory.
+	tk.MustExec(fmt.Sprintf("load stats 'local://%s'", dir))
+	checkStatsLoaded("t1", "2")
+	checkStatsLoaded("t2", "2")
+
+	// Load a single file.
+	tk.MustExec("drop stats t1")
+	tk.MustExec(fmt.Sprintf("load stats 'local://%s'", filepath.Join(dir, "test.t1.json")))
+	checkStatsLoaded("t1", "2")
+
+	emptyDir := t.TempDir()
+	tk.MustContainErrMsg(fmt.Sprintf("load stats 'local://%s'", emptyDir), "Load Stats: no stats file is found")
+}
+
 func TestTimezonePushDown(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
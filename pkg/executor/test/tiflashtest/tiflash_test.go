@@ -2167,3 +2167,42 @@ func TestIndexMergeCarePreferTiflash(t *testing.T) {
 			"    └─Selection 0.00 mpp[tiflash]  ge(test.t.m, 1726910326), le(test.t.m, 1726910391), not(in(test.t.a, -1, 0)), or(eq(test.t.w, \"1123\"), eq(test.t.l, \"1123\"))",
 			"      └─TableFullScan 10.00 mpp[tiflash] table:a pushed down filter:eq(test.t.s, 0), keep order:false, stats:pseudo"))
 }
+
+func TestAnalyzeWithTiFlash(t *testing.T) {
+	store := testkit.CreateMockStore(t, withMockTiFlash(2))
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int not null primary key, b varchar(10), c int as (a + 1) virtual, key(b)) partition by hash(a) partitions 2")
+	tk.MustExec("insert into t(a, b) values(1, 'a'), (2, 'b'), (3, 'b'), (4, null)")
+	err := tk.ExecToErr("analyze table t with tiflash")
+	require.ErrorContains(t, err, "the TiFlash replica of table test.t is not available")
+
+	tk.MustExec("alter table t set tiflash replica 1")
+	tb := external.GetTableByName(t, tk, "test", "t")
+	err = domain.GetDomain(tk.Session()).DDLExecutor().UpdateTableReplicaInfo(tk.Session(), tb.Meta().ID, true)
+	require.NoError(t, err)
+	for _, def := range tb.Meta().Partition.Definitions {
+		err = domain.GetDomain(tk.Session()).DDLExecutor().UpdateTableReplicaInfo(tk.Session(), def.ID, true)
+		require.NoError(t, err)
+	}
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("analyze table t with tiflash")
+	rows := tk.MustQuery("show analyze status where table_name = 't' and job_info like '%tiflash%'").Rows()
+	require.Len(t, rows, 2)
+	for _, row := range rows {
+		require.Equal(t, "finished", row[7])
+	}
+	tk.MustQuery("show stats_meta where table_name = 't' and partition_name = 'global'").CheckAt([]int{5}, testkit.Rows("4"))
+	tk.MustQuery("show stats_histograms where table_name = 't' and partition_name = 'global' and column_name = 'b'").CheckAt([]int{6, 7}, testkit.Rows("2 1"))
+
+	// The TiFlash replica is read automatically by tidb_analyze_auto_tiflash.
+	tk.MustExec("delete from mysql.analyze_jobs")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("show analyze status where table_name = 't' and job_info like '%tiflash%'").Check(testkit.Rows())
+	tk.MustExec("set @@tidb_analyze_auto_tiflash = on")
+	tk.MustExec("delete from mysql.analyze_jobs")
+	tk.MustExec("analyze table t")
+	rows = tk.MustQuery("show analyze status where table_name = 't' and job_info like '%tiflash%'").Rows()
+	require.Len(t, rows, 2)
+}
@@ -73,8 +73,9 @@ const (
 	// TableTables is the string constant of infoschema table.
 	TableTables = "TABLES"
 	// TableColumns is the string constant of infoschema table
-	TableColumns          = "COLUMNS"
-	tableColumnStatistics = "COLUMN_STATISTICS"
+	TableColumns = "COLUMNS"
+	// TableColumnStatistics is the string constant of infoschema table, which shows the histograms in the format of MySQL.
+	TableColumnStatistics = "COLUMN_STATISTICS"
 	// TableStatistics is the string constant of infoschema table
 	TableStatistics = "STATISTICS"
 	// TableCharacterSets is the string constant of infoschema charactersets memory table
@@ -214,6 +215,16 @@ const (
 	TableKeywords = "KEYWORDS"
 	// TableTiDBIndexUsage is a table to show the usage stats of indexes in the current instance.
 	TableTiDBIndexUsage = "TIDB_INDEX_USAGE"
+	// TableTiDBTopSQLCPUUsage is a table to show the CPU time consumed by SQL digests over time windows in the current instance.
+	TableTiDBTopSQLCPUUsage = "TIDB_TOP_SQL_CPU_USAGE"
+	// TableTiDBStatsHealth is a table to show the health of the statistics of tables and partitions.
+	TableTiDBStatsHealth = "TIDB_STATS_HEALTH"
+	// TableObjectDependencies is a table to show the dependencies between the schema objects.
+	TableObjectDependencies = "OBJECT_DEPENDENCIES"
+	// TableTiDBLongTxnEvents is a table to show the recent transactions violating tidb_long_txn_policy in the current instance.
+	TableTiDBLongTxnEvents = "TIDB_LONG_TXN_EVENTS"
+	// TableTiDBPartitionStatsRollup is a table to show the stats rollups of the partitions of the range partitioned tables.
+	TableTiDBPartitionStatsRollup = "TIDB_PARTITION_STATS_ROLLUP"
 )
 
 const (
@@ -243,7 +254,7 @@ var tableIDMap = map[string]int64{
 	TableSchemata:         autoid.InformationSchemaDBID + 1,
 	TableTables:           autoid.InformationSchemaDBID + 2,
 	TableColumns:          autoid.InformationSchemaDBID + 3,
-	tableColumnStatistics: autoid.InformationSchemaDBID + 4,
+	TableColumnStatistics: autoid.InformationSchemaDBID + 4,
 	TableStatistics:       autoid.InformationSchemaDBID + 5,
 	TableCharacterSets:    autoid.InformationSchemaDBID + 6,
 	TableCollations:       autoid.InformationSchemaDBID + 7,
@@ -338,6 +349,12 @@ var tableIDMap = map[string]int64{
 	TableTiDBIndexUsage:                  autoid.InformationSchemaDBID + 93,
 	ClusterTableTiDBIndexUsage:           autoid.InformationSchemaDBID + 94,
 	TableTiFlashIndexes:                  autoid.InformationSchemaDBID + 95,
+	TableTiDBTopSQLCPUUsage:              autoid.InformationSchemaDBID + 96,
+	ClusterTableTiDBTopSQLCPUUsage:       autoid.InformationSchemaDBID + 97,
+	TableTiDBStatsHealth:                 autoid.InformationSchemaDBID + 98,
+	TableObjectDependencies:              autoid.InformationSchemaDBID + 99,
+	TableTiDBLongTxnEvents:               autoid.InformationSchemaDBID + 100,
+	TableTiDBPartitionStatsRollup:        autoid.InformationSchemaDBID + 101,
 }
 
 // columnInfo represents the basic column information of all kinds of INFORMATION_SCHEMA tables
@@ -1385,6 +1402,12 @@ var tableStatementsSummaryCols = []columnInfo{
 	{name: stmtsummary.ResourceGroupName, tp: mysql.TypeVarchar, size: 64, comment: "Bind resource group name"},
 	{name: stmtsummary.PlanCacheUnqualifiedStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag, comment: "The number of times that these statements are not supported by the plan cache"},
 	{name: stmtsummary.PlanCacheUnqualifiedLastReasonStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The last reason why the statement is not supported by the plan cache"},
+	{name: stmtsummary.AvgIdxMergeIntersectEstRowsStr, tp: mysql.TypeDouble, size: 22, flag: mysql.NotNullFlag, comment: "Average estimated rows of the intersections of the index merge readers"},
+	{name: stmtsummary.AvgIdxMergeIntersectActRowsStr, tp: mysql.TypeDouble, size: 22, flag: mysql.NotNullFlag, comment: "Average actual rows of the intersections of the index merge readers"},
+	{name: stmtsummary.PseudoStatsCountStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag, comment: "The number of times that these statements are estimated by the pseudo stats"},
+	{name: stmtsummary.OutdatedStatsCountStr, tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag, comment: "The number of times that these statements are estimated by the outdated stats"},
+	{name: stmtsummary.MaxEstimationErrorStr, tp: mysql.TypeDouble, size: 22, flag: mysql.NotNullFlag, comment: "Max q-error of the row count estimations of the operators"},
+	{name: stmtsummary.StatsVersionsStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The versions of the stats used by the last statement"},
 }
 
 var tableStorageStatsCols = []columnInfo{
@@ -1719,6 +1742,73 @@ var tableTiDBIndexUsage = []columnInfo{
 	{name: "LAST_ACCESS_TIME", tp: mysql.TypeDatetime, size: 21},
 }
 
+var tableTiDBTopSQLCPUUsageCols = []columnInfo{
+	{name: "WINDOW_START", tp: mysql.TypeDatetime, size: 19},
+	{name: "WINDOW_END", tp: mysql.TypeDatetime, size: 19},
+	{name: "SQL_DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "PLAN_DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "DIGEST_TEXT", tp: mysql.TypeBlob, size: types.UnspecifiedLength},
+	{name: "IS_INTERNAL", tp: mysql.TypeTiny, size: 1},
+	{name: "CPU_TIME_MS", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "EXEC_COUNT", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+}
+
+var tableTiDBStatsHealthCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "PARTITION_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_ID", tp: mysql.TypeLonglong, size: 21},
+	{name: "HEALTHY", tp: mysql.TypeLonglong, size: 21},
+	{name: "ROW_COUNT", tp: mysql.TypeLonglong, size: 21},
+	{name: "MODIFY_COUNT", tp: mysql.TypeLonglong, size: 21},
+	{name: "MODIFY_RATIO", tp: mysql.TypeDouble, size: 22},
+	{name: "LAST_ANALYZE_TIME", tp: mysql.TypeDatetime, size: 19},
+	{name: "STATS_VERSION", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The version of the stats meta"},
+	{name: "ANALYZE_VERSION", tp: mysql.TypeLonglong, size: 21, comment: "The value of tidb_analyze_version used by the last analysis"},
+	{name: "UNUSABLE_REASON", tp: mysql.TypeVarchar, size: 32, comment: "One of never_analyzed, too_stale, mixed_version and partial_columns, NULL if the stats are usable"},
+}
+
+var tableObjectDependenciesCols = []columnInfo{
+	{name: "OBJECT_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "OBJECT_NAME", tp: mysql.TypeVarchar, size: 64, comment: "The SQL digest if the object is a binding"},
+	{name: "OBJECT_TYPE", tp: mysql.TypeVarchar, size: 16, comment: "One of SCHEMA, TABLE, VIEW, SEQUENCE and BINDING"},
+	{name: "REFERENCED_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "REFERENCED_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "REFERENCED_COLUMN", tp: mysql.TypeVarchar, size: 256},
+	{name: "REFERENCED_TYPE", tp: mysql.TypeVarchar, size: 16, comment: "One of TABLE, VIEW, SEQUENCE, COLUMN and PLACEMENT POLICY"},
+	{name: "DEPENDENCY_TYPE", tp: mysql.TypeVarchar, size: 16, comment: "One of VIEW, FOREIGN KEY, DEFAULT VALUE, BINDING, TTL and PLACEMENT"},
+	{name: "DETAIL", tp: mysql.TypeVarchar, size: 1024},
+}
+
+var tableTiDBLongTxnEventsCols = []columnInfo{
+	{name: "TIME", tp: mysql.TypeTimestamp, decimal: 6, size: 26, comment: "The time when the violation is found"},
+	{name: "SESSION_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "USER", tp: mysql.TypeVarchar, size: 16},
+	{name: "HOST", tp: mysql.TypeVarchar, size: 64},
+	{name: "DB", tp: mysql.TypeVarchar, size: 64},
+	{name: "RESOURCE_GROUP", tp: mysql.TypeVarchar, size: 32},
+	{name: "TRX_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "The start ts of the transaction"},
+	{name: "TRX_START_TIME", tp: mysql.TypeTimestamp, decimal: 6, size: 26},
+	{name: "VIOLATION", tp: mysql.TypeVarchar, size: 16, comment: "IDLE or OPEN"},
+	{name: "DURATION", tp: mysql.TypeDouble, size: 22, comment: "How long the transaction has been idle or open, in seconds"},
+	{name: "THRESHOLD", tp: mysql.TypeDouble, size: 22, comment: "The threshold of the violation, in seconds"},
+	{name: "ACTION", tp: mysql.TypeVarchar, size: 16, comment: "One of warn, notify and kill"},
+}
+
+var tableTiDBPartitionStatsRollupCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "PARTITION_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "PARTITION_ID", tp: mysql.TypeLonglong, size: 21},
+	{name: "LOWER_BOUND", tp: mysql.TypeVarchar, size: 1024, comment: "The inclusive lower bound of the partition range"},
+	{name: "UPPER_BOUND", tp: mysql.TypeVarchar, size: 1024, comment: "The exclusive upper bound of the partition range"},
+	{name: "ROW_COUNT", tp: mysql.TypeLonglong, size: 21},
+	{name: "MODIFY_COUNT", tp: mysql.TypeLonglong, size: 21},
+	{name: "DATA_SIZE", tp: mysql.TypeLonglong, size: 21, comment: "The total size of the columns in bytes"},
+	{name: "FULL_STATS", tp: mysql.TypeTiny, size: 1, comment: "Whether the partition keeps the full stats, see tidb_stats_rollup_recent_partitions"},
+	{name: "LAST_ANALYZE_TIME", tp: mysql.TypeDatetime, size: 19},
+}
+
 // GetShardingInfo returns a nil or description string for the sharding information of given TableInfo.
 // The returned description string may be:
 //   - "NOT_SHARDED": for tables that SHARD_ROW_ID_BITS is not specified.
@@ -2287,7 +2377,7 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableSchemata:                           schemataCols,
 	TableTables:                             tablesCols,
 	TableColumns:                            columnsCols,
-	tableColumnStatistics:                   columnStatisticsCols,
+	TableColumnStatistics:                   columnStatisticsCols,
 	TableStatistics:                         statisticsCols,
 	TableCharacterSets:                      charsetCols,
 	TableCollations:                         collationsCols,
@@ -2362,6 +2452,11 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableTiDBCheckConstraints:               tableTiDBCheckConstraintsCols,
 	TableKeywords:                           tableKeywords,
 	TableTiDBIndexUsage:                     tableTiDBIndexUsage,
+	TableTiDBTopSQLCPUUsage:                 tableTiDBTopSQLCPUUsageCols,
+	TableTiDBStatsHealth:                    tableTiDBStatsHealthCols,
+	TableObjectDependencies:                 tableObjectDependenciesCols,
+	TableTiDBLongTxnEvents:                  tableTiDBLongTxnEventsCols,
+	TableTiDBPartitionStatsRollup:           tableTiDBPartitionStatsRollupCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, _ func() (pools.Resource, error), meta *model.TableInfo) (table.Table, error) {
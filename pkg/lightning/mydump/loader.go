@@ -101,7 +101,7 @@ type SourceFileMeta struct {
 	// If the file is parquet, RealSize is the estimated data size after convert
 	// to row oriented storage.
 	RealSize int64
-	Rows     int64 // only for parquet
+	Rows     int64 // only for parquet and generated data
 }
 
 // NewMDTableMeta creates an Mydumper table meta with specified character set.
@@ -237,7 +237,9 @@ func MakeTableRegions(
 				err     error
 			)
 			dataFileSize := info.FileMeta.FileSize
-			if info.FileMeta.Type == SourceTypeParquet {
+			if info.FileMeta.Type == SourceTypeParquet || info.FileMeta.Type == SourceTypeDatagen {
+				// the row count of a generated data file is known in advance, so it's
+				// handled like a parquet file.
 				regions, sizes, err = makeParquetFileRegion(egCtx, cfg, info)
 			} else if info.FileMeta.Type == SourceTypeCSV && cfg.StrictFormat &&
 				info.FileMeta.Compression == CompressionNone &&
@@ -48,6 +48,9 @@ const (
 	SourceTypeParquet
 	// SourceTypeViewSchema means this source file is a schema file for the view.
 	SourceTypeViewSchema
+	// SourceTypeDatagen means this source file is a virtual data file whose rows
+	// are generated by IMPORT INTO ... FROM DATAGEN(...).
+	SourceTypeDatagen
 )
 
 const (
@@ -63,6 +66,8 @@ const (
 	TypeCSV = "csv"
 	// TypeParquet is the source type value for parquet data file.
 	TypeParquet = "parquet"
+	// TypeDatagen is the source type value for generated data file.
+	TypeDatagen = "datagen"
 	// TypeIgnore is the source type value for a ignored data file.
 	TypeIgnore = "ignore"
 )
@@ -139,6 +144,8 @@ func (s SourceType) String() string {
 		return TypeParquet
 	case SourceTypeViewSchema:
 		return ViewSchema
+	case SourceTypeDatagen:
+		return TypeDatagen
 	default:
 		return TypeIgnore
 	}
@@ -111,6 +111,7 @@ const (
 	ActionAlterTablePartitioning ActionType = 71
 	ActionRemovePartitioning     ActionType = 72
 	ActionAddVectorIndex         ActionType = 73
+	ActionDropRange              ActionType = 74
 )
 
 // ActionMap is the map of DDL ActionType to string.
@@ -183,6 +184,7 @@ var ActionMap = map[ActionType]string{
 	ActionAlterTablePartitioning:        "alter table partition by",
 	ActionRemovePartitioning:            "alter table remove partitioning",
 	ActionAddVectorIndex:                "add vector index",
+	ActionDropRange:                     "drop range",
 
 	// `ActionAlterTableAlterPartition` is removed and will never be used.
 	// Just left a tombstone here for compatibility.
@@ -741,7 +743,8 @@ func (job *Job) IsRollbackable() bool {
 		ActionTruncateTable, ActionAddForeignKey, ActionRenameTable, ActionRenameTables,
 		ActionModifyTableCharsetAndCollate,
 		ActionModifySchemaCharsetAndCollate, ActionRepairTable,
-		ActionModifyTableAutoIDCache, ActionModifySchemaDefaultPlacement, ActionDropCheckConstraint:
+		ActionModifyTableAutoIDCache, ActionModifySchemaDefaultPlacement, ActionDropCheckConstraint,
+		ActionDropRange:
 		return job.SchemaState == StateNone
 	case ActionMultiSchemaChange:
 		return job.MultiSchemaInfo.Revertible
@@ -791,6 +794,9 @@ type SubJob struct {
 	CtxVars     []any           `json:"-"`
 	SchemaVer   int64           `json:"schema_version"`
 	ReorgTp     ReorgType       `json:"reorg_tp"`
+	// SchemaChangeEvents are the schema change events of the sub-job. They are published together with the events of
+	// the other sub-jobs when the multi-schema change is done.
+	SchemaChangeEvents []json.RawMessage `json:"schema_change_events,omitempty"`
 }
 
 // IsNormal returns true if the sub-job is normally running.
@@ -858,6 +864,7 @@ func (sub *SubJob) FromProxyJob(proxyJob *Job, ver int64) {
 	sub.Warning = proxyJob.Warning
 	sub.RowCount = proxyJob.RowCount
 	sub.SchemaVer = ver
+	sub.SchemaChangeEvents = append(sub.SchemaChangeEvents, proxyJob.MultiSchemaInfo.SchemaChangeEvents...)
 	if proxyJob.ReorgMeta != nil {
 		sub.ReorgTp = proxyJob.ReorgMeta.ReorgTp
 	}
@@ -889,6 +896,8 @@ type MultiSchemaInfo struct {
 
 	// SkipVersion is used to control whether generating a new schema version for a sub-job.
 	SkipVersion bool `json:"-"`
+	// SchemaChangeEvents is used by the proxy job to collect the schema change events of the sub-job.
+	SchemaChangeEvents []json.RawMessage `json:"-"`
 
 	AddColumns    []model.CIStr `json:"-"`
 	DropColumns   []model.CIStr `json:"-"`
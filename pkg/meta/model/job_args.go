@@ -673,6 +673,47 @@ func GetModifyTableCommentArgs(job *Job) (*ModifyTableCommentArgs, error) {
 	return getOrDecodeArgs[*ModifyTableCommentArgs](&ModifyTableCommentArgs{}, job)
 }
 
+// AlterTableStatsOptionsArgs is the arguments for ActionAlterTableStatsOptions ddl.
+// Only the options which are not nil are changed.
+type AlterTableStatsOptionsArgs struct {
+	AutoAnalyzeCoolDown *uint64 `json:"auto_analyze_cool_down,omitempty"`
+	AutoRecalc          *bool   `json:"auto_recalc,omitempty"`
+}
+
+func (a *AlterTableStatsOptionsArgs) getArgsV1(*Job) []any {
+	return []any{a.AutoAnalyzeCoolDown, a.AutoRecalc}
+}
+
+func (a *AlterTableStatsOptionsArgs) decodeV1(job *Job) error {
+	return errors.Trace(job.decodeArgs(&a.AutoAnalyzeCoolDown, &a.AutoRecalc))
+}
+
+// GetAlterTableStatsOptionsArgs gets the args for ActionAlterTableStatsOptions.
+func GetAlterTableStatsOptionsArgs(job *Job) (*AlterTableStatsOptionsArgs, error) {
+	return getOrDecodeArgs[*AlterTableStatsOptionsArgs](&AlterTableStatsOptionsArgs{}, job)
+}
+
+// DropRangeArgs is the arguments for ActionDropRange ddl.
+type DropRangeArgs struct {
+	// StartHandles and EndHandles are the encoded handles of the dropped ranges.
+	// The i-th range is [StartHandles[i], EndHandles[i]) in each physical table.
+	StartHandles [][]byte `json:"start_handles,omitempty"`
+	EndHandles   [][]byte `json:"end_handles,omitempty"`
+}
+
+func (a *DropRangeArgs) getArgsV1(*Job) []any {
+	return []any{a.StartHandles, a.EndHandles}
+}
+
+func (a *DropRangeArgs) decodeV1(job *Job) error {
+	return errors.Trace(job.decodeArgs(&a.StartHandles, &a.EndHandles))
+}
+
+// GetDropRangeArgs gets the args for ActionDropRange.
+func GetDropRangeArgs(job *Job) (*DropRangeArgs, error) {
+	return getOrDecodeArgs[*DropRangeArgs](&DropRangeArgs{}, job)
+}
+
 // ModifyTableCharsetAndCollateArgs is the arguments for ActionModifyTableCharsetAndCollate ddl.
 type ModifyTableCharsetAndCollateArgs struct {
 	ToCharset          string `json:"to_charset,omitempty"`
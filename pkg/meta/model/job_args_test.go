@@ -561,6 +561,38 @@ func TestGetModifyTableCommentArgs(t *testing.T) {
 	}
 }
 
+func TestGetAlterTableStatsOptionsArgs(t *testing.T) {
+	coolDown := uint64(30)
+	autoRecalc := false
+	for _, inArgs := range []*AlterTableStatsOptionsArgs{
+		{AutoAnalyzeCoolDown: &coolDown},
+		{AutoRecalc: &autoRecalc},
+	} {
+		for _, v := range []JobVersion{JobVersion1, JobVersion2} {
+			j2 := &Job{}
+			require.NoError(t, j2.Decode(getJobBytes(t, inArgs, v, ActionAlterTableStatsOptions)))
+			args, err := GetAlterTableStatsOptionsArgs(j2)
+			require.NoError(t, err)
+			require.Equal(t, inArgs, args)
+		}
+	}
+}
+
+func TestGetDropRangeArgs(t *testing.T) {
+	inArgs := &DropRangeArgs{
+		StartHandles: [][]byte{{1, 2}, {5}},
+		EndHandles:   [][]byte{{3}, {6, 7}},
+	}
+
+	for _, v := range []JobVersion{JobVersion1, JobVersion2} {
+		j2 := &Job{}
+		require.NoError(t, j2.Decode(getJobBytes(t, inArgs, v, ActionDropRange)))
+		args, err := GetDropRangeArgs(j2)
+		require.NoError(t, err)
+		require.Equal(t, inArgs, args)
+	}
+}
+
 func TestGetAlterIndexVisibilityArgs(t *testing.T) {
 	inArgs := &AlterIndexVisibilityArgs{
 		IndexName: model.NewCIStr("index-name"),
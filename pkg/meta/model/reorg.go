@@ -77,6 +77,9 @@ type DDLReorgMeta struct {
 	// Note: Don't get or set these two variables directly, use the functions instead.
 	Concurrency int64 `json:"concurrency"`
 	BatchSize   int64 `json:"batch_size"`
+	// VectorIndexPendingRowCount is the number of rows that TiFlash has not built the vector index for yet.
+	// It's only used to show the progress of adding a vector index.
+	VectorIndexPendingRowCount int64 `json:"vector_index_pending_row_count,omitempty"`
 }
 
 // GetConcurrencyOrDefault gets the concurrency from DDLReorgMeta,
@@ -1269,6 +1269,9 @@ type StatsOptions struct {
 	Buckets      uint64             `json:"buckets"`
 	TopN         uint64             `json:"topn"`
 	Concurrency  uint               `json:"concurrency"`
+	// AutoAnalyzeCoolDown is the minutes that auto analyze skips the table after a successful manual analyze.
+	// 0 means using the global variable tidb_auto_analyze_cool_down.
+	AutoAnalyzeCoolDown uint64 `json:"auto_analyze_cool_down,omitempty"`
 }
 
 // NewStatsOptions creates a new StatsOptions.
@@ -50,6 +50,11 @@ var (
 	SchemaValidatorCacheMiss  = "cache_miss"
 	// HandleSchemaValidate records the counter of handling schema validate.
 	HandleSchemaValidate *prometheus.CounterVec
+
+	// InternalTableGCBacklog records the number of the rows to be deleted by the GC of the internal tables.
+	InternalTableGCBacklog *prometheus.GaugeVec
+	// InternalTableGCDeletedRows records the number of the rows deleted by the GC of the internal tables.
+	InternalTableGCDeletedRows *prometheus.CounterVec
 )
 
 // InitDomainMetrics initializes domain metrics.
@@ -110,4 +115,20 @@ func InitDomainMetrics() {
 			Name:      "handle_schema_validate",
 			Help:      "Counter of handle schema validate",
 		}, []string{LblType})
+
+	InternalTableGCBacklog = NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "domain",
+			Name:      "internal_table_gc_backlog",
+			Help:      "Number of the rows exceeding the retention of the internal tables",
+		}, []string{LblTable})
+
+	InternalTableGCDeletedRows = NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "domain",
+			Name:      "internal_table_gc_deleted_rows",
+			Help:      "Counter of the rows deleted by the GC of the internal tables",
+		}, []string{LblTable})
 }
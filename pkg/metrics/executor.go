@@ -34,6 +34,9 @@ var (
 	// OngoingTxnDurationHistogram records the duration of ongoing transactions.
 	OngoingTxnDurationHistogram *prometheus.HistogramVec
 
+	// LongTxnPolicyCounter records the number of transactions violating tidb_long_txn_policy.
+	LongTxnPolicyCounter *prometheus.CounterVec
+
 	// MppCoordinatorStats records the number of mpp coordinator instances and related events
 	MppCoordinatorStats *prometheus.GaugeVec
 
@@ -100,6 +103,14 @@ func InitExecutorMetrics() {
 			Buckets:   prometheus.ExponentialBuckets(60, 2, 15), // 60s ~ 273hours
 		}, []string{LblType})
 
+	LongTxnPolicyCounter = NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "executor",
+			Name:      "long_txn_policy_total",
+			Help:      "Counter of the transactions violating the long transaction policy.",
+		}, []string{LblType, LblResourceGroup, LblAction})
+
 	MppCoordinatorStats = NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "tidb",
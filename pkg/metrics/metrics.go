@@ -154,6 +154,10 @@ func RegisterMetrics() {
 	prometheus.MustRegister(SyncLoadDedupCounter)
 	prometheus.MustRegister(SyncLoadHistogram)
 	prometheus.MustRegister(ReadStatsHistogram)
+	prometheus.MustRegister(SyncLoadWaitHistogram)
+	prometheus.MustRegister(SyncLoadQueueGauge)
+	prometheus.MustRegister(StatsLoadItemCounter)
+	prometheus.MustRegister(AsyncLoadBacklogGauge)
 	prometheus.MustRegister(JobsGauge)
 	prometheus.MustRegister(LoadPrivilegeCounter)
 	prometheus.MustRegister(InfoCacheCounters)
@@ -166,6 +170,7 @@ func RegisterMetrics() {
 	prometheus.MustRegister(PanicCounter)
 	prometheus.MustRegister(PlanCacheCounter)
 	prometheus.MustRegister(PlanCacheMissCounter)
+	prometheus.MustRegister(PlanCacheStatsInvalidation)
 	prometheus.MustRegister(PlanCacheInstanceMemoryUsage)
 	prometheus.MustRegister(PlanCacheInstancePlanNumCounter)
 	prometheus.MustRegister(PlanCacheProcessDuration)
@@ -190,6 +195,7 @@ func RegisterMetrics() {
 	prometheus.MustRegister(DbStmtNodeCounter)
 	prometheus.MustRegister(ExecPhaseDuration)
 	prometheus.MustRegister(OngoingTxnDurationHistogram)
+	prometheus.MustRegister(LongTxnPolicyCounter)
 	prometheus.MustRegister(MppCoordinatorStats)
 	prometheus.MustRegister(MppCoordinatorLatency)
 	prometheus.MustRegister(TimeJumpBackCounter)
@@ -211,6 +217,8 @@ func RegisterMetrics() {
 	prometheus.MustRegister(TotalCopWaitHistogram)
 	prometheus.MustRegister(CopMVCCRatioHistogram)
 	prometheus.MustRegister(HandleSchemaValidate)
+	prometheus.MustRegister(InternalTableGCBacklog)
+	prometheus.MustRegister(InternalTableGCDeletedRows)
 	prometheus.MustRegister(MaxProcs)
 	prometheus.MustRegister(GOGC)
 	prometheus.MustRegister(ConnIdleDurationHistogram)
@@ -238,8 +246,14 @@ func RegisterMetrics() {
 	prometheus.MustRegister(StatsCacheCounter)
 	prometheus.MustRegister(StatsCacheGauge)
 	prometheus.MustRegister(StatsHealthyGauge)
+	prometheus.MustRegister(StatsAlertGauge)
+	prometheus.MustRegister(AnalyzeDebtGauge)
 	prometheus.MustRegister(StatsDeltaLoadHistogram)
 	prometheus.MustRegister(StatsDeltaUpdateHistogram)
+	prometheus.MustRegister(StatsSessionPoolInUseGauge)
+	prometheus.MustRegister(StatsSessionPoolWaitingGauge)
+	prometheus.MustRegister(StatsSessionPoolWaitHistogram)
+	prometheus.MustRegister(StatsSessionPoolOverflowCounter)
 	prometheus.MustRegister(TxnStatusEnteringCounter)
 	prometheus.MustRegister(TxnDurationHistogram)
 	prometheus.MustRegister(LastCheckpoint)
@@ -276,6 +290,7 @@ func RegisterMetrics() {
 	prometheus.MustRegister(DistTaskStartTimeGauge)
 	prometheus.MustRegister(DistTaskUsedSlotsGauge)
 	prometheus.MustRegister(RunawayCheckerCounter)
+	prometheus.MustRegister(AnalyzeRUCounter)
 	prometheus.MustRegister(GlobalSortWriteToCloudStorageDuration)
 	prometheus.MustRegister(GlobalSortWriteToCloudStorageRate)
 	prometheus.MustRegister(GlobalSortReadFromCloudStorageDuration)
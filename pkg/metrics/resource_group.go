@@ -20,6 +20,7 @@ import "github.com/prometheus/client_golang/prometheus"
 // Query duration by query is QueryDurationHistogram in `server.go`.
 var (
 	RunawayCheckerCounter *prometheus.CounterVec
+	AnalyzeRUCounter      *prometheus.CounterVec
 )
 
 // InitResourceGroupMetrics initializes resource group metrics.
@@ -31,4 +32,12 @@ func InitResourceGroupMetrics() {
 			Name:      "query_runaway_check",
 			Help:      "Counter of query triggering runaway check.",
 		}, []string{LblResourceGroup, LblType, LblAction})
+
+	AnalyzeRUCounter = NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "analyze_request_unit",
+			Help:      "Counter of the request units consumed by the coprocessor requests of analyze.",
+		}, []string{LblResourceGroup, LblType})
 }
@@ -48,6 +48,7 @@ var (
 	TimeJumpBackCounter             prometheus.Counter
 	PlanCacheCounter                *prometheus.CounterVec
 	PlanCacheMissCounter            *prometheus.CounterVec
+	PlanCacheStatsInvalidation      *prometheus.CounterVec
 	PlanCacheInstanceMemoryUsage    *prometheus.GaugeVec
 	PlanCacheInstancePlanNumCounter *prometheus.GaugeVec
 	PlanCacheProcessDuration        *prometheus.HistogramVec
@@ -192,6 +193,14 @@ func InitServerMetrics() {
 			Help:      "Counter of plan cache miss.",
 		}, []string{LblType})
 
+	PlanCacheStatsInvalidation = NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "plan_cache_stats_invalidation_total",
+			Help:      "Counter of the cached plans of tables invalidated by stats updates.",
+		}, []string{LblType})
+
 	PlanCacheInstanceMemoryUsage = NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "tidb",
@@ -29,12 +29,23 @@ var (
 	SyncLoadDedupCounter      prometheus.Counter
 	SyncLoadHistogram         prometheus.Histogram
 	ReadStatsHistogram        prometheus.Histogram
+	SyncLoadWaitHistogram     prometheus.Histogram
+	SyncLoadQueueGauge        *prometheus.GaugeVec
+	StatsLoadItemCounter      *prometheus.CounterVec
+	AsyncLoadBacklogGauge     prometheus.Gauge
 	StatsCacheCounter         *prometheus.CounterVec
 	StatsCacheGauge           *prometheus.GaugeVec
 	StatsHealthyGauge         *prometheus.GaugeVec
+	StatsAlertGauge           *prometheus.GaugeVec
+	AnalyzeDebtGauge          *prometheus.GaugeVec
 	StatsDeltaLoadHistogram   prometheus.Histogram
 	StatsDeltaUpdateHistogram prometheus.Histogram
 
+	StatsSessionPoolInUseGauge      *prometheus.GaugeVec
+	StatsSessionPoolWaitingGauge    *prometheus.GaugeVec
+	StatsSessionPoolWaitHistogram   *prometheus.HistogramVec
+	StatsSessionPoolOverflowCounter *prometheus.CounterVec
+
 	HistoricalStatsCounter        *prometheus.CounterVec
 	PlanReplayerTaskCounter       *prometheus.CounterVec
 	PlanReplayerRegisterTaskGauge prometheus.Gauge
@@ -117,6 +128,36 @@ func InitStatsMetrics() {
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 22), // 1ms ~ 1h
 		})
 
+	SyncLoadWaitHistogram = NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "statistics",
+			Name:      "sync_load_wait_latency_millis",
+			Help:      "Bucketed histogram of waiting time (ms) of sync-load tasks in the queue before being handled.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 22), // 1ms ~ 1h
+		})
+
+	SyncLoadQueueGauge = NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tidb",
+		Subsystem: "statistics",
+		Name:      "sync_load_queue_depth",
+		Help:      "Gauge of the number of tasks in the sync-load queues",
+	}, []string{LblType})
+
+	StatsLoadItemCounter = NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tidb",
+		Subsystem: "statistics",
+		Name:      "stats_load_item_total",
+		Help:      "Counter of the stats items requested by the sync-load and async-load, hit means the item is already loaded",
+	}, []string{LblType, LblResult})
+
+	AsyncLoadBacklogGauge = NewGauge(prometheus.GaugeOpts{
+		Namespace: "tidb",
+		Subsystem: "statistics",
+		Name:      "async_load_backlog",
+		Help:      "Gauge of the number of stats items waiting to be loaded by the async-load",
+	})
+
 	StatsHealthyGauge = NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "tidb",
 		Subsystem: "statistics",
@@ -124,6 +165,20 @@ func InitStatsMetrics() {
 		Help:      "Gauge of stats healthy",
 	}, []string{LblType})
 
+	StatsAlertGauge = NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tidb",
+		Subsystem: "statistics",
+		Name:      "stats_alert_tables",
+		Help:      "Gauge of the number of the tables exceeding the stats alert thresholds although auto analyze is enabled",
+	}, []string{LblType})
+
+	AnalyzeDebtGauge = NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tidb",
+		Subsystem: "statistics",
+		Name:      "analyze_debt",
+		Help:      "Gauge of the outstanding auto analyze work in stale bytes, the throughput of auto analyze in stale bytes per second, and the ETA to catch up in seconds",
+	}, []string{LblType})
+
 	HistoricalStatsCounter = NewCounterVec(prometheus.CounterOpts{
 		Namespace: "tidb",
 		Subsystem: "statistics",
@@ -162,4 +217,34 @@ func InitStatsMetrics() {
 			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 24), // 10ms ~ 24h
 		},
 	)
+
+	StatsSessionPoolInUseGauge = NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tidb",
+		Subsystem: "statistics",
+		Name:      "session_pool_in_use",
+		Help:      "Gauge of the number of the sessions of the stats session pool in use, by the priority class",
+	}, []string{LblType})
+
+	StatsSessionPoolWaitingGauge = NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tidb",
+		Subsystem: "statistics",
+		Name:      "session_pool_waiting",
+		Help:      "Gauge of the number of the stats operations waiting for the sessions of the stats session pool, by the priority class",
+	}, []string{LblType})
+
+	StatsSessionPoolWaitHistogram = NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "statistics",
+			Name:      "session_pool_wait_duration_seconds",
+			Help:      "Bucketed histogram of the time waiting for the sessions of the saturated stats session pool, by the priority class",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 12), // 0.5ms ~ 1s
+		}, []string{LblType})
+
+	StatsSessionPoolOverflowCounter = NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tidb",
+		Subsystem: "statistics",
+		Name:      "session_pool_overflow_total",
+		Help:      "Counter of the sessions taken beyond tidb_stats_session_pool_size after waiting too long, by the priority class",
+	}, []string{LblType})
 }
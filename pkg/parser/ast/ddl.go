@@ -1090,6 +1090,29 @@ const (
 	TemporaryLocal
 )
 
+// DropBehavior is the RESTRICT or CASCADE option of the DROP statements, which decides how to handle the objects
+// depending on the dropped ones.
+type DropBehavior int
+
+const (
+	// DropBehaviorDefault reports the dependent objects as warnings and drops the objects anyway.
+	DropBehaviorDefault DropBehavior = iota
+	// DropBehaviorRestrict refuses to drop the objects if any object depends on them.
+	DropBehaviorRestrict
+	// DropBehaviorCascade drops the dependent objects together.
+	DropBehaviorCascade
+)
+
+// Restore writes the drop behavior, with a leading space.
+func (b DropBehavior) Restore(ctx *format.RestoreCtx) {
+	switch b {
+	case DropBehaviorRestrict:
+		ctx.WriteKeyWord(" RESTRICT")
+	case DropBehaviorCascade:
+		ctx.WriteKeyWord(" CASCADE")
+	}
+}
+
 // CreateTableStmt is a statement to create a table.
 // See https://dev.mysql.com/doc/refman/5.7/en/create-table.html
 type CreateTableStmt struct {
@@ -1109,6 +1132,9 @@ type CreateTableStmt struct {
 	Partition      *PartitionOptions
 	OnDuplicate    OnDuplicateKeyHandlingType
 	Select         ResultSetNode
+	// Datagen is used for `CREATE TABLE ... AS DATAGEN(...)`, the generated data is
+	// imported into the table after it's created.
+	Datagen *DatagenSpec
 }
 
 // Restore implements Node interface.
@@ -1186,6 +1212,11 @@ func (n *CreateTableStmt) Restore(ctx *format.RestoreCtx) error {
 		if err := n.Select.Restore(ctx); err != nil {
 			return errors.Annotate(err, "An error occurred while splicing CreateTableStmt Select")
 		}
+	} else if n.Datagen != nil {
+		ctx.WriteKeyWord(" AS ")
+		if err := n.Datagen.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while splicing CreateTableStmt Datagen")
+		}
 	}
 
 	if n.TemporaryKeyword == TemporaryGlobal {
@@ -1239,6 +1270,13 @@ func (n *CreateTableStmt) Accept(v Visitor) (Node, bool) {
 		}
 		n.Select = node.(ResultSetNode)
 	}
+	if n.Datagen != nil {
+		node, ok := n.Datagen.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Datagen = node.(*DatagenSpec)
+	}
 	if n.Partition != nil {
 		node, ok := n.Partition.Accept(v)
 		if !ok {
@@ -1266,6 +1304,7 @@ type DropTableStmt struct {
 	Tables           []*TableName
 	IsView           bool
 	TemporaryKeyword // make sense ONLY if/when IsView == false
+	Behavior         DropBehavior
 }
 
 // Restore implements Node interface.
@@ -1294,6 +1333,7 @@ func (n *DropTableStmt) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotatef(err, "An error occurred while restore DropTableStmt.Tables[%d]", index)
 		}
 	}
+	n.Behavior.Restore(ctx)
 
 	return nil
 }
@@ -1416,6 +1456,7 @@ type DropSequenceStmt struct {
 
 	IfExists  bool
 	Sequences []*TableName
+	Behavior  DropBehavior
 }
 
 // Restore implements Node interface.
@@ -1432,6 +1473,7 @@ func (n *DropSequenceStmt) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotatef(err, "An error occurred while restore DropSequenceStmt.Sequences[%d]", i)
 		}
 	}
+	n.Behavior.Restore(ctx)
 
 	return nil
 }
@@ -2464,6 +2506,7 @@ const (
 	StatsOptionColsChoice
 	StatsOptionColList
 	StatsOptionSampleRate
+	StatsOptionAutoAnalyzeCoolDown
 )
 
 // TableOptionType is the type for TableOption
@@ -2515,6 +2558,8 @@ const (
 	TableOptionStatsColsChoice = TableOptionType(StatsOptionColsChoice)
 	TableOptionStatsColList    = TableOptionType(StatsOptionColList)
 	TableOptionStatsSampleRate = TableOptionType(StatsOptionSampleRate)
+	// TableOptionAutoAnalyzeCoolDown is the minutes that auto analyze skips the table after a manual analyze.
+	TableOptionAutoAnalyzeCoolDown = TableOptionType(StatsOptionAutoAnalyzeCoolDown)
 )
 
 // RowFormat types
@@ -2819,6 +2864,13 @@ func (n *TableOption) Restore(ctx *format.RestoreCtx) error {
 		} else {
 			ctx.WritePlainf("%d", n.UintValue)
 		}
+	case TableOptionAutoAnalyzeCoolDown:
+		_ = ctx.WriteWithSpecialComments(tidb.FeatureIDAutoAnalyzeCoolDown, func() error {
+			ctx.WriteKeyWord("AUTO_ANALYZE_COOL_DOWN ")
+			ctx.WritePlain("= ")
+			ctx.WritePlainf("%d", n.UintValue)
+			return nil
+		})
 	case TableOptionStatsSampleRate:
 		ctx.WriteKeyWord("STATS_SAMPLE_RATE ")
 		ctx.WritePlain("= ")
@@ -3093,6 +3145,7 @@ const (
 	AlterTableReorganizeLastPartition
 	AlterTableReorganizeFirstPartition
 	AlterTableRemoveTTL
+	AlterTableDropRange
 )
 
 // LockType is the type for AlterTableSpec.
@@ -3193,6 +3246,8 @@ type AlterTableSpec struct {
 	Statistics       *StatisticsSpec
 	AttributesSpec   *AttributesSpec
 	StatsOptionsSpec *StatsOptionsSpec
+	// Where is the condition of the rows deleted by DROP RANGE.
+	Where ExprNode
 }
 
 type TiFlashReplicaSpec struct {
@@ -3265,6 +3320,8 @@ func (n *AlterTableSpec) Restore(ctx *format.RestoreCtx) error {
 			ctx.WriteKeyWord(" DEPENDENCY(")
 		case StatsTypeCorrelation:
 			ctx.WriteKeyWord(" CORRELATION(")
+		case StatsTypeHistogram:
+			ctx.WriteKeyWord(" HISTOGRAM(")
 		}
 		for i, col := range n.Statistics.Columns {
 			if i != 0 {
@@ -3780,6 +3837,11 @@ func (n *AlterTableSpec) Restore(ctx *format.RestoreCtx) error {
 			ctx.WriteKeyWord("REMOVE TTL")
 			return nil
 		})
+	case AlterTableDropRange:
+		ctx.WriteKeyWord("DROP RANGE WHERE ")
+		if err := n.Where.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore AlterTableSpec.Where")
+		}
 	default:
 		// TODO: not support
 		ctx.WritePlainf(" /* AlterTableType(%d) is not supported */ ", n.Tp)
@@ -3855,6 +3917,13 @@ func (n *AlterTableSpec) Accept(v Visitor) (Node, bool) {
 			return n, false
 		}
 	}
+	if n.Where != nil {
+		node, ok := n.Where.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Where = node.(ExprNode)
+	}
 	return v.Leave(n)
 }
 
@@ -2137,6 +2137,7 @@ type ImportIntoStmt struct {
 	Format             *string
 	Options            []*LoadDataOpt
 	Select             ResultSetNode
+	Datagen            *DatagenSpec
 }
 
 var _ SensitiveStmtNode = &ImportIntoStmt{}
@@ -2177,6 +2178,10 @@ func (n *ImportIntoStmt) Restore(ctx *format.RestoreCtx) error {
 		if err := n.Select.Restore(ctx); err != nil {
 			return errors.Annotate(err, "An error occurred while restore ImportIntoStmt.Select")
 		}
+	} else if n.Datagen != nil {
+		if err := n.Datagen.Restore(ctx); err != nil {
+			return errors.Annotate(err, "An error occurred while restore ImportIntoStmt.Datagen")
+		}
 	} else {
 		ctx.WriteString(n.Path)
 		if n.Format != nil {
@@ -2236,9 +2241,56 @@ func (n *ImportIntoStmt) Accept(v Visitor) (Node, bool) {
 		}
 		n.Select = node.(ResultSetNode)
 	}
+	if n.Datagen != nil {
+		node, ok := n.Datagen.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.Datagen = node.(*DatagenSpec)
+	}
 	return v.Leave(n)
 }
 
+// DatagenSpec is the synthetic data source of `IMPORT INTO ... FROM DATAGEN(...)` and
+// `CREATE TABLE ... AS DATAGEN(...)`, such as `DATAGEN(ROWS = 1e8, a = 'zipf(1.1, 1000)')`.
+type DatagenSpec struct {
+	node
+
+	// Rows is the number of rows to generate.
+	Rows uint64
+	// Columns are the value templates of the columns, the columns without a template
+	// are generated according to their types.
+	Columns []*DatagenColumn
+}
+
+// DatagenColumn is the value template of a column in DATAGEN.
+type DatagenColumn struct {
+	Name     model.CIStr
+	Template string
+}
+
+// Restore implements Node interface.
+func (n *DatagenSpec) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("DATAGEN")
+	ctx.WritePlain("(")
+	ctx.WriteKeyWord("ROWS")
+	ctx.WritePlainf(" = %d", n.Rows)
+	for _, col := range n.Columns {
+		ctx.WritePlain(", ")
+		ctx.WriteName(col.Name.O)
+		ctx.WritePlain(" = ")
+		ctx.WriteString(col.Template)
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *DatagenSpec) Accept(v Visitor) (Node, bool) {
+	newNode, _ := v.Enter(n)
+	return v.Leave(newNode)
+}
+
 func (n *ImportIntoStmt) SecureText() string {
 	redactedStmt := *n
 	redactedStmt.Path = RedactURL(n.Path)
@@ -3051,6 +3103,8 @@ const (
 	ShowCreateProcedure
 	ShowBinlogStatus
 	ShowReplicaStatus
+	ShowStatsDistribution
+	ShowStatsIndexes
 )
 
 const (
@@ -3100,6 +3154,8 @@ type ShowStmt struct {
 	ShowProfileLimit *Limit // Used for `SHOW PROFILE` syntax
 
 	ImportJobID *int64 // Used for `SHOW IMPORT JOB <ID>` syntax
+
+	AsOf *AsOfClause // Used for `SHOW STATS_HISTOGRAMS AS OF TIMESTAMP <expr>` syntax
 }
 
 // Restore implements Node interface.
@@ -3137,6 +3193,15 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 		}
 		return nil
 	}
+	restoreShowLimitOpt := func() error {
+		if n.Limit != nil {
+			ctx.WritePlain(" ")
+			if err := n.Limit.Restore(ctx); err != nil {
+				return errors.Annotate(err, "An error occurred while restore ShowStmt.Limit")
+			}
+		}
+		return nil
+	}
 
 	ctx.WriteKeyWord("SHOW ")
 	switch n.Tp {
@@ -3220,6 +3285,12 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 		}
 	case ShowStatsHistograms:
 		ctx.WriteKeyWord("STATS_HISTOGRAMS")
+		if n.AsOf != nil {
+			ctx.WritePlain(" ")
+			if err := n.AsOf.Restore(ctx); err != nil {
+				return errors.Annotate(err, "An error occurred while restore ShowStmt.AsOf")
+			}
+		}
 		if err := restoreShowLikeOrWhereOpt(); err != nil {
 			return err
 		}
@@ -3228,16 +3299,32 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 		if err := restoreShowLikeOrWhereOpt(); err != nil {
 			return err
 		}
+		if err := restoreShowLimitOpt(); err != nil {
+			return err
+		}
 	case ShowStatsBuckets:
 		ctx.WriteKeyWord("STATS_BUCKETS")
 		if err := restoreShowLikeOrWhereOpt(); err != nil {
 			return err
 		}
+		if err := restoreShowLimitOpt(); err != nil {
+			return err
+		}
 	case ShowStatsHealthy:
 		ctx.WriteKeyWord("STATS_HEALTHY")
 		if err := restoreShowLikeOrWhereOpt(); err != nil {
 			return err
 		}
+	case ShowStatsDistribution:
+		ctx.WriteKeyWord("STATS_DISTRIBUTION")
+		if err := restoreShowLikeOrWhereOpt(); err != nil {
+			return err
+		}
+	case ShowStatsIndexes:
+		ctx.WriteKeyWord("STATS_INDEXES")
+		if err := restoreShowLikeOrWhereOpt(); err != nil {
+			return err
+		}
 	case ShowHistogramsInFlight:
 		ctx.WriteKeyWord("HISTOGRAMS_IN_FLIGHT")
 		if err := restoreShowLikeOrWhereOpt(); err != nil {
@@ -3395,6 +3482,10 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 			ctx.WriteKeyWord("BINDING_CACHE STATUS")
 		case ShowAnalyzeStatus:
 			ctx.WriteKeyWord("ANALYZE STATUS")
+			if err := restoreShowLikeOrWhereOpt(); err != nil {
+				return err
+			}
+			return restoreShowLimitOpt()
 		case ShowRegions:
 			ctx.WriteKeyWord("TABLE ")
 			if err := n.Table.Restore(ctx); err != nil {
@@ -3474,6 +3565,13 @@ func (n *ShowStmt) Accept(v Visitor) (Node, bool) {
 		}
 		n.Where = node.(ExprNode)
 	}
+	if n.AsOf != nil {
+		node, ok := n.AsOf.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.AsOf = node.(*AsOfClause)
+	}
 	if n.Limit != nil {
 		node, ok := n.Limit.Accept(v)
 		if !ok {
@@ -3498,8 +3596,8 @@ func (n *ShowStmt) NeedLimitRSRow() bool {
 		//
 		// 2) The STMT is a MySQL syntax extend, so just keep it behavior as before:
 		//    ShowCreateSequence, ShowCreatePlacementPolicy, ShowConfig, ShowStatsExtended,
-		//    ShowStatsMeta, ShowStatsHistograms, ShowStatsTopN, ShowStatsBuckets, ShowStatsHealthy
-		//    ShowHistogramsInFlight, ShowColumnStatsUsage, ShowBindings, ShowBindingCacheStatus,
+		//    ShowStatsMeta, ShowStatsHistograms, ShowStatsTopN, ShowStatsBuckets, ShowStatsHealthy, ShowStatsDistribution
+		//    ShowStatsIndexes, ShowHistogramsInFlight, ShowColumnStatsUsage, ShowBindings, ShowBindingCacheStatus,
 		//    ShowPumpStatus, ShowDrainerStatus, ShowAnalyzeStatus, ShowRegions, ShowBuiltins,
 		//    ShowTableNextRowId, ShowBackups, ShowRestores, ShowImports, ShowCreateImport, ShowPlacement
 		//    ShowPlacementForDatabase, ShowPlacementForTable, ShowPlacementForPartition, ShowPlacementLabels
@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -48,6 +49,7 @@ var (
 	_ StmtNode = &SetDefaultRoleStmt{}
 	_ StmtNode = &SetStmt{}
 	_ StmtNode = &SetSessionStatesStmt{}
+	_ StmtNode = &ResetSessionStatesStmt{}
 	_ StmtNode = &UseStmt{}
 	_ StmtNode = &FlushStmt{}
 	_ StmtNode = &KillStmt{}
@@ -211,6 +213,9 @@ type ExplainStmt struct {
 	Stmt    StmtNode
 	Format  string
 	Analyze bool
+	// StatsOverlay is the JSON of the statistics overlaid on the stats cache in the planning of the statement, which
+	// is specified by `EXPLAIN WITH STATS ('<json>')`.
+	StatsOverlay string
 }
 
 // Restore implements Node interface.
@@ -232,6 +237,12 @@ func (n *ExplainStmt) Restore(ctx *format.RestoreCtx) error {
 	if n.Analyze {
 		ctx.WriteKeyWord("ANALYZE ")
 	}
+	if n.StatsOverlay != "" {
+		ctx.WriteKeyWord("WITH STATS ")
+		ctx.WritePlain("(")
+		ctx.WriteString(n.StatsOverlay)
+		ctx.WritePlain(") ")
+	}
 	if !n.Analyze || strings.ToLower(n.Format) != "row" {
 		ctx.WriteKeyWord("FORMAT ")
 		ctx.WritePlain("= ")
@@ -275,6 +286,9 @@ type PlanReplayerStmt struct {
 
 	SQLDigest  string
 	PlanDigest string
+	// CaptureThresholds indicates 'plan replayer capture <sql_digest> <plan_digest> when <thresholds>', it's nil if the
+	// capture is unconditional.
+	CaptureThresholds *PlanReplayerCaptureThresholds
 
 	// File is used to store 2 cases:
 	// 1. plan replayer load 'file';
@@ -291,6 +305,31 @@ type PlanReplayerStmt struct {
 	Limit *Limit
 }
 
+// PlanReplayerCaptureThresholds are the thresholds of 'plan replayer capture ... when ...', an execution of the digests
+// is captured only if it exceeds any of them. The thresholds which are not specified are zero.
+type PlanReplayerCaptureThresholds struct {
+	// EstimationError is the threshold of the max ratio between the estimated rows and the actual rows of the operators.
+	EstimationError float64
+	// Latency is the threshold of the latency of the execution.
+	Latency time.Duration
+}
+
+// Restore restores the thresholds as the WHEN clause.
+func (t *PlanReplayerCaptureThresholds) Restore(ctx *format.RestoreCtx) {
+	ctx.WriteKeyWord(" WHEN ")
+	if t.EstimationError > 0 {
+		ctx.WriteKeyWord("ESTIMATION_ERROR > ")
+		ctx.WritePlain(strconv.FormatFloat(t.EstimationError, 'f', -1, 64))
+		if t.Latency > 0 {
+			ctx.WriteKeyWord(" OR ")
+		}
+	}
+	if t.Latency > 0 {
+		ctx.WriteKeyWord("LATENCY > ")
+		ctx.WriteString(t.Latency.String())
+	}
+}
+
 // Restore implements Node interface.
 func (n *PlanReplayerStmt) Restore(ctx *format.RestoreCtx) error {
 	if n.Load {
@@ -303,6 +342,9 @@ func (n *PlanReplayerStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteString(n.SQLDigest)
 		ctx.WriteKeyWord(" ")
 		ctx.WriteString(n.PlanDigest)
+		if n.CaptureThresholds != nil {
+			n.CaptureThresholds.Restore(ctx)
+		}
 		return nil
 	}
 	if n.Remove {
@@ -795,6 +837,8 @@ const (
 	SetCharset = "SetCharset"
 	// TiDBCloudStorageURI is the const for set tidb_cloud_storage_uri stmt.
 	TiDBCloudStorageURI = "tidb_cloud_storage_uri"
+	// TiDBPlanReplayerSnapshotStorage is the const for set tidb_plan_replayer_snapshot_storage stmt.
+	TiDBPlanReplayerSnapshotStorage = "tidb_plan_replayer_snapshot_storage"
 )
 
 // VariableAssignment is a variable assignment struct.
@@ -833,7 +877,7 @@ func (n *VariableAssignment) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteName(n.Name)
 		ctx.WritePlain("=")
 	}
-	if n.Name == TiDBCloudStorageURI {
+	if n.Name == TiDBCloudStorageURI || n.Name == TiDBPlanReplayerSnapshotStorage {
 		// need to redact the url for safety when `show processlist;`
 		ctx.WritePlain(RedactURL(n.Value.(ValueExpr).GetString()))
 	} else if err := n.Value.Restore(ctx); err != nil {
@@ -1182,6 +1226,26 @@ func (n *SetSessionStatesStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// ResetSessionStatesStmt is a statement to reset session states while keeping the prepared statements
+// and the plan cache, which is lighter than COM_RESET_CONNECTION.
+type ResetSessionStatesStmt struct {
+	stmtNode
+}
+
+func (*ResetSessionStatesStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("RESET SESSION_STATES")
+	return nil
+}
+
+func (n *ResetSessionStatesStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ResetSessionStatesStmt)
+	return v.Leave(n)
+}
+
 /*
 // SetCharsetStmt is a statement to assign values to character and collation variables.
 // See https://dev.mysql.com/doc/refman/5.7/en/set-statement.html
@@ -2012,10 +2076,12 @@ type RecommendIndexOption struct {
 type RecommendIndexStmt struct {
 	stmtNode
 
-	Action  string
-	SQL     string
-	ID      int64
-	Options []RecommendIndexOption
+	Action string
+	SQL    string
+	// SQLDigest is the digest of the query in the statement summary to recommend indexes for.
+	SQLDigest string
+	ID        int64
+	Options   []RecommendIndexOption
 }
 
 func (n *RecommendIndexStmt) Restore(ctx *format.RestoreCtx) error {
@@ -2026,6 +2092,9 @@ func (n *RecommendIndexStmt) Restore(ctx *format.RestoreCtx) error {
 		if n.SQL != "" {
 			ctx.WriteKeyWord(" FOR ")
 			ctx.WriteString(n.SQL)
+		} else if n.SQLDigest != "" {
+			ctx.WriteKeyWord(" FOR DIGEST ")
+			ctx.WriteString(n.SQLDigest)
 		}
 		if len(n.Options) > 0 {
 			ctx.WriteKeyWord(" WITH ")
@@ -2291,6 +2360,23 @@ const (
 	StatsTypeCardinality uint8 = iota
 	StatsTypeDependency
 	StatsTypeCorrelation
+	// StatsTypeJSONPath is the path-level statistics of a JSON column. It's maintained by ANALYZE automatically
+	// and can't be created by CREATE STATISTICS.
+	StatsTypeJSONPath
+	// StatsTypeForeignKey is the statistics of a foreign key, which tells how the child rows reference the parent
+	// rows. It's maintained by ANALYZE of the child table automatically and can't be created by CREATE STATISTICS.
+	StatsTypeForeignKey
+	// StatsTypeVector is the statistics of a vector column and its vector indexes. It's maintained by ANALYZE
+	// automatically and can't be created by CREATE STATISTICS.
+	StatsTypeVector
+	// StatsTypeTrigram is the trigram statistics of a string column. It's maintained by ANALYZE if
+	// tidb_analyze_collect_trigram_stats is on and can't be created by CREATE STATISTICS.
+	StatsTypeTrigram
+	// StatsTypeHistogram is the 2-dimensional histogram of 2 columns, which tells the joint distribution of them.
+	StatsTypeHistogram
+	// StatsTypeIndexPrefixTopN is the TopN of the prefixes of a composite index. It's maintained by ANALYZE
+	// automatically and can't be created by CREATE STATISTICS.
+	StatsTypeIndexPrefixTopN
 )
 
 // StatisticsSpec is the specification for ADD /DROP STATISTICS.
@@ -2330,6 +2416,8 @@ func (n *CreateStatisticsStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord(" (dependency) ")
 	case StatsTypeCorrelation:
 		ctx.WriteKeyWord(" (correlation) ")
+	case StatsTypeHistogram:
+		ctx.WriteKeyWord(" (histogram) ")
 	}
 	ctx.WriteKeyWord("ON ")
 	if err := n.Table.Restore(ctx); err != nil {
@@ -2470,6 +2558,21 @@ const (
 	AdminShowBDRRole
 	AdminUnsetBDRRole
 	AdminAlterDDLJob
+	AdminRemoveAnalyzeJob
+	AdminBenchStatsLoad
+	AdminValidateVectorIndex
+	AdminShowPlanDiff
+	AdminCalibrateCostModel
+	AdminExportStats
+	AdminCheckStats
+	AdminShowColumnStatsUsage
+	AdminResetColumnStatsUsage
+)
+
+// The formats of the file exported by `ADMIN EXPORT STATS`.
+const (
+	ExportStatsFormatCSV     = "CSV"
+	ExportStatsFormatParquet = "PARQUET"
 )
 
 // HandleRange represents a range where handle value >= Begin and < End.
@@ -2595,6 +2698,15 @@ type AdminStmt struct {
 	LimitSimple     LimitSimple
 	BDRRole         BDRRole
 	AlterJobOptions []*AlterJobOption
+	SampleCount     uint64
+	// CompareWith is the DSN of the external MySQL compared with in `ADMIN CHECKSUM TABLE ... COMPARE WITH CONNECTION`.
+	CompareWith string
+	// ExportURI and ExportFormat are the destination and the file format of `ADMIN EXPORT STATS TO ... FORMAT ...`.
+	ExportURI    string
+	ExportFormat string
+	// CheckStatsThreshold is the drift score of `ADMIN CHECK STATS ... THRESHOLD ...` above which the table is enqueued
+	// for auto analyze, it's nil if THRESHOLD is not specified.
+	CheckStatsThreshold ValueExpr
 }
 
 // Restore implements Node interface.
@@ -2683,6 +2795,10 @@ func (n *AdminStmt) Restore(ctx *format.RestoreCtx) error {
 		if err := restoreTables(); err != nil {
 			return err
 		}
+		if n.CompareWith != "" {
+			ctx.WriteKeyWord(" COMPARE WITH CONNECTION ")
+			ctx.WriteString(n.CompareWith)
+		}
 	case AdminCancelDDLJobs:
 		ctx.WriteKeyWord("CANCEL DDL JOBS ")
 		restoreJobIDs()
@@ -2758,6 +2874,14 @@ func (n *AdminStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("SHOW BDR ROLE")
 	case AdminUnsetBDRRole:
 		ctx.WriteKeyWord("UNSET BDR ROLE")
+	case AdminCalibrateCostModel:
+		ctx.WriteKeyWord("CALIBRATE COST MODEL")
+	case AdminShowPlanDiff:
+		ctx.WriteKeyWord("SHOW PLAN DIFF")
+		if n.LimitSimple.Count > 0 {
+			ctx.WriteKeyWord(" LIMIT ")
+			ctx.WritePlainf("%d", n.LimitSimple.Count)
+		}
 	case AdminAlterDDLJob:
 		ctx.WriteKeyWord("ALTER DDL JOBS ")
 		ctx.WritePlainf("%d", n.JobNumber)
@@ -2770,6 +2894,54 @@ func (n *AdminStmt) Restore(ctx *format.RestoreCtx) error {
 				return errors.Annotatef(err, "An error occurred while restore AdminStmt.AlterJobOptions[%d]", i)
 			}
 		}
+	case AdminRemoveAnalyzeJob:
+		ctx.WriteKeyWord("REMOVE ANALYZE JOB FOR TABLE ")
+		if err := restoreTables(); err != nil {
+			return err
+		}
+	case AdminBenchStatsLoad:
+		ctx.WriteKeyWord("BENCH STATS LOAD ")
+		if err := restoreTables(); err != nil {
+			return err
+		}
+	case AdminCheckStats:
+		ctx.WriteKeyWord("CHECK STATS ")
+		if err := restoreTables(); err != nil {
+			return err
+		}
+		if n.SampleCount > 0 {
+			ctx.WriteKeyWord(" SAMPLE ")
+			ctx.WritePlainf("%d", n.SampleCount)
+		}
+		if n.CheckStatsThreshold != nil {
+			ctx.WriteKeyWord(" THRESHOLD ")
+			ctx.WritePlainf("%v", n.CheckStatsThreshold.GetValue())
+		}
+	case AdminShowColumnStatsUsage:
+		ctx.WriteKeyWord("SHOW COLUMN_STATS_USAGE FOR TABLE ")
+		if err := restoreTables(); err != nil {
+			return err
+		}
+	case AdminResetColumnStatsUsage:
+		ctx.WriteKeyWord("RESET COLUMN_STATS_USAGE FOR TABLE ")
+		if err := restoreTables(); err != nil {
+			return err
+		}
+	case AdminExportStats:
+		ctx.WriteKeyWord("EXPORT STATS TO ")
+		ctx.WriteString(n.ExportURI)
+		ctx.WriteKeyWord(" FORMAT ")
+		ctx.WritePlain(n.ExportFormat)
+	case AdminValidateVectorIndex:
+		ctx.WriteKeyWord("VALIDATE VECTOR INDEX ")
+		if err := restoreTables(); err != nil {
+			return err
+		}
+		ctx.WritePlainf(" %s", n.Index)
+		if n.SampleCount > 0 {
+			ctx.WriteKeyWord(" SAMPLE ")
+			ctx.WritePlainf("%d", n.SampleCount)
+		}
 	default:
 		return errors.New("Unsupported AdminStmt type")
 	}
@@ -2803,6 +2975,25 @@ func (n *AdminStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// SecureText implements SensitiveStmtNode, it hides the password in the DSN of `COMPARE WITH CONNECTION`
+// and the credentials in the URI of `EXPORT STATS`.
+func (n *AdminStmt) SecureText() string {
+	if n.CompareWith == "" && n.ExportURI == "" {
+		return n.Text()
+	}
+	redactedStmt := *n
+	redactedStmt.ExportURI = RedactURL(n.ExportURI)
+	if u, err := url.Parse(n.CompareWith); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "xxxxxx")
+		}
+		redactedStmt.CompareWith = u.String()
+	}
+	var sb strings.Builder
+	_ = redactedStmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb))
+	return sb.String()
+}
+
 // RoleOrPriv is a temporary structure to be further processed into auth.RoleIdentity or PrivElem
 type RoleOrPriv struct {
 	Symbols string      // hold undecided symbols
@@ -3911,7 +4102,7 @@ func (n *TableOptimizerHint) Restore(ctx *format.RestoreCtx) error {
 		ctx.WritePlainf("%d", n.HintData.(int64))
 	case "tidb_hj", "tidb_smj", "tidb_inlj", "hash_join", "hash_join_build", "hash_join_probe", "merge_join", "inl_join",
 		"broadcast_join", "shuffle_join", "inl_hash_join", "inl_merge_join", "leading", "no_hash_join", "no_merge_join",
-		"no_index_join", "no_index_hash_join", "no_index_merge_join":
+		"no_index_join", "no_index_hash_join", "no_index_merge_join", "ignore_stats":
 		for i, table := range n.Tables {
 			if i != 0 {
 				ctx.WritePlain(", ")
@@ -17,12 +17,16 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/parser/format"
 	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 )
 
 var (
 	_ StmtNode = &AnalyzeTableStmt{}
+	_ StmtNode = &AnalyzeDatabaseStmt{}
 	_ StmtNode = &DropStatsStmt{}
 	_ StmtNode = &LoadStatsStmt{}
+	_ StmtNode = &ModifyStatsStmt{}
+	_ StmtNode = &AutoAnalyzeOptionsStmt{}
 )
 
 // AnalyzeTableStmt is used to create table statistics.
@@ -43,6 +47,11 @@ type AnalyzeTableStmt struct {
 	// ColumnNames indicate the columns whose statistics need to be collected.
 	ColumnNames  []model.CIStr
 	ColumnChoice model.ColumnChoice
+	// Priority is set in "ANALYZE TABLE ... PRIORITY LOW|HIGH" statement. It's the priority of the scan requests.
+	Priority mysql.PriorityEnum
+	// Quick is set in "ANALYZE TABLE ... QUICK" statement. The approximate stats are built from a tiny fraction of
+	// the regions, and the row count is estimated by the region metadata.
+	Quick bool
 }
 
 // AnalyzeOptType is the type for analyze options.
@@ -56,16 +65,24 @@ const (
 	AnalyzeOptCMSketchWidth
 	AnalyzeOptNumSamples
 	AnalyzeOptSampleRate
+	AnalyzeOptBlockSampleRate
+	// AnalyzeOptTiFlash reads the data from the TiFlash replica, its value is always 1.
+	AnalyzeOptTiFlash
+	// AnalyzeOptSampling is the name of the sampling strategy, see statistics.SamplingStrategy.
+	AnalyzeOptSampling
 )
 
 // AnalyzeOptionString stores the string form of analyze options.
 var AnalyzeOptionString = map[AnalyzeOptionType]string{
-	AnalyzeOptNumBuckets:    "BUCKETS",
-	AnalyzeOptNumTopN:       "TOPN",
-	AnalyzeOptCMSketchWidth: "CMSKETCH WIDTH",
-	AnalyzeOptCMSketchDepth: "CMSKETCH DEPTH",
-	AnalyzeOptNumSamples:    "SAMPLES",
-	AnalyzeOptSampleRate:    "SAMPLERATE",
+	AnalyzeOptNumBuckets:      "BUCKETS",
+	AnalyzeOptNumTopN:         "TOPN",
+	AnalyzeOptCMSketchWidth:   "CMSKETCH WIDTH",
+	AnalyzeOptCMSketchDepth:   "CMSKETCH DEPTH",
+	AnalyzeOptNumSamples:      "SAMPLES",
+	AnalyzeOptSampleRate:      "SAMPLERATE",
+	AnalyzeOptBlockSampleRate: "BLOCKSAMPLE",
+	AnalyzeOptTiFlash:         "TIFLASH",
+	AnalyzeOptSampling:        "SAMPLING",
 }
 
 // HistogramOperationType is the type for histogram operation.
@@ -152,6 +169,9 @@ func (n *AnalyzeTableStmt) Restore(ctx *format.RestoreCtx) error {
 			ctx.WriteName(columnName.O)
 		}
 	}
+	if n.Quick {
+		ctx.WriteKeyWord(" QUICK")
+	}
 	if n.IndexFlag {
 		ctx.WriteKeyWord(" INDEX")
 	}
@@ -169,10 +189,26 @@ func (n *AnalyzeTableStmt) Restore(ctx *format.RestoreCtx) error {
 			if i != 0 {
 				ctx.WritePlain(",")
 			}
+			if opt.Type == AnalyzeOptTiFlash {
+				ctx.WriteKeyWord(" TIFLASH")
+				continue
+			}
+			if opt.Type == AnalyzeOptSampling {
+				ctx.WritePlain(" ")
+				ctx.WriteString(opt.Value.GetString())
+				ctx.WriteKeyWord(" SAMPLING")
+				continue
+			}
 			ctx.WritePlainf(" %v ", opt.Value.GetValue())
 			ctx.WritePlain(AnalyzeOptionString[opt.Type])
 		}
 	}
+	switch n.Priority {
+	case mysql.LowPriority:
+		ctx.WriteKeyWord(" PRIORITY LOW")
+	case mysql.HighPriority:
+		ctx.WriteKeyWord(" PRIORITY HIGH")
+	}
 	return nil
 }
 
@@ -193,6 +229,43 @@ func (n *AnalyzeTableStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// AnalyzeDatabaseStmt is used to analyze all tables of a database.
+// See ANALYZE DATABASE db [CONCURRENCY n] [BUDGET 'duration'].
+type AnalyzeDatabaseStmt struct {
+	stmtNode
+
+	DBName model.CIStr
+	// Concurrency is the number of tables analyzed at the same time. 0 means using the default value.
+	Concurrency uint64
+	// Budget is the time budget of the statement, e.g. '2h'. Empty means no limit.
+	Budget string
+}
+
+// Restore implements Node interface.
+func (n *AnalyzeDatabaseStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("ANALYZE DATABASE ")
+	ctx.WriteName(n.DBName.O)
+	if n.Concurrency > 0 {
+		ctx.WriteKeyWord(" CONCURRENCY ")
+		ctx.WritePlainf("%d", n.Concurrency)
+	}
+	if n.Budget != "" {
+		ctx.WriteKeyWord(" BUDGET ")
+		ctx.WriteString(n.Budget)
+	}
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *AnalyzeDatabaseStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*AnalyzeDatabaseStmt)
+	return v.Leave(n)
+}
+
 // DropStatsStmt is used to drop table statistics.
 // if the PartitionNames is not empty, or IsGlobalStats is true, it will contain exactly one table
 type DropStatsStmt struct {
@@ -255,12 +328,17 @@ type LoadStatsStmt struct {
 	stmtNode
 
 	Path string
+	// Merge indicates the stats are merged into the existing stats of the table instead of replacing them.
+	Merge bool
 }
 
 // Restore implements Node interface.
 func (n *LoadStatsStmt) Restore(ctx *format.RestoreCtx) error {
 	ctx.WriteKeyWord("LOAD STATS ")
 	ctx.WriteString(n.Path)
+	if n.Merge {
+		ctx.WriteKeyWord(" MERGE")
+	}
 	return nil
 }
 
@@ -349,3 +427,206 @@ func (n *UnlockStatsStmt) Accept(v Visitor) (Node, bool) {
 	}
 	return v.Leave(n)
 }
+
+// ModifyStatsOptionType is the type of the statistics modified by ALTER TABLE ... MODIFY STATS.
+type ModifyStatsOptionType int
+
+// ModifyStatsOptionType types.
+const (
+	ModifyStatsRowCount ModifyStatsOptionType = iota
+	ModifyStatsColNDV
+	ModifyStatsNullRatio
+)
+
+// ModifyStatsOptionString stores the string form of modify stats options.
+var ModifyStatsOptionString = map[ModifyStatsOptionType]string{
+	ModifyStatsRowCount:  "ROW_COUNT",
+	ModifyStatsColNDV:    "COL_NDV",
+	ModifyStatsNullRatio: "NULL_RATIO",
+}
+
+// ModifyStatsOptionTypeByName returns the modify stats option type of the lower case name.
+func ModifyStatsOptionTypeByName(name string) (ModifyStatsOptionType, bool) {
+	switch name {
+	case "row_count":
+		return ModifyStatsRowCount, true
+	case "col_ndv":
+		return ModifyStatsColNDV, true
+	case "null_ratio":
+		return ModifyStatsNullRatio, true
+	}
+	return 0, false
+}
+
+// ModifyStatsOption is a statistics value set by ALTER TABLE ... MODIFY STATS.
+type ModifyStatsOption struct {
+	Tp ModifyStatsOptionType
+	// Column is the column of the statistics. It's empty for ROW_COUNT.
+	Column model.CIStr
+	Value  ValueExpr
+}
+
+// ModifyStatsStmt is the statement node for injecting the statistics of a table manually, like
+// ALTER TABLE t MODIFY STATS SET (ROW_COUNT = 1000, COL_NDV('a') = 10, NULL_RATIO('a') = 0.1).
+type ModifyStatsStmt struct {
+	stmtNode
+
+	Table   *TableName
+	Options []*ModifyStatsOption
+}
+
+// Restore implements Node interface.
+func (n *ModifyStatsStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("ALTER TABLE ")
+	if err := n.Table.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore ModifyStatsStmt.Table")
+	}
+	ctx.WriteKeyWord(" MODIFY STATS SET ")
+	ctx.WritePlain("(")
+	for i, opt := range n.Options {
+		if i != 0 {
+			ctx.WritePlain(", ")
+		}
+		ctx.WriteKeyWord(ModifyStatsOptionString[opt.Tp])
+		if opt.Tp != ModifyStatsRowCount {
+			ctx.WritePlain("(")
+			ctx.WriteString(opt.Column.O)
+			ctx.WritePlain(")")
+		}
+		ctx.WritePlainf(" = %v", opt.Value.GetValue())
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *ModifyStatsStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ModifyStatsStmt)
+	node, ok := n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	return v.Leave(n)
+}
+
+// AutoAnalyzeOptionType is the type of the auto analyze options set by ALTER TABLE ... AUTO_ANALYZE OPTIONS.
+type AutoAnalyzeOptionType int
+
+// AutoAnalyzeOptionType types.
+const (
+	AutoAnalyzeOptionPriority AutoAnalyzeOptionType = iota
+	AutoAnalyzeOptionWindowStart
+	AutoAnalyzeOptionWindowEnd
+	AutoAnalyzeOptionSampleRate
+	AutoAnalyzeOptionColumnChoice
+	AutoAnalyzeOptionDisabled
+	AutoAnalyzeOptionCoolDown
+	AutoAnalyzeOptionResourceGroup
+	AutoAnalyzeOptionTargetQError
+	AutoAnalyzeOptionTargetQErrorPercentile
+)
+
+// AutoAnalyzeOptionString stores the string form of auto analyze options.
+var AutoAnalyzeOptionString = map[AutoAnalyzeOptionType]string{
+	AutoAnalyzeOptionPriority:               "PRIORITY",
+	AutoAnalyzeOptionWindowStart:            "WINDOW_START",
+	AutoAnalyzeOptionWindowEnd:              "WINDOW_END",
+	AutoAnalyzeOptionSampleRate:             "SAMPLE_RATE",
+	AutoAnalyzeOptionColumnChoice:           "COLUMN_CHOICE",
+	AutoAnalyzeOptionDisabled:               "DISABLED",
+	AutoAnalyzeOptionCoolDown:               "COOL_DOWN",
+	AutoAnalyzeOptionResourceGroup:          "RESOURCE_GROUP",
+	AutoAnalyzeOptionTargetQError:           "TARGET_QERROR",
+	AutoAnalyzeOptionTargetQErrorPercentile: "TARGET_QERROR_PERCENTILE",
+}
+
+// AutoAnalyzeOptionTypeByName returns the auto analyze option type of the lower case name.
+func AutoAnalyzeOptionTypeByName(name string) (AutoAnalyzeOptionType, bool) {
+	switch name {
+	case "priority":
+		return AutoAnalyzeOptionPriority, true
+	case "window_start":
+		return AutoAnalyzeOptionWindowStart, true
+	case "window_end":
+		return AutoAnalyzeOptionWindowEnd, true
+	case "sample_rate":
+		return AutoAnalyzeOptionSampleRate, true
+	case "column_choice":
+		return AutoAnalyzeOptionColumnChoice, true
+	case "disabled":
+		return AutoAnalyzeOptionDisabled, true
+	case "cool_down":
+		return AutoAnalyzeOptionCoolDown, true
+	case "resource_group":
+		return AutoAnalyzeOptionResourceGroup, true
+	case "target_qerror":
+		return AutoAnalyzeOptionTargetQError, true
+	case "target_qerror_percentile":
+		return AutoAnalyzeOptionTargetQErrorPercentile, true
+	}
+	return 0, false
+}
+
+// AutoAnalyzeOption is an option set by ALTER TABLE ... AUTO_ANALYZE OPTIONS.
+type AutoAnalyzeOption struct {
+	Tp AutoAnalyzeOptionType
+	// Value is nil for DEFAULT, which resets the option.
+	Value ValueExpr
+}
+
+// AutoAnalyzeOptionsStmt is the statement node for setting the auto analyze options of a table, like
+// ALTER TABLE t AUTO_ANALYZE OPTIONS (PRIORITY = 1, SAMPLE_RATE = 0.1, COOL_DOWN = DEFAULT).
+type AutoAnalyzeOptionsStmt struct {
+	stmtNode
+
+	Table   *TableName
+	Options []*AutoAnalyzeOption
+}
+
+// Restore implements Node interface.
+func (n *AutoAnalyzeOptionsStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("ALTER TABLE ")
+	if err := n.Table.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore AutoAnalyzeOptionsStmt.Table")
+	}
+	ctx.WriteKeyWord(" AUTO_ANALYZE OPTIONS ")
+	ctx.WritePlain("(")
+	for i, opt := range n.Options {
+		if i != 0 {
+			ctx.WritePlain(", ")
+		}
+		ctx.WriteKeyWord(AutoAnalyzeOptionString[opt.Tp])
+		ctx.WritePlain(" = ")
+		if opt.Value == nil {
+			ctx.WriteKeyWord("DEFAULT")
+			continue
+		}
+		if s, ok := opt.Value.GetValue().(string); ok {
+			ctx.WriteString(s)
+			continue
+		}
+		ctx.WritePlainf("%v", opt.Value.GetValue())
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *AutoAnalyzeOptionsStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*AutoAnalyzeOptionsStmt)
+	node, ok := n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	return v.Leave(n)
+}
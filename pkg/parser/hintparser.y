@@ -124,6 +124,7 @@ import (
 	hintLeading               "LEADING"
 	hintSemiJoinRewrite       "SEMI_JOIN_REWRITE"
 	hintNoDecorrelate         "NO_DECORRELATE"
+	hintIgnoreStats           "IGNORE_STATS"
 
 	/* Other keywords */
 	hintOLAP            "OLAP"
@@ -648,6 +649,7 @@ SupportedTableLevelOptimizerHintName:
 |	"HASH_JOIN_PROBE"
 |	"LEADING"
 |	"HYPO_INDEX"
+|	"IGNORE_STATS"
 
 UnsupportedIndexLevelOptimizerHintName:
 	"INDEX_MERGE"
@@ -778,6 +780,7 @@ Identifier:
 |	"LEADING"
 |	"SEMI_JOIN_REWRITE"
 |	"NO_DECORRELATE"
+|	"IGNORE_STATS"
 /* other keywords */
 |	"OLAP"
 |	"OLTP"
@@ -281,6 +281,7 @@ var Keywords = []KeywordsType{
 	{"BACKUPS", false, "unreserved"},
 	{"BDR", false, "unreserved"},
 	{"BEGIN", false, "unreserved"},
+	{"BENCH", false, "unreserved"},
 	{"BERNOULLI", false, "unreserved"},
 	{"BINDING", false, "unreserved"},
 	{"BINDINGS", false, "unreserved"},
@@ -317,6 +318,7 @@ var Keywords = []KeywordsType{
 	{"COMMIT", false, "unreserved"},
 	{"COMMITTED", false, "unreserved"},
 	{"COMPACT", false, "unreserved"},
+	{"COMPARE", false, "unreserved"},
 	{"COMPRESSED", false, "unreserved"},
 	{"COMPRESSION", false, "unreserved"},
 	{"COMPRESSION_LEVEL", false, "unreserved"},
@@ -327,6 +329,7 @@ var Keywords = []KeywordsType{
 	{"CONSISTENCY", false, "unreserved"},
 	{"CONSISTENT", false, "unreserved"},
 	{"CONTEXT", false, "unreserved"},
+	{"COST", false, "unreserved"},
 	{"CPU", false, "unreserved"},
 	{"CSV_BACKSLASH_ESCAPE", false, "unreserved"},
 	{"CSV_DELIMITER", false, "unreserved"},
@@ -338,6 +341,7 @@ var Keywords = []KeywordsType{
 	{"CURRENT", false, "unreserved"},
 	{"CYCLE", false, "unreserved"},
 	{"DATA", false, "unreserved"},
+	{"DATAGEN", false, "unreserved"},
 	{"DATE", false, "unreserved"},
 	{"DATETIME", false, "unreserved"},
 	{"DAY", false, "unreserved"},
@@ -345,6 +349,7 @@ var Keywords = []KeywordsType{
 	{"DECLARE", false, "unreserved"},
 	{"DEFINER", false, "unreserved"},
 	{"DELAY_KEY_WRITE", false, "unreserved"},
+	{"DIFF", false, "unreserved"},
 	{"DIGEST", false, "unreserved"},
 	{"DIRECTORY", false, "unreserved"},
 	{"DISABLE", false, "unreserved"},
@@ -375,6 +380,7 @@ var Keywords = []KeywordsType{
 	{"EXECUTE", false, "unreserved"},
 	{"EXPANSION", false, "unreserved"},
 	{"EXPIRE", false, "unreserved"},
+	{"EXPORT", false, "unreserved"},
 	{"EXTENDED", false, "unreserved"},
 	{"FAILED_LOGIN_ATTEMPTS", false, "unreserved"},
 	{"FAULTS", false, "unreserved"},
@@ -446,6 +452,7 @@ var Keywords = []KeywordsType{
 	{"MINVALUE", false, "unreserved"},
 	{"MIN_ROWS", false, "unreserved"},
 	{"MODE", false, "unreserved"},
+	{"MODEL", false, "unreserved"},
 	{"MODIFY", false, "unreserved"},
 	{"MONTH", false, "unreserved"},
 	{"NAMES", false, "unreserved"},
@@ -475,6 +482,7 @@ var Keywords = []KeywordsType{
 	{"ON_DUPLICATE", false, "unreserved"},
 	{"OPEN", false, "unreserved"},
 	{"OPTIONAL", false, "unreserved"},
+	{"OPTIONS", false, "unreserved"},
 	{"PACK_KEYS", false, "unreserved"},
 	{"PAGE", false, "unreserved"},
 	{"PARSER", false, "unreserved"},
@@ -534,6 +542,7 @@ var Keywords = []KeywordsType{
 	{"ROW_COUNT", false, "unreserved"},
 	{"ROW_FORMAT", false, "unreserved"},
 	{"RTREE", false, "unreserved"},
+	{"SAMPLE", false, "unreserved"},
 	{"SAN", false, "unreserved"},
 	{"SAVEPOINT", false, "unreserved"},
 	{"SECOND", false, "unreserved"},
@@ -599,6 +608,7 @@ var Keywords = []KeywordsType{
 	{"TEMPTABLE", false, "unreserved"},
 	{"TEXT", false, "unreserved"},
 	{"THAN", false, "unreserved"},
+	{"THRESHOLD", false, "unreserved"},
 	{"TIKV_IMPORTER", false, "unreserved"},
 	{"TIME", false, "unreserved"},
 	{"TIMESTAMP", false, "unreserved"},
@@ -622,6 +632,7 @@ var Keywords = []KeywordsType{
 	{"UNKNOWN", false, "unreserved"},
 	{"UNSET", false, "unreserved"},
 	{"USER", false, "unreserved"},
+	{"VALIDATE", false, "unreserved"},
 	{"VALIDATION", false, "unreserved"},
 	{"VALUE", false, "unreserved"},
 	{"VARIABLES", false, "unreserved"},
@@ -639,8 +650,12 @@ var Keywords = []KeywordsType{
 	{"X509", false, "unreserved"},
 	{"YEAR", false, "unreserved"},
 	{"ADMIN", false, "tidb"},
+	{"AUTO_ANALYZE", false, "tidb"},
+	{"AUTO_ANALYZE_COOL_DOWN", false, "tidb"},
 	{"BATCH", false, "tidb"},
+	{"BLOCKSAMPLE", false, "tidb"},
 	{"BUCKETS", false, "tidb"},
+	{"BUDGET", false, "tidb"},
 	{"BUILTINS", false, "tidb"},
 	{"CANCEL", false, "tidb"},
 	{"CARDINALITY", false, "tidb"},
@@ -664,14 +679,17 @@ var Keywords = []KeywordsType{
 	{"RUN", false, "tidb"},
 	{"SAMPLERATE", false, "tidb"},
 	{"SAMPLES", false, "tidb"},
+	{"SAMPLING", false, "tidb"},
 	{"SESSION_STATES", false, "tidb"},
 	{"SPLIT", false, "tidb"},
 	{"STATISTICS", false, "tidb"},
 	{"STATS", false, "tidb"},
 	{"STATS_BUCKETS", false, "tidb"},
+	{"STATS_DISTRIBUTION", false, "tidb"},
 	{"STATS_EXTENDED", false, "tidb"},
 	{"STATS_HEALTHY", false, "tidb"},
 	{"STATS_HISTOGRAMS", false, "tidb"},
+	{"STATS_INDEXES", false, "tidb"},
 	{"STATS_LOCKED", false, "tidb"},
 	{"STATS_META", false, "tidb"},
 	{"STATS_TOPN", false, "tidb"},
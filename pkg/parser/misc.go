@@ -187,6 +187,8 @@ var tokenMap = map[string]int{
 	"STATS_SAMPLE_RATE":        statsSampleRate,
 	"STATS_COL_CHOICE":         statsColChoice,
 	"STATS_COL_LIST":           statsColList,
+	"AUTO_ANALYZE":             autoAnalyze,
+	"AUTO_ANALYZE_COOL_DOWN":   autoAnalyzeCoolDown,
 	"AUTO_ID_CACHE":            autoIdCache,
 	"AUTO_INCREMENT":           autoIncrement,
 	"AUTO_RANDOM":              autoRandom,
@@ -198,6 +200,7 @@ var tokenMap = map[string]int{
 	"BACKUPS":                  backups,
 	"BDR":                      bdr,
 	"BEGIN":                    begin,
+	"BENCH":                    bench,
 	"BETWEEN":                  between,
 	"BERNOULLI":                bernoulli,
 	"BIGINT":                   bigIntType,
@@ -212,6 +215,7 @@ var tokenMap = map[string]int{
 	"BIT":                      bitType,
 	"BLOB":                     blobType,
 	"BLOCK":                    block,
+	"BLOCKSAMPLE":              blockSample,
 	"BOOL":                     boolType,
 	"BOOLEAN":                  booleanType,
 	"BOTH":                     both,
@@ -220,6 +224,7 @@ var tokenMap = map[string]int{
 	"BRIEF":                    briefType,
 	"BTREE":                    btree,
 	"BUCKETS":                  buckets,
+	"BUDGET":                   budget,
 	"BUILTINS":                 builtins,
 	"BURSTABLE":                burstable,
 	"BY":                       by,
@@ -262,6 +267,7 @@ var tokenMap = map[string]int{
 	"COMMIT":                   commit,
 	"COMMITTED":                committed,
 	"COMPACT":                  compact,
+	"COMPARE":                  compare,
 	"COMPRESSED":               compressed,
 	"COMPRESSION":              compression,
 	"CONCURRENCY":              concurrency,
@@ -277,6 +283,7 @@ var tokenMap = map[string]int{
 	"COOLDOWN":                 cooldown,
 	"COPY":                     copyKwd,
 	"CORRELATION":              correlation,
+	"COST":                     costKwd,
 	"CPU":                      cpu,
 	"CREATE":                   create,
 	"CROSS":                    cross,
@@ -309,6 +316,7 @@ var tokenMap = map[string]int{
 	"DATA":                     data,
 	"DATABASE":                 database,
 	"DATABASES":                databases,
+	"DATAGEN":                  datagen,
 	"DATE_ADD":                 dateAdd,
 	"DATE_SUB":                 dateSub,
 	"DATE":                     dateType,
@@ -333,6 +341,7 @@ var tokenMap = map[string]int{
 	"DEPTH":                    depth,
 	"DESC":                     desc,
 	"DESCRIBE":                 describe,
+	"DIFF":                     diff,
 	"DIGEST":                   digest,
 	"DIRECTORY":                directory,
 	"DISABLE":                  disable,
@@ -383,6 +392,7 @@ var tokenMap = map[string]int{
 	"EXPANSION":                expansion,
 	"EXPIRE":                   expire,
 	"EXPLAIN":                  explain,
+	"EXPORT":                   export,
 	"EXPR_PUSHDOWN_BLACKLIST":  exprPushdownBlacklist,
 	"EXTENDED":                 extended,
 	"EXTRACT":                  extract,
@@ -551,6 +561,7 @@ var tokenMap = map[string]int{
 	"MINVALUE":                 minValue,
 	"MOD":                      mod,
 	"MODE":                     mode,
+	"MODEL":                    modelKwd,
 	"MODIFY":                   modify,
 	"MONTH":                    month,
 	"NAMES":                    names,
@@ -596,6 +607,7 @@ var tokenMap = map[string]int{
 	"OPTIMIZE":                 optimize,
 	"OPTION":                   option,
 	"OPTIONAL":                 optional,
+	"OPTIONS":                  options,
 	"OPTIONALLY":               optionally,
 	"OR":                       or,
 	"ORDER":                    order,
@@ -702,6 +714,8 @@ var tokenMap = map[string]int{
 	"S3":                       s3,
 	"SAMPLES":                  samples,
 	"SAMPLERATE":               sampleRate,
+	"SAMPLE":                   sample,
+	"SAMPLING":                 sampling,
 	"SAN":                      san,
 	"SAVEPOINT":                savepoint,
 	"SCHEDULE":                 schedule,
@@ -769,9 +783,11 @@ var tokenMap = map[string]int{
 	"STATISTICS":               statistics,
 	"STATS_AUTO_RECALC":        statsAutoRecalc,
 	"STATS_BUCKETS":            statsBuckets,
+	"STATS_DISTRIBUTION":       statsDistribution,
 	"STATS_EXTENDED":           statsExtended,
 	"STATS_HEALTHY":            statsHealthy,
 	"STATS_HISTOGRAMS":         statsHistograms,
+	"STATS_INDEXES":            statsIndexes,
 	"STATS_TOPN":               statsTopN,
 	"STATS_META":               statsMeta,
 	"STATS_LOCKED":             statsLocked,
@@ -817,6 +833,7 @@ var tokenMap = map[string]int{
 	"TERMINATED":               terminated,
 	"TEXT":                     textType,
 	"THAN":                     than,
+	"THRESHOLD":                threshold,
 	"THEN":                     then,
 	"TIDB":                     tidb,
 	"TIDB_CURRENT_TSO":         tidbCurrentTSO,
@@ -882,6 +899,7 @@ var tokenMap = map[string]int{
 	"UTC_TIME":                 utcTime,
 	"UTC_TIMESTAMP":            utcTimestamp,
 	"UTILIZATION_LIMIT":        utilizationLimit,
+	"VALIDATE":                 validate,
 	"VALIDATION":               validation,
 	"VALUE":                    value,
 	"VALUES":                   values,
@@ -1074,6 +1092,7 @@ var hintTokenMap = map[string]int{
 	"LEADING":                 hintLeading,
 	"SEMI_JOIN_REWRITE":       hintSemiJoinRewrite,
 	"NO_DECORRELATE":          hintNoDecorrelate,
+	"IGNORE_STATS":            hintIgnoreStats,
 
 	// TiDB hint aliases
 	"TIDB_HJ":   hintHashJoin,
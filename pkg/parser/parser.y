@@ -335,6 +335,7 @@ import (
 	backups               "BACKUPS"
 	bdr                   "BDR"
 	begin                 "BEGIN"
+	bench                 "BENCH"
 	bernoulli             "BERNOULLI"
 	binding               "BINDING"
 	bindings              "BINDINGS"
@@ -371,6 +372,7 @@ import (
 	commit                "COMMIT"
 	committed             "COMMITTED"
 	compact               "COMPACT"
+	compare               "COMPARE"
 	compressed            "COMPRESSED"
 	compression           "COMPRESSION"
 	compressionLevel      "COMPRESSION_LEVEL"
@@ -381,6 +383,7 @@ import (
 	consistency           "CONSISTENCY"
 	consistent            "CONSISTENT"
 	context               "CONTEXT"
+	costKwd               "COST"
 	cpu                   "CPU"
 	csvBackslashEscape    "CSV_BACKSLASH_ESCAPE"
 	csvDelimiter          "CSV_DELIMITER"
@@ -392,6 +395,7 @@ import (
 	current               "CURRENT"
 	cycle                 "CYCLE"
 	data                  "DATA"
+	datagen               "DATAGEN"
 	dateType              "DATE"
 	datetimeType          "DATETIME"
 	day                   "DAY"
@@ -399,6 +403,7 @@ import (
 	declare               "DECLARE"
 	definer               "DEFINER"
 	delayKeyWrite         "DELAY_KEY_WRITE"
+	diff                  "DIFF"
 	digest                "DIGEST"
 	directory             "DIRECTORY"
 	disable               "DISABLE"
@@ -429,6 +434,7 @@ import (
 	execute               "EXECUTE"
 	expansion             "EXPANSION"
 	expire                "EXPIRE"
+	export                "EXPORT"
 	extended              "EXTENDED"
 	failedLoginAttempts   "FAILED_LOGIN_ATTEMPTS"
 	faultsSym             "FAULTS"
@@ -500,6 +506,7 @@ import (
 	minValue              "MINVALUE"
 	minRows               "MIN_ROWS"
 	mode                  "MODE"
+	modelKwd              "MODEL"
 	modify                "MODIFY"
 	month                 "MONTH"
 	names                 "NAMES"
@@ -529,6 +536,7 @@ import (
 	onDuplicate           "ON_DUPLICATE"
 	open                  "OPEN"
 	optional              "OPTIONAL"
+	options               "OPTIONS"
 	packKeys              "PACK_KEYS"
 	pageSym               "PAGE"
 	parser                "PARSER"
@@ -589,6 +597,7 @@ import (
 	rowCount              "ROW_COUNT"
 	rowFormat             "ROW_FORMAT"
 	rtree                 "RTREE"
+	sample                "SAMPLE"
 	san                   "SAN"
 	savepoint             "SAVEPOINT"
 	second                "SECOND"
@@ -654,6 +663,7 @@ import (
 	temptable             "TEMPTABLE"
 	textType              "TEXT"
 	than                  "THAN"
+	threshold             "THRESHOLD"
 	tikvImporter          "TIKV_IMPORTER"
 	timeType              "TIME"
 	timestampType         "TIMESTAMP"
@@ -677,6 +687,7 @@ import (
 	unknown               "UNKNOWN"
 	unset                 "UNSET"
 	user                  "USER"
+	validate              "VALIDATE"
 	validation            "VALIDATION"
 	value                 "VALUE"
 	variables             "VARIABLES"
@@ -820,8 +831,12 @@ import (
 
 	/* The following tokens belong to TiDBKeyword. Notice: make sure these tokens are contained in TiDBKeyword. */
 	admin                      "ADMIN"
+	autoAnalyze                "AUTO_ANALYZE"
+	autoAnalyzeCoolDown        "AUTO_ANALYZE_COOL_DOWN"
 	batch                      "BATCH"
+	blockSample                "BLOCKSAMPLE"
 	buckets                    "BUCKETS"
+	budget                     "BUDGET"
 	builtinApproxCountDistinct
 	builtinApproxPercentile
 	builtinBitAnd
@@ -872,14 +887,17 @@ import (
 	run                        "RUN"
 	sampleRate                 "SAMPLERATE"
 	samples                    "SAMPLES"
+	sampling                   "SAMPLING"
 	sessionStates              "SESSION_STATES"
 	split                      "SPLIT"
 	statistics                 "STATISTICS"
 	stats                      "STATS"
 	statsBuckets               "STATS_BUCKETS"
+	statsDistribution          "STATS_DISTRIBUTION"
 	statsExtended              "STATS_EXTENDED"
 	statsHealthy               "STATS_HEALTHY"
 	statsHistograms            "STATS_HISTOGRAMS"
+	statsIndexes               "STATS_INDEXES"
 	statsLocked                "STATS_LOCKED"
 	statsMeta                  "STATS_META"
 	statsTopN                  "STATS_TOPN"
@@ -964,6 +982,7 @@ import (
 	AlterPolicyStmt            "Alter Placement Policy statement"
 	AlterResourceGroupStmt     "Alter Resource Group statement"
 	AlterSequenceStmt          "Alter sequence statement"
+	AnalyzeDatabaseStmt        "Analyze database statement"
 	AnalyzeTableStmt           "Analyze table statement"
 	BeginTransactionStmt       "BEGIN TRANSACTION statement"
 	BinlogStmt                 "Binlog base64 statement"
@@ -1034,6 +1053,7 @@ import (
 	RenameTableStmt            "rename table statement"
 	RenameUserStmt             "rename user statement"
 	ReplaceIntoStmt            "REPLACE INTO statement"
+	ResetSessionStatesStmt     "RESET SESSION_STATES statement"
 	RecoverTableStmt           "recover table statement"
 	RevokeStmt                 "Revoke statement"
 	RevokeRoleStmt             "Revoke role statement"
@@ -1102,19 +1122,25 @@ import (
 	AlterSequenceOption                    "Alter sequence option"
 	AlterSequenceOptionList                "Alter sequence option list"
 	ArrayKwdOpt                            "Array options"
+	AnalyzeDatabaseBudgetOpt               "Optional budget of analyze database"
+	AnalyzeDatabaseConcurrencyOpt          "Optional concurrency of analyze database"
 	AnalyzeOption                          "Analyze option"
 	AnalyzeOptionList                      "Analyze option list"
 	AnalyzeOptionListOpt                   "Optional analyze option list"
+	AnalyzePriorityOpt                     "Optional analyze priority"
 	AnyOrAll                               "Any or All for subquery"
 	Assignment                             "assignment"
 	AssignmentList                         "assignment list"
 	AuthOption                             "User auth option"
+	AutoAnalyzeOption                      "Auto analyze option"
+	AutoAnalyzeOptionList                  "Auto analyze option list"
 	AutoRandomOpt                          "Auto random option"
 	Boolean                                "Boolean (0, 1, false, true)"
 	BDRRole                                "BDR role (primary, secondary)"
 	OptionalBraces                         "optional braces"
 	CastType                               "Cast function target type"
 	CharsetOpt                             "CHARACTER SET option in LOAD DATA"
+	CheckStatsThresholdOpt                 "ADMIN CHECK STATS threshold option"
 	ColumnDef                              "table column definition"
 	ColumnName                             "column name"
 	ColumnNameOrUserVariable               "column name or user variable"
@@ -1146,6 +1172,11 @@ import (
 	CreateTableOptionListOpt               "create table option list opt"
 	CreateTableSelectOpt                   "Select/Union statement in CREATE TABLE ... SELECT"
 	DatabaseOption                         "CREATE Database specification"
+	DatagenColumn                          "DATAGEN column template"
+	DatagenColumnList                      "DATAGEN column template list"
+	DatagenColumnListOpt                   "DATAGEN column template list opt"
+	DatagenRowsNum                         "DATAGEN row count"
+	DatagenSpec                            "DATAGEN data source"
 	DatabaseOptionList                     "CREATE Database specification list"
 	DatabaseOptionListOpt                  "CREATE Database specification list opt"
 	DistinctOpt                            "Explicit distinct option"
@@ -1231,6 +1262,8 @@ import (
 	LogTypeOpt                             "Optional log type used in FLUSH statements"
 	LowPriorityOpt                         "LOAD DATA low priority option"
 	MaxValPartOpt                          "MAXVALUE partition option"
+	ModifyStatsOption                      "Modify stats option"
+	ModifyStatsOptionList                  "Modify stats option list"
 	NullPartOpt                            "NULL Partition option"
 	NumLiteral                             "Num/Int/Float/Decimal Literal"
 	NoWriteToBinLogAliasOpt                "NO_WRITE_TO_BINLOG alias LOCAL or empty"
@@ -1269,6 +1302,8 @@ import (
 	PasswordOrLockOption                   "Single password or lock option for create user statement"
 	PasswordOrLockOptionList               "Password or lock options for create user statement"
 	PasswordOrLockOptions                  "Optional password or lock options for create user statement"
+	PlanReplayerCaptureThreshold           "Plan Replayer Capture threshold"
+	PlanReplayerCaptureThresholdList       "Plan Replayer Capture threshold list"
 	PlanReplayerDumpOpt                    "Plan Replayer Dump option"
 	CommentOrAttributeOption               "Optional comment or attribute option for CREATE/ALTER USER statements"
 	ColumnPosition                         "Column position [First|After ColumnName]"
@@ -1288,6 +1323,7 @@ import (
 	RecommendIndexOption                   "Recommend index option"
 	ReferOpt                               "reference option"
 	ReorganizePartitionRuleOpt             "optional reorganize partition partition list and definitions"
+	RestrictOrCascadeOpt                   "RESTRICT or CASCADE or empty"
 	RequireList                            "require list for tls options"
 	RequireListElement                     "require list element for tls option"
 	ResourceGroupNameOption                "resource group name for user"
@@ -1394,6 +1430,7 @@ import (
 	ValuesStmtList                         "VALUES statement field list"
 	VariableAssignment                     "set variable value"
 	VariableAssignmentList                 "set variable value list"
+	VectorIndexSampleOpt                   "Vector index validation sample option"
 	ViewAlgorithm                          "view algorithm"
 	ViewCheckOption                        "view check option"
 	ViewDefiner                            "view definer"
@@ -1608,6 +1645,7 @@ import (
 	ProcedurceLabelOpt              "Optional Procedure label name"
 
 %precedence empty
+%precedence priority
 %precedence statsExtended
 %precedence as
 %precedence placement
@@ -1747,6 +1785,94 @@ AlterTableStmt:
 			ReplicaKind:    ast.CompactReplicaKindTiFlash,
 		}
 	}
+|	"ALTER" IgnoreOptional "TABLE" TableName "MODIFY" ColumnKeywordOpt IfExists "STATS" "SET" '(' ModifyStatsOptionList ')'
+	{
+		// The optional COLUMN and IF EXISTS are only for resolving the conflicts with MODIFY COLUMN.
+		if $7.(bool) {
+			yylex.AppendError(yylex.Errorf("IF EXISTS is not supported by MODIFY STATS"))
+			return 1
+		}
+		$$ = &ast.ModifyStatsStmt{
+			Table:   $4.(*ast.TableName),
+			Options: $11.([]*ast.ModifyStatsOption),
+		}
+	}
+|	"ALTER" IgnoreOptional "TABLE" TableName "AUTO_ANALYZE" "OPTIONS" '(' AutoAnalyzeOptionList ')'
+	{
+		$$ = &ast.AutoAnalyzeOptionsStmt{
+			Table:   $4.(*ast.TableName),
+			Options: $8.([]*ast.AutoAnalyzeOption),
+		}
+	}
+
+ModifyStatsOptionList:
+	ModifyStatsOption
+	{
+		$$ = []*ast.ModifyStatsOption{$1.(*ast.ModifyStatsOption)}
+	}
+|	ModifyStatsOptionList ',' ModifyStatsOption
+	{
+		$$ = append($1.([]*ast.ModifyStatsOption), $3.(*ast.ModifyStatsOption))
+	}
+
+ModifyStatsOption:
+	Identifier eq NumLiteral
+	{
+		tp, ok := ast.ModifyStatsOptionTypeByName(strings.ToLower($1))
+		if !ok || tp != ast.ModifyStatsRowCount {
+			yylex.AppendError(yylex.Errorf("Unknown statistics option %s", $1))
+			return 1
+		}
+		$$ = &ast.ModifyStatsOption{Tp: tp, Value: ast.NewValueExpr($3, "", "")}
+	}
+|	Identifier '(' stringLit ')' eq NumLiteral
+	{
+		tp, ok := ast.ModifyStatsOptionTypeByName(strings.ToLower($1))
+		if !ok || tp == ast.ModifyStatsRowCount {
+			yylex.AppendError(yylex.Errorf("Unknown statistics option %s", $1))
+			return 1
+		}
+		$$ = &ast.ModifyStatsOption{Tp: tp, Column: model.NewCIStr($3), Value: ast.NewValueExpr($6, "", "")}
+	}
+
+AutoAnalyzeOptionList:
+	AutoAnalyzeOption
+	{
+		$$ = []*ast.AutoAnalyzeOption{$1.(*ast.AutoAnalyzeOption)}
+	}
+|	AutoAnalyzeOptionList ',' AutoAnalyzeOption
+	{
+		$$ = append($1.([]*ast.AutoAnalyzeOption), $3.(*ast.AutoAnalyzeOption))
+	}
+
+AutoAnalyzeOption:
+	Identifier eq NumLiteral
+	{
+		tp, ok := ast.AutoAnalyzeOptionTypeByName(strings.ToLower($1))
+		if !ok {
+			yylex.AppendError(yylex.Errorf("Unknown auto analyze option %s", $1))
+			return 1
+		}
+		$$ = &ast.AutoAnalyzeOption{Tp: tp, Value: ast.NewValueExpr($3, "", "")}
+	}
+|	Identifier eq stringLit
+	{
+		tp, ok := ast.AutoAnalyzeOptionTypeByName(strings.ToLower($1))
+		if !ok {
+			yylex.AppendError(yylex.Errorf("Unknown auto analyze option %s", $1))
+			return 1
+		}
+		$$ = &ast.AutoAnalyzeOption{Tp: tp, Value: ast.NewValueExpr($3, "", "")}
+	}
+|	Identifier eq "DEFAULT"
+	{
+		tp, ok := ast.AutoAnalyzeOptionTypeByName(strings.ToLower($1))
+		if !ok {
+			yylex.AppendError(yylex.Errorf("Unknown auto analyze option %s", $1))
+			return 1
+		}
+		$$ = &ast.AutoAnalyzeOption{Tp: tp}
+	}
 
 ResourceGroupOptionList:
 	DirectResourceGroupOption
@@ -2397,6 +2523,13 @@ AlterTableSpec:
 			PartitionNames: $4.([]model.CIStr),
 		}
 	}
+|	"DROP" "RANGE" "WHERE" Expression
+	{
+		$$ = &ast.AlterTableSpec{
+			Tp:    ast.AlterTableDropRange,
+			Where: $4,
+		}
+	}
 |	"FIRST" "PARTITION" "LESS" "THAN" '(' BitExpr ')' IfExists
 	{
 		partitionMethod := ast.PartitionMethod{Expr: $6}
@@ -3229,23 +3362,27 @@ SplitSyntaxOption:
 	}
 
 AnalyzeTableStmt:
-	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableNameList AllColumnsOrPredicateColumnsOpt AnalyzeOptionListOpt
+	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableNameList AllColumnsOrPredicateColumnsOpt QuickOptional AnalyzeOptionListOpt AnalyzePriorityOpt
+	{
+		$$ = &ast.AnalyzeTableStmt{TableNames: $4.([]*ast.TableName), NoWriteToBinLog: $2.(bool), ColumnChoice: $5.(model.ColumnChoice), Quick: $6.(bool), AnalyzeOpts: $7.([]ast.AnalyzeOpt), Priority: $8.(mysql.PriorityEnum)}
+	}
+|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "INDEX" IndexNameList AnalyzeOptionListOpt AnalyzePriorityOpt
 	{
-		$$ = &ast.AnalyzeTableStmt{TableNames: $4.([]*ast.TableName), NoWriteToBinLog: $2.(bool), ColumnChoice: $5.(model.ColumnChoice), AnalyzeOpts: $6.([]ast.AnalyzeOpt)}
+		$$ = &ast.AnalyzeTableStmt{TableNames: []*ast.TableName{$4.(*ast.TableName)}, NoWriteToBinLog: $2.(bool), IndexNames: $6.([]model.CIStr), IndexFlag: true, AnalyzeOpts: $7.([]ast.AnalyzeOpt), Priority: $8.(mysql.PriorityEnum)}
 	}
-|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "INDEX" IndexNameList AnalyzeOptionListOpt
+|	"ANALYZE" NoWriteToBinLogAliasOpt "INCREMENTAL" "TABLE" TableName AnalyzeOptionListOpt AnalyzePriorityOpt
 	{
-		$$ = &ast.AnalyzeTableStmt{TableNames: []*ast.TableName{$4.(*ast.TableName)}, NoWriteToBinLog: $2.(bool), IndexNames: $6.([]model.CIStr), IndexFlag: true, AnalyzeOpts: $7.([]ast.AnalyzeOpt)}
+		$$ = &ast.AnalyzeTableStmt{TableNames: []*ast.TableName{$5.(*ast.TableName)}, NoWriteToBinLog: $2.(bool), Incremental: true, AnalyzeOpts: $6.([]ast.AnalyzeOpt), Priority: $7.(mysql.PriorityEnum)}
 	}
-|	"ANALYZE" NoWriteToBinLogAliasOpt "INCREMENTAL" "TABLE" TableName "INDEX" IndexNameList AnalyzeOptionListOpt
+|	"ANALYZE" NoWriteToBinLogAliasOpt "INCREMENTAL" "TABLE" TableName "INDEX" IndexNameList AnalyzeOptionListOpt AnalyzePriorityOpt
 	{
-		$$ = &ast.AnalyzeTableStmt{TableNames: []*ast.TableName{$5.(*ast.TableName)}, NoWriteToBinLog: $2.(bool), IndexNames: $7.([]model.CIStr), IndexFlag: true, Incremental: true, AnalyzeOpts: $8.([]ast.AnalyzeOpt)}
+		$$ = &ast.AnalyzeTableStmt{TableNames: []*ast.TableName{$5.(*ast.TableName)}, NoWriteToBinLog: $2.(bool), IndexNames: $7.([]model.CIStr), IndexFlag: true, Incremental: true, AnalyzeOpts: $8.([]ast.AnalyzeOpt), Priority: $9.(mysql.PriorityEnum)}
 	}
-|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "PARTITION" PartitionNameList AllColumnsOrPredicateColumnsOpt AnalyzeOptionListOpt
+|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "PARTITION" PartitionNameList AllColumnsOrPredicateColumnsOpt QuickOptional AnalyzeOptionListOpt AnalyzePriorityOpt
 	{
-		$$ = &ast.AnalyzeTableStmt{TableNames: []*ast.TableName{$4.(*ast.TableName)}, NoWriteToBinLog: $2.(bool), PartitionNames: $6.([]model.CIStr), ColumnChoice: $7.(model.ColumnChoice), AnalyzeOpts: $8.([]ast.AnalyzeOpt)}
+		$$ = &ast.AnalyzeTableStmt{TableNames: []*ast.TableName{$4.(*ast.TableName)}, NoWriteToBinLog: $2.(bool), PartitionNames: $6.([]model.CIStr), ColumnChoice: $7.(model.ColumnChoice), Quick: $8.(bool), AnalyzeOpts: $9.([]ast.AnalyzeOpt), Priority: $10.(mysql.PriorityEnum)}
 	}
-|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "PARTITION" PartitionNameList "INDEX" IndexNameList AnalyzeOptionListOpt
+|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "PARTITION" PartitionNameList "INDEX" IndexNameList AnalyzeOptionListOpt AnalyzePriorityOpt
 	{
 		$$ = &ast.AnalyzeTableStmt{
 			TableNames:      []*ast.TableName{$4.(*ast.TableName)},
@@ -3254,9 +3391,10 @@ AnalyzeTableStmt:
 			IndexNames:      $8.([]model.CIStr),
 			IndexFlag:       true,
 			AnalyzeOpts:     $9.([]ast.AnalyzeOpt),
+			Priority:        $10.(mysql.PriorityEnum),
 		}
 	}
-|	"ANALYZE" NoWriteToBinLogAliasOpt "INCREMENTAL" "TABLE" TableName "PARTITION" PartitionNameList "INDEX" IndexNameList AnalyzeOptionListOpt
+|	"ANALYZE" NoWriteToBinLogAliasOpt "INCREMENTAL" "TABLE" TableName "PARTITION" PartitionNameList "INDEX" IndexNameList AnalyzeOptionListOpt AnalyzePriorityOpt
 	{
 		$$ = &ast.AnalyzeTableStmt{
 			TableNames:      []*ast.TableName{$5.(*ast.TableName)},
@@ -3266,15 +3404,17 @@ AnalyzeTableStmt:
 			IndexFlag:       true,
 			Incremental:     true,
 			AnalyzeOpts:     $10.([]ast.AnalyzeOpt),
+			Priority:        $11.(mysql.PriorityEnum),
 		}
 	}
-|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "UPDATE" "HISTOGRAM" "ON" IdentList AnalyzeOptionListOpt
+|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "UPDATE" "HISTOGRAM" "ON" IdentList AnalyzeOptionListOpt AnalyzePriorityOpt
 	{
 		$$ = &ast.AnalyzeTableStmt{
 			TableNames:         []*ast.TableName{$4.(*ast.TableName)},
 			NoWriteToBinLog:    $2.(bool),
 			ColumnNames:        $8.([]model.CIStr),
 			AnalyzeOpts:        $9.([]ast.AnalyzeOpt),
+			Priority:           $10.(mysql.PriorityEnum),
 			HistogramOperation: ast.HistogramOperationUpdate,
 		}
 	}
@@ -3287,16 +3427,18 @@ AnalyzeTableStmt:
 			HistogramOperation: ast.HistogramOperationDrop,
 		}
 	}
-|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "COLUMNS" IdentList AnalyzeOptionListOpt
+|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "COLUMNS" IdentList AnalyzeOptionListOpt AnalyzePriorityOpt
 	{
 		$$ = &ast.AnalyzeTableStmt{
 			TableNames:      []*ast.TableName{$4.(*ast.TableName)},
 			NoWriteToBinLog: $2.(bool),
 			ColumnNames:     $6.([]model.CIStr),
 			ColumnChoice:    model.ColumnList,
-			AnalyzeOpts:     $7.([]ast.AnalyzeOpt)}
+			AnalyzeOpts:     $7.([]ast.AnalyzeOpt),
+			Priority:        $8.(mysql.PriorityEnum),
+		}
 	}
-|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "PARTITION" PartitionNameList "COLUMNS" IdentList AnalyzeOptionListOpt
+|	"ANALYZE" NoWriteToBinLogAliasOpt "TABLE" TableName "PARTITION" PartitionNameList "COLUMNS" IdentList AnalyzeOptionListOpt AnalyzePriorityOpt
 	{
 		$$ = &ast.AnalyzeTableStmt{
 			TableNames:      []*ast.TableName{$4.(*ast.TableName)},
@@ -3304,7 +3446,22 @@ AnalyzeTableStmt:
 			PartitionNames:  $6.([]model.CIStr),
 			ColumnNames:     $8.([]model.CIStr),
 			ColumnChoice:    model.ColumnList,
-			AnalyzeOpts:     $9.([]ast.AnalyzeOpt)}
+			AnalyzeOpts:     $9.([]ast.AnalyzeOpt),
+			Priority:        $10.(mysql.PriorityEnum),
+		}
+	}
+
+AnalyzePriorityOpt:
+	{
+		$$ = mysql.NoPriority
+	}
+|	"PRIORITY" "LOW"
+	{
+		$$ = mysql.LowPriority
+	}
+|	"PRIORITY" "HIGH"
+	{
+		$$ = mysql.HighPriority
 	}
 
 AllColumnsOrPredicateColumnsOpt:
@@ -3321,6 +3478,34 @@ AllColumnsOrPredicateColumnsOpt:
 		$$ = model.PredicateColumns
 	}
 
+AnalyzeDatabaseStmt:
+	"ANALYZE" DatabaseSym DBName AnalyzeDatabaseConcurrencyOpt AnalyzeDatabaseBudgetOpt
+	{
+		$$ = &ast.AnalyzeDatabaseStmt{
+			DBName:      model.NewCIStr($3),
+			Concurrency: $4.(uint64),
+			Budget:      $5.(string),
+		}
+	}
+
+AnalyzeDatabaseConcurrencyOpt:
+	{
+		$$ = uint64(0)
+	}
+|	"CONCURRENCY" LengthNum
+	{
+		$$ = $2.(uint64)
+	}
+
+AnalyzeDatabaseBudgetOpt:
+	{
+		$$ = ""
+	}
+|	"BUDGET" stringLit
+	{
+		$$ = $2
+	}
+
 AnalyzeOptionListOpt:
 	{
 		$$ = []ast.AnalyzeOpt{}
@@ -3365,6 +3550,18 @@ AnalyzeOption:
 	{
 		$$ = ast.AnalyzeOpt{Type: ast.AnalyzeOptSampleRate, Value: ast.NewValueExpr($1, "", "")}
 	}
+|	NumLiteral "BLOCKSAMPLE"
+	{
+		$$ = ast.AnalyzeOpt{Type: ast.AnalyzeOptBlockSampleRate, Value: ast.NewValueExpr($1, "", "")}
+	}
+|	"TIFLASH"
+	{
+		$$ = ast.AnalyzeOpt{Type: ast.AnalyzeOptTiFlash, Value: ast.NewValueExpr(uint64(1), "", "")}
+	}
+|	stringLit "SAMPLING"
+	{
+		$$ = ast.AnalyzeOpt{Type: ast.AnalyzeOptSampling, Value: ast.NewValueExpr($1, "", "")}
+	}
 
 /*******************************************************************************************/
 Assignment:
@@ -4145,6 +4342,10 @@ StatsType:
 	{
 		$$ = ast.StatsTypeCorrelation
 	}
+|	"HISTOGRAM"
+	{
+		$$ = ast.StatsTypeHistogram
+	}
 
 BindingStatusType:
 	"ENABLED"
@@ -4490,6 +4691,7 @@ CreateTableStmt:
 		}
 		stmt.OnDuplicate = $9.(ast.OnDuplicateKeyHandlingType)
 		stmt.Select = $11.(*ast.CreateTableStmt).Select
+		stmt.Datagen = $11.(*ast.CreateTableStmt).Datagen
 		if ($12 != nil && stmt.TemporaryKeyword != ast.TemporaryGlobal) || (stmt.TemporaryKeyword == ast.TemporaryGlobal && $12 == nil) {
 			yylex.AppendError(yylex.Errorf("GLOBAL TEMPORARY and ON COMMIT DELETE ROWS must appear together"))
 		} else {
@@ -5013,6 +5215,51 @@ CreateTableSelectOpt:
 		}
 		$$ = &ast.CreateTableStmt{Select: sel}
 	}
+|	DatagenSpec
+	{
+		$$ = &ast.CreateTableStmt{Datagen: $1.(*ast.DatagenSpec)}
+	}
+
+DatagenSpec:
+	"DATAGEN" '(' "ROWS" EqOpt DatagenRowsNum DatagenColumnListOpt ')'
+	{
+		$$ = &ast.DatagenSpec{
+			Rows:    $5.(uint64),
+			Columns: $6.([]*ast.DatagenColumn),
+		}
+	}
+
+DatagenRowsNum:
+	LengthNum
+|	floatLit
+	{
+		$$ = uint64($1.(float64))
+	}
+
+DatagenColumnListOpt:
+	{
+		$$ = []*ast.DatagenColumn{}
+	}
+|	',' DatagenColumnList
+	{
+		$$ = $2
+	}
+
+DatagenColumnList:
+	DatagenColumn
+	{
+		$$ = []*ast.DatagenColumn{$1.(*ast.DatagenColumn)}
+	}
+|	DatagenColumnList ',' DatagenColumn
+	{
+		$$ = append($1.([]*ast.DatagenColumn), $3.(*ast.DatagenColumn))
+	}
+
+DatagenColumn:
+	Identifier EqOpt stringLit
+	{
+		$$ = &ast.DatagenColumn{Name: model.NewCIStr($1), Template: $3}
+	}
 
 CreateViewSelectOpt:
 	SetOprStmt
@@ -5312,7 +5559,7 @@ DropIndexStmt:
 DropTableStmt:
 	"DROP" OptTemporary TableOrTables IfExists TableNameList RestrictOrCascadeOpt
 	{
-		$$ = &ast.DropTableStmt{IfExists: $4.(bool), Tables: $5.([]*ast.TableName), IsView: false, TemporaryKeyword: $2.(ast.TemporaryKeyword)}
+		$$ = &ast.DropTableStmt{IfExists: $4.(bool), Tables: $5.([]*ast.TableName), IsView: false, TemporaryKeyword: $2.(ast.TemporaryKeyword), Behavior: $6.(ast.DropBehavior)}
 	}
 
 OptTemporary:
@@ -5332,11 +5579,11 @@ OptTemporary:
 DropViewStmt:
 	"DROP" "VIEW" TableNameList RestrictOrCascadeOpt
 	{
-		$$ = &ast.DropTableStmt{Tables: $3.([]*ast.TableName), IsView: true}
+		$$ = &ast.DropTableStmt{Tables: $3.([]*ast.TableName), IsView: true, Behavior: $4.(ast.DropBehavior)}
 	}
 |	"DROP" "VIEW" "IF" "EXISTS" TableNameList RestrictOrCascadeOpt
 	{
-		$$ = &ast.DropTableStmt{IfExists: true, Tables: $5.([]*ast.TableName), IsView: true}
+		$$ = &ast.DropTableStmt{IfExists: true, Tables: $5.([]*ast.TableName), IsView: true, Behavior: $6.(ast.DropBehavior)}
 	}
 
 DropUserStmt:
@@ -5394,9 +5641,17 @@ DropStatsStmt:
 	}
 
 RestrictOrCascadeOpt:
-	{}
+	{
+		$$ = ast.DropBehaviorDefault
+	}
 |	"RESTRICT"
+	{
+		$$ = ast.DropBehaviorRestrict
+	}
 |	"CASCADE"
+	{
+		$$ = ast.DropBehaviorCascade
+	}
 
 TableOrTables:
 	"TABLE"
@@ -5544,6 +5799,30 @@ ExplainStmt:
 			Analyze: true,
 		}
 	}
+|	ExplainSym "WITH" "STATS" '(' stringLit ')' ExplainableStmt
+	{
+		$$ = &ast.ExplainStmt{
+			Stmt:         $7,
+			Format:       "row",
+			StatsOverlay: $5,
+		}
+	}
+|	ExplainSym "WITH" "STATS" '(' stringLit ')' "FORMAT" "=" ExplainFormatType ExplainableStmt
+	{
+		$$ = &ast.ExplainStmt{
+			Stmt:         $10,
+			Format:       $9,
+			StatsOverlay: $5,
+		}
+	}
+|	ExplainSym "WITH" "STATS" '(' stringLit ')' "FORMAT" "=" stringLit ExplainableStmt
+	{
+		$$ = &ast.ExplainStmt{
+			Stmt:         $10,
+			Format:       $9,
+			StatsOverlay: $5,
+		}
+	}
 
 ExplainFormatType:
 	"TRADITIONAL"
@@ -6751,6 +7030,7 @@ UnReservedKeyword:
 |	"AVG"
 |	"BDR"
 |	"BEGIN"
+|	"BENCH"
 |	"BIT"
 |	"BOOL"
 |	"BOOLEAN"
@@ -6764,14 +7044,17 @@ UnReservedKeyword:
 |	"CHARSET"
 |	"COLUMNS"
 |	"CONFIG"
+|	"SAMPLE"
 |	"SAN"
 |	"COMMIT"
 |	"COMPACT"
+|	"COMPARE"
 |	"COMPRESSED"
 |	"CONSISTENCY"
 |	"CONSISTENT"
 |	"CURRENT"
 |	"DATA"
+|	"DATAGEN"
 |	"DATE" %prec lowerThanStringLitToken
 |	"DATETIME"
 |	"DAY"
@@ -6842,6 +7125,7 @@ UnReservedKeyword:
 |	"TABLESPACE"
 |	"TEXT"
 |	"THAN"
+|	"THRESHOLD"
 |	"TIME" %prec lowerThanStringLitToken
 |	"TIMESTAMP" %prec lowerThanStringLitToken
 |	"TRACE"
@@ -6855,6 +7139,8 @@ UnReservedKeyword:
 |	"WARNINGS"
 |	"YEAR"
 |	"MODE"
+|	"MODEL"
+|	"COST"
 |	"WEEK"
 |	"WEIGHT_STRING"
 |	"ANY"
@@ -6958,6 +7244,7 @@ UnReservedKeyword:
 |	"X509"
 |	"NEVER"
 |	"EXPIRE"
+|	"EXPORT"
 |	"ACCOUNT"
 |	"INCREMENTAL"
 |	"CPU"
@@ -6991,6 +7278,7 @@ UnReservedKeyword:
 |	"SECONDARY_ENGINE"
 |	"SECONDARY_LOAD"
 |	"SECONDARY_UNLOAD"
+|	"VALIDATE"
 |	"VALIDATION"
 |	"WITHOUT"
 |	"RTREE"
@@ -7078,6 +7366,7 @@ UnReservedKeyword:
 |	"RESUME"
 |	"OFF"
 |	"OPTIONAL"
+|	"OPTIONS"
 |	"REQUIRED"
 |	"PURGE"
 |	"SKIP"
@@ -7092,6 +7381,7 @@ UnReservedKeyword:
 |	"TTL_JOB_INTERVAL"
 |	"FAILED_LOGIN_ATTEMPTS"
 |	"PASSWORD_LOCK_TIME"
+|	"DIFF"
 |	"DIGEST"
 |	"REUSE" %prec lowerThanEq
 |	"DECLARE"
@@ -7117,8 +7407,12 @@ UnReservedKeyword:
 
 TiDBKeyword:
 	"ADMIN"
+|	"AUTO_ANALYZE"
+|	"AUTO_ANALYZE_COOL_DOWN"
 |	"BATCH"
+|	"BLOCKSAMPLE"
 |	"BUCKETS"
+|	"BUDGET"
 |	"BUILTINS"
 |	"CANCEL"
 |	"CARDINALITY"
@@ -7134,13 +7428,16 @@ TiDBKeyword:
 |	"NODE_STATE"
 |	"SAMPLES"
 |	"SAMPLERATE"
+|	"SAMPLING"
 |	"SESSION_STATES"
 |	"STATISTICS"
-|	"STATS"
+|	"STATS" %prec lowerThanSetKeyword
 |	"STATS_BUCKETS"
+|	"STATS_DISTRIBUTION"
 |	"STATS_EXTENDED"
 |	"STATS_HEALTHY"
 |	"STATS_HISTOGRAMS"
+|	"STATS_INDEXES"
 |	"STATS_LOCKED"
 |	"STATS_META"
 |	"STATS_TOPN"
@@ -9949,6 +10246,7 @@ IndexHint:
 	}
 
 IndexNameList:
+	%prec empty
 	{
 		var nameList []model.CIStr
 		$$ = nameList
@@ -10744,6 +11042,12 @@ SetStmt:
 		$$ = &ast.SetResourceGroupStmt{Name: model.NewCIStr($4)}
 	}
 
+ResetSessionStatesStmt:
+	"RESET" "SESSION_STATES"
+	{
+		$$ = &ast.ResetSessionStatesStmt{}
+	}
+
 SetRoleStmt:
 	"SET" "ROLE" SetRoleOpt
 	{
@@ -11188,6 +11492,18 @@ AdminStmt:
 			Tables: $4.([]*ast.TableName),
 		}
 	}
+|	"ADMIN" "CHECK" "STATS" TableName VectorIndexSampleOpt CheckStatsThresholdOpt
+	{
+		stmt := &ast.AdminStmt{
+			Tp:          ast.AdminCheckStats,
+			Tables:      []*ast.TableName{$4.(*ast.TableName)},
+			SampleCount: $5.(uint64),
+		}
+		if $6 != nil {
+			stmt.CheckStatsThreshold = $6.(ast.ValueExpr)
+		}
+		$$ = stmt
+	}
 |	"ADMIN" "CHECK" "INDEX" TableName Identifier
 	{
 		$$ = &ast.AdminStmt{
@@ -11228,6 +11544,14 @@ AdminStmt:
 			Tables: $4.([]*ast.TableName),
 		}
 	}
+|	"ADMIN" "CHECKSUM" "TABLE" TableName "COMPARE" "WITH" "CONNECTION" stringLit
+	{
+		$$ = &ast.AdminStmt{
+			Tp:          ast.AdminChecksumTable,
+			Tables:      []*ast.TableName{$4.(*ast.TableName)},
+			CompareWith: $8,
+		}
+	}
 |	"ADMIN" "CANCEL" "DDL" "JOBS" NumList
 	{
 		$$ = &ast.AdminStmt{
@@ -11367,12 +11691,82 @@ AdminStmt:
 			Tp: ast.AdminShowBDRRole,
 		}
 	}
+|	"ADMIN" "SHOW" "PLAN" "DIFF"
+	{
+		$$ = &ast.AdminStmt{
+			Tp: ast.AdminShowPlanDiff,
+		}
+	}
+|	"ADMIN" "SHOW" "PLAN" "DIFF" "LIMIT" LengthNum
+	{
+		stmt := &ast.AdminStmt{
+			Tp: ast.AdminShowPlanDiff,
+		}
+		stmt.LimitSimple.Count = $6.(uint64)
+		$$ = stmt
+	}
+|	"ADMIN" "CALIBRATE" "COST" "MODEL"
+	{
+		$$ = &ast.AdminStmt{
+			Tp: ast.AdminCalibrateCostModel,
+		}
+	}
 |	"ADMIN" "UNSET" "BDR" "ROLE"
 	{
 		$$ = &ast.AdminStmt{
 			Tp: ast.AdminUnsetBDRRole,
 		}
 	}
+|	"ADMIN" "REMOVE" "ANALYZE" "JOB" "FOR" "TABLE" TableName
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminRemoveAnalyzeJob,
+			Tables: []*ast.TableName{$7.(*ast.TableName)},
+		}
+	}
+|	"ADMIN" "BENCH" "STATS" "LOAD" TableName
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminBenchStatsLoad,
+			Tables: []*ast.TableName{$5.(*ast.TableName)},
+		}
+	}
+|	"ADMIN" "SHOW" "COLUMN_STATS_USAGE" "FOR" "TABLE" TableName
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminShowColumnStatsUsage,
+			Tables: []*ast.TableName{$6.(*ast.TableName)},
+		}
+	}
+|	"ADMIN" "RESET" "COLUMN_STATS_USAGE" "FOR" "TABLE" TableName
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminResetColumnStatsUsage,
+			Tables: []*ast.TableName{$6.(*ast.TableName)},
+		}
+	}
+|	"ADMIN" "EXPORT" "STATS" "TO" stringLit "FORMAT" Identifier
+	{
+		format := strings.ToUpper($7)
+		if format != ast.ExportStatsFormatCSV && format != ast.ExportStatsFormatParquet {
+			yylex.AppendError(yylex.Errorf("Unknown format %s of ADMIN EXPORT STATS, it should be CSV or PARQUET", $7))
+			return 1
+		}
+		$$ = &ast.AdminStmt{
+			Tp:           ast.AdminExportStats,
+			ExportURI:    $5,
+			ExportFormat: format,
+		}
+	}
+|	"ADMIN" "VALIDATE" "VECTOR" "INDEX" TableName Identifier VectorIndexSampleOpt
+	{
+		$$ = &ast.AdminStmt{
+			Tp:          ast.AdminValidateVectorIndex,
+			Tables:      []*ast.TableName{$5.(*ast.TableName)},
+			Index:       string($6),
+			SampleCount: $7.(uint64),
+		}
+	}
 |	"ADMIN" "ALTER" "DDL" "JOBS" Int64Num AlterJobOptionList
 	{
 		$$ = &ast.AdminStmt{
@@ -11382,6 +11776,24 @@ AdminStmt:
 		}
 	}
 
+VectorIndexSampleOpt:
+	{
+		$$ = uint64(0)
+	}
+|	"SAMPLE" LengthNum
+	{
+		$$ = $2
+	}
+
+CheckStatsThresholdOpt:
+	{
+		$$ = nil
+	}
+|	"THRESHOLD" NumLiteral
+	{
+		$$ = ast.NewValueExpr($2, "", "")
+	}
+
 AlterJobOptionList:
 	AlterJobOption
 	{
@@ -11654,6 +12066,51 @@ ShowStmt:
 			ImportJobID: &v,
 		}
 	}
+|	"SHOW" "ANALYZE" "STATUS" ShowLikeOrWhereOpt SelectStmtLimitOpt
+	{
+		stmt := &ast.ShowStmt{Tp: ast.ShowAnalyzeStatus}
+		if $4 != nil {
+			if x, ok := $4.(*ast.PatternLikeOrIlikeExpr); ok && x.Expr == nil {
+				stmt.Pattern = x
+			} else {
+				stmt.Where = $4.(ast.ExprNode)
+			}
+		}
+		if $5 != nil {
+			stmt.Limit = $5.(*ast.Limit)
+		}
+		$$ = stmt
+	}
+|	"SHOW" "STATS_TOPN" ShowLikeOrWhereOpt SelectStmtLimitOpt
+	{
+		stmt := &ast.ShowStmt{Tp: ast.ShowStatsTopN}
+		if $3 != nil {
+			if x, ok := $3.(*ast.PatternLikeOrIlikeExpr); ok && x.Expr == nil {
+				stmt.Pattern = x
+			} else {
+				stmt.Where = $3.(ast.ExprNode)
+			}
+		}
+		if $4 != nil {
+			stmt.Limit = $4.(*ast.Limit)
+		}
+		$$ = stmt
+	}
+|	"SHOW" "STATS_BUCKETS" ShowLikeOrWhereOpt SelectStmtLimitOpt
+	{
+		stmt := &ast.ShowStmt{Tp: ast.ShowStatsBuckets, Table: &ast.TableName{Name: model.NewCIStr("STATS_BUCKETS"), Schema: model.NewCIStr(mysql.SystemDB)}}
+		if $3 != nil {
+			if x, ok := $3.(*ast.PatternLikeOrIlikeExpr); ok && x.Expr == nil {
+				stmt.Pattern = x
+			} else {
+				stmt.Where = $3.(ast.ExprNode)
+			}
+		}
+		if $4 != nil {
+			stmt.Limit = $4.(*ast.Limit)
+		}
+		$$ = stmt
+	}
 |	"SHOW" "CREATE" "PROCEDURE" TableName
 	{
 		$$ = &ast.ShowStmt{
@@ -11944,18 +12401,27 @@ ShowTargetFilterable:
 	{
 		$$ = &ast.ShowStmt{Tp: ast.ShowStatsHistograms, Table: &ast.TableName{Name: model.NewCIStr("STATS_HISTOGRAMS"), Schema: model.NewCIStr(mysql.SystemDB)}}
 	}
-|	"STATS_TOPN"
-	{
-		$$ = &ast.ShowStmt{Tp: ast.ShowStatsTopN}
-	}
-|	"STATS_BUCKETS"
+|	"STATS_HISTOGRAMS" asof "TIMESTAMP" BitExpr
 	{
-		$$ = &ast.ShowStmt{Tp: ast.ShowStatsBuckets, Table: &ast.TableName{Name: model.NewCIStr("STATS_BUCKETS"), Schema: model.NewCIStr(mysql.SystemDB)}}
+		// Use BitExpr instead of Expression to avoid the conflict with `LIKE` in ShowLikeOrWhereOpt.
+		$$ = &ast.ShowStmt{
+			Tp:    ast.ShowStatsHistograms,
+			Table: &ast.TableName{Name: model.NewCIStr("STATS_HISTOGRAMS"), Schema: model.NewCIStr(mysql.SystemDB)},
+			AsOf:  &ast.AsOfClause{TsExpr: $4.(ast.ExprNode)},
+		}
 	}
 |	"STATS_HEALTHY"
 	{
 		$$ = &ast.ShowStmt{Tp: ast.ShowStatsHealthy}
 	}
+|	"STATS_DISTRIBUTION"
+	{
+		$$ = &ast.ShowStmt{Tp: ast.ShowStatsDistribution}
+	}
+|	"STATS_INDEXES"
+	{
+		$$ = &ast.ShowStmt{Tp: ast.ShowStatsIndexes}
+	}
 |	"STATS_LOCKED"
 	{
 		$$ = &ast.ShowStmt{Tp: ast.ShowStatsLocked, Table: &ast.TableName{Name: model.NewCIStr("STATS_TABLE_LOCKED"), Schema: model.NewCIStr(mysql.SystemDB)}}
@@ -11968,10 +12434,6 @@ ShowTargetFilterable:
 	{
 		$$ = &ast.ShowStmt{Tp: ast.ShowColumnStatsUsage}
 	}
-|	"ANALYZE" "STATUS"
-	{
-		$$ = &ast.ShowStmt{Tp: ast.ShowAnalyzeStatus}
-	}
 |	"BACKUPS"
 	{
 		$$ = &ast.ShowStmt{Tp: ast.ShowBackups}
@@ -12201,6 +12663,7 @@ Statement:
 |	AlterSequenceStmt
 |	AlterPolicyStmt
 |	AlterResourceGroupStmt
+|	AnalyzeDatabaseStmt
 |	AnalyzeTableStmt
 |	BeginTransactionStmt
 |	BinlogStmt
@@ -12281,6 +12744,7 @@ Statement:
 		}
 		$$ = sel
 	}
+|	ResetSessionStatesStmt
 |	SetStmt
 |	SetBindingStmt
 |	SetRoleStmt
@@ -12568,14 +13032,10 @@ TableOption:
 			return 1
 		}
 		$$ = &ast.TableOption{Tp: ast.TableOptionStatsAutoRecalc, UintValue: n}
-		yylex.AppendError(yylex.Errorf("The STATS_AUTO_RECALC is parsed but ignored by all storage engines."))
-		parser.lastErrorAsWarn()
 	}
 |	"STATS_AUTO_RECALC" EqOpt "DEFAULT"
 	{
 		$$ = &ast.TableOption{Tp: ast.TableOptionStatsAutoRecalc, Default: true}
-		yylex.AppendError(yylex.Errorf("The STATS_AUTO_RECALC is parsed but ignored by all storage engines."))
-		parser.lastErrorAsWarn()
 	}
 |	"STATS_SAMPLE_PAGES" EqOpt LengthNum
 	{
@@ -12602,6 +13062,10 @@ TableOption:
 	{
 		$$ = &ast.TableOption{Tp: ast.TableOptionStatsTopN, UintValue: $3.(uint64)}
 	}
+|	"AUTO_ANALYZE_COOL_DOWN" EqOpt LengthNum
+	{
+		$$ = &ast.TableOption{Tp: ast.TableOptionAutoAnalyzeCoolDown, UintValue: $3.(uint64)}
+	}
 |	"STATS_SAMPLE_RATE" EqOpt NumLiteral
 	{
 		$$ = &ast.TableOption{Tp: ast.TableOptionStatsSampleRate, Value: ast.NewValueExpr($3, "", "")}
@@ -14270,6 +14734,16 @@ RecommendIndexStmt:
 			Options: $6.([]ast.RecommendIndexOption),
 		}
 
+		$$ = x
+	}
+|	"RECOMMEND" "INDEX" "RUN" "FOR" "DIGEST" stringLit RecommendIndexOptionListOpt
+	{
+		x := &ast.RecommendIndexStmt{
+			Action:    "run",
+			SQLDigest: $6,
+			Options:   $7.([]ast.RecommendIndexOption),
+		}
+
 		$$ = x
 	}
 |	"RECOMMEND" "INDEX" "RUN" RecommendIndexOptionListOpt
@@ -15055,6 +15529,16 @@ ImportIntoStmt:
 		}
 		$$ = st
 	}
+|	"IMPORT" "INTO" TableName ColumnNameOrUserVarListOptWithBrackets LoadDataSetSpecOpt "FROM" DatagenSpec LoadDataOptionListOpt
+	{
+		$$ = &ast.ImportIntoStmt{
+			Table:              $3.(*ast.TableName),
+			ColumnsAndUserVars: $4.([]*ast.ColumnNameOrUserVar),
+			ColumnAssignments:  $5.([]*ast.Assignment),
+			Datagen:            $7.(*ast.DatagenSpec),
+			Options:            $8.([]*ast.LoadDataOpt),
+		}
+	}
 
 ImportFromSelectStmt:
 	SelectStmt
@@ -15255,6 +15739,13 @@ LoadStatsStmt:
 			Path: $3,
 		}
 	}
+|	"LOAD" "STATS" stringLit "MERGE"
+	{
+		$$ = &ast.LoadStatsStmt{
+			Path:  $3,
+			Merge: true,
+		}
+	}
 
 LockStatsStmt:
 	"LOCK" "STATS" TableNameList
@@ -15491,11 +15982,12 @@ SignedNum:
 	}
 
 DropSequenceStmt:
-	"DROP" "SEQUENCE" IfExists TableNameList
+	"DROP" "SEQUENCE" IfExists TableNameList RestrictOrCascadeOpt
 	{
 		$$ = &ast.DropSequenceStmt{
 			IfExists:  $3.(bool),
 			Sequences: $4.([]*ast.TableName),
+			Behavior:  $5.(ast.DropBehavior),
 		}
 	}
 
@@ -15735,6 +16227,22 @@ PlanReplayerStmt:
 			Limit:      nil,
 		}
 
+		$$ = x
+	}
+|	"PLAN" "REPLAYER" "CAPTURE" stringLit stringLit "WHEN" PlanReplayerCaptureThresholdList
+	{
+		x := &ast.PlanReplayerStmt{
+			Stmt:              nil,
+			Analyze:           false,
+			Capture:           true,
+			SQLDigest:         $4,
+			PlanDigest:        $5,
+			CaptureThresholds: $7.(*ast.PlanReplayerCaptureThresholds),
+			Where:             nil,
+			OrderBy:           nil,
+			Limit:             nil,
+		}
+
 		$$ = x
 	}
 |	"PLAN" "REPLAYER" "CAPTURE" "REMOVE" stringLit stringLit
@@ -15753,6 +16261,48 @@ PlanReplayerStmt:
 		$$ = x
 	}
 
+PlanReplayerCaptureThresholdList:
+	PlanReplayerCaptureThreshold
+|	PlanReplayerCaptureThresholdList "OR" PlanReplayerCaptureThreshold
+	{
+		t, other := $1.(*ast.PlanReplayerCaptureThresholds), $3.(*ast.PlanReplayerCaptureThresholds)
+		if (t.EstimationError > 0 && other.EstimationError > 0) || (t.Latency > 0 && other.Latency > 0) {
+			yylex.AppendError(yylex.Errorf("Dupliated capture thresholds specified"))
+			return 1
+		}
+		t.EstimationError += other.EstimationError
+		t.Latency += other.Latency
+		$$ = t
+	}
+
+PlanReplayerCaptureThreshold:
+	Identifier '>' NumLiteral
+	{
+		if strings.ToLower($1) != "estimation_error" {
+			yylex.AppendError(yylex.Errorf("Unknown capture threshold %s", $1))
+			return 1
+		}
+		v, ok := getFloat64FromNUM($3)
+		if !ok || v <= 0 {
+			yylex.AppendError(yylex.Errorf("The ESTIMATION_ERROR threshold must be a positive number"))
+			return 1
+		}
+		$$ = &ast.PlanReplayerCaptureThresholds{EstimationError: v}
+	}
+|	Identifier '>' stringLit
+	{
+		if strings.ToLower($1) != "latency" {
+			yylex.AppendError(yylex.Errorf("Unknown capture threshold %s", $1))
+			return 1
+		}
+		d, err := time.ParseDuration($3)
+		if err != nil || d <= 0 {
+			yylex.AppendError(yylex.Errorf("The LATENCY threshold is not a valid duration: %s", $3))
+			return 1
+		}
+		$$ = &ast.PlanReplayerCaptureThresholds{Latency: d}
+	}
+
 PlanReplayerDumpOpt:
 	{
 		$$ = nil
@@ -34,17 +34,20 @@ const (
 	FeatureIDResourceGroup = "resource_group"
 	// FeatureIDGlobalIndex is the `Global Index` feature.
 	FeatureIDGlobalIndex = "global_index"
+	// FeatureIDAutoAnalyzeCoolDown is the `auto_analyze_cool_down` table option.
+	FeatureIDAutoAnalyzeCoolDown = "auto_analyze_cool_down"
 )
 
 var featureIDs = map[string]struct{}{
-	FeatureIDAutoRandom:     {},
-	FeatureIDAutoIDCache:    {},
-	FeatureIDAutoRandomBase: {},
-	FeatureIDClusteredIndex: {},
-	FeatureIDForceAutoInc:   {},
-	FeatureIDPlacement:      {},
-	FeatureIDTTL:            {},
-	FeatureIDGlobalIndex:    {},
+	FeatureIDAutoRandom:          {},
+	FeatureIDAutoIDCache:         {},
+	FeatureIDAutoRandomBase:      {},
+	FeatureIDClusteredIndex:      {},
+	FeatureIDForceAutoInc:        {},
+	FeatureIDPlacement:           {},
+	FeatureIDTTL:                 {},
+	FeatureIDGlobalIndex:         {},
+	FeatureIDAutoAnalyzeCoolDown: {},
 }
 
 // CanParseFeature is used to check if a feature can be parsed.
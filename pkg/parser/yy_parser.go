@@ -339,6 +339,21 @@ func getUint64FromNUM(num interface{}) uint64 {
 	return 0
 }
 
+func getFloat64FromNUM(num interface{}) (float64, bool) {
+	switch v := num.(type) {
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case fmt.Stringer:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
 func getInt64FromNUM(num interface{}) (val int64, errMsg string) {
 	switch v := num.(type) {
 	case int64:
@@ -0,0 +1,116 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinality
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/planner/planctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+)
+
+// inListEstimationBatchSize is the number of the items of an in-list estimated in a batch, which bounds the memory of
+// the ranges built for the estimation.
+const inListEstimationBatchSize = 1024
+
+// getInListSelectivity estimates the selectivity of `col IN (c1, c2, ...)` whose ranges are not built when calculating
+// the selectivity, e.g. the ranges of a long in-list exceed tidb_opt_range_max_size. Each distinct item is estimated
+// against the TopN and the histogram of the column like a point range, one batch of items after another.
+// The result is cached in the session, so the variants of a parameterized query with the same items, such as the
+// re-optimizations of a prepared statement, don't estimate them again.
+func getInListSelectivity(ctx planctx.PlanContext, coll *statistics.HistColl, expr expression.Expression) (float64, bool) {
+	sf, ok := expr.(*expression.ScalarFunction)
+	if !ok || sf.FuncName.L != ast.In || coll.RealtimeCount <= 0 {
+		return 0, false
+	}
+	args := sf.GetArgs()
+	col, ok := args[0].(*expression.Column)
+	if !ok {
+		return 0, false
+	}
+	c := coll.GetCol(col.UniqueID)
+	if statistics.ColumnStatsIsInvalid(c, ctx, coll, col.UniqueID) {
+		return 0, false
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	evalCtx := ctx.GetExprCtx().GetEvalCtx()
+	items := make([]expression.Expression, 0, len(args)-1)
+	seen := make(map[string]struct{}, len(args)-1)
+	hasher := fnv.New128a()
+	var key []byte
+	for _, arg := range args[1:] {
+		con, ok := arg.(*expression.Constant)
+		if !ok {
+			return 0, false
+		}
+		val, err := con.Eval(evalCtx, chunk.Row{})
+		if err != nil {
+			return 0, false
+		}
+		if val.IsNull() {
+			// NULL matches no rows.
+			continue
+		}
+		key, err = codec.EncodeKey(sc.TimeZone(), key[:0], val)
+		if err != nil {
+			return 0, false
+		}
+		if _, ok := seen[string(key)]; ok {
+			continue
+		}
+		seen[string(key)] = struct{}{}
+		hasher.Write(key)
+		// The parameters are evaluated, so the estimation doesn't prevent the plan from being cached.
+		items = append(items, &expression.Constant{Value: val, RetType: con.RetType})
+	}
+
+	vars := ctx.GetSessionVars()
+	cacheKey := fmt.Sprintf("%d_%d_%d_%d_%d_%x", coll.PhysicalID, c.Histogram.ID, c.LastUpdateVersion,
+		coll.RealtimeCount, coll.ModifyCount, hasher.Sum(nil))
+	if sel, ok := vars.GetInListSelectivity(cacheKey); ok {
+		return sel, true
+	}
+	var rowCount float64
+	for start := 0; start < len(items); start += inListEstimationBatchSize {
+		batch := items[start:min(start+inListEstimationBatchSize, len(items))]
+		cond, err := expression.NewFunction(ctx.GetExprCtx(), ast.In, types.NewFieldType(mysql.TypeLonglong),
+			append([]expression.Expression{col}, batch...)...)
+		if err != nil {
+			return 0, false
+		}
+		// The memory of the ranges of a batch is bounded by the batch size, so they're always built.
+		ranges, accessConds, _, err := ranger.BuildColumnRange([]expression.Expression{cond}, ctx.GetRangerCtx(),
+			col.RetType, types.UnspecifiedLength, 0)
+		if err != nil || len(accessConds) == 0 {
+			return 0, false
+		}
+		cnt, err := GetRowCountByColumnRanges(ctx, coll, col.UniqueID, ranges)
+		if err != nil {
+			return 0, false
+		}
+		rowCount += cnt
+	}
+	sel := min(rowCount/float64(coll.RealtimeCount), 1)
+	vars.SetInListSelectivity(cacheKey, sel)
+	return sel, true
+}
@@ -15,11 +15,16 @@
 package cardinality
 
 import (
+	"cmp"
 	"math"
+	"slices"
 
 	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/planner/planctx"
 	"github.com/pingcap/tidb/pkg/planner/property"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
 )
 
 // EstimateFullJoinRowCount estimates the row count of a full join.
@@ -49,3 +54,186 @@ func EstimateFullJoinRowCount(sctx planctx.PlanContext,
 	// This estimation logic is referred to Presto.
 	return count * math.Pow(0.9, float64(len(leftJoinKeys)-max(leftColCnt, rightColCnt)))
 }
+
+// histJoinPoint is a value of the join key and its row count, which is a TopN value or the upper bound of a bucket.
+type histJoinPoint struct {
+	value float64
+	count float64
+}
+
+// histJoinRange is the values of the join key between the bounds of a bucket, excluding the upper bound.
+type histJoinRange struct {
+	lower, upper float64
+	count, ndv   float64
+}
+
+// EstimateHistJoinRowCount estimates the row count of an equi-join on a single join key by joining the histograms and
+// TopN of the join key columns, instead of the NDV-based containment assumption which can't capture the skewed keys.
+// The TopN values are joined with the values of the other side exactly, and the histogram buckets are joined by their
+// overlapped ranges assuming the values are uniformly distributed in the buckets. The result is scaled by the row
+// counts of the two sides, assuming the filters of them are independent of the join keys.
+// It only supports the numeric columns analyzed by stats version 2, since the bounds of the string columns can't be
+// compared across the histograms.
+func EstimateHistJoinRowCount(leftRowCount, rightRowCount float64, leftCol, rightCol *statistics.Column) (float64, bool) {
+	leftPoints, leftRanges, ok := buildHistJoinInput(leftCol)
+	if !ok {
+		return 0, false
+	}
+	rightPoints, rightRanges, ok := buildHistJoinInput(rightCol)
+	if !ok {
+		return 0, false
+	}
+	leftPointMap := make(map[float64]float64, len(leftPoints))
+	for _, p := range leftPoints {
+		leftPointMap[p.value] += p.count
+	}
+	rightPointMap := make(map[float64]float64, len(rightPoints))
+	for _, p := range rightPoints {
+		rightPointMap[p.value] += p.count
+	}
+	var count float64
+	for v, cnt := range leftPointMap {
+		if rightCnt, ok := rightPointMap[v]; ok {
+			count += cnt * rightCnt
+		} else {
+			count += cnt * histJoinRangeDensity(rightRanges, v)
+		}
+	}
+	for v, cnt := range rightPointMap {
+		// The values matched with the points of the left side are counted above.
+		if _, ok := leftPointMap[v]; !ok {
+			count += cnt * histJoinRangeDensity(leftRanges, v)
+		}
+	}
+	// The ranges are sorted and disjoint, so the overlapped ones are found by merging them.
+	for i, j := 0, 0; i < len(leftRanges) && j < len(rightRanges); {
+		l, r := leftRanges[i], rightRanges[j]
+		lower, upper := max(l.lower, r.lower), min(l.upper, r.upper)
+		if lower < upper {
+			leftFrac, rightFrac := histJoinRangeFraction(l, lower, upper), histJoinRangeFraction(r, lower, upper)
+			ndv := max(l.ndv*leftFrac, r.ndv*rightFrac, 1)
+			count += l.count * leftFrac * r.count * rightFrac / ndv
+		}
+		if l.upper < r.upper {
+			i++
+		} else {
+			j++
+		}
+	}
+	leftTotal, rightTotal := leftCol.TotalRowCount(), rightCol.TotalRowCount()
+	if leftTotal <= 0 || rightTotal <= 0 {
+		return 0, false
+	}
+	return count * (leftRowCount / leftTotal) * (rightRowCount / rightTotal), true
+}
+
+// buildHistJoinInput converts the TopN and the histogram of the column to the points and the sorted ranges.
+func buildHistJoinInput(c *statistics.Column) ([]histJoinPoint, []histJoinRange, bool) {
+	if c == nil || c.StatsVer < statistics.Version2 || !c.IsFullLoad() || c.Info == nil {
+		return nil, nil, false
+	}
+	switch c.Info.FieldType.EvalType() {
+	case types.ETInt, types.ETReal, types.ETDecimal:
+	default:
+		return nil, nil, false
+	}
+	hg := &c.Histogram
+	points := make([]histJoinPoint, 0, c.TopN.Num()+hg.Len())
+	if c.TopN != nil {
+		for _, meta := range c.TopN.TopN {
+			_, d, err := codec.DecodeOne(meta.Encoded)
+			if err != nil {
+				return nil, nil, false
+			}
+			v, ok := histJoinScalar(&d)
+			if !ok {
+				return nil, nil, false
+			}
+			points = append(points, histJoinPoint{value: v, count: float64(meta.Count)})
+		}
+	}
+	ranges := make([]histJoinRange, 0, hg.Len())
+	for i := 0; i < hg.Len(); i++ {
+		lower, ok := histJoinScalar(hg.GetLower(i))
+		if !ok {
+			return nil, nil, false
+		}
+		upper, ok := histJoinScalar(hg.GetUpper(i))
+		if !ok {
+			return nil, nil, false
+		}
+		bkt := hg.Buckets[i]
+		if bkt.Repeat > 0 {
+			points = append(points, histJoinPoint{value: upper, count: float64(bkt.Repeat)})
+		}
+		rangeCount := float64(hg.BucketCount(i) - bkt.Repeat)
+		if rangeCount <= 0 || lower >= upper {
+			continue
+		}
+		var ndv float64
+		if bkt.NDV > 1 {
+			ndv = float64(bkt.NDV - 1)
+		} else if hg.NotNullCount() > 0 {
+			ndv = rangeCount * float64(hg.NDV) / hg.NotNullCount()
+		}
+		ranges = append(ranges, histJoinRange{lower: lower, upper: upper, count: rangeCount, ndv: max(ndv, 1)})
+	}
+	if len(points) == 0 && len(ranges) == 0 {
+		return nil, nil, false
+	}
+	slices.SortFunc(ranges, func(a, b histJoinRange) int {
+		return cmp.Compare(a.lower, b.lower)
+	})
+	return points, ranges, true
+}
+
+// histJoinScalar converts the numeric value to a float64, whose order is the same as the value.
+func histJoinScalar(d *types.Datum) (float64, bool) {
+	switch d.Kind() {
+	case types.KindInt64:
+		return float64(d.GetInt64()), true
+	case types.KindUint64:
+		return float64(d.GetUint64()), true
+	case types.KindFloat32:
+		return float64(d.GetFloat32()), true
+	case types.KindFloat64:
+		return d.GetFloat64(), true
+	case types.KindMysqlDecimal:
+		v, err := d.GetMysqlDecimal().ToFloat64()
+		return v, err == nil
+	}
+	return 0, false
+}
+
+// histJoinRangeDensity returns the average row count of a value in the range containing the value.
+func histJoinRangeDensity(ranges []histJoinRange, v float64) float64 {
+	// Find the first range whose upper bound is greater than v, since the upper bound is excluded.
+	i, _ := slices.BinarySearchFunc(ranges, v, func(r histJoinRange, v float64) int {
+		if r.upper <= v {
+			return -1
+		}
+		return 1
+	})
+	if i < len(ranges) && ranges[i].lower <= v {
+		return ranges[i].count / ranges[i].ndv
+	}
+	return 0
+}
+
+// histJoinRangeFraction returns the fraction of the range between lower and upper.
+func histJoinRangeFraction(r histJoinRange, lower, upper float64) float64 {
+	return (upper - lower) / (r.upper - r.lower)
+}
+
+// EstimateFKJoinRowCount estimates the row count of the join between the child rows and the parent rows on the foreign
+// key. Different from the NDV-based containment assumption, each child row references exactly one parent row unless
+// the foreign key is violated, so the result is the child rows whose parents are kept by the filters on the parent,
+// which are assumed to be independent of the references.
+// parentTableRowCount is the row count of the whole parent table before the filters.
+func EstimateFKJoinRowCount(childRowCount, parentRowCount, parentTableRowCount float64, fk *statistics.ForeignKeyStats) float64 {
+	parentSelectivity := 1.0
+	if parentTableRowCount > 0 {
+		parentSelectivity = min(parentRowCount/parentTableRowCount, 1)
+	}
+	return childRowCount * fk.MatchRatio * parentSelectivity
+}
@@ -0,0 +1,224 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinality
+
+import (
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/planner/planctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+)
+
+// jsonPathArg is an argument referring to a path of a JSON column, such as `j->'$.a'`, `j->>'$.a'` and a virtual
+// generated column defined by them.
+type jsonPathArg struct {
+	stats *statistics.JSONPathStats
+	path  string
+	// unquoted means the value on the path is unquoted, so it's compared as a string rather than a JSON value.
+	unquoted bool
+}
+
+// getJSONPathSelectivity estimates the selectivity of the predicates on the paths of the JSON columns by the JSON
+// path stats. It supports `path = const`, `const MEMBER OF (path)` and `JSON_CONTAINS(path, const)`, where the
+// const of JSON_CONTAINS must be a scalar.
+func getJSONPathSelectivity(ctx planctx.PlanContext, coll *statistics.HistColl, expr expression.Expression) (float64, bool) {
+	sf, ok := expr.(*expression.ScalarFunction)
+	if !ok {
+		return 0, false
+	}
+	args := sf.GetArgs()
+	if len(args) != 2 {
+		return 0, false
+	}
+	evalCtx := ctx.GetExprCtx().GetEvalCtx()
+	switch sf.FuncName.L {
+	case ast.EQ:
+		arg, ok := extractJSONPathArg(evalCtx, coll, args[0])
+		c := args[1]
+		if !ok {
+			arg, ok = extractJSONPathArg(evalCtx, coll, args[1])
+			c = args[0]
+		}
+		if !ok {
+			return 0, false
+		}
+		match, ok := buildJSONPathValueMatcher(ctx, c, arg.unquoted, false)
+		if !ok {
+			return 0, false
+		}
+		return arg.stats.ValueSelectivity(arg.path, match)
+	case ast.JSONMemberOf:
+		arg, ok := extractJSONPathArg(evalCtx, coll, args[1])
+		if !ok || arg.unquoted {
+			return 0, false
+		}
+		match, ok := buildJSONPathValueMatcher(ctx, args[0], false, false)
+		if !ok {
+			return 0, false
+		}
+		// MEMBER OF checks the elements of an array, or the value itself if it's a scalar.
+		elemSel, elemOK := arg.stats.ValueSelectivity(arg.path+"[*]", match)
+		scalarSel, scalarOK := arg.stats.ValueSelectivity(arg.path, match)
+		return mergeJSONPathSelectivity(elemSel, elemOK, scalarSel, scalarOK)
+	case ast.JSONContains:
+		arg, ok := extractJSONPathArg(evalCtx, coll, args[0])
+		if !ok || arg.unquoted {
+			return 0, false
+		}
+		match, ok := buildJSONPathValueMatcher(ctx, args[1], false, true)
+		if !ok {
+			return 0, false
+		}
+		// A scalar is contained by an array containing it, or a scalar equal to it.
+		elemSel, elemOK := arg.stats.ValueSelectivity(arg.path+"[*]", match)
+		scalarSel, scalarOK := arg.stats.ValueSelectivity(arg.path, match)
+		return mergeJSONPathSelectivity(elemSel, elemOK, scalarSel, scalarOK)
+	}
+	return 0, false
+}
+
+func mergeJSONPathSelectivity(sel1 float64, ok1 bool, sel2 float64, ok2 bool) (float64, bool) {
+	switch {
+	case ok1 && ok2:
+		return min(sel1+sel2, 1), true
+	case ok1:
+		return sel1, true
+	case ok2:
+		return sel2, true
+	}
+	return 0, false
+}
+
+// extractJSONPathArg extracts the JSON column and the path referred by the expression.
+func extractJSONPathArg(evalCtx expression.EvalContext, coll *statistics.HistColl, expr expression.Expression) (jsonPathArg, bool) {
+	switch x := expr.(type) {
+	case *expression.Column:
+		if x.VirtualExpr != nil {
+			return extractJSONPathArg(evalCtx, coll, x.VirtualExpr)
+		}
+		if x.GetType(evalCtx).GetType() != mysql.TypeJSON {
+			return jsonPathArg{}, false
+		}
+		stats, ok := coll.JSONPathStats[x.UniqueID]
+		if !ok {
+			return jsonPathArg{}, false
+		}
+		return jsonPathArg{stats: stats, path: "$"}, true
+	case *expression.ScalarFunction:
+		args := x.GetArgs()
+		switch x.FuncName.L {
+		case ast.JSONExtract:
+			if len(args) != 2 {
+				return jsonPathArg{}, false
+			}
+			arg, ok := extractJSONPathArg(evalCtx, coll, args[0])
+			if !ok || arg.path != "$" || arg.unquoted {
+				return jsonPathArg{}, false
+			}
+			c, ok := args[1].(*expression.Constant)
+			if !ok || c.ParamMarker != nil || c.DeferredExpr != nil || c.Value.Kind() != types.KindString {
+				return jsonPathArg{}, false
+			}
+			pe, err := types.ParseJSONPathExpr(c.Value.GetString())
+			if err != nil || pe.CouldMatchMultipleValues() {
+				return jsonPathArg{}, false
+			}
+			arg.path = pe.String()
+			return arg, true
+		case ast.JSONUnquote:
+			arg, ok := extractJSONPathArg(evalCtx, coll, args[0])
+			if !ok {
+				return jsonPathArg{}, false
+			}
+			arg.unquoted = true
+			return arg, true
+		case ast.Cast:
+			// Casting a JSON value to a string keeps the quotes, which can't be estimated.
+			if args[0].GetType(evalCtx).GetType() == mysql.TypeJSON && x.GetType(evalCtx).EvalType() == types.ETString {
+				return jsonPathArg{}, false
+			}
+			arg, ok := extractJSONPathArg(evalCtx, coll, args[0])
+			if !ok {
+				return jsonPathArg{}, false
+			}
+			arg.unquoted = true
+			return arg, true
+		}
+	}
+	return jsonPathArg{}, false
+}
+
+// buildJSONPathValueMatcher builds a function to match the scalar values on the path with the constant.
+// If unquoted is true, the values are unquoted and compared with the constant as strings. If parseJSON is true,
+// the constant string is parsed as a JSON text, like the candidate of JSON_CONTAINS.
+func buildJSONPathValueMatcher(ctx planctx.PlanContext, expr expression.Expression, unquoted, parseJSON bool) (func(types.BinaryJSON) bool, bool) {
+	c, ok := expr.(*expression.Constant)
+	if !ok || expression.MaybeOverOptimized4PlanCache(ctx.GetExprCtx(), []expression.Expression{c}) {
+		return nil, false
+	}
+	val, err := c.Eval(ctx.GetExprCtx().GetEvalCtx(), chunk.Row{})
+	if err != nil || val.IsNull() {
+		return nil, false
+	}
+	if unquoted {
+		str, err := val.ToString()
+		if err != nil {
+			return nil, false
+		}
+		return func(bj types.BinaryJSON) bool {
+			s, err := bj.Unquote()
+			return err == nil && s == str
+		}, true
+	}
+	var target types.BinaryJSON
+	switch val.Kind() {
+	case types.KindMysqlJSON:
+		target = val.GetMysqlJSON()
+	case types.KindString, types.KindBytes:
+		if parseJSON {
+			target, err = types.ParseBinaryJSONFromString(val.GetString())
+			if err != nil {
+				return nil, false
+			}
+		} else {
+			target = types.CreateBinaryJSON(val.GetString())
+		}
+	case types.KindInt64:
+		target = types.CreateBinaryJSON(val.GetInt64())
+	case types.KindUint64:
+		target = types.CreateBinaryJSON(val.GetUint64())
+	case types.KindFloat32:
+		target = types.CreateBinaryJSON(float64(val.GetFloat32()))
+	case types.KindFloat64:
+		target = types.CreateBinaryJSON(val.GetFloat64())
+	case types.KindMysqlDecimal:
+		f, err := val.GetMysqlDecimal().ToFloat64()
+		if err != nil {
+			return nil, false
+		}
+		target = types.CreateBinaryJSON(f)
+	default:
+		return nil, false
+	}
+	if target.TypeCode == types.JSONTypeCodeObject || target.TypeCode == types.JSONTypeCodeArray {
+		return nil, false
+	}
+	return func(bj types.BinaryJSON) bool {
+		return types.CompareBinaryJSON(bj, target) == 0
+	}, true
+}
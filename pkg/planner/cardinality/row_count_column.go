@@ -16,6 +16,7 @@ package cardinality
 
 import (
 	"math"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/planner/planctx"
@@ -61,6 +62,9 @@ func GetRowCountByColumnRanges(sctx planctx.PlanContext, coll *statistics.HistCo
 		if err == nil && sc.EnableOptimizerCETrace && c != nil {
 			ceTraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, colRanges, "Column Stats-Pseudo", uint64(result))
 		}
+		if err == nil && selTraceEnabled(sctx) && name != "" {
+			selTraceRanges(sctx, coll.PhysicalID, []string{name}, "", colRanges, selTraceMethodPseudo, result, coll.RealtimeCount)
+		}
 		return result, err
 	}
 	if sctx.GetSessionVars().StmtCtx.EnableOptimizerDebugTrace {
@@ -110,6 +114,9 @@ func GetRowCountByIntColumnRanges(sctx planctx.PlanContext, coll *statistics.His
 		if sc.EnableOptimizerCETrace && c != nil {
 			ceTraceRange(sctx, coll.PhysicalID, []string{c.Info.Name.O}, intRanges, "Column Stats-Pseudo", uint64(result))
 		}
+		if selTraceEnabled(sctx) && name != "" {
+			selTraceRanges(sctx, coll.PhysicalID, []string{name}, "", intRanges, selTraceMethodPseudo, result, coll.RealtimeCount)
+		}
 		return result, nil
 	}
 	if sctx.GetSessionVars().StmtCtx.EnableOptimizerDebugTrace {
@@ -126,8 +133,9 @@ func GetRowCountByIntColumnRanges(sctx planctx.PlanContext, coll *statistics.His
 	return result, errors.Trace(err)
 }
 
-// equalRowCountOnColumn estimates the row count by a slice of Range and a Datum.
-func equalRowCountOnColumn(sctx planctx.PlanContext, c *statistics.Column, val types.Datum, encodedVal []byte, realtimeRowCount, modifyCount int64) (result float64, err error) {
+// equalRowCountOnColumn estimates the row count by a slice of Range and a Datum. It also returns the method used to
+// estimate it for the selectivity trace.
+func equalRowCountOnColumn(sctx planctx.PlanContext, c *statistics.Column, val types.Datum, encodedVal []byte, realtimeRowCount, modifyCount int64) (result float64, method string, err error) {
 	if sctx.GetSessionVars().StmtCtx.EnableOptimizerDebugTrace {
 		debugtrace.EnterContextCommon(sctx)
 		debugtrace.RecordAnyValuesWithNames(sctx, "Value", val.String(), "Encoded", encodedVal)
@@ -137,40 +145,40 @@ func equalRowCountOnColumn(sctx planctx.PlanContext, c *statistics.Column, val t
 		}()
 	}
 	if val.IsNull() {
-		return float64(c.NullCount), nil
+		return float64(c.NullCount), selTraceMethodNullCount, nil
 	}
 	if c.StatsVer < statistics.Version2 {
 		// All the values are null.
 		if c.Histogram.Bounds.NumRows() == 0 {
-			return 0.0, nil
+			return 0.0, selTraceMethodEmptyStats, nil
 		}
 		if c.Histogram.NDV > 0 && c.OutOfRange(val) {
-			return outOfRangeEQSelectivity(sctx, c.Histogram.NDV, realtimeRowCount, int64(c.TotalRowCount())) * c.TotalRowCount(), nil
+			return outOfRangeEQSelectivity(sctx, c.Histogram.NDV, realtimeRowCount, int64(c.TotalRowCount())) * c.TotalRowCount(), selTraceMethodOutOfRange, nil
 		}
 		if c.CMSketch != nil {
 			count, err := statistics.QueryValue(sctx, c.CMSketch, c.TopN, val)
-			return float64(count), errors.Trace(err)
+			return float64(count), selTraceMethodCMSketch, errors.Trace(err)
 		}
 		histRowCount, _ := c.Histogram.EqualRowCount(sctx, val, false)
-		return histRowCount, nil
+		return histRowCount, selTraceMethodHistogram, nil
 	}
 
 	// Stats version == 2
 	// All the values are null.
 	if c.Histogram.Bounds.NumRows() == 0 && c.TopN.Num() == 0 {
-		return 0, nil
+		return 0, selTraceMethodEmptyStats, nil
 	}
 	// 1. try to find this value in TopN
 	if c.TopN != nil {
 		rowcount, ok := c.TopN.QueryTopN(sctx, encodedVal)
 		if ok {
-			return float64(rowcount), nil
+			return float64(rowcount), selTraceMethodTopN, nil
 		}
 	}
 	// 2. try to find this value in bucket.Repeat(the last value in every bucket)
 	histCnt, matched := c.Histogram.EqualRowCount(sctx, val, true)
 	if matched {
-		return histCnt, nil
+		return histCnt, selTraceMethodBucketRepeat, nil
 	}
 	// 3. use uniform distribution assumption for the rest (even when this value is not covered by the range of stats)
 	histNDV := float64(c.Histogram.NDV - int64(c.TopN.Num()))
@@ -180,7 +188,7 @@ func equalRowCountOnColumn(sctx planctx.PlanContext, c *statistics.Column, val t
 		//
 		// If the table hasn't been modified, it's safe to return 0.
 		if modifyCount == 0 {
-			return 0, nil
+			return 0, selTraceMethodUniform, nil
 		}
 		// ELSE calculate an approximate estimate based upon newly inserted rows.
 		//
@@ -193,10 +201,10 @@ func equalRowCountOnColumn(sctx planctx.PlanContext, c *statistics.Column, val t
 		// As a conservative estimate - take the smaller of the orignal totalRows or the additions.
 		// "realtimeRowCount - original count" is a better measure of inserts than modifyCount
 		totalRowCount := min(c.NotNullCount(), float64(realtimeRowCount)-c.NotNullCount())
-		return max(1, totalRowCount/histNDV), nil
+		return max(1, totalRowCount/histNDV), selTraceMethodUniform, nil
 	}
 	// return the average histogram rows (which excludes topN) and NDV that excluded topN
-	return c.Histogram.NotNullCount() / histNDV, nil
+	return c.Histogram.NotNullCount() / histNDV, selTraceMethodUniform, nil
 }
 
 // GetColumnRowCount estimates the row count by a slice of Range.
@@ -207,6 +215,10 @@ func GetColumnRowCount(sctx planctx.PlanContext, c *statistics.Column, ranges []
 		debugtrace.EnterContextCommon(sctx)
 		defer debugtrace.LeaveContextCommon(sctx)
 	}
+	var colNames []string
+	if selTraceEnabled(sctx) && c.Info != nil {
+		colNames = []string{c.Info.Name.O}
+	}
 	var rowCount float64
 	for _, rg := range ranges {
 		highVal := *rg.HighVal[0].Clone()
@@ -243,10 +255,14 @@ func GetColumnRowCount(sctx planctx.PlanContext, c *statistics.Column, ranges []
 					if debugTrace {
 						debugTraceEndEstimateRange(sctx, 1, debugTraceUniquePoint)
 					}
+					if colNames != nil {
+						selTraceRanges(sctx, c.PhysicalID, colNames, "", []*ranger.Range{rg}, selTraceMethodUniquePoint, 1, realtimeRowCount)
+					}
 					continue
 				}
 				var cnt float64
-				cnt, err = equalRowCountOnColumn(sctx, c, lowVal, lowEncoded, realtimeRowCount, modifyCount)
+				var method string
+				cnt, method, err = equalRowCountOnColumn(sctx, c, lowVal, lowEncoded, realtimeRowCount, modifyCount)
 				if err != nil {
 					return 0, errors.Trace(err)
 				}
@@ -256,6 +272,11 @@ func GetColumnRowCount(sctx planctx.PlanContext, c *statistics.Column, ranges []
 				if debugTrace {
 					debugTraceEndEstimateRange(sctx, cnt, debugTracePoint)
 				}
+				if colNames != nil {
+					selTraceRanges(sctx, c.PhysicalID, colNames, "", []*ranger.Range{rg}, method, cnt, realtimeRowCount)
+				}
+			} else if colNames != nil {
+				selTraceRanges(sctx, c.PhysicalID, colNames, "", []*ranger.Range{rg}, selTraceMethodImpossible, 0, realtimeRowCount)
 			}
 			continue
 		}
@@ -266,8 +287,10 @@ func GetColumnRowCount(sctx planctx.PlanContext, c *statistics.Column, ranges []
 
 			// case 2: it's a small range && using ver1 stats
 			if rangeVals != nil {
+				var rangeCnt float64
+				var methods []string
 				for _, val := range rangeVals {
-					cnt, err := equalRowCountOnColumn(sctx, c, val, lowEncoded, realtimeRowCount, modifyCount)
+					cnt, method, err := equalRowCountOnColumn(sctx, c, val, lowEncoded, realtimeRowCount, modifyCount)
 					if err != nil {
 						return 0, err
 					}
@@ -277,6 +300,11 @@ func GetColumnRowCount(sctx planctx.PlanContext, c *statistics.Column, ranges []
 						debugTraceEndEstimateRange(sctx, cnt, debugTraceVer1SmallRange)
 					}
 					rowCount += cnt
+					rangeCnt += cnt
+					methods = selTraceMethods(methods, method)
+				}
+				if colNames != nil {
+					selTraceRanges(sctx, c.PhysicalID, colNames, "", []*ranger.Range{rg}, strings.Join(methods, "+"), rangeCnt, realtimeRowCount)
 				}
 
 				continue
@@ -291,7 +319,7 @@ func GetColumnRowCount(sctx planctx.PlanContext, c *statistics.Column, ranges []
 		// And because we use (2, MaxValue] to represent expressions like a > 2 and use [MinNotNull, 3) to represent
 		//   expressions like b < 3, we need to exclude the special values.
 		if rg.LowExclude && !lowVal.IsNull() && lowVal.Kind() != types.KindMaxValue && lowVal.Kind() != types.KindMinNotNull {
-			lowCnt, err := equalRowCountOnColumn(sctx, c, lowVal, lowEncoded, realtimeRowCount, modifyCount)
+			lowCnt, _, err := equalRowCountOnColumn(sctx, c, lowVal, lowEncoded, realtimeRowCount, modifyCount)
 			if err != nil {
 				return 0, errors.Trace(err)
 			}
@@ -302,7 +330,7 @@ func GetColumnRowCount(sctx planctx.PlanContext, c *statistics.Column, ranges []
 			cnt += float64(c.NullCount)
 		}
 		if !rg.HighExclude && highVal.Kind() != types.KindMaxValue && highVal.Kind() != types.KindMinNotNull {
-			highCnt, err := equalRowCountOnColumn(sctx, c, highVal, highEncoded, realtimeRowCount, modifyCount)
+			highCnt, _, err := equalRowCountOnColumn(sctx, c, highVal, highEncoded, realtimeRowCount, modifyCount)
 			if err != nil {
 				return 0, errors.Trace(err)
 			}
@@ -315,6 +343,7 @@ func GetColumnRowCount(sctx planctx.PlanContext, c *statistics.Column, ranges []
 		increaseFactor := c.GetIncreaseFactor(realtimeRowCount)
 		cnt *= increaseFactor
 
+		method := selTraceMethodBucketInterpolation
 		// handling the out-of-range part
 		if (c.OutOfRange(lowVal) && !lowVal.IsNull()) || c.OutOfRange(highVal) {
 			histNDV := c.NDV
@@ -323,11 +352,15 @@ func GetColumnRowCount(sctx planctx.PlanContext, c *statistics.Column, ranges []
 				histNDV -= int64(c.TopN.Num())
 			}
 			cnt += c.Histogram.OutOfRangeRowCount(sctx, &lowVal, &highVal, modifyCount, histNDV, increaseFactor)
+			method += "+" + selTraceMethodOutOfRange
 		}
 
 		if debugTrace {
 			debugTraceEndEstimateRange(sctx, cnt, debugTraceRange)
 		}
+		if colNames != nil {
+			selTraceRanges(sctx, c.PhysicalID, colNames, "", []*ranger.Range{rg}, method, cnt, realtimeRowCount)
+		}
 		rowCount += cnt
 	}
 	allowZeroEst := fixcontrol.GetBoolWithDefault(
@@ -408,7 +441,7 @@ func ColumnEqualRowCount(sctx planctx.PlanContext, t *statistics.Table, value ty
 	if err != nil {
 		return 0, err
 	}
-	result, err := equalRowCountOnColumn(sctx, c, value, encodedVal, t.RealtimeCount, t.ModifyCount)
+	result, _, err := equalRowCountOnColumn(sctx, c, value, encodedVal, t.RealtimeCount, t.ModifyCount)
 	result *= c.GetIncreaseFactor(t.RealtimeCount)
 	return result, errors.Trace(err)
 }
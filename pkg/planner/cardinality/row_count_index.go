@@ -69,6 +69,9 @@ func GetRowCountByIndexRanges(sctx planctx.PlanContext, coll *statistics.HistCol
 		if err == nil && sc.EnableOptimizerCETrace && idx != nil {
 			ceTraceRange(sctx, coll.PhysicalID, colNames, indexRanges, "Index Stats-Pseudo", uint64(result))
 		}
+		if err == nil && selTraceEnabled(sctx) && name != "" {
+			selTraceRanges(sctx, coll.PhysicalID, colNames, name, indexRanges, selTraceMethodPseudo, result, coll.RealtimeCount)
+		}
 		return result, err
 	}
 	realtimeCnt, modifyCount := coll.GetScaledRealtimeAndModifyCnt(idx)
@@ -194,6 +197,9 @@ func getIndexRowCountForStatsV1(sctx planctx.PlanContext, coll *statistics.HistC
 		if debugTrace {
 			debugTraceEndEstimateRange(sctx, count, debugTraceRange)
 		}
+		if selTraceEnabled(sctx) {
+			selTraceRanges(sctx, coll.PhysicalID, indexColNames(idx), idx.Info.Name.O, []*ranger.Range{ran}, selTraceMethodCMSketch, count, coll.RealtimeCount)
+		}
 		totalCount += count
 	}
 	if totalCount > idx.TotalRowCount() {
@@ -202,6 +208,15 @@ func getIndexRowCountForStatsV1(sctx planctx.PlanContext, coll *statistics.HistC
 	return totalCount, nil
 }
 
+// indexColNames returns the names of the columns of the index.
+func indexColNames(idx *statistics.Index) []string {
+	colNames := make([]string, 0, len(idx.Info.Columns))
+	for _, col := range idx.Info.Columns {
+		colNames = append(colNames, col.Name.O)
+	}
+	return colNames
+}
+
 // isSingleColIdxNullRange checks if a range is [NULL, NULL] on a single-column index.
 func isSingleColIdxNullRange(idx *statistics.Index, ran *ranger.Range) bool {
 	if len(idx.Info.Columns) > 1 {
@@ -222,6 +237,10 @@ func getIndexRowCountForStatsV2(sctx planctx.PlanContext, idx *statistics.Index,
 		debugtrace.EnterContextCommon(sctx)
 		defer debugtrace.LeaveContextCommon(sctx)
 	}
+	var colNames []string
+	if selTraceEnabled(sctx) {
+		colNames = indexColNames(idx)
+	}
 	totalCount := float64(0)
 	isSingleColIdx := len(idx.Info.Columns) == 1
 	for _, indexRange := range indexRanges {
@@ -246,6 +265,9 @@ func getIndexRowCountForStatsV2(sctx planctx.PlanContext, idx *statistics.Index,
 				if debugTrace {
 					debugTraceEndEstimateRange(sctx, 0, debugTraceImpossible)
 				}
+				if colNames != nil {
+					selTraceRanges(sctx, idx.PhysicalID, colNames, idx.Info.Name.O, []*ranger.Range{indexRange}, selTraceMethodImpossible, 0, realtimeRowCount)
+				}
 				continue
 			}
 			if fullLen {
@@ -256,23 +278,48 @@ func getIndexRowCountForStatsV2(sctx planctx.PlanContext, idx *statistics.Index,
 						if debugTrace {
 							debugTraceEndEstimateRange(sctx, 1, debugTraceUniquePoint)
 						}
+						if colNames != nil {
+							selTraceRanges(sctx, idx.PhysicalID, colNames, idx.Info.Name.O, []*ranger.Range{indexRange}, selTraceMethodUniquePoint, 1, realtimeRowCount)
+						}
 						continue
 					}
 					totalCount = float64(idx.NullCount)
 					if debugTrace {
 						debugTraceEndEstimateRange(sctx, float64(idx.NullCount), debugTraceUniquePoint)
 					}
+					if colNames != nil {
+						selTraceRanges(sctx, idx.PhysicalID, colNames, idx.Info.Name.O, []*ranger.Range{indexRange}, selTraceMethodNullCount, float64(idx.NullCount), realtimeRowCount)
+					}
 					continue
 				}
-				count = equalRowCountOnIndex(sctx, idx, lb, realtimeRowCount, modifyCount)
+				var method string
+				count, method = equalRowCountOnIndex(sctx, idx, lb, realtimeRowCount, modifyCount)
 				// If the current table row count has changed, we should scale the row count accordingly.
 				count *= idx.GetIncreaseFactor(realtimeRowCount)
 				if debugTrace {
 					debugTraceEndEstimateRange(sctx, count, debugTracePoint)
 				}
+				if colNames != nil {
+					selTraceRanges(sctx, idx.PhysicalID, colNames, idx.Info.Name.O, []*ranger.Range{indexRange}, method, count, realtimeRowCount)
+				}
 				totalCount += count
 				continue
 			}
+			// The equal conditions on a prefix of the composite index, whose skewed values are kept in the prefix
+			// TopN rather than the index TopN.
+			if coll != nil && len(indexRange.LowVal) == len(indexRange.HighVal) {
+				if sel, ok := coll.IndexPrefixTopNs[idx.Info.ID].EqualSelectivity(lb, len(indexRange.LowVal)); ok {
+					count = sel * idx.TotalRowCount() * idx.GetIncreaseFactor(realtimeRowCount)
+					if debugTrace {
+						debugTraceEndEstimateRange(sctx, count, debugTracePoint)
+					}
+					if colNames != nil {
+						selTraceRanges(sctx, idx.PhysicalID, colNames, idx.Info.Name.O, []*ranger.Range{indexRange}, selTraceMethodIndexPrefixTopN, count, realtimeRowCount)
+					}
+					totalCount += count
+					continue
+				}
+			}
 		}
 
 		// case 2: it's an interval
@@ -328,8 +375,10 @@ func getIndexRowCountForStatsV2(sctx planctx.PlanContext, idx *statistics.Index,
 				count += expBackoffCnt
 			}
 		}
+		method := selTraceMethodExpBackoff
 		if !expBackoffSuccess {
 			count += betweenRowCountOnIndex(sctx, idx, l, r)
+			method = selTraceMethodBucketInterpolation
 		}
 
 		// If the current table row count has changed, we should scale the row count accordingly.
@@ -351,11 +400,15 @@ func getIndexRowCountForStatsV2(sctx planctx.PlanContext, idx *statistics.Index,
 				}
 			}
 			count += idx.Histogram.OutOfRangeRowCount(sctx, &l, &r, modifyCount, histNDV, increaseFactor)
+			method += "+" + selTraceMethodOutOfRange
 		}
 
 		if debugTrace {
 			debugTraceEndEstimateRange(sctx, count, debugTraceRange)
 		}
+		if colNames != nil {
+			selTraceRanges(sctx, idx.PhysicalID, colNames, idx.Info.Name.O, []*ranger.Range{indexRange}, method, count, realtimeRowCount)
+		}
 		totalCount += count
 	}
 	allowZeroEst := fixcontrol.GetBoolWithDefault(
@@ -374,7 +427,9 @@ func getIndexRowCountForStatsV2(sctx planctx.PlanContext, idx *statistics.Index,
 
 var nullKeyBytes, _ = codec.EncodeKey(time.UTC, nil, types.NewDatum(nil))
 
-func equalRowCountOnIndex(sctx planctx.PlanContext, idx *statistics.Index, b []byte, realtimeRowCount, modifyCount int64) (result float64) {
+// equalRowCountOnIndex estimates the row count of the encoded value on the index. It also returns the method used to
+// estimate it for the selectivity trace.
+func equalRowCountOnIndex(sctx planctx.PlanContext, idx *statistics.Index, b []byte, realtimeRowCount, modifyCount int64) (result float64, method string) {
 	if sctx.GetSessionVars().StmtCtx.EnableOptimizerDebugTrace {
 		debugtrace.EnterContextCommon(sctx)
 		debugtrace.RecordAnyValuesWithNames(sctx, "Encoded Value", b)
@@ -385,32 +440,32 @@ func equalRowCountOnIndex(sctx planctx.PlanContext, idx *statistics.Index, b []b
 	}
 	if len(idx.Info.Columns) == 1 {
 		if bytes.Equal(b, nullKeyBytes) {
-			return float64(idx.Histogram.NullCount)
+			return float64(idx.Histogram.NullCount), selTraceMethodNullCount
 		}
 	}
 	val := types.NewBytesDatum(b)
 	if idx.StatsVer < statistics.Version2 {
 		if idx.Histogram.NDV > 0 && outOfRangeOnIndex(idx, val) {
-			return outOfRangeEQSelectivity(sctx, idx.Histogram.NDV, realtimeRowCount, int64(idx.TotalRowCount())) * idx.TotalRowCount()
+			return outOfRangeEQSelectivity(sctx, idx.Histogram.NDV, realtimeRowCount, int64(idx.TotalRowCount())) * idx.TotalRowCount(), selTraceMethodOutOfRange
 		}
 		if idx.CMSketch != nil {
-			return float64(idx.QueryBytes(sctx, b))
+			return float64(idx.QueryBytes(sctx, b)), selTraceMethodCMSketch
 		}
 		histRowCount, _ := idx.Histogram.EqualRowCount(sctx, val, false)
-		return histRowCount
+		return histRowCount, selTraceMethodHistogram
 	}
 	// stats version == 2
 	// 1. try to find this value in TopN
 	if idx.TopN != nil {
 		count, found := idx.TopN.QueryTopN(sctx, b)
 		if found {
-			return float64(count)
+			return float64(count), selTraceMethodTopN
 		}
 	}
 	// 2. try to find this value in bucket.Repeat(the last value in every bucket)
 	histCnt, matched := idx.Histogram.EqualRowCount(sctx, val, true)
 	if matched {
-		return histCnt
+		return histCnt, selTraceMethodBucketRepeat
 	}
 	// 3. use uniform distribution assumption for the rest (even when this value is not covered by the range of stats)
 	histNDV := float64(idx.Histogram.NDV - int64(idx.TopN.Num()))
@@ -420,7 +475,7 @@ func equalRowCountOnIndex(sctx planctx.PlanContext, idx *statistics.Index, b []b
 		//
 		// If the table hasn't been modified, it's safe to return 0.
 		if modifyCount == 0 {
-			return 0
+			return 0, selTraceMethodUniform
 		}
 		// ELSE calculate an approximate estimate based upon newly inserted rows.
 		//
@@ -433,10 +488,10 @@ func equalRowCountOnIndex(sctx planctx.PlanContext, idx *statistics.Index, b []b
 		// As a conservative estimate - take the smaller of the orignal totalRows or the additions.
 		// "realtimeRowCount - original count" is a better measure of inserts than modifyCount
 		totalRowCount := min(idx.TotalRowCount(), float64(realtimeRowCount)-idx.TotalRowCount())
-		return max(1, totalRowCount/histNDV)
+		return max(1, totalRowCount/histNDV), selTraceMethodUniform
 	}
 	// return the average histogram rows (which excludes topN) and NDV that excluded topN
-	return idx.Histogram.NotNullCount() / histNDV
+	return idx.Histogram.NotNullCount() / histNDV, selTraceMethodUniform
 }
 
 // expBackoffEstimation estimate the multi-col cases following the Exponential Backoff. See comment below for details.
@@ -16,6 +16,7 @@ package cardinality
 
 import (
 	"cmp"
+	"fmt"
 	"math"
 	"math/bits"
 	"slices"
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/pkg/planner/planctx"
 	planutil "github.com/pingcap/tidb/pkg/planner/util"
 	"github.com/pingcap/tidb/pkg/planner/util/debugtrace"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/chunk"
@@ -82,6 +84,9 @@ func Selectivity(
 			ceTraceExpr(ctx, tableID, "Table Stats-Pseudo-Expression",
 				expression.ComposeCNFCondition(ctx.GetExprCtx(), exprs...), ret*float64(coll.RealtimeCount))
 		}
+		if selTraceEnabled(ctx) {
+			selTraceExprs(ctx, tableID, exprs, selTraceMethodPseudo, ret, coll.RealtimeCount)
+		}
 		return ret, nil, nil
 	}
 
@@ -207,6 +212,7 @@ func Selectivity(
 				return 0, nil, errors.Trace(err)
 			}
 			selectivity := cnt / float64(coll.RealtimeCount)
+			selectivity = boundPrefixIndexSelectivity(ctx, coll, idxInfo, idxCols, maskCovered, nodes, selectivity)
 			nodes = append(nodes, &StatsNode{
 				Tp:                       IndexType,
 				ID:                       id,
@@ -220,14 +226,20 @@ func Selectivity(
 		}
 	}
 	usedSets := GetUsableSetsByGreedy(nodes)
+	adjustedSelectivities := getSelectivitiesByFuncDeps(ctx, coll, usedSets)
+	adjustedSelectivities = getSelectivitiesByMultiColHists(ctx, coll, usedSets, adjustedSelectivities)
 	// Initialize the mask with the full set.
 	mask := (int64(1) << uint(len(remainedExprs))) - 1
 	// curExpr records covered expressions by now. It's for cardinality estimation tracing.
 	var curExpr []expression.Expression
 
-	for _, set := range usedSets {
+	for i, set := range usedSets {
 		mask &^= set.mask
-		ret *= set.Selectivity
+		if sel, ok := adjustedSelectivities[i]; ok {
+			ret *= sel
+		} else {
+			ret *= set.Selectivity
+		}
 		// If `partCover` is true, it means that the conditions are in DNF form, and only part
 		// of the DNF expressions are extracted as access conditions, so besides from the selectivity
 		// of the extracted access conditions, we multiply another selectionFactor for the residual
@@ -384,6 +396,37 @@ OUTER:
 		}
 	}
 
+	// Try to cover remaining in-lists whose ranges are not built, e.g. the ranges exceed tidb_opt_range_max_size, by
+	// estimating their items one by one.
+	for i, expr := range notCoveredOtherExpr {
+		sel, ok := getInListSelectivity(ctx, coll, expr)
+		if !ok {
+			continue
+		}
+		ret *= sel
+		mask &^= 1 << uint64(i)
+		delete(notCoveredOtherExpr, i)
+		if sc.EnableOptimizerDebugTrace {
+			debugtrace.RecordAnyValuesWithNames(ctx, "Expression", remainedExprStrs[i], "Selectivity", sel)
+		}
+	}
+
+	// Try to cover remaining predicates on the paths of the JSON columns by the JSON path stats.
+	if len(coll.JSONPathStats) > 0 {
+		for i, expr := range notCoveredOtherExpr {
+			sel, ok := getJSONPathSelectivity(ctx, coll, expr)
+			if !ok {
+				continue
+			}
+			ret *= sel
+			mask &^= 1 << uint64(i)
+			delete(notCoveredOtherExpr, i)
+			if sc.EnableOptimizerDebugTrace {
+				debugtrace.RecordAnyValuesWithNames(ctx, "Expression", remainedExprStrs[i], "Selectivity", sel)
+			}
+		}
+	}
+
 	// Try to cover remaining string matching functions by evaluating the expressions with TopN to estimate.
 	if ctx.GetSessionVars().EnableEvalTopNEstimationForStrMatch() {
 		for i, scalarCond := range notCoveredStrMatch {
@@ -418,6 +461,24 @@ OUTER:
 		}
 	}
 
+	// Try to cover remaining string matching functions on the string columns by the trigram stats.
+	if len(coll.TrigramStats) > 0 {
+		for _, notCovered := range []map[int]*expression.ScalarFunction{notCoveredStrMatch, notCoveredNegateStrMatch} {
+			for i, scalarCond := range notCovered {
+				sel, ok := getTrigramSelectivity(ctx, coll, scalarCond)
+				if !ok {
+					continue
+				}
+				ret *= sel
+				mask &^= 1 << uint64(i)
+				delete(notCovered, i)
+				if sc.EnableOptimizerDebugTrace {
+					debugtrace.RecordAnyValuesWithNames(ctx, "Expression", remainedExprStrs[i], "Selectivity", sel)
+				}
+			}
+		}
+	}
+
 	// At last, if there are still conditions which cannot be estimated, we multiply the selectivity with
 	// the minimal default selectivity of the remaining conditions.
 	// Currently, only string matching functions (like and regexp) may have a different default selectivity,
@@ -437,6 +498,15 @@ OUTER:
 		if sc.EnableOptimizerDebugTrace {
 			debugtrace.RecordAnyValuesWithNames(ctx, "Default Selectivity", minSelectivity)
 		}
+		if selTraceEnabled(ctx) {
+			notCoveredExprs := make([]expression.Expression, 0, len(remainedExprs))
+			for i := range remainedExprs {
+				if mask&(1<<uint64(i)) > 0 {
+					notCoveredExprs = append(notCoveredExprs, remainedExprs[i])
+				}
+			}
+			selTraceExprs(ctx, tableID, notCoveredExprs, selTraceMethodDefault, minSelectivity, coll.RealtimeCount)
+		}
 	}
 
 	if sc.EnableOptimizerCETrace {
@@ -533,6 +603,168 @@ func CalcTotalSelectivityForMVIdxPath(
 	return totalSelectivity
 }
 
+// AdjustIntersectionSelectivity adjusts the selectivity of the intersection of the partial paths of an index merge by
+// the correlations between the columns of the partial paths, which are from the correlation extended stats.
+// Multiplying the selectivities of the partial paths underestimates the intersection if the columns are correlated.
+// For two paths with selectivities s1 <= s2 whose columns have the correlation c, we estimate the selectivity of the
+// intersection as s1 * s2 + |c| * (s1 - s1 * s2), i.e., it approaches the smaller one as the columns are more
+// correlated. The paths are merged in ascending order of their selectivities, and sel, the selectivity estimated
+// with the independence assumption, is scaled by the ratio of the estimations with and without the correlations.
+func AdjustIntersectionSelectivity(coll *statistics.HistColl, partialPaths []*planutil.AccessPath, rowCount, sel float64) float64 {
+	if len(coll.ColCorrelations) == 0 || len(partialPaths) < 2 || rowCount <= 0 {
+		return sel
+	}
+	type pathItem struct {
+		// col is the UniqueID of the column of the access conditions, 0 if they are on more than one column.
+		col int64
+		sel float64
+	}
+	items := make([]pathItem, 0, len(partialPaths))
+	for _, path := range partialPaths {
+		item := pathItem{sel: min(path.CountAfterAccess/rowCount, 1)}
+		for i, col := range expression.ExtractColumnsFromExpressions(nil, path.AccessConds, nil) {
+			if i == 0 {
+				item.col = col.UniqueID
+			} else if col.UniqueID != item.col {
+				item.col = 0
+				break
+			}
+		}
+		items = append(items, item)
+	}
+	slices.SortStableFunc(items, func(a, b pathItem) int {
+		return cmp.Compare(a.sel, b.sel)
+	})
+	independent, correlated := items[0].sel, items[0].sel
+	for i := 1; i < len(items); i++ {
+		corr := 0.0
+		for j := 0; j < i; j++ {
+			corr = max(corr, math.Abs(getColCorrelation(coll, items[i].col, items[j].col)))
+		}
+		corr = min(corr, 1)
+		independent *= items[i].sel
+		correlated = correlated*items[i].sel + corr*(correlated-correlated*items[i].sel)
+	}
+	if independent <= 0 || correlated <= independent {
+		return sel
+	}
+	return min(sel*correlated/independent, 1)
+}
+
+func getColCorrelation(coll *statistics.HistColl, col1, col2 int64) float64 {
+	if col1 == 0 || col2 == 0 || col1 == col2 {
+		return 0
+	}
+	for _, corr := range coll.ColCorrelations {
+		if (corr.Col1 == col1 && corr.Col2 == col2) || (corr.Col1 == col2 && corr.Col2 == col1) {
+			return corr.Corr
+		}
+	}
+	return 0
+}
+
+// getSelectivitiesByFuncDeps adjusts the selectivities of the equal conditions on the columns which are functionally
+// dependent on another column with an equal condition, so that the selectivities of the dependent conditions are not
+// simply multiplied. For a dependency a => b with degree d, we estimate sel(a = x and b = y) as
+// sel(a = x) * (d + (1 - d) * sel(b = y)). The returned map is from the index of usedSets to the adjusted selectivity.
+func getSelectivitiesByFuncDeps(ctx planctx.PlanContext, coll *statistics.HistColl, usedSets []*StatsNode) map[int]float64 {
+	if len(coll.FuncDeps) == 0 || len(usedSets) < 2 {
+		return nil
+	}
+	tc := ctx.GetSessionVars().StmtCtx.TypeCtx()
+	// colEqSets maps the column UniqueID to the index of the set which is an equal condition on the column.
+	colEqSets := make(map[int64]int, len(usedSets))
+	for i, set := range usedSets {
+		if set.Tp != ColType || set.partCover || len(set.Ranges) != 1 || !set.Ranges[0].IsPointNonNullable(tc) {
+			continue
+		}
+		colEqSets[set.ID] = i
+	}
+	if len(colEqSets) < 2 {
+		return nil
+	}
+	var ret map[int]float64
+	for _, dep := range coll.FuncDeps {
+		if dep.Degree <= 0 {
+			continue
+		}
+		determinant, ok := colEqSets[dep.Determinant]
+		if !ok {
+			continue
+		}
+		dependent, ok := colEqSets[dep.Dependent]
+		if !ok {
+			continue
+		}
+		// Skip it if either column has been adjusted by another dependency, to avoid chaining the adjustments.
+		if _, ok := ret[dependent]; ok {
+			continue
+		}
+		if _, ok := ret[determinant]; ok {
+			continue
+		}
+		if ret == nil {
+			ret = make(map[int]float64, len(colEqSets))
+		}
+		degree := min(dep.Degree, 1)
+		ret[dependent] = degree + (1-degree)*usedSets[dependent].Selectivity
+	}
+	return ret
+}
+
+// getSelectivitiesByMultiColHists estimates the conjunctions of the range conditions on 2 columns by the multi-column
+// histograms instead of multiplying their selectivities, since the columns may be correlated, such as `start_time`
+// and `end_time`. The joint selectivity is put on the set of the first column, and the selectivity of the set of the
+// second column is adjusted to 1. The sets adjusted by the functional dependencies are skipped.
+func getSelectivitiesByMultiColHists(ctx planctx.PlanContext, coll *statistics.HistColl, usedSets []*StatsNode, adjusted map[int]float64) map[int]float64 {
+	if len(coll.MultiColHists) == 0 || len(usedSets) < 2 {
+		return adjusted
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	// colSets maps the column UniqueID to the index of the set which is the ranges on the column.
+	colSets := make(map[int64]int, len(usedSets))
+	for i, set := range usedSets {
+		if (set.Tp != ColType && set.Tp != PkType) || set.partCover || len(set.Ranges) == 0 {
+			continue
+		}
+		if _, ok := adjusted[i]; ok {
+			continue
+		}
+		colSets[set.ID] = i
+	}
+	if len(colSets) < 2 {
+		return adjusted
+	}
+	for _, hist := range coll.MultiColHists {
+		first, ok := colSets[hist.Cols[0]]
+		if !ok {
+			continue
+		}
+		second, ok := colSets[hist.Cols[1]]
+		if !ok {
+			continue
+		}
+		sel, err := hist.Hist.Selectivity(sc, usedSets[first].Ranges, usedSets[second].Ranges)
+		if err != nil {
+			continue
+		}
+		if adjusted == nil {
+			adjusted = make(map[int]float64, 2)
+		}
+		adjusted[first], adjusted[second] = sel, 1
+		// Each set is only adjusted once.
+		delete(colSets, hist.Cols[0])
+		delete(colSets, hist.Cols[1])
+		if sc.InExplainStmt {
+			note := fmt.Sprintf("multi-column histogram %s is used to estimate the conditions on columns %s and %s together", hist.Name, hist.ColNames[0], hist.ColNames[1])
+			if !slices.ContainsFunc(sc.GetWarnings(), func(w stmtctx.SQLWarn) bool { return w.Err.Error() == note }) {
+				sc.AppendNote(errors.NewNoStackError(note))
+			}
+		}
+	}
+	return adjusted
+}
+
 // StatsNode is used for calculating selectivity.
 type StatsNode struct {
 	// Ranges contains all the Ranges we got.
@@ -735,6 +967,28 @@ func isColEqCorCol(filter expression.Expression) *expression.Column {
 	return nil
 }
 
+// boundPrefixIndexSelectivity bounds the selectivity of an index by the full-column stats of its prefix columns.
+// The ranges of a prefix column are built on the truncated values, so the index stats estimate the rows whose prefixes
+// satisfy the conditions, which can be far more than the rows satisfying them when the values share long prefixes.
+// A column node can bound the index only if all of its conditions are covered by the index.
+func boundPrefixIndexSelectivity(sctx planctx.PlanContext, coll *statistics.HistColl, idxInfo *model.IndexInfo,
+	idxCols []*expression.Column, maskCovered int64, nodes []*StatsNode, selectivity float64) float64 {
+	for i, col := range idxCols {
+		if i >= len(idxInfo.Columns) || idxInfo.Columns[i].Length == types.UnspecifiedLength {
+			continue
+		}
+		if statistics.ColumnStatsIsInvalid(coll.GetCol(col.UniqueID), sctx, coll, col.UniqueID) {
+			continue
+		}
+		for _, node := range nodes {
+			if node.Tp == ColType && node.ID == col.UniqueID && node.mask != 0 && node.mask&maskCovered == node.mask {
+				selectivity = min(selectivity, node.Selectivity)
+			}
+		}
+	}
+	return selectivity
+}
+
 // findPrefixOfIndexByCol will find columns in index by checking the unique id or the virtual expression.
 // So it will return at once no matching column is found.
 func findPrefixOfIndexByCol(ctx planctx.PlanContext, cols []*expression.Column, idxColIDs []int64,
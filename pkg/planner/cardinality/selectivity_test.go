@@ -21,12 +21,14 @@ import (
 	"os"
 	"runtime/pprof"
 	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/meta/model"
 	pmodel "github.com/pingcap/tidb/pkg/parser/model"
@@ -36,6 +38,7 @@ import (
 	"github.com/pingcap/tidb/pkg/planner/core/base"
 	"github.com/pingcap/tidb/pkg/planner/core/operator/logicalop"
 	"github.com/pingcap/tidb/pkg/planner/core/resolve"
+	planutil "github.com/pingcap/tidb/pkg/planner/util"
 	"github.com/pingcap/tidb/pkg/session"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
@@ -212,6 +215,54 @@ func TestOutOfRangeEstimationAfterDelete(t *testing.T) {
 	}
 }
 
+func TestOutOfRangeEstimationForMonotonicColumns(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	h := dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b datetime)")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	insertRows := func(start, end int) {
+		values := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			values = append(values, fmt.Sprintf("(%d, '2024-01-01 00:00:00' + interval %d minute)", i, i))
+		}
+		tk.MustExec("insert into t values " + strings.Join(values, ", "))
+	}
+	// [0, 1000)
+	insertRows(0, 1000)
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t all columns with 1 samplerate, 0 topn")
+	// The rows of [1000, 2000) are appended after the analyze.
+	insertRows(1000, 2000)
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+
+	estRows := func(sql string) float64 {
+		rows := tk.MustQuery("explain format = 'brief' " + sql).Rows()
+		count, err := strconv.ParseFloat(rows[0][1].(string), 64)
+		require.NoError(t, err)
+		return count
+	}
+	sqls := []string{
+		"select * from t where a > 1800",
+		"select * from t where b > '2024-01-01 00:00:00' + interval 1800 minute",
+	}
+	for _, sql := range sqls {
+		// The out-of-range rows are assumed to decrease away from the max value.
+		count := estRows(sql)
+		require.Truef(t, count < 50, "for %s, expected: around 20, got: %v", sql, count)
+	}
+	tk.MustExec("set @@tidb_opt_extrapolate_monotonic_histogram = on")
+	for _, sql := range sqls {
+		// The last bucket is extended to 2000 by the inserted rows.
+		count := estRows(sql)
+		require.Truef(t, count > 150 && count < 250, "for %s, expected: around 200, got: %v", sql, count)
+	}
+	// There are no rows below the min value of the monotonically increasing columns.
+	require.Less(t, estRows("select * from t where a < -100"), 2.0)
+}
+
 func TestEstimationForUnknownValues(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	testKit := testkit.NewTestKit(t, store)
@@ -1409,3 +1460,233 @@ func TestBuiltinInEstWithoutStats(t *testing.T) {
 		require.False(t, statsTbl.ColAndIdxExistenceMap.HasAnalyzed(col.ID, false))
 	}
 }
+
+func TestVirtualColumnStatsForExpression(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	h := dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int as (a % 10) virtual, c varchar(20), index idx((lower(c))))")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	tk.MustExec("insert into t(a, c) values (0, 'A'), (1, 'a'), (2, 'B'), (3, 'b'), (4, 'b'), (5, 'C'), (6, 'D'), (7, 'E'), (8, 'F'), (9, 'G')")
+	for i := 0; i < 3; i++ {
+		tk.MustExec("insert into t(a, c) select a + 10, c from t")
+	}
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t all columns")
+
+	// The predicate on the expression of the virtual generated column is estimated by the stats of the column.
+	rows := tk.MustQuery("explain format = 'brief' select * from t where a % 10 = 3").Rows()
+	require.Equal(t, "8.00", rows[0][1])
+	// The hidden column of the expression index is used as well even if the index is not chosen.
+	rows = tk.MustQuery("explain format = 'brief' select * from t use index() where lower(c) = 'b'").Rows()
+	require.Equal(t, "24.00", rows[0][1])
+}
+
+func TestJSONPathStatsSelectivity(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	h := dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, j json)")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	for i := 0; i < 80; i++ {
+		a := "y"
+		if i < 20 {
+			a = "x"
+		}
+		tk.MustExec(fmt.Sprintf(`insert into t values (%d, '{"a": "%s", "tags": [%d, 100]}')`, i, a, i%4))
+	}
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("set @@session.tidb_analyze_skip_column_types = ''")
+	tk.MustExec("analyze table t all columns")
+	tk.MustQuery("show stats_extended where table_name = 't'").CheckAt([]int{2, 3, 4}, testkit.Rows("__json_path_2 [j] json_path"))
+
+	rows := tk.MustQuery(`explain format = 'brief' select * from t where j->>'$.a' = 'x'`).Rows()
+	require.Equal(t, "20.00", rows[0][1])
+	rows = tk.MustQuery(`explain format = 'brief' select * from t where 1 member of (j->'$.tags')`).Rows()
+	require.Equal(t, "20.00", rows[0][1])
+	rows = tk.MustQuery(`explain format = 'brief' select * from t where json_contains(j->'$.tags', '100')`).Rows()
+	require.Equal(t, "80.00", rows[0][1])
+}
+
+func TestTrigramStatsSelectivity(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	h := dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("create table t(id int primary key, msg varchar(64))")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	for i := 0; i < 100; i++ {
+		msg := fmt.Sprintf("info: request %d done", i)
+		if i < 20 {
+			msg = fmt.Sprintf("Error: disk %d is full", i)
+		}
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, '%s')", i, msg))
+	}
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t all columns")
+	tk.MustQuery("show stats_extended where table_name = 't'").Check(testkit.Rows())
+	// Without the trigram stats, the default selectivity is used.
+	rows := tk.MustQuery(`explain format = 'brief' select * from t where msg like '%error%'`).Rows()
+	require.Equal(t, "80.00", rows[0][1])
+
+	tk.MustExec("set @@session.tidb_analyze_collect_trigram_stats = 1")
+	tk.MustExec("analyze table t all columns")
+	tk.MustQuery("show stats_extended where table_name = 't'").CheckAt([]int{2, 3, 4}, testkit.Rows("__trigram_2 [msg] trigram"))
+
+	rows = tk.MustQuery(`explain format = 'brief' select * from t where msg like '%error%'`).Rows()
+	require.Equal(t, "20.00", rows[0][1])
+	rows = tk.MustQuery(`explain format = 'brief' select * from t where msg not like '%error%'`).Rows()
+	require.Equal(t, "80.00", rows[0][1])
+	rows = tk.MustQuery(`explain format = 'brief' select * from t where msg regexp 'disk.*full'`).Rows()
+	require.Equal(t, "20.00", rows[0][1])
+	// The patterns without 3 consecutive literal characters still use the default selectivity.
+	rows = tk.MustQuery(`explain format = 'brief' select * from t where msg like '%er%'`).Rows()
+	require.Equal(t, "80.00", rows[0][1])
+}
+
+func TestLongInListSelectivity(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	h := dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i%10, i))
+	}
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t all columns")
+
+	estRows := func(sql string) string {
+		return tk.MustQuery("explain format = 'brief' " + sql).Rows()[0][1].(string)
+	}
+	queries := []string{
+		"select * from t where a in (1, 2, 1000)",
+		"select * from t where a in (1, 1, 2, null)",
+	}
+	expected := make([]string, 0, len(queries))
+	for _, sql := range queries {
+		expected = append(expected, estRows(sql))
+	}
+	require.Equal(t, "20.00", expected[1])
+
+	// The ranges of the in-lists fall back to the full range, and the items are estimated one by one.
+	tk.MustExec("set @@tidb_opt_range_max_size = 1")
+	for i, sql := range queries {
+		require.Equal(t, expected[i], estRows(sql), sql)
+		// The selectivity is cached in the session.
+		require.Equal(t, expected[i], estRows(sql), sql)
+	}
+}
+
+func TestHistJoinEstimation(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	h := dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int)")
+	tk.MustExec("create table t2(b int)")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	// Both sides are skewed on the value 1, and the other values don't match.
+	for i := 0; i < 50; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t1 values (1), (%d)", i+2))
+		tk.MustExec(fmt.Sprintf("insert into t2 values (1), (%d)", i+100))
+	}
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t1 all columns")
+	tk.MustExec("analyze table t2 all columns")
+
+	estRows := func() string {
+		return tk.MustQuery("explain format = 'brief' select * from t1 join t2 on t1.a = t2.b").Rows()[0][1].(string)
+	}
+	require.Equal(t, "2500.00", estRows())
+
+	// Fall back to the NDV-based estimation.
+	tk.MustExec(`set @@tidb_opt_fix_control = "56318:OFF"`)
+	require.NotEqual(t, "2500.00", estRows())
+}
+
+func TestMultiColHistSelectivity(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	h := dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_enable_extended_stats = on")
+	tk.MustExec("create table t(id int primary key, start_time int, end_time int)")
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	// All the tasks last 10 seconds, so start_time < 50 implies end_time < 60.
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, %d)", i, i, i+10))
+	}
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("alter table t add stats_extended s1 histogram(start_time, end_time)")
+	tk.MustExec("analyze table t all columns")
+	tk.MustQuery("show stats_extended where table_name = 't'").CheckAt([]int{2, 3, 4}, testkit.Rows("s1 [start_time,end_time] histogram"))
+
+	query := "explain format = 'brief' select * from t where start_time < 50 and end_time < 60"
+	rows := tk.MustQuery(query).Rows()
+	require.Equal(t, "50.00", rows[0][1])
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Note 1105 multi-column histogram s1 is used to estimate the conditions on columns start_time and end_time together"))
+
+	// The selectivities of the columns are multiplied without the extended stats.
+	tk.MustExec("set @@session.tidb_enable_extended_stats = off")
+	rows = tk.MustQuery(query).Rows()
+	require.NotEqual(t, "50.00", rows[0][1])
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+}
+
+func TestAdjustIntersectionSelectivity(t *testing.T) {
+	newPath := func(colID int64, count float64) *planutil.AccessPath {
+		return &planutil.AccessPath{
+			AccessConds:      []expression.Expression{&expression.Column{UniqueID: colID}},
+			CountAfterAccess: count,
+		}
+	}
+	a, b, c := newPath(1, 100), newPath(2, 200), newPath(3, 500)
+	coll := &statistics.HistColl{}
+	// Without the correlation stats, the selectivity is estimated with the independence assumption.
+	require.Equal(t, 0.02, cardinality.AdjustIntersectionSelectivity(coll, []*planutil.AccessPath{a, b}, 1000, 0.02))
+
+	coll.ColCorrelations = []statistics.ColCorrelation{{Col1: 1, Col2: 2, Corr: -0.9}}
+	// 0.1 * 0.2 + 0.9 * (0.1 - 0.1 * 0.2)
+	require.InDelta(t, 0.092, cardinality.AdjustIntersectionSelectivity(coll, []*planutil.AccessPath{b, a}, 1000, 0.02), 1e-9)
+	// The selectivity of c is multiplied since it's not correlated with a or b.
+	require.InDelta(t, 0.046, cardinality.AdjustIntersectionSelectivity(coll, []*planutil.AccessPath{a, c, b}, 1000, 0.01), 1e-9)
+	// The selectivity estimated by other ways is scaled by the same ratio.
+	require.InDelta(t, 0.0092, cardinality.AdjustIntersectionSelectivity(coll, []*planutil.AccessPath{a, b}, 1000, 0.002), 1e-9)
+
+	// The access conditions on more than one column are not adjusted.
+	b.AccessConds = append(b.AccessConds, &expression.Column{UniqueID: 3})
+	require.Equal(t, 0.02, cardinality.AdjustIntersectionSelectivity(coll, []*planutil.AccessPath{a, b}, 1000, 0.02))
+}
+
+func TestPrefixIndexEstimation(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	h := dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a varchar(100), b text, index ia(a(5)), index ib(b(5)))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values ('aaaaaaaaaa%03d', null), ('b%03d', null)", i, i))
+	}
+	// The values are too long to be sampled as the full-column values, but their prefixes are still sampled.
+	for i := 0; i < 10; i++ {
+		tk.MustExec("insert into t values (null, concat(repeat('x', 40000), ?))", i)
+	}
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t")
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tk.MustQuery(fmt.Sprintf("select count from mysql.stats_top_n where table_id = %d and is_index = 1 and hist_id = %d",
+		tbl.Meta().ID, tbl.Meta().Indices[1].ID)).Check(testkit.Rows("10"))
+
+	// All the 'aaaaaaaaaa%' values share the same prefix in the index, so the estimation is bounded by the full-column stats.
+	rows := tk.MustQuery("explain format = 'brief' select * from t use index(ia) where a = 'aaaaaaaaaa001'").Rows()
+	require.Equal(t, "1.00", rows[0][1])
+	// The index scan still reads all the rows with the same prefix.
+	require.Equal(t, "100.00", rows[len(rows)-1][1])
+}
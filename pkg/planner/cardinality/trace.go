@@ -18,6 +18,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"slices"
+	"strings"
 
 	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/expression"
@@ -28,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/pkg/planner/util/debugtrace"
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/types"
 	driver "github.com/pingcap/tidb/pkg/types/parser_driver"
 	"github.com/pingcap/tidb/pkg/util/chunk"
 	"github.com/pingcap/tidb/pkg/util/logutil"
@@ -144,7 +147,8 @@ func debugTraceGetRowCountInput(
 // GetTblInfoForUsedStatsByPhysicalID get table name, partition name and TableInfo that will be used to record used stats.
 var GetTblInfoForUsedStatsByPhysicalID func(sctx planctx.PlanContext, id int64) (fullName string, tblInfo *model.TableInfo)
 
-// recordUsedItemStatsStatus only records un-FullLoad item load status during user query
+// recordUsedItemStatsStatus only records un-FullLoad item load status during user query.
+// For EXPLAIN FORMAT='stats', it records the details of all the used items as well.
 func recordUsedItemStatsStatus(sctx planctx.PlanContext, stats any, tableID, id int64) {
 	// Sometimes we try to use stats on _tidb_rowid (id == -1), which must be empty, we ignore this case here.
 	if id <= 0 {
@@ -169,8 +173,9 @@ func recordUsedItemStatsStatus(sctx planctx.PlanContext, stats any, tableID, id
 		}
 	}
 
+	recordDetails := strings.EqualFold(sctx.GetSessionVars().StmtCtx.ExplainFormat, types.ExplainFormatStats)
 	// no need to record
-	if !missing && loadStatus != nil && loadStatus.IsFullLoad() {
+	if !recordDetails && !missing && loadStatus != nil && loadStatus.IsFullLoad() {
 		return
 	}
 
@@ -184,6 +189,12 @@ func recordUsedItemStatsStatus(sctx planctx.PlanContext, stats any, tableID, id
 		})
 	}
 	recordForTbl := statsRecord.GetUsedInfo(tableID)
+	if recordDetails {
+		recordUsedItemStatsDetails(recordForTbl, stats, id, isIndex, missing)
+		if !missing && loadStatus != nil && loadStatus.IsFullLoad() {
+			return
+		}
+	}
 
 	var recordForColOrIdx map[int64]string
 	if isIndex {
@@ -212,6 +223,43 @@ func recordUsedItemStatsStatus(sctx planctx.PlanContext, stats any, tableID, id
 	recordForColOrIdx[id] = loadStatus.StatusToString()
 }
 
+// recordUsedItemStatsDetails records the details of the column or index stats used for EXPLAIN FORMAT='stats'.
+func recordUsedItemStatsDetails(recordForTbl *stmtctx.UsedStatsInfoForTable, stats any, id int64, isIndex, missing bool) {
+	item := &stmtctx.UsedStatsItem{}
+	switch x := stats.(type) {
+	case *statistics.Column:
+		if !missing {
+			item.Version, item.StatsVer, item.NDV = x.LastUpdateVersion, x.GetStatsVer(), x.Histogram.NDV
+			item.Buckets, item.TopN = x.Histogram.Len(), x.TopN.Num()
+			if !x.IsFullLoad() {
+				item.Status = x.StatsLoadedStatus.StatusToString()
+			}
+		}
+	case *statistics.Index:
+		if !missing {
+			item.Version, item.StatsVer, item.NDV = x.LastUpdateVersion, x.GetStatsVer(), x.Histogram.NDV
+			item.Buckets, item.TopN = x.Histogram.Len(), x.TopN.Num()
+			if !x.IsFullLoad() {
+				item.Status = x.StatsLoadedStatus.StatusToString()
+			}
+		}
+	}
+	if missing {
+		item.Status = "missing"
+	}
+	if isIndex {
+		if recordForTbl.IndexStatsUsed == nil {
+			recordForTbl.IndexStatsUsed = make(map[int64]*stmtctx.UsedStatsItem, 1)
+		}
+		recordForTbl.IndexStatsUsed[id] = item
+	} else {
+		if recordForTbl.ColumnStatsUsed == nil {
+			recordForTbl.ColumnStatsUsed = make(map[int64]*stmtctx.UsedStatsItem, 1)
+		}
+		recordForTbl.ColumnStatsUsed[id] = item
+	}
+}
+
 // ceTraceRange appends a list of ranges and related information into CE trace
 func ceTraceRange(sctx planctx.PlanContext, tableID int64, colNames []string, ranges []*ranger.Range, tp string, rowCount uint64) {
 	sc := sctx.GetSessionVars().StmtCtx
@@ -245,6 +293,102 @@ func ceTraceRange(sctx planctx.PlanContext, tableID int64, colNames []string, ra
 	sc.OptimizerCETrace = append(sc.OptimizerCETrace, &ceRecord)
 }
 
+/*
+ Below is the selectivity trace for TRACE PLAN, which records how the selectivity of each predicate is estimated.
+*/
+
+// The methods to estimate the selectivity.
+const (
+	selTraceMethodPseudo              = "Pseudo"
+	selTraceMethodDefault             = "Default"
+	selTraceMethodImpossible          = "Impossible"
+	selTraceMethodUniquePoint         = "Unique Point"
+	selTraceMethodNullCount           = "Null Count"
+	selTraceMethodEmptyStats          = "Empty Stats"
+	selTraceMethodTopN                = "TopN Hit"
+	selTraceMethodIndexPrefixTopN     = "Index Prefix TopN Hit"
+	selTraceMethodBucketRepeat        = "Bucket Repeat"
+	selTraceMethodUniform             = "Uniform Distribution"
+	selTraceMethodCMSketch            = "CMSketch"
+	selTraceMethodHistogram           = "Histogram"
+	selTraceMethodOutOfRange          = "Out Of Range"
+	selTraceMethodBucketInterpolation = "Bucket Interpolation"
+	selTraceMethodExpBackoff          = "Exponential Backoff"
+)
+
+// selTraceEnabled returns whether the selectivity computations need to be recorded, i.e. in TRACE PLAN.
+func selTraceEnabled(sctx planctx.PlanContext) bool {
+	sc := sctx.GetSessionVars().StmtCtx
+	return sc.EnableOptimizeTrace && sc.OptimizeTracer != nil
+}
+
+// selTraceRanges appends the selectivity computation of the ranges on the columns into the optimizer trace.
+func selTraceRanges(sctx planctx.PlanContext, tableID int64, colNames []string, idxName string,
+	ranges []*ranger.Range, method string, rowCount float64, realtimeRowCount int64) {
+	for _, ran := range ranges {
+		if len(ran.LowVal) > len(colNames) {
+			return
+		}
+	}
+	predicate, err := ranger.RangesToString(sctx.GetSessionVars().StmtCtx, ranges, colNames)
+	if err != nil {
+		logutil.BgLogger().Debug("Failed to trace selectivity of ranges", zap.String("category", "OptimizerTrace"), zap.Error(err))
+		return
+	}
+	selectivity := 1.0
+	if realtimeRowCount > 0 {
+		selectivity = min(1, rowCount/float64(realtimeRowCount))
+	}
+	selTraceAppend(sctx, &tracing.SelectivityTraceRecord{
+		TableID:     tableID,
+		Columns:     colNames,
+		Index:       idxName,
+		Predicate:   predicate,
+		Method:      method,
+		RowCount:    rowCount,
+		Selectivity: selectivity,
+	})
+}
+
+// selTraceExprs appends the selectivity computation of the expressions which are not estimated by the stats of the
+// columns or the indexes into the optimizer trace.
+func selTraceExprs(sctx planctx.PlanContext, tableID int64, exprs []expression.Expression, method string,
+	selectivity float64, realtimeRowCount int64) {
+	exprStrs := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		exprStr, err := exprToString(sctx.GetExprCtx().GetEvalCtx(), expr)
+		if err != nil {
+			logutil.BgLogger().Debug("Failed to trace selectivity of an expression", zap.String("category", "OptimizerTrace"),
+				zap.Any("expression", expr))
+			return
+		}
+		exprStrs = append(exprStrs, exprStr)
+	}
+	selTraceAppend(sctx, &tracing.SelectivityTraceRecord{
+		TableID:     tableID,
+		Predicate:   strings.Join(exprStrs, " and "),
+		Method:      method,
+		RowCount:    selectivity * float64(realtimeRowCount),
+		Selectivity: selectivity,
+	})
+}
+
+func selTraceAppend(sctx planctx.PlanContext, rec *tracing.SelectivityTraceRecord) {
+	if GetTblInfoForUsedStatsByPhysicalID != nil && rec.TableID > 0 {
+		rec.TableName, _ = GetTblInfoForUsedStatsByPhysicalID(sctx, rec.TableID)
+	}
+	tracer := sctx.GetSessionVars().StmtCtx.OptimizeTracer
+	tracer.Selectivity = append(tracer.Selectivity, rec)
+}
+
+// selTraceMethods joins the methods used to estimate the row count of a range, skipping the duplicated ones.
+func selTraceMethods(methods []string, method string) []string {
+	if slices.Contains(methods, method) {
+		return methods
+	}
+	return append(methods, method)
+}
+
 /*
  Below is debug trace for the estimation for each single range inside GetRowCountByXXX().
 */
@@ -112,6 +112,58 @@ func TestTraceCE(t *testing.T) {
 	}
 }
 
+func TestTraceSelectivity(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c varchar(10), index ia(a))")
+	tk.MustExec(`insert into t values (1, 1, "aaa"), (1, 2, "bbb"), (1, 3, "ccc"), (1, 4, "ddd"),
+		(2, 5, "eee"), (3, 6, "fff"), (4, 7, "ggg"), (5, 8, "hhh")`)
+	tk.MustExec("analyze table t all columns with 1 topn")
+
+	sql := "select * from t where a = 1 and b > 2 and c like '%a%'"
+	tk.MustExec("explain " + sql)
+	require.NoError(t, dom.StatsHandle().LoadNeededHistograms(dom.InfoSchema()))
+
+	sctx := tk.Session().(sessionctx.Context)
+	is := sctx.GetInfoSchema().(infoschema.InfoSchema)
+	stmtCtx := sctx.GetSessionVars().StmtCtx
+	stmtCtx.EnableOptimizeTrace = true
+	defer func() {
+		stmtCtx.EnableOptimizeTrace = false
+	}()
+	stmt, err := parser.New().ParseOneStmt(sql, "", "")
+	require.NoError(t, err)
+	_, _, err = plannercore.OptimizeAstNode(context.Background(), sctx, resolve.NewNodeW(stmt), is)
+	require.NoError(t, err)
+
+	records := stmtCtx.OptimizeTracer.Selectivity
+	findRecord := func(index, method, predicate string) *tracing.SelectivityTraceRecord {
+		for _, rec := range records {
+			if rec.Index == index && strings.HasPrefix(rec.Method, method) && strings.Contains(rec.Predicate, predicate) {
+				return rec
+			}
+		}
+		return nil
+	}
+	// The point on the index hits the TopN.
+	rec := findRecord("ia", "TopN Hit", "a = 1")
+	require.NotNil(t, rec)
+	require.Equal(t, "t", rec.TableName)
+	require.Equal(t, []string{"a"}, rec.Columns)
+	require.Equal(t, 4.0, rec.RowCount)
+	require.Equal(t, 0.5, rec.Selectivity)
+	// The range on the column is estimated by the histogram.
+	rec = findRecord("", "Bucket Interpolation", "b > 2")
+	require.NotNil(t, rec)
+	require.Equal(t, []string{"b"}, rec.Columns)
+	// The string matching function can't be estimated by the stats.
+	rec = findRecord("", "Default", "like")
+	require.NotNil(t, rec)
+	require.Equal(t, 0.8, rec.Selectivity)
+}
+
 func TestTraceDebugSelectivity(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	tk := testkit.NewTestKit(t, store)
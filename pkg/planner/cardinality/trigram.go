@@ -0,0 +1,166 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinality
+
+import (
+	"regexp/syntax"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/expression"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/planner/planctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/util/chunk"
+)
+
+// getTrigramSelectivity estimates the selectivity of the string matching functions on the string columns by the
+// trigram stats. It supports `col LIKE const`, `col ILIKE const`, `col REGEXP const`, `REGEXP_LIKE(col, const)` and
+// the negations of them, where the literal parts of the pattern must contain at least 3 characters.
+func getTrigramSelectivity(ctx planctx.PlanContext, coll *statistics.HistColl, expr expression.Expression) (float64, bool) {
+	sf, ok := expr.(*expression.ScalarFunction)
+	if !ok {
+		return 0, false
+	}
+	if sf.FuncName.L == ast.UnaryNot {
+		inner, ok := expression.GetExprInsideIsTruth(sf.GetArgs()[0]).(*expression.ScalarFunction)
+		if !ok {
+			return 0, false
+		}
+		stats, sel, ok := getStrMatchTrigramSelectivity(ctx, coll, inner)
+		if !ok {
+			return 0, false
+		}
+		// The rows whose value is NULL match neither the function nor its negation.
+		return max(1-sel-stats.NullSelectivity(), 0), true
+	}
+	_, sel, ok := getStrMatchTrigramSelectivity(ctx, coll, sf)
+	return sel, ok
+}
+
+func getStrMatchTrigramSelectivity(ctx planctx.PlanContext, coll *statistics.HistColl, sf *expression.ScalarFunction) (*statistics.TrigramStats, float64, bool) {
+	args := sf.GetArgs()
+	if len(args) < 2 {
+		return nil, 0, false
+	}
+	col, ok := args[0].(*expression.Column)
+	if !ok {
+		return nil, 0, false
+	}
+	stats, ok := coll.TrigramStats[col.UniqueID]
+	if !ok {
+		return nil, 0, false
+	}
+	pattern, ok := evalTrigramPatternArg(ctx, args[1])
+	if !ok {
+		return nil, 0, false
+	}
+	var substrs []string
+	switch sf.FuncName.L {
+	case ast.Like, ast.Ilike:
+		escape := int64('\\')
+		if len(args) == 3 {
+			c, ok := args[2].(*expression.Constant)
+			if !ok || c.Value.IsNull() {
+				return nil, 0, false
+			}
+			escape = c.Value.GetInt64()
+		}
+		substrs = extractLikeLiterals(pattern, rune(escape))
+	case ast.Regexp, ast.RegexpLike:
+		// The match type of REGEXP_LIKE may change the meaning of the pattern.
+		if len(args) != 2 {
+			return nil, 0, false
+		}
+		substrs, ok = extractRegexpLiterals(pattern)
+		if !ok {
+			return nil, 0, false
+		}
+	default:
+		return nil, 0, false
+	}
+	sel, ok := stats.SubstringSelectivity(substrs)
+	return stats, sel, ok
+}
+
+// evalTrigramPatternArg evaluates the pattern of the string matching functions, which must be a constant string.
+func evalTrigramPatternArg(ctx planctx.PlanContext, expr expression.Expression) (string, bool) {
+	c, ok := expr.(*expression.Constant)
+	if !ok || expression.MaybeOverOptimized4PlanCache(ctx.GetExprCtx(), []expression.Expression{c}) {
+		return "", false
+	}
+	val, err := c.Eval(ctx.GetExprCtx().GetEvalCtx(), chunk.Row{})
+	if err != nil || val.IsNull() {
+		return "", false
+	}
+	str, err := val.ToString()
+	if err != nil {
+		return "", false
+	}
+	return str, true
+}
+
+// extractLikeLiterals returns the literal parts of the LIKE pattern, which are separated by the wildcards.
+func extractLikeLiterals(pattern string, escape rune) []string {
+	var (
+		substrs []string
+		sb      strings.Builder
+		escaped bool
+	)
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			sb.WriteRune(r)
+			escaped = false
+		case r == escape:
+			escaped = true
+		case r == '%' || r == '_':
+			if sb.Len() > 0 {
+				substrs = append(substrs, sb.String())
+				sb.Reset()
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if escaped {
+		// A trailing escape character matches itself.
+		sb.WriteRune(escape)
+	}
+	if sb.Len() > 0 {
+		substrs = append(substrs, sb.String())
+	}
+	return substrs
+}
+
+// extractRegexpLiterals returns the literal parts which must be contained in the strings matching the regular
+// expression. Only the literals concatenated at the top level are extracted, such as `abc` and `def` of `abc.*def`.
+func extractRegexpLiterals(pattern string) ([]string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, false
+	}
+	re = re.Simplify()
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+	var substrs []string
+	for _, sub := range subs {
+		if sub.Op == syntax.OpLiteral {
+			substrs = append(substrs, string(sub.Rune))
+		}
+	}
+	return substrs, true
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/asyncload"
 	"github.com/pingcap/tidb/pkg/statistics/handle/types"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/pingcap/tidb/pkg/testkit/testdata"
@@ -336,6 +337,67 @@ func TestPlanStatsLoadTimeout(t *testing.T) {
 	default:
 		t.Error("unexpected plan:", pp)
 	}
+
+	// The row count in the stats meta is used in the degraded mode, and the pseudo stats are used in the strict mode.
+	for _, mode := range []string{"degraded", "strict"} {
+		tk.MustExec(fmt.Sprintf("set @@session.tidb_stats_load_timeout_mode = '%s'", mode))
+		ctx.GetSessionVars().StmtCtx.SetWarnings(nil)
+		plan, _, err = planner.Optimize(context.TODO(), ctx, nodeW, is)
+		require.NoError(t, err)
+		stats := plan.StatsInfo().HistColl
+		require.Equal(t, mode == "strict", stats.Pseudo)
+		if mode == "degraded" {
+			require.Equal(t, int64(3), stats.RealtimeCount)
+			warnings := make([]string, 0)
+			for _, w := range ctx.GetSessionVars().StmtCtx.GetWarnings() {
+				warnings = append(warnings, w.Err.Error())
+			}
+			require.Contains(t, warnings, "use the partial stats of table t since the stats loading timed out")
+		}
+	}
+}
+
+func TestPlanCacheStatsPrefetch(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("set @@session.tidb_stats_load_sync_wait = 60000")
+	tk.MustExec("create table t(a int, b int, c int)")
+	tk.MustExec("insert into t values (1,1,1),(2,2,2),(3,3,3)")
+	tk.MustExec("analyze table t all columns")
+	h := dom.StatsHandle()
+	is := dom.InfoSchema()
+	tbl, err := is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := tbl.Meta()
+	colC := tableInfo.Columns[2].ID
+
+	// The stats of c are needed by the cached plan.
+	tk.MustExec("prepare stmt from 'select * from t where c > ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustExec("execute stmt using @a")
+	tk.MustExec("execute stmt using @a")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	require.True(t, slices.ContainsFunc(asyncload.PlanCacheNeededItems.AllItems(), func(item model.StatsLoadItem) bool {
+		return item.TableID == tableInfo.ID && item.ID == colC && !item.IsIndex
+	}))
+
+	// The stats evicted from the stats cache are loaded again in the background.
+	h.Clear()
+	require.NoError(t, h.Update(context.Background(), is))
+	col := h.GetTableStats(tableInfo).GetCol(colC)
+	require.True(t, col == nil || !col.IsFullLoad())
+	asyncload.PlanCacheNeededItems.Prefetch(time.Now(), asyncload.PlanCacheItemsTTL)
+	require.NoError(t, h.LoadNeededHistograms(is))
+	col = h.GetTableStats(tableInfo).GetCol(colC)
+	require.True(t, col != nil && col.IsFullLoad())
+
+	// The items which are not used for a long time are removed.
+	asyncload.PlanCacheNeededItems.Prefetch(time.Now().Add(2*asyncload.PlanCacheItemsTTL), asyncload.PlanCacheItemsTTL)
+	require.False(t, slices.ContainsFunc(asyncload.PlanCacheNeededItems.AllItems(), func(item model.StatsLoadItem) bool {
+		return item.TableID == tableInfo.ID && item.ID == colC && !item.IsIndex
+	}))
 }
 
 func TestPlanStatsStatusRecord(t *testing.T) {
@@ -17,6 +17,7 @@ package casetest
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tidb/pkg/parser"
@@ -155,3 +156,196 @@ func TestNDVGroupCols(t *testing.T) {
 		tk.MustQuery("explain format = 'brief' " + tt).Check(testkit.Rows(output[i].Plan...))
 	}
 }
+
+func TestIgnoreStats(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, key(a))")
+	tk.MustExec("insert into t values(1,1),(2,2),(3,3),(4,4),(5,5),(6,6),(7,7),(8,8),(9,9),(10,10)")
+	tk.MustExec("analyze table t all columns")
+	isPseudo := func(sql string) bool {
+		for _, row := range tk.MustQuery("explain " + sql).Rows() {
+			if strings.Contains(fmt.Sprint(row), "stats:pseudo") {
+				return true
+			}
+		}
+		return false
+	}
+
+	require.False(t, isPseudo("select * from t where b = 1"))
+	require.True(t, isPseudo("select /*+ ignore_stats(t) */ * from t where b = 1"))
+	require.True(t, isPseudo("select /*+ ignore_stats(t1) */ * from t t1, t t2 where t1.b = t2.b"))
+	// The hint must use the alias of the table.
+	require.False(t, isPseudo("select /*+ ignore_stats(t) */ * from t t1 where b = 1"))
+	tk.MustQuery("explain select /*+ ignore_stats(t2) */ * from t where b = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1815 There are no matching table names for (t2) in optimizer hint /*+ IGNORE_STATS(t2) */. Maybe you can use the table alias name"))
+	tk.MustQuery("explain select /*+ ignore_stats() */ * from t where b = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1815 Hint IGNORE_STATS() is inapplicable. Please specify the table names in the arguments."))
+
+	tk.MustExec("set @@tidb_ignore_stats = 'Test.T'")
+	require.True(t, isPseudo("select * from t where b = 1"))
+	tk.MustExec("set @@tidb_ignore_stats = 'test.t2'")
+	require.False(t, isPseudo("select * from t where b = 1"))
+	tk.MustGetErrMsg("set @@tidb_ignore_stats = 't'", "[variable:1231]Variable 'tidb_ignore_stats' can't be set to the value of 't'")
+	tk.MustExec("set @@tidb_ignore_stats = ''")
+	require.False(t, isPseudo("select * from t where b = 1"))
+}
+
+func TestExplainWithStats(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, key ia(a))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d)", i%10, i))
+	}
+	tk.MustExec("analyze table t all columns")
+	tk.MustExec("create table t2(a int)")
+	estRows := func(explain, op string) string {
+		for _, row := range tk.MustQuery(explain).Rows() {
+			if strings.Contains(row[0].(string), op) {
+				return row[1].(string)
+			}
+		}
+		require.FailNow(t, "the operator is not found", "explain: %s, operator: %s", explain, op)
+		return ""
+	}
+
+	require.Equal(t, "100.00", estRows("explain format = 'brief' select * from t", "TableFullScan"))
+	require.Equal(t, "10000.00", estRows(`explain with stats ('{"t": {"count": 10000}}') format = 'brief' select * from t`, "TableFullScan"))
+	require.Equal(t, "10000.00", estRows(`explain with stats ('{"Test.T": {"count": 10000}}') select * from t`, "TableFullScan"))
+	require.Equal(t, "100.00", estRows("explain format = 'brief' select b from t group by b", "HashAgg"))
+	require.Equal(t, "5.00", estRows(`explain with stats ('{"t": {"columns": {"b": {"ndv": 5}}}}') format = 'brief' select b from t group by b`, "HashAgg"))
+	// The stats cache is not modified by the overlay.
+	require.Equal(t, "100.00", estRows("explain format = 'brief' select * from t", "TableFullScan"))
+	require.Equal(t, "100.00", estRows("explain format = 'brief' select b from t group by b", "HashAgg"))
+	require.Nil(t, tk.Session().GetSessionVars().StmtCtx.StatsOverlay)
+
+	tk.MustQuery(`explain with stats ('{"t2": {"count": 100, "columns": {"a": {"ndv": 5}}}}') select * from t2`)
+	tk.MustQuery("show warnings").CheckContain("the column a of test.t2 is not analyzed, its stats are not overlaid")
+
+	tk.MustGetErrMsg(`explain with stats ('{"t3": {"count": 100}}') select * from t`, "[schema:1146]Table 'test.t3' doesn't exist")
+	tk.MustGetErrMsg(`explain with stats ('{"t": {"columns": {"c": {"ndv": 5}}}}') select * from t`, "[planner:1054]Unknown column 'c' in 't'")
+	tk.MustGetErrMsg(`explain with stats ('{"t": {"indices": {"ic": {"ndv": 5}}}}') select * from t`, "[planner:1176]Key 'ic' doesn't exist in table 't'")
+	tk.MustGetErrMsg(`explain with stats ('{"t": {"count": -1}}') select * from t`, "invalid stats of EXPLAIN WITH STATS: count of t is negative")
+	tk.MustGetErrMsg(`explain with stats ('{"t": {"row_count": 100}}') select * from t`, `invalid stats of EXPLAIN WITH STATS: json: unknown field "row_count"`)
+}
+
+func TestStaleStatsRiskMode(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1(a int, b int, key(a))")
+	tk.MustExec("create table t2(a int, b int, key(a))")
+	plan := func(sql string) string {
+		var sb strings.Builder
+		for _, row := range tk.MustQuery("explain format = 'brief' " + sql).Rows() {
+			sb.WriteString(fmt.Sprintln(row[0]))
+		}
+		return sb.String()
+	}
+	joinSQL := "select * from t1 join t2 on t1.a = t2.a"
+	aggSQL := "select sum(t1.b) from t1 join t2 on t1.a = t2.a group by t1.a"
+	tk.MustExec("set @@tidb_opt_agg_push_down = 1")
+	pushedPlan := plan(aggSQL)
+	tk.MustExec("set @@tidb_opt_agg_push_down = 0")
+	require.NotEqual(t, pushedPlan, plan(aggSQL))
+
+	// The tables have never been analyzed, so their stats are risky in the robust mode.
+	tk.MustExec("set @@tidb_opt_stale_stats_risk_mode = 'robust'")
+	require.Regexp(t, "Index(Hash|Merge)?Join", plan(joinSQL))
+	tk.MustExec("set @@tidb_opt_agg_push_down = 1")
+	robustPlan := plan(aggSQL)
+	tk.MustExec("set @@tidb_opt_agg_push_down = 0")
+	require.Equal(t, robustPlan, plan(aggSQL))
+	// The join hints are still respected.
+	require.Regexp(t, "(?m)(^|─)HashJoin", plan("select /*+ hash_join(t1, t2) */ * from t1 join t2 on t1.a = t2.a"))
+
+	tk.MustExec("set @@tidb_opt_stale_stats_healthy_threshold = 0")
+	tk.MustExec("set @@tidb_opt_agg_push_down = 1")
+	require.Equal(t, pushedPlan, plan(aggSQL))
+}
+
+func TestHypoIndexEstimation(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d)", i%10, i))
+	}
+	tk.MustExec("analyze table t all columns")
+	tk.MustExec("create index hypo_a type hypo on t(a)")
+	estRows := func(sql string) string {
+		for _, row := range tk.MustQuery("explain " + sql).Rows() {
+			if strings.Contains(row[3].(string), "index:hypo_a(a)") {
+				return row[1].(string)
+			}
+		}
+		return ""
+	}
+
+	// The hypothetical index is estimated by the stats of column a rather than the pseudo stats.
+	require.Equal(t, "10.00", estRows("select /*+ use_index(t, hypo_a) */ * from t where a = 1"))
+	require.Equal(t, "30.00", estRows("select /*+ use_index(t, hypo_a) */ * from t where a in (1, 2, 3)"))
+	require.Equal(t, "100.00", estRows("select /*+ use_index(t, hypo_a) */ * from t"))
+	tk.MustExec("drop hypo index hypo_a on t")
+	require.Equal(t, "", estRows("select * from t where a = 1"))
+}
+
+func TestWindowFuncNDV(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d)", i%10, i))
+	}
+	tk.MustExec("analyze table t all columns")
+	estRows := func(sql string) string {
+		return tk.MustQuery("explain format = 'brief' " + sql).Rows()[0][1].(string)
+	}
+
+	// The ranking functions have at most as many distinct results as the rows of a partition.
+	require.Equal(t, "10.00", estRows("select rn from (select row_number() over (partition by a order by b) as rn from t) tt group by rn"))
+	require.Equal(t, "4.00", estRows("select n from (select ntile(4) over (order by b) as n from t) tt group by n"))
+	require.Equal(t, "1.00", estRows("select r from (select rank() over (partition by a) as r from t) tt group by r"))
+	// The aggregate functions have one result for each partition if the frame is the whole partition.
+	require.Equal(t, "10.00", estRows("select s from (select sum(b) over (partition by a) as s from t) tt group by s"))
+	require.Equal(t, "10.00", estRows(`select s from (select sum(b) over (partition by a order by b
+		rows between unbounded preceding and unbounded following) as s from t) tt group by s`))
+	require.Equal(t, "100.00", estRows("select s from (select sum(b) over (partition by a order by b) as s from t) tt group by s"))
+	// The results of LEAD and LAG are the values of their arguments.
+	require.Equal(t, "10.00", estRows("select l from (select lead(a) over (order by b) as l from t) tt group by l"))
+}
+
+func TestDerivedSelectivityCache(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, index ia(a))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d)", i%10, i))
+	}
+	tk.MustExec("analyze table t all columns")
+
+	// The selectivity of the same filters on the same table is derived once in the statement.
+	rows := tk.MustQuery(`explain format = 'brief' with c as (select /*+ merge() */ * from t where a < 3 and b > 50)
+		select /*+ hash_join(c1, c2) */ * from c c1, c c2 where c1.b = c2.b`).Rows()
+	require.Len(t, tk.Session().GetSessionVars().StmtCtx.DerivedSelectivities, 1)
+	var estRows []string
+	for _, row := range rows {
+		if strings.Contains(row[0].(string), "Selection") {
+			estRows = append(estRows, row[1].(string))
+		}
+	}
+	require.Len(t, estRows, 2)
+	require.Equal(t, estRows[0], estRows[1])
+
+	// The different filters are derived separately, and the cache is cleared by the next statement.
+	tk.MustQuery("explain format = 'brief' select * from (select * from t where a < 3) t1, (select * from t where a < 4) t2 where t1.b = t2.b")
+	require.Len(t, tk.Session().GetSessionVars().StmtCtx.DerivedSelectivities, 2)
+}
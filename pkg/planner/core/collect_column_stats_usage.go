@@ -146,6 +146,16 @@ func (c *columnStatsUsageCollector) collectPredicateColumnsForDataSource(ds *log
 	}
 	// We should use `PushedDownConds` here. `AllConds` is used for partition pruning, which doesn't need stats.
 	c.addPredicateColumnsFromExpressions(ds.PushedDownConds, true)
+	// The stats of the virtual generated columns are used to estimate the predicates on their expressions, so
+	// they are also regarded as predicate columns. See substituteVirtualColumns4Estimation for details.
+	for _, col := range ds.TblCols {
+		if _, ok := c.colMap[col.UniqueID]; !ok && col.VirtualExpr != nil {
+			c.colMap[col.UniqueID] = map[model.TableItemID]struct{}{{TableID: tblID, ID: col.ID, IsIndex: false}: {}}
+		}
+	}
+	if conds, ok := substituteVirtualColumns4Estimation(ds, ds.PushedDownConds, func(*expression.Column) bool { return true }); ok {
+		c.addPredicateColumnsFromExpressions(conds, true)
+	}
 }
 
 func (c *columnStatsUsageCollector) collectPredicateColumnsForJoin(p *logicalop.LogicalJoin) {
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/expression"
@@ -35,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/pkg/planner/util"
 	"github.com/pingcap/tidb/pkg/planner/util/costusage"
 	"github.com/pingcap/tidb/pkg/planner/util/optimizetrace"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/table"
@@ -125,6 +127,8 @@ type ChecksumTable struct {
 	baseSchemaProducer
 
 	Tables []*resolve.TableNameW
+	// CompareWith is the DSN of the external MySQL to compare the chunked checksums with.
+	CompareWith string
 }
 
 // CancelDDLJobs represents a cancel DDL jobs plan.
@@ -272,10 +276,11 @@ type SetConfig struct {
 type RecommendIndexPlan struct {
 	baseSchemaProducer
 
-	Action   string
-	SQL      string
-	AdviseID int64
-	Options  []ast.RecommendIndexOption
+	Action    string
+	SQL       string
+	SQLDigest string
+	AdviseID  int64
+	Options   []ast.RecommendIndexOption
 }
 
 // SQLBindOpType repreents the SQL bind type
@@ -573,6 +578,12 @@ type AnalyzeInfo struct {
 	TableID       statistics.AnalyzeTableID
 	StatsVersion  int
 	V2Options     *V2AnalyzeOptions
+	// Incremental indicates only the rows appended after the last analyze are analyzed,
+	// and the result is merged with the existing stats.
+	Incremental bool
+	// Quick indicates only a tiny fraction of the regions are scanned, and the row count is estimated by the region
+	// metadata, so that the approximate stats of a very large table are built in seconds.
+	Quick bool
 }
 
 // V2AnalyzeOptions is used to hold analyze options information.
@@ -603,15 +614,35 @@ type AnalyzeIndexTask struct {
 	AnalyzeInfo
 }
 
+// AnalyzeTempTableTask is used for analyze temporary tables, whose stats are collected in the session.
+type AnalyzeTempTableTask struct {
+	DBName  string
+	TblInfo *model.TableInfo
+}
+
 // Analyze represents an analyze plan
 type Analyze struct {
 	baseSchemaProducer
 
 	ColTasks []AnalyzeColumnsTask
 	IdxTasks []AnalyzeIndexTask
-	Opts     map[ast.AnalyzeOptionType]uint64
+	// TempTableTasks are the temporary tables to analyze. Their data is only visible to the session, so they are
+	// analyzed in the session instead of being pushed down.
+	TempTableTasks []AnalyzeTempTableTask
+	Opts           map[ast.AnalyzeOptionType]uint64
 	// OptionsMap is used to store the options for each partition.
 	OptionsMap map[int64]V2AnalyzeOptions
+	// Priority is the priority of the scan requests, set by `ANALYZE TABLE ... PRIORITY LOW|HIGH`.
+	Priority mysql.PriorityEnum
+}
+
+// AnalyzeDatabase represents an analyze database plan.
+type AnalyzeDatabase struct {
+	baseSchemaProducer
+
+	DBName      pmodel.CIStr
+	Concurrency int
+	Budget      time.Duration
 }
 
 // LoadData represents a loaddata plan.
@@ -652,6 +683,7 @@ type ImportInto struct {
 	ColumnsAndUserVars []*ast.ColumnNameOrUserVar
 	Path               string
 	Format             *string
+	Datagen            *ast.DatagenSpec
 	Options            []*LoadDataOpt
 
 	GenCols InsertGeneratedColumns
@@ -664,7 +696,24 @@ type ImportInto struct {
 type LoadStats struct {
 	baseSchemaProducer
 
-	Path string
+	Path  string
+	Merge bool
+}
+
+// ModifyStats represents a plan injecting the stats of a table manually.
+type ModifyStats struct {
+	baseSchemaProducer
+
+	Table   *ast.TableName
+	Options []*ast.ModifyStatsOption
+}
+
+// AutoAnalyzeOptions represents a plan setting the auto analyze options of a table.
+type AutoAnalyzeOptions struct {
+	baseSchemaProducer
+
+	Table   *ast.TableName
+	Options []*ast.AutoAnalyzeOption
 }
 
 // LockStats represents a lock stats for table
@@ -690,10 +739,11 @@ type PlanReplayer struct {
 	File              string
 	HistoricalStatsTS uint64
 
-	Capture    bool
-	Remove     bool
-	SQLDigest  string
-	PlanDigest string
+	Capture           bool
+	Remove            bool
+	SQLDigest         string
+	PlanDigest        string
+	CaptureThresholds *ast.PlanReplayerCaptureThresholds
 }
 
 // SplitRegion represents a split regions plan.
@@ -885,6 +935,12 @@ func (e *Explain) prepareSchema() error {
 		}
 	case (format == types.ExplainFormatROW || format == types.ExplainFormatBrief || format == types.ExplainFormatPlanCache) && (e.Analyze || e.RuntimeStatsColl != nil):
 		fieldNames = []string{"id", "estRows", "actRows", "task", "access object", "execution info", "operator info", "memory", "disk"}
+	case format == types.ExplainFormatStats:
+		if e.Analyze || e.RuntimeStatsColl != nil {
+			fieldNames = []string{"id", "estRows", "actRows", "task", "access object", "stats info", "execution info", "operator info", "memory", "disk"}
+		} else {
+			fieldNames = []string{"id", "estRows", "task", "access object", "stats info", "operator info"}
+		}
 	case format == types.ExplainFormatDOT:
 		fieldNames = []string{"dot contents"}
 	case format == types.ExplainFormatHint:
@@ -937,7 +993,7 @@ func (e *Explain) RenderResult() error {
 					pp.SCtx().GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf("factor costs: %v", string(data)))
 
 					// output cost factor weights for cost calibration
-					factors := defaultVer2Factors.tolist()
+					factors := getVer2Factors().tolist()
 					weights := make(map[string]float64)
 					for _, factor := range factors {
 						if factorCost, ok := trace.GetFactorCosts()[factor.Name]; ok && factor.Value > 0 {
@@ -967,7 +1023,7 @@ func (e *Explain) RenderResult() error {
 	}
 
 	switch strings.ToLower(e.Format) {
-	case types.ExplainFormatROW, types.ExplainFormatBrief, types.ExplainFormatVerbose, types.ExplainFormatTrueCardCost, types.ExplainFormatCostTrace, types.ExplainFormatPlanCache:
+	case types.ExplainFormatROW, types.ExplainFormatBrief, types.ExplainFormatVerbose, types.ExplainFormatTrueCardCost, types.ExplainFormatCostTrace, types.ExplainFormatPlanCache, types.ExplainFormatStats:
 		if e.Rows == nil || e.Analyze {
 			flat := FlattenPhysicalPlan(e.TargetPlan, true)
 			e.explainFlatPlanInRowFormat(flat)
@@ -1153,7 +1209,11 @@ func (e *Explain) prepareOperatorInfo(p base.Plan, taskType, id string) {
 			row = append(row, costFormula)
 		}
 		actRows, analyzeInfo, memoryInfo, diskInfo := getRuntimeInfoStr(e.SCtx(), p, e.RuntimeStatsColl)
-		row = append(row, actRows, taskType, accessObject, analyzeInfo, operatorInfo, memoryInfo, diskInfo)
+		row = append(row, actRows, taskType, accessObject)
+		if e.Format == types.ExplainFormatStats {
+			row = append(row, getUsedStatsInfoForExplain(p))
+		}
+		row = append(row, analyzeInfo, operatorInfo, memoryInfo, diskInfo)
 	} else {
 		row = []string{id, estRows}
 		if e.Format == types.ExplainFormatVerbose || e.Format == types.ExplainFormatTrueCardCost ||
@@ -1163,11 +1223,33 @@ func (e *Explain) prepareOperatorInfo(p base.Plan, taskType, id string) {
 		if e.Format == types.ExplainFormatCostTrace {
 			row = append(row, costFormula)
 		}
-		row = append(row, taskType, accessObject, operatorInfo)
+		row = append(row, taskType, accessObject)
+		if e.Format == types.ExplainFormatStats {
+			row = append(row, getUsedStatsInfoForExplain(p))
+		}
+		row = append(row, operatorInfo)
 	}
 	e.Rows = append(e.Rows, row)
 }
 
+// getUsedStatsInfoForExplain returns the stats used to estimate the row counts of the scan on the table,
+// which is shown in EXPLAIN FORMAT='stats'.
+func getUsedStatsInfoForExplain(p base.Plan) string {
+	var usedStatsInfo *stmtctx.UsedStatsInfoForTable
+	switch x := p.(type) {
+	case *PhysicalTableScan:
+		usedStatsInfo = x.usedStatsInfo
+	case *PhysicalIndexScan:
+		usedStatsInfo = x.usedStatsInfo
+	default:
+		return ""
+	}
+	if usedStatsInfo == nil {
+		return "N/A"
+	}
+	return usedStatsInfo.FormatDetailsForExplain()
+}
+
 func (e *Explain) prepareOperatorInfoForJSONFormat(p base.Plan, taskType, id string, explainID string) *ExplainInfoForEncode {
 	if p.ExplainID().String() == "_0" {
 		return nil
@@ -1503,3 +1585,48 @@ func IsAutoCommitTxn(vars *variable.SessionVars) bool {
 type AdminShowBDRRole struct {
 	baseSchemaProducer
 }
+
+// AdminBenchStatsLoad represents an admin bench stats load plan.
+type AdminBenchStatsLoad struct {
+	baseSchemaProducer
+
+	Table *resolve.TableNameW
+}
+
+// AdminShowColumnStatsUsage represents an admin show column stats usage plan.
+type AdminShowColumnStatsUsage struct {
+	baseSchemaProducer
+
+	Table *resolve.TableNameW
+}
+
+// AdminCalibrateCostModel represents an admin calibrate cost model plan.
+type AdminCalibrateCostModel struct {
+	baseSchemaProducer
+}
+
+// AdminShowPlanDiff represents an admin show plan diff plan.
+type AdminShowPlanDiff struct {
+	baseSchemaProducer
+
+	Limit uint64
+}
+
+// AdminCheckStats represents an admin check stats plan.
+type AdminCheckStats struct {
+	baseSchemaProducer
+
+	Table       *resolve.TableNameW
+	SampleCount uint64
+	// Threshold is the drift score above which the table is enqueued for auto analyze, 0 means it's never enqueued.
+	Threshold float64
+}
+
+// AdminValidateVectorIndex represents an admin validate vector index plan.
+type AdminValidateVectorIndex struct {
+	baseSchemaProducer
+
+	Table       *resolve.TableNameW
+	IndexInfo   *model.IndexInfo
+	SampleCount uint64
+}
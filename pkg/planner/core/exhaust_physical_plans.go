@@ -2025,6 +2025,11 @@ func exhaustPhysicalPlans4LogicalJoin(lp base.LogicalPlan, prop *property.Physic
 		if forced {
 			return indexJoins, true, nil
 		}
+		// The estimations on the risky stats are not trusted, so the index joins are preferred since the cost of them is
+		// bounded by the outer rows, while a hash join may build a massive table underestimated by the stale stats.
+		if len(indexJoins) > 0 && p.PreferJoinType == 0 && hasRiskyStats(p) {
+			return indexJoins, true, nil
+		}
 		joins = append(joins, indexJoins...)
 	}
 
@@ -2483,6 +2483,22 @@ func isPointGetPath(ds *logicalop.DataSource, path *util.AccessPath) bool {
 }
 
 // convertToTableScan converts the DataSource to table scan.
+// vectorIndexScanRowCount estimates the number of the rows read by an ANN search through the vector index. Besides
+// the TopK rows found by the index, the rows not covered by the index yet are searched by brute force, whose number
+// is estimated by the vector stats of the column.
+func vectorIndexScanRowCount(ds *logicalop.DataSource, idx *model.IndexInfo, topK uint32) float64 {
+	rowCount := float64(topK)
+	if ds.StatisticTable == nil || ds.StatisticTable.ExtendedStats == nil {
+		return rowCount
+	}
+	colID := ds.TableInfo.Columns[idx.Columns[0].Offset].ID
+	item, ok := ds.StatisticTable.ExtendedStats.Stats[statistics.VectorStatsName(colID)]
+	if !ok || item.Vector == nil {
+		return rowCount
+	}
+	return rowCount + item.Vector.UnindexedRowCount(idx.ID, float64(ds.StatisticTable.RealtimeCount))
+}
+
 func convertToTableScan(ds *logicalop.DataSource, prop *property.PhysicalProperty, candidate *candidatePath, _ *optimizetrace.PhysicalOptimizeOp) (base.Task, error) {
 	// It will be handled in convertToIndexScan.
 	if prop.TaskTp == property.CopMultiReadTaskType {
@@ -2541,7 +2557,7 @@ func convertToTableScan(ds *logicalop.DataSource, prop *property.PhysicalPropert
 					RefVecF32:      prop.VectorProp.Vec.SerializeTo(nil),
 				},
 			}
-			ts.SetStats(util.DeriveLimitStats(ts.StatsInfo(), float64(prop.VectorProp.TopK)))
+			ts.SetStats(util.DeriveLimitStats(ts.StatsInfo(), vectorIndexScanRowCount(ds, candidate.path.Index, prop.VectorProp.TopK)))
 		}
 		// ********************************** future deprecated start **************************/
 		var hasVirtualColumn bool
@@ -544,3 +544,37 @@ func (f *FlatPhysicalPlan) flattenScalarSubQRecursively(scalarSubQ *ScalarSubque
 	}
 	return target
 }
+
+// GetMaxEstimationError returns the max ratio between the estimated rows and the actual rows of the operators of the
+// executed plan, which is at least 1. The operators without runtime stats are skipped, and the actual rows of the
+// operators probed more than once are averaged by the probe count.
+func GetMaxEstimationError(p base.Plan) float64 {
+	maxErr := 1.0
+	runtimeStatsColl := p.SCtx().GetSessionVars().StmtCtx.RuntimeStatsColl
+	flat := FlattenPhysicalPlan(p, false)
+	if runtimeStatsColl == nil || flat == nil {
+		return maxErr
+	}
+	check := func(tree FlatPlanTree) {
+		for _, op := range tree {
+			pp, ok := op.Origin.(base.PhysicalPlan)
+			if !ok || (!runtimeStatsColl.ExistsRootStats(pp.ID()) && !runtimeStatsColl.ExistsCopStats(pp.ID())) {
+				continue
+			}
+			actRows := getOperatorActRows(pp)
+			if probeCnt := pp.GetActualProbeCnt(runtimeStatsColl); probeCnt > 1 {
+				actRows /= float64(probeCnt)
+			}
+			estRows, actRows := max(pp.StatsCount(), 1), max(actRows, 1)
+			maxErr = max(maxErr, estRows/actRows, actRows/estRows)
+		}
+	}
+	check(flat.Main)
+	for _, tree := range flat.CTEs {
+		check(tree)
+	}
+	for _, tree := range flat.ScalarSubQueries {
+		check(tree)
+	}
+	return maxErr
+}
@@ -587,6 +587,7 @@ func generateIndexMergeAndPaths(ds *logicalop.DataSource, normalPathCnt int, use
 		logutil.BgLogger().Debug("something wrong happened, use the default selectivity", zap.Error(err))
 		sel = cost.SelectionFactor
 	}
+	sel = cardinality.AdjustIntersectionSelectivity(ds.TableStats.HistColl, partialPaths, ds.TableStats.RowCount, sel)
 
 	indexMergePath := &util.AccessPath{
 		PartialIndexPaths:        partialPaths,
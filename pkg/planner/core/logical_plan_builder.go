@@ -59,6 +59,8 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle"
+	handleutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/table/tables"
 	"github.com/pingcap/tidb/pkg/table/temptable"
@@ -495,6 +497,53 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 	}
 }
 
+// isStatsIgnored checks whether the stats of the DataSource are ignored by the IGNORE_STATS hint or tidb_ignore_stats.
+func isStatsIgnored(ds *logicalop.DataSource, hintInfo *h.PlanHints) bool {
+	if hintInfo != nil {
+		alias := &h.HintedTable{DBName: ds.DBName, TblName: ds.TableInfo.Name, SelectOffset: ds.QueryBlockOffset()}
+		if len(ds.TableAsName.L) != 0 {
+			alias.TblName = *ds.TableAsName
+		}
+		if hintInfo.IfIgnoreStats(alias) {
+			return true
+		}
+	}
+	tables := ds.SCtx().GetSessionVars().IgnoreStatsTables
+	if len(tables) == 0 {
+		return false
+	}
+	_, ok := tables[variable.PinnedStatsKey(ds.DBName.L, ds.TableInfo.Name.L)]
+	return ok
+}
+
+// isStatsRisky checks whether the stats healthy of the DataSource is below tidb_opt_stale_stats_healthy_threshold in the
+// robust tidb_opt_stale_stats_risk_mode. The tables which have never been analyzed are always risky.
+func isStatsRisky(ds *logicalop.DataSource) bool {
+	vars := ds.SCtx().GetSessionVars()
+	if vars.StaleStatsRiskMode != variable.StaleStatsRiskModeRobust || ds.TableInfo.TempTableType != model.TempTableNone {
+		return false
+	}
+	statsHandle := domain.GetDomain(ds.SCtx()).StatsHandle()
+	if statsHandle == nil {
+		return false
+	}
+	healthy, _ := statsHandle.GetTableStats(ds.TableInfo).GetStatsHealthy()
+	return healthy < vars.StaleStatsHealthyThreshold
+}
+
+// hasRiskyStats checks whether the plan reads any table whose stats are risky, see isStatsRisky.
+func hasRiskyStats(p base.LogicalPlan) bool {
+	if ds, ok := p.(*logicalop.DataSource); ok {
+		return ds.RiskyStats
+	}
+	for _, child := range p.Children() {
+		if hasRiskyStats(child) {
+			return true
+		}
+	}
+	return false
+}
+
 func setPreferredStoreType(ds *logicalop.DataSource, hintInfo *h.PlanHints) {
 	if hintInfo == nil {
 		return
@@ -4082,13 +4131,32 @@ func getStatsTable(ctx base.PlanContext, tblInfo *model.TableInfo, pid int64) *s
 	if statsHandle == nil {
 		return statistics.PseudoTable(tblInfo, false, true)
 	}
+	if tblInfo.TempTableType != model.TempTableNone {
+		if tmpStatsTbl := getTemporaryTableStats(ctx, tblInfo); tmpStatsTbl != nil {
+			statsTbl = tmpStatsTbl
+			return statsTbl
+		}
+	}
 
+	physicalID := tblInfo.ID
 	if pid == tblInfo.ID || ctx.GetSessionVars().StmtCtx.UseDynamicPartitionPrune() {
 		statsTbl = statsHandle.GetTableStats(tblInfo)
 	} else {
 		usePartitionStats = true
+		physicalID = pid
 		statsTbl = statsHandle.GetPartitionStats(tblInfo, pid)
 	}
+	if pinnedStatsTbl := getPinnedStatsTable(ctx, statsHandle, tblInfo, physicalID); pinnedStatsTbl != nil {
+		statsTbl = pinnedStatsTbl
+	}
+	if sharedStatsTbl := getStatsTemplateSharedTable(ctx, statsHandle, tblInfo, statsTbl); sharedStatsTbl != nil {
+		statsTbl = sharedStatsTbl
+	}
+	if usePartitionStats {
+		if rolledUpStatsTbl := getRolledUpPartitionStatsTable(statsHandle, tblInfo, pid, statsTbl); rolledUpStatsTbl != nil {
+			statsTbl = rolledUpStatsTbl
+		}
+	}
 	intest.Assert(statsTbl.ColAndIdxExistenceMap != nil, "The existence checking map must not be nil.")
 
 	allowPseudoTblTriggerLoading := false
@@ -4114,6 +4182,8 @@ func getStatsTable(ctx base.PlanContext, tblInfo *model.TableInfo, pid int64) *s
 			statsTbl.ModifyCount = 0
 		}
 	}
+	// The stats overlaid by EXPLAIN WITH STATS take the place of the ones above.
+	statsTbl = overlayStatsTable(ctx, tblInfo, pid, statsTbl)
 
 	// 2. table row count from statistics is zero.
 	if statsTbl.RealtimeCount == 0 {
@@ -4124,6 +4194,14 @@ func getStatsTable(ctx base.PlanContext, tblInfo *model.TableInfo, pid int64) *s
 
 	// 3. statistics is uninitialized or outdated.
 	pseudoStatsForUninitialized = !statsTbl.IsInitialized()
+	if pseudoStatsForUninitialized && useDegradedStatsForLoadTimeout(ctx, statsTbl) {
+		// None of the stats are loaded because the sync loading timed out, but the table is analyzed, so the row count
+		// in the stats meta is still better than the pseudo one. The columns and indexes fall back to the pseudo stats
+		// one by one in the estimation.
+		pseudoStatsForUninitialized = false
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackErrorf(
+			"use the partial stats of table %s since the stats loading timed out", tblInfo.Name.O))
+	}
 	pseudoStatsForOutdated = ctx.GetSessionVars().GetEnablePseudoForOutdatedStats() && statsTbl.IsOutdated()
 	if pseudoStatsForUninitialized || pseudoStatsForOutdated {
 		tbl := *statsTbl
@@ -4139,6 +4217,123 @@ func getStatsTable(ctx base.PlanContext, tblInfo *model.TableInfo, pid int64) *s
 	return statsTbl
 }
 
+// getTemporaryTableStats returns the stats of the temporary table kept in the session. There are no histograms for
+// the temporary tables, so the columns and the indexes are estimated by the pseudo stats, but with the real row count.
+// It returns nil if the session has neither written nor analyzed the table.
+func getTemporaryTableStats(ctx base.PlanContext, tblInfo *model.TableInfo) *statistics.Table {
+	_, rowCount, ok := statistics.GetTemporaryTableStats(ctx.GetSessionVars(), tblInfo)
+	if !ok || rowCount == 0 {
+		return nil
+	}
+	statsTbl := statistics.PseudoTable(tblInfo, false, true)
+	statsTbl.RealtimeCount = rowCount
+	return statsTbl
+}
+
+// useDegradedStatsForLoadTimeout checks whether the optimizer uses the partial stats instead of the pseudo stats
+// for the table whose stats are not loaded because the sync loading timed out.
+func useDegradedStatsForLoadTimeout(ctx base.PlanContext, statsTbl *statistics.Table) bool {
+	sessVars := ctx.GetSessionVars()
+	return sessVars.StmtCtx.IsSyncStatsFailed &&
+		sessVars.StatsLoadTimeoutMode != variable.StatsLoadTimeoutModeStrict &&
+		!statsTbl.Pseudo && statsTbl.IsAnalyzed()
+}
+
+// getRolledUpPartitionStatsTable returns the stats of the most recent analyzed partition for an older partition of the
+// range partitioned table which has not been analyzed, since the older partitions are not analyzed automatically under
+// tidb_stats_rollup_recent_partitions. The stats are scaled to the row count in the rollup of the partition.
+// It returns nil if the stats of the partition are not rolled up or none of the partitions has been analyzed.
+func getRolledUpPartitionStatsTable(statsHandle *handle.Handle, tblInfo *model.TableInfo, pid int64, statsTbl *statistics.Table) *statistics.Table {
+	if statsTbl.IsAnalyzed() || statsTbl.RealtimeCount == 0 || !statistics.IsRolledUpPartition(tblInfo, pid) {
+		return nil
+	}
+	defs := tblInfo.GetPartitionInfo().Definitions
+	for i := len(defs) - 1; i >= 0; i-- {
+		recentStats := statsHandle.GetPartitionStats(tblInfo, defs[i].ID)
+		if recentStats.Pseudo || !recentStats.IsAnalyzed() {
+			continue
+		}
+		rolledUpStatsTbl := recentStats.ShallowCopy()
+		// The selectivity from the histograms is scaled by the real-time row count when estimating.
+		rolledUpStatsTbl.RealtimeCount = statsTbl.RealtimeCount
+		rolledUpStatsTbl.ModifyCount = 0
+		return rolledUpStatsTbl
+	}
+	return nil
+}
+
+// getStatsTemplateSharedTable returns the stats shared from the representative tables of the stats template
+// of the table if the table has not been analyzed. The shared stats are scaled to the row count of the table.
+// It returns nil if the table doesn't share the stats or none of the representative tables has been analyzed.
+func getStatsTemplateSharedTable(ctx base.PlanContext, statsHandle *handle.Handle, tblInfo *model.TableInfo, statsTbl *statistics.Table) *statistics.Table {
+	if statsTbl.IsAnalyzed() || statsTbl.RealtimeCount == 0 {
+		return nil
+	}
+	is, ok := ctx.GetInfoSchema().(infoschema.InfoSchema)
+	if !ok {
+		return nil
+	}
+	representatives, shared := handleutil.GetStatsTemplateRepresentatives(is, tblInfo)
+	if !shared {
+		return nil
+	}
+	for _, representative := range representatives {
+		representativeStats := statsHandle.GetTableStats(representative)
+		if representativeStats.Pseudo || !representativeStats.IsAnalyzed() {
+			continue
+		}
+		sharedStatsTbl := representativeStats.ShallowCopy()
+		// The selectivity from the histograms is scaled by the real-time row count when estimating.
+		sharedStatsTbl.RealtimeCount = statsTbl.RealtimeCount
+		sharedStatsTbl.ModifyCount = 0
+		return sharedStatsTbl
+	}
+	return nil
+}
+
+// pinnedStatsCacheEntry is the value of SessionVars.PinnedStatsCache.
+type pinnedStatsCacheEntry struct {
+	statsTbl *statistics.Table
+	// tblUpdateTS is the UpdateTS of the table info used to load the stats.
+	tblUpdateTS uint64
+}
+
+// getPinnedStatsTable returns the historical stats pinned by tidb_pin_stats for the table.
+// It returns nil if the table is not pinned or the pinned stats can't be loaded, and the caller should fall back
+// to the latest stats.
+func getPinnedStatsTable(ctx base.PlanContext, statsHandle *handle.Handle, tblInfo *model.TableInfo, physicalID int64) *statistics.Table {
+	vars := ctx.GetSessionVars()
+	if len(vars.PinnedStats) == 0 {
+		return nil
+	}
+	dbInfo, ok := ctx.GetInfoSchema().SchemaByID(tblInfo.DBID)
+	if !ok {
+		return nil
+	}
+	version, ok := vars.PinnedStats[variable.PinnedStatsKey(dbInfo.Name.L, tblInfo.Name.L)]
+	if !ok {
+		return nil
+	}
+	if entry, ok := vars.PinnedStatsCache[physicalID].(*pinnedStatsCacheEntry); ok && entry.tblUpdateTS == tblInfo.UpdateTS {
+		return entry.statsTbl
+	}
+	statsTbl, exist, err := statsHandle.HistoricalTableStats(tblInfo, physicalID, version)
+	if err != nil {
+		vars.StmtCtx.AppendWarning(err)
+		return nil
+	}
+	if !exist {
+		vars.StmtCtx.AppendWarning(errors.NewNoStackErrorf("no historical stats of %s.%s before version %d, use the latest stats instead",
+			dbInfo.Name.O, tblInfo.Name.O, version))
+		return nil
+	}
+	if vars.PinnedStatsCache == nil {
+		vars.PinnedStatsCache = make(map[int64]any)
+	}
+	vars.PinnedStatsCache[physicalID] = &pinnedStatsCacheEntry{statsTbl: statsTbl, tblUpdateTS: tblInfo.UpdateTS}
+	return statsTbl
+}
+
 // getLatestVersionFromStatsTable gets statistics information for a table specified by "tableID", and get the max
 // LastUpdateVersion among all Columns and Indices in it.
 // Its overall logic is quite similar to getStatsTable(). During plan cache matching, only the latest version is needed.
@@ -4666,6 +4861,8 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	ds.SetSchema(schema)
 	ds.SetOutputNames(names)
 	setPreferredStoreType(ds, b.TableHints())
+	ds.IgnoreStats = isStatsIgnored(ds, b.TableHints())
+	ds.RiskyStats = isStatsRisky(ds)
 	ds.SampleInfo = tablesampler.NewTableSampleInfo(tn.TableSample, schema, b.partitionedTable)
 	b.isSampling = ds.SampleInfo != nil
 
@@ -36,6 +36,9 @@ var (
 	sessionPlanCacheLookupDuration          prometheus.Observer
 	instancePlanCacheLookupDuration         prometheus.Observer
 	instancePlanCacheCloneDuration          prometheus.Observer
+	planCacheStatsInvalidationAnyChange     prometheus.Counter
+	planCacheStatsInvalidationRowCount      prometheus.Counter
+	planCacheStatsInvalidationPseudo        prometheus.Counter
 )
 
 func init() {
@@ -60,6 +63,9 @@ func InitMetricsVars() {
 	sessionPlanCacheLookupDuration = metrics.PlanCacheProcessDuration.WithLabelValues(" session-plan-cache-lookup")
 	instancePlanCacheLookupDuration = metrics.PlanCacheProcessDuration.WithLabelValues(" instance-plan-cache-lookup")
 	instancePlanCacheCloneDuration = metrics.PlanCacheProcessDuration.WithLabelValues(" instance-plan-cache-clone")
+	planCacheStatsInvalidationAnyChange = metrics.PlanCacheStatsInvalidation.WithLabelValues("any-change")
+	planCacheStatsInvalidationRowCount = metrics.PlanCacheStatsInvalidation.WithLabelValues("row-count-change")
+	planCacheStatsInvalidationPseudo = metrics.PlanCacheStatsInvalidation.WithLabelValues("pseudo-stats")
 }
 
 // GetPlanCacheHitCounter get different plan cache hit counter
@@ -112,6 +118,17 @@ func GetPlanCacheLookupDuration(instancePlanCache bool) prometheus.Observer {
 	return sessionPlanCacheLookupDuration
 }
 
+// GetPlanCacheStatsInvalidationCounter get the counter of the cached plans invalidated by stats updates for the reason.
+func GetPlanCacheStatsInvalidationCounter(rowCountChanged, pseudoStats bool) prometheus.Counter {
+	if pseudoStats {
+		return planCacheStatsInvalidationPseudo
+	}
+	if rowCountChanged {
+		return planCacheStatsInvalidationRowCount
+	}
+	return planCacheStatsInvalidationAnyChange
+}
+
 // GetPlanCacheInstanceEvict get instance plan cache evict counter.
 func GetPlanCacheInstanceEvict() prometheus.Gauge {
 	return instancePlanCacheInstanceNumEvict
@@ -67,6 +67,12 @@ type DataSource struct {
 
 	StatisticTable *statistics.Table
 	TableStats     *property.StatsInfo
+	// IgnoreStats means the pseudo stats are used for the table, which is set by the IGNORE_STATS hint or
+	// tidb_ignore_stats.
+	IgnoreStats bool
+	// RiskyStats means the stats of the table are too stale to be trusted in the robust tidb_opt_stale_stats_risk_mode,
+	// so the robust operators are preferred on it.
+	RiskyStats bool
 
 	// PossibleAccessPaths stores all the possible access path for physical plan, including table scan.
 	PossibleAccessPaths []*util.AccessPath
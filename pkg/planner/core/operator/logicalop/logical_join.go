@@ -31,8 +31,10 @@ import (
 	"github.com/pingcap/tidb/pkg/planner/funcdep"
 	"github.com/pingcap/tidb/pkg/planner/property"
 	"github.com/pingcap/tidb/pkg/planner/util"
+	"github.com/pingcap/tidb/pkg/planner/util/fixcontrol"
 	"github.com/pingcap/tidb/pkg/planner/util/optimizetrace"
 	"github.com/pingcap/tidb/pkg/planner/util/utilfuncp"
+	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/types"
 	utilhint "github.com/pingcap/tidb/pkg/util/hint"
 	"github.com/pingcap/tidb/pkg/util/intset"
@@ -505,6 +507,11 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		leftJoinKeys, rightJoinKeys,
 		childSchema[0], childSchema[1],
 		nil, nil)
+	if count, ok := p.estimateFKJoinRowCount(leftProfile, rightProfile, leftJoinKeys, rightJoinKeys); ok {
+		p.EqualCondOutCnt = count
+	} else if count, ok := p.estimateHistJoinRowCount(leftProfile, rightProfile, leftJoinKeys, rightJoinKeys); ok {
+		p.EqualCondOutCnt = count
+	}
 	if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin {
 		p.SetStats(&property.StatsInfo{
 			RowCount: leftProfile.RowCount * cost.SelectionFactor,
@@ -1198,6 +1205,111 @@ func (p *LogicalJoin) getGroupNDVs(colGroups [][]*expression.Column, childStats
 	return nil
 }
 
+// estimateFKJoinRowCount estimates the row count of the equal conditions by the foreign key stats if the join keys
+// are the foreign key columns of one side and the referenced unique columns of the other side.
+func (p *LogicalJoin) estimateFKJoinRowCount(leftProfile, rightProfile *property.StatsInfo, leftJoinKeys, rightJoinKeys []*expression.Column) (float64, bool) {
+	if len(leftJoinKeys) == 0 || !p.SCtx().GetSessionVars().EnableFKJoinEstimation {
+		return 0, false
+	}
+	if count, ok := estimateFKJoinRowCount(p.Children()[0], p.Children()[1], leftProfile, rightProfile, leftJoinKeys, rightJoinKeys); ok {
+		return count, true
+	}
+	return estimateFKJoinRowCount(p.Children()[1], p.Children()[0], rightProfile, leftProfile, rightJoinKeys, leftJoinKeys)
+}
+
+func estimateFKJoinRowCount(child, parent base.LogicalPlan, childProfile, parentProfile *property.StatsInfo, childKeys, parentKeys []*expression.Column) (float64, bool) {
+	// The child side may be joined with other tables, since each of its rows still references one parent row.
+	// The parent side must be a filtered table, so that its row count tells the selectivity of the filters.
+	childDS := findDataSourceOfColumns(child, childKeys, true)
+	parentDS := findDataSourceOfColumns(parent, parentKeys, false)
+	if childDS == nil || parentDS == nil || childDS.StatisticTable == nil || childDS.StatisticTable.ExtendedStats == nil || parentDS.TableStats == nil {
+		return 0, false
+	}
+	// A child row may match more than one parent row if the referenced columns are not unique.
+	if !parentDS.Schema().IsUnique(true, parentKeys...) && !parentDS.Schema().IsUnique(false, parentKeys...) {
+		return 0, false
+	}
+	for _, fk := range childDS.TableInfo.ForeignKeys {
+		item, ok := childDS.StatisticTable.ExtendedStats.Stats[statistics.ForeignKeyStatsName(fk.ID)]
+		if !ok || item.ForeignKey == nil || item.ForeignKey.RefTableID != parentDS.TableInfo.ID || !matchFKJoinKeys(item, childKeys, parentKeys) {
+			continue
+		}
+		return cardinality.EstimateFKJoinRowCount(childProfile.RowCount, parentProfile.RowCount, parentDS.TableStats.RowCount, item.ForeignKey), true
+	}
+	return 0, false
+}
+
+// estimateHistJoinRowCount estimates the row count of the equal condition by the histograms and TopN of the join keys
+// if both sides are filtered tables joined on a single key.
+func (p *LogicalJoin) estimateHistJoinRowCount(leftProfile, rightProfile *property.StatsInfo, leftJoinKeys, rightJoinKeys []*expression.Column) (float64, bool) {
+	if len(leftJoinKeys) != 1 || len(rightJoinKeys) != 1 ||
+		!fixcontrol.GetBoolWithDefault(p.SCtx().GetSessionVars().GetOptimizerFixControlMap(), fixcontrol.Fix56318, true) {
+		return 0, false
+	}
+	leftDS := findDataSourceOfColumns(p.Children()[0], leftJoinKeys, false)
+	rightDS := findDataSourceOfColumns(p.Children()[1], rightJoinKeys, false)
+	if leftDS == nil || rightDS == nil || leftDS.StatisticTable == nil || rightDS.StatisticTable == nil ||
+		leftDS.StatisticTable.Pseudo || rightDS.StatisticTable.Pseudo {
+		return 0, false
+	}
+	leftCol := leftDS.StatisticTable.GetCol(leftJoinKeys[0].ID)
+	rightCol := rightDS.StatisticTable.GetCol(rightJoinKeys[0].ID)
+	return cardinality.EstimateHistJoinRowCount(leftProfile.RowCount, rightProfile.RowCount, leftCol, rightCol)
+}
+
+// matchFKJoinKeys checks whether the join keys are exactly the foreign key columns and the referenced columns.
+func matchFKJoinKeys(item *statistics.ExtendedStatsItem, childKeys, parentKeys []*expression.Column) bool {
+	if len(item.ColIDs) != len(childKeys) || len(item.ForeignKey.RefColIDs) != len(childKeys) {
+		return false
+	}
+	for i := range childKeys {
+		matched := false
+		for j, colID := range item.ColIDs {
+			if childKeys[i].ID == colID && parentKeys[i].ID == item.ForeignKey.RefColIDs[j] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// findDataSourceOfColumns finds the DataSource producing all the columns through the operators which don't change
+// the columns. The joins are looked through only if passJoin is true.
+func findDataSourceOfColumns(p base.LogicalPlan, cols []*expression.Column, passJoin bool) *DataSource {
+	for {
+		switch x := p.(type) {
+		case *DataSource:
+			if x.Schema().ColumnsIndices(cols) == nil {
+				return nil
+			}
+			return x
+		case *LogicalSelection, *LogicalSort, *LogicalUnionScan:
+			p = x.Children()[0]
+		case *LogicalJoin:
+			if !passJoin {
+				return nil
+			}
+			var next base.LogicalPlan
+			for _, child := range x.Children() {
+				if child.Schema().ColumnsIndices(cols) != nil {
+					next = child
+					break
+				}
+			}
+			if next == nil {
+				return nil
+			}
+			p = next
+		default:
+			return nil
+		}
+	}
+}
+
 // PreferAny checks whether the join type is in the joinFlags.
 func (p *LogicalJoin) PreferAny(joinFlags ...uint) bool {
 	for _, flag := range joinFlags {
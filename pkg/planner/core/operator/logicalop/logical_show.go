@@ -60,6 +60,22 @@ type ShowContents struct {
 	Limit       *ast.Limit // Used for limit Result Set row number.
 
 	ImportJobID *int64 // Used for SHOW LOAD DATA JOB <jobID>
+
+	StatsSnapshot uint64 // Used for `show stats_histograms as of timestamp`, 0 means the latest stats.
+
+	AllAnalyzeJobs bool // Used for `show analyze status`, whether to fetch all the jobs instead of the latest ones.
+
+	StatsFilter *ShowStatsFilter // Used for `show stats_buckets` and `show stats_topn` to skip the stats not matched.
+}
+
+// ShowStatsFilter is the names compared by equal conditions in the WHERE clause of `show stats_buckets` and
+// `show stats_topn`. The tables and columns not matched are skipped before their stats are converted to rows, while
+// the WHERE clause is still evaluated on the rows. The names are in lower case, and the empty ones are not filtered.
+type ShowStatsFilter struct {
+	DBName        string
+	TableName     string
+	PartitionName string
+	ColumnName    string
 }
 
 const emptyShowContentsSize = int64(unsafe.Sizeof(ShowContents{}))
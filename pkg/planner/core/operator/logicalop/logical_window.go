@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/expression/aggregation"
 	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/planner/cardinality"
 	base2 "github.com/pingcap/tidb/pkg/planner/cascades/base"
 	"github.com/pingcap/tidb/pkg/planner/core/base"
 	ruleutil "github.com/pingcap/tidb/pkg/planner/core/rule/util"
@@ -370,7 +371,7 @@ func (p *LogicalWindow) PruneColumns(parentUsedCols []*expression.Column, opt *o
 // RecursiveDeriveStats inherits BaseLogicalPlan.LogicalPlan.<10th> implementation.
 
 // DeriveStats implements base.LogicalPlan.<11th> interface.
-func (p *LogicalWindow) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, _ []*expression.Schema, colGroups [][]*expression.Column) (*property.StatsInfo, error) {
+func (p *LogicalWindow) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, colGroups [][]*expression.Column) (*property.StatsInfo, error) {
 	if p.StatsInfo() != nil {
 		// Reload GroupNDVs since colGroups may have changed.
 		p.StatsInfo().GroupNDVs = p.GetGroupNDVs(colGroups, childStats)
@@ -386,26 +387,94 @@ func (p *LogicalWindow) DeriveStats(childStats []*property.StatsInfo, selfSchema
 		id := selfSchema.Columns[i].UniqueID
 		p.StatsInfo().ColNDVs[id] = childProfile.ColNDVs[id]
 	}
+	partitionCnt := 1.0
+	if partitionCols := p.GetPartitionByCols(); len(partitionCols) > 0 {
+		partitionCnt, _ = cardinality.EstimateColsNDVWithMatchedLen(partitionCols, childSchema[0], childProfile)
+	}
 	for i := childLen; i < selfSchema.Len(); i++ {
-		p.StatsInfo().ColNDVs[selfSchema.Columns[i].UniqueID] = childProfile.RowCount
+		ndv := p.estimateWindowFuncNDV(p.WindowFuncDescs[i-childLen], childProfile, partitionCnt)
+		p.StatsInfo().ColNDVs[selfSchema.Columns[i].UniqueID] = ndv
 	}
 	p.StatsInfo().GroupNDVs = p.GetGroupNDVs(colGroups, childStats)
 	return p.StatsInfo(), nil
 }
 
+// estimateWindowFuncNDV estimates the NDV of the results of the window function, assuming the rows are evenly split
+// into partitionCnt partitions. The ranking functions depend only on the positions of the rows in their partitions,
+// so they have at most as many distinct results as the rows of a partition. The other functions depend on the frame:
+// they have one result for each partition if the frame is the whole partition, otherwise one for each row.
+func (p *LogicalWindow) estimateWindowFuncNDV(desc *aggregation.WindowFuncDesc, childProfile *property.StatsInfo, partitionCnt float64) float64 {
+	rowCount := childProfile.RowCount
+	partitionSize := rowCount / max(partitionCnt, 1)
+	argNDV := func() float64 {
+		if len(desc.Args) > 0 {
+			if col, ok := desc.Args[0].(*expression.Column); ok {
+				if ndv, ok := childProfile.ColNDVs[col.UniqueID]; ok {
+					return ndv
+				}
+			}
+		}
+		return rowCount
+	}
+	var ndv float64
+	switch desc.Name {
+	case ast.WindowFuncRowNumber:
+		ndv = partitionSize
+	case ast.WindowFuncRank, ast.WindowFuncDenseRank, ast.WindowFuncPercentRank, ast.WindowFuncCumeDist:
+		// All the rows are peers if there's no ORDER BY, and the peers share the same result.
+		ndv = 1
+		if len(p.OrderBy) > 0 {
+			orderByCols := make([]*expression.Column, 0, len(p.OrderBy))
+			for _, item := range p.OrderBy {
+				orderByCols = append(orderByCols, item.Col)
+			}
+			orderByNDV, _ := cardinality.EstimateColsNDVWithMatchedLen(orderByCols, p.Children()[0].Schema(), childProfile)
+			ndv = min(partitionSize, orderByNDV)
+		}
+	case ast.WindowFuncNtile:
+		ndv = partitionSize
+		if c, ok := desc.Args[0].(*expression.Constant); ok && !c.Value.IsNull() {
+			ndv = min(ndv, float64(c.Value.GetInt64()))
+		}
+	case ast.WindowFuncLead, ast.WindowFuncLag:
+		ndv = argNDV()
+	default:
+		ndv = rowCount
+		if p.isWholePartitionFrame() {
+			ndv = partitionCnt
+		}
+		switch desc.Name {
+		case ast.WindowFuncFirstValue, ast.WindowFuncLastValue, ast.WindowFuncNthValue, ast.AggFuncMax, ast.AggFuncMin:
+			// The results are the values of the argument.
+			ndv = min(ndv, argNDV())
+		}
+	}
+	return max(min(ndv, rowCount), 1)
+}
+
+// isWholePartitionFrame returns whether the frame of the window functions is the whole partition.
+func (p *LogicalWindow) isWholePartitionFrame() bool {
+	if p.Frame == nil {
+		// The default frame is from the start of the partition to the current row and its peers if there's an
+		// ORDER BY, otherwise the whole partition.
+		return len(p.OrderBy) == 0
+	}
+	return p.Frame.Start != nil && p.Frame.Start.UnBounded && p.Frame.End != nil && p.Frame.End.UnBounded
+}
+
 // ExtractColGroups implements base.LogicalPlan.<12th> interface.
 func (p *LogicalWindow) ExtractColGroups(colGroups [][]*expression.Column) [][]*expression.Column {
-	if len(colGroups) == 0 {
-		return nil
-	}
-	childSchema := p.Children()[0].Schema()
-	_, offsets := childSchema.ExtractColGroups(colGroups)
-	if len(offsets) == 0 {
-		return nil
+	var extracted [][]*expression.Column
+	if len(colGroups) > 0 {
+		childSchema := p.Children()[0].Schema()
+		_, offsets := childSchema.ExtractColGroups(colGroups)
+		for _, offset := range offsets {
+			extracted = append(extracted, colGroups[offset])
+		}
 	}
-	extracted := make([][]*expression.Column, len(offsets))
-	for i, offset := range offsets {
-		extracted[i] = colGroups[offset]
+	// The NDV of the PARTITION BY columns is the number of the partitions.
+	if partitionCols := p.GetPartitionByCols(); len(partitionCols) > 1 {
+		extracted = append(extracted, expression.SortColumns(partitionCols))
 	}
 	return extracted
 }
@@ -1022,6 +1022,11 @@ func (ts *PhysicalTableScan) IsPartition() (bool, int64) {
 	return ts.isPartition, ts.physicalTableID
 }
 
+// PhysicalTableID returns the ID of the table or the partition it scans.
+func (ts *PhysicalTableScan) PhysicalTableID() int64 {
+	return ts.physicalTableID
+}
+
 // ResolveCorrelatedColumns resolves the correlated columns in range access.
 // We already limit range mem usage when building ranges in optimizer phase, so we don't need and shouldn't limit range
 // mem usage when rebuilding ranges during the execution phase.
@@ -2350,6 +2355,11 @@ func (p *PhysicalIndexScan) IsPartition() (bool, int64) {
 	return p.isPartition, p.physicalTableID
 }
 
+// PhysicalTableID returns the ID of the table or the partition it scans.
+func (p *PhysicalIndexScan) PhysicalTableID() int64 {
+	return p.physicalTableID
+}
+
 // IsPointGetByUniqueKey checks whether is a point get by unique key.
 func (p *PhysicalIndexScan) IsPointGetByUniqueKey(tc types.Context) bool {
 	return len(p.Ranges) == 1 &&
@@ -292,6 +292,10 @@ func lookupPlanCache(ctx context.Context, sctx sessionctx.Context, cacheKey stri
 		return nil, nil, nil, false
 	}
 	pcv := v.(*PlanCacheValue)
+	pcv.touchStatsItems(time.Now())
+	if pcv.warmUp != nil {
+		pcv.warmUp.hits.Inc()
+	}
 	return pcv.Plan, pcv.OutputColumns, pcv.stmtHints, true
 }
 
@@ -349,11 +353,14 @@ func generateNewPlan(ctx context.Context, sctx sessionctx.Context, isNonPrepared
 
 	// put this plan into the plan cache.
 	if stmtCtx.UseCache() {
-		cached := NewPlanCacheValue(p, names, paramTypes, &stmtCtx.StmtHints)
+		cached := NewPlanCacheValue(p, names, paramTypes, &stmtCtx.StmtHints, stmtCtx.StatsLoad.PlanNeededItems)
 		stmt.NormalizedPlan, stmt.PlanDigest = NormalizePlan(p)
 		stmtCtx.SetPlan(p)
 		stmtCtx.SetPlanDigest(stmt.NormalizedPlan, stmt.PlanDigest)
 		if instancePlanCacheEnabled(ctx) {
+			if !isNonPrepared && variable.PlanCacheWarmUpAfterAnalyze.Load() > 0 {
+				cached.warmUp = globalPlanCacheWarmUpStmts.record(sctx, stmt, paramTypes)
+			}
 			domain.GetDomain(sctx).GetInstancePlanCache().Put(cacheKey, cached, paramTypes)
 		} else {
 			sctx.GetSessionPlanCache().Put(cacheKey, cached, paramTypes)
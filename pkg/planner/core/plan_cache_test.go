@@ -119,6 +119,49 @@ func TestNonPreparedPlanCacheInformationSchema(t *testing.T) {
 	require.True(t, tk.Session().GetSessionVars().FoundInPlanCache)
 }
 
+func TestNonPreparedPlanCacheSubqueryAndJoin(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int, b int, key(a))")
+	tk.MustExec("create table t2 (a int, b int, key(a))")
+	tk.MustExec("create table t3 (a int, b int, key(a))")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t3 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+
+	queries := []string{
+		"select t1.a from t1, t2, t3 where t1.a = t2.a and t2.b = t3.b and t1.b < %d",
+		"select a from t1 where a in (select a from t2 where b < %d)",
+		"select a from t1 where exists (select 1 from t2 where t2.a = t1.a and t2.b < %d)",
+		"select t.a from (select a from t1 where b < %d) t",
+	}
+	for _, q := range queries {
+		// not cached by default
+		tk.MustQuery(fmt.Sprintf(q, 2)).Sort().Check(testkit.Rows("1"))
+		tk.MustQuery(fmt.Sprintf(q, 3)).Sort().Check(testkit.Rows("1", "2"))
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	}
+
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache_for_subquery = 1")
+	tk.MustExec("set @@tidb_non_prepared_plan_cache_max_join_tables = 3")
+	for _, q := range queries {
+		tk.MustQuery(fmt.Sprintf(q, 2)).Sort().Check(testkit.Rows("1"))
+		tk.MustQuery(fmt.Sprintf(q, 3)).Sort().Check(testkit.Rows("1", "2"))
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	}
+
+	// in-lists of different lengths are cached separately, and the long ones are not cached.
+	tk.MustExec("set @@tidb_non_prepared_plan_cache_max_in_list_len = 3")
+	tk.MustQuery("select a from t1 where a in (1, 2, 3)").Sort().Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select a from t1 where a in (1, 2, 4)").Sort().Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t1 where a in (1, 2, 3, 4)").Sort().Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select a from t1 where a in (1, 2, 3, 5)").Sort().Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+}
+
 func TestNonPreparedPlanTypeRandomly(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -1695,3 +1738,53 @@ func TestIssue54652(t *testing.T) {
 	tk.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("0")) // can't reuse since it's in txn now.
 	tk.MustExec(`commit`)
 }
+
+func TestPlanCacheStatsInvalidationPolicy(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec(`use test`)
+	tk.MustExec(`create table t (a int, b int, key(a))`)
+	tk.MustExec(`insert into t values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8), (9, 9), (10, 10)`)
+	tk.MustExec(`analyze table t`)
+	defer func() {
+		tk.MustExec(`set @@global.tidb_plan_cache_stats_invalidation_policy = default`)
+		tk.MustExec(`set @@global.tidb_plan_cache_stats_invalidation_row_count_ratio = default`)
+	}()
+	tk.MustExec(`set @@global.tidb_plan_cache_stats_invalidation_policy = 'row_count_change'`)
+	tk.MustExec(`set @@global.tidb_plan_cache_stats_invalidation_row_count_ratio = 0.5`)
+	require.Error(t, tk.ExecToErr(`set @@global.tidb_plan_cache_stats_invalidation_policy = 'unknown'`))
+
+	tk.MustExec(`prepare st from 'select * from t where a = ?'`)
+	tk.MustExec(`set @a = 1`)
+	tk.MustExec(`execute st using @a`)
+	tk.MustExec(`execute st using @a`)
+	tk.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("1"))
+
+	// The row count changed by 20%, which doesn't invalidate the cached plan.
+	tk.MustExec(`insert into t values (11, 11), (12, 12)`)
+	tk.MustExec(`analyze table t`)
+	tk.MustExec(`execute st using @a`)
+	tk.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("1"))
+
+	// The row count changed by 120% from the one which the cached plan is built on.
+	tk.MustExec(`insert into t select a + 12, b from t where a <= 10`)
+	tk.MustExec(`analyze table t`)
+	tk.MustExec(`execute st using @a`)
+	tk.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("0"))
+	tk.MustExec(`execute st using @a`)
+	tk.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("1"))
+
+	// Only the plans built on pseudo stats are invalidated.
+	tk.MustExec(`set @@global.tidb_plan_cache_stats_invalidation_policy = 'pseudo_only'`)
+	tk.MustExec(`insert into t select a + 22, b from t`)
+	tk.MustExec(`analyze table t`)
+	tk.MustExec(`execute st using @a`)
+	tk.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("1"))
+
+	// Any new version of the stats invalidates the cached plans.
+	tk.MustExec(`set @@global.tidb_plan_cache_stats_invalidation_policy = 'any_change'`)
+	tk.MustExec(`execute st using @a`)
+	tk.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("0"))
+	tk.MustExec(`execute st using @a`)
+	tk.MustQuery(`select @@last_plan_from_cache`).Check(testkit.Rows("1"))
+}
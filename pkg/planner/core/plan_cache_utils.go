@@ -22,6 +22,7 @@ import (
 	"sort"
 	"strconv"
 	"time"
+	"unsafe"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/bindinfo"
@@ -30,17 +31,20 @@ import (
 	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/kv"
+	tmodel "github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/planner/core/base"
+	core_metrics "github.com/pingcap/tidb/pkg/planner/core/metrics"
 	"github.com/pingcap/tidb/pkg/planner/core/resolve"
 	"github.com/pingcap/tidb/pkg/planner/core/rule"
 	"github.com/pingcap/tidb/pkg/planner/util"
 	"github.com/pingcap/tidb/pkg/planner/util/fixcontrol"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics/asyncload"
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/types"
 	driver "github.com/pingcap/tidb/pkg/types/parser_driver"
@@ -249,6 +253,33 @@ func hashInt64Uint64Map(b []byte, m map[int64]uint64) []byte {
 	return b
 }
 
+func hashStringUint64Map(b []byte, m map[string]uint64) []byte {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b = codec.EncodeCompactBytes(b, hack.Slice(k))
+		b = codec.EncodeUint(b, m[k])
+	}
+	return b
+}
+
+func hashStringSet(b []byte, m map[string]struct{}) []byte {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b = codec.EncodeCompactBytes(b, hack.Slice(k))
+	}
+	return b
+}
+
 // NewPlanCacheKey creates the plan cache key for this statement.
 // Note: lastUpdatedSchemaVersion will only be set in the case of rc or for update read in order to
 // differentiate the cache key. In other cases, it will be 0.
@@ -329,12 +360,16 @@ func NewPlanCacheKey(sctx sessionctx.Context, stmt *PlanCacheStmt) (key, binding
 		hash = append(hash, kv.TiFlash.Name()...)
 	}
 	hash = codec.EncodeInt(hash, int64(vars.SelectLimit))
+	// the pinned stats versions can affect the cached plan
+	hash = hashStringUint64Map(hash, vars.PinnedStats)
+	hash = hashStringSet(hash, vars.IgnoreStatsTables)
 	hash = append(hash, hack.Slice(binding)...)
 	hash = append(hash, hack.Slice(connCharset)...)
 	hash = append(hash, hack.Slice(connCollation)...)
 	hash = append(hash, hack.Slice(strconv.FormatBool(vars.InRestrictedSQL))...)
 	hash = append(hash, hack.Slice(strconv.FormatBool(variable.RestrictedReadOnly.Load()))...)
 	hash = append(hash, hack.Slice(strconv.FormatBool(variable.VarTiDBSuperReadOnly.Load()))...)
+	hash = append(hash, hack.Slice(strconv.FormatBool(variable.InstanceReadOnly.Load()))...)
 	// expr-pushdown-blacklist can affect query optimization, so we need to consider it in plan cache.
 	hash = codec.EncodeInt(hash, expression.ExprPushDownBlackListReloadTimeStamp.Load())
 
@@ -381,7 +416,7 @@ func NewPlanCacheKey(sctx sessionctx.Context, stmt *PlanCacheStmt) (key, binding
 	if sctx.GetSessionVars().PlanCacheInvalidationOnFreshStats {
 		var statsVerHash uint64
 		for _, t := range stmt.tables {
-			statsVerHash += getLatestVersionFromStatsTable(sctx, t.Meta(), t.Meta().ID) // use '+' as the hash function for simplicity
+			statsVerHash += getPlanCacheStatsVersion(sctx, t.Meta()) // use '+' as the hash function for simplicity
 		}
 		hash = codec.EncodeUint(hash, statsVerHash)
 	}
@@ -413,6 +448,64 @@ func NewPlanCacheKey(sctx sessionctx.Context, stmt *PlanCacheStmt) (key, binding
 	return string(hash), binding, true, "", nil
 }
 
+// planCacheStatsSnapshot is the stats of a table which the cached plans are built on.
+type planCacheStatsSnapshot struct {
+	version  uint64
+	rowCount int64
+}
+
+func (s planCacheStatsSnapshot) pseudo() bool {
+	return s.version == 0
+}
+
+// getPlanCacheStatsVersion returns the stats version of the table in the plan cache key, which is the version of the
+// baseline stats snapshot of the table. The baseline is only moved forward when the stats update invalidates the cached
+// plans under tidb_plan_cache_stats_invalidation_policy, so the cached plans are kept across the other stats updates.
+func getPlanCacheStatsVersion(sctx sessionctx.Context, tblInfo *tmodel.TableInfo) uint64 {
+	dom := domain.GetDomain(sctx)
+	cur := planCacheStatsSnapshot{version: getLatestVersionFromStatsTable(sctx, tblInfo, tblInfo.ID)}
+	if !cur.pseudo() {
+		cur.rowCount = dom.StatsHandle().GetTableStats(tblInfo).RealtimeCount
+	}
+	baselines := dom.PlanCacheStatsBaselines()
+	v, ok := baselines.Load(tblInfo.ID)
+	if !ok {
+		baselines.Store(tblInfo.ID, cur)
+		return cur.version
+	}
+	base := v.(planCacheStatsSnapshot)
+	if base.version == cur.version {
+		return base.version
+	}
+	invalidated, rowCountChanged := planCacheStatsInvalidated(base, cur)
+	if !invalidated {
+		return base.version
+	}
+	core_metrics.GetPlanCacheStatsInvalidationCounter(rowCountChanged, base.pseudo()).Inc()
+	baselines.Store(tblInfo.ID, cur)
+	return cur.version
+}
+
+// planCacheStatsInvalidated returns whether the stats update from the baseline invalidates the cached plans.
+func planCacheStatsInvalidated(base, cur planCacheStatsSnapshot) (invalidated, rowCountChanged bool) {
+	// The plans built on pseudo stats are always invalidated once the stats are available.
+	if base.pseudo() && !cur.pseudo() {
+		return true, false
+	}
+	switch variable.PlanCacheStatsInvalidationPolicy.Load() {
+	case variable.PlanCacheStatsInvalidationRowCountChange:
+		diff := math.Abs(float64(cur.rowCount - base.rowCount))
+		if diff > variable.PlanCacheStatsInvalidationRatio.Load()*float64(max(base.rowCount, 1)) {
+			return true, true
+		}
+		return false, false
+	case variable.PlanCacheStatsInvalidationPseudoOnly:
+		return false, false
+	default:
+		return true, false
+	}
+}
+
 func bool2Byte(flag bool) byte {
 	if flag {
 		return '1'
@@ -428,6 +521,31 @@ type PlanCacheValue struct {
 	testKey       int64              // test-only
 	paramTypes    []*types.FieldType // read-only, all parameters' types, different parameters may share same plan
 	stmtHints     *hint.StmtHints    // read-only, hints which set session variables
+
+	// statsItems is the columns/indices whose stats are needed to build the plan, which are kept loaded while the
+	// plan is used. See asyncload.PlanCacheNeededItems.
+	statsItems     []tmodel.StatsLoadItem // read-only
+	statsTouchedAt atomic2.Int64          // the unix nano of the last time statsItems are touched
+
+	// warmUp is the entry of the statement to warm up the instance plan cache after analyze, nil if the warm-up is
+	// disabled or the plan is in the session plan cache.
+	warmUp *planCacheWarmUpStmt
+}
+
+// planCacheStatsTouchInterval is the min interval to touch the stats items of a cached plan, which avoids the lock
+// contention on asyncload.PlanCacheNeededItems when the plan is hit frequently.
+const planCacheStatsTouchInterval = time.Minute
+
+// touchStatsItems records that the stats items are needed by the cached plan.
+func (v *PlanCacheValue) touchStatsItems(now time.Time) {
+	if len(v.statsItems) == 0 {
+		return
+	}
+	last := v.statsTouchedAt.Load()
+	if now.UnixNano()-last < int64(planCacheStatsTouchInterval) || !v.statsTouchedAt.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	asyncload.PlanCacheNeededItems.Touch(v.statsItems, now)
 }
 
 // unKnownMemoryUsage represent the memory usage of uncounted structure, maybe need implement later
@@ -456,8 +574,8 @@ func (v *PlanCacheValue) MemoryUsage() (sum int64) {
 		sum = unKnownMemoryUsage
 	}
 
-	sum += size.SizeOfInterface + size.SizeOfSlice*2 + int64(cap(v.OutputColumns))*size.SizeOfPointer +
-		size.SizeOfMap + size.SizeOfInt64*2
+	sum += size.SizeOfInterface + size.SizeOfSlice*3 + int64(cap(v.OutputColumns))*size.SizeOfPointer +
+		size.SizeOfMap + size.SizeOfInt64*3 + int64(cap(v.statsItems))*int64(unsafe.Sizeof(tmodel.StatsLoadItem{}))
 	if v.paramTypes != nil {
 		sum += int64(cap(v.paramTypes)) * size.SizeOfPointer
 		for _, ft := range v.paramTypes {
@@ -474,17 +592,20 @@ func (v *PlanCacheValue) MemoryUsage() (sum int64) {
 
 // NewPlanCacheValue creates a SQLCacheValue.
 func NewPlanCacheValue(plan base.Plan, names []*types.FieldName,
-	paramTypes []*types.FieldType, stmtHints *hint.StmtHints) *PlanCacheValue {
+	paramTypes []*types.FieldType, stmtHints *hint.StmtHints, statsItems []tmodel.StatsLoadItem) *PlanCacheValue {
 	userParamTypes := make([]*types.FieldType, len(paramTypes))
 	for i, tp := range paramTypes {
 		userParamTypes[i] = tp.Clone()
 	}
-	return &PlanCacheValue{
+	v := &PlanCacheValue{
 		Plan:          plan,
 		OutputColumns: names,
 		paramTypes:    userParamTypes,
 		stmtHints:     stmtHints.Clone(),
+		statsItems:    slices.Clone(statsItems),
 	}
+	v.touchStatsItems(time.Now())
+	return v
 }
 
 // planCacheStmtProcessor records all query features which may affect plan selection.
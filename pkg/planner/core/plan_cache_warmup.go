@@ -0,0 +1,247 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/auth"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/types"
+	tidbutil "github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/sqlexec"
+	atomic2 "go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// maxPlanCacheWarmUpStmts is the max number of the prepared statements kept for the warm-up.
+// The new statements are dropped when the limit is reached.
+const maxPlanCacheWarmUpStmts = 10000
+
+// planCacheWarmUpStmt is what is needed to re-optimize a prepared statement cached in the instance plan cache, i.e.
+// the statement text, the session states in the plan cache key and the parameters of the last execution.
+type planCacheWarmUpStmt struct {
+	stmtText   string
+	stmtDB     string
+	user       *auth.UserIdentity
+	sqlMode    mysql.SQLMode
+	timeZone   *time.Location
+	tableIDs   []int64
+	params     []types.Datum
+	paramTypes []*types.FieldType
+
+	// hits is the number of the executions which hit the cached plans of the statement.
+	hits atomic2.Int64
+}
+
+type planCacheWarmUpStmts struct {
+	stmts map[string]*planCacheWarmUpStmt
+	mu    sync.Mutex
+}
+
+// globalPlanCacheWarmUpStmts collects the prepared statements cached in the instance plan cache, which are
+// re-optimized after their tables are analyzed, see tidb_plan_cache_warm_up_after_analyze.
+var globalPlanCacheWarmUpStmts = &planCacheWarmUpStmts{stmts: make(map[string]*planCacheWarmUpStmt)}
+
+// record records the statement whose plan is just put into the instance plan cache and returns its entry. The
+// parameters of the entry are replaced by the latest ones.
+func (s *planCacheWarmUpStmts) record(sctx sessionctx.Context, stmt *PlanCacheStmt,
+	paramTypes []*types.FieldType) *planCacheWarmUpStmt {
+	vars := sctx.GetSessionVars()
+	var userName, hostName string
+	if vars.User != nil {
+		userName, hostName = vars.User.AuthUsername, vars.User.AuthHostname
+	}
+	stmtDB := stmt.StmtDB
+	if stmtDB == "" {
+		stmtDB = vars.CurrentDB
+	}
+	key := userName + "@" + hostName + "|" + stmtDB + "|" + stmt.StmtText
+	params := vars.PlanCacheParams.AllParamValues()
+	clonedParams := make([]types.Datum, len(params))
+	for i := range params {
+		params[i].Copy(&clonedParams[i])
+	}
+	clonedTypes := make([]*types.FieldType, len(paramTypes))
+	for i, tp := range paramTypes {
+		clonedTypes[i] = tp.Clone()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ws, ok := s.stmts[key]; ok {
+		ws.params, ws.paramTypes = clonedParams, clonedTypes
+		return ws
+	}
+	if len(s.stmts) >= maxPlanCacheWarmUpStmts {
+		return nil
+	}
+	tableIDs := make([]int64, 0, len(stmt.tables))
+	for _, tbl := range stmt.tables {
+		tableIDs = append(tableIDs, tbl.Meta().ID)
+	}
+	ws := &planCacheWarmUpStmt{
+		stmtText:   stmt.StmtText,
+		stmtDB:     stmtDB,
+		user:       vars.User,
+		sqlMode:    vars.SQLMode,
+		timeZone:   vars.TimeZone,
+		tableIDs:   tableIDs,
+		params:     clonedParams,
+		paramTypes: clonedTypes,
+	}
+	s.stmts[key] = ws
+	return ws
+}
+
+// topN returns the copies of at most n statements accessing any of the tables, which hit the cache most. The copies
+// are returned since the parameters of the entries are replaced concurrently.
+func (s *planCacheWarmUpStmts) topN(tableIDs []int64, n int) []*planCacheWarmUpStmt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var stmts []*planCacheWarmUpStmt
+	for _, ws := range s.stmts {
+		for _, id := range ws.tableIDs {
+			if slices.Contains(tableIDs, id) {
+				stmts = append(stmts, ws)
+				break
+			}
+		}
+	}
+	slices.SortFunc(stmts, func(a, b *planCacheWarmUpStmt) int {
+		return cmp.Compare(b.hits.Load(), a.hits.Load())
+	})
+	stmts = stmts[:min(len(stmts), n)]
+	for i, ws := range stmts {
+		stmts[i] = &planCacheWarmUpStmt{
+			stmtText:   ws.stmtText,
+			stmtDB:     ws.stmtDB,
+			user:       ws.user,
+			sqlMode:    ws.sqlMode,
+			timeZone:   ws.timeZone,
+			tableIDs:   ws.tableIDs,
+			params:     ws.params,
+			paramTypes: ws.paramTypes,
+		}
+		stmts[i].hits.Store(ws.hits.Load())
+	}
+	return stmts
+}
+
+// reset drops all the statements.
+func (s *planCacheWarmUpStmts) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stmts = make(map[string]*planCacheWarmUpStmt)
+}
+
+// WarmUpPlanCacheAfterAnalyze re-optimizes the prepared statements accessing the analyzed tables which hit the
+// instance plan cache most in the background, so that the plans built on the fresh stats are put into the cache
+// before the next executions of the statements rather than at their next cache misses.
+// The number of the statements is limited by tidb_plan_cache_warm_up_after_analyze, and it does nothing if the
+// variable is 0 or the instance plan cache is disabled. The plans are only replaced if the stats versions are in the
+// plan cache key, see tidb_plan_cache_invalidation_on_fresh_stats.
+func WarmUpPlanCacheAfterAnalyze(sctx sessionctx.Context, tableIDs []int64) {
+	n := variable.PlanCacheWarmUpAfterAnalyze.Load()
+	if n <= 0 || len(tableIDs) == 0 || !variable.EnableInstancePlanCache.Load() {
+		return
+	}
+	stmts := globalPlanCacheWarmUpStmts.topN(tableIDs, int(n))
+	if len(stmts) == 0 {
+		return
+	}
+	pool := domain.GetDomain(sctx).SysSessionPool()
+	go tidbutil.WithRecovery(func() {
+		res, err := pool.Get()
+		if err != nil {
+			logutil.BgLogger().Warn("failed to get the session to warm up the plan cache", zap.Error(err))
+			return
+		}
+		defer pool.Put(res)
+		warmUpSctx := res.(sessionctx.Context)
+		for _, ws := range stmts {
+			if err := warmUpPlanCacheStmt(warmUpSctx, ws); err != nil {
+				logutil.BgLogger().Warn("failed to warm up the plan cache",
+					zap.String("sql", ws.stmtText), zap.Error(err))
+			}
+		}
+		logutil.BgLogger().Info("the plan cache is warmed up after analyze",
+			zap.Int64s("tableIDs", tableIDs), zap.Int("statements", len(stmts)))
+	}, nil)
+}
+
+// warmUpPlanCacheStmt re-optimizes the statement by `EXPLAIN EXECUTE` in the session, which puts the new plan into
+// the instance plan cache as the real executions without running the statement. The session states in the plan
+// cache key are set to the ones of the statement during the warm-up.
+func warmUpPlanCacheStmt(sctx sessionctx.Context, ws *planCacheWarmUpStmt) (err error) {
+	vars := sctx.GetSessionVars()
+	origDB, origUser, origSQLMode, origTimeZone := vars.CurrentDB, vars.User, vars.SQLMode, vars.TimeZone
+	origEnablePlanCache := vars.EnablePreparedPlanCache
+	vars.CurrentDB, vars.User, vars.SQLMode, vars.TimeZone = ws.stmtDB, ws.user, ws.sqlMode, ws.timeZone
+	vars.EnablePreparedPlanCache = true
+	const stmtName = "__plan_cache_warm_up_stmt"
+	usingVars := make([]ast.ExprNode, 0, len(ws.params))
+	for i := range ws.params {
+		name := fmt.Sprintf("__plan_cache_warm_up_param_%d", i)
+		vars.SetUserVarVal(name, ws.params[i])
+		vars.SetUserVarType(name, ws.paramTypes[i])
+		usingVars = append(usingVars, &ast.VariableExpr{Name: name})
+	}
+	defer func() {
+		for i := range ws.params {
+			vars.UnsetUserVar(fmt.Sprintf("__plan_cache_warm_up_param_%d", i))
+		}
+		vars.CurrentDB, vars.User, vars.SQLMode, vars.TimeZone = origDB, origUser, origSQLMode, origTimeZone
+		vars.EnablePreparedPlanCache = origEnablePlanCache
+	}()
+
+	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats)
+	exec := sctx.(sqlexec.SQLExecutor)
+	if _, err := exec.ExecuteStmt(ctx, &ast.PrepareStmt{Name: stmtName, SQLText: ws.stmtText}); err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if _, dErr := exec.ExecuteStmt(ctx, &ast.DeallocateStmt{Name: stmtName}); dErr != nil && err == nil {
+			err = errors.Trace(dErr)
+		}
+	}()
+	rs, err := exec.ExecuteStmt(ctx, &ast.ExplainStmt{
+		Stmt:   &ast.ExecuteStmt{Name: stmtName, UsingVars: usingVars},
+		Format: types.ExplainFormatROW,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if rs == nil {
+		return nil
+	}
+	_, err = sqlexec.DrainRecordSet(ctx, rs, vars.MaxChunkSize)
+	if closeErr := rs.Close(); err == nil {
+		err = closeErr
+	}
+	return errors.Trace(err)
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanCacheWarmUpStmts(t *testing.T) {
+	sctx := MockContext()
+	defer func() {
+		domain.GetDomain(sctx).StatsHandle().Close()
+	}()
+	stmts := &planCacheWarmUpStmts{stmts: make(map[string]*planCacheWarmUpStmt)}
+	stmt := &PlanCacheStmt{StmtText: "select * from t where a > ?", StmtDB: "test"}
+	paramTypes := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}
+
+	// the parameters are replaced by the latest ones
+	vars := sctx.GetSessionVars()
+	vars.PlanCacheParams.Reset()
+	vars.PlanCacheParams.Append(types.NewIntDatum(1))
+	ws := stmts.record(sctx, stmt, paramTypes)
+	require.Equal(t, "test", ws.stmtDB)
+	require.Equal(t, int64(1), ws.params[0].GetInt64())
+	vars.PlanCacheParams.Reset()
+	vars.PlanCacheParams.Append(types.NewIntDatum(2))
+	require.Same(t, ws, stmts.record(sctx, stmt, paramTypes))
+	require.Equal(t, int64(2), ws.params[0].GetInt64())
+	require.Len(t, stmts.stmts, 1)
+
+	// the statements hitting the cache most are returned
+	stmts.reset()
+	for i, tableIDs := range [][]int64{{1}, {1, 2}, {3}} {
+		ws := &planCacheWarmUpStmt{stmtText: string(rune('a' + i)), tableIDs: tableIDs}
+		ws.hits.Store(int64(i))
+		stmts.stmts[ws.stmtText] = ws
+	}
+	top := stmts.topN([]int64{1}, 1)
+	require.Len(t, top, 1)
+	require.Equal(t, "b", top[0].stmtText)
+	require.NotSame(t, stmts.stmts["b"], top[0])
+	top = stmts.topN([]int64{1, 3}, 10)
+	require.Len(t, top, 3)
+	require.Equal(t, "c", top[0].stmtText)
+	require.Equal(t, "a", top[2].stmtText)
+	require.Empty(t, stmts.topN([]int64{4}, 10))
+}
@@ -220,7 +220,8 @@ func NonPreparedPlanCacheableWithCtx(sctx base.PlanContext, node ast.Node, is in
 		return false, "not a SELECT statement"
 	}
 
-	maxNumParam := getMaxParamLimit(sctx)
+	// the values of an in-list are also parameters, so the limit of parameters is at least the limit of the in-list.
+	maxNumParam := max(getMaxParamLimit(sctx), sctx.GetSessionVars().NonPreparedPlanCacheMaxInListLen)
 	var tableNames []*ast.TableName
 	switch x := node.(type) {
 	case *ast.SelectStmt:
@@ -235,7 +236,7 @@ func NonPreparedPlanCacheableWithCtx(sctx base.PlanContext, node ast.Node, is in
 		if x.MultipleTable {
 			return false, "not support multiple tables update statements"
 		}
-		tableNames, ok, reason = extractTableNames(x.TableRefs.TableRefs, tableNames)
+		tableNames, ok, reason = extractTableNames(sctx, x.TableRefs.TableRefs, tableNames)
 		if !ok {
 			return ok, reason
 		}
@@ -252,7 +253,7 @@ func NonPreparedPlanCacheableWithCtx(sctx base.PlanContext, node ast.Node, is in
 			if nRows*nCols > maxNumParam { // to save memory
 				return false, "too many values in the insert statement"
 			}
-			tableNames, ok, reason = extractTableNames(x.Table.TableRefs, tableNames)
+			tableNames, ok, reason = extractTableNames(sctx, x.Table.TableRefs, tableNames)
 			if !ok {
 				return ok, reason
 			}
@@ -265,7 +266,7 @@ func NonPreparedPlanCacheableWithCtx(sctx base.PlanContext, node ast.Node, is in
 			if !ok {
 				return ok, reason
 			}
-			tableNames, ok, reason = extractTableNames(x.Table.TableRefs, tableNames)
+			tableNames, ok, reason = extractTableNames(sctx, x.Table.TableRefs, tableNames)
 			if !ok {
 				return ok, reason
 			}
@@ -277,7 +278,7 @@ func NonPreparedPlanCacheableWithCtx(sctx base.PlanContext, node ast.Node, is in
 		if x.IsMultiTable {
 			return false, "not support multiple tables delete statements"
 		}
-		tableNames, ok, reason = extractTableNames(x.TableRefs.TableRefs, tableNames)
+		tableNames, ok, reason = extractTableNames(sctx, x.TableRefs.TableRefs, tableNames)
 		if !ok {
 			return ok, reason
 		}
@@ -304,6 +305,12 @@ func NonPreparedPlanCacheableWithCtx(sctx base.PlanContext, node ast.Node, is in
 
 // isSelectStmtNonPrepCacheableFastCheck checks whether the input select statement is cacheable for non-prepared plan cache.
 func isSelectStmtNonPrepCacheableFastCheck(sctx base.PlanContext, selectStmt *ast.SelectStmt) (names []*ast.TableName, ok bool, reason string) {
+	return extractSelectStmtTableNames(sctx, selectStmt, nil)
+}
+
+// extractSelectStmtTableNames checks whether the select statement, which may be a sub-query, is cacheable for
+// non-prepared plan cache, and appends its table names to names.
+func extractSelectStmtTableNames(sctx base.PlanContext, selectStmt *ast.SelectStmt, names []*ast.TableName) ([]*ast.TableName, bool, string) {
 	if selectStmt.Kind != ast.SelectStmtKindSelect {
 		return nil, false, "not a select statement"
 	}
@@ -320,27 +327,35 @@ func isSelectStmtNonPrepCacheableFastCheck(sctx base.PlanContext, selectStmt *as
 	}
 	tableRefs := from.TableRefs
 
-	// match table names, currently only support 2 tables(2-way join) at most.
-	tableNames, ok, reason := extractTableNames(tableRefs, nil)
+	// match table names, the number of tables is limited by tidb_non_prepared_plan_cache_max_join_tables.
+	tableNames, ok, reason := extractTableNames(sctx, tableRefs, names)
 	if !ok {
 		return nil, false, reason
 	}
 	return tableNames, true, ""
 }
 
-// extractTableNames extracts table names from the input node.
-// Currently support 2 tables(2-way join) at most.
-func extractTableNames(node ast.ResultSetNode, names []*ast.TableName) ([]*ast.TableName, bool, string) {
+// extractTableNames extracts table names from the input node, including the ones in the derived tables.
+// The number of tables is limited by tidb_non_prepared_plan_cache_max_join_tables.
+func extractTableNames(sctx base.PlanContext, node ast.ResultSetNode, names []*ast.TableName) ([]*ast.TableName, bool, string) {
 	var ok bool
 	var reason string
 	switch x := node.(type) {
 	case *ast.TableSource:
-		name, isName := x.Source.(*ast.TableName)
-		if isName {
-			names = append(names, name)
-		} else {
+		switch source := x.Source.(type) {
+		case *ast.TableName:
+			names = append(names, source)
+		case *ast.SelectStmt: // derived table, e.g. `select * from (select * from t where a < 10) t1`
+			if !sctx.GetSessionVars().EnableNonPreparedPlanCacheForSubquery {
+				return names, false, "queries that have sub-queries are not supported"
+			}
+			names, ok, reason = extractSelectStmtTableNames(sctx, source, names)
+			if !ok {
+				return nil, ok, reason
+			}
+		default:
 			if x.Source != nil {
-				names, ok, reason = extractTableNames(x.Source, names)
+				names, ok, reason = extractTableNames(sctx, x.Source, names)
 				if !ok {
 					return nil, ok, reason
 				}
@@ -348,13 +363,13 @@ func extractTableNames(node ast.ResultSetNode, names []*ast.TableName) ([]*ast.T
 		}
 	case *ast.Join:
 		if x.Left != nil {
-			names, ok, reason = extractTableNames(x.Left, names)
+			names, ok, reason = extractTableNames(sctx, x.Left, names)
 			if !ok {
 				return nil, ok, reason
 			}
 		}
 		if x.Right != nil {
-			names, ok, reason = extractTableNames(x.Right, names)
+			names, ok, reason = extractTableNames(sctx, x.Right, names)
 			if !ok {
 				return nil, ok, reason
 			}
@@ -362,8 +377,8 @@ func extractTableNames(node ast.ResultSetNode, names []*ast.TableName) ([]*ast.T
 	default:
 		return names, false, "queries that have sub-queries are not supported"
 	}
-	if len(names) > 2 {
-		return names, false, "queries that have more than 2 tables are not supported"
+	if maxTables := sctx.GetSessionVars().NonPreparedPlanCacheMaxJoinTables; len(names) > maxTables {
+		return names, false, fmt.Sprintf("queries that have more than %d tables are not supported", maxTables)
 	}
 	return names, true, ""
 }
@@ -376,7 +391,7 @@ type nonPreparedPlanCacheableChecker struct {
 	reason    string // reason why this statement cannot hit the cache
 	schema    infoschema.InfoSchema
 
-	tableNodes []*ast.TableName // only support 2-way joins currently
+	tableNodes []*ast.TableName // the tables in the query, including the ones in the sub-queries
 
 	constCnt  int // the number of constants/parameters in this query
 	filterCnt int // the number of filters in the current node
@@ -404,8 +419,35 @@ func (checker *nonPreparedPlanCacheableChecker) Enter(in ast.Node) (out ast.Node
 	switch node := in.(type) {
 	case *ast.SelectStmt, *ast.FieldList, *ast.SelectField, *ast.TableRefsClause, *ast.Join, *ast.BetweenExpr, *ast.OnCondition,
 		*ast.InsertStmt, *ast.DeleteStmt, *ast.UpdateStmt, *ast.Assignment, *ast.ParenthesesExpr, *ast.RowExpr,
-		*ast.TableSource, *ast.ColumnNameExpr, *ast.PatternInExpr, *ast.BinaryOperationExpr, *ast.ByItem, *ast.AggregateFuncExpr:
+		*ast.TableSource, *ast.ColumnNameExpr, *ast.BinaryOperationExpr, *ast.ByItem, *ast.AggregateFuncExpr,
+		*ast.ExistsSubqueryExpr, *ast.CompareSubqueryExpr:
 		return in, !checker.cacheable // skip child if un-cacheable
+	case *ast.PatternInExpr:
+		if len(node.List) > checker.sctx.GetSessionVars().NonPreparedPlanCacheMaxInListLen {
+			checker.cacheable = false
+			checker.reason = "query has too many values in in-list"
+		}
+		return in, !checker.cacheable
+	case *ast.SubqueryExpr:
+		// the derived tables are checked when extracting the table names, so only the sub-queries in expressions, e.g.
+		// `a in (select ...)`, `exists (select ...)` and `a > (select ...)`, are checked here.
+		if !checker.sctx.GetSessionVars().EnableNonPreparedPlanCacheForSubquery {
+			break
+		}
+		sel, isSelect := node.Query.(*ast.SelectStmt)
+		if !isSelect {
+			checker.cacheable = false
+			checker.reason = "queries that have set operators in sub-queries are not supported"
+			return in, !checker.cacheable
+		}
+		tableNodes, ok, reason := extractSelectStmtTableNames(checker.sctx, sel, checker.tableNodes)
+		if !ok {
+			checker.cacheable = false
+			checker.reason = reason
+			return in, !checker.cacheable
+		}
+		checker.tableNodes = tableNodes
+		return in, !checker.cacheable
 	case *ast.Limit:
 		if !checker.sctx.GetSessionVars().EnablePlanCacheForParamLimit {
 			checker.cacheable = false
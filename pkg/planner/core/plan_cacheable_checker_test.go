@@ -456,6 +456,43 @@ func TestNonPreparedPlanCacheable(t *testing.T) {
 		ok, _ := core.NonPreparedPlanCacheableWithCtx(sctx.GetPlanCtx(), stmt, is)
 		require.True(t, ok)
 	}
+
+	// simple sub-queries, multi-table joins and long in-lists
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache_for_subquery = 1")
+	tk.MustExec("set @@tidb_non_prepared_plan_cache_max_join_tables = 3")
+	tk.MustExec("set @@tidb_non_prepared_plan_cache_max_in_list_len = 3")
+	supported = []string{
+		"select * from (select * from test.t) t",
+		"select * from (select * from test.t1 where a < 10) t",
+		"select * from test.t where a in (select a from t)",
+		"select * from test.t1 where a in (select a from t where b < 10)",
+		"select * from test.t where a in (select a from test.t where a > t1.a)",
+		"select * from test.t where exists (select 1 from test.t3 where t3.a = t.a and t3.b > 1)",
+		"select * from test.t where a > (select max(a) from test.t3)",
+		"select * from test.t, test.t3, test.t2 where test.t.a=test.t3.a and test.t3.b=test.t2.b",
+		"select * from test.t, test.t3 where test.t.a in (select a from test.t2)",
+		"select * from test.t where a in (1, 2, 3)",
+	}
+	unsupported = []string{
+		"select * from test.t, test.t1, test.t2, test.t3",                                       // too many tables
+		"select * from test.t, test.t3 where test.t.a in (select a from test.t1, test.t2)",      // too many tables
+		"select * from test.t where a in (select a from test.t union select a from test.t3)",    // set operators
+		"select * from test.t where a in (select /*+ use_index(t, idx_b) */ a from test.t)",     // hint
+		"select * from test.t where a in (select a from test.t group by a having count(*) > 1)", // having
+		"select * from test.t where a in (1, 2, 3, 4)",                                          // too long in-list
+	}
+	for i, q := range unsupported {
+		stmt, err := p.ParseOneStmt(q, charset, collation)
+		require.NoError(t, err)
+		ok, _ := core.NonPreparedPlanCacheableWithCtx(sctx.GetPlanCtx(), stmt, is)
+		require.False(t, ok, "unsupported index: %d: %s", i, q)
+	}
+	for i, q := range supported {
+		stmt, err := p.ParseOneStmt(q, charset, collation)
+		require.NoError(t, err)
+		ok, reason := core.NonPreparedPlanCacheableWithCtx(sctx.GetPlanCtx(), stmt, is)
+		require.True(t, ok, "supported index: %d: %s, reason: %s", i, q, reason)
+	}
 }
 
 func BenchmarkNonPreparedPlanCacheableChecker(b *testing.B) {
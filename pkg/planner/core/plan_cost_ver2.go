@@ -15,8 +15,10 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
+	"sync/atomic"
 
 	"github.com/pingcap/tidb/pkg/expression"
 	"github.com/pingcap/tidb/pkg/expression/aggregation"
@@ -193,15 +195,15 @@ func (p *PhysicalTableScan) GetPlanCostVer2(taskType property.TaskType, option *
 
 	if p.AnnIndexExtra != nil {
 		if p.AnnIndexExtra.PushDownQueryInfo == nil {
-			p.PlanCostVer2 = costusage.NewCostVer2(option, defaultVer2Factors.ANNIndexNoTopK, defaultVer2Factors.ANNIndexNoTopK.Value, func() string {
-				return fmt.Sprintf("ann-index-no-topk(%v)", defaultVer2Factors.ANNIndexNoTopK)
+			p.PlanCostVer2 = costusage.NewCostVer2(option, getVer2Factors().ANNIndexNoTopK, getVer2Factors().ANNIndexNoTopK.Value, func() string {
+				return fmt.Sprintf("ann-index-no-topk(%v)", getVer2Factors().ANNIndexNoTopK)
 			})
 		} else {
-			p.PlanCostVer2 = costusage.SumCostVer2(p.PlanCostVer2, costusage.NewCostVer2(option, defaultVer2Factors.ANNIndexStart, rows*defaultVer2Factors.ANNIndexStart.Value, func() string {
-				return fmt.Sprintf("ann-index-start(%v*%v)", rows, defaultVer2Factors.ANNIndexStart)
+			p.PlanCostVer2 = costusage.SumCostVer2(p.PlanCostVer2, costusage.NewCostVer2(option, getVer2Factors().ANNIndexStart, rows*getVer2Factors().ANNIndexStart.Value, func() string {
+				return fmt.Sprintf("ann-index-start(%v*%v)", rows, getVer2Factors().ANNIndexStart)
 			}))
-			p.PlanCostVer2 = costusage.SumCostVer2(p.PlanCostVer2, costusage.NewCostVer2(option, defaultVer2Factors.ANNIndexScanRow, float64(p.AnnIndexExtra.PushDownQueryInfo.TopK)*defaultVer2Factors.ANNIndexScanRow.Value, func() string {
-				return fmt.Sprintf("ann-index-topk(%v*%v)", p.AnnIndexExtra.PushDownQueryInfo.TopK, defaultVer2Factors.ANNIndexScanRow)
+			p.PlanCostVer2 = costusage.SumCostVer2(p.PlanCostVer2, costusage.NewCostVer2(option, getVer2Factors().ANNIndexScanRow, float64(p.AnnIndexExtra.PushDownQueryInfo.TopK)*getVer2Factors().ANNIndexScanRow.Value, func() string {
+				return fmt.Sprintf("ann-index-topk(%v*%v)", p.AnnIndexExtra.PushDownQueryInfo.TopK, getVer2Factors().ANNIndexScanRow)
 			}))
 		}
 	}
@@ -277,7 +279,7 @@ func (p *PhysicalTableReader) GetPlanCostVer2(taskType property.TaskType, option
 // double-read-cost = double-read-request-cost + double-read-cpu-cost
 // double-read-request-cost = double-read-tasks * request-factor
 // double-read-cpu-cost = index-rows * cpu-factor
-// double-read-tasks = index-rows / batch-size * task-per-batch # task-per-batch is a magic number now
+// double-read-tasks = index-rows / batch-size * task-per-batch # task-per-batch depends on the index-handle correlation
 func (p *PhysicalIndexLookUpReader) GetPlanCostVer2(taskType property.TaskType, option *optimizetrace.PlanCostOption) (costusage.CostVer2, error) {
 	if p.PlanCostInit && !hasCostFlag(option.CostFlag, costusage.CostFlagRecalculate) {
 		return p.PlanCostVer2, nil
@@ -314,7 +316,7 @@ func (p *PhysicalIndexLookUpReader) GetPlanCostVer2(taskType property.TaskType,
 		indexRows*cpuFactor.Value,
 		func() string { return fmt.Sprintf("double-read-cpu(%v*%v)", doubleReadRows, cpuFactor) })
 	batchSize := float64(p.SCtx().GetSessionVars().IndexLookupSize)
-	taskPerBatch := 32.0 // TODO: remove this magic number
+	taskPerBatch := p.doubleReadTaskPerBatch()
 	doubleReadTasks := doubleReadRows / batchSize * taskPerBatch
 	doubleReadRequestCost := doubleReadCostVer2(option, doubleReadTasks, requestFactor)
 	doubleReadCost := costusage.SumCostVer2(doubleReadCPUCost, doubleReadRequestCost)
@@ -331,6 +333,25 @@ func (p *PhysicalIndexLookUpReader) GetPlanCostVer2(taskType property.TaskType,
 	return p.PlanCostVer2, nil
 }
 
+// doubleReadTaskPerBatch returns the number of the table-side tasks of a batch of handles, i.e. the number of the regions
+// the handles fall in. The handles of an index correlated with the handle order are clustered in a few regions, while
+// the ones of an uncorrelated index are scattered across the table, which is the worst case.
+func (p *PhysicalIndexLookUpReader) doubleReadTaskPerBatch() float64 {
+	const maxTaskPerBatch = 32.0 // TODO: remove this magic number
+	if !p.SCtx().GetSessionVars().EnableCorrelationAdjustment || len(p.IndexPlans) == 0 {
+		return maxTaskPerBatch
+	}
+	is, ok := p.IndexPlans[0].(*PhysicalIndexScan)
+	if !ok || is.Index == nil || is.tblColHists == nil || is.tblColHists.Pseudo {
+		return maxTaskPerBatch
+	}
+	idx := is.tblColHists.GetIdx(is.Index.ID)
+	if idx == nil || !idx.IsAnalyzed() {
+		return maxTaskPerBatch
+	}
+	return 1 + (maxTaskPerBatch-1)*(1-min(math.Abs(idx.Correlation), 1))
+}
+
 // GetPlanCostVer2 returns the plan-cost of this sub-plan, which is:
 // plan-cost = table-side-cost + sum(index-side-cost)
 // index-side-cost = (index-child-cost + index-net-cost) / dist-concurrency # same with IndexReader
@@ -408,7 +429,7 @@ func (p *PhysicalSort) GetPlanCostVer2(taskType property.TaskType, option *optim
 	rowSize := max(MinRowSize, getAvgRowSize(p.StatsInfo(), p.Schema().Columns))
 	cpuFactor := getTaskCPUFactorVer2(p, taskType)
 	memFactor := getTaskMemFactorVer2(p, taskType)
-	diskFactor := defaultVer2Factors.TiDBDisk
+	diskFactor := getVer2Factors().TiDBDisk
 	oomUseTmpStorage := variable.EnableTmpStorageOnOOM.Load()
 	memQuota := p.SCtx().GetSessionVars().MemTracker.GetBytesLimit()
 	spill := taskType == property.RootTaskType && // only TiDB can spill
@@ -983,38 +1004,90 @@ var defaultVer2Factors = costVer2Factors{
 	ANNIndexNoTopK:  costusage.CostVer2Factor{Name: "ann_index_no_topk_factor", Value: math.MaxUint64},
 }
 
+// calibratedVer2Factors caches the factors overridden by tidb_cost_model_v2_calibrated_factors, so the JSON value is
+// only parsed once it's changed.
+var calibratedVer2Factors atomic.Pointer[calibratedFactors]
+
+type calibratedFactors struct {
+	source  string
+	factors costVer2Factors
+}
+
+// getVer2Factors returns the factors of cost model v2, which are the default ones overridden by the ones calibrated
+// by ADMIN CALIBRATE COST MODEL.
+func getVer2Factors() *costVer2Factors {
+	source := variable.CostModelV2CalibratedFactors.Load()
+	if source == "" {
+		return &defaultVer2Factors
+	}
+	if cached := calibratedVer2Factors.Load(); cached != nil && cached.source == source {
+		return &cached.factors
+	}
+	values := make(map[string]float64)
+	// The value has been validated when it's set.
+	if err := json.Unmarshal([]byte(source), &values); err != nil {
+		return &defaultVer2Factors
+	}
+	cached := &calibratedFactors{source: source, factors: defaultVer2Factors}
+	for _, factor := range cached.factors.calibratable() {
+		if v, ok := values[factor.Name]; ok {
+			factor.Value = v
+		}
+	}
+	calibratedVer2Factors.Store(cached)
+	return &cached.factors
+}
+
+// calibratable returns the factors which can be calibrated by ADMIN CALIBRATE COST MODEL, i.e. the ones in tolist.
+func (c *costVer2Factors) calibratable() []*costusage.CostVer2Factor {
+	return []*costusage.CostVer2Factor{&c.TiDBTemp, &c.TiKVScan, &c.TiKVDescScan, &c.TiFlashScan, &c.TiDBCPU, &c.TiKVCPU,
+		&c.TiFlashCPU, &c.TiDB2KVNet, &c.TiDB2FlashNet, &c.TiFlashMPPNet, &c.TiDBMem, &c.TiKVMem, &c.TiFlashMem, &c.TiDBDisk,
+		&c.TiDBRequest}
+}
+
+// DefaultCostModelV2Factor returns the default value of the factor of cost model v2 by its name, which is the base of
+// the calibration.
+func DefaultCostModelV2Factor(name string) (float64, bool) {
+	for _, factor := range defaultVer2Factors.tolist() {
+		if factor.Name == name {
+			return factor.Value, true
+		}
+	}
+	return 0, false
+}
+
 func getTaskCPUFactorVer2(_ base.PhysicalPlan, taskType property.TaskType) costusage.CostVer2Factor {
 	switch taskType {
 	case property.RootTaskType: // TiDB
-		return defaultVer2Factors.TiDBCPU
+		return getVer2Factors().TiDBCPU
 	case property.MppTaskType: // TiFlash
-		return defaultVer2Factors.TiFlashCPU
+		return getVer2Factors().TiFlashCPU
 	default: // TiKV
-		return defaultVer2Factors.TiKVCPU
+		return getVer2Factors().TiKVCPU
 	}
 }
 
 func getTaskMemFactorVer2(_ base.PhysicalPlan, taskType property.TaskType) costusage.CostVer2Factor {
 	switch taskType {
 	case property.RootTaskType: // TiDB
-		return defaultVer2Factors.TiDBMem
+		return getVer2Factors().TiDBMem
 	case property.MppTaskType: // TiFlash
-		return defaultVer2Factors.TiFlashMem
+		return getVer2Factors().TiFlashMem
 	default: // TiKV
-		return defaultVer2Factors.TiKVMem
+		return getVer2Factors().TiKVMem
 	}
 }
 
 func getTaskScanFactorVer2(p base.PhysicalPlan, storeType kv.StoreType, taskType property.TaskType) costusage.CostVer2Factor {
 	if isTemporaryTable(getTableInfo(p)) {
-		return defaultVer2Factors.TiDBTemp
+		return getVer2Factors().TiDBTemp
 	}
 	if storeType == kv.TiFlash {
-		return defaultVer2Factors.TiFlashScan
+		return getVer2Factors().TiFlashScan
 	}
 	switch taskType {
 	case property.MppTaskType: // TiFlash
-		return defaultVer2Factors.TiFlashScan
+		return getVer2Factors().TiFlashScan
 	default: // TiKV
 		var desc bool
 		if indexScan, ok := p.(*PhysicalIndexScan); ok {
@@ -1024,32 +1097,32 @@ func getTaskScanFactorVer2(p base.PhysicalPlan, storeType kv.StoreType, taskType
 			desc = tableScan.Desc
 		}
 		if desc {
-			return defaultVer2Factors.TiKVDescScan
+			return getVer2Factors().TiKVDescScan
 		}
-		return defaultVer2Factors.TiKVScan
+		return getVer2Factors().TiKVScan
 	}
 }
 
 func getTaskNetFactorVer2(p base.PhysicalPlan, _ property.TaskType) costusage.CostVer2Factor {
 	if isTemporaryTable(getTableInfo(p)) {
-		return defaultVer2Factors.TiDBTemp
+		return getVer2Factors().TiDBTemp
 	}
 	if _, ok := p.(*PhysicalExchangeReceiver); ok { // TiFlash MPP
-		return defaultVer2Factors.TiFlashMPPNet
+		return getVer2Factors().TiFlashMPPNet
 	}
 	if tblReader, ok := p.(*PhysicalTableReader); ok {
 		if _, isMPP := tblReader.tablePlan.(*PhysicalExchangeSender); isMPP { // TiDB to TiFlash with mpp protocol
-			return defaultVer2Factors.TiDB2FlashNet
+			return getVer2Factors().TiDB2FlashNet
 		}
 	}
-	return defaultVer2Factors.TiDB2KVNet
+	return getVer2Factors().TiDB2KVNet
 }
 
 func getTaskRequestFactorVer2(p base.PhysicalPlan, _ property.TaskType) costusage.CostVer2Factor {
 	if isTemporaryTable(getTableInfo(p)) {
-		return defaultVer2Factors.TiDBTemp
+		return getVer2Factors().TiDBTemp
 	}
-	return defaultVer2Factors.TiDBRequest
+	return getVer2Factors().TiDBRequest
 }
 
 func isTemporaryTable(tbl *model.TableInfo) bool {
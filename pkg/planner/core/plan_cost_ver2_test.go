@@ -78,6 +78,27 @@ func TestCostModelVer2ScanRowSize(t *testing.T) {
 		`└─IndexRangeScan_5 10.00 cop[tikv] table:t, index:abc(a, b, c) range:[1,1], keep order:false, stats:pseudo`))
 }
 
+func TestCostModelVer2CalibratedFactors(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (pk int, a int, b int, primary key(pk), index ab(a, b))")
+	tk.MustExec("insert into t values (1, 1, 1)")
+	tk.MustExec("set @@tidb_cost_model_version=2")
+	defer tk.MustExec("set global tidb_cost_model_v2_calibrated_factors = ''")
+
+	query := "explain analyze format=true_card_cost select a, b from t use index(ab) where a=1"
+	checkScanFormula := func(formula string) {
+		rs := tk.MustQuery(query).Rows()
+		require.Equal(t, formula, rs[len(rs)-1][3])
+	}
+	checkScanFormula("scan(1*logrowsize(32)*tikv_scan_factor(40.7))")
+	tk.MustExec(`set global tidb_cost_model_v2_calibrated_factors = '{"tikv_scan_factor": 20, "tidb_cpu_factor": 30}'`)
+	checkScanFormula("scan(1*logrowsize(32)*tikv_scan_factor(20))")
+	tk.MustExec("set global tidb_cost_model_v2_calibrated_factors = ''")
+	checkScanFormula("scan(1*logrowsize(32)*tikv_scan_factor(40.7))")
+}
+
 func TestCostModelTraceVer2(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/br/pkg/storage"
@@ -524,7 +525,11 @@ func (b *PlanBuilder) Build(ctx context.Context, node *resolve.NodeW) (base.Plan
 	case *ast.LoadDataStmt:
 		return b.buildLoadData(ctx, x)
 	case *ast.LoadStatsStmt:
-		return b.buildLoadStats(x), nil
+		return b.buildLoadStats(x)
+	case *ast.ModifyStatsStmt:
+		return b.buildModifyStats(x), nil
+	case *ast.AutoAnalyzeOptionsStmt:
+		return b.buildAutoAnalyzeOptions(x), nil
 	case *ast.LockStatsStmt:
 		return b.buildLockStats(x), nil
 	case *ast.UnlockStatsStmt:
@@ -552,11 +557,13 @@ func (b *PlanBuilder) Build(ctx context.Context, node *resolve.NodeW) (base.Plan
 		return b.buildSetConfig(ctx, x)
 	case *ast.AnalyzeTableStmt:
 		return b.buildAnalyze(x)
+	case *ast.AnalyzeDatabaseStmt:
+		return b.buildAnalyzeDatabase(x)
 	case *ast.BinlogStmt, *ast.FlushStmt, *ast.UseStmt, *ast.BRIEStmt,
 		*ast.BeginStmt, *ast.CommitStmt, *ast.SavepointStmt, *ast.ReleaseSavepointStmt, *ast.RollbackStmt, *ast.CreateUserStmt, *ast.SetPwdStmt, *ast.AlterInstanceStmt,
 		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.AlterRangeStmt, *ast.RevokeStmt, *ast.KillStmt, *ast.DropStatsStmt,
 		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
-		*ast.RenameUserStmt, *ast.NonTransactionalDMLStmt, *ast.SetSessionStatesStmt, *ast.SetResourceGroupStmt,
+		*ast.RenameUserStmt, *ast.NonTransactionalDMLStmt, *ast.SetSessionStatesStmt, *ast.ResetSessionStatesStmt, *ast.SetResourceGroupStmt,
 		*ast.ImportIntoActionStmt, *ast.CalibrateResourceStmt, *ast.AddQueryWatchStmt, *ast.DropQueryWatchStmt:
 		return b.buildSimple(ctx, node.Node.(ast.StmtNode))
 	case ast.DDLNode:
@@ -1490,8 +1497,12 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (base.P
 		for _, tn := range as.Tables {
 			tnWs = append(tnWs, b.resolveCtx.GetTableName(tn))
 		}
-		p := &ChecksumTable{Tables: tnWs}
-		p.setSchemaAndNames(buildChecksumTableSchema())
+		p := &ChecksumTable{Tables: tnWs, CompareWith: as.CompareWith}
+		if as.CompareWith != "" {
+			p.setSchemaAndNames(buildChecksumTableCompareSchema())
+		} else {
+			p.setSchemaAndNames(buildChecksumTableSchema())
+		}
 		ret = p
 	case ast.AdminShowNextRowID:
 		p := &ShowNextRowID{TableName: as.Tables[0]}
@@ -1560,24 +1571,46 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (base.P
 	case ast.AdminCaptureBindings:
 		return &SQLBindPlan{SQLBindOp: OpCaptureBindings}, nil
 	case ast.AdminEvolveBindings:
-		var err error
-		// The 'baseline evolution' only work in the test environment before the feature is GA.
-		if !config.CheckTableBeforeDrop {
-			err = errors.Errorf("Cannot enable baseline evolution feature, it is not generally available now")
-		}
-		return &SQLBindPlan{SQLBindOp: OpEvolveBindings}, err
+		return &SQLBindPlan{SQLBindOp: OpEvolveBindings}, nil
 	case ast.AdminReloadBindings:
 		return &SQLBindPlan{SQLBindOp: OpReloadBindings}, nil
 	case ast.AdminReloadStatistics:
 		return &Simple{Statement: as, ResolveCtx: b.resolveCtx}, nil
 	case ast.AdminFlushPlanCache:
 		return &Simple{Statement: as, ResolveCtx: b.resolveCtx}, nil
-	case ast.AdminSetBDRRole, ast.AdminUnsetBDRRole:
+	case ast.AdminSetBDRRole, ast.AdminUnsetBDRRole, ast.AdminRemoveAnalyzeJob, ast.AdminExportStats, ast.AdminResetColumnStatsUsage:
 		ret = &Simple{Statement: as, ResolveCtx: b.resolveCtx}
 	case ast.AdminShowBDRRole:
 		p := &AdminShowBDRRole{}
 		p.setSchemaAndNames(buildAdminShowBDRRoleFields())
 		ret = p
+	case ast.AdminBenchStatsLoad:
+		tnW := b.resolveCtx.GetTableName(as.Tables[0])
+		p := &AdminBenchStatsLoad{Table: tnW}
+		p.setSchemaAndNames(buildAdminBenchStatsLoadFields())
+		ret = p
+	case ast.AdminShowColumnStatsUsage:
+		p := &AdminShowColumnStatsUsage{Table: b.resolveCtx.GetTableName(as.Tables[0])}
+		p.setSchemaAndNames(buildAdminShowColumnStatsUsageFields())
+		ret = p
+	case ast.AdminValidateVectorIndex:
+		ret, err = b.buildAdminValidateVectorIndex(as)
+		if err != nil {
+			return nil, err
+		}
+	case ast.AdminCheckStats:
+		ret, err = b.buildAdminCheckStats(as)
+		if err != nil {
+			return nil, err
+		}
+	case ast.AdminShowPlanDiff:
+		p := &AdminShowPlanDiff{Limit: as.LimitSimple.Count}
+		p.setSchemaAndNames(buildAdminShowPlanDiffFields())
+		ret = p
+	case ast.AdminCalibrateCostModel:
+		p := &AdminCalibrateCostModel{}
+		p.setSchemaAndNames(buildAdminCalibrateCostModelFields())
+		ret = p
 	case ast.AdminAlterDDLJob:
 		ret, err = b.buildAdminAlterDDLJob(ctx, as)
 		if err != nil {
@@ -1910,6 +1943,38 @@ func (b *PlanBuilder) buildCheckIndexSchema(tn *ast.TableName, indexName string)
 	return schema, names, nil
 }
 
+func (b *PlanBuilder) buildAdminValidateVectorIndex(as *ast.AdminStmt) (base.Plan, error) {
+	tnW := b.resolveCtx.GetTableName(as.Tables[0])
+	indexName := strings.ToLower(as.Index)
+	idxInfo := tnW.TableInfo.FindIndexByName(indexName)
+	if idxInfo == nil {
+		return nil, errors.Errorf("index %s not found", indexName)
+	}
+	if idxInfo.VectorInfo == nil {
+		return nil, errors.Errorf("index %s is not a vector index", indexName)
+	}
+	p := &AdminValidateVectorIndex{Table: tnW, IndexInfo: idxInfo, SampleCount: as.SampleCount}
+	p.setSchemaAndNames(buildAdminValidateVectorIndexFields())
+	return p, nil
+}
+
+func (b *PlanBuilder) buildAdminCheckStats(as *ast.AdminStmt) (base.Plan, error) {
+	p := &AdminCheckStats{Table: b.resolveCtx.GetTableName(as.Tables[0]), SampleCount: as.SampleCount}
+	if as.CheckStatsThreshold != nil {
+		d := types.NewDatum(as.CheckStatsThreshold.GetValue())
+		threshold, err := d.ToFloat64(b.ctx.GetSessionVars().StmtCtx.TypeCtx())
+		if err != nil {
+			return nil, err
+		}
+		if threshold <= 0 {
+			return nil, errors.Errorf("THRESHOLD of ADMIN CHECK STATS should be positive")
+		}
+		p.Threshold = threshold
+	}
+	p.setSchemaAndNames(buildAdminCheckStatsFields())
+	return p, nil
+}
+
 // getColsInfo returns the info of index columns, normal columns and primary key.
 func (b *PlanBuilder) getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []*model.ColumnInfo) {
 	tnW := b.resolveCtx.GetTableName(tn)
@@ -2149,6 +2214,10 @@ func (b *PlanBuilder) getFullAnalyzeColumnsInfo(
 	switch columnChoice {
 	case pmodel.DefaultChoice:
 		columnOptions := variable.AnalyzeColumnOptions.Load()
+		// tidb_analyze_default_column_choice of the session takes precedence over the global default.
+		if choice := b.ctx.GetSessionVars().AnalyzeDefaults.ColumnChoice; choice != "" {
+			columnOptions = choice
+		}
 		switch columnOptions {
 		case pmodel.AllColumns.String():
 			return tbl.TableInfo.Columns, nil, nil
@@ -2360,7 +2429,8 @@ func (b *PlanBuilder) filterSkipColumnTypes(origin []*model.ColumnInfo, tbl *res
 	}
 	// If one column's type is in the skipTypes list and it doesn't exist in mustAnalyzedCols, we will skip it.
 	for _, colInfo := range origin {
-		// Vector type is skip by hardcoded. Just because that collecting it is meanless for current TiDB.
+		// Vector type is skip by hardcoded, since its histograms are meaningless. The stats of the vector columns are
+		// collected as the extended stats instead.
 		if colInfo.FieldType.GetType() == mysql.TypeTiDBVectorFloat32 {
 			continue
 		}
@@ -2423,12 +2493,6 @@ func (b *PlanBuilder) buildAnalyzeFullSamplingTask(
 	version int,
 	persistOpts bool,
 ) error {
-	// Version 2 doesn't support incremental analyze.
-	// And incremental analyze will be deprecated in the future.
-	if as.Incremental {
-		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.NewNoStackError("The version 2 stats would ignore the INCREMENTAL keyword and do full sampling"))
-	}
-
 	isAnalyzeTable := len(as.PartitionNames) == 0
 
 	allSpecialGlobalIndex, err := checkIsAllSpecialGlobalIndex(as, tbl)
@@ -2461,6 +2525,18 @@ func (b *PlanBuilder) buildAnalyzeFullSamplingTask(
 	if err != nil {
 		return err
 	}
+	if b.ctx.GetSessionVars().InRestrictedSQL && variable.EnableWorkloadAwareAnalyze.Load() {
+		if optionsMap == nil {
+			optionsMap = make(map[int64]V2AnalyzeOptions, len(physicalIDs))
+		}
+		if colsInfoMap == nil {
+			colsInfoMap = make(map[int64][]*model.ColumnInfo, len(physicalIDs))
+		}
+		err = b.applyWorkloadClassAnalyzeOptions(tbl, physicalIDs, astOpts, as.ColumnChoice, astColList, optionsMap, colsInfoMap, &predicateCols, &mustAnalyzedCols, mustAllColumns)
+		if err != nil {
+			return err
+		}
+	}
 	for physicalID, opts := range optionsMap {
 		analyzePlan.OptionsMap[physicalID] = opts
 	}
@@ -2485,6 +2561,8 @@ func (b *PlanBuilder) buildAnalyzeFullSamplingTask(
 				PartitionName: partitionNames[i],
 				TableID:       statistics.AnalyzeTableID{TableID: tbl.TableInfo.ID, PartitionID: id},
 				StatsVersion:  version,
+				Incremental:   as.Incremental,
+				Quick:         as.Quick,
 			}
 			if optsV2, ok := optionsMap[physicalID]; ok {
 				info.V2Options = &optsV2
@@ -2588,7 +2666,9 @@ func (b *PlanBuilder) genV2AnalyzeOptions(
 		tblColChoice, tblColList = pickColumnList(astColChoice, astColList, tblSavedColChoice, tblSavedColList)
 	}
 
-	tblFilledOpts := fillAnalyzeOptionsV2(tblOpts)
+	// The default options of the session are used for the execution only, they are not persisted.
+	sessionOpts := sessionAnalyzeOptions(&b.ctx.GetSessionVars().AnalyzeDefaults, statistics.Version2)
+	tblFilledOpts := fillAnalyzeOptionsV2(mergeDefaultAnalyzeOptions(tblOpts, sessionOpts))
 
 	tblColsInfo, tblColList, err := b.getFullAnalyzeColumnsInfo(tbl, tblColChoice, tblColList, predicateCols, mustAnalyzedCols, mustAllColumns, false)
 	if err != nil {
@@ -2633,7 +2713,7 @@ func (b *PlanBuilder) genV2AnalyzeOptions(
 			savedColChoice, savedColList := pickColumnList(parSavedColChoice, parSavedColList, tblSavedColChoice, tblSavedColList)
 			// then merge statement level options
 			mergedOpts := mergeAnalyzeOptions(astOpts, savedOpts)
-			filledMergedOpts := fillAnalyzeOptionsV2(mergedOpts)
+			filledMergedOpts := fillAnalyzeOptionsV2(mergeDefaultAnalyzeOptions(mergedOpts, sessionOpts))
 			finalColChoice, mergedColList := pickColumnList(astColChoice, astColList, savedColChoice, savedColList)
 			finalColsInfo, finalColList, err := b.getFullAnalyzeColumnsInfo(tbl, finalColChoice, mergedColList, predicateCols, mustAnalyzedCols, mustAllColumns, false)
 			if err != nil {
@@ -2727,6 +2807,114 @@ func pickColumnList(astColChoice pmodel.ColumnChoice, astColList []*model.Column
 	return tblSavedColChoice, tblSavedColList
 }
 
+// workloadClassAnalyzeOptions are the default analyze options of the workload classes.
+var workloadClassAnalyzeOptions = map[statistics.WorkloadClass]map[ast.AnalyzeOptionType]uint64{
+	// The newly inserted values are usually out of the range of the histogram,
+	// so more buckets are used to keep the ranges fine-grained after the next analysis.
+	statistics.WorkloadClassAppendOnly: {ast.AnalyzeOptNumBuckets: 512},
+	// Updates usually concentrate on hot values, so more TopN values are used to capture the skew.
+	statistics.WorkloadClassUpdateHeavy: {ast.AnalyzeOptNumTopN: 500},
+}
+
+// workloadClassColumnChoice are the default column choices of the workload classes.
+var workloadClassColumnChoice = map[statistics.WorkloadClass]pmodel.ColumnChoice{
+	// Dimension tables are usually joined and filtered by many columns, and it is cheap to analyze all of them.
+	statistics.WorkloadClassSmallDimension: pmodel.AllColumns,
+	// Collecting the stats of all columns is too expensive for huge tables.
+	statistics.WorkloadClassHugeFact: pmodel.PredicateColumns,
+}
+
+// applyWorkloadClassAnalyzeOptions applies the default analyze options of the workload classes of the tables
+// for auto analyze. The class options only take effect when the options are neither specified in the statement
+// nor persisted for the table, and they are not persisted, so that the class can change as the workload changes.
+// The default options of the session, which are inherited by the jobs enqueued for auto analyze, take precedence
+// over the class options.
+func (b *PlanBuilder) applyWorkloadClassAnalyzeOptions(
+	tbl *resolve.TableNameW,
+	physicalIDs []int64,
+	astOpts map[ast.AnalyzeOptionType]uint64,
+	astColChoice pmodel.ColumnChoice,
+	astColList []*model.ColumnInfo,
+	optionsMap map[int64]V2AnalyzeOptions,
+	colsInfoMap map[int64][]*model.ColumnInfo,
+	predicateCols, mustAnalyzedCols *calcOnceMap,
+	mustAllColumns bool,
+) error {
+	statsHandle := domain.GetDomain(b.ctx).StatsHandle()
+	if statsHandle == nil {
+		return nil
+	}
+	// In dynamic mode, all the partitions use the same options as the table level.
+	dynamicPrune := variable.PartitionPruneMode(b.ctx.GetSessionVars().PartitionPruneMode.Load()) == variable.Dynamic
+	tblClass := statistics.ClassifyWorkload(statsHandle.GetPartitionStatsForAutoAnalyze(tbl.TableInfo, tbl.TableInfo.ID))
+	sessionDefaults := &b.ctx.GetSessionVars().AnalyzeDefaults
+	sessionOpts := sessionAnalyzeOptions(sessionDefaults, statistics.Version2)
+	for _, physicalID := range physicalIDs {
+		class := tblClass
+		if physicalID != tbl.TableInfo.ID && !dynamicPrune {
+			class = statistics.ClassifyWorkload(statsHandle.GetPartitionStatsForAutoAnalyze(tbl.TableInfo, physicalID))
+		}
+		classOpts, hasClassOpts := workloadClassAnalyzeOptions[class]
+		classColChoice, hasClassColChoice := workloadClassColumnChoice[class]
+		if !hasClassOpts && !hasClassColChoice {
+			continue
+		}
+		opts, ok := optionsMap[physicalID]
+		if !ok {
+			opts = V2AnalyzeOptions{
+				PhyTableID:  physicalID,
+				RawOpts:     astOpts,
+				FilledOpts:  fillAnalyzeOptionsV2(mergeDefaultAnalyzeOptions(astOpts, sessionOpts)),
+				ColChoice:   astColChoice,
+				ColumnList:  astColList,
+				IsPartition: physicalID != tbl.TableInfo.ID,
+			}
+		}
+		if hasClassOpts {
+			merged := mergeDefaultAnalyzeOptions(mergeDefaultAnalyzeOptions(opts.RawOpts, sessionOpts), classOpts)
+			opts.FilledOpts = fillAnalyzeOptionsV2(merged)
+		}
+		if hasClassColChoice && opts.ColChoice == pmodel.DefaultChoice && sessionDefaults.ColumnChoice == "" {
+			colsInfo, _, err := b.getFullAnalyzeColumnsInfo(tbl, classColChoice, nil, predicateCols, mustAnalyzedCols, mustAllColumns, false)
+			if err != nil {
+				return err
+			}
+			colsInfoMap[physicalID] = colsInfo
+		}
+		optionsMap[physicalID] = opts
+		logutil.BgLogger().Debug("apply workload class analyze options",
+			zap.String("table", tbl.Name.O),
+			zap.Int64("physicalID", physicalID),
+			zap.String("class", string(class)))
+	}
+	return nil
+}
+
+// mergeDefaultAnalyzeOptions fills the default options, e.g. the options of the workload class or the session,
+// which are not set in the options.
+func mergeDefaultAnalyzeOptions(
+	opts map[ast.AnalyzeOptionType]uint64,
+	defaultOpts map[ast.AnalyzeOptionType]uint64,
+) map[ast.AnalyzeOptionType]uint64 {
+	merged := make(map[ast.AnalyzeOptionType]uint64, len(opts)+len(defaultOpts))
+	for optType, val := range opts {
+		merged[optType] = val
+	}
+	_, hasNumSamples := opts[ast.AnalyzeOptNumSamples]
+	_, hasSampleRate := opts[ast.AnalyzeOptSampleRate]
+	for optType, val := range defaultOpts {
+		if _, ok := merged[optType]; ok {
+			continue
+		}
+		// The sample number and the sample rate can not be used together.
+		if (optType == ast.AnalyzeOptSampleRate || optType == ast.AnalyzeOptNumSamples) && (hasNumSamples || hasSampleRate) {
+			continue
+		}
+		merged[optType] = val
+	}
+	return merged
+}
+
 // buildAnalyzeTable constructs analyze tasks for each table.
 func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.AnalyzeOptionType]uint64, version int) (base.Plan, error) {
 	p := &Analyze{Opts: opts}
@@ -2742,6 +2930,10 @@ func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.A
 		if tnW.TableInfo.IsSequence() {
 			return nil, errors.Errorf("analyze sequence %s is not supported now", tbl.Name.O)
 		}
+		if tnW.TableInfo.TempTableType != model.TempTableNone {
+			p.TempTableTasks = append(p.TempTableTasks, AnalyzeTempTableTask{DBName: tbl.Schema.O, TblInfo: tnW.TableInfo})
+			continue
+		}
 
 		idxInfo, colInfo := b.getColsInfo(tbl)
 		physicalIDs, partitionNames, err := GetPhysicalIDsAndPartitionNames(tnW.TableInfo, as.PartitionNames)
@@ -2955,6 +3147,9 @@ var analyzeOptionLimit = map[ast.AnalyzeOptionType]uint64{
 	ast.AnalyzeOptCMSketchDepth: CMSketchSizeLimit,
 	ast.AnalyzeOptNumSamples:    5000000,
 	ast.AnalyzeOptSampleRate:    math.Float64bits(1),
+	// The block sample rate 1 means all the regions are scanned.
+	ast.AnalyzeOptBlockSampleRate: math.Float64bits(1),
+	ast.AnalyzeOptTiFlash:         1,
 }
 
 // TODO(hi-rustin): give some explanation about the default value.
@@ -2965,6 +3160,11 @@ var analyzeOptionDefault = map[ast.AnalyzeOptionType]uint64{
 	ast.AnalyzeOptCMSketchDepth: 5,
 	ast.AnalyzeOptNumSamples:    10000,
 	ast.AnalyzeOptSampleRate:    math.Float64bits(0),
+	// 0 means using tidb_analyze_block_sample_rate.
+	ast.AnalyzeOptBlockSampleRate: math.Float64bits(0),
+	// 0 means using tidb_analyze_auto_tiflash.
+	ast.AnalyzeOptTiFlash:  0,
+	ast.AnalyzeOptSampling: statistics.DefaultSamplingStrategyID,
 }
 
 // TopN reduced from 500 to 100 due to concerns over large number of TopN values collected for customers with many tables.
@@ -2976,6 +3176,11 @@ var analyzeOptionDefaultV2 = map[ast.AnalyzeOptionType]uint64{
 	ast.AnalyzeOptCMSketchDepth: 5,
 	ast.AnalyzeOptNumSamples:    0,
 	ast.AnalyzeOptSampleRate:    math.Float64bits(-1),
+	// 0 means using tidb_analyze_block_sample_rate.
+	ast.AnalyzeOptBlockSampleRate: math.Float64bits(0),
+	// 0 means using tidb_analyze_auto_tiflash.
+	ast.AnalyzeOptTiFlash:  0,
+	ast.AnalyzeOptSampling: statistics.DefaultSamplingStrategyID,
 }
 
 // This function very similar to handleAnalyzeOptions, but it's used for analyze version 2.
@@ -3004,6 +3209,22 @@ func handleAnalyzeOptionsV2(opts []ast.AnalyzeOpt) (map[ast.AnalyzeOptionType]ui
 			}
 			sampleRate = fVal
 			optMap[opt.Type] = math.Float64bits(fVal)
+		case ast.AnalyzeOptBlockSampleRate:
+			fVal, err := datumValue.ToFloat64(types.DefaultStmtNoWarningContext)
+			if err != nil {
+				return nil, err
+			}
+			limit := math.Float64frombits(analyzeOptionLimit[opt.Type])
+			if fVal <= 0 || fVal > limit {
+				return nil, errors.Errorf("Value of analyze option %s should not larger than %f, and should be greater than 0", ast.AnalyzeOptionString[opt.Type], limit)
+			}
+			optMap[opt.Type] = math.Float64bits(fVal)
+		case ast.AnalyzeOptSampling:
+			id, ok := statistics.SamplingStrategyIDByName(datumValue.GetString())
+			if !ok {
+				return nil, errors.Errorf("Unknown sampling strategy %s", datumValue.GetString())
+			}
+			optMap[opt.Type] = id
 		default:
 			v := datumValue.GetUint64()
 			if opt.Type == ast.AnalyzeOptNumSamples {
@@ -3034,6 +3255,41 @@ func fillAnalyzeOptionsV2(optMap map[ast.AnalyzeOptionType]uint64) map[ast.Analy
 	return filledMap
 }
 
+// sessionAnalyzeOptions returns the default analyze options set by tidb_analyze_default_sample_rate,
+// tidb_analyze_default_buckets and tidb_analyze_default_topn. The sample rate is only supported by the version 2 stats.
+func sessionAnalyzeOptions(defaults *variable.AnalyzeDefaults, statsVer int) map[ast.AnalyzeOptionType]uint64 {
+	opts := make(map[ast.AnalyzeOptionType]uint64, 3)
+	if defaults.Buckets > 0 {
+		opts[ast.AnalyzeOptNumBuckets] = defaults.Buckets
+	}
+	if defaults.TopN >= 0 {
+		opts[ast.AnalyzeOptNumTopN] = uint64(defaults.TopN)
+	}
+	if defaults.SampleRate > 0 && statsVer != statistics.Version1 {
+		opts[ast.AnalyzeOptSampleRate] = math.Float64bits(defaults.SampleRate)
+	}
+	return opts
+}
+
+// applySessionAnalyzeOptions replaces the built-in defaults in the options with the default options of the session
+// if they are not specified in the statement.
+func applySessionAnalyzeOptions(opts map[ast.AnalyzeOptionType]uint64, stmtOpts []ast.AnalyzeOpt, sessionOpts map[ast.AnalyzeOptionType]uint64) {
+	specified := make(map[ast.AnalyzeOptionType]struct{}, len(stmtOpts))
+	for _, opt := range stmtOpts {
+		specified[opt.Type] = struct{}{}
+	}
+	for optType, val := range sessionOpts {
+		if _, ok := specified[optType]; ok {
+			continue
+		}
+		// The sample number and the sample rate can not be used together.
+		if _, ok := specified[ast.AnalyzeOptNumSamples]; ok && optType == ast.AnalyzeOptSampleRate {
+			continue
+		}
+		opts[optType] = val
+	}
+}
+
 func handleAnalyzeOptions(opts []ast.AnalyzeOpt, statsVer int) (map[ast.AnalyzeOptionType]uint64, error) {
 	optMap := make(map[ast.AnalyzeOptionType]uint64, len(analyzeOptionDefault))
 	if statsVer == statistics.Version1 {
@@ -3070,6 +3326,33 @@ func handleAnalyzeOptions(opts []ast.AnalyzeOpt, statsVer int) (map[ast.AnalyzeO
 			}
 			sampleRate = fVal
 			optMap[opt.Type] = math.Float64bits(fVal)
+		case ast.AnalyzeOptBlockSampleRate:
+			fVal, err := datumValue.ToFloat64(types.DefaultStmtNoWarningContext)
+			if err != nil {
+				return nil, err
+			}
+			if statsVer == statistics.Version1 {
+				return nil, errors.Errorf("Version 1's statistics doesn't support the BLOCKSAMPLE option, please set tidb_analyze_version to 2")
+			}
+			limit := math.Float64frombits(analyzeOptionLimit[opt.Type])
+			if fVal <= 0 || fVal > limit {
+				return nil, errors.Errorf("Value of analyze option %s should not larger than %f, and should be greater than 0", ast.AnalyzeOptionString[opt.Type], limit)
+			}
+			optMap[opt.Type] = math.Float64bits(fVal)
+		case ast.AnalyzeOptTiFlash:
+			if statsVer == statistics.Version1 {
+				return nil, errors.Errorf("Version 1's statistics doesn't support the TIFLASH option, please set tidb_analyze_version to 2")
+			}
+			optMap[opt.Type] = 1
+		case ast.AnalyzeOptSampling:
+			if statsVer == statistics.Version1 {
+				return nil, errors.Errorf("Version 1's statistics doesn't support the SAMPLING option, please set tidb_analyze_version to 2")
+			}
+			id, ok := statistics.SamplingStrategyIDByName(datumValue.GetString())
+			if !ok {
+				return nil, errors.Errorf("Unknown sampling strategy %s", datumValue.GetString())
+			}
+			optMap[opt.Type] = id
 		default:
 			v := datumValue.GetUint64()
 			if opt.Type == ast.AnalyzeOptNumSamples {
@@ -3095,25 +3378,131 @@ func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (base.Plan, error)
 	if as.NoWriteToBinLog {
 		return nil, dbterror.ErrNotSupportedYet.GenWithStackByArgs("[NO_WRITE_TO_BINLOG | LOCAL]")
 	}
+	statsVersion := b.ctx.GetSessionVars().AnalyzeVersion
 	if as.Incremental {
-		return nil, errors.Errorf("the incremental analyze feature has already been removed in TiDB v7.5.0, so this will have no effect")
+		if err := b.checkIncrementalAnalyze(as, statsVersion); err != nil {
+			return nil, err
+		}
+	}
+	if as.Quick {
+		if err := checkQuickAnalyze(as, statsVersion); err != nil {
+			return nil, err
+		}
 	}
-	statsVersion := b.ctx.GetSessionVars().AnalyzeVersion
 	// Require INSERT and SELECT privilege for tables.
 	b.requireInsertAndSelectPriv(as.TableNames)
 
+	switch as.HistogramOperation {
+	case ast.HistogramOperationUpdate:
+		// UPDATE HISTOGRAM ON c1, c2 is the MySQL way to analyze the specified columns.
+		colListStmt := *as
+		colListStmt.ColumnChoice = pmodel.ColumnList
+		as = &colListStmt
+	case ast.HistogramOperationDrop:
+		return b.buildDropHistogram(as)
+	}
+
 	opts, err := handleAnalyzeOptions(as.AnalyzeOpts, statsVersion)
 	if err != nil {
 		return nil, err
 	}
+	applySessionAnalyzeOptions(opts, as.AnalyzeOpts, sessionAnalyzeOptions(&b.ctx.GetSessionVars().AnalyzeDefaults, statsVersion))
 
+	var p base.Plan
 	if as.IndexFlag {
 		if len(as.IndexNames) == 0 {
-			return b.buildAnalyzeAllIndex(as, opts, statsVersion)
+			p, err = b.buildAnalyzeAllIndex(as, opts, statsVersion)
+		} else {
+			p, err = b.buildAnalyzeIndex(as, opts, statsVersion)
+		}
+	} else {
+		p, err = b.buildAnalyzeTable(as, opts, statsVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if analyze, ok := p.(*Analyze); ok {
+		analyze.Priority = as.Priority
+	}
+	return p, nil
+}
+
+// buildDropHistogram builds the plan of ANALYZE TABLE ... DROP HISTOGRAM ON ..., which deletes the stats of the
+// specified columns.
+func (b *PlanBuilder) buildDropHistogram(as *ast.AnalyzeTableStmt) (base.Plan, error) {
+	tnW := b.resolveCtx.GetTableName(as.TableNames[0])
+	if tnW.TableInfo.IsView() || tnW.TableInfo.IsSequence() || tnW.TableInfo.TempTableType != model.TempTableNone {
+		return nil, errors.Errorf("dropping the histograms of %s is not supported", as.TableNames[0].Name.O)
+	}
+	if _, err := getAnalyzeColumnList(as.ColumnNames, tnW); err != nil {
+		return nil, err
+	}
+	return &Simple{Statement: as, ResolveCtx: b.resolveCtx}, nil
+}
+
+// checkIncrementalAnalyze checks whether the incremental analyze can be applied to the table.
+// The incremental analyze only scans the rows whose handles are larger than the ones analyzed last time,
+// so it's only for the non-partitioned tables which are organized by an integer primary key.
+func (b *PlanBuilder) checkIncrementalAnalyze(as *ast.AnalyzeTableStmt, statsVersion int) error {
+	if statsVersion != statistics.Version2 {
+		return errors.Errorf("Only the version 2 of analyze supports the incremental analyze")
+	}
+	tblInfo := b.resolveCtx.GetTableName(as.TableNames[0]).TableInfo
+	if tblInfo.GetPartitionInfo() != nil {
+		return errors.Errorf("The incremental analyze doesn't support the partitioned table %s", tblInfo.Name.O)
+	}
+	if !tblInfo.PKIsHandle {
+		return errors.Errorf("The incremental analyze only supports the table whose primary key is a clustered integer column, but table %s is not", tblInfo.Name.O)
+	}
+	return nil
+}
+
+// checkQuickAnalyze checks whether the quick analyze can be applied. The quick analyze scans a tiny fraction of the
+// regions of the TiKV replica by the block sampling, so it conflicts with the options choosing the scanned data.
+func checkQuickAnalyze(as *ast.AnalyzeTableStmt, statsVersion int) error {
+	if statsVersion != statistics.Version2 {
+		return errors.Errorf("Only the version 2 of analyze supports the quick analyze")
+	}
+	for _, opt := range as.AnalyzeOpts {
+		if opt.Type == ast.AnalyzeOptBlockSampleRate || opt.Type == ast.AnalyzeOptTiFlash {
+			return errors.Errorf("The quick analyze doesn't support the %s option", ast.AnalyzeOptionString[opt.Type])
+		}
+	}
+	return nil
+}
+
+// buildAnalyzeDatabase requires INSERT and SELECT privilege for the database.
+func (b *PlanBuilder) buildAnalyzeDatabase(as *ast.AnalyzeDatabaseStmt) (base.Plan, error) {
+	if _, ok := b.is.SchemaByName(as.DBName); !ok {
+		return nil, infoschema.ErrDatabaseNotExists.GenWithStackByArgs(as.DBName.O)
+	}
+	if as.Concurrency > math.MaxInt32 {
+		return nil, plannererrors.ErrWrongArguments.GenWithStackByArgs("ANALYZE DATABASE CONCURRENCY")
+	}
+	var budget time.Duration
+	if as.Budget != "" {
+		var err error
+		budget, err = time.ParseDuration(as.Budget)
+		if err != nil || budget <= 0 {
+			return nil, plannererrors.ErrWrongArguments.GenWithStackByArgs("ANALYZE DATABASE BUDGET")
 		}
-		return b.buildAnalyzeIndex(as, opts, statsVersion)
 	}
-	return b.buildAnalyzeTable(as, opts, statsVersion)
+	user := b.ctx.GetSessionVars().User
+	var insertErr, selectErr error
+	if user != nil {
+		insertErr = plannererrors.ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, as.DBName.O)
+		selectErr = insertErr
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, as.DBName.L, "", "", insertErr)
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, as.DBName.L, "", "", selectErr)
+
+	p := &AnalyzeDatabase{
+		DBName:      as.DBName,
+		Concurrency: int(as.Concurrency),
+		Budget:      budget,
+	}
+	p.setSchemaAndNames(buildAnalyzeDatabaseSchema())
+	return p, nil
 }
 
 func buildShowNextRowID() (*expression.Schema, types.NameSlice) {
@@ -3257,6 +3646,94 @@ func buildAdminShowBDRRoleFields() (*expression.Schema, types.NameSlice) {
 	return schema.col2Schema(), schema.names
 }
 
+func buildAdminBenchStatsLoadFields() (*expression.Schema, types.NameSlice) {
+	names := []string{"Db_name", "Table_name", "Partition_name", "Column_name", "Is_index", "Buckets", "TopN", "Load_time", "Mem_usage"}
+	ftypes := []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeTiny, mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeVarchar, mysql.TypeLonglong}
+	schema := newColumnsWithNames(len(names))
+	for i := range names {
+		fLen, _ := mysql.GetDefaultFieldLengthAndDecimal(ftypes[i])
+		if ftypes[i] == mysql.TypeVarchar {
+			fLen = 64
+		}
+		schema.Append(buildColumnWithName("", names[i], ftypes[i], fLen))
+	}
+	return schema.col2Schema(), schema.names
+}
+
+func buildAdminShowColumnStatsUsageFields() (*expression.Schema, types.NameSlice) {
+	names := []string{"Db_name", "Table_name", "Partition_name", "Column_name", "Last_used_at", "Last_analyzed_at", "Is_predicate"}
+	ftypes := []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime, mysql.TypeDatetime, mysql.TypeTiny}
+	schema := newColumnsWithNames(len(names))
+	for i := range names {
+		fLen, _ := mysql.GetDefaultFieldLengthAndDecimal(ftypes[i])
+		if ftypes[i] == mysql.TypeVarchar {
+			fLen = 64
+		}
+		schema.Append(buildColumnWithName("", names[i], ftypes[i], fLen))
+	}
+	return schema.col2Schema(), schema.names
+}
+
+func buildAdminValidateVectorIndexFields() (*expression.Schema, types.NameSlice) {
+	names := []string{"Db_name", "Table_name", "Index_name", "Distance_metric", "Sample_count", "Top_k", "Recall"}
+	ftypes := []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeDouble}
+	schema := newColumnsWithNames(len(names))
+	for i := range names {
+		fLen, _ := mysql.GetDefaultFieldLengthAndDecimal(ftypes[i])
+		if ftypes[i] == mysql.TypeVarchar {
+			fLen = 64
+		}
+		schema.Append(buildColumnWithName("", names[i], ftypes[i], fLen))
+	}
+	return schema.col2Schema(), schema.names
+}
+
+func buildAdminCheckStatsFields() (*expression.Schema, types.NameSlice) {
+	names := []string{"Db_name", "Table_name", "Column_name", "Stored_row_count", "Observed_row_count", "Stored_ndv",
+		"Observed_ndv", "Stored_null_ratio", "Observed_null_ratio", "Bucket_drift", "Drift_score"}
+	ftypes := []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeLonglong,
+		mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeDouble, mysql.TypeDouble, mysql.TypeDouble, mysql.TypeDouble}
+	schema := newColumnsWithNames(len(names))
+	for i := range names {
+		fLen, _ := mysql.GetDefaultFieldLengthAndDecimal(ftypes[i])
+		if ftypes[i] == mysql.TypeVarchar {
+			fLen = 64
+		}
+		schema.Append(buildColumnWithName("", names[i], ftypes[i], fLen))
+	}
+	return schema.col2Schema(), schema.names
+}
+
+func buildAdminCalibrateCostModelFields() (*expression.Schema, types.NameSlice) {
+	doubleSize, _ := mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeDouble)
+
+	schema := newColumnsWithNames(5)
+	schema.Append(buildColumnWithName("", "Factor", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Benchmark", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Default_value", mysql.TypeDouble, doubleSize))
+	schema.Append(buildColumnWithName("", "Calibrated_value", mysql.TypeDouble, doubleSize))
+	schema.Append(buildColumnWithName("", "Message", mysql.TypeVarchar, 256))
+	return schema.col2Schema(), schema.names
+}
+
+func buildAdminShowPlanDiffFields() (*expression.Schema, types.NameSlice) {
+	longlongSize, _ := mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeLonglong)
+	tinySize, _ := mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeTiny)
+
+	schema := newColumnsWithNames(10)
+	schema.Append(buildColumnWithName("", "Digest", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Schema_name", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Query_sample_text", mysql.TypeBlob, types.UnspecifiedLength))
+	schema.Append(buildColumnWithName("", "Exec_count", mysql.TypeLonglong, longlongSize))
+	schema.Append(buildColumnWithName("", "Old_plan_digest", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "New_plan_digest", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Plan_changed", mysql.TypeTiny, tinySize))
+	schema.Append(buildColumnWithName("", "Old_plan", mysql.TypeBlob, types.UnspecifiedLength))
+	schema.Append(buildColumnWithName("", "New_plan", mysql.TypeBlob, types.UnspecifiedLength))
+	schema.Append(buildColumnWithName("", "Error", mysql.TypeVarchar, 256))
+	return schema.col2Schema(), schema.names
+}
+
 func buildShowBackupMetaSchema() (*expression.Schema, types.NameSlice) {
 	names := []string{"Database", "Table", "Total_kvs", "Total_bytes", "Time_range_start", "Time_range_end"}
 	ftypes := []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeDatetime, mysql.TypeDatetime}
@@ -3465,7 +3942,7 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (base.P
 		p.setSchemaAndNames(buildShowNextRowID())
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, show.Table.Schema.L, show.Table.Name.L, "", plannererrors.ErrPrivilegeCheckFail)
 		return p, nil
-	case ast.ShowStatsExtended, ast.ShowStatsHealthy, ast.ShowStatsTopN, ast.ShowHistogramsInFlight, ast.ShowColumnStatsUsage:
+	case ast.ShowStatsExtended, ast.ShowStatsHealthy, ast.ShowStatsTopN, ast.ShowStatsDistribution, ast.ShowStatsIndexes, ast.ShowHistogramsInFlight, ast.ShowColumnStatsUsage:
 		var err error
 		if user := b.ctx.GetSessionVars().User; user != nil {
 			err = plannererrors.ErrDBaccessDenied.GenWithStackByArgs(user.AuthUsername, user.AuthHostname, mysql.SystemDB)
@@ -3477,12 +3954,25 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (base.P
 				buildPattern = false
 			}
 		}
+		if show.Tp == ast.ShowStatsTopN || show.Tp == ast.ShowStatsIndexes {
+			p.StatsFilter = extractShowStatsFilter(show.Where)
+		}
 	case ast.ShowStatsBuckets, ast.ShowStatsHistograms, ast.ShowStatsMeta, ast.ShowStatsLocked:
+		if show.Tp == ast.ShowStatsBuckets {
+			p.StatsFilter = extractShowStatsFilter(show.Where)
+		}
 		var err error
 		if user := b.ctx.GetSessionVars().User; user != nil {
 			err = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("SHOW", user.AuthUsername, user.AuthHostname, show.Table.Name.L)
 		}
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, show.Table.Schema.L, show.Table.Name.L, "", err)
+		if show.AsOf != nil {
+			ts, err := staleread.CalculateAsOfTsExpr(ctx, b.ctx, show.AsOf.TsExpr)
+			if err != nil {
+				return nil, err
+			}
+			p.StatsSnapshot = ts
+		}
 	case ast.ShowRegions:
 		tableInfo, err := b.is.TableByName(ctx, show.Table.Schema, show.Table.Name)
 		if err != nil {
@@ -3493,6 +3983,10 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (base.P
 		}
 	case ast.ShowReplicaStatus:
 		return nil, dbterror.ErrNotSupportedYet.GenWithStackByArgs("SHOW {REPLICA | SLAVE} STATUS")
+	case ast.ShowAnalyzeStatus:
+		// Only the latest jobs are shown by default, but the jobs filtered or limited by the user are looked up in all
+		// the jobs of the cluster, otherwise the filter only applies to the latest jobs.
+		p.AllAnalyzeJobs = show.Pattern != nil || show.Where != nil || show.Limit != nil
 	}
 
 	schema, names := buildShowSchema(show, isView, isSequence)
@@ -4516,7 +5010,7 @@ func (b *PlanBuilder) buildImportInto(ctx context.Context, ld *ast.ImportIntoStm
 		importFromServer bool
 	)
 
-	if ld.Select == nil {
+	if ld.Select == nil && ld.Datagen == nil {
 		importFromServer, err = storage.IsLocalPath(ld.Path)
 		if err != nil {
 			return nil, exeerrors.ErrLoadDataInvalidURI.FastGenByArgs(ImportIntoDataSource, err.Error())
@@ -4570,6 +5064,7 @@ func (b *PlanBuilder) buildImportInto(ctx context.Context, ld *ast.ImportIntoStm
 	p := ImportInto{
 		Path:               ld.Path,
 		Format:             ld.Format,
+		Datagen:            ld.Datagen,
 		Table:              tnW,
 		ColumnAssignments:  ld.ColumnAssignments,
 		ColumnsAndUserVars: ld.ColumnsAndUserVars,
@@ -4651,8 +5146,35 @@ func (b *PlanBuilder) buildImportInto(ctx context.Context, ld *ast.ImportIntoStm
 	return p, nil
 }
 
-func (*PlanBuilder) buildLoadStats(ld *ast.LoadStatsStmt) base.Plan {
-	p := &LoadStats{Path: ld.Path}
+func (b *PlanBuilder) buildLoadStats(ld *ast.LoadStatsStmt) (base.Plan, error) {
+	p := &LoadStats{Path: ld.Path, Merge: ld.Merge}
+	// Loading stats from the server disk requires the FILE privilege, same as IMPORT INTO.
+	if strings.HasPrefix(ld.Path, "local://") || strings.HasPrefix(ld.Path, "file://") {
+		if sem.IsEnabled() {
+			return nil, plannererrors.ErrNotSupportedWithSem.GenWithStackByArgs("LOAD STATS from server disk")
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.FilePriv, "", "", "", plannererrors.ErrSpecificAccessDenied.GenWithStackByArgs("FILE"))
+	}
+	return p, nil
+}
+
+// buildModifyStats requires INSERT and SELECT privilege for the table same as buildAnalyze.
+func (b *PlanBuilder) buildModifyStats(ms *ast.ModifyStatsStmt) base.Plan {
+	p := &ModifyStats{
+		Table:   ms.Table,
+		Options: ms.Options,
+	}
+	b.requireInsertAndSelectPriv([]*ast.TableName{ms.Table})
+	return p
+}
+
+// buildAutoAnalyzeOptions requires INSERT and SELECT privilege for the table same as buildAnalyze.
+func (b *PlanBuilder) buildAutoAnalyzeOptions(as *ast.AutoAnalyzeOptionsStmt) base.Plan {
+	p := &AutoAnalyzeOptions{
+		Table:   as.Table,
+		Options: as.Options,
+	}
+	b.requireInsertAndSelectPriv([]*ast.TableName{as.Table})
 	return p
 }
 
@@ -5012,6 +5534,13 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (base.Plan
 				}
 				b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.Table.Schema.L,
 					v.Table.Name.L, "", authErr)
+			} else if spec.Tp == ast.AlterTableDropRange {
+				if b.ctx.GetSessionVars().User != nil {
+					authErr = plannererrors.ErrTableaccessDenied.GenWithStackByArgs("DELETE", b.ctx.GetSessionVars().User.AuthUsername,
+						b.ctx.GetSessionVars().User.AuthHostname, v.Table.Name.L)
+				}
+				b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DeletePriv, dbName,
+					v.Table.Name.L, "", authErr)
 			} else if spec.Tp == ast.AlterTableWriteable {
 				b.visitInfo[0].alterWritable = true
 			} else if spec.Tp == ast.AlterTableAddStatistics {
@@ -5422,6 +5951,19 @@ func (b *PlanBuilder) buildExplain(ctx context.Context, explain *ast.ExplainStmt
 		return nil, err
 	}
 
+	if explain.StatsOverlay != "" {
+		overlay, err := b.buildStatsOverlay(explain.StatsOverlay)
+		if err != nil {
+			return nil, err
+		}
+		// The overlay is only used in the planning of the explained statement, and the plan is not cached.
+		stmtCtx := sctx.GetSessionVars().StmtCtx
+		stmtCtx.StatsOverlay = overlay
+		defer func() {
+			stmtCtx.StatsOverlay = nil
+		}()
+	}
+
 	nodeW := resolve.NewNodeWWithCtx(explain.Stmt, b.resolveCtx)
 	targetPlan, _, err := OptimizeAstNode(ctx, sctx, nodeW, b.is)
 	if err != nil {
@@ -5650,9 +6192,17 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 	case ast.ShowStatsTopN:
 		names = []string{"Db_name", "Table_name", "Partition_name", "Column_name", "Is_index", "Value", "Count"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeTiny, mysql.TypeVarchar, mysql.TypeLonglong}
+	case ast.ShowStatsDistribution:
+		names = []string{"Db_name", "Table_name", "Partition_name", "Column_name", "Is_index", "Kind", "Value", "Count"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeTiny, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong}
 	case ast.ShowStatsHealthy:
 		names = []string{"Db_name", "Table_name", "Partition_name", "Healthy"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong}
+	case ast.ShowStatsIndexes:
+		names = []string{"Db_name", "Table_name", "Partition_name", "Index_name", "Stats_version", "Last_analyzed_at",
+			"Modify_count", "Healthy", "Query_total", "Last_used_at"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong,
+			mysql.TypeDatetime, mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeDatetime}
 	case ast.ShowHistogramsInFlight:
 		names = []string{"HistogramsInFlight"}
 		ftypes = []byte{mysql.TypeLonglong}
@@ -5733,7 +6283,8 @@ func convert2OutputSchemasAndNames(names []string, ftypes []byte, flags []uint)
 
 func (b *PlanBuilder) buildPlanReplayer(pc *ast.PlanReplayerStmt) base.Plan {
 	p := &PlanReplayer{ExecStmt: pc.Stmt, Analyze: pc.Analyze, Load: pc.Load, File: pc.File,
-		Capture: pc.Capture, Remove: pc.Remove, SQLDigest: pc.SQLDigest, PlanDigest: pc.PlanDigest}
+		Capture: pc.Capture, Remove: pc.Remove, SQLDigest: pc.SQLDigest, PlanDigest: pc.PlanDigest,
+		CaptureThresholds: pc.CaptureThresholds}
 
 	if pc.HistoricalStatsInfo != nil {
 		p.HistoricalStatsTS = calcTSForPlanReplayer(b.ctx, pc.HistoricalStatsInfo.TsExpr)
@@ -5785,6 +6336,17 @@ func calcTSForPlanReplayer(sctx base.PlanContext, tsExpr ast.ExprNode) uint64 {
 	return oracle.GoTimeToTS(goTime)
 }
 
+func buildAnalyzeDatabaseSchema() (*expression.Schema, []*types.FieldName) {
+	schema := newColumnsWithNames(6)
+	schema.Append(buildColumnWithName("", "Db_name", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Table_name", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Partition_name", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Status", mysql.TypeVarchar, 16))
+	schema.Append(buildColumnWithName("", "Duration", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Message", mysql.TypeVarchar, 512))
+	return schema.col2Schema(), schema.names
+}
+
 func buildChecksumTableSchema() (*expression.Schema, []*types.FieldName) {
 	schema := newColumnsWithNames(5)
 	schema.Append(buildColumnWithName("", "Db_name", mysql.TypeVarchar, 128))
@@ -5795,6 +6357,20 @@ func buildChecksumTableSchema() (*expression.Schema, []*types.FieldName) {
 	return schema.col2Schema(), schema.names
 }
 
+func buildChecksumTableCompareSchema() (*expression.Schema, []*types.FieldName) {
+	schema := newColumnsWithNames(9)
+	schema.Append(buildColumnWithName("", "Db_name", mysql.TypeVarchar, 128))
+	schema.Append(buildColumnWithName("", "Table_name", mysql.TypeVarchar, 128))
+	schema.Append(buildColumnWithName("", "Chunk_id", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Chunk_range", mysql.TypeVarchar, 1024))
+	schema.Append(buildColumnWithName("", "Local_rows", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Remote_rows", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Local_checksum", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Remote_checksum", mysql.TypeLonglong, 22))
+	schema.Append(buildColumnWithName("", "Result", mysql.TypeVarchar, 16))
+	return schema.col2Schema(), schema.names
+}
+
 // adjustOverlongViewColname adjusts the overlong outputNames of a view to
 // `new_exp_$off` where `$off` is the offset of the output column, $off starts from 1.
 // There is still some MySQL compatible problems.
@@ -5844,15 +6420,16 @@ func (b *PlanBuilder) buildCompactTable(node *ast.CompactTableStmt) (base.Plan,
 
 func (*PlanBuilder) buildRecommendIndex(v *ast.RecommendIndexStmt) (base.Plan, error) {
 	p := &RecommendIndexPlan{
-		Action:   v.Action,
-		SQL:      v.SQL,
-		AdviseID: v.ID,
-		Options:  v.Options,
+		Action:    v.Action,
+		SQL:       v.SQL,
+		SQLDigest: v.SQLDigest,
+		AdviseID:  v.ID,
+		Options:   v.Options,
 	}
 
 	switch v.Action {
 	case "run":
-		schema := newColumnsWithNames(7)
+		schema := newColumnsWithNames(9)
 		schema.Append(buildColumnWithName("", "database", mysql.TypeVarchar, 64))
 		schema.Append(buildColumnWithName("", "table", mysql.TypeVarchar, 64))
 		schema.Append(buildColumnWithName("", "index_name", mysql.TypeVarchar, 64))
@@ -5860,6 +6437,8 @@ func (*PlanBuilder) buildRecommendIndex(v *ast.RecommendIndexStmt) (base.Plan, e
 		schema.Append(buildColumnWithName("", "index_size", mysql.TypeVarchar, 256))
 		schema.Append(buildColumnWithName("", "reason", mysql.TypeVarchar, 256))
 		schema.Append(buildColumnWithName("", "top_impacted_query", mysql.TypeBlob, -1))
+		schema.Append(buildColumnWithName("", "workload_improvement", mysql.TypeDouble, 22))
+		schema.Append(buildColumnWithName("", "create_index_statement", mysql.TypeVarchar, 1024))
 		p.setSchemaAndNames(schema.col2Schema(), schema.names)
 	case "set":
 		if len(p.Options) == 0 {
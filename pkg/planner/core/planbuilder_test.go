@@ -609,6 +609,39 @@ func TestHandleAnalyzeOptionsV1AndV2(t *testing.T) {
 			statsVer:    statistics.Version2,
 			ExpectedErr: "Value of analyze option SAMPLERATE should not larger than 1.000000, and should be greater than 0",
 		},
+		{
+			name: "Use BlockSample option in stats version 1",
+			opts: []ast.AnalyzeOpt{
+				{
+					Type:  ast.AnalyzeOptBlockSampleRate,
+					Value: ast.NewValueExpr(0.1, "", ""),
+				},
+			},
+			statsVer:    statistics.Version1,
+			ExpectedErr: "Version 1's statistics doesn't support the BLOCKSAMPLE option, please set tidb_analyze_version to 2",
+		},
+		{
+			name: "Too big BlockSample option",
+			opts: []ast.AnalyzeOpt{
+				{
+					Type:  ast.AnalyzeOptBlockSampleRate,
+					Value: ast.NewValueExpr(2, "", ""),
+				},
+			},
+			statsVer:    statistics.Version2,
+			ExpectedErr: "Value of analyze option BLOCKSAMPLE should not larger than 1.000000, and should be greater than 0",
+		},
+		{
+			name: "Use TiFlash option in stats version 1",
+			opts: []ast.AnalyzeOpt{
+				{
+					Type:  ast.AnalyzeOptTiFlash,
+					Value: ast.NewValueExpr(uint64(1), "", ""),
+				},
+			},
+			statsVer:    statistics.Version1,
+			ExpectedErr: "Version 1's statistics doesn't support the TIFLASH option, please set tidb_analyze_version to 2",
+		},
 		{
 			name: "Too big NumBuckets option",
 			opts: []ast.AnalyzeOpt{
@@ -952,6 +952,16 @@ func (p *preprocessor) checkCreateTableGrammar(stmt *ast.CreateTableStmt) {
 		p.err = dbterror.ErrTableMustHaveColumns
 		return
 	}
+	if stmt.Datagen != nil {
+		if stmt.TemporaryKeyword != ast.TemporaryNone {
+			p.err = errors.New("'CREATE TEMPORARY TABLE ... AS DATAGEN' is not supported")
+			return
+		}
+		if stmt.IfNotExists {
+			p.err = errors.New("'CREATE TABLE IF NOT EXISTS ... AS DATAGEN' is not supported")
+			return
+		}
+	}
 
 	if stmt.Partition != nil {
 		for _, def := range stmt.Partition.Definitions {
@@ -81,6 +81,7 @@ func (c *CollectPredicateColumnsPoint) Optimize(_ context.Context, plan base.Log
 	histNeededItems := collectHistNeededItems(histNeededColumns, histNeededIndices)
 	histNeededItems = c.expandStatsNeededColumnsForStaticPruning(histNeededItems, tid2pids)
 	if len(histNeededItems) > 0 {
+		plan.SCtx().GetSessionVars().StmtCtx.StatsLoad.PlanNeededItems = histNeededItems
 		err := RequestLoadStats(plan.SCtx(), histNeededItems, syncWait)
 		return plan, planChanged, err
 	}
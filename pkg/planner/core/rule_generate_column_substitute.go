@@ -238,3 +238,40 @@ func (gc *GcSubstituter) substitute(ctx context.Context, lp base.LogicalPlan, ex
 func (*GcSubstituter) Name() string {
 	return "generate_column_substitute"
 }
+
+// substituteVirtualColumns4Estimation returns the copies of the conditions in which the expressions of the virtual
+// generated columns are substituted by the columns themselves. The columns are not required to be indexed since the
+// result is only used for estimation, so that the predicates on expressions, e.g. `date_format(ts, '%Y-%m') = ...`,
+// can be estimated with the stats of the virtual generated columns, including the hidden columns of expression indexes.
+// The second return value indicates whether any expression is substituted.
+func substituteVirtualColumns4Estimation(ds *logicalop.DataSource, conds []expression.Expression, accept func(*expression.Column) bool) ([]expression.Expression, bool) {
+	ectx := ds.SCtx().GetExprCtx().GetEvalCtx()
+	exprToColumn := make(ExprColumnMap)
+	for _, col := range ds.TblCols {
+		if col.VirtualExpr == nil || !accept(col) {
+			continue
+		}
+		if col.GetType(ectx).PartialEqual(col.VirtualExpr.GetType(ectx), ds.SCtx().GetSessionVars().EnableUnsafeSubstitute) {
+			exprToColumn[col.VirtualExpr] = col
+		}
+	}
+	if len(exprToColumn) == 0 {
+		return conds, false
+	}
+	schema := expression.NewSchema(ds.TblCols...)
+	substituted := false
+	newConds := make([]expression.Expression, 0, len(conds))
+	for _, cond := range conds {
+		newCond := cond.Clone()
+		if substituteExpression(newCond, ds, exprToColumn, schema, nil) {
+			substituted = true
+			newConds = append(newConds, newCond)
+			continue
+		}
+		newConds = append(newConds, cond)
+	}
+	if !substituted {
+		return conds, false
+	}
+	return newConds, true
+}
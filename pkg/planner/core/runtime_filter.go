@@ -241,6 +241,8 @@ func (rf *RuntimeFilter) ToPB(ctx *base.BuildPBContext, client kv.Client) (*tipb
 		rfTypePB = tipb.RuntimeFilterType_IN
 	case variable.MinMax:
 		rfTypePB = tipb.RuntimeFilterType_MIN_MAX
+	case variable.BloomFilter:
+		rfTypePB = tipb.RuntimeFilterType_BLOOM_FILTER
 	}
 	rfModePB := tipb.RuntimeFilterMode_LOCAL
 	switch rf.rfMode {
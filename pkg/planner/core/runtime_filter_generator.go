@@ -22,12 +22,22 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/planner/core/base"
 	"github.com/pingcap/tidb/pkg/planner/core/operator/logicalop"
+	"github.com/pingcap/tidb/pkg/planner/property"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"go.uber.org/zap"
 )
 
+const (
+	// bloomFilterMaxBuildRows is the max estimated row count of the build side to generate a bloom filter, the larger
+	// bloom filters take too much memory and network to be beneficial.
+	bloomFilterMaxBuildRows = 10000000
+	// bloomFilterMaxPassRatio is the max estimated fraction of the probe side rows passing the bloom filter, the bloom
+	// filters filtering fewer rows are not worth building and checking.
+	bloomFilterMaxPassRatio = 0.5
+)
+
 // RuntimeFilterGenerator One plan one generator
 type RuntimeFilterGenerator struct {
 	rfIDGenerator                 *util.IDGenerator
@@ -95,6 +105,10 @@ func (generator *RuntimeFilterGenerator) generateRuntimeFilterInterval(hashJoinP
 		if generator.matchEQPredicate(ectx, eqPredicate, hashJoinPlan.RightIsBuildSide()) {
 			// construct runtime filter
 			newRFList, targetColumnUniqueID := NewRuntimeFilter(generator.rfIDGenerator, eqPredicate, hashJoinPlan)
+			newRFList = generator.pruneBloomFilter(newRFList, eqPredicate, hashJoinPlan)
+			if len(newRFList) == 0 {
+				continue
+			}
 			// update generator rf list
 			rfList := generator.columnUniqueIDToRF[targetColumnUniqueID]
 			if rfList == nil {
@@ -157,6 +171,49 @@ func (generator *RuntimeFilterGenerator) assignRuntimeFilter(physicalTableScan *
 	// filter predicate selectivity, A scan node does not need many RFs, and the same column does not need many RFs
 }
 
+// pruneBloomFilter removes the bloom filter from the runtime filters of the equal predicate if it's not beneficial by
+// the stats. Unlike IN and MIN_MAX, the bloom filter is built for every build side, so it's only generated when the
+// build side is small enough and its keys are expected to filter out most of the probe side rows.
+func (*RuntimeFilterGenerator) pruneBloomFilter(rfList []*RuntimeFilter, eqPredicate *expression.ScalarFunction,
+	hashJoinPlan *PhysicalHashJoin) []*RuntimeFilter {
+	buildIdx := 1
+	if !hashJoinPlan.RightIsBuildSide() {
+		buildIdx = 0
+	}
+	buildStats := hashJoinPlan.Children()[buildIdx].StatsInfo()
+	probeStats := hashJoinPlan.Children()[1-buildIdx].StatsInfo()
+	buildCol := eqPredicate.GetArgs()[buildIdx].(*expression.Column)
+	probeCol := eqPredicate.GetArgs()[1-buildIdx].(*expression.Column)
+	beneficial := false
+	if buildStats != nil && probeStats != nil && buildStats.RowCount <= bloomFilterMaxBuildRows {
+		buildNDV := getRFColumnNDV(buildStats, buildCol)
+		probeNDV := getRFColumnNDV(probeStats, probeCol)
+		beneficial = probeNDV > 0 && buildNDV/probeNDV <= bloomFilterMaxPassRatio
+	}
+	if beneficial {
+		return rfList
+	}
+	result := rfList[:0]
+	for _, rf := range rfList {
+		if rf.rfType == variable.BloomFilter {
+			logutil.BgLogger().Debug("The bloom filter is not beneficial by the stats. Remove it",
+				zap.Int("BuildNodeId", hashJoinPlan.ID()),
+				zap.String("EQPredicate", eqPredicate.StringWithCtx(hashJoinPlan.SCtx().GetExprCtx().GetEvalCtx(), errors.RedactLogDisable)))
+			continue
+		}
+		result = append(result, rf)
+	}
+	return result
+}
+
+// getRFColumnNDV returns the estimated NDV of the column, the row count is used if the NDV is unknown.
+func getRFColumnNDV(stats *property.StatsInfo, col *expression.Column) float64 {
+	if ndv, ok := stats.ColNDVs[col.UniqueID]; ok && ndv > 0 {
+		return min(ndv, stats.RowCount)
+	}
+	return stats.RowCount
+}
+
 func (*RuntimeFilterGenerator) matchRFJoinType(hashJoinPlan *PhysicalHashJoin) bool {
 	if hashJoinPlan.RightIsBuildSide() {
 		// case1: build side is on the right
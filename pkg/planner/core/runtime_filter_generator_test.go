@@ -17,6 +17,7 @@ package core_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/failpoint"
@@ -90,3 +91,51 @@ func TestRuntimeFilterGenerator(t *testing.T) {
 		tk.MustQuery("explain " + ts).Check(testkit.Rows(output[i].Plan...))
 	}
 }
+
+func TestRuntimeFilterBloomFilter(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (k1 int, k2 int)")
+	tk.MustExec("create table t2 (k1 int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 1), (3, 2), (4, 2), (5, 3), (6, 3), (7, 4), (8, 4), (9, 5), (10, 5)")
+	tk.MustExec("insert into t2 values (1), (2), (3), (4), (5), (6), (7), (8), (9), (10)")
+	tk.MustExec("analyze table t1, t2 all columns")
+	dom := domain.GetDomain(tk.Session())
+	is := dom.InfoSchema()
+	for _, tblName := range []string{"t1", "t2"} {
+		tblInfo, err := is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr(tblName))
+		require.NoError(t, err)
+		tblInfo.Meta().TiFlashReplica = &model.TiFlashReplicaInfo{
+			Count:     1,
+			Available: true,
+		}
+	}
+	tk.MustExec("set @@tidb_allow_mpp=1; set @@tidb_enforce_mpp=1;")
+	tk.MustExec("set tidb_runtime_filter_mode=LOCAL;")
+	tk.MustExec("set tidb_runtime_filter_type='IN,BLOOM_FILTER';")
+	require.NoError(t, failpoint.Enable("github.com/pingcap/tidb/pkg/planner/core/mockPreferredBuildIndex", fmt.Sprintf(`return(%d)`, 0)))
+	defer func() {
+		require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/pkg/planner/core/mockPreferredBuildIndex"))
+	}()
+	hasRF := func(sql, rfType string) bool {
+		for _, row := range tk.MustQuery("explain " + sql).Rows() {
+			if strings.Contains(fmt.Sprint(row), "["+rfType+"]") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The keys of the build side filter out most of the probe side rows.
+	sql := "select * from t1, t2 where t1.k1 = t2.k1 and t1.k2 = 1"
+	require.True(t, hasRF(sql, "IN"))
+	require.True(t, hasRF(sql, "BLOOM_FILTER"))
+	// The keys of the build side cover the probe side rows, so only the IN runtime filter is generated.
+	sql = "select * from t1, t2 where t1.k1 = t2.k1"
+	require.True(t, hasRF(sql, "IN"))
+	require.False(t, hasRF(sql, "BLOOM_FILTER"))
+
+	tk.MustGetErrMsg("set tidb_runtime_filter_type='IN,BLOOM'",
+		"incorrect value: IN,BLOOM. tidb_runtime_filter_type should be sepreated by , such as IN, also we only support IN, MIN_MAX and BLOOM_FILTER now. ")
+}
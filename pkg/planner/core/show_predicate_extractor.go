@@ -21,7 +21,10 @@ import (
 
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
 	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/planner/core/operator/logicalop"
+	"github.com/pingcap/tidb/pkg/types"
 	driver "github.com/pingcap/tidb/pkg/types/parser_driver"
 	"github.com/pingcap/tidb/pkg/util/collate"
 	"github.com/pingcap/tidb/pkg/util/stringutil"
@@ -128,3 +131,50 @@ func (e *ShowBaseExtractor) FieldPatternLike() collate.WildcardPattern {
 	fieldPatternsLike.Compile(e.fieldPattern, byte('\\'))
 	return fieldPatternsLike
 }
+
+// extractShowStatsFilter extracts the names compared by equal conditions from the WHERE clause of
+// `show stats_buckets`, `show stats_topn` and `show stats_indexes`. It returns nil if no name can be filtered.
+func extractShowStatsFilter(where ast.ExprNode) *logicalop.ShowStatsFilter {
+	filter := &logicalop.ShowStatsFilter{}
+	extracted := false
+	for _, cond := range splitWhere(where) {
+		expr, ok := cond.(*ast.BinaryOperationExpr)
+		if !ok || expr.Op != opcode.EQ {
+			continue
+		}
+		col, ok := expr.L.(*ast.ColumnNameExpr)
+		val, isValue := expr.R.(*driver.ValueExpr)
+		if !ok || !isValue {
+			col, ok = expr.R.(*ast.ColumnNameExpr)
+			val, isValue = expr.L.(*driver.ValueExpr)
+			if !ok || !isValue {
+				continue
+			}
+		}
+		if val.Kind() != types.KindString {
+			continue
+		}
+		var name *string
+		switch col.Name.Name.L {
+		case "db_name":
+			name = &filter.DBName
+		case "table_name":
+			name = &filter.TableName
+		case "partition_name":
+			name = &filter.PartitionName
+		case "column_name":
+			name = &filter.ColumnName
+		default:
+			continue
+		}
+		// The conflicting conditions are left to the WHERE clause, which filters out all the rows.
+		if *name == "" {
+			*name = strings.ToLower(val.GetString())
+			extracted = true
+		}
+	}
+	if !extracted {
+		return nil
+	}
+	return filter
+}
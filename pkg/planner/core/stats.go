@@ -15,6 +15,7 @@
 package core
 
 import (
+	"cmp"
 	"fmt"
 	"math"
 	"slices"
@@ -28,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/ast"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/planner/cardinality"
 	"github.com/pingcap/tidb/pkg/planner/core/base"
@@ -42,6 +44,7 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics/asyncload"
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/chunk"
 	h "github.com/pingcap/tidb/pkg/util/hint"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"github.com/pingcap/tidb/pkg/util/ranger"
@@ -400,10 +403,28 @@ func detachCondAndBuildRangeForPath(
 			path.ConstCols[i] = res.ColumnValues[i] != nil
 		}
 	}
+	if path.Index.Tp == pmodel.IndexTypeHypo {
+		path.CountAfterAccess, err = getHypoIndexRowCount(sctx, histColl, path.AccessConds)
+		return err
+	}
 	path.CountAfterAccess, err = cardinality.GetRowCountByIndexRanges(sctx, histColl, path.Index.ID, path.Ranges)
 	return err
 }
 
+// getHypoIndexRowCount estimates the row count of the access conditions of the hypothetical index. The hypothetical
+// index has no stats since it's never built, so the row count is estimated by the stats of its columns instead of
+// the pseudo stats of the index.
+func getHypoIndexRowCount(sctx base.PlanContext, histColl *statistics.HistColl, accessConds []expression.Expression) (float64, error) {
+	if len(accessConds) == 0 {
+		return float64(histColl.RealtimeCount), nil
+	}
+	selectivity, _, err := cardinality.Selectivity(sctx, histColl, accessConds, nil)
+	if err != nil {
+		return 0, err
+	}
+	return float64(histColl.RealtimeCount) * selectivity, nil
+}
+
 func getMinSelectivityFromPaths(paths []*util.AccessPath, totalRowCount float64) float64 {
 	minSelectivity := 1.0
 	if totalRowCount <= 0 {
@@ -464,9 +485,313 @@ func getGroupNDVs(ds *logicalop.DataSource, colGroups [][]*expression.Column) []
 		}
 		return false
 	})
+	return appendExtStatsGroupNDVs(ds, colGroups, ndvs)
+}
+
+// getExtStatsFuncDeps collects the functional dependencies between the columns of the DataSource from the
+// dependency extended stats.
+func getExtStatsFuncDeps(ds *logicalop.DataSource) []statistics.FuncDependency {
+	extStats := ds.StatisticTable.ExtendedStats
+	if extStats == nil || len(extStats.Stats) == 0 {
+		return nil
+	}
+	var deps []statistics.FuncDependency
+	for _, item := range extStats.Stats {
+		if item.Tp != ast.StatsTypeDependency || len(item.ColIDs) != 2 {
+			continue
+		}
+		var determinant, dependent *expression.Column
+		for _, col := range ds.TblCols {
+			switch col.ID {
+			case item.ColIDs[0]:
+				determinant = col
+			case item.ColIDs[1]:
+				dependent = col
+			}
+		}
+		if determinant == nil || dependent == nil {
+			continue
+		}
+		deps = append(deps, statistics.FuncDependency{
+			Determinant: determinant.UniqueID,
+			Dependent:   dependent.UniqueID,
+			Degree:      item.ScalarVals,
+		})
+	}
+	// Stabilize the result since the extended stats are kept in a map.
+	slices.SortFunc(deps, func(a, b statistics.FuncDependency) int {
+		return cmp.Or(cmp.Compare(a.Determinant, b.Determinant), cmp.Compare(a.Dependent, b.Dependent))
+	})
+	return deps
+}
+
+// getExtStatsMultiColHists collects the multi-column histograms of the columns of the DataSource from the histogram
+// extended stats.
+func getExtStatsMultiColHists(ds *logicalop.DataSource) []statistics.MultiColHist {
+	extStats := ds.StatisticTable.ExtendedStats
+	if extStats == nil || len(extStats.Stats) == 0 {
+		return nil
+	}
+	var hists []statistics.MultiColHist
+	for name, item := range extStats.Stats {
+		if item.Tp != ast.StatsTypeHistogram || item.MultiColHist == nil || len(item.ColIDs) != 2 {
+			continue
+		}
+		hist := statistics.MultiColHist{Name: name, Hist: item.MultiColHist}
+		found := 0
+		for i, colID := range item.ColIDs {
+			colInfo := model.FindColumnInfoByID(ds.TableInfo.Columns, colID)
+			if colInfo == nil {
+				continue
+			}
+			for _, col := range ds.TblCols {
+				if col.ID == colID {
+					hist.Cols[i] = col.UniqueID
+					hist.ColNames[i] = colInfo.Name.O
+					found++
+					break
+				}
+			}
+		}
+		if found != 2 {
+			continue
+		}
+		hists = append(hists, hist)
+	}
+	// Stabilize the result since the extended stats are kept in a map.
+	slices.SortFunc(hists, func(a, b statistics.MultiColHist) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return hists
+}
+
+// getJSONPathStats collects the JSON path stats of the JSON columns of the DataSource.
+func getJSONPathStats(ds *logicalop.DataSource) map[int64]*statistics.JSONPathStats {
+	extStats := ds.StatisticTable.ExtendedStats
+	if extStats == nil || len(extStats.Stats) == 0 {
+		return nil
+	}
+	var ret map[int64]*statistics.JSONPathStats
+	for _, item := range extStats.Stats {
+		if item.Tp != ast.StatsTypeJSONPath || item.JSONPaths == nil || len(item.ColIDs) != 1 {
+			continue
+		}
+		for _, col := range ds.TblCols {
+			if col.ID != item.ColIDs[0] {
+				continue
+			}
+			if ret == nil {
+				ret = make(map[int64]*statistics.JSONPathStats)
+			}
+			ret[col.UniqueID] = item.JSONPaths
+			break
+		}
+	}
+	return ret
+}
+
+// getTrigramStats collects the trigram stats of the string columns of the DataSource.
+func getTrigramStats(ds *logicalop.DataSource) map[int64]*statistics.TrigramStats {
+	extStats := ds.StatisticTable.ExtendedStats
+	if extStats == nil || len(extStats.Stats) == 0 {
+		return nil
+	}
+	var ret map[int64]*statistics.TrigramStats
+	for _, item := range extStats.Stats {
+		if item.Tp != ast.StatsTypeTrigram || item.Trigrams == nil || len(item.ColIDs) != 1 {
+			continue
+		}
+		for _, col := range ds.TblCols {
+			if col.ID != item.ColIDs[0] {
+				continue
+			}
+			if ret == nil {
+				ret = make(map[int64]*statistics.TrigramStats)
+			}
+			ret[col.UniqueID] = item.Trigrams
+			break
+		}
+	}
+	return ret
+}
+
+// getIndexPrefixTopNs collects the TopN of the prefixes of the composite indexes of the DataSource. The ones whose
+// columns don't match the current index columns, e.g. the columns are modified after ANALYZE, are skipped.
+func getIndexPrefixTopNs(ds *logicalop.DataSource) map[int64]*statistics.IndexPrefixTopN {
+	extStats := ds.StatisticTable.ExtendedStats
+	if extStats == nil || len(extStats.Stats) == 0 {
+		return nil
+	}
+	var ret map[int64]*statistics.IndexPrefixTopN
+	for _, idx := range ds.TableInfo.Indices {
+		item, ok := extStats.Stats[statistics.IndexPrefixTopNName(idx.ID)]
+		if !ok || item.Tp != ast.StatsTypeIndexPrefixTopN || item.IndexPrefixTopN == nil || len(item.ColIDs) > len(idx.Columns) {
+			continue
+		}
+		matched := true
+		for i, colID := range item.ColIDs {
+			if ds.TableInfo.Columns[idx.Columns[i].Offset].ID != colID {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if ret == nil {
+			ret = make(map[int64]*statistics.IndexPrefixTopN)
+		}
+		ret[idx.ID] = item.IndexPrefixTopN
+	}
+	return ret
+}
+
+// getExtStatsColCorrelations collects the correlations between the columns of the DataSource from the
+// correlation extended stats. The ones collected for the composite indexes are always used, while the ones
+// created by users are only used when tidb_enable_extended_stats is on.
+func getExtStatsColCorrelations(ds *logicalop.DataSource) []statistics.ColCorrelation {
+	extStats := ds.StatisticTable.ExtendedStats
+	if extStats == nil || len(extStats.Stats) == 0 {
+		return nil
+	}
+	enableExtendedStats := ds.SCtx().GetSessionVars().EnableExtendedStats
+	var corrs []statistics.ColCorrelation
+	for name, item := range extStats.Stats {
+		if item.Tp != ast.StatsTypeCorrelation || len(item.ColIDs) != 2 {
+			continue
+		}
+		if !enableExtendedStats && !statistics.IsIndexColCorrelation(name) {
+			continue
+		}
+		var col1, col2 *expression.Column
+		for _, col := range ds.TblCols {
+			switch col.ID {
+			case item.ColIDs[0]:
+				col1 = col
+			case item.ColIDs[1]:
+				col2 = col
+			}
+		}
+		if col1 == nil || col2 == nil {
+			continue
+		}
+		corrs = append(corrs, statistics.ColCorrelation{
+			Col1: col1.UniqueID,
+			Col2: col2.UniqueID,
+			Corr: item.ScalarVals,
+		})
+	}
+	// Stabilize the result since the extended stats are kept in a map.
+	slices.SortFunc(corrs, func(a, b statistics.ColCorrelation) int {
+		return cmp.Or(cmp.Compare(a.Col1, b.Col1), cmp.Compare(a.Col2, b.Col2))
+	})
+	return corrs
+}
+
+// appendExtStatsGroupNDVs appends the joint NDVs of the column groups which are not matched by any index
+// from the cardinality extended stats. The group exactly covered by an extended stats uses its NDV directly,
+// otherwise the NDV is composed by getExtStatsPartialGroupNDV.
+func appendExtStatsGroupNDVs(ds *logicalop.DataSource, colGroups [][]*expression.Column, ndvs []property.GroupNDV) []property.GroupNDV {
+	if !ds.SCtx().GetSessionVars().EnableExtendedStats || ds.StatisticTable == nil {
+		return ndvs
+	}
+	extStats := ds.StatisticTable.ExtendedStats
+	if extStats == nil || len(extStats.Stats) == 0 {
+		return ndvs
+	}
+	for _, g := range colGroups {
+		if matched := slices.ContainsFunc(ndvs, func(ndv property.GroupNDV) bool {
+			return slices.EqualFunc(ndv.Cols, g, func(id int64, col *expression.Column) bool { return id == col.UniqueID })
+		}); matched {
+			continue
+		}
+		ndv, found := 0.0, false
+		for _, item := range extStats.Stats {
+			if item.Tp != ast.StatsTypeCardinality || len(item.ColIDs) != len(g) {
+				continue
+			}
+			covered := true
+			for _, col := range g {
+				if !slices.Contains(item.ColIDs, col.ID) {
+					covered = false
+					break
+				}
+			}
+			if covered {
+				ndv, found = item.ScalarVals, true
+				break
+			}
+		}
+		if !found {
+			ndv, found = getExtStatsPartialGroupNDV(ds, extStats, g)
+		}
+		if !found {
+			continue
+		}
+		cols := make([]int64, 0, len(g))
+		for _, col := range g {
+			cols = append(cols, col.UniqueID)
+		}
+		ndvs = append(ndvs, property.GroupNDV{
+			Cols: cols,
+			NDV:  ndv,
+		})
+	}
 	return ndvs
 }
 
+// getExtStatsPartialGroupNDV estimates the joint NDV of the column group which is partially covered by the
+// cardinality extended stats. The group is split into the disjoint subsets covered by the extended stats, which are
+// picked greedily from the largest ones, and the remaining columns. The subsets and the remaining columns are assumed
+// to be independent, so the joint NDV is the product of their NDVs, which is bounded by the row count and the max NDV
+// of the columns. It returns false if no extended stats covers more than one column of the group.
+func getExtStatsPartialGroupNDV(ds *logicalop.DataSource, extStats *statistics.ExtendedStatsColl, g []*expression.Column) (float64, bool) {
+	items := make([]*statistics.ExtendedStatsItem, 0, len(extStats.Stats))
+	for _, item := range extStats.Stats {
+		if item.Tp != ast.StatsTypeCardinality || len(item.ColIDs) < 2 || len(item.ColIDs) > len(g) {
+			continue
+		}
+		if slices.ContainsFunc(item.ColIDs, func(id int64) bool {
+			return !slices.ContainsFunc(g, func(col *expression.Column) bool { return col.ID == id })
+		}) {
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return 0, false
+	}
+	// Stabilize the result since the extended stats are kept in a map.
+	slices.SortFunc(items, func(a, b *statistics.ExtendedStatsItem) int {
+		return cmp.Or(cmp.Compare(len(b.ColIDs), len(a.ColIDs)), slices.Compare(a.ColIDs, b.ColIDs))
+	})
+	covered := make(map[int64]struct{}, len(g))
+	ndv := 1.0
+	for _, item := range items {
+		if slices.ContainsFunc(item.ColIDs, func(id int64) bool {
+			_, ok := covered[id]
+			return ok
+		}) {
+			continue
+		}
+		for _, id := range item.ColIDs {
+			covered[id] = struct{}{}
+		}
+		ndv *= max(item.ScalarVals, 1)
+	}
+	maxColNDV := 1.0
+	for _, col := range g {
+		colNDV := ds.TableStats.ColNDVs[col.UniqueID]
+		maxColNDV = max(maxColNDV, colNDV)
+		if _, ok := covered[col.ID]; ok {
+			continue
+		}
+		covered[col.ID] = struct{}{}
+		ndv *= max(colNDV, 1)
+	}
+	return max(min(ndv, ds.TableStats.RowCount), maxColNDV), true
+}
+
 // getTblInfoForUsedStatsByPhysicalID get table name, partition name and HintedTable that will be used to record used stats.
 func getTblInfoForUsedStatsByPhysicalID(sctx base.PlanContext, id int64) (fullName string, tblInfo *model.TableInfo) {
 	fullName = "tableID " + strconv.FormatInt(id, 10)
@@ -496,7 +821,11 @@ func initStats(ds *logicalop.DataSource, colGroups [][]*expression.Column) {
 		return
 	}
 	if ds.StatisticTable == nil {
-		ds.StatisticTable = getStatsTable(ds.SCtx(), ds.TableInfo, ds.PhysicalTableID)
+		if ds.IgnoreStats {
+			ds.StatisticTable = statistics.PseudoTable(ds.TableInfo, false, true)
+		} else {
+			ds.StatisticTable = getStatsTable(ds.SCtx(), ds.TableInfo, ds.PhysicalTableID)
+		}
 	}
 	tableStats := &property.StatsInfo{
 		RowCount:     float64(ds.StatisticTable.RealtimeCount),
@@ -507,6 +836,14 @@ func initStats(ds *logicalop.DataSource, colGroups [][]*expression.Column) {
 	if ds.StatisticTable.Pseudo {
 		tableStats.StatsVersion = statistics.PseudoVersion
 	}
+	if ds.SCtx().GetSessionVars().EnableExtendedStats {
+		tableStats.HistColl.FuncDeps = getExtStatsFuncDeps(ds)
+		tableStats.HistColl.MultiColHists = getExtStatsMultiColHists(ds)
+	}
+	tableStats.HistColl.ColCorrelations = getExtStatsColCorrelations(ds)
+	tableStats.HistColl.JSONPathStats = getJSONPathStats(ds)
+	tableStats.HistColl.TrigramStats = getTrigramStats(ds)
+	tableStats.HistColl.IndexPrefixTopNs = getIndexPrefixTopNs(ds)
 
 	statsRecord := ds.SCtx().GetSessionVars().StmtCtx.GetUsedStatsInfo(true)
 	name, tblInfo := getTblInfoForUsedStatsByPhysicalID(ds.SCtx(), ds.PhysicalTableID)
@@ -517,11 +854,15 @@ func initStats(ds *logicalop.DataSource, colGroups [][]*expression.Column) {
 		RealtimeCount:   tableStats.HistColl.RealtimeCount,
 		ModifyCount:     tableStats.HistColl.ModifyCount,
 		ColAndIdxStatus: ds.StatisticTable.ColAndIdxExistenceMap,
+		Outdated:        ds.StatisticTable.IsOutdated(),
 	})
 
 	for _, col := range ds.Schema().Columns {
 		tableStats.ColNDVs[col.UniqueID] = cardinality.EstimateColumnNDV(ds.StatisticTable, col.ID)
 	}
+	if ds.TableInfo.TempTableType != model.TempTableNone {
+		adjustTemporaryTableNDVs(ds, tableStats)
+	}
 	ds.TableStats = tableStats
 	ds.TableStats.GroupNDVs = getGroupNDVs(ds, colGroups)
 	ds.TblColHists = ds.StatisticTable.ID2UniqueID(ds.TblCols)
@@ -542,16 +883,53 @@ func initStats(ds *logicalop.DataSource, colGroups [][]*expression.Column) {
 	}
 }
 
+// adjustTemporaryTableNDVs replaces the NDVs of the temporary table estimated by the pseudo stats with the ones
+// collected by ANALYZE in the session.
+func adjustTemporaryTableNDVs(ds *logicalop.DataSource, tableStats *property.StatsInfo) {
+	s, rowCount, ok := statistics.GetTemporaryTableStats(ds.SCtx().GetSessionVars(), ds.TableInfo)
+	if !ok || rowCount == 0 {
+		return
+	}
+	for _, col := range ds.Schema().Columns {
+		if ndv, ok := s.ColumnNDV(col.ID, float64(rowCount)); ok {
+			tableStats.ColNDVs[col.UniqueID] = ndv
+		}
+	}
+}
+
 func deriveStatsByFilter(ds *logicalop.DataSource, conds expression.CNFExprs, filledPaths []*util.AccessPath) *property.StatsInfo {
 	if ds.SCtx().GetSessionVars().StmtCtx.EnableOptimizerDebugTrace {
 		debugtrace.EnterContextCommon(ds.SCtx())
 		defer debugtrace.LeaveContextCommon(ds.SCtx())
 	}
+	// The same CTE or subquery referenced several times has the same filters on the same table, so the selectivity
+	// is derived once in the statement. It's not cached when tracing the optimizer, which records the derivation.
+	stmtCtx := ds.SCtx().GetSessionVars().StmtCtx
+	cacheKey, cacheable := "", false
+	if !stmtCtx.EnableOptimizerDebugTrace {
+		cacheKey, cacheable = derivedSelectivityKey(ds, conds, filledPaths)
+	}
+	if cacheable {
+		if selectivity, ok := stmtCtx.DerivedSelectivities[cacheKey]; ok {
+			return ds.TableStats.Scale(selectivity)
+		}
+	}
+	// Use the stats of the analyzed virtual generated columns for the predicates on their expressions.
+	conds, _ = substituteVirtualColumns4Estimation(ds, conds, func(col *expression.Column) bool {
+		colStats := ds.StatisticTable.GetCol(col.ID)
+		return colStats != nil && colStats.IsAnalyzed()
+	})
 	selectivity, _, err := cardinality.Selectivity(ds.SCtx(), ds.TableStats.HistColl, conds, filledPaths)
 	if err != nil {
 		logutil.BgLogger().Debug("something wrong happened, use the default selectivity", zap.Error(err))
 		selectivity = cost.SelectionFactor
 	}
+	if cacheable {
+		if stmtCtx.DerivedSelectivities == nil {
+			stmtCtx.DerivedSelectivities = make(map[string]float64)
+		}
+		stmtCtx.DerivedSelectivities[cacheKey] = selectivity
+	}
 	// TODO: remove NewHistCollBySelectivity later on.
 	// if ds.SCtx().GetSessionVars().OptimizerSelectivityLevel >= 1 {
 	// Only '0' is suggested, see https://docs.pingcap.com/zh/tidb/stable/system-variables#tidb_optimizer_selectivity_level.
@@ -560,6 +938,64 @@ func deriveStatsByFilter(ds *logicalop.DataSource, conds expression.CNFExprs, fi
 	return ds.TableStats.Scale(selectivity)
 }
 
+// derivedSelectivityKey returns the key of the selectivity of the filters on the data source in the statement. The
+// columns are identified by their IDs in the table instead of the unique IDs, so the key is the same for the
+// references of the same CTE or subquery. It returns false if the filters can't be identified in this way.
+func derivedSelectivityKey(ds *logicalop.DataSource, conds expression.CNFExprs, filledPaths []*util.AccessPath) (string, bool) {
+	if ds.TableStats == nil || ds.TableStats.HistColl == nil || ds.StatisticTable == nil {
+		return "", false
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d:%d:%t:%v:", ds.PhysicalTableID, ds.StatisticTable.Version, ds.StatisticTable.Pseudo, ds.TableStats.RowCount)
+	for _, path := range filledPaths {
+		if path.IsTablePath() {
+			fmt.Fprintf(&sb, "t%d,", path.StoreType)
+		} else {
+			fmt.Fprintf(&sb, "i%d,", path.Index.ID)
+		}
+	}
+	evalCtx := ds.SCtx().GetExprCtx().GetEvalCtx()
+	for _, cond := range conds {
+		sb.WriteByte(';')
+		if !writeSelectivityExprKey(&sb, evalCtx, cond) {
+			return "", false
+		}
+	}
+	return sb.String(), true
+}
+
+func writeSelectivityExprKey(sb *strings.Builder, evalCtx expression.EvalContext, expr expression.Expression) bool {
+	switch x := expr.(type) {
+	case *expression.CorrelatedColumn:
+		return false
+	case *expression.Column:
+		if x.ID == 0 {
+			return false
+		}
+		fmt.Fprintf(sb, "c%d", x.ID)
+	case *expression.Constant:
+		val, err := x.Eval(evalCtx, chunk.Row{})
+		if err != nil {
+			return false
+		}
+		fmt.Fprintf(sb, "%s(%s)", x.RetType.String(), val.String())
+	case *expression.ScalarFunction:
+		fmt.Fprintf(sb, "%s#%d(", x.FuncName.L, x.Function.PbCode())
+		for i, arg := range x.GetArgs() {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			if !writeSelectivityExprKey(sb, evalCtx, arg) {
+				return false
+			}
+		}
+		fmt.Fprintf(sb, ")%s", x.RetType.String())
+	default:
+		return false
+	}
+	return true
+}
+
 // We bind logic of derivePathStats and tryHeuristics together. When some path matches the heuristic rule, we don't need
 // to derive stats of subsequent paths. In this way we can save unnecessary computation of derivePathStats.
 func derivePathStatsAndTryHeuristics(ds *logicalop.DataSource) error {
@@ -740,6 +1176,7 @@ func loadTableStats(ctx sessionctx.Context, tblInfo *model.TableInfo, pid int64)
 		RealtimeCount: tableStats.HistColl.RealtimeCount,
 		ModifyCount:   tableStats.HistColl.ModifyCount,
 		Version:       tableStats.Version,
+		Outdated:      tableStats.IsOutdated(),
 	}
 	if tableStats.Pseudo {
 		usedStats.Version = statistics.PseudoVersion
@@ -0,0 +1,200 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/planner/core/base"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/util/dbterror/plannererrors"
+)
+
+// jsonTableStatsOverlay is the stats of a table in `EXPLAIN WITH STATS ('<json>')`, whose fields are named after the
+// ones of the dumped stats. The absent fields keep the stats in the stats cache. The NDVs and the null counts are the
+// ones of the analyzed stats, which are scaled to the row count of the table like the analyzed ones.
+type jsonTableStatsOverlay struct {
+	Count       *int64                             `json:"count"`
+	ModifyCount *int64                             `json:"modify_count"`
+	Columns     map[string]*jsonColumnStatsOverlay `json:"columns"`
+	Indices     map[string]*jsonIndexStatsOverlay  `json:"indices"`
+}
+
+type jsonColumnStatsOverlay struct {
+	NDV       *int64 `json:"ndv"`
+	NullCount *int64 `json:"null_count"`
+}
+
+type jsonIndexStatsOverlay struct {
+	NDV *int64 `json:"ndv"`
+}
+
+// tableStatsOverlay is the stats overlaid on the stats of a table, whose columns and indexes are resolved to IDs.
+type tableStatsOverlay struct {
+	name        string
+	count       *int64
+	modifyCount *int64
+	columns     map[int64]*jsonColumnStatsOverlay
+	indices     map[int64]*jsonIndexStatsOverlay
+}
+
+// statsOverlay is the stats overlaid on the stats cache by `EXPLAIN WITH STATS`, keyed by the table ID. It's kept in
+// StatementContext.StatsOverlay during the planning of the explained statement.
+type statsOverlay map[int64]*tableStatsOverlay
+
+// buildStatsOverlay parses the stats of `EXPLAIN WITH STATS ('<json>')`, which is a JSON object keyed by `db.table`
+// or `table` of the current database, e.g.
+//
+//	{"test.t": {"count": 1000000, "columns": {"a": {"ndv": 10, "null_count": 0}}, "indices": {"idx_a": {"ndv": 10}}}}
+//
+// The tables, columns and indexes must exist, and the SELECT privilege is required on the tables.
+func (b *PlanBuilder) buildStatsOverlay(str string) (statsOverlay, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(str)))
+	dec.DisallowUnknownFields()
+	var tables map[string]*jsonTableStatsOverlay
+	if err := dec.Decode(&tables); err != nil {
+		return nil, errors.Errorf("invalid stats of EXPLAIN WITH STATS: %v", err)
+	}
+	overlay := make(statsOverlay, len(tables))
+	for name, tbl := range tables {
+		if tbl == nil {
+			continue
+		}
+		dbName, tblName, ok := strings.Cut(name, ".")
+		if !ok {
+			dbName, tblName = b.ctx.GetSessionVars().CurrentDB, name
+			if dbName == "" {
+				return nil, plannererrors.ErrNoDB
+			}
+		}
+		tblInfo, err := b.is.TableInfoByName(pmodel.NewCIStr(dbName), pmodel.NewCIStr(tblName))
+		if err != nil {
+			return nil, err
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName, tblInfo.Name.L, "", nil)
+		if err := checkStatsOverlayCount(name, "count", tbl.Count); err != nil {
+			return nil, err
+		}
+		if err := checkStatsOverlayCount(name, "modify_count", tbl.ModifyCount); err != nil {
+			return nil, err
+		}
+		tblOverlay := &tableStatsOverlay{
+			name:        dbName + "." + tblInfo.Name.O,
+			count:       tbl.Count,
+			modifyCount: tbl.ModifyCount,
+			columns:     make(map[int64]*jsonColumnStatsOverlay, len(tbl.Columns)),
+			indices:     make(map[int64]*jsonIndexStatsOverlay, len(tbl.Indices)),
+		}
+		for colName, col := range tbl.Columns {
+			colInfo := model.FindColumnInfo(tblInfo.Cols(), colName)
+			if colInfo == nil {
+				return nil, plannererrors.ErrUnknownColumn.GenWithStackByArgs(colName, name)
+			}
+			if col == nil {
+				continue
+			}
+			if err := checkStatsOverlayCount(name+"."+colName, "ndv", col.NDV); err != nil {
+				return nil, err
+			}
+			if err := checkStatsOverlayCount(name+"."+colName, "null_count", col.NullCount); err != nil {
+				return nil, err
+			}
+			tblOverlay.columns[colInfo.ID] = col
+		}
+		for idxName, idx := range tbl.Indices {
+			idxInfo := tblInfo.FindIndexByName(strings.ToLower(idxName))
+			if idxInfo == nil {
+				return nil, plannererrors.ErrKeyDoesNotExist.GenWithStackByArgs(idxName, tblInfo.Name)
+			}
+			if idx == nil {
+				continue
+			}
+			if err := checkStatsOverlayCount(name+"."+idxName, "ndv", idx.NDV); err != nil {
+				return nil, err
+			}
+			tblOverlay.indices[idxInfo.ID] = idx
+		}
+		overlay[tblInfo.ID] = tblOverlay
+	}
+	return overlay, nil
+}
+
+func checkStatsOverlayCount(name, field string, val *int64) error {
+	if val != nil && *val < 0 {
+		return errors.Errorf("invalid stats of EXPLAIN WITH STATS: %s of %s is negative", field, name)
+	}
+	return nil
+}
+
+// overlayStatsTable returns a copy of the stats of the table overlaid by `EXPLAIN WITH STATS`, or the stats
+// themselves if the table is not overlaid. The stats in the stats cache are never modified. The stats of the
+// partitions in the static prune mode are not overlaid, since the overlay is the stats of the whole table.
+func overlayStatsTable(ctx base.PlanContext, tblInfo *model.TableInfo, pid int64, statsTbl *statistics.Table) *statistics.Table {
+	stmtCtx := ctx.GetSessionVars().StmtCtx
+	overlay, ok := stmtCtx.StatsOverlay.(statsOverlay)
+	if !ok {
+		return statsTbl
+	}
+	tblOverlay, ok := overlay[tblInfo.ID]
+	if !ok {
+		return statsTbl
+	}
+	if pid != tblInfo.ID && !stmtCtx.UseDynamicPartitionPrune() {
+		stmtCtx.AppendWarning(errors.NewNoStackErrorf("the stats of the partitions of %s are not overlaid in the static prune mode",
+			tblOverlay.name))
+		return statsTbl
+	}
+	statsTbl = statsTbl.Copy()
+	if tblOverlay.count != nil {
+		statsTbl.RealtimeCount = *tblOverlay.count
+	}
+	if tblOverlay.modifyCount != nil {
+		statsTbl.ModifyCount = *tblOverlay.modifyCount
+	}
+	for id, colOverlay := range tblOverlay.columns {
+		col := statsTbl.GetCol(id)
+		if col == nil || !col.IsAnalyzed() {
+			colInfo := model.FindColumnInfoByID(tblInfo.Columns, id)
+			stmtCtx.AppendWarning(errors.NewNoStackErrorf("the column %s of %s is not analyzed, its stats are not overlaid",
+				colInfo.Name.O, tblOverlay.name))
+			continue
+		}
+		if colOverlay.NDV != nil {
+			col.Histogram.NDV = *colOverlay.NDV
+		}
+		if colOverlay.NullCount != nil {
+			col.Histogram.NullCount = *colOverlay.NullCount
+		}
+	}
+	for id, idxOverlay := range tblOverlay.indices {
+		idx := statsTbl.GetIdx(id)
+		if idx == nil || !idx.IsAnalyzed() {
+			idxInfo := tblInfo.FindIndexByID(id)
+			stmtCtx.AppendWarning(errors.NewNoStackErrorf("the index %s of %s is not analyzed, its stats are not overlaid",
+				idxInfo.Name.O, tblOverlay.name))
+			continue
+		}
+		if idxOverlay.NDV != nil {
+			idx.Histogram.NDV = *idxOverlay.NDV
+		}
+	}
+	return statsTbl
+}
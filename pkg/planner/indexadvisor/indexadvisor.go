@@ -43,6 +43,8 @@ type Option struct {
 	MaxNumQuery   int
 	Timeout       time.Duration
 	SpecifiedSQLs []string
+	// SQLDigest is the digest of the query in the statement summary to recommend indexes for.
+	SQLDigest string
 }
 
 // AdviseIndexes is the entry point for the index advisor.
@@ -58,6 +60,19 @@ func AdviseIndexes(ctx context.Context, sctx sessionctx.Context,
 	return adviseIndexesWithOption(ctx, sctx, option)
 }
 
+// AdviseIndexesForDigest recommends indexes for the query of the digest, whose text and schema are loaded from the
+// statement summary.
+func AdviseIndexesForDigest(ctx context.Context, sctx sessionctx.Context,
+	sqlDigest string, userOptions []ast.RecommendIndexOption) ([]*Recommendation, error) {
+	option := &Option{SQLDigest: sqlDigest}
+	if err := fillOption(sctx, option, userOptions); err != nil {
+		advisorLogger().Error("fill index advisor option failed", zap.Error(err))
+		return nil, err
+	}
+
+	return adviseIndexesWithOption(ctx, sctx, option)
+}
+
 func adviseIndexesWithOption(ctx context.Context, sctx sessionctx.Context,
 	option *Option) (results []*Recommendation, err error) {
 	if ctx == nil || sctx == nil || option == nil {
@@ -117,6 +132,14 @@ func prepareQuerySet(ctx context.Context, sctx sessionctx.Context,
 		for _, sql := range option.SpecifiedSQLs {
 			querySet.Add(Query{SchemaName: defaultDB, Text: sql, Frequency: 1})
 		}
+	} else if option.SQLDigest != "" { // if the digest of the target query is specified
+		var err error
+		if querySet, err = loadQuerySetFromStmtSummaryByDigest(sctx, option.SQLDigest); err != nil {
+			return nil, err
+		}
+		if querySet.Size() == 0 {
+			return nil, fmt.Errorf("can't find the query of digest %s from statements_summary", option.SQLDigest)
+		}
 	} else {
 		if intest.InTest && ctx.Value(TestKey("query_set")) != nil {
 			querySet = ctx.Value(TestKey("query_set")).(s.Set[Query])
@@ -183,6 +206,29 @@ func loadQuerySetFromStmtSummary(sctx sessionctx.Context, option *Option) (s.Set
 	return querySet, nil
 }
 
+func loadQuerySetFromStmtSummaryByDigest(sctx sessionctx.Context, sqlDigest string) (s.Set[Query], error) {
+	template := `SELECT any_value(ifnull(schema_name, "")) as schema_name,
+				any_value(query_sample_text) as query_sample_text,
+				sum(cast(exec_count as double)) as exec_count
+			FROM information_schema.statements_summary_history
+			WHERE digest = %? AND prepared = 0
+			GROUP BY digest`
+	rows, err := exec(sctx, template, sqlDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	querySet := s.NewSet[Query]()
+	for _, r := range rows {
+		querySet.Add(Query{
+			SchemaName: r.GetString(0),
+			Text:       r.GetString(1),
+			Frequency:  max(int(r.GetFloat64(2)), 1),
+		})
+	}
+	return querySet, nil
+}
+
 func prepareRecommendation(indexes s.Set[Index], queries s.Set[Query], optimizer Optimizer) ([]*Recommendation, error) {
 	advisorLogger().Info("recommend index", zap.Int("num-index", indexes.Size()))
 	results := make([]*Recommendation, 0, indexes.Size())
@@ -268,6 +314,10 @@ func prepareRecommendation(indexes s.Set[Index], queries s.Set[Query], optimizer
 			fmt.Sprintf(`Column %v appear in Equal or Range Predicate clause(s) in query: %v`, cols, normText)
 		results = append(results, indexResult)
 	}
+	// rank the indexes by their improvements on the workload.
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].WorkloadImpact.WorkloadImprovement > results[j].WorkloadImpact.WorkloadImprovement
+	})
 	return results, nil
 }
 
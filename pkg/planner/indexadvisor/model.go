@@ -162,3 +162,17 @@ type Recommendation struct {
 	WorkloadImpact     *WorkloadImpact
 	TopImpactedQueries []*ImpactedQuery
 }
+
+// CreateIndexStmt returns the statement to create the recommended index.
+func (r *Recommendation) CreateIndexStmt() string {
+	cols := make([]string, 0, len(r.IndexColumns))
+	for _, col := range r.IndexColumns {
+		cols = append(cols, quoteIdentifier(col))
+	}
+	return fmt.Sprintf("CREATE INDEX %s ON %s.%s(%s)", quoteIdentifier(r.IndexName),
+		quoteIdentifier(r.Database), quoteIdentifier(r.Table), strings.Join(cols, ", "))
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
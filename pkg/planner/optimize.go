@@ -73,6 +73,7 @@ func getPlanFromNonPreparedPlanCache(ctx context.Context, sctx sessionctx.Contex
 		stmtCtx.InPreparedPlanBuilding || // already in cached plan rebuilding phase
 		stmtCtx.EnableOptimizerCETrace || stmtCtx.EnableOptimizeTrace || // in trace
 		stmtCtx.InRestrictedSQL || // is internal SQL
+		stmtCtx.StatsOverlay != nil || // explain with stats
 		isExplain || // explain external
 		!sctx.GetSessionVars().DisableTxnAutoRetry || // txn-auto-retry
 		sctx.GetSessionVars().InMultiStmts || // in multi-stmt
@@ -141,6 +142,17 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node *resolve.NodeW,
 		defer debugtrace.LeaveContextCommon(pctx)
 	}
 
+	// The instance read-only mode is used to fence the instance, so no privilege can skip the check.
+	if !sessVars.InRestrictedSQL && variable.InstanceReadOnly.Load() {
+		allowed, err := allowStmtInReadOnlyMode(pctx, node.Node)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !allowed {
+			return nil, nil, errors.Trace(plannererrors.ErrInstanceReadOnly)
+		}
+	}
+
 	if !sessVars.InRestrictedSQL && (variable.RestrictedReadOnly.Load() || variable.VarTiDBSuperReadOnly.Load()) {
 		allowed, err := allowInReadOnlyMode(pctx, node.Node)
 		if err != nil {
@@ -424,12 +436,17 @@ func allowInReadOnlyMode(sctx planctx.PlanContext, node ast.Node) (bool, error)
 	if pm.HasExplicitlyGrantedDynamicPrivilege(roles, "RESTRICTED_REPLICA_WRITER_ADMIN", false) {
 		return true, nil
 	}
+	return allowStmtInReadOnlyMode(sctx, node)
+}
 
+// allowStmtInReadOnlyMode checks whether the statement is allowed in the read-only mode regardless of the privileges.
+func allowStmtInReadOnlyMode(sctx planctx.PlanContext, node ast.Node) (bool, error) {
 	switch node.(type) {
 	// allow change variables (otherwise can't unset read-only mode)
 	case *ast.SetStmt,
 		// allow analyze table
 		*ast.AnalyzeTableStmt,
+		*ast.AnalyzeDatabaseStmt,
 		*ast.UseStmt,
 		*ast.ShowStmt,
 		*ast.CreateBindingStmt,
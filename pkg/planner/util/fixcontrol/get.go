@@ -71,6 +71,9 @@ const (
 	Fix52869 uint64 = 52869
 	// Fix54337 controls whether to apply or not range intersection for index access.
 	Fix54337 uint64 = 54337
+	// Fix56318 controls whether to estimate the equi-joins on a single numeric key by joining the histograms and TopN
+	// of the join keys. Turn it off to fall back to the NDV-based containment assumption.
+	Fix56318 uint64 = 56318
 )
 
 // GetStr fetches the given key from the fix control map as a string type.
@@ -85,15 +85,16 @@ func NewChecker(
 	return c
 }
 
-// DeriveChecker derives a RunawayChecker from the given resource group
-func (rm *Manager) DeriveChecker(resourceGroupName, originalSQL, sqlDigest, planDigest string, startTime time.Time) *Checker {
+// DeriveChecker derives a RunawayChecker from the given resource group.
+// Only the normal statements, which have plan digests, are checked unless force is set, e.g. for ANALYZE.
+func (rm *Manager) DeriveChecker(resourceGroupName, originalSQL, sqlDigest, planDigest string, startTime time.Time, force bool) *Checker {
 	group, err := rm.ResourceGroupCtl.GetResourceGroup(resourceGroupName)
 	if err != nil || group == nil {
 		logutil.BgLogger().Warn("cannot setup up runaway checker", zap.Error(err))
 		return nil
 	}
 	// Only check the normal statement.
-	if len(planDigest) == 0 {
+	if len(planDigest) == 0 && !force {
 		return nil
 	}
 	rm.ActiveLock.RLock()
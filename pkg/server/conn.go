@@ -1555,6 +1555,9 @@ func (cc *clientConn) writeError(ctx context.Context, e error) error {
 
 	cc.lastCode = m.Code
 	defer errno.IncrementError(m.Code, cc.user, cc.peerHost)
+	if tidbCtx := cc.getCtx(); tidbCtx != nil && tidbCtx.GetSessionVars().EnableErrorAttributes {
+		m.Message = fmt.Sprintf("%s [%s]", m.Message, errno.GetErrorAttributes(m.Code))
+	}
 	data := cc.alloc.AllocWithLen(4, 16+len(m.Message))
 	data = append(data, mysql.ErrHeader)
 	data = append(data, byte(m.Code), byte(m.Code>>8))
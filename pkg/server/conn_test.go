@@ -1805,6 +1805,33 @@ func TestOkEof(t *testing.T) {
 	require.Equal(t, []byte{0x7, 0x0, 0x0, 0x1, 0xfe, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0}, outBuffer.Bytes())
 }
 
+func TestErrorAttributes(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+
+	var outBuffer bytes.Buffer
+	cc := &clientConn{
+		pkt:        internal.NewPacketIOForTest(bufio.NewWriter(&outBuffer)),
+		alloc:      arena.NewAllocator(512),
+		chunkAlloc: chunk.NewAllocator(),
+		capability: mysql.ClientProtocol41,
+	}
+	tk := testkit.NewTestKit(t, store)
+	cc.SetCtx(&TiDBContext{Session: tk.Session()})
+
+	// The attributes are not appended by default.
+	require.NoError(t, cc.writeError(context.Background(), domain.ErrInfoSchemaChanged))
+	require.NotContains(t, outBuffer.String(), "retryable=")
+
+	tk.MustExec("set @@tidb_enable_error_attributes = on")
+	outBuffer.Reset()
+	require.NoError(t, cc.writeError(context.Background(), domain.ErrInfoSchemaChanged))
+	require.True(t, strings.HasSuffix(outBuffer.String(), "[retryable=true, conflict_class=schema_change]"))
+
+	outBuffer.Reset()
+	require.NoError(t, cc.writeError(context.Background(), exeerrors.ErrQueryInterrupted))
+	require.True(t, strings.HasSuffix(outBuffer.String(), "[retryable=false, conflict_class=none]"))
+}
+
 func TestExtensionChangeUser(t *testing.T) {
 	defer extension.Reset()
 	extension.Reset()
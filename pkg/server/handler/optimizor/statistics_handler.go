@@ -22,11 +22,15 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/domain"
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/server/handler"
+	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/logutil"
@@ -49,7 +53,7 @@ func (sh *StatsHandler) Domain() *domain.Domain {
 	return sh.do
 }
 
-// ServeHTTP dumps the statistics to json.
+// ServeHTTP dumps the statistics to json, or to the binary format if the format=binary is specified.
 func (sh StatsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -70,6 +74,15 @@ func (sh StatsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	tbl, err := is.TableByName(context.Background(), model.NewCIStr(params[handler.DBName]), model.NewCIStr(params[handler.TableName]))
 	if err != nil {
 		handler.WriteError(w, err)
+	} else if req.URL.Query().Get(handler.StatsFormat) == handler.StatsFormatBinary {
+		// The binary stats are streamed to the response, so it doesn't hold the stats of all the partitions in memory.
+		w.Header().Set(handler.HeaderContentType, handler.ContentTypeOctet)
+		if err := h.DumpStatsToBinary(w, params[handler.DBName], tbl.Meta(), 0, dumpPartitionStats); err != nil {
+			// The loader can detect the truncated data because the end frame is missing.
+			logutil.BgLogger().Warn("dump binary stats failed", zap.String("db", params[handler.DBName]),
+				zap.String("table", params[handler.TableName]), zap.Error(err))
+			handler.WriteError(w, err)
+		}
 	} else {
 		js, err := h.DumpStatsToJSON(params[handler.DBName], tbl.Meta(), nil, dumpPartitionStats)
 		if err != nil {
@@ -167,3 +180,221 @@ func (sh StatsPriorityQueueHandler) ServeHTTP(w http.ResponseWriter, _ *http.Req
 		handler.WriteData(w, tables)
 	}
 }
+
+// StatsRunningJobsHandler is the handler for dumping the auto analyze jobs being run by the current instance.
+type StatsRunningJobsHandler struct {
+	do *domain.Domain
+}
+
+// NewStatsRunningJobsHandler creates a new StatsRunningJobsHandler.
+func NewStatsRunningJobsHandler(do *domain.Domain) *StatsRunningJobsHandler {
+	return &StatsRunningJobsHandler{do: do}
+}
+
+// ServeHTTP dumps the running auto analyze jobs to json.
+func (sh StatsRunningJobsHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	handler.WriteData(w, sh.do.StatsHandle().GetRunningAnalysisJobs())
+}
+
+// StatsHealthHandler is the handler for dumping the health of the stats of a table and its partitions.
+type StatsHealthHandler struct {
+	do *domain.Domain
+}
+
+// NewStatsHealthHandler creates a new StatsHealthHandler.
+func NewStatsHealthHandler(do *domain.Domain) *StatsHealthHandler {
+	return &StatsHealthHandler{do: do}
+}
+
+// ServeHTTP dumps the health of the stats of the table to json.
+func (sh StatsHealthHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := mux.Vars(req)
+	tbl, err := sh.do.InfoSchema().TableByName(context.Background(), model.NewCIStr(params[handler.DBName]), model.NewCIStr(params[handler.TableName]))
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	handler.WriteData(w, sh.do.StatsHandle().GetTableStatsHealth(params[handler.DBName], tbl.Meta()))
+}
+
+// StatsIndicatorsHandler is the handler for dumping the indicators of tables,
+// which are used to evaluate the analysis priority of tables.
+type StatsIndicatorsHandler struct {
+	do *domain.Domain
+}
+
+// NewStatsIndicatorsHandler creates a new StatsIndicatorsHandler.
+func NewStatsIndicatorsHandler(do *domain.Domain) *StatsIndicatorsHandler {
+	return &StatsIndicatorsHandler{do: do}
+}
+
+// ServeHTTP dumps the indicators of tables to json.
+func (sh StatsIndicatorsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := mux.Vars(req)
+	h := sh.do.StatsHandle()
+	indicators, err := h.GetTableIndicators(params[handler.DBName], params[handler.TableName])
+	if err != nil {
+		handler.WriteError(w, err)
+	} else {
+		handler.WriteData(w, indicators)
+	}
+}
+
+// StatsSummaryHandler is the handler for fetching the summary of the stats of a table or a partition by its ID,
+// which is for the components only knowing the physical table IDs, like TiFlash.
+type StatsSummaryHandler struct {
+	do *domain.Domain
+}
+
+// NewStatsSummaryHandler creates a new StatsSummaryHandler.
+func NewStatsSummaryHandler(do *domain.Domain) *StatsSummaryHandler {
+	return &StatsSummaryHandler{do: do}
+}
+
+// ServeHTTP dumps the summary of the stats of the table to json.
+func (sh StatsSummaryHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := mux.Vars(req)
+	physicalID, err := strconv.ParseInt(params[handler.TableID], 10, 64)
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	summary, err := sh.do.StatsHandle().GetTableStatsSummary(sh.do.InfoSchema(), physicalID)
+	if err != nil {
+		handler.WriteError(w, err)
+	} else {
+		handler.WriteData(w, summary)
+	}
+}
+
+const (
+	// AutoAnalyzeOpPause pauses auto analyze of the cluster.
+	AutoAnalyzeOpPause = "pause"
+	// AutoAnalyzeOpResume resumes auto analyze of the cluster.
+	AutoAnalyzeOpResume = "resume"
+	// AutoAnalyzeOpEnqueue pushes the analysis job of a table or a partition into the priority queue.
+	AutoAnalyzeOpEnqueue = "enqueue"
+	// AutoAnalyzeOpJobs fetches the outcomes of the latest auto analyze jobs.
+	AutoAnalyzeOpJobs = "jobs"
+
+	defAutoAnalyzeJobsLimit = 100
+	maxAutoAnalyzeJobsLimit = 1000
+)
+
+// StatsAutoAnalyzeHandler is the handler for the external schedulers to control auto analyze,
+// so that they can coordinate auto analyze with their own maintenance tasks.
+// Like other APIs of the status port, the callers are authenticated by the client certificates
+// if cluster-verify-cn is configured.
+type StatsAutoAnalyzeHandler struct {
+	do *domain.Domain
+	op string
+}
+
+// NewStatsAutoAnalyzeHandler creates a new StatsAutoAnalyzeHandler for the operation.
+func NewStatsAutoAnalyzeHandler(do *domain.Domain, op string) *StatsAutoAnalyzeHandler {
+	return &StatsAutoAnalyzeHandler{do: do, op: op}
+}
+
+// ServeHTTP handles the request of the operation.
+func (sh StatsAutoAnalyzeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if sh.op != AutoAnalyzeOpJobs && req.Method != http.MethodPost {
+		handler.WriteError(w, errors.Errorf("This api only support POST method"))
+		return
+	}
+	switch sh.op {
+	case AutoAnalyzeOpPause:
+		sh.setAutoAnalyzeEnabled(w, req, false)
+	case AutoAnalyzeOpResume:
+		sh.setAutoAnalyzeEnabled(w, req, true)
+	case AutoAnalyzeOpEnqueue:
+		sh.enqueue(w, req)
+	case AutoAnalyzeOpJobs:
+		sh.jobs(w, req)
+	default:
+		handler.WriteError(w, errors.Errorf("unknown auto analyze operation %s", sh.op))
+	}
+}
+
+// setAutoAnalyzeEnabled sets tidb_enable_auto_analyze, which takes effect on the stats owner once the global
+// variable is synced. The running jobs are not interrupted.
+func (sh StatsAutoAnalyzeHandler) setAutoAnalyzeEnabled(w http.ResponseWriter, req *http.Request, enabled bool) {
+	value := variable.Off
+	if enabled {
+		value = variable.On
+	}
+	err := statsutil.CallWithSCtx(sh.do.SysSessionPool(), func(sctx sessionctx.Context) error {
+		return sctx.GetSessionVars().GlobalVarsAccessor.SetGlobalSysVar(req.Context(), variable.TiDBEnableAutoAnalyze, value)
+	})
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	logutil.BgLogger().Info("set auto analyze by the http api", zap.Bool("enabled", enabled))
+	handler.WriteData(w, map[string]bool{"enabled": enabled})
+}
+
+// enqueue pushes the analysis job of the table, or its partition if the partition is specified, into the priority
+// queue at the given priority. It fails if the current instance is not the stats owner.
+func (sh StatsAutoAnalyzeHandler) enqueue(w http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	tbl, err := sh.do.InfoSchema().TableByName(req.Context(), model.NewCIStr(params[handler.DBName]), model.NewCIStr(params[handler.TableName]))
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	tblInfo := tbl.Meta()
+	physicalID := tblInfo.ID
+	if partitionName := req.FormValue(handler.Partition); partitionName != "" {
+		pi := tblInfo.GetPartitionInfo()
+		if pi == nil {
+			handler.WriteError(w, errors.Errorf("table %s is not partitioned", tblInfo.Name.O))
+			return
+		}
+		physicalID = pi.GetPartitionIDByName(partitionName)
+		if physicalID == -1 {
+			handler.WriteError(w, errors.Errorf("unknown partition %s of table %s", partitionName, tblInfo.Name.O))
+			return
+		}
+	}
+	var opts []statstypes.AnalysisJobOption
+	if priority := req.FormValue(handler.Priority); priority != "" {
+		weight, err := strconv.ParseFloat(priority, 64)
+		if err != nil || weight <= 0 {
+			handler.WriteError(w, errors.Errorf("priority must be a positive number"))
+			return
+		}
+		opts = append(opts, statstypes.WithAnalysisJobWeight(weight))
+	}
+	if err := sh.do.StatsHandle().EnqueueAnalysisJob(physicalID, opts...); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	handler.WriteData(w, map[string]int64{"physical_id": physicalID})
+}
+
+// jobs fetches the outcomes of the latest auto analyze jobs of the cluster, which can be filtered by db and table.
+func (sh StatsAutoAnalyzeHandler) jobs(w http.ResponseWriter, req *http.Request) {
+	limit := defAutoAnalyzeJobsLimit
+	if limitValue := req.FormValue(handler.Limit); limitValue != "" {
+		var err error
+		limit, err = strconv.Atoi(limitValue)
+		if err != nil || limit < 1 || limit > maxAutoAnalyzeJobsLimit {
+			handler.WriteError(w, errors.Errorf("limit must be greater than 0 and less than or equal to %d", maxAutoAnalyzeJobsLimit))
+			return
+		}
+	}
+	outcomes, err := sh.do.StatsHandle().GetAutoAnalyzeJobOutcomes(req.FormValue(handler.DBName), req.FormValue(handler.TableName), limit)
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	handler.WriteData(w, outcomes)
+}
@@ -345,3 +345,292 @@ func TestStatsPriorityQueueAPI(t *testing.T) {
 	require.Empty(t, snapshot.CurrentJobs)
 	require.Empty(t, snapshot.MustRetryTables)
 }
+
+func TestStatsIndicatorsAPI(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	driver := server2.NewTiDBDriver(store)
+	client := testserverclient.NewTestServerClient()
+	cfg := util.NewTestConfig()
+	cfg.Port = client.Port
+	cfg.Status.StatusPort = client.StatusPort
+	cfg.Status.ReportStatus = true
+	cfg.Socket = fmt.Sprintf("/tmp/tidb-mock-%d.sock", time.Now().UnixNano())
+
+	server, err := server2.NewServer(cfg, driver)
+	require.NoError(t, err)
+	defer server.Close()
+
+	dom, err := session.GetDomain(store)
+	require.NoError(t, err)
+	server.SetDomain(dom)
+	go func() {
+		err := server.Run(nil)
+		require.NoError(t, err)
+	}()
+	<-server2.RunInGoTestChan
+	client.Port = testutil.GetPortFromTCPAddr(server.ListenAddr())
+	client.StatusPort = testutil.GetPortFromTCPAddr(server.StatusListenerAddr())
+	client.WaitUntilServerOnline()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database indicators_db")
+	tk.MustExec("use indicators_db")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("insert into t1 values (1), (2)")
+	h := dom.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t1")
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+
+	fetch := func(path string) []types.TableIndicators {
+		resp, err := client.FetchStatus(path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		js, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var indicators []types.TableIndicators
+		require.NoError(t, json.Unmarshal(js, &indicators), string(js))
+		return indicators
+	}
+
+	indicators := fetch("/stats/indicators/indicators_db/t1")
+	require.Len(t, indicators, 1)
+	require.Equal(t, "indicators_db", indicators[0].DBName)
+	require.Equal(t, "t1", indicators[0].TableName)
+	require.True(t, indicators[0].Analyzed)
+	require.Len(t, fetch("/stats/indicators/indicators_db"), 2)
+	require.Len(t, fetch("/stats/indicators"), 2)
+
+	resp, err := client.FetchStatus("/stats/indicators/not_exist")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	js, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "[schema:1049]Unknown database 'not_exist'", string(js))
+}
+
+func TestStatsSummaryAPI(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	driver := server2.NewTiDBDriver(store)
+	client := testserverclient.NewTestServerClient()
+	cfg := util.NewTestConfig()
+	cfg.Port = client.Port
+	cfg.Status.StatusPort = client.StatusPort
+	cfg.Status.ReportStatus = true
+	cfg.Socket = fmt.Sprintf("/tmp/tidb-mock-%d.sock", time.Now().UnixNano())
+
+	server, err := server2.NewServer(cfg, driver)
+	require.NoError(t, err)
+	defer server.Close()
+
+	dom, err := session.GetDomain(store)
+	require.NoError(t, err)
+	server.SetDomain(dom)
+	go func() {
+		err := server.Run(nil)
+		require.NoError(t, err)
+	}()
+	<-server2.RunInGoTestChan
+	client.Port = testutil.GetPortFromTCPAddr(server.ListenAddr())
+	client.StatusPort = testutil.GetPortFromTCPAddr(server.StatusListenerAddr())
+	client.WaitUntilServerOnline()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, index ia(a))")
+	tk.MustExec("insert into t values (1, 1), (2, 1), (3, null)")
+	h := dom.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t")
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+
+	resp, err := client.FetchStatus(fmt.Sprintf("/stats/summary/%d", tbl.Meta().ID))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	js, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var summary types.TableStatsSummary
+	require.NoError(t, json.Unmarshal(js, &summary), string(js))
+	require.False(t, summary.Pseudo)
+	require.Equal(t, int64(3), summary.RowCount)
+	require.Len(t, summary.Columns, 2)
+	require.Equal(t, "a", summary.Columns[0].Name)
+	require.Equal(t, int64(3), summary.Columns[0].NDV)
+	require.Equal(t, int64(1), summary.Columns[1].NDV)
+	require.Equal(t, int64(1), summary.Columns[1].NullCount)
+	require.Len(t, summary.Indexes, 1)
+	require.Equal(t, "ia", summary.Indexes[0].Name)
+
+	resp2, err := client.FetchStatus("/stats/summary/1234567")
+	require.NoError(t, err)
+	defer resp2.Body.Close()
+	js, err = io.ReadAll(resp2.Body)
+	require.NoError(t, err)
+	require.Equal(t, "table 1234567 does not exist", string(js))
+}
+
+func TestStatsHealthAPI(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	driver := server2.NewTiDBDriver(store)
+	client := testserverclient.NewTestServerClient()
+	cfg := util.NewTestConfig()
+	cfg.Port = client.Port
+	cfg.Status.StatusPort = client.StatusPort
+	cfg.Status.ReportStatus = true
+	cfg.Socket = fmt.Sprintf("/tmp/tidb-mock-%d.sock", time.Now().UnixNano())
+
+	server, err := server2.NewServer(cfg, driver)
+	require.NoError(t, err)
+	defer server.Close()
+
+	dom, err := session.GetDomain(store)
+	require.NoError(t, err)
+	server.SetDomain(dom)
+	go func() {
+		err := server.Run(nil)
+		require.NoError(t, err)
+	}()
+	<-server2.RunInGoTestChan
+	client.Port = testutil.GetPortFromTCPAddr(server.ListenAddr())
+	client.StatusPort = testutil.GetPortFromTCPAddr(server.StatusListenerAddr())
+	client.WaitUntilServerOnline()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int) partition by hash(a) partitions 2")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("insert into t values (1), (2), (3), (4)")
+	h := dom.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t")
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+
+	fetch := func(path string) []byte {
+		resp, err := client.FetchStatus(path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		js, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return js
+	}
+
+	var healths []types.TableStatsHealth
+	js := fetch("/stats/health/test/t")
+	require.NoError(t, json.Unmarshal(js, &healths), string(js))
+	require.Len(t, healths, 3)
+	require.Equal(t, "global", healths[0].PartitionName)
+	require.Equal(t, int64(4), healths[0].RowCount)
+	for _, health := range healths {
+		require.Equal(t, "t", health.TableName)
+		require.False(t, health.Pseudo)
+		require.Equal(t, int64(100), health.Healthy)
+		require.NotNil(t, health.LastAnalyzeTime)
+		require.Empty(t, health.UnusableReason)
+	}
+
+	var healths2 []types.TableStatsHealth
+	js = fetch("/stats/health/test/t2")
+	require.NoError(t, json.Unmarshal(js, &healths2), string(js))
+	require.Len(t, healths2, 1)
+	require.Empty(t, healths2[0].PartitionName)
+	require.Nil(t, healths2[0].LastAnalyzeTime)
+	require.Equal(t, "never_analyzed", healths2[0].UnusableReason)
+
+	require.Equal(t, "[schema:1146]Table 'test.not_exist' doesn't exist", string(fetch("/stats/health/test/not_exist")))
+
+	var runningJobs []types.RunningAnalysisJobJSON
+	js = fetch("/stats/jobs/running")
+	require.NoError(t, json.Unmarshal(js, &runningJobs), string(js))
+	require.Empty(t, runningJobs)
+}
+
+func TestStatsAutoAnalyzeAPI(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	driver := server2.NewTiDBDriver(store)
+	client := testserverclient.NewTestServerClient()
+	cfg := util.NewTestConfig()
+	cfg.Port = client.Port
+	cfg.Status.StatusPort = client.StatusPort
+	cfg.Status.ReportStatus = true
+	cfg.Socket = fmt.Sprintf("/tmp/tidb-mock-%d.sock", time.Now().UnixNano())
+
+	server, err := server2.NewServer(cfg, driver)
+	require.NoError(t, err)
+	defer server.Close()
+
+	dom, err := session.GetDomain(store)
+	require.NoError(t, err)
+	server.SetDomain(dom)
+	go func() {
+		err := server.Run(nil)
+		require.NoError(t, err)
+	}()
+	<-server2.RunInGoTestChan
+	client.Port = testutil.GetPortFromTCPAddr(server.ListenAddr())
+	client.StatusPort = testutil.GetPortFromTCPAddr(server.StatusListenerAddr())
+	client.WaitUntilServerOnline()
+
+	post := func(path string) string {
+		resp, err := client.PostStatus(path, "application/x-www-form-urlencoded", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		js, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(js)
+	}
+	tk := testkit.NewTestKit(t, store)
+	post("/stats/auto-analyze/pause")
+	tk.MustQuery("select @@global.tidb_enable_auto_analyze").Check(testkit.Rows("0"))
+	post("/stats/auto-analyze/resume")
+	tk.MustQuery("select @@global.tidb_enable_auto_analyze").Check(testkit.Rows("1"))
+	resp, err := client.FetchStatus("/stats/auto-analyze/pause")
+	require.NoError(t, err)
+	js, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "This api only support POST method", string(js))
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("create table tp (a int) partition by hash(a) partitions 2")
+	h := dom.StatsHandle()
+	require.Equal(t, "priority queue not initialized", post("/stats/auto-analyze/enqueue/test/t"))
+	// Initialize the priority queue.
+	h.HandleAutoAnalyze()
+	post("/stats/auto-analyze/enqueue/test/t?priority=10")
+	post("/stats/auto-analyze/enqueue/test/tp?partition=p1")
+	require.Equal(t, "unknown partition p2 of table tp", post("/stats/auto-analyze/enqueue/test/tp?partition=p2"))
+	require.Equal(t, "priority must be a positive number", post("/stats/auto-analyze/enqueue/test/t?priority=-1"))
+	snapshot, err := h.GetPriorityQueueSnapshot()
+	require.NoError(t, err)
+	require.NotEmpty(t, snapshot.CurrentJobs)
+	require.Equal(t, float64(10), snapshot.CurrentJobs[0].Weight)
+	require.Equal(t, dom.MustGetTableInfo(t, "test", "t").ID, snapshot.CurrentJobs[0].TableID)
+
+	tk.MustExec("insert into mysql.analyze_jobs (table_schema, table_name, job_info, state, fail_reason, instance) values " +
+		"('test', 't', 'auto analyze table all columns with 256 buckets', 'failed', 'mock error', '10.0.0.1:4000'), " +
+		"('test', 'tp', 'auto analyze table all columns with 256 buckets', 'finished', null, '10.0.0.1:4000'), " +
+		"('test', 't', 'analyze table all columns with 256 buckets', 'finished', null, '10.0.0.1:4000')")
+	fetchJobs := func(path string) []types.AutoAnalyzeJobOutcome {
+		resp, err := client.FetchStatus(path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		js, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var outcomes []types.AutoAnalyzeJobOutcome
+		require.NoError(t, json.Unmarshal(js, &outcomes), string(js))
+		return outcomes
+	}
+	outcomes := fetchJobs("/stats/auto-analyze/jobs")
+	require.Len(t, outcomes, 2)
+	require.Equal(t, "tp", outcomes[0].Table)
+	require.Equal(t, "finished", outcomes[0].State)
+	outcomes = fetchJobs("/stats/auto-analyze/jobs?db=test&table=t")
+	require.Len(t, outcomes, 1)
+	require.Equal(t, "failed", outcomes[0].State)
+	require.Equal(t, "mock error", outcomes[0].FailReason)
+	require.Len(t, fetchJobs("/stats/auto-analyze/jobs?limit=1"), 1)
+}
@@ -64,6 +64,7 @@ func TestPostSettings(t *testing.T) {
 	form := make(url.Values)
 	form.Set("log_level", "error")
 	form.Set("tidb_general_log", "1")
+	form.Set("tidb_instance_read_only", "1")
 	form.Set("tidb_enable_async_commit", "1")
 	form.Set("tidb_enable_1pc", "1")
 	resp, err := ts.FormStatus("/settings", form)
@@ -73,6 +74,7 @@ func TestPostSettings(t *testing.T) {
 	require.Equal(t, zap.ErrorLevel, log.GetLevel())
 	require.Equal(t, "error", config.GetGlobalConfig().Log.Level)
 	require.True(t, variable.ProcessGeneralLog.Load())
+	require.True(t, variable.InstanceReadOnly.Load())
 	val, err := se.GetSessionVars().GetGlobalSystemVar(context.Background(), variable.TiDBEnableAsyncCommit)
 	require.NoError(t, err)
 	require.Equal(t, variable.On, val)
@@ -83,6 +85,7 @@ func TestPostSettings(t *testing.T) {
 	form = make(url.Values)
 	form.Set("log_level", "fatal")
 	form.Set("tidb_general_log", "0")
+	form.Set("tidb_instance_read_only", "0")
 	form.Set("tidb_enable_async_commit", "0")
 	form.Set("tidb_enable_1pc", "0")
 	resp, err = ts.FormStatus("/settings", form)
@@ -90,6 +93,7 @@ func TestPostSettings(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode)
 	require.NoError(t, resp.Body.Close())
 	require.False(t, variable.ProcessGeneralLog.Load())
+	require.False(t, variable.InstanceReadOnly.Load())
 	require.Equal(t, zap.FatalLevel, log.GetLevel())
 	require.Equal(t, "fatal", config.GetGlobalConfig().Log.Level)
 	val, err = se.GetSessionVars().GetGlobalSystemVar(context.Background(), variable.TiDBEnableAsyncCommit)
@@ -434,6 +434,17 @@ func (h SettingsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				return
 			}
 		}
+		if instanceReadOnly := req.Form.Get("tidb_instance_read_only"); instanceReadOnly != "" {
+			switch instanceReadOnly {
+			case "0":
+				variable.InstanceReadOnly.Store(false)
+			case "1":
+				variable.InstanceReadOnly.Store(true)
+			default:
+				handler.WriteError(w, errors.New("illegal argument"))
+				return
+			}
+		}
 		if asyncCommit := req.Form.Get("tidb_enable_async_commit"); asyncCommit != "" {
 			s, err := session.CreateSession(h.Store)
 			if err != nil {
@@ -41,6 +41,7 @@ const (
 	Snapshot           = "snapshot"
 	FileName           = "filename"
 	DumpPartitionStats = "dumpPartitionStats"
+	StatsFormat        = "format"
 	Begin              = "begin"
 	End                = "end"
 )
@@ -64,11 +65,19 @@ const (
 	JobID         = "start_job_id"
 	Operation     = "op"
 	Seconds       = "seconds"
+	Partition     = "partition"
+	Priority      = "priority"
 )
 
 const (
 	HeaderContentType = "Content-Type"
 	ContentTypeJSON   = "application/json"
+	ContentTypeOctet  = "application/octet-stream"
+)
+
+// For stats dump handler
+const (
+	StatsFormatBinary = "binary"
 )
 
 //revive:enable
@@ -219,6 +219,29 @@ func (s *Server) startHTTPServer() {
 		Name("StatsHistoryDump")
 	router.Handle("/stats/priority-queue", s.newStatsPriorityQueueHandler()).
 		Name("StatsPriorityQueue")
+	router.Handle("/stats/queue", s.newStatsPriorityQueueHandler()).
+		Name("StatsQueue")
+	router.Handle("/stats/jobs/running", s.newStatsRunningJobsHandler()).
+		Name("StatsRunningJobs")
+	router.Handle("/stats/health/{db}/{table}", s.newStatsHealthHandler()).
+		Name("StatsHealth")
+	router.Handle("/stats/indicators", s.newStatsIndicatorsHandler()).
+		Name("StatsIndicators")
+	router.Handle("/stats/indicators/{db}", s.newStatsIndicatorsHandler()).
+		Name("StatsIndicatorsOfDB")
+	router.Handle("/stats/indicators/{db}/{table}", s.newStatsIndicatorsHandler()).
+		Name("StatsIndicatorsOfTable")
+	router.Handle("/stats/summary/{tableID}", s.newStatsSummaryHandler()).
+		Name("StatsSummary")
+	// HTTP path for the external schedulers to control auto analyze.
+	router.Handle("/stats/auto-analyze/pause", s.newStatsAutoAnalyzeHandler(optimizor.AutoAnalyzeOpPause)).
+		Name("StatsAutoAnalyzePause")
+	router.Handle("/stats/auto-analyze/resume", s.newStatsAutoAnalyzeHandler(optimizor.AutoAnalyzeOpResume)).
+		Name("StatsAutoAnalyzeResume")
+	router.Handle("/stats/auto-analyze/enqueue/{db}/{table}", s.newStatsAutoAnalyzeHandler(optimizor.AutoAnalyzeOpEnqueue)).
+		Name("StatsAutoAnalyzeEnqueue")
+	router.Handle("/stats/auto-analyze/jobs", s.newStatsAutoAnalyzeHandler(optimizor.AutoAnalyzeOpJobs)).
+		Name("StatsAutoAnalyzeJobs")
 
 	router.Handle("/plan_replayer/dump/{filename}", s.newPlanReplayerHandler()).Name("PlanReplayerDump")
 	router.Handle("/extract_task/dump", s.newExtractServeHandler()).Name("ExtractTaskDump")
@@ -637,3 +660,73 @@ func (s *Server) newStatsPriorityQueueHandler() *optimizor.StatsPriorityQueueHan
 
 	return optimizor.NewStatsPriorityQueueHandler(do)
 }
+
+func (s *Server) newStatsRunningJobsHandler() *optimizor.StatsRunningJobsHandler {
+	store, ok := s.driver.(*TiDBDriver)
+	if !ok {
+		panic("Illegal driver")
+	}
+
+	do, err := session.GetDomain(store.store)
+	if err != nil {
+		panic("Failed to get domain")
+	}
+
+	return optimizor.NewStatsRunningJobsHandler(do)
+}
+
+func (s *Server) newStatsHealthHandler() *optimizor.StatsHealthHandler {
+	store, ok := s.driver.(*TiDBDriver)
+	if !ok {
+		panic("Illegal driver")
+	}
+
+	do, err := session.GetDomain(store.store)
+	if err != nil {
+		panic("Failed to get domain")
+	}
+
+	return optimizor.NewStatsHealthHandler(do)
+}
+
+func (s *Server) newStatsIndicatorsHandler() *optimizor.StatsIndicatorsHandler {
+	store, ok := s.driver.(*TiDBDriver)
+	if !ok {
+		panic("Illegal driver")
+	}
+
+	do, err := session.GetDomain(store.store)
+	if err != nil {
+		panic("Failed to get domain")
+	}
+
+	return optimizor.NewStatsIndicatorsHandler(do)
+}
+
+func (s *Server) newStatsSummaryHandler() *optimizor.StatsSummaryHandler {
+	store, ok := s.driver.(*TiDBDriver)
+	if !ok {
+		panic("Illegal driver")
+	}
+
+	do, err := session.GetDomain(store.store)
+	if err != nil {
+		panic("Failed to get domain")
+	}
+
+	return optimizor.NewStatsSummaryHandler(do)
+}
+
+func (s *Server) newStatsAutoAnalyzeHandler(op string) *optimizor.StatsAutoAnalyzeHandler {
+	store, ok := s.driver.(*TiDBDriver)
+	if !ok {
+		panic("Illegal driver")
+	}
+
+	do, err := session.GetDomain(store.store)
+	if err != nil {
+		panic("Failed to get domain")
+	}
+
+	return optimizor.NewStatsAutoAnalyzeHandler(do, op)
+}
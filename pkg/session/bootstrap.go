@@ -220,6 +220,8 @@ const (
 		modify_count	BIGINT(64) NOT NULL DEFAULT 0,
 		count 			BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
 		snapshot        BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		insert_count	BIGINT(64) NOT NULL DEFAULT 0,
+		delete_count	BIGINT(64) NOT NULL DEFAULT 0,
 		INDEX idx_ver(version),
 		UNIQUE INDEX tbl(table_id)
 	);`
@@ -239,6 +241,7 @@ const (
 		flag 				BIGINT(64) NOT NULL DEFAULT 0,
 		correlation 		DOUBLE NOT NULL DEFAULT 0,
 		last_analyze_pos 	LONGBLOB DEFAULT NULL,
+		ndv_sketch 			LONGBLOB DEFAULT NULL,
 		UNIQUE INDEX tbl(table_id, is_index, hist_id)
 	);`
 
@@ -495,6 +498,8 @@ const (
 		sql_digest VARCHAR(128) NOT NULL,
 		plan_digest VARCHAR(128) NOT NULL,
 		update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+		est_error_threshold DOUBLE NOT NULL DEFAULT 0,
+		latency_threshold DOUBLE NOT NULL DEFAULT 0 COMMENT 'in seconds',
 		PRIMARY KEY (sql_digest,plan_digest));`
 
 	// CreateStatsTableLocked stores the locked tables
@@ -759,6 +764,164 @@ const (
         status varchar(128),
         description text,
         primary key(module, name))`
+
+	// CreateAnalyzeIndicatorsHistoryTable stores the periodic snapshots of the auto-analyze priority queue indicators.
+	CreateAnalyzeIndicatorsHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.analyze_indicators_history (
+		table_id BIGINT(64) NOT NULL,
+		snapshot_time DATETIME(6) NOT NULL,
+		job_type VARCHAR(64) NOT NULL,
+		change_percentage DOUBLE NOT NULL,
+		table_size DOUBLE NOT NULL,
+		last_analysis_duration BIGINT(64) NOT NULL comment 'seconds since the last analysis',
+		weight DOUBLE NOT NULL,
+		PRIMARY KEY (table_id, snapshot_time),
+		KEY idx_snapshot_time (snapshot_time)
+	);`
+
+	// CreateStatsGlobalMergeJobsTable stores the pending jobs which merge the partition-level stats into the global-level stats in the background.
+	CreateStatsGlobalMergeJobsTable = `CREATE TABLE IF NOT EXISTS mysql.stats_global_merge_jobs (
+		table_id BIGINT(64) NOT NULL,
+		index_id BIGINT(64) NOT NULL comment '-1 for the columns',
+		hist_ids TEXT NOT NULL,
+		stats_ver INT NOT NULL,
+		options TEXT NOT NULL,
+		retry_count INT NOT NULL DEFAULT 0,
+		last_error TEXT,
+		create_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		update_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (table_id, index_id),
+		KEY idx_create_time (create_time)
+	);`
+
+	// CreateStatsVersionMigrationTable stores the progress of migrating the tables with the version 1 stats to
+	// the version 2 stats in the background.
+	CreateStatsVersionMigrationTable = `CREATE TABLE IF NOT EXISTS mysql.stats_version_migration (
+		table_id BIGINT(64) NOT NULL,
+		table_schema VARCHAR(64) NOT NULL,
+		table_name VARCHAR(64) NOT NULL,
+		state ENUM('pending', 'running', 'finished', 'failed', 'skipped') NOT NULL DEFAULT 'pending',
+		fail_reason TEXT,
+		start_time DATETIME(6) DEFAULT NULL,
+		end_time DATETIME(6) DEFAULT NULL,
+		create_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (table_id),
+		KEY idx_state (state)
+	);`
+
+	// CreateStatsQuickRefreshTable stores the modify count baselines of the tables whose row count and null counts
+	// are refreshed by auto analyze without rebuilding the histograms.
+	CreateStatsQuickRefreshTable = `CREATE TABLE IF NOT EXISTS mysql.stats_quick_refresh (
+		table_id BIGINT(64) NOT NULL,
+		modify_count BIGINT(64) NOT NULL DEFAULT 0,
+		analyze_version BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		refresh_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (table_id)
+	);`
+
+	// CreateStatsInjectedTable stores the statistics injected manually by ALTER TABLE ... MODIFY STATS.
+	// The records are removed when the tables are analyzed again.
+	CreateStatsInjectedTable = `CREATE TABLE IF NOT EXISTS mysql.stats_injected (
+		table_id BIGINT(64) NOT NULL,
+		stats JSON NOT NULL,
+		version BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		inject_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (table_id)
+	);`
+
+	// CreatePlanBaselineEvolveTasksTable stores the candidate plan baselines of the statements whose plans regressed,
+	// which are verified and promoted to the bindings by the evolution of plan baselines.
+	CreatePlanBaselineEvolveTasksTable = `CREATE TABLE IF NOT EXISTS mysql.plan_baseline_evolve_tasks (
+		sql_digest VARCHAR(64) NOT NULL,
+		plan_digest VARCHAR(64) NOT NULL,
+		regressed_plan_digest VARCHAR(64) NOT NULL,
+		original_sql TEXT NOT NULL,
+		bind_sql TEXT NOT NULL,
+		default_db TEXT NOT NULL,
+		charset TEXT NOT NULL,
+		collation TEXT NOT NULL,
+		status VARCHAR(16) NOT NULL DEFAULT 'pending',
+		candidate_latency BIGINT(64) NOT NULL DEFAULT 0 COMMENT "the average latency of the candidate plan in nanoseconds",
+		regressed_latency BIGINT(64) NOT NULL DEFAULT 0 COMMENT "the average latency of the regressed plan in nanoseconds",
+		message TEXT,
+		create_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		update_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (sql_digest, plan_digest),
+		INDEX idx_status(status)
+	);`
+
+	// CreateAnalyzeCheckpointsTable stores the partitions whose stats have been saved by an ANALYZE statement which
+	// hasn't finished yet, so the statement can skip them when it runs again after being interrupted.
+	CreateAnalyzeCheckpointsTable = `CREATE TABLE IF NOT EXISTS mysql.analyze_checkpoints (
+		table_id BIGINT(64) NOT NULL,
+		partition_id BIGINT(64) NOT NULL,
+		analyze_sql TEXT NOT NULL,
+		checkpoint_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (table_id, partition_id)
+	);`
+
+	// CreateAutoAnalyzeConfigTable stores the auto analyze options of the tables set by ALTER TABLE ... AUTO_ANALYZE OPTIONS.
+	// The NULL options are not set, which fall back to the table options and the global variables.
+	CreateAutoAnalyzeConfigTable = `CREATE TABLE IF NOT EXISTS mysql.auto_analyze_config (
+		table_id BIGINT(64) NOT NULL,
+		priority DOUBLE DEFAULT NULL,
+		window_start VARCHAR(32) DEFAULT NULL,
+		window_end VARCHAR(32) DEFAULT NULL,
+		sample_rate DOUBLE DEFAULT NULL,
+		column_choice VARCHAR(16) DEFAULT NULL,
+		disabled TINYINT(1) DEFAULT NULL,
+		cool_down BIGINT(64) UNSIGNED DEFAULT NULL COMMENT "in minutes",
+		resource_group VARCHAR(32) DEFAULT NULL,
+		target_qerror DOUBLE DEFAULT NULL,
+		target_qerror_percentile DOUBLE DEFAULT NULL,
+		update_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (table_id)
+	);`
+
+	// CreateStatsAlertsTable stores the alerts of the tables whose stats exceed the thresholds of
+	// tidb_stats_alert_stale_ratio or tidb_stats_alert_estimation_error_threshold although auto analyze is enabled.
+	CreateStatsAlertsTable = `CREATE TABLE IF NOT EXISTS mysql.stats_alerts (
+		id BIGINT(64) UNSIGNED NOT NULL AUTO_INCREMENT,
+		alert_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		table_schema CHAR(64) NOT NULL DEFAULT '',
+		table_name CHAR(64) NOT NULL DEFAULT '',
+		partition_name CHAR(64) NOT NULL DEFAULT '',
+		table_id BIGINT(64) NOT NULL,
+		alert_type VARCHAR(32) NOT NULL,
+		value DOUBLE NOT NULL,
+		threshold DOUBLE NOT NULL,
+		skip_reason TEXT,
+		PRIMARY KEY (id),
+		KEY (alert_time)
+	);`
+
+	// CreateAnalyzeQuarantineTable stores the stats built by ANALYZE which fail the verification of
+	// tidb_analyze_verify_stats, and are not saved.
+	CreateAnalyzeQuarantineTable = `CREATE TABLE IF NOT EXISTS mysql.analyze_quarantine (
+		id BIGINT(64) UNSIGNED NOT NULL AUTO_INCREMENT,
+		create_time DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		table_schema CHAR(64) NOT NULL DEFAULT '',
+		table_name CHAR(64) NOT NULL DEFAULT '',
+		partition_name CHAR(64) NOT NULL DEFAULT '',
+		table_id BIGINT(64) NOT NULL,
+		job_info TEXT,
+		old_count BIGINT(64) NOT NULL,
+		new_count BIGINT(64) NOT NULL,
+		reason TEXT NOT NULL,
+		PRIMARY KEY (id),
+		KEY (table_id),
+		KEY (create_time)
+	);`
+
+	// CreateStatsGlobalNDVSketchTable stores the HLL sketches of the partitioned tables merged from the ones of their
+	// partitions, which are maintained incrementally when a partition is analyzed.
+	CreateStatsGlobalNDVSketchTable = `CREATE TABLE IF NOT EXISTS mysql.stats_global_ndv_sketch (
+		table_id BIGINT(64) NOT NULL,
+		is_index TINYINT(2) NOT NULL,
+		hist_id BIGINT(64) NOT NULL,
+		value LONGBLOB NOT NULL,
+		version BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		PRIMARY KEY (table_id, is_index, hist_id)
+	);`
 )
 
 // CreateTimers is a table to store all timers for tidb
@@ -1196,12 +1359,74 @@ const (
 	// [version219, version238] is the version range reserved for patches of 8.5.x
 	// ...
 
-	// next version should start with 239
+	// version 239
+	//   create `mysql.analyze_indicators_history` table
+	version239 = 239
+
+	// version 240
+	//   create `mysql.stats_global_merge_jobs` table
+	version240 = 240
+
+	// version 241
+	//   add column `ndv_sketch` to `mysql.stats_histograms`
+	version241 = 241
+
+	// version 242
+	//   create `mysql.stats_version_migration` table
+	version242 = 242
+
+	// version 243
+	//   create `mysql.stats_quick_refresh` table
+	version243 = 243
+
+	// version 244
+	//   create `mysql.stats_injected` table
+	version244 = 244
+
+	// version 245
+	//   add `insert_count` and `delete_count` columns to `mysql.stats_meta`
+	version245 = 245
+
+	// version 246
+	//   create `mysql.plan_baseline_evolve_tasks` table
+	version246 = 246
+
+	// version 247
+	//   add `est_error_threshold` and `latency_threshold` columns to `mysql.plan_replayer_task`
+	version247 = 247
+
+	// version 248
+	//   create `mysql.analyze_checkpoints` table
+	version248 = 248
+
+	// version 249
+	//   create `mysql.auto_analyze_config` table
+	version249 = 249
+
+	// version 250
+	//   create `mysql.stats_alerts` table
+	version250 = 250
+
+	// version 251
+	//   add column `resource_group` to `mysql.auto_analyze_config`
+	version251 = 251
+
+	// version 252
+	//   add columns `target_qerror` and `target_qerror_percentile` to `mysql.auto_analyze_config`
+	version252 = 252
+
+	// version 253
+	//   create `mysql.analyze_quarantine` table
+	version253 = 253
+
+	// version 254
+	//   create `mysql.stats_global_ndv_sketch` table
+	version254 = 254
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version218
+var currentBootstrapVersion int64 = version254
 
 // DDL owner key's expired time is ManagerSessionTTL seconds, we should wait the time and give more time to have a chance to finish it.
 var internalSQLTimeout = owner.ManagerSessionTTL + 15
@@ -1375,6 +1600,22 @@ var (
 		upgradeToVer216,
 		upgradeToVer217,
 		upgradeToVer218,
+		upgradeToVer239,
+		upgradeToVer240,
+		upgradeToVer241,
+		upgradeToVer242,
+		upgradeToVer243,
+		upgradeToVer244,
+		upgradeToVer245,
+		upgradeToVer246,
+		upgradeToVer247,
+		upgradeToVer248,
+		upgradeToVer249,
+		upgradeToVer250,
+		upgradeToVer251,
+		upgradeToVer252,
+		upgradeToVer253,
+		upgradeToVer254,
 	}
 )
 
@@ -3272,6 +3513,137 @@ func upgradeToVer218(_ sessiontypes.Session, ver int64) {
 	// empty, just make lint happy.
 }
 
+func upgradeToVer239(s sessiontypes.Session, ver int64) {
+	if ver >= version239 {
+		return
+	}
+
+	mustExecute(s, CreateAnalyzeIndicatorsHistoryTable)
+}
+
+func upgradeToVer240(s sessiontypes.Session, ver int64) {
+	if ver >= version240 {
+		return
+	}
+
+	mustExecute(s, CreateStatsGlobalMergeJobsTable)
+}
+
+func upgradeToVer241(s sessiontypes.Session, ver int64) {
+	if ver >= version241 {
+		return
+	}
+
+	doReentrantDDL(s, "ALTER TABLE mysql.stats_histograms ADD COLUMN IF NOT EXISTS `ndv_sketch` LONGBLOB DEFAULT NULL")
+}
+
+func upgradeToVer242(s sessiontypes.Session, ver int64) {
+	if ver >= version242 {
+		return
+	}
+
+	mustExecute(s, CreateStatsVersionMigrationTable)
+}
+
+func upgradeToVer243(s sessiontypes.Session, ver int64) {
+	if ver >= version243 {
+		return
+	}
+
+	mustExecute(s, CreateStatsQuickRefreshTable)
+}
+
+func upgradeToVer244(s sessiontypes.Session, ver int64) {
+	if ver >= version244 {
+		return
+	}
+
+	mustExecute(s, CreateStatsInjectedTable)
+}
+
+func upgradeToVer245(s sessiontypes.Session, ver int64) {
+	if ver >= version245 {
+		return
+	}
+
+	doReentrantDDL(s, "ALTER TABLE mysql.stats_meta ADD COLUMN insert_count BIGINT(64) NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.stats_meta ADD COLUMN delete_count BIGINT(64) NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer246(s sessiontypes.Session, ver int64) {
+	if ver >= version246 {
+		return
+	}
+
+	mustExecute(s, CreatePlanBaselineEvolveTasksTable)
+}
+
+func upgradeToVer247(s sessiontypes.Session, ver int64) {
+	if ver >= version247 {
+		return
+	}
+
+	doReentrantDDL(s, "ALTER TABLE mysql.plan_replayer_task ADD COLUMN est_error_threshold DOUBLE NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.plan_replayer_task ADD COLUMN latency_threshold DOUBLE NOT NULL DEFAULT 0 COMMENT 'in seconds'", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer248(s sessiontypes.Session, ver int64) {
+	if ver >= version248 {
+		return
+	}
+
+	mustExecute(s, CreateAnalyzeCheckpointsTable)
+}
+
+func upgradeToVer249(s sessiontypes.Session, ver int64) {
+	if ver >= version249 {
+		return
+	}
+
+	mustExecute(s, CreateAutoAnalyzeConfigTable)
+}
+
+func upgradeToVer250(s sessiontypes.Session, ver int64) {
+	if ver >= version250 {
+		return
+	}
+
+	mustExecute(s, CreateStatsAlertsTable)
+}
+
+func upgradeToVer251(s sessiontypes.Session, ver int64) {
+	if ver >= version251 {
+		return
+	}
+
+	doReentrantDDL(s, "ALTER TABLE mysql.auto_analyze_config ADD COLUMN IF NOT EXISTS `resource_group` VARCHAR(32) DEFAULT NULL AFTER `cool_down`")
+}
+
+func upgradeToVer252(s sessiontypes.Session, ver int64) {
+	if ver >= version252 {
+		return
+	}
+
+	doReentrantDDL(s, "ALTER TABLE mysql.auto_analyze_config ADD COLUMN IF NOT EXISTS `target_qerror` DOUBLE DEFAULT NULL AFTER `resource_group`")
+	doReentrantDDL(s, "ALTER TABLE mysql.auto_analyze_config ADD COLUMN IF NOT EXISTS `target_qerror_percentile` DOUBLE DEFAULT NULL AFTER `target_qerror`")
+}
+
+func upgradeToVer253(s sessiontypes.Session, ver int64) {
+	if ver >= version253 {
+		return
+	}
+
+	mustExecute(s, CreateAnalyzeQuarantineTable)
+}
+
+func upgradeToVer254(s sessiontypes.Session, ver int64) {
+	if ver >= version254 {
+		return
+	}
+
+	mustExecute(s, CreateStatsGlobalNDVSketchTable)
+}
+
 // initGlobalVariableIfNotExists initialize a global variable with specific val if it does not exist.
 func initGlobalVariableIfNotExists(s sessiontypes.Session, name string, val any) {
 	ctx := kv.WithInternalSourceType(context.Background(), kv.InternalTxnBootstrap)
@@ -3426,6 +3798,28 @@ func doDDLWorks(s sessiontypes.Session) {
 	mustExecute(s, CreateIndexAdvisorTable)
 	// create mysql.tidb_kernel_options
 	mustExecute(s, CreateKernelOptionsTable)
+	// create mysql.analyze_indicators_history
+	mustExecute(s, CreateAnalyzeIndicatorsHistoryTable)
+	// create mysql.stats_global_merge_jobs
+	mustExecute(s, CreateStatsGlobalMergeJobsTable)
+	// create mysql.stats_version_migration
+	mustExecute(s, CreateStatsVersionMigrationTable)
+	// create mysql.stats_quick_refresh
+	mustExecute(s, CreateStatsQuickRefreshTable)
+	// create mysql.stats_injected
+	mustExecute(s, CreateStatsInjectedTable)
+	// create mysql.plan_baseline_evolve_tasks
+	mustExecute(s, CreatePlanBaselineEvolveTasksTable)
+	// create mysql.analyze_checkpoints
+	mustExecute(s, CreateAnalyzeCheckpointsTable)
+	// create mysql.auto_analyze_config
+	mustExecute(s, CreateAutoAnalyzeConfigTable)
+	// create mysql.stats_alerts
+	mustExecute(s, CreateStatsAlertsTable)
+	// create mysql.analyze_quarantine
+	mustExecute(s, CreateAnalyzeQuarantineTable)
+	// create mysql.stats_global_ndv_sketch
+	mustExecute(s, CreateStatsGlobalNDVSketchTable)
 }
 
 // doBootstrapSQLFile executes SQL commands in a file as the last stage of bootstrap.
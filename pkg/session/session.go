@@ -93,6 +93,7 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/sessiontxn"
+	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle/syncload"
 	"github.com/pingcap/tidb/pkg/statistics/handle/usage"
 	"github.com/pingcap/tidb/pkg/statistics/handle/usage/indexusage"
@@ -505,6 +506,12 @@ func (s *session) doCommit(ctx context.Context) error {
 	if s.txn.IsReadOnly() {
 		return nil
 	}
+	// check if the instance is read-only, the long running auto-commit statement may be trying to commit
+	// after the instance is fenced.
+	if !s.sessionVars.InRestrictedSQL && variable.InstanceReadOnly.Load() {
+		s.RollbackTxn(ctx)
+		return plannererrors.ErrInstanceReadOnly
+	}
 	// check if the cluster is read-only
 	if !s.sessionVars.InRestrictedSQL && (variable.RestrictedReadOnly.Load() || variable.VarTiDBSuperReadOnly.Load()) {
 		// It is not internal SQL, and the cluster has one of RestrictedReadOnly or SuperReadOnly
@@ -904,11 +911,17 @@ func addTableNameInTableIDField(ctx context.Context, tableIDField any, is infosc
 }
 
 func (s *session) updateStatsDeltaToCollector() {
-	mapper := s.GetSessionVars().TxnCtx.TableDeltaMap
+	txnCtx := s.GetSessionVars().TxnCtx
+	// The temporary tables are only visible to the session, so their stats are kept in the session instead.
+	statistics.CommitTemporaryTableStats(s.GetSessionVars())
+	mapper := txnCtx.TableDeltaMap
 	if s.statsCollector != nil && mapper != nil {
 		for _, item := range mapper {
+			if _, ok := txnCtx.TemporaryTables[item.TableID]; ok {
+				continue
+			}
 			if item.TableID > 0 {
-				s.statsCollector.Update(item.TableID, item.Delta, item.Count, &item.ColSize)
+				s.statsCollector.Update(item.TableID, item)
 			}
 		}
 	}
@@ -3644,6 +3657,18 @@ func bootstrapSessionImpl(ctx context.Context, store kv.Storage, createSessionsI
 		}
 		return s
 	}
+	ttlworker.RefreshStats = func(physicalID int64) error {
+		h := dom.StatsHandle()
+		if h == nil {
+			return nil
+		}
+		if err := h.DumpStatsDeltaToKV(true); err != nil {
+			return err
+		}
+		// It fails if the current instance is not the stats owner. The flushed deletions still raise the shrink
+		// indicator of the table in the priority queue of the stats owner in that case.
+		return h.EnqueueAnalysisJob(physicalID)
+	}
 	dom.StartTTLJobManager()
 
 	dom.LoadSigningCertLoop(cfg.Security.SessionTokenSigningCert, cfg.Security.SessionTokenSigningKey)
@@ -4345,32 +4370,8 @@ func (s *session) SetMemoryFootprintChangeHook() {
 // EncodeSessionStates implements SessionStatesHandler.EncodeSessionStates interface.
 func (s *session) EncodeSessionStates(ctx context.Context,
 	_ sessionctx.Context, sessionStates *sessionstates.SessionStates) error {
-	// Transaction status is hard to encode, so we do not support it.
-	s.txn.mu.Lock()
-	valid := s.txn.Valid()
-	s.txn.mu.Unlock()
-	if valid {
-		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has an active transaction")
-	}
-	// Data in local temporary tables is hard to encode, so we do not support it.
-	// Check temporary tables here to avoid circle dependency.
-	if s.sessionVars.LocalTemporaryTables != nil {
-		localTempTables := s.sessionVars.LocalTemporaryTables.(*infoschema.SessionTables)
-		if localTempTables.Count() > 0 {
-			return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has local temporary tables")
-		}
-	}
-	// The advisory locks will be released when the session is closed.
-	if len(s.advisoryLocks) > 0 {
-		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has advisory locks")
-	}
-	// The TableInfo stores session ID and server ID, so the session cannot be migrated.
-	if len(s.lockedTables) > 0 {
-		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has locked tables")
-	}
-	// It's insecure to migrate sandBoxMode because users can fake it.
-	if s.InSandBoxMode() {
-		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session is in sandbox mode")
+	if err := s.sessionShareFlags().Err(); err != nil {
+		return err
 	}
 
 	if err := s.sessionVars.EncodeSessionStates(ctx, sessionStates); err != nil {
@@ -4432,6 +4433,78 @@ func (s *session) EncodeSessionStates(ctx context.Context,
 	return nil
 }
 
+// sessionShareFlags returns the session states that prevent the session from being shared or migrated.
+func (s *session) sessionShareFlags() sessionstates.ShareFlags {
+	var flags sessionstates.ShareFlags
+	s.txn.mu.Lock()
+	valid := s.txn.Valid()
+	s.txn.mu.Unlock()
+	if valid {
+		flags |= sessionstates.ShareFlagActiveTxn
+	}
+	// Check temporary tables here to avoid circle dependency.
+	if s.sessionVars.LocalTemporaryTables != nil {
+		localTempTables := s.sessionVars.LocalTemporaryTables.(*infoschema.SessionTables)
+		if localTempTables.Count() > 0 {
+			flags |= sessionstates.ShareFlagLocalTemporaryTables
+		}
+	}
+	if len(s.advisoryLocks) > 0 {
+		flags |= sessionstates.ShareFlagAdvisoryLocks
+	}
+	if len(s.lockedTables) > 0 {
+		flags |= sessionstates.ShareFlagLockedTables
+	}
+	if s.InSandBoxMode() {
+		flags |= sessionstates.ShareFlagSandBoxMode
+	}
+	return flags
+}
+
+// ResetSessionStates implements the sessionctx.Context interface.
+// It resets the session like COM_RESET_CONNECTION, but keeps the prepared statements, the plan cache
+// and the current database, so proxies can reuse the session for another client at a lower cost.
+func (s *session) ResetSessionStates(ctx context.Context) error {
+	s.sessionVars.SetInTxn(false)
+	s.RollbackTxn(ctx)
+	s.ReleaseAllAdvisoryLocks()
+	if s.HasLockedTables() {
+		if err := domain.GetDomain(s).DDLExecutor().UnlockTables(s, s.GetAllTableLocks()); err != nil {
+			return err
+		}
+	}
+	vars := s.sessionVars
+	vars.LocalTemporaryTables = nil
+	vars.TemporaryTableData = nil
+	vars.UserVars = variable.NewUserVars()
+	vars.SequenceState = variable.NewSequenceState()
+
+	// Reset the session variables to the global values or the default values, just like a new session.
+	sessionCache, err := domain.GetDomain(s).GetSessionCache()
+	if err != nil {
+		return err
+	}
+	if vars.ClientCapability&mysql.ClientInteractive > 0 {
+		if varVal, ok := sessionCache[variable.InteractiveTimeout]; ok {
+			sessionCache[variable.WaitTimeout] = varVal
+		}
+	}
+	for _, name := range variable.OrderByDependency(sessionCache) {
+		val := sessionCache[name]
+		if oldVal, ok := vars.GetSystemVar(name); ok && oldVal == val {
+			continue
+		}
+		if err := vars.SetSystemVarWithRelaxedValidation(name, val); err != nil {
+			if variable.ErrUnknownSystemVar.Equal(err) {
+				continue
+			}
+			return err
+		}
+	}
+	vars.ShareFlags = s.sessionShareFlags()
+	return nil
+}
+
 // DecodeSessionStates implements SessionStatesHandler.DecodeSessionStates interface.
 func (s *session) DecodeSessionStates(ctx context.Context,
 	_ sessionctx.Context, sessionStates *sessionstates.SessionStates) error {
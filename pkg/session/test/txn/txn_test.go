@@ -318,6 +318,35 @@ func TestAutoCommitRespectsReadOnly(t *testing.T) {
 	tk1.MustExec("SET GLOBAL tidb_super_read_only = 0")
 }
 
+func TestInstanceReadOnly(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	require.NoError(t, tk.Session().Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil, nil))
+	tk.MustExec("create table test.instance_read_only (a int)")
+	tk.MustExec("insert into test.instance_read_only values (1)")
+	tk.MustExec("grant RESTRICTED_REPLICA_WRITER_ADMIN on *.* to 'root'")
+	tk.MustExec("begin")
+	tk.MustExec("insert into test.instance_read_only values (2)")
+
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("set global tidb_instance_read_only = on")
+	defer tk2.MustExec("set global tidb_instance_read_only = off")
+	tk2.MustQuery("show global variables like 'tidb_instance_read_only'").Check(testkit.Rows("tidb_instance_read_only ON"))
+	// The transaction started before the instance is fenced can't be committed.
+	err := tk.ExecToErr("commit")
+	require.True(t, terror.ErrorEqual(err, plannererrors.ErrInstanceReadOnly), fmt.Sprintf("err %v", err))
+	err = tk.ExecToErr("insert into test.instance_read_only values (3)")
+	require.True(t, terror.ErrorEqual(err, plannererrors.ErrInstanceReadOnly), fmt.Sprintf("err %v", err))
+	// The privilege can't skip the check of the instance read-only mode.
+	err = tk.ExecToErr("delete from test.instance_read_only")
+	require.True(t, terror.ErrorEqual(err, plannererrors.ErrInstanceReadOnly), fmt.Sprintf("err %v", err))
+	tk.MustQuery("select * from test.instance_read_only").Check(testkit.Rows("1"))
+
+	tk2.MustExec("set global tidb_instance_read_only = off")
+	tk.MustExec("insert into test.instance_read_only values (4)")
+	tk.MustQuery("select * from test.instance_read_only").Sort().Check(testkit.Rows("1", "4"))
+}
+
 func TestTxnRetryErrMsg(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	setTxnTk := testkit.NewTestKit(t, store)
@@ -244,6 +244,7 @@ func finishStmt(ctx context.Context, se *session, meetsErr error, sql sqlexec.St
 		// Reset txn state to invalid to dispose the pending start ts.
 		se.txn.changeToInvalid()
 	}
+	sessVars.ShareFlags = se.sessionShareFlags()
 	if err != nil {
 		return err
 	}
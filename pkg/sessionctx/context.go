@@ -86,6 +86,9 @@ type Context interface {
 	tablelock.TableLockContext
 	// RollbackTxn rolls back the current transaction.
 	RollbackTxn(ctx context.Context)
+	// ResetSessionStates resets the session states like COM_RESET_CONNECTION, but keeps the prepared statements
+	// and the plan cache.
+	ResetSessionStates(ctx context.Context) error
 	// CommitTxn commits the current transaction.
 	// buffered KV changes will be discarded, call StmtCommit if you want to commit them.
 	CommitTxn(ctx context.Context) error
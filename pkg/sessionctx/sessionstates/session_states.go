@@ -39,6 +39,51 @@ const (
 	StateBinding
 )
 
+// ShareFlags is a bit set that indicates why a session can't be shared by multiple clients or migrated to
+// another TiDB instance. Proxies can multiplex a session only when all the flags are unset.
+type ShareFlags uint64
+
+// These flags represent the session states that prevent the session from being shared.
+const (
+	// ShareFlagActiveTxn means the session has an active transaction.
+	ShareFlagActiveTxn ShareFlags = 1 << iota
+	// ShareFlagLocalTemporaryTables means the session has local temporary tables.
+	ShareFlagLocalTemporaryTables
+	// ShareFlagAdvisoryLocks means the session holds advisory locks.
+	ShareFlagAdvisoryLocks
+	// ShareFlagLockedTables means the session holds table locks.
+	ShareFlagLockedTables
+	// ShareFlagSandBoxMode means the session is in sandbox mode.
+	ShareFlagSandBoxMode
+)
+
+// Shareable returns whether the session can be shared by multiple clients.
+func (f ShareFlags) Shareable() bool {
+	return f == 0
+}
+
+// Err returns the error that explains why the session can't be migrated, or nil if it can be migrated.
+func (f ShareFlags) Err() error {
+	switch {
+	case f&ShareFlagActiveTxn != 0:
+		// Transaction status is hard to encode, so we do not support it.
+		return ErrCannotMigrateSession.GenWithStackByArgs("session has an active transaction")
+	case f&ShareFlagLocalTemporaryTables != 0:
+		// Data in local temporary tables is hard to encode, so we do not support it.
+		return ErrCannotMigrateSession.GenWithStackByArgs("session has local temporary tables")
+	case f&ShareFlagAdvisoryLocks != 0:
+		// The advisory locks will be released when the session is closed.
+		return ErrCannotMigrateSession.GenWithStackByArgs("session has advisory locks")
+	case f&ShareFlagLockedTables != 0:
+		// The TableInfo stores session ID and server ID, so the session cannot be migrated.
+		return ErrCannotMigrateSession.GenWithStackByArgs("session has locked tables")
+	case f&ShareFlagSandBoxMode != 0:
+		// It's insecure to migrate sandBoxMode because users can fake it.
+		return ErrCannotMigrateSession.GenWithStackByArgs("session is in sandbox mode")
+	}
+	return nil
+}
+
 // PreparedStmtInfo contains the information about prepared statements, both text and binary protocols.
 type PreparedStmtInfo struct {
 	Name       string `json:"name,omitempty"`
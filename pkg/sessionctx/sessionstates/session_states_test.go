@@ -1630,6 +1630,45 @@ func TestInvalidSysVar(t *testing.T) {
 	tk.MustQuery("select @@max_prepared_stmt_count").Check(testkit.Rows("-1"))
 }
 
+func TestResetSessionStates(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key)")
+	tk.MustExec("prepare stmt from 'select * from t where a = ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	tk.MustQuery("select @@tidb_session_share_flags").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@max_execution_time = 100")
+	tk.MustExec("create temporary table tmp(id int)")
+	tk.MustQuery("select get_lock('lock1', 0)").Check(testkit.Rows("1"))
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values(1)")
+	flags := sessionstates.ShareFlagActiveTxn | sessionstates.ShareFlagLocalTemporaryTables | sessionstates.ShareFlagAdvisoryLocks
+	tk.MustQuery("select @@tidb_session_share_flags").Check(testkit.Rows(strconv.FormatUint(uint64(flags), 10)))
+	require.False(t, flags.Shareable())
+	err := tk.QueryToErr("show session_states")
+	errEqualsCode(t, err, errno.ErrCannotMigrateSession)
+
+	tk.MustExec("reset session_states")
+	tk.MustQuery("select @@tidb_session_share_flags").Check(testkit.Rows("0"))
+	// The transaction is rolled back, and the user variables, session variables, temporary tables
+	// and advisory locks are all cleared.
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+	tk.MustQuery("select @a, @@max_execution_time").Check(testkit.Rows("<nil> 0"))
+	tk.MustGetErrCode("select * from tmp", errno.ErrNoSuchTable)
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustQuery("select get_lock('lock1', 0)").Check(testkit.Rows("1"))
+	tk2.MustQuery("select release_lock('lock1')").Check(testkit.Rows("1"))
+	// The current database, prepared statements and the plan cache are kept.
+	tk.MustQuery("select database()").Check(testkit.Rows("test"))
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	showSessionStatesAndSet(t, tk, tk2)
+}
+
 func showSessionStatesAndSet(t *testing.T, tk1, tk2 *testkit.TestKit) {
 	rows := tk1.MustQuery("show session_states").Rows()
 	require.Len(t, rows, 1)
@@ -331,6 +331,9 @@ type StatementContext struct {
 
 	// InVerboseExplain indicates the statement is "explain format='verbose' ...".
 	InVerboseExplain bool
+	// StatsOverlay is the stats overlaid on the stats cache by "explain with stats ('<json>') ...". It's only used in
+	// the planning of the explained statement.
+	StatsOverlay any
 
 	// EnableOptimizeTrace indicates whether enable optimizer trace by 'trace plan statement'
 	EnableOptimizeTrace bool
@@ -362,6 +365,9 @@ type StatementContext struct {
 		Timeout time.Duration
 		// NeededItems stores the columns/indices whose stats are needed for planner.
 		NeededItems []model.StatsLoadItem
+		// PlanNeededItems stores all the columns/indices whose stats are needed for planner, including the loaded
+		// ones. They are kept loaded while the plan is in the plan cache.
+		PlanNeededItems []model.StatsLoadItem
 		// ResultCh to receive stats loading results
 		ResultCh []<-chan singleflight.Result
 		// LoadStartTime is to record the load start time to calculate latency
@@ -414,6 +420,9 @@ type StatementContext struct {
 
 	// TableStats stores the visited runtime table stats by table id during query
 	TableStats map[int64]any
+	// DerivedSelectivities caches the selectivities of the filters on the tables derived by the optimizer, so the
+	// references of the same CTE or subquery in the statement reuse them. The keys are built by the optimizer.
+	DerivedSelectivities map[string]float64
 	// useChunkAlloc indicates whether statement use chunk alloc
 	useChunkAlloc bool
 	// Check if TiFlash read engine is removed due to strict sql mode.
@@ -1223,6 +1232,76 @@ type UsedStatsInfoForTable struct {
 	ColumnStatsLoadStatus map[int64]string
 	IndexStatsLoadStatus  map[int64]string
 	ColAndIdxStatus       any
+	// Outdated is whether the modify count exceeds tidb_pseudo_estimate_ratio of the row count.
+	Outdated bool
+	// ColumnStatsUsed and IndexStatsUsed are the column and index stats used to estimate the row counts.
+	// They are only recorded for EXPLAIN FORMAT='stats'.
+	ColumnStatsUsed map[int64]*UsedStatsItem
+	IndexStatsUsed  map[int64]*UsedStatsItem
+}
+
+// UsedStatsItem records the stats of a column or an index used to estimate the row counts.
+type UsedStatsItem struct {
+	// Status is the load status of the stats, or "missing" if there are no stats. It's empty if the stats are
+	// fully loaded.
+	Status   string
+	Version  uint64
+	StatsVer int64
+	NDV      int64
+	Buckets  int
+	TopN     int
+}
+
+// FormatDetailsForExplain formats the details of the used stats for EXPLAIN FORMAT='stats', e.g.
+// "version:451, realtime count:100, modify count:3, columns:[a:{buckets:3, topn:2, ndv:10, stats ver:2, version:451}]".
+func (s *UsedStatsInfoForTable) FormatDetailsForExplain() string {
+	var b strings.Builder
+	if s.Version == 0 {
+		b.WriteString("version:pseudo")
+	} else {
+		b.WriteString("version:")
+		b.WriteString(strconv.FormatUint(s.Version, 10))
+	}
+	fmt.Fprintf(&b, ", realtime count:%d, modify count:%d", s.RealtimeCount, s.ModifyCount)
+	if s.Outdated {
+		b.WriteString(", outdated")
+	}
+	s.writeUsedStatsItems(&b, "columns", s.ColumnStatsUsed, false)
+	s.writeUsedStatsItems(&b, "indexes", s.IndexStatsUsed, true)
+	return b.String()
+}
+
+func (s *UsedStatsInfoForTable) writeUsedStatsItems(b *strings.Builder, kind string, items map[int64]*UsedStatsItem, isIndex bool) {
+	if len(items) == 0 {
+		return
+	}
+	keys := maps.Keys(items)
+	slices.Sort(keys)
+	strs := make([]string, 0, len(keys))
+	for _, id := range keys {
+		var name string
+		if s.TblInfo != nil {
+			if isIndex {
+				name = s.TblInfo.FindIndexNameByID(id)
+			} else {
+				name = s.TblInfo.FindColumnNameByID(id)
+			}
+		}
+		if len(name) == 0 {
+			name = "ID " + strconv.FormatInt(id, 10)
+		}
+		item := items[id]
+		if item.Status == "missing" {
+			strs = append(strs, name+":missing")
+			continue
+		}
+		str := fmt.Sprintf("%s:{buckets:%d, topn:%d, ndv:%d, stats ver:%d, version:%d", name, item.Buckets, item.TopN, item.NDV, item.StatsVer, item.Version)
+		if item.Status != "" {
+			str += ", " + item.Status
+		}
+		strs = append(strs, str+"}")
+	}
+	fmt.Fprintf(b, ", %s:[%s]", kind, strings.Join(strs, ", "))
 }
 
 // FormatForExplain format the content in the format expected to be printed in the execution plan.
@@ -381,6 +381,11 @@ func (tc *TransactionContext) UpdateDeltaForTable(
 	item := tc.TableDeltaMap[physicalTableID]
 	item.Delta += delta
 	item.Count += count
+	if delta > 0 {
+		item.InsertCount += delta
+	} else {
+		item.DeleteCount -= delta
+	}
 	item.TableID = physicalTableID
 	if cols != nil {
 		item.ColSize = cols.UpdateColSizeMap(item.ColSize)
@@ -388,6 +393,13 @@ func (tc *TransactionContext) UpdateDeltaForTable(
 	tc.TableDeltaMap[physicalTableID] = item
 }
 
+// GetDeltaForTable returns the delta info of the table in the transaction.
+func (tc *TransactionContext) GetDeltaForTable(physicalTableID int64) TableDelta {
+	tc.tdmLock.Lock()
+	defer tc.tdmLock.Unlock()
+	return tc.TableDeltaMap[physicalTableID]
+}
+
 // GetKeyInPessimisticLockCache gets a key in pessimistic lock cache.
 func (tc *TransactionContext) GetKeyInPessimisticLockCache(key kv.Key) (val []byte, ok bool) {
 	if tc.pessimisticLockCache == nil && tc.CurrentStmtPessimisticLockCache == nil {
@@ -803,6 +815,8 @@ type SessionVars struct {
 	SysErrorCount uint16
 	// nonPreparedPlanCacheStmts stores PlanCacheStmts for non-prepared plan cache.
 	nonPreparedPlanCacheStmts *kvcache.SimpleLRUCache
+	// inListSelCache caches the selectivities of the long in-lists estimated item by item.
+	inListSelCache *kvcache.SimpleLRUCache
 	// PreparedStmts stores prepared statement.
 	PreparedStmts        map[uint32]any
 	PreparedStmtNameToID map[string]uint32
@@ -1027,6 +1041,15 @@ type SessionVars struct {
 	// EnableCorrelationAdjustment is used to indicate if correlation adjustment is enabled.
 	EnableCorrelationAdjustment bool
 
+	// EnableFKJoinEstimation indicates whether to estimate the joins on the foreign keys by the foreign key stats.
+	EnableFKJoinEstimation bool
+
+	// AnalyzeValidateForeignKeys indicates whether ANALYZE validates the foreign keys by the sampled values.
+	AnalyzeValidateForeignKeys bool
+
+	// AnalyzeCollectTrigramStats indicates whether ANALYZE collects the trigram stats of the string columns.
+	AnalyzeCollectTrigramStats bool
+
 	// CorrelationExpFactor is used to control the heuristic approach of row count estimation when CorrelationThreshold is not met.
 	CorrelationExpFactor int
 
@@ -1321,6 +1344,10 @@ type SessionVars struct {
 	// LastQueryInfo keeps track the info of last query.
 	LastQueryInfo sessionstates.QueryInfo
 
+	// ShareFlags keeps track of the session states that prevent the session from being shared,
+	// which are updated after each statement.
+	ShareFlags sessionstates.ShareFlags
+
 	// LastDDLInfo keeps track the info of last DDL.
 	LastDDLInfo sessionstates.LastDDLInfo
 
@@ -1498,6 +1525,17 @@ type SessionVars struct {
 	// EnableNonPreparedPlanCacheForDML indicates whether to enable non-prepared plan cache for DML statements.
 	EnableNonPreparedPlanCacheForDML bool
 
+	// EnableNonPreparedPlanCacheForSubquery indicates whether to enable non-prepared plan cache for the queries with
+	// simple sub-queries.
+	EnableNonPreparedPlanCacheForSubquery bool
+
+	// NonPreparedPlanCacheMaxJoinTables is the maximum number of tables in a query cached by non-prepared plan cache.
+	NonPreparedPlanCacheMaxJoinTables int
+
+	// NonPreparedPlanCacheMaxInListLen is the maximum number of values in an in-list of a query cached by non-prepared
+	// plan cache.
+	NonPreparedPlanCacheMaxInListLen int
+
 	// EnableFuzzyBinding indicates whether to enable fuzzy binding.
 	EnableFuzzyBinding bool
 
@@ -1580,6 +1618,13 @@ type SessionVars struct {
 	// EnablePlanReplayedContinuesCapture indicates whether enabled plan replayer continues capture
 	EnablePlanReplayedContinuesCapture bool
 
+	// PlanReplayerStatsFormat indicates the format of the stats dumped by the plan replayer.
+	// The binary format is much smaller and faster to dump and load for the tables with lots of partitions.
+	PlanReplayerStatsFormat string
+
+	// EnableErrorAttributes indicates whether to append the retryability attributes to the error messages.
+	EnableErrorAttributes bool
+
 	// PlanReplayerFinishedTaskKey used to record the finished plan replayer task key in order not to record the
 	// duplicate task in plan replayer continues capture
 	PlanReplayerFinishedTaskKey map[replayer.PlanReplayerTaskKey]struct{}
@@ -1665,6 +1710,32 @@ type SessionVars struct {
 	// AnalyzeSkipColumnTypes indicates the column types whose statistics would not be collected when executing the ANALYZE command.
 	AnalyzeSkipColumnTypes map[string]struct{}
 
+	// AnalyzeAdaptiveTopNLimit indicates the upper bound of the TopN size chosen adaptively for each column by ANALYZE.
+	// If it's positive and the TopN size is not specified, ANALYZE keeps the values which are significantly more
+	// frequent than the rest, up to this limit, instead of using the fixed default TopN size.
+	AnalyzeAdaptiveTopNLimit int
+
+	// AnalyzeAdaptiveBucketLimit indicates the upper bound of the number of the histogram buckets chosen adaptively
+	// for each column and index by ANALYZE. If it's positive, the number of the buckets grows with the NDV and the
+	// number of the samples instead of using the fixed default, and the number specified by the user bounds it.
+	AnalyzeAdaptiveBucketLimit int
+
+	// AnalyzeBlockSampleRate indicates the default fraction of the regions scanned by ANALYZE if the BLOCKSAMPLE
+	// option is not specified. The whole sampled regions are scanned instead of sampling rows from all the regions.
+	AnalyzeBlockSampleRate float64
+
+	// AnalyzeReadNearbyReplica indicates whether ANALYZE reads the replicas in the zone of the TiDB instance instead
+	// of the leaders, if the leaders of the table are placed in another zone or region by the placement rules.
+	AnalyzeReadNearbyReplica bool
+
+	// AnalyzeAutoTiFlash indicates whether ANALYZE reads the data from the TiFlash replica if it's available and the
+	// TIFLASH option is not specified. The columnar data is cheaper to scan for the wide tables.
+	AnalyzeAutoTiFlash bool
+
+	// AnalyzeDefaults is the default analyze options of the ANALYZE statements issued in the session, which are used
+	// if the options are neither specified in the statement nor persisted for the table.
+	AnalyzeDefaults AnalyzeDefaults
+
 	// SkipMissingPartitionStats controls how to handle missing partition stats when merging partition stats to global stats.
 	// When set to true, skip missing partition stats and continue to merge other partition stats to global stats.
 	// When set to false, give up merging partition stats to global stats.
@@ -1679,6 +1750,43 @@ type SessionVars struct {
 	// OptObjectiveDeterminate: The optimizer doesn't consider the real-time stats.
 	OptObjective string
 
+	// StatsLoadTimeoutMode indicates which stats the optimizer uses for a table when the sync stats loading times out
+	// and none of its column or index stats are loaded. For now, the possible values and corresponding behaviors are:
+	// StatsLoadTimeoutModeStrict: The optimizer uses the pseudo stats for the whole table.
+	// StatsLoadTimeoutModeDegraded: The default value. The optimizer uses the row count in the stats meta, the stats
+	// which are already loaded, and the pseudo stats for the others if the table is analyzed.
+	StatsLoadTimeoutMode string
+
+	// StaleStatsRiskMode indicates whether the optimizer trusts the estimations on the tables whose stats healthy is
+	// below StaleStatsHealthyThreshold. For now, the possible values and corresponding behaviors are:
+	// StaleStatsRiskModeTrust: The default value. The optimizer trusts the estimations as usual.
+	// StaleStatsRiskModeRobust: The optimizer prefers the robust operators on the stale tables, i.e. it prefers the
+	// index joins to the hash joins and merge joins, and doesn't push the aggregations down across the joins.
+	StaleStatsRiskMode string
+	// StaleStatsHealthyThreshold is the threshold of the stats healthy below which the stats of a table are stale.
+	StaleStatsHealthyThreshold int64
+
+	// ExtrapolateMonotonicHistogram indicates whether the optimizer extrapolates the histograms of the monotonically
+	// increasing columns, e.g. the auto-increment IDs and the insertion time, for the out-of-range estimation. If it's
+	// on, the rows inserted since the last analyze are assumed to be appended after the max value of the histogram,
+	// i.e. the last bucket is extended by them, instead of decreasing away from the max value.
+	ExtrapolateMonotonicHistogram bool
+
+	// PinnedStats records the historical stats versions pinned by tidb_pin_stats, keyed by `db.table` in lower case.
+	PinnedStats map[string]uint64
+
+	// PinnedStatsCache caches the historical stats loaded for the pinned tables in the session, keyed by physical ID.
+	// It's reset whenever tidb_pin_stats is changed.
+	PinnedStatsCache map[int64]any
+
+	// IgnoreStatsTables records the tables whose stats are ignored by tidb_ignore_stats, keyed by `db.table` in lower
+	// case. The optimizer uses the pseudo stats for them.
+	IgnoreStatsTables map[string]struct{}
+
+	// TemporaryTableStats keeps the stats of the temporary tables in the session memory, keyed by table ID. The values
+	// are *statistics.TemporaryTableStats, use interface to avoid circle dependency.
+	TemporaryTableStats map[int64]any
+
 	CompressionAlgorithm int
 	CompressionLevel     int
 
@@ -2141,6 +2249,7 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 		allowInSubqToJoinAndAgg:       DefOptInSubqToJoinAndAgg,
 		preferRangeScan:               DefOptPreferRangeScan,
 		EnableCorrelationAdjustment:   DefOptEnableCorrelationAdjustment,
+		EnableFKJoinEstimation:        DefOptEnableFKJoinEstimation,
 		LimitPushDownThreshold:        DefOptLimitPushDownThreshold,
 		CorrelationThreshold:          DefOptCorrelationThreshold,
 		CorrelationExpFactor:          DefOptCorrelationExpFactor,
@@ -2190,6 +2299,7 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 		Enable1PC:                     DefTiDBEnable1PC,
 		GuaranteeLinearizability:      DefTiDBGuaranteeLinearizability,
 		AnalyzeVersion:                DefTiDBAnalyzeVersion,
+		AnalyzeDefaults:               AnalyzeDefaults{TopN: DefTiDBAnalyzeDefaultTopN},
 		EnableIndexMergeJoin:          DefTiDBEnableIndexMergeJoin,
 		AllowFallbackToTiKV:           make(map[kv.StoreType]struct{}),
 		CTEMaxRecursionDepth:          DefCTEMaxRecursionDepth,
@@ -2206,6 +2316,7 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 		ForeignKeyChecks:              DefTiDBForeignKeyChecks,
 		HookContext:                   hctx,
 		EnableReuseChunk:              DefTiDBEnableReusechunk,
+		PlanReplayerStatsFormat:       DefTiDBPlanReplayerStatsFormat,
 		preUseChunkAlloc:              DefTiDBUseAlloc,
 		chunkPool:                     nil,
 		mppExchangeCompressionMode:    DefaultExchangeCompressionMode,
@@ -2250,6 +2361,8 @@ func NewSessionVars(hctx HookContext) *SessionVars {
 		MaxPagingSize:      DefMaxPagingSize,
 	}
 	vars.DMLBatchSize = DefDMLBatchSize
+	vars.NonPreparedPlanCacheMaxJoinTables = DefTiDBNonPreparedPlanCacheMaxJoinTables
+	vars.NonPreparedPlanCacheMaxInListLen = DefTiDBNonPreparedPlanCacheMaxInListLen
 	vars.AllowBatchCop = DefTiDBAllowBatchCop
 	vars.allowMPPExecution = DefTiDBAllowMPPExecution
 	vars.HashExchangeWithNewCollation = DefTiDBHashExchangeWithNewCollation
@@ -2599,6 +2712,36 @@ func (k planCacheStmtKey) Hash() []byte {
 	return []byte(k)
 }
 
+// inListSelCacheCapacity is the max number of the in-list selectivities cached in a session.
+const inListSelCacheCapacity = 100
+
+type inListSelKey string
+
+func (k inListSelKey) Hash() []byte {
+	return []byte(k)
+}
+
+// GetInListSelectivity gets the cached selectivity of an in-list estimated item by item.
+func (s *SessionVars) GetInListSelectivity(key string) (float64, bool) {
+	if s.inListSelCache == nil {
+		return 0, false
+	}
+	sel, ok := s.inListSelCache.Get(inListSelKey(key))
+	if !ok {
+		return 0, false
+	}
+	return sel.(float64), true
+}
+
+// SetInListSelectivity caches the selectivity of an in-list estimated item by item. The key identifies the column, the
+// version of its stats and the items of the in-list.
+func (s *SessionVars) SetInListSelectivity(key string, sel float64) {
+	if s.inListSelCache == nil {
+		s.inListSelCache = kvcache.NewSimpleLRUCache(inListSelCacheCapacity, 0, 0)
+	}
+	s.inListSelCache.Put(inListSelKey(key), sel)
+}
+
 // AddNonPreparedPlanCacheStmt adds this PlanCacheStmt into non-preapred plan-cache stmt cache
 func (s *SessionVars) AddNonPreparedPlanCacheStmt(sql string, stmt any) {
 	if s.nonPreparedPlanCacheStmts == nil {
@@ -2904,23 +3047,43 @@ func (s *SessionVars) SetResourceGroupName(groupName string) {
 	s.ResourceGroupName = groupName
 }
 
+// AnalyzeDefaults is the default analyze options set by tidb_analyze_default_sample_rate,
+// tidb_analyze_default_buckets, tidb_analyze_default_topn and tidb_analyze_default_column_choice.
+// It's copied to the jobs enqueued for auto analyze so that they inherit the options of the session.
+type AnalyzeDefaults struct {
+	// SampleRate is 0 if it's not set.
+	SampleRate float64
+	// Buckets is 0 if it's not set.
+	Buckets uint64
+	// TopN is -1 if it's not set, since 0 is a valid TopN size.
+	TopN int64
+	// ColumnChoice is 'ALL' or 'PREDICATE', or an empty string if it's not set.
+	ColumnChoice string
+}
+
 // TableDelta stands for the changed count for one table or partition.
 type TableDelta struct {
-	Delta    int64
-	Count    int64
-	ColSize  map[int64]int64
-	InitTime time.Time // InitTime is the time that this delta is generated.
-	TableID  int64
+	Delta int64
+	Count int64
+	// InsertCount and DeleteCount are the numbers of the inserted and the deleted rows,
+	// whose difference is Delta.
+	InsertCount int64
+	DeleteCount int64
+	ColSize     map[int64]int64
+	InitTime    time.Time // InitTime is the time that this delta is generated.
+	TableID     int64
 }
 
 // Clone returns a cloned TableDelta.
 func (td TableDelta) Clone() TableDelta {
 	return TableDelta{
-		Delta:    td.Delta,
-		Count:    td.Count,
-		ColSize:  maps.Clone(td.ColSize),
-		InitTime: td.InitTime,
-		TableID:  td.TableID,
+		Delta:       td.Delta,
+		Count:       td.Count,
+		InsertCount: td.InsertCount,
+		DeleteCount: td.DeleteCount,
+		ColSize:     maps.Clone(td.ColSize),
+		InitTime:    td.InitTime,
+		TableID:     td.TableID,
 	}
 }
 
@@ -3846,10 +4009,11 @@ type RuntimeFilterType int64
 
 // In type of runtime filter, like "t.k1 in (?)"
 // MinMax type of runtime filter, like "t.k1 < ? and t.k1 > ?"
+// BloomFilter type of runtime filter, which is a bloom filter of the build side keys
 const (
 	In RuntimeFilterType = iota
 	MinMax
-	// todo BloomFilter, bf/in
+	BloomFilter
 )
 
 // String convert Runtime Filter Type to String name
@@ -3859,6 +4023,8 @@ func (rfType RuntimeFilterType) String() string {
 		return "IN"
 	case MinMax:
 		return "MIN_MAX"
+	case BloomFilter:
+		return "BLOOM_FILTER"
 	default:
 		return ""
 	}
@@ -3868,13 +4034,15 @@ func (rfType RuntimeFilterType) String() string {
 // If name is legal, it will return Runtime Filter Type and true
 // Else, it will return -1 and false
 // The second param means the convert is ok or not. True is ok, false means it is illegal name
-// At present, we only support two names: "IN" and "MIN_MAX"
+// At present, we only support three names: "IN", "MIN_MAX" and "BLOOM_FILTER"
 func RuntimeFilterTypeStringToType(name string) (RuntimeFilterType, bool) {
 	switch name {
 	case "IN":
 		return In, true
 	case "MIN_MAX":
 		return MinMax, true
+	case "BLOOM_FILTER":
+		return BloomFilter, true
 	default:
 		return -1, false
 	}
@@ -3951,6 +4119,39 @@ const (
 	OptObjectiveDeterminate = "determinate"
 )
 
+const (
+	// PlanCacheStatsInvalidationAnyChange is a possible value and the default value for
+	// TiDBPlanCacheStatsInvalidationPolicy.
+	PlanCacheStatsInvalidationAnyChange = "any_change"
+	// PlanCacheStatsInvalidationRowCountChange is a possible value for TiDBPlanCacheStatsInvalidationPolicy.
+	PlanCacheStatsInvalidationRowCountChange = "row_count_change"
+	// PlanCacheStatsInvalidationPseudoOnly is a possible value for TiDBPlanCacheStatsInvalidationPolicy.
+	PlanCacheStatsInvalidationPseudoOnly = "pseudo_only"
+)
+
+const (
+	// StaleStatsRiskModeTrust is a possible value and the default value for TiDBOptStaleStatsRiskMode.
+	// Please see comments of SessionVars.StaleStatsRiskMode for details.
+	StaleStatsRiskModeTrust = "trust"
+	// StaleStatsRiskModeRobust is a possible value for TiDBOptStaleStatsRiskMode.
+	StaleStatsRiskModeRobust = "robust"
+)
+
+const (
+	// StatsLoadTimeoutModeStrict is a possible value for TiDBStatsLoadTimeoutMode.
+	// Please see comments of SessionVars.StatsLoadTimeoutMode for details.
+	StatsLoadTimeoutModeStrict = "strict"
+	// StatsLoadTimeoutModeDegraded is a possible value and the default value for TiDBStatsLoadTimeoutMode.
+	StatsLoadTimeoutModeDegraded = "degraded"
+)
+
+const (
+	// PlanReplayerStatsFormatJSON is a possible value and the default value for TiDBPlanReplayerStatsFormat.
+	PlanReplayerStatsFormatJSON = "json"
+	// PlanReplayerStatsFormatBinary is a possible value for TiDBPlanReplayerStatsFormat.
+	PlanReplayerStatsFormatBinary = "binary"
+)
+
 // GetOptObjective return the session variable "tidb_opt_objective".
 // Please see comments of SessionVars.OptObjective for details.
 func (s *SessionVars) GetOptObjective() string {
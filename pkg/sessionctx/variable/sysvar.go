@@ -267,6 +267,9 @@ var defaultSysVars = []*SysVar{
 	{Scope: ScopeSession, Name: TiDBLastTxnInfo, Value: "", ReadOnly: true, GetSession: func(s *SessionVars) (string, error) {
 		return s.LastTxnInfo, nil
 	}},
+	{Scope: ScopeSession, Name: TiDBSessionShareFlags, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint64, ReadOnly: true, GetSession: func(s *SessionVars) (string, error) {
+		return strconv.FormatUint(uint64(s.ShareFlags), 10), nil
+	}},
 	{Scope: ScopeSession, Name: TiDBLastQueryInfo, Value: "", ReadOnly: true, GetSession: func(s *SessionVars) (string, error) {
 		info, err := json.Marshal(s.LastQueryInfo)
 		if err != nil {
@@ -454,6 +457,12 @@ var defaultSysVars = []*SysVar{
 	}, GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
 		return BoolToOnOff(ProcessGeneralLog.Load()), nil
 	}},
+	{Scope: ScopeInstance, Name: TiDBInstanceReadOnly, Value: BoolToOnOff(DefTiDBInstanceReadOnly), Type: TypeBool, SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+		InstanceReadOnly.Store(TiDBOptOn(val))
+		return nil
+	}, GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+		return BoolToOnOff(InstanceReadOnly.Load()), nil
+	}},
 	{Scope: ScopeSession, Name: TiDBSlowTxnLogThreshold, Value: strconv.Itoa(logutil.DefaultSlowTxnThreshold),
 		Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
 			s.SlowTxnThreshold = TidbOptUint64(val, logutil.DefaultSlowTxnThreshold)
@@ -853,6 +862,8 @@ var defaultSysVars = []*SysVar{
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskMaxTime, Value: strconv.Itoa(DefTiDBEvolvePlanTaskMaxTime), Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64},
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskStartTime, Value: DefTiDBEvolvePlanTaskStartTime, Type: TypeTime},
 	{Scope: ScopeGlobal, Name: TiDBEvolvePlanTaskEndTime, Value: DefTiDBEvolvePlanTaskEndTime, Type: TypeTime},
+	{Scope: ScopeGlobal, Name: TiDBCaptureRegressedPlanBaselines, Value: BoolToOnOff(DefTiDBCaptureRegressedPlanBaselines), Type: TypeBool},
+	{Scope: ScopeGlobal, Name: TiDBPlanBaselineRegressionRatio, Value: strconv.FormatFloat(DefTiDBPlanBaselineRegressionRatio, 'f', -1, 64), Type: TypeFloat, MinValue: 1, MaxValue: math.MaxUint32},
 	{Scope: ScopeGlobal, Name: TiDBStoreLimit, Value: strconv.FormatInt(atomic.LoadInt64(&config.GetGlobalConfig().TiKVClient.StoreLimit), 10), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
 		return strconv.FormatInt(tikvstore.StoreLimit.Load(), 10), nil
 	}, SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
@@ -1041,6 +1052,12 @@ var defaultSysVars = []*SysVar{
 		topsqlstate.GlobalState.MaxCollect.Store(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal, Name: TiDBEnableTopSQLLocalStorage, Value: BoolToOnOff(topsqlstate.DefTiDBTopSQLLocalStorageEnable), Type: TypeBool, GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+		return BoolToOnOff(topsqlstate.GlobalState.LocalStorageEnable.Load()), nil
+	}, SetGlobal: func(_ context.Context, vars *SessionVars, s string) error {
+		topsqlstate.GlobalState.LocalStorageEnable.Store(TiDBOptOn(s))
+		return nil
+	}},
 	{Scope: ScopeGlobal, Name: SkipNameResolve, Value: Off, Type: TypeBool},
 	{Scope: ScopeGlobal, Name: DefaultAuthPlugin, Value: mysql.AuthNativePassword, Type: TypeEnum, PossibleValues: []string{mysql.AuthNativePassword, mysql.AuthCachingSha2Password, mysql.AuthTiDBSM3Password, mysql.AuthLDAPSASL, mysql.AuthLDAPSimple}},
 	{
@@ -1092,6 +1109,45 @@ var defaultSysVars = []*SysVar{
 			return normalizedValue, nil
 		},
 	},
+	{Scope: ScopeGlobal, Name: TiDBPredicateColumnRetentionDays, Value: strconv.Itoa(DefTiDBPredicateColumnRetentionDays), Type: TypeInt, MinValue: 0, MaxValue: 3650,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return strconv.FormatInt(PredicateColumnRetentionDays.Load(), 10), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			PredicateColumnRetentionDays.Store(TidbOptInt64(val, DefTiDBPredicateColumnRetentionDays))
+			return nil
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBEnableBackgroundMergeGlobalStats, Value: BoolToOnOff(DefTiDBEnableBackgroundMergeGlobalStats), Type: TypeBool,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return BoolToOnOff(EnableBackgroundMergeGlobalStats.Load()), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			EnableBackgroundMergeGlobalStats.Store(TiDBOptOn(val))
+			return nil
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBEnableCardinalityFeedback, Value: BoolToOnOff(DefTiDBEnableCardinalityFeedback), Type: TypeBool,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return BoolToOnOff(EnableCardinalityFeedback.Load()), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			EnableCardinalityFeedback.Store(TiDBOptOn(val))
+			return nil
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBEnableStatsVersionMigration, Value: BoolToOnOff(DefTiDBEnableStatsVersionMigration), Type: TypeBool,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return BoolToOnOff(EnableStatsVersionMigration.Load()), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			EnableStatsVersionMigration.Store(TiDBOptOn(val))
+			return nil
+		},
+	},
 	{
 		Scope: ScopeGlobal, Name: TiDBEnableAutoAnalyzePriorityQueue, Value: BoolToOnOff(DefTiDBEnableAutoAnalyzePriorityQueue), Type: TypeBool,
 		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
@@ -1275,6 +1331,45 @@ var defaultSysVars = []*SysVar{
 			return nil
 		},
 	},
+	{Scope: ScopeGlobal, Name: TiDBStatsLoadMaxItemsPerQuery, Value: strconv.Itoa(DefTiDBStatsLoadMaxItemsPerQuery), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt32,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return strconv.FormatInt(StatsLoadMaxItemsPerQuery.Load(), 10), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			StatsLoadMaxItemsPerQuery.Store(TidbOptInt64(val, DefTiDBStatsLoadMaxItemsPerQuery))
+			return nil
+		},
+	},
+	{
+		Scope:          ScopeGlobal | ScopeSession,
+		Name:           TiDBStatsLoadTimeoutMode,
+		Value:          DefTiDBStatsLoadTimeoutMode,
+		Type:           TypeEnum,
+		PossibleValues: []string{StatsLoadTimeoutModeStrict, StatsLoadTimeoutModeDegraded},
+		SetSession: func(vars *SessionVars, s string) error {
+			vars.StatsLoadTimeoutMode = s
+			return nil
+		},
+	},
+	{
+		Scope:          ScopeGlobal | ScopeSession,
+		Name:           TiDBOptStaleStatsRiskMode,
+		Value:          DefTiDBOptStaleStatsRiskMode,
+		Type:           TypeEnum,
+		PossibleValues: []string{StaleStatsRiskModeTrust, StaleStatsRiskModeRobust},
+		SetSession: func(vars *SessionVars, s string) error {
+			vars.StaleStatsRiskMode = s
+			return nil
+		},
+	},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptStaleStatsHealthyThreshold, Value: strconv.Itoa(DefTiDBOptStaleStatsHealthyThreshold), Type: TypeInt, MinValue: 0, MaxValue: 100, SetSession: func(s *SessionVars, val string) error {
+		s.StaleStatsHealthyThreshold = TidbOptInt64(val, DefTiDBOptStaleStatsHealthyThreshold)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptExtrapolateMonotonicHistogram, Value: BoolToOnOff(DefTiDBOptExtrapolateMonotonicHistogram), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.ExtrapolateMonotonicHistogram = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal, Name: TiDBEnableBatchDML, Value: BoolToOnOff(DefTiDBEnableBatchDML), Type: TypeBool, SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
 		EnableBatchDML.Store(TiDBOptOn(val))
 		return nil
@@ -1319,6 +1414,15 @@ var defaultSysVars = []*SysVar{
 			return nil
 		},
 	},
+	{Scope: ScopeGlobal, Name: TiDBAnalyzeOOMAction, Value: DefTiDBAnalyzeOOMAction, PossibleValues: []string{AnalyzeOOMActionCancel, AnalyzeOOMActionDegrade}, Type: TypeEnum,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return AnalyzeOOMAction.Load(), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			AnalyzeOOMAction.Store(val)
+			return nil
+		},
+	},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnablePrepPlanCache, Value: BoolToOnOff(DefTiDBEnablePrepPlanCache), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnablePreparedPlanCache = TiDBOptOn(val)
 		return nil
@@ -1354,6 +1458,18 @@ var defaultSysVars = []*SysVar{
 		s.EnableNonPreparedPlanCacheForDML = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableNonPreparedPlanCacheForSubquery, Value: BoolToOnOff(DefTiDBEnableNonPrepPlanCacheForSubquery), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableNonPreparedPlanCacheForSubquery = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBNonPreparedPlanCacheMaxJoinTables, Value: strconv.Itoa(DefTiDBNonPreparedPlanCacheMaxJoinTables), Type: TypeInt, MinValue: 1, MaxValue: 16, SetSession: func(s *SessionVars, val string) error {
+		s.NonPreparedPlanCacheMaxJoinTables = tidbOptPositiveInt32(val, DefTiDBNonPreparedPlanCacheMaxJoinTables)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBNonPreparedPlanCacheMaxInListLen, Value: strconv.Itoa(DefTiDBNonPreparedPlanCacheMaxInListLen), Type: TypeInt, MinValue: 1, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+		s.NonPreparedPlanCacheMaxInListLen = tidbOptPositiveInt32(val, DefTiDBNonPreparedPlanCacheMaxInListLen)
+		return nil
+	}},
 	{
 		Scope:                   ScopeGlobal | ScopeSession,
 		Name:                    TiDBOptEnableFuzzyBinding,
@@ -1410,6 +1526,33 @@ var defaultSysVars = []*SysVar{
 			InstancePlanCacheReservedPercentage.Store(v)
 			return nil
 		}},
+	{Scope: ScopeGlobal, Name: TiDBPlanCacheStatsInvalidationPolicy, Value: DefTiDBPlanCacheStatsInvalidationPolicy, Type: TypeEnum,
+		PossibleValues: []string{PlanCacheStatsInvalidationAnyChange, PlanCacheStatsInvalidationRowCountChange, PlanCacheStatsInvalidationPseudoOnly},
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return PlanCacheStatsInvalidationPolicy.Load(), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			PlanCacheStatsInvalidationPolicy.Store(val)
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBPlanCacheStatsInvalidationRowCountRatio,
+		Value: strconv.FormatFloat(DefTiDBPlanCacheStatsInvalidationRatio, 'f', -1, 64),
+		Type:  TypeFloat, MinValue: 0, MaxValue: math.MaxUint32,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return strconv.FormatFloat(PlanCacheStatsInvalidationRatio.Load(), 'f', -1, 64), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			PlanCacheStatsInvalidationRatio.Store(tidbOptFloat64(val, DefTiDBPlanCacheStatsInvalidationRatio))
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBPlanCacheWarmUpAfterAnalyze, Value: strconv.Itoa(DefTiDBPlanCacheWarmUpAfterAnalyze), Type: TypeInt, MinValue: 0, MaxValue: 1000,
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return strconv.FormatInt(PlanCacheWarmUpAfterAnalyze.Load(), 10), nil
+		},
+		SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			PlanCacheWarmUpAfterAnalyze.Store(TidbOptInt64(val, DefTiDBPlanCacheWarmUpAfterAnalyze))
+			return nil
+		}},
 	{Scope: ScopeGlobal, Name: TiDBInstancePlanCacheMaxMemSize, Value: strconv.Itoa(int(DefTiDBInstancePlanCacheMaxMemSize)), Type: TypeStr,
 		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
 			return strconv.FormatInt(InstancePlanCacheMaxMemSize.Load(), 10), nil
@@ -1478,6 +1621,252 @@ var defaultSysVars = []*SysVar{
 			return normalizedValue, nil
 		},
 	},
+	{
+		Scope: ScopeGlobal, Name: TiDBAutoAnalyzeCoolDown,
+		Value:    strconv.Itoa(DefTiDBAutoAnalyzeCoolDown),
+		Type:     TypeUnsigned,
+		MinValue: 0, MaxValue: MaxAutoAnalyzeCoolDown,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return strconv.FormatInt(AutoAnalyzeCoolDown.Load(), 10), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			num, err := strconv.ParseInt(val, 10, 64)
+			if err == nil {
+				AutoAnalyzeCoolDown.Store(num)
+			}
+			return err
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBEnableWorkloadAwareAnalyze, Value: BoolToOnOff(DefTiDBEnableWorkloadAwareAnalyze), Type: TypeBool,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return BoolToOnOff(EnableWorkloadAwareAnalyze.Load()), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			EnableWorkloadAwareAnalyze.Store(TiDBOptOn(val))
+			return nil
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBStatsTemplateRepresentatives,
+		Value:    strconv.Itoa(DefTiDBStatsTemplateRepresentatives),
+		Type:     TypeUnsigned,
+		MinValue: 0, MaxValue: 1024,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return strconv.FormatInt(StatsTemplateRepresentatives.Load(), 10), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			num, err := strconv.ParseInt(val, 10, 64)
+			if err == nil {
+				StatsTemplateRepresentatives.Store(num)
+			}
+			return err
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBStatsTemplateOptOutSchemas, Value: DefTiDBStatsTemplateOptOutSchemas, Type: TypeStr,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return StatsTemplateOptOutSchemas.Load(), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			StatsTemplateOptOutSchemas.Store(val)
+			return nil
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBAutoAnalyzeColumnChoice, Value: DefTiDBAutoAnalyzeColumnChoice, Type: TypeEnum,
+		PossibleValues: []string{DefTiDBAutoAnalyzeColumnChoice, model.PredicateColumns.String()},
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return AutoAnalyzeColumnChoice.Load(), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			AutoAnalyzeColumnChoice.Store(val)
+			return nil
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBAutoAnalyzeQuickRefreshRatio,
+		Value:    strconv.FormatFloat(DefTiDBAutoAnalyzeQuickRefreshRatio, 'f', -1, 64),
+		Type:     TypeFloat,
+		MinValue: 0, MaxValue: 1,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return strconv.FormatFloat(AutoAnalyzeQuickRefreshRatio.Load(), 'f', -1, 64), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			ratio, err := strconv.ParseFloat(val, 64)
+			if err == nil {
+				AutoAnalyzeQuickRefreshRatio.Store(ratio)
+			}
+			return err
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBAutoAnalyzeQuickSizeThreshold,
+		Value:    strconv.Itoa(DefTiDBAutoAnalyzeQuickSizeThreshold),
+		Type:     TypeUnsigned,
+		MinValue: 0, MaxValue: math.MaxInt64,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return strconv.FormatInt(AutoAnalyzeQuickSizeThreshold.Load(), 10), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			threshold, err := strconv.ParseInt(val, 10, 64)
+			if err == nil {
+				AutoAnalyzeQuickSizeThreshold.Store(threshold)
+			}
+			return err
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBAutoAnalyzeSystemTables, Value: DefTiDBAutoAnalyzeSystemTables, Type: TypeStr,
+		Validation: func(_ *SessionVars, normalizedValue string, originalValue string, _ ScopeFlag) (string, error) {
+			if strings.TrimSpace(normalizedValue) == "" {
+				return "", nil
+			}
+			names := strings.Split(normalizedValue, ",")
+			for i, name := range names {
+				schema, table, ok := strings.Cut(strings.ToLower(strings.TrimSpace(name)), ".")
+				if !ok || table == "" || !util.IsSysDB(schema) {
+					return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBAutoAnalyzeSystemTables, originalValue)
+				}
+				names[i] = schema + "." + table
+			}
+			return strings.Join(names, ","), nil
+		},
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return AutoAnalyzeSystemTables.Load(), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			AutoAnalyzeSystemTables.Store(val)
+			return nil
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBAutoAnalyzeDecisionLogSampleRate,
+		Value:    strconv.FormatFloat(DefTiDBAutoAnalyzeDecisionLogSampleRate, 'f', -1, 64),
+		Type:     TypeFloat,
+		MinValue: 0, MaxValue: 1,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return strconv.FormatFloat(AutoAnalyzeDecisionLogSampleRate.Load(), 'f', -1, 64), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			rate, err := strconv.ParseFloat(val, 64)
+			if err == nil {
+				AutoAnalyzeDecisionLogSampleRate.Store(rate)
+			}
+			return err
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBAutoAnalyzeDecisionLogTables, Value: DefTiDBAutoAnalyzeDecisionLogTables, Type: TypeStr,
+		Validation: func(_ *SessionVars, normalizedValue string, originalValue string, _ ScopeFlag) (string, error) {
+			if strings.TrimSpace(normalizedValue) == "" {
+				return "", nil
+			}
+			names := strings.Split(normalizedValue, ",")
+			for i, name := range names {
+				schema, table, ok := strings.Cut(strings.ToLower(strings.TrimSpace(name)), ".")
+				if !ok || schema == "" || table == "" {
+					return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBAutoAnalyzeDecisionLogTables, originalValue)
+				}
+				names[i] = schema + "." + table
+			}
+			return strings.Join(names, ","), nil
+		},
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return AutoAnalyzeDecisionLogTables.Load(), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			AutoAnalyzeDecisionLogTables.Store(val)
+			return nil
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBAutoAnalyzeStatsProbeInterval, Value: DefTiDBAutoAnalyzeStatsProbeInterval.String(),
+		Type: TypeDuration, MinValue: 0, MaxValue: uint64(time.Hour * 24),
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return AutoAnalyzeStatsProbeInterval.Load().String(), nil
+		},
+		SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			d, err := time.ParseDuration(val)
+			if err != nil {
+				return err
+			}
+			AutoAnalyzeStatsProbeInterval.Store(d)
+			return nil
+		},
+	},
+	{Scope: ScopeGlobal, Name: TiDBStatsRollupRecentPartitions, Value: strconv.Itoa(DefTiDBStatsRollupRecentPartitions), Type: TypeInt, MinValue: 0, MaxValue: 8192,
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return strconv.FormatInt(StatsRollupRecentPartitions.Load(), 10), nil
+		},
+		SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			StatsRollupRecentPartitions.Store(TidbOptInt64(val, DefTiDBStatsRollupRecentPartitions))
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBStatsSessionPoolSize, Value: strconv.Itoa(DefTiDBStatsSessionPoolSize), Type: TypeInt, MinValue: 1, MaxValue: 1024,
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return strconv.FormatInt(StatsSessionPoolSize.Load(), 10), nil
+		},
+		SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			StatsSessionPoolSize.Store(TidbOptInt64(val, DefTiDBStatsSessionPoolSize))
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBStatsTinyTableThreshold, Value: strconv.Itoa(DefTiDBStatsTinyTableThreshold), Type: TypeInt, MinValue: 0, MaxValue: 10000,
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return strconv.FormatInt(StatsTinyTableThreshold.Load(), 10), nil
+		},
+		SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			StatsTinyTableThreshold.Store(TidbOptInt64(val, DefTiDBStatsTinyTableThreshold))
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBAnalyzeVerifyStats, Value: BoolToOnOff(DefTiDBAnalyzeVerifyStats), Type: TypeBool,
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return BoolToOnOff(AnalyzeVerifyStats.Load()), nil
+		},
+		SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			AnalyzeVerifyStats.Store(TiDBOptOn(val))
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzeMaxJobsPerStore, Value: strconv.Itoa(DefTiDBAutoAnalyzeMaxJobsPerStore), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt32,
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return strconv.FormatInt(AutoAnalyzeMaxJobsPerStore.Load(), 10), nil
+		},
+		SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			AutoAnalyzeMaxJobsPerStore.Store(TidbOptInt64(val, DefTiDBAutoAnalyzeMaxJobsPerStore))
+			return nil
+		}},
+	{
+		Scope: ScopeGlobal, Name: TiDBStatsAlertStaleRatio,
+		Value:    strconv.FormatFloat(DefTiDBStatsAlertStaleRatio, 'f', -1, 64),
+		Type:     TypeFloat,
+		MinValue: 0, MaxValue: math.MaxUint32,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return strconv.FormatFloat(StatsAlertStaleRatio.Load(), 'f', -1, 64), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			ratio, err := strconv.ParseFloat(val, 64)
+			if err == nil {
+				StatsAlertStaleRatio.Store(ratio)
+			}
+			return err
+		},
+	},
+	{
+		Scope: ScopeGlobal, Name: TiDBStatsAlertEstimationErrorThreshold,
+		Value:    strconv.FormatFloat(DefTiDBStatsAlertEstimationErrorThreshold, 'f', -1, 64),
+		Type:     TypeFloat,
+		MinValue: 0, MaxValue: math.MaxUint32,
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return strconv.FormatFloat(StatsAlertEstimationErrorThreshold.Load(), 'f', -1, 64), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			threshold, err := strconv.ParseFloat(val, 64)
+			if err == nil {
+				StatsAlertEstimationErrorThreshold.Store(threshold)
+			}
+			return err
+		},
+	},
 	{Scope: ScopeGlobal, Name: TiDBEnableMDL, Value: BoolToOnOff(DefTiDBEnableMDL), Type: TypeBool, SetGlobal: func(_ context.Context, vars *SessionVars, val string) error {
 		if EnableMDL.Load() != TiDBOptOn(val) {
 			err := SwitchMDL(TiDBOptOn(val))
@@ -1569,6 +1958,73 @@ var defaultSysVars = []*SysVar{
 			HistoricalStatsDuration.Store(d)
 			return nil
 		}},
+	{Scope: ScopeGlobal, Name: TiDBStatsGCInterval, Value: DefTiDBStatsGCInterval.String(), Type: TypeDuration, MinValue: 0, MaxValue: uint64(time.Hour * 24),
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return StatsGCInterval.Load().String(), nil
+		}, SetGlobal: func(_ context.Context, _ *SessionVars, s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			StatsGCInterval.Store(d)
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBStatsGCRetention, Value: DefTiDBStatsGCRetention.String(), Type: TypeDuration, MinValue: 0, MaxValue: uint64(time.Hour * 24 * 365),
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return StatsGCRetention.Load().String(), nil
+		}, SetGlobal: func(_ context.Context, _ *SessionVars, s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			StatsGCRetention.Store(d)
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBStatsGCBatchSize, Value: strconv.Itoa(DefTiDBStatsGCBatchSize), Type: TypeInt, MinValue: 1, MaxValue: 1 << 20,
+		SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			StatsGCBatchSize.Store(TidbOptInt64(val, DefTiDBStatsGCBatchSize))
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBAnalyzeJobsMaxAge, Value: DefTiDBAnalyzeJobsMaxAge.String(), Type: TypeDuration, MinValue: 0, MaxValue: uint64(time.Hour * 24 * 3650),
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return AnalyzeJobsMaxAge.Load().String(), nil
+		}, SetGlobal: func(_ context.Context, _ *SessionVars, s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			AnalyzeJobsMaxAge.Store(d)
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBAnalyzeJobsMaxRows, Value: strconv.Itoa(DefTiDBAnalyzeJobsMaxRows), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64,
+		SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			AnalyzeJobsMaxRows.Store(TidbOptInt64(val, DefTiDBAnalyzeJobsMaxRows))
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBAnalyzeCheckpointTTL, Value: DefTiDBAnalyzeCheckpointTTL.String(), Type: TypeDuration, MinValue: 0, MaxValue: uint64(time.Hour * 24 * 365),
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return AnalyzeCheckpointTTL.Load().String(), nil
+		}, SetGlobal: func(_ context.Context, _ *SessionVars, s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			AnalyzeCheckpointTTL.Store(d)
+			return nil
+		}},
+	// The max staleness is limited to keep the snapshot, which is at most twice the staleness old, above the GC safe
+	// point, since the min GC life time is 10 minutes.
+	{Scope: ScopeGlobal, Name: TiDBAnalyzeSnapshotStaleness, Value: DefTiDBAnalyzeSnapshotStaleness.String(), Type: TypeDuration, MinValue: 0, MaxValue: uint64(5 * time.Minute),
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return AnalyzeSnapshotStaleness.Load().String(), nil
+		}, SetGlobal: func(_ context.Context, _ *SessionVars, s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			AnalyzeSnapshotStaleness.Store(d)
+			return nil
+		}},
 	{Scope: ScopeGlobal, Name: TiDBLowResolutionTSOUpdateInterval, Value: strconv.Itoa(DefTiDBLowResolutionTSOUpdateInterval), Type: TypeInt, MinValue: 10, MaxValue: 60000,
 		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
 			LowResolutionTSOUpdateInterval.Store(uint32(TidbOptInt64(val, DefTiDBLowResolutionTSOUpdateInterval)))
@@ -1616,6 +2072,54 @@ var defaultSysVars = []*SysVar{
 			return BoolToOnOff(vars.EnablePlanReplayerCapture), nil
 		},
 	},
+	{Scope: ScopeGlobal, Name: TiDBPlanReplayerSnapshotInterval, Value: DefTiDBPlanReplayerSnapshotInterval.String(), Type: TypeDuration, MinValue: 0, MaxValue: uint64(time.Hour * 24 * 7),
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			return PlanReplayerSnapshotInterval.Load().String(), nil
+		}, SetGlobal: func(_ context.Context, _ *SessionVars, s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			PlanReplayerSnapshotInterval.Store(d)
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBPlanReplayerSnapshotStorage, Value: "", Type: TypeStr,
+		GetGlobal: func(_ context.Context, _ *SessionVars) (string, error) {
+			uri := PlanReplayerSnapshotStorage.Load()
+			if len(uri) > 0 {
+				uri = ast.RedactURL(uri)
+			}
+			return uri, nil
+		}, SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			PlanReplayerSnapshotStorage.Store(val)
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBPlanReplayerSnapshotMaxCount, Value: strconv.Itoa(DefTiDBPlanReplayerSnapshotMaxCount), Type: TypeInt, MinValue: 1, MaxValue: 10000,
+		SetGlobal: func(_ context.Context, _ *SessionVars, val string) error {
+			PlanReplayerSnapshotMaxCount.Store(TidbOptInt64(val, DefTiDBPlanReplayerSnapshotMaxCount))
+			return nil
+		}},
+	{
+		Scope:          ScopeGlobal | ScopeSession,
+		Name:           TiDBPlanReplayerStatsFormat,
+		Value:          DefTiDBPlanReplayerStatsFormat,
+		Type:           TypeEnum,
+		PossibleValues: []string{PlanReplayerStatsFormatJSON, PlanReplayerStatsFormatBinary},
+		SetSession: func(s *SessionVars, val string) error {
+			s.PlanReplayerStatsFormat = val
+			return nil
+		},
+	},
+	{
+		Scope: ScopeGlobal | ScopeSession,
+		Name:  TiDBEnableErrorAttributes,
+		Value: BoolToOnOff(DefTiDBEnableErrorAttributes),
+		Type:  TypeBool,
+		SetSession: func(s *SessionVars, val string) error {
+			s.EnableErrorAttributes = TiDBOptOn(val)
+			return nil
+		},
+	},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBRowFormatVersion, Value: strconv.Itoa(DefTiDBRowFormatV1), Type: TypeUnsigned, MinValue: 1, MaxValue: 2, SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
 		SetDDLReorgRowFormat(TidbOptInt64(val, DefTiDBRowFormatV2))
 		return nil
@@ -2008,6 +2512,10 @@ var defaultSysVars = []*SysVar{
 		s.EnableCorrelationAdjustment = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnableFKJoinEstimation, Value: BoolToOnOff(DefOptEnableFKJoinEstimation), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableFKJoinEstimation = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCorrelationExpFactor, Value: strconv.Itoa(DefOptCorrelationExpFactor), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
 		s.CorrelationExpFactor = int(TidbOptInt64(val, DefOptCorrelationExpFactor))
 		return nil
@@ -2368,12 +2876,7 @@ var defaultSysVars = []*SysVar{
 		s.UsePlanBaselines = TiDBOptOn(val)
 		return nil
 	}},
-	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEvolvePlanBaselines, Value: BoolToOnOff(DefTiDBEvolvePlanBaselines), Type: TypeBool, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
-		if normalizedValue == "ON" && !config.CheckTableBeforeDrop {
-			return normalizedValue, errors.Errorf("Cannot enable baseline evolution feature, it is not generally available now")
-		}
-		return normalizedValue, nil
-	}, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEvolvePlanBaselines, Value: BoolToOnOff(DefTiDBEvolvePlanBaselines), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EvolvePlanBaselines = TiDBOptOn(val)
 		return nil
 	}},
@@ -2599,6 +3102,36 @@ var defaultSysVars = []*SysVar{
 			return nil
 		},
 	},
+	{
+		Scope: ScopeGlobal, Name: TiDBCostModelV2CalibratedFactors, Value: DefTiDBCostModelV2CalibratedFactors, Type: TypeStr,
+		Validation: func(_ *SessionVars, normalizedValue string, originalValue string, _ ScopeFlag) (string, error) {
+			if strings.TrimSpace(normalizedValue) == "" {
+				return "", nil
+			}
+			factors := make(map[string]float64)
+			if err := json.Unmarshal([]byte(normalizedValue), &factors); err != nil {
+				return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBCostModelV2CalibratedFactors, originalValue)
+			}
+			for _, v := range factors {
+				if v < 0 {
+					return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBCostModelV2CalibratedFactors, originalValue)
+				}
+			}
+			// The keys are sorted by json.Marshal.
+			normalized, err := json.Marshal(factors)
+			if err != nil {
+				return normalizedValue, err
+			}
+			return string(normalized), nil
+		},
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return CostModelV2CalibratedFactors.Load(), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			CostModelV2CalibratedFactors.Store(val)
+			return nil
+		},
+	},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBIndexJoinDoubleReadPenaltyCostRate, Value: strconv.Itoa(0), Hidden: false, Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64,
 		SetSession: func(vars *SessionVars, s string) error {
 			vars.IndexJoinDoubleReadPenaltyCostRate = tidbOptFloat64(s, 0)
@@ -3017,6 +3550,16 @@ var defaultSysVars = []*SysVar{
 	}, GetGlobal: func(ctx context.Context, vars *SessionVars) (string, error) {
 		return strconv.Itoa(int(TTLRunningTasks.Load())), nil
 	}},
+	{Scope: ScopeGlobal, Name: TiDBTTLStatsRefreshRatio, Value: strconv.FormatFloat(DefTiDBTTLStatsRefreshRatio, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: 1, SetGlobal: func(ctx context.Context, vars *SessionVars, s string) error {
+		ratio, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		TTLStatsRefreshRatio.Store(ratio)
+		return nil
+	}, GetGlobal: func(ctx context.Context, vars *SessionVars) (string, error) {
+		return strconv.FormatFloat(TTLStatsRefreshRatio.Load(), 'f', -1, 64), nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptOrderingIdxSelThresh, Value: strconv.FormatFloat(DefTiDBOptOrderingIdxSelThresh, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: 1,
 		SetSession: func(s *SessionVars, val string) error {
 			s.OptOrderingIdxSelThresh = tidbOptFloat64(val, DefTiDBOptOrderingIdxSelThresh)
@@ -3068,6 +3611,61 @@ var defaultSysVars = []*SysVar{
 			s.AnalyzeSkipColumnTypes = ParseAnalyzeSkipColumnTypes(val)
 			return nil
 		}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeAdaptiveTopNLimit, Value: strconv.Itoa(DefTiDBAnalyzeAdaptiveTopNLimit), Type: TypeUnsigned, MinValue: 0, MaxValue: 100000,
+		SetSession: func(s *SessionVars, val string) error {
+			s.AnalyzeAdaptiveTopNLimit = int(TidbOptInt64(val, DefTiDBAnalyzeAdaptiveTopNLimit))
+			return nil
+		}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeAdaptiveBucketLimit, Value: strconv.Itoa(DefTiDBAnalyzeAdaptiveBucketLimit), Type: TypeUnsigned, MinValue: 0, MaxValue: 100000,
+		SetSession: func(s *SessionVars, val string) error {
+			s.AnalyzeAdaptiveBucketLimit = int(TidbOptInt64(val, DefTiDBAnalyzeAdaptiveBucketLimit))
+			return nil
+		}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeBlockSampleRate, Value: strconv.FormatFloat(DefTiDBAnalyzeBlockSampleRate, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: 1,
+		SetSession: func(s *SessionVars, val string) error {
+			s.AnalyzeBlockSampleRate = tidbOptFloat64(val, DefTiDBAnalyzeBlockSampleRate)
+			return nil
+		}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeReadNearbyReplica, Value: BoolToOnOff(DefTiDBAnalyzeReadNearbyReplica), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.AnalyzeReadNearbyReplica = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeAutoTiFlash, Value: BoolToOnOff(DefTiDBAnalyzeAutoTiFlash), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.AnalyzeAutoTiFlash = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeSession, Name: TiDBAnalyzeDefaultSampleRate, Value: strconv.FormatFloat(DefTiDBAnalyzeDefaultSampleRate, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: 1,
+		SetSession: func(s *SessionVars, val string) error {
+			s.AnalyzeDefaults.SampleRate = tidbOptFloat64(val, DefTiDBAnalyzeDefaultSampleRate)
+			return nil
+		}},
+	{Scope: ScopeSession, Name: TiDBAnalyzeDefaultBuckets, Value: strconv.Itoa(DefTiDBAnalyzeDefaultBuckets), Type: TypeUnsigned, MinValue: 0, MaxValue: 100000,
+		SetSession: func(s *SessionVars, val string) error {
+			s.AnalyzeDefaults.Buckets = uint64(TidbOptInt64(val, DefTiDBAnalyzeDefaultBuckets))
+			return nil
+		}},
+	{Scope: ScopeSession, Name: TiDBAnalyzeDefaultTopN, Value: strconv.Itoa(DefTiDBAnalyzeDefaultTopN), Type: TypeInt, MinValue: -1, MaxValue: 100000,
+		SetSession: func(s *SessionVars, val string) error {
+			s.AnalyzeDefaults.TopN = TidbOptInt64(val, DefTiDBAnalyzeDefaultTopN)
+			return nil
+		}},
+	{Scope: ScopeSession, Name: TiDBAnalyzeDefaultColumnChoice, Value: DefTiDBAnalyzeDefaultColumnChoice, Type: TypeEnum,
+		PossibleValues: []string{DefTiDBAnalyzeDefaultColumnChoice, model.AllColumns.String(), model.PredicateColumns.String()},
+		SetSession: func(s *SessionVars, val string) error {
+			s.AnalyzeDefaults.ColumnChoice = ""
+			if val != DefTiDBAnalyzeDefaultColumnChoice {
+				s.AnalyzeDefaults.ColumnChoice = val
+			}
+			return nil
+		}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeValidateForeignKeys, Value: BoolToOnOff(DefTiDBAnalyzeValidateForeignKeys), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.AnalyzeValidateForeignKeys = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeCollectTrigramStats, Value: BoolToOnOff(DefTiDBAnalyzeCollectTrigramStats), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.AnalyzeCollectTrigramStats = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBPlanCacheInvalidationOnFreshStats, Value: BoolToOnOff(DefTiDBPlanCacheInvalidationOnFreshStats), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.PlanCacheInvalidationOnFreshStats = TiDBOptOn(val)
 		return nil
@@ -3089,6 +3687,12 @@ var defaultSysVars = []*SysVar{
 		s.SkipMissingPartitionStats = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal, Name: TiDBEnableHLLNDVSketch, Value: BoolToOnOff(DefTiDBEnableHLLNDVSketch), Type: TypeBool, SetGlobal: func(ctx context.Context, vars *SessionVars, s string) error {
+		EnableHLLNDVSketch.Store(TiDBOptOn(s))
+		return nil
+	}, GetGlobal: func(ctx context.Context, vars *SessionVars) (string, error) {
+		return BoolToOnOff(EnableHLLNDVSketch.Load()), nil
+	}},
 	{Scope: ScopeGlobal, Name: AuthenticationLDAPSASLAuthMethodName, Value: DefAuthenticationLDAPSASLAuthMethodName, Type: TypeEnum, PossibleValues: []string{ldap.SASLAuthMethodSCRAMSHA1, ldap.SASLAuthMethodSCRAMSHA256, ldap.SASLAuthMethodGSSAPI}, SetGlobal: func(ctx context.Context, vars *SessionVars, s string) error {
 		ldap.LDAPSASLAuthImpl.SetSASLAuthMethod(s)
 		return nil
@@ -3264,7 +3868,7 @@ var defaultSysVars = []*SysVar{
 			if ok {
 				return normalizedValue, nil
 			}
-			errMsg := fmt.Sprintf("incorrect value: %s. %s should be sepreated by , such as %s, also we only support IN and MIN_MAX now. ",
+			errMsg := fmt.Sprintf("incorrect value: %s. %s should be sepreated by , such as %s, also we only support IN, MIN_MAX and BLOOM_FILTER now. ",
 				originalValue, TiDBRuntimeFilterTypeName, DefRuntimeFilterType)
 			return normalizedValue, errors.New(errMsg)
 		},
@@ -3370,6 +3974,35 @@ var defaultSysVars = []*SysVar{
 			return nil
 		},
 	},
+	{Scope: ScopeSession, Name: TiDBPinStats, Value: "", Type: TypeStr,
+		Validation: func(vars *SessionVars, normalizedValue string, _ string, _ ScopeFlag) (string, error) {
+			_, err := parsePinnedStats(vars, normalizedValue)
+			return normalizedValue, err
+		},
+		SetSession: func(vars *SessionVars, s string) error {
+			pinned, err := parsePinnedStats(vars, s)
+			if err != nil {
+				return err
+			}
+			vars.PinnedStats = pinned
+			vars.PinnedStatsCache = nil
+			return nil
+		},
+	},
+	{Scope: ScopeSession, Name: TiDBIgnoreStats, Value: "", Type: TypeStr,
+		Validation: func(_ *SessionVars, normalizedValue string, _ string, _ ScopeFlag) (string, error) {
+			_, err := parseIgnoreStatsTables(normalizedValue)
+			return normalizedValue, err
+		},
+		SetSession: func(vars *SessionVars, s string) error {
+			tables, err := parseIgnoreStatsTables(s)
+			if err != nil {
+				return err
+			}
+			vars.IgnoreStatsTables = tables
+			return nil
+		},
+	},
 	{Scope: ScopeInstance, Name: TiDBServiceScope, Value: "", Type: TypeStr,
 		Validation: func(_ *SessionVars, normalizedValue string, originalValue string, _ ScopeFlag) (string, error) {
 			return normalizedValue, servicescope.CheckServiceScope(originalValue)
@@ -3397,6 +4030,65 @@ var defaultSysVars = []*SysVar{
 			s.IdleTransactionTimeout = tidbOptPositiveInt32(val, DefTiDBIdleTransactionTimeout)
 			return nil
 		}},
+	{Scope: ScopeGlobal, Name: TiDBLongTxnPolicy, Value: DefTiDBLongTxnPolicy, Type: TypeStr,
+		Validation: func(_ *SessionVars, normalizedValue string, originalValue string, _ ScopeFlag) (string, error) {
+			if _, err := ParseLongTxnPolicy(normalizedValue); err != nil {
+				return originalValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBLongTxnPolicy, originalValue)
+			}
+			return normalizedValue, nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			policy, err := ParseLongTxnPolicy(val)
+			if err == nil {
+				LongTxnPolicyValue.Store(policy)
+			}
+			return err
+		}},
+	{Scope: ScopeGlobal, Name: TiDBLongTxnPolicyExemptUsers, Value: DefTiDBLongTxnPolicyExemptUsers, Type: TypeStr,
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			LongTxnPolicyExemptUsers.Store(val)
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzePlacementWindows, Value: DefTiDBAutoAnalyzePlacementWindows, Type: TypeStr,
+		Validation: func(_ *SessionVars, normalizedValue string, originalValue string, _ ScopeFlag) (string, error) {
+			if _, err := ParsePlacementAnalyzeWindows(normalizedValue); err != nil {
+				return originalValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBAutoAnalyzePlacementWindows, originalValue)
+			}
+			return normalizedValue, nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			windows, err := ParsePlacementAnalyzeWindows(val)
+			if err == nil {
+				AutoAnalyzePlacementWindowsValue.Store(windows)
+			}
+			return err
+		}},
+	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzeResourceGroup, Value: DefTiDBAutoAnalyzeResourceGroup, Type: TypeStr,
+		Validation: func(_ *SessionVars, normalizedValue string, originalValue string, _ ScopeFlag) (string, error) {
+			// The resource group may be created later, so it's checked when the jobs are executed.
+			return strings.ToLower(strings.TrimSpace(normalizedValue)), nil
+		},
+		GetGlobal: func(_ context.Context, s *SessionVars) (string, error) {
+			return AutoAnalyzeResourceGroup.Load(), nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			AutoAnalyzeResourceGroup.Store(val)
+			return nil
+		}},
+	{Scope: ScopeGlobal, Name: TiDBInternalTableRetention, Value: DefTiDBInternalTableRetention, Type: TypeStr,
+		Validation: func(_ *SessionVars, normalizedValue string, originalValue string, _ ScopeFlag) (string, error) {
+			if _, err := ParseInternalTableRetention(normalizedValue); err != nil {
+				return originalValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBInternalTableRetention, originalValue)
+			}
+			return normalizedValue, nil
+		},
+		SetGlobal: func(_ context.Context, s *SessionVars, val string) error {
+			retention, err := ParseInternalTableRetention(val)
+			if err == nil {
+				InternalTableRetentionValue.Store(retention)
+			}
+			return err
+		}},
 	{Scope: ScopeGlobal | ScopeSession, Name: DivPrecisionIncrement, Value: strconv.Itoa(DefDivPrecisionIncrement), Type: TypeUnsigned, MinValue: 0, MaxValue: 30,
 		SetSession: func(s *SessionVars, val string) error {
 			s.DivPrecisionIncrement = tidbOptPositiveInt32(val, DefDivPrecisionIncrement)
@@ -1831,3 +1831,70 @@ func TestTiDBAutoAnalyzeConcurrencyValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestTiDBAutoAnalyzeSystemTablesValidation(t *testing.T) {
+	vars := NewSessionVars(nil)
+	sv := GetSysVar(TiDBAutoAnalyzeSystemTables)
+	require.NotNil(t, sv)
+
+	val, err := sv.Validate(vars, "", ScopeGlobal)
+	require.NoError(t, err)
+	require.Equal(t, "", val)
+	val, err = sv.Validate(vars, " MySQL.Analyze_Jobs, mysql.stats_meta ", ScopeGlobal)
+	require.NoError(t, err)
+	require.Equal(t, "mysql.analyze_jobs,mysql.stats_meta", val)
+
+	// Only the tables in the system schemas can be specified.
+	_, err = sv.Validate(vars, "test.t", ScopeGlobal)
+	require.Error(t, err)
+	_, err = sv.Validate(vars, "mysql", ScopeGlobal)
+	require.Error(t, err)
+	_, err = sv.Validate(vars, "mysql.stats_meta,", ScopeGlobal)
+	require.Error(t, err)
+}
+
+func TestTiDBAutoAnalyzeDecisionLogTablesValidation(t *testing.T) {
+	vars := NewSessionVars(nil)
+	sv := GetSysVar(TiDBAutoAnalyzeDecisionLogTables)
+	require.NotNil(t, sv)
+
+	val, err := sv.Validate(vars, " ", ScopeGlobal)
+	require.NoError(t, err)
+	require.Equal(t, "", val)
+	val, err = sv.Validate(vars, " Test.T1, test.t2 ", ScopeGlobal)
+	require.NoError(t, err)
+	require.Equal(t, "test.t1,test.t2", val)
+
+	_, err = sv.Validate(vars, "t1", ScopeGlobal)
+	require.Error(t, err)
+	_, err = sv.Validate(vars, ".t1", ScopeGlobal)
+	require.Error(t, err)
+	_, err = sv.Validate(vars, "test.t1,", ScopeGlobal)
+	require.Error(t, err)
+
+	sv = GetSysVar(TiDBAutoAnalyzeDecisionLogSampleRate)
+	require.NotNil(t, sv)
+	val, err = sv.Validate(vars, "1.5", ScopeGlobal)
+	require.NoError(t, err)
+	require.Equal(t, "1", val)
+}
+
+func TestTiDBCostModelV2CalibratedFactorsValidation(t *testing.T) {
+	vars := NewSessionVars(nil)
+	sv := GetSysVar(TiDBCostModelV2CalibratedFactors)
+	require.NotNil(t, sv)
+
+	val, err := sv.Validate(vars, " ", ScopeGlobal)
+	require.NoError(t, err)
+	require.Equal(t, "", val)
+	val, err = sv.Validate(vars, `{"tidb_cpu_factor": 30, "tikv_scan_factor": 40.7}`, ScopeGlobal)
+	require.NoError(t, err)
+	require.Equal(t, `{"tidb_cpu_factor":30,"tikv_scan_factor":40.7}`, val)
+
+	_, err = sv.Validate(vars, `{"tikv_scan_factor": -1}`, ScopeGlobal)
+	require.Error(t, err)
+	_, err = sv.Validate(vars, `{"tikv_scan_factor": "a"}`, ScopeGlobal)
+	require.Error(t, err)
+	_, err = sv.Validate(vars, `[40.7]`, ScopeGlobal)
+	require.Error(t, err)
+}
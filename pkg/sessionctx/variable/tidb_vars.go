@@ -116,6 +116,10 @@ const (
 	// TiDBLastPlanReplayerToken is used to get the last plan replayer token within the current session
 	TiDBLastPlanReplayerToken = "tidb_last_plan_replayer_token"
 
+	// TiDBSessionShareFlags is used to get the flags that prevent the current session from being shared by proxies.
+	// It's 0 if the session can be multiplexed. It is read-only.
+	TiDBSessionShareFlags = "tidb_session_share_flags"
+
 	// TiDBConfig is a read-only variable that shows the config of the current server.
 	TiDBConfig = "tidb_config"
 
@@ -318,6 +322,10 @@ const (
 	// TiDBOptEnableCorrelationAdjustment is used to indicates if enable correlation adjustment.
 	TiDBOptEnableCorrelationAdjustment = "tidb_opt_enable_correlation_adjustment"
 
+	// TiDBOptEnableFKJoinEstimation indicates whether to estimate the joins on the foreign keys by the foreign key
+	// stats collected by ANALYZE, which regard each child row as matching exactly one parent row.
+	TiDBOptEnableFKJoinEstimation = "tidb_opt_enable_fk_join_estimation"
+
 	// TiDBOptLimitPushDownThreshold determines if push Limit or TopN down to TiKV forcibly.
 	TiDBOptLimitPushDownThreshold = "tidb_opt_limit_push_down_threshold"
 
@@ -624,6 +632,14 @@ const (
 	// TiDBEvolvePlanBaselines indicates whether the evolution of plan baselines is enabled.
 	TiDBEvolvePlanBaselines = "tidb_evolve_plan_baselines"
 
+	// TiDBCaptureRegressedPlanBaselines indicates whether to capture the better plans of the statements whose plans
+	// regressed, which are verified and promoted to the plan baselines by the evolution of plan baselines.
+	TiDBCaptureRegressedPlanBaselines = "tidb_capture_regressed_plan_baselines"
+
+	// TiDBPlanBaselineRegressionRatio is the ratio of the average latency of the latest plan of a statement to the
+	// lowest one of its other plans, above which the latest plan is regarded as regressed.
+	TiDBPlanBaselineRegressionRatio = "tidb_plan_baseline_regression_ratio"
+
 	// TiDBOptEnableFuzzyBinding indicates whether to enable the universal binding.
 	TiDBOptEnableFuzzyBinding = "tidb_opt_enable_fuzzy_binding"
 
@@ -671,6 +687,10 @@ const (
 	// TiDBSuperReadOnly is tidb's variant of mysql's super_read_only, which has some differences from mysql's super_read_only.
 	TiDBSuperReadOnly = "tidb_super_read_only"
 
+	// TiDBInstanceReadOnly is used to fence the current TiDB instance, user statements that write data are rejected
+	// on it while the internal SQLs are not affected.
+	TiDBInstanceReadOnly = "tidb_instance_read_only"
+
 	// TiDBShardAllocateStep indicates the max size of continuous rowid shard in one transaction.
 	TiDBShardAllocateStep = "tidb_shard_allocate_step"
 	// TiDBEnableTelemetry indicates that whether usage data report to PingCAP is enabled.
@@ -727,6 +747,10 @@ const (
 	// TiDBTopSQLMaxMetaCount indicates the max capacity of the collect meta per second.
 	TiDBTopSQLMaxMetaCount = "tidb_top_sql_max_meta_count"
 
+	// TiDBEnableTopSQLLocalStorage indicates whether to keep the CPU usage of SQL digests in
+	// the INFORMATION_SCHEMA.TIDB_TOP_SQL_CPU_USAGE table of the current instance.
+	TiDBEnableTopSQLLocalStorage = "tidb_enable_top_sql_local_storage"
+
 	// TiDBEnableLocalTxn indicates whether to enable Local Txn.
 	TiDBEnableLocalTxn = "tidb_enable_local_txn"
 
@@ -786,6 +810,11 @@ const (
 	// TiDBCostModelVersion is a internal switch to indicates the cost model version.
 	TiDBCostModelVersion = "tidb_cost_model_version"
 
+	// TiDBCostModelV2CalibratedFactors is the factors of cost model v2 calibrated by ADMIN CALIBRATE COST MODEL on the
+	// current hardware, in the JSON object of the factor names and values, e.g. '{"tikv_scan_factor": 40.7}'.
+	// The factors not in it keep their default values.
+	TiDBCostModelV2CalibratedFactors = "tidb_cost_model_v2_calibrated_factors"
+
 	// TiDBIndexJoinDoubleReadPenaltyCostRate indicates whether to add some penalty cost to IndexJoin and how much of it.
 	// IndexJoin can cause plenty of extra double read tasks, which consume lots of resources and take a long time.
 	// Since the number of double read tasks is hard to estimated accurately, we leave this variable to let us can adjust this
@@ -847,6 +876,15 @@ const (
 	TiDBEnableNonPreparedPlanCache = "tidb_enable_non_prepared_plan_cache"
 	// TiDBEnableNonPreparedPlanCacheForDML indicates whether to enable non-prepared plan cache for DML statements.
 	TiDBEnableNonPreparedPlanCacheForDML = "tidb_enable_non_prepared_plan_cache_for_dml"
+	// TiDBEnableNonPreparedPlanCacheForSubquery indicates whether to enable non-prepared plan cache for the queries
+	// with simple sub-queries, which are the derived tables and the IN / EXISTS / comparison sub-queries of plain SELECT.
+	TiDBEnableNonPreparedPlanCacheForSubquery = "tidb_enable_non_prepared_plan_cache_for_subquery"
+	// TiDBNonPreparedPlanCacheMaxJoinTables is the maximum number of tables in a query, including the ones in the
+	// sub-queries, for the query to be cached by non-prepared plan cache.
+	TiDBNonPreparedPlanCacheMaxJoinTables = "tidb_non_prepared_plan_cache_max_join_tables"
+	// TiDBNonPreparedPlanCacheMaxInListLen is the maximum number of values in an in-list for the query to be cached by
+	// non-prepared plan cache.
+	TiDBNonPreparedPlanCacheMaxInListLen = "tidb_non_prepared_plan_cache_max_in_list_len"
 	// TiDBNonPreparedPlanCacheSize controls the size of non-prepared plan cache.
 	// This variable is deprecated, use tidb_session_plan_cache_size instead.
 	TiDBNonPreparedPlanCacheSize = "tidb_non_prepared_plan_cache_size"
@@ -855,6 +893,20 @@ const (
 	// TiDBPlanCacheInvalidationOnFreshStats controls if plan cache will be invalidated automatically when
 	// related stats are analyzed after the plan cache is generated.
 	TiDBPlanCacheInvalidationOnFreshStats = "tidb_plan_cache_invalidation_on_fresh_stats"
+	// TiDBPlanCacheStatsInvalidationPolicy controls which stats updates invalidate the cached plans if
+	// tidb_plan_cache_invalidation_on_fresh_stats is on:
+	//   - any_change: any new version of the stats invalidates the cached plans.
+	//   - row_count_change: only the row count changed by more than tidb_plan_cache_stats_invalidation_row_count_ratio
+	//     invalidates the cached plans, as well as the stats becoming available for the plans built on pseudo stats.
+	//   - pseudo_only: only the stats becoming available for the plans built on pseudo stats invalidates them.
+	TiDBPlanCacheStatsInvalidationPolicy = "tidb_plan_cache_stats_invalidation_policy"
+	// TiDBPlanCacheStatsInvalidationRowCountRatio is the ratio of the row count change which invalidates the cached
+	// plans under the row_count_change policy of tidb_plan_cache_stats_invalidation_policy.
+	TiDBPlanCacheStatsInvalidationRowCountRatio = "tidb_plan_cache_stats_invalidation_row_count_ratio"
+	// TiDBPlanCacheWarmUpAfterAnalyze is the max number of the most frequently hit prepared statements of a table in
+	// the instance plan cache, which are re-optimized in the background after the table is analyzed, so that the
+	// plans built on the fresh stats are cached before the statements are executed again. 0 disables the warm-up.
+	TiDBPlanCacheWarmUpAfterAnalyze = "tidb_plan_cache_warm_up_after_analyze"
 	// TiDBSessionPlanCacheSize controls the size of session plan cache.
 	TiDBSessionPlanCacheSize = "tidb_session_plan_cache_size"
 
@@ -882,7 +934,8 @@ const (
 	TiDBOptAdvancedJoinHint = "tidb_opt_advanced_join_hint"
 	// TiDBOptUseInvisibleIndexes indicates whether to use invisible indexes.
 	TiDBOptUseInvisibleIndexes = "tidb_opt_use_invisible_indexes"
-	// TiDBAnalyzePartitionConcurrency is the number of concurrent workers to save statistics to the system tables.
+	// TiDBAnalyzePartitionConcurrency is the number of concurrent workers to save statistics to the system tables,
+	// and the max number of the partitions of a table analyzed concurrently.
 	TiDBAnalyzePartitionConcurrency = "tidb_analyze_partition_concurrency"
 	// TiDBMergePartitionStatsConcurrency indicates the concurrency when merge partition stats into global stats
 	TiDBMergePartitionStatsConcurrency = "tidb_merge_partition_stats_concurrency"
@@ -900,6 +953,25 @@ const (
 
 	// TiDBEnablePlanReplayerContinuousCapture indicates whether to enable continuous capture
 	TiDBEnablePlanReplayerContinuousCapture = "tidb_enable_plan_replayer_continuous_capture"
+
+	// TiDBPlanReplayerStatsFormat indicates the format of the stats dumped by the plan replayer, which is json or binary.
+	TiDBPlanReplayerStatsFormat = "tidb_plan_replayer_stats_format"
+
+	// TiDBPlanReplayerSnapshotInterval indicates the interval of the plan replayer snapshots, which dump the schemas,
+	// stats and global bindings of the user tables periodically. 0 means the snapshots are disabled.
+	TiDBPlanReplayerSnapshotInterval = "tidb_plan_replayer_snapshot_interval"
+
+	// TiDBPlanReplayerSnapshotStorage indicates the external storage URI of the plan replayer snapshots. The snapshots
+	// are written to the local directory beside the plan replayer files if it's empty.
+	TiDBPlanReplayerSnapshotStorage = "tidb_plan_replayer_snapshot_storage"
+
+	// TiDBPlanReplayerSnapshotMaxCount indicates the max number of the plan replayer snapshots kept in the storage,
+	// the oldest ones are removed when it's exceeded.
+	TiDBPlanReplayerSnapshotMaxCount = "tidb_plan_replayer_snapshot_max_count"
+
+	// TiDBEnableErrorAttributes indicates whether to append the retryability attributes to the error messages
+	// sent to the client, so that connection pools and ORMs can decide whether to retry without parsing the message.
+	TiDBEnableErrorAttributes = "tidb_enable_error_attributes"
 	// TiDBEnableReusechunk indicates whether to enable chunk alloc
 	TiDBEnableReusechunk = "tidb_enable_reuse_chunk"
 
@@ -958,6 +1030,50 @@ const (
 	// TiDBAnalyzeSkipColumnTypes indicates the column types whose statistics would not be collected when executing the ANALYZE command.
 	TiDBAnalyzeSkipColumnTypes = "tidb_analyze_skip_column_types"
 
+	// TiDBAnalyzeValidateForeignKeys indicates whether ANALYZE looks up the sampled foreign key values in the
+	// referenced tables to validate the foreign keys, which may be violated if they are not enforced.
+	TiDBAnalyzeValidateForeignKeys = "tidb_analyze_validate_foreign_keys"
+
+	// TiDBAnalyzeCollectTrigramStats indicates whether ANALYZE collects the trigram stats of the string columns from the
+	// samples, which are used to estimate the selectivity of LIKE and REGEXP.
+	TiDBAnalyzeCollectTrigramStats = "tidb_analyze_collect_trigram_stats"
+
+	// TiDBAnalyzeAdaptiveTopNLimit indicates the upper bound of the TopN size chosen adaptively for each column by ANALYZE.
+	// 0 means the adaptive TopN is disabled.
+	TiDBAnalyzeAdaptiveTopNLimit = "tidb_analyze_adaptive_topn_limit"
+
+	// TiDBAnalyzeAdaptiveBucketLimit indicates the upper bound of the number of the histogram buckets chosen
+	// adaptively for each column and index by ANALYZE. 0 means the adaptive bucket number is disabled.
+	TiDBAnalyzeAdaptiveBucketLimit = "tidb_analyze_adaptive_bucket_limit"
+
+	// TiDBAnalyzeBlockSampleRate indicates the default fraction of the regions scanned by ANALYZE if the BLOCKSAMPLE
+	// option is not specified. 0 means the block sampling is disabled.
+	TiDBAnalyzeBlockSampleRate = "tidb_analyze_block_sample_rate"
+
+	// TiDBAnalyzeReadNearbyReplica indicates whether ANALYZE reads the replicas in the zone of the TiDB instance
+	// instead of the leaders, if the leaders of the table are placed in another zone or region by the placement rules.
+	TiDBAnalyzeReadNearbyReplica = "tidb_analyze_read_nearby_replica"
+
+	// TiDBAnalyzeAutoTiFlash indicates whether ANALYZE reads the data from the TiFlash replica if it's available and
+	// the TIFLASH option is not specified.
+	TiDBAnalyzeAutoTiFlash = "tidb_analyze_auto_tiflash"
+
+	// TiDBAnalyzeDefaultSampleRate indicates the default sample rate of the ANALYZE statements issued in the session if
+	// neither the SAMPLES nor the SAMPLERATE option is specified or persisted. 0 means the built-in default is used.
+	TiDBAnalyzeDefaultSampleRate = "tidb_analyze_default_sample_rate"
+
+	// TiDBAnalyzeDefaultBuckets indicates the default number of the histogram buckets of the ANALYZE statements issued
+	// in the session if the BUCKETS option is neither specified nor persisted. 0 means the built-in default is used.
+	TiDBAnalyzeDefaultBuckets = "tidb_analyze_default_buckets"
+
+	// TiDBAnalyzeDefaultTopN indicates the default TopN size of the ANALYZE statements issued in the session if the
+	// TOPN option is neither specified nor persisted. -1 means the built-in default is used.
+	TiDBAnalyzeDefaultTopN = "tidb_analyze_default_topn"
+
+	// TiDBAnalyzeDefaultColumnChoice indicates the columns analyzed by the ANALYZE statements issued in the session if
+	// the columns are neither specified nor persisted. DEFAULT means tidb_analyze_column_options is used.
+	TiDBAnalyzeDefaultColumnChoice = "tidb_analyze_default_column_choice"
+
 	// TiDBEnableCheckConstraint indicates whether to enable check constraint feature.
 	TiDBEnableCheckConstraint = "tidb_enable_check_constraint"
 
@@ -971,6 +1087,14 @@ const (
 	// Please see comments of SessionVars.OptObjective for details.
 	TiDBOptObjective = "tidb_opt_objective"
 
+	// TiDBPinStats pins the statistics used by the optimizer to a historical version for some tables in the session.
+	// The value is a comma separated list of `db.table@version`, where version is a TSO or a datetime.
+	TiDBPinStats = "tidb_pin_stats"
+
+	// TiDBIgnoreStats makes the optimizer use the pseudo statistics for some tables in the session.
+	// The value is a comma separated list of `db.table`.
+	TiDBIgnoreStats = "tidb_ignore_stats"
+
 	// TiDBEnableParallelHashaggSpill is the name of the `tidb_enable_parallel_hashagg_spill` system variable
 	TiDBEnableParallelHashaggSpill = "tidb_enable_parallel_hashagg_spill"
 
@@ -1018,6 +1142,10 @@ const (
 	// `PREDICATE`: Analyze only the columns that are used in the predicates of the query.
 	// `ALL`: Analyze all columns in the table.
 	TiDBAnalyzeColumnOptions = "tidb_analyze_column_options"
+	// TiDBPredicateColumnRetentionDays is the number of days after which a predicate column not used by any predicate
+	// is no longer treated as a predicate column, i.e. its stats are not collected by the predicate-column analyze.
+	// 0 means the predicate columns never expire.
+	TiDBPredicateColumnRetentionDays = "tidb_predicate_column_retention_days"
 	// TiDBDisableColumnTrackingTime records the last time TiDBEnableColumnTracking is set off.
 	// It is used to invalidate the collected predicate columns after turning off TiDBEnableColumnTracking, which avoids physical deletion.
 	// It doesn't have cache in memory, and we directly get/set the variable value from/to mysql.tidb.
@@ -1025,6 +1153,24 @@ const (
 	TiDBDisableColumnTrackingTime = "tidb_disable_column_tracking_time"
 	// TiDBStatsLoadPseudoTimeout indicates whether to fallback to pseudo stats after load timeout.
 	TiDBStatsLoadPseudoTimeout = "tidb_stats_load_pseudo_timeout"
+	// TiDBStatsLoadMaxItemsPerQuery is the max number of the stats items that a statement can send to the sync-load
+	// workers at the same time, so that a statement missing the stats of many columns doesn't starve the others.
+	// 0 means no limit.
+	TiDBStatsLoadMaxItemsPerQuery = "tidb_stats_load_max_items_per_query"
+	// TiDBOptStaleStatsRiskMode indicates whether the optimizer trusts the estimations on the tables whose stats are
+	// stale. Please see comments of SessionVars.StaleStatsRiskMode for details.
+	TiDBOptStaleStatsRiskMode = "tidb_opt_stale_stats_risk_mode"
+	// TiDBOptStaleStatsHealthyThreshold is the threshold of the stats healthy below which the stats of a table are
+	// considered stale by tidb_opt_stale_stats_risk_mode.
+	TiDBOptStaleStatsHealthyThreshold = "tidb_opt_stale_stats_healthy_threshold"
+	// TiDBOptExtrapolateMonotonicHistogram indicates whether the optimizer extrapolates the histograms of the
+	// monotonically increasing columns for the out-of-range estimation.
+	// Please see comments of SessionVars.ExtrapolateMonotonicHistogram for details.
+	TiDBOptExtrapolateMonotonicHistogram = "tidb_opt_extrapolate_monotonic_histogram"
+	// TiDBStatsLoadTimeoutMode indicates which stats the optimizer uses for a table whose stats are not loaded
+	// because the sync loading times out.
+	// Please see comments of SessionVars.StatsLoadTimeoutMode for details.
+	TiDBStatsLoadTimeoutMode = "tidb_stats_load_timeout_mode"
 	// TiDBMemQuotaBindingCache indicates the memory quota for the bind cache.
 	TiDBMemQuotaBindingCache = "tidb_mem_quota_binding_cache"
 	// TiDBRCReadCheckTS indicates the tso optimization for read-consistency read is enabled.
@@ -1039,11 +1185,23 @@ const (
 	TiDBStatsCacheMemQuota = "tidb_stats_cache_mem_quota"
 	// TiDBMemQuotaAnalyze indicates the memory quota for all analyze jobs.
 	TiDBMemQuotaAnalyze = "tidb_mem_quota_analyze"
+	// TiDBAnalyzeOOMAction indicates what operation TiDB performs when an analyze exceeds tidb_mem_quota_analyze.
+	// DEGRADE retries the analyze of the table with fewer samples, and CANCEL fails the analyze directly.
+	TiDBAnalyzeOOMAction = "tidb_analyze_oom_action"
 	// TiDBEnableAutoAnalyze determines whether TiDB executes automatic analysis.
 	// In test, we disable it by default. See GlobalSystemVariableInitialValue for details.
 	TiDBEnableAutoAnalyze = "tidb_enable_auto_analyze"
 	// TiDBEnableAutoAnalyzePriorityQueue determines whether TiDB executes automatic analysis with priority queue.
 	TiDBEnableAutoAnalyzePriorityQueue = "tidb_enable_auto_analyze_priority_queue"
+	// TiDBEnableCardinalityFeedback determines whether to collect the estimated and actual row counts of the scans,
+	// which are used to raise the priority of the tables with inaccurate stats in the auto analyze queue.
+	TiDBEnableCardinalityFeedback = "tidb_enable_cardinality_feedback"
+	// TiDBEnableStatsVersionMigration determines whether the stats owner re-analyzes the tables with the version 1
+	// stats in the background when tidb_analyze_version is 2, so that all the tables use the version 2 stats.
+	TiDBEnableStatsVersionMigration = "tidb_enable_stats_version_migration"
+	// TiDBEnableBackgroundMergeGlobalStats determines whether the analyze merges the partition-level stats into
+	// the global-level stats in a background worker instead of merging them synchronously.
+	TiDBEnableBackgroundMergeGlobalStats = "tidb_enable_background_merge_global_stats"
 	// TiDBMemOOMAction indicates what operation TiDB perform when a single SQL statement exceeds
 	// the memory quota specified by tidb_mem_quota_query and cannot be spilled to disk.
 	TiDBMemOOMAction = "tidb_mem_oom_action"
@@ -1054,6 +1212,86 @@ const (
 	TiDBMaxAutoAnalyzeTime = "tidb_max_auto_analyze_time"
 	// TiDBAutoAnalyzeConcurrency is the concurrency of the auto analyze
 	TiDBAutoAnalyzeConcurrency = "tidb_auto_analyze_concurrency"
+	// TiDBAutoAnalyzeCoolDown is the number of minutes that auto analyze won't analyze a table again after a successful
+	// manual analyze. 0 indicates that there is no cool-down. It can be overridden by the table option
+	// STATS_AUTO_ANALYZE_COOL_DOWN.
+	TiDBAutoAnalyzeCoolDown = "tidb_auto_analyze_cool_down"
+	// TiDBEnableWorkloadAwareAnalyze indicates whether auto analyze applies the default analyze options of the
+	// workload class of the table, e.g. append-only or update-heavy. The options specified in the statement or
+	// persisted for the table still take precedence.
+	TiDBEnableWorkloadAwareAnalyze = "tidb_enable_workload_aware_analyze"
+	// TiDBStatsTemplateRepresentatives is the number of the representative tables of a stats template.
+	// Tables with the same name and the same structure in different schemas, e.g. the per-tenant schemas
+	// of a SaaS application, make up a stats template. Only the representative tables, which are in the
+	// first schemas in name order, are auto analyzed, and other tables without stats share the stats of
+	// the representative tables. 0 means the stats are not shared.
+	TiDBStatsTemplateRepresentatives = "tidb_stats_template_representatives"
+	// TiDBStatsTemplateOptOutSchemas is the comma separated schemas that don't share the stats of the stats
+	// templates. Their tables are auto analyzed and use their own stats as usual.
+	TiDBStatsTemplateOptOutSchemas = "tidb_stats_template_opt_out_schemas"
+	// TiDBAutoAnalyzeColumnChoice specifies the columns whose stats are collected by auto analyze.
+	// 'DEFAULT' means following the persisted analyze options and tidb_analyze_column_options.
+	// 'PREDICATE' means only collecting the stats of the columns recorded in mysql.column_stats_usage,
+	// which reduces the analyze cost on wide tables where only a few columns appear in predicates.
+	TiDBAutoAnalyzeColumnChoice = "tidb_auto_analyze_column_choice"
+	// TiDBAutoAnalyzeQuickRefreshRatio is the change ratio above which auto analyze refreshes the row count and the
+	// null counts of a table without rebuilding its histograms, when the change ratio hasn't reached
+	// tidb_auto_analyze_ratio yet. 0 disables the quick refresh.
+	TiDBAutoAnalyzeQuickRefreshRatio = "tidb_auto_analyze_quick_refresh_ratio"
+	// TiDBAutoAnalyzeQuickSizeThreshold is the size of a table, i.e. the row count multiplied by the number of the
+	// columns, above which auto analyze uses ANALYZE TABLE ... QUICK to build the approximate stats. 0 disables it.
+	TiDBAutoAnalyzeQuickSizeThreshold = "tidb_auto_analyze_quick_size_threshold"
+	// TiDBAutoAnalyzeSystemTables is the comma separated tables in the system schemas, e.g. 'mysql.analyze_jobs',
+	// which are auto analyzed like the user tables. Other tables in the system schemas are never auto analyzed.
+	TiDBAutoAnalyzeSystemTables = "tidb_auto_analyze_system_tables"
+	// TiDBAutoAnalyzeDecisionLogSampleRate is the ratio of the decisions of auto analyze, i.e. enqueuing, skipping
+	// and dispatching the tables, which are logged. 0 only logs the decisions of tidb_auto_analyze_decision_log_tables.
+	TiDBAutoAnalyzeDecisionLogSampleRate = "tidb_auto_analyze_decision_log_sample_rate"
+	// TiDBAutoAnalyzeDecisionLogTables is the comma separated tables, e.g. 'test.t1,test.t2', whose decisions of auto
+	// analyze are always logged regardless of tidb_auto_analyze_decision_log_sample_rate.
+	TiDBAutoAnalyzeDecisionLogTables = "tidb_auto_analyze_decision_log_tables"
+	// TiDBAutoAnalyzeStatsProbeInterval is the interval of the stats probe, which runs tiny aggregate queries on the
+	// most queried tables and compares the results with their stats. The tables whose stats are verified to drift are
+	// analyzed with a high priority. 0 disables the probe.
+	TiDBAutoAnalyzeStatsProbeInterval = "tidb_auto_analyze_stats_probe_interval"
+	// TiDBStatsRollupRecentPartitions is the number of the most recent partitions of a range partitioned table, e.g. a
+	// time-series table partitioned by time, which keep the full stats refreshed by auto analyze. The older partitions
+	// are no longer analyzed automatically, and only the rollups of their stats maintained by the DML deltas are kept
+	// fresh. 0 disables the rollups.
+	TiDBStatsRollupRecentPartitions = "tidb_stats_rollup_recent_partitions"
+	// TiDBStatsSessionPoolSize is the max number of the internal sessions used by the statistics operations
+	// concurrently, which are taken from the dedicated session pool of the stats handle. Part of the sessions are
+	// reserved for the sync loads and the delta dumps, so they are not blocked by the auto analyze jobs.
+	TiDBStatsSessionPoolSize = "tidb_stats_session_pool_size"
+	// TiDBStatsTinyTableThreshold is the row count of a non-partitioned table at or below which the table is never
+	// analyzed automatically. Instead, its row count is refreshed by a cheap count scan when its stats delta is
+	// dumped, since the tiny tables like the config tables churn the auto analyze queue with high change ratios.
+	// 0 disables the fast path.
+	TiDBStatsTinyTableThreshold = "tidb_stats_tiny_table_threshold"
+	// TiDBAnalyzeVerifyStats indicates whether to verify the stats built by ANALYZE against the existing ones before
+	// they are saved. The suspicious stats, e.g. whose row count is far from the current one, or whose NDV collapses to
+	// 1, are not saved but quarantined in mysql.analyze_quarantine for review.
+	TiDBAnalyzeVerifyStats = "tidb_analyze_verify_stats"
+	// TiDBAutoAnalyzeMaxJobsPerStore is the max number of the auto analyze jobs running concurrently on the tables
+	// whose region leaders are on the same TiKV store. The jobs exceeding it are staggered to the next rounds, so the
+	// small tables colocated on a few stores don't put all the analyze scans on them at the same time. 0 means no
+	// limit.
+	TiDBAutoAnalyzeMaxJobsPerStore = "tidb_auto_analyze_max_jobs_per_store"
+	// TiDBStatsAlertStaleRatio is the change percentage of a table above which an alert is recorded in
+	// mysql.stats_alerts, although auto analyze is enabled for the table. 0 disables the alerts.
+	TiDBStatsAlertStaleRatio = "tidb_stats_alert_stale_ratio"
+	// TiDBStatsAlertEstimationErrorThreshold is the estimation error of a table above which an alert is recorded in
+	// mysql.stats_alerts, although auto analyze is enabled for the table. 0 disables the alerts.
+	TiDBStatsAlertEstimationErrorThreshold = "tidb_stats_alert_estimation_error_threshold"
+	// TiDBAutoAnalyzePlacementWindows is the auto analyze windows of the data placed on the stores with the labels by
+	// the placement rules. It's a semicolon separated list of rules like
+	// `region=us-east-1: start=01:00 -0500, end=05:00 -0500; disk=hdd: start=22:00 +0000, end=06:00 +0000`.
+	// The tables and partitions placed on the stores with the labels are only auto analyzed within the windows, which
+	// are usually the off-peak hours local to the stores.
+	TiDBAutoAnalyzePlacementWindows = "tidb_auto_analyze_placement_windows"
+	// TiDBAutoAnalyzeResourceGroup is the resource group under which the auto analyze jobs are executed, unless the
+	// RESOURCE_GROUP option of the table is set in mysql.auto_analyze_config. Empty means the default resource group.
+	TiDBAutoAnalyzeResourceGroup = "tidb_auto_analyze_resource_group"
 	// TiDBEnableDistTask indicates whether to enable the distributed execute background tasks(For example DDL, Import etc).
 	TiDBEnableDistTask = "tidb_enable_dist_task"
 	// TiDBEnableFastCreateTable indicates whether to enable the fast create table feature.
@@ -1115,6 +1353,27 @@ const (
 	PasswordReuseTime = "password_reuse_interval"
 	// TiDBHistoricalStatsDuration indicates the duration to remain tidb historical stats
 	TiDBHistoricalStatsDuration = "tidb_historical_stats_duration"
+	// TiDBStatsGCInterval is the interval of the stats GC. 0 means 100 times of the stats lease.
+	TiDBStatsGCInterval = "tidb_stats_gc_interval"
+	// TiDBStatsGCRetention is the min duration to keep the stats of the dropped tables, partitions, columns and
+	// indexes before the GC deletes them. The stats are kept at least for 10 times of the stats lease anyway.
+	TiDBStatsGCRetention = "tidb_stats_gc_retention"
+	// TiDBStatsGCBatchSize is the number of the rows deleted in a transaction when the stats GC cleans up the
+	// stats of the dropped tables.
+	TiDBStatsGCBatchSize = "tidb_stats_gc_batch_size"
+	// TiDBAnalyzeJobsMaxAge is the max age of the analyze job history in mysql.analyze_jobs. 0 means the history is not
+	// pruned by the age.
+	TiDBAnalyzeJobsMaxAge = "tidb_analyze_jobs_max_age"
+	// TiDBAnalyzeJobsMaxRows is the max number of the rows of the analyze job history in mysql.analyze_jobs. 0 means the
+	// history is not pruned by the row count.
+	TiDBAnalyzeJobsMaxRows = "tidb_analyze_jobs_max_rows"
+	// TiDBAnalyzeCheckpointTTL is the max age of the checkpoints of the partitions analyzed by an interrupted ANALYZE,
+	// which are skipped when the same ANALYZE statement runs again. 0 means the checkpoints are disabled.
+	TiDBAnalyzeCheckpointTTL = "tidb_analyze_checkpoint_ttl"
+	// TiDBAnalyzeSnapshotStaleness is the staleness of the snapshot at which the analyze reads the tables, so the scan
+	// doesn't need to resolve the locks of the recent writes. The snapshot is advanced once it's older than twice the
+	// staleness. 0 means the analyze reads the latest data.
+	TiDBAnalyzeSnapshotStaleness = "tidb_analyze_snapshot_staleness"
 	// TiDBEnableHistoricalStatsForCapture indicates whether use historical stats in plan replayer capture
 	TiDBEnableHistoricalStatsForCapture = "tidb_enable_historical_stats_for_capture"
 	// TiDBEnableResourceControl indicates whether resource control feature is enabled
@@ -1135,6 +1394,9 @@ const (
 	// TiDBTTLRunningTasks limits the count of running ttl tasks. Default to 0, means 3 times the count of TiKV (or no
 	// limitation, if the storage is not TiKV).
 	TiDBTTLRunningTasks = "tidb_ttl_running_tasks"
+	// TiDBTTLStatsRefreshRatio is the ratio of the rows deleted by a TTL job to the row count of the table, above which
+	// the table is pushed into the auto analyze priority queue when the TTL job finishes. 0 means disabled.
+	TiDBTTLStatsRefreshRatio = "tidb_ttl_stats_refresh_ratio"
 	// AuthenticationLDAPSASLAuthMethodName defines the authentication method used by LDAP SASL authentication plugin
 	AuthenticationLDAPSASLAuthMethodName = "authentication_ldap_sasl_auth_method_name"
 	// AuthenticationLDAPSASLCAPath defines the ca certificate to verify LDAP connection in LDAP SASL authentication plugin
@@ -1183,7 +1445,8 @@ const (
 	AuthenticationLDAPSimpleInitPoolSize = "authentication_ldap_simple_init_pool_size"
 	// AuthenticationLDAPSimpleMaxPoolSize defines the max size of connection pool to LDAP server for SASL plugin.
 	AuthenticationLDAPSimpleMaxPoolSize = "authentication_ldap_simple_max_pool_size"
-	// TiDBRuntimeFilterTypeName the value of is string, a runtime filter type list split by ",", such as: "IN,MIN_MAX"
+	// TiDBRuntimeFilterTypeName the value of is string, a runtime filter type list split by ",", such as: "IN,MIN_MAX".
+	// The BLOOM_FILTER type is only generated for the hash joins whose build side is selective enough by the stats.
 	TiDBRuntimeFilterTypeName = "tidb_runtime_filter_type"
 	// TiDBRuntimeFilterModeName the mode of runtime filter, such as "OFF", "LOCAL"
 	TiDBRuntimeFilterModeName = "tidb_runtime_filter_mode"
@@ -1191,6 +1454,10 @@ const (
 	// When set to true, skip missing partition stats and continue to merge other partition stats to global stats.
 	// When set to false, give up merging partition stats to global stats.
 	TiDBSkipMissingPartitionStats = "tidb_skip_missing_partition_stats"
+	// TiDBEnableHLLNDVSketch indicates whether to persist the HLL sketches of the partition stats and use them to merge
+	// the global NDV. The FMSketches are still used if any partition doesn't have the HLL sketch. The merged global
+	// sketches are kept in mysql.stats_global_ndv_sketch and maintained incrementally when the partitions are analyzed.
+	TiDBEnableHLLNDVSketch = "tidb_enable_hll_ndv_sketch"
 	// TiDBSessionAlias indicates the alias of a session which is used for tracing.
 	TiDBSessionAlias = "tidb_session_alias"
 	// TiDBServiceScope indicates the role for tidb for distributed task framework.
@@ -1204,6 +1471,21 @@ const (
 	// Any idle transaction will be killed after being idle for `tidb_idle_transaction_timeout` seconds.
 	// This is similar to https://docs.percona.com/percona-server/5.7/management/innodb_kill_idle_trx.html and https://mariadb.com/kb/en/transaction-timeouts/
 	TiDBIdleTransactionTimeout = "tidb_idle_transaction_timeout"
+	// TiDBLongTxnPolicy is the policy applied to the transactions that are idle or open for too long, which block
+	// the GC and hold the locks. It's a semicolon separated list of rules like
+	// `idle=5m,open=1h,action=warn; rg1: open=10m,action=kill`. A rule prefixed by a resource group name applies to
+	// the transactions in the resource group, and the rule without the prefix applies to the other transactions.
+	// The action is one of `warn`, which records the transaction in INFORMATION_SCHEMA.TIDB_LONG_TXN_EVENTS,
+	// `notify`, which also writes a log and reports a metric, and `kill`, which kills the connection to roll back
+	// the transaction. A rule without `idle` and `open` exempts the transactions in the resource group.
+	TiDBLongTxnPolicy = "tidb_long_txn_policy"
+	// TiDBLongTxnPolicyExemptUsers is the comma separated users whose transactions are exempted from tidb_long_txn_policy.
+	TiDBLongTxnPolicyExemptUsers = "tidb_long_txn_policy_exempt_users"
+	// TiDBInternalTableRetention is the retention of the internal job and bookkeeping tables, which grow unbounded
+	// on busy clusters. It's a semicolon separated list of rules like
+	// `analyze_jobs: age=72h,rows=100000; tidb_runaway_queries: size=1GiB`. The rows exceeding any of the caps of
+	// the age, the row count and the table size are deleted from the oldest in low priority batches by the DDL owner.
+	TiDBInternalTableRetention = "tidb_internal_table_retention"
 	// TiDBLowResolutionTSOUpdateInterval defines how often to refresh low resolution timestamps.
 	TiDBLowResolutionTSOUpdateInterval = "tidb_low_resolution_tso_update_interval"
 	// TiDBDMLType indicates the execution type of DML in TiDB.
@@ -1233,6 +1515,9 @@ const (
 
 	// MaxPreSplitRegions is the maximum number of regions that can be pre-split.
 	MaxPreSplitRegions = 15
+
+	// MaxAutoAnalyzeCoolDown is the maximum minutes of the auto analyze cool-down, which is one week.
+	MaxAutoAnalyzeCoolDown = 7 * 24 * 60
 )
 
 // Default TiDB system variable values.
@@ -1261,6 +1546,7 @@ const (
 	DefOptMPPOuterJoinFixedBuildSide        = false
 	DefOptWriteRowID                        = false
 	DefOptEnableCorrelationAdjustment       = true
+	DefOptEnableFKJoinEstimation            = true
 	DefOptLimitPushDownThreshold            = 100
 	DefOptCorrelationThreshold              = 0.9
 	DefOptCorrelationExpFactor              = 1
@@ -1355,6 +1641,8 @@ const (
 	DefTiDBAllowRemoveAutoInc               = false
 	DefTiDBUsePlanBaselines                 = true
 	DefTiDBEvolvePlanBaselines              = false
+	DefTiDBCaptureRegressedPlanBaselines    = false
+	DefTiDBPlanBaselineRegressionRatio      = 2.0
 	DefTiDBEvolvePlanTaskMaxTime            = 600 // 600s
 	DefTiDBEvolvePlanTaskStartTime          = "00:00 +0000"
 	DefTiDBEvolvePlanTaskEndTime            = "23:59 +0000"
@@ -1370,6 +1658,7 @@ const (
 	DefTiDBRedactLog                        = Off
 	DefTiDBRestrictedReadOnly               = false
 	DefTiDBSuperReadOnly                    = false
+	DefTiDBInstanceReadOnly                 = false
 	DefTiDBShardAllocateStep                = math.MaxInt64
 	DefTiDBEnableTelemetry                  = false
 	DefTiDBEnableParallelApply              = false
@@ -1409,6 +1698,11 @@ const (
 	DefTiDBPersistAnalyzeOptions                      = true
 	DefTiDBStatsLoadSyncWait                          = 100
 	DefTiDBStatsLoadPseudoTimeout                     = true
+	DefTiDBStatsLoadMaxItemsPerQuery                  = 64
+	DefTiDBStatsLoadTimeoutMode                       = StatsLoadTimeoutModeDegraded
+	DefTiDBOptStaleStatsRiskMode                      = StaleStatsRiskModeTrust
+	DefTiDBOptStaleStatsHealthyThreshold              = 50
+	DefTiDBOptExtrapolateMonotonicHistogram           = false
 	DefSysdateIsNow                                   = false
 	DefTiDBEnableParallelHashaggSpill                 = true
 	DefTiDBEnableMutationChecker                      = false
@@ -1435,10 +1729,35 @@ const (
 	DefTiDBMemQuotaAnalyze                            = -1
 	DefTiDBEnableAutoAnalyze                          = true
 	DefTiDBEnableAutoAnalyzePriorityQueue             = true
+	DefTiDBEnableCardinalityFeedback                  = true
+	DefTiDBEnableStatsVersionMigration                = false
+	DefTiDBEnableBackgroundMergeGlobalStats           = false
 	DefTiDBAnalyzeColumnOptions                       = "PREDICATE"
+	DefTiDBPredicateColumnRetentionDays               = 0
 	DefTiDBMemOOMAction                               = "CANCEL"
+	DefTiDBAnalyzeOOMAction                           = AnalyzeOOMActionDegrade
 	DefTiDBMaxAutoAnalyzeTime                         = 12 * 60 * 60
 	DefTiDBAutoAnalyzeConcurrency                     = 1
+	DefTiDBAutoAnalyzeCoolDown                        = 0
+	DefTiDBEnableWorkloadAwareAnalyze                 = false
+	DefTiDBStatsTemplateRepresentatives               = 0
+	DefTiDBStatsTemplateOptOutSchemas                 = ""
+	DefTiDBAutoAnalyzeColumnChoice                    = "DEFAULT"
+	DefTiDBAutoAnalyzeQuickRefreshRatio               = 0.0
+	DefTiDBAutoAnalyzeQuickSizeThreshold              = 0
+	DefTiDBAutoAnalyzeSystemTables                    = ""
+	DefTiDBAutoAnalyzeDecisionLogSampleRate           = 0.0
+	DefTiDBAutoAnalyzeDecisionLogTables               = ""
+	DefTiDBAutoAnalyzeStatsProbeInterval              = time.Duration(0)
+	DefTiDBStatsRollupRecentPartitions                = 0
+	DefTiDBStatsSessionPoolSize                       = 64
+	DefTiDBStatsTinyTableThreshold                    = 0
+	DefTiDBAnalyzeVerifyStats                         = false
+	DefTiDBAutoAnalyzeMaxJobsPerStore                 = 0
+	DefTiDBStatsAlertStaleRatio                       = 0.0
+	DefTiDBStatsAlertEstimationErrorThreshold         = 0.0
+	DefTiDBAutoAnalyzePlacementWindows                = ""
+	DefTiDBAutoAnalyzeResourceGroup                   = ""
 	DefTiDBEnablePrepPlanCache                        = true
 	DefTiDBPrepPlanCacheSize                          = 100
 	DefTiDBSessionPlanCacheSize                       = 100
@@ -1466,6 +1785,9 @@ const (
 	DefExecutorConcurrency                            = 5
 	DefTiDBEnableNonPreparedPlanCache                 = false
 	DefTiDBEnableNonPreparedPlanCacheForDML           = false
+	DefTiDBEnableNonPrepPlanCacheForSubquery          = false
+	DefTiDBNonPreparedPlanCacheMaxJoinTables          = 2
+	DefTiDBNonPreparedPlanCacheMaxInListLen           = 200
 	DefTiDBNonPreparedPlanCacheSize                   = 100
 	DefTiDBPlanCacheMaxPlanSize                       = 2 * size.MB
 	DefTiDBInstancePlanCacheMaxMemSize                = 100 * size.MB
@@ -1484,6 +1806,7 @@ const (
 	DefTiDBAnalyzePartitionConcurrency           = 2
 	DefTiDBOptRangeMaxSize                       = 64 * int64(size.MB) // 64 MB
 	DefTiDBCostModelVer                          = 2
+	DefTiDBCostModelV2CalibratedFactors          = ""
 	DefTiDBServerMemoryLimitSessMinSize          = 128 << 20
 	DefTiDBMergePartitionStatsConcurrency        = 1
 	DefTiDBServerMemoryLimitGCTrigger            = 0.7
@@ -1499,6 +1822,10 @@ const (
 	DefTiDBEnableReusechunk                           = true
 	DefTiDBUseAlloc                                   = false
 	DefTiDBEnablePlanReplayerCapture                  = true
+	DefTiDBPlanReplayerStatsFormat                    = PlanReplayerStatsFormatJSON
+	DefTiDBPlanReplayerSnapshotInterval               = time.Duration(0)
+	DefTiDBPlanReplayerSnapshotMaxCount               = 24
+	DefTiDBEnableErrorAttributes                      = false
 	DefTiDBIndexMergeIntersectionConcurrency          = ConcurrencyUnset
 	DefTiDBTTLJobEnable                               = true
 	DefTiDBTTLScanBatchSize                           = 500
@@ -1509,10 +1836,18 @@ const (
 	DefTiDBTTLDeleteBatchMinSize                      = 1
 	DefTiDBTTLDeleteRateLimit                         = 0
 	DefTiDBTTLRunningTasks                            = -1
+	DefTiDBTTLStatsRefreshRatio                       = 0.0
 	DefPasswordReuseHistory                           = 0
 	DefPasswordReuseTime                              = 0
 	DefTiDBStoreBatchSize                             = 4
 	DefTiDBHistoricalStatsDuration                    = 7 * 24 * time.Hour
+	DefTiDBStatsGCInterval                            = time.Duration(0)
+	DefTiDBStatsGCRetention                           = time.Duration(0)
+	DefTiDBStatsGCBatchSize                           = 1024
+	DefTiDBAnalyzeJobsMaxAge                          = 7 * 24 * time.Hour
+	DefTiDBAnalyzeJobsMaxRows                         = 0
+	DefTiDBAnalyzeCheckpointTTL                       = 24 * time.Hour
+	DefTiDBAnalyzeSnapshotStaleness                   = time.Duration(0)
 	DefTiDBEnableHistoricalStatsForCapture            = false
 	DefTiDBTTLJobScheduleWindowStartTime              = "00:00 +0000"
 	DefTiDBTTLJobScheduleWindowEndTime                = "23:59 +0000"
@@ -1532,6 +1867,9 @@ const (
 	DefTiDBOptOrderingIdxSelRatio                     = -1
 	DefTiDBOptEnableMPPSharedCTEExecution             = false
 	DefTiDBPlanCacheInvalidationOnFreshStats          = true
+	DefTiDBPlanCacheStatsInvalidationPolicy           = PlanCacheStatsInvalidationAnyChange
+	DefTiDBPlanCacheStatsInvalidationRatio            = 0.2
+	DefTiDBPlanCacheWarmUpAfterAnalyze                = 0
 	DefTiDBEnableRowLevelChecksum                     = false
 	DefAuthenticationLDAPSASLAuthMethodName           = "SCRAM-SHA-1"
 	DefAuthenticationLDAPSASLServerPort               = 389
@@ -1552,11 +1890,26 @@ const (
 	DefTiDBLockUnchangedKeys                          = true
 	DefTiDBEnableCheckConstraint                      = false
 	DefTiDBSkipMissingPartitionStats                  = true
+	DefTiDBEnableHLLNDVSketch                         = false
+	DefTiDBAnalyzeAdaptiveTopNLimit                   = 0
+	DefTiDBAnalyzeAdaptiveBucketLimit                 = 0
+	DefTiDBAnalyzeBlockSampleRate                     = 0.0
+	DefTiDBAnalyzeReadNearbyReplica                   = false
+	DefTiDBAnalyzeAutoTiFlash                         = false
+	DefTiDBAnalyzeDefaultSampleRate                   = 0.0
+	DefTiDBAnalyzeDefaultBuckets                      = 0
+	DefTiDBAnalyzeDefaultTopN                         = -1
+	DefTiDBAnalyzeDefaultColumnChoice                 = "DEFAULT"
+	DefTiDBAnalyzeValidateForeignKeys                 = false
+	DefTiDBAnalyzeCollectTrigramStats                 = false
 	DefTiDBOptEnableHashJoin                          = true
 	DefTiDBHashJoinVersion                            = joinversion.HashJoinVersionOptimized
 	DefTiDBOptObjective                               = OptObjectiveModerate
 	DefTiDBSchemaVersionCacheLimit                    = 16
 	DefTiDBIdleTransactionTimeout                     = 0
+	DefTiDBLongTxnPolicy                              = ""
+	DefTiDBLongTxnPolicyExemptUsers                   = ""
+	DefTiDBInternalTableRetention                     = ""
 	DefTiDBTxnEntrySizeLimit                          = 0
 	DefTiDBSchemaCacheSize                            = 512 * 1024 * 1024
 	DefTiDBLowResolutionTSOUpdateInterval             = 2000
@@ -1576,6 +1929,12 @@ var (
 	ProcessGeneralLog              = atomic.NewBool(false)
 	RunAutoAnalyze                 = atomic.NewBool(DefTiDBEnableAutoAnalyze)
 	EnableAutoAnalyzePriorityQueue = atomic.NewBool(DefTiDBEnableAutoAnalyzePriorityQueue)
+	// EnableCardinalityFeedback indicates whether to collect the feedback of the row count estimations.
+	EnableCardinalityFeedback = atomic.NewBool(DefTiDBEnableCardinalityFeedback)
+	// EnableStatsVersionMigration indicates whether to migrate the version 1 stats to the version 2 stats in the background.
+	EnableStatsVersionMigration = atomic.NewBool(DefTiDBEnableStatsVersionMigration)
+	// EnableBackgroundMergeGlobalStats indicates whether to merge the global stats in the background worker.
+	EnableBackgroundMergeGlobalStats = atomic.NewBool(DefTiDBEnableBackgroundMergeGlobalStats)
 	// AnalyzeColumnOptions is a global variable that indicates the default column choice for ANALYZE.
 	// The value of this variable is a string that can be one of the following values:
 	// "PREDICATE", "ALL".
@@ -1611,26 +1970,57 @@ var (
 	EnablePDFollowerHandleRegion         = atomic.NewBool(DefPDEnableFollowerHandleRegion)
 	RestrictedReadOnly                   = atomic.NewBool(DefTiDBRestrictedReadOnly)
 	VarTiDBSuperReadOnly                 = atomic.NewBool(DefTiDBSuperReadOnly)
+	InstanceReadOnly                     = atomic.NewBool(DefTiDBInstanceReadOnly)
 	PersistAnalyzeOptions                = atomic.NewBool(DefTiDBPersistAnalyzeOptions)
 	TableCacheLease                      = atomic.NewInt64(DefTiDBTableCacheLease)
 	StatsLoadSyncWait                    = atomic.NewInt64(DefTiDBStatsLoadSyncWait)
+	StatsLoadMaxItemsPerQuery            = atomic.NewInt64(DefTiDBStatsLoadMaxItemsPerQuery)
 	StatsLoadPseudoTimeout               = atomic.NewBool(DefTiDBStatsLoadPseudoTimeout)
+	PredicateColumnRetentionDays         = atomic.NewInt64(DefTiDBPredicateColumnRetentionDays)
 	MemQuotaBindingCache                 = atomic.NewInt64(DefTiDBMemQuotaBindingCache)
 	GCMaxWaitTime                        = atomic.NewInt64(DefTiDBGCMaxWaitTime)
 	StatsCacheMemQuota                   = atomic.NewInt64(DefTiDBStatsCacheMemQuota)
 	OOMAction                            = atomic.NewString(DefTiDBMemOOMAction)
+	AnalyzeOOMAction                     = atomic.NewString(DefTiDBAnalyzeOOMAction)
 	MaxAutoAnalyzeTime                   = atomic.NewInt64(DefTiDBMaxAutoAnalyzeTime)
 	// variables for plan cache
 	PreparedPlanCacheMemoryGuardRatio   = atomic.NewFloat64(DefTiDBPrepPlanCacheMemoryGuardRatio)
 	EnableInstancePlanCache             = atomic.NewBool(false)
 	InstancePlanCacheReservedPercentage = atomic.NewFloat64(0.1)
 	InstancePlanCacheMaxMemSize         = atomic.NewInt64(int64(DefTiDBInstancePlanCacheMaxMemSize))
+	PlanCacheStatsInvalidationPolicy    = atomic.NewString(DefTiDBPlanCacheStatsInvalidationPolicy)
+	PlanCacheStatsInvalidationRatio     = atomic.NewFloat64(DefTiDBPlanCacheStatsInvalidationRatio)
+	PlanCacheWarmUpAfterAnalyze         = atomic.NewInt64(DefTiDBPlanCacheWarmUpAfterAnalyze)
 	EnableDistTask                      = atomic.NewBool(DefTiDBEnableDistTask)
 	EnableFastCreateTable               = atomic.NewBool(DefTiDBEnableFastCreateTable)
 	EnableNoopVariables                 = atomic.NewBool(DefTiDBEnableNoopVariables)
 	EnableMDL                           = atomic.NewBool(false)
 	AutoAnalyzePartitionBatchSize       = atomic.NewInt64(DefTiDBAutoAnalyzePartitionBatchSize)
 	AutoAnalyzeConcurrency              = atomic.NewInt32(DefTiDBAutoAnalyzeConcurrency)
+	AutoAnalyzeCoolDown                 = atomic.NewInt64(DefTiDBAutoAnalyzeCoolDown)
+	EnableWorkloadAwareAnalyze          = atomic.NewBool(DefTiDBEnableWorkloadAwareAnalyze)
+	StatsTemplateRepresentatives        = atomic.NewInt64(DefTiDBStatsTemplateRepresentatives)
+	StatsTemplateOptOutSchemas          = atomic.NewString(DefTiDBStatsTemplateOptOutSchemas)
+	AutoAnalyzeColumnChoice             = atomic.NewString(DefTiDBAutoAnalyzeColumnChoice)
+	AutoAnalyzeQuickRefreshRatio        = atomic.NewFloat64(DefTiDBAutoAnalyzeQuickRefreshRatio)
+	AutoAnalyzeQuickSizeThreshold       = atomic.NewInt64(DefTiDBAutoAnalyzeQuickSizeThreshold)
+	AutoAnalyzeSystemTables             = atomic.NewString(DefTiDBAutoAnalyzeSystemTables)
+	AutoAnalyzeDecisionLogSampleRate    = atomic.NewFloat64(DefTiDBAutoAnalyzeDecisionLogSampleRate)
+	AutoAnalyzeDecisionLogTables        = atomic.NewString(DefTiDBAutoAnalyzeDecisionLogTables)
+	AutoAnalyzeStatsProbeInterval       = atomic.NewDuration(DefTiDBAutoAnalyzeStatsProbeInterval)
+	StatsRollupRecentPartitions         = atomic.NewInt64(DefTiDBStatsRollupRecentPartitions)
+	StatsSessionPoolSize                = atomic.NewInt64(DefTiDBStatsSessionPoolSize)
+	StatsTinyTableThreshold             = atomic.NewInt64(DefTiDBStatsTinyTableThreshold)
+	AnalyzeVerifyStats                  = atomic.NewBool(DefTiDBAnalyzeVerifyStats)
+	AutoAnalyzeMaxJobsPerStore          = atomic.NewInt64(DefTiDBAutoAnalyzeMaxJobsPerStore)
+	StatsAlertStaleRatio                = atomic.NewFloat64(DefTiDBStatsAlertStaleRatio)
+	StatsAlertEstimationErrorThreshold  = atomic.NewFloat64(DefTiDBStatsAlertEstimationErrorThreshold)
+	AutoAnalyzePlacementWindowsValue    = atomic.NewPointer[PlacementAnalyzeWindows](nil)
+	AutoAnalyzeResourceGroup            = atomic.NewString(DefTiDBAutoAnalyzeResourceGroup)
+	CostModelV2CalibratedFactors        = atomic.NewString(DefTiDBCostModelV2CalibratedFactors)
+	LongTxnPolicyValue                  = atomic.NewPointer[LongTxnPolicy](nil)
+	LongTxnPolicyExemptUsers            = atomic.NewString(DefTiDBLongTxnPolicyExemptUsers)
+	InternalTableRetentionValue         = atomic.NewPointer[InternalTableRetention](nil)
 	// EnableFastReorg indicates whether to use lightning to enhance DDL reorg performance.
 	EnableFastReorg = atomic.NewBool(DefTiDBEnableFastReorg)
 	// DDLDiskQuota is the temporary variable for set disk quota for lightning
@@ -1673,14 +2063,26 @@ var (
 	IsSandBoxModeEnabled            = atomic.NewBool(false)
 	MaxPreparedStmtCountValue       = atomic.NewInt64(DefMaxPreparedStmtCount)
 	HistoricalStatsDuration         = atomic.NewDuration(DefTiDBHistoricalStatsDuration)
+	StatsGCInterval                 = atomic.NewDuration(DefTiDBStatsGCInterval)
+	StatsGCRetention                = atomic.NewDuration(DefTiDBStatsGCRetention)
+	StatsGCBatchSize                = atomic.NewInt64(DefTiDBStatsGCBatchSize)
+	AnalyzeJobsMaxAge               = atomic.NewDuration(DefTiDBAnalyzeJobsMaxAge)
+	AnalyzeJobsMaxRows              = atomic.NewInt64(DefTiDBAnalyzeJobsMaxRows)
+	AnalyzeCheckpointTTL            = atomic.NewDuration(DefTiDBAnalyzeCheckpointTTL)
+	AnalyzeSnapshotStaleness        = atomic.NewDuration(DefTiDBAnalyzeSnapshotStaleness)
 	EnableHistoricalStatsForCapture = atomic.NewBool(DefTiDBEnableHistoricalStatsForCapture)
+	PlanReplayerSnapshotInterval    = atomic.NewDuration(DefTiDBPlanReplayerSnapshotInterval)
+	PlanReplayerSnapshotStorage     = atomic.NewString("")
+	PlanReplayerSnapshotMaxCount    = atomic.NewInt64(DefTiDBPlanReplayerSnapshotMaxCount)
 	TTLRunningTasks                 = atomic.NewInt32(DefTiDBTTLRunningTasks)
+	TTLStatsRefreshRatio            = atomic.NewFloat64(DefTiDBTTLStatsRefreshRatio)
 	// always set the default value to false because the resource control in kv-client is not inited
 	// It will be initialized to the right value after the first call of `rebuildSysVarCache`
 	EnableResourceControl           = atomic.NewBool(false)
 	EnableResourceControlStrictMode = atomic.NewBool(true)
 	EnableCheckConstraint           = atomic.NewBool(DefTiDBEnableCheckConstraint)
 	SkipMissingPartitionStats       = atomic.NewBool(DefTiDBSkipMissingPartitionStats)
+	EnableHLLNDVSketch              = atomic.NewBool(DefTiDBEnableHLLNDVSketch)
 	TiFlashEnablePipelineMode       = atomic.NewBool(DefTiDBEnableTiFlashPipelineMode)
 	ServiceScope                    = atomic.NewString("")
 	SchemaVersionCacheLimit         = atomic.NewInt64(DefTiDBSchemaVersionCacheLimit)
@@ -83,6 +83,10 @@ const (
 	OOMActionCancel = "CANCEL"
 	// OOMActionLog constants represents the valid action configurations for OOMAction "LOG".
 	OOMActionLog = "LOG"
+	// AnalyzeOOMActionCancel represents the analyze is cancelled when it exceeds tidb_mem_quota_analyze.
+	AnalyzeOOMActionCancel = "CANCEL"
+	// AnalyzeOOMActionDegrade represents the analyze is retried with fewer samples when it exceeds tidb_mem_quota_analyze.
+	AnalyzeOOMActionDegrade = "DEGRADE"
 
 	// TSOClientRPCModeDefault is a choice of variable TiDBTSOClientRPCMode. In this mode, the TSO client sends batched
 	// TSO requests serially.
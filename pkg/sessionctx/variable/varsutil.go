@@ -464,6 +464,54 @@ func parseTSFromNumberOrTime(s *SessionVars, sVal string) (uint64, error) {
 	return oracle.GoTimeToTS(t1), err
 }
 
+// parsePinnedStats parses the value of tidb_pin_stats, which is a comma separated list of `db.table@version`.
+func parsePinnedStats(s *SessionVars, sVal string) (map[string]uint64, error) {
+	sVal = strings.TrimSpace(sVal)
+	if sVal == "" {
+		return nil, nil
+	}
+	pinned := make(map[string]uint64)
+	for _, item := range strings.Split(sVal, ",") {
+		item = strings.TrimSpace(item)
+		tbl, ver, ok := strings.Cut(item, "@")
+		if !ok {
+			return nil, ErrWrongValueForVar.GenWithStackByArgs(TiDBPinStats, sVal)
+		}
+		dbName, tblName, ok := strings.Cut(strings.TrimSpace(tbl), ".")
+		if !ok || dbName == "" || tblName == "" {
+			return nil, ErrWrongValueForVar.GenWithStackByArgs(TiDBPinStats, sVal)
+		}
+		version, err := parseTSFromNumberOrTime(s, strings.TrimSpace(ver))
+		if err != nil || version == 0 {
+			return nil, ErrWrongValueForVar.GenWithStackByArgs(TiDBPinStats, sVal)
+		}
+		pinned[PinnedStatsKey(dbName, tblName)] = version
+	}
+	return pinned, nil
+}
+
+// parseIgnoreStatsTables parses the value of tidb_ignore_stats, which is a comma separated list of `db.table`.
+func parseIgnoreStatsTables(sVal string) (map[string]struct{}, error) {
+	sVal = strings.TrimSpace(sVal)
+	if sVal == "" {
+		return nil, nil
+	}
+	tables := make(map[string]struct{})
+	for _, item := range strings.Split(sVal, ",") {
+		dbName, tblName, ok := strings.Cut(strings.TrimSpace(item), ".")
+		if !ok || dbName == "" || tblName == "" {
+			return nil, ErrWrongValueForVar.GenWithStackByArgs(TiDBIgnoreStats, sVal)
+		}
+		tables[PinnedStatsKey(dbName, tblName)] = struct{}{}
+	}
+	return tables, nil
+}
+
+// PinnedStatsKey returns the key of the table in SessionVars.PinnedStats and SessionVars.IgnoreStatsTables.
+func PinnedStatsKey(dbName, tblName string) string {
+	return strings.ToLower(dbName) + "." + strings.ToLower(tblName)
+}
+
 func setTxnReadTS(s *SessionVars, sVal string) error {
 	if sVal == "" {
 		s.TxnReadTS = NewTxnReadTS(0)
@@ -642,3 +690,273 @@ func parseSchemaCacheSize(s *SessionVars, normalizedValue string, originalValue
 
 	return 0, "", ErrTruncatedWrongValue.GenWithStackByArgs(TiDBSchemaCacheSize, originalValue)
 }
+
+// The actions of tidb_long_txn_policy.
+const (
+	// LongTxnActionWarn records the transaction in INFORMATION_SCHEMA.TIDB_LONG_TXN_EVENTS.
+	LongTxnActionWarn = "warn"
+	// LongTxnActionNotify records the transaction, writes a log and reports a metric.
+	LongTxnActionNotify = "notify"
+	// LongTxnActionKill records the transaction and kills the connection to roll it back.
+	LongTxnActionKill = "kill"
+)
+
+// LongTxnRule is a rule of tidb_long_txn_policy.
+type LongTxnRule struct {
+	// IdleTimeout is the max time a transaction can be idle, 0 means no limit.
+	IdleTimeout time.Duration
+	// OpenTimeout is the max time a transaction can be open, 0 means no limit.
+	OpenTimeout time.Duration
+	Action      string
+}
+
+// LongTxnPolicy is the parsed tidb_long_txn_policy.
+type LongTxnPolicy struct {
+	// Default is the rule of the transactions in the resource groups without their own rules.
+	Default *LongTxnRule
+	// Groups is the rules of the resource groups, keyed by the lower case group names.
+	Groups map[string]*LongTxnRule
+}
+
+// Rule returns the rule applied to the transactions in the resource group, nil if there is none.
+func (p *LongTxnPolicy) Rule(group string) *LongTxnRule {
+	if p == nil {
+		return nil
+	}
+	if group == "" {
+		group = "default"
+	}
+	if rule, ok := p.Groups[strings.ToLower(group)]; ok {
+		return rule
+	}
+	return p.Default
+}
+
+// ParseLongTxnPolicy parses tidb_long_txn_policy. It returns nil if the policy is empty.
+func ParseLongTxnPolicy(val string) (*LongTxnPolicy, error) {
+	var policy *LongTxnPolicy
+	for _, item := range strings.Split(val, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if policy == nil {
+			policy = &LongTxnPolicy{Groups: make(map[string]*LongTxnRule)}
+		}
+		group := ""
+		if idx := strings.Index(item, ":"); idx >= 0 {
+			group = strings.ToLower(strings.TrimSpace(item[:idx]))
+			item = item[idx+1:]
+			if group == "" {
+				return nil, errors.Errorf("empty resource group name in '%s'", val)
+			}
+		}
+		rule := &LongTxnRule{Action: LongTxnActionWarn}
+		for _, opt := range strings.Split(item, ",") {
+			k, v, ok := strings.Cut(opt, "=")
+			if !ok {
+				return nil, errors.Errorf("invalid option '%s'", strings.TrimSpace(opt))
+			}
+			k, v = strings.ToLower(strings.TrimSpace(k)), strings.TrimSpace(v)
+			switch k {
+			case "idle", "open":
+				d, err := time.ParseDuration(v)
+				if err != nil {
+					return nil, err
+				}
+				if d <= 0 {
+					return nil, errors.Errorf("the duration of '%s' should be positive", k)
+				}
+				if k == "idle" {
+					rule.IdleTimeout = d
+				} else {
+					rule.OpenTimeout = d
+				}
+			case "action":
+				rule.Action = strings.ToLower(v)
+				switch rule.Action {
+				case LongTxnActionWarn, LongTxnActionNotify, LongTxnActionKill:
+				default:
+					return nil, errors.Errorf("unknown action '%s'", v)
+				}
+			default:
+				return nil, errors.Errorf("unknown option '%s'", k)
+			}
+		}
+		if group == "" {
+			if policy.Default != nil {
+				return nil, errors.New("duplicated default rule")
+			}
+			policy.Default = rule
+		} else {
+			if _, ok := policy.Groups[group]; ok {
+				return nil, errors.Errorf("duplicated rule of resource group '%s'", group)
+			}
+			policy.Groups[group] = rule
+		}
+	}
+	return policy, nil
+}
+
+// InternalTableRetentionTables is the internal tables whose retention can be configured by
+// tidb_internal_table_retention. They are in the mysql schema.
+var InternalTableRetentionTables = []string{
+	"analyze_jobs",
+	"tidb_background_subtask_history",
+	"tidb_ttl_job_history",
+	"tidb_runaway_queries",
+	"tidb_runaway_watch_done",
+}
+
+// InternalTableRetentionCap is the caps of an internal table. A zero cap means unlimited.
+type InternalTableRetentionCap struct {
+	// MaxAge is the max age of the rows.
+	MaxAge time.Duration
+	// MaxRows is the max number of the rows.
+	MaxRows uint64
+	// MaxSize is the max size of the table in bytes, including the indexes.
+	MaxSize uint64
+}
+
+// InternalTableRetention is the parsed tidb_internal_table_retention.
+type InternalTableRetention struct {
+	// Caps is the caps of the tables, keyed by the table names.
+	Caps map[string]*InternalTableRetentionCap
+}
+
+// Cap returns the caps of the table, nil if there is none.
+func (r *InternalTableRetention) Cap(table string) *InternalTableRetentionCap {
+	if r == nil {
+		return nil
+	}
+	return r.Caps[table]
+}
+
+// ParseInternalTableRetention parses tidb_internal_table_retention. It returns nil if the retention is empty.
+func ParseInternalTableRetention(val string) (*InternalTableRetention, error) {
+	var retention *InternalTableRetention
+	for _, item := range strings.Split(val, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if retention == nil {
+			retention = &InternalTableRetention{Caps: make(map[string]*InternalTableRetentionCap)}
+		}
+		table, opts, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, errors.Errorf("missing table name in '%s'", item)
+		}
+		table = strings.ToLower(strings.TrimSpace(table))
+		if !slices.Contains(InternalTableRetentionTables, table) {
+			return nil, errors.Errorf("unsupported table '%s'", table)
+		}
+		if _, ok := retention.Caps[table]; ok {
+			return nil, errors.Errorf("duplicated rule of table '%s'", table)
+		}
+		tableCap := &InternalTableRetentionCap{}
+		for _, opt := range strings.Split(opts, ",") {
+			k, v, ok := strings.Cut(opt, "=")
+			if !ok {
+				return nil, errors.Errorf("invalid option '%s'", strings.TrimSpace(opt))
+			}
+			k, v = strings.ToLower(strings.TrimSpace(k)), strings.TrimSpace(v)
+			switch k {
+			case "age":
+				d, err := time.ParseDuration(v)
+				if err != nil {
+					return nil, err
+				}
+				if d <= 0 {
+					return nil, errors.Errorf("the age of table '%s' should be positive", table)
+				}
+				tableCap.MaxAge = d
+			case "rows":
+				rows, err := strconv.ParseUint(v, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				tableCap.MaxRows = rows
+			case "size":
+				size, normalized := parseByteSize(v)
+				if normalized == "" {
+					return nil, errors.Errorf("invalid size '%s'", v)
+				}
+				tableCap.MaxSize = size
+			default:
+				return nil, errors.Errorf("unknown option '%s'", k)
+			}
+		}
+		retention.Caps[table] = tableCap
+	}
+	return retention, nil
+}
+
+// PlacementAnalyzeWindow is the auto analyze window of the data placed on the stores with a label.
+type PlacementAnalyzeWindow struct {
+	// Key and Value are the label of the stores, e.g. region=us-east-1 or disk=hdd.
+	Key   string
+	Value string
+	// Start and End are parsed in UTC like tidb_auto_analyze_start_time and tidb_auto_analyze_end_time.
+	Start time.Time
+	End   time.Time
+}
+
+// PlacementAnalyzeWindows is the parsed tidb_auto_analyze_placement_windows.
+type PlacementAnalyzeWindows struct {
+	Windows []*PlacementAnalyzeWindow
+}
+
+// ParsePlacementAnalyzeWindows parses tidb_auto_analyze_placement_windows. It returns nil if there is no window.
+func ParsePlacementAnalyzeWindows(val string) (*PlacementAnalyzeWindows, error) {
+	var windows *PlacementAnalyzeWindows
+	for _, item := range strings.Split(val, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if windows == nil {
+			windows = &PlacementAnalyzeWindows{}
+		}
+		label, opts, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, errors.Errorf("missing store label in '%s'", item)
+		}
+		key, value, ok := strings.Cut(label, "=")
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, errors.Errorf("invalid store label '%s'", strings.TrimSpace(label))
+		}
+		for _, w := range windows.Windows {
+			if w.Key == key && w.Value == value {
+				return nil, errors.Errorf("duplicated window of store label '%s=%s'", key, value)
+			}
+		}
+		window := &PlacementAnalyzeWindow{Key: key, Value: value}
+		var hasStart, hasEnd bool
+		for _, opt := range strings.Split(opts, ",") {
+			k, v, ok := strings.Cut(opt, "=")
+			if !ok {
+				return nil, errors.Errorf("invalid option '%s'", strings.TrimSpace(opt))
+			}
+			k, v = strings.ToLower(strings.TrimSpace(k)), strings.TrimSpace(v)
+			if k != "start" && k != "end" {
+				return nil, errors.Errorf("unknown option '%s'", k)
+			}
+			t, err := time.ParseInLocation(FullDayTimeFormat, v, time.UTC)
+			if err != nil {
+				return nil, err
+			}
+			if k == "start" {
+				window.Start, hasStart = t, true
+			} else {
+				window.End, hasEnd = t, true
+			}
+		}
+		if !hasStart || !hasEnd {
+			return nil, errors.Errorf("both the start and the end of the window of store label '%s=%s' should be set", key, value)
+		}
+		windows.Windows = append(windows.Windows, window)
+	}
+	return windows, nil
+}
@@ -705,3 +705,91 @@ func TestAssertionLevel(t *testing.T) {
 	require.Equal(t, AssertionLevelFast, tidbOptAssertionLevel(AssertionFastStr))
 	require.Equal(t, AssertionLevelOff, tidbOptAssertionLevel("bogus"))
 }
+
+func TestParseLongTxnPolicy(t *testing.T) {
+	policy, err := ParseLongTxnPolicy("")
+	require.NoError(t, err)
+	require.Nil(t, policy)
+	require.Nil(t, policy.Rule("rg1"))
+
+	policy, err = ParseLongTxnPolicy("idle=5m, open=1h; RG1: open=10m,action=KILL; rg2: action=notify")
+	require.NoError(t, err)
+	require.Equal(t, &LongTxnRule{IdleTimeout: 5 * time.Minute, OpenTimeout: time.Hour, Action: LongTxnActionWarn}, policy.Rule(""))
+	require.Equal(t, &LongTxnRule{OpenTimeout: 10 * time.Minute, Action: LongTxnActionKill}, policy.Rule("rg1"))
+	require.Equal(t, &LongTxnRule{Action: LongTxnActionNotify}, policy.Rule("rg2"))
+	require.Same(t, policy.Default, policy.Rule("rg3"))
+
+	for _, val := range []string{
+		"idle=5",
+		"idle=-5m",
+		"open",
+		"timeout=5m",
+		"action=rollback",
+		": idle=5m",
+		"idle=5m; open=1h",
+		"rg1: idle=5m; RG1: open=1h",
+	} {
+		_, err = ParseLongTxnPolicy(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestParseInternalTableRetention(t *testing.T) {
+	retention, err := ParseInternalTableRetention("")
+	require.NoError(t, err)
+	require.Nil(t, retention)
+	require.Nil(t, retention.Cap("analyze_jobs"))
+
+	retention, err = ParseInternalTableRetention("Analyze_Jobs: age=72h, rows=100000; tidb_runaway_queries: size=1GiB,age=24h")
+	require.NoError(t, err)
+	require.Equal(t, &InternalTableRetentionCap{MaxAge: 72 * time.Hour, MaxRows: 100000}, retention.Cap("analyze_jobs"))
+	require.Equal(t, &InternalTableRetentionCap{MaxAge: 24 * time.Hour, MaxSize: 1 << 30}, retention.Cap("tidb_runaway_queries"))
+	require.Nil(t, retention.Cap("tidb_ttl_job_history"))
+
+	for _, val := range []string{
+		"age=72h",
+		"stats_meta: age=72h",
+		"analyze_jobs: age=72",
+		"analyze_jobs: age=-1h",
+		"analyze_jobs: rows=-1",
+		"analyze_jobs: size=1XB",
+		"analyze_jobs: count=1",
+		"analyze_jobs: rows",
+		"analyze_jobs: age=1h; ANALYZE_JOBS: rows=1",
+	} {
+		_, err = ParseInternalTableRetention(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestParsePlacementAnalyzeWindows(t *testing.T) {
+	windows, err := ParsePlacementAnalyzeWindows("")
+	require.NoError(t, err)
+	require.Nil(t, windows)
+
+	windows, err = ParsePlacementAnalyzeWindows("Region=us-east-1: start=01:00 -0500, end=05:00 -0500; disk=hdd: end=06:00 +0000,start=22:00 +0000")
+	require.NoError(t, err)
+	require.Len(t, windows.Windows, 2)
+	require.Equal(t, "region", windows.Windows[0].Key)
+	require.Equal(t, "us-east-1", windows.Windows[0].Value)
+	require.Equal(t, "06:00 +0000", windows.Windows[0].Start.UTC().Format(FullDayTimeFormat))
+	require.Equal(t, "10:00 +0000", windows.Windows[0].End.UTC().Format(FullDayTimeFormat))
+	require.Equal(t, "disk", windows.Windows[1].Key)
+	require.Equal(t, "hdd", windows.Windows[1].Value)
+	require.Equal(t, "22:00 +0000", windows.Windows[1].Start.UTC().Format(FullDayTimeFormat))
+	require.Equal(t, "06:00 +0000", windows.Windows[1].End.UTC().Format(FullDayTimeFormat))
+
+	for _, val := range []string{
+		"start=01:00 +0000, end=05:00 +0000",
+		"region: start=01:00 +0000, end=05:00 +0000",
+		"=hdd: start=01:00 +0000, end=05:00 +0000",
+		"disk=hdd: start=01:00 +0000",
+		"disk=hdd: start=01:00, end=05:00 +0000",
+		"disk=hdd: start=01:00 +0000, stop=05:00 +0000",
+		"disk=hdd: start=01:00 +0000, end",
+		"disk=hdd: start=01:00 +0000, end=05:00 +0000; DISK=hdd: start=02:00 +0000, end=03:00 +0000",
+	} {
+		_, err = ParsePlacementAnalyzeWindows(val)
+		require.Error(t, err, val)
+	}
+}
@@ -113,6 +113,10 @@ type AnalyzeResults struct {
 	// The global index has only one key range, so an independent task is used to process it.
 	// Global index needs to update only the version at the table-level fields, just like mv index.
 	ForMVIndexOrGlobalIndex bool
+	// Incremental indicates the result is merged from the existing stats and the stats of the rows appended
+	// after the last analyze. Its FMSketches are always persisted, so that the NDV can be merged by the next
+	// incremental analyze.
+	Incremental bool
 }
 
 // DestroyAndPutToPool destroys the result and put it to the pool.
@@ -41,13 +41,9 @@ const (
 	GlobalStatsMergeJob
 )
 
-const (
-	// maxDelta is the threshold of delta count. If the delta count reaches this threshold, it will be dumped into
-	// mysql.analyze_jobs.
-	maxDelta int64 = 10000000
-	// dumpTimeInterval is the time interval of dumping delta count into mysql.analyze_jobs.
-	dumpTimeInterval = 5 * time.Second
-)
+// dumpTimeInterval is the time interval of dumping delta count into mysql.analyze_jobs, so SHOW ANALYZE STATUS can
+// report the progress of the running jobs.
+const dumpTimeInterval = 5 * time.Second
 
 // AnalyzeJob is used to represent the status of one analyze job.
 type AnalyzeJob struct {
@@ -73,15 +69,15 @@ type AnalyzeProgress struct {
 	deltaCount atomic.Int64
 }
 
-// Update adds rowCount to the delta count. If the updated delta count reaches threshold, it returns the delta count for
-// dumping it into mysql.analyze_jobs and resets the delta count to 0. Otherwise, it returns 0.
+// Update adds rowCount to the delta count. If the delta count hasn't been dumped for dumpTimeInterval, it returns the
+// delta count for dumping it into mysql.analyze_jobs and resets the delta count to 0. Otherwise, it returns 0.
 func (p *AnalyzeProgress) Update(rowCount int64) int64 {
 	dumpCount := int64(0)
 	newCount := p.deltaCount.Add(rowCount)
 
 	t := time.Now()
 	p.lastDumpTimeMu.Lock()
-	if newCount > maxDelta && t.Sub(p.lastDumpTime) > dumpTimeInterval {
+	if newCount > 0 && t.Sub(p.lastDumpTime) > dumpTimeInterval {
 		dumpCount = newCount
 		p.deltaCount.Store(0)
 		p.lastDumpTime = t
@@ -0,0 +1,95 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package asyncload
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+)
+
+// PlanCacheItemsTTL is how long the stats of the columns/indices are kept loaded after the cached plans depending
+// on them are used for the last time.
+const PlanCacheItemsTTL = 30 * time.Minute
+
+// PlanCacheNeededItems stores the columns/indices whose stats are needed by the cached plans in the plan cache.
+// Their histograms are loaded again in the background after being evicted from the stats cache, so that the plans
+// rebuilt later, e.g. after the stats are updated, don't wait for the sync loading.
+var PlanCacheNeededItems = newPlanCacheItems()
+
+type planCacheItem struct {
+	lastUsed time.Time
+	fullLoad bool
+}
+
+type planCacheItems struct {
+	items map[model.TableItemID]planCacheItem
+	m     sync.Mutex
+}
+
+func newPlanCacheItems() *planCacheItems {
+	return &planCacheItems{items: make(map[model.TableItemID]planCacheItem)}
+}
+
+// Touch records that the items are needed by a cached plan used at now.
+func (p *planCacheItems) Touch(items []model.StatsLoadItem, now time.Time) {
+	if len(items) == 0 {
+		return
+	}
+	p.m.Lock()
+	defer p.m.Unlock()
+	for _, item := range items {
+		cur := p.items[item.TableItemID]
+		p.items[item.TableItemID] = planCacheItem{lastUsed: now, fullLoad: cur.fullLoad || item.FullLoad}
+	}
+}
+
+// Prefetch removes the items which are not used in ttl, and inserts the others into AsyncLoadHistogramNeededItems.
+// The items whose stats are loaded are removed from AsyncLoadHistogramNeededItems by the async loading quickly,
+// so the histograms are only loaded again after they are evicted.
+func (p *planCacheItems) Prefetch(now time.Time, ttl time.Duration) {
+	p.m.Lock()
+	items := make([]model.StatsLoadItem, 0, len(p.items))
+	for id, item := range p.items {
+		if now.Sub(item.lastUsed) > ttl {
+			delete(p.items, id)
+			continue
+		}
+		items = append(items, model.StatsLoadItem{TableItemID: id, FullLoad: item.fullLoad})
+	}
+	p.m.Unlock()
+	for _, item := range items {
+		AsyncLoadHistogramNeededItems.Insert(item.TableItemID, item.FullLoad)
+	}
+}
+
+// AllItems returns all the items.
+func (p *planCacheItems) AllItems() []model.StatsLoadItem {
+	p.m.Lock()
+	defer p.m.Unlock()
+	items := make([]model.StatsLoadItem, 0, len(p.items))
+	for id, item := range p.items {
+		items = append(items, model.StatsLoadItem{TableItemID: id, FullLoad: item.fullLoad})
+	}
+	return items
+}
+
+// Length returns the number of the items.
+func (p *planCacheItems) Length() int {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return len(p.items)
+}
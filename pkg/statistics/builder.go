@@ -256,6 +256,80 @@ func BuildColumn(ctx sessionctx.Context, numBuckets, id int64, collector *Sample
 	return BuildColumnHist(ctx, numBuckets, id, collector, tp, collector.Count, collector.FMSketch.NDV(), collector.NullCount)
 }
 
+// defaultTopNSizeV2 is the default TopN size of the analyze version 2. Other TopN sizes are regarded as specified by
+// the user.
+const defaultTopNSizeV2 = 100
+
+// AdaptiveTopNSize returns the max TopN size used by ANALYZE. If the TopN size is not specified by the user and
+// tidb_analyze_adaptive_topn_limit is positive, the TopN size is chosen adaptively for each column up to the limit,
+// so skewed columns can keep more values while uniform columns keep few of them.
+func AdaptiveTopNSize(ctx sessionctx.Context, numTopN int) int {
+	if limit := ctx.GetSessionVars().AnalyzeAdaptiveTopNLimit; numTopN == defaultTopNSizeV2 && limit > 0 {
+		return limit
+	}
+	return numTopN
+}
+
+// defaultBucketNum is the default number of the histogram buckets. Other numbers are regarded as specified by the user.
+const defaultBucketNum = 256
+
+// minSamplesPerAdaptiveBucket is the min number of the samples in each bucket when the number of the buckets is chosen
+// adaptively, so that the count of each bucket can be estimated reliably from the samples.
+const minSamplesPerAdaptiveBucket = 20
+
+// adaptiveBucketLimit returns the upper bound of the number of the histogram buckets chosen adaptively by ANALYZE, or 0
+// if tidb_analyze_adaptive_bucket_limit is 0. The number specified by the user takes precedence over the variable.
+func adaptiveBucketLimit(ctx sessionctx.Context, numBuckets int) int {
+	limit := ctx.GetSessionVars().AnalyzeAdaptiveBucketLimit
+	if limit <= 0 {
+		return 0
+	}
+	if numBuckets != defaultBucketNum {
+		return numBuckets
+	}
+	return limit
+}
+
+// adaptiveBucketNum chooses the number of the histogram buckets from the NDV and the number of the samples which are
+// not in the TopN. The columns with more values spread over wider ranges get more buckets, while the columns with
+// low NDV or few samples get fewer buckets, since the extra buckets don't make the estimation more accurate.
+func adaptiveBucketNum(ndv, sampleNum int64, limit int) int64 {
+	return max(min(ndv, sampleNum/minSamplesPerAdaptiveBucket, int64(limit)), 1)
+}
+
+// blockSampledNDV scales the NDV of the scanned regions to the whole table when only a fraction of the regions are
+// scanned. The frequent values are likely to appear in every region, while the values appearing only once in the
+// sorted samples are likely to be rare values that only exist in a few regions, so only the share of the rare values
+// is scaled by the fraction.
+func blockSampledNDV(ndv int64, samples []*SampleItem, fraction float64, getComparedBytes func(datum types.Datum) ([]byte, error)) (int64, error) {
+	var distinct, singletons int64
+	var last []byte
+	lastCnt := 0
+	for i, sample := range samples {
+		cur, err := getComparedBytes(sample.Value)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if i > 0 && bytes.Equal(last, cur) {
+			lastCnt++
+			continue
+		}
+		if lastCnt == 1 {
+			singletons++
+		}
+		distinct++
+		last, lastCnt = cur, 1
+	}
+	if lastCnt == 1 {
+		singletons++
+	}
+	if distinct == 0 {
+		return ndv, nil
+	}
+	rare := float64(ndv) * float64(singletons) / float64(distinct)
+	return int64(float64(ndv) - rare + rare/fraction), nil
+}
+
 // BuildHistAndTopN build a histogram and TopN for a column or an index from samples.
 func BuildHistAndTopN(
 	ctx sessionctx.Context,
@@ -315,6 +389,13 @@ func BuildHistAndTopN(
 	if err != nil {
 		return nil, nil, err
 	}
+	if collector.BlockSampleFraction > 0 && collector.BlockSampleFraction < 1 {
+		ndv, err = blockSampledNDV(ndv, samples, collector.BlockSampleFraction, getComparedBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		ndv = min(ndv, count)
+	}
 	hg := NewHistogram(id, ndv, nullCount, 0, tp, numBuckets, collector.TotalSize)
 
 	sampleNum := int64(len(samples))
@@ -322,9 +403,12 @@ func BuildHistAndTopN(
 	sampleFactor := float64(count) / float64(len(samples))
 	// If a numTopn value other than 100 is passed in, we assume it's a value that the user wants us to honor
 	allowPruning := true
-	if numTopN != 100 {
+	if numTopN != defaultTopNSizeV2 {
 		allowPruning = false
 	}
+	// With the adaptive TopN, the candidates are collected up to the limit, and the pruning below keeps the values
+	// until the remaining ones flatten out.
+	numTopN = AdaptiveTopNSize(ctx, numTopN)
 
 	// Step1: collect topn from samples
 
@@ -339,9 +423,7 @@ func BuildHistAndTopN(
 
 	// Iterate through the samples
 	for i := int64(0); i < sampleNum; i++ {
-		if isColumn {
-			corrXYSum += float64(i) * float64(samples[i].Ordinal)
-		}
+		corrXYSum += float64(i) * float64(samples[i].Ordinal)
 		if numTopN == 0 {
 			continue
 		}
@@ -387,10 +469,9 @@ func BuildHistAndTopN(
 		cur, curCnt = sampleBytes, 1
 	}
 
-	// Calc the correlation of the column between the handle column.
-	if isColumn {
-		hg.Correlation = calcCorrelation(sampleNum, corrXYSum)
-	}
+	// Calc the correlation of the column or the index between the handle column. The ordinals of the samples are their
+	// positions in the handle order, so the correlation tells how scattered the rows of an index range are in the table.
+	hg.Correlation = calcCorrelation(sampleNum, corrXYSum)
 
 	// Handle the counting for the last value. Basically equal to the case 2 above - including
 	// limiting addition of a value with a count of 1 (since it will be pruned anyway).
@@ -478,6 +559,9 @@ func BuildHistAndTopN(
 
 	// Step3: build histogram with the rest samples
 	if len(samples) > 0 {
+		if limit := adaptiveBucketLimit(ctx, numBuckets); limit > 0 {
+			numBuckets = int(adaptiveBucketNum(ndv-int64(len(topn.TopN)), int64(len(samples)), limit))
+		}
 		_, err = buildHist(sc, hg, samples, count-int64(topn.TotalCount()), ndv-int64(len(topn.TopN)), int64(numBuckets), memTracker)
 		if err != nil {
 			return nil, nil, err
@@ -23,13 +23,46 @@ import (
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"go.uber.org/zap"
 )
 
+// ExtStatsColGroup is the column group of a cardinality extended stats, whose joint NDV is collected by
+// a composite FMSketch during ANALYZE.
+type ExtStatsColGroup struct {
+	// Key is the column_ids of the record in mysql.stats_extended.
+	Key    string
+	ColIDs []int64
+}
+
+// CollectCardinalityColGroups returns the column groups of the cardinality extended stats of the table.
+func CollectCardinalityColGroups(sctx sessionctx.Context, tableID int64) ([]ExtStatsColGroup, error) {
+	const sql = "SELECT DISTINCT column_ids FROM mysql.stats_extended WHERE table_id = %? and type = %? and status in (%?, %?)"
+
+	sqlExec := sctx.GetRestrictedSQLExecutor()
+	rows, _, err := sqlExec.ExecRestrictedSQL(kv.WithInternalSourceType(context.Background(), kv.InternalTxnStats), nil, sql,
+		tableID, ast.StatsTypeCardinality, ExtendedStatsAnalyzed, ExtendedStatsInited)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	groups := make([]ExtStatsColGroup, 0, len(rows))
+	for _, row := range rows {
+		group := ExtStatsColGroup{Key: row.GetString(0)}
+		if err := json.Unmarshal([]byte(group.Key), &group.ColIDs); err != nil {
+			logutil.BgLogger().Error("invalid column_ids in mysql.stats_extended, skip collecting extended stats for this row", zap.String("column_ids", group.Key), zap.Error(err))
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
 // BuildExtendedStats build extended stats for column groups if needed based on the column samples.
+// colGroupNDVs is the joint NDV of the cardinality extended stats collected by composite FMSketches,
+// whose key is the ExtStatsColGroup.Key. The cardinality extended stats are skipped if it's nil.
 func BuildExtendedStats(sctx sessionctx.Context,
-	tableID int64, cols []*model.ColumnInfo, collectors []*SampleCollector) (*ExtendedStatsColl, error) {
+	tableID int64, cols []*model.ColumnInfo, collectors []*SampleCollector, colGroupNDVs map[string]int64) (*ExtendedStatsColl, error) {
 	const sql = "SELECT name, type, column_ids FROM mysql.stats_extended WHERE table_id = %? and status in (%?, %?)"
 
 	sqlExec := sctx.GetRestrictedSQLExecutor()
@@ -50,7 +83,11 @@ func BuildExtendedStats(sctx sessionctx.Context,
 			logutil.BgLogger().Error("invalid column_ids in mysql.stats_extended, skip collecting extended stats for this row", zap.String("column_ids", colIDs), zap.Error(err))
 			continue
 		}
-		item = fillExtendedStatsItemVals(sctx, item, cols, collectors)
+		if item.Tp == ast.StatsTypeCardinality {
+			item = fillExtStatsCardinalityVals(item, colIDs, colGroupNDVs)
+		} else {
+			item = fillExtendedStatsItemVals(sctx, item, cols, collectors)
+		}
 		if item != nil {
 			statsColl.Stats[name] = item
 		}
@@ -63,14 +100,86 @@ func BuildExtendedStats(sctx sessionctx.Context,
 
 func fillExtendedStatsItemVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*SampleCollector) *ExtendedStatsItem {
 	switch item.Tp {
-	case ast.StatsTypeCardinality, ast.StatsTypeDependency:
-		return nil
+	case ast.StatsTypeDependency:
+		return fillExtStatsDepVals(sctx, item, cols, collectors)
 	case ast.StatsTypeCorrelation:
 		return fillExtStatsCorrVals(sctx, item, cols, collectors)
+	case ast.StatsTypeHistogram:
+		return fillExtStatsHistVals(sctx, item, cols, collectors)
 	}
 	return nil
 }
 
+func fillExtStatsCardinalityVals(item *ExtendedStatsItem, key string, colGroupNDVs map[string]int64) *ExtendedStatsItem {
+	ndv, ok := colGroupNDVs[key]
+	if !ok {
+		return nil
+	}
+	item.ScalarVals = float64(ndv)
+	return item
+}
+
+// fillExtStatsDepVals computes the degree of the functional dependency from the first column to the second one,
+// i.e, the fraction of the sampled rows whose value of the second column is the most frequent one among the rows
+// sharing the same value of the first column. A degree of 1 means the first column exactly determines the second one.
+func fillExtStatsDepVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*SampleCollector) *ExtendedStatsItem {
+	colOffsets := make([]int, 0, 2)
+	for _, id := range item.ColIDs {
+		for i, col := range cols {
+			if col.ID == id {
+				colOffsets = append(colOffsets, i)
+				break
+			}
+		}
+	}
+	if len(colOffsets) != 2 {
+		return nil
+	}
+	tz := sctx.GetSessionVars().StmtCtx.TimeZone()
+	// The NULL values are not sampled, so the samples of the two columns are joined by their ordinals.
+	samplesY := make(map[int]string, len(collectors[colOffsets[1]].Samples))
+	for _, sample := range collectors[colOffsets[1]].Samples {
+		key, err := codec.EncodeKey(tz, nil, sample.Value)
+		if err != nil {
+			return nil
+		}
+		samplesY[sample.Ordinal] = string(key)
+	}
+	groups := make(map[string]map[string]int)
+	total := 0
+	for _, sample := range collectors[colOffsets[0]].Samples {
+		valY, ok := samplesY[sample.Ordinal]
+		if !ok {
+			continue
+		}
+		key, err := codec.EncodeKey(tz, nil, sample.Value)
+		if err != nil {
+			return nil
+		}
+		group, ok := groups[string(key)]
+		if !ok {
+			group = make(map[string]int)
+			groups[string(key)] = group
+		}
+		group[valY]++
+		total++
+	}
+	if total == 0 {
+		item.ScalarVals = 0
+		return item
+	}
+	dependent := 0
+	for _, group := range groups {
+		maxCnt := 0
+		for _, cnt := range group {
+			maxCnt = max(maxCnt, cnt)
+		}
+		dependent += maxCnt
+	}
+	item.ScalarVals = float64(dependent) / float64(total)
+	return item
+}
+
 func fillExtStatsCorrVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*SampleCollector) *ExtendedStatsItem {
 	colOffsets := make([]int, 0, 2)
 	for _, id := range item.ColIDs {
@@ -0,0 +1,125 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// maxFeedbackTables is the max number of the tables whose feedback is kept in memory.
+	// The feedback of the new tables is dropped when the limit is reached.
+	maxFeedbackTables = 10000
+	// maxFeedbackQError bounds the q-error of a single estimation, so that a few extremely bad estimations
+	// don't dominate the feedback of a table.
+	maxFeedbackQError = 1e4
+	// qErrorBuckets is the number of the buckets of the q-errors, where the i-th bucket counts the q-errors in
+	// [2^i, 2^(i+1)), and the last one also counts the q-errors up to maxFeedbackQError.
+	qErrorBuckets = 14
+)
+
+// TableFeedback is the aggregated feedback of the row count estimations on a table.
+// Unlike the feedback of the stats v1, it doesn't correct the stats, but only tells how inaccurate the stats are,
+// which is used to adjust the priority of the table in the auto analyze queue.
+type TableFeedback struct {
+	// Since is the time of the first feedback.
+	Since time.Time
+	// Count is the number of the estimations.
+	Count int64
+	// SumLog10QError is the sum of the log10 of the q-errors, where the q-error is max(est/act, act/est).
+	SumLog10QError float64
+	// QErrorBuckets is the histogram of the q-errors in log2 scale, see qErrorBuckets.
+	QErrorBuckets [qErrorBuckets]int64
+}
+
+// AvgQError returns the geometric mean of the q-errors, which is 1 if there is no feedback.
+func (f TableFeedback) AvgQError() float64 {
+	if f.Count == 0 {
+		return 1
+	}
+	return math.Pow(10, f.SumLog10QError/float64(f.Count))
+}
+
+// FractionWithinQError returns the fraction of the estimations whose q-errors are not larger than the target, which
+// is 1 if there is no feedback. The q-errors are assumed to be uniformly distributed in log scale within a bucket.
+func (f TableFeedback) FractionWithinQError(target float64) float64 {
+	if f.Count == 0 {
+		return 1
+	}
+	bound := math.Log2(max(target, 1))
+	var within float64
+	for i, cnt := range f.QErrorBuckets {
+		lower := float64(i)
+		if bound >= lower+1 {
+			within += float64(cnt)
+		} else if bound > lower {
+			within += float64(cnt) * (bound - lower)
+		}
+	}
+	return within / float64(f.Count)
+}
+
+type cardinalityFeedback struct {
+	tables map[int64]*TableFeedback
+	mu     sync.Mutex
+}
+
+// CardinalityFeedback collects the estimated and actual row counts of the table scans and index scans of the
+// executed statements.
+var CardinalityFeedback = &cardinalityFeedback{tables: make(map[int64]*TableFeedback)}
+
+// Record records an estimation on the table. Both the estimated and actual row counts are regarded as at least 1,
+// so the estimations of the empty ranges don't make the q-error infinite.
+func (c *cardinalityFeedback) Record(physicalID int64, estRows float64, actRows int64, now time.Time) {
+	est, act := max(estRows, 1), max(float64(actRows), 1)
+	qError := min(max(est/act, act/est), maxFeedbackQError)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.tables[physicalID]
+	if !ok {
+		if len(c.tables) >= maxFeedbackTables {
+			return
+		}
+		f = &TableFeedback{Since: now}
+		c.tables[physicalID] = f
+	}
+	f.Count++
+	f.SumLog10QError += math.Log10(qError)
+	f.QErrorBuckets[min(int(math.Log2(qError)), qErrorBuckets-1)]++
+}
+
+// Get returns the feedback of the table. The feedback recorded before the last analysis is stale, so it's dropped.
+func (c *cardinalityFeedback) Get(physicalID int64, lastAnalyzed time.Time) TableFeedback {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, ok := c.tables[physicalID]
+	if !ok {
+		return TableFeedback{}
+	}
+	if f.Since.Before(lastAnalyzed) {
+		delete(c.tables, physicalID)
+		return TableFeedback{}
+	}
+	return *f
+}
+
+// Reset drops all the feedback.
+func (c *cardinalityFeedback) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tables = make(map[int64]*TableFeedback)
+}
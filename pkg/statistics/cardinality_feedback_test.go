@@ -0,0 +1,74 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalityFeedback(t *testing.T) {
+	CardinalityFeedback.Reset()
+	defer CardinalityFeedback.Reset()
+	now := time.Now()
+	require.Equal(t, 1.0, CardinalityFeedback.Get(1, time.Time{}).AvgQError())
+
+	// The q-errors are 10 and 1000, so the geometric mean is 100.
+	CardinalityFeedback.Record(1, 10, 100, now)
+	CardinalityFeedback.Record(1, 1000, 1, now)
+	f := CardinalityFeedback.Get(1, time.Time{})
+	require.Equal(t, int64(2), f.Count)
+	require.InDelta(t, 100, f.AvgQError(), 1e-9)
+
+	// The empty ranges and the extremely bad estimations are bounded.
+	CardinalityFeedback.Record(2, 0, 0, now)
+	require.InDelta(t, 1, CardinalityFeedback.Get(2, time.Time{}).AvgQError(), 1e-9)
+	CardinalityFeedback.Record(3, 1, 1e9, now)
+	require.InDelta(t, maxFeedbackQError, CardinalityFeedback.Get(3, time.Time{}).AvgQError(), 1e-6)
+
+	// The feedback before the last analysis is dropped.
+	require.Equal(t, int64(0), CardinalityFeedback.Get(1, now.Add(time.Second)).Count)
+	require.Equal(t, int64(0), CardinalityFeedback.Get(1, time.Time{}).Count)
+}
+
+func TestFeedbackFractionWithinQError(t *testing.T) {
+	CardinalityFeedback.Reset()
+	defer CardinalityFeedback.Reset()
+	now := time.Now()
+	require.Equal(t, 1.0, CardinalityFeedback.Get(1, time.Time{}).FractionWithinQError(2))
+
+	// 8 accurate estimations, an estimation with the q-error 3 and another with the q-error 1000.
+	for range 8 {
+		CardinalityFeedback.Record(1, 100, 100, now)
+	}
+	CardinalityFeedback.Record(1, 300, 100, now)
+	CardinalityFeedback.Record(1, 1, 1000, now)
+	f := CardinalityFeedback.Get(1, time.Time{})
+	require.Equal(t, int64(8), f.QErrorBuckets[0])
+	require.Equal(t, int64(1), f.QErrorBuckets[1])
+	require.Equal(t, int64(1), f.QErrorBuckets[9])
+	require.InDelta(t, 0.8, f.FractionWithinQError(2), 1e-9)
+	require.InDelta(t, 0.9, f.FractionWithinQError(4), 1e-9)
+	// The q-errors are interpolated in log scale within a bucket.
+	require.InDelta(t, 0.85, f.FractionWithinQError(math.Sqrt2*2), 1e-9)
+	require.InDelta(t, 1, f.FractionWithinQError(maxFeedbackQError), 1e-9)
+
+	// The extremely bad estimations are in the last bucket.
+	CardinalityFeedback.Record(2, 1, 1e9, now)
+	require.Equal(t, int64(1), CardinalityFeedback.Get(2, time.Time{}).QErrorBuckets[qErrorBuckets-1])
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+// IsLosslessColumnTypeChange returns whether all the values of a column can be converted from oldTp to newTp without
+// changing their equality and order, e.g. INT to BIGINT or VARCHAR(10) to VARCHAR(20). The stats of such a column
+// are still valid after the type change once their values are converted to the new type.
+func IsLosslessColumnTypeChange(oldTp, newTp *types.FieldType) bool {
+	if oldTp.GetCharset() != newTp.GetCharset() || oldTp.GetCollate() != newTp.GetCollate() {
+		return false
+	}
+	oldUnsigned, newUnsigned := mysql.HasUnsignedFlag(oldTp.GetFlag()), mysql.HasUnsignedFlag(newTp.GetFlag())
+	switch oldTp.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		oldDigits := integerDigits(oldTp.GetType(), oldUnsigned)
+		switch newTp.GetType() {
+		case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+			return oldUnsigned == newUnsigned && integerDigits(newTp.GetType(), newUnsigned) >= oldDigits
+		case mysql.TypeNewDecimal:
+			// The negative values can't be kept in an unsigned decimal.
+			return (oldUnsigned || !newUnsigned) && newTp.GetFlen()-newTp.GetDecimal() >= oldDigits
+		}
+	case mysql.TypeFloat:
+		return newTp.GetType() == mysql.TypeDouble && oldUnsigned == newUnsigned &&
+			newTp.GetDecimal() == types.UnspecifiedLength
+	case mysql.TypeNewDecimal:
+		return newTp.GetType() == mysql.TypeNewDecimal && oldUnsigned == newUnsigned &&
+			newTp.GetDecimal() >= oldTp.GetDecimal() &&
+			newTp.GetFlen()-newTp.GetDecimal() >= oldTp.GetFlen()-oldTp.GetDecimal()
+	case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeBlob, mysql.TypeLongBlob:
+		// The trailing spaces are removed when the values are converted to CHAR.
+		if newTp.GetType() == mysql.TypeString && oldTp.GetType() != mysql.TypeString {
+			return false
+		}
+		return types.IsString(newTp.GetType()) && newTp.GetFlen() >= oldTp.GetFlen()
+	case mysql.TypeDate:
+		return newTp.GetType() == mysql.TypeDate || newTp.GetType() == mysql.TypeDatetime
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+		return newTp.GetType() == oldTp.GetType() && newTp.GetDecimal() >= oldTp.GetDecimal()
+	}
+	return false
+}
+
+// integerDigits returns the max number of the digits of the integer type.
+func integerDigits(tp byte, unsigned bool) int {
+	switch tp {
+	case mysql.TypeTiny:
+		return 3
+	case mysql.TypeShort:
+		return 5
+	case mysql.TypeInt24:
+		if unsigned {
+			return 8
+		}
+		return 7
+	case mysql.TypeLong:
+		return 10
+	case mysql.TypeLonglong:
+		if unsigned {
+			return 20
+		}
+		return 19
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsLosslessColumnTypeChange(t *testing.T) {
+	newTp := func(tp byte, flen, decimal int, unsigned bool) *types.FieldType {
+		b := types.NewFieldTypeBuilder().SetType(tp).SetFlen(flen).SetDecimal(decimal).SetCharset(mysql.DefaultCharset).SetCollate(mysql.DefaultCollationName)
+		if !types.IsString(tp) {
+			b.SetCharset(charset.CharsetBin).SetCollate(charset.CollationBin)
+		}
+		if unsigned {
+			b.AddFlag(mysql.UnsignedFlag)
+		}
+		return b.BuildP()
+	}
+	tests := []struct {
+		oldTp, newTp *types.FieldType
+		lossless     bool
+	}{
+		{newTp(mysql.TypeLong, 11, 0, false), newTp(mysql.TypeLonglong, 20, 0, false), true},
+		{newTp(mysql.TypeLonglong, 20, 0, false), newTp(mysql.TypeLong, 11, 0, false), false},
+		{newTp(mysql.TypeLong, 11, 0, false), newTp(mysql.TypeLonglong, 20, 0, true), false},
+		{newTp(mysql.TypeLong, 11, 0, false), newTp(mysql.TypeNewDecimal, 12, 2, false), true},
+		{newTp(mysql.TypeLong, 11, 0, false), newTp(mysql.TypeNewDecimal, 11, 2, false), false},
+		{newTp(mysql.TypeLong, 10, 0, true), newTp(mysql.TypeNewDecimal, 10, 0, true), true},
+		{newTp(mysql.TypeLong, 11, 0, false), newTp(mysql.TypeNewDecimal, 12, 0, true), false},
+		{newTp(mysql.TypeFloat, 12, types.UnspecifiedLength, false), newTp(mysql.TypeDouble, 22, types.UnspecifiedLength, false), true},
+		{newTp(mysql.TypeDouble, 22, types.UnspecifiedLength, false), newTp(mysql.TypeFloat, 12, types.UnspecifiedLength, false), false},
+		{newTp(mysql.TypeNewDecimal, 10, 2, false), newTp(mysql.TypeNewDecimal, 12, 3, false), true},
+		{newTp(mysql.TypeNewDecimal, 10, 2, false), newTp(mysql.TypeNewDecimal, 12, 1, false), false},
+		{newTp(mysql.TypeVarchar, 10, 0, false), newTp(mysql.TypeVarchar, 20, 0, false), true},
+		{newTp(mysql.TypeVarchar, 20, 0, false), newTp(mysql.TypeVarchar, 10, 0, false), false},
+		{newTp(mysql.TypeString, 10, 0, false), newTp(mysql.TypeVarchar, 10, 0, false), true},
+		{newTp(mysql.TypeVarchar, 10, 0, false), newTp(mysql.TypeString, 10, 0, false), false},
+		{newTp(mysql.TypeVarchar, 10, 0, false), newTp(mysql.TypeLong, 11, 0, false), false},
+		{newTp(mysql.TypeDate, 10, 0, false), newTp(mysql.TypeDatetime, 19, 0, false), true},
+		{newTp(mysql.TypeDatetime, 19, 0, false), newTp(mysql.TypeDatetime, 23, 3, false), true},
+		{newTp(mysql.TypeDatetime, 23, 3, false), newTp(mysql.TypeDatetime, 19, 0, false), false},
+		{newTp(mysql.TypeDatetime, 19, 0, false), newTp(mysql.TypeTimestamp, 19, 0, false), false},
+	}
+	for i, tt := range tests {
+		require.Equal(t, tt.lossless, IsLosslessColumnTypeChange(tt.oldTp, tt.newTp), "case %d", i)
+	}
+	// The collation keys of the strings are changed with the collation.
+	oldTp := newTp(mysql.TypeVarchar, 10, 0, false)
+	newCollationTp := newTp(mysql.TypeVarchar, 20, 0, false)
+	newCollationTp.SetCollate("utf8mb4_general_ci")
+	require.False(t, IsLosslessColumnTypeChange(oldTp, newCollationTp))
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+// ForeignKeyStats is the statistics of a foreign key. It's collected from the samples of ANALYZE on the child table
+// and kept in mysql.stats_extended as the type StatsTypeForeignKey, whose column IDs are the foreign key columns.
+// The foreign key is recorded even if it's not enforced, since it still tells that the child rows reference the parent
+// rows, which is used to estimate the joins between them.
+type ForeignKeyStats struct {
+	// RefTableID is the ID of the referenced table.
+	RefTableID int64 `json:"ref_table_id"`
+	// RefColIDs are the IDs of the referenced columns, in the same order as the foreign key columns.
+	RefColIDs []int64 `json:"ref_col_ids"`
+	// MatchRatio is the fraction of the sampled not NULL foreign key values which are found in the referenced table.
+	// It's 1 if the foreign key is not validated.
+	MatchRatio float64 `json:"match_ratio"`
+	// Validated indicates whether MatchRatio is validated by looking up the sampled values in the referenced table.
+	Validated bool `json:"validated"`
+}
+
+// ForeignKeyStatsName returns the name of the foreign key stats in mysql.stats_extended.
+func ForeignKeyStatsName(fkID int64) string {
+	return fmt.Sprintf("__fk_%d", fkID)
+}
+
+// DecodeForeignKeyStats decodes the foreign key stats from the stats of mysql.stats_extended.
+func DecodeForeignKeyStats(str string) (*ForeignKeyStats, error) {
+	s := &ForeignKeyStats{}
+	if err := json.Unmarshal([]byte(str), s); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s, nil
+}
+
+// NewForeignKeyStatsItem encodes the foreign key stats into an extended stats item.
+func NewForeignKeyStatsItem(colIDs []int64, s *ForeignKeyStats) (*ExtendedStatsItem, error) {
+	bytes, err := json.Marshal(s)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ExtendedStatsItem{
+		Tp:         ast.StatsTypeForeignKey,
+		ColIDs:     colIDs,
+		StringVals: string(bytes),
+		ForeignKey: s,
+	}, nil
+}
+
+// CollectForeignKeyValues collects the distinct values of the foreign key columns from the sampled rows, where
+// colOffsets are the offsets of the foreign key columns in the sampled rows. The values containing NULL are skipped,
+// since they don't reference any row. At most maxValues values are returned.
+func CollectForeignKeyValues(colOffsets []int, samples []*ReservoirRowSampleItem, maxValues int) [][]types.Datum {
+	var ret [][]types.Datum
+	seen := make(map[string]struct{}, min(len(samples), maxValues))
+	var key strings.Builder
+nextSample:
+	for _, sample := range samples {
+		if len(ret) >= maxValues {
+			break
+		}
+		values := make([]types.Datum, 0, len(colOffsets))
+		key.Reset()
+		for _, offset := range colOffsets {
+			d := sample.Columns[offset]
+			if d.IsNull() {
+				continue nextSample
+			}
+			// The values are looked up by their strings, so the values which can't be converted are skipped.
+			str, err := d.ToString()
+			if err != nil {
+				continue nextSample
+			}
+			key.WriteString(str)
+			key.WriteByte(0)
+			values = append(values, d)
+		}
+		if _, ok := seen[key.String()]; ok {
+			continue
+		}
+		seen[key.String()] = struct{}{}
+		ret = append(ret, values)
+	}
+	return ret
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForeignKeyStats(t *testing.T) {
+	samples := make([]*ReservoirRowSampleItem, 0, 10)
+	for i := 0; i < 10; i++ {
+		// The foreign key is on the first two columns, and the third column is not a part of it.
+		cols := types.MakeDatums(i%3, "x", i)
+		if i%4 == 0 {
+			cols[1].SetNull()
+		}
+		samples = append(samples, &ReservoirRowSampleItem{Columns: cols})
+	}
+	// The rows 0, 4 and 8 contain NULL, and the rest have 3 distinct values.
+	values := CollectForeignKeyValues([]int{0, 1}, samples, 10)
+	require.Len(t, values, 3)
+	require.Len(t, CollectForeignKeyValues([]int{0, 1}, samples, 2), 2)
+
+	item, err := NewForeignKeyStatsItem([]int64{1, 2}, &ForeignKeyStats{RefTableID: 100, RefColIDs: []int64{3, 4}, MatchRatio: 0.5, Validated: true})
+	require.NoError(t, err)
+	require.Equal(t, `{"ref_table_id":100,"ref_col_ids":[3,4],"match_ratio":0.5,"validated":true}`, item.StringVals)
+	fk, err := DecodeForeignKeyStats(item.StringVals)
+	require.NoError(t, err)
+	require.Equal(t, item.ForeignKey, fk)
+	require.Equal(t, "__fk_7", ForeignKeyStatsName(7))
+}
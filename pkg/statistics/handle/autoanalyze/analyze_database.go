@@ -0,0 +1,167 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoanalyze
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// AnalyzeDatabaseStatusFinished means the table is analyzed successfully.
+	AnalyzeDatabaseStatusFinished = "finished"
+	// AnalyzeDatabaseStatusFailed means the table failed to be analyzed.
+	AnalyzeDatabaseStatusFailed = "failed"
+	// AnalyzeDatabaseStatusSkipped means the table is not analyzed.
+	AnalyzeDatabaseStatusSkipped = "skipped"
+
+	budgetExhausted = "budget exhausted"
+	analyzeFailed   = "analyze failed, see SHOW ANALYZE STATUS for the reason"
+)
+
+// AnalyzeDatabase implements the StatsAnalyze interface.
+func (sa *statsAnalyze) AnalyzeDatabase(
+	schema string,
+	concurrency int,
+	budget time.Duration,
+) ([]statstypes.AnalyzeDatabaseTableResult, error) {
+	var (
+		jobs []priorityqueue.AnalysisJob
+		is   infoschema.InfoSchema
+	)
+	err := statsutil.CallWithSCtx(sa.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		is = sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
+		var err error
+		jobs, err = priorityqueue.CreateAnalysisJobsForSchema(sctx, sa.statsHandle, pmodel.NewCIStr(schema))
+		return err
+	}, statsutil.FlagWrapTxn)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if concurrency <= 0 {
+		concurrency = int(variable.AutoAnalyzeConcurrency.Load())
+	}
+	var deadline time.Time
+	if budget > 0 {
+		deadline = time.Now().Add(budget)
+	}
+	statslogutil.StatsLogger().Info("Analyze database started",
+		zap.String("schema", schema),
+		zap.Int("jobs", len(jobs)),
+		zap.Int("concurrency", concurrency),
+		zap.Duration("budget", budget),
+	)
+
+	results := make([]statstypes.AnalyzeDatabaseTableResult, len(jobs))
+	jobCh := make(chan int, len(jobs))
+	for i, job := range jobs {
+		results[i].TableName, results[i].PartitionName = getJobTableAndPartitionName(is, job)
+		jobCh <- i
+	}
+	close(jobCh)
+
+	var wg util.WaitGroupWrapper
+	for range min(concurrency, len(jobs)) {
+		wg.Run(func() {
+			for i := range jobCh {
+				sa.runAnalyzeDatabaseJob(jobs[i], deadline, &results[i])
+			}
+		})
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// runAnalyzeDatabaseJob runs one job of the ANALYZE DATABASE statement and records the result.
+func (sa *statsAnalyze) runAnalyzeDatabaseJob(
+	job priorityqueue.AnalysisJob,
+	deadline time.Time,
+	result *statstypes.AnalyzeDatabaseTableResult,
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			statslogutil.StatsLogger().Error("Analyze database job panicked",
+				zap.Stringer("job", job), zap.Any("recover", r), zap.Stack("stack"))
+			result.Status = AnalyzeDatabaseStatusFailed
+			result.Message = fmt.Sprintf("%v", r)
+		}
+	}()
+	if !deadline.IsZero() && time.Now().After(deadline) {
+		result.Status = AnalyzeDatabaseStatusSkipped
+		result.Message = budgetExhausted
+		return
+	}
+
+	var (
+		valid      bool
+		failReason string
+	)
+	if err := statsutil.CallWithSCtx(sa.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		valid, failReason = job.ValidateAndPrepare(sctx)
+		return nil
+	}); err != nil {
+		result.Status = AnalyzeDatabaseStatusFailed
+		result.Message = err.Error()
+		return
+	}
+	if !valid {
+		result.Status = AnalyzeDatabaseStatusSkipped
+		result.Message = failReason
+		return
+	}
+
+	success := true
+	job.RegisterFailureHook(func(priorityqueue.AnalysisJob, bool) {
+		success = false
+	})
+	start := time.Now()
+	err := job.Analyze(sa.statsHandle, sa.sysProcTracker)
+	result.Duration = time.Since(start)
+	switch {
+	case err != nil:
+		result.Status = AnalyzeDatabaseStatusFailed
+		result.Message = err.Error()
+	case !success:
+		result.Status = AnalyzeDatabaseStatusFailed
+		result.Message = analyzeFailed
+	default:
+		result.Status = AnalyzeDatabaseStatusFinished
+	}
+}
+
+// getJobTableAndPartitionName gets the table name and the partition name of the job.
+// The partition name is only returned for the static partition job.
+func getJobTableAndPartitionName(is infoschema.InfoSchema, job priorityqueue.AnalysisJob) (string, string) {
+	if tblInfo, ok := is.TableInfoByID(job.GetTableID()); ok {
+		return tblInfo.Name.O, ""
+	}
+	if tblInfo, _, def := is.FindTableInfoByPartitionID(job.GetTableID()); tblInfo != nil {
+		return tblInfo.Name.O, def.Name.O
+	}
+	return "", ""
+}
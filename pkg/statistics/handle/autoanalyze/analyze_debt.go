@@ -0,0 +1,51 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoanalyze
+
+import (
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"go.uber.org/zap"
+)
+
+// GetScope implements the variable.Statistics interface.
+func (*statsAnalyze) GetScope(_ string) variable.ScopeFlag {
+	return variable.DefaultStatusVarScopeFlag
+}
+
+// Stats implements the variable.Statistics interface. It returns the analyze debt and the ETA to catch it up, which
+// are estimated by the stats owner from its priority queue and persisted in mysql.tidb, see
+// AnalysisPriorityQueue.RefreshAnalyzeDebt. They're absent until the stats owner estimates them.
+func (sa *statsAnalyze) Stats(_ *variable.SessionVars) (map[string]any, error) {
+	m := make(map[string]any, len(priorityqueue.AnalyzeDebtKeys))
+	if err := statsutil.CallWithSCtx(sa.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		rows, _, err := statsutil.ExecRows(sctx, "SELECT variable_name, variable_value FROM mysql.tidb WHERE variable_name IN (%?)",
+			priorityqueue.AnalyzeDebtKeys)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			m[row.GetString(0)] = row.GetString(1)
+		}
+		return nil
+	}); err != nil {
+		// The other status variables are still shown.
+		statslogutil.StatsLogger().Warn("Failed to load the analyze debt", zap.Error(err))
+	}
+	return m, nil
+}
@@ -36,13 +36,13 @@ import (
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/exec"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
 	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/refresher"
 	"github.com/pingcap/tidb/pkg/statistics/handle/lockstats"
 	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
 	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/types"
-	"github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/intest"
 	"github.com/pingcap/tidb/pkg/util/logutil"
 	"github.com/pingcap/tidb/pkg/util/sqlescape"
@@ -70,11 +70,14 @@ func NewStatsAnalyze(
 	// Usually, we should only create the refresher when auto-analyze priority queue is enabled.
 	// But to allow users to enable auto-analyze priority queue on the fly, we need to create the refresher here.
 	r := refresher.NewRefresher(statsHandle, sysProcTracker, ddlNotifier)
-	return &statsAnalyze{
+	sa := &statsAnalyze{
 		statsHandle:    statsHandle,
 		sysProcTracker: sysProcTracker,
 		refresher:      r,
 	}
+	// Show the analyze debt by the status variables.
+	variable.RegisterStatistics(sa)
+	return sa
 }
 
 // InsertAnalyzeJob inserts the analyze job to the storage.
@@ -292,7 +295,7 @@ func CleanupCorruptedAnalyzeJobsOnDeadInstances(
 // HandleAutoAnalyze analyzes the outdated tables. (The change percent of the table exceeds the threshold)
 // It also analyzes newly created tables and newly added indexes.
 func (sa *statsAnalyze) HandleAutoAnalyze() (analyzed bool) {
-	if err := statsutil.CallWithSCtx(sa.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+	if err := statsutil.CallWithSCtx(sa.statsHandle.PrioritizedSPool(statsutil.SessionPriorityLow), func(sctx sessionctx.Context) error {
 		analyzed = sa.handleAutoAnalyze(sctx)
 		return nil
 	}); err != nil {
@@ -322,6 +325,99 @@ func (sa *statsAnalyze) GetPriorityQueueSnapshot() (statstypes.PriorityQueueSnap
 	return sa.refresher.GetPriorityQueueSnapshot()
 }
 
+// GetRunningAnalysisJobs returns the auto analyze jobs being run by the current instance.
+func (sa *statsAnalyze) GetRunningAnalysisJobs() []statstypes.RunningAnalysisJobJSON {
+	return sa.refresher.GetRunningJobsSnapshot()
+}
+
+// GetAutoAnalyzeJobOutcomes returns the latest auto analyze jobs recorded in mysql.analyze_jobs.
+func (sa *statsAnalyze) GetAutoAnalyzeJobOutcomes(schema, table string, limit int) ([]statstypes.AutoAnalyzeJobOutcome, error) {
+	var outcomes []statstypes.AutoAnalyzeJobOutcome
+	err := statsutil.CallWithSCtx(sa.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		var err error
+		outcomes, err = getAutoAnalyzeJobOutcomes(sctx, schema, table, limit)
+		return err
+	})
+	return outcomes, err
+}
+
+func getAutoAnalyzeJobOutcomes(sctx sessionctx.Context, schema, table string, limit int) ([]statstypes.AutoAnalyzeJobOutcome, error) {
+	// The auto analyze jobs are told apart by the prefix of their job info, see the analyze executor.
+	sql := new(strings.Builder)
+	sql.WriteString(`SELECT id, table_schema, table_name, partition_name, job_info, processed_rows,
+		CONVERT_TZ(start_time, @@TIME_ZONE, '+00:00'), CONVERT_TZ(end_time, @@TIME_ZONE, '+00:00'),
+		state, fail_reason, instance
+		FROM mysql.analyze_jobs WHERE job_info LIKE %?`)
+	args := []any{"auto analyze%"}
+	if schema != "" {
+		sql.WriteString(" AND table_schema = %?")
+		args = append(args, schema)
+	}
+	if table != "" {
+		sql.WriteString(" AND table_name = %?")
+		args = append(args, table)
+	}
+	sql.WriteString(" ORDER BY id DESC LIMIT %?")
+	args = append(args, limit)
+	rows, _, err := statsutil.ExecRows(sctx, sql.String(), args...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	outcomes := make([]statstypes.AutoAnalyzeJobOutcome, 0, len(rows))
+	for _, row := range rows {
+		outcome := statstypes.AutoAnalyzeJobOutcome{
+			ID:            row.GetUint64(0),
+			Schema:        row.GetString(1),
+			Table:         row.GetString(2),
+			PartitionName: row.GetString(3),
+			JobInfo:       row.GetString(4),
+			ProcessedRows: row.GetUint64(5),
+			State:         row.GetEnum(8).String(),
+			Instance:      row.GetString(10),
+		}
+		if !row.IsNull(6) {
+			if t, err := row.GetTime(6).GoTime(time.UTC); err == nil {
+				outcome.StartTime = &t
+			}
+		}
+		if !row.IsNull(7) {
+			if t, err := row.GetTime(7).GoTime(time.UTC); err == nil {
+				outcome.EndTime = &t
+			}
+		}
+		if !row.IsNull(9) {
+			outcome.FailReason = row.GetString(9)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes, nil
+}
+
+// GetTableIndicators returns the indicators of the tables and partitions.
+func (sa *statsAnalyze) GetTableIndicators(schema, table string) ([]statstypes.TableIndicators, error) {
+	var indicators []statstypes.TableIndicators
+	err := statsutil.CallWithSCtx(sa.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		var err error
+		indicators, err = priorityqueue.GetTableIndicators(sctx, sa.statsHandle, pmodel.NewCIStr(schema), pmodel.NewCIStr(table))
+		return err
+	}, statsutil.FlagWrapTxn)
+	return indicators, err
+}
+
+// EnqueueAnalysisJob pushes the analysis job of the table or partition into the priority queue.
+func (sa *statsAnalyze) EnqueueAnalysisJob(physicalID int64, opts ...statstypes.AnalysisJobOption) error {
+	var options statstypes.AnalysisJobOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return sa.refresher.EnqueueAnalysisJob(physicalID, options.Weight, options.AnalyzeDefaults)
+}
+
+// RemoveAnalysisJobs removes the pending analysis jobs of the tables or partitions from the priority queue.
+func (sa *statsAnalyze) RemoveAnalysisJobs(physicalIDs []int64) (int, error) {
+	return sa.refresher.RemoveAnalysisJobs(physicalIDs)
+}
+
 func (sa *statsAnalyze) handleAutoAnalyze(sctx sessionctx.Context) bool {
 	defer func() {
 		if r := recover(); r != nil {
@@ -368,6 +464,7 @@ func (sa *statsAnalyze) handleAutoAnalyze(sctx sessionctx.Context) bool {
 
 // Close closes the auto-analyze worker.
 func (sa *statsAnalyze) Close() {
+	variable.UnregisterStatistics(sa)
 	sa.refresher.Close()
 }
 
@@ -430,8 +527,8 @@ func RandomPickOneTableAndTryAutoAnalyze(
 	}
 
 	for _, db := range dbs {
-		// Ignore the memory and system database.
-		if util.IsMemOrSysDB(strings.ToLower(db)) {
+		// Ignore the memory and system database, except the system tables opted in to auto analyze.
+		if !statsutil.IsAutoAnalyzeSchema(strings.ToLower(db)) {
 			continue
 		}
 
@@ -458,7 +555,23 @@ func RandomPickOneTableAndTryAutoAnalyze(
 				continue
 			}
 
-			if tblInfo.IsView() {
+			if tblInfo.IsView() || !statsutil.IsAutoAnalyzeTable(strings.ToLower(db), tblInfo.Name.L) {
+				continue
+			}
+			if priorityqueue.IsAutoRecalcDisabled(tblInfo) {
+				continue
+			}
+			// Cached tables are analyzed through the underlying storage like normal tables,
+			// but skip them while `ALTER TABLE ... CACHE/NOCACHE` is running. They will be analyzed in the next round.
+			if tblInfo.TableCacheStatusType == model.TableCacheStatusSwitching {
+				continue
+			}
+			// Skip the tables that were manually analyzed within the auto analyze cool-down.
+			if inCoolDown, _ := priorityqueue.IsInAutoAnalyzeCoolDown(sctx, tblInfo, db, tblInfo.Name.O); inCoolDown {
+				continue
+			}
+			// Skip the tables that share the stats of the representative tables of their stats templates.
+			if _, shared := statsutil.GetStatsTemplateRepresentatives(is, tblInfo); shared {
 				continue
 			}
 
@@ -545,6 +658,10 @@ func tryAutoAnalyzeTable(
 	if statsTbl == nil || statsTbl.Pseudo || statsTbl.RealtimeCount < statistics.AutoAnalyzeMinCnt {
 		return false
 	}
+	// 3. The row counts of the tiny non-partitioned tables are refreshed by the stats delta dump instead.
+	if statsTbl.PhysicalID == tblInfo.ID && statistics.IsTinyTable(statsTbl.RealtimeCount) {
+		return false
+	}
 
 	// Check if the table needs to analyze.
 	if needAnalyze, reason := NeedAnalyzeTable(
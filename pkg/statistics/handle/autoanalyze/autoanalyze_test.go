@@ -677,3 +677,193 @@ func TestAutoAnalyzeWithVectorIndex(t *testing.T) {
 	// Vector Index can not trigger auto analyze.
 	require.False(t, h.HandleAutoAnalyze())
 }
+
+func TestAutoAnalyzeCachedTable(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, index idx(a))")
+	tk.MustExec("insert into t values (1), (2)")
+	h := dom.StatsHandle()
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	tk.MustExec("alter table t cache")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	statistics.AutoAnalyzeMinCnt = 0
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = 1000
+	}()
+
+	for _, enablePriorityQueue := range []bool{true, false} {
+		tk.MustExec(fmt.Sprintf("set global tidb_enable_auto_analyze_priority_queue = %v", enablePriorityQueue))
+		tk.MustExec("insert into t values (3), (4), (5)")
+		require.NoError(t, h.DumpStatsDeltaToKV(true))
+		require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+		// The cached table is analyzed through the underlying storage.
+		require.True(t, h.HandleAutoAnalyze())
+		require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+		tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+		require.NoError(t, err)
+		statsTbl := h.GetTableStats(tbl.Meta())
+		require.Equal(t, int64(0), statsTbl.ModifyCount)
+		require.Equal(t, statsTbl.RealtimeCount, int64(statsTbl.GetAnalyzeRowCount()))
+	}
+	tk.MustQuery("select count(*) from mysql.analyze_jobs where table_name = 't' and state = 'finished'").Check(testkit.Rows("2"))
+}
+
+func TestAutoAnalyzeCoolDown(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set global tidb_enable_auto_analyze_priority_queue = off")
+	tk.MustExec("create table t (a int, index idx(a)) auto_analyze_cool_down = 60")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  KEY `idx` (`a`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T![auto_analyze_cool_down] AUTO_ANALYZE_COOL_DOWN=60 */"))
+	tk.MustExec("insert into t values (1), (2)")
+	h := dom.StatsHandle()
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	tk.MustExec("analyze table t")
+	tk.MustExec("insert into t values (3), (4), (5)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	statistics.AutoAnalyzeMinCnt = 0
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = 1000
+	}()
+
+	// The table was manually analyzed within the cool-down of the table option.
+	require.False(t, h.HandleAutoAnalyze())
+	// The global cool-down is used if the table option is not set.
+	tk.MustExec("alter table t auto_analyze_cool_down = 0")
+	tk.MustExec("set global tidb_auto_analyze_cool_down = 60")
+	defer tk.MustExec("set global tidb_auto_analyze_cool_down = default")
+	require.False(t, h.HandleAutoAnalyze())
+	tk.MustExec("set global tidb_auto_analyze_cool_down = 0")
+	require.True(t, h.HandleAutoAnalyze())
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  KEY `idx` (`a`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+}
+
+func TestWorkloadAwareAutoAnalyze(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set global tidb_enable_auto_analyze_priority_queue = off")
+	tk.MustExec("set global tidb_enable_workload_aware_analyze = on")
+	defer tk.MustExec("set global tidb_enable_workload_aware_analyze = default")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	h := dom.StatsHandle()
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t")
+	statistics.AutoAnalyzeMinCnt = 0
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = 1000
+	}()
+	triggerAutoAnalyze := func() {
+		tk.MustExec("insert into t values (3, 3), (4, 4), (5, 5)")
+		require.NoError(t, h.DumpStatsDeltaToKV(true))
+		require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+		require.True(t, h.HandleAutoAnalyze())
+	}
+	lastAutoAnalyzeJobInfo := func() string {
+		rows := tk.MustQuery("select job_info from mysql.analyze_jobs where job_info like 'auto analyze%' order by id desc limit 1").Rows()
+		require.Len(t, rows, 1)
+		return rows[0][0].(string)
+	}
+
+	// All the columns of the small dimension table are analyzed.
+	triggerAutoAnalyze()
+	require.Equal(t, "auto analyze table all columns with 256 buckets, 100 topn, 1 samplerate", lastAutoAnalyzeJobInfo())
+
+	// The table is classified by the changes if it is not small, more buckets are used for the append-only table.
+	statistics.SmallDimensionMaxRowCount = 0
+	defer func() {
+		statistics.SmallDimensionMaxRowCount = 100_000
+	}()
+	triggerAutoAnalyze()
+	require.Equal(t, "auto analyze table with 512 buckets, 100 topn, 1 samplerate", lastAutoAnalyzeJobInfo())
+	// The class options are not persisted.
+	tk.MustQuery("select buckets from mysql.analyze_options where table_id = (select tidb_table_id from information_schema.tables where table_name = 't')").
+		Check(testkit.Rows("0"))
+
+	// The persisted options of the table take precedence.
+	tk.MustExec("analyze table t with 300 buckets")
+	triggerAutoAnalyze()
+	require.Equal(t, "auto analyze table with 300 buckets, 100 topn, 1 samplerate", lastAutoAnalyzeJobInfo())
+}
+
+func TestMigrateStatsVersion(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int, index idx(a))")
+	tk.MustExec("create table t2 (a int) partition by hash(a) partitions 2")
+	tk.MustExec("create table t3 (a int)")
+	tk.MustExec("insert into t1 values (1), (2)")
+	tk.MustExec("insert into t2 values (1), (2)")
+	tk.MustExec("insert into t3 values (1), (2)")
+	tk.MustExec("set @@session.tidb_analyze_version = 1")
+	tk.MustExec("analyze table t1, t2, t3")
+	tk.MustExec("lock stats t3")
+	h := dom.StatsHandle()
+
+	// Nothing is migrated until the analyze version is switched to 2.
+	tk.MustExec("set @@global.tidb_analyze_version = 1")
+	require.NoError(t, h.MigrateStatsVersion(dom.InfoSchema()))
+	tk.MustQuery("select count(*) from mysql.stats_version_migration").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@global.tidb_analyze_version = 2")
+	defer tk.MustExec("set @@global.tidb_analyze_version = default")
+	for range 3 {
+		require.NoError(t, h.MigrateStatsVersion(dom.InfoSchema()))
+	}
+	tk.MustQuery("select table_name, state, fail_reason from mysql.stats_version_migration order by table_name").Check(testkit.Rows(
+		"t1 finished <nil>",
+		"t2 finished <nil>",
+		"t3 skipped stats of the table or partitions are locked",
+	))
+	tk.MustQuery("select distinct stats_ver from mysql.stats_histograms where table_id in (select tidb_table_id from information_schema.tables where table_name = 't1') or " +
+		"table_id in (select tidb_partition_id from information_schema.partitions where table_name = 't2')").Check(testkit.Rows("2"))
+}
+
+func TestCheckStatsAlerts(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("create table t3 (a int) stats_auto_recalc = 0")
+	tk.MustExec("insert into t1 values (1), (2)")
+	tk.MustExec("insert into t2 values (1), (2)")
+	tk.MustExec("insert into t3 values (1), (2)")
+	tk.MustExec("analyze table t1, t2, t3")
+	tk.MustExec("insert into t1 values (3), (4), (5), (6)")
+	tk.MustExec("insert into t3 values (3), (4), (5), (6)")
+	h := dom.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+
+	// Nothing is alerted by default.
+	require.NoError(t, h.CheckStatsAlerts())
+	tk.MustQuery("select count(*) from mysql.stats_alerts").Check(testkit.Rows("0"))
+
+	// The table whose auto analyze is disabled is not alerted.
+	tk.MustExec("set @@global.tidb_stats_alert_stale_ratio = 0.5")
+	defer tk.MustExec("set @@global.tidb_stats_alert_stale_ratio = default")
+	require.NoError(t, h.CheckStatsAlerts())
+	tk.MustQuery("select table_schema, table_name, partition_name, alert_type, value > threshold, threshold, skip_reason from mysql.stats_alerts").Check(testkit.Rows(
+		"test t1  stale 1 0.5 <nil>",
+	))
+
+	// Nothing is alerted if auto analyze is disabled.
+	tk.MustExec("set @@global.tidb_enable_auto_analyze = off")
+	defer tk.MustExec("set @@global.tidb_enable_auto_analyze = default")
+	require.NoError(t, h.CheckStatsAlerts())
+	tk.MustQuery("select count(*) from mysql.stats_alerts").Check(testkit.Rows("1"))
+}
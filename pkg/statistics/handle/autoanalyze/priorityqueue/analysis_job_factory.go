@@ -17,14 +17,19 @@ package priorityqueue
 import (
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/util/intest"
 	"github.com/pingcap/tidb/pkg/util/timeutil"
 	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
 )
 
 const (
@@ -32,6 +37,11 @@ const (
 	unanalyzedTableDefaultChangePercentage = 1
 	// unanalyzedTableDefaultLastUpdateDuration is the default last update duration of unanalyzed table.
 	unanalyzedTableDefaultLastUpdateDuration = -30 * time.Minute
+	// minErrorBudgetEstimations is the min number of the estimations on the table since the last analysis to tell
+	// whether its error budget is violated, since a few estimations are not representative.
+	minErrorBudgetEstimations = 100
+	// maxErrorBudgetBurnRate bounds the burn rate of the error budget.
+	maxErrorBudgetBurnRate = 100
 )
 
 // AnalysisJobFactory is responsible for creating different types of analysis jobs.
@@ -41,6 +51,8 @@ type AnalysisJobFactory struct {
 	autoAnalyzeRatio float64
 	// The current TSO.
 	currentTs uint64
+	// truncatedTables are the tables and partitions recreated by TRUNCATE TABLE and not analyzed yet.
+	truncatedTables map[int64]struct{}
 }
 
 // NewAnalysisJobFactory creates a new AnalysisJobFactory.
@@ -52,31 +64,61 @@ func NewAnalysisJobFactory(sctx sessionctx.Context, autoAnalyzeRatio float64, cu
 	}
 }
 
-// CreateNonPartitionedTableAnalysisJob creates a job for non-partitioned tables.
+// CreateNonPartitionedTableAnalysisJob creates a job for non-partitioned tables
+// by the creator registered for NonPartitionedTableJobKind.
 func (f *AnalysisJobFactory) CreateNonPartitionedTableAnalysisJob(
 	tblInfo *model.TableInfo,
 	tblStats *statistics.Table,
 ) AnalysisJob {
-	if !tblStats.IsEligibleForAnalysis() {
+	return getAnalysisJobCreator(NonPartitionedTableJobKind)(&AnalysisJobCreateParams{
+		Factory:    f,
+		TableInfo:  tblInfo,
+		TableStats: tblStats,
+	})
+}
+
+// createNonPartitionedTableAnalysisJob is the default creator of the jobs for non-partitioned tables.
+func (f *AnalysisJobFactory) createNonPartitionedTableAnalysisJob(
+	tblInfo *model.TableInfo,
+	tblStats *statistics.Table,
+) AnalysisJob {
+	if !isEligibleForAnalysis(tblStats, f.truncatedTables) {
+		return nil
+	}
+	// The row counts of the tiny tables are refreshed by the stats delta dump instead, so they never churn the queue.
+	if statistics.IsTinyTable(tblStats.RealtimeCount) {
 		return nil
 	}
 
 	tableStatsVer := f.sctx.GetSessionVars().AnalyzeVersion
 	statistics.CheckAnalyzeVerOnTable(tblStats, &tableStatsVer)
 
-	changePercentage := f.CalculateChangePercentage(tblStats)
+	changePercentage, shrinkPercentage, burnRate := f.applyErrorBudget(
+		tblInfo.ID,
+		tblStats,
+		f.CalculateChangePercentage(tblStats),
+		f.CalculateShrinkPercentage(tblStats),
+	)
 	tableSize := f.CalculateTableSize(tblStats)
 	lastAnalysisDuration := f.GetTableLastAnalyzeDuration(tblStats)
 	indexes := f.CheckIndexesNeedAnalyze(tblInfo, tblStats)
+	if changePercentage == 0 && len(indexes) == 0 {
+		// Only the indexes are analyzed if they are stale but the table is not.
+		indexes, changePercentage = f.CheckStaleIndexes(tblInfo, tblStats)
+	}
+	verifiedDrift := f.CalculateVerifiedDrift(tblStats)
 
 	// No need to analyze.
 	// We perform a separate check because users may set the auto analyze ratio to 0,
 	// yet still wish to analyze newly added indexes and tables that have not been analyzed.
-	if changePercentage == 0 && len(indexes) == 0 {
-		return nil
+	// The tables whose stats are verified to drift or whose error budgets are violated are analyzed regardless of
+	// their changes.
+	if changePercentage == 0 && shrinkPercentage == 0 && len(indexes) == 0 && verifiedDrift == 0 && burnRate == 0 {
+		// The table may still be stale enough to refresh its row count and null counts.
+		return f.createQuickRefreshAnalysisJob(tblStats, tableSize, lastAnalysisDuration)
 	}
 
-	return NewNonPartitionedTableAnalysisJob(
+	job := NewNonPartitionedTableAnalysisJob(
 		tblInfo.ID,
 		indexes,
 		tableStatsVer,
@@ -84,34 +126,120 @@ func (f *AnalysisJobFactory) CreateNonPartitionedTableAnalysisJob(
 		tableSize,
 		lastAnalysisDuration,
 	)
+	job.ShrinkPercentage = shrinkPercentage
+	job.EstimationError = f.CalculateEstimationError(tblStats)
+	job.PseudoStatsQueries = f.CalculatePseudoStatsQueries(tblStats)
+	job.VerifiedDrift = verifiedDrift
+	job.ErrorBudgetBurnRate = burnRate
+	return job
 }
 
-// CreateStaticPartitionAnalysisJob creates a job for static partitions.
+// createQuickRefreshAnalysisJob creates a quick refresh job if the changes of the table since the last analysis or
+// quick refresh exceed tidb_auto_analyze_quick_refresh_ratio. It returns nil if the quick refresh is disabled.
+func (f *AnalysisJobFactory) createQuickRefreshAnalysisJob(
+	tblStats *statistics.Table,
+	tableSize float64,
+	lastAnalysisDuration time.Duration,
+) AnalysisJob {
+	quickRefreshRatio := variable.AutoAnalyzeQuickRefreshRatio.Load()
+	if quickRefreshRatio == 0 || f.autoAnalyzeRatio == 0 || !tblStats.IsAnalyzed() || tblStats.GetAnalyzeRowCount() <= 0 {
+		return nil
+	}
+	// Check the raw change percentage first to avoid reading the baseline of the tables which don't change much.
+	if calculateRawChangePercentage(tblStats) <= quickRefreshRatio {
+		return nil
+	}
+	baseline, err := f.getQuickRefreshBaseline(tblStats)
+	if err != nil {
+		logutil.SingletonStatsSamplerLogger().Warn(
+			"Fail to get the quick refresh baseline",
+			zap.Int64("tableID", tblStats.PhysicalID),
+			zap.Error(err),
+		)
+		return nil
+	}
+	changePercentage := float64(tblStats.ModifyCount-baseline) / tblStats.GetAnalyzeRowCount()
+	if changePercentage <= quickRefreshRatio {
+		return nil
+	}
+	return NewQuickRefreshAnalysisJob(
+		tblStats.PhysicalID,
+		tblStats.LastAnalyzeVersion,
+		changePercentage,
+		tableSize,
+		lastAnalysisDuration,
+	)
+}
+
+// getQuickRefreshBaseline returns the modify count recorded by the last quick refresh of the table.
+// It returns 0 if the table is not refreshed since its last analysis.
+func (f *AnalysisJobFactory) getQuickRefreshBaseline(tblStats *statistics.Table) (int64, error) {
+	rows, _, err := util.ExecRows(f.sctx, "select modify_count, analyze_version from mysql.stats_quick_refresh where table_id = %?", tblStats.PhysicalID)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	baseline, analyzeVersion := rows[0].GetInt64(0), rows[0].GetUint64(1)
+	// The modify count is reset by the analysis after the quick refresh.
+	if analyzeVersion != tblStats.LastAnalyzeVersion || baseline > tblStats.ModifyCount {
+		return 0, nil
+	}
+	return baseline, nil
+}
+
+// CreateStaticPartitionAnalysisJob creates a job for static partitions
+// by the creator registered for StaticPartitionJobKind.
 func (f *AnalysisJobFactory) CreateStaticPartitionAnalysisJob(
 	globalTblInfo *model.TableInfo,
 	partitionID int64,
 	partitionStats *statistics.Table,
 ) AnalysisJob {
-	if !partitionStats.IsEligibleForAnalysis() {
+	return getAnalysisJobCreator(StaticPartitionJobKind)(&AnalysisJobCreateParams{
+		Factory:     f,
+		TableInfo:   globalTblInfo,
+		PartitionID: partitionID,
+		TableStats:  partitionStats,
+	})
+}
+
+// createStaticPartitionAnalysisJob is the default creator of the jobs for static partitions.
+func (f *AnalysisJobFactory) createStaticPartitionAnalysisJob(
+	globalTblInfo *model.TableInfo,
+	partitionID int64,
+	partitionStats *statistics.Table,
+) AnalysisJob {
+	if !isEligibleForAnalysis(partitionStats, f.truncatedTables) {
 		return nil
 	}
 
 	tableStatsVer := f.sctx.GetSessionVars().AnalyzeVersion
 	statistics.CheckAnalyzeVerOnTable(partitionStats, &tableStatsVer)
 
-	changePercentage := f.CalculateChangePercentage(partitionStats)
+	// The estimations on the static partitions are recorded on the partitions.
+	changePercentage, shrinkPercentage, burnRate := f.applyErrorBudget(
+		globalTblInfo.ID,
+		partitionStats,
+		f.CalculateChangePercentage(partitionStats),
+		f.CalculateShrinkPercentage(partitionStats),
+	)
 	tableSize := f.CalculateTableSize(partitionStats)
 	lastAnalysisDuration := f.GetTableLastAnalyzeDuration(partitionStats)
 	indexes := f.CheckIndexesNeedAnalyze(globalTblInfo, partitionStats)
+	if changePercentage == 0 && len(indexes) == 0 {
+		// Only the indexes are analyzed if they are stale but the partition is not.
+		indexes, changePercentage = f.CheckStaleIndexes(globalTblInfo, partitionStats)
+	}
 
 	// No need to analyze.
 	// We perform a separate check because users may set the auto analyze ratio to 0,
 	// yet still wish to analyze newly added indexes and tables that have not been analyzed.
-	if changePercentage == 0 && len(indexes) == 0 {
+	if changePercentage == 0 && shrinkPercentage == 0 && len(indexes) == 0 && burnRate == 0 {
 		return nil
 	}
 
-	return NewStaticPartitionTableAnalysisJob(
+	job := NewStaticPartitionTableAnalysisJob(
 		globalTblInfo.ID,
 		partitionID,
 		indexes,
@@ -120,13 +248,33 @@ func (f *AnalysisJobFactory) CreateStaticPartitionAnalysisJob(
 		tableSize,
 		lastAnalysisDuration,
 	)
+	job.ShrinkPercentage = shrinkPercentage
+	job.EstimationError = f.CalculateEstimationError(partitionStats)
+	job.PseudoStatsQueries = f.CalculatePseudoStatsQueries(partitionStats)
+	job.ErrorBudgetBurnRate = burnRate
+	return job
 }
 
-// CreateDynamicPartitionedTableAnalysisJob creates a job for dynamic partitioned tables.
+// CreateDynamicPartitionedTableAnalysisJob creates a job for dynamic partitioned tables
+// by the creator registered for DynamicPartitionedTableJobKind.
 func (f *AnalysisJobFactory) CreateDynamicPartitionedTableAnalysisJob(
 	globalTblInfo *model.TableInfo,
 	globalTblStats *statistics.Table,
 	partitionStats map[PartitionIDAndName]*statistics.Table,
+) AnalysisJob {
+	return getAnalysisJobCreator(DynamicPartitionedTableJobKind)(&AnalysisJobCreateParams{
+		Factory:        f,
+		TableInfo:      globalTblInfo,
+		TableStats:     globalTblStats,
+		PartitionStats: partitionStats,
+	})
+}
+
+// createDynamicPartitionedTableAnalysisJob is the default creator of the jobs for dynamic partitioned tables.
+func (f *AnalysisJobFactory) createDynamicPartitionedTableAnalysisJob(
+	globalTblInfo *model.TableInfo,
+	globalTblStats *statistics.Table,
+	partitionStats map[PartitionIDAndName]*statistics.Table,
 ) AnalysisJob {
 	if !globalTblStats.IsEligibleForAnalysis() {
 		return nil
@@ -138,6 +286,16 @@ func (f *AnalysisJobFactory) CreateDynamicPartitionedTableAnalysisJob(
 
 	avgChange, avgSize, minLastAnalyzeDuration, partitionIDs := f.CalculateIndicatorsForPartitions(globalTblStats, partitionStats)
 	partitionIndexes := f.CheckNewlyAddedIndexesNeedAnalyzeForPartitionedTable(globalTblInfo, partitionStats)
+	// The scans on the dynamic partitioned table use the global stats, so the error budget is checked on the global table.
+	budgeted, burnRate := calculateErrorBudgetBurnRate(globalTblInfo.ID, globalTblStats)
+	if budgeted && burnRate == 0 {
+		avgChange, avgSize, minLastAnalyzeDuration, partitionIDs = 0, 0, 0, make(map[int64]struct{})
+	} else if burnRate > 0 && len(partitionIDs) == 0 {
+		// The global stats are rebuilt from all the partitions anyway.
+		for pIDAndName := range partitionStats {
+			partitionIDs[pIDAndName.ID] = struct{}{}
+		}
+	}
 
 	// No need to analyze.
 	// We perform a separate check because users may set the auto analyze ratio to 0,
@@ -146,7 +304,7 @@ func (f *AnalysisJobFactory) CreateDynamicPartitionedTableAnalysisJob(
 		return nil
 	}
 
-	return NewDynamicPartitionedTableAnalysisJob(
+	job := NewDynamicPartitionedTableAnalysisJob(
 		globalTblInfo.ID,
 		partitionIDs,
 		partitionIndexes,
@@ -155,6 +313,32 @@ func (f *AnalysisJobFactory) CreateDynamicPartitionedTableAnalysisJob(
 		avgSize,
 		minLastAnalyzeDuration,
 	)
+	job.ShrinkPercentage = f.calculateAvgShrinkPercentageForPartitions(partitionIDs, partitionStats)
+	// The scans on the dynamic partitioned table use the global stats, so the feedback is recorded on the global table.
+	job.EstimationError = f.CalculateEstimationError(globalTblStats)
+	job.PseudoStatsQueries = f.CalculatePseudoStatsQueries(globalTblStats)
+	job.ErrorBudgetBurnRate = burnRate
+	return job
+}
+
+// isEligibleForAnalysis returns whether the table is eligible for analysis. The tables and partitions recreated by
+// TRUNCATE TABLE are eligible as soon as the first rows are written to them, regardless of their sizes, and so are
+// the never analyzed ones which are already queried.
+func isEligibleForAnalysis(tblStats *statistics.Table, truncatedTables map[int64]struct{}) bool {
+	if tblStats != nil && !tblStats.Pseudo && tblStats.RealtimeCount > 0 {
+		if _, ok := truncatedTables[tblStats.PhysicalID]; ok {
+			return true
+		}
+		if isQueriedWithPseudoStats(tblStats) {
+			return true
+		}
+	}
+	return tblStats.IsEligibleForAnalysis()
+}
+
+// isQueriedWithPseudoStats returns whether the table has never been analyzed but is already queried.
+func isQueriedWithPseudoStats(tblStats *statistics.Table) bool {
+	return !tblStats.IsAnalyzed() && statistics.PseudoStatsUsage.Get(tblStats.PhysicalID) > 0
 }
 
 // CalculateChangePercentage calculates the change percentage of the table
@@ -171,11 +355,25 @@ func (f *AnalysisJobFactory) CalculateChangePercentage(tblStats *statistics.Tabl
 		return 0
 	}
 
-	tblCnt := float64(tblStats.RealtimeCount)
-	if histCnt := tblStats.GetAnalyzeRowCount(); histCnt > 0 {
-		tblCnt = histCnt
+	res := calculateRawChangePercentage(tblStats)
+	if res > f.autoAnalyzeRatio {
+		return res
+	}
+
+	return 0
+}
+
+// CalculateShrinkPercentage calculates the shrink percentage of the table
+// based on the realtime count and the analysis count.
+// The realtime count is decreased by both the `DELETE` statements and the TTL jobs,
+// which delete the expired rows by `DELETE` statements too.
+func (f *AnalysisJobFactory) CalculateShrinkPercentage(tblStats *statistics.Table) float64 {
+	// The unanalyzed table is always analyzed by the change percentage.
+	if !tblStats.IsAnalyzed() || f.autoAnalyzeRatio == 0 {
+		return 0
 	}
-	res := float64(tblStats.ModifyCount) / tblCnt
+
+	res := calculateRawShrinkPercentage(tblStats)
 	if res > f.autoAnalyzeRatio {
 		return res
 	}
@@ -183,6 +381,128 @@ func (f *AnalysisJobFactory) CalculateChangePercentage(tblStats *statistics.Tabl
 	return 0
 }
 
+// calculateRawShrinkPercentage calculates the shrink percentage of the table
+// without comparing it with the auto analyze ratio.
+// The inserted and deleted rows tracked in the stats meta are preferred, since they don't rely on the loaded histograms.
+// The analysis count is used instead if they are not tracked yet, e.g. the cluster is just upgraded.
+func calculateRawShrinkPercentage(tblStats *statistics.Table) float64 {
+	if tblStats.InsertCount > 0 || tblStats.DeleteCount > 0 {
+		removed := tblStats.DeleteCount - tblStats.InsertCount
+		if removed <= 0 {
+			return 0
+		}
+		return float64(removed) / float64(tblStats.RealtimeCount+removed)
+	}
+	histCnt := tblStats.GetAnalyzeRowCount()
+	if histCnt <= 0 || float64(tblStats.RealtimeCount) >= histCnt {
+		return 0
+	}
+	return (histCnt - float64(tblStats.RealtimeCount)) / histCnt
+}
+
+// CalculateEstimationError calculates the estimation error of the table since the last analysis
+// based on the feedback of the executed statements.
+func (f *AnalysisJobFactory) CalculateEstimationError(tblStats *statistics.Table) float64 {
+	// The unanalyzed table is always analyzed by the change percentage.
+	if !tblStats.IsAnalyzed() {
+		return 0
+	}
+	feedback := statistics.CardinalityFeedback.Get(tblStats.PhysicalID, f.FindLastAnalyzeTime(tblStats))
+	if feedback.Count == 0 {
+		return 0
+	}
+	return feedback.AvgQError()
+}
+
+// CalculatePseudoStatsQueries returns the number of the statements estimated by the pseudo stats of the table if it
+// has never been analyzed.
+func (*AnalysisJobFactory) CalculatePseudoStatsQueries(tblStats *statistics.Table) int64 {
+	if tblStats.IsAnalyzed() {
+		// The usage before the analysis is stale.
+		statistics.PseudoStatsUsage.Delete(tblStats.PhysicalID)
+		return 0
+	}
+	return statistics.PseudoStatsUsage.Get(tblStats.PhysicalID)
+}
+
+// CalculateVerifiedDrift returns the drift of the stats of the table verified by the stats probe since the last
+// analysis. Only the non-partitioned tables are probed.
+func (*AnalysisJobFactory) CalculateVerifiedDrift(tblStats *statistics.Table) float64 {
+	if !tblStats.IsAnalyzed() {
+		return 0
+	}
+	return statistics.VerifiedStatsDrift.Get(tblStats.PhysicalID, tblStats.LastAnalyzeVersion)
+}
+
+// CalculateErrorBudgetBurnRate returns whether the table has the error budget set by ALTER TABLE ... AUTO_ANALYZE
+// OPTIONS, and how fast the budget is burnt since the last analysis if it's violated, i.e. the fraction of the
+// estimations whose q-errors exceed the target divided by the allowed fraction.
+// The tableID is the ID of the logical table, while the stats are the ones on which the estimations are recorded.
+func (*AnalysisJobFactory) CalculateErrorBudgetBurnRate(tableID int64, tblStats *statistics.Table) (bool, float64) {
+	return calculateErrorBudgetBurnRate(tableID, tblStats)
+}
+
+// calculateErrorBudgetBurnRate is the implementation of CalculateErrorBudgetBurnRate. The burn rate is 0 if the budget
+// is not violated or there are not enough estimations to tell. The unanalyzed table is regarded as without the error
+// budget, since it's always analyzed by the change percentage.
+func calculateErrorBudgetBurnRate(tableID int64, tblStats *statistics.Table) (bool, float64) {
+	targetQError, fraction, ok := getErrorBudget(tableID)
+	if !ok || !tblStats.IsAnalyzed() {
+		return false, 0
+	}
+	feedback := statistics.CardinalityFeedback.Get(tblStats.PhysicalID, oracle.GetTimeFromTS(tblStats.LastAnalyzeVersion))
+	if feedback.Count < minErrorBudgetEstimations {
+		return true, 0
+	}
+	burnRate := (1 - feedback.FractionWithinQError(targetQError)) / (1 - fraction)
+	if burnRate <= 1 {
+		return true, 0
+	}
+	return true, min(burnRate, maxErrorBudgetBurnRate)
+}
+
+// applyErrorBudget returns the change percentage, the shrink percentage and the burn rate of the error budget of the
+// table. The analysis of the table with the error budget is driven by the budget rather than its changes, so the
+// change and shrink percentages are cleared unless the budget is violated.
+func (*AnalysisJobFactory) applyErrorBudget(
+	tableID int64,
+	tblStats *statistics.Table,
+	changePercentage, shrinkPercentage float64,
+) (float64, float64, float64) {
+	budgeted, burnRate := calculateErrorBudgetBurnRate(tableID, tblStats)
+	if budgeted && burnRate == 0 {
+		return 0, 0, 0
+	}
+	return changePercentage, shrinkPercentage, burnRate
+}
+
+// calculateAvgShrinkPercentageForPartitions calculates the average shrink percentage of the partitions that need to be analyzed.
+func (f *AnalysisJobFactory) calculateAvgShrinkPercentageForPartitions(
+	partitionIDs map[int64]struct{},
+	partitionStats map[PartitionIDAndName]*statistics.Table,
+) float64 {
+	if len(partitionIDs) == 0 {
+		return 0
+	}
+	totalShrinkPercent := 0.0
+	for pIDAndName, tblStats := range partitionStats {
+		if _, ok := partitionIDs[pIDAndName.ID]; ok {
+			totalShrinkPercent += f.CalculateShrinkPercentage(tblStats)
+		}
+	}
+	return totalShrinkPercent / float64(len(partitionIDs))
+}
+
+// calculateRawChangePercentage calculates the change percentage of the table
+// without comparing it with the auto analyze ratio.
+func calculateRawChangePercentage(tblStats *statistics.Table) float64 {
+	tblCnt := float64(tblStats.RealtimeCount)
+	if histCnt := tblStats.GetAnalyzeRowCount(); histCnt > 0 {
+		tblCnt = histCnt
+	}
+	return float64(tblStats.ModifyCount) / tblCnt
+}
+
 // CalculateTableSize calculates the size of the table.
 func (*AnalysisJobFactory) CalculateTableSize(tblStats *statistics.Table) float64 {
 	tblCnt := float64(tblStats.RealtimeCount)
@@ -236,6 +556,54 @@ func (*AnalysisJobFactory) CheckIndexesNeedAnalyze(tblInfo *model.TableInfo, tbl
 	return indexIDs
 }
 
+// CheckStaleIndexes checks if some indexes of the table are stale while the table itself is not, i.e. the analyses of
+// the table since the last analyses of the indexes don't cover them, so that the modifications since the last
+// analyses of the indexes exceed the auto analyze ratio. It returns the stale indexes and the max change percentage
+// of them. The stats meta of the indexes is only read for the tables modified since their last analyses, since the
+// modifications carried over to the indexes rarely exceed the ratio alone. The stale indexes are analyzed in the same
+// way as the newly added ones. See statistics.IndexStatsMeta for more details.
+func (f *AnalysisJobFactory) CheckStaleIndexes(tblInfo *model.TableInfo, tblStats *statistics.Table) (map[int64]struct{}, float64) {
+	if f.autoAnalyzeRatio == 0 || !tblStats.IsAnalyzed() || tblStats.ModifyCount == 0 {
+		return nil, 0
+	}
+	tblCnt := tblStats.GetAnalyzeRowCount()
+	if tblCnt <= 0 {
+		return nil, 0
+	}
+	metas, err := storage.IndexStatsMetasFromStorage(f.sctx, tblStats.PhysicalID)
+	if err != nil {
+		logutil.SingletonStatsSamplerLogger().Warn(
+			"Fail to read the stats meta of the indexes",
+			zap.Int64("tableID", tblStats.PhysicalID),
+			zap.Error(err),
+		)
+		return nil, 0
+	}
+
+	var indexIDs map[int64]struct{}
+	maxChangePercentage := 0.0
+	for _, idx := range tblInfo.Indices {
+		// Vector index doesn't have stats currently.
+		if idx.State != model.StatePublic || idx.VectorInfo != nil {
+			continue
+		}
+		meta, ok := metas[idx.ID]
+		if !ok || !meta.IsAnalyzed() || meta.MissedModifyCount <= 0 {
+			continue
+		}
+		changePercentage := float64(meta.ModifyCount(tblStats)) / tblCnt
+		if changePercentage <= f.autoAnalyzeRatio {
+			continue
+		}
+		if indexIDs == nil {
+			indexIDs = make(map[int64]struct{}, len(tblInfo.Indices))
+		}
+		indexIDs[idx.ID] = struct{}{}
+		maxChangePercentage = max(maxChangePercentage, changePercentage)
+	}
+	return indexIDs, maxChangePercentage
+}
+
 // CalculateIndicatorsForPartitions calculates the average change percentage,
 // average size and average last analyze duration for the partitions that meet the threshold.
 // Change percentage is the ratio of the number of modified rows to the total number of rows.
@@ -262,7 +630,7 @@ func (f *AnalysisJobFactory) CalculateIndicatorsForPartitions(
 		// Skip partition analysis if it doesn't meet the threshold, stats are not yet loaded,
 		// or the auto analyze ratio is set to 0 by the user.
 		changePercent := f.CalculateChangePercentage(tblStats)
-		if changePercent == 0 {
+		if changePercent == 0 && f.CalculateShrinkPercentage(tblStats) == 0 {
 			continue
 		}
 
@@ -337,14 +705,19 @@ func NewPartitionIDAndName(name string, id int64) PartitionIDAndName {
 }
 
 // GetPartitionStats gets the partition stats.
+// The older partitions whose stats are rolled up are ignored, see tidb_stats_rollup_recent_partitions.
 func GetPartitionStats(
 	statsHandle statstypes.StatsHandle,
 	tblInfo *model.TableInfo,
 	defs []model.PartitionDefinition,
 ) map[PartitionIDAndName]*statistics.Table {
 	partitionStats := make(map[PartitionIDAndName]*statistics.Table, len(defs))
+	recent, rolledUp := statistics.RecentRangePartitions(tblInfo)
 
 	for _, def := range defs {
+		if _, ok := recent[def.ID]; rolledUp && !ok {
+			continue
+		}
 		stats := statsHandle.GetPartitionStatsForAutoAnalyze(tblInfo, def.ID)
 		// Ignore the partition if it's not ready to analyze.
 		if !stats.IsEligibleForAnalysis() {
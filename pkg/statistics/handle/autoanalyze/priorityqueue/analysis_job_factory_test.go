@@ -21,8 +21,10 @@ import (
 
 	"github.com/pingcap/tidb/pkg/meta/model"
 	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	"github.com/pingcap/tidb/pkg/types"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/client-go/v2/oracle"
 )
@@ -84,6 +86,88 @@ func TestCalculateChangePercentage(t *testing.T) {
 	}
 }
 
+func TestCalculateShrinkPercentage(t *testing.T) {
+	// newAnalyzedTable creates the stats of a table which has analyzeCnt rows at the last analysis.
+	newAnalyzedTable := func(analyzeCnt, realtimeCnt, modifyCnt int64) *statistics.Table {
+		hg := statistics.NewHistogram(1, 1, 0, 0, types.NewFieldType(mysql.TypeLonglong), 1, 0)
+		d := types.NewIntDatum(1)
+		hg.AppendBucket(&d, &d, analyzeCnt, 1)
+		return &statistics.Table{
+			HistColl: *statistics.NewHistCollWithColsAndIdxs(0, false, realtimeCnt, modifyCnt, map[int64]*statistics.Column{
+				1: {Histogram: *hg, StatsLoadedStatus: statistics.NewStatsFullLoadStatus()},
+			}, nil),
+			ColAndIdxExistenceMap: statistics.NewColAndIndexExistenceMap(1, 0),
+			LastAnalyzeVersion:    1,
+		}
+	}
+	// withDeltas sets the inserted and deleted rows tracked in the stats meta.
+	withDeltas := func(tbl *statistics.Table, insertCnt, deleteCnt int64) *statistics.Table {
+		tbl.InsertCount = insertCnt
+		tbl.DeleteCount = deleteCnt
+		return tbl
+	}
+	tests := []struct {
+		name             string
+		tblStats         *statistics.Table
+		autoAnalyzeRatio float64
+		want             float64
+	}{
+		{
+			name: "Unanalyzed table",
+			tblStats: &statistics.Table{
+				HistColl:              *statistics.NewHistCollWithColsAndIdxs(0, false, statistics.AutoAnalyzeMinCnt+1, 0, nil, nil),
+				ColAndIdxExistenceMap: statistics.NewColAndIndexExistenceMap(0, 0),
+			},
+			autoAnalyzeRatio: 0.5,
+			want:             0,
+		},
+		{
+			name:             "Analyzed table with shrink percentage above threshold",
+			tblStats:         newAnalyzedTable(100, 40, 60),
+			autoAnalyzeRatio: 0.5,
+			want:             0.6,
+		},
+		{
+			name:             "Analyzed table with shrink percentage below threshold",
+			tblStats:         newAnalyzedTable(100, 70, 30),
+			autoAnalyzeRatio: 0.5,
+			want:             0,
+		},
+		{
+			name:             "Analyzed table which grows",
+			tblStats:         newAnalyzedTable(100, 200, 100),
+			autoAnalyzeRatio: 0.5,
+			want:             0,
+		},
+		{
+			name:             "Analyzed table with tracked deletes",
+			tblStats:         withDeltas(newAnalyzedTable(10, 40, 80), 10, 70),
+			autoAnalyzeRatio: 0.5,
+			want:             0.6,
+		},
+		{
+			name:             "Analyzed table with tracked inserts and deletes which cancel out",
+			tblStats:         withDeltas(newAnalyzedTable(100, 40, 100), 50, 50),
+			autoAnalyzeRatio: 0.5,
+			want:             0,
+		},
+		{
+			name:             "Auto analyze ratio set to 0",
+			tblStats:         newAnalyzedTable(100, 40, 60),
+			autoAnalyzeRatio: 0,
+			want:             0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := priorityqueue.NewAnalysisJobFactory(nil, tt.autoAnalyzeRatio, 0)
+			got := factory.CalculateShrinkPercentage(tt.tblStats)
+			require.InDelta(t, tt.want, got, 0.001)
+		})
+	}
+}
+
 func TestGetTableLastAnalyzeDuration(t *testing.T) {
 	tests := []struct {
 		name         string
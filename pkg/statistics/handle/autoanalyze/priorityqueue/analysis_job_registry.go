@@ -0,0 +1,87 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"sync"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/statistics"
+)
+
+// AnalysisJobKind is the kind of tables that an analysis job is created for.
+type AnalysisJobKind int
+
+const (
+	// NonPartitionedTableJobKind is for non-partitioned tables.
+	NonPartitionedTableJobKind AnalysisJobKind = iota
+	// StaticPartitionJobKind is for partitions analyzed as separate tables in static prune mode.
+	StaticPartitionJobKind
+	// DynamicPartitionedTableJobKind is for partitioned tables in dynamic prune mode.
+	DynamicPartitionedTableJobKind
+)
+
+// AnalysisJobCreateParams contains the information to create an analysis job.
+type AnalysisJobCreateParams struct {
+	// Factory provides the current auto analyze settings and the helpers to calculate the indicators.
+	Factory *AnalysisJobFactory
+	// TableInfo is the table info. For partitions, it is the info of the partitioned table.
+	TableInfo *model.TableInfo
+	// TableStats is the stats of the table. For StaticPartitionJobKind, it is the stats of the partition.
+	// For DynamicPartitionedTableJobKind, it is the global stats.
+	TableStats *statistics.Table
+	// PartitionStats is the stats of the partitions, only set for DynamicPartitionedTableJobKind.
+	PartitionStats map[PartitionIDAndName]*statistics.Table
+	// PartitionID is the partition ID, only set for StaticPartitionJobKind.
+	PartitionID int64
+}
+
+// AnalysisJobCreator creates an analysis job. It returns nil if the table does not need to be analyzed.
+type AnalysisJobCreator func(params *AnalysisJobCreateParams) AnalysisJob
+
+var analysisJobCreators = struct {
+	creators map[AnalysisJobKind]AnalysisJobCreator
+	sync.RWMutex
+}{
+	creators: map[AnalysisJobKind]AnalysisJobCreator{
+		NonPartitionedTableJobKind: func(params *AnalysisJobCreateParams) AnalysisJob {
+			return params.Factory.createNonPartitionedTableAnalysisJob(params.TableInfo, params.TableStats)
+		},
+		StaticPartitionJobKind: func(params *AnalysisJobCreateParams) AnalysisJob {
+			return params.Factory.createStaticPartitionAnalysisJob(params.TableInfo, params.PartitionID, params.TableStats)
+		},
+		DynamicPartitionedTableJobKind: func(params *AnalysisJobCreateParams) AnalysisJob {
+			return params.Factory.createDynamicPartitionedTableAnalysisJob(params.TableInfo, params.TableStats, params.PartitionStats)
+		},
+	},
+}
+
+// RegisterAnalysisJobCreator registers the creator of the analysis jobs for the kind of tables,
+// so new AnalysisJob implementations can be used without modifying the priority queue.
+// It returns the previous creator, so the new creator can fall back to it for the tables
+// it is not interested in, and tests can restore it.
+func RegisterAnalysisJobCreator(kind AnalysisJobKind, creator AnalysisJobCreator) AnalysisJobCreator {
+	analysisJobCreators.Lock()
+	defer analysisJobCreators.Unlock()
+	prev := analysisJobCreators.creators[kind]
+	analysisJobCreators.creators[kind] = creator
+	return prev
+}
+
+func getAnalysisJobCreator(kind AnalysisJobKind) AnalysisJobCreator {
+	analysisJobCreators.RLock()
+	defer analysisJobCreators.RUnlock()
+	return analysisJobCreators.creators[kind]
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+type quickAnalysisJob struct {
+	*priorityqueue.NonPartitionedTableAnalysisJob
+}
+
+func TestRegisterAnalysisJobCreator(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("insert into t1 values (1)")
+	statistics.AutoAnalyzeMinCnt = 0
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = 1000
+	}()
+	handle := dom.StatsHandle()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
+
+	var prev priorityqueue.AnalysisJobCreator
+	prev = priorityqueue.RegisterAnalysisJobCreator(
+		priorityqueue.NonPartitionedTableJobKind,
+		func(params *priorityqueue.AnalysisJobCreateParams) priorityqueue.AnalysisJob {
+			job := prev(params)
+			if job == nil {
+				return nil
+			}
+			return &quickAnalysisJob{job.(*priorityqueue.NonPartitionedTableAnalysisJob)}
+		},
+	)
+	defer priorityqueue.RegisterAnalysisJobCreator(priorityqueue.NonPartitionedTableJobKind, prev)
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+	job, err := pq.Pop()
+	require.NoError(t, err)
+	require.IsType(t, &quickAnalysisJob{}, job)
+}
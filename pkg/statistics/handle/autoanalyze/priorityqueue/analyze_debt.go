@@ -0,0 +1,252 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	statsmetrics "github.com/pingcap/tidb/pkg/statistics/handle/metrics"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// analyzeDebtRefreshInterval is the interval to refresh the analyze debt.
+	analyzeDebtRefreshInterval = time.Minute
+	// analyzeThroughputWindow is the window of the finished jobs which the throughput of auto analyze is measured by.
+	analyzeThroughputWindow = time.Hour
+	// defaultColumnBytes is the assumed size of a value of the tables whose column sizes are unknown, e.g. the ones
+	// never analyzed.
+	defaultColumnBytes = 8
+	// columnBytesQueryBatchSize is the max number of the tables whose column sizes are read by one query.
+	columnBytesQueryBatchSize = 1024
+)
+
+// The analyze debt is persisted in mysql.tidb by the stats owner, and shown as the status variables of the same names
+// on all the instances.
+const (
+	// AnalyzeDebtBytesKey is the key of the analyze debt in stale bytes.
+	AnalyzeDebtBytesKey = "tidb_analyze_debt_bytes"
+	// AnalyzeThroughputKey is the key of the throughput of auto analyze in stale bytes per second.
+	AnalyzeThroughputKey = "tidb_analyze_throughput_bytes_per_second"
+	// AnalyzeDebtETAKey is the key of the ETA in seconds to catch up the analyze debt.
+	AnalyzeDebtETAKey = "tidb_analyze_debt_eta_seconds"
+)
+
+// AnalyzeDebtKeys are the keys of the analyze debt in mysql.tidb.
+var AnalyzeDebtKeys = []string{AnalyzeDebtBytesKey, AnalyzeThroughputKey, AnalyzeDebtETAKey}
+
+var analyzeDebtComments = map[string]string{
+	AnalyzeDebtBytesKey:  "The outstanding work of auto analyze in stale bytes.",
+	AnalyzeThroughputKey: "The stale bytes cleared by auto analyze per second.",
+	AnalyzeDebtETAKey:    "The ETA in seconds to catch up the analyze debt, -1 means auto analyze doesn't make progress.",
+}
+
+// debtJob is the part of a job which its stale bytes are estimated from. It's copied under the lock of the queue, so
+// the stale bytes are estimated without blocking the queue.
+type debtJob struct {
+	physicalIDs      []int64
+	changePercentage float64
+	tableSize        float64
+}
+
+func newDebtJob(job AnalysisJob) debtJob {
+	jobJSON := job.AsJSON()
+	physicalIDs := make([]int64, 0, len(jobJSON.PartitionIDs)+len(jobJSON.PartitionIndexIDs))
+	physicalIDs = append(physicalIDs, jobJSON.PartitionIDs...)
+	for partitionID := range jobJSON.PartitionIndexIDs {
+		physicalIDs = append(physicalIDs, partitionID)
+	}
+	if len(physicalIDs) == 0 {
+		physicalIDs = append(physicalIDs, jobJSON.TableID)
+	}
+	indicators := job.GetIndicators()
+	return debtJob{
+		physicalIDs:      physicalIDs,
+		changePercentage: indicators.ChangePercentage,
+		tableSize:        indicators.TableSize,
+	}
+}
+
+// finishedDebt is the stale bytes cleared by a finished job.
+type finishedDebt struct {
+	finishTime time.Time
+	staleBytes float64
+}
+
+// jobsStaleBytes estimates the stale bytes of the jobs, which are the bytes of the tables or partitions to analyze
+// weighted by their change percentages, i.e. roughly the bytes changed since the last analysis. The bytes of a table
+// are the total size of its columns collected by the last analysis, or estimated from the table size if it's unknown.
+func jobsStaleBytes(sctx sessionctx.Context, jobs []debtJob) ([]float64, error) {
+	physicalIDs := make([]int64, 0, len(jobs))
+	for _, job := range jobs {
+		physicalIDs = append(physicalIDs, job.physicalIDs...)
+	}
+	columnBytes := make(map[int64]float64, len(physicalIDs))
+	for i := 0; i < len(physicalIDs); i += columnBytesQueryBatchSize {
+		end := min(i+columnBytesQueryBatchSize, len(physicalIDs))
+		rows, _, err := statsutil.ExecRows(sctx,
+			"SELECT table_id, SUM(tot_col_size) FROM mysql.stats_histograms WHERE table_id IN (%?) AND is_index = 0 GROUP BY table_id",
+			physicalIDs[i:end])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, row := range rows {
+			bytes, err := row.GetMyDecimal(1).ToFloat64()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			columnBytes[row.GetInt64(0)] = bytes
+		}
+	}
+	staleBytes := make([]float64, len(jobs))
+	for i, job := range jobs {
+		var bytes float64
+		for _, physicalID := range job.physicalIDs {
+			bytes += columnBytes[physicalID]
+		}
+		if bytes <= 0 {
+			// The table size of the partitioned table is the average one of its partitions.
+			bytes = job.tableSize * float64(len(job.physicalIDs)) * defaultColumnBytes
+		}
+		staleBytes[i] = bytes * job.changePercentage
+	}
+	return staleBytes, nil
+}
+
+// calculateAnalyzeDebtETA returns the ETA in seconds to catch up the analyze debt at the throughput, or -1 if the
+// debt is never caught up, i.e. auto analyze doesn't clear any debt.
+func calculateAnalyzeDebtETA(debt, throughput float64) float64 {
+	if debt <= 0 {
+		return 0
+	}
+	if throughput <= 0 {
+		return -1
+	}
+	return debt / throughput
+}
+
+// recordFinishedDebt records the stale bytes cleared by the finished job, which are estimated by the indicators of
+// the job when it's popped.
+func (pq *AnalysisPriorityQueue) recordFinishedDebt(job debtJob) {
+	var staleBytes []float64
+	if err := statsutil.CallWithSCtx(pq.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		var err error
+		staleBytes, err = jobsStaleBytes(sctx, []debtJob{job})
+		return err
+	}); err != nil {
+		statslogutil.StatsLogger().Warn("Failed to estimate the stale bytes of the finished job", zap.Error(err))
+		return
+	}
+	pq.syncFields.mu.Lock()
+	defer pq.syncFields.mu.Unlock()
+	if !pq.syncFields.initialized {
+		return
+	}
+	pq.syncFields.finishedDebts = append(pq.syncFields.finishedDebts, finishedDebt{
+		finishTime: Now(),
+		staleBytes: staleBytes[0],
+	})
+}
+
+// throughputWithoutLock returns the stale bytes cleared per second by the jobs finished in the throughput window,
+// and prunes the ones out of the window. The throughput is measured since the queue is initialized if it's within
+// the window.
+func (pq *AnalysisPriorityQueue) throughputWithoutLock(now time.Time) float64 {
+	windowStart := now.Add(-analyzeThroughputWindow)
+	finished := pq.syncFields.finishedDebts
+	i := 0
+	for i < len(finished) && finished[i].finishTime.Before(windowStart) {
+		i++
+	}
+	pq.syncFields.finishedDebts = finished[i:]
+	var clearedBytes float64
+	for _, f := range pq.syncFields.finishedDebts {
+		clearedBytes += f.staleBytes
+	}
+	if pq.syncFields.initializedTime.After(windowStart) {
+		windowStart = pq.syncFields.initializedTime
+	}
+	elapsed := now.Sub(windowStart).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return clearedBytes / elapsed
+}
+
+// RefreshAnalyzeDebt estimates the analyze debt, i.e. the stale bytes of all the jobs in the queue, and the ETA to
+// catch it up at the throughput of auto analyze. They're reported by the metrics and persisted in mysql.tidb, so they
+// can be seen by the status variables on all the instances.
+// Note: This function is thread-safe.
+func (pq *AnalysisPriorityQueue) RefreshAnalyzeDebt() {
+	pq.syncFields.mu.Lock()
+	if !pq.syncFields.initialized {
+		pq.syncFields.mu.Unlock()
+		return
+	}
+	// Copy the jobs under the lock, so we don't block the queue while reading the storage.
+	list := pq.syncFields.inner.list()
+	jobs := make([]debtJob, 0, len(list))
+	for _, job := range list {
+		jobs = append(jobs, newDebtJob(job))
+	}
+	throughput := pq.throughputWithoutLock(Now())
+	pq.syncFields.mu.Unlock()
+
+	if err := statsutil.CallWithSCtx(pq.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		staleBytes, err := jobsStaleBytes(sctx, jobs)
+		if err != nil {
+			return err
+		}
+		var debt float64
+		for _, bytes := range staleBytes {
+			debt += bytes
+		}
+		eta := calculateAnalyzeDebtETA(debt, throughput)
+		statsmetrics.AnalyzeDebtBytesGauge.Set(debt)
+		statsmetrics.AnalyzeThroughputGauge.Set(throughput)
+		statsmetrics.AnalyzeDebtETAGauge.Set(eta)
+		return saveAnalyzeDebt(sctx, map[string]float64{
+			AnalyzeDebtBytesKey:  debt,
+			AnalyzeThroughputKey: throughput,
+			AnalyzeDebtETAKey:    eta,
+		})
+	}, statsutil.FlagWrapTxn); err != nil {
+		statslogutil.StatsLogger().Error("Failed to refresh the analyze debt", zap.Error(err))
+	}
+}
+
+func saveAnalyzeDebt(sctx sessionctx.Context, values map[string]float64) error {
+	for _, key := range AnalyzeDebtKeys {
+		value := strconv.FormatFloat(values[key], 'f', 2, 64)
+		if _, err := statsutil.Exec(sctx,
+			"INSERT HIGH_PRIORITY INTO mysql.tidb VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE variable_value = %?, comment = %?",
+			key, value, analyzeDebtComments[key], value, analyzeDebtComments[key]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// resetAnalyzeDebtMetrics resets the metrics of the analyze debt, which are only reported by the stats owner.
+func resetAnalyzeDebtMetrics() {
+	statsmetrics.AnalyzeDebtBytesGauge.Set(0)
+	statsmetrics.AnalyzeThroughputGauge.Set(0)
+	statsmetrics.AnalyzeDebtETAGauge.Set(0)
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"sync"
+
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/sysproctrack"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/exec"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+)
+
+// inheritedAnalyzeDefaults keeps the default analyze options inherited from the sessions which enqueued the jobs by
+// EnqueueJob. They are kept by the table IDs of the jobs rather than in the jobs, since the jobs may be recreated on
+// new DML changes before they are analyzed.
+var inheritedAnalyzeDefaults struct {
+	sync.Mutex
+	defaults map[int64]variable.AnalyzeDefaults
+}
+
+// setInheritedAnalyzeDefaults sets the default analyze options used to analyze the table.
+func setInheritedAnalyzeDefaults(tableID int64, defaults variable.AnalyzeDefaults) {
+	inheritedAnalyzeDefaults.Lock()
+	defer inheritedAnalyzeDefaults.Unlock()
+	if inheritedAnalyzeDefaults.defaults == nil {
+		inheritedAnalyzeDefaults.defaults = make(map[int64]variable.AnalyzeDefaults)
+	}
+	inheritedAnalyzeDefaults.defaults[tableID] = defaults
+}
+
+// getInheritedAnalyzeDefaults returns the default analyze options used to analyze the table, if they are inherited.
+func getInheritedAnalyzeDefaults(tableID int64) (variable.AnalyzeDefaults, bool) {
+	inheritedAnalyzeDefaults.Lock()
+	defer inheritedAnalyzeDefaults.Unlock()
+	defaults, ok := inheritedAnalyzeDefaults.defaults[tableID]
+	return defaults, ok
+}
+
+// clearInheritedAnalyzeDefaults forgets the inherited default analyze options once the job of the table is finished.
+func clearInheritedAnalyzeDefaults(tableID int64) {
+	inheritedAnalyzeDefaults.Lock()
+	defer inheritedAnalyzeDefaults.Unlock()
+	delete(inheritedAnalyzeDefaults.defaults, tableID)
+}
+
+// autoAnalyze executes the analyze SQL of the job of the table. If the job inherits the default analyze options,
+// they are set to the internal session during the execution, so that they are used as if the SQL were issued by the
+// session enqueuing the job. The job is executed under the resource group of the table, so that it's throttled by
+// the budget of the workload owning the table rather than the default resource group.
+func autoAnalyze(
+	sctx sessionctx.Context,
+	statsHandle statstypes.StatsHandle,
+	sysProcTracker sysproctrack.Tracker,
+	tableID int64,
+	statsVer int,
+	sql string,
+	params ...any,
+) bool {
+	vars := sctx.GetSessionVars()
+	if groupName := getAutoAnalyzeResourceGroup(sctx.GetDomainInfoSchema().(infoschema.InfoSchema), tableID); groupName != vars.ResourceGroupName {
+		originGroupName := vars.ResourceGroupName
+		// The name is set directly rather than by SetResourceGroupName, since the internal session isn't a connection.
+		vars.ResourceGroupName = groupName
+		defer func() {
+			vars.ResourceGroupName = originGroupName
+		}()
+	}
+	if defaults, ok := getInheritedAnalyzeDefaults(tableID); ok {
+		origin := vars.AnalyzeDefaults
+		vars.AnalyzeDefaults = defaults
+		// The internal session is put back to the pool after the job, so the options must be restored.
+		defer func() {
+			vars.AnalyzeDefaults = origin
+		}()
+	}
+	failpoint.InjectCall("beforeAutoAnalyze", tableID, vars.ResourceGroupName)
+	return exec.AutoAnalyze(sctx, statsHandle, sysProcTracker, statsVer, sql, params...)
+}
@@ -21,6 +21,11 @@ const (
 	EventNone = 0.0
 	// EventNewIndex represents a special event for newly added indexes.
 	EventNewIndex = 2.0
+	// EventExchangePartition represents a special event for the tables and partitions swapped by EXCHANGE PARTITION.
+	EventExchangePartition = 1.0
+	// EventTruncateTable represents a special event for the tables and partitions recreated by TRUNCATE TABLE, which
+	// have no stats at all until they are analyzed.
+	EventTruncateTable = 2.0
 )
 
 // TODO: make these configurable.
@@ -28,6 +33,24 @@ const (
 	changeRatioWeight = 0.6
 	sizeWeight        = 0.1
 	analysisInterval  = 0.3
+	// shrinkRatioWeight is an extra weight for the tables which shrink a lot since the last analysis.
+	// It is zero for the tables which only grow, so it doesn't affect their priority.
+	shrinkRatioWeight = 0.3
+	// estimationErrorWeight is an extra weight for the tables whose stats lead to inaccurate estimations.
+	// It is zero for the tables whose estimations are accurate or have no feedback.
+	estimationErrorWeight = 0.2
+	// pseudoStatsQueryWeight is an extra weight for the tables which have never been analyzed but are already
+	// queried, e.g. the newly created ones, whose queries are estimated by the pseudo stats until they are analyzed.
+	// It is zero for the tables which are analyzed or not queried.
+	pseudoStatsQueryWeight = 1.0
+	// verifiedDriftWeight is an extra weight for the tables whose stats are verified to drift by the stats probe,
+	// which is large enough to outrank the change ratio.
+	// It is zero for the tables which are not probed or whose stats are accurate.
+	verifiedDriftWeight = 2.0
+	// errorBudgetWeight is an extra weight for the tables whose error budgets are violated, which grows with the burn
+	// rate of the budget.
+	// It is zero for the tables which have no error budget or whose budgets are not violated.
+	errorBudgetWeight = 1.0
 )
 
 // PriorityCalculator implements the WeightCalculator interface.
@@ -45,21 +68,41 @@ func NewPriorityCalculator() *PriorityCalculator {
 // - Table Change Ratio (Change Ratio): Accounts for 60%
 // - Table Size (Size): Accounts for 10%
 // - Analysis Interval (Analysis Interval): Accounts for 30%
+// - Table Shrink Ratio (Shrink Ratio): An extra 30% for the tables that shrink
+// - Estimation Error (Estimation Error): An extra 20% for the tables whose estimations are inaccurate
+// - Pseudo Stats Queries (Pseudo Stats Queries): An extra 100% for the never analyzed tables which are queried
+// - Verified Drift (Verified Drift): An extra 200% for the tables whose stats are verified to drift
+// - Error Budget Burn Rate (Burn Rate): An extra 100% for the tables whose error budgets are violated
 // priority_score calculates the priority score based on the following formula:
 //
 //	priority_score = (0.6 * math.Log10(1 + ChangeRatio) +
 //	                  0.1 * (1 - math.Log10(1 + TableSize)) +
 //	                  0.3 * math.Log10(1 + math.Sqrt(AnalysisInterval)) +
-//	                  special_event[event])
+//	                  0.3 * math.Log10(1 + ShrinkRatio) +
+//	                  0.2 * math.Log10(max(EstimationError, 1)) +
+//	                  1.0 * math.Log10(1 + PseudoStatsQueries) +
+//	                  2.0 * VerifiedDrift +
+//	                  1.0 * math.Log10(1 + BurnRate) +
+//	                  special_event[event] +
+//	                  priority)
+//
+// The priority is set by ALTER TABLE ... AUTO_ANALYZE OPTIONS (PRIORITY = ...), which is 0 by default.
 func (pc *PriorityCalculator) CalculateWeight(job AnalysisJob) float64 {
 	// We multiply the priority_score by 100 to increase its magnitude. This ensures that
 	// when we apply the log10 function, the resulting value is more meaningful and reasonable.
 	indicators := job.GetIndicators()
 	changeRatio := 100 * indicators.ChangePercentage
+	shrinkRatio := 100 * indicators.ShrinkPercentage
 	return changeRatioWeight*math.Log10(1+changeRatio) +
 		sizeWeight*(1-math.Log10(1+indicators.TableSize)) +
 		analysisInterval*math.Log10(1+math.Sqrt(indicators.LastAnalysisDuration.Seconds())) +
-		pc.GetSpecialEvent(job)
+		shrinkRatioWeight*math.Log10(1+shrinkRatio) +
+		estimationErrorWeight*math.Log10(max(indicators.EstimationError, 1)) +
+		pseudoStatsQueryWeight*math.Log10(1+float64(indicators.PseudoStatsQueries)) +
+		verifiedDriftWeight*indicators.VerifiedDrift +
+		errorBudgetWeight*math.Log10(1+indicators.ErrorBudgetBurnRate) +
+		pc.GetSpecialEvent(job) +
+		getTablePriority(logicalTableID(job))
 }
 
 // GetSpecialEvent returns the special event weight.
@@ -68,6 +111,12 @@ func (*PriorityCalculator) GetSpecialEvent(job AnalysisJob) float64 {
 	if job.HasNewlyAddedIndex() {
 		return EventNewIndex
 	}
+	if job.GetIndicators().Truncated {
+		return EventTruncateTable
+	}
+	if job.GetIndicators().PartitionExchanged {
+		return EventExchangePartition
+	}
 
 	return EventNone
 }
@@ -25,6 +25,11 @@ import (
 type testData struct {
 	ID                   int
 	ChangePercentage     float64
+	ShrinkPercentage     float64
+	EstimationError      float64
+	PseudoStatsQueries   int64
+	VerifiedDrift        float64
+	ErrorBudgetBurnRate  float64
 	TableSize            float64
 	LastAnalysisDuration time.Duration
 }
@@ -103,6 +108,103 @@ func TestCalculateWeight(t *testing.T) {
 		},
 	}
 	testWeightCalculation(t, pc, justBeingAnalyzedGroup)
+	// Only focus on shrink percentage. Bigger shrink percentage, higher weight.
+	shrinkPercentageGroup := []testData{
+		{
+			ChangePercentage:     0.6,
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+		{
+			ChangePercentage:     0.6,
+			ShrinkPercentage:     0.5,
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+		{
+			ChangePercentage:     0.6,
+			ShrinkPercentage:     0.6,
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+	}
+	testWeightCalculation(t, pc, shrinkPercentageGroup)
+	// Only focus on estimation error. Bigger estimation error, higher weight.
+	estimationErrorGroup := []testData{
+		{
+			ChangePercentage:     0.6,
+			EstimationError:      1,
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+		{
+			ChangePercentage:     0.6,
+			EstimationError:      10,
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+		{
+			ChangePercentage:     0.6,
+			EstimationError:      100,
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+	}
+	testWeightCalculation(t, pc, estimationErrorGroup)
+	// Only focus on the queries estimated by the pseudo stats. More queries, higher weight.
+	pseudoStatsQueriesGroup := []testData{
+		{
+			ChangePercentage:     1,
+			TableSize:            10,
+			LastAnalysisDuration: 30 * time.Minute,
+		},
+		{
+			ChangePercentage:     1,
+			PseudoStatsQueries:   1,
+			TableSize:            10,
+			LastAnalysisDuration: 30 * time.Minute,
+		},
+		{
+			ChangePercentage:     1,
+			PseudoStatsQueries:   100,
+			TableSize:            10,
+			LastAnalysisDuration: 30 * time.Minute,
+		},
+	}
+	testWeightCalculation(t, pc, pseudoStatsQueriesGroup)
+	// The verified drift outranks the change percentage.
+	verifiedDriftGroup := []testData{
+		{
+			ChangePercentage:     1,
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+		{
+			ChangePercentage:     0.01,
+			VerifiedDrift:        1,
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+	}
+	testWeightCalculation(t, pc, verifiedDriftGroup)
+	// Faster the error budget is burnt, higher weight.
+	errorBudgetGroup := []testData{
+		{
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+		{
+			ErrorBudgetBurnRate:  2,
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+		{
+			ErrorBudgetBurnRate:  20,
+			TableSize:            1000,
+			LastAnalysisDuration: time.Hour,
+		},
+	}
+	testWeightCalculation(t, pc, errorBudgetGroup)
 }
 
 // testWeightCalculation is a helper function to test the weight calculation.
@@ -113,6 +215,11 @@ func testWeightCalculation(t *testing.T, pc *priorityqueue.PriorityCalculator, g
 		job := &priorityqueue.NonPartitionedTableAnalysisJob{
 			Indicators: priorityqueue.Indicators{
 				ChangePercentage:     tc.ChangePercentage,
+				ShrinkPercentage:     tc.ShrinkPercentage,
+				EstimationError:      tc.EstimationError,
+				PseudoStatsQueries:   tc.PseudoStatsQueries,
+				VerifiedDrift:        tc.VerifiedDrift,
+				ErrorBudgetBurnRate:  tc.ErrorBudgetBurnRate,
 				TableSize:            tc.TableSize,
 				LastAnalysisDuration: tc.LastAnalysisDuration,
 			},
@@ -145,4 +252,18 @@ func TestGetSpecialEvent(t *testing.T) {
 		PartitionIndexIDs: map[int64][]int64{},
 	}
 	require.Equal(t, priorityqueue.EventNone, pc.GetSpecialEvent(jobWithoutIndex))
+
+	jobExchanged := &priorityqueue.NonPartitionedTableAnalysisJob{
+		Indicators: priorityqueue.Indicators{
+			PartitionExchanged: true,
+		},
+	}
+	require.Equal(t, priorityqueue.EventExchangePartition, pc.GetSpecialEvent(jobExchanged))
+
+	jobTruncated := &priorityqueue.NonPartitionedTableAnalysisJob{
+		Indicators: priorityqueue.Indicators{
+			Truncated: true,
+		},
+	}
+	require.Equal(t, priorityqueue.EventTruncateTable, pc.GetSpecialEvent(jobTruncated))
 }
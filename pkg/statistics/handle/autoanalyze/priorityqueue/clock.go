@@ -0,0 +1,69 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/util/intest"
+	"github.com/tikv/client-go/v2/oracle"
+)
+
+// Clock is the source of the time which the scheduling of auto analyze depends on, e.g. the time since the last
+// analysis of the tables and the time windows of auto analyze.
+type Clock interface {
+	Now() time.Time
+}
+
+// virtualClock replaces the wall clock in the deterministic tests, see SetVirtualClockForTest.
+var virtualClock atomic.Pointer[Clock]
+
+// SetVirtualClockForTest replaces the clock of the scheduling with the virtual clock, and returns the function to
+// restore the wall clock. The periodic maintenance of the priority queues initialized under the virtual clock is not
+// run in the background, the tests drive it instead, e.g. by ProcessDMLChanges, so that the scheduling is
+// deterministic.
+// Only used in the test.
+func SetVirtualClockForTest(c Clock) (restore func()) {
+	intest.Assert(intest.InTest, "the virtual clock is only used in the test")
+	virtualClock.Store(&c)
+	return func() {
+		virtualClock.Store(nil)
+	}
+}
+
+// Now returns the current time of the scheduling, which is the time of the virtual clock if it's set.
+func Now() time.Time {
+	if c := virtualClock.Load(); c != nil {
+		return (*c).Now()
+	}
+	return time.Now()
+}
+
+// isVirtualClock returns whether the scheduling runs under the virtual clock.
+func isVirtualClock() bool {
+	return virtualClock.Load() != nil
+}
+
+// currentTS returns the current timestamp of the scheduling, which is the start TS of the session, or the timestamp
+// of the virtual clock if it's set.
+func currentTS(sctx sessionctx.Context) (uint64, error) {
+	if c := virtualClock.Load(); c != nil {
+		return oracle.GoTimeToTS((*c).Now()), nil
+	}
+	return statsutil.GetStartTS(sctx)
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// DecisionEnqueue means the table is pushed into the priority queue.
+	DecisionEnqueue = "enqueue"
+	// DecisionSkip means the table is popped from the priority queue but not analyzed.
+	DecisionSkip = "skip"
+	// DecisionDispatch means the table is submitted to the auto analyze worker.
+	DecisionDispatch = "dispatch"
+)
+
+// decisionLogTables is the tables of tidb_auto_analyze_decision_log_tables resolved to their IDs. The jobs don't
+// carry the names of the tables until they are validated, so the tables are matched by the IDs.
+var decisionLogTables struct {
+	sync.RWMutex
+	// tables is the value of tidb_auto_analyze_decision_log_tables which ids are resolved from.
+	tables        string
+	schemaVersion int64
+	// ids maps the ID of the table to its name, i.e. 'schema.table'.
+	ids map[int64]string
+}
+
+// skipReasons keeps the latest reasons why the jobs of the tables were skipped, which are reported by the stats
+// alerts no matter whether the decisions are logged. The reason of a table is cleared once its job is dispatched.
+var skipReasons struct {
+	sync.Mutex
+	reasons map[int64]string
+}
+
+// GetSkipReason returns the latest reason why the job of the table or the partition was skipped, or an empty
+// string if its job has not been skipped since it was dispatched last time.
+func GetSkipReason(tableID int64) string {
+	skipReasons.Lock()
+	defer skipReasons.Unlock()
+	return skipReasons.reasons[tableID]
+}
+
+func recordSkipReason(tableID int64, decision, reason string) {
+	skipReasons.Lock()
+	defer skipReasons.Unlock()
+	switch decision {
+	case DecisionSkip:
+		if skipReasons.reasons == nil {
+			skipReasons.reasons = make(map[int64]string)
+		}
+		skipReasons.reasons[tableID] = reason
+	case DecisionDispatch:
+		delete(skipReasons.reasons, tableID)
+	}
+}
+
+// RefreshDecisionLogTables resolves the tables of tidb_auto_analyze_decision_log_tables if the variable or the
+// schema has been changed since the last refresh.
+func RefreshDecisionLogTables(sctx sessionctx.Context) {
+	tables := variable.AutoAnalyzeDecisionLogTables.Load()
+	is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
+	decisionLogTables.RLock()
+	upToDate := decisionLogTables.tables == tables && decisionLogTables.schemaVersion == is.SchemaMetaVersion()
+	decisionLogTables.RUnlock()
+	if upToDate {
+		return
+	}
+
+	ids := make(map[int64]string)
+	if tables != "" {
+		for _, name := range strings.Split(tables, ",") {
+			schema, table, _ := strings.Cut(name, ".")
+			tbl, err := is.TableByName(context.Background(), pmodel.NewCIStr(schema), pmodel.NewCIStr(table))
+			if err != nil {
+				// The table may be created later, it's resolved again once the schema is changed.
+				continue
+			}
+			ids[tbl.Meta().ID] = name
+		}
+	}
+	decisionLogTables.Lock()
+	defer decisionLogTables.Unlock()
+	decisionLogTables.tables = tables
+	decisionLogTables.schemaVersion = is.SchemaMetaVersion()
+	decisionLogTables.ids = ids
+}
+
+// LogDecision logs the decision made by auto analyze for the job, if the table of the job is always logged by
+// tidb_auto_analyze_decision_log_tables or the decision is sampled by tidb_auto_analyze_decision_log_sample_rate.
+func LogDecision(job AnalysisJob, decision, reason string) {
+	recordSkipReason(job.GetTableID(), decision, reason)
+	tableID := logicalTableID(job)
+	name, ok := shouldLogDecision(tableID)
+	if !ok {
+		return
+	}
+	if jobName := jobTableName(job); jobName != "" {
+		name = jobName
+	}
+	statslogutil.StatsLogger().Info(
+		"Auto analyze decision",
+		zap.String("table", name),
+		zap.Int64("tableID", tableID),
+		zap.String("decision", decision),
+		zap.String("reason", reason),
+		zap.Float64("weight", job.GetWeight()),
+		zap.Any("indicators", job.AsJSON().Indicators),
+	)
+}
+
+// shouldLogDecision returns whether the decision of the table is logged, and the name of the table if it's always
+// logged.
+func shouldLogDecision(tableID int64) (string, bool) {
+	decisionLogTables.RLock()
+	name, ok := decisionLogTables.ids[tableID]
+	decisionLogTables.RUnlock()
+	if ok {
+		return name, true
+	}
+	rate := variable.AutoAnalyzeDecisionLogSampleRate.Load()
+	// #nosec G404
+	return "", rate > 0 && rand.Float64() < rate
+}
+
+// logicalTableID returns the ID of the table that the job belongs to. For the static partitions, it's the ID
+// of the partitioned table, so the partitions are logged and configured along with the table.
+func logicalTableID(job AnalysisJob) int64 {
+	if j, ok := job.(*StaticPartitionedTableAnalysisJob); ok {
+		return j.GlobalTableID
+	}
+	return job.GetTableID()
+}
+
+// jobTableName returns the name of the table of the job, or an empty string if the job hasn't been validated.
+func jobTableName(job AnalysisJob) string {
+	var schema, table string
+	switch j := job.(type) {
+	case *NonPartitionedTableAnalysisJob:
+		schema, table = j.SchemaName, j.TableName
+	case *QuickRefreshAnalysisJob:
+		schema, table = j.SchemaName, j.TableName
+	case *StaticPartitionedTableAnalysisJob:
+		schema, table = j.SchemaName, j.GlobalTableName
+	case *DynamicPartitionedTableAnalysisJob:
+		schema, table = j.SchemaName, j.GlobalTableName
+	}
+	if schema == "" || table == "" {
+		return ""
+	}
+	return strings.ToLower(schema + "." + table)
+}
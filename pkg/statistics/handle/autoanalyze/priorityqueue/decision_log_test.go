@@ -0,0 +1,71 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue_test
+
+import (
+	"context"
+	"testing"
+
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldLogDecision(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	t1, err := dom.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t1"))
+	require.NoError(t, err)
+	t2, err := dom.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t2"))
+	require.NoError(t, err)
+
+	// Nothing is logged by default.
+	priorityqueue.RefreshDecisionLogTables(tk.Session())
+	_, ok := priorityqueue.ShouldLogDecisionForTesting(t1.Meta().ID)
+	require.False(t, ok)
+
+	// The tables in the list are always logged.
+	tk.MustExec("set global tidb_auto_analyze_decision_log_tables = 'Test.T1, test.not_exist'")
+	priorityqueue.RefreshDecisionLogTables(tk.Session())
+	name, ok := priorityqueue.ShouldLogDecisionForTesting(t1.Meta().ID)
+	require.True(t, ok)
+	require.Equal(t, "test.t1", name)
+	_, ok = priorityqueue.ShouldLogDecisionForTesting(t2.Meta().ID)
+	require.False(t, ok)
+
+	// The table created after the refresh is resolved once the schema is changed.
+	tk.MustExec("create table not_exist (a int)")
+	tk.MustExec("set global tidb_auto_analyze_decision_log_sample_rate = 1")
+	priorityqueue.RefreshDecisionLogTables(tk.Session())
+	newTbl, err := dom.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("not_exist"))
+	require.NoError(t, err)
+	name, ok = priorityqueue.ShouldLogDecisionForTesting(newTbl.Meta().ID)
+	require.True(t, ok)
+	require.Equal(t, "test.not_exist", name)
+	// Other tables are logged by the sample rate.
+	name, ok = priorityqueue.ShouldLogDecisionForTesting(t2.Meta().ID)
+	require.True(t, ok)
+	require.Empty(t, name)
+
+	tk.MustExec("set global tidb_auto_analyze_decision_log_tables = ''")
+	tk.MustExec("set global tidb_auto_analyze_decision_log_sample_rate = 0")
+	priorityqueue.RefreshDecisionLogTables(tk.Session())
+	_, ok = priorityqueue.ShouldLogDecisionForTesting(t1.Meta().ID)
+	require.False(t, ok)
+}
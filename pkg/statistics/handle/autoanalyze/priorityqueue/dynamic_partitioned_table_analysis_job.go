@@ -19,10 +19,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/sysproctrack"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
-	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/exec"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
 	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 )
@@ -112,7 +112,7 @@ func (j *DynamicPartitionedTableAnalysisJob) Analyze(
 		}
 	}()
 
-	return statsutil.CallWithSCtx(statsHandle.SPool(), func(sctx sessionctx.Context) error {
+	return statsutil.CallWithSCtx(statsHandle.PrioritizedSPool(statsutil.SessionPriorityLow), func(sctx sessionctx.Context) error {
 		switch j.getAnalyzeType() {
 		case analyzeDynamicPartition:
 			success = j.analyzePartitions(sctx, statsHandle, sysProcTracker)
@@ -153,6 +153,7 @@ func (j *DynamicPartitionedTableAnalysisJob) HasNewlyAddedIndex() bool {
 // - Schema exists
 // - Table exists and is partitioned
 // - All specified partitions exist
+// - The table is not locked, and the locked partitions are skipped
 // - No recent failed analysis for any partition to avoid queue blocking
 func (j *DynamicPartitionedTableAnalysisJob) ValidateAndPrepare(
 	sctx sessionctx.Context,
@@ -179,6 +180,10 @@ func (j *DynamicPartitionedTableAnalysisJob) ValidateAndPrepare(
 		callFailureHook(false)
 		return false, notPartitionedTable
 	}
+	if valid, failReason := j.skipLockedPartitions(sctx); !valid {
+		callFailureHook(false)
+		return false, failReason
+	}
 	partitionNames := make([]string, 0, len(j.PartitionIDs))
 	for _, partition := range partitionInfo.Definitions {
 		if _, ok := j.PartitionIDs[partition.ID]; ok {
@@ -224,10 +229,70 @@ func (j *DynamicPartitionedTableAnalysisJob) ValidateAndPrepare(
 			return false, failReason
 		}
 	}
+	if valid, needRetry, reason := validateTableConfig(tableInfo); !valid {
+		callFailureHook(needRetry)
+		return false, reason
+	}
+	partitionIDs := make([]int64, 0, len(j.PartitionIDs))
+	for partitionID := range j.PartitionIDs {
+		partitionIDs = append(partitionIDs, partitionID)
+	}
+	if within, reason := isWithinPlacementWindows(is.(infoschema.InfoSchema), tableInfo, partitionIDs, Now()); !within {
+		// Retry the job later, it can be analyzed once the window comes.
+		callFailureHook(true)
+		return false, reason
+	}
+	if inCoolDown, reason := IsInAutoAnalyzeCoolDown(sctx, tableInfo, j.SchemaName, j.GlobalTableName); inCoolDown {
+		callFailureHook(true)
+		return false, reason
+	}
 
 	return true, ""
 }
 
+// skipLockedPartitions removes the locked partitions from the job, so the other partitions can still be analyzed.
+// It returns false if the whole table is locked or there is nothing left to analyze.
+func (j *DynamicPartitionedTableAnalysisJob) skipLockedPartitions(sctx sessionctx.Context) (bool, string) {
+	tableIDs := make([]int64, 0, len(j.PartitionIDs)+1)
+	tableIDs = append(tableIDs, j.GlobalTableID)
+	for partitionID := range j.PartitionIDs {
+		tableIDs = append(tableIDs, partitionID)
+	}
+	for _, partitionIDs := range j.PartitionIndexIDs {
+		tableIDs = append(tableIDs, partitionIDs...)
+	}
+	lockedTables, err := getLockedTables(sctx, tableIDs...)
+	if err != nil {
+		return false, fmt.Sprintf("fail to query locked tables: %v", err)
+	}
+	if len(lockedTables) == 0 {
+		return true, ""
+	}
+	if _, ok := lockedTables[j.GlobalTableID]; ok {
+		return false, statsLocked
+	}
+	for partitionID := range lockedTables {
+		delete(j.PartitionIDs, partitionID)
+	}
+	for indexID, partitionIDs := range j.PartitionIndexIDs {
+		unlocked := make([]int64, 0, len(partitionIDs))
+		for _, partitionID := range partitionIDs {
+			if _, ok := lockedTables[partitionID]; !ok {
+				unlocked = append(unlocked, partitionID)
+			}
+		}
+		if len(unlocked) == 0 {
+			delete(j.PartitionIndexIDs, indexID)
+			continue
+		}
+		j.PartitionIndexIDs[indexID] = unlocked
+	}
+	if len(j.PartitionIDs) == 0 && len(j.PartitionIndexIDs) == 0 {
+		return false, statsLocked
+	}
+	return true, ""
+}
+
 // SetWeight sets the weight of the job.
 func (j *DynamicPartitionedTableAnalysisJob) SetWeight(weight float64) {
 	j.Weight = weight
@@ -250,6 +315,8 @@ func (j *DynamicPartitionedTableAnalysisJob) String() string {
 			"\tGlobal TableID: %d\n"+
 			"\tTableStatsVer: %d\n"+
 			"\tChangePercentage: %.6f\n"+
+			"\tShrinkPercentage: %.6f\n"+
+			"\tEstimationError: %.2f\n"+
 			"\tTableSize: %.2f\n"+
 			"\tLastAnalysisDuration: %s\n"+
 			"\tWeight: %.6f\n",
@@ -257,7 +324,7 @@ func (j *DynamicPartitionedTableAnalysisJob) String() string {
 		strings.Join(j.PartitionNames, ", "),
 		j.PartitionIndexNames,
 		j.SchemaName, j.GlobalTableName,
-		j.GlobalTableID, j.TableStatsVer, j.ChangePercentage,
+		j.GlobalTableID, j.TableStatsVer, j.ChangePercentage, j.ShrinkPercentage, j.EstimationError,
 		j.TableSize, j.LastAnalysisDuration, j.Weight,
 	)
 }
@@ -283,9 +350,9 @@ func (j *DynamicPartitionedTableAnalysisJob) analyzePartitions(
 			end = len(needAnalyzePartitionNames)
 		}
 
-		sql := getPartitionSQL("analyze table %n.%n partition", "", end-start)
+		sql := getPartitionSQL("analyze table %n.%n partition", genAnalyzeOptionsClause(j.GlobalTableID, j.TableStatsVer, j.TableSize), end-start)
 		params := append([]any{j.SchemaName, j.GlobalTableName}, needAnalyzePartitionNames[start:end]...)
-		success := exec.AutoAnalyze(sctx, statsHandle, sysProcTracker, j.TableStatsVer, sql, params...)
+		success := autoAnalyze(sctx, statsHandle, sysProcTracker, j.GetTableID(), j.TableStatsVer, sql, params...)
 		if !success {
 			return false
 		}
@@ -319,7 +386,7 @@ func (j *DynamicPartitionedTableAnalysisJob) analyzePartitionIndexes(
 			sql := getPartitionSQL("analyze table %n.%n partition", " index %n", end-start)
 			params := append([]any{j.SchemaName, j.GlobalTableName}, needAnalyzePartitionNames[start:end]...)
 			params = append(params, indexName)
-			success = exec.AutoAnalyze(sctx, statsHandle, sysProcTracker, j.TableStatsVer, sql, params...)
+			success = autoAnalyze(sctx, statsHandle, sysProcTracker, j.GetTableID(), j.TableStatsVer, sql, params...)
 			if !success {
 				return false
 			}
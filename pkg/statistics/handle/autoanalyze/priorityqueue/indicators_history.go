@@ -0,0 +1,162 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+)
+
+const (
+	// indicatorsSnapshotInterval is the interval to persist the indicators of all jobs in the queue.
+	indicatorsSnapshotInterval = time.Hour
+	// indicatorsHistoryRetention is how long the persisted indicators are kept.
+	indicatorsHistoryRetention = 7 * 24 * time.Hour
+	// indicatorsSnapshotBatchSize is the max number of rows written by one INSERT statement.
+	indicatorsSnapshotBatchSize = 1024
+)
+
+const insertIndicatorsHistoryPrefix = `INSERT IGNORE INTO mysql.analyze_indicators_history
+	(table_id, snapshot_time, job_type, change_percentage, table_size, last_analysis_duration, weight) VALUES `
+
+const deleteOutdatedIndicatorsHistorySQL = `DELETE FROM mysql.analyze_indicators_history
+	WHERE snapshot_time < CONVERT_TZ(%?, '+00:00', @@TIME_ZONE)`
+
+const selectIndicatorsHistorySQL = `SELECT
+		CONVERT_TZ(snapshot_time, @@TIME_ZONE, '+00:00'), change_percentage, table_size, last_analysis_duration, weight
+	FROM mysql.analyze_indicators_history
+	WHERE table_id = %? AND snapshot_time >= CONVERT_TZ(%?, '+00:00', @@TIME_ZONE)
+	ORDER BY snapshot_time`
+
+// IndicatorsSnapshot is a persisted snapshot of the indicators of a table.
+type IndicatorsSnapshot struct {
+	// SnapshotTime is the time when the snapshot was taken, in UTC.
+	SnapshotTime time.Time
+	Indicators
+	Weight float64
+}
+
+// indicatorsRecord is the in-memory form of a row of mysql.analyze_indicators_history.
+type indicatorsRecord struct {
+	jobType    string
+	indicators Indicators
+	tableID    int64
+	weight     float64
+}
+
+// SaveIndicatorsSnapshot writes the indicators of the given jobs into mysql.analyze_indicators_history
+// and removes the records that are older than the retention.
+func SaveIndicatorsSnapshot(sctx sessionctx.Context, jobs []AnalysisJob, snapshotTime time.Time) error {
+	return saveIndicatorsRecords(sctx, newIndicatorsRecords(jobs), snapshotTime)
+}
+
+func newIndicatorsRecords(jobs []AnalysisJob) []indicatorsRecord {
+	records := make([]indicatorsRecord, 0, len(jobs))
+	for _, job := range jobs {
+		records = append(records, indicatorsRecord{
+			tableID:    job.GetTableID(),
+			jobType:    job.AsJSON().Type,
+			indicators: job.GetIndicators(),
+			weight:     job.GetWeight(),
+		})
+	}
+	return records
+}
+
+func saveIndicatorsRecords(sctx sessionctx.Context, records []indicatorsRecord, snapshotTime time.Time) error {
+	snapshotTimeStr := snapshotTime.UTC().Format(types.TimeFSPFormat)
+	for i := 0; i < len(records); i += indicatorsSnapshotBatchSize {
+		end := min(i+indicatorsSnapshotBatchSize, len(records))
+		sql := new(strings.Builder)
+		sqlescape.MustFormatSQL(sql, insertIndicatorsHistoryPrefix)
+		for j := i; j < end; j++ {
+			r := records[j]
+			sqlescape.MustFormatSQL(
+				sql,
+				"(%?, CONVERT_TZ(%?, '+00:00', @@TIME_ZONE), %?, %?, %?, %?, %?)",
+				r.tableID,
+				snapshotTimeStr,
+				r.jobType,
+				r.indicators.ChangePercentage,
+				r.indicators.TableSize,
+				int64(r.indicators.LastAnalysisDuration.Seconds()),
+				r.weight,
+			)
+			if j < end-1 {
+				sqlescape.MustFormatSQL(sql, ",")
+			}
+		}
+		if _, _, err := statsutil.ExecRows(sctx, sql.String()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	expiredTime := snapshotTime.Add(-indicatorsHistoryRetention).UTC().Format(types.TimeFSPFormat)
+	_, _, err := statsutil.ExecRows(sctx, deleteOutdatedIndicatorsHistorySQL, expiredTime)
+	return errors.Trace(err)
+}
+
+// GetIndicatorsHistory returns the persisted indicators of the table since the given time.
+// The snapshots are ordered by the snapshot time.
+func GetIndicatorsHistory(sctx sessionctx.Context, tableID int64, since time.Time) ([]IndicatorsSnapshot, error) {
+	rows, _, err := statsutil.ExecRows(
+		sctx,
+		selectIndicatorsHistorySQL,
+		tableID,
+		since.UTC().Format(types.TimeFSPFormat),
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	snapshots := make([]IndicatorsSnapshot, 0, len(rows))
+	for _, row := range rows {
+		snapshotTime, err := row.GetTime(0).CoreTime().GoTime(time.UTC)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		snapshots = append(snapshots, IndicatorsSnapshot{
+			SnapshotTime: snapshotTime,
+			Indicators: Indicators{
+				ChangePercentage:     row.GetFloat64(1),
+				TableSize:            row.GetFloat64(2),
+				LastAnalysisDuration: time.Duration(row.GetInt64(3)) * time.Second,
+			},
+			Weight: row.GetFloat64(4),
+		})
+	}
+	return snapshots, nil
+}
+
+// CalculateChangeGrowthRate calculates how fast the change percentage grows per hour
+// between the first and the last snapshot.
+// It returns 0 if there are not enough snapshots to calculate a trend.
+func CalculateChangeGrowthRate(snapshots []IndicatorsSnapshot) float64 {
+	if len(snapshots) < 2 {
+		return 0
+	}
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	hours := last.SnapshotTime.Sub(first.SnapshotTime).Hours()
+	if hours <= 0 {
+		return 0
+	}
+	return (last.ChangePercentage - first.ChangePercentage) / hours
+}
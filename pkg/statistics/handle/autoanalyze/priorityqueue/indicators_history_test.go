@@ -0,0 +1,64 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndGetIndicatorsHistory(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	sctx := tk.Session().(sessionctx.Context)
+
+	job1 := priorityqueue.NewNonPartitionedTableAnalysisJob(1, nil, 2, 0.5, 100, time.Hour)
+	job1.SetWeight(1.5)
+	job2 := priorityqueue.NewNonPartitionedTableAnalysisJob(2, nil, 2, 0.3, 10, time.Minute)
+	job2.SetWeight(0.5)
+
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, priorityqueue.SaveIndicatorsSnapshot(sctx, []priorityqueue.AnalysisJob{job1, job2}, now.Add(-2*time.Hour)))
+	job1.SetIndicators(priorityqueue.Indicators{ChangePercentage: 0.9, TableSize: 120, LastAnalysisDuration: 3 * time.Hour})
+	require.NoError(t, priorityqueue.SaveIndicatorsSnapshot(sctx, []priorityqueue.AnalysisJob{job1}, now))
+	tk.MustQuery("select count(*) from mysql.analyze_indicators_history").Check(testkit.Rows("3"))
+
+	history, err := priorityqueue.GetIndicatorsHistory(sctx, 1, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.True(t, history[0].SnapshotTime.Equal(now.Add(-2*time.Hour)))
+	require.Equal(t, 0.5, history[0].ChangePercentage)
+	require.Equal(t, float64(100), history[0].TableSize)
+	require.Equal(t, time.Hour, history[0].LastAnalysisDuration)
+	require.Equal(t, 1.5, history[0].Weight)
+	require.True(t, history[1].SnapshotTime.Equal(now))
+	require.Equal(t, 0.9, history[1].ChangePercentage)
+	require.InDelta(t, 0.2, priorityqueue.CalculateChangeGrowthRate(history), 1e-9)
+
+	// Only the snapshots after the given time are returned.
+	history, err = priorityqueue.GetIndicatorsHistory(sctx, 1, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, float64(0), priorityqueue.CalculateChangeGrowthRate(history))
+
+	// Outdated snapshots are removed when a new snapshot is saved.
+	require.NoError(t, priorityqueue.SaveIndicatorsSnapshot(sctx, []priorityqueue.AnalysisJob{job2}, now.Add(8*24*time.Hour)))
+	tk.MustQuery("select table_id from mysql.analyze_indicators_history").Check(testkit.Rows("2"))
+}
@@ -80,6 +80,15 @@ const lastFailedDurationQueryForPartition = `
 	JOIN mysql.analyze_jobs aj ON aj.id = latest_failures.max_id;
 `
 
+// Auto analyze jobs are recorded with the `auto ` prefix in job_info, so the others are manual analyze jobs.
+// Both the table level and partition level jobs are considered, because a manual analyze on a partitioned table
+// only records the partition level jobs.
+const lastManualAnalysisDurationQuery = `
+	SELECT TIMESTAMPDIFF(SECOND, MAX(end_time), CURRENT_TIMESTAMP)
+	FROM mysql.analyze_jobs
+	WHERE table_schema = %? AND table_name = %? AND state = 'finished' AND fail_reason IS NULL AND job_info NOT LIKE 'auto %%'
+`
+
 // GetAverageAnalysisDuration returns the average duration of the last 5 successful analyses for each specified partition.
 // If there are no successful analyses, it returns 0.
 func GetAverageAnalysisDuration(
@@ -148,3 +157,20 @@ func GetLastFailedAnalysisDuration(
 
 	return time.Duration(lastFailedDuration) * time.Second, nil
 }
+
+// GetLastManualAnalysisDuration returns the duration since the last successful manual analysis of the table.
+// If there is no successful manual analysis, it returns NoRecord.
+func GetLastManualAnalysisDuration(
+	sctx sessionctx.Context,
+	schema, tableName string,
+) (time.Duration, error) {
+	rows, _, err := util.ExecRows(sctx, lastManualAnalysisDurationQuery, schema, tableName)
+	if err != nil {
+		return NoRecord, err
+	}
+
+	if len(rows) == 0 || rows[0].IsNull(0) {
+		return NoRecord, nil
+	}
+	return time.Duration(rows[0].GetInt64(0)) * time.Second, nil
+}
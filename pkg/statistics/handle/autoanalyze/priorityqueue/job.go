@@ -18,10 +18,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/sysproctrack"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics/handle/lockstats"
 	"github.com/pingcap/tidb/pkg/statistics/handle/logutil"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"go.uber.org/zap"
 )
 
@@ -37,10 +41,38 @@ type Indicators struct {
 	// Usually, the more the changed rows, the higher the priority.
 	// It is calculated by modifiedCount / last time analysis count.
 	ChangePercentage float64
+	// ShrinkPercentage is the percentage of the rows removed since the last analysis.
+	// Large deletes and TTL jobs shift the data distribution a lot once the deleted versions are GCed,
+	// so the queue should react to the shrinkage of the table, not only its growth.
+	// It is calculated by (deleted rows - inserted rows) / (current count + deleted rows - inserted rows), where the
+	// inserted and deleted rows are tracked in the stats meta, or by
+	// (last time analysis count - current count) / last time analysis count if they are not tracked.
+	ShrinkPercentage float64
+	// EstimationError is the geometric mean of the q-errors of the row count estimations on the table since the
+	// last analysis, which is collected from the executed statements. See statistics.CardinalityFeedback.
+	// It's 1 if the estimations are accurate, and 0 if there is no feedback.
+	EstimationError float64
+	// PseudoStatsQueries is the number of the executed statements estimated by the pseudo stats of the table, which
+	// is only counted if the table has never been analyzed. See statistics.PseudoStatsUsage.
+	// The newly created tables are analyzed soon after they are queried, instead of waiting for enough changes.
+	PseudoStatsQueries int64
+	// VerifiedDrift is how much the stats of the table drift from the data, which is verified by the stats probe
+	// since the last analysis. See statistics.VerifiedStatsDrift.
+	// Unlike the change percentage, it tells the stats are really stale, so it outranks the raw modify counts.
+	VerifiedDrift float64
+	// ErrorBudgetBurnRate is how fast the error budget of the table is burnt since the last analysis if the budget is
+	// violated, i.e. the fraction of the estimations whose q-errors exceed TARGET_QERROR divided by the fraction
+	// allowed by TARGET_QERROR_PERCENTILE. It's 0 if the table has no error budget or the budget is not violated.
+	ErrorBudgetBurnRate float64
 	// TableSize is the table size in rows * len(columns).
 	TableSize float64
 	// LastAnalysisDuration is the duration from the last analysis to now.
 	LastAnalysisDuration time.Duration
+	// PartitionExchanged is whether the data of the table or the partition is swapped by EXCHANGE PARTITION.
+	// The stats of the swapped data may be quite different from the rest of the partitioned table.
+	PartitionExchanged bool
+	// Truncated is whether the table or the partition is recreated by TRUNCATE TABLE and never analyzed since then.
+	Truncated bool
 }
 
 // SuccessJobHook is the successHook function that will be called after the job is completed.
@@ -57,6 +89,7 @@ type AnalysisJob interface {
 	// - Schema/table/partition doesn't exist
 	// - Table is not partitioned (for partition jobs)
 	// - Recent failed analysis (within 2x avg duration) to avoid queue blocking
+	// - Recent successful manual analysis (within the auto analyze cool-down)
 	ValidateAndPrepare(
 		sctx sessionctx.Context,
 	) (bool, string)
@@ -102,8 +135,22 @@ const (
 	tableNotExist       = "table does not exist"
 	notPartitionedTable = "table is not a partitioned table"
 	partitionNotExist   = "partition does not exist"
+	tableCacheSwitching = "table is switching its cache status"
+	statsTemplateShared = "table shares the stats of the representative tables of its stats template"
+	statsLocked         = "stats of the table or partitions are locked"
+	autoRecalcDisabled  = "auto analyze of the table is disabled by STATS_AUTO_RECALC=0"
 )
 
+// getLockedTables returns the tables or partitions whose stats are locked among the given IDs.
+// The lock status may be changed after the job is created, so we check it again before analyzing.
+func getLockedTables(sctx sessionctx.Context, tableIDs ...int64) (map[int64]struct{}, error) {
+	lockedTables, err := lockstats.QueryLockedTables(statsutil.StatsCtx, sctx)
+	if err != nil {
+		return nil, err
+	}
+	return lockstats.GetLockedTables(lockedTables, tableIDs...), nil
+}
+
 // isValidToAnalyze checks whether the table is valid to analyze.
 // It checks the last failed analysis duration and the average analysis duration.
 // If the last failed analysis duration is less than 2 times the average analysis duration,
@@ -184,6 +231,68 @@ func isValidToAnalyze(
 	return true, ""
 }
 
+// IsAutoRecalcDisabled checks whether auto analyze is disabled for the table by the table option STATS_AUTO_RECALC=0.
+// The tables can still be analyzed manually.
+func IsAutoRecalcDisabled(tblInfo *model.TableInfo) bool {
+	return tblInfo.StatsOptions != nil && !tblInfo.StatsOptions.AutoRecalc
+}
+
+// GetAutoAnalyzeCoolDown returns how long auto analyze should skip the table after a successful manual analyze.
+// The COOL_DOWN option of mysql.auto_analyze_config takes precedence over the table option AUTO_ANALYZE_COOL_DOWN,
+// which takes precedence over the global variable tidb_auto_analyze_cool_down.
+func GetAutoAnalyzeCoolDown(tblInfo *model.TableInfo) time.Duration {
+	if cfg := getTableConfig(tblInfo.ID); cfg != nil && cfg.CoolDown != nil {
+		return time.Duration(*cfg.CoolDown) * time.Minute
+	}
+	if tblInfo.StatsOptions != nil && tblInfo.StatsOptions.AutoAnalyzeCoolDown > 0 {
+		return time.Duration(tblInfo.StatsOptions.AutoAnalyzeCoolDown) * time.Minute
+	}
+	return time.Duration(variable.AutoAnalyzeCoolDown.Load()) * time.Minute
+}
+
+// IsInAutoAnalyzeCoolDown checks whether the table was manually analyzed within the auto analyze cool-down.
+// DBAs usually run controlled analyzes during maintenance, so we don't want to analyze the table again right after.
+// It returns (true, reason) if the table should be skipped.
+func IsInAutoAnalyzeCoolDown(
+	sctx sessionctx.Context,
+	tblInfo *model.TableInfo,
+	schema, table string,
+) (bool, string) {
+	coolDown := GetAutoAnalyzeCoolDown(tblInfo)
+	if coolDown <= 0 {
+		return false, ""
+	}
+	lastManualAnalysisDuration, err := GetLastManualAnalysisDuration(sctx, schema, table)
+	if err != nil {
+		logutil.SingletonStatsSamplerLogger().Warn(
+			"Fail to get last manual analysis duration",
+			zap.String("schema", schema),
+			zap.String("table", table),
+			zap.Error(err),
+		)
+		return true, fmt.Sprintf("fail to get last manual analysis duration: %v", err)
+	}
+	if lastManualAnalysisDuration != NoRecord && lastManualAnalysisDuration < coolDown {
+		logutil.SingletonStatsSamplerLogger().Info(
+			"Skip analysis because the table was manually analyzed within the cool-down",
+			zap.String("schema", schema),
+			zap.String("table", table),
+			zap.Duration("lastManualAnalysisDuration", lastManualAnalysisDuration),
+			zap.Duration("coolDown", coolDown),
+		)
+		return true, fmt.Sprintf("table was manually analyzed within the cool-down %v", coolDown)
+	}
+	return false, ""
+}
+
+// isCacheStatusSwitching checks whether the table is in the middle of `ALTER TABLE ... CACHE/NOCACHE`.
+// Cached tables are analyzed like normal tables, because ANALYZE always reads the underlying TiKV data
+// instead of the in-memory cache. But we should not analyze the table while its cache status is switching,
+// otherwise the analysis may race with the DDL and get failed.
+func isCacheStatusSwitching(tblInfo *model.TableInfo) bool {
+	return tblInfo.TableCacheStatusType == model.TableCacheStatusSwitching
+}
+
 // IsDynamicPartitionedTableAnalysisJob checks whether the job is a dynamic partitioned table analysis job.
 func IsDynamicPartitionedTableAnalysisJob(job AnalysisJob) bool {
 	_, ok := job.(*DynamicPartitionedTableAnalysisJob)
@@ -194,6 +303,11 @@ func IsDynamicPartitionedTableAnalysisJob(job AnalysisJob) bool {
 func asJSONIndicators(indicators Indicators) statstypes.IndicatorsJSON {
 	return statstypes.IndicatorsJSON{
 		ChangePercentage:     fmt.Sprintf("%.2f%%", indicators.ChangePercentage*100),
+		ShrinkPercentage:     fmt.Sprintf("%.2f%%", indicators.ShrinkPercentage*100),
+		EstimationError:      fmt.Sprintf("%.2f", indicators.EstimationError),
+		PseudoStatsQueries:   indicators.PseudoStatsQueries,
+		VerifiedDrift:        indicators.VerifiedDrift,
+		ErrorBudgetBurnRate:  indicators.ErrorBudgetBurnRate,
 		TableSize:            fmt.Sprintf("%.2f", indicators.TableSize),
 		LastAnalysisDuration: fmt.Sprintf("%v", indicators.LastAnalysisDuration),
 	}
@@ -19,9 +19,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/sysproctrack"
-	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/exec"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
 	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 )
@@ -97,7 +97,7 @@ func (j *NonPartitionedTableAnalysisJob) Analyze(
 		}
 	}()
 
-	return statsutil.CallWithSCtx(statsHandle.SPool(), func(sctx sessionctx.Context) error {
+	return statsutil.CallWithSCtx(statsHandle.PrioritizedSPool(statsutil.SessionPriorityLow), func(sctx sessionctx.Context) error {
 		switch j.getAnalyzeType() {
 		case analyzeTable:
 			success = j.analyzeTable(sctx, statsHandle, sysProcTracker)
@@ -127,7 +127,9 @@ func (j *NonPartitionedTableAnalysisJob) HasNewlyAddedIndex() bool {
 // For non-partitioned tables, it checks:
 // - Schema exists
 // - Table exists
+// - Table is not switching its cache status
 // - No recent failed analysis to avoid queue blocking
+// - Table does not share the stats of its stats template
 func (j *NonPartitionedTableAnalysisJob) ValidateAndPrepare(
 	sctx sessionctx.Context,
 ) (bool, string) {
@@ -148,6 +150,12 @@ func (j *NonPartitionedTableAnalysisJob) ValidateAndPrepare(
 		callFailureHook(false)
 		return false, schemaNotExist
 	}
+	// Cached tables can only be non-partitioned tables, so we only need to check it here.
+	if isCacheStatusSwitching(tableInfo) {
+		// The DDL is usually finished soon, so we need to retry it later.
+		callFailureHook(true)
+		return false, tableCacheSwitching
+	}
 	tableName := tableInfo.Name.O
 	indexNames := make([]string, 0, len(j.IndexIDs))
 	for _, index := range tableInfo.Indices {
@@ -167,6 +175,24 @@ func (j *NonPartitionedTableAnalysisJob) ValidateAndPrepare(
 		callFailureHook(true)
 		return false, failReason
 	}
+	if valid, needRetry, reason := validateTableConfig(tableInfo); !valid {
+		callFailureHook(needRetry)
+		return false, reason
+	}
+	if within, reason := isWithinPlacementWindows(is.(infoschema.InfoSchema), tableInfo, nil, Now()); !within {
+		// Retry the job later, it can be analyzed once the window comes.
+		callFailureHook(true)
+		return false, reason
+	}
+	if inCoolDown, reason := IsInAutoAnalyzeCoolDown(sctx, tableInfo, j.SchemaName, j.TableName); inCoolDown {
+		callFailureHook(true)
+		return false, reason
+	}
+	if _, shared := statsutil.GetStatsTemplateRepresentatives(is.(infoschema.InfoSchema), tableInfo); shared {
+		// The table keeps sharing the stats until the stats template changes, so no need to retry.
+		callFailureHook(false)
+		return false, statsTemplateShared
+	}
 
 	return true, ""
 }
@@ -202,13 +228,15 @@ func (j *NonPartitionedTableAnalysisJob) String() string {
 			"\tTableID: %d\n"+
 			"\tTableStatsVer: %d\n"+
 			"\tChangePercentage: %.6f\n"+
+			"\tShrinkPercentage: %.6f\n"+
+			"\tEstimationError: %.2f\n"+
 			"\tTableSize: %.2f\n"+
 			"\tLastAnalysisDuration: %v\n"+
 			"\tWeight: %.6f\n",
 		j.getAnalyzeType(),
 		strings.Join(j.IndexNames, ", "),
 		j.SchemaName, j.TableName, j.TableID, j.TableStatsVer,
-		j.ChangePercentage, j.TableSize, j.LastAnalysisDuration, j.Weight,
+		j.ChangePercentage, j.ShrinkPercentage, j.EstimationError, j.TableSize, j.LastAnalysisDuration, j.Weight,
 	)
 }
 func (j *NonPartitionedTableAnalysisJob) getAnalyzeType() analyzeType {
@@ -224,12 +252,12 @@ func (j *NonPartitionedTableAnalysisJob) analyzeTable(
 	sysProcTracker sysproctrack.Tracker,
 ) bool {
 	sql, params := j.GenSQLForAnalyzeTable()
-	return exec.AutoAnalyze(sctx, statsHandle, sysProcTracker, j.TableStatsVer, sql, params...)
+	return autoAnalyze(sctx, statsHandle, sysProcTracker, j.GetTableID(), j.TableStatsVer, sql, params...)
 }
 
 // GenSQLForAnalyzeTable generates the SQL for analyzing the specified table.
 func (j *NonPartitionedTableAnalysisJob) GenSQLForAnalyzeTable() (string, []any) {
-	sql := "analyze table %n.%n"
+	sql := "analyze table %n.%n" + genAnalyzeOptionsClause(j.TableID, j.TableStatsVer, j.TableSize)
 	params := []any{j.SchemaName, j.TableName}
 
 	return sql, params
@@ -249,7 +277,7 @@ func (j *NonPartitionedTableAnalysisJob) analyzeIndexes(
 	if analyzeVersion == 1 {
 		for _, index := range j.IndexNames {
 			sql, params := j.GenSQLForAnalyzeIndex(index)
-			if !exec.AutoAnalyze(sctx, statsHandle, sysProcTracker, j.TableStatsVer, sql, params...) {
+			if !autoAnalyze(sctx, statsHandle, sysProcTracker, j.GetTableID(), j.TableStatsVer, sql, params...) {
 				return false
 			}
 		}
@@ -260,7 +288,7 @@ func (j *NonPartitionedTableAnalysisJob) analyzeIndexes(
 	// Therefore, to avoid redundancy, we prevent multiple analyses of the same table.
 	firstIndex := j.IndexNames[0]
 	sql, params := j.GenSQLForAnalyzeIndex(firstIndex)
-	return exec.AutoAnalyze(sctx, statsHandle, sysProcTracker, j.TableStatsVer, sql, params...)
+	return autoAnalyze(sctx, statsHandle, sysProcTracker, j.GetTableID(), j.TableStatsVer, sql, params...)
 }
 
 // GenSQLForAnalyzeIndex generates the SQL for analyzing the specified index.
@@ -16,11 +16,14 @@ package priorityqueue_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/session"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/stretchr/testify/require"
@@ -41,6 +44,50 @@ func TestGenSQLForNonPartitionedTable(t *testing.T) {
 	require.Equal(t, expectedParams, params)
 }
 
+func TestGenSQLForNonPartitionedTableWithPredicateColumns(t *testing.T) {
+	variable.AutoAnalyzeColumnChoice.Store(model.PredicateColumns.String())
+	defer variable.AutoAnalyzeColumnChoice.Store(variable.DefTiDBAutoAnalyzeColumnChoice)
+	job := &priorityqueue.NonPartitionedTableAnalysisJob{
+		SchemaName: "test_schema",
+		TableName:  "test_table",
+	}
+
+	expectedSQL := "analyze table %n.%n predicate columns"
+	expectedParams := []any{"test_schema", "test_table"}
+
+	sql, params := job.GenSQLForAnalyzeTable()
+
+	require.Equal(t, expectedSQL, sql)
+	require.Equal(t, expectedParams, params)
+}
+
+func TestGenSQLForNonPartitionedTableWithQuickAnalyze(t *testing.T) {
+	variable.AutoAnalyzeQuickSizeThreshold.Store(1000)
+	defer variable.AutoAnalyzeQuickSizeThreshold.Store(variable.DefTiDBAutoAnalyzeQuickSizeThreshold)
+	job := &priorityqueue.NonPartitionedTableAnalysisJob{
+		SchemaName:    "test_schema",
+		TableName:     "test_table",
+		TableStatsVer: 2,
+		Indicators: priorityqueue.Indicators{
+			TableSize: 1001,
+		},
+	}
+
+	sql, params := job.GenSQLForAnalyzeTable()
+	require.Equal(t, "analyze table %n.%n quick", sql)
+	require.Equal(t, []any{"test_schema", "test_table"}, params)
+
+	// The tables not larger than the threshold are analyzed as usual.
+	job.TableSize = 1000
+	sql, _ = job.GenSQLForAnalyzeTable()
+	require.Equal(t, "analyze table %n.%n", sql)
+	// The quick analyze is not supported by the version 1 stats.
+	job.TableSize = 1001
+	job.TableStatsVer = 1
+	sql, _ = job.GenSQLForAnalyzeTable()
+	require.Equal(t, "analyze table %n.%n", sql)
+}
+
 func TestGenSQLForNonPartitionedTableIndex(t *testing.T) {
 	job := &priorityqueue.NonPartitionedTableAnalysisJob{
 		SchemaName: "test_schema",
@@ -205,3 +252,86 @@ func TestValidateAndPrepareWhenOnlyHasFailedAnalysisRecords(t *testing.T) {
 	require.False(t, valid)
 	require.Equal(t, "last failed analysis duration is less than 30m0s", failReason)
 }
+
+func TestValidateAndPrepareOutOfPlacementWindow(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create schema example_schema")
+	tk.MustExec("use example_schema")
+	tk.MustExec(`create placement policy cold constraints="[+disk=hdd]"`)
+	tk.MustExec("create table example_table1 (a int, b int, index idx(a)) placement policy = cold")
+	tk.MustExec("create table example_table2 (a int, b int, index idx(a))")
+	defer tk.MustExec("set @@global.tidb_auto_analyze_placement_windows = default")
+	now := time.Now().UTC()
+	tk.MustExec(fmt.Sprintf("set @@global.tidb_auto_analyze_placement_windows = 'disk=hdd: start=%s, end=%s'",
+		now.Add(time.Hour).Format("15:04 -0700"), now.Add(2*time.Hour).Format("15:04 -0700")))
+	sctx := tk.Session().(sessionctx.Context)
+	newJob := func(tableName string) *priorityqueue.NonPartitionedTableAnalysisJob {
+		tableInfo, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("example_schema"), model.NewCIStr(tableName))
+		require.NoError(t, err)
+		return &priorityqueue.NonPartitionedTableAnalysisJob{
+			TableID: tableInfo.Meta().ID,
+			Weight:  2,
+		}
+	}
+
+	// The table placed on the cold stores is only analyzed within their window.
+	valid, failReason := newJob("example_table1").ValidateAndPrepare(sctx)
+	require.False(t, valid)
+	require.Equal(t, "current time is out of the auto analyze window of the stores with label disk=hdd where the table is placed", failReason)
+	// The tables placed elsewhere are not affected.
+	valid, _ = newJob("example_table2").ValidateAndPrepare(sctx)
+	require.True(t, valid)
+
+	tk.MustExec(fmt.Sprintf("set @@global.tidb_auto_analyze_placement_windows = 'disk=hdd: start=%s, end=%s'",
+		now.Add(-time.Hour).Format("15:04 -0700"), now.Add(time.Hour).Format("15:04 -0700")))
+	valid, failReason = newJob("example_table1").ValidateAndPrepare(sctx)
+	require.True(t, valid)
+	require.Equal(t, "", failReason)
+	tk.MustGetErrMsg("set @@global.tidb_auto_analyze_placement_windows = 'disk=hdd: start=01:00 +0000'",
+		"[variable:1231]Variable 'tidb_auto_analyze_placement_windows' can't be set to the value of 'disk=hdd: start=01:00 +0000'")
+}
+
+func TestValidateAndPrepareInAutoAnalyzeCoolDown(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create schema example_schema")
+	tk.MustExec("use example_schema")
+	tk.MustExec("create table example_table1 (a int, b int, index idx(a)) auto_analyze_cool_down = 60")
+	tableInfo, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("example_schema"), model.NewCIStr("example_table1"))
+	require.NoError(t, err)
+	job := &priorityqueue.NonPartitionedTableAnalysisJob{
+		TableID: tableInfo.Meta().ID,
+		Weight:  2,
+	}
+	sctx := tk.Session().(sessionctx.Context)
+	valid, failReason := job.ValidateAndPrepare(sctx)
+	require.True(t, valid)
+	require.Equal(t, "", failReason)
+
+	// Auto analyze doesn't start a cool-down.
+	tk.MustExec(`INSERT INTO mysql.analyze_jobs (table_schema, table_name, job_info, start_time, end_time, state, instance)
+		VALUES ('example_schema', 'example_table1', 'auto analyze table', now(), now(), 'finished', 'example_instance')`)
+	valid, _ = job.ValidateAndPrepare(sctx)
+	require.True(t, valid)
+
+	// Manually analyzed recently.
+	tk.MustExec(`INSERT INTO mysql.analyze_jobs (table_schema, table_name, job_info, start_time, end_time, state, instance)
+		VALUES ('example_schema', 'example_table1', 'analyze table', now() - interval 10 minute, now() - interval 10 minute, 'finished', 'example_instance')`)
+	valid, failReason = job.ValidateAndPrepare(sctx)
+	require.False(t, valid)
+	require.Equal(t, "table was manually analyzed within the cool-down 1h0m0s", failReason)
+
+	// The global variable is used if the table option is not set.
+	tk.MustExec("alter table example_table1 auto_analyze_cool_down = 0")
+	valid, _ = job.ValidateAndPrepare(sctx)
+	require.True(t, valid)
+	tk.MustExec("set global tidb_auto_analyze_cool_down = 5")
+	defer tk.MustExec("set global tidb_auto_analyze_cool_down = default")
+	valid, _ = job.ValidateAndPrepare(sctx)
+	require.True(t, valid)
+	tk.MustExec("set global tidb_auto_analyze_cool_down = 20")
+	valid, failReason = job.ValidateAndPrepare(sctx)
+	require.False(t, valid)
+	require.Equal(t, "table was manually analyzed within the cool-down 20m0s", failReason)
+}
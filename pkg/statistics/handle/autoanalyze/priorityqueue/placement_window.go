@@ -0,0 +1,68 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/ddl/placement"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	pd "github.com/tikv/pd/client/http"
+)
+
+const outOfPlacementWindow = "current time is out of the auto analyze window of the stores with label %s=%s where the table is placed"
+
+// isWithinPlacementWindows checks whether the current time is within the auto analyze windows of the stores where
+// the table or the partitions are placed by the placement rules, see tidb_auto_analyze_placement_windows.
+// The partitions without their own placement rules follow the rules of the table, so the table is always checked.
+// It returns the reason if the current time is out of any of the windows.
+func isWithinPlacementWindows(is infoschema.InfoSchema, tblInfo *model.TableInfo, partitionIDs []int64, now time.Time) (bool, string) {
+	windows := variable.AutoAnalyzePlacementWindowsValue.Load()
+	if windows == nil || len(windows.Windows) == 0 {
+		return true, ""
+	}
+	physicalIDs := append([]int64{tblInfo.ID}, partitionIDs...)
+	for _, id := range physicalIDs {
+		bundle, ok := is.PlacementBundleByPhysicalTableID(id)
+		if !ok {
+			continue
+		}
+		for _, window := range windows.Windows {
+			if !isPlacedOnLabel(bundle, window.Key, window.Value) {
+				continue
+			}
+			if !NewAutoAnalysisTimeWindow(window.Start, window.End).IsWithinTimeWindow(now) {
+				return false, fmt.Sprintf(outOfPlacementWindow, window.Key, window.Value)
+			}
+		}
+	}
+	return true, ""
+}
+
+// isPlacedOnLabel checks whether any of the replicas are constrained to the stores with the label by the bundle.
+func isPlacedOnLabel(bundle *placement.Bundle, key, value string) bool {
+	for _, rule := range bundle.Rules {
+		for _, constraint := range rule.LabelConstraints {
+			if constraint.Op == pd.In && constraint.Key == key && slices.Contains(constraint.Values, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
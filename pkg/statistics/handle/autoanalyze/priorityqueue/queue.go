@@ -105,6 +105,18 @@ type AnalysisPriorityQueue struct {
 		//    particularly for tables with new indexes created during this process.
 		// We will requeue the must retry jobs periodically.
 		mustRetryJobs map[int64]struct{}
+		// truncatedTables is a map to store the tables and partitions recreated by TRUNCATE TABLE,
+		// whose stats are reset to empty. They are analyzed with a high priority once the first rows are written,
+		// and removed from the map when their jobs are created.
+		// In the dynamic prune mode, the partitions are analyzed along with the whole table as usual.
+		truncatedTables map[int64]struct{}
+		// lastStatsProbeTime is the time of the last run of the stats probe.
+		lastStatsProbeTime time.Time
+		// finishedDebts are the stale bytes cleared by the jobs finished in the throughput window, in the order of
+		// their finish time. See RefreshAnalyzeDebt.
+		finishedDebts []finishedDebt
+		// initializedTime is the time when the queue is initialized.
+		initializedTime time.Time
 		// initialized is a flag to check if the queue is initialized.
 		initialized bool
 	}
@@ -156,11 +168,17 @@ func (pq *AnalysisPriorityQueue) Initialize() error {
 	pq.syncFields.cancel = cancel
 	pq.syncFields.runningJobs = make(map[int64]struct{})
 	pq.syncFields.mustRetryJobs = make(map[int64]struct{})
+	pq.syncFields.truncatedTables = make(map[int64]struct{})
+	pq.syncFields.finishedDebts = nil
+	pq.syncFields.initializedTime = Now()
 	pq.syncFields.initialized = true
 	pq.syncFields.mu.Unlock()
 
 	// Start a goroutine to maintain the priority queue.
-	pq.wg.Run(pq.run)
+	// Under the virtual clock, the tests maintain the priority queue instead.
+	if !isVirtualClock() {
+		pq.wg.Run(pq.run)
+	}
 	return nil
 }
 
@@ -212,18 +230,19 @@ func (pq *AnalysisPriorityQueue) fetchAllTablesAndBuildAnalysisJobs() error {
 		if err != nil {
 			return err
 		}
-		// Get current timestamp from the session context.
-		currentTs, err := statsutil.GetStartTS(sctx)
+		// Get current timestamp of the scheduling.
+		currentTs, err := currentTS(sctx)
 		if err != nil {
 			return err
 		}
 
 		jobFactory := NewAnalysisJobFactory(sctx, autoAnalyzeRatio, currentTs)
+		RefreshTableConfigs(sctx)
 
 		dbs := is.AllSchemaNames()
 		for _, db := range dbs {
-			// Ignore the memory and system database.
-			if util.IsMemOrSysDB(db.L) {
+			// Ignore the memory and system database, except the system tables opted in to auto analyze.
+			if !statsutil.IsAutoAnalyzeSchema(db.L) {
 				continue
 			}
 
@@ -239,7 +258,11 @@ func (pq *AnalysisPriorityQueue) fetchAllTablesAndBuildAnalysisJobs() error {
 					continue
 				}
 
-				if tblInfo.IsView() {
+				if tblInfo.IsView() || !statsutil.IsAutoAnalyzeTable(db.L, tblInfo.Name.L) {
+					continue
+				}
+				// The table disables auto analyze by STATS_AUTO_RECALC=0 or mysql.auto_analyze_config.
+				if disabled, _ := IsAutoAnalyzeDisabled(tblInfo); disabled {
 					continue
 				}
 
@@ -309,6 +332,12 @@ func (pq *AnalysisPriorityQueue) run() {
 	defer timeRefreshInterval.Stop()
 	mustRetryJobRequeueInterval := time.NewTicker(mustRetryJobRequeueInterval)
 	defer mustRetryJobRequeueInterval.Stop()
+	indicatorsSnapshotInterval := time.NewTicker(indicatorsSnapshotInterval)
+	defer indicatorsSnapshotInterval.Stop()
+	statsProbeCheckInterval := time.NewTicker(statsProbeCheckInterval)
+	defer statsProbeCheckInterval.Stop()
+	analyzeDebtRefreshInterval := time.NewTicker(analyzeDebtRefreshInterval)
+	defer analyzeDebtRefreshInterval.Stop()
 
 	for {
 		select {
@@ -324,6 +353,13 @@ func (pq *AnalysisPriorityQueue) run() {
 		case <-mustRetryJobRequeueInterval.C:
 			queueSamplerLogger().Info("Start to requeue must retry jobs")
 			pq.RequeueMustRetryJobs()
+		case <-indicatorsSnapshotInterval.C:
+			queueSamplerLogger().Info("Start to persist indicators of jobs")
+			pq.PersistIndicators()
+		case <-statsProbeCheckInterval.C:
+			pq.maybeProbeStatsDrift()
+		case <-analyzeDebtRefreshInterval.C:
+			pq.RefreshAnalyzeDebt()
 		}
 	}
 }
@@ -356,10 +392,15 @@ func (pq *AnalysisPriorityQueue) ProcessDMLChanges() {
 		}
 		values := pq.statsHandle.Values()
 		lastFetchTimestamp := pq.syncFields.lastDMLUpdateFetchTimestamp
+		is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
 		for _, value := range values {
 			// We only process the tables that have been updated.
 			// So here we only need to process the tables whose version is greater than the last fetch timestamp.
-			if value.Version > lastFetchTimestamp {
+			// The never analyzed tables are also processed once they are queried, although they may not be updated,
+			// and so are the tables whose stats are verified to drift by the stats probe or whose error budgets are
+			// violated.
+			if value.Version > lastFetchTimestamp || pq.isNewlyQueriedWithoutLock(value) ||
+				pq.hasNewVerifiedDriftWithoutLock(value) || pq.hasNewErrorBudgetViolationWithoutLock(is, value) {
 				err := pq.processTableStats(sctx, value, parameters, lockedTables)
 				if err != nil {
 					statslogutil.StatsLogger().Error(
@@ -382,6 +423,45 @@ func (pq *AnalysisPriorityQueue) ProcessDMLChanges() {
 	}
 }
 
+// isNewlyQueriedWithoutLock returns whether the table has never been analyzed and is queried, but has no job yet.
+// Note: Please hold the lock before calling this function.
+func (pq *AnalysisPriorityQueue) isNewlyQueriedWithoutLock(stats *statistics.Table) bool {
+	if stats.Pseudo || stats.RealtimeCount <= 0 || !isQueriedWithPseudoStats(stats) {
+		return false
+	}
+	_, ok, _ := pq.syncFields.inner.getByKey(stats.PhysicalID)
+	return !ok
+}
+
+// hasNewVerifiedDriftWithoutLock returns whether the stats of the table are verified to drift by the stats probe, but
+// the drift is not reflected in its job yet.
+// Note: Please hold the lock before calling this function.
+func (pq *AnalysisPriorityQueue) hasNewVerifiedDriftWithoutLock(stats *statistics.Table) bool {
+	if stats.Pseudo || !stats.IsAnalyzed() || statistics.VerifiedStatsDrift.Get(stats.PhysicalID, stats.LastAnalyzeVersion) == 0 {
+		return false
+	}
+	job, ok, _ := pq.syncFields.inner.getByKey(stats.PhysicalID)
+	return !ok || job.GetIndicators().VerifiedDrift == 0
+}
+
+// hasNewErrorBudgetViolationWithoutLock returns whether the error budget of the table is violated, but the violation
+// is not reflected in its job yet. The budget is burnt by the queries, which may not come with any DML changes.
+// Note: Please hold the lock before calling this function.
+func (pq *AnalysisPriorityQueue) hasNewErrorBudgetViolationWithoutLock(is infoschema.InfoSchema, stats *statistics.Table) bool {
+	if !hasErrorBudget() || stats.Pseudo || !stats.IsAnalyzed() {
+		return false
+	}
+	tbl, ok := pq.statsHandle.TableInfoByID(is, stats.PhysicalID)
+	if !ok {
+		return false
+	}
+	if _, burnRate := calculateErrorBudgetBurnRate(tbl.Meta().ID, stats); burnRate == 0 {
+		return false
+	}
+	job, ok, _ := pq.syncFields.inner.getByKey(stats.PhysicalID)
+	return !ok || job.GetIndicators().ErrorBudgetBurnRate == 0
+}
+
 // Note: Please hold the lock before calling this function.
 func (pq *AnalysisPriorityQueue) processTableStats(
 	sctx sessionctx.Context,
@@ -390,17 +470,18 @@ func (pq *AnalysisPriorityQueue) processTableStats(
 	lockedTables map[int64]struct{},
 ) error {
 	// Check if the table is eligible for analysis first to avoid unnecessary work.
-	if !stats.IsEligibleForAnalysis() {
+	if !isEligibleForAnalysis(stats, pq.syncFields.truncatedTables) {
 		return nil
 	}
 
 	autoAnalyzeRatio := exec.ParseAutoAnalyzeRatio(parameters[variable.TiDBAutoAnalyzeRatio])
-	// Get current timestamp from the session context.
-	currentTs, err := statsutil.GetStartTS(sctx)
+	// Get current timestamp of the scheduling.
+	currentTs, err := currentTS(sctx)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	jobFactory := NewAnalysisJobFactory(sctx, autoAnalyzeRatio, currentTs)
+	jobFactory.truncatedTables = pq.syncFields.truncatedTables
 	is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
 	pruneMode := variable.PartitionPruneMode(sctx.GetSessionVars().PartitionPruneMode.Load())
 
@@ -412,6 +493,12 @@ func (pq *AnalysisPriorityQueue) processTableStats(
 	job, ok, _ := pq.syncFields.inner.getByKey(stats.PhysicalID)
 	if !ok {
 		job = pq.tryCreateJob(is, stats, pruneMode, jobFactory, lockedTables)
+		if _, truncated := pq.syncFields.truncatedTables[stats.PhysicalID]; truncated && job != nil {
+			indicators := job.GetIndicators()
+			indicators.Truncated = true
+			job.SetIndicators(indicators)
+			delete(pq.syncFields.truncatedTables, stats.PhysicalID)
+		}
 	} else {
 		// Skip analysis if the table is locked.
 		// Dynamic partitioned tables are managed in the tryCreateJob branch.
@@ -430,7 +517,20 @@ func (pq *AnalysisPriorityQueue) processTableStats(
 			}
 			return nil
 		}
-		job = pq.tryUpdateJob(is, stats, job, jobFactory)
+		newJob := pq.tryUpdateJob(is, stats, job, jobFactory, lockedTables)
+		if newJob == nil {
+			// Nothing needs to be analyzed anymore, e.g. all the changed partitions are locked.
+			err := pq.syncFields.inner.delete(job)
+			if err != nil {
+				statslogutil.StatsLogger().Error(
+					"Failed to delete job from priority queue",
+					zap.Error(err),
+					zap.String("job", job.String()),
+				)
+			}
+			return nil
+		}
+		job = newJob
 	}
 	return pq.pushWithoutLock(job)
 }
@@ -454,6 +554,13 @@ func (pq *AnalysisPriorityQueue) tryCreateJob(
 		return nil
 	}
 	tableMeta := tableInfo.Meta()
+	// The system tables may be removed from tidb_auto_analyze_system_tables after their stats are collected.
+	if db, ok := infoschema.SchemaByTable(is, tableMeta); !ok || !statsutil.IsAutoAnalyzeTable(db.Name.L, tableMeta.Name.L) {
+		return nil
+	}
+	if disabled, _ := IsAutoAnalyzeDisabled(tableMeta); disabled {
+		return nil
+	}
 	partitionedTable := tableMeta.GetPartitionInfo()
 	if partitionedTable == nil {
 		// If the table is locked, we do not analyze it.
@@ -487,6 +594,10 @@ func (pq *AnalysisPriorityQueue) tryCreateJob(
 			if _, ok := lockedTables[partitionDef.ID]; ok {
 				return nil
 			}
+			// The older partitions only keep the rollups of their stats.
+			if statistics.IsRolledUpPartition(tableMeta, partitionDef.ID) {
+				return nil
+			}
 			job = jobFactory.CreateStaticPartitionAnalysisJob(
 				tableMeta,
 				partitionDef.ID,
@@ -534,6 +645,7 @@ func (pq *AnalysisPriorityQueue) tryUpdateJob(
 	stats *statistics.Table,
 	oldJob AnalysisJob,
 	jobFactory *AnalysisJobFactory,
+	lockedTables map[int64]struct{},
 ) AnalysisJob {
 	if stats == nil {
 		return nil
@@ -555,7 +667,13 @@ func (pq *AnalysisPriorityQueue) tryUpdateJob(
 		}
 		tableMeta := tableInfo.Meta()
 		partitionedTable := tableMeta.GetPartitionInfo()
-		partitionDefs := partitionedTable.Definitions
+		// Only analyze the partitions that have not been locked.
+		partitionDefs := make([]model.PartitionDefinition, 0, len(partitionedTable.Definitions))
+		for _, def := range partitionedTable.Definitions {
+			if _, ok := lockedTables[def.ID]; !ok {
+				partitionDefs = append(partitionDefs, def)
+			}
+		}
 		partitionStats := GetPartitionStats(pq.statsHandle, tableMeta, partitionDefs)
 		return jobFactory.CreateDynamicPartitionedTableAnalysisJob(
 			tableMeta,
@@ -563,8 +681,24 @@ func (pq *AnalysisPriorityQueue) tryUpdateJob(
 			partitionStats,
 		)
 	}
+	// The quick refresh job is recreated, since the table may need a full analysis now, or no refresh anymore.
+	if IsQuickRefreshAnalysisJob(oldJob) {
+		tableInfo, ok := pq.statsHandle.TableInfoByID(is, stats.PhysicalID)
+		if !ok {
+			return nil
+		}
+		return jobFactory.CreateNonPartitionedTableAnalysisJob(tableInfo.Meta(), stats)
+	}
 	// Otherwise, we update the indicators of the job.
-	indicators.ChangePercentage = jobFactory.CalculateChangePercentage(stats)
+	indicators.ChangePercentage, indicators.ShrinkPercentage, indicators.ErrorBudgetBurnRate = jobFactory.applyErrorBudget(
+		logicalTableID(oldJob),
+		stats,
+		jobFactory.CalculateChangePercentage(stats),
+		jobFactory.CalculateShrinkPercentage(stats),
+	)
+	indicators.EstimationError = jobFactory.CalculateEstimationError(stats)
+	indicators.PseudoStatsQueries = jobFactory.CalculatePseudoStatsQueries(stats)
+	indicators.VerifiedDrift = jobFactory.CalculateVerifiedDrift(stats)
 	indicators.TableSize = jobFactory.CalculateTableSize(stats)
 	oldJob.SetIndicators(indicators)
 	return oldJob
@@ -630,7 +764,7 @@ func (pq *AnalysisPriorityQueue) RefreshLastAnalysisDuration() {
 			}
 		}()
 		jobs := pq.syncFields.inner.list()
-		currentTs, err := statsutil.GetStartTS(sctx)
+		currentTs, err := currentTS(sctx)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -669,6 +803,32 @@ func (pq *AnalysisPriorityQueue) RefreshLastAnalysisDuration() {
 	}
 }
 
+// PersistIndicators persists the indicators of all jobs in the priority queue to mysql.analyze_indicators_history.
+// Note: This function is thread-safe.
+func (pq *AnalysisPriorityQueue) PersistIndicators() {
+	pq.syncFields.mu.RLock()
+	if !pq.syncFields.initialized {
+		pq.syncFields.mu.RUnlock()
+		return
+	}
+	// Copy the indicators under the lock, so we don't block the queue while writing to the storage.
+	records := newIndicatorsRecords(pq.syncFields.inner.list())
+	pq.syncFields.mu.RUnlock()
+
+	if err := statsutil.CallWithSCtx(pq.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		start := time.Now()
+		defer func() {
+			duration := time.Since(start)
+			if duration > slowLogThreshold {
+				queueSamplerLogger().Info("Indicators persisted", zap.Duration("duration", duration), zap.Int("jobs", len(records)))
+			}
+		}()
+		return saveIndicatorsRecords(sctx, records, start)
+	}); err != nil {
+		statslogutil.StatsLogger().Error("Failed to persist indicators", zap.Error(err))
+	}
+}
+
 // GetRunningJobs returns the running jobs.
 // Note: This function is thread-safe.
 // Exported for testing.
@@ -694,7 +854,95 @@ func (pq *AnalysisPriorityQueue) Push(job AnalysisJob) error {
 
 	return pq.pushWithoutLock(job)
 }
+
+// Requeue pushes the popped job back into the queue with its weight, e.g. the job is not dispatched since it's
+// staggered, so it's popped again in the next rounds.
+// Note: This function is thread-safe.
+func (pq *AnalysisPriorityQueue) Requeue(job AnalysisJob) error {
+	pq.syncFields.mu.Lock()
+	defer pq.syncFields.mu.Unlock()
+	if !pq.syncFields.initialized {
+		return errors.New(notInitializedErrMsg)
+	}
+
+	delete(pq.syncFields.runningJobs, job.GetTableID())
+	return pq.syncFields.inner.addOrUpdate(job)
+}
+
+// EnqueueJob creates the analysis jobs for the given table or partition and pushes them into the queue,
+// no matter whether the stats are outdated. If the weight is positive, it is used as the priority of
+// the jobs instead of the calculated one. Note that the weight may be recalculated once the queue
+// refreshes the job on new DML changes. If the analyze defaults are not nil, the jobs are analyzed with
+// them until they are finished, even if the jobs are recreated in the meantime.
+// Note: This function is thread-safe.
+func (pq *AnalysisPriorityQueue) EnqueueJob(physicalID int64, weight float64, analyzeDefaults *variable.AnalyzeDefaults) error {
+	if !pq.IsInitialized() {
+		return errors.New(notInitializedErrMsg)
+	}
+	var jobs []AnalysisJob
+	err := statsutil.CallWithSCtx(pq.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		var err error
+		jobs, err = CreateAnalysisJobsForTable(sctx, pq.statsHandle, physicalID)
+		return err
+	}, statsutil.FlagWrapTxn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	pq.syncFields.mu.Lock()
+	defer pq.syncFields.mu.Unlock()
+	if !pq.syncFields.initialized {
+		return errors.New(notInitializedErrMsg)
+	}
+	for _, job := range jobs {
+		if err := pq.pushWithWeightWithoutLock(job, weight); err != nil {
+			return errors.Trace(err)
+		}
+		if analyzeDefaults != nil {
+			setInheritedAnalyzeDefaults(job.GetTableID(), *analyzeDefaults)
+		}
+	}
+	return nil
+}
+
+// RemoveJobs removes the pending analysis jobs of the given tables or partitions from the queue,
+// and returns the number of the removed jobs. The running jobs are not affected. The tables are not
+// blocked from the queue, they are pushed into the queue again once they have new DML changes.
+// Note: This function is thread-safe.
+func (pq *AnalysisPriorityQueue) RemoveJobs(physicalIDs []int64) (int, error) {
+	pq.syncFields.mu.Lock()
+	defer pq.syncFields.mu.Unlock()
+	if !pq.syncFields.initialized {
+		return 0, errors.New(notInitializedErrMsg)
+	}
+
+	removed := 0
+	for _, physicalID := range physicalIDs {
+		// Forget the pending retry as well, otherwise the job would be requeued soon.
+		delete(pq.syncFields.mustRetryJobs, physicalID)
+		clearInheritedAnalyzeDefaults(physicalID)
+		job, ok, err := pq.syncFields.inner.getByKey(physicalID)
+		if err != nil {
+			return removed, errors.Trace(err)
+		}
+		if !ok {
+			continue
+		}
+		if err := pq.syncFields.inner.delete(job); err != nil {
+			return removed, errors.Trace(err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
 func (pq *AnalysisPriorityQueue) pushWithoutLock(job AnalysisJob) error {
+	return pq.pushWithWeightWithoutLock(job, 0)
+}
+
+// pushWithWeightWithoutLock pushes the job with the given weight.
+// If the weight is not positive, the weight is calculated by the priority calculator.
+func (pq *AnalysisPriorityQueue) pushWithWeightWithoutLock(job AnalysisJob, weight float64) error {
 	if job == nil {
 		return nil
 	}
@@ -718,11 +966,16 @@ func (pq *AnalysisPriorityQueue) pushWithoutLock(job AnalysisJob) error {
 		// Because potentially the job can be analyzed in the near future.
 		// For example, the table has new indexes added when the job is running.
 		pq.syncFields.mustRetryJobs[job.GetTableID()] = struct{}{}
+		LogDecision(job, DecisionSkip, "job is running")
 		return nil
 	}
 	// We apply a penalty to larger tables, which can potentially result in a negative weight.
 	// To prevent this, we filter out any negative weights. Under normal circumstances, table sizes should not be negative.
-	weight := pq.calculator.CalculateWeight(job)
+	if weight > 0 {
+		job.SetWeight(weight)
+		return pq.addOrUpdateWithoutLock(job)
+	}
+	weight = pq.calculator.CalculateWeight(job)
 	if weight <= 0 {
 		statslogutil.SingletonStatsSamplerLogger().Warn(
 			"Table gets a negative weight",
@@ -731,7 +984,16 @@ func (pq *AnalysisPriorityQueue) pushWithoutLock(job AnalysisJob) error {
 		)
 	}
 	job.SetWeight(weight)
-	return pq.syncFields.inner.addOrUpdate(job)
+	return pq.addOrUpdateWithoutLock(job)
+}
+
+// addOrUpdateWithoutLock adds the job into the heap or updates it, and logs the decision of enqueuing it.
+func (pq *AnalysisPriorityQueue) addOrUpdateWithoutLock(job AnalysisJob) error {
+	if err := pq.syncFields.inner.addOrUpdate(job); err != nil {
+		return err
+	}
+	LogDecision(job, DecisionEnqueue, "")
+	return nil
 }
 
 // Pop pops a job from the priority queue and marks it as running.
@@ -748,11 +1010,14 @@ func (pq *AnalysisPriorityQueue) Pop() (AnalysisJob, error) {
 		return nil, errors.Trace(err)
 	}
 	pq.syncFields.runningJobs[job.GetTableID()] = struct{}{}
+	debt := newDebtJob(job)
 
 	job.RegisterSuccessHook(func(j AnalysisJob) {
+		pq.recordFinishedDebt(debt)
 		pq.syncFields.mu.Lock()
 		defer pq.syncFields.mu.Unlock()
 		delete(pq.syncFields.runningJobs, j.GetTableID())
+		clearInheritedAnalyzeDefaults(j.GetTableID())
 	})
 	job.RegisterFailureHook(func(j AnalysisJob, needRetry bool) {
 		pq.syncFields.mu.Lock()
@@ -761,6 +1026,9 @@ func (pq *AnalysisPriorityQueue) Pop() (AnalysisJob, error) {
 		delete(pq.syncFields.runningJobs, j.GetTableID())
 		if needRetry {
 			pq.syncFields.mustRetryJobs[j.GetTableID()] = struct{}{}
+		} else {
+			// The retried job keeps the inherited analyze defaults.
+			clearInheritedAnalyzeDefaults(j.GetTableID())
 		}
 	})
 	return job, nil
@@ -855,6 +1123,9 @@ func (pq *AnalysisPriorityQueue) Close() {
 	pq.syncFields.inner = nil
 	pq.syncFields.runningJobs = nil
 	pq.syncFields.mustRetryJobs = nil
+	pq.syncFields.truncatedTables = nil
+	pq.syncFields.finishedDebts = nil
 	pq.syncFields.lastDMLUpdateFetchTimestamp = 0
 	pq.syncFields.cancel = nil
+	resetAnalyzeDebtMetrics()
 }
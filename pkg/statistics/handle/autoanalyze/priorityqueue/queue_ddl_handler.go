@@ -33,6 +33,15 @@ import (
 	"go.uber.org/zap"
 )
 
+// modifyColumnRefreshWeight is the weight of the quick refresh jobs for the tables whose column types are changed
+// losslessly. It's lower than the weights of the tables which need analysis, since the converted stats are still valid.
+const modifyColumnRefreshWeight = 0.01
+
+// recoveredTableAnalyzeWeight is the weight of the analysis jobs which verify the stats restored for the tables
+// recovered by FLASHBACK TABLE or RECOVER TABLE. It's lower than the weights of the tables which need analysis, since
+// the restored stats are usually still valid.
+const recoveredTableAnalyzeWeight = 0.01
+
 // HandleDDLEvent handles DDL events for the priority queue.
 func (pq *AnalysisPriorityQueue) HandleDDLEvent(_ context.Context, sctx sessionctx.Context, event *notifier.SchemaChangeEvent) (err error) {
 	pq.syncFields.mu.Lock()
@@ -55,10 +64,16 @@ func (pq *AnalysisPriorityQueue) HandleDDLEvent(_ context.Context, sctx sessionc
 	switch event.GetType() {
 	case model.ActionAddIndex:
 		err = pq.handleAddIndexEvent(sctx, event)
+	case model.ActionModifyColumn:
+		err = pq.handleModifyColumnEvent(sctx, event)
+	case model.ActionMultiSchemaChange:
+		err = pq.handleMultiSchemaChangeEvent(sctx, event)
 	case model.ActionTruncateTable:
 		err = pq.handleTruncateTableEvent(sctx, event)
 	case model.ActionDropTable:
 		err = pq.handleDropTableEvent(sctx, event)
+	case model.ActionRecoverTable:
+		err = pq.handleRecoverTableEvent(sctx, event)
 	case model.ActionTruncateTablePartition:
 		err = pq.handleTruncateTablePartitionEvent(sctx, event)
 	case model.ActionDropTablePartition:
@@ -82,6 +97,8 @@ func (pq *AnalysisPriorityQueue) HandleDDLEvent(_ context.Context, sctx sessionc
 
 // getAndDeleteJob tries to get a job from the priority queue and delete it if it exists.
 func (pq *AnalysisPriorityQueue) getAndDeleteJob(tableID int64) error {
+	// The table is dropped or its data is replaced, so it's no longer the one recreated by TRUNCATE TABLE.
+	delete(pq.syncFields.truncatedTables, tableID)
 	job, ok, err := pq.syncFields.inner.getByKey(tableID)
 	if err != nil {
 		statslogutil.StatsLogger().Error(
@@ -200,11 +217,85 @@ func (pq *AnalysisPriorityQueue) handleAddIndexEvent(
 	return pq.pushWithoutLock(job)
 }
 
+// handleModifyColumnEvent enqueues a low-priority quick refresh job for the table whose column types are changed
+// losslessly, since the stats of the columns are converted to the new types instead of being invalidated.
+func (pq *AnalysisPriorityQueue) handleModifyColumnEvent(
+	sctx sessionctx.Context,
+	event *notifier.SchemaChangeEvent,
+) error {
+	tableInfo, modifiedColumns, oldColumns := event.GetModifyColumnInfo()
+	// The column types can't be changed with data reorganization on the partitioned tables.
+	if tableInfo.GetPartitionInfo() != nil || len(modifiedColumns) != len(oldColumns) {
+		return nil
+	}
+	for i, col := range modifiedColumns {
+		if !statistics.IsLosslessColumnTypeChange(&oldColumns[i].FieldType, &col.FieldType) {
+			return nil
+		}
+	}
+	// The table which already needs analysis keeps its job.
+	_, ok, err := pq.syncFields.inner.getByKey(tableInfo.ID)
+	if err != nil || ok {
+		return errors.Trace(err)
+	}
+	lockedTables, err := lockstats.QueryLockedTables(statsutil.StatsCtx, sctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := lockedTables[tableInfo.ID]; ok {
+		return nil
+	}
+	stats := pq.statsHandle.GetTableStatsForAutoAnalyze(tableInfo)
+	if stats == nil || !stats.IsAnalyzed() {
+		return nil
+	}
+	currentTs, err := statsutil.GetStartTS(sctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	parameters := exec.GetAutoAnalyzeParameters(sctx)
+	autoAnalyzeRatio := exec.ParseAutoAnalyzeRatio(parameters[variable.TiDBAutoAnalyzeRatio])
+	jobFactory := NewAnalysisJobFactory(sctx, autoAnalyzeRatio, currentTs)
+	job := NewQuickRefreshAnalysisJob(
+		tableInfo.ID,
+		stats.LastAnalyzeVersion,
+		0,
+		jobFactory.CalculateTableSize(stats),
+		jobFactory.GetTableLastAnalyzeDuration(stats),
+	)
+	return pq.pushWithWeightWithoutLock(job, modifyColumnRefreshWeight)
+}
+
+// handleMultiSchemaChangeEvent enqueues at most one job for the table changed by a multi-schema change. The job is
+// recreated with the final table info if any index is added, otherwise the lossless column type changes may enqueue
+// a quick refresh job.
+func (pq *AnalysisPriorityQueue) handleMultiSchemaChangeEvent(
+	sctx sessionctx.Context,
+	event *notifier.SchemaChangeEvent,
+) error {
+	tableInfo, subEvents := event.GetMultiSchemaChangeInfo()
+	for _, subEvent := range subEvents {
+		if subEvent.GetType() == model.ActionAddIndex {
+			return pq.recreateAndPushJobForTable(sctx, tableInfo)
+		}
+	}
+	for _, subEvent := range subEvents {
+		if subEvent.GetType() != model.ActionModifyColumn {
+			continue
+		}
+		// The table which already has a job is skipped, so the quick refresh job is enqueued only once.
+		if err := pq.handleModifyColumnEvent(sctx, subEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (pq *AnalysisPriorityQueue) handleTruncateTableEvent(
 	_ sessionctx.Context,
 	event *notifier.SchemaChangeEvent,
 ) error {
-	_, droppedTableInfo := event.GetTruncateTableInfo()
+	newTableInfo, droppedTableInfo := event.GetTruncateTableInfo()
 
 	// For non-partitioned tables or dynamic partitioned tables.
 	err := pq.getAndDeleteJob(droppedTableInfo.ID)
@@ -223,6 +314,16 @@ func (pq *AnalysisPriorityQueue) handleTruncateTableEvent(
 		}
 	}
 
+	// The stats of the new table are reset to empty, so it's regarded as never analyzed,
+	// and analyzed with a high priority once the first rows are written.
+	if newPartitionInfo := newTableInfo.GetPartitionInfo(); newPartitionInfo != nil {
+		for _, def := range newPartitionInfo.Definitions {
+			pq.syncFields.truncatedTables[def.ID] = struct{}{}
+		}
+	} else {
+		pq.syncFields.truncatedTables[newTableInfo.ID] = struct{}{}
+	}
+
 	return nil
 }
 
@@ -252,6 +353,51 @@ func (pq *AnalysisPriorityQueue) handleDropTableEvent(
 	return nil
 }
 
+// handleRecoverTableEvent enqueues a low-priority analysis job for the recovered table, which verifies the stats
+// restored from the time when the table was dropped.
+func (pq *AnalysisPriorityQueue) handleRecoverTableEvent(
+	sctx sessionctx.Context,
+	event *notifier.SchemaChangeEvent,
+) error {
+	tableInfo := event.GetRecoverTableInfo()
+	if disabled, _ := IsAutoAnalyzeDisabled(tableInfo); disabled {
+		return nil
+	}
+	lockedTables, err := lockstats.QueryLockedTables(statsutil.StatsCtx, sctx)
+	if err != nil {
+		return err
+	}
+	tableStatsVer := sctx.GetSessionVars().AnalyzeVersion
+	partitionInfo := tableInfo.GetPartitionInfo()
+	if partitionInfo == nil {
+		if _, ok := lockedTables[tableInfo.ID]; ok {
+			return nil
+		}
+		job := NewNonPartitionedTableAnalysisJob(tableInfo.ID, nil, tableStatsVer, 0, 0, 0)
+		return pq.pushWithWeightWithoutLock(job, recoveredTableAnalyzeWeight)
+	}
+	pruneMode := variable.PartitionPruneMode(sctx.GetSessionVars().PartitionPruneMode.Load())
+	partitionIDs := make(map[int64]struct{}, len(partitionInfo.Definitions))
+	for _, def := range partitionInfo.Definitions {
+		if _, ok := lockedTables[def.ID]; ok {
+			continue
+		}
+		if pruneMode == variable.Static {
+			job := NewStaticPartitionTableAnalysisJob(tableInfo.ID, def.ID, nil, tableStatsVer, 0, 0, 0)
+			if err := pq.pushWithWeightWithoutLock(job, recoveredTableAnalyzeWeight); err != nil {
+				return err
+			}
+			continue
+		}
+		partitionIDs[def.ID] = struct{}{}
+	}
+	if len(partitionIDs) == 0 {
+		return nil
+	}
+	job := NewDynamicPartitionedTableAnalysisJob(tableInfo.ID, partitionIDs, nil, tableStatsVer, 0, 0, 0)
+	return pq.pushWithWeightWithoutLock(job, recoveredTableAnalyzeWeight)
+}
+
 func (pq *AnalysisPriorityQueue) handleTruncateTablePartitionEvent(
 	sctx sessionctx.Context,
 	event *notifier.SchemaChangeEvent,
@@ -333,9 +479,34 @@ func (pq *AnalysisPriorityQueue) handleExchangeTablePartitionEvent(
 	// Note: We should use the ID of the partitioned table to get the new table info after the exchange.
 	tblInfo, ok := pq.statsHandle.TableInfoByID(is, partInfo.Definitions[0].ID)
 	if ok {
-		return pq.recreateAndPushJobForTable(sctx, tblInfo.Meta())
+		if err := pq.recreateAndPushJobForTable(sctx, tblInfo.Meta()); err != nil {
+			return err
+		}
 	}
 
+	// The physical IDs are swapped by the exchange, so the partition has the ID of the original table now,
+	// and vice versa.
+	return pq.markPartitionExchanged(globalTableInfo.ID, nonPartTableInfo.ID, partInfo.Definitions[0].ID)
+}
+
+// markPartitionExchanged marks the jobs of the tables and partitions swapped by EXCHANGE PARTITION,
+// which raises their priorities.
+func (pq *AnalysisPriorityQueue) markPartitionExchanged(ids ...int64) error {
+	for _, id := range ids {
+		job, ok, err := pq.syncFields.inner.getByKey(id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			continue
+		}
+		indicators := job.GetIndicators()
+		indicators.PartitionExchanged = true
+		job.SetIndicators(indicators)
+		if err := pq.pushWithoutLock(job); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return nil
 }
 
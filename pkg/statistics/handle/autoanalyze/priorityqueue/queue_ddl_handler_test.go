@@ -951,3 +951,96 @@ func TestAddIndexTriggerAutoAnalyzeWithStatsVersion1(t *testing.T) {
 	require.True(t, tableStats.GetIdx(2).IsAnalyzed())
 	require.True(t, tableStats.GetIdx(3).IsAnalyzed())
 }
+
+func TestModifyColumnLosslessly(t *testing.T) {
+	store, do := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t (a int, b varchar(10))")
+	testKit.MustExec("insert into t values (1, '1'), (2, '2'), (3, '3')")
+	h := do.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	testKit.MustExec("analyze table t")
+	require.NoError(t, h.Update(context.Background(), do.InfoSchema()))
+	tbl, err := do.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := tbl.Meta()
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(h)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+	isEmpty, err := pq.IsEmpty()
+	require.NoError(t, err)
+	require.True(t, isEmpty)
+
+	ctx := context.Background()
+	sctx := testKit.Session().(sessionctx.Context)
+	// VARCHAR to INT is not lossless, the table is left to the DML changes.
+	testKit.MustExec("alter table t modify column b int")
+	modifyColumnEvent := findEvent(h.DDLEventCh(), model.ActionModifyColumn)
+	require.NoError(t, h.HandleDDLEvent(modifyColumnEvent))
+	require.NoError(t, pq.HandleDDLEvent(ctx, sctx, modifyColumnEvent))
+	isEmpty, err = pq.IsEmpty()
+	require.NoError(t, err)
+	require.True(t, isEmpty)
+
+	// INT to DECIMAL is lossless, a low-priority quick refresh job is pushed.
+	testKit.MustExec("alter table t modify column a decimal(20, 2)")
+	modifyColumnEvent = findEvent(h.DDLEventCh(), model.ActionModifyColumn)
+	require.NoError(t, h.HandleDDLEvent(modifyColumnEvent))
+	require.NoError(t, statsutil.CallWithSCtx(h.SPool(), func(sctx sessionctx.Context) error {
+		return pq.HandleDDLEvent(ctx, sctx, modifyColumnEvent)
+	}, statsutil.FlagWrapTxn))
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tableInfo.ID, job.GetTableID())
+	require.True(t, priorityqueue.IsQuickRefreshAnalysisJob(job))
+	require.Equal(t, 0.01, job.GetWeight())
+}
+
+func TestTruncateTableTriggerAutoAnalyze(t *testing.T) {
+	store, do := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t (c1 int, c2 int, index idx(c1, c2))")
+	testKit.MustExec("insert into t values (1,2),(2,2),(6,2),(11,2),(16,2)")
+	h := do.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	testKit.MustExec("analyze table t")
+	require.NoError(t, h.Update(context.Background(), do.InfoSchema()))
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(h)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+	isEmpty, err := pq.IsEmpty()
+	require.NoError(t, err)
+	require.True(t, isEmpty)
+
+	testKit.MustExec("truncate table t")
+	truncateTableEvent := findEvent(h.DDLEventCh(), model.ActionTruncateTable)
+	require.NoError(t, h.HandleDDLEvent(truncateTableEvent))
+	ctx := context.Background()
+	sctx := testKit.Session().(sessionctx.Context)
+	require.NoError(t, pq.HandleDDLEvent(ctx, sctx, truncateTableEvent))
+	tbl, err := do.InfoSchema().TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := tbl.Meta()
+
+	// Nothing is written yet.
+	require.NoError(t, h.Update(ctx, do.InfoSchema()))
+	pq.ProcessDMLChanges()
+	isEmpty, err = pq.IsEmpty()
+	require.NoError(t, err)
+	require.True(t, isEmpty)
+
+	// The first batch of writes triggers the analysis, although the table is smaller than the min count.
+	testKit.MustExec("insert into t values (1,2),(2,2)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(ctx, do.InfoSchema()))
+	pq.ProcessDMLChanges()
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tableInfo.ID, job.GetTableID())
+	require.True(t, job.GetIndicators().Truncated)
+	require.Equal(t, priorityqueue.EventTruncateTable, priorityqueue.NewPriorityCalculator().GetSpecialEvent(job))
+}
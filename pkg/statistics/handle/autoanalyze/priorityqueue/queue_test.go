@@ -16,14 +16,18 @@ package priorityqueue_test
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/pingcap/tidb/pkg/meta/model"
 	pmodel "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
 	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/pingcap/tidb/pkg/testkit/testfailpoint"
 	"github.com/stretchr/testify/require"
 )
 
@@ -60,6 +64,16 @@ func TestCallAPIBeforeInitialize(t *testing.T) {
 		require.Error(t, err)
 		require.Nil(t, job)
 	})
+
+	t.Run("EnqueueJob", func(t *testing.T) {
+		err := pq.EnqueueJob(1, 0, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("RemoveJobs", func(t *testing.T) {
+		_, err := pq.RemoveJobs([]int64{1})
+		require.Error(t, err)
+	})
 }
 
 func TestAnalysisPriorityQueue(t *testing.T) {
@@ -465,6 +479,118 @@ func TestProcessDMLChangesWithLockedTables(t *testing.T) {
 	require.Equal(t, 2, l)
 }
 
+func TestProcessDMLChangesWithQueriedUnanalyzedTables(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	handle := dom.StatsHandle()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("insert into t1 values (1)")
+	tk.MustExec("insert into t2 values (1)")
+	statistics.PseudoStatsUsage.Reset()
+	defer statistics.PseudoStatsUsage.Reset()
+
+	ctx := context.Background()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+	// The tables are too small to be analyzed by the change percentage.
+	l, err := pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 0, l)
+
+	// Query t2, which is estimated by the pseudo stats.
+	tk.MustQuery("select * from t2 where a > 0").Check(testkit.Rows("1"))
+	tk.MustQuery("select * from t2 where a < 10").Check(testkit.Rows("1"))
+	tbl2, err := dom.InfoSchema().TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t2"))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), statistics.PseudoStatsUsage.Get(tbl2.Meta().ID))
+
+	// t2 is enqueued although its stats are not updated.
+	pq.ProcessDMLChanges()
+	l, err = pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 1, l)
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tbl2.Meta().ID, job.GetTableID())
+	require.Equal(t, int64(2), job.GetIndicators().PseudoStatsQueries)
+
+	// The usage is dropped once t2 is analyzed.
+	tk.MustExec("analyze table t2")
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+	statsTbl, ok := handle.Get(tbl2.Meta().ID)
+	require.True(t, ok)
+	factory := priorityqueue.NewAnalysisJobFactory(tk.Session().(sessionctx.Context), 0.5, 0)
+	require.Zero(t, factory.CalculatePseudoStatsQueries(statsTbl))
+	require.Zero(t, statistics.PseudoStatsUsage.Get(tbl2.Meta().ID))
+}
+
+func TestProbeStatsDrift(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	handle := dom.StatsHandle()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int, index ib(b))")
+	values := make([]string, 0, 1000)
+	for i := 1; i <= 1000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t")
+	// The new rows are far beyond the range of the stats, although they are too few to be analyzed.
+	tk.MustExec("insert into t values (5001, 5001), (5002, 5002)")
+	statistics.CardinalityFeedback.Reset()
+	defer statistics.CardinalityFeedback.Reset()
+	statistics.VerifiedStatsDrift.Reset()
+	defer statistics.VerifiedStatsDrift.Reset()
+
+	ctx := context.Background()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+	// Load the histograms of the columns.
+	tk.MustQuery("select count(*) from t where a > 0 and b > 0").Check(testkit.Rows("1002"))
+	tbl, err := dom.InfoSchema().TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableID := tbl.Meta().ID
+	statistics.CardinalityFeedback.Record(tableID, 1, 1, time.Now())
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+	l, err := pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 0, l)
+
+	pq.ProbeStatsDrift()
+	statsTbl, ok := handle.Get(tableID)
+	require.True(t, ok)
+	require.Equal(t, 1.0, statistics.VerifiedStatsDrift.Get(tableID, statsTbl.LastAnalyzeVersion))
+
+	// t is enqueued although its changes are too few.
+	pq.ProcessDMLChanges()
+	l, err = pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 1, l)
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tableID, job.GetTableID())
+	require.Equal(t, 1.0, job.GetIndicators().VerifiedDrift)
+
+	// The drift is dropped once t is analyzed.
+	tk.MustExec("analyze table t")
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+	statsTbl, ok = handle.Get(tableID)
+	require.True(t, ok)
+	factory := priorityqueue.NewAnalysisJobFactory(tk.Session().(sessionctx.Context), 0.5, 0)
+	require.Zero(t, factory.CalculateVerifiedDrift(statsTbl))
+}
+
 func TestProcessDMLChangesWithLockedPartitionsAndDynamicPruneMode(t *testing.T) {
 	ctx := context.Background()
 	store, dom := testkit.CreateMockStoreAndDomain(t)
@@ -526,6 +652,71 @@ func TestProcessDMLChangesWithLockedPartitionsAndDynamicPruneMode(t *testing.T)
 	require.Equal(t, tableID, job.GetTableID())
 }
 
+func TestProcessDMLChangesWithSomeLockedPartitionsAndDynamicPruneMode(t *testing.T) {
+	ctx := context.Background()
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	handle := dom.StatsHandle()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	tk.MustExec("insert into t1 values (1), (11)")
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+	tk.MustExec("analyze table t1")
+	tk.MustExec("set global tidb_partition_prune_mode = 'dynamic'")
+	statistics.AutoAnalyzeMinCnt = 0
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = 1000
+	}()
+
+	// Insert more rows into both partitions.
+	tk.MustExec("insert into t1 values (2), (3), (4), (5), (12), (13), (14), (15)")
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+
+	schema := pmodel.NewCIStr("test")
+	tbl, err := dom.InfoSchema().TableByName(ctx, schema, pmodel.NewCIStr("t1"))
+	require.NoError(t, err)
+	tableID := tbl.Meta().ID
+	p0 := tbl.Meta().Partition.Definitions[0].ID
+	p1 := tbl.Meta().Partition.Definitions[1].ID
+
+	// Both partitions need to be analyzed.
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tableID, job.GetTableID())
+	require.Equal(t, map[int64]struct{}{p0: {}, p1: {}}, job.(*priorityqueue.DynamicPartitionedTableAnalysisJob).PartitionIDs)
+
+	// Lock partition p0, the rest of the table is still analyzed.
+	tk.MustExec("lock stats t1 partition p0")
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+	pq.ProcessDMLChanges()
+	job, err = pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tableID, job.GetTableID())
+	require.Equal(t, map[int64]struct{}{p1: {}}, job.(*priorityqueue.DynamicPartitionedTableAnalysisJob).PartitionIDs)
+
+	// Lock partition p1 after the job is created, the job is skipped before analyzing.
+	tk.MustExec("lock stats t1 partition p1")
+	sctx := tk.Session().(sessionctx.Context)
+	valid, failReason := job.ValidateAndPrepare(sctx)
+	require.False(t, valid)
+	require.Equal(t, "stats of the table or partitions are locked", failReason)
+
+	// Unlock partition p0, only p0 needs to be analyzed.
+	tk.MustExec("unlock stats t1 partition p0")
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+	pq.ProcessDMLChanges()
+	job, err = pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tableID, job.GetTableID())
+	require.Equal(t, map[int64]struct{}{p0: {}}, job.(*priorityqueue.DynamicPartitionedTableAnalysisJob).PartitionIDs)
+}
+
 func TestProcessDMLChangesWithLockedPartitionsAndStaticPruneMode(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	handle := dom.StatsHandle()
@@ -608,3 +799,434 @@ func TestPQCanBeClosedAndReInitialized(t *testing.T) {
 	// Check if the priority queue is initialized.
 	require.True(t, pq.IsInitialized())
 }
+
+func TestEnqueueJob(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	tk.MustExec("create table t3 (a int)")
+	tk.MustExec("insert into t1 values (1)")
+	tk.MustExec("insert into t2 values (1), (11)")
+	ctx := context.Background()
+	handle := dom.StatsHandle()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t1, t2")
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+	// All the tables are analyzed or too small, so no jobs are created by the queue itself.
+	isEmpty, err := pq.IsEmpty()
+	require.NoError(t, err)
+	require.True(t, isEmpty)
+
+	is := dom.InfoSchema()
+	tbl1, err := is.TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t1"))
+	require.NoError(t, err)
+	tbl2, err := is.TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t2"))
+	require.NoError(t, err)
+	tbl3, err := is.TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t3"))
+	require.NoError(t, err)
+
+	// The job is enqueued even if the stats are not outdated.
+	require.NoError(t, pq.EnqueueJob(tbl1.Meta().ID, 0, nil))
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tbl1.Meta().ID, job.GetTableID())
+
+	// The specified weight is used as the priority.
+	require.NoError(t, pq.EnqueueJob(tbl3.Meta().ID, 100, nil))
+	job, err = pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tbl3.Meta().ID, job.GetTableID())
+	require.Equal(t, float64(100), job.GetWeight())
+
+	// The whole table is analyzed for the partition in dynamic prune mode.
+	partitionID := tbl2.Meta().GetPartitionInfo().Definitions[0].ID
+	require.NoError(t, pq.EnqueueJob(partitionID, 0, nil))
+	l, err := pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 3, l)
+	snapshot, err := pq.Snapshot()
+	require.NoError(t, err)
+	tableIDs := make([]int64, 0, len(snapshot.CurrentJobs))
+	for _, job := range snapshot.CurrentJobs {
+		tableIDs = append(tableIDs, job.TableID)
+	}
+	require.ElementsMatch(t, []int64{tbl1.Meta().ID, tbl2.Meta().ID, tbl3.Meta().ID}, tableIDs)
+
+	// Locked and nonexistent tables are rejected.
+	tk.MustExec("lock stats t3")
+	require.ErrorContains(t, pq.EnqueueJob(tbl3.Meta().ID, 0, nil), "is locked")
+	require.ErrorContains(t, pq.EnqueueJob(-1, 0, nil), "not found")
+}
+
+func TestEnqueueJobWithInheritedAnalyzeDefaults(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("insert into t values (1), (1), (1), (2), (2), (3), (3), (4), (5), (6), (7)")
+	handle := dom.StatsHandle()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+
+	tk.MustExec("set @@tidb_analyze_default_buckets = 2, @@tidb_analyze_default_topn = 1, @@tidb_analyze_default_column_choice = 'all'")
+	defaults := tk.Session().GetSessionVars().AnalyzeDefaults
+	tblInfo := dom.MustGetTableInfo(t, "test", "t")
+	require.NoError(t, pq.EnqueueJob(tblInfo.ID, 0, &defaults))
+	job, err := pq.Pop()
+	require.NoError(t, err)
+	require.Equal(t, tblInfo.ID, job.GetTableID())
+	valid, _ := job.ValidateAndPrepare(tk.Session())
+	require.True(t, valid)
+	require.NoError(t, job.Analyze(handle, dom.SysProcTracker()))
+	// The job is analyzed with the default analyze options of the session.
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_buckets where table_id = %d and is_index = 0", tblInfo.ID)).Check(testkit.Rows("2"))
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_top_n where table_id = %d and is_index = 0", tblInfo.ID)).Check(testkit.Rows("1"))
+
+	// The jobs enqueued without the analyze defaults don't inherit them.
+	tk.MustExec("set global tidb_analyze_column_options = 'ALL'")
+	defer tk.MustExec("set global tidb_analyze_column_options = 'PREDICATE'")
+	require.NoError(t, pq.EnqueueJob(tblInfo.ID, 0, nil))
+	job, err = pq.Pop()
+	require.NoError(t, err)
+	valid, _ = job.ValidateAndPrepare(tk.Session())
+	require.True(t, valid)
+	require.NoError(t, job.Analyze(handle, dom.SysProcTracker()))
+	// All the values are kept in the TopN with the default options.
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_top_n where table_id = %d and is_index = 0", tblInfo.ID)).Check(testkit.Rows("7"))
+}
+
+func TestRemoveJobs(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	ctx := context.Background()
+	handle := dom.StatsHandle()
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+
+	is := dom.InfoSchema()
+	tbl1, err := is.TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t1"))
+	require.NoError(t, err)
+	tbl2, err := is.TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t2"))
+	require.NoError(t, err)
+	require.NoError(t, pq.EnqueueJob(tbl1.Meta().ID, 0, nil))
+	require.NoError(t, pq.EnqueueJob(tbl2.Meta().ID, 0, nil))
+
+	removed, err := pq.RemoveJobs([]int64{tbl1.Meta().ID, -1})
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+	l, err := pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 1, l)
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tbl2.Meta().ID, job.GetTableID())
+
+	// The removed table can be pushed into the queue again.
+	require.NoError(t, pq.EnqueueJob(tbl1.Meta().ID, 0, nil))
+	l, err = pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 2, l)
+}
+
+func TestAutoAnalyzeSystemTables(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	handle := dom.StatsHandle()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set global tidb_auto_analyze_system_tables = 'mysql.opt_rule_blacklist'")
+	defer tk.MustExec("set global tidb_auto_analyze_system_tables = ''")
+	tk.MustExec("insert into mysql.opt_rule_blacklist values ('topn_push_down')")
+	tk.MustExec("insert into mysql.expr_pushdown_blacklist values ('abs', 'tikv', 'for test')")
+	statistics.AutoAnalyzeMinCnt = 0
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = 1000
+	}()
+
+	ctx := context.Background()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+
+	// Only the opted-in system table is auto analyzed.
+	tbl, err := dom.InfoSchema().TableByName(ctx, pmodel.NewCIStr("mysql"), pmodel.NewCIStr("opt_rule_blacklist"))
+	require.NoError(t, err)
+	l, err := pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 1, l)
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tbl.Meta().ID, job.GetTableID())
+}
+
+func TestStatsAutoRecalcTableOption(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	handle := dom.StatsHandle()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int) stats_auto_recalc = 0")
+	tk.MustExec("create table t2 (a int) stats_auto_recalc = default")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustExec("insert into t1 values (1)")
+	tk.MustExec("insert into t2 values (1)")
+	statistics.AutoAnalyzeMinCnt = 0
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = 1000
+	}()
+
+	ctx := context.Background()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+
+	// The table with STATS_AUTO_RECALC=0 is not queued.
+	tbl2, err := dom.InfoSchema().TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t2"))
+	require.NoError(t, err)
+	l, err := pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 1, l)
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tbl2.Meta().ID, job.GetTableID())
+
+	// The queued job is skipped once the option is changed.
+	tk.MustExec("alter table t2 stats_auto_recalc = 0")
+	tk.MustQuery("show create table t2").Check(testkit.Rows("t2 CREATE TABLE `t2` (\n" +
+		"  `a` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin STATS_AUTO_RECALC=0"))
+	valid, failReason := job.ValidateAndPrepare(tk.Session())
+	require.False(t, valid)
+	require.Equal(t, "auto analyze of the table is disabled by STATS_AUTO_RECALC=0", failReason)
+}
+
+func TestAutoAnalyzeTableConfig(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	handle := dom.StatsHandle()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("insert into t1 values (1)")
+	tk.MustExec("insert into t2 values (1)")
+	statistics.AutoAnalyzeMinCnt = 0
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = 1000
+	}()
+
+	tk.MustExec("alter table t1 auto_analyze options (disabled = 1)")
+	tk.MustExec("alter table t2 auto_analyze options (priority = 10, sample_rate = 0.5, column_choice = 'predicate')")
+	tk.MustGetErrMsg("alter table t2 auto_analyze options (sample_rate = 2)", "Auto Analyze Options: SAMPLE_RATE should be in (0, 1]")
+	tk.MustGetErrMsg("alter table t2 auto_analyze options (window_start = '1am')", "Auto Analyze Options: WINDOW_START should be in the format of '15:04 -0700'")
+	tk.MustGetErrMsg("alter table t2 auto_analyze options (column_choice = 'list')", "Auto Analyze Options: COLUMN_CHOICE should be 'ALL' or 'PREDICATE'")
+	tk.MustGetErrMsg("alter table t2 auto_analyze options (priority = 1, priority = 2)", "Auto Analyze Options: PRIORITY is specified more than once")
+
+	ctx := context.Background()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+
+	// The disabled table is not queued, and the priority is added to the weight.
+	tbl2, err := dom.InfoSchema().TableByName(ctx, pmodel.NewCIStr("test"), pmodel.NewCIStr("t2"))
+	require.NoError(t, err)
+	l, err := pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 1, l)
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tbl2.Meta().ID, job.GetTableID())
+	require.Greater(t, job.GetWeight(), 10.0)
+	valid, _ := job.ValidateAndPrepare(tk.Session())
+	require.True(t, valid)
+	sql, _ := job.(*priorityqueue.NonPartitionedTableAnalysisJob).GenSQLForAnalyzeTable()
+	require.Equal(t, "analyze table %n.%n predicate columns with 0.5 samplerate", sql)
+
+	// The queued job is skipped once the table is disabled or out of its window.
+	tk.MustExec("alter table t2 auto_analyze options (disabled = 1)")
+	priorityqueue.RefreshTableConfigs(tk.Session())
+	valid, failReason := job.ValidateAndPrepare(tk.Session())
+	require.False(t, valid)
+	require.Equal(t, "auto analyze of the table is disabled by mysql.auto_analyze_config", failReason)
+	now := time.Now().UTC()
+	tk.MustExec(fmt.Sprintf("alter table t2 auto_analyze options (disabled = default, window_start = '%s', window_end = '%s')",
+		now.Add(time.Hour).Format("15:04 -0700"), now.Add(2*time.Hour).Format("15:04 -0700")))
+	priorityqueue.RefreshTableConfigs(tk.Session())
+	valid, failReason = job.ValidateAndPrepare(tk.Session())
+	require.False(t, valid)
+	require.Equal(t, "current time is out of the auto analyze window of the table", failReason)
+	tk.MustQuery(fmt.Sprintf("select priority, sample_rate, column_choice, disabled from mysql.auto_analyze_config where table_id = %d", tbl2.Meta().ID)).
+		Check(testkit.Rows("10 0.5 PREDICATE <nil>"))
+}
+
+func TestAutoAnalyzeResourceGroup(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	handle := dom.StatsHandle()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("insert into t1 values (1)")
+	tk.MustExec("insert into t2 values (1)")
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	tk.MustExec("create resource group rg1 ru_per_sec = 1000")
+	tk.MustExec("create resource group rg2 ru_per_sec = 1000")
+	tk.MustExec("alter table t1 auto_analyze options (resource_group = 'RG1')")
+	tk.MustGetErrMsg("alter table t1 auto_analyze options (resource_group = 1)", "Auto Analyze Options: RESOURCE_GROUP should be a string")
+	tk.MustContainErrMsg("alter table t1 auto_analyze options (resource_group = 'rg3')", "Unknown resource group 'rg3'")
+	tk.MustExec("set global tidb_auto_analyze_resource_group = 'rg2'")
+	defer tk.MustExec("set global tidb_auto_analyze_resource_group = default")
+	priorityqueue.RefreshTableConfigs(tk.Session())
+
+	groups := make(map[int64]string)
+	testfailpoint.EnableCall(t, "github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue/beforeAutoAnalyze",
+		func(tableID int64, groupName string) {
+			groups[tableID] = groupName
+		})
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+	analyze := func(tblInfo *model.TableInfo) {
+		require.NoError(t, pq.EnqueueJob(tblInfo.ID, 0, nil))
+		job, err := pq.Pop()
+		require.NoError(t, err)
+		valid, _ := job.ValidateAndPrepare(tk.Session())
+		require.True(t, valid)
+		require.NoError(t, job.Analyze(handle, dom.SysProcTracker()))
+	}
+
+	// The option of the table takes precedence over tidb_auto_analyze_resource_group.
+	tbl1 := dom.MustGetTableInfo(t, "test", "t1")
+	tbl2 := dom.MustGetTableInfo(t, "test", "t2")
+	analyze(tbl1)
+	analyze(tbl2)
+	require.Equal(t, "rg1", groups[tbl1.ID])
+	require.Equal(t, "rg2", groups[tbl2.ID])
+
+	// It falls back to the default resource group once the resource group is dropped.
+	tk.MustExec("drop resource group rg1")
+	analyze(tbl1)
+	require.Equal(t, "default", groups[tbl1.ID])
+	tk.MustQuery(fmt.Sprintf("select resource_group from mysql.auto_analyze_config where table_id = %d", tbl1.ID)).Check(testkit.Rows("rg1"))
+}
+
+func TestAutoAnalyzeErrorBudget(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	handle := dom.StatsHandle()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int)")
+	values := make([]string, 0, 1000)
+	for i := 1; i <= 1000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("analyze table t")
+	tk.MustExec("alter table t auto_analyze options (target_qerror = 2)")
+	tk.MustGetErrMsg("alter table t auto_analyze options (target_qerror = 1)", "Auto Analyze Options: TARGET_QERROR should be larger than 1")
+	tk.MustGetErrMsg("alter table t auto_analyze options (target_qerror_percentile = 100)", "Auto Analyze Options: TARGET_QERROR_PERCENTILE should be in (0, 100)")
+	// The changes exceed tidb_auto_analyze_ratio.
+	values = values[:0]
+	for i := 1001; i <= 1600; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	statistics.CardinalityFeedback.Reset()
+	defer statistics.CardinalityFeedback.Reset()
+
+	ctx := context.Background()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(ctx, dom.InfoSchema()))
+	tableID := dom.MustGetTableInfo(t, "test", "t").ID
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+
+	// t is not queued by its changes since it has the error budget.
+	l, err := pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 0, l)
+	now := time.Now()
+	for range 100 {
+		statistics.CardinalityFeedback.Record(tableID, 100, 100, now)
+	}
+	pq.ProcessDMLChanges()
+	l, err = pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 0, l)
+
+	// 10 of the 110 estimations exceed the target, which burns the budget of 5% by about 1.8x.
+	for range 10 {
+		statistics.CardinalityFeedback.Record(tableID, 1000, 100, now)
+	}
+	pq.ProcessDMLChanges()
+	l, err = pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 1, l)
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tableID, job.GetTableID())
+	require.InDelta(t, 10.0/110/0.05, job.GetIndicators().ErrorBudgetBurnRate, 1e-9)
+	require.Greater(t, job.GetIndicators().ChangePercentage, 0.0)
+}
+
+func TestAutoAnalyzeStaleIndexes(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	handle := dom.StatsHandle()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 1")
+	tk.MustExec("set global tidb_auto_analyze_ratio = 0.3")
+	defer tk.MustExec("set global tidb_auto_analyze_ratio = default")
+	tk.MustExec("create table t (a int primary key, b int, c int, index ib(b), index ic(c))")
+	insertRows := func(start, end int) {
+		values := make([]string, 0, end-start+1)
+		for i := start; i <= end; i++ {
+			values = append(values, fmt.Sprintf("(%d, %d, %d)", i, i, i))
+		}
+		tk.MustExec("insert into t values " + strings.Join(values, ","))
+		require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	}
+	insertRows(1, 1000)
+	tk.MustExec("analyze table t")
+	// Only ib is analyzed, so the modifications before the analysis are carried over to ic.
+	insertRows(1001, 1800)
+	tk.MustExec("analyze table t index ib")
+	// The changes of the table don't exceed tidb_auto_analyze_ratio, but the ones of ic do.
+	insertRows(1801, 1900)
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
+
+	tblInfo := dom.MustGetTableInfo(t, "test", "t")
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+	l, err := pq.Len()
+	require.NoError(t, err)
+	require.Equal(t, 1, l)
+	job, err := pq.Peek()
+	require.NoError(t, err)
+	require.Equal(t, tblInfo.ID, job.GetTableID())
+	jobJSON := job.AsJSON()
+	require.Equal(t, "analyzeIndex", jobJSON.Type)
+	require.Equal(t, []int64{tblInfo.FindIndexByName("ic").ID}, jobJSON.IndexIDs)
+	require.Greater(t, job.GetIndicators().ChangePercentage, 0.3)
+}
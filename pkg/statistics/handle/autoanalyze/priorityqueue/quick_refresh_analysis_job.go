@@ -0,0 +1,288 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/sysproctrack"
+	"github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"go.uber.org/zap"
+)
+
+var _ AnalysisJob = &QuickRefreshAnalysisJob{}
+
+const quickRefresh analyzeType = "quickRefresh"
+
+// QuickRefreshAnalysisJob is a job for refreshing the row count and the null counts of a non-partitioned table
+// without rebuilding its histograms. It's created for the tables whose changes since the last analysis or quick
+// refresh exceed tidb_auto_analyze_quick_refresh_ratio but haven't reached tidb_auto_analyze_ratio yet,
+// so the estimations stay reasonable until the next full analysis.
+//
+// The modify count is not reset by the quick refresh, otherwise the table would never be fully analyzed.
+// Instead, the modify count at the quick refresh is recorded in mysql.stats_quick_refresh as the baseline
+// of the next quick refresh.
+//
+//nolint:fieldalignment
+type QuickRefreshAnalysisJob struct {
+	successHook SuccessJobHook
+	failureHook FailureJobHook
+
+	TableID int64
+	// LastAnalyzeVersion is the version of the last analysis of the table when the job is created.
+	// The recorded baseline is ignored once the table is analyzed again.
+	LastAnalyzeVersion uint64
+
+	Indicators
+	Weight float64
+
+	// Lazy initialized.
+	SchemaName string
+	TableName  string
+}
+
+// NewQuickRefreshAnalysisJob creates a job for refreshing the row count and the null counts of the table.
+func NewQuickRefreshAnalysisJob(
+	tableID int64,
+	lastAnalyzeVersion uint64,
+	changePercentage float64,
+	tableSize float64,
+	lastAnalysisDuration time.Duration,
+) *QuickRefreshAnalysisJob {
+	return &QuickRefreshAnalysisJob{
+		TableID:            tableID,
+		LastAnalyzeVersion: lastAnalyzeVersion,
+		Indicators: Indicators{
+			ChangePercentage:     changePercentage,
+			TableSize:            tableSize,
+			LastAnalysisDuration: lastAnalysisDuration,
+		},
+	}
+}
+
+// IsQuickRefreshAnalysisJob checks whether the job is a quick refresh job.
+func IsQuickRefreshAnalysisJob(job AnalysisJob) bool {
+	_, ok := job.(*QuickRefreshAnalysisJob)
+	return ok
+}
+
+// GetTableID gets the table ID of the job.
+func (j *QuickRefreshAnalysisJob) GetTableID() int64 {
+	return j.TableID
+}
+
+// Analyze refreshes the row count and the null counts of the table.
+func (j *QuickRefreshAnalysisJob) Analyze(
+	statsHandle statstypes.StatsHandle,
+	_ sysproctrack.Tracker,
+) error {
+	start := time.Now()
+	err := statsutil.CallWithSCtx(statsHandle.PrioritizedSPool(statsutil.SessionPriorityLow), j.refresh, statsutil.FlagWrapTxn)
+	if err != nil {
+		logutil.StatsLogger().Warn("quick refresh failed",
+			zap.String("db", j.SchemaName),
+			zap.String("table", j.TableName),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err),
+		)
+		if j.failureHook != nil {
+			j.failureHook(j, true)
+		}
+		return nil
+	}
+	logutil.StatsLogger().Info("quick refresh finished",
+		zap.String("db", j.SchemaName),
+		zap.String("table", j.TableName),
+		zap.Duration("duration", time.Since(start)),
+	)
+	if j.successHook != nil {
+		j.successHook(j)
+	}
+	return nil
+}
+
+// refresh counts the rows and the not NULL values of the analyzed columns, and updates them to the stats in the same
+// transaction. The counts usually come from the smallest index, which is much cheaper than sampling the table.
+func (j *QuickRefreshAnalysisJob) refresh(sctx sessionctx.Context) error {
+	tableInfo, ok := sctx.GetDomainInfoSchema().TableInfoByID(j.TableID)
+	if !ok {
+		return errors.New(tableNotExist)
+	}
+	rows, _, err := statsutil.ExecRows(sctx, "select hist_id from mysql.stats_histograms where table_id = %? and is_index = 0", j.TableID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cols := make([]*model.ColumnInfo, 0, len(rows))
+	for _, row := range rows {
+		if col := model.FindColumnInfoByID(tableInfo.Columns, row.GetInt64(0)); col != nil {
+			cols = append(cols, col)
+		}
+	}
+	sql, params := j.GenSQLForCount(cols)
+	rows, _, err = statsutil.ExecRows(sctx, sql, params...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	count := rows[0].GetInt64(0)
+
+	startTS, err := statsutil.GetStartTS(sctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, _, err = statsutil.ExecRows(sctx, "update mysql.stats_meta set version = %?, count = %? where table_id = %?", startTS, count, j.TableID); err != nil {
+		return errors.Trace(err)
+	}
+	// The version of the histograms is kept, so the histograms are not reloaded and the table is not regarded as analyzed.
+	for i, col := range cols {
+		nullCount := count - rows[0].GetInt64(i+1)
+		if _, _, err = statsutil.ExecRows(sctx, "update mysql.stats_histograms set null_count = %? where table_id = %? and is_index = 0 and hist_id = %?",
+			nullCount, j.TableID, col.ID); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	_, _, err = statsutil.ExecRows(sctx, "replace into mysql.stats_quick_refresh (table_id, modify_count, analyze_version) "+
+		"select table_id, modify_count, %? from mysql.stats_meta where table_id = %?", j.LastAnalyzeVersion, j.TableID)
+	return errors.Trace(err)
+}
+
+// GenSQLForCount generates the SQL for counting the rows and the not NULL values of the columns.
+func (j *QuickRefreshAnalysisJob) GenSQLForCount(cols []*model.ColumnInfo) (string, []any) {
+	var sql strings.Builder
+	params := make([]any, 0, len(cols)+2)
+	sql.WriteString("select count(1)")
+	for _, col := range cols {
+		sql.WriteString(", count(%n)")
+		params = append(params, col.Name.O)
+	}
+	sql.WriteString(" from %n.%n")
+	params = append(params, j.SchemaName, j.TableName)
+	return sql.String(), params
+}
+
+// RegisterSuccessHook registers a successHook function that will be called after the job can be marked as successful.
+func (j *QuickRefreshAnalysisJob) RegisterSuccessHook(hook SuccessJobHook) {
+	j.successHook = hook
+}
+
+// RegisterFailureHook registers a failureHook function that will be called after the job can be marked as failed.
+func (j *QuickRefreshAnalysisJob) RegisterFailureHook(hook FailureJobHook) {
+	j.failureHook = hook
+}
+
+// HasNewlyAddedIndex checks whether the table has newly added indexes.
+// The newly added indexes are always analyzed by the full analysis jobs.
+func (*QuickRefreshAnalysisJob) HasNewlyAddedIndex() bool {
+	return false
+}
+
+// ValidateAndPrepare validates if the quick refresh can run and prepares it for execution.
+// It checks:
+// - Schema exists
+// - Table exists
+// - Table is not switching its cache status
+// - Stats of the table are not locked
+func (j *QuickRefreshAnalysisJob) ValidateAndPrepare(
+	sctx sessionctx.Context,
+) (bool, string) {
+	callFailureHook := func(needRetry bool) {
+		if j.failureHook != nil {
+			j.failureHook(j, needRetry)
+		}
+	}
+	is := sctx.GetDomainInfoSchema()
+	tableInfo, ok := is.TableInfoByID(j.TableID)
+	if !ok {
+		callFailureHook(false)
+		return false, tableNotExist
+	}
+	schema, ok := is.SchemaByID(tableInfo.DBID)
+	if !ok {
+		callFailureHook(false)
+		return false, schemaNotExist
+	}
+	if isCacheStatusSwitching(tableInfo) {
+		callFailureHook(true)
+		return false, tableCacheSwitching
+	}
+	lockedTables, err := getLockedTables(sctx, j.TableID)
+	if err != nil {
+		callFailureHook(true)
+		return false, fmt.Sprintf("fail to query locked tables: %v", err)
+	}
+	if len(lockedTables) > 0 {
+		callFailureHook(false)
+		return false, statsLocked
+	}
+
+	j.SchemaName = schema.Name.O
+	j.TableName = tableInfo.Name.O
+	return true, ""
+}
+
+// SetWeight sets the weight of the job.
+func (j *QuickRefreshAnalysisJob) SetWeight(weight float64) {
+	j.Weight = weight
+}
+
+// GetWeight gets the weight of the job.
+func (j *QuickRefreshAnalysisJob) GetWeight() float64 {
+	return j.Weight
+}
+
+// GetIndicators returns the indicators of the table.
+func (j *QuickRefreshAnalysisJob) GetIndicators() Indicators {
+	return j.Indicators
+}
+
+// SetIndicators sets the indicators of the table.
+func (j *QuickRefreshAnalysisJob) SetIndicators(indicators Indicators) {
+	j.Indicators = indicators
+}
+
+// String implements fmt.Stringer interface.
+func (j *QuickRefreshAnalysisJob) String() string {
+	return fmt.Sprintf(
+		"QuickRefreshAnalysisJob:\n"+
+			"\tAnalyzeType: %s\n"+
+			"\tSchema: %s\n"+
+			"\tTable: %s\n"+
+			"\tTableID: %d\n"+
+			"\tLastAnalyzeVersion: %d\n"+
+			"\tChangePercentage: %.6f\n"+
+			"\tTableSize: %.2f\n"+
+			"\tLastAnalysisDuration: %v\n"+
+			"\tWeight: %.6f\n",
+		quickRefresh,
+		j.SchemaName, j.TableName, j.TableID, j.LastAnalyzeVersion,
+		j.ChangePercentage, j.TableSize, j.LastAnalysisDuration, j.Weight,
+	)
+}
+
+// AsJSON converts the job to a JSON object.
+func (j *QuickRefreshAnalysisJob) AsJSON() statstypes.AnalysisJobJSON {
+	return statstypes.AnalysisJobJSON{
+		Type:       string(quickRefresh),
+		TableID:    j.TableID,
+		Weight:     j.Weight,
+		Indicators: asJSONIndicators(j.Indicators),
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuickRefreshAnalysisJob(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, index idx(a))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8), (9, 9), (10, 10)")
+	handle := dom.StatsHandle()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t")
+	statistics.AutoAnalyzeMinCnt = 0
+	variable.AutoAnalyzeQuickRefreshRatio.Store(0.1)
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = 1000
+		variable.AutoAnalyzeQuickRefreshRatio.Store(variable.DefTiDBAutoAnalyzeQuickRefreshRatio)
+	}()
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tableID := tbl.Meta().ID
+
+	// 2 of 10 rows are changed, which exceeds the quick refresh ratio but not the auto analyze ratio.
+	tk.MustExec("insert into t values (11, null), (12, null)")
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	// Make the row count inaccurate.
+	tk.MustExec(fmt.Sprintf("update mysql.stats_meta set count = 100 where table_id = %d", tableID))
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
+
+	pq := priorityqueue.NewAnalysisPriorityQueue(handle)
+	defer pq.Close()
+	require.NoError(t, pq.Initialize())
+	job, err := pq.Pop()
+	require.NoError(t, err)
+	require.True(t, priorityqueue.IsQuickRefreshAnalysisJob(job))
+	require.Equal(t, "quickRefresh", job.AsJSON().Type)
+	valid, failReason := job.ValidateAndPrepare(tk.Session())
+	require.True(t, valid)
+	require.Equal(t, "", failReason)
+	require.NoError(t, job.Analyze(handle, dom.SysProcTracker()))
+
+	// The row count and the null counts are refreshed, while the modify count is kept.
+	tk.MustQuery(fmt.Sprintf("select count, modify_count from mysql.stats_meta where table_id = %d", tableID)).Check(testkit.Rows("12 2"))
+	tk.MustQuery(fmt.Sprintf("select null_count from mysql.stats_histograms where table_id = %d and is_index = 0 and hist_id = %d",
+		tableID, tbl.Meta().Columns[1].ID)).Check(testkit.Rows("2"))
+	tk.MustQuery(fmt.Sprintf("select modify_count from mysql.stats_quick_refresh where table_id = %d", tableID)).Check(testkit.Rows("2"))
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
+	tblStats := handle.GetTableStats(tbl.Meta())
+	require.Equal(t, int64(12), tblStats.RealtimeCount)
+	require.True(t, tblStats.IsAnalyzed())
+
+	// No more quick refresh until the table changes again.
+	require.NoError(t, pq.Rebuild())
+	isEmpty, err := pq.IsEmpty()
+	require.NoError(t, err)
+	require.True(t, isEmpty)
+	tk.MustExec("insert into t values (13, null), (14, null)")
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
+	require.NoError(t, pq.Rebuild())
+	job, err = pq.Pop()
+	require.NoError(t, err)
+	require.True(t, priorityqueue.IsQuickRefreshAnalysisJob(job))
+	// Only the changes after the last quick refresh are counted.
+	require.Greater(t, job.GetIndicators().ChangePercentage, 0.1)
+	require.Less(t, job.GetIndicators().ChangePercentage, 0.3)
+
+	// The baseline is ignored after the table is analyzed again.
+	tk.MustExec("analyze table t")
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
+	require.NoError(t, pq.Rebuild())
+	isEmpty, err = pq.IsEmpty()
+	require.NoError(t, err)
+	require.True(t, isEmpty)
+}
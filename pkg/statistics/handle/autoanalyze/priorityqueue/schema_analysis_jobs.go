@@ -0,0 +1,247 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/exec"
+	"github.com/pingcap/tidb/pkg/statistics/handle/lockstats"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+)
+
+// CreateAnalysisJobsForSchema creates analysis jobs for all tables of the given schema.
+// It is used by the ANALYZE DATABASE statement. Unlike auto analyze, every table and
+// partition gets a job even if its stats are not outdated or it is too small, since
+// the user explicitly asks to analyze the whole schema. Locked tables and views are skipped.
+// The jobs are returned in priority order, so the tables needing analysis most go first.
+func CreateAnalysisJobsForSchema(
+	sctx sessionctx.Context,
+	statsHandle statstypes.StatsHandle,
+	schema pmodel.CIStr,
+) ([]AnalysisJob, error) {
+	is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
+	if _, ok := is.SchemaByName(schema); !ok {
+		return nil, infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema.O)
+	}
+	tbls, err := is.SchemaTableInfos(context.Background(), schema)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	lockedTables, err := lockstats.QueryLockedTables(statsutil.StatsCtx, sctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	currentTs, err := statsutil.GetStartTS(sctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	parameters := exec.GetAutoAnalyzeParameters(sctx)
+	autoAnalyzeRatio := exec.ParseAutoAnalyzeRatio(parameters[variable.TiDBAutoAnalyzeRatio])
+	pruneMode := variable.PartitionPruneMode(sctx.GetSessionVars().PartitionPruneMode.Load())
+	jobFactory := NewAnalysisJobFactory(sctx, autoAnalyzeRatio, currentTs)
+	calculator := NewPriorityCalculator()
+	h := newHeap()
+	push := func(job AnalysisJob) error {
+		job.SetWeight(calculator.CalculateWeight(job))
+		return h.addOrUpdate(job)
+	}
+
+	for _, tblInfo := range tbls {
+		if tblInfo.IsView() || tblInfo.IsSequence() || tblInfo.TempTableType != model.TempTableNone {
+			continue
+		}
+		if _, ok := lockedTables[tblInfo.ID]; ok {
+			continue
+		}
+		for _, job := range jobFactory.createAnalysisJobsForTable(statsHandle, tblInfo, pruneMode, lockedTables) {
+			if err := push(job); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+
+	jobs := make([]AnalysisJob, 0, h.len())
+	for !h.isEmpty() {
+		job, err := h.pop()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// CreateAnalysisJobsForTable creates analysis jobs for the given table or partition.
+// Like CreateAnalysisJobsForSchema, the jobs are created even if the stats are not outdated.
+// If the ID is a partition ID, only the partition is analyzed in static prune mode,
+// and the whole table is analyzed in dynamic prune mode.
+func CreateAnalysisJobsForTable(
+	sctx sessionctx.Context,
+	statsHandle statstypes.StatsHandle,
+	physicalID int64,
+) ([]AnalysisJob, error) {
+	is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
+	tblInfo, ok := is.TableInfoByID(physicalID)
+	partitionID := int64(0)
+	if !ok {
+		var def *model.PartitionDefinition
+		tblInfo, _, def = is.FindTableInfoByPartitionID(physicalID)
+		if tblInfo == nil {
+			return nil, errors.Errorf("table %d not found", physicalID)
+		}
+		partitionID = def.ID
+	}
+	if tblInfo.IsView() || tblInfo.IsSequence() || tblInfo.TempTableType != model.TempTableNone {
+		return nil, errors.Errorf("table %s is not supported to analyze", tblInfo.Name.O)
+	}
+	lockedTables, err := lockstats.QueryLockedTables(statsutil.StatsCtx, sctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, ok := lockedTables[tblInfo.ID]; ok {
+		return nil, errors.Errorf("table %s is locked", tblInfo.Name.O)
+	}
+	if _, ok := lockedTables[partitionID]; ok {
+		return nil, errors.Errorf("partition %d of table %s is locked", partitionID, tblInfo.Name.O)
+	}
+	currentTs, err := statsutil.GetStartTS(sctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	parameters := exec.GetAutoAnalyzeParameters(sctx)
+	autoAnalyzeRatio := exec.ParseAutoAnalyzeRatio(parameters[variable.TiDBAutoAnalyzeRatio])
+	pruneMode := variable.PartitionPruneMode(sctx.GetSessionVars().PartitionPruneMode.Load())
+	jobFactory := NewAnalysisJobFactory(sctx, autoAnalyzeRatio, currentTs)
+	if partitionID != 0 && pruneMode == variable.Static {
+		return []AnalysisJob{jobFactory.createStaticPartitionAnalysisJobForTable(statsHandle, tblInfo, partitionID)}, nil
+	}
+	return jobFactory.createAnalysisJobsForTable(statsHandle, tblInfo, pruneMode, lockedTables), nil
+}
+
+// createAnalysisJobsForTable creates the analysis jobs for the table regardless of whether
+// its stats are outdated. In static prune mode every unlocked partition gets a job,
+// otherwise the table gets a single job.
+func (f *AnalysisJobFactory) createAnalysisJobsForTable(
+	statsHandle statstypes.StatsHandle,
+	tblInfo *model.TableInfo,
+	pruneMode variable.PartitionPruneMode,
+	lockedTables map[int64]struct{},
+) []AnalysisJob {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		tblStats := statsHandle.GetTableStatsForAutoAnalyze(tblInfo)
+		changePercentage, tableSize, lastAnalysisDuration := f.calculateIndicatorsForSchemaAnalysis(tblStats)
+		return []AnalysisJob{NewNonPartitionedTableAnalysisJob(
+			tblInfo.ID,
+			nil,
+			f.getTableStatsVer(tblStats),
+			changePercentage,
+			tableSize,
+			lastAnalysisDuration,
+		)}
+	}
+
+	partitionDefs := make([]model.PartitionDefinition, 0, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		if _, ok := lockedTables[def.ID]; !ok {
+			partitionDefs = append(partitionDefs, def)
+		}
+	}
+	if len(partitionDefs) == 0 {
+		return nil
+	}
+	// If the prune mode is static, we need to analyze every partition as a separate table.
+	if pruneMode == variable.Static {
+		jobs := make([]AnalysisJob, 0, len(partitionDefs))
+		for _, def := range partitionDefs {
+			jobs = append(jobs, f.createStaticPartitionAnalysisJobForTable(statsHandle, tblInfo, def.ID))
+		}
+		return jobs
+	}
+
+	partitionIDs := make(map[int64]struct{}, len(partitionDefs))
+	var totalChange, totalSize float64
+	var totalLastAnalysisDuration time.Duration
+	for _, def := range partitionDefs {
+		partitionIDs[def.ID] = struct{}{}
+		changePercentage, tableSize, lastAnalysisDuration := f.calculateIndicatorsForSchemaAnalysis(
+			statsHandle.GetPartitionStatsForAutoAnalyze(tblInfo, def.ID),
+		)
+		totalChange += changePercentage
+		totalSize += tableSize
+		totalLastAnalysisDuration += lastAnalysisDuration
+	}
+	count := len(partitionDefs)
+	return []AnalysisJob{NewDynamicPartitionedTableAnalysisJob(
+		tblInfo.ID,
+		partitionIDs,
+		nil,
+		f.getTableStatsVer(statsHandle.GetPartitionStatsForAutoAnalyze(tblInfo, tblInfo.ID)),
+		totalChange/float64(count),
+		totalSize/float64(count),
+		totalLastAnalysisDuration/time.Duration(count),
+	)}
+}
+
+// createStaticPartitionAnalysisJobForTable creates the analysis job for one partition in static prune mode.
+func (f *AnalysisJobFactory) createStaticPartitionAnalysisJobForTable(
+	statsHandle statstypes.StatsHandle,
+	tblInfo *model.TableInfo,
+	partitionID int64,
+) AnalysisJob {
+	partitionStats := statsHandle.GetPartitionStatsForAutoAnalyze(tblInfo, partitionID)
+	changePercentage, tableSize, lastAnalysisDuration := f.calculateIndicatorsForSchemaAnalysis(partitionStats)
+	return NewStaticPartitionTableAnalysisJob(
+		tblInfo.ID,
+		partitionID,
+		nil,
+		f.getTableStatsVer(partitionStats),
+		changePercentage,
+		tableSize,
+		lastAnalysisDuration,
+	)
+}
+
+// calculateIndicatorsForSchemaAnalysis calculates the indicators of a table for ANALYZE DATABASE.
+// The stats may be missing or pseudo because the table is not required to be eligible for auto analyze,
+// in that case we treat it as an unanalyzed table.
+func (f *AnalysisJobFactory) calculateIndicatorsForSchemaAnalysis(
+	tblStats *statistics.Table,
+) (changePercentage, tableSize float64, lastAnalysisDuration time.Duration) {
+	if tblStats == nil || tblStats.Pseudo ||
+		tblStats.ColAndIdxExistenceMap == nil || tblStats.ColAndIdxExistenceMap.ColNum() == 0 {
+		return unanalyzedTableDefaultChangePercentage, 0, -unanalyzedTableDefaultLastUpdateDuration
+	}
+	return f.CalculateChangePercentage(tblStats), f.CalculateTableSize(tblStats), f.GetTableLastAnalyzeDuration(tblStats)
+}
+
+// getTableStatsVer gets the stats version used to analyze the table.
+func (f *AnalysisJobFactory) getTableStatsVer(tblStats *statistics.Table) int {
+	tableStatsVer := f.sctx.GetSessionVars().AnalyzeVersion
+	if tblStats != nil {
+		statistics.CheckAnalyzeVerOnTable(tblStats, &tableStatsVer)
+	}
+	return tableStatsVer
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAnalysisJobsForSchema(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create schema example_schema")
+	tk.MustExec("use example_schema")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int, index idx(a))")
+	tk.MustExec("create table t3 (a int) partition by hash(a) partitions 2")
+	tk.MustExec("create table t4 (a int)")
+	tk.MustExec("create view v as select * from t1")
+	tk.MustExec("insert into t1 values (1), (2)")
+	tk.MustExec("insert into t2 values (1), (2)")
+	tk.MustExec("insert into t3 values (1), (2)")
+	tk.MustExec("analyze table t1")
+	tk.MustExec("lock stats t4")
+	h := dom.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+
+	createJobs := func(schema string) (jobs []priorityqueue.AnalysisJob, err error) {
+		err = statsutil.CallWithSCtx(h.SPool(), func(sctx sessionctx.Context) error {
+			jobs, err = priorityqueue.CreateAnalysisJobsForSchema(sctx, h, model.NewCIStr(schema))
+			return err
+		}, statsutil.FlagWrapTxn)
+		return jobs, err
+	}
+	jobs, err := createJobs("example_schema")
+	require.NoError(t, err)
+	// The locked table and the view are skipped.
+	require.Len(t, jobs, 3)
+	is := dom.InfoSchema()
+	t1, err := is.TableByName(context.Background(), model.NewCIStr("example_schema"), model.NewCIStr("t1"))
+	require.NoError(t, err)
+	t3, err := is.TableByName(context.Background(), model.NewCIStr("example_schema"), model.NewCIStr("t3"))
+	require.NoError(t, err)
+	// The analyzed table without changes has the lowest priority, but it is still analyzed.
+	require.Equal(t, t1.Meta().ID, jobs[2].GetTableID())
+	for i := 1; i < len(jobs); i++ {
+		require.GreaterOrEqual(t, jobs[i-1].GetWeight(), jobs[i].GetWeight())
+	}
+	for _, job := range jobs {
+		if job.GetTableID() == t3.Meta().ID {
+			dynamicJob, ok := job.(*priorityqueue.DynamicPartitionedTableAnalysisJob)
+			require.True(t, ok)
+			require.Len(t, dynamicJob.PartitionIDs, 2)
+		}
+	}
+
+	// Every partition is a separate job in static prune mode.
+	tk.MustExec("set global tidb_partition_prune_mode = 'static'")
+	defer tk.MustExec("set global tidb_partition_prune_mode = default")
+	jobs, err = createJobs("example_schema")
+	require.NoError(t, err)
+	require.Len(t, jobs, 4)
+
+	_, err = createJobs("not_exist")
+	require.True(t, infoschema.ErrDatabaseNotExists.Equal(err))
+}
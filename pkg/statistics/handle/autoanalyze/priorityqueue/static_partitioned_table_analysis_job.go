@@ -19,9 +19,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/sysproctrack"
-	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/exec"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
 	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 )
@@ -102,7 +102,7 @@ func (j *StaticPartitionedTableAnalysisJob) Analyze(
 		}
 	}()
 
-	return statsutil.CallWithSCtx(statsHandle.SPool(), func(sctx sessionctx.Context) error {
+	return statsutil.CallWithSCtx(statsHandle.PrioritizedSPool(statsutil.SessionPriorityLow), func(sctx sessionctx.Context) error {
 		switch j.getAnalyzeType() {
 		case analyzeStaticPartition:
 			success = j.analyzeStaticPartition(sctx, statsHandle, sysProcTracker)
@@ -143,6 +143,7 @@ func (j *StaticPartitionedTableAnalysisJob) HasNewlyAddedIndex() bool {
 // - Schema exists
 // - Table exists and is partitioned
 // - Specified partition exists
+// - Neither the table nor the partition is locked
 // - No recent failed analysis to avoid queue blocking
 func (j *StaticPartitionedTableAnalysisJob) ValidateAndPrepare(
 	sctx sessionctx.Context,
@@ -180,6 +181,15 @@ func (j *StaticPartitionedTableAnalysisJob) ValidateAndPrepare(
 		callFailureHook(false)
 		return false, partitionNotExist
 	}
+	lockedTables, err := getLockedTables(sctx, j.GlobalTableID, j.StaticPartitionID)
+	if err != nil {
+		callFailureHook(true)
+		return false, fmt.Sprintf("fail to query locked tables: %v", err)
+	}
+	if len(lockedTables) > 0 {
+		callFailureHook(false)
+		return false, statsLocked
+	}
 	indexNames := make([]string, 0, len(j.IndexIDs))
 	for _, index := range tableInfo.Indices {
 		if _, ok := j.IndexIDs[index.ID]; ok {
@@ -206,6 +216,19 @@ func (j *StaticPartitionedTableAnalysisJob) ValidateAndPrepare(
 			return false, failReason
 		}
 	}
+	if valid, needRetry, reason := validateTableConfig(tableInfo); !valid {
+		callFailureHook(needRetry)
+		return false, reason
+	}
+	if within, reason := isWithinPlacementWindows(is.(infoschema.InfoSchema), tableInfo, []int64{j.StaticPartitionID}, Now()); !within {
+		// Retry the job later, it can be analyzed once the window comes.
+		callFailureHook(true)
+		return false, reason
+	}
+	if inCoolDown, reason := IsInAutoAnalyzeCoolDown(sctx, tableInfo, j.SchemaName, j.GlobalTableName); inCoolDown {
+		callFailureHook(true)
+		return false, reason
+	}
 
 	return true, ""
 }
@@ -233,6 +256,8 @@ func (j *StaticPartitionedTableAnalysisJob) String() string {
 			"\tStaticPartitionID: %d\n"+
 			"\tTableStatsVer: %d\n"+
 			"\tChangePercentage: %.6f\n"+
+			"\tShrinkPercentage: %.6f\n"+
+			"\tEstimationError: %.2f\n"+
 			"\tTableSize: %.2f\n"+
 			"\tLastAnalysisDuration: %s\n"+
 			"\tWeight: %.6f\n",
@@ -240,7 +265,7 @@ func (j *StaticPartitionedTableAnalysisJob) String() string {
 		strings.Join(j.IndexNames, ", "),
 		j.SchemaName, j.GlobalTableName, j.GlobalTableID,
 		j.StaticPartitionName, j.StaticPartitionID,
-		j.TableStatsVer, j.ChangePercentage, j.TableSize,
+		j.TableStatsVer, j.ChangePercentage, j.ShrinkPercentage, j.EstimationError, j.TableSize,
 		j.LastAnalysisDuration, j.Weight,
 	)
 }
@@ -260,7 +285,7 @@ func (j *StaticPartitionedTableAnalysisJob) analyzeStaticPartition(
 	sysProcTracker sysproctrack.Tracker,
 ) bool {
 	sql, params := j.GenSQLForAnalyzeStaticPartition()
-	return exec.AutoAnalyze(sctx, statsHandle, sysProcTracker, j.TableStatsVer, sql, params...)
+	return autoAnalyze(sctx, statsHandle, sysProcTracker, j.GetTableID(), j.TableStatsVer, sql, params...)
 }
 
 func (j *StaticPartitionedTableAnalysisJob) analyzeStaticPartitionIndexes(
@@ -277,7 +302,7 @@ func (j *StaticPartitionedTableAnalysisJob) analyzeStaticPartitionIndexes(
 	if analyzeVersion == 1 {
 		for _, index := range j.IndexNames {
 			sql, params := j.GenSQLForAnalyzeStaticPartitionIndex(index)
-			if !exec.AutoAnalyze(sctx, statsHandle, sysProcTracker, j.TableStatsVer, sql, params...) {
+			if !autoAnalyze(sctx, statsHandle, sysProcTracker, j.GetTableID(), j.TableStatsVer, sql, params...) {
 				return false
 			}
 		}
@@ -288,12 +313,12 @@ func (j *StaticPartitionedTableAnalysisJob) analyzeStaticPartitionIndexes(
 	// Therefore, to avoid redundancy, we prevent multiple analyses of the same partition.
 	firstIndex := j.IndexNames[0]
 	sql, params := j.GenSQLForAnalyzeStaticPartitionIndex(firstIndex)
-	return exec.AutoAnalyze(sctx, statsHandle, sysProcTracker, j.TableStatsVer, sql, params...)
+	return autoAnalyze(sctx, statsHandle, sysProcTracker, j.GetTableID(), j.TableStatsVer, sql, params...)
 }
 
 // GenSQLForAnalyzeStaticPartition generates the SQL for analyzing the specified static partition.
 func (j *StaticPartitionedTableAnalysisJob) GenSQLForAnalyzeStaticPartition() (string, []any) {
-	sql := "analyze table %n.%n partition %n"
+	sql := "analyze table %n.%n partition %n" + genAnalyzeOptionsClause(j.GlobalTableID, j.TableStatsVer, j.TableSize)
 	params := []any{j.SchemaName, j.GlobalTableName, j.StaticPartitionName}
 
 	return sql, params
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/session"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/stretchr/testify/require"
@@ -42,6 +43,24 @@ func TestGenSQLForAnalyzeStaticPartitionedTable(t *testing.T) {
 	require.Equal(t, expectedParams, params)
 }
 
+func TestGenSQLForAnalyzeStaticPartitionedTableWithPredicateColumns(t *testing.T) {
+	variable.AutoAnalyzeColumnChoice.Store(model.PredicateColumns.String())
+	defer variable.AutoAnalyzeColumnChoice.Store(variable.DefTiDBAutoAnalyzeColumnChoice)
+	job := &priorityqueue.StaticPartitionedTableAnalysisJob{
+		SchemaName:          "test_schema",
+		GlobalTableName:     "test_table",
+		StaticPartitionName: "p0",
+	}
+
+	expectedSQL := "analyze table %n.%n partition %n predicate columns"
+	expectedParams := []any{"test_schema", "test_table", "p0"}
+
+	sql, params := job.GenSQLForAnalyzeStaticPartition()
+
+	require.Equal(t, expectedSQL, sql)
+	require.Equal(t, expectedParams, params)
+}
+
 func TestGenSQLForAnalyzeStaticPartitionedTableIndex(t *testing.T) {
 	job := &priorityqueue.StaticPartitionedTableAnalysisJob{
 		SchemaName:          "test_schema",
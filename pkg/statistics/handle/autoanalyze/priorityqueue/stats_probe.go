@@ -0,0 +1,219 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+const (
+	// statsProbeCheckInterval is the interval to check whether the stats probe should run,
+	// see tidb_auto_analyze_stats_probe_interval.
+	statsProbeCheckInterval = time.Minute
+	// maxStatsProbeTables is the max number of the tables probed in a round.
+	maxStatsProbeTables = 10
+	// maxStatsProbeColumns is the max number of the columns of a table whose min and max values are probed.
+	maxStatsProbeColumns = 8
+	// maxStatsProbeCountRows is the max row count of a table whose row count is probed, since counting the rows
+	// requires a full scan.
+	maxStatsProbeCountRows = 1000000
+	// minVerifiedStatsDrift is the min drift recorded by the stats probe, the smaller drift is regarded as noise.
+	minVerifiedStatsDrift = 0.1
+)
+
+// statsProbeTarget is a table to be probed.
+type statsProbeTarget struct {
+	tblInfo *model.TableInfo
+	stats   *statistics.Table
+	dbName  string
+	// queries is the number of the estimations on the table since the last analysis.
+	queries int64
+}
+
+// maybeProbeStatsDrift runs the stats probe if tidb_auto_analyze_stats_probe_interval has passed since the last run.
+func (pq *AnalysisPriorityQueue) maybeProbeStatsDrift() {
+	interval := variable.AutoAnalyzeStatsProbeInterval.Load()
+	if interval <= 0 {
+		return
+	}
+	pq.syncFields.mu.Lock()
+	if Now().Sub(pq.syncFields.lastStatsProbeTime) < interval {
+		pq.syncFields.mu.Unlock()
+		return
+	}
+	pq.syncFields.lastStatsProbeTime = Now()
+	pq.syncFields.mu.Unlock()
+	pq.ProbeStatsDrift()
+}
+
+// ProbeStatsDrift runs the tiny aggregate queries, i.e. the row count and the min and max values of the indexed
+// columns, on the most queried tables which have no job in the queue, and compares the results with their stats.
+// The drift of the stats is recorded in statistics.VerifiedStatsDrift and picked up by the next ProcessDMLChanges.
+// Only the non-partitioned tables are probed.
+// Note: This function is thread-safe.
+func (pq *AnalysisPriorityQueue) ProbeStatsDrift() {
+	if err := statsutil.CallWithSCtx(pq.statsHandle.PrioritizedSPool(statsutil.SessionPriorityLow), func(sctx sessionctx.Context) error {
+		is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
+		for _, target := range pq.statsProbeTargets(is) {
+			drift, err := probeStatsDrift(sctx, target)
+			if err != nil {
+				statslogutil.StatsLogger().Warn("Failed to probe the stats drift",
+					zap.Error(err), zap.Int64("tableID", target.tblInfo.ID))
+				continue
+			}
+			if drift < minVerifiedStatsDrift {
+				continue
+			}
+			statistics.VerifiedStatsDrift.Record(target.stats.PhysicalID, drift, target.stats.LastAnalyzeVersion)
+			statslogutil.StatsLogger().Info("The stats drift is verified by the stats probe",
+				zap.String("table", target.dbName+"."+target.tblInfo.Name.O), zap.Float64("drift", drift))
+		}
+		return nil
+	}); err != nil {
+		statslogutil.StatsLogger().Error("Failed to probe the stats drift", zap.Error(err))
+	}
+}
+
+// statsProbeTargets returns the most queried non-partitioned tables which are analyzed but have no job in the queue.
+func (pq *AnalysisPriorityQueue) statsProbeTargets(is infoschema.InfoSchema) []statsProbeTarget {
+	pq.syncFields.mu.RLock()
+	defer pq.syncFields.mu.RUnlock()
+	if !pq.syncFields.initialized {
+		return nil
+	}
+	var targets []statsProbeTarget
+	for _, stats := range pq.statsHandle.Values() {
+		if !stats.IsEligibleForAnalysis() || !stats.IsAnalyzed() {
+			continue
+		}
+		if _, ok, _ := pq.syncFields.inner.getByKey(stats.PhysicalID); ok {
+			continue
+		}
+		feedback := statistics.CardinalityFeedback.Get(stats.PhysicalID, oracle.GetTimeFromTS(stats.LastAnalyzeVersion))
+		if feedback.Count == 0 {
+			continue
+		}
+		tableInfo, ok := pq.statsHandle.TableInfoByID(is, stats.PhysicalID)
+		if !ok {
+			continue
+		}
+		tblInfo := tableInfo.Meta()
+		if tblInfo.ID != stats.PhysicalID || tblInfo.GetPartitionInfo() != nil {
+			continue
+		}
+		db, ok := infoschema.SchemaByTable(is, tblInfo)
+		if !ok || !statsutil.IsAutoAnalyzeTable(db.Name.L, tblInfo.Name.L) {
+			continue
+		}
+		if disabled, _ := IsAutoAnalyzeDisabled(tblInfo); disabled {
+			continue
+		}
+		targets = append(targets, statsProbeTarget{tblInfo: tblInfo, stats: stats, dbName: db.Name.O, queries: feedback.Count})
+	}
+	slices.SortFunc(targets, func(a, b statsProbeTarget) int {
+		return cmp.Compare(b.queries, a.queries)
+	})
+	return targets[:min(len(targets), maxStatsProbeTables)]
+}
+
+// probeStatsDrift returns the drift of the stats of the table, which is the max of the relative error of the row count
+// and the range drifts of the probed columns.
+func probeStatsDrift(sctx sessionctx.Context, target statsProbeTarget) (float64, error) {
+	var drift float64
+	if target.stats.RealtimeCount <= maxStatsProbeCountRows {
+		rows, _, err := statsutil.ExecRows(sctx, "SELECT COUNT(*) FROM %n.%n", target.dbName, target.tblInfo.Name.O)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		stored, observed := float64(target.stats.RealtimeCount), float64(rows[0].GetInt64(0))
+		if stored > 0 || observed > 0 {
+			drift = math.Abs(stored-observed) / max(stored, observed)
+		}
+	}
+	cols := statsProbeColumns(target.tblInfo, target.stats)
+	if len(cols) == 0 {
+		return drift, nil
+	}
+	// The min and max values of the indexed columns are read from the heads of the indexes.
+	sql := new(strings.Builder)
+	sqlescape.MustFormatSQL(sql, "SELECT ")
+	for i, col := range cols {
+		if i > 0 {
+			sqlescape.MustFormatSQL(sql, ", ")
+		}
+		sqlescape.MustFormatSQL(sql, "MIN(%n), MAX(%n)", col.Name.O, col.Name.O)
+	}
+	sqlescape.MustFormatSQL(sql, " FROM %n.%n", target.dbName, target.tblInfo.Name.O)
+	rows, fields, err := statsutil.ExecRows(sctx, sql.String())
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	for i, col := range cols {
+		minVal := rows[0].GetDatum(2*i, &fields[2*i].Column.FieldType)
+		maxVal := rows[0].GetDatum(2*i+1, &fields[2*i+1].Column.FieldType)
+		drift = max(drift, target.stats.GetCol(col.ID).Histogram.RangeDrift(&minVal, &maxVal))
+	}
+	return drift, nil
+}
+
+// statsProbeColumns returns the columns whose min and max values are probed, i.e. the int handle and the first
+// columns of the indexes, whose histograms are loaded and whose values can be converted to scalars.
+func statsProbeColumns(tblInfo *model.TableInfo, stats *statistics.Table) []*model.ColumnInfo {
+	cols := make([]*model.ColumnInfo, 0, maxStatsProbeColumns)
+	add := func(col *model.ColumnInfo) {
+		if len(cols) >= maxStatsProbeColumns || col == nil || col.Hidden || col.FieldType.IsArray() {
+			return
+		}
+		switch col.FieldType.EvalType() {
+		case types.ETInt, types.ETReal, types.ETDecimal, types.ETDatetime, types.ETTimestamp, types.ETDuration:
+		default:
+			return
+		}
+		if colStats := stats.GetCol(col.ID); colStats == nil || colStats.Histogram.Len() == 0 {
+			return
+		}
+		if slices.Contains(cols, col) {
+			return
+		}
+		cols = append(cols, col)
+	}
+	if tblInfo.PKIsHandle {
+		add(tblInfo.GetPkColInfo())
+	}
+	for _, idx := range tblInfo.Indices {
+		if idx.State != model.StatePublic || idx.VectorInfo != nil {
+			continue
+		}
+		add(tblInfo.Columns[idx.Columns[0].Offset])
+	}
+	return cols
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/resourcegroup"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/exec"
+	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"go.uber.org/zap"
+)
+
+const (
+	autoAnalyzeConfigDisabled = "auto analyze of the table is disabled by mysql.auto_analyze_config"
+	outOfTableWindow          = "current time is out of the auto analyze window of the table"
+	// defaultTargetQErrorPercentile is the percentile of the error budget if only TARGET_QERROR is set.
+	defaultTargetQErrorPercentile = 95
+)
+
+// TableConfig is the auto analyze options of a table set by ALTER TABLE ... AUTO_ANALYZE OPTIONS.
+// The nil options are not set, which fall back to the table options and the global variables.
+type TableConfig struct {
+	// Priority is added to the weight of the jobs of the table.
+	Priority *float64
+	// WindowStart and WindowEnd are the auto analyze window of the table in the format of tidb_auto_analyze_start_time.
+	WindowStart *string
+	WindowEnd   *string
+	// SampleRate is the sample rate used to analyze the table.
+	SampleRate *float64
+	// ColumnChoice is 'ALL' or 'PREDICATE', which overrides tidb_auto_analyze_column_choice.
+	ColumnChoice *string
+	// Disabled overrides the table option STATS_AUTO_RECALC.
+	Disabled *bool
+	// CoolDown is in minutes, which overrides the table option AUTO_ANALYZE_COOL_DOWN.
+	CoolDown *uint64
+	// ResourceGroup is the resource group under which the jobs of the table are executed,
+	// which overrides tidb_auto_analyze_resource_group.
+	ResourceGroup *string
+	// TargetQError and TargetQErrorPercentile are the error budget of the table, i.e. the q-errors of the row count
+	// estimations on the table should be under TargetQError for TargetQErrorPercentile percent of the estimations.
+	// The tables with the error budget are analyzed only when the budget is violated, instead of by their changes.
+	TargetQError           *float64
+	TargetQErrorPercentile *float64
+}
+
+// tableConfigs caches mysql.auto_analyze_config, which is reloaded by RefreshTableConfigs.
+var tableConfigs struct {
+	sync.RWMutex
+	configs map[int64]*TableConfig
+	// hasErrorBudget is whether any table has the error budget.
+	hasErrorBudget bool
+}
+
+// RefreshTableConfigs reloads the auto analyze options of the tables from mysql.auto_analyze_config.
+// The cached options are kept if it fails to reload them.
+func RefreshTableConfigs(sctx sessionctx.Context) {
+	rows, _, err := statsutil.ExecRows(
+		sctx,
+		"select table_id, priority, window_start, window_end, sample_rate, column_choice, disabled, cool_down, resource_group, target_qerror, target_qerror_percentile from mysql.auto_analyze_config",
+	)
+	if err != nil {
+		statslogutil.StatsLogger().Warn("Failed to load the auto analyze options of the tables", zap.Error(err))
+		return
+	}
+	configs := make(map[int64]*TableConfig, len(rows))
+	hasErrorBudget := false
+	for _, row := range rows {
+		cfg := &TableConfig{}
+		if !row.IsNull(1) {
+			v := row.GetFloat64(1)
+			cfg.Priority = &v
+		}
+		if !row.IsNull(2) {
+			v := row.GetString(2)
+			cfg.WindowStart = &v
+		}
+		if !row.IsNull(3) {
+			v := row.GetString(3)
+			cfg.WindowEnd = &v
+		}
+		if !row.IsNull(4) {
+			v := row.GetFloat64(4)
+			cfg.SampleRate = &v
+		}
+		if !row.IsNull(5) {
+			v := row.GetString(5)
+			cfg.ColumnChoice = &v
+		}
+		if !row.IsNull(6) {
+			v := row.GetInt64(6) != 0
+			cfg.Disabled = &v
+		}
+		if !row.IsNull(7) {
+			v := row.GetUint64(7)
+			cfg.CoolDown = &v
+		}
+		if !row.IsNull(8) {
+			v := row.GetString(8)
+			cfg.ResourceGroup = &v
+		}
+		if !row.IsNull(9) {
+			v := row.GetFloat64(9)
+			cfg.TargetQError = &v
+			hasErrorBudget = true
+		}
+		if !row.IsNull(10) {
+			v := row.GetFloat64(10)
+			cfg.TargetQErrorPercentile = &v
+		}
+		configs[row.GetInt64(0)] = cfg
+	}
+	tableConfigs.Lock()
+	defer tableConfigs.Unlock()
+	tableConfigs.configs = configs
+	tableConfigs.hasErrorBudget = hasErrorBudget
+}
+
+// getTableConfig returns the auto analyze options of the table, or nil if they are not set.
+func getTableConfig(tableID int64) *TableConfig {
+	tableConfigs.RLock()
+	defer tableConfigs.RUnlock()
+	return tableConfigs.configs[tableID]
+}
+
+// getTablePriority returns the priority of the table which is added to the weight of its jobs.
+func getTablePriority(tableID int64) float64 {
+	if cfg := getTableConfig(tableID); cfg != nil && cfg.Priority != nil {
+		return *cfg.Priority
+	}
+	return 0
+}
+
+// getErrorBudget returns the target q-error of the table and the fraction of the estimations which should be under it.
+// It returns false if the table has no error budget. The fraction is 95% by default.
+func getErrorBudget(tableID int64) (targetQError, fraction float64, ok bool) {
+	cfg := getTableConfig(tableID)
+	if cfg == nil || cfg.TargetQError == nil {
+		return 0, 0, false
+	}
+	fraction = float64(defaultTargetQErrorPercentile) / 100
+	if cfg.TargetQErrorPercentile != nil {
+		fraction = *cfg.TargetQErrorPercentile / 100
+	}
+	return *cfg.TargetQError, fraction, true
+}
+
+// hasErrorBudget returns whether any table has the error budget.
+func hasErrorBudget() bool {
+	tableConfigs.RLock()
+	defer tableConfigs.RUnlock()
+	return tableConfigs.hasErrorBudget
+}
+
+// getAutoAnalyzeResourceGroup returns the resource group under which the jobs of the table are executed, so that
+// the cost of analyzing the table is charged to the workload owning it.
+// The RESOURCE_GROUP option of mysql.auto_analyze_config takes precedence over tidb_auto_analyze_resource_group.
+// It falls back to the default resource group if the resource group doesn't exist, e.g. it's dropped after being set.
+func getAutoAnalyzeResourceGroup(is infoschema.InfoSchema, tableID int64) string {
+	groupName := variable.AutoAnalyzeResourceGroup.Load()
+	if cfg := getTableConfig(tableID); cfg != nil && cfg.ResourceGroup != nil {
+		groupName = *cfg.ResourceGroup
+	}
+	if groupName == "" || groupName == resourcegroup.DefaultResourceGroupName {
+		return resourcegroup.DefaultResourceGroupName
+	}
+	if _, ok := is.ResourceGroupByName(pmodel.NewCIStr(groupName)); !ok {
+		statslogutil.StatsLogger().Warn("The resource group of auto analyze doesn't exist, use the default one",
+			zap.Int64("tableID", tableID),
+			zap.String("resourceGroup", groupName),
+		)
+		return resourcegroup.DefaultResourceGroupName
+	}
+	return groupName
+}
+
+// IsAutoAnalyzeDisabled checks whether auto analyze is disabled for the table, and returns the reason if so.
+// The DISABLED option of mysql.auto_analyze_config takes precedence over the table option STATS_AUTO_RECALC.
+func IsAutoAnalyzeDisabled(tblInfo *model.TableInfo) (bool, string) {
+	if cfg := getTableConfig(tblInfo.ID); cfg != nil && cfg.Disabled != nil {
+		return *cfg.Disabled, autoAnalyzeConfigDisabled
+	}
+	return IsAutoRecalcDisabled(tblInfo), autoRecalcDisabled
+}
+
+// isWithinTableWindow checks whether the current time is within the auto analyze window of the table.
+// The tables without their own windows are always within the window, since the global window is checked before.
+func isWithinTableWindow(tblInfo *model.TableInfo, now time.Time) bool {
+	cfg := getTableConfig(tblInfo.ID)
+	if cfg == nil || (cfg.WindowStart == nil && cfg.WindowEnd == nil) {
+		return true
+	}
+	var start, end string
+	if cfg.WindowStart != nil {
+		start = *cfg.WindowStart
+	}
+	if cfg.WindowEnd != nil {
+		end = *cfg.WindowEnd
+	}
+	s, e, err := exec.ParseAutoAnalysisWindow(start, end)
+	if err != nil {
+		// The options are validated when they are set, so it should not happen.
+		statslogutil.StatsLogger().Warn("Failed to parse the auto analyze window of the table",
+			zap.Int64("tableID", tblInfo.ID),
+			zap.Error(err),
+		)
+		return true
+	}
+	return NewAutoAnalysisTimeWindow(s, e).IsWithinTimeWindow(now)
+}
+
+// validateTableConfig checks the auto analyze options of the table again before analyzing it,
+// since they may be changed after the job is created. It returns whether the failed job needs to be retried.
+func validateTableConfig(tblInfo *model.TableInfo) (valid bool, needRetry bool, reason string) {
+	if disabled, disabledReason := IsAutoAnalyzeDisabled(tblInfo); disabled {
+		return false, false, disabledReason
+	}
+	if !isWithinTableWindow(tblInfo, Now()) {
+		// Retry the job later, it can be analyzed once the window comes.
+		return false, true, outOfTableWindow
+	}
+	return true, false, ""
+}
+
+// genAnalyzeOptionsClause generates the clause appended to the SQL for analyzing the tables or partitions,
+// i.e. the column choice, the quick analyze and the sample rate.
+// The options of mysql.auto_analyze_config take precedence over tidb_auto_analyze_column_choice.
+// The quick analyze and the sample rate are only supported by the version 2 stats.
+func genAnalyzeOptionsClause(tableID int64, statsVer int, tableSize float64) string {
+	columnChoice := variable.AutoAnalyzeColumnChoice.Load()
+	cfg := getTableConfig(tableID)
+	if cfg != nil && cfg.ColumnChoice != nil {
+		columnChoice = *cfg.ColumnChoice
+	}
+	var clause string
+	switch strings.ToUpper(columnChoice) {
+	case pmodel.PredicateColumns.String():
+		clause = " predicate columns"
+	case pmodel.AllColumns.String():
+		clause = " all columns"
+	}
+	// The tables larger than tidb_auto_analyze_quick_size_threshold are analyzed quickly with the approximate stats,
+	// otherwise the analysis may take hours and block the other jobs in the queue.
+	quickThreshold := variable.AutoAnalyzeQuickSizeThreshold.Load()
+	if quickThreshold > 0 && tableSize > float64(quickThreshold) && statsVer == statistics.Version2 {
+		clause += " quick"
+	}
+	if cfg != nil && cfg.SampleRate != nil && statsVer == statistics.Version2 {
+		clause += fmt.Sprintf(" with %v samplerate", *cfg.SampleRate)
+	}
+	return clause
+}
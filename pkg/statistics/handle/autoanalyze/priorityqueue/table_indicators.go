@@ -0,0 +1,145 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue
+
+import (
+	"context"
+	"math"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+)
+
+// GetTableIndicators returns the indicators of the tables and partitions.
+// If the schema is empty, all the user tables are returned.
+// If the table is empty, all the tables of the schema are returned.
+// Unlike the analysis jobs, the change percentage is not compared with the auto analyze ratio,
+// and the tables whose stats are not loaded or too small are also returned.
+func GetTableIndicators(
+	sctx sessionctx.Context,
+	statsHandle statstypes.StatsHandle,
+	schema, table pmodel.CIStr,
+) ([]statstypes.TableIndicators, error) {
+	is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
+	currentTs, err := statsutil.GetStartTS(sctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jobFactory := NewAnalysisJobFactory(sctx, 0, currentTs)
+
+	var dbs []pmodel.CIStr
+	if schema.L == "" {
+		dbs = is.AllSchemaNames()
+	} else {
+		if _, ok := is.SchemaByName(schema); !ok {
+			return nil, infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema.O)
+		}
+		dbs = []pmodel.CIStr{schema}
+	}
+
+	result := make([]statstypes.TableIndicators, 0)
+	for _, db := range dbs {
+		if !statsutil.IsAutoAnalyzeSchema(db.L) {
+			continue
+		}
+		var tbls []*model.TableInfo
+		if table.L == "" {
+			tbls, err = is.SchemaTableInfos(context.Background(), db)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		} else {
+			tbl, err := is.TableInfoByName(db, table)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			tbls = []*model.TableInfo{tbl}
+		}
+
+		for _, tblInfo := range tbls {
+			if tblInfo.IsView() || tblInfo.IsSequence() || !statsutil.IsAutoAnalyzeTable(db.L, tblInfo.Name.L) {
+				continue
+			}
+			result = append(result, jobFactory.calculateTableIndicators(
+				db.O, tblInfo.Name.O, "", tblInfo.ID,
+				statsHandle.GetPartitionStatsForAutoAnalyze(tblInfo, tblInfo.ID),
+			))
+			pi := tblInfo.GetPartitionInfo()
+			if pi == nil {
+				continue
+			}
+			for _, def := range pi.Definitions {
+				result = append(result, jobFactory.calculateTableIndicators(
+					db.O, tblInfo.Name.O, def.Name.O, def.ID,
+					statsHandle.GetPartitionStatsForAutoAnalyze(tblInfo, def.ID),
+				))
+			}
+		}
+	}
+	return result, nil
+}
+
+func (f *AnalysisJobFactory) calculateTableIndicators(
+	dbName, tableName, partitionName string,
+	physicalID int64,
+	tblStats *statistics.Table,
+) statstypes.TableIndicators {
+	indicators := statstypes.TableIndicators{
+		DBName:        dbName,
+		TableName:     tableName,
+		PartitionName: partitionName,
+		PhysicalID:    physicalID,
+		WorkloadClass: string(statistics.ClassifyWorkload(tblStats)),
+	}
+	if tblStats != nil {
+		indicators.InsertCount = tblStats.InsertCount
+		indicators.DeleteCount = tblStats.DeleteCount
+	}
+	// The stats may not be loaded yet, e.g. the table is just created.
+	if tblStats == nil || tblStats.Pseudo {
+		indicators.ChangePercentage = unanalyzedTableDefaultChangePercentage
+		if tblStats != nil {
+			indicators.PseudoStatsQueries = f.CalculatePseudoStatsQueries(tblStats)
+		}
+		return indicators
+	}
+	if tblStats.ColAndIdxExistenceMap != nil {
+		indicators.TableSize = float64(tblStats.RealtimeCount) * float64(tblStats.ColAndIdxExistenceMap.ColNum())
+	}
+	if !tblStats.IsAnalyzed() {
+		// All the rows are considered as changed if the table has not been analyzed.
+		indicators.ChangePercentage = unanalyzedTableDefaultChangePercentage
+		return indicators
+	}
+	indicators.Analyzed = true
+	indicators.ChangePercentage = calculateRawChangePercentage(tblStats)
+	// The row count may be zero, e.g. all the rows are deleted after the last analysis.
+	if math.IsNaN(indicators.ChangePercentage) {
+		indicators.ChangePercentage = 0
+	} else if math.IsInf(indicators.ChangePercentage, 0) {
+		indicators.ChangePercentage = unanalyzedTableDefaultChangePercentage
+	}
+	indicators.ShrinkPercentage = calculateRawShrinkPercentage(tblStats)
+	indicators.EstimationError = f.CalculateEstimationError(tblStats)
+	indicators.VerifiedDrift = f.CalculateVerifiedDrift(tblStats)
+	indicators.LastAnalysisDuration = f.GetTableLastAnalyzeDuration(tblStats)
+	return indicators
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorityqueue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTableIndicators(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create schema example_schema")
+	tk.MustExec("use example_schema")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("create table t2 (a int) partition by hash(a) partitions 2")
+	tk.MustExec("create view v as select * from t1")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3), (4, 4)")
+	tk.MustExec("insert into t2 values (1), (2)")
+	h := dom.StatsHandle()
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t1")
+	tk.MustExec("insert into t1 values (5, 5)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+
+	getIndicators := func(schema, table string) (indicators []statstypes.TableIndicators, err error) {
+		err = statsutil.CallWithSCtx(h.SPool(), func(sctx sessionctx.Context) error {
+			indicators, err = priorityqueue.GetTableIndicators(sctx, h, model.NewCIStr(schema), model.NewCIStr(table))
+			return err
+		}, statsutil.FlagWrapTxn)
+		return indicators, err
+	}
+
+	indicators, err := getIndicators("example_schema", "t1")
+	require.NoError(t, err)
+	require.Len(t, indicators, 1)
+	require.Equal(t, "example_schema", indicators[0].DBName)
+	require.Equal(t, "t1", indicators[0].TableName)
+	require.True(t, indicators[0].Analyzed)
+	require.Equal(t, 0.2, indicators[0].ChangePercentage)
+	require.Equal(t, float64(5*2), indicators[0].TableSize)
+	require.Greater(t, indicators[0].LastAnalysisDuration, time.Duration(0))
+	require.Equal(t, string(statistics.WorkloadClassSmallDimension), indicators[0].WorkloadClass)
+
+	// The view is skipped, the partitioned table returns the global table and all the partitions.
+	indicators, err = getIndicators("example_schema", "")
+	require.NoError(t, err)
+	require.Len(t, indicators, 4)
+	partitions := make([]string, 0, 3)
+	for _, indicator := range indicators {
+		if indicator.TableName != "t2" {
+			continue
+		}
+		partitions = append(partitions, indicator.PartitionName)
+		require.False(t, indicator.Analyzed)
+		require.Equal(t, float64(1), indicator.ChangePercentage)
+		require.Equal(t, string(statistics.WorkloadClassGeneral), indicator.WorkloadClass)
+	}
+	require.ElementsMatch(t, []string{"", "p0", "p1"}, partitions)
+
+	// All the user tables are returned if the schema is empty.
+	indicators, err = getIndicators("", "")
+	require.NoError(t, err)
+	require.Len(t, indicators, 4)
+
+	_, err = getIndicators("not_exist", "")
+	require.True(t, infoschema.ErrDatabaseNotExists.Equal(err))
+	_, err = getIndicators("example_schema", "not_exist")
+	require.True(t, infoschema.ErrTableNotExists.Equal(err))
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeDebt(t *testing.T) {
+	h := testkit.NewHarness(t)
+	h.MustExec("create table t (a int, b int)")
+	h.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	h.MustExec("analyze table t")
+	tableID := h.TableID("test", "t")
+	analyzeDebt := func() (debt, throughput, eta float64) {
+		values := make(map[string]float64, len(priorityqueue.AnalyzeDebtKeys))
+		rows := h.TestKit().MustQuery("show global status like 'tidb_analyze_%'").Rows()
+		require.Len(t, rows, len(priorityqueue.AnalyzeDebtKeys))
+		for _, row := range rows {
+			value, err := strconv.ParseFloat(row[1].(string), 64)
+			require.NoError(t, err)
+			values[row[0].(string)] = value
+		}
+		return values[priorityqueue.AnalyzeDebtBytesKey], values[priorityqueue.AnalyzeThroughputKey], values[priorityqueue.AnalyzeDebtETAKey]
+	}
+
+	// No debt after the table is analyzed.
+	require.False(t, h.Tick())
+	h.Advance(time.Minute)
+	debt, throughput, eta := analyzeDebt()
+	require.Zero(t, debt)
+	require.Zero(t, throughput)
+	require.Zero(t, eta)
+
+	// The debt is never caught up since no job has finished.
+	h.ApplyDelta(tableID, 3, 3)
+	h.Advance(time.Minute)
+	require.Equal(t, []int64{tableID}, h.QueuedTableIDs())
+	debt, throughput, eta = analyzeDebt()
+	require.Greater(t, debt, float64(0))
+	require.Zero(t, throughput)
+	require.Equal(t, float64(-1), eta)
+
+	// The finished job clears the debt and contributes to the throughput.
+	require.True(t, h.Tick())
+	h.Advance(time.Minute)
+	debt, throughput, eta = analyzeDebt()
+	require.Zero(t, debt)
+	require.Greater(t, throughput, float64(0))
+	require.Zero(t, eta)
+
+	// The new debt is caught up at the throughput.
+	h.ApplyDelta(tableID, 3, 3)
+	h.Advance(time.Minute)
+	debt, throughput, eta = analyzeDebt()
+	require.Greater(t, debt, float64(0))
+	require.Greater(t, throughput, float64(0))
+	// The throughput is rounded to 2 decimals.
+	require.InEpsilon(t, debt/throughput, eta, 0.1)
+}
@@ -0,0 +1,255 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/ddl/notifier"
+	"github.com/pingcap/tidb/pkg/domain"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/refresher"
+	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	tidbtestkit "github.com/pingcap/tidb/pkg/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+// VirtualClock is a clock which only moves forward by Advance.
+type VirtualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewVirtualClock creates a VirtualClock starting from the time.
+func NewVirtualClock(start time.Time) *VirtualClock {
+	return &VirtualClock{now: start}
+}
+
+// Now implements the priorityqueue.Clock interface.
+func (c *VirtualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by the duration.
+func (c *VirtualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Harness drives the refresher of auto analyze deterministically, so the scheduling behaviors can be reproduced by
+// the regression tests step by step:
+//  1. The stats deltas can be written to the stats meta directly by ApplyDelta, rather than collected from DML.
+//  2. The DDL events are handed to the priority queue synchronously by ExecDDL and HandleDDLEvent, rather than
+//     delivered by the DDL notifier in the background.
+//  3. The time of the scheduling is a virtual clock which only moves by Advance, and the periodic maintenance of the
+//     priority queue, which runs in the background in production, is run by Advance too.
+//
+// The jobs are dispatched by Tick, which waits for them to finish. AutoAnalyzeMinCnt is 0 in the harness, so the
+// small tables of the tests can be analyzed automatically.
+type Harness struct {
+	t         testing.TB
+	dom       *domain.Domain
+	tk        *tidbtestkit.TestKit
+	clock     *VirtualClock
+	refresher *refresher.Refresher
+}
+
+// NewHarness creates a Harness on a new mock store, whose virtual clock starts from the current time. The current
+// database of its session is `test`.
+func NewHarness(t testing.TB) *Harness {
+	store, dom := tidbtestkit.CreateMockStoreAndDomain(t)
+	tk := tidbtestkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	clock := NewVirtualClock(time.Now())
+	t.Cleanup(priorityqueue.SetVirtualClockForTest(clock))
+	origMinCnt := statistics.AutoAnalyzeMinCnt
+	statistics.AutoAnalyzeMinCnt = 0
+	t.Cleanup(func() {
+		statistics.AutoAnalyzeMinCnt = origMinCnt
+	})
+	// The DDL events are handed to the priority queue by the harness rather than the DDL notifier.
+	r := refresher.NewRefresher(dom.StatsHandle(), dom.SysProcTracker(), nil)
+	t.Cleanup(r.Close)
+	return &Harness{
+		t:         t,
+		dom:       dom,
+		tk:        tk,
+		clock:     clock,
+		refresher: r,
+	}
+}
+
+// TestKit returns the test kit of the harness.
+func (h *Harness) TestKit() *tidbtestkit.TestKit {
+	return h.tk
+}
+
+// Domain returns the domain of the harness.
+func (h *Harness) Domain() *domain.Domain {
+	return h.dom
+}
+
+// Clock returns the virtual clock of the harness.
+func (h *Harness) Clock() *VirtualClock {
+	return h.clock
+}
+
+// Refresher returns the refresher driven by the harness.
+func (h *Harness) Refresher() *refresher.Refresher {
+	return h.refresher
+}
+
+// MustExec executes the statement, and flushes the stats deltas of the DML to the stats cache.
+func (h *Harness) MustExec(sql string, args ...any) {
+	h.tk.MustExec(sql, args...)
+	h.FlushDeltas()
+}
+
+// FlushDeltas dumps the stats deltas collected from the DML to the stats meta, and updates the stats cache.
+func (h *Harness) FlushDeltas() {
+	handle := h.dom.StatsHandle()
+	require.NoError(h.t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(h.t, handle.Update(context.Background(), h.dom.InfoSchema()))
+}
+
+// TableID returns the ID of the table in the database.
+func (h *Harness) TableID(db, table string) int64 {
+	tbl, err := h.dom.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr(db), pmodel.NewCIStr(table))
+	require.NoError(h.t, err)
+	return tbl.Meta().ID
+}
+
+// PartitionID returns the ID of the partition of the table in the database.
+func (h *Harness) PartitionID(db, table, partition string) int64 {
+	tbl, err := h.dom.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr(db), pmodel.NewCIStr(table))
+	require.NoError(h.t, err)
+	pi := tbl.Meta().GetPartitionInfo()
+	require.NotNil(h.t, pi, "table %s.%s is not partitioned", db, table)
+	id := pi.GetPartitionIDByName(partition)
+	require.NotEqual(h.t, int64(-1), id, "partition %s doesn't exist", partition)
+	return id
+}
+
+// ApplyDelta writes the synthetic stats delta of the table or partition to the stats meta as if the DML were
+// executed, i.e. the row count changes by rowCountDelta and the modify count increases by modifyCount, and updates
+// the stats cache. The deltas of the partitioned tables are not rolled up, so the tables in the dynamic prune mode
+// need their own deltas.
+func (h *Harness) ApplyDelta(physicalID int64, rowCountDelta, modifyCount int64) {
+	handle := h.dom.StatsHandle()
+	delta := variable.TableDelta{Delta: rowCountDelta, Count: modifyCount}
+	if rowCountDelta > 0 {
+		delta.InsertCount = rowCountDelta
+	} else {
+		delta.DeleteCount = -rowCountDelta
+	}
+	require.NoError(h.t, statsutil.CallWithSCtx(handle.SPool(), func(sctx sessionctx.Context) error {
+		startTS, err := statsutil.GetStartTS(sctx)
+		if err != nil {
+			return err
+		}
+		return storage.UpdateStatsMeta(statsutil.StatsCtx, sctx, startTS, delta, physicalID, false)
+	}, statsutil.FlagWrapTxn))
+	require.NoError(h.t, handle.Update(context.Background(), h.dom.InfoSchema()))
+}
+
+// ExecDDL executes the DDL statement, and hands its events to the stats handle and the priority queue, up to the
+// first one of the action type. The events of the DDL statements executed by MustExec before are handed over too.
+func (h *Harness) ExecDDL(sql string, action model.ActionType) {
+	h.tk.MustExec(sql)
+	handle := h.dom.StatsHandle()
+	for {
+		select {
+		case event := <-handle.DDLEventCh():
+			h.HandleDDLEvent(event)
+			if event.GetType() == action {
+				return
+			}
+		case <-time.After(10 * time.Second):
+			require.FailNow(h.t, "the DDL event is not found", "action: %s", action)
+		}
+	}
+}
+
+// HandleDDLEvent hands the DDL event to the stats handle and the priority queue, e.g. the synthetic events built by
+// the constructors of the notifier package. The events are ignored by the priority queue before it's initialized,
+// since it's built from scratch by the first Tick.
+func (h *Harness) HandleDDLEvent(event *notifier.SchemaChangeEvent) {
+	handle := h.dom.StatsHandle()
+	require.NoError(h.t, handle.HandleDDLEvent(event))
+	err := statsutil.CallWithSCtx(handle.SPool(), func(sctx sessionctx.Context) error {
+		return h.refresher.HandleDDLEventForTest(statsutil.StatsCtx, sctx, event)
+	}, statsutil.FlagWrapTxn)
+	if errors.Is(err, notifier.ErrNotReadyRetryLater) {
+		return
+	}
+	require.NoError(h.t, err)
+}
+
+// Advance moves the virtual clock forward by the duration, and runs the periodic maintenance of the priority queue,
+// i.e. processes the DML changes, refreshes the last analysis durations, requeues the jobs which must be retried and
+// refreshes the analyze debt.
+func (h *Harness) Advance(d time.Duration) {
+	h.clock.Advance(d)
+	h.refresher.ProcessDMLChangesForTest()
+	h.refresher.RefreshLastAnalysisDurationForTest()
+	h.refresher.RequeueMustRetryJobsForTest()
+	h.refresher.RefreshAnalyzeDebtForTest()
+}
+
+// Tick runs a round of auto analyze, which initializes the priority queue if needed and dispatches the jobs of the
+// highest priorities, and waits for the jobs to finish. It returns whether any job is dispatched.
+func (h *Harness) Tick() bool {
+	handle := h.dom.StatsHandle()
+	var dispatched bool
+	require.NoError(h.t, statsutil.CallWithSCtx(handle.SPool(), func(sctx sessionctx.Context) error {
+		dispatched = h.refresher.AnalyzeHighestPriorityTables(sctx)
+		return nil
+	}))
+	h.refresher.WaitAutoAnalyzeFinishedForTest()
+	require.NoError(h.t, handle.Update(context.Background(), h.dom.InfoSchema()))
+	return dispatched
+}
+
+// QueuedJobs returns the jobs in the priority queue in the descending order of their weights.
+func (h *Harness) QueuedJobs() []statstypes.AnalysisJobJSON {
+	snapshot, err := h.refresher.GetPriorityQueueSnapshot()
+	require.NoError(h.t, err)
+	return snapshot.CurrentJobs
+}
+
+// QueuedTableIDs returns the table IDs of the jobs in the priority queue in the descending order of their weights.
+func (h *Harness) QueuedTableIDs() []int64 {
+	jobs := h.QueuedJobs()
+	ids := make([]int64, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.TableID)
+	}
+	return ids
+}
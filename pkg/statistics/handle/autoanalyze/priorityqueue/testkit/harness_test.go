@@ -0,0 +1,54 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarness(t *testing.T) {
+	h := testkit.NewHarness(t)
+	h.MustExec("create table t (a int, b int)")
+	h.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	h.MustExec("analyze table t")
+	tableID := h.TableID("test", "t")
+
+	// The queue is initialized by the first tick, and the table has just been analyzed.
+	start := h.Clock().Now()
+	require.Equal(t, start, priorityqueue.Now())
+	require.False(t, h.Tick())
+	require.Empty(t, h.QueuedTableIDs())
+
+	// The synthetic delta is picked up by the maintenance of the queue.
+	h.ApplyDelta(tableID, 10, 10)
+	require.Empty(t, h.QueuedTableIDs())
+	h.Advance(time.Hour)
+	require.Equal(t, start.Add(time.Hour), priorityqueue.Now())
+	require.Equal(t, []int64{tableID}, h.QueuedTableIDs())
+	require.True(t, h.Tick())
+	require.Empty(t, h.QueuedTableIDs())
+
+	// The table is queued by the DDL event of the new index.
+	h.ExecDDL("alter table t add index ia(a)", model.ActionAddIndex)
+	require.Equal(t, []int64{tableID}, h.QueuedTableIDs())
+	require.True(t, h.Tick())
+	require.Empty(t, h.QueuedTableIDs())
+}
@@ -15,8 +15,8 @@
 package refresher
 
 import (
+	"context"
 	stderrors "errors"
-	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/ddl/notifier"
@@ -53,6 +53,10 @@ type Refresher struct {
 	// lastSeenAutoAnalyzeRatio is the last seen value of the auto analyze ratio.
 	// Used to detect changes in the auto analyze ratio.
 	lastSeenAutoAnalyzeRatio float64
+
+	// runningStores records the stores of the region leaders of the dispatched jobs, which are staggered by
+	// tidb_auto_analyze_max_jobs_per_store.
+	runningStores map[int64][]uint64
 }
 
 // NewRefresher creates a new Refresher and starts the goroutine.
@@ -67,6 +71,7 @@ func NewRefresher(
 		sysProcTracker: sysProcTracker,
 		jobs:           priorityqueue.NewAnalysisPriorityQueue(statsHandle),
 		worker:         NewWorker(statsHandle, sysProcTracker, maxConcurrency),
+		runningStores:  make(map[int64][]uint64),
 	}
 	if ddlNotifier != nil {
 		ddlNotifier.RegisterHandler(notifier.PriorityQueueHandlerID, r.jobs.HandleDDLEvent)
@@ -127,8 +132,28 @@ func (r *Refresher) AnalyzeHighestPriorityTables(sctx sessionctx.Context) bool {
 		return false
 	}
 
+	priorityqueue.RefreshDecisionLogTables(sctx)
+	priorityqueue.RefreshTableConfigs(sctx)
+	for tableID := range r.runningStores {
+		if _, isRunning := currentRunningJobs[tableID]; !isRunning {
+			delete(r.runningStores, tableID)
+		}
+	}
+	stagger := newStoreStagger(sctx.GetStore(), int(variable.AutoAnalyzeMaxJobsPerStore.Load()), r.runningStores)
+	var staggeredJobs []priorityqueue.AnalysisJob
+	defer func() {
+		// Push the staggered jobs back, so they are dispatched in the next rounds.
+		for _, job := range staggeredJobs {
+			if err := r.jobs.Requeue(job); err != nil {
+				statslogutil.StatsLogger().Warn("Failed to requeue the staggered job", zap.Stringer("job", job), zap.Error(err))
+			}
+		}
+	}()
 	analyzedCount := 0
 	for analyzedCount < remainConcurrency {
+		if stagger != nil && stagger.exhausted() {
+			break
+		}
 		job, err := r.jobs.Pop()
 		if err != nil {
 			// No more jobs to analyze.
@@ -142,6 +167,7 @@ func (r *Refresher) AnalyzeHighestPriorityTables(sctx sessionctx.Context) bool {
 
 		if _, isRunning := currentRunningJobs[job.GetTableID()]; isRunning {
 			statslogutil.StatsLogger().Debug("Job already running, skipping", zap.Int64("tableID", job.GetTableID()))
+			priorityqueue.LogDecision(job, priorityqueue.DecisionSkip, "job is running")
 			continue
 		}
 		if valid, failReason := job.ValidateAndPrepare(sctx); !valid {
@@ -150,8 +176,20 @@ func (r *Refresher) AnalyzeHighestPriorityTables(sctx sessionctx.Context) bool {
 				zap.String("reason", failReason),
 				zap.Stringer("job", job),
 			)
+			priorityqueue.LogDecision(job, priorityqueue.DecisionSkip, failReason)
 			continue
 		}
+		var stores []uint64
+		if stagger != nil {
+			var canDispatch bool
+			stores, canDispatch = stagger.tryDispatch(job)
+			if !canDispatch {
+				statslogutil.StatsLogger().Debug("Job staggered by the stores", zap.Stringer("job", job), zap.Uint64s("stores", stores))
+				priorityqueue.LogDecision(job, priorityqueue.DecisionSkip, "staggered by the stores")
+				staggeredJobs = append(staggeredJobs, job)
+				continue
+			}
+		}
 
 		statslogutil.StatsLogger().Info("Auto analyze triggered", zap.Stringer("job", job))
 
@@ -160,6 +198,11 @@ func (r *Refresher) AnalyzeHighestPriorityTables(sctx sessionctx.Context) bool {
 			"This should not occur as the concurrency limit was checked prior to job submission. "+
 			"Please investigate potential race conditions or inconsistencies in the concurrency management logic.")
 		if submitted {
+			priorityqueue.LogDecision(job, priorityqueue.DecisionDispatch, "")
+			if stagger != nil {
+				stagger.dispatched(stores)
+				r.runningStores[job.GetTableID()] = stores
+			}
 			statslogutil.StatsLogger().Debug("Job submitted successfully",
 				zap.Stringer("job", job),
 				zap.Int("remainConcurrency", remainConcurrency),
@@ -193,6 +236,21 @@ func (r *Refresher) GetPriorityQueueSnapshot() (statstypes.PriorityQueueSnapshot
 	return r.jobs.Snapshot()
 }
 
+// GetRunningJobsSnapshot returns the JSON of the jobs being run by the worker.
+func (r *Refresher) GetRunningJobsSnapshot() []statstypes.RunningAnalysisJobJSON {
+	return r.worker.GetRunningJobsSnapshot()
+}
+
+// EnqueueAnalysisJob pushes the analysis job of the table or partition into the priority queue.
+func (r *Refresher) EnqueueAnalysisJob(physicalID int64, weight float64, analyzeDefaults *variable.AnalyzeDefaults) error {
+	return r.jobs.EnqueueJob(physicalID, weight, analyzeDefaults)
+}
+
+// RemoveAnalysisJobs removes the pending analysis jobs of the tables or partitions from the priority queue.
+func (r *Refresher) RemoveAnalysisJobs(physicalIDs []int64) (int, error) {
+	return r.jobs.RemoveJobs(physicalIDs)
+}
+
 func (r *Refresher) setAutoAnalysisTimeWindow(
 	parameters map[string]string,
 ) error {
@@ -209,7 +267,7 @@ func (r *Refresher) setAutoAnalysisTimeWindow(
 
 // isWithinTimeWindow checks if the current time is within the auto analyze time window.
 func (r *Refresher) isWithinTimeWindow() bool {
-	return r.autoAnalysisTimeWindow.IsWithinTimeWindow(time.Now())
+	return r.autoAnalysisTimeWindow.IsWithinTimeWindow(priorityqueue.Now())
 }
 
 // WaitAutoAnalyzeFinishedForTest waits for the auto analyze job to be finished.
@@ -238,6 +296,27 @@ func (r *Refresher) RequeueMustRetryJobsForTest() {
 	r.jobs.RequeueMustRetryJobs()
 }
 
+// RefreshLastAnalysisDurationForTest refreshes the last analysis durations of the jobs for the test.
+// Only used in the test.
+func (r *Refresher) RefreshLastAnalysisDurationForTest() {
+	if r.jobs.IsInitialized() {
+		r.jobs.RefreshLastAnalysisDuration()
+	}
+}
+
+// RefreshAnalyzeDebtForTest refreshes the analyze debt for the test.
+// Only used in the test.
+func (r *Refresher) RefreshAnalyzeDebtForTest() {
+	r.jobs.RefreshAnalyzeDebt()
+}
+
+// HandleDDLEventForTest handles the DDL event in the priority queue for the test, which is done by the DDL notifier
+// if the refresher is created with it.
+// Only used in the test.
+func (r *Refresher) HandleDDLEventForTest(ctx context.Context, sctx sessionctx.Context, event *notifier.SchemaChangeEvent) error {
+	return r.jobs.HandleDDLEvent(ctx, sctx, event)
+}
+
 // Len returns the length of the analysis job queue.
 func (r *Refresher) Len() int {
 	l, err := r.jobs.Len()
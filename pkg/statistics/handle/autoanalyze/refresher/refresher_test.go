@@ -62,6 +62,7 @@ func TestChangePruneMode(t *testing.T) {
 		return nil
 	}))
 	r.WaitAutoAnalyzeFinishedForTest()
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
 	require.Equal(t, 0, r.Len())
 
 	// Insert more data to each partition.
@@ -76,6 +77,7 @@ func TestChangePruneMode(t *testing.T) {
 		return nil
 	}))
 	r.WaitAutoAnalyzeFinishedForTest()
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
 	require.Equal(t, 0, r.Len())
 }
 
@@ -377,6 +379,66 @@ func TestAnalyzeHighestPriorityTablesConcurrently(t *testing.T) {
 	require.Equal(t, int64(6), tblStats3.RealtimeCount)
 }
 
+func TestStaggerJobsByStores(t *testing.T) {
+	statistics.AutoAnalyzeMinCnt = 0
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = 1000
+	}()
+
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set global tidb_enable_auto_analyze=true")
+	tk.MustExec("set global tidb_auto_analyze_concurrency=2")
+	tk.MustExec("set global tidb_auto_analyze_max_jobs_per_store=1")
+	defer tk.MustExec("set global tidb_auto_analyze_max_jobs_per_store=default")
+	tk.MustExec("create table t1 (a int, b int, index idx(a))")
+	tk.MustExec("create table t2 (a int, b int, index idx(a))")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (3, 3)")
+	handle := dom.StatsHandle()
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
+	tk.MustExec("analyze table t1")
+	tk.MustExec("analyze table t2")
+	// More data is inserted into t1, so it's analyzed first.
+	tk.MustExec("insert into t1 values (4, 4), (5, 5), (6, 6), (7, 7), (8, 8), (9, 9)")
+	tk.MustExec("insert into t2 values (4, 4), (5, 5), (6, 6)")
+	require.NoError(t, handle.DumpStatsDeltaToKV(true))
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
+	r := refresher.NewRefresher(handle, dom.SysProcTracker(), dom.DDLNotifier())
+	defer r.Close()
+
+	// All the regions of the mock store are on the same store, so only t1 is analyzed although the concurrency is 2.
+	require.NoError(t, util.CallWithSCtx(handle.SPool(), func(sctx sessionctx.Context) error {
+		require.True(t, r.AnalyzeHighestPriorityTables(sctx))
+		return nil
+	}))
+	r.WaitAutoAnalyzeFinishedForTest()
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
+	require.Equal(t, 1, r.Len())
+	tbl1 := dom.MustGetTableInfo(t, "test", "t1")
+	tbl2 := dom.MustGetTableInfo(t, "test", "t2")
+	tblStats1, ok := handle.Get(tbl1.ID)
+	require.True(t, ok)
+	require.Equal(t, int64(0), tblStats1.ModifyCount)
+	tblStats2, ok := handle.Get(tbl2.ID)
+	require.True(t, ok)
+	require.Equal(t, int64(3), tblStats2.ModifyCount)
+
+	// The staggered job of t2 is dispatched in the next round.
+	require.NoError(t, util.CallWithSCtx(handle.SPool(), func(sctx sessionctx.Context) error {
+		require.True(t, r.AnalyzeHighestPriorityTables(sctx))
+		return nil
+	}))
+	r.WaitAutoAnalyzeFinishedForTest()
+	require.NoError(t, handle.Update(context.Background(), dom.InfoSchema()))
+	require.Equal(t, 0, r.Len())
+	tblStats2, ok = handle.Get(tbl2.ID)
+	require.True(t, ok)
+	require.Equal(t, int64(0), tblStats2.ModifyCount)
+}
+
 func TestDoNotRetryTableNotExistJob(t *testing.T) {
 	statistics.AutoAnalyzeMinCnt = 0
 	defer func() {
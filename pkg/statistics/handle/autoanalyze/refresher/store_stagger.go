@@ -0,0 +1,144 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package refresher
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/pkg/kv"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	"github.com/pingcap/tidb/pkg/tablecodec"
+	"github.com/tikv/client-go/v2/tikv"
+)
+
+const (
+	// maxStaggerRegions is the max number of the regions of a table whose jobs are staggered. The regions of the
+	// larger tables are spread over the stores, and so are the scans of their jobs.
+	maxStaggerRegions = 64
+	// maxStaggeredJobsPerRound bounds the jobs staggered in a round, since the regions of every popped job are
+	// located.
+	maxStaggeredJobsPerRound = 16
+	// locateRegionsMaxBackoff is the max backoff in milliseconds to locate the regions of a table.
+	locateRegionsMaxBackoff = 2000
+)
+
+// storeStagger limits the number of the auto analyze jobs running on the tables whose region leaders are on the same
+// store in a round of dispatching, see tidb_auto_analyze_max_jobs_per_store.
+type storeStagger struct {
+	store tikv.Storage
+	limit int
+	// jobs counts the running and the dispatched jobs of each store.
+	jobs map[uint64]int
+	// reserved are the stores of the staggered jobs. The jobs of the lower priorities on them are staggered too, so the
+	// staggered jobs are dispatched first once the stores are free, rather than starved by the others.
+	reserved map[uint64]struct{}
+	// staggered is the number of the staggered jobs.
+	staggered int
+}
+
+// newStoreStagger creates a storeStagger from the stores of the running jobs. It returns nil if the jobs are not
+// staggered, i.e. the limit is not set or the storage is not TiKV.
+func newStoreStagger(store kv.Storage, limit int, runningStores map[int64][]uint64) *storeStagger {
+	if limit <= 0 {
+		return nil
+	}
+	tikvStore, ok := store.(tikv.Storage)
+	if !ok {
+		return nil
+	}
+	s := &storeStagger{
+		store:    tikvStore,
+		limit:    limit,
+		jobs:     make(map[uint64]int),
+		reserved: make(map[uint64]struct{}),
+	}
+	for _, stores := range runningStores {
+		for _, storeID := range stores {
+			s.jobs[storeID]++
+		}
+	}
+	return s
+}
+
+// exhausted returns whether no more jobs should be popped in the round.
+func (s *storeStagger) exhausted() bool {
+	return s.staggered >= maxStaggeredJobsPerRound
+}
+
+// tryDispatch returns the stores of the job and whether it can be dispatched. The stores are reserved by the job if
+// it's staggered. The stores are nil if the job is not staggered by the stores, e.g. the table has too many regions,
+// or its regions can't be located.
+func (s *storeStagger) tryDispatch(job priorityqueue.AnalysisJob) ([]uint64, bool) {
+	stores := s.jobStores(job)
+	canDispatch := true
+	for _, storeID := range stores {
+		if _, ok := s.reserved[storeID]; ok || s.jobs[storeID] >= s.limit {
+			canDispatch = false
+			break
+		}
+	}
+	if !canDispatch {
+		s.staggered++
+		for _, storeID := range stores {
+			s.reserved[storeID] = struct{}{}
+		}
+	}
+	return stores, canDispatch
+}
+
+// dispatched records the stores of the dispatched job.
+func (s *storeStagger) dispatched(stores []uint64) {
+	for _, storeID := range stores {
+		s.jobs[storeID]++
+	}
+}
+
+// jobStores returns the stores of the leaders of the regions of the tables or partitions analyzed by the job.
+func (s *storeStagger) jobStores(job priorityqueue.AnalysisJob) []uint64 {
+	jobJSON := job.AsJSON()
+	physicalIDs := make([]int64, 0, len(jobJSON.PartitionIDs)+len(jobJSON.PartitionIndexIDs))
+	physicalIDs = append(physicalIDs, jobJSON.PartitionIDs...)
+	for partitionID := range jobJSON.PartitionIndexIDs {
+		physicalIDs = append(physicalIDs, partitionID)
+	}
+	if len(physicalIDs) == 0 {
+		physicalIDs = append(physicalIDs, jobJSON.TableID)
+	}
+	regionCache := s.store.GetRegionCache()
+	bo := tikv.NewBackofferWithVars(context.Background(), locateRegionsMaxBackoff, nil)
+	stores := make(map[uint64]struct{})
+	regions := 0
+	for _, physicalID := range physicalIDs {
+		startKey := tablecodec.GenTablePrefix(physicalID)
+		locs, err := regionCache.LocateKeyRange(bo, startKey, startKey.PrefixNext())
+		if err != nil {
+			return nil
+		}
+		regions += len(locs)
+		if regions > maxStaggerRegions {
+			return nil
+		}
+		for _, loc := range locs {
+			if region := regionCache.GetCachedRegionWithRLock(loc.Region); region != nil {
+				stores[region.GetLeaderStoreID()] = struct{}{}
+			}
+		}
+	}
+	storeIDs := make([]uint64, 0, len(stores))
+	for storeID := range stores {
+		storeIDs = append(storeIDs, storeID)
+	}
+	return storeIDs
+}
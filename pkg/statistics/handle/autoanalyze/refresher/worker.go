@@ -15,6 +15,7 @@
 package refresher
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -37,7 +38,9 @@ type worker struct {
 
 	mu sync.Mutex
 	// mu is used to protect the following fields.
-	runningJobs    map[int64]struct{}
+	// runningJobs records the JSON of the running jobs, which is taken when they are submitted, since the jobs
+	// may be changed by the analysis.
+	runningJobs    map[int64]statstypes.RunningAnalysisJobJSON
 	maxConcurrency int
 }
 
@@ -46,7 +49,7 @@ func NewWorker(statsHandle statstypes.StatsHandle, sysProcTracker sysproctrack.T
 	w := &worker{
 		statsHandle:    statsHandle,
 		sysProcTracker: sysProcTracker,
-		runningJobs:    make(map[int64]struct{}),
+		runningJobs:    make(map[int64]statstypes.RunningAnalysisJobJSON),
 		maxConcurrency: maxConcurrency,
 	}
 	return w
@@ -76,7 +79,10 @@ func (w *worker) SubmitJob(job priorityqueue.AnalysisJob) bool {
 		statslogutil.StatsLogger().Warn("Worker at maximum capacity, job discarded", zap.Stringer("job", job))
 		return false
 	}
-	w.runningJobs[job.GetTableID()] = struct{}{}
+	w.runningJobs[job.GetTableID()] = statstypes.RunningAnalysisJobJSON{
+		AnalysisJobJSON: job.AsJSON(),
+		StartTime:       time.Now(),
+	}
 
 	w.wg.RunWithRecover(
 		func() {
@@ -119,6 +125,20 @@ func (w *worker) GetRunningJobs() map[int64]struct{} {
 	return runningJobs
 }
 
+// GetRunningJobsSnapshot returns the JSON of the running jobs, sorted by the start time.
+func (w *worker) GetRunningJobsSnapshot() []statstypes.RunningAnalysisJobJSON {
+	w.mu.Lock()
+	jobs := make([]statstypes.RunningAnalysisJobJSON, 0, len(w.runningJobs))
+	for _, job := range w.runningJobs {
+		jobs = append(jobs, job)
+	}
+	w.mu.Unlock()
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].StartTime.Before(jobs[j].StartTime)
+	})
+	return jobs
+}
+
 // GetMaxConcurrency returns the maximum concurrency for the worker.
 func (w *worker) GetMaxConcurrency() int {
 	w.mu.Lock()
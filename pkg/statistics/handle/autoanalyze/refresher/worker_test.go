@@ -68,7 +68,7 @@ func (m *mockAnalysisJob) SetIndicators(indicators priorityqueue.Indicators) {
 	panic("not implemented")
 }
 func (m *mockAnalysisJob) AsJSON() statstypes.AnalysisJobJSON {
-	panic("not implemented")
+	return statstypes.AnalysisJobJSON{TableID: m.tableID}
 }
 
 func TestWorker(t *testing.T) {
@@ -135,6 +135,7 @@ func TestWorker(t *testing.T) {
 		require.Len(t, runningJobs, 2)
 		require.Contains(t, runningJobs, int64(1))
 		require.Contains(t, runningJobs, int64(2))
+		require.Len(t, w.GetRunningJobsSnapshot(), 2)
 
 		w.WaitAutoAnalyzeFinishedForTest()
 		require.Empty(t, w.GetRunningJobs())
@@ -0,0 +1,157 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoanalyze
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/priorityqueue"
+	statsmetrics "github.com/pingcap/tidb/pkg/statistics/handle/metrics"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+)
+
+const (
+	// StatsAlertTypeStale means the change percentage of the table exceeds tidb_stats_alert_stale_ratio.
+	StatsAlertTypeStale = "stale"
+	// StatsAlertTypeEstimationError means the estimation error of the table exceeds
+	// tidb_stats_alert_estimation_error_threshold.
+	StatsAlertTypeEstimationError = "estimation_error"
+
+	// statsAlertsMaxAge is how long the alerts are kept in mysql.stats_alerts.
+	statsAlertsMaxAge = 7 * 24 * time.Hour
+)
+
+type statsAlert struct {
+	indicators statstypes.TableIndicators
+	tp         string
+	value      float64
+	threshold  float64
+	skipReason string
+}
+
+// CheckStatsAlerts implements the StatsAnalyze interface.
+// It records an alert in mysql.stats_alerts for every table or partition whose change percentage or estimation
+// error exceeds the threshold although auto analyze is enabled for it, along with the latest reason why the
+// priority queue skipped its job. The number of the alerting tables is reported by the metrics.
+func (sa *statsAnalyze) CheckStatsAlerts() error {
+	staleRatio := variable.StatsAlertStaleRatio.Load()
+	errorThreshold := variable.StatsAlertEstimationErrorThreshold.Load()
+	if !variable.RunAutoAnalyze.Load() || (staleRatio <= 0 && errorThreshold <= 0) {
+		statsmetrics.StaleStatsAlertGauge.Set(0)
+		statsmetrics.EstimationErrorStatsAlertGauge.Set(0)
+		return nil
+	}
+	return statsutil.CallWithSCtx(sa.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		alerts, err := sa.collectStatsAlerts(sctx, staleRatio, errorThreshold)
+		if err != nil {
+			return err
+		}
+		var stale, estimationError float64
+		for _, alert := range alerts {
+			if alert.tp == StatsAlertTypeStale {
+				stale++
+			} else {
+				estimationError++
+			}
+		}
+		statsmetrics.StaleStatsAlertGauge.Set(stale)
+		statsmetrics.EstimationErrorStatsAlertGauge.Set(estimationError)
+		return insertStatsAlerts(sctx, alerts)
+	}, statsutil.FlagWrapTxn)
+}
+
+func (sa *statsAnalyze) collectStatsAlerts(
+	sctx sessionctx.Context,
+	staleRatio, errorThreshold float64,
+) ([]statsAlert, error) {
+	indicators, err := priorityqueue.GetTableIndicators(sctx, sa.statsHandle, pmodel.CIStr{}, pmodel.CIStr{})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
+	var alerts []statsAlert
+	for _, ind := range indicators {
+		// The tables which have never been analyzed, e.g. the newly created ones, are not alerted.
+		if !ind.Analyzed {
+			continue
+		}
+		tblInfo, err := is.TableInfoByName(pmodel.NewCIStr(ind.DBName), pmodel.NewCIStr(ind.TableName))
+		if err != nil {
+			// The table may be dropped in the meantime.
+			continue
+		}
+		if disabled, _ := priorityqueue.IsAutoAnalyzeDisabled(tblInfo); disabled {
+			continue
+		}
+		skipReason := priorityqueue.GetSkipReason(ind.PhysicalID)
+		if skipReason == "" {
+			skipReason = priorityqueue.GetSkipReason(tblInfo.ID)
+		}
+		if staleRatio > 0 && ind.ChangePercentage > staleRatio {
+			alerts = append(alerts, statsAlert{
+				indicators: ind,
+				tp:         StatsAlertTypeStale,
+				value:      ind.ChangePercentage,
+				threshold:  staleRatio,
+				skipReason: skipReason,
+			})
+		}
+		if errorThreshold > 0 && ind.EstimationError > errorThreshold {
+			alerts = append(alerts, statsAlert{
+				indicators: ind,
+				tp:         StatsAlertTypeEstimationError,
+				value:      ind.EstimationError,
+				threshold:  errorThreshold,
+				skipReason: skipReason,
+			})
+		}
+	}
+	return alerts, nil
+}
+
+// insertStatsAlerts inserts the alerts into mysql.stats_alerts, and prunes the outdated ones.
+func insertStatsAlerts(sctx sessionctx.Context, alerts []statsAlert) error {
+	if _, err := statsutil.Exec(sctx, "DELETE FROM mysql.stats_alerts WHERE alert_time < DATE_SUB(NOW(6), INTERVAL %? SECOND)",
+		int64(statsAlertsMaxAge/time.Second)); err != nil {
+		return errors.Trace(err)
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+	sql := new(strings.Builder)
+	sql.WriteString("INSERT INTO mysql.stats_alerts (table_schema, table_name, partition_name, table_id, alert_type, value, threshold, skip_reason) VALUES ")
+	args := make([]any, 0, len(alerts)*8)
+	for i, alert := range alerts {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString("(%?, %?, %?, %?, %?, %?, %?, %?)")
+		var skipReason any
+		if alert.skipReason != "" {
+			skipReason = alert.skipReason
+		}
+		args = append(args, alert.indicators.DBName, alert.indicators.TableName, alert.indicators.PartitionName,
+			alert.indicators.PhysicalID, alert.tp, alert.value, alert.threshold, skipReason)
+	}
+	_, err := statsutil.Exec(sctx, sql.String(), args...)
+	return errors.Trace(err)
+}
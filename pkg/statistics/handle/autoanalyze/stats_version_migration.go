@@ -0,0 +1,190 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoanalyze
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/autoanalyze/exec"
+	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// StatsVersionMigrationStatePending means the table is waiting to be migrated.
+	StatsVersionMigrationStatePending = "pending"
+	// StatsVersionMigrationStateRunning means the table is being analyzed.
+	StatsVersionMigrationStateRunning = "running"
+	// StatsVersionMigrationStateFinished means the table has been analyzed with the version 2 stats.
+	StatsVersionMigrationStateFinished = "finished"
+	// StatsVersionMigrationStateFailed means the analyze of the table failed.
+	StatsVersionMigrationStateFailed = "failed"
+	// StatsVersionMigrationStateSkipped means the table is not migrated, e.g. its stats are locked.
+	StatsVersionMigrationStateSkipped = "skipped"
+
+	migrationAnalyzeFailed = "analyze failed, see SHOW ANALYZE STATUS for the reason"
+)
+
+// MigrateStatsVersion implements the StatsAnalyze interface.
+// It finds the tables which still have the version 1 stats and records them in mysql.stats_version_migration,
+// then re-analyzes the first pending one with the version 2 stats at low priority.
+// Only one table is migrated in each call, so the caller controls the pace of the migration.
+// The failed and skipped tables are not retried, deleting their rows makes them migrated again.
+func (sa *statsAnalyze) MigrateStatsVersion(is infoschema.InfoSchema) error {
+	var (
+		tableID   int64
+		tblInfo   *model.TableInfo
+		dbName    string
+		migrating bool
+	)
+	err := statsutil.CallWithSCtx(sa.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		if sctx.GetSessionVars().AnalyzeVersion != statistics.Version2 || !CheckAutoAnalyzeWindow(sctx) {
+			return nil
+		}
+		if err := discoverStatsVersion1Tables(sctx, is); err != nil {
+			return err
+		}
+		var err error
+		tableID, tblInfo, dbName, err = sa.pickStatsVersionMigrationTable(sctx, is)
+		migrating = tblInfo != nil
+		return err
+	}, statsutil.FlagWrapTxn)
+	if err != nil || !migrating {
+		return errors.Trace(err)
+	}
+
+	start := time.Now()
+	success := false
+	err = statsutil.CallWithSCtx(sa.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		success = exec.AutoAnalyze(sctx, sa.statsHandle, sa.sysProcTracker, statistics.Version2,
+			"analyze table %n.%n priority low", dbName, tblInfo.Name.O)
+		return nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	state, failReason := StatsVersionMigrationStateFinished, ""
+	if !success {
+		state, failReason = StatsVersionMigrationStateFailed, migrationAnalyzeFailed
+	}
+	statslogutil.StatsLogger().Info("migrate the stats of the table to version 2",
+		zap.String("db", dbName),
+		zap.String("table", tblInfo.Name.O),
+		zap.String("state", state),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return statsutil.CallWithSCtx(sa.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		return finishStatsVersionMigration(sctx, tableID, state, failReason)
+	})
+}
+
+// discoverStatsVersion1Tables records the tables whose columns or indexes have the version 1 stats.
+// The partitions are recorded by their tables, because all the partitions of a table must use the same stats version.
+// It reads the stats from the storage instead of the stats cache, since the cache may not contain all the tables.
+func discoverStatsVersion1Tables(sctx sessionctx.Context, is infoschema.InfoSchema) error {
+	rows, _, err := statsutil.ExecRows(sctx, "select distinct table_id from mysql.stats_histograms where stats_ver = %?", statistics.Version1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	seen := make(map[int64]struct{}, len(rows))
+	for _, row := range rows {
+		tblInfo, db, ok := getTableAndDBByPhysicalID(is, row.GetInt64(0))
+		if !ok || util.IsMemOrSysDB(db.Name.L) {
+			continue
+		}
+		if _, ok := seen[tblInfo.ID]; ok {
+			continue
+		}
+		seen[tblInfo.ID] = struct{}{}
+		_, _, err = statsutil.ExecRows(sctx, "insert ignore into mysql.stats_version_migration (table_id, table_schema, table_name) values (%?, %?, %?)",
+			tblInfo.ID, db.Name.O, tblInfo.Name.O)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// pickStatsVersionMigrationTable marks the first pending table as running and returns it.
+// The running tables are picked again, since they are left by the stats owner which quit during the analyze.
+// The tables which have been dropped are removed, and the locked tables are skipped.
+func (sa *statsAnalyze) pickStatsVersionMigrationTable(
+	sctx sessionctx.Context,
+	is infoschema.InfoSchema,
+) (int64, *model.TableInfo, string, error) {
+	rows, _, err := statsutil.ExecRows(sctx, "select table_id from mysql.stats_version_migration where state in (%?, %?) order by table_id",
+		StatsVersionMigrationStatePending, StatsVersionMigrationStateRunning)
+	if err != nil {
+		return 0, nil, "", errors.Trace(err)
+	}
+	for _, row := range rows {
+		tableID := row.GetInt64(0)
+		tblInfo, db, ok := getTableAndDBByPhysicalID(is, tableID)
+		if !ok {
+			if _, _, err := statsutil.ExecRows(sctx, "delete from mysql.stats_version_migration where table_id = %?", tableID); err != nil {
+				return 0, nil, "", errors.Trace(err)
+			}
+			continue
+		}
+		physicalIDs := []int64{tableID}
+		if pi := tblInfo.GetPartitionInfo(); pi != nil {
+			for _, def := range pi.Definitions {
+				physicalIDs = append(physicalIDs, def.ID)
+			}
+		}
+		lockedTables, err := sa.statsHandle.GetLockedTables(physicalIDs...)
+		if err != nil {
+			return 0, nil, "", errors.Trace(err)
+		}
+		if len(lockedTables) > 0 {
+			if err := finishStatsVersionMigration(sctx, tableID, StatsVersionMigrationStateSkipped, "stats of the table or partitions are locked"); err != nil {
+				return 0, nil, "", err
+			}
+			continue
+		}
+		_, _, err = statsutil.ExecRows(sctx, "update mysql.stats_version_migration set state = %?, start_time = now(6), end_time = NULL, fail_reason = NULL where table_id = %?",
+			StatsVersionMigrationStateRunning, tableID)
+		if err != nil {
+			return 0, nil, "", errors.Trace(err)
+		}
+		return tableID, tblInfo, db.Name.O, nil
+	}
+	return 0, nil, "", nil
+}
+
+func finishStatsVersionMigration(sctx sessionctx.Context, tableID int64, state, failReason string) error {
+	_, _, err := statsutil.ExecRows(sctx, "update mysql.stats_version_migration set state = %?, fail_reason = nullif(%?, ''), end_time = now(6) where table_id = %?",
+		state, failReason, tableID)
+	return errors.Trace(err)
+}
+
+// getTableAndDBByPhysicalID returns the table and its database of the table or partition.
+func getTableAndDBByPhysicalID(is infoschema.InfoSchema, physicalID int64) (*model.TableInfo, *model.DBInfo, bool) {
+	if tblInfo, ok := is.TableInfoByID(physicalID); ok {
+		db, ok := is.SchemaByID(tblInfo.DBID)
+		return tblInfo, db, ok
+	}
+	if tblInfo, db, _ := is.FindTableInfoByPartitionID(physicalID); tblInfo != nil {
+		return tblInfo, db, true
+	}
+	return nil, nil, false
+}
@@ -78,6 +78,8 @@ func (*Handle) initStatsMeta4Chunk(cache statstypes.StatsCache, iter *chunk.Iter
 			// But in the future, we maybe will create some records for _row_id, see:
 			// https://github.com/pingcap/tidb/issues/51098
 			LastAnalyzeVersion: snapshot,
+			InsertCount:        row.GetInt64(5),
+			DeleteCount:        row.GetInt64(6),
 		}
 		cache.Put(physicalID, tbl) // put this table again since it is updated
 	}
@@ -90,7 +92,7 @@ func (*Handle) initStatsMeta4Chunk(cache statstypes.StatsCache, iter *chunk.Iter
 
 func (h *Handle) initStatsMeta(ctx context.Context) (statstypes.StatsCache, error) {
 	ctx = kv.WithInternalSourceType(ctx, kv.InternalTxnStats)
-	sql := "select HIGH_PRIORITY version, table_id, modify_count, count, snapshot from mysql.stats_meta"
+	sql := "select HIGH_PRIORITY version, table_id, modify_count, count, snapshot, insert_count, delete_count from mysql.stats_meta"
 	rc, err := util.Exec(h.initStatsCtx, sql)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -205,6 +207,7 @@ func (h *Handle) initStatsHistograms4Chunk(is infoschema.InfoSchema, cache stats
 				}
 			}
 			hist := statistics.NewHistogram(id, ndv, nullCount, version, types.NewFieldType(mysql.TypeBlob), chunk.InitialCapacity, 0)
+			hist.Correlation = row.GetFloat64(9)
 			index := &statistics.Index{
 				Histogram:  *hist,
 				CMSketch:   cms,
@@ -315,6 +318,8 @@ func (h *Handle) initStatsHistogramsByPaging(is infoschema.InfoSchema, cache sta
 	defer func() {
 		if err == nil { // only recycle when no error
 			h.Pool.SPool().Put(se)
+		} else {
+			util.DiscardSession(h.Pool.SPool())
 		}
 	}()
 
@@ -432,6 +437,8 @@ func (h *Handle) initStatsTopNByPaging(cache statstypes.StatsCache, task initsta
 	defer func() {
 		if err == nil { // only recycle when no error
 			h.Pool.SPool().Put(se)
+		} else {
+			util.DiscardSession(h.Pool.SPool())
 		}
 	}()
 	sctx := se.(sessionctx.Context)
@@ -665,6 +672,8 @@ func (h *Handle) initStatsBucketsByPaging(cache statstypes.StatsCache, task init
 	defer func() {
 		if err == nil { // only recycle when no error
 			h.Pool.SPool().Put(se)
+		} else {
+			util.DiscardSession(h.Pool.SPool())
 		}
 	}()
 	sctx := se.(sessionctx.Context)
@@ -728,7 +737,8 @@ func (h *Handle) InitStatsLite(ctx context.Context) (err error) {
 			err = err1
 		}
 	}()
-	_, err = util.Exec(h.initStatsCtx, "begin")
+	sql, args := util.BeginInitStatsTxnSQL()
+	_, err = util.Exec(h.initStatsCtx, sql, args...)
 	if err != nil {
 		return err
 	}
@@ -763,7 +773,8 @@ func (h *Handle) InitStats(ctx context.Context, is infoschema.InfoSchema) (err e
 			err = err1
 		}
 	}()
-	_, err = util.Exec(h.initStatsCtx, "begin")
+	sql, args := util.BeginInitStatsTxnSQL()
+	_, err = util.Exec(h.initStatsCtx, sql, args...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,195 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/cache/internal"
+	"github.com/pingcap/tidb/pkg/statistics/handle/cache/internal/metrics"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/memory"
+	"go.uber.org/zap"
+)
+
+type cacheItem struct {
+	value *statistics.Table
+	key   int64
+	cost  int64
+}
+
+// LRU is a stats cache with a hard memory quota. When the memory usage exceeds the quota,
+// the full histograms, CMSketches and TopNs of the least recently used tables are evicted,
+// and the tables are kept in the cache as meta-only entries, which can be reloaded on demand by sync load.
+type LRU struct {
+	elements map[int64]*list.Element
+	// cache is the list of the cache items, the front is the most recently used one.
+	cache    *list.List
+	capacity int64
+	cost     int64
+	mu       sync.Mutex
+}
+
+// NewLRU creates a new LRU cache.
+func NewLRU(totalMemCost int64) (*LRU, error) {
+	capacity, err := adjustMemCost(totalMemCost)
+	if err != nil {
+		return nil, err
+	}
+	metrics.CapacityGauge.Set(float64(capacity))
+	return &LRU{
+		elements: make(map[int64]*list.Element),
+		cache:    list.New(),
+		capacity: capacity,
+	}, nil
+}
+
+// adjustMemCost adjusts the memory cost according to the total memory cost.
+// When the total memory cost is 0, the memory cost is set to half of the total memory.
+func adjustMemCost(totalMemCost int64) (result int64, err error) {
+	if totalMemCost == 0 {
+		memTotal, err := memory.MemTotal()
+		if err != nil {
+			return 0, err
+		}
+		return int64(memTotal / 2), nil
+	}
+	return totalMemCost, nil
+}
+
+// Get implements statsCacheInner
+func (s *LRU) Get(tid int64) (*statistics.Table, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.elements[tid]
+	if !ok {
+		return nil, false
+	}
+	s.cache.MoveToFront(element)
+	return element.Value.(*cacheItem).value, true
+}
+
+// Put implements statsCacheInner
+func (s *LRU) Put(tblID int64, tbl *statistics.Table) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cost := tbl.MemoryUsage().TotalTrackingMemUsage()
+	if element, ok := s.elements[tblID]; ok {
+		item := element.Value.(*cacheItem)
+		s.addCost(cost - item.cost)
+		item.value = tbl
+		item.cost = cost
+		s.cache.MoveToFront(element)
+	} else {
+		s.elements[tblID] = s.cache.PushFront(&cacheItem{key: tblID, value: tbl, cost: cost})
+		s.addCost(cost)
+	}
+	s.evictIfNeeded()
+	return true
+}
+
+// Del implements statsCacheInner
+func (s *LRU) Del(tblID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.elements[tblID]
+	if !ok {
+		return
+	}
+	s.cache.Remove(element)
+	delete(s.elements, tblID)
+	s.addCost(-element.Value.(*cacheItem).cost)
+}
+
+// Cost implements statsCacheInner
+func (s *LRU) Cost() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cost
+}
+
+// Values implements statsCacheInner
+func (s *LRU) Values() []*statistics.Table {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]*statistics.Table, 0, len(s.elements))
+	for element := s.cache.Front(); element != nil; element = element.Next() {
+		result = append(result, element.Value.(*cacheItem).value)
+	}
+	return result
+}
+
+// Len implements statsCacheInner
+func (s *LRU) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.elements)
+}
+
+// Copy implements statsCacheInner
+func (s *LRU) Copy() internal.StatsCacheInner {
+	return s
+}
+
+// SetCapacity implements statsCacheInner
+func (s *LRU) SetCapacity(maxCost int64) {
+	capacity, err := adjustMemCost(maxCost)
+	if err != nil {
+		logutil.BgLogger().Warn("adjustMemCost failed", zap.Error(err))
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capacity = capacity
+	s.evictIfNeeded()
+	metrics.CapacityGauge.Set(float64(capacity))
+}
+
+// Close implements statsCacheInner
+func (s *LRU) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.elements = make(map[int64]*list.Element)
+	s.cache.Init()
+	s.addCost(-s.cost)
+}
+
+// evictIfNeeded evicts the stats of the least recently used tables until the memory usage doesn't exceed the capacity.
+// The evicted tables are kept as meta-only entries, so the loop stops once all of them have been evicted.
+func (s *LRU) evictIfNeeded() {
+	for element := s.cache.Back(); element != nil && s.cost > s.capacity; element = element.Prev() {
+		item := element.Value.(*cacheItem)
+		if item.cost == 0 {
+			continue
+		}
+		table := item.value.Copy()
+		table.DropEvicted()
+		cost := table.MemoryUsage().TotalTrackingMemUsage()
+		if cost >= item.cost {
+			continue
+		}
+		s.addCost(cost - item.cost)
+		item.value = table
+		item.cost = cost
+		metrics.EvictCounter.Inc()
+	}
+}
+
+func (s *LRU) addCost(v int64) {
+	s.cost += v
+	metrics.CostGauge.Set(float64(s.cost))
+}
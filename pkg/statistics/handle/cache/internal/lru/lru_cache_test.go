@@ -0,0 +1,90 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lru
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/statistics/handle/cache/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mockCMSMemoryUsage = int64(4)
+)
+
+func TestLRUPutGetDel(t *testing.T) {
+	lru, err := NewLRU(100)
+	require.NoError(t, err)
+	mockTable := testutil.NewMockStatisticsTable(1, 1, true, false, false)
+	lru.Put(1, mockTable)
+	v, ok := lru.Get(1)
+	require.True(t, ok)
+	require.Equal(t, mockTable, v)
+	require.Equal(t, 2*mockCMSMemoryUsage, lru.Cost())
+	lru.Del(1)
+	v, ok = lru.Get(1)
+	require.False(t, ok)
+	require.Nil(t, v)
+	require.Equal(t, int64(0), lru.Cost())
+	require.Equal(t, 0, len(lru.Values()))
+}
+
+func TestLRUFreshMemUsage(t *testing.T) {
+	lru, err := NewLRU(10000)
+	require.NoError(t, err)
+	lru.Put(1, testutil.NewMockStatisticsTable(1, 1, true, false, false))
+	lru.Put(2, testutil.NewMockStatisticsTable(2, 2, true, false, false))
+	lru.Put(3, testutil.NewMockStatisticsTable(3, 3, true, false, false))
+	require.Equal(t, 12*mockCMSMemoryUsage, lru.Cost())
+	lru.Put(1, testutil.NewMockStatisticsTable(2, 1, true, false, false))
+	require.Equal(t, 13*mockCMSMemoryUsage, lru.Cost())
+	lru.Put(1, testutil.NewMockStatisticsTable(1, 1, true, false, false))
+	require.Equal(t, 12*mockCMSMemoryUsage, lru.Cost())
+	require.Equal(t, 3, lru.Len())
+}
+
+func TestLRUEvictLeastRecentlyUsed(t *testing.T) {
+	capacity := 5 * mockCMSMemoryUsage
+	lru, err := NewLRU(capacity)
+	require.NoError(t, err)
+	lru.Put(1, testutil.NewMockStatisticsTable(1, 1, true, false, false))
+	lru.Put(2, testutil.NewMockStatisticsTable(1, 1, true, false, false))
+	// Table 1 becomes the most recently used one.
+	_, ok := lru.Get(1)
+	require.True(t, ok)
+	lru.Put(3, testutil.NewMockStatisticsTable(1, 1, true, false, false))
+	require.LessOrEqual(t, lru.Cost(), capacity)
+
+	// Table 2 is evicted to a meta-only entry, and the other tables are kept.
+	tbl2, ok := lru.Get(2)
+	require.True(t, ok)
+	require.True(t, tbl2.GetCol(1).IsAllEvicted())
+	require.True(t, tbl2.GetIdx(1).IsEvicted())
+	for _, id := range []int64{1, 3} {
+		tbl, ok := lru.Get(id)
+		require.True(t, ok)
+		require.False(t, tbl.GetCol(1).IsAllEvicted())
+	}
+	require.Equal(t, 3, lru.Len())
+
+	// Shrinking the capacity evicts all the tables.
+	lru.SetCapacity(1)
+	require.Equal(t, int64(0), lru.Cost())
+	for _, tbl := range lru.Values() {
+		require.True(t, tbl.GetCol(1).IsAllEvicted())
+	}
+	require.Equal(t, 3, lru.Len())
+}
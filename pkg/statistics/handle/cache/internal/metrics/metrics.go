@@ -36,6 +36,12 @@ var (
 	CostGauge prometheus.Gauge
 	// CapacityGauge is the gauge of capacity.
 	CapacityGauge prometheus.Gauge
+	// HistBytesGauge is the gauge of the bytes of histograms resident in the cache.
+	HistBytesGauge prometheus.Gauge
+	// TopNBytesGauge is the gauge of the bytes of TopN resident in the cache.
+	TopNBytesGauge prometheus.Gauge
+	// CMSketchBytesGauge is the gauge of the bytes of CMSketch resident in the cache.
+	CMSketchBytesGauge prometheus.Gauge
 )
 
 func init() {
@@ -65,4 +71,7 @@ func initMetricsVars() {
 	RejectCounter = metrics.StatsCacheCounter.WithLabelValues("reject")
 	CostGauge = metrics.StatsCacheGauge.WithLabelValues("track")
 	CapacityGauge = metrics.StatsCacheGauge.WithLabelValues("capacity")
+	HistBytesGauge = metrics.StatsCacheGauge.WithLabelValues("hist_bytes")
+	TopNBytesGauge = metrics.StatsCacheGauge.WithLabelValues("topn_bytes")
+	CMSketchBytesGauge = metrics.StatsCacheGauge.WithLabelValues("cmsketch_bytes")
 }
@@ -74,7 +74,7 @@ func (s *StatsCacheImpl) Update(ctx context.Context, is infoschema.InfoSchema) e
 	if err := util.CallWithSCtx(s.statsHandle.SPool(), func(sctx sessionctx.Context) error {
 		rows, _, err = util.ExecRows(
 			sctx,
-			"SELECT version, table_id, modify_count, count, snapshot from mysql.stats_meta where version > %? order by version",
+			"SELECT version, table_id, modify_count, count, snapshot, insert_count, delete_count from mysql.stats_meta where version > %? order by version",
 			lastVersion,
 		)
 		return err
@@ -91,6 +91,8 @@ func (s *StatsCacheImpl) Update(ctx context.Context, is infoschema.InfoSchema) e
 		modifyCount := row.GetInt64(2)
 		count := row.GetInt64(3)
 		snapshot := row.GetUint64(4)
+		insertCount := row.GetInt64(5)
+		deleteCount := row.GetInt64(6)
 
 		// Detect the context cancel signal, since it may take a long time for the loop.
 		// TODO: add context to TableInfoByID and remove this code block?
@@ -136,6 +138,8 @@ func (s *StatsCacheImpl) Update(ctx context.Context, is infoschema.InfoSchema) e
 		tbl.Version = version
 		tbl.RealtimeCount = count
 		tbl.ModifyCount = modifyCount
+		tbl.InsertCount = insertCount
+		tbl.DeleteCount = deleteCount
 		tbl.TblInfoUpdateTS = tableInfo.UpdateTS
 		// It only occurs in the following situations:
 		// 1. The table has already been analyzed,
@@ -257,10 +261,20 @@ func (s *StatsCacheImpl) SetStatsCacheCapacity(c int64) {
 }
 
 // UpdateStatsHealthyMetrics updates stats healthy distribution metrics according to stats cache.
+// The bytes of histograms, TopN and CMSketch resident in the cache are also updated here.
 func (s *StatsCacheImpl) UpdateStatsHealthyMetrics() {
 	distribution := make([]int64, 9)
 	uneligibleAnalyze := 0
+	var histBytes, topNBytes, cmsBytes int64
 	for _, tbl := range s.Values() {
+		memUsage := tbl.MemoryUsage()
+		for _, items := range []map[int64]statistics.CacheItemMemoryUsage{memUsage.ColumnsMemUsage, memUsage.IndicesMemUsage} {
+			for _, item := range items {
+				histBytes += item.HistMemUsage()
+				topNBytes += item.TopnMemUsage()
+				cmsBytes += item.CMSMemUsage()
+			}
+		}
 		distribution[7]++ // total table count
 		isEligibleForAnalysis := tbl.IsEligibleForAnalysis()
 		if !isEligibleForAnalysis {
@@ -291,4 +305,7 @@ func (s *StatsCacheImpl) UpdateStatsHealthyMetrics() {
 		handle_metrics.StatsHealthyGauges[i].Set(float64(val))
 	}
 	handle_metrics.StatsHealthyGauges[8].Set(float64(uneligibleAnalyze))
+	metrics.HistBytesGauge.Set(float64(histBytes))
+	metrics.TopNBytesGauge.Set(float64(topNBytes))
+	metrics.CMSketchBytesGauge.Set(float64(cmsBytes))
 }
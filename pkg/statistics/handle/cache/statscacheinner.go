@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle/cache/internal"
 	"github.com/pingcap/tidb/pkg/statistics/handle/cache/internal/lfu"
+	"github.com/pingcap/tidb/pkg/statistics/handle/cache/internal/lru"
 	"github.com/pingcap/tidb/pkg/statistics/handle/cache/internal/mapcache"
 	"github.com/pingcap/tidb/pkg/statistics/handle/cache/internal/metrics"
 	"github.com/pingcap/tidb/pkg/util/logutil"
@@ -34,6 +35,15 @@ func NewStatsCache() (*StatsCache, error) {
 	enableQuota := config.GetGlobalConfig().Performance.EnableStatsCacheMemQuota
 	if enableQuota {
 		capacity := variable.StatsCacheMemQuota.Load()
+		if config.GetGlobalConfig().Performance.StatsCacheEvictionPolicy == config.StatsCacheEvictionPolicyLRU {
+			stats, err := lru.NewLRU(capacity)
+			if err != nil {
+				return nil, err
+			}
+			return &StatsCache{
+				c: stats,
+			}, nil
+		}
 		stats, err := lfu.NewLFU(capacity)
 		if err != nil {
 			return nil, err
@@ -18,17 +18,21 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/pingcap/tidb/pkg/ddl/notifier"
+	ddlutil "github.com/pingcap/tidb/pkg/ddl/util"
 	"github.com/pingcap/tidb/pkg/meta/model"
 	pmodel "github.com/pingcap/tidb/pkg/parser/model"
 	"github.com/pingcap/tidb/pkg/planner/cardinality"
+	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/statistics/handle/ddl"
 	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
 	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/testkit"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tidb/pkg/util/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -209,6 +213,41 @@ func TestTruncateTable(t *testing.T) {
 	require.NotEqual(t, version, rows[0][0].(string))
 }
 
+func TestTruncateTableResetsStats(t *testing.T) {
+	store, do := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t (c1 int, c2 int, index idx(c1, c2))")
+	testKit.MustExec("insert into t values (1,2),(2,2),(6,2),(11,2),(16,2)")
+	testKit.MustExec("analyze table t")
+	h := do.StatsHandle()
+
+	testKit.MustExec("truncate table t")
+	truncateTableEvent := findEvent(h.DDLEventCh(), model.ActionTruncateTable)
+	require.NoError(t, h.HandleDDLEvent(truncateTableEvent))
+	tbl, err := do.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	newTableInfo := tbl.Meta()
+
+	// Leave some stale stats for the new table ID, which are removed when the event is delivered again.
+	testKit.MustExec("update mysql.stats_meta set count = 5, modify_count = 5 where table_id = ?", newTableInfo.ID)
+	testKit.MustExec("insert into mysql.stats_buckets (table_id, is_index, hist_id, bucket_id, count, repeats, lower_bound, upper_bound) values (?, 0, 1, 0, 5, 1, 'a', 'b')", newTableInfo.ID)
+	testKit.MustExec("insert into mysql.stats_top_n (table_id, is_index, hist_id, value, count) values (?, 0, 1, 'a', 5)", newTableInfo.ID)
+	handleSchemaChange := ddl.NewSchemaChangeHandler(h)
+	require.NoError(t, statsutil.CallWithSCtx(h.SPool(), func(sctx sessionctx.Context) error {
+		return handleSchemaChange(context.Background(), sctx, truncateTableEvent)
+	}, statsutil.FlagWrapTxn))
+
+	testKit.MustQuery("select count, modify_count, snapshot from mysql.stats_meta where table_id = ?", newTableInfo.ID).Check(testkit.Rows("0 0 0"))
+	testKit.MustQuery("select count(*) from mysql.stats_histograms where table_id = ? and distinct_count = 0 and stats_ver = 0", newTableInfo.ID).Check(testkit.Rows("3"))
+	testKit.MustQuery("select count(*) from mysql.stats_buckets where table_id = ?", newTableInfo.ID).Check(testkit.Rows("0"))
+	testKit.MustQuery("select count(*) from mysql.stats_top_n where table_id = ?", newTableInfo.ID).Check(testkit.Rows("0"))
+	require.NoError(t, h.Update(context.Background(), do.InfoSchema()))
+	statsTbl := h.GetTableStats(newTableInfo)
+	require.False(t, statsTbl.IsAnalyzed())
+	require.Equal(t, int64(0), statsTbl.RealtimeCount)
+}
+
 func TestTruncateAPartitionedTable(t *testing.T) {
 	store, do := testkit.CreateMockStoreAndDomain(t)
 	testKit := testkit.NewTestKit(t, store)
@@ -392,6 +431,61 @@ func TestDDLHistogram(t *testing.T) {
 	rs.Check(testkit.Rows("2"))
 }
 
+func TestModifyColumnKeepsStats(t *testing.T) {
+	store, do := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	h := do.StatsHandle()
+
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t (a int, b varchar(10))")
+	<-h.DDLEventCh()
+	testKit.MustExec("insert into t values (1, '1'), (2, '2'), (2, '2'), (2, '2'), (3, '3'), (4, '4'), (5, '5')")
+	testKit.MustExec("set @@tidb_analyze_version = 2")
+	testKit.MustExec("analyze table t with 1 topn, 2 buckets")
+	is := do.InfoSchema()
+	tbl, err := is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	oldColID := tbl.Meta().Columns[0].ID
+
+	// INT to DECIMAL is lossless, so the histogram and the TopN are converted.
+	testKit.MustExec("alter table t modify column a decimal(20, 2)")
+	require.NoError(t, h.HandleDDLEvent(findEvent(h.DDLEventCh(), model.ActionModifyColumn)))
+	is = do.InfoSchema()
+	require.Nil(t, h.Update(context.Background(), is))
+	tbl, err = is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := tbl.Meta()
+	newColID := tableInfo.Columns[0].ID
+	require.NotEqual(t, oldColID, newColID)
+	testKit.MustQuery("select distinct_count, null_count, stats_ver from mysql.stats_histograms where table_id = ? and is_index = 0 and hist_id = ?",
+		tableInfo.ID, newColID).Check(testkit.Rows("5 0 2"))
+	testKit.MustQuery("select bucket_id, count, repeats, lower_bound, upper_bound from mysql.stats_buckets where table_id = ? and is_index = 0 and hist_id = ?",
+		tableInfo.ID, newColID).Sort().Check(testkit.Rows("0 2 1 1 3", "1 2 1 4 5"))
+	rows := testKit.MustQuery("select value, count from mysql.stats_top_n where table_id = ? and is_index = 0 and hist_id = ?",
+		tableInfo.ID, newColID).Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, "3", rows[0][1])
+	_, d, err := codec.DecodeOne([]byte(rows[0][0].(string)))
+	require.NoError(t, err)
+	require.Equal(t, types.KindMysqlDecimal, d.Kind())
+	require.Equal(t, "2.00", d.GetMysqlDecimal().String())
+	require.False(t, h.GetTableStats(tableInfo).Pseudo)
+
+	// VARCHAR to INT is not lossless, so the stats of the column are invalidated.
+	testKit.MustExec("alter table t modify column b int")
+	require.NoError(t, h.HandleDDLEvent(findEvent(h.DDLEventCh(), model.ActionModifyColumn)))
+	is = do.InfoSchema()
+	require.Nil(t, h.Update(context.Background(), is))
+	tbl, err = is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo = tbl.Meta()
+	newColID = tableInfo.Columns[1].ID
+	testKit.MustQuery("select stats_ver from mysql.stats_histograms where table_id = ? and is_index = 0 and hist_id = ?",
+		tableInfo.ID, newColID).Check(testkit.Rows("0"))
+	testKit.MustQuery("select count(*) from mysql.stats_top_n where table_id = ? and is_index = 0 and hist_id = ?",
+		tableInfo.ID, newColID).Check(testkit.Rows("0"))
+}
+
 func TestDDLPartition(t *testing.T) {
 	store, do := testkit.CreateMockStoreAndDomain(t)
 	testKit := testkit.NewTestKit(t, store)
@@ -1306,6 +1400,163 @@ func TestAddPartitioning(t *testing.T) {
 	)
 }
 
+func TestExchangePartitionRemergesGlobalStats(t *testing.T) {
+	store, do := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	h := do.StatsHandle()
+	testKit.MustExec("use test")
+	testKit.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	testKit.MustExec("create table t (a int, b int, index idx(b)) partition by range (a) (partition p0 values less than (6), partition p1 values less than (11))")
+	testKit.MustExec("insert into t values (1, 1), (2, 2), (6, 6)")
+	testKit.MustExec("analyze table t")
+	testKit.MustExec("create table t1 (a int, b int, index idx(b))")
+	testKit.MustExec("insert into t1 values (3, 3), (4, 4), (5, 5)")
+	testKit.MustExec("analyze table t1")
+	is := do.InfoSchema()
+	tbl, err := is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := tbl.Meta()
+	tbl1, err := is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t1"))
+	require.NoError(t, err)
+	partitionID, tableID := tableInfo.GetPartitionInfo().Definitions[0].ID, tbl1.Meta().ID
+	oldVersion := testKit.MustQuery("select max(version) from mysql.stats_meta where table_id in (?, ?)", partitionID, tableID).Rows()[0][0].(string)
+
+	testKit.MustExec("alter table t exchange partition p0 with table t1")
+	require.NoError(t, h.HandleDDLEvent(findEvent(h.DDLEventCh(), model.ActionExchangeTablePartition)))
+	// The stats of the partition and the table are reloaded together.
+	testKit.MustQuery("select count(distinct version), count(*) from mysql.stats_meta where table_id in (?, ?) and version > ?",
+		partitionID, tableID, oldVersion).Check(testkit.Rows("1 2"))
+	// The global stats are merged again in the background.
+	testKit.MustQuery("select index_id, hist_ids from mysql.stats_global_merge_jobs where table_id = ? order by index_id", tableInfo.ID).Check(testkit.Rows(
+		"-1 []",
+		"1 [1]",
+	))
+	require.NoError(t, h.MergePendingGlobalStats(do.InfoSchema()))
+	testKit.MustQuery("select count(*) from mysql.stats_global_merge_jobs").Check(testkit.Rows("0"))
+}
+
+func TestDropPartitionRemergesGlobalStats(t *testing.T) {
+	store, do := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	h := do.StatsHandle()
+	testKit.MustExec("use test")
+	testKit.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	testKit.MustExec("create table t (a int, b int, index idx(b)) partition by range (a) (partition p0 values less than (6), partition p1 values less than (11))")
+	testKit.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (6, 6)")
+	testKit.MustExec("analyze table t")
+	tbl, err := do.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := tbl.Meta()
+	testKit.MustQuery("select distinct_count from mysql.stats_histograms where table_id = ? and is_index = 1", tableInfo.ID).Check(testkit.Rows("4"))
+
+	testKit.MustExec("alter table t drop partition p0")
+	require.NoError(t, h.HandleDDLEvent(findEvent(h.DDLEventCh(), model.ActionDropTablePartition)))
+	testKit.MustQuery("select index_id, hist_ids from mysql.stats_global_merge_jobs where table_id = ? order by index_id", tableInfo.ID).Check(testkit.Rows(
+		"-1 []",
+		"1 [1]",
+	))
+	require.NoError(t, h.MergePendingGlobalStats(do.InfoSchema()))
+	testKit.MustQuery("select count(*) from mysql.stats_global_merge_jobs").Check(testkit.Rows("0"))
+	// The global stats are merged from the remaining partition.
+	testKit.MustQuery("select distinct_count from mysql.stats_histograms where table_id = ? and is_index = 1", tableInfo.ID).Check(testkit.Rows("1"))
+}
+
+func TestRecoverTableRestoresHistoricalStats(t *testing.T) {
+	store, do := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	h := do.StatsHandle()
+	defer func(originGC bool) {
+		if originGC {
+			ddlutil.EmulatorGCEnable()
+		}
+	}(ddlutil.IsEmulatorGCEnable())
+	ddlutil.EmulatorGCDisable()
+	testKit.MustExec("insert into mysql.tidb values ('tikv_gc_safe_point', '20000101-00:00:00 +0000', '') on duplicate key update variable_value = '20000101-00:00:00 +0000'")
+	testKit.MustExec("set global tidb_enable_historical_stats = 1")
+	defer testKit.MustExec("set global tidb_enable_historical_stats = 0")
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t (a int, b int, index idx(b))")
+	testKit.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	testKit.MustExec("analyze table t")
+	hsWorker := do.GetHistoricalStatsWorker()
+	require.NoError(t, hsWorker.DumpHistoricalStats(hsWorker.GetOneHistoricalStatsTable(), h))
+	tbl, err := do.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableID := tbl.Meta().ID
+
+	testKit.MustExec("drop table t")
+	require.NoError(t, h.HandleDDLEvent(findEvent(h.DDLEventCh(), model.ActionDropTable)))
+	// The stats of the dropped table are removed by the stats GC.
+	require.NoError(t, h.DeleteTableStatsFromKV([]int64{tableID}))
+	testKit.MustQuery("select count(*) from mysql.stats_histograms where table_id = ?", tableID).Check(testkit.Rows("0"))
+
+	testKit.MustExec("recover table t")
+	require.NoError(t, h.HandleDDLEvent(findEvent(h.DDLEventCh(), model.ActionRecoverTable)))
+	testKit.MustQuery("select count from mysql.stats_meta where table_id = ?", tableID).Check(testkit.Rows("3"))
+	testKit.MustQuery("select distinct_count from mysql.stats_histograms where table_id = ? and is_index = 1", tableID).Check(testkit.Rows("3"))
+	require.NoError(t, h.Update(context.Background(), do.InfoSchema()))
+	tbl, err = do.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	statsTbl := h.GetTableStats(tbl.Meta())
+	require.False(t, statsTbl.Pseudo)
+	require.True(t, statsTbl.IsAnalyzed())
+}
+
+func TestMultiSchemaChangeUpdatesStatsAtomically(t *testing.T) {
+	store, do := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	h := do.StatsHandle()
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t (a int, b int, c int)")
+	testKit.MustExec("insert into t values (1, 1, 1), (2, 2, 2)")
+	testKit.MustExec("analyze table t")
+	tbl, err := do.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := tbl.Meta()
+	oldColA, colC := tableInfo.Columns[0].ID, tableInfo.Columns[2].ID
+
+	testKit.MustExec("alter table t drop column c, add index idx(b), modify column a varchar(20)")
+	event := findEvent(h.DDLEventCh(), model.ActionMultiSchemaChange)
+	_, subEvents := event.GetMultiSchemaChangeInfo()
+	require.Len(t, subEvents, 2)
+	require.NoError(t, h.HandleDDLEvent(event))
+	tbl, err = do.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	newColA := tbl.Meta().Columns[0].ID
+	require.NotEqual(t, oldColA, newColA)
+	// The stats of the new column are inserted, and the stats of the dropped and replaced columns are removed.
+	testKit.MustQuery("select hist_id from mysql.stats_histograms where table_id = ? and is_index = 0 order by hist_id", tableInfo.ID).Check(
+		testkit.Rows(fmt.Sprint(tableInfo.Columns[1].ID), fmt.Sprint(newColA)))
+	testKit.MustQuery("select count(*) from mysql.stats_histograms where table_id = ? and hist_id in (?, ?) and is_index = 0", tableInfo.ID, oldColA, colC).Check(
+		testkit.Rows("0"))
+}
+
+func TestDDLEventsDeliveredByNotifier(t *testing.T) {
+	store, do := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	h := do.StatsHandle()
+	testKit.MustExec("use test")
+	testKit.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	testKit.MustExec("create table t (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	testKit.MustExec("insert into t values (1), (2), (11)")
+	testKit.MustExec("analyze table t")
+	tbl, err := do.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := tbl.Meta()
+	testKit.MustQuery("select count from mysql.stats_meta where table_id = ?", tableInfo.ID).Check(testkit.Rows("3"))
+
+	// The event is delivered by the DDL notifier, without being consumed from DDLEventCh.
+	testKit.MustExec("alter table t truncate partition p0")
+	require.Eventually(t, func() bool {
+		rows := testKit.MustQuery("select count from mysql.stats_meta where table_id = ?", tableInfo.ID).Rows()
+		return rows[0][0].(string) == "1"
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// The delivered event isn't handled again.
+	require.NoError(t, h.HandleDDLEvent(findEvent(h.DDLEventCh(), model.ActionTruncateTablePartition)))
+	testKit.MustQuery("select count from mysql.stats_meta where table_id = ?", tableInfo.ID).Check(testkit.Rows("1"))
+}
+
 func findEvent(eventCh <-chan *notifier.SchemaChangeEvent, eventType model.ActionType) *notifier.SchemaChangeEvent {
 	// Find the target event.
 	for {
@@ -18,29 +18,50 @@ import (
 	"context"
 
 	"github.com/pingcap/errors"
-	"github.com/pingcap/tidb/pkg/ddl/notifier"
 	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics/handle/globalstats"
 	"github.com/pingcap/tidb/pkg/statistics/handle/logutil"
 	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"go.uber.org/zap"
 )
 
-func (h *ddlHandlerImpl) onExchangeAPartition(t *notifier.SchemaChangeEvent) error {
-	globalTableInfo, originalPartInfo,
-		originalTableInfo := t.GetExchangePartitionInfo()
-	// Note: Put all the operations in a transaction.
-	return util.CallWithSCtx(h.statsHandler.SPool(), func(sctx sessionctx.Context) error {
-		return updateGlobalTableStats4ExchangePartition(
-			util.StatsCtx,
-			sctx,
-			globalTableInfo,
-			originalPartInfo,
-			originalTableInfo,
-		)
-	}, util.FlagWrapTxn)
+// updateStats4ExchangePartition updates the stats after the partition and the table are exchanged. The data of them
+// are swapped along with their physical IDs, and so are their stats, which are keyed by the physical IDs. So it only
+// updates the versions of both of them, then all the stats caches reload them together. The count of the global
+// stats is adjusted by the counts of the swapped data, while the global histograms are merged again in the background,
+// since they were merged from the stats of the old partition.
+// All of them are done in the same transaction.
+func updateStats4ExchangePartition(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	globalTableInfo *model.TableInfo,
+	originalPartInfo *model.PartitionInfo,
+	originalTableInfo *model.TableInfo,
+) error {
+	startTS, err := util.GetStartTS(sctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err = util.ExecWithCtx(
+		ctx, sctx,
+		"update mysql.stats_meta set version = %? where table_id in (%?, %?)",
+		startTS, originalPartInfo.Definitions[0].ID, originalTableInfo.ID,
+	); err != nil {
+		return errors.Trace(err)
+	}
+	if err = updateGlobalTableStats4ExchangePartition(
+		ctx,
+		sctx,
+		globalTableInfo,
+		originalPartInfo,
+		originalTableInfo,
+	); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(globalstats.EnqueueGlobalStatsRemergeJobs(sctx, globalTableInfo))
 }
 
 func updateGlobalTableStats4ExchangePartition(
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics/handle/globalstats"
 	"github.com/pingcap/tidb/pkg/statistics/handle/history"
 	"github.com/pingcap/tidb/pkg/statistics/handle/logutil"
 	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
@@ -35,14 +36,12 @@ type handler struct {
 	statsCache types.StatsCache
 }
 
-// NewHandlerAndRegister creates a new handler and registers it to the DDL
-// notifier.
-func NewHandlerAndRegister(
-	statsCache types.StatsCache,
-	registry *notifier.DDLNotifier,
-) {
+// NewSchemaChangeHandler creates the handler of the schema change events, which
+// updates the stats system tables. It's registered to the DDL notifier with
+// notifier.StatsMetaHandlerID.
+func NewSchemaChangeHandler(statsCache types.StatsCache) notifier.SchemaChangeHandler {
 	h := handler{statsCache: statsCache}
-	registry.RegisterHandler(notifier.StatsMetaHandlerID, h.handle)
+	return h.handle
 }
 
 func (h handler) handle(
@@ -70,7 +69,7 @@ func (h handler) handle(
 			return err
 		}
 		for _, id := range ids {
-			err = h.insertStats4PhysicalID(ctx, sctx, newTableInfo, id)
+			err = h.resetStats4PhysicalID(ctx, sctx, newTableInfo, id)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -99,6 +98,35 @@ func (h handler) handle(
 				return errors.Trace(err)
 			}
 		}
+	case model.ActionRecoverTable:
+		recoveredTableInfo := change.GetRecoverTableInfo()
+		ids, err := getPhysicalIDs(sctx, recoveredTableInfo)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := h.recoverStats4PhysicalID(ctx, sctx, recoveredTableInfo, id); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	case model.ActionMultiSchemaChange:
+		tableInfo, subEvents := change.GetMultiSchemaChangeInfo()
+		// The events of the sub-jobs are handled in the same transaction, so the stats are changed atomically.
+		for _, subEvent := range subEvents {
+			if err := h.handle(ctx, sctx, subEvent); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		// The stats of the dropped or replaced columns and indexes are removed at the same time.
+		ids, err := getPhysicalIDs(sctx, tableInfo)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := h.removeDroppedStats4PhysicalID(ctx, sctx, tableInfo, id); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	case model.ActionAddColumn:
 		newTableInfo, newColumnInfo := change.GetAddColumnInfo()
 		ids, err := getPhysicalIDs(sctx, newTableInfo)
@@ -111,17 +139,18 @@ func (h handler) handle(
 			}
 		}
 	case model.ActionModifyColumn:
-		newTableInfo, modifiedColumnInfo := change.GetModifyColumnInfo()
+		newTableInfo, modifiedColumnInfo, oldColumnInfo := change.GetModifyColumnInfo()
 		ids, err := getPhysicalIDs(sctx, newTableInfo)
 		if err != nil {
 			return errors.Trace(err)
 		}
 		for _, id := range ids {
-			if err = h.insertStats4Col(ctx, sctx, id, modifiedColumnInfo); err != nil {
+			if err = h.modifyStats4Col(ctx, sctx, id, modifiedColumnInfo, oldColumnInfo); err != nil {
 				return errors.Trace(err)
 			}
 		}
 	case model.ActionAddTablePartition:
+		// The added partitions are empty, so the global stats are still correct.
 		globalTableInfo, addedPartitionInfo := change.GetAddPartitionInfo()
 		for _, def := range addedPartitionInfo.Definitions {
 			if err := h.insertStats4PhysicalID(ctx, sctx, globalTableInfo, def.ID); err != nil {
@@ -146,6 +175,10 @@ func (h handler) handle(
 		); err != nil {
 			return errors.Trace(err)
 		}
+		// The global histograms are merged again from the remaining partitions in the background.
+		if err := globalstats.EnqueueGlobalStatsRemergeJobs(sctx, globalTableInfo); err != nil {
+			return errors.Trace(err)
+		}
 
 		// Third, clean up the old stats meta from partition stats meta for the dropped partitions.
 		for _, def := range droppedPartInfo.Definitions {
@@ -165,6 +198,11 @@ func (h handler) handle(
 		); err != nil {
 			return errors.Trace(err)
 		}
+		// The global histograms are merged again from the truncated partitions in the background, instead of
+		// keeping their old values until the next analyze.
+		if err := globalstats.EnqueueGlobalStatsRemergeJobs(sctx, globalTableInfo); err != nil {
+			return errors.Trace(err)
+		}
 
 		// Reset the partition stats.
 		for _, def := range droppedPartitionInfo.Definitions {
@@ -176,7 +214,7 @@ func (h handler) handle(
 		return nil
 	case model.ActionExchangeTablePartition:
 		globalTableInfo, originalPartInfo, originalTableInfo := change.GetExchangePartitionInfo()
-		return errors.Trace(updateGlobalTableStats4ExchangePartition(
+		return errors.Trace(updateStats4ExchangePartition(
 			ctx,
 			sctx,
 			globalTableInfo,
@@ -192,6 +230,8 @@ func (h handler) handle(
 		// It's acceptable to not update it immediately,
 		// as the new partitions will be analyzed shortly due to the absence of statistics for them.
 		// Therefore, the auto-analyze worker will handle them in the near future.
+		// The global histograms are not merged again either, since the data of the table is not changed, and the
+		// new partitions have no stats to be merged until they are analyzed.
 		for _, def := range addedPartInfo.Definitions {
 			if err := h.insertStats4PhysicalID(ctx, sctx, globalTableInfo, def.ID); err != nil {
 				return err
@@ -224,6 +264,10 @@ func (h handler) handle(
 		if err := storage.ChangeGlobalStatsID(ctx, sctx, oldTblID, newSingleTableInfo.ID); err != nil {
 			return errors.Trace(err)
 		}
+		// The global NDV sketches are only for the partitioned tables.
+		if err := storage.DeleteGlobalNDVSketches(sctx, oldTblID); err != nil {
+			return errors.Trace(err)
+		}
 
 		// Remove partition stats.
 		for _, def := range droppedPartInfo.Definitions {
@@ -235,6 +279,8 @@ func (h handler) handle(
 		return errors.Trace(storage.UpdateStatsVersion(ctx, sctx))
 	case model.ActionAddIndex:
 		// No need to update the stats meta for the adding index event.
+	case model.ActionDropSchema:
+		// The stats of the tables in the dropped schema are removed by the stats GC.
 	default:
 		intest.Assert(false)
 		logutil.StatsLogger().Error("Unhandled schema change event",
@@ -256,6 +302,32 @@ func (h handler) insertStats4PhysicalID(
 	return errors.Trace(h.recordHistoricalStatsMeta(ctx, sctx, id, startTS))
 }
 
+func (h handler) resetStats4PhysicalID(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	info *model.TableInfo,
+	id int64,
+) error {
+	startTS, err := storage.ResetTableStats2KV(ctx, sctx, info, id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(h.recordHistoricalStatsMeta(ctx, sctx, id, startTS))
+}
+
+func (h handler) recoverStats4PhysicalID(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	info *model.TableInfo,
+	id int64,
+) error {
+	startTS, err := storage.RecoverTableStats2KV(ctx, sctx, info, id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(h.recordHistoricalStatsMeta(ctx, sctx, id, startTS))
+}
+
 func (h handler) recordHistoricalStatsMeta(
 	ctx context.Context,
 	sctx sessionctx.Context,
@@ -299,6 +371,19 @@ func (h handler) delayedDeleteStats4PhysicalID(
 	return errors.Trace(h.recordHistoricalStatsMeta(ctx, sctx, id, startTS))
 }
 
+func (h handler) removeDroppedStats4PhysicalID(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	info *model.TableInfo,
+	id int64,
+) error {
+	startTS, err := storage.RemoveDroppedColumnAndIndexStats(ctx, sctx, info, id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(h.recordHistoricalStatsMeta(ctx, sctx, id, startTS))
+}
+
 func (h handler) insertStats4Col(
 	ctx context.Context,
 	sctx sessionctx.Context,
@@ -312,6 +397,19 @@ func (h handler) insertStats4Col(
 	return errors.Trace(h.recordHistoricalStatsMeta(ctx, sctx, physicalID, startTS))
 }
 
+func (h handler) modifyStats4Col(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	physicalID int64,
+	newColInfos, oldColInfos []*model.ColumnInfo,
+) error {
+	startTS, err := storage.ModifyColStats2KV(ctx, sctx, physicalID, newColInfos, oldColInfos)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(h.recordHistoricalStatsMeta(ctx, sctx, physicalID, startTS))
+}
+
 func getPhysicalIDs(
 	sctx sessionctx.Context,
 	tblInfo *model.TableInfo,
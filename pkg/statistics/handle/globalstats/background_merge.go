@@ -0,0 +1,287 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package globalstats
+
+import (
+	"encoding/json"
+	"maps"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	"github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// columnsMergeJobIndexID is the index_id of the job which merges the column stats.
+	columnsMergeJobIndexID = -1
+	// maxMergeJobRetryCount is the max number of times a failed merge job is retried.
+	// The job is removed after that, and the next analyze of the table will enqueue it again.
+	maxMergeJobRetryCount = 3
+)
+
+// globalStatsMergeJob is a pending job in mysql.stats_global_merge_jobs.
+type globalStatsMergeJob struct {
+	opts       map[ast.AnalyzeOptionType]uint64
+	createTime string
+	info       statstypes.GlobalStatsInfo
+	tableID    int64
+	indexID    int64
+	retryCount int64
+}
+
+// EnqueueGlobalStatsMergeJob persists a job which merges the partition-level stats into the global-level stats,
+// and the job will be executed by the background worker of the stats owner.
+// If there's already a pending job for the same table and index, the histogram IDs are merged into it.
+func (sg *statsGlobalImpl) EnqueueGlobalStatsMergeJob(
+	tableID int64,
+	info *statstypes.GlobalStatsInfo,
+	opts map[ast.AnalyzeOptionType]uint64,
+) error {
+	return util.CallWithSCtx(sg.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		return enqueueGlobalStatsMergeJob(sctx, tableID, info, opts)
+	}, util.FlagWrapTxn)
+}
+
+// EnqueueGlobalStatsRemergeJobs persists the jobs which merge all the global stats of the partitioned table again
+// in the transaction of sctx, e.g. after the data of a partition is swapped by EXCHANGE PARTITION. It does nothing if
+// the table has no global stats. The persisted analyze options of the table are used if any.
+// The global NDV sketches of the table are removed, since the hash values of the removed data can't be subtracted from
+// them, and they're built again by the merge.
+func EnqueueGlobalStatsRemergeJobs(sctx sessionctx.Context, globalTableInfo *model.TableInfo) error {
+	if err := storage.DeleteGlobalNDVSketches(sctx, globalTableInfo.ID); err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := util.ExecRows(sctx, "select max(stats_ver) from mysql.stats_histograms where table_id = %? and stats_ver > 0", globalTableInfo.ID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) == 0 || rows[0].IsNull(0) {
+		return nil
+	}
+	statsVer := int(rows[0].GetInt64(0))
+	opts, err := loadGlobalStatsMergeOptions(sctx, globalTableInfo.ID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The empty histogram IDs mean all the columns.
+	info := &statstypes.GlobalStatsInfo{HistIDs: []int64{}, StatsVersion: statsVer}
+	if err := enqueueGlobalStatsMergeJob(sctx, globalTableInfo.ID, info, opts); err != nil {
+		return err
+	}
+	for _, idx := range globalTableInfo.Indices {
+		// The stats of the global indexes are not merged from the partitions.
+		if idx.State != model.StatePublic || idx.Global || idx.VectorInfo != nil {
+			continue
+		}
+		info = &statstypes.GlobalStatsInfo{HistIDs: []int64{idx.ID}, IsIndex: 1, StatsVersion: statsVer}
+		if err := enqueueGlobalStatsMergeJob(sctx, globalTableInfo.ID, info, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func enqueueGlobalStatsMergeJob(
+	sctx sessionctx.Context,
+	tableID int64,
+	info *statstypes.GlobalStatsInfo,
+	opts map[ast.AnalyzeOptionType]uint64,
+) error {
+	indexID := int64(columnsMergeJobIndexID)
+	if info.IsIndex == 1 {
+		indexID = info.HistIDs[0]
+	}
+	rows, _, err := util.ExecRows(sctx, "select hist_ids from mysql.stats_global_merge_jobs where table_id = %? and index_id = %? for update", tableID, indexID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	histIDs := info.HistIDs
+	if len(rows) > 0 {
+		var pendingHistIDs []int64
+		if err := json.Unmarshal(rows[0].GetBytes(0), &pendingHistIDs); err != nil {
+			return errors.Trace(err)
+		}
+		histIDs = unionHistIDs(pendingHistIDs, histIDs)
+	}
+	histIDsJSON, err := json.Marshal(histIDs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	optsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, _, err = util.ExecRows(sctx, "replace into mysql.stats_global_merge_jobs (table_id, index_id, hist_ids, stats_ver, options) values (%?, %?, %?, %?, %?)",
+		tableID, indexID, histIDsJSON, info.StatsVersion, optsJSON)
+	return errors.Trace(err)
+}
+
+// loadGlobalStatsMergeOptions returns the options to merge the global stats of the table, which are the persisted
+// analyze options of the table, or analyzeOptionDefault if they are not set.
+func loadGlobalStatsMergeOptions(sctx sessionctx.Context, tableID int64) (map[ast.AnalyzeOptionType]uint64, error) {
+	opts := maps.Clone(analyzeOptionDefault)
+	rows, _, err := util.ExecRows(sctx, "select buckets, topn from mysql.analyze_options where table_id = %?", tableID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(rows) == 0 {
+		return opts, nil
+	}
+	if buckets := rows[0].GetInt64(0); buckets > 0 {
+		opts[ast.AnalyzeOptNumBuckets] = uint64(buckets)
+	}
+	if topN := rows[0].GetInt64(1); topN >= 0 {
+		opts[ast.AnalyzeOptNumTopN] = uint64(topN)
+	}
+	return opts, nil
+}
+
+// unionHistIDs returns the union of the two histogram ID lists.
+// An empty list means all the columns, so the union is also empty if any of them is empty.
+func unionHistIDs(a, b []int64) []int64 {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	res := make([]int64, 0, len(a)+len(b))
+	seen := make(map[int64]struct{}, len(a)+len(b))
+	for _, ids := range [][]int64{a, b} {
+		for _, id := range ids {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// MergePendingGlobalStats executes the pending global stats merge jobs one by one in the order of their creation.
+// Merging one table at a time keeps the memory usage much lower than merging all of them at the end of the analyze.
+// A job is removed only after its global stats are saved, so the unfinished jobs are resumed by the next call,
+// even after TiDB restarts or the stats owner changes.
+func (sg *statsGlobalImpl) MergePendingGlobalStats(is infoschema.InfoSchema) error {
+	var jobs []*globalStatsMergeJob
+	err := util.CallWithSCtx(sg.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		var err error
+		jobs, err = loadPendingGlobalStatsMergeJobs(sctx)
+		return err
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, job := range jobs {
+		mergeErr := sg.runGlobalStatsMergeJob(is, job)
+		if err := sg.finishGlobalStatsMergeJob(job, mergeErr); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func loadPendingGlobalStatsMergeJobs(sctx sessionctx.Context) ([]*globalStatsMergeJob, error) {
+	rows, _, err := util.ExecRows(sctx, "select table_id, index_id, hist_ids, stats_ver, options, retry_count, create_time from mysql.stats_global_merge_jobs order by create_time")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	jobs := make([]*globalStatsMergeJob, 0, len(rows))
+	for _, row := range rows {
+		job := &globalStatsMergeJob{
+			tableID:    row.GetInt64(0),
+			indexID:    row.GetInt64(1),
+			retryCount: row.GetInt64(5),
+			createTime: row.GetTime(6).String(),
+		}
+		job.info.StatsVersion = int(row.GetInt64(3))
+		if err := json.Unmarshal(row.GetBytes(2), &job.info.HistIDs); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := json.Unmarshal(row.GetBytes(4), &job.opts); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if job.indexID != columnsMergeJobIndexID {
+			job.info.IsIndex = 1
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (sg *statsGlobalImpl) runGlobalStatsMergeJob(is infoschema.InfoSchema, job *globalStatsMergeJob) (err error) {
+	defer func() {
+		// The merge panics when it exceeds tidb_mem_quota_analyze. Regard it as a failure of the job,
+		// so that the job is retried later instead of crashing the stats owner.
+		if r := recover(); r != nil {
+			err = errors.Errorf("merge global stats panicked: %v", r)
+		}
+	}()
+	tbl, ok := sg.statsHandler.TableInfoByID(is, job.tableID)
+	if !ok || tbl.Meta().GetPartitionInfo() == nil {
+		// The table has been dropped or is no longer partitioned, so there's nothing to merge.
+		return nil
+	}
+	return util.CallWithSCtx(sg.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		start := time.Now()
+		globalStats, err := MergePartitionStats2GlobalStatsByTableID(sctx, sg.statsHandler, job.opts, is, job.tableID, job.info.IsIndex == 1, job.info.HistIDs,
+			AnalyzeMemoryTracker)
+		if err != nil {
+			return err
+		}
+		memUsage := globalStats.MemoryUsage()
+		sg.memTracker.Consume(memUsage)
+		defer sg.memTracker.Consume(-memUsage)
+		if err := WriteGlobalStatsToStorage(sg.statsHandler, globalStats, &job.info, job.tableID); err != nil {
+			return err
+		}
+		statslogutil.StatsLogger().Info("merge global stats in the background finished",
+			zap.Int64("tableID", job.tableID),
+			zap.Int64("indexID", job.indexID),
+			zap.Duration("duration", time.Since(start)),
+			zap.Int64("memoryUsage", memUsage),
+			zap.Int64("maxMemoryUsage", sg.memTracker.MaxConsumed()),
+		)
+		return nil
+	})
+}
+
+// finishGlobalStatsMergeJob removes the job if it succeeds or has failed too many times.
+// Otherwise, it records the error and keeps the job for the next round.
+// The job is matched by its creation time, so a job re-enqueued during the merge is not removed.
+func (sg *statsGlobalImpl) finishGlobalStatsMergeJob(job *globalStatsMergeJob, mergeErr error) error {
+	return util.CallWithSCtx(sg.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		if mergeErr == nil || job.retryCount+1 >= maxMergeJobRetryCount {
+			if mergeErr != nil {
+				statslogutil.StatsLogger().Warn("give up merging global stats in the background",
+					zap.Int64("tableID", job.tableID), zap.Int64("indexID", job.indexID), zap.Error(mergeErr))
+			}
+			_, _, err := util.ExecRows(sctx, "delete from mysql.stats_global_merge_jobs where table_id = %? and index_id = %? and create_time = %?",
+				job.tableID, job.indexID, job.createTime)
+			return errors.Trace(err)
+		}
+		statslogutil.StatsLogger().Warn("merge global stats in the background failed",
+			zap.Int64("tableID", job.tableID), zap.Int64("indexID", job.indexID), zap.Error(mergeErr))
+		_, _, err := util.ExecRows(sctx, "update mysql.stats_global_merge_jobs set retry_count = retry_count + 1, last_error = %? where table_id = %? and index_id = %? and create_time = %?",
+			mergeErr.Error(), job.tableID, job.indexID, job.createTime)
+		return errors.Trace(err)
+	}, util.FlagWrapTxn)
+}
@@ -16,18 +16,22 @@ package globalstats
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
 	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/memory"
 	"github.com/tiancaiamao/gp"
 	"go.uber.org/zap"
 )
@@ -37,14 +41,23 @@ const (
 	MaxPartitionMergeBatchSize = 256
 )
 
+// AnalyzeMemoryTracker is the tracker enforcing tidb_mem_quota_analyze. The global stats merged in the background
+// are tracked by it. It's set by the executor package, which owns the tracker.
+var AnalyzeMemoryTracker *memory.Tracker
+
 // statsGlobalImpl implements util.StatsGlobal
 type statsGlobalImpl struct {
 	statsHandler statstypes.StatsHandle
+	// memTracker tracks the memory used by the global stats merged in the background.
+	memTracker *memory.Tracker
 }
 
 // NewStatsGlobal creates a new StatsGlobal.
 func NewStatsGlobal(statsHandler statstypes.StatsHandle) statstypes.StatsGlobal {
-	return &statsGlobalImpl{statsHandler: statsHandler}
+	return &statsGlobalImpl{
+		statsHandler: statsHandler,
+		memTracker:   memory.NewTracker(memory.LabelForAnalyzeMemory, -1),
+	}
 }
 
 // MergePartitionStats2GlobalStatsByTableID merge the partition-level stats to global-level stats based on the tableID.
@@ -53,7 +66,8 @@ func (sg *statsGlobalImpl) MergePartitionStats2GlobalStatsByTableID(sc sessionct
 	info *statstypes.GlobalStatsInfo,
 	physicalID int64,
 ) (err error) {
-	globalStats, err := MergePartitionStats2GlobalStatsByTableID(sc, sg.statsHandler, opts, is, physicalID, info.IsIndex == 1, info.HistIDs)
+	globalStats, err := MergePartitionStats2GlobalStatsByTableID(sc, sg.statsHandler, opts, is, physicalID, info.IsIndex == 1, info.HistIDs,
+		sc.GetSessionVars().StmtCtx.MemTracker)
 	if err != nil {
 		if types.ErrPartitionStatsMissing.Equal(err) || types.ErrPartitionColumnStatsMissing.Equal(err) {
 			// When we find some partition-level stats are missing, we need to report warning.
@@ -80,6 +94,15 @@ type GlobalStats struct {
 	ModifyCount           int64
 }
 
+// MemoryUsage returns the total memory usage of the histograms, CMSketches and TopNs in the GlobalStats.
+// The FMSketches are not counted since they are released after the NDVs are calculated.
+func (g *GlobalStats) MemoryUsage() (sum int64) {
+	for i := 0; i < g.Num; i++ {
+		sum += g.Hg[i].MemoryUsage() + g.Cms[i].MemoryUsage() + g.TopN[i].MemoryUsage()
+	}
+	return
+}
+
 func newGlobalStats(histCount int) *GlobalStats {
 	globalStats := new(GlobalStats)
 	globalStats.Num = histCount
@@ -93,6 +116,8 @@ func newGlobalStats(histCount int) *GlobalStats {
 }
 
 // MergePartitionStats2GlobalStats merge the partition-level stats to global-level stats based on the tableInfo.
+// The memory of the partition-level stats loaded for merging is tracked by a child of parentTracker, so the merge
+// fails instead of causing OOM when it exceeds the quota of parentTracker, e.g. tidb_mem_quota_analyze.
 func MergePartitionStats2GlobalStats(
 	sc sessionctx.Context,
 	statsHandle statstypes.StatsHandle,
@@ -101,13 +126,19 @@ func MergePartitionStats2GlobalStats(
 	globalTableInfo *model.TableInfo,
 	isIndex bool,
 	histIDs []int64,
+	parentTracker *memory.Tracker,
 ) (globalStats *GlobalStats, err error) {
+	memTracker := memory.NewTracker(memory.LabelForAnalyzeMemory, -1)
+	if parentTracker != nil {
+		memTracker.AttachTo(parentTracker)
+		defer memTracker.Detach()
+	}
 	if sc.GetSessionVars().EnableAsyncMergeGlobalStats {
 		statslogutil.SingletonStatsSamplerLogger().Info("use async merge global stats",
 			zap.Int64("tableID", globalTableInfo.ID),
 			zap.String("table", globalTableInfo.Name.L),
 		)
-		worker, err := NewAsyncMergePartitionStats2GlobalStats(statsHandle, globalTableInfo, histIDs, is)
+		worker, err := NewAsyncMergePartitionStats2GlobalStats(statsHandle, globalTableInfo, histIDs, is, memTracker)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -121,7 +152,11 @@ func MergePartitionStats2GlobalStats(
 		zap.Int64("tableID", globalTableInfo.ID),
 		zap.String("table", globalTableInfo.Name.L),
 	)
-	return blockingMergePartitionStats2GlobalStats(sc, statsHandle.GPool(), opts, is, globalTableInfo, isIndex, histIDs, nil, statsHandle)
+	var spillAction *util.AnalyzeSpillAction
+	if parentTracker != nil {
+		spillAction = util.SetAnalyzeSpillAction(memTracker, parentTracker.GetBytesLimit())
+	}
+	return blockingMergePartitionStats2GlobalStats(sc, statsHandle.GPool(), opts, is, globalTableInfo, isIndex, histIDs, nil, statsHandle, memTracker, spillAction)
 }
 
 // MergePartitionStats2GlobalStatsByTableID merge the partition-level stats to global-level stats based on the tableID.
@@ -133,6 +168,7 @@ func MergePartitionStats2GlobalStatsByTableID(
 	tableID int64,
 	isIndex bool,
 	histIDs []int64,
+	parentTracker *memory.Tracker,
 ) (globalStats *GlobalStats, err error) {
 	// Get the partition table IDs.
 	globalTable, ok := statsHandle.TableInfoByID(is, tableID)
@@ -142,7 +178,7 @@ func MergePartitionStats2GlobalStatsByTableID(
 	}
 
 	globalTableInfo := globalTable.Meta()
-	globalStats, err = MergePartitionStats2GlobalStats(sc, statsHandle, opts, is, globalTableInfo, isIndex, histIDs)
+	globalStats, err = MergePartitionStats2GlobalStats(sc, statsHandle, opts, is, globalTableInfo, isIndex, histIDs, parentTracker)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -163,6 +199,19 @@ func MergePartitionStats2GlobalStatsByTableID(
 	return
 }
 
+// mergeNDVSketches gets the global HLL sketch merged from the ones of the partitions to estimate the global NDV, which
+// is maintained incrementally in mysql.stats_global_ndv_sketch.
+// It returns nil if any partition doesn't have the HLL sketch, then the FMSketches are used instead.
+func mergeNDVSketches(statsHandle statstypes.StatsHandle, globalTableInfo *model.TableInfo, partitionIDs []int64, isIndex bool, histID int64) (ndvSketch *statistics.HLLSketch, err error) {
+	allPartitions := len(partitionIDs) == len(globalTableInfo.Partition.Definitions)
+	err = util.CallWithSCtx(statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		var err1 error
+		ndvSketch, err1 = storage.GlobalNDVSketch(sctx, globalTableInfo.ID, partitionIDs, allPartitions, toSQLIndex(isIndex), histID)
+		return err1
+	}, util.FlagWrapTxn)
+	return ndvSketch, err
+}
+
 // analyzeOptionDefault saves the default values of NumBuckets and NumTopN.
 // These values will be used in dynamic mode when we drop table partition and then need to merge global-stats.
 // These values originally came from the analyzeOptionDefault structure in the planner/core/planbuilder.go file.
@@ -183,6 +232,8 @@ func blockingMergePartitionStats2GlobalStats(
 	histIDs []int64,
 	allPartitionStats map[int64]*statistics.Table,
 	statsHandle statstypes.StatsHandle,
+	memTracker *memory.Tracker,
+	spillAction *util.AnalyzeSpillAction,
 ) (globalStats *GlobalStats, err error) {
 	externalCache := false
 	if allPartitionStats != nil {
@@ -212,11 +263,30 @@ func blockingMergePartitionStats2GlobalStats(
 	allCms := make([][]*statistics.CMSketch, globalStats.Num)
 	allTopN := make([][]*statistics.TopN, globalStats.Num)
 	allFms := make([][]*statistics.FMSketch, globalStats.Num)
+	allPartitionIDs := make([][]int64, globalStats.Num)
+	// When the memory exceeds the spill limit, the histograms and the TopNs are released and loaded from the storage
+	// again column by column when they are merged, so at most the partition-level stats of a column are in memory.
+	spilled := make([][]bool, globalStats.Num)
+	partitionTableInfos := make(map[int64]*model.TableInfo, partitionNum)
+	isSpilled := false
+	spillPartitionStats := func() {
+		for i := range allHg {
+			for j := range allHg[i] {
+				if spilled[i][j] {
+					continue
+				}
+				memTracker.Release(allHg[i][j].MemoryUsage() + allTopN[i][j].MemoryUsage())
+				allHg[i][j], allTopN[i][j], spilled[i][j] = nil, nil, true
+			}
+		}
+		isSpilled = true
+	}
 	for i := 0; i < globalStats.Num; i++ {
 		allHg[i] = make([]*statistics.Histogram, 0, partitionNum)
 		allCms[i] = make([]*statistics.CMSketch, 0, partitionNum)
 		allTopN[i] = make([]*statistics.TopN, 0, partitionNum)
 		allFms[i] = make([]*statistics.FMSketch, 0, partitionNum)
+		allPartitionIDs[i] = make([]int64, 0, partitionNum)
 	}
 
 	skipMissingPartitionStats := sc.GetSessionVars().SkipMissingPartitionStats
@@ -228,6 +298,7 @@ func blockingMergePartitionStats2GlobalStats(
 			return
 		}
 		tableInfo := partitionTable.Meta()
+		partitionTableInfos[partitionID] = tableInfo
 		var partitionStats *statistics.Table
 		var okLoad bool
 		if allPartitionStats != nil {
@@ -295,10 +366,22 @@ func blockingMergePartitionStats2GlobalStats(
 			}
 
 			if !skipPartition {
+				// All the partition-level stats are kept until the end of the merge unless they are spilled.
+				if !isSpilled && spillAction.Triggered() {
+					spillPartitionStats()
+				}
+				if isSpilled {
+					memTracker.Consume(cms.MemoryUsage())
+					hg, topN = nil, nil
+				} else {
+					memTracker.Consume(hg.MemoryUsage() + cms.MemoryUsage() + topN.MemoryUsage())
+				}
 				allHg[i] = append(allHg[i], hg)
 				allCms[i] = append(allCms[i], cms)
 				allTopN[i] = append(allTopN[i], topN)
 				allFms[i] = append(allFms[i], fms)
+				allPartitionIDs[i] = append(allPartitionIDs[i], partitionID)
+				spilled[i] = append(spilled[i], isSpilled)
 			}
 		}
 	}
@@ -330,6 +413,16 @@ func blockingMergePartitionStats2GlobalStats(
 			globalStatsNDV = globalStats.Count
 		}
 		globalStats.Fms[i].DestroyAndPutToPool()
+		if variable.EnableHLLNDVSketch.Load() {
+			var ndvSketch *statistics.HLLSketch
+			ndvSketch, err = mergeNDVSketches(statsHandle, globalTableInfo, allPartitionIDs[i], isIndex, histIDs[i])
+			if err != nil {
+				return
+			}
+			if ndvSketch != nil {
+				globalStatsNDV = min(ndvSketch.NDV(), globalStats.Count)
+			}
+		}
 
 		// Merge CMSketch.
 		globalStats.Cms[i] = allCms[i][0]
@@ -340,6 +433,14 @@ func blockingMergePartitionStats2GlobalStats(
 			}
 		}
 
+		var loadedMemSize int64
+		loadedMemSize, err = loadSpilledPartitionStats(statsHandle, isIndex, histIDs[i], allPartitionIDs[i],
+			partitionTableInfos, allHg[i], allTopN[i], spilled[i])
+		if err != nil {
+			return
+		}
+		memTracker.Consume(loadedMemSize)
+
 		// Merge topN.
 		// Note: We need to merge TopN before merging the histogram.
 		// Because after merging TopN, some numbers will be left.
@@ -347,7 +448,8 @@ func blockingMergePartitionStats2GlobalStats(
 		var poppedTopN []statistics.TopNMeta
 		wrapper := NewStatsWrapper(allHg[i], allTopN[i])
 		globalStats.TopN[i], poppedTopN, allHg[i], err = mergeGlobalStatsTopN(gpool, sc, wrapper,
-			sc.GetSessionVars().StmtCtx.TimeZone(), sc.GetSessionVars().AnalyzeVersion, uint32(opts[ast.AnalyzeOptNumTopN]), isIndex)
+			sc.GetSessionVars().StmtCtx.TimeZone(), sc.GetSessionVars().AnalyzeVersion,
+			uint32(statistics.AdaptiveTopNSize(sc, int(opts[ast.AnalyzeOptNumTopN]))), isIndex)
 		if err != nil {
 			return
 		}
@@ -365,10 +467,48 @@ func blockingMergePartitionStats2GlobalStats(
 		}
 
 		globalStats.Hg[i].NDV = globalStatsNDV
+		// The stats loaded again are not needed by the other columns.
+		memTracker.Release(loadedMemSize)
 	}
 	return
 }
 
+// loadSpilledPartitionStats loads the spilled histograms and TopNs of a column from the storage, and returns their
+// memory usage.
+func loadSpilledPartitionStats(
+	statsHandle statstypes.StatsHandle,
+	isIndex bool,
+	histID int64,
+	partitionIDs []int64,
+	partitionTableInfos map[int64]*model.TableInfo,
+	hists []*statistics.Histogram,
+	topNs []*statistics.TopN,
+	spilled []bool,
+) (memSize int64, err error) {
+	if !slices.Contains(spilled, true) {
+		return 0, nil
+	}
+	err = util.CallWithSCtx(statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		for j, partitionID := range partitionIDs {
+			if !spilled[j] {
+				continue
+			}
+			hg, err := storage.LoadHistogram(sctx, partitionID, toSQLIndex(isIndex), histID, partitionTableInfos[partitionID])
+			if err != nil {
+				return err
+			}
+			topN, err := storage.TopNFromStorage(sctx, partitionID, toSQLIndex(isIndex), histID)
+			if err != nil {
+				return err
+			}
+			hists[j], topNs[j] = hg, topN
+			memSize += hg.MemoryUsage() + topN.MemoryUsage()
+		}
+		return nil
+	})
+	return memSize, err
+}
+
 // WriteGlobalStatsToStorage is to write global stats to storage
 func WriteGlobalStatsToStorage(statsHandle statstypes.StatsHandle, globalStats *GlobalStats, info *statstypes.GlobalStatsInfo, gid int64) (err error) {
 	// Dump global-level stats to kv.
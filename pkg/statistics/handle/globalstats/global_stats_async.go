@@ -27,12 +27,14 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
 	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
 	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/memory"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -93,6 +95,10 @@ type AsyncMergePartitionStats2GlobalStats struct {
 	partitionIDs              []int64
 	partitionNum              int
 	skipMissingPartitionStats bool
+	// ndvSketches are the merged HLL sketches, which are used to estimate the global NDV if they exist.
+	ndvSketches []*statistics.HLLSketch
+	// memTracker tracks the histograms and TopNs loaded but not merged yet.
+	memTracker *memory.Tracker
 }
 
 // NewAsyncMergePartitionStats2GlobalStats creates a new AsyncMergePartitionStats2GlobalStats.
@@ -100,7 +106,8 @@ func NewAsyncMergePartitionStats2GlobalStats(
 	statsHandle statstypes.StatsHandle,
 	globalTableInfo *model.TableInfo,
 	histIDs []int64,
-	is infoschema.InfoSchema) (*AsyncMergePartitionStats2GlobalStats, error) {
+	is infoschema.InfoSchema,
+	memTracker *memory.Tracker) (*AsyncMergePartitionStats2GlobalStats, error) {
 	partitionNum := len(globalTableInfo.Partition.Definitions)
 	return &AsyncMergePartitionStats2GlobalStats{
 		statsHandle:             statsHandle,
@@ -118,6 +125,7 @@ func NewAsyncMergePartitionStats2GlobalStats(
 		histIDs:                 histIDs,
 		is:                      is,
 		partitionNum:            partitionNum,
+		memTracker:              memTracker,
 	}, nil
 }
 
@@ -239,6 +247,14 @@ func (a *AsyncMergePartitionStats2GlobalStats) ioWorker(sctx sessionctx.Context,
 		close(a.ioWorkerExitWhenErrChan)
 		return err
 	}
+	if variable.EnableHLLNDVSketch.Load() {
+		// Load them before closing the fmsketch channel, so the cpuWorker can see them after dealing with the FMSketches.
+		err = a.loadNDVSketch(isIndex)
+		if err != nil {
+			close(a.ioWorkerExitWhenErrChan)
+			return err
+		}
+	}
 	close(a.fmsketch)
 	err = a.loadCMsketch(sctx, isIndex)
 	if err != nil {
@@ -280,6 +296,9 @@ func (a *AsyncMergePartitionStats2GlobalStats) cpuWorker(stmtCtx *stmtctx.Statem
 			if globalStatsNDV > a.globalStats.Count {
 				globalStatsNDV = a.globalStats.Count
 			}
+			if a.ndvSketches != nil && a.ndvSketches[i] != nil {
+				globalStatsNDV = min(a.ndvSketches[i].NDV(), a.globalStats.Count)
+			}
 			a.globalStatsNDV = append(a.globalStatsNDV, globalStatsNDV)
 			a.globalStats.Fms[i].DestroyAndPutToPool()
 		}
@@ -373,6 +392,28 @@ func (a *AsyncMergePartitionStats2GlobalStats) loadFmsketch(sctx sessionctx.Cont
 	return nil
 }
 
+func (a *AsyncMergePartitionStats2GlobalStats) loadNDVSketch(isIndex bool) error {
+	a.ndvSketches = make([]*statistics.HLLSketch, a.globalStats.Num)
+	for i := 0; i < a.globalStats.Num; i++ {
+		partitionIDs := make([]int64, 0, a.partitionNum)
+		for _, partitionID := range a.partitionIDs {
+			if _, ok := a.skipPartition[skipItem{
+				histID:      a.histIDs[i],
+				partitionID: partitionID,
+			}]; ok {
+				continue
+			}
+			partitionIDs = append(partitionIDs, partitionID)
+		}
+		ndvSketch, err := mergeNDVSketches(a.statsHandle, a.globalTableInfo, partitionIDs, isIndex, a.histIDs[i])
+		if err != nil {
+			return err
+		}
+		a.ndvSketches[i] = ndvSketch
+	}
+	return nil
+}
+
 func (a *AsyncMergePartitionStats2GlobalStats) loadCMsketch(sctx sessionctx.Context, isIndex bool) error {
 	failpoint.Inject("PanicInIOWorker", nil)
 	for i := 0; i < a.globalStats.Num; i++ {
@@ -414,6 +455,7 @@ func (a *AsyncMergePartitionStats2GlobalStats) loadHistogramAndTopN(sctx session
 	for i := 0; i < a.globalStats.Num; i++ {
 		hists := make([]*statistics.Histogram, 0, a.partitionNum)
 		topn := make([]*statistics.TopN, 0, a.partitionNum)
+		memSize := int64(0)
 		for _, partitionID := range a.partitionIDs {
 			_, ok := a.skipPartition[skipItem{
 				histID:      a.histIDs[i],
@@ -432,10 +474,15 @@ func (a *AsyncMergePartitionStats2GlobalStats) loadHistogramAndTopN(sctx session
 			}
 			hists = append(hists, h)
 			topn = append(topn, t)
+			size := h.MemoryUsage() + t.MemoryUsage()
+			a.memTracker.Consume(size)
+			memSize += size
 		}
+		wrapper := NewStatsWrapper(hists, topn)
+		wrapper.memSize = memSize
 		select {
 		case a.histogramAndTopn <- mergeItem[*StatsWrapper]{
-			NewStatsWrapper(hists, topn), i,
+			wrapper, i,
 		}:
 		case <-a.cpuWorkerExitChan:
 			statslogutil.StatsLogger().Warn("ioWorker detects CPUWorker has exited")
@@ -513,7 +560,7 @@ func (a *AsyncMergePartitionStats2GlobalStats) dealHistogramAndTopN(stmtCtx *stm
 			var allhg []*statistics.Histogram
 			wrapper := item.item
 			a.globalStats.TopN[item.idx], poppedTopN, allhg, err = mergeGlobalStatsTopN(a.statsHandle.GPool(), sctx, wrapper,
-				tz, analyzeVersion, uint32(opts[ast.AnalyzeOptNumTopN]), isIndex)
+				tz, analyzeVersion, uint32(statistics.AdaptiveTopNSize(sctx, int(opts[ast.AnalyzeOptNumTopN]))), isIndex)
 			if err != nil {
 				return err
 			}
@@ -531,6 +578,7 @@ func (a *AsyncMergePartitionStats2GlobalStats) dealHistogramAndTopN(stmtCtx *stm
 				(*globalHg).Buckets[j].NDV = 0
 			}
 			(*globalHg).NDV = a.globalStatsNDV[item.idx]
+			a.memTracker.Consume(-wrapper.memSize)
 		case <-a.ioWorkerExitWhenErrChan:
 			return nil
 		}
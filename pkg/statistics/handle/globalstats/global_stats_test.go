@@ -661,6 +661,77 @@ func TestGlobalStatsNDV(t *testing.T) {
 	checkNDV(13, 3, 3, 3, 4)
 }
 
+func TestGlobalStatsHLLNDVSketch(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("set @@global.tidb_enable_hll_ndv_sketch = 1")
+	defer tk.MustExec("set @@global.tidb_enable_hll_ndv_sketch = default")
+	tk.MustExec(`CREATE TABLE t ( a int, b int )
+	PARTITION BY RANGE (a) (
+    PARTITION p0 VALUES LESS THAN (10),
+    PARTITION p1 VALUES LESS THAN (20))`)
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (11, 2), (12, 3), (13, 4), (14, 5)")
+
+	for _, asyncMerge := range []string{"0", "1"} {
+		tk.MustExec("set @@tidb_enable_async_merge_global_stats = " + asyncMerge)
+		tk.MustExec("analyze table t")
+		// Only the partitions have the HLL sketches.
+		tk.MustQuery("select count(*) from mysql.stats_histograms where ndv_sketch is not null").Check(testkit.Rows("4"))
+		rs := tk.MustQuery("show stats_histograms where table_name = 't' and column_name = 'b'").Rows()
+		require.Len(t, rs, 3)
+		require.Equal(t, "global", rs[0][2])
+		require.Equal(t, "5", rs[0][6])
+	}
+
+	// Fall back to the FMSketches if any partition doesn't have the HLL sketch.
+	tk.MustExec("update mysql.stats_histograms set ndv_sketch = null where table_id = (select tidb_partition_id from information_schema.partitions where table_name = 't' and partition_name = 'p0') and hist_id = 2")
+	tk.MustExec("delete from mysql.stats_global_ndv_sketch")
+	tk.MustExec("analyze table t partition p1")
+	tk.MustQuery("show stats_histograms where table_name = 't' and column_name = 'b' and partition_name = 'global'").CheckAt([]int{6}, testkit.Rows("5"))
+	// The global sketch of b can't be built without the sketch of p0.
+	tk.MustQuery("select hist_id from mysql.stats_global_ndv_sketch").Check(testkit.Rows("1"))
+}
+
+func TestGlobalNDVSketchIncremental(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("set @@global.tidb_enable_hll_ndv_sketch = 1")
+	defer tk.MustExec("set @@global.tidb_enable_hll_ndv_sketch = default")
+	tk.MustExec(`CREATE TABLE t ( a int, b int )
+	PARTITION BY RANGE (a) (
+    PARTITION p0 VALUES LESS THAN (10),
+    PARTITION p1 VALUES LESS THAN (20))`)
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (11, 2), (12, 3), (13, 4), (14, 5)")
+	tk.MustExec("analyze table t")
+	// The global sketches are built by the merge.
+	tk.MustQuery("select count(*) from mysql.stats_global_ndv_sketch where table_id = (select tidb_table_id from information_schema.tables where table_schema = 'test' and table_name = 't')").Check(testkit.Rows("2"))
+	version := tk.MustQuery("select version from mysql.stats_global_ndv_sketch where hist_id = 2").Rows()[0][0].(string)
+
+	// The new sketch of p1 is merged into the global one, which doesn't need the sketch of p0 any more.
+	tk.MustExec("update mysql.stats_histograms set ndv_sketch = null where table_id = (select tidb_partition_id from information_schema.partitions where table_name = 't' and partition_name = 'p0') and hist_id = 2")
+	tk.MustExec("insert into t values (15, 6), (16, 7)")
+	tk.MustExec("analyze table t partition p1")
+	tk.MustQuery("show stats_histograms where table_name = 't' and column_name = 'b' and partition_name = 'global'").CheckAt([]int{6}, testkit.Rows("7"))
+	rows := tk.MustQuery("select version from mysql.stats_global_ndv_sketch where hist_id = 2").Rows()
+	require.Len(t, rows, 1)
+	require.NotEqual(t, version, rows[0][0])
+
+	// The global sketch is built again after the values are removed from p1.
+	tk.MustExec("delete from t where b >= 4")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("show stats_histograms where table_name = 't' and column_name = 'b' and partition_name = 'global'").CheckAt([]int{6}, testkit.Rows("3"))
+	tk.MustQuery("select count(*) from mysql.stats_global_ndv_sketch").Check(testkit.Rows("2"))
+
+	// The global sketches are removed if a partition is analyzed without the HLL sketch.
+	tk.MustExec("set @@global.tidb_enable_hll_ndv_sketch = 0")
+	tk.MustExec("analyze table t partition p0")
+	tk.MustQuery("select count(*) from mysql.stats_global_ndv_sketch").Check(testkit.Rows("0"))
+}
+
 func TestGlobalStatsIndexNDV(t *testing.T) {
 	store := testkit.CreateMockStore(t)
 	tk := testkit.NewTestKit(t, store)
@@ -966,3 +1037,50 @@ func TestGlobalStatsAndSQLBindingWithConcurrency(t *testing.T) {
 	tk.MustExec("set global tidb_merge_partition_stats_concurrency=2")
 	testGlobalStatsAndSQLBinding(tk)
 }
+
+func TestBackgroundMergeGlobalStats(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("set @@global.tidb_enable_background_merge_global_stats = 1")
+	defer tk.MustExec("set @@global.tidb_enable_background_merge_global_stats = default")
+	tk.MustExec("create table t (a int, b int, key(a)) partition by hash(a) partitions 2")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4)")
+	tk.MustExec("analyze table t all columns with 0 topn, 1 buckets")
+	tk.MustQuery("show warnings").CheckContain("The global stats will be merged in the background")
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tableID := tbl.Meta().ID
+	tk.MustQuery("select index_id, hist_ids, retry_count from mysql.stats_global_merge_jobs order by index_id").Check(testkit.Rows(
+		"-1 [1,2] 0",
+		"1 [1] 0",
+	))
+	require.Len(t, tk.MustQuery("show stats_histograms where partition_name='global'").Rows(), 0)
+
+	// Enqueue the same job again, the histogram IDs are merged.
+	tk.MustExec("analyze table t columns a with 0 topn, 1 buckets")
+	tk.MustQuery("select index_id, hist_ids from mysql.stats_global_merge_jobs order by index_id").Check(testkit.Rows(
+		"-1 [1,2]",
+		"1 [1]",
+	))
+
+	h := dom.StatsHandle()
+	require.NoError(t, h.MergePendingGlobalStats(dom.InfoSchema()))
+	tk.MustQuery("select count(*) from mysql.stats_global_merge_jobs").Check(testkit.Rows("0"))
+	tk.MustQuery(fmt.Sprintf("select count from mysql.stats_meta where table_id = %d", tableID)).Check(testkit.Rows("4"))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	tk.MustQuery("show stats_histograms where partition_name='global'").CheckAt([]int{3, 6}, testkit.Rows(
+		"a 4",
+		"b 4",
+		"a 4",
+	))
+
+	// The job of the dropped table is removed.
+	tk.MustExec("analyze table t all columns")
+	tk.MustExec("drop table t")
+	require.NoError(t, dom.Reload())
+	require.NoError(t, h.MergePendingGlobalStats(dom.InfoSchema()))
+	tk.MustQuery("select count(*) from mysql.stats_global_merge_jobs").Check(testkit.Rows("0"))
+}
@@ -27,6 +27,8 @@ import (
 type StatsWrapper struct {
 	AllHg   []*statistics.Histogram
 	AllTopN []*statistics.TopN
+	// memSize is the memory usage of the partition-level stats tracked when they are loaded.
+	memSize int64
 }
 
 // NewStatsWrapper returns wrapper
@@ -17,6 +17,7 @@ package handle
 import (
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/ddl/notifier"
 	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/meta/model"
@@ -36,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics/handle/usage"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	pkgutil "github.com/pingcap/tidb/pkg/util"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
 
@@ -143,8 +145,8 @@ func NewHandle(
 	handle.StatsSyncLoad = syncload.NewStatsSyncLoad(is, handle)
 	handle.StatsGlobal = globalstats.NewStatsGlobal(handle)
 	handle.DDL = ddl.NewDDLHandler(
-		handle.StatsReadWriter,
 		handle,
+		ddlNotifier,
 	)
 	return handle, nil
 }
@@ -171,6 +173,86 @@ func (h *Handle) GetPartitionStatsForAutoAnalyze(tblInfo *model.TableInfo, pid i
 	return h.getPartitionStats(tblInfo, pid, false)
 }
 
+// GetTableStatsSummary returns the summary of the cached stats of the table or the partition.
+func (h *Handle) GetTableStatsSummary(is infoschema.InfoSchema, physicalID int64) (*types.TableStatsSummary, error) {
+	tbl, ok := h.TableInfoByID(is, physicalID)
+	if !ok {
+		return nil, errors.Errorf("table %d does not exist", physicalID)
+	}
+	tblInfo := tbl.Meta()
+	statsTbl := h.GetPartitionStats(tblInfo, physicalID)
+	summary := &types.TableStatsSummary{
+		PhysicalID:  physicalID,
+		Version:     statsTbl.Version,
+		RowCount:    statsTbl.RealtimeCount,
+		ModifyCount: statsTbl.ModifyCount,
+		Pseudo:      statsTbl.Pseudo,
+	}
+	if statsTbl.Pseudo {
+		return summary, nil
+	}
+	for _, colInfo := range tblInfo.Columns {
+		col := statsTbl.GetCol(colInfo.ID)
+		if col == nil || !col.IsAnalyzed() {
+			continue
+		}
+		summary.Columns = append(summary.Columns, types.ColumnStatsSummary{
+			ID:         colInfo.ID,
+			Name:       colInfo.Name.O,
+			NDV:        col.NDV,
+			NullCount:  col.NullCount,
+			TotColSize: col.TotColSize,
+		})
+	}
+	for _, idxInfo := range tblInfo.Indices {
+		idx := statsTbl.GetIdx(idxInfo.ID)
+		if idx == nil || !idx.IsAnalyzed() {
+			continue
+		}
+		summary.Indexes = append(summary.Indexes, types.ColumnStatsSummary{
+			ID:         idxInfo.ID,
+			Name:       idxInfo.Name.O,
+			NDV:        idx.NDV,
+			NullCount:  idx.NullCount,
+			TotColSize: idx.TotColSize,
+		})
+	}
+	return summary, nil
+}
+
+// GetTableStatsHealth returns the health of the cached stats of the table and its partitions.
+func (h *Handle) GetTableStatsHealth(dbName string, tblInfo *model.TableInfo) []types.TableStatsHealth {
+	newHealth := func(partitionName string, physicalID int64) types.TableStatsHealth {
+		statsTbl := h.GetPartitionStats(tblInfo, physicalID)
+		health := types.TableStatsHealth{
+			DBName:         dbName,
+			TableName:      tblInfo.Name.O,
+			PartitionName:  partitionName,
+			PhysicalID:     physicalID,
+			Pseudo:         statsTbl.Pseudo,
+			RowCount:       statsTbl.RealtimeCount,
+			ModifyCount:    statsTbl.ModifyCount,
+			UnusableReason: statsTbl.GetStatsUnusableReason(tblInfo),
+		}
+		health.Healthy, _ = statsTbl.GetStatsHealthy()
+		if statsTbl.IsAnalyzed() {
+			lastAnalyzeTime := oracle.GetTimeFromTS(statsTbl.LastAnalyzeVersion)
+			health.LastAnalyzeTime = &lastAnalyzeTime
+		}
+		return health
+	}
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		return []types.TableStatsHealth{newHealth("", tblInfo.ID)}
+	}
+	healths := make([]types.TableStatsHealth, 0, len(pi.Definitions)+1)
+	healths = append(healths, newHealth("global", tblInfo.ID))
+	for _, def := range pi.Definitions {
+		healths = append(healths, newHealth(def.Name.O, def.ID))
+	}
+	return healths
+}
+
 func (h *Handle) getPartitionStats(tblInfo *model.TableInfo, pid int64, returnPseudo bool) *statistics.Table {
 	var tbl *statistics.Table
 	if h == nil {
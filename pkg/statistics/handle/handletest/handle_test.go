@@ -392,9 +392,10 @@ func TestCorrelation(t *testing.T) {
 	require.Equal(t, "1", result.Rows()[0][9])
 	require.Equal(t, "1", result.Rows()[1][9])
 	require.Equal(t, "1", result.Rows()[2][9])
+	// The correlation of the index is collected by the analyze version 2.
 	result = testKit.MustQuery("show stats_histograms where Table_name = 't' and Is_index = 1").Sort()
 	require.Len(t, result.Rows(), 1)
-	require.Equal(t, "0", result.Rows()[0][9])
+	require.Equal(t, "1", result.Rows()[0][9])
 }
 
 func TestMergeGlobalTopN(t *testing.T) {
@@ -574,10 +575,15 @@ func TestCorrelationStatsCompute(t *testing.T) {
 	tk.MustExec("create table t(a int, b int, c int, index idx(a, b, c))")
 	tk.MustExec("insert into t values(1,1,5),(2,2,4),(3,3,3),(4,4,2),(5,5,1)")
 	tk.MustExec("analyze table t")
-	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Check(testkit.Rows())
+	// The correlations between the columns of the composite index are collected by the analyze version 2.
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"2 [1,2] 1.000000 1",
+		"2 [1,3] -1.000000 1",
+		"2 [2,3] -1.000000 1",
+	))
 	tk.MustExec("alter table t add stats_extended s1 correlation(a,b)")
 	tk.MustExec("alter table t add stats_extended s2 correlation(a,c)")
-	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended where name like 's_'").Sort().Check(testkit.Rows(
 		"2 [1,2] <nil> 0",
 		"2 [1,3] <nil> 0",
 	))
@@ -591,16 +597,16 @@ func TestCorrelationStatsCompute(t *testing.T) {
 	statsTbl := do.StatsHandle().GetTableStats(tableInfo)
 	require.NotNil(t, statsTbl)
 	require.NotNil(t, statsTbl.ExtendedStats)
-	require.Len(t, statsTbl.ExtendedStats.Stats, 0)
+	require.Len(t, statsTbl.ExtendedStats.Stats, 3)
 
 	tk.MustExec("analyze table t")
-	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended where name like 's_'").Sort().Check(testkit.Rows(
 		"2 [1,2] 1.000000 1",
 		"2 [1,3] -1.000000 1",
 	))
 	tk.MustExec("set @@session.tidb_analyze_version=2")
 	tk.MustExec("analyze table t")
-	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended where name like 's_'").Sort().Check(testkit.Rows(
 		"2 [1,2] 1.000000 1",
 		"2 [1,3] -1.000000 1",
 	))
@@ -609,7 +615,7 @@ func TestCorrelationStatsCompute(t *testing.T) {
 	statsTbl = do.StatsHandle().GetTableStats(tableInfo)
 	require.NotNil(t, statsTbl)
 	require.NotNil(t, statsTbl.ExtendedStats)
-	require.Len(t, statsTbl.ExtendedStats.Stats, 2)
+	require.Len(t, statsTbl.ExtendedStats.Stats, 5)
 	foundS1, foundS2 := false, false
 	for name, item := range statsTbl.ExtendedStats.Stats {
 		switch name {
@@ -620,7 +626,7 @@ func TestCorrelationStatsCompute(t *testing.T) {
 			foundS2 = true
 			require.Equal(t, float64(-1), item.ScalarVals)
 		default:
-			require.FailNow(t, "Unexpected extended stats in cache")
+			require.True(t, statistics.IsIndexColCorrelation(name), "Unexpected extended stats in cache")
 		}
 	}
 	require.True(t, foundS1 && foundS2)
@@ -630,31 +636,167 @@ func TestCorrelationStatsCompute(t *testing.T) {
 	tk.MustExec("insert into t values(1,null,2), (2,null,null)")
 	tk.MustExec("set @@session.tidb_analyze_version=1")
 	tk.MustExec("analyze table t")
-	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended where name like 's_'").Sort().Check(testkit.Rows(
 		"2 [1,2] 0.000000 1",
 		"2 [1,3] 1.000000 1",
 	))
 	tk.MustExec("set @@session.tidb_analyze_version=2")
 	tk.MustExec("analyze table t")
-	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended where name like 's_'").Sort().Check(testkit.Rows(
 		"2 [1,2] 0.000000 1",
 		"2 [1,3] 1.000000 1",
 	))
 	tk.MustExec("insert into t values(3,3,3)")
 	tk.MustExec("set @@session.tidb_analyze_version=1")
 	tk.MustExec("analyze table t")
-	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended where name like 's_'").Sort().Check(testkit.Rows(
 		"2 [1,2] 1.000000 1",
 		"2 [1,3] 1.000000 1",
 	))
 	tk.MustExec("set @@session.tidb_analyze_version=2")
 	tk.MustExec("analyze table t")
-	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended where name like 's_'").Sort().Check(testkit.Rows(
 		"2 [1,2] 1.000000 1",
 		"2 [1,3] 1.000000 1",
 	))
 }
 
+func TestCardinalityStatsCompute(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int)")
+	tk.MustExec("insert into t values(1,1,1),(2,1,2),(3,1,3),(4,1,4),(1,2,5),(2,2,6),(3,2,7),(4,2,8),(1,1,9),(2,1,10),(3,1,11),(4,1,12),(1,2,13),(2,2,14),(3,2,15),(4,2,16)")
+	err := tk.ExecToErr("alter table t add stats_extended s1 cardinality(a)")
+	require.Equal(t, "Only support Cardinality statistics type on at least 2 columns", err.Error())
+	tk.MustExec("alter table t add stats_extended s1 cardinality(a,b)")
+	tk.MustExec("alter table t add stats_extended s2 cardinality(a,b,c)")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"0 [1,2,3] <nil> 0",
+		"0 [1,2] <nil> 0",
+	))
+
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"0 [1,2,3] 16.000000 1",
+		"0 [1,2] 8.000000 1",
+	))
+	require.NoError(t, dom.StatsHandle().Update(context.Background(), dom.InfoSchema()))
+	rows := tk.MustQuery("show stats_extended where db_name = 'test' and table_name = 't' and stats_name = 's1'").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, []any{"test", "t", "s1", "[a,b]", "cardinality", "8"}, rows[0][:len(rows[0])-1])
+
+	// The joint NDV is used instead of the max NDV of a and b.
+	estRows := func() string {
+		return tk.MustQuery("explain format = 'brief' select a, b from t group by a, b").Rows()[0][1].(string)
+	}
+	require.Equal(t, "8.00", estRows())
+	tk.MustExec("set session tidb_enable_extended_stats = off")
+	require.Equal(t, "4.00", estRows())
+}
+
+func TestDependencyStatsCompute(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	// b is determined by a, e.g, a is the city and b is the province.
+	tk.MustExec("insert into t values(1,1),(2,1),(3,2),(4,2),(5,3),(6,3),(7,4),(8,4),(1,1),(2,1),(3,2),(4,2),(5,3),(6,3),(7,4),(8,4)")
+	tk.MustExec("alter table t add stats_extended s1 dependency(a,b)")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Check(testkit.Rows(
+		"1 [1,2] <nil> 0",
+	))
+
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Check(testkit.Rows(
+		"1 [1,2] 1.000000 1",
+	))
+	require.NoError(t, dom.StatsHandle().Update(context.Background(), dom.InfoSchema()))
+	rows := tk.MustQuery("show stats_extended where db_name = 'test' and table_name = 't' and stats_name = 's1'").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, []any{"test", "t", "s1", "[a,b]", "dependency", "1.000000"}, rows[0][:len(rows[0])-1])
+
+	// The selectivity of b = 1 is not multiplied since it's determined by a = 1.
+	estRows := func() string {
+		return tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 1").Rows()[0][1].(string)
+	}
+	require.Equal(t, "2.00", estRows())
+	tk.MustExec("set session tidb_enable_extended_stats = off")
+	require.Equal(t, "0.50", estRows())
+}
+
+func TestForeignKeyStatsCompute(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table p(id int primary key, b int)")
+	tk.MustExec("create table c(id int primary key, pid int, key(pid), foreign key fk_p(pid) references p(id))")
+	tk.MustExec("insert into p values (1,1),(2,2),(3,3),(4,4),(5,5),(6,6),(7,7),(8,8),(9,9),(10,10)")
+	// Half of the child rows reference the parent rows which don't exist.
+	tk.MustExec("set @@foreign_key_checks = 0")
+	for i := 1; i <= 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into c values (%d, %d)", i, i))
+	}
+	tk.MustExec("analyze table p all columns")
+	tk.MustExec("analyze table c all columns")
+	tk.MustQuery("select type, column_ids, json_extract(stats, '$.match_ratio'), json_extract(stats, '$.validated') from mysql.stats_extended").Check(testkit.Rows(
+		"4 [2] 1 false",
+	))
+	require.NoError(t, dom.StatsHandle().Update(context.Background(), dom.InfoSchema()))
+	rows := tk.MustQuery("show stats_extended where db_name = 'test' and table_name = 'c'").Rows()
+	require.Len(t, rows, 1)
+	require.Equal(t, []any{"test", "c", "[pid]", "foreign_key"}, []any{rows[0][0], rows[0][1], rows[0][3], rows[0][4]})
+
+	// Each child row is regarded as referencing exactly one parent row.
+	estRows := func() string {
+		return tk.MustQuery("explain format = 'brief' select * from c join p on c.pid = p.id").Rows()[0][1].(string)
+	}
+	require.Equal(t, "20.00", estRows())
+	tk.MustExec("set session tidb_opt_enable_fk_join_estimation = off")
+	require.Equal(t, "10.00", estRows())
+	tk.MustExec("set session tidb_opt_enable_fk_join_estimation = on")
+
+	// The validation finds that only half of the sampled values reference the parent rows.
+	tk.MustExec("set session tidb_analyze_validate_foreign_keys = on")
+	tk.MustExec("analyze table c all columns")
+	tk.MustQuery("select type, column_ids, json_extract(stats, '$.match_ratio'), json_extract(stats, '$.validated') from mysql.stats_extended").Check(testkit.Rows(
+		"4 [2] 0.5 true",
+	))
+	require.NoError(t, dom.StatsHandle().Update(context.Background(), dom.InfoSchema()))
+	require.Equal(t, "10.00", estRows())
+}
+
+func TestCardinalityStatsPartialGroupBy(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("set @@session.tidb_analyze_version = 2")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, d int)")
+	tk.MustExec("insert into t values(1,1,1,0),(2,1,2,0),(3,1,3,0),(4,1,4,0),(1,2,5,0),(2,2,6,0),(3,2,7,0),(4,2,8,0)")
+	tk.MustExec("insert into t select a, b, c + 8, 1 from t")
+	tk.MustExec("insert into t select * from t")
+	tk.MustExec("alter table t add stats_extended s1 cardinality(a,b)")
+	tk.MustExec("analyze table t")
+	require.NoError(t, dom.StatsHandle().Update(context.Background(), dom.InfoSchema()))
+
+	estRows := func(sql string) string {
+		return tk.MustQuery("explain format = 'brief' " + sql).Rows()[0][1].(string)
+	}
+	// The joint NDV of a and b is multiplied by the NDV of the remaining column d.
+	require.Equal(t, "16.00", estRows("select a, b, d from t group by a, b, d"))
+	// The product is bounded by the row count.
+	require.Equal(t, "32.00", estRows("select a, b, c, d from t group by a, b, c, d"))
+	// The group which is not covered by any extended stats still uses the max NDV.
+	require.Equal(t, "16.00", estRows("select c, d from t group by c, d"))
+	tk.MustExec("set session tidb_enable_extended_stats = off")
+	require.Equal(t, "4.00", estRows("select a, b, d from t group by a, b, d"))
+}
+
 func TestSyncStatsExtendedRemoval(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	tk := testkit.NewTestKit(t, store)
@@ -1489,3 +1631,45 @@ func TestSkipMissingPartitionStats(t *testing.T) {
 		return false
 	})
 }
+
+func TestStatsReadOnly(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	h := dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, index idx(a))")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	tk.MustExec("analyze table t all columns")
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tableID := tbl.Meta().ID
+
+	restore := config.RestoreFunc()
+	defer restore()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Performance.StatsReadOnly = true
+		conf.Performance.StatsReadStaleness = "0s"
+	})
+	// The delta and the column stats usage are dropped instead of being written.
+	tk.MustExec("insert into t values (3, 3)")
+	tk.MustQuery("select * from t where b > 2").Check(testkit.Rows("3 3"))
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.DumpColStatsUsageToKV())
+	tk.MustQuery("select count, modify_count from mysql.stats_meta where table_id = ?", tableID).Check(testkit.Rows("2 0"))
+	tk.MustQuery("select count(*) from mysql.column_stats_usage where table_id = ? and last_used_at is not null", tableID).Check(testkit.Rows("0"))
+	// ANALYZE is rejected.
+	tk.MustGetErrMsg("analyze table t", util.ErrStatsReadOnly.Error())
+
+	// The stats cache is still updated from the storage.
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Performance.StatsReadOnly = false
+	})
+	tk.MustExec("insert into t values (4, 4)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Performance.StatsReadOnly = true
+	})
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	require.Equal(t, int64(3), h.GetTableStats(tbl.Meta()).RealtimeCount)
+}
@@ -37,6 +37,9 @@ const (
 
 	insertSQL            = "INSERT INTO mysql.stats_table_locked (table_id) VALUES (%?) ON DUPLICATE KEY UPDATE table_id = %?"
 	updateMetaVersionSQL = "UPDATE mysql.stats_meta SET version = %? WHERE table_id = %?"
+	// The tables without stats meta record nothing.
+	recordHistoricalStatsMetaSQL = "REPLACE INTO mysql.stats_meta_history (table_id, modify_count, count, version, source, create_time) " +
+		"SELECT table_id, modify_count, count, version, %?, NOW() FROM mysql.stats_meta WHERE table_id = %? AND version = %?"
 )
 
 // statsLockImpl implements the util.StatsLock interface.
@@ -307,5 +310,18 @@ func insertIntoStatsTableLockedAndUpdateStatsVersion(sctx sessionctx.Context, ti
 		logutil.StatsLogger().Error("error occurred when update mysql.stats_meta version", zap.Error(err))
 		return err
 	}
+	return recordHistoricalStatsMeta(sctx, tid, version, util.StatsMetaHistorySourceLockStats)
+}
+
+// recordHistoricalStatsMeta records the stats meta of the version changed by locking or unlocking the stats
+// to mysql.stats_meta_history in the same transaction, if tidb_enable_historical_stats is on.
+func recordHistoricalStatsMeta(sctx sessionctx.Context, tid int64, version uint64, source string) error {
+	if !sctx.GetSessionVars().EnableHistoricalStats {
+		return nil
+	}
+	if _, _, err := util.ExecRows(sctx, recordHistoricalStatsMetaSQL, source, tid, version); err != nil {
+		logutil.StatsLogger().Error("error occurred when record mysql.stats_meta_history", zap.Error(err))
+		return err
+	}
 	return nil
 }
@@ -181,6 +181,30 @@ func TestInsertIntoStatsTableLocked(t *testing.T) {
 
 	err = insertIntoStatsTableLockedAndUpdateStatsVersion(wrapAsSCtx(exec), 1)
 	require.Equal(t, "test error", err.Error())
+
+	// The stats meta history is recorded if the historical stats is enabled.
+	exec.EXPECT().ExecRestrictedSQL(
+		util.StatsCtx,
+		util.UseCurrentSessionOpt,
+		gomock.Eq(insertSQL),
+		gomock.Eq([]any{int64(1), int64(1)}),
+	)
+	exec.EXPECT().ExecRestrictedSQL(
+		util.StatsCtx,
+		util.UseCurrentSessionOpt,
+		gomock.Eq(updateMetaVersionSQL),
+		gomock.Any(),
+	)
+	exec.EXPECT().ExecRestrictedSQL(
+		util.StatsCtx,
+		util.UseCurrentSessionOpt,
+		gomock.Eq(recordHistoricalStatsMetaSQL),
+		gomock.Any(),
+	)
+	sctx := wrapAsSCtx(exec)
+	sctx.GetSessionVars().EnableHistoricalStats = true
+	err = insertIntoStatsTableLockedAndUpdateStatsVersion(sctx, 1)
+	require.NoError(t, err)
 }
 
 func TestAddLockedTables(t *testing.T) {
@@ -157,7 +157,7 @@ func updateDelta(sctx sessionctx.Context, count, modifyCount int64, tid int64) e
 		return err
 	}
 
-	return nil
+	return recordHistoricalStatsMeta(sctx, tid, version, util.StatsMetaHistorySourceUnlockStats)
 }
 
 func updateStatsAndUnlockTable(sctx sessionctx.Context, tid int64) error {
@@ -23,6 +23,13 @@ import (
 var (
 	StatsHealthyGauges []prometheus.Gauge
 
+	StaleStatsAlertGauge           prometheus.Gauge
+	EstimationErrorStatsAlertGauge prometheus.Gauge
+
+	AnalyzeDebtBytesGauge  prometheus.Gauge
+	AnalyzeThroughputGauge prometheus.Gauge
+	AnalyzeDebtETAGauge    prometheus.Gauge
+
 	DumpHistoricalStatsSuccessCounter prometheus.Counter
 	DumpHistoricalStatsFailedCounter  prometheus.Counter
 )
@@ -45,6 +52,13 @@ func InitMetricsVars() {
 		metrics.StatsHealthyGauge.WithLabelValues("unneeded analyze"), // 8
 	}
 
+	StaleStatsAlertGauge = metrics.StatsAlertGauge.WithLabelValues("stale")
+	EstimationErrorStatsAlertGauge = metrics.StatsAlertGauge.WithLabelValues("estimation_error")
+
+	AnalyzeDebtBytesGauge = metrics.AnalyzeDebtGauge.WithLabelValues("bytes")
+	AnalyzeThroughputGauge = metrics.AnalyzeDebtGauge.WithLabelValues("throughput")
+	AnalyzeDebtETAGauge = metrics.AnalyzeDebtGauge.WithLabelValues("eta_seconds")
+
 	DumpHistoricalStatsSuccessCounter = metrics.HistoricalStatsCounter.WithLabelValues("dump", "success")
 	DumpHistoricalStatsFailedCounter = metrics.HistoricalStatsCounter.WithLabelValues("dump", "fail")
 }
@@ -0,0 +1,558 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+// The binary stats format is a stream of frames, so both the encoder and the decoder only need to
+// hold the stats of one physical table in memory, no matter how many partitions the table has.
+//
+//	magic | version | header frame | table frame* | end frame
+//
+// Every frame is a one-byte kind, followed by the uvarint length of the payload and the payload.
+// The histograms and sketches in the payload are encoded in protobuf, and the other fields are varints.
+const (
+	binaryStatsMagic   = "TIDBSTATS"
+	binaryStatsVersion = 1
+
+	binaryFrameHeader byte = 1
+	binaryFrameTable  byte = 2
+	binaryFrameEnd    byte = 3
+
+	// maxBinaryFrameSize is the max size of a frame, which protects the decoder from the corrupted input.
+	maxBinaryFrameSize = 1 << 32
+)
+
+// IsBinaryStats checks whether the data read from r is in the binary stats format without consuming it,
+// so that the callers can load the stats dumped in both the json and the binary format.
+func IsBinaryStats(r *bufio.Reader) bool {
+	magic, err := r.Peek(len(binaryStatsMagic))
+	return err == nil && string(magic) == binaryStatsMagic
+}
+
+// binaryStatsWriter writes the stats of a table in the binary format.
+type binaryStatsWriter struct {
+	w   *bufio.Writer
+	enc binaryEncoder
+}
+
+func newBinaryStatsWriter(w io.Writer, dbName, tableName string) (*binaryStatsWriter, error) {
+	bw := &binaryStatsWriter{w: bufio.NewWriter(w)}
+	if _, err := bw.w.WriteString(binaryStatsMagic); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := bw.w.WriteByte(binaryStatsVersion); err != nil {
+		return nil, errors.Trace(err)
+	}
+	bw.enc.putString(dbName)
+	bw.enc.putString(tableName)
+	return bw, bw.flushFrame(binaryFrameHeader)
+}
+
+// writeTable writes the stats of a physical table. The partition name is empty for the non-partitioned table.
+func (bw *binaryStatsWriter) writeTable(partitionName string, jsonTbl *util.JSONTable) error {
+	bw.enc.putString(partitionName)
+	if err := bw.enc.putJSONTable(jsonTbl); err != nil {
+		return err
+	}
+	return bw.flushFrame(binaryFrameTable)
+}
+
+// close writes the end frame and flushes the buffered data.
+func (bw *binaryStatsWriter) close() error {
+	if err := bw.flushFrame(binaryFrameEnd); err != nil {
+		return err
+	}
+	return errors.Trace(bw.w.Flush())
+}
+
+func (bw *binaryStatsWriter) flushFrame(kind byte) error {
+	if err := bw.w.WriteByte(kind); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := bw.w.Write(binary.AppendUvarint(nil, uint64(len(bw.enc.buf)))); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := bw.w.Write(bw.enc.buf)
+	bw.enc.buf = bw.enc.buf[:0]
+	return errors.Trace(err)
+}
+
+// binaryStatsReader reads the stats written by binaryStatsWriter.
+type binaryStatsReader struct {
+	r         *bufio.Reader
+	dbName    string
+	tableName string
+	buf       []byte
+}
+
+func newBinaryStatsReader(r io.Reader) (*binaryStatsReader, error) {
+	br := &binaryStatsReader{r: bufio.NewReader(r)}
+	magic := make([]byte, len(binaryStatsMagic)+1)
+	if _, err := io.ReadFull(br.r, magic); err != nil {
+		return nil, errors.Annotate(err, "invalid binary stats")
+	}
+	if !bytes.Equal(magic[:len(binaryStatsMagic)], []byte(binaryStatsMagic)) {
+		return nil, errors.New("invalid binary stats: magic mismatch")
+	}
+	if ver := magic[len(binaryStatsMagic)]; ver != binaryStatsVersion {
+		return nil, errors.Errorf("unsupported binary stats version %d", ver)
+	}
+	dec, err := br.readFrame(binaryFrameHeader)
+	if err != nil {
+		return nil, err
+	}
+	br.dbName = dec.getString()
+	br.tableName = dec.getString()
+	return br, dec.finish()
+}
+
+// next returns the stats of the next physical table. It returns io.EOF after the last one.
+func (br *binaryStatsReader) next() (partitionName string, jsonTbl *util.JSONTable, err error) {
+	dec, err := br.readFrame(binaryFrameTable, binaryFrameEnd)
+	if err != nil {
+		return "", nil, err
+	}
+	if dec == nil {
+		return "", nil, io.EOF
+	}
+	partitionName = dec.getString()
+	jsonTbl = dec.getJSONTable()
+	if err := dec.finish(); err != nil {
+		return "", nil, err
+	}
+	jsonTbl.DatabaseName = br.dbName
+	jsonTbl.TableName = br.tableName
+	return partitionName, jsonTbl, nil
+}
+
+// readFrame reads the next frame, whose kind must be one of the expected kinds.
+// It returns a nil decoder for the end frame.
+func (br *binaryStatsReader) readFrame(expected ...byte) (*binaryDecoder, error) {
+	kind, err := br.r.ReadByte()
+	if err != nil {
+		// The end frame is missing, so the input is truncated.
+		return nil, errors.Annotate(noEOF(err), "invalid binary stats")
+	}
+	if !slices.Contains(expected, kind) {
+		return nil, errors.Errorf("invalid binary stats: unexpected frame kind %d", kind)
+	}
+	size, err := binary.ReadUvarint(br.r)
+	if err != nil {
+		return nil, errors.Annotate(noEOF(err), "invalid binary stats")
+	}
+	if size > maxBinaryFrameSize {
+		return nil, errors.Errorf("invalid binary stats: frame size %d is too large", size)
+	}
+	br.buf = slices.Grow(br.buf[:0], int(size))[:size]
+	if _, err := io.ReadFull(br.r, br.buf); err != nil {
+		return nil, errors.Annotate(noEOF(err), "invalid binary stats")
+	}
+	if kind == binaryFrameEnd {
+		return nil, nil
+	}
+	return &binaryDecoder{buf: br.buf}, nil
+}
+
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+type protoMarshaler interface {
+	Marshal() ([]byte, error)
+}
+
+type binaryEncoder struct {
+	buf []byte
+}
+
+func (e *binaryEncoder) putUvarint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *binaryEncoder) putVarint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+func (e *binaryEncoder) putBool(v bool) {
+	if v {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *binaryEncoder) putFloat64(v float64) {
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(v))
+}
+
+func (e *binaryEncoder) putBytes(v []byte) {
+	e.putUvarint(uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *binaryEncoder) putString(v string) {
+	e.putUvarint(uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *binaryEncoder) putOptionalString(v *string) {
+	e.putBool(v != nil)
+	if v != nil {
+		e.putString(*v)
+	}
+}
+
+// putProto encodes an optional protobuf message. isNil must be true if the message is a nil pointer.
+func (e *binaryEncoder) putProto(m protoMarshaler, isNil bool) error {
+	e.putBool(!isNil)
+	if isNil {
+		return nil
+	}
+	data, err := m.Marshal()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	e.putBytes(data)
+	return nil
+}
+
+func (e *binaryEncoder) putJSONTable(jsonTbl *util.JSONTable) error {
+	e.putVarint(jsonTbl.Count)
+	e.putVarint(jsonTbl.ModifyCount)
+	e.putUvarint(jsonTbl.Version)
+	e.putBool(jsonTbl.IsHistoricalStats)
+	for _, cols := range []map[string]*util.JSONColumn{jsonTbl.Columns, jsonTbl.Indices} {
+		names := make([]string, 0, len(cols))
+		for name, col := range cols {
+			if col != nil {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		e.putUvarint(uint64(len(names)))
+		for _, name := range names {
+			e.putString(name)
+			if err := e.putJSONColumn(cols[name]); err != nil {
+				return err
+			}
+		}
+	}
+	var extStats, initedExtStats []*util.JSONExtendedStats
+	for _, ext := range jsonTbl.ExtStats {
+		if ext.Inited {
+			initedExtStats = append(initedExtStats, ext)
+		} else {
+			extStats = append(extStats, ext)
+		}
+	}
+	e.putUvarint(uint64(len(extStats)))
+	for _, ext := range extStats {
+		e.putString(ext.StatsName)
+		e.putString(ext.StringVals)
+		e.putUvarint(uint64(len(ext.ColIDs)))
+		for _, id := range ext.ColIDs {
+			e.putVarint(id)
+		}
+		e.putFloat64(ext.ScalarVals)
+		e.putUvarint(uint64(ext.Tp))
+	}
+	e.putUvarint(uint64(len(jsonTbl.PredicateColumns)))
+	for _, col := range jsonTbl.PredicateColumns {
+		e.putVarint(col.ID)
+		e.putOptionalString(col.LastUsedAt)
+		e.putOptionalString(col.LastAnalyzedAt)
+	}
+	// The analyze options and the definitions of the extended stats are appended at the end of the frame,
+	// so the frames written before they are dumped can still be decoded.
+	e.putBool(jsonTbl.AnalyzeOptions != nil)
+	if opts := jsonTbl.AnalyzeOptions; opts != nil {
+		e.putString(opts.ColumnChoice)
+		e.putUvarint(uint64(len(opts.ColumnIDs)))
+		for _, id := range opts.ColumnIDs {
+			e.putVarint(id)
+		}
+		e.putVarint(opts.SampleNum)
+		e.putFloat64(opts.SampleRate)
+		e.putVarint(opts.Buckets)
+		e.putVarint(opts.TopN)
+	}
+	e.putUvarint(uint64(len(initedExtStats)))
+	for _, ext := range initedExtStats {
+		e.putString(ext.StatsName)
+		e.putUvarint(uint64(len(ext.ColIDs)))
+		for _, id := range ext.ColIDs {
+			e.putVarint(id)
+		}
+		e.putUvarint(uint64(ext.Tp))
+	}
+	return nil
+}
+
+func (e *binaryEncoder) putJSONColumn(col *util.JSONColumn) error {
+	if err := e.putProto(col.Histogram, col.Histogram == nil); err != nil {
+		return err
+	}
+	if err := e.putProto(col.CMSketch, col.CMSketch == nil); err != nil {
+		return err
+	}
+	if err := e.putProto(col.FMSketch, col.FMSketch == nil); err != nil {
+		return err
+	}
+	e.putBool(col.StatsVer != nil)
+	if col.StatsVer != nil {
+		e.putVarint(*col.StatsVer)
+	}
+	e.putVarint(col.NullCount)
+	e.putVarint(col.TotColSize)
+	e.putUvarint(col.LastUpdateVersion)
+	e.putFloat64(col.Correlation)
+	return nil
+}
+
+// binaryDecoder decodes a frame. The first error is kept and all the following reads return zero values,
+// so the callers only need to check the error once by finish.
+type binaryDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *binaryDecoder) setErr(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *binaryDecoder) getUvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.setErr(errors.New("invalid binary stats: bad uvarint"))
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *binaryDecoder) getVarint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.setErr(errors.New("invalid binary stats: bad varint"))
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+// getLen reads a length and checks that there are at least minSize*length bytes left,
+// so that a corrupted length can't make the decoder allocate a huge amount of memory.
+func (d *binaryDecoder) getLen(minSize int) int {
+	l := d.getUvarint()
+	if d.err == nil && l > uint64(len(d.buf)/minSize) {
+		d.setErr(errors.New("invalid binary stats: length out of range"))
+		return 0
+	}
+	return int(l)
+}
+
+func (d *binaryDecoder) getBool() bool {
+	if d.err != nil {
+		return false
+	}
+	if len(d.buf) == 0 {
+		d.setErr(io.ErrUnexpectedEOF)
+		return false
+	}
+	v := d.buf[0]
+	d.buf = d.buf[1:]
+	return v != 0
+}
+
+func (d *binaryDecoder) getFloat64() float64 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) < 8 {
+		d.setErr(io.ErrUnexpectedEOF)
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.buf))
+	d.buf = d.buf[8:]
+	return v
+}
+
+// getBytes returns the next bytes. The result refers to the frame buffer, so it must be copied before being retained.
+func (d *binaryDecoder) getBytes() []byte {
+	l := d.getLen(1)
+	if d.err != nil {
+		return nil
+	}
+	v := d.buf[:l]
+	d.buf = d.buf[l:]
+	return v
+}
+
+func (d *binaryDecoder) getString() string {
+	return string(d.getBytes())
+}
+
+func (d *binaryDecoder) getOptionalString() *string {
+	if !d.getBool() {
+		return nil
+	}
+	v := d.getString()
+	return &v
+}
+
+func (d *binaryDecoder) getProto(m interface{ Unmarshal([]byte) error }) bool {
+	if !d.getBool() {
+		return false
+	}
+	data := d.getBytes()
+	if d.err != nil {
+		return false
+	}
+	// Unmarshal copies the bytes, so it's safe to reuse the frame buffer.
+	if err := m.Unmarshal(data); err != nil {
+		d.setErr(errors.Trace(err))
+		return false
+	}
+	return true
+}
+
+func (d *binaryDecoder) getJSONTable() *util.JSONTable {
+	jsonTbl := &util.JSONTable{
+		Count:             d.getVarint(),
+		ModifyCount:       d.getVarint(),
+		Version:           d.getUvarint(),
+		IsHistoricalStats: d.getBool(),
+	}
+	jsonTbl.Columns = d.getJSONColumns()
+	jsonTbl.Indices = d.getJSONColumns()
+	if n := d.getLen(1); n > 0 {
+		jsonTbl.ExtStats = make([]*util.JSONExtendedStats, 0, n)
+		for range n {
+			ext := &util.JSONExtendedStats{
+				StatsName:  d.getString(),
+				StringVals: d.getString(),
+			}
+			ext.ColIDs = make([]int64, d.getLen(1))
+			for i := range ext.ColIDs {
+				ext.ColIDs[i] = d.getVarint()
+			}
+			ext.ScalarVals = d.getFloat64()
+			ext.Tp = uint8(d.getUvarint())
+			jsonTbl.ExtStats = append(jsonTbl.ExtStats, ext)
+		}
+	}
+	if n := d.getLen(1); n > 0 {
+		jsonTbl.PredicateColumns = make([]*util.JSONPredicateColumn, 0, n)
+		for range n {
+			col := &util.JSONPredicateColumn{ID: d.getVarint()}
+			col.LastUsedAt = d.getOptionalString()
+			col.LastAnalyzedAt = d.getOptionalString()
+			jsonTbl.PredicateColumns = append(jsonTbl.PredicateColumns, col)
+		}
+	}
+	if len(d.buf) == 0 {
+		// The frame is written before the analyze options are dumped.
+		return jsonTbl
+	}
+	if d.getBool() {
+		opts := &util.JSONAnalyzeOptions{ColumnChoice: d.getString()}
+		if n := d.getLen(1); n > 0 {
+			opts.ColumnIDs = make([]int64, n)
+			for i := range opts.ColumnIDs {
+				opts.ColumnIDs[i] = d.getVarint()
+			}
+		}
+		opts.SampleNum = d.getVarint()
+		opts.SampleRate = d.getFloat64()
+		opts.Buckets = d.getVarint()
+		opts.TopN = d.getVarint()
+		jsonTbl.AnalyzeOptions = opts
+	}
+	for range d.getLen(1) {
+		ext := &util.JSONExtendedStats{StatsName: d.getString(), Inited: true}
+		ext.ColIDs = make([]int64, d.getLen(1))
+		for i := range ext.ColIDs {
+			ext.ColIDs[i] = d.getVarint()
+		}
+		ext.Tp = uint8(d.getUvarint())
+		jsonTbl.ExtStats = append(jsonTbl.ExtStats, ext)
+	}
+	return jsonTbl
+}
+
+func (d *binaryDecoder) getJSONColumns() map[string]*util.JSONColumn {
+	n := d.getLen(1)
+	cols := make(map[string]*util.JSONColumn, n)
+	for range n {
+		name := d.getString()
+		col := &util.JSONColumn{}
+		if hist := new(tipb.Histogram); d.getProto(hist) {
+			col.Histogram = hist
+		}
+		if cms := new(tipb.CMSketch); d.getProto(cms) {
+			col.CMSketch = cms
+		}
+		if fms := new(tipb.FMSketch); d.getProto(fms) {
+			col.FMSketch = fms
+		}
+		if d.getBool() {
+			statsVer := d.getVarint()
+			col.StatsVer = &statsVer
+		}
+		col.NullCount = d.getVarint()
+		col.TotColSize = d.getVarint()
+		col.LastUpdateVersion = d.getUvarint()
+		col.Correlation = d.getFloat64()
+		if d.err != nil {
+			return nil
+		}
+		cols[name] = col
+	}
+	return cols
+}
+
+// finish returns the first error during decoding, and checks that the whole frame is consumed.
+func (d *binaryDecoder) finish() error {
+	if d.err != nil {
+		return errors.Annotate(d.err, "invalid binary stats")
+	}
+	if len(d.buf) != 0 {
+		return errors.New("invalid binary stats: unexpected trailing bytes")
+	}
+	return nil
+}
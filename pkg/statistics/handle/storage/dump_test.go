@@ -15,6 +15,8 @@
 package storage_test
 
 import (
+	"bufio"
+	"bytes"
 	"cmp"
 	"context"
 	"encoding/json"
@@ -241,6 +243,68 @@ func TestLoadPartitionStats(t *testing.T) {
 	requireTableEqual(t, originGlobalStats, dom.StatsHandle().GetTableStats(tableInfo))
 }
 
+func TestLoadPartitionStatsFromBinary(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(20), key(a), key(a, b)) partition by hash(a) partitions 8")
+	vals := make([]string, 0, 5000)
+	for i := 0; i < 5000; i++ {
+		vals = append(vals, fmt.Sprintf("(%v, 'v%v')", i, i%100))
+	}
+	tk.MustExec("insert into t values " + strings.Join(vals, ","))
+	tk.MustExec("analyze table t")
+
+	h := dom.StatsHandle()
+	table, err := dom.InfoSchema().TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := table.Meta()
+	var buf bytes.Buffer
+	require.NoError(t, h.DumpStatsToBinary(&buf, "test", tableInfo, 0, true))
+	jsonTbl, err := h.DumpStatsToJSON("test", tableInfo, nil, true)
+	require.NoError(t, err)
+	jsonData, err := json.Marshal(jsonTbl)
+	require.NoError(t, err)
+	require.Less(t, buf.Len(), len(jsonData))
+	require.True(t, storage.IsBinaryStats(bufio.NewReader(bytes.NewReader(buf.Bytes()))))
+	require.False(t, storage.IsBinaryStats(bufio.NewReader(bytes.NewReader(jsonData))))
+
+	pi := tableInfo.GetPartitionInfo()
+	originPartStats := make([]*statistics.Table, 0, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		originPartStats = append(originPartStats, h.GetPartitionStats(tableInfo, def.ID))
+	}
+	originGlobalStats := h.GetTableStats(tableInfo)
+
+	// remove all statistics
+	tk.MustExec("delete from mysql.stats_meta")
+	tk.MustExec("delete from mysql.stats_histograms")
+	tk.MustExec("delete from mysql.stats_buckets")
+	h.Clear()
+	clearedStats := getStatsJSON(t, dom, "test", "t")
+	require.Equal(t, 0, len(clearedStats.Partitions))
+
+	// The truncated data can't be loaded.
+	data := buf.Bytes()
+	err = h.LoadStatsFromBinary(context.Background(), dom.InfoSchema(), bytes.NewReader(data[:len(data)-1]), 0)
+	require.ErrorContains(t, err, "invalid binary stats")
+	err = h.LoadStatsFromBinary(context.Background(), dom.InfoSchema(), bytes.NewReader(jsonData), 0)
+	require.ErrorContains(t, err, "invalid binary stats")
+
+	// load stats back
+	require.NoError(t, h.LoadStatsFromBinary(context.Background(), dom.InfoSchema(), bytes.NewReader(data), 0))
+
+	// compare
+	for i, def := range pi.Definitions {
+		newPartStats := h.GetPartitionStats(tableInfo, def.ID)
+		requireTableEqual(t, originPartStats[i], newPartStats)
+	}
+	requireTableEqual(t, originGlobalStats, h.GetTableStats(tableInfo))
+}
+
 func TestLoadPredicateColumns(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	tk := testkit.NewTestKit(t, store)
@@ -283,6 +347,54 @@ func TestLoadPredicateColumns(t *testing.T) {
 	require.NotEqual(t, "<nil>", rows[1][3])
 }
 
+func TestLoadAnalyzeOptionsAndExtendedStats(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("create table t (a int, b int, c int)")
+	tk.MustExec("insert into t values (1, 5, 1), (2, 4, 2), (3, 3, 3), (4, 2, 4), (5, 1, 5)")
+	tk.MustExec("alter table t add stats_extended s1 correlation(a, b)")
+	tk.MustExec("analyze table t columns a, b with 2 topn, 3 buckets")
+	// s2 is not analyzed, so only its definition is dumped.
+	tk.MustExec("alter table t add stats_extended s2 correlation(b, c)")
+
+	is := dom.InfoSchema()
+	h := dom.StatsHandle()
+	table, err := is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := table.Meta()
+	jsonTbl, err := h.DumpStatsToJSON("test", tableInfo, nil, true)
+	require.NoError(t, err)
+	require.NotNil(t, jsonTbl.AnalyzeOptions)
+	require.Equal(t, "LIST", jsonTbl.AnalyzeOptions.ColumnChoice)
+	require.Len(t, jsonTbl.ExtStats, 2)
+	var buf bytes.Buffer
+	require.NoError(t, h.DumpStatsToBinary(&buf, "test", tableInfo, 0, true))
+
+	optsSQL := "select sample_num, sample_rate, buckets, topn, column_choice, column_ids from mysql.analyze_options"
+	extStatsSQL := "select name, type, column_ids, status from mysql.stats_extended order by name"
+	opts := tk.MustQuery(optsSQL).Rows()
+	require.Len(t, opts, 1)
+	extStats := tk.MustQuery(extStatsSQL).Rows()
+	require.Len(t, extStats, 2)
+
+	for _, load := range []func() error{
+		func() error { return h.LoadStatsFromJSON(context.Background(), is, jsonTbl, 0) },
+		func() error {
+			return h.LoadStatsFromBinary(context.Background(), is, bytes.NewReader(buf.Bytes()), 0)
+		},
+	} {
+		tk.MustExec("delete from mysql.analyze_options")
+		tk.MustExec("delete from mysql.stats_extended")
+		h.Clear()
+		require.NoError(t, load())
+		tk.MustQuery(optsSQL).Check(opts)
+		tk.MustQuery(extStatsSQL).Check(extStats)
+	}
+}
+
 func TestLoadPartitionStatsErrPanic(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	tk := testkit.NewTestKit(t, store)
@@ -734,3 +846,56 @@ PARTITION BY RANGE ( a ) (
 	})
 	require.Equal(t, statsCnt, 1)
 }
+
+func TestMergeStatsFromJSON(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, index ia(a))")
+	tk.MustExec("create table t2(a int, b int, index ia(a))")
+	for i := 1; i <= 10; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i))
+		tk.MustExec(fmt.Sprintf("insert into t2 values (%d, %d)", i+10, i+10))
+	}
+	tk.MustExec("analyze table t with 0 topn, 4 buckets")
+	tk.MustExec("analyze table t2 with 0 topn, 4 buckets")
+	h := dom.StatsHandle()
+	is := dom.InfoSchema()
+	require.NoError(t, h.Update(context.Background(), is))
+	tbl, err := is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tbl2, err := is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t2"))
+	require.NoError(t, err)
+	baseVersion := h.GetTableStats(tbl.Meta()).LastAnalyzeVersion
+
+	// The stats of t2 are regarded as the partial stats of t built on the rows not in t.
+	partial, err := h.DumpStatsToJSON("test", tbl2.Meta(), nil, true)
+	require.NoError(t, err)
+	partial.TableName = "t"
+	partial.Version = baseVersion
+	require.NoError(t, h.MergeStatsFromJSON(context.Background(), is, partial))
+	statsTbl, err := h.TableStatsFromStorage(tbl.Meta(), tbl.Meta().ID, true, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(20), statsTbl.RealtimeCount)
+	colA := statsTbl.GetCol(tbl.Meta().Columns[0].ID)
+	require.Equal(t, int64(1), colA.Histogram.GetLower(0).GetInt64())
+	require.Equal(t, int64(20), colA.Histogram.GetUpper(colA.Histogram.Len()-1).GetInt64())
+	require.Equal(t, float64(20), colA.Histogram.NotNullCount())
+	idxA := statsTbl.GetIdx(tbl.Meta().Indices[0].ID)
+	require.Equal(t, float64(20), idxA.Histogram.NotNullCount())
+
+	// The partial stats based on the stale stats can't be merged.
+	err = h.MergeStatsFromJSON(context.Background(), is, partial)
+	require.ErrorContains(t, err, "the table has been analyzed")
+
+	// The stats of version 1 can't be merged.
+	tk.MustExec("set @@tidb_analyze_version = 1")
+	tk.MustExec("analyze table t2")
+	partial, err = h.DumpStatsToJSON("test", tbl2.Meta(), nil, true)
+	require.NoError(t, err)
+	partial.TableName = "t"
+	partial.Version = 0
+	err = h.MergeStatsFromJSON(context.Background(), is, partial)
+	require.ErrorContains(t, err, "only the stats of version 2 can be merged")
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/terror"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
@@ -73,6 +74,42 @@ func (gc *statsGCImpl) DeleteTableStatsFromKV(statsIDs []int64) (err error) {
 	}, util.FlagWrapTxn)
 }
 
+// DeleteColumnStatsFromKV deletes the statistics of the columns from kv and removes them from the stats cache.
+// A statsID refers to statistic of a table or a partition.
+func (gc *statsGCImpl) DeleteColumnStatsFromKV(statsIDs []int64, colIDs []int64) (err error) {
+	err = util.CallWithSCtx(gc.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		for _, statsID := range statsIDs {
+			for _, colID := range colIDs {
+				if err := deleteHistStatsFromKV(sctx, statsID, colID, 0); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+		return nil
+	}, util.FlagWrapTxn)
+	if err != nil {
+		return err
+	}
+	// The stats are reloaded on top of the cached ones, so the deleted columns must be removed from the cache here.
+	tables := make([]*statistics.Table, 0, len(statsIDs))
+	for _, statsID := range statsIDs {
+		tbl, ok := gc.statsHandle.Get(statsID)
+		if !ok {
+			continue
+		}
+		tbl = tbl.Copy()
+		for _, colID := range colIDs {
+			tbl.DelCol(colID)
+			if tbl.ColAndIdxExistenceMap != nil {
+				tbl.ColAndIdxExistenceMap.InsertCol(colID, false)
+			}
+		}
+		tables = append(tables, tbl)
+	}
+	gc.statsHandle.UpdateStatsCache(tables, nil)
+	return nil
+}
+
 // GCStats will garbage collect the useless stats' info.
 // For dropped tables, we will first update their version
 // so that other tidb could know that table is deleted.
@@ -83,9 +120,9 @@ func GCStats(
 	ddlLease time.Duration,
 ) (err error) {
 	// To make sure that all the deleted tables' schema and stats info have been acknowledged to all tidb,
-	// we only garbage collect version before 10 lease.
+	// we only garbage collect version before 10 lease, or tidb_stats_gc_retention if it's longer.
 	lease := max(statsHandle.Lease(), ddlLease)
-	offset := util.DurationToTS(10 * lease)
+	offset := util.DurationToTS(max(10*lease, variable.StatsGCRetention.Load()))
 	now := oracle.GoTimeToTS(time.Now())
 	if now < offset {
 		return nil
@@ -109,15 +146,11 @@ func GCStats(
 		return errors.Trace(err)
 	}
 	for _, row := range rows {
+		// The historical stats of the dropped tables are kept until they are outdated, so the stats can be
+		// restored if the tables are recovered.
 		if err := gcTableStats(sctx, statsHandle, is, row.GetInt64(0)); err != nil {
 			return errors.Trace(err)
 		}
-		_, existed := is.TableByID(context.Background(), row.GetInt64(0))
-		if !existed {
-			if err := gcHistoryStatsFromKV(sctx, row.GetInt64(0)); err != nil {
-				return errors.Trace(err)
-			}
-		}
 	}
 
 	if err := ClearOutdatedHistoryStats(sctx); err != nil {
@@ -162,6 +195,24 @@ func DeleteTableStatsFromKV(sctx sessionctx.Context, statsIDs []int64) (err erro
 		if _, err = util.Exec(sctx, "delete from mysql.analyze_options where table_id = %?", statsID); err != nil {
 			return err
 		}
+		if _, err = util.Exec(sctx, "delete from mysql.stats_quick_refresh where table_id = %?", statsID); err != nil {
+			return err
+		}
+		if _, err = util.Exec(sctx, "delete from mysql.stats_injected where table_id = %?", statsID); err != nil {
+			return err
+		}
+		if _, err = util.Exec(sctx, "delete from mysql.analyze_checkpoints where table_id = %? or partition_id = %?", statsID, statsID); err != nil {
+			return err
+		}
+		if _, err = util.Exec(sctx, "delete from mysql.auto_analyze_config where table_id = %?", statsID); err != nil {
+			return err
+		}
+		if _, err = util.Exec(sctx, "delete from mysql.analyze_quarantine where table_id = %?", statsID); err != nil {
+			return err
+		}
+		if _, err = util.Exec(sctx, "delete from mysql.stats_global_ndv_sketch where table_id = %?", statsID); err != nil {
+			return err
+		}
 		if _, err = util.Exec(sctx, lockstats.DeleteLockSQL, statsID); err != nil {
 			return err
 		}
@@ -211,18 +262,6 @@ func ClearOutdatedHistoryStats(sctx sessionctx.Context) error {
 	return nil
 }
 
-// gcHistoryStatsFromKV delete history stats from kv.
-func gcHistoryStatsFromKV(sctx sessionctx.Context, physicalID int64) (err error) {
-	sql := "delete from mysql.stats_history where table_id = %?"
-	_, err = util.Exec(sctx, sql, physicalID)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	sql = "delete from mysql.stats_meta_history where table_id = %?"
-	_, err = util.Exec(sctx, sql, physicalID)
-	return err
-}
-
 // deleteHistStatsFromKV deletes all records about a column or an index and updates version.
 func deleteHistStatsFromKV(sctx sessionctx.Context, physicalID int64, histID int64, isIndex int) (err error) {
 	startTS, err := util.GetStartTS(sctx)
@@ -285,6 +324,9 @@ func gcTableStats(sctx sessionctx.Context,
 	tbl, ok := statsHandler.TableInfoByID(is, physicalID)
 	if !ok {
 		logutil.BgLogger().Info("remove stats in GC due to dropped table", zap.Int64("table_id", physicalID))
+		if err := deleteDroppedTableStatsInBatches(sctx, physicalID); err != nil {
+			return errors.Trace(err)
+		}
 		return util.WrapTxn(sctx, func(sctx sessionctx.Context) error {
 			return errors.Trace(DeleteTableStatsFromKV(sctx, []int64{physicalID}))
 		})
@@ -292,23 +334,7 @@ func gcTableStats(sctx sessionctx.Context,
 	tblInfo := tbl.Meta()
 	for _, row := range rows {
 		isIndex, histID := row.GetInt64(0), row.GetInt64(1)
-		find := false
-		if isIndex == 1 {
-			for _, idx := range tblInfo.Indices {
-				if idx.ID == histID {
-					find = true
-					break
-				}
-			}
-		} else {
-			for _, col := range tblInfo.Columns {
-				if col.ID == histID {
-					find = true
-					break
-				}
-			}
-		}
-		if !find {
+		if !isHistInTable(tblInfo, isIndex, histID) {
 			err := util.WrapTxn(sctx, func(sctx sessionctx.Context) error {
 				return errors.Trace(deleteHistStatsFromKV(sctx, physicalID, histID, int(isIndex)))
 			})
@@ -356,6 +382,71 @@ func gcTableStats(sctx sessionctx.Context,
 	return nil
 }
 
+// RemoveDroppedColumnAndIndexStats removes the stats of the columns and indexes which don't exist in the table
+// anymore in the same transaction, instead of leaving them to the stats GC. It returns 0 if nothing is removed.
+func RemoveDroppedColumnAndIndexStats(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	tblInfo *model.TableInfo,
+	physicalID int64,
+) (uint64, error) {
+	rows, _, err := util.ExecRowsWithCtx(ctx, sctx, "select is_index, hist_id from mysql.stats_histograms where table_id = %?", physicalID)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	removed := false
+	for _, row := range rows {
+		isIndex, histID := row.GetInt64(0), row.GetInt64(1)
+		if isHistInTable(tblInfo, isIndex, histID) {
+			continue
+		}
+		if err := deleteHistStatsFromKV(sctx, physicalID, histID, int(isIndex)); err != nil {
+			return 0, errors.Trace(err)
+		}
+		removed = true
+	}
+	if !removed {
+		return 0, nil
+	}
+	return util.GetStartTS(sctx)
+}
+
+func isHistInTable(tblInfo *model.TableInfo, isIndex, histID int64) bool {
+	if isIndex == 1 {
+		for _, idx := range tblInfo.Indices {
+			if idx.ID == histID {
+				return true
+			}
+		}
+		return false
+	}
+	for _, col := range tblInfo.Columns {
+		if col.ID == histID {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteDroppedTableStatsInBatches deletes the histograms, buckets, topn and fm sketches of a dropped table in
+// batches, each of which is in its own transaction. A table with many columns and partitions may have millions of
+// these rows, which can't be deleted in one transaction. The remaining stats are deleted by DeleteTableStatsFromKV.
+func deleteDroppedTableStatsInBatches(sctx sessionctx.Context, physicalID int64) error {
+	batchSize := variable.StatsGCBatchSize.Load()
+	// Delete the histograms at last, so the GC can find the table again if it fails in the middle.
+	for _, table := range []string{"stats_buckets", "stats_top_n", "stats_fm_sketch", "stats_histograms"} {
+		for {
+			if _, err := util.Exec(sctx, "delete low_priority from mysql.%n where table_id = %? limit %?", table, physicalID, batchSize); err != nil {
+				return err
+			}
+			if int64(sctx.GetSessionVars().StmtCtx.AffectedRows()) < batchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
 const gcLastTSVarName = "tidb_stats_gc_last_ts"
 
 // getLastGCTimestamp loads the last gc time from mysql.tidb.
@@ -57,6 +57,38 @@ func TestGCStats(t *testing.T) {
 	testKit.MustQuery("select count(*) from mysql.stats_meta").Check(testkit.Rows("0"))
 }
 
+func TestGCStatsWithRetentionAndBatches(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	testKit.MustExec("set @@tidb_analyze_version = 1")
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t(a int, b int, index idx(a, b), index idx_a(a))")
+	testKit.MustExec("insert into t values (1,1),(2,2),(3,3)")
+	testKit.MustExec("analyze table t")
+	testKit.MustQuery("select count(*) from mysql.stats_buckets").Check(testkit.Rows("12"))
+
+	testKit.MustExec("set @@global.tidb_stats_gc_retention = '1h'")
+	defer testKit.MustExec("set @@global.tidb_stats_gc_retention = default")
+	testKit.MustExec("set @@global.tidb_stats_gc_batch_size = 5")
+	defer testKit.MustExec("set @@global.tidb_stats_gc_batch_size = default")
+	testKit.MustExec("drop table t")
+	h := dom.StatsHandle()
+	ddlLease := time.Duration(0)
+	// The stats of the dropped table are kept within the retention.
+	require.Nil(t, h.GCStats(dom.InfoSchema(), ddlLease))
+	testKit.MustQuery("select count(*) from mysql.stats_histograms").Check(testkit.Rows("4"))
+	testKit.MustQuery("select count(*) from mysql.stats_buckets").Check(testkit.Rows("12"))
+
+	testKit.MustExec("set @@global.tidb_stats_gc_retention = '0s'")
+	require.Nil(t, h.GCStats(dom.InfoSchema(), ddlLease))
+	testKit.MustQuery("select count(*) from mysql.stats_meta").Check(testkit.Rows("1"))
+	testKit.MustQuery("select count(*) from mysql.stats_histograms").Check(testkit.Rows("0"))
+	testKit.MustQuery("select count(*) from mysql.stats_buckets").Check(testkit.Rows("0"))
+	testKit.MustQuery("select count(*) from mysql.stats_top_n").Check(testkit.Rows("0"))
+	require.Nil(t, h.GCStats(dom.InfoSchema(), ddlLease))
+	testKit.MustQuery("select count(*) from mysql.stats_meta").Check(testkit.Rows("0"))
+}
+
 func TestGCPartition(t *testing.T) {
 	store, dom := testkit.CreateMockStoreAndDomain(t)
 	testKit := testkit.NewTestKit(t, store)
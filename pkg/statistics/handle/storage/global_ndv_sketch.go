@@ -0,0 +1,145 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/util"
+)
+
+// The global NDV sketch of a column or an index of a partitioned table is the HLL sketch merged from the ones of all
+// its partitions, which is kept in mysql.stats_global_ndv_sketch. So the global NDV is estimated from a single sketch
+// when the global stats are merged, instead of the sketches of all the partitions.
+//
+// The HLL sketch can't remove the hash values of a partition once they are merged, so the global sketch is maintained
+// as follows:
+//  1. It's built by merging the sketches of all the partitions when the global stats are merged and it doesn't exist.
+//  2. When a partition is analyzed, its new sketch is merged into the global one if the new sketch covers the old one,
+//     which is the common case that the rows are only appended to the partition. Otherwise, the global sketch is
+//     removed, and it's built again by the next merge.
+//  3. It's removed when the partitions are dropped, truncated or exchanged, or a partition is analyzed without the
+//     HLL sketch, e.g. tidb_enable_hll_ndv_sketch is off.
+//
+// The row of the partition is always locked before the global sketch, so the concurrent analysis of the partitions
+// and the building of the global sketch are serialized without deadlocks.
+
+// globalNDVSketchForUpdate reads the global NDV sketch and locks it.
+func globalNDVSketchForUpdate(sctx sessionctx.Context, tableID int64, isIndex int, histID int64) (*statistics.HLLSketch, error) {
+	rows, _, err := util.ExecRows(sctx, "select value from mysql.stats_global_ndv_sketch where table_id = %? and is_index = %? and hist_id = %? for update",
+		tableID, isIndex, histID)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return statistics.DecodeHLLSketch(rows[0].GetBytes(0))
+}
+
+// partitionNDVSketchForUpdate reads the HLL sketch of the partition and locks its row.
+func partitionNDVSketchForUpdate(sctx sessionctx.Context, partitionID int64, isIndex int, histID int64) (*statistics.HLLSketch, error) {
+	rows, _, err := util.ExecRows(sctx, "select ndv_sketch from mysql.stats_histograms where table_id = %? and is_index = %? and hist_id = %? for update",
+		partitionID, isIndex, histID)
+	if err != nil || len(rows) == 0 || rows[0].IsNull(0) {
+		return nil, err
+	}
+	return statistics.DecodeHLLSketch(rows[0].GetBytes(0))
+}
+
+func saveGlobalNDVSketch(sctx sessionctx.Context, tableID int64, isIndex int, histID int64, sketch *statistics.HLLSketch, version uint64) error {
+	_, err := util.Exec(sctx, "replace into mysql.stats_global_ndv_sketch (table_id, is_index, hist_id, value, version) values (%?, %?, %?, %?, %?)",
+		tableID, isIndex, histID, statistics.EncodeHLLSketch(sketch), version)
+	return err
+}
+
+// updateGlobalNDVSketch merges the new HLL sketch of the partition into the global one if it exists, see the comments
+// at the top of the file. The old sketch of the partition must be read by partitionNDVSketchForUpdate before.
+func updateGlobalNDVSketch(sctx sessionctx.Context, tableID int64, isIndex int, histID int64, oldSketch, newSketch *statistics.HLLSketch, version uint64) error {
+	globalSketch, err := globalNDVSketchForUpdate(sctx, tableID, isIndex, histID)
+	if err != nil || globalSketch == nil {
+		return err
+	}
+	if newSketch == nil || !newSketch.Covers(oldSketch) {
+		_, err = util.Exec(sctx, "delete from mysql.stats_global_ndv_sketch where table_id = %? and is_index = %? and hist_id = %?",
+			tableID, isIndex, histID)
+		return err
+	}
+	globalSketch.MergeHLLSketch(newSketch)
+	return saveGlobalNDVSketch(sctx, tableID, isIndex, histID, globalSketch, version)
+}
+
+// DeleteGlobalNDVSketches removes the global NDV sketches of the partitioned table, e.g. when its partitions are
+// dropped, since the hash values of the dropped partitions can't be removed from the sketches.
+func DeleteGlobalNDVSketches(sctx sessionctx.Context, tableID int64) error {
+	_, err := util.Exec(sctx, "delete from mysql.stats_global_ndv_sketch where table_id = %?", tableID)
+	return err
+}
+
+// GlobalNDVSketch returns the global NDV sketch of a column or an index of the partitioned table. If it doesn't
+// exist, it's merged from the HLL sketches of the partitions and saved. If the partitions are not all the ones of the
+// table, e.g. the stats of some partitions are missing, the sketches of the partitions are merged without reading or
+// saving the global one. It returns nil if any partition doesn't have the HLL sketch. It must be called in a
+// transaction.
+func GlobalNDVSketch(sctx sessionctx.Context, tableID int64, partitionIDs []int64, allPartitions bool, isIndex int, histID int64) (*statistics.HLLSketch, error) {
+	if len(partitionIDs) == 0 {
+		return nil, nil
+	}
+	if allPartitions {
+		rows, _, err := util.ExecRows(sctx, "select value from mysql.stats_global_ndv_sketch where table_id = %? and is_index = %? and hist_id = %?",
+			tableID, isIndex, histID)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) > 0 {
+			return statistics.DecodeHLLSketch(rows[0].GetBytes(0))
+		}
+	}
+	// Lock the rows of the partitions before the global sketch, so the partitions analyzed concurrently are either
+	// seen here or merged into the saved global sketch by themselves.
+	rows, _, err := util.ExecRows(sctx, "select ndv_sketch from mysql.stats_histograms where table_id in (%?) and is_index = %? and hist_id = %? for update",
+		partitionIDs, isIndex, histID)
+	if err != nil || len(rows) < len(partitionIDs) {
+		return nil, err
+	}
+	var merged *statistics.HLLSketch
+	for _, row := range rows {
+		if row.IsNull(0) {
+			return nil, nil
+		}
+		sketch, err := statistics.DecodeHLLSketch(row.GetBytes(0))
+		if err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = sketch
+		} else {
+			merged.MergeHLLSketch(sketch)
+		}
+	}
+	if !allPartitions {
+		return merged, nil
+	}
+	globalSketch, err := globalNDVSketchForUpdate(sctx, tableID, isIndex, histID)
+	if err != nil {
+		return nil, err
+	}
+	if globalSketch != nil {
+		// It's built by the concurrent merge.
+		return globalSketch, nil
+	}
+	version, err := util.GetStartTS(sctx)
+	if err != nil {
+		return nil, err
+	}
+	return merged, saveGlobalNDVSketch(sctx, tableID, isIndex, histID, merged, version)
+}
@@ -18,10 +18,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/klauspost/compress/gzip"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/statistics"
@@ -58,17 +61,161 @@ func extendedStatsFromJSON(statsColl []*util.JSONExtendedStats) *statistics.Exte
 	}
 	stats := statistics.NewExtendedStatsColl()
 	for _, js := range statsColl {
+		if js.Inited {
+			// The definitions are saved by SaveInitedExtendedStatsFromJSON.
+			continue
+		}
 		item := &statistics.ExtendedStatsItem{
 			ColIDs:     js.ColIDs,
 			Tp:         js.Tp,
 			ScalarVals: js.ScalarVals,
 			StringVals: js.StringVals,
 		}
+		if item.Tp == ast.StatsTypeJSONPath {
+			jsonPaths, err := statistics.DecodeJSONPathStats(js.StringVals)
+			if err != nil {
+				logutil.BgLogger().Warn("decode JSON path stats failed, skip it", zap.String("name", js.StatsName), zap.Error(err))
+				continue
+			}
+			item.JSONPaths = jsonPaths
+		}
+		if item.Tp == ast.StatsTypeHistogram {
+			hist, err := statistics.DecodeMultiColHistogram(js.StringVals)
+			if err != nil {
+				logutil.BgLogger().Warn("decode multi-column histogram failed, skip it", zap.String("name", js.StatsName), zap.Error(err))
+				continue
+			}
+			item.MultiColHist = hist
+		}
+		if item.Tp == ast.StatsTypeTrigram {
+			trigrams, err := statistics.DecodeTrigramStats(js.StringVals)
+			if err != nil {
+				logutil.BgLogger().Warn("decode trigram stats failed, skip it", zap.String("name", js.StatsName), zap.Error(err))
+				continue
+			}
+			item.Trigrams = trigrams
+		}
+		if item.Tp == ast.StatsTypeForeignKey {
+			fk, err := statistics.DecodeForeignKeyStats(js.StringVals)
+			if err != nil {
+				logutil.BgLogger().Warn("decode foreign key stats failed, skip it", zap.String("name", js.StatsName), zap.Error(err))
+				continue
+			}
+			item.ForeignKey = fk
+		}
+		if item.Tp == ast.StatsTypeVector {
+			vec, err := statistics.DecodeVectorStats(js.StringVals)
+			if err != nil {
+				logutil.BgLogger().Warn("decode vector stats failed, skip it", zap.String("name", js.StatsName), zap.Error(err))
+				continue
+			}
+			item.Vector = vec
+		}
+		if item.Tp == ast.StatsTypeIndexPrefixTopN {
+			topN, err := statistics.DecodeIndexPrefixTopN(js.StringVals)
+			if err != nil {
+				logutil.BgLogger().Warn("decode index prefix TopN failed, skip it", zap.String("name", js.StatsName), zap.Error(err))
+				continue
+			}
+			item.IndexPrefixTopN = topN
+		}
 		stats.Stats[js.StatsName] = item
 	}
 	return stats
 }
 
+// InitedExtendedStatsToJSON loads the definitions of the extended stats which are created but not analyzed yet.
+// They are not in the stats cache, but they should be dumped too, so that they are analyzed in the next analyze
+// after being loaded in another cluster.
+func InitedExtendedStatsToJSON(sctx sessionctx.Context, physicalID int64) ([]*util.JSONExtendedStats, error) {
+	rows, _, err := util.ExecRows(sctx, "select name, type, column_ids from mysql.stats_extended where table_id = %? and status = %?", physicalID, statistics.ExtendedStatsInited)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stats := make([]*util.JSONExtendedStats, 0, len(rows))
+	for _, row := range rows {
+		js := &util.JSONExtendedStats{
+			StatsName: row.GetString(0),
+			Tp:        uint8(row.GetInt64(1)),
+			Inited:    true,
+		}
+		if err := json.Unmarshal([]byte(row.GetString(2)), &js.ColIDs); err != nil {
+			return nil, errors.Trace(err)
+		}
+		stats = append(stats, js)
+	}
+	return stats, nil
+}
+
+// SaveInitedExtendedStatsFromJSON saves the definitions of the extended stats which are not analyzed yet.
+func SaveInitedExtendedStatsFromJSON(sctx sessionctx.Context, physicalID int64, stats []*util.JSONExtendedStats) error {
+	version, err := util.GetStartTS(sctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, js := range stats {
+		if js == nil || !js.Inited {
+			continue
+		}
+		colIDs, err := json.Marshal(js.ColIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, _, err := util.ExecRows(sctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, NULL, %?, %?)",
+			js.StatsName, js.Tp, physicalID, string(colIDs), version, statistics.ExtendedStatsInited); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// AnalyzeOptionsToJSON loads the analyze options persisted in mysql.analyze_options.
+// It returns nil if there are no persisted options for the table.
+func AnalyzeOptionsToJSON(sctx sessionctx.Context, physicalID int64) (*util.JSONAnalyzeOptions, error) {
+	rows, _, err := util.ExecRows(sctx, "select sample_num, sample_rate, buckets, topn, column_choice, column_ids from mysql.analyze_options where table_id = %?", physicalID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	row := rows[0]
+	opts := &util.JSONAnalyzeOptions{
+		SampleNum:    row.GetInt64(0),
+		SampleRate:   row.GetFloat64(1),
+		Buckets:      row.GetInt64(2),
+		TopN:         row.GetInt64(3),
+		ColumnChoice: row.GetEnum(4).Name,
+	}
+	if !row.IsNull(5) {
+		for _, idStr := range strings.Split(row.GetString(5), ",") {
+			if idStr == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(idStr, 10, 64)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			opts.ColumnIDs = append(opts.ColumnIDs, id)
+		}
+	}
+	return opts, nil
+}
+
+// SaveAnalyzeOptionsFromJSON saves the dumped analyze options into mysql.analyze_options.
+func SaveAnalyzeOptionsFromJSON(sctx sessionctx.Context, physicalID int64, opts *util.JSONAnalyzeOptions) error {
+	if opts == nil {
+		return nil
+	}
+	colIDs := make([]string, 0, len(opts.ColumnIDs))
+	for _, id := range opts.ColumnIDs {
+		colIDs = append(colIDs, strconv.FormatInt(id, 10))
+	}
+	_, _, err := util.ExecRows(sctx, "replace into mysql.analyze_options (table_id, sample_num, sample_rate, buckets, topn, column_choice, column_ids) values (%?, %?, %?, %?, %?, %?, %?)",
+		physicalID, opts.SampleNum, opts.SampleRate, opts.Buckets, opts.TopN, opts.ColumnChoice, strings.Join(colIDs, ","))
+	return errors.Trace(err)
+}
+
 func dumpJSONCol(hist *statistics.Histogram, cmsketch *statistics.CMSketch, topn *statistics.TopN, fmsketch *statistics.FMSketch, statsVer *int64) *util.JSONColumn {
 	jsonCol := &util.JSONColumn{
 		Histogram:         statistics.HistogramToProto(hist),
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/kv"
 	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/metrics"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/sessionctx"
@@ -223,6 +224,26 @@ func FMSketchFromStorage(sctx sessionctx.Context, tblID int64, isIndex, histID i
 	return statistics.DecodeFMSketch(rows[0].GetBytes(0))
 }
 
+// IndexStatsMetasFromStorage reads the stats meta of the indexes of the table from mysql.stats_histograms. The
+// indexes without stats in the storage are not returned.
+func IndexStatsMetasFromStorage(sctx sessionctx.Context, tblID int64) (map[int64]*statistics.IndexStatsMeta, error) {
+	rows, _, err := util.ExecRows(sctx, "select hist_id, version, stats_ver, modify_count from mysql.stats_histograms where table_id = %? and is_index = 1", tblID)
+	if err != nil {
+		return nil, err
+	}
+	metas := make(map[int64]*statistics.IndexStatsMeta, len(rows))
+	for _, row := range rows {
+		meta := &statistics.IndexStatsMeta{
+			ID:                row.GetInt64(0),
+			Version:           row.GetUint64(1),
+			StatsVer:          row.GetInt64(2),
+			MissedModifyCount: row.GetInt64(3),
+		}
+		metas[meta.ID] = meta
+	}
+	return metas, nil
+}
+
 // CheckSkipPartition checks if we can skip loading the partition.
 func CheckSkipPartition(sctx sessionctx.Context, tblID int64, isIndex int) error {
 	rows, _, err := util.ExecRows(sctx, "select distinct_count from mysql.stats_histograms where table_id =%? and is_index = %?", tblID, isIndex)
@@ -280,7 +301,7 @@ func ExtendedStatsFromStorage(sctx sessionctx.Context, table *statistics.Table,
 				return nil, err
 			}
 			statsStr := row.GetString(4)
-			if item.Tp == ast.StatsTypeCardinality || item.Tp == ast.StatsTypeCorrelation {
+			if item.Tp == ast.StatsTypeCardinality || item.Tp == ast.StatsTypeCorrelation || item.Tp == ast.StatsTypeDependency {
 				if statsStr != "" {
 					item.ScalarVals, err = strconv.ParseFloat(statsStr, 64)
 					if err != nil {
@@ -291,6 +312,48 @@ func ExtendedStatsFromStorage(sctx sessionctx.Context, table *statistics.Table,
 			} else {
 				item.StringVals = statsStr
 			}
+			if item.Tp == ast.StatsTypeJSONPath {
+				item.JSONPaths, err = statistics.DecodeJSONPathStats(statsStr)
+				if err != nil {
+					statslogutil.StatsLogger().Error("decode JSON path stats failed", zap.Int64("table_id", tableID), zap.Error(err))
+					return nil, err
+				}
+			}
+			if item.Tp == ast.StatsTypeHistogram {
+				item.MultiColHist, err = statistics.DecodeMultiColHistogram(statsStr)
+				if err != nil {
+					statslogutil.StatsLogger().Error("decode multi-column histogram failed", zap.Int64("table_id", tableID), zap.Error(err))
+					return nil, err
+				}
+			}
+			if item.Tp == ast.StatsTypeTrigram {
+				item.Trigrams, err = statistics.DecodeTrigramStats(statsStr)
+				if err != nil {
+					statslogutil.StatsLogger().Error("decode trigram stats failed", zap.Int64("table_id", tableID), zap.Error(err))
+					return nil, err
+				}
+			}
+			if item.Tp == ast.StatsTypeForeignKey {
+				item.ForeignKey, err = statistics.DecodeForeignKeyStats(statsStr)
+				if err != nil {
+					statslogutil.StatsLogger().Error("decode foreign key stats failed", zap.Int64("table_id", tableID), zap.Error(err))
+					return nil, err
+				}
+			}
+			if item.Tp == ast.StatsTypeVector {
+				item.Vector, err = statistics.DecodeVectorStats(statsStr)
+				if err != nil {
+					statslogutil.StatsLogger().Error("decode vector stats failed", zap.Int64("table_id", tableID), zap.Error(err))
+					return nil, err
+				}
+			}
+			if item.Tp == ast.StatsTypeIndexPrefixTopN {
+				item.IndexPrefixTopN, err = statistics.DecodeIndexPrefixTopN(statsStr)
+				if err != nil {
+					statslogutil.StatsLogger().Error("decode index prefix TopN failed", zap.Int64("table_id", tableID), zap.Error(err))
+					return nil, err
+				}
+			}
 			table.ExtendedStats.Stats[name] = item
 		}
 	}
@@ -306,6 +369,7 @@ func indexStatsFromStorage(sctx sessionctx.Context, row chunk.Row, table *statis
 	statsVer := row.GetInt64(7)
 	idx := table.GetIdx(histID)
 	flag := row.GetInt64(8)
+	correlation := row.GetFloat64(9)
 	lastAnalyzePos := row.GetDatum(10, types.NewFieldType(mysql.TypeBlob))
 
 	for _, idxInfo := range tableInfo.Indices {
@@ -340,6 +404,7 @@ func indexStatsFromStorage(sctx sessionctx.Context, row chunk.Row, table *statis
 				Flag:       flag,
 				PhysicalID: table.PhysicalID,
 			}
+			idx.Correlation = correlation
 			if idx.IsAnalyzed() {
 				idx.StatsLoadedStatus = statistics.NewStatsAllEvictedStatus()
 			}
@@ -347,7 +412,7 @@ func indexStatsFromStorage(sctx sessionctx.Context, row chunk.Row, table *statis
 			break
 		}
 		if idx == nil || idx.LastUpdateVersion < histVer || loadAll {
-			hg, err := HistogramFromStorageWithPriority(sctx, table.PhysicalID, histID, types.NewFieldType(mysql.TypeBlob), distinct, 1, histVer, nullCount, 0, 0, kv.PriorityNormal)
+			hg, err := HistogramFromStorageWithPriority(sctx, table.PhysicalID, histID, types.NewFieldType(mysql.TypeBlob), distinct, 1, histVer, nullCount, 0, correlation, kv.PriorityNormal)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -491,9 +556,11 @@ func columnStatsFromStorage(sctx sessionctx.Context, row chunk.Row, table *stati
 			lastAnalyzePos.Copy(&col.LastAnalyzePos)
 			break
 		}
-		if col.TotColSize != totColSize {
+		// The null count may be refreshed by the quick refresh of auto analyze without updating the histogram version.
+		if col.TotColSize != totColSize || col.NullCount != nullCount {
 			newCol := *col
 			newCol.TotColSize = totColSize
+			newCol.NullCount = nullCount
 			col = &newCol
 		}
 		break
@@ -587,9 +654,75 @@ func LoadHistogram(sctx sessionctx.Context, tableID int64, isIndex int, histID i
 	return HistogramFromStorageWithPriority(sctx, tableID, histID, types.NewFieldType(mysql.TypeBlob), distinct, isIndex, histVer, nullCount, 0, 0, kv.PriorityNormal)
 }
 
+// BenchLoadStats loads the histograms, TopNs and CMSketches of all the analyzed columns and indices of the table from
+// storage one by one, and measures the time and memory used to load and decode each of them.
+// The loaded stats are dropped after being measured, so the stats cache is not affected.
+func BenchLoadStats(sctx sessionctx.Context, tableInfo *model.TableInfo, physicalID int64) ([]*statstypes.StatsLoadBenchItem, error) {
+	rows, _, err := util.ExecRows(sctx,
+		"select hist_id, is_index, distinct_count, version, null_count, tot_col_size, stats_ver, correlation from mysql.stats_histograms where table_id = %? order by is_index, hist_id",
+		physicalID,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	items := make([]*statstypes.StatsLoadBenchItem, 0, len(rows))
+	for _, row := range rows {
+		histID, isIndex, statsVer := row.GetInt64(0), row.GetInt64(1), row.GetInt64(6)
+		if statsVer == statistics.Version0 {
+			continue
+		}
+		var (
+			name    string
+			tp      *types.FieldType
+			idxInfo *model.IndexInfo
+			colInfo *model.ColumnInfo
+		)
+		if isIndex == 1 {
+			idxInfo = tableInfo.FindIndexByID(histID)
+			if idxInfo == nil {
+				continue
+			}
+			name, tp = idxInfo.Name.O, types.NewFieldType(mysql.TypeBlob)
+		} else {
+			colInfo = model.FindColumnInfoByID(tableInfo.Columns, histID)
+			if colInfo == nil {
+				continue
+			}
+			name, tp = colInfo.Name.O, &colInfo.FieldType
+		}
+		start := time.Now()
+		hg, err := HistogramFromStorageWithPriority(sctx, physicalID, histID, tp, row.GetInt64(2), int(isIndex), row.GetUint64(3), row.GetInt64(4), row.GetInt64(5), row.GetFloat64(7), kv.PriorityNormal)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		cms, topN, err := CMSketchAndTopNFromStorageWithHighPriority(sctx, physicalID, isIndex, histID, statsVer)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		item := &statstypes.StatsLoadBenchItem{
+			Name:       name,
+			PhysicalID: physicalID,
+			IsIndex:    isIndex == 1,
+			Buckets:    hg.Len(),
+			TopNs:      topN.Num(),
+			LoadTime:   time.Since(start),
+		}
+		if idxInfo != nil {
+			idx := &statistics.Index{Histogram: *hg, CMSketch: cms, TopN: topN, Info: idxInfo, StatsVer: statsVer}
+			item.MemoryUsage = idx.MemoryUsage().TotalMemoryUsage()
+		} else {
+			col := &statistics.Column{Histogram: *hg, CMSketch: cms, TopN: topN, Info: colInfo, StatsVer: statsVer}
+			item.MemoryUsage = col.MemoryUsage().TotalMemoryUsage()
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
 // LoadNeededHistograms will load histograms for those needed columns/indices.
 func LoadNeededHistograms(sctx sessionctx.Context, is infoschema.InfoSchema, statsHandle statstypes.StatsHandle, loadFMSketch bool) (err error) {
 	items := asyncload.AsyncLoadHistogramNeededItems.AllItems()
+	metrics.AsyncLoadBacklogGauge.Set(float64(len(items)))
 	for _, item := range items {
 		if !item.IsIndex {
 			err = loadNeededColumnHistograms(sctx, statsHandle, item.TableItemID, loadFMSketch, item.FullLoad)
@@ -640,9 +773,11 @@ func loadNeededColumnHistograms(sctx sessionctx.Context, statsHandle statstypes.
 	var colInfo *model.ColumnInfo
 	_, loadNeeded, analyzed := tbl.ColumnIsLoadNeeded(col.ID, true)
 	if !loadNeeded || !analyzed {
+		metrics.StatsLoadItemCounter.WithLabelValues("async", "hit").Inc()
 		asyncload.AsyncLoadHistogramNeededItems.Delete(col)
 		return nil
 	}
+	metrics.StatsLoadItemCounter.WithLabelValues("async", "miss").Inc()
 
 	// Now, we cannot init the column info in the ColAndIdxExistenceMap when to disable lite-init-stats.
 	// so we have to get the column info from the domain.
@@ -730,9 +865,11 @@ func loadNeededIndexHistograms(sctx sessionctx.Context, is infoschema.InfoSchema
 	}
 	_, loadNeeded := tbl.IndexIsLoadNeeded(idx.ID)
 	if !loadNeeded {
+		metrics.StatsLoadItemCounter.WithLabelValues("async", "hit").Inc()
 		asyncload.AsyncLoadHistogramNeededItems.Delete(idx)
 		return nil
 	}
+	metrics.StatsLoadItemCounter.WithLabelValues("async", "miss").Inc()
 	hgMeta, lastAnalyzePos, statsVer, flag, err := HistMetaFromStorageWithHighPriority(sctx, &idx, nil)
 	if hgMeta == nil || err != nil {
 		asyncload.AsyncLoadHistogramNeededItems.Delete(idx)
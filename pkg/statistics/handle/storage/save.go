@@ -18,7 +18,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/meta/model"
@@ -26,12 +30,15 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/parser/terror"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle/cache"
 	statslogutil "github.com/pingcap/tidb/pkg/statistics/handle/logutil"
+	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/chunk"
+	"github.com/pingcap/tidb/pkg/util/codec"
 	"github.com/pingcap/tidb/pkg/util/sqlescape"
 	"github.com/pingcap/tidb/pkg/util/sqlexec"
 	"go.uber.org/zap"
@@ -126,7 +133,9 @@ func saveBucketsToStorage(sctx sessionctx.Context, tableID int64, isIndex int, h
 // SaveTableStatsToStorage saves the stats of a table to storage.
 func SaveTableStatsToStorage(sctx sessionctx.Context,
 	results *statistics.AnalyzeResults, analyzeSnapshot bool) (statsVer uint64, err error) {
-	needDumpFMS := results.TableID.IsPartitionTable()
+	needDumpFMS := results.TableID.IsPartitionTable() || results.Incremental
+	// The HLL sketch is only used to merge the global NDV.
+	needDumpNDVSketch := results.TableID.IsPartitionTable() && variable.EnableHLLNDVSketch.Load()
 	tableID := results.TableID.GetStatisticsID()
 	ctx := util.StatsCtx
 	txn, err := sctx.Txn(true)
@@ -150,7 +159,7 @@ func SaveTableStatsToStorage(sctx sessionctx.Context,
 	if err != nil {
 		return 0, err
 	}
-	var curCnt, curModifyCnt int64
+	var curCnt, curModifyCnt, carriedModifyCnt int64
 	if len(rows) > 0 {
 		snapshot := rows[0].GetUint64(0)
 		// A newer version analyze result has been written, so skip this writing.
@@ -184,6 +193,8 @@ func SaveTableStatsToStorage(sctx sessionctx.Context,
 			return 0, err
 		}
 		statsVer = version
+		// The modify count is reset by REPLACE INTO.
+		carriedModifyCnt = curModifyCnt
 	} else if results.ForMVIndexOrGlobalIndex {
 		// 1-2. There's already an existing record for this table, and we are handling stats for mv index now.
 		// In this case, we only update the version. See comments for AnalyzeResults.ForMVIndex for more details.
@@ -227,8 +238,9 @@ func SaveTableStatsToStorage(sctx sessionctx.Context,
 				zap.Int64("results.Count", results.Count),
 				zap.Int64("count", cnt))
 		}
+		// The inserted and deleted rows are counted from the new analysis.
 		if _, err = util.Exec(sctx,
-			"update mysql.stats_meta set version=%?, modify_count=%?, count=%?, snapshot=%? where table_id=%?",
+			"update mysql.stats_meta set version=%?, modify_count=%?, count=%?, snapshot=%?, insert_count=0, delete_count=0 where table_id=%?",
 			version,
 			modifyCnt,
 			cnt,
@@ -238,8 +250,32 @@ func SaveTableStatsToStorage(sctx sessionctx.Context,
 			return 0, err
 		}
 		statsVer = version
+		carriedModifyCnt = curModifyCnt - modifyCnt
+	}
+	// The modifications removed from the modify count of the table are carried over to the analyzed indexes which are
+	// not covered by this analysis, i.e. analyzed before its snapshot, see statistics.IndexStatsMeta. The indexes
+	// covered by it are reset by the REPLACE INTO of their histograms below.
+	if carriedModifyCnt > 0 {
+		if _, err = util.Exec(sctx,
+			"update mysql.stats_histograms set modify_count = modify_count + %? where table_id = %? and is_index = 1 and stats_ver > 0 and version < %?",
+			carriedModifyCnt,
+			tableID,
+			results.Snapshot,
+		); err != nil {
+			return 0, err
+		}
 	}
 	cache.TableRowStatsCache.Invalidate(tableID)
+	// The injected stats don't survive the analyze.
+	if _, err = util.Exec(sctx, "delete from mysql.stats_injected where table_id = %?", tableID); err != nil {
+		return 0, err
+	}
+	// The partition is analyzed without the HLL sketches, so the global NDV sketches can't be maintained.
+	if results.TableID.IsPartitionTable() && !needDumpNDVSketch {
+		if err = DeleteGlobalNDVSketches(sctx, results.TableID.TableID); err != nil {
+			return 0, err
+		}
+	}
 	// 2. Save histograms.
 	for _, result := range results.Ars {
 		for i, hg := range result.Hist {
@@ -274,6 +310,13 @@ func SaveTableStatsToStorage(sctx sessionctx.Context,
 					return 0, err
 				}
 			}
+			var oldNDVSketch *statistics.HLLSketch
+			if needDumpNDVSketch {
+				// Read the old sketch before it's overwritten, to decide whether the new one can be merged into the global one.
+				if oldNDVSketch, err = partitionNDVSketchForUpdate(sctx, tableID, result.IsIndex, hg.ID); err != nil {
+					return 0, err
+				}
+			}
 			if _, err = util.Exec(sctx, "replace into mysql.stats_histograms (table_id, is_index, hist_id, distinct_count, version, null_count, cm_sketch, tot_col_size, stats_ver, flag, correlation) values (%?, %?, %?, %?, %?, %?, %?, GREATEST(%?, 0), %?, %?, %?)",
 				tableID, result.IsIndex, hg.ID, hg.NDV, version, hg.NullCount, cmSketch, hg.TotColSize, results.StatsVer, statistics.AnalyzeFlag, hg.Correlation); err != nil {
 				return 0, err
@@ -291,6 +334,19 @@ func SaveTableStatsToStorage(sctx sessionctx.Context,
 					return 0, err
 				}
 			}
+			if needDumpNDVSketch {
+				var newNDVSketch *statistics.HLLSketch
+				if result.Fms[i] != nil {
+					newNDVSketch = statistics.NewHLLSketchFromFMSketch(result.Fms[i])
+					ndvSketch := statistics.EncodeHLLSketch(newNDVSketch)
+					if _, err = util.Exec(sctx, "update mysql.stats_histograms set ndv_sketch = %? where table_id = %? and is_index = %? and hist_id = %?", ndvSketch, tableID, result.IsIndex, hg.ID); err != nil {
+						return 0, err
+					}
+				}
+				if err = updateGlobalNDVSketch(sctx, results.TableID.TableID, result.IsIndex, hg.ID, oldNDVSketch, newNDVSketch, version); err != nil {
+					return 0, err
+				}
+			}
 			if result.IsIndex == 0 {
 				if _, err = util.Exec(sctx, "insert into mysql.column_stats_usage (table_id, column_id, last_analyzed_at) values(%?, %?, current_timestamp()) on duplicate key update last_analyzed_at = values(last_analyzed_at)", tableID, hg.ID); err != nil {
 					return 0, err
@@ -312,9 +368,10 @@ func SaveTableStatsToStorage(sctx sessionctx.Context,
 		}
 		strColIDs := string(bytes)
 		switch item.Tp {
-		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
+		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation, ast.StatsTypeDependency:
 			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
+		case ast.StatsTypeJSONPath, ast.StatsTypeForeignKey, ast.StatsTypeVector, ast.StatsTypeTrigram, ast.StatsTypeHistogram,
+			ast.StatsTypeIndexPrefixTopN:
 			statsStr = item.StringVals
 		}
 		if _, err = util.Exec(sctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, %?, %?, %?)", name, item.Tp, tableID, strColIDs, statsStr, version, statistics.ExtendedStatsAnalyzed); err != nil {
@@ -413,6 +470,103 @@ func SaveMetaToStorage(
 	return
 }
 
+// InjectStats writes the manually injected stats to mysql.stats_meta and mysql.stats_histograms, and records them in
+// mysql.stats_injected. The stats are merged into the ones injected before, and the null ratios are applied to the
+// latest row count. The histograms, TopN and stats versions of the columns are kept, so the injected NDVs and null
+// counts replace the ones of the existing stats until the table is analyzed again.
+func InjectStats(sctx sessionctx.Context, physicalID int64, stats *statstypes.InjectedStats) (statsVer uint64, err error) {
+	version, err := util.GetStartTS(sctx)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	count, _, _, err := StatsMetaCountAndModifyCountForUpdate(util.StatsCtx, sctx, physicalID)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	injected := &statstypes.InjectedStats{}
+	rows, _, err := util.ExecRows(sctx, "select stats from mysql.stats_injected where table_id = %?", physicalID)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if len(rows) > 0 {
+		if err = json.Unmarshal([]byte(rows[0].GetJSON(0).String()), injected); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	mergeInjectedStats(injected, stats)
+
+	if stats.RowCount != nil {
+		count = *stats.RowCount
+		// The modify count is reset as if the table were just analyzed.
+		_, err = util.Exec(sctx, "insert into mysql.stats_meta (version, table_id, count, modify_count) values (%?, %?, %?, 0) "+
+			"on duplicate key update version = values(version), count = values(count), modify_count = 0, insert_count = 0, delete_count = 0",
+			version, physicalID, count)
+	} else {
+		_, err = util.Exec(sctx, "insert into mysql.stats_meta (version, table_id) values (%?, %?) "+
+			"on duplicate key update version = values(version)", version, physicalID)
+	}
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	cache.TableRowStatsCache.Invalidate(physicalID)
+
+	colIDs := make([]int64, 0, len(injected.ColNDVs)+len(injected.NullRatios))
+	for colID := range injected.ColNDVs {
+		colIDs = append(colIDs, colID)
+	}
+	for colID := range injected.NullRatios {
+		if _, ok := injected.ColNDVs[colID]; !ok {
+			colIDs = append(colIDs, colID)
+		}
+	}
+	slices.Sort(colIDs)
+	for _, colID := range colIDs {
+		rows, _, err = util.ExecRows(sctx, "select distinct_count, null_count from mysql.stats_histograms where table_id = %? and is_index = 0 and hist_id = %?", physicalID, colID)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		var ndv, nullCount int64
+		if len(rows) > 0 {
+			ndv, nullCount = rows[0].GetInt64(0), rows[0].GetInt64(1)
+		}
+		if v, ok := injected.ColNDVs[colID]; ok {
+			ndv = v
+		}
+		if ratio, ok := injected.NullRatios[colID]; ok {
+			nullCount = int64(math.Round(ratio * float64(count)))
+		}
+		if _, err = util.Exec(sctx, "insert into mysql.stats_histograms (table_id, is_index, hist_id, distinct_count, null_count, version) values (%?, 0, %?, %?, %?, %?) "+
+			"on duplicate key update distinct_count = values(distinct_count), null_count = values(null_count), version = values(version)",
+			physicalID, colID, ndv, nullCount, version); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+
+	bytes, err := json.Marshal(injected)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if _, err = util.Exec(sctx, "replace into mysql.stats_injected (table_id, stats, version) values (%?, %?, %?)", physicalID, string(bytes), version); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return version, nil
+}
+
+// mergeInjectedStats merges the newly injected stats into the injected ones.
+func mergeInjectedStats(injected, stats *statstypes.InjectedStats) {
+	if stats.RowCount != nil {
+		injected.RowCount = stats.RowCount
+	}
+	if len(stats.ColNDVs) > 0 && injected.ColNDVs == nil {
+		injected.ColNDVs = make(map[int64]int64, len(stats.ColNDVs))
+	}
+	maps.Copy(injected.ColNDVs, stats.ColNDVs)
+	if len(stats.NullRatios) > 0 && injected.NullRatios == nil {
+		injected.NullRatios = make(map[int64]float64, len(stats.NullRatios))
+	}
+	maps.Copy(injected.NullRatios, stats.NullRatios)
+}
+
 // InsertColStats2KV insert a record to stats_histograms with distinct_count 1
 // and insert a bucket to stats_buckets with default value. This operation also
 // updates version.
@@ -506,6 +660,117 @@ func InsertColStats2KV(
 	return startTS, nil
 }
 
+// ModifyColStats2KV handles the stats of the columns whose types are changed by DDL. If the type of a column is
+// changed losslessly and it has been analyzed with the stats version 2, its histogram and TopN are converted to the
+// new column instead of being invalidated. Otherwise, the stats of the new column are inserted as InsertColStats2KV
+// does. This operation also updates version.
+func ModifyColStats2KV(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	physicalID int64,
+	newColInfos, oldColInfos []*model.ColumnInfo,
+) (uint64, error) {
+	insertedCols := make([]*model.ColumnInfo, 0, len(newColInfos))
+	convertedCols := make([][2]*model.ColumnInfo, 0, len(newColInfos))
+	for i, newCol := range newColInfos {
+		// The events of the older versions don't carry the old columns.
+		if i >= len(oldColInfos) || !statistics.IsLosslessColumnTypeChange(&oldColInfos[i].FieldType, &newCol.FieldType) {
+			insertedCols = append(insertedCols, newCol)
+			continue
+		}
+		rows, _, err := util.ExecRowsWithCtx(
+			ctx, sctx,
+			"select stats_ver from mysql.stats_histograms where table_id = %? and is_index = 0 and hist_id = %?",
+			physicalID, oldColInfos[i].ID,
+		)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if len(rows) == 0 || rows[0].GetInt64(0) != statistics.Version2 {
+			insertedCols = append(insertedCols, newCol)
+			continue
+		}
+		convertedCols = append(convertedCols, [2]*model.ColumnInfo{oldColInfos[i], newCol})
+	}
+	// The version is updated here, so the converted stats are loaded as well.
+	startTS, err := InsertColStats2KV(ctx, sctx, physicalID, insertedCols)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	for _, cols := range convertedCols {
+		if err := convertColStats2KV(ctx, sctx, physicalID, cols[0], cols[1]); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	return startTS, nil
+}
+
+// convertColStats2KV copies the stats of the old column to the new column whose type is changed losslessly.
+// The bounds of the buckets are stored as strings and converted to the column type when they are loaded, so they are
+// copied directly. The TopN values are encoded by the type of the column, so they are converted to the new type.
+// The version of the histogram is kept, since the histogram is still the one collected by the last analysis.
+func convertColStats2KV(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	physicalID int64,
+	oldCol, newCol *model.ColumnInfo,
+) error {
+	if _, err := util.ExecWithCtx(
+		ctx, sctx,
+		`replace into mysql.stats_histograms
+			(table_id, is_index, hist_id, distinct_count, null_count, tot_col_size, modify_count, version, cm_sketch, stats_ver, flag, correlation, last_analyze_pos)
+		select table_id, is_index, %?, distinct_count, null_count, tot_col_size, modify_count, version, cm_sketch, stats_ver, flag, correlation, last_analyze_pos
+		from mysql.stats_histograms where table_id = %? and is_index = 0 and hist_id = %?`,
+		newCol.ID, physicalID, oldCol.ID,
+	); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := util.ExecWithCtx(
+		ctx, sctx,
+		`insert into mysql.stats_buckets
+			(table_id, is_index, hist_id, bucket_id, count, repeats, lower_bound, upper_bound, ndv)
+		select table_id, is_index, %?, bucket_id, count, repeats, lower_bound, upper_bound, ndv
+		from mysql.stats_buckets where table_id = %? and is_index = 0 and hist_id = %?`,
+		newCol.ID, physicalID, oldCol.ID,
+	); err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := util.ExecRowsWithCtx(
+		ctx, sctx,
+		"select value, count from mysql.stats_top_n where table_id = %? and is_index = 0 and hist_id = %?",
+		physicalID, oldCol.ID,
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	topN := &statistics.TopN{TopN: make([]statistics.TopNMeta, 0, len(rows))}
+	for _, row := range rows {
+		encoded := row.GetBytes(0)
+		// The string values are encoded by their collation keys, which are not changed with the same collation.
+		if oldCol.FieldType.EvalType() != types.ETString {
+			encoded, err = convertTopNValue(encoded, &oldCol.FieldType, &newCol.FieldType)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		topN.AppendTopN(encoded, row.GetUint64(1))
+	}
+	return errors.Trace(saveTopNToStorage(sctx, physicalID, 0, newCol.ID, topN))
+}
+
+// convertTopNValue converts the encoded TopN value from the old type to the new type.
+func convertTopNValue(encoded []byte, oldTp, newTp *types.FieldType) ([]byte, error) {
+	vals, _, err := codec.DecodeRange(encoded, 1, []byte{oldTp.GetType()}, time.UTC)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	d, err := vals[0].ConvertTo(statistics.UTCWithAllowInvalidDateCtx, newTp)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return codec.EncodeKey(time.UTC, nil, d)
+}
+
 // InsertTableStats2KV inserts a record standing for a new table to stats_meta
 // and inserts some records standing for the new columns and indices which belong
 // to this table.
@@ -526,27 +791,147 @@ func InsertTableStats2KV(
 	); err != nil {
 		return 0, errors.Trace(err)
 	}
-	for _, col := range info.Columns {
+	if err = insertEmptyHistograms(ctx, sctx, info, physicalID, startTS); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return startTS, nil
+}
+
+// ResetTableStats2KV resets the stats of the physical table to the empty stats of a new table, which is used for the
+// tables recreated by TRUNCATE TABLE. Unlike InsertTableStats2KV, it overwrites the existing stats meta and removes
+// the existing stats of the physical ID, so the table is always regarded as empty and never analyzed, even if the
+// event is handled more than once.
+func ResetTableStats2KV(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	info *model.TableInfo,
+	physicalID int64,
+) (uint64, error) {
+	startTS, err := util.GetStartTS(sctx)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if _, err = util.ExecWithCtx(
+		ctx, sctx,
+		"replace into mysql.stats_meta (version, table_id, count, modify_count, snapshot) values (%?, %?, 0, 0, 0)",
+		startTS, physicalID,
+	); err != nil {
+		return 0, errors.Trace(err)
+	}
+	for _, table := range []string{"stats_histograms", "stats_buckets", "stats_top_n", "stats_fm_sketch"} {
 		if _, err = util.ExecWithCtx(
+			ctx, sctx,
+			"delete from %n.%n where table_id = %?",
+			mysql.SystemDB, table, physicalID,
+		); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	if err = insertEmptyHistograms(ctx, sctx, info, physicalID, startTS); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return startTS, nil
+}
+
+// RecoverTableStats2KV recovers the stats of the physical table recovered by FLASHBACK TABLE or RECOVER TABLE. The
+// stats which haven't been removed by the stats GC since the table was dropped are reloaded, otherwise the latest
+// historical stats of the table are restored. If neither exists, the table is regarded as a new table.
+func RecoverTableStats2KV(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	info *model.TableInfo,
+	physicalID int64,
+) (uint64, error) {
+	rows, _, err := util.ExecRowsWithCtx(
+		ctx, sctx,
+		"select count(*) from mysql.stats_histograms where table_id = %?",
+		physicalID,
+	)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if rows[0].GetInt64(0) > 0 {
+		// Update the version so the stats are loaded again, since they were removed from the stats cache when the
+		// table was dropped.
+		return UpdateStatsMetaVersionForGC(ctx, sctx, physicalID)
+	}
+	startTS, err := restoreHistoricalStats(sctx, info, physicalID)
+	if err != nil || startTS != 0 {
+		return startTS, errors.Trace(err)
+	}
+	return ResetTableStats2KV(ctx, sctx, info, physicalID)
+}
+
+// restoreHistoricalStats saves the latest historical stats of the physical table in mysql.stats_history as its stats.
+// It returns 0 if there are no historical stats of the table.
+func restoreHistoricalStats(
+	sctx sessionctx.Context,
+	info *model.TableInfo,
+	physicalID int64,
+) (uint64, error) {
+	jsonTbl, exist, err := TableHistoricalStatsToJSON(sctx, physicalID, math.MaxUint64)
+	if err != nil || !exist {
+		return 0, errors.Trace(err)
+	}
+	// The historical stats of the partitioned table are dumped with the stats of all its partitions.
+	if jsonTbl.Partitions != nil {
+		globalStats, ok := jsonTbl.Partitions[util.TiDBGlobalStats]
+		if !ok {
+			return 0, nil
+		}
+		globalStats.Count, globalStats.ModifyCount = jsonTbl.Count, jsonTbl.ModifyCount
+		jsonTbl = globalStats
+	}
+	tbl, err := TableStatsFromJSON(info, physicalID, jsonTbl)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	tbl.ForEachColumnImmutable(func(_ int64, col *statistics.Column) bool {
+		_, err = SaveStatsToStorage(sctx, physicalID, -1, 0, 0, &col.Histogram, col.CMSketch, col.TopN, int(col.GetStatsVer()), statistics.AnalyzeFlag, false)
+		return err != nil
+	})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	tbl.ForEachIndexImmutable(func(_ int64, idx *statistics.Index) bool {
+		_, err = SaveStatsToStorage(sctx, physicalID, -1, 0, 1, &idx.Histogram, idx.CMSketch, idx.TopN, int(idx.GetStatsVer()), statistics.AnalyzeFlag, false)
+		return err != nil
+	})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return SaveMetaToStorage(sctx, physicalID, tbl.RealtimeCount, tbl.ModifyCount)
+}
+
+// insertEmptyHistograms inserts the records standing for the columns and indices of a new table to stats_histograms.
+func insertEmptyHistograms(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	info *model.TableInfo,
+	physicalID int64,
+	startTS uint64,
+) error {
+	for _, col := range info.Columns {
+		if _, err := util.ExecWithCtx(
 			ctx, sctx,
 			`insert into mysql.stats_histograms
 				(table_id, is_index, hist_id, distinct_count, version)
 			values (%?, 0, %?, 0, %?)`,
 			physicalID, col.ID, startTS,
 		); err != nil {
-			return 0, errors.Trace(err)
+			return errors.Trace(err)
 		}
 	}
 	for _, idx := range info.Indices {
-		if _, err = util.ExecWithCtx(
+		if _, err := util.ExecWithCtx(
 			ctx, sctx,
 			`insert into mysql.stats_histograms
 				(table_id, is_index, hist_id, distinct_count, version)
 			values(%?, 1, %?, 0, %?)`,
 			physicalID, idx.ID, startTS,
 		); err != nil {
-			return 0, errors.Trace(err)
+			return errors.Trace(err)
 		}
 	}
-	return startTS, nil
+	return nil
 }
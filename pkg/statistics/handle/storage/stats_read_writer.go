@@ -17,7 +17,9 @@ package storage
 import (
 	"context"
 	"fmt"
+	"io"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -70,6 +72,46 @@ func (s *statsReadWriter) InsertColStats2KV(physicalID int64, colInfos []*model.
 	}, util.FlagWrapTxn)
 }
 
+// ModifyColStats2KV converts the stats of the columns whose types are changed losslessly to the new columns, and
+// inserts the stats of the other modified columns as InsertColStats2KV does. This operation also updates version.
+func (s *statsReadWriter) ModifyColStats2KV(physicalID int64, newColInfos, oldColInfos []*model.ColumnInfo) (err error) {
+	statsVer := uint64(0)
+	defer func() {
+		if err == nil && statsVer != 0 {
+			s.statsHandler.RecordHistoricalStatsMeta(physicalID, statsVer, util.StatsMetaHistorySourceSchemaChange, false)
+		}
+	}()
+
+	return util.CallWithSCtx(s.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		startTS, err := ModifyColStats2KV(util.StatsCtx, sctx, physicalID, newColInfos, oldColInfos)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		statsVer = startTS
+		return nil
+	}, util.FlagWrapTxn)
+}
+
+// ResetTableStats2KV resets the stats of the physical table to the empty stats of a new table, which is used for the
+// tables recreated by TRUNCATE TABLE.
+func (s *statsReadWriter) ResetTableStats2KV(info *model.TableInfo, physicalID int64) (err error) {
+	statsVer := uint64(0)
+	defer func() {
+		if err == nil && statsVer != 0 {
+			s.statsHandler.RecordHistoricalStatsMeta(physicalID, statsVer, util.StatsMetaHistorySourceSchemaChange, false)
+		}
+	}()
+
+	return util.CallWithSCtx(s.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		startTS, err := ResetTableStats2KV(util.StatsCtx, sctx, info, physicalID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		statsVer = startTS
+		return nil
+	}, util.FlagWrapTxn)
+}
+
 // InsertTableStats2KV inserts a record standing for a new table to stats_meta and inserts some records standing for the
 // new columns and indices which belong to this table.
 func (s *statsReadWriter) InsertTableStats2KV(info *model.TableInfo, physicalID int64) (err error) {
@@ -125,13 +167,29 @@ func (s *statsReadWriter) UpdateStatsVersion() error {
 	}, util.FlagWrapTxn)
 }
 
-// SaveTableStatsToStorage saves the stats of a table to storage.
+// SaveTableStatsToStorage saves the stats of a table to storage. If tidb_analyze_verify_stats is on, the stats built
+// by ANALYZE are verified first, and the suspicious ones are quarantined in mysql.analyze_quarantine instead.
 func (s *statsReadWriter) SaveTableStatsToStorage(results *statistics.AnalyzeResults, analyzeSnapshot bool, source string) (err error) {
 	var statsVer uint64
+	var reasons []string
 	err = util.CallWithSCtx(s.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		if source == util.StatsMetaHistorySourceAnalyze && variable.AnalyzeVerifyStats.Load() {
+			var curCnt int64
+			curCnt, reasons, err = VerifyAnalyzeResults(sctx, results)
+			if err != nil {
+				return err
+			}
+			if len(reasons) > 0 {
+				return QuarantineAnalyzeResults(sctx, results, curCnt, reasons)
+			}
+		}
 		statsVer, err = SaveTableStatsToStorage(sctx, results, analyzeSnapshot)
 		return err
 	}, util.FlagWrapTxn)
+	if err == nil && len(reasons) > 0 {
+		return errors.Errorf("the analyze results of table %d are quarantined in mysql.analyze_quarantine: %s",
+			results.TableID.GetStatisticsID(), strings.Join(reasons, "; "))
+	}
 	if err == nil && statsVer != 0 {
 		tableID := results.TableID.GetStatisticsID()
 		s.statsHandler.RecordHistoricalStatsMeta(tableID, statsVer, source, true)
@@ -162,6 +220,16 @@ func (s *statsReadWriter) TableStatsFromStorage(tableInfo *model.TableInfo, phys
 	return
 }
 
+// BenchLoadStats loads the stats of all the columns and indices of the table from storage without updating the
+// stats cache, and reports the load time and memory usage of each of them.
+func (s *statsReadWriter) BenchLoadStats(tableInfo *model.TableInfo, physicalID int64) (items []*statstypes.StatsLoadBenchItem, err error) {
+	err = util.CallWithSCtx(s.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		items, err = BenchLoadStats(sctx, tableInfo, physicalID)
+		return err
+	}, util.FlagWrapTxn)
+	return
+}
+
 // SaveStatsToStorage saves the stats to storage.
 // If count is negative, both count and modify count would not be used and not be written to the table. Unless, corresponding
 // fields in the stats_meta table will be updated.
@@ -203,6 +271,19 @@ func (s *statsReadWriter) SaveMetaToStorage(tableID, count, modifyCount int64, s
 	return
 }
 
+// InjectStats writes the manually injected stats of a table or a partition to storage.
+func (s *statsReadWriter) InjectStats(physicalID int64, stats *statstypes.InjectedStats) (err error) {
+	var statsVer uint64
+	err = util.CallWithSCtx(s.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		statsVer, err = InjectStats(sctx, physicalID, stats)
+		return err
+	}, util.FlagWrapTxn)
+	if err == nil && statsVer != 0 {
+		s.statsHandler.RecordHistoricalStatsMeta(physicalID, statsVer, util.StatsMetaHistorySourceInjectStats, false)
+	}
+	return
+}
+
 // InsertExtendedStats inserts a record into mysql.stats_extended and update version in mysql.stats_meta.
 func (s *statsReadWriter) InsertExtendedStats(statsName string, colIDs []int64, tp int, tableID int64, ifNotExists bool) (err error) {
 	var statsVer uint64
@@ -211,7 +292,7 @@ func (s *statsReadWriter) InsertExtendedStats(statsName string, colIDs []int64,
 		return err
 	}, util.FlagWrapTxn)
 	if err == nil && statsVer != 0 {
-		s.statsHandler.RecordHistoricalStatsMeta(tableID, statsVer, "extended stats", false)
+		s.statsHandler.RecordHistoricalStatsMeta(tableID, statsVer, util.StatsMetaHistorySourceExtendedStats, false)
 	}
 	return
 }
@@ -224,7 +305,7 @@ func (s *statsReadWriter) MarkExtendedStatsDeleted(statsName string, tableID int
 		return err
 	}, util.FlagWrapTxn)
 	if err == nil && statsVer != 0 {
-		s.statsHandler.RecordHistoricalStatsMeta(tableID, statsVer, "extended stats", false)
+		s.statsHandler.RecordHistoricalStatsMeta(tableID, statsVer, util.StatsMetaHistorySourceExtendedStats, false)
 	}
 	return
 }
@@ -237,7 +318,7 @@ func (s *statsReadWriter) SaveExtendedStatsToStorage(tableID int64, extStats *st
 		return err
 	}, util.FlagWrapTxn)
 	if err == nil && statsVer != 0 {
-		s.statsHandler.RecordHistoricalStatsMeta(tableID, statsVer, "extended stats", false)
+		s.statsHandler.RecordHistoricalStatsMeta(tableID, statsVer, util.StatsMetaHistorySourceExtendedStats, false)
 	}
 	return
 }
@@ -442,6 +523,60 @@ func (s *statsReadWriter) DumpStatsToJSONBySnapshot(dbName string, tableInfo *mo
 	return jsonTbl, nil
 }
 
+// DumpStatsToBinary dumps statistic to w in the binary format.
+// Unlike DumpStatsToJSONBySnapshot, the stats of the partitions are written one by one,
+// so the memory usage doesn't grow with the number of partitions.
+func (s *statsReadWriter) DumpStatsToBinary(w io.Writer, dbName string, tableInfo *model.TableInfo, snapshot uint64, dumpPartitionStats bool) error {
+	pruneMode, err := util.GetCurrentPruneMode(s.statsHandler.SPool())
+	if err != nil {
+		return err
+	}
+	isDynamicMode := variable.PartitionPruneMode(pruneMode) == variable.Dynamic
+	bw, err := newBinaryStatsWriter(w, dbName, tableInfo.Name.L)
+	if err != nil {
+		return err
+	}
+	pi := tableInfo.GetPartitionInfo()
+	if pi == nil {
+		tbl, err := s.TableStatsToJSON(dbName, tableInfo, tableInfo.ID, snapshot)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if tbl != nil {
+			if err := bw.writeTable("", tbl); err != nil {
+				return err
+			}
+		}
+		return bw.close()
+	}
+	// dump partition stats only if in static mode or enable dumpPartitionStats flag in dynamic mode
+	if !isDynamicMode || dumpPartitionStats {
+		for _, def := range pi.Definitions {
+			tbl, err := s.TableStatsToJSON(dbName, tableInfo, def.ID, snapshot)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if tbl == nil {
+				continue
+			}
+			if err := bw.writeTable(def.Name.L, tbl); err != nil {
+				return err
+			}
+		}
+	}
+	// dump its global-stats if existed
+	tbl, err := s.TableStatsToJSON(dbName, tableInfo, tableInfo.ID, snapshot)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tbl != nil {
+		if err := bw.writeTable(util.TiDBGlobalStats, tbl); err != nil {
+			return err
+		}
+	}
+	return bw.close()
+}
+
 // getTableHistoricalStatsToJSONWithFallback try to get table historical stats, if not exist, directly fallback to the
 // latest stats, and the second return value would be true.
 func (s *statsReadWriter) getTableHistoricalStatsToJSONWithFallback(
@@ -477,6 +612,20 @@ func (s *statsReadWriter) tableHistoricalStatsToJSON(physicalID int64, snapshot
 	return
 }
 
+// HistoricalTableStats loads the stats of the table at the given version from mysql.stats_meta_history
+// and mysql.stats_history. The second return value is false if there is no historical stats before the version.
+func (s *statsReadWriter) HistoricalTableStats(tableInfo *model.TableInfo, physicalID int64, version uint64) (*statistics.Table, bool, error) {
+	jt, exist, err := s.tableHistoricalStatsToJSON(physicalID, version)
+	if err != nil || !exist {
+		return nil, false, err
+	}
+	tbl, err := TableStatsFromJSON(tableInfo, physicalID, jt)
+	if err != nil {
+		return nil, false, err
+	}
+	return tbl, true, nil
+}
+
 // TableStatsToJSON dumps statistic to json.
 func (s *statsReadWriter) TableStatsToJSON(dbName string, tableInfo *model.TableInfo, physicalID int64, snapshot uint64) (*util.JSONTable, error) {
 	tbl, err := s.TableStatsFromStorage(tableInfo, physicalID, true, snapshot)
@@ -495,6 +644,17 @@ func (s *statsReadWriter) TableStatsToJSON(dbName string, tableInfo *model.Table
 			return err
 		}
 		jsonTbl, err = GenJSONTableFromStats(sctx, dbName, tableInfo, tbl, colStatsUsage)
+		if err != nil {
+			return err
+		}
+		// The analyze options and the definitions of the extended stats are dumped too, so that the next analyze
+		// behaves the same after the stats are loaded in another cluster.
+		initedExtStats, err := InitedExtendedStatsToJSON(sctx, physicalID)
+		if err != nil {
+			return err
+		}
+		jsonTbl.ExtStats = append(jsonTbl.ExtStats, initedExtStats...)
+		jsonTbl.AnalyzeOptions, err = AnalyzeOptionsToJSON(sctx, physicalID)
 		return err
 	})
 	if err != nil {
@@ -613,17 +773,164 @@ func (s *statsReadWriter) LoadStatsFromJSON(ctx context.Context, is infoschema.I
 	return errors.Trace(s.statsHandler.Update(ctx, is))
 }
 
+// MergeStatsFromJSON merges the partial stats in the JSONTable into the stats of the table, and saves the merged stats
+// to the storage. See statistics.MergeTableStats for how the stats are merged. If the version of the JSONTable is not
+// 0, it's the last analyze version of the stats which the partial stats are based on, and the merge fails if the table
+// has been analyzed since then, so that the rows counted by the partial stats aren't counted twice.
+// Only the non-partitioned tables are supported. In final, it will also update the stats cache.
+func (s *statsReadWriter) MergeStatsFromJSON(ctx context.Context, is infoschema.InfoSchema, jsonTbl *util.JSONTable) error {
+	table, err := is.TableByName(context.Background(), pmodel.NewCIStr(jsonTbl.DatabaseName), pmodel.NewCIStr(jsonTbl.TableName))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tableInfo := table.Meta()
+	if tableInfo.GetPartitionInfo() != nil || len(jsonTbl.Partitions) > 0 {
+		return errors.Errorf("merging the stats of the partitioned table %s.%s is not supported", jsonTbl.DatabaseName, jsonTbl.TableName)
+	}
+	partial, err := TableStatsFromJSON(tableInfo, tableInfo.ID, jsonTbl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var merged *statistics.Table
+	err = util.CallWithSCtx(s.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		base, err := TableStatsFromStorage(sctx, 0, tableInfo, tableInfo.ID, true, 0, nil)
+		if err != nil {
+			return err
+		}
+		if base == nil {
+			// The table has no stats yet, the partial stats are loaded as they are.
+			base = &statistics.Table{HistColl: *statistics.NewHistColl(tableInfo.ID, true, 0, 0, 0, 0)}
+		}
+		if jsonTbl.Version != 0 && jsonTbl.Version != base.LastAnalyzeVersion {
+			return errors.Errorf("the stats of %s.%s are based on version %d, but the table has been analyzed at version %d",
+				jsonTbl.DatabaseName, jsonTbl.TableName, jsonTbl.Version, base.LastAnalyzeVersion)
+		}
+		merged, err = statistics.MergeTableStats(sctx.GetSessionVars().StmtCtx, base, partial)
+		return err
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := s.saveColumnsAndIndicesToStorage(merged); err != nil {
+		return errors.Trace(err)
+	}
+	if err := s.SaveMetaToStorage(merged.PhysicalID, merged.RealtimeCount, merged.ModifyCount, util.StatsMetaHistorySourceLoadStats); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(s.statsHandler.Update(ctx, is))
+}
+
+// LoadStatsFromBinary will load statistic dumped by DumpStatsToBinary from r, and save it to the storage.
+// The stats of the partitions are saved while they are being decoded, so the whole dump is never held in memory.
+// In final, it will also update the stats cache.
+func (s *statsReadWriter) LoadStatsFromBinary(ctx context.Context, is infoschema.InfoSchema,
+	r io.Reader, concurrencyForPartition int) error {
+	br, err := newBinaryStatsReader(r)
+	if err != nil {
+		return err
+	}
+	table, err := is.TableByName(context.Background(), pmodel.NewCIStr(br.dbName), pmodel.NewCIStr(br.tableName))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tableInfo := table.Meta()
+	physicalIDs := map[string]int64{
+		"":                   tableInfo.ID,
+		util.TiDBGlobalStats: tableInfo.ID,
+	}
+	if pi := tableInfo.GetPartitionInfo(); pi != nil {
+		for _, def := range pi.Definitions {
+			physicalIDs[def.Name.L] = def.ID
+		}
+	}
+
+	taskCh := make(chan *statstypes.PartitionStatisticLoadTask)
+	done := make(chan struct{})
+	var readErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	s.statsHandler.GPool().Go(func() {
+		defer func() {
+			close(taskCh)
+			wg.Done()
+		}()
+		readErr = readBinaryStatsLoadTasks(br, physicalIDs, taskCh, done)
+	})
+	err = s.LoadStatsFromJSONConcurrently(ctx, tableInfo, taskCh, concurrencyForPartition)
+	// Stop the reader if the loading fails.
+	close(done)
+	wg.Wait()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if readErr != nil {
+		return errors.Trace(readErr)
+	}
+	return errors.Trace(s.statsHandler.Update(ctx, is))
+}
+
+// readBinaryStatsLoadTasks reads the stats of the physical tables one by one and sends them to taskCh.
+// The partitions which don't exist anymore are skipped.
+func readBinaryStatsLoadTasks(
+	br *binaryStatsReader,
+	physicalIDs map[string]int64,
+	taskCh chan<- *statstypes.PartitionStatisticLoadTask,
+	done <-chan struct{},
+) error {
+	for {
+		partitionName, jsonTbl, err := br.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		physicalID, ok := physicalIDs[partitionName]
+		if !ok {
+			continue
+		}
+		select {
+		case taskCh <- &statstypes.PartitionStatisticLoadTask{PhysicalID: physicalID, JSONTable: jsonTbl}:
+		case <-done:
+			return nil
+		}
+	}
+}
+
 func (s *statsReadWriter) loadStatsFromJSON(tableInfo *model.TableInfo, physicalID int64, jsonTbl *util.JSONTable) error {
 	tbl, err := TableStatsFromJSON(tableInfo, physicalID, jsonTbl)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err := s.saveColumnsAndIndicesToStorage(tbl); err != nil {
+		return errors.Trace(err)
+	}
+	err = s.SaveExtendedStatsToStorage(tbl.PhysicalID, tbl.ExtendedStats, true)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = s.SaveColumnStatsUsageToStorage(tbl.PhysicalID, jsonTbl.PredicateColumns)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = util.CallWithSCtx(s.statsHandler.SPool(), func(sctx sessionctx.Context) error {
+		if err := SaveInitedExtendedStatsFromJSON(sctx, tbl.PhysicalID, jsonTbl.ExtStats); err != nil {
+			return err
+		}
+		return SaveAnalyzeOptionsFromJSON(sctx, tbl.PhysicalID, jsonTbl.AnalyzeOptions)
+	}, util.FlagWrapTxn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return s.SaveMetaToStorage(tbl.PhysicalID, tbl.RealtimeCount, tbl.ModifyCount, util.StatsMetaHistorySourceLoadStats)
+}
 
+// saveColumnsAndIndicesToStorage saves the stats of the columns and the indices of the table to the storage.
+func (s *statsReadWriter) saveColumnsAndIndicesToStorage(tbl *statistics.Table) error {
 	var outerErr error
 	tbl.ForEachColumnImmutable(func(_ int64, col *statistics.Column) bool {
-		// loadStatsFromJSON doesn't support partition table now.
-		// The table level count and modify_count would be overridden by the SaveMetaToStorage below, so we don't need
-		// to care about them here.
+		// The table level count and modify_count would be overridden by the SaveMetaToStorage of the callers, so we
+		// don't need to care about them here.
 		if err := s.SaveStatsToStorage(tbl.PhysicalID, tbl.RealtimeCount, 0, 0, &col.Histogram, col.CMSketch, col.TopN, int(col.GetStatsVer()), statistics.AnalyzeFlag, false, util.StatsMetaHistorySourceLoadStats); err != nil {
 			outerErr = err
 			return true
@@ -634,27 +941,15 @@ func (s *statsReadWriter) loadStatsFromJSON(tableInfo *model.TableInfo, physical
 		return outerErr
 	}
 	tbl.ForEachIndexImmutable(func(_ int64, idx *statistics.Index) bool {
-		// loadStatsFromJSON doesn't support partition table now.
-		// The table level count and modify_count would be overridden by the SaveMetaToStorage below, so we don't need
-		// to care about them here.
+		// The table level count and modify_count would be overridden by the SaveMetaToStorage of the callers, so we
+		// don't need to care about them here.
 		if err := s.SaveStatsToStorage(tbl.PhysicalID, tbl.RealtimeCount, 0, 1, &idx.Histogram, idx.CMSketch, idx.TopN, int(idx.GetStatsVer()), statistics.AnalyzeFlag, false, util.StatsMetaHistorySourceLoadStats); err != nil {
 			outerErr = err
 			return true
 		}
 		return false
 	})
-	if outerErr != nil {
-		return outerErr
-	}
-	err = s.SaveExtendedStatsToStorage(tbl.PhysicalID, tbl.ExtendedStats, true)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	err = s.SaveColumnStatsUsageToStorage(tbl.PhysicalID, jsonTbl.PredicateColumns)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	return s.SaveMetaToStorage(tbl.PhysicalID, tbl.RealtimeCount, tbl.ModifyCount, util.StatsMetaHistorySourceLoadStats)
+	return outerErr
 }
 
 // SaveColumnStatsUsageToStorage saves column statistics usage information for a table into mysql.column_stats_usage.
@@ -81,3 +81,47 @@ func TestUpdateStatsMetaVersionForGC(t *testing.T) {
 	).Rows()
 	require.Equal(t, 1, len(rows))
 }
+
+func TestInjectStats(t *testing.T) {
+	store, do := testkit.CreateMockStoreAndDomain(t)
+	testKit := testkit.NewTestKit(t, store)
+	h := do.StatsHandle()
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t (a int, b int)")
+	testKit.MustExec("insert into t values (1, 1), (2, 2)")
+	testKit.MustExec("analyze table t")
+	tbl, err := do.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := tbl.Meta()
+	colA, colB := tableInfo.Columns[0].ID, tableInfo.Columns[1].ID
+
+	testKit.MustExec("alter table t modify stats set (row_count = 1000, col_ndv('a') = 100, null_ratio('b') = 0.2)")
+	require.NoError(t, h.Update(context.Background(), do.InfoSchema()))
+	statsTbl := h.GetTableStats(tableInfo)
+	require.Equal(t, int64(1000), statsTbl.RealtimeCount)
+	require.Equal(t, int64(0), statsTbl.ModifyCount)
+	require.Equal(t, int64(100), statsTbl.GetCol(colA).Histogram.NDV)
+	require.Equal(t, int64(200), statsTbl.GetCol(colB).NullCount)
+	testKit.MustQuery("select json_extract(stats, '$.row_count') from mysql.stats_injected where table_id = ?", tableInfo.ID).Check(testkit.Rows("1000"))
+
+	// The injected stats are merged into the ones injected before.
+	testKit.MustExec("alter table t modify stats set (null_ratio('a') = 0.5)")
+	require.NoError(t, h.Update(context.Background(), do.InfoSchema()))
+	statsTbl = h.GetTableStats(tableInfo)
+	require.Equal(t, int64(1000), statsTbl.RealtimeCount)
+	require.Equal(t, int64(100), statsTbl.GetCol(colA).Histogram.NDV)
+	require.Equal(t, int64(500), statsTbl.GetCol(colA).NullCount)
+	require.Equal(t, int64(200), statsTbl.GetCol(colB).NullCount)
+
+	testKit.MustGetErrMsg("alter table t modify stats set (col_ndv('c') = 1)", "[schema:1054]Unknown column 'c' in 't'")
+	testKit.MustGetErrMsg("alter table t modify stats set (null_ratio('a') = 1.5)", "Modify Stats: NULL_RATIO should be between 0 and 1")
+	testKit.MustGetErrMsg("alter table t modify stats set (row_count = 1.5)", "Modify Stats: ROW_COUNT should be an integer")
+
+	// The injected stats don't survive the analyze.
+	testKit.MustExec("analyze table t")
+	require.NoError(t, h.Update(context.Background(), do.InfoSchema()))
+	statsTbl = h.GetTableStats(tableInfo)
+	require.Equal(t, int64(2), statsTbl.RealtimeCount)
+	require.Equal(t, int64(2), statsTbl.GetCol(colA).Histogram.NDV)
+	testKit.MustQuery("select count(*) from mysql.stats_injected").Check(testkit.Rows("0"))
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics/handle/cache"
 	"github.com/pingcap/tidb/pkg/statistics/handle/types"
 	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
+	"github.com/pingcap/tidb/pkg/util/sqlescape"
 )
 
 // UpdateStatsVersion will set statistics version to the newest TS, then
@@ -74,20 +75,129 @@ func UpdateStatsMeta(
 	} else {
 		if delta.Delta < 0 {
 			// use INSERT INTO ... ON DUPLICATE KEY UPDATE here to fill missing stats_meta.
-			_, err = statsutil.ExecWithCtx(ctx, sctx, "insert into mysql.stats_meta (version, table_id, modify_count, count) values (%?, %?, %?, 0) on duplicate key "+
-				"update version = values(version), modify_count = modify_count + values(modify_count), count = if(count > %?, count - %?, 0)",
-				startTS, id, delta.Count, -delta.Delta, -delta.Delta)
+			_, err = statsutil.ExecWithCtx(ctx, sctx, "insert into mysql.stats_meta (version, table_id, modify_count, count, insert_count, delete_count) values (%?, %?, %?, 0, %?, %?) on duplicate key "+
+				"update version = values(version), modify_count = modify_count + values(modify_count), count = if(count > %?, count - %?, 0), "+
+				"insert_count = insert_count + values(insert_count), delete_count = delete_count + values(delete_count)",
+				startTS, id, delta.Count, delta.InsertCount, delta.DeleteCount, -delta.Delta, -delta.Delta)
 		} else {
 			// use INSERT INTO ... ON DUPLICATE KEY UPDATE here to fill missing stats_meta.
-			_, err = statsutil.ExecWithCtx(ctx, sctx, "insert into mysql.stats_meta (version, table_id, modify_count, count) values (%?, %?, %?, %?) on duplicate key "+
-				"update version = values(version), modify_count = modify_count + values(modify_count), count = count + values(count)", startTS,
-				id, delta.Count, delta.Delta)
+			_, err = statsutil.ExecWithCtx(ctx, sctx, "insert into mysql.stats_meta (version, table_id, modify_count, count, insert_count, delete_count) values (%?, %?, %?, %?, %?, %?) on duplicate key "+
+				"update version = values(version), modify_count = modify_count + values(modify_count), count = count + values(count), "+
+				"insert_count = insert_count + values(insert_count), delete_count = delete_count + values(delete_count)", startTS,
+				id, delta.Count, delta.Delta, delta.InsertCount, delta.DeleteCount)
 		}
 		cache.TableRowStatsCache.Invalidate(id)
 	}
 	return err
 }
 
+// ResetStatsMetaRowCount sets the row count of the table to the exact count and resets its modify count, as if the
+// table were just analyzed. It's used by the row-count-only fast path of the tiny tables.
+func ResetStatsMetaRowCount(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	version uint64,
+	id int64,
+	count int64,
+) error {
+	_, err := statsutil.ExecWithCtx(ctx, sctx, "update mysql.stats_meta set version = %?, count = %?, modify_count = 0 where table_id = %?",
+		version, count, id)
+	cache.TableRowStatsCache.Invalidate(id)
+	return err
+}
+
+// DeltaUpdate is the stats meta delta of a table or partition to be dumped to storage.
+type DeltaUpdate struct {
+	Delta    variable.TableDelta
+	TableID  int64
+	IsLocked bool
+}
+
+// UpdateStatsMetaInBatch updates the stats meta of multiple tables with multi-row inserts.
+// It has the same semantics as calling UpdateStatsMeta for each of the updates,
+// the caller should make sure that the table IDs of the updates are unique.
+func UpdateStatsMetaInBatch(
+	ctx context.Context,
+	sctx sessionctx.Context,
+	startTS uint64,
+	updates ...DeltaUpdate,
+) error {
+	var locked, increased, decreased []DeltaUpdate
+	for _, update := range updates {
+		switch {
+		case update.IsLocked:
+			locked = append(locked, update)
+		case update.Delta.Delta < 0:
+			decreased = append(decreased, update)
+		default:
+			increased = append(increased, update)
+		}
+	}
+	if len(locked) > 0 {
+		// Note: For locked tables, it is possible that the record gets deleted. So it can be negative.
+		sql := new(strings.Builder)
+		sqlescape.MustFormatSQL(sql, "insert into mysql.stats_table_locked (version, table_id, modify_count, count) values ")
+		for i, update := range locked {
+			if i > 0 {
+				sqlescape.MustFormatSQL(sql, ",")
+			}
+			sqlescape.MustFormatSQL(sql, "(%?, %?, %?, %?)", startTS, update.TableID, update.Delta.Count, update.Delta.Delta)
+		}
+		sqlescape.MustFormatSQL(sql, " on duplicate key update version = values(version), "+
+			"modify_count = modify_count + values(modify_count), count = count + values(count)")
+		if _, err := statsutil.ExecWithCtx(ctx, sctx, sql.String()); err != nil {
+			return err
+		}
+	}
+	if len(increased) > 0 {
+		sql := new(strings.Builder)
+		sqlescape.MustFormatSQL(sql, "insert into mysql.stats_meta (version, table_id, modify_count, count, insert_count, delete_count) values ")
+		for i, update := range increased {
+			if i > 0 {
+				sqlescape.MustFormatSQL(sql, ",")
+			}
+			sqlescape.MustFormatSQL(sql, "(%?, %?, %?, %?, %?, %?)", startTS, update.TableID, update.Delta.Count, update.Delta.Delta,
+				update.Delta.InsertCount, update.Delta.DeleteCount)
+		}
+		sqlescape.MustFormatSQL(sql, " on duplicate key update version = values(version), "+
+			"modify_count = modify_count + values(modify_count), count = count + values(count), "+
+			"insert_count = insert_count + values(insert_count), delete_count = delete_count + values(delete_count)")
+		if _, err := statsutil.ExecWithCtx(ctx, sctx, sql.String()); err != nil {
+			return err
+		}
+	}
+	if len(decreased) > 0 {
+		// The count column is unsigned, so the decreased count can't be carried by values(count).
+		// Use a CASE expression to subtract the count of each table instead.
+		sql := new(strings.Builder)
+		sqlescape.MustFormatSQL(sql, "insert into mysql.stats_meta (version, table_id, modify_count, count, insert_count, delete_count) values ")
+		for i, update := range decreased {
+			if i > 0 {
+				sqlescape.MustFormatSQL(sql, ",")
+			}
+			sqlescape.MustFormatSQL(sql, "(%?, %?, %?, 0, %?, %?)", startTS, update.TableID, update.Delta.Count,
+				update.Delta.InsertCount, update.Delta.DeleteCount)
+		}
+		sqlescape.MustFormatSQL(sql, " on duplicate key update version = values(version), "+
+			"modify_count = modify_count + values(modify_count), "+
+			"insert_count = insert_count + values(insert_count), delete_count = delete_count + values(delete_count), count = case table_id")
+		for _, update := range decreased {
+			sqlescape.MustFormatSQL(sql, " when %? then if(count > %?, count - %?, 0)", update.TableID, -update.Delta.Delta, -update.Delta.Delta)
+		}
+		sqlescape.MustFormatSQL(sql, " else count end")
+		if _, err := statsutil.ExecWithCtx(ctx, sctx, sql.String()); err != nil {
+			return err
+		}
+	}
+	for _, update := range increased {
+		cache.TableRowStatsCache.Invalidate(update.TableID)
+	}
+	for _, update := range decreased {
+		cache.TableRowStatsCache.Invalidate(update.TableID)
+	}
+	return nil
+}
+
 // DumpTableStatColSizeToKV dumps the column size stats to storage.
 func DumpTableStatColSizeToKV(sctx sessionctx.Context, id int64, delta variable.TableDelta) error {
 	if len(delta.ColSize) == 0 {
@@ -135,7 +245,8 @@ func InsertExtendedStats(sctx sessionctx.Context,
 			}
 			return 0, errors.Errorf("extended statistics '%s' for the specified table already exists", statsName)
 		}
-		if tp == int(currTp) && currStrColIDs == strColIDs {
+		// The correlations collected for the composite indexes don't prevent users from creating their own ones.
+		if tp == int(currTp) && currStrColIDs == strColIDs && !statistics.IsIndexColCorrelation(currStatsName) {
 			return 0, errors.Errorf("extended statistics '%s' with same type on same columns already exists", statsName)
 		}
 	}
@@ -184,9 +295,10 @@ func SaveExtendedStatsToStorage(sctx sessionctx.Context,
 		strColIDs := string(bytes)
 		var statsStr string
 		switch item.Tp {
-		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
+		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation, ast.StatsTypeDependency:
 			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
+		case ast.StatsTypeJSONPath, ast.StatsTypeForeignKey, ast.StatsTypeVector, ast.StatsTypeTrigram, ast.StatsTypeHistogram,
+			ast.StatsTypeIndexPrefixTopN:
 			statsStr = item.StringVals
 		}
 		// If isLoad is true, it's INSERT; otherwise, it's UPDATE.
@@ -0,0 +1,101 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/statistics"
+	"github.com/pingcap/tidb/pkg/statistics/handle/util"
+)
+
+const (
+	// verifyMinRowCount is the min row count of a table whose stats are verified. The stats of the small tables are
+	// cheap to rebuild, and their row counts and NDVs change drastically by nature.
+	verifyMinRowCount = 1000
+	// verifyMaxRowCountRatio bounds the ratio between the row count of the new stats and the current one maintained by
+	// the DML deltas.
+	verifyMaxRowCountRatio = 10
+	// verifyMinNDV is the min NDV of the existing stats of a column or an index whose new NDV is verified.
+	verifyMinNDV = 10
+)
+
+// VerifyAnalyzeResults checks the stats built by ANALYZE against the existing ones of the table before they are
+// saved, and returns the current row count of the table and the reasons why the new stats are suspicious, which are
+// empty if they pass the verification. The new stats are suspicious if:
+//  1. The row count is out of the plausible bounds of the current one, which is maintained by the DML deltas.
+//  2. The NDV of a column or an index collapses to 1, while it's not a small one in the existing stats. It usually
+//     comes from a bug of the sampling rather than the data.
+func VerifyAnalyzeResults(sctx sessionctx.Context, results *statistics.AnalyzeResults) (curCnt int64, reasons []string, err error) {
+	tableID := results.TableID.GetStatisticsID()
+	rows, _, err := util.ExecRows(sctx, "select count from mysql.stats_meta where table_id = %?", tableID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(rows) == 0 {
+		return 0, nil, nil
+	}
+	curCnt = int64(rows[0].GetUint64(0))
+	if curCnt < verifyMinRowCount {
+		return curCnt, nil, nil
+	}
+	// The row count of the multi-valued index or global index results is not the table row count.
+	if !results.ForMVIndexOrGlobalIndex &&
+		(results.Count*verifyMaxRowCountRatio < curCnt || results.Count > curCnt*verifyMaxRowCountRatio) {
+		reasons = append(reasons, fmt.Sprintf("the row count %d is out of the plausible bounds of the current row count %d", results.Count, curCnt))
+	}
+	if results.Count < verifyMinRowCount {
+		return curCnt, reasons, nil
+	}
+	rows, _, err = util.ExecRows(sctx,
+		"select is_index, hist_id, distinct_count from mysql.stats_histograms where table_id = %? and stats_ver > 0", tableID)
+	if err != nil {
+		return 0, nil, err
+	}
+	// oldNDVs[isIndex] maps the column or index ID to its NDV in the existing stats.
+	oldNDVs := [2]map[int64]int64{make(map[int64]int64), make(map[int64]int64)}
+	for _, row := range rows {
+		oldNDVs[row.GetInt64(0)][row.GetInt64(1)] = row.GetInt64(2)
+	}
+	for _, result := range results.Ars {
+		tp := "column"
+		if result.IsIndex == 1 {
+			tp = "index"
+		}
+		for _, hg := range result.Hist {
+			if oldNDV := oldNDVs[result.IsIndex][hg.ID]; hg.NDV == 1 && oldNDV >= verifyMinNDV {
+				reasons = append(reasons, fmt.Sprintf("the NDV of the %s %d collapses from %d to 1", tp, hg.ID, oldNDV))
+			}
+		}
+	}
+	return curCnt, reasons, nil
+}
+
+// QuarantineAnalyzeResults records the stats built by ANALYZE which fail the verification in
+// mysql.analyze_quarantine for review.
+func QuarantineAnalyzeResults(sctx sessionctx.Context, results *statistics.AnalyzeResults, curCnt int64, reasons []string) error {
+	var dbName, tableName, partitionName, jobInfo string
+	if job := results.Job; job != nil {
+		dbName, tableName, partitionName, jobInfo = job.DBName, job.TableName, job.PartitionName, job.JobInfo
+	}
+	_, err := util.Exec(sctx, `insert into mysql.analyze_quarantine
+		(table_schema, table_name, partition_name, table_id, job_info, old_count, new_count, reason)
+		values (%?, %?, %?, %?, %?, %?, %?, %?)`,
+		dbName, tableName, partitionName, results.TableID.GetStatisticsID(), jobInfo, curCnt, results.Count,
+		strings.Join(reasons, "; "))
+	return err
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncload
+
+import (
+	"sync"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"golang.org/x/sync/singleflight"
+)
+
+// loadRequests coalesces the sync-load requests of the same column or index from the concurrent statements, so that
+// only one task is sent to the workers for them and all the statements wait for its result in the shared wait queue.
+// Since the full stats contain the meta of the histogram, a request of the full stats is also shared by the ones
+// only needing the meta.
+type loadRequests struct {
+	mu sync.Mutex
+	// waiters records the result channels of the statements waiting for the in-flight requests, in arrival order.
+	waiters map[string][]chan singleflight.Result
+}
+
+func newLoadRequests() *loadRequests {
+	return &loadRequests{waiters: make(map[string][]chan singleflight.Result)}
+}
+
+// join adds a waiter for the item and returns its result channel. It returns true if there is already an in-flight
+// request which can be shared, otherwise a new request is registered and the caller must send the task for it and
+// call finish when it's done.
+func (r *loadRequests) join(item model.StatsLoadItem) (<-chan singleflight.Result, bool) {
+	ch := make(chan singleflight.Result, 1)
+	key := item.Key()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !item.FullLoad {
+		fullKey := model.StatsLoadItem{TableItemID: item.TableItemID, FullLoad: true}.Key()
+		if waiters, ok := r.waiters[fullKey]; ok {
+			r.waiters[fullKey] = append(waiters, ch)
+			return ch, true
+		}
+	}
+	waiters, ok := r.waiters[key]
+	r.waiters[key] = append(waiters, ch)
+	return ch, ok
+}
+
+// finish removes the request of the item and sends the result to all its waiters.
+func (r *loadRequests) finish(item model.StatsLoadItem, result singleflight.Result) {
+	key := item.Key()
+	r.mu.Lock()
+	waiters := r.waiters[key]
+	delete(r.waiters, key)
+	r.mu.Unlock()
+	result.Shared = len(waiters) > 1
+	for _, ch := range waiters {
+		ch <- result
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
+	utilstats "github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/table"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util"
@@ -65,7 +66,7 @@ type statsSyncLoad struct {
 	StatsLoad   statstypes.StatsLoad
 }
 
-var globalStatsSyncLoadSingleFlight singleflight.Group
+var globalLoadRequests = newLoadRequests()
 
 // NewStatsSyncLoad creates a new StatsSyncLoad.
 func NewStatsSyncLoad(is infoschema.InfoSchema, statsHandle statstypes.StatsHandle) statstypes.StatsSyncLoad {
@@ -86,6 +87,8 @@ type statsWrapper struct {
 // SendLoadRequests send neededColumns requests
 func (s *statsSyncLoad) SendLoadRequests(sc *stmtctx.StatementContext, neededHistItems []model.StatsLoadItem, timeout time.Duration) error {
 	remainedItems := s.removeHistLoadedColumns(neededHistItems)
+	metrics.StatsLoadItemCounter.WithLabelValues("sync", "hit").Add(float64(len(neededHistItems) - len(remainedItems)))
+	metrics.StatsLoadItemCounter.WithLabelValues("sync", "miss").Add(float64(len(remainedItems)))
 
 	failpoint.Inject("assertSyncLoadItems", func(val failpoint.Value) {
 		if sc.OptimizeTracer != nil {
@@ -101,32 +104,56 @@ func (s *statsSyncLoad) SendLoadRequests(sc *stmtctx.StatementContext, neededHis
 	sc.StatsLoad.Timeout = timeout
 	sc.StatsLoad.NeededItems = remainedItems
 	sc.StatsLoad.ResultCh = make([]<-chan singleflight.Result, 0, len(remainedItems))
+	// The quota limits the number of the tasks of this statement in the queue, so that the tasks of the other
+	// statements can be handled in the meantime. It's released when the task is done.
+	var quota chan struct{}
+	if limit := variable.StatsLoadMaxItemsPerQuery.Load(); limit > 0 && int64(len(remainedItems)) > limit {
+		quota = make(chan struct{}, limit)
+	}
 	for _, item := range remainedItems {
-		localItem := item
-		resultCh := globalStatsSyncLoadSingleFlight.DoChan(localItem.Key(), func() (any, error) {
-			timer := time.NewTimer(timeout)
-			defer timer.Stop()
-			task := &statstypes.NeededItemTask{
-				Item:      localItem,
-				ToTimeout: time.Now().Local().Add(timeout),
-				ResultCh:  make(chan stmtctx.StatsLoadResult, 1),
-			}
-			select {
-			case s.StatsLoad.NeededItemsCh <- task:
-				metrics.SyncLoadDedupCounter.Inc()
-				result, ok := <-task.ResultCh
-				intest.Assert(ok, "task.ResultCh cannot be closed")
-				return result, nil
-			case <-timer.C:
-				return nil, errors.New("sync load stats channel is full and timeout sending task to channel")
-			}
-		})
+		resultCh, shared := globalLoadRequests.join(item)
+		if shared {
+			metrics.SyncLoadDedupCounter.Inc()
+		} else {
+			go func(item model.StatsLoadItem) {
+				globalLoadRequests.finish(item, s.sendLoadTask(item, timeout, quota))
+			}(item)
+		}
 		sc.StatsLoad.ResultCh = append(sc.StatsLoad.ResultCh, resultCh)
 	}
 	sc.StatsLoad.LoadStartTime = time.Now()
 	return nil
 }
 
+// sendLoadTask sends the task of the item to the workers and waits for its result.
+func (s *statsSyncLoad) sendLoadTask(item model.StatsLoadItem, timeout time.Duration, quota chan struct{}) singleflight.Result {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	task := &statstypes.NeededItemTask{
+		Item:      item,
+		ToTimeout: time.Now().Local().Add(timeout),
+		ResultCh:  make(chan stmtctx.StatsLoadResult, 1),
+	}
+	if quota != nil {
+		select {
+		case quota <- struct{}{}:
+			defer func() { <-quota }()
+		case <-timer.C:
+			return singleflight.Result{Err: errors.New("sync load stats timeout waiting for the other items of the statement")}
+		}
+	}
+	task.EnqueueTime = time.Now()
+	select {
+	case s.StatsLoad.NeededItemsCh <- task:
+		s.observeQueueDepth()
+		result, ok := <-task.ResultCh
+		intest.Assert(ok, "task.ResultCh cannot be closed")
+		return singleflight.Result{Val: result}
+	case <-timer.C:
+		return singleflight.Result{Err: errors.New("sync load stats channel is full and timeout sending task to channel")}
+	}
+}
+
 // SyncWaitStatsLoad sync waits loading of neededColumns and return false if timeout
 func (*statsSyncLoad) SyncWaitStatsLoad(sc *stmtctx.StatementContext) error {
 	if len(sc.StatsLoad.NeededItems) <= 0 {
@@ -261,6 +288,8 @@ func (s *statsSyncLoad) HandleOneTask(sctx sessionctx.Context, lastTask *statsty
 			}
 			return task, err
 		}
+		s.observeQueueDepth()
+		metrics.SyncLoadWaitHistogram.Observe(float64(time.Since(task.EnqueueTime).Milliseconds()))
 	} else {
 		task = lastTask
 	}
@@ -284,7 +313,9 @@ func isVaildForRetry(task *statstypes.NeededItemTask) bool {
 }
 
 func (s *statsSyncLoad) handleOneItemTask(task *statstypes.NeededItemTask) (err error) {
-	se, err := s.statsHandle.SPool().Get()
+	// The sync loads block the optimization of the queries, so they take the sessions before the other operations.
+	pool := s.statsHandle.PrioritizedSPool(utilstats.SessionPriorityHigh)
+	se, err := pool.Get()
 	if err != nil {
 		return err
 	}
@@ -298,9 +329,12 @@ func (s *statsSyncLoad) handleOneItemTask(task *statstypes.NeededItemTask) (err
 		}
 		if err == nil { // only recycle when no error
 			sctx.GetSessionVars().StmtCtx.Priority = mysql.NoPriority
-			s.statsHandle.SPool().Put(se)
+			pool.Put(se)
+		} else {
+			utilstats.DiscardSession(pool)
 		}
 	}()
+	defer utilstats.SetStaleReadForStats(sctx)()
 	var skipTypes map[string]struct{}
 	val, err := sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBAnalyzeSkipColumnTypes)
 	if err != nil {
@@ -526,11 +560,23 @@ func (s *statsSyncLoad) drainColTask(sctx sessionctx.Context, exit chan struct{}
 	}
 }
 
+// observeQueueDepth updates the metrics of the number of tasks in the queues.
+func (s *statsSyncLoad) observeQueueDepth() {
+	metrics.SyncLoadQueueGauge.WithLabelValues("needed").Set(float64(len(s.StatsLoad.NeededItemsCh)))
+	metrics.SyncLoadQueueGauge.WithLabelValues("timeout").Set(float64(len(s.StatsLoad.TimeoutItemsCh)))
+}
+
 // writeToTimeoutChan writes in a nonblocking way, and if the channel queue is full, it's ok to drop the task.
-func (*statsSyncLoad) writeToTimeoutChan(taskCh chan *statstypes.NeededItemTask, task *statstypes.NeededItemTask) {
+// The dropped task is finished with an error, so that the statements waiting for it are not blocked, and a new task
+// can be sent for the item later.
+func (s *statsSyncLoad) writeToTimeoutChan(taskCh chan *statstypes.NeededItemTask, task *statstypes.NeededItemTask) {
 	select {
 	case taskCh <- task:
 	default:
+		s.writeToResultChan(task.ResultCh, stmtctx.StatsLoadResult{
+			Item:  task.Item.TableItemID,
+			Error: errors.New("sync load stats timeout queue is full and the task is dropped"),
+		})
 	}
 }
 
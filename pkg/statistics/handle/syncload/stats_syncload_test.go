@@ -365,3 +365,69 @@ func TestRetry(t *testing.T) {
 	}
 	require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/pkg/statistics/handle/syncload/mockReadStatsForOneFail"))
 }
+
+func TestSyncLoadMaxItemsPerQuery(t *testing.T) {
+	originConfig := config.GetGlobalConfig()
+	newConfig := config.NewConfig()
+	newConfig.Performance.StatsLoadConcurrency = -1 // no worker to consume channel
+	config.StoreGlobalConfig(newConfig)
+	defer config.StoreGlobalConfig(originConfig)
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+
+	testKit := testkit.NewTestKit(t, store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t(a int, b int)")
+	testKit.MustExec("insert into t values (1,1),(2,2),(3,3)")
+	testKit.MustExec("analyze table t all columns")
+	testKit.MustExec("set @@global.tidb_stats_load_max_items_per_query = 1")
+	defer testKit.MustExec("set @@global.tidb_stats_load_max_items_per_query = default")
+
+	is := dom.InfoSchema()
+	tbl, err := is.TableByName(context.Background(), pmodel.NewCIStr("test"), pmodel.NewCIStr("t"))
+	require.NoError(t, err)
+	tableInfo := tbl.Meta()
+	h := dom.StatsHandle()
+	h.Clear()
+	require.NoError(t, h.Update(context.Background(), is))
+
+	neededColumns := make([]model.StatsLoadItem, 0, len(tableInfo.Columns))
+	for _, col := range tableInfo.Columns {
+		neededColumns = append(neededColumns, model.StatsLoadItem{TableItemID: model.TableItemID{TableID: tableInfo.ID, ID: col.ID, IsIndex: false}, FullLoad: true})
+	}
+	stmtCtx := stmtctx.NewStmtCtx()
+	require.NoError(t, h.SendLoadRequests(stmtCtx, neededColumns, 100*time.Millisecond))
+	require.Len(t, stmtCtx.StatsLoad.ResultCh, 2)
+	// No worker handles the tasks, so only one of them is sent to the queue, and the other one times out waiting for
+	// the quota of the statement.
+	var errMsgs []string
+	for _, resultCh := range stmtCtx.StatsLoad.ResultCh {
+		select {
+		case rs := <-resultCh:
+			require.Error(t, rs.Err)
+			errMsgs = append(errMsgs, rs.Err.Error())
+		case <-time.After(time.Second):
+		}
+	}
+	require.Equal(t, []string{"sync load stats timeout waiting for the other items of the statement"}, errMsgs)
+
+	// The meta-only request of the column in the queue shares the in-flight full load request.
+	neededColumns[0].FullLoad = false
+	neededColumns[1].FullLoad = false
+	stmtCtx2 := stmtctx.NewStmtCtx()
+	require.NoError(t, h.SendLoadRequests(stmtCtx2, neededColumns, time.Second))
+	exitCh := make(chan struct{})
+	// One task is for the in-flight full load request, and the other one is sent by the second statement.
+	for i := 0; i < 2; i++ {
+		task, err := h.HandleOneTask(testKit.Session().(sessionctx.Context), nil, exitCh)
+		require.NoError(t, err)
+		require.Nil(t, task)
+	}
+	var shared int
+	for _, resultCh := range stmtCtx2.StatsLoad.ResultCh {
+		rs := <-resultCh
+		if rs.Shared {
+			shared++
+		}
+	}
+	require.Equal(t, 1, shared)
+}
@@ -16,6 +16,7 @@ package types
 
 import (
 	"context"
+	"io"
 	"sync"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle/usage/indexusage"
 	statsutil "github.com/pingcap/tidb/pkg/statistics/handle/util"
@@ -48,6 +50,10 @@ type StatsGC interface {
 	// DeleteTableStatsFromKV deletes table statistics from kv.
 	// A statsID refers to statistic of a table or a partition.
 	DeleteTableStatsFromKV(statsIDs []int64) (err error)
+
+	// DeleteColumnStatsFromKV deletes the statistics of the columns from kv and removes them from the stats cache.
+	// A statsID refers to statistic of a table or a partition.
+	DeleteColumnStatsFromKV(statsIDs []int64, colIDs []int64) (err error)
 }
 
 // ColStatsTimeInfo records usage information of this column stats.
@@ -63,9 +69,15 @@ type StatsUsage interface {
 	// LoadColumnStatsUsage returns all columns' usage information.
 	LoadColumnStatsUsage(loc *time.Location) (map[model.TableItemID]ColStatsTimeInfo, error)
 
+	// LoadColumnStatsUsageForTables returns the usage information of the columns of the tables.
+	LoadColumnStatsUsageForTables(loc *time.Location, tableIDs []int64) (map[model.TableItemID]ColStatsTimeInfo, error)
+
 	// GetPredicateColumns returns IDs of predicate columns, which are the columns whose stats are used(needed) when generating query plans.
 	GetPredicateColumns(tableID int64) ([]int64, error)
 
+	// ResetColumnStatsUsage clears the predicate columns of the tables, so that they are collected from scratch.
+	ResetColumnStatsUsage(tableIDs []int64) error
+
 	// CollectColumnsInExtendedStats returns IDs of the columns involved in extended stats.
 	CollectColumnsInExtendedStats(tableID int64) ([]int64, error)
 
@@ -84,6 +96,9 @@ type StatsUsage interface {
 	// DumpStatsDeltaToKV sweeps the whole list and updates the global map, then we dumps every table that held in map to KV.
 	DumpStatsDeltaToKV(dumpAll bool) error
 
+	// DumpStatsDeltaInterval returns the interval before the next stats delta dump, adapted to the volume of the last dump.
+	DumpStatsDeltaInterval(baseInterval time.Duration) time.Duration
+
 	// DumpColStatsUsageToKV sweeps the whole list, updates the column stats usage map and dumps it to KV.
 	DumpColStatsUsageToKV() error
 }
@@ -138,11 +153,156 @@ type AnalysisJobJSON struct {
 	HasNewlyAddedIndex bool              `json:"has_newly_added_index"`
 }
 
+// RunningAnalysisJobJSON represents the JSON format of an AnalysisJob which is being run by the auto analyze worker.
+type RunningAnalysisJobJSON struct {
+	AnalysisJobJSON
+	StartTime time.Time `json:"start_time"`
+}
+
+// AutoAnalyzeJobOutcome is the outcome of an auto analyze job recorded in mysql.analyze_jobs.
+type AutoAnalyzeJobOutcome struct {
+	StartTime     *time.Time `json:"start_time,omitempty"`
+	EndTime       *time.Time `json:"end_time,omitempty"`
+	Schema        string     `json:"schema"`
+	Table         string     `json:"table"`
+	PartitionName string     `json:"partition_name,omitempty"`
+	JobInfo       string     `json:"job_info"`
+	// State is one of pending, running, finished and failed.
+	State         string `json:"state"`
+	FailReason    string `json:"fail_reason,omitempty"`
+	Instance      string `json:"instance"`
+	ID            uint64 `json:"id"`
+	ProcessedRows uint64 `json:"processed_rows"`
+}
+
 // IndicatorsJSON represents the JSON format of Indicators.
 type IndicatorsJSON struct {
-	ChangePercentage     string `json:"change_percentage"`
-	TableSize            string `json:"table_size"`
-	LastAnalysisDuration string `json:"last_analysis_duration"`
+	ChangePercentage     string  `json:"change_percentage"`
+	ShrinkPercentage     string  `json:"shrink_percentage"`
+	EstimationError      string  `json:"estimation_error"`
+	PseudoStatsQueries   int64   `json:"pseudo_stats_queries,omitempty"`
+	VerifiedDrift        float64 `json:"verified_drift,omitempty"`
+	ErrorBudgetBurnRate  float64 `json:"error_budget_burn_rate,omitempty"`
+	TableSize            string  `json:"table_size"`
+	LastAnalysisDuration string  `json:"last_analysis_duration"`
+}
+
+// TableStatsSummary is the summary of the stats of a table or a partition. It's small enough to be fetched by the
+// other components, like TiFlash, to size their operators by the cardinalities instead of the static defaults.
+type TableStatsSummary struct {
+	PhysicalID int64 `json:"physical_id"`
+	// Version is the version of the stats, by which the fetchers can tell whether their copies are outdated.
+	Version     uint64 `json:"version"`
+	RowCount    int64  `json:"row_count"`
+	ModifyCount int64  `json:"modify_count"`
+	// Pseudo indicates the table has no stats, and the other fields except the row count are meaningless.
+	Pseudo  bool                 `json:"pseudo"`
+	Columns []ColumnStatsSummary `json:"columns"`
+	Indexes []ColumnStatsSummary `json:"indexes"`
+}
+
+// TableStatsHealth is the health of the stats of a table or a partition, which is the same as SHOW STATS_HEALTHY
+// shows, along with the reason why the stats are considered unusable by the optimizer.
+type TableStatsHealth struct {
+	DBName    string `json:"db_name"`
+	TableName string `json:"table_name"`
+	// PartitionName is "global" for the global stats of the partitioned table, and empty for the non-partitioned table.
+	PartitionName string `json:"partition_name,omitempty"`
+	PhysicalID    int64  `json:"physical_id"`
+	// Healthy is meaningless if the stats are pseudo.
+	Healthy     int64 `json:"healthy"`
+	Pseudo      bool  `json:"pseudo"`
+	RowCount    int64 `json:"row_count"`
+	ModifyCount int64 `json:"modify_count"`
+	// LastAnalyzeTime is nil if the table has never been analyzed.
+	LastAnalyzeTime *time.Time `json:"last_analyze_time,omitempty"`
+	// UnusableReason is empty if the stats are usable, see statistics.Table.GetStatsUnusableReason.
+	UnusableReason string `json:"unusable_reason,omitempty"`
+}
+
+// ColumnStatsSummary is the summary of the stats of a column or an index. Only the analyzed ones are summarized.
+type ColumnStatsSummary struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	NDV        int64  `json:"ndv"`
+	NullCount  int64  `json:"null_count"`
+	TotColSize int64  `json:"tot_col_size"`
+}
+
+// TableIndicators contains the indicators of a table or a partition, which are used by the
+// auto analyze priority queue to evaluate the analysis priority.
+// External schedulers can use them to build their own analyze scheduling or alerting.
+type TableIndicators struct {
+	DBName    string `json:"db_name"`
+	TableName string `json:"table_name"`
+	// PartitionName is empty for the non-partitioned table and the global stats of the partitioned table.
+	PartitionName string `json:"partition_name,omitempty"`
+	PhysicalID    int64  `json:"physical_id"`
+	// Analyzed indicates whether the table has been analyzed.
+	// LastAnalysisDuration is meaningless if the table has not been analyzed.
+	Analyzed bool `json:"analyzed"`
+	// ChangePercentage is the ratio of the modified rows to the row count of the last analysis.
+	ChangePercentage float64 `json:"change_percentage"`
+	// ShrinkPercentage is the ratio of the removed rows to the row count of the last analysis.
+	ShrinkPercentage float64 `json:"shrink_percentage"`
+	// InsertCount and DeleteCount are the numbers of the rows inserted and deleted since the last analysis.
+	InsertCount int64 `json:"insert_count"`
+	DeleteCount int64 `json:"delete_count"`
+	// EstimationError is the geometric mean of the q-errors of the row count estimations since the last analysis.
+	EstimationError float64 `json:"estimation_error"`
+	// PseudoStatsQueries is the number of the statements estimated by the pseudo stats of the table which has never
+	// been analyzed.
+	PseudoStatsQueries int64 `json:"pseudo_stats_queries"`
+	// VerifiedDrift is how much the stats drift from the data, which is verified by the stats probe.
+	VerifiedDrift float64 `json:"verified_drift"`
+	// TableSize is the table size in rows * len(columns).
+	TableSize float64 `json:"table_size"`
+	// LastAnalysisDuration is the duration from the last analysis to now, encoded in nanoseconds in JSON.
+	LastAnalysisDuration time.Duration `json:"last_analysis_duration"`
+	// WorkloadClass is the workload class of the table, which decides the default analyze options of auto analyze
+	// if tidb_enable_workload_aware_analyze is on.
+	WorkloadClass string `json:"workload_class"`
+}
+
+// AnalyzeDatabaseTableResult is the result of a table analyzed by the ANALYZE DATABASE statement.
+type AnalyzeDatabaseTableResult struct {
+	TableName string
+	// PartitionName is only set when the partition is analyzed as a separate table in static prune mode.
+	PartitionName string
+	// Status is one of finished, failed and skipped.
+	Status   string
+	Message  string
+	Duration time.Duration
+}
+
+// AnalysisJobOptions is the options of the analysis job enqueued by EnqueueAnalysisJob.
+type AnalysisJobOptions struct {
+	// Weight is the priority of the job, the job with a higher weight is analyzed earlier.
+	// Zero means the weight is calculated in the same way as other auto analyze jobs.
+	Weight float64
+	// AnalyzeDefaults is the default analyze options used to analyze the table, which are inherited from the session
+	// enqueuing the job. Nil means the default analyze options of the internal session are used.
+	AnalyzeDefaults *variable.AnalyzeDefaults
+}
+
+// AnalysisJobOption sets an option of the analysis job enqueued by EnqueueAnalysisJob.
+type AnalysisJobOption func(*AnalysisJobOptions)
+
+// WithAnalysisJobWeight sets the weight of the enqueued analysis job.
+func WithAnalysisJobWeight(weight float64) AnalysisJobOption {
+	return func(opts *AnalysisJobOptions) {
+		opts.Weight = weight
+	}
+}
+
+// WithInheritedAnalyzeDefaults makes the enqueued analysis job inherit the default analyze options of the session,
+// i.e. tidb_analyze_default_sample_rate, tidb_analyze_default_buckets, tidb_analyze_default_topn and
+// tidb_analyze_default_column_choice, as if the ANALYZE statement were issued in the session.
+func WithInheritedAnalyzeDefaults(vars *variable.SessionVars) AnalysisJobOption {
+	return func(opts *AnalysisJobOptions) {
+		defaults := vars.AnalyzeDefaults
+		opts.AnalyzeDefaults = &defaults
+	}
 }
 
 // StatsAnalyze is used to handle auto-analyze and manage analyze jobs.
@@ -191,6 +351,46 @@ type StatsAnalyze interface {
 	// GetPriorityQueueSnapshot returns the stats priority queue.
 	GetPriorityQueueSnapshot() (PriorityQueueSnapshot, error)
 
+	// GetRunningAnalysisJobs returns the auto analyze jobs being run by the current instance.
+	GetRunningAnalysisJobs() []RunningAnalysisJobJSON
+
+	// GetAutoAnalyzeJobOutcomes returns the latest auto analyze jobs recorded in mysql.analyze_jobs by all the
+	// instances, in the descending order of their IDs. If the schema or the table is empty, the jobs are not
+	// filtered by it. At most limit jobs are returned.
+	GetAutoAnalyzeJobOutcomes(schema, table string, limit int) ([]AutoAnalyzeJobOutcome, error)
+
+	// GetTableIndicators returns the indicators of the tables and partitions.
+	// If the schema is empty, all the user tables are returned.
+	// If the table is empty, all the tables of the schema are returned.
+	GetTableIndicators(schema, table string) ([]TableIndicators, error)
+
+	// AnalyzeDatabase analyzes all tables of the schema through the priority queue analysis jobs.
+	// At most concurrency tables are analyzed at the same time, and no more tables are analyzed
+	// once the budget is used up. Zero concurrency means using tidb_auto_analyze_concurrency,
+	// zero budget means no limit.
+	AnalyzeDatabase(schema string, concurrency int, budget time.Duration) ([]AnalyzeDatabaseTableResult, error)
+
+	// EnqueueAnalysisJob pushes the analysis job of the table or partition into the auto analyze priority queue,
+	// no matter whether its stats are outdated. It is used by the components which need to request analyze
+	// without issuing SQL. It returns an error if the priority queue is not initialized, e.g. the current
+	// instance is not the stats owner.
+	EnqueueAnalysisJob(physicalID int64, opts ...AnalysisJobOption) error
+
+	// RemoveAnalysisJobs removes the pending analysis jobs of the tables or partitions from the auto analyze
+	// priority queue, and returns the number of the removed jobs. It doesn't prevent the tables from being
+	// pushed into the queue again later. It returns an error if the priority queue is not initialized.
+	RemoveAnalysisJobs(physicalIDs []int64) (int, error)
+
+	// MigrateStatsVersion re-analyzes one of the tables which still have the version 1 stats with the version 2 stats,
+	// and records the progress in mysql.stats_version_migration. It does nothing if tidb_analyze_version is not 2
+	// or it's out of the auto analyze time window.
+	MigrateStatsVersion(is infoschema.InfoSchema) error
+
+	// CheckStatsAlerts records the tables whose change percentages or estimation errors exceed
+	// tidb_stats_alert_stale_ratio or tidb_stats_alert_estimation_error_threshold in mysql.stats_alerts,
+	// although auto analyze is enabled for them.
+	CheckStatsAlerts() error
+
 	// Close closes the analyze worker.
 	Close()
 }
@@ -238,7 +438,7 @@ type StatsCache interface {
 	// Replace replaces this cache.
 	Replace(cache StatsCache)
 
-	// UpdateStatsHealthyMetrics updates stats healthy distribution metrics according to stats cache.
+	// UpdateStatsHealthyMetrics updates stats healthy distribution and resident memory metrics according to stats cache.
 	UpdateStatsHealthyMetrics()
 }
 
@@ -302,6 +502,28 @@ type PartitionStatisticLoadTask struct {
 // PersistFunc is used to persist JSONTable in the partition level.
 type PersistFunc func(ctx context.Context, jsonTable *statsutil.JSONTable, physicalID int64) error
 
+// StatsLoadBenchItem is the result of loading the stats of one column or index from the storage.
+// It is used by ADMIN BENCH STATS LOAD.
+type StatsLoadBenchItem struct {
+	Name        string
+	PhysicalID  int64
+	IsIndex     bool
+	Buckets     int
+	TopNs       int
+	LoadTime    time.Duration
+	MemoryUsage int64
+}
+
+// InjectedStats is the statistics injected manually by ALTER TABLE ... MODIFY STATS.
+type InjectedStats struct {
+	// RowCount is the injected row count of the table. It's nil if the row count is not injected.
+	RowCount *int64 `json:"row_count,omitempty"`
+	// ColNDVs are the injected NDVs of the columns, keyed by the column IDs.
+	ColNDVs map[int64]int64 `json:"col_ndvs,omitempty"`
+	// NullRatios are the injected fractions of the NULL values of the columns, keyed by the column IDs.
+	NullRatios map[int64]float64 `json:"null_ratios,omitempty"`
+}
+
 // StatsReadWriter is used to read and write stats to the storage.
 // TODO: merge and remove some methods.
 type StatsReadWriter interface {
@@ -314,6 +536,10 @@ type StatsReadWriter interface {
 	// StatsMetaCountAndModifyCount reads count and modify_count for the given table from mysql.stats_meta.
 	StatsMetaCountAndModifyCount(tableID int64) (count, modifyCount int64, err error)
 
+	// BenchLoadStats loads the stats of all the columns and indices of the table from storage without updating the
+	// stats cache, and reports the load time and memory usage of each of them.
+	BenchLoadStats(tableInfo *model.TableInfo, physicalID int64) ([]*StatsLoadBenchItem, error)
+
 	// LoadNeededHistograms will load histograms for those needed columns/indices and put them into the cache.
 	LoadNeededHistograms(is infoschema.InfoSchema) (err error)
 
@@ -324,19 +550,32 @@ type StatsReadWriter interface {
 	SaveStatsToStorage(tableID int64, count, modifyCount int64, isIndex int, hg *statistics.Histogram,
 		cms *statistics.CMSketch, topN *statistics.TopN, statsVersion int, isAnalyzed int64, updateAnalyzeTime bool, source string) (err error)
 
-	// SaveTableStatsToStorage saves the stats of a table to storage.
+	// SaveTableStatsToStorage saves the stats of a table to storage. The stats built by ANALYZE are quarantined
+	// instead if they fail the verification of tidb_analyze_verify_stats.
 	SaveTableStatsToStorage(results *statistics.AnalyzeResults, analyzeSnapshot bool, source string) (err error)
 
 	// SaveMetaToStorage saves the stats meta of a table to storage.
 	SaveMetaToStorage(tableID, count, modifyCount int64, source string) (err error)
 
+	// InjectStats writes the manually injected stats of a table or a partition to storage. The injected stats are
+	// recorded in mysql.stats_injected and survive until the next analyze.
+	InjectStats(physicalID int64, stats *InjectedStats) (err error)
+
 	// InsertColStats2KV inserts columns stats to kv.
 	InsertColStats2KV(physicalID int64, colInfos []*model.ColumnInfo) (err error)
 
+	// ModifyColStats2KV converts the stats of the columns whose types are changed losslessly to the new columns,
+	// and inserts the stats of the other modified columns to kv.
+	ModifyColStats2KV(physicalID int64, newColInfos, oldColInfos []*model.ColumnInfo) (err error)
+
 	// InsertTableStats2KV inserts a record standing for a new table to stats_meta and inserts some records standing for the
 	// new columns and indices which belong to this table.
 	InsertTableStats2KV(info *model.TableInfo, physicalID int64) (err error)
 
+	// ResetTableStats2KV resets the stats of the physical table to the empty stats of a new table, which is used for
+	// the tables recreated by TRUNCATE TABLE.
+	ResetTableStats2KV(info *model.TableInfo, physicalID int64) (err error)
+
 	// UpdateStatsVersion will set statistics version to the newest TS,
 	// then tidb-server will reload automatic.
 	UpdateStatsVersion() error
@@ -392,6 +631,10 @@ type StatsReadWriter interface {
 	// DumpStatsToJSONBySnapshot dumps statistic to json.
 	DumpStatsToJSONBySnapshot(dbName string, tableInfo *model.TableInfo, snapshot uint64, dumpPartitionStats bool) (*statsutil.JSONTable, error)
 
+	// HistoricalTableStats loads the stats of the table at the given version from mysql.stats_meta_history
+	// and mysql.stats_history. The second return value is false if there is no historical stats before the version.
+	HistoricalTableStats(tableInfo *model.TableInfo, physicalID int64, version uint64) (*statistics.Table, bool, error)
+
 	// PersistStatsBySnapshot dumps statistic to json and call the function for each partition statistic to persist.
 	// Notice:
 	//  1. It might call the function `persist` with nil jsontable.
@@ -408,6 +651,19 @@ type StatsReadWriter interface {
 	// LoadStatsFromJSONNoUpdate will load statistic from JSONTable, and save it to the storage.
 	LoadStatsFromJSONNoUpdate(ctx context.Context, is infoschema.InfoSchema, jsonTbl *statsutil.JSONTable, concurrencyForPartition int) error
 
+	// DumpStatsToBinary dumps statistic to w in the binary format.
+	// The stats of the partitions are dumped one by one, so the memory usage doesn't grow with the number of partitions.
+	DumpStatsToBinary(w io.Writer, dbName string, tableInfo *model.TableInfo, snapshot uint64, dumpPartitionStats bool) error
+
+	// MergeStatsFromJSON merges the partial stats in the JSONTable, which are built on the rows not counted by the
+	// stats of the table, e.g. by an external job, into the stats of the table and saves them to the storage.
+	// In final, it will also update the stats cache.
+	MergeStatsFromJSON(ctx context.Context, is infoschema.InfoSchema, jsonTbl *statsutil.JSONTable) error
+
+	// LoadStatsFromBinary will load statistic dumped by DumpStatsToBinary from r, and save it to the storage.
+	// In final, it will also update the stats cache.
+	LoadStatsFromBinary(ctx context.Context, is infoschema.InfoSchema, r io.Reader, concurrencyForPartition int) error
+
 	// Methods for extended stast.
 
 	// InsertExtendedStats inserts a record into mysql.stats_extended and update version in mysql.stats_meta.
@@ -423,9 +679,11 @@ type StatsReadWriter interface {
 // NeededItemTask represents one needed column/indices with expire time.
 type NeededItemTask struct {
 	ToTimeout time.Time
-	ResultCh  chan stmtctx.StatsLoadResult
-	Item      model.StatsLoadItem
-	Retry     int
+	// EnqueueTime is the time when the task is sent to the queue, it's used to observe the waiting time.
+	EnqueueTime time.Time
+	ResultCh    chan stmtctx.StatsLoadResult
+	Item        model.StatsLoadItem
+	Retry       int
 }
 
 // StatsLoad is used to load stats concurrently
@@ -491,13 +749,20 @@ type StatsGlobal interface {
 		info *GlobalStatsInfo,
 		physicalID int64,
 	) (err error)
+
+	// EnqueueGlobalStatsMergeJob persists a job which merges the partition-level stats into the global-level stats
+	// in the background.
+	EnqueueGlobalStatsMergeJob(tableID int64, info *GlobalStatsInfo, opts map[ast.AnalyzeOptionType]uint64) error
+
+	// MergePendingGlobalStats executes the pending global stats merge jobs.
+	MergePendingGlobalStats(is infoschema.InfoSchema) error
 }
 
 // DDL is used to handle ddl events.
 type DDL interface {
-	// HandleDDLEvent handles ddl events.
+	// HandleDDLEvent makes sure the ddl event has been handled. The events are delivered by the DDL notifier.
 	HandleDDLEvent(changeEvent *notifier.SchemaChangeEvent) error
-	// DDLEventCh returns ddl events channel in handle.
+	// DDLEventCh returns ddl events channel in handle, which only receives the events in tests.
 	DDLEventCh() chan *notifier.SchemaChangeEvent
 }
 
@@ -527,6 +792,12 @@ type StatsHandle interface {
 	// GetPartitionStatsForAutoAnalyze retrieves the partition stats from cache, but it will not return pseudo.
 	GetPartitionStatsForAutoAnalyze(tblInfo *model.TableInfo, pid int64) *statistics.Table
 
+	// GetTableStatsSummary returns the summary of the cached stats of the table or the partition.
+	GetTableStatsSummary(is infoschema.InfoSchema, physicalID int64) (*TableStatsSummary, error)
+
+	// GetTableStatsHealth returns the health of the cached stats of the table and its partitions.
+	GetTableStatsHealth(dbName string, tblInfo *model.TableInfo) []TableStatsHealth
+
 	// StatsGC is used to do the GC job.
 	StatsGC
 
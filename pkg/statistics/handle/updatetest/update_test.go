@@ -1289,3 +1289,40 @@ func TestAutoAnalyzePartitionTableAfterAddingIndex(t *testing.T) {
 	}, 3*time.Second, time.Millisecond*100)
 	require.NotNil(t, h.GetTableStats(tblInfo).GetIdx(idxInfo.ID))
 }
+
+func TestTinyTableRowCountFastPath(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	oriMinCnt := statistics.AutoAnalyzeMinCnt
+	defer func() {
+		statistics.AutoAnalyzeMinCnt = oriMinCnt
+		tk.MustExec("set global tidb_stats_tiny_table_threshold = default")
+	}()
+	statistics.AutoAnalyzeMinCnt = 0
+	tk.MustExec("set global tidb_stats_tiny_table_threshold = 5")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int)")
+	h := dom.StatsHandle()
+	require.NoError(t, h.HandleDDLEvent(<-h.DDLEventCh()))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tblInfo := tbl.Meta()
+
+	// The row count of the tiny table is refreshed by the dump, and its modify count is reset.
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("delete from t where a = 3")
+	tk.MustExec("insert into t values (4, 4)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	tk.MustQuery(fmt.Sprintf("select count, modify_count from mysql.stats_meta where table_id = %d", tblInfo.ID)).Check(testkit.Rows("3 0"))
+	// It's never analyzed automatically.
+	require.False(t, h.HandleAutoAnalyze())
+	require.False(t, h.GetTableStats(tblInfo).IsAnalyzed())
+
+	// The table is no longer tiny after the delta.
+	tk.MustExec("insert into t values (5, 5), (6, 6), (7, 7)")
+	require.NoError(t, h.DumpStatsDeltaToKV(true))
+	require.NoError(t, h.Update(context.Background(), dom.InfoSchema()))
+	tk.MustQuery(fmt.Sprintf("select count, modify_count from mysql.stats_meta where table_id = %d", tblInfo.ID)).Check(testkit.Rows("6 3"))
+}
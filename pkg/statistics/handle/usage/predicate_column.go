@@ -15,6 +15,8 @@
 package usage
 
 import (
+	"maps"
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/tidb/pkg/meta/model"
@@ -34,6 +36,9 @@ type statsUsageImpl struct {
 
 	// SessionStatsList contains all the stats collector required by session.
 	*SessionStatsList
+
+	// lastDumpedDeltaCount is the total modify count dumped by the last DumpStatsDeltaToKV.
+	lastDumpedDeltaCount atomic.Int64
 }
 
 // NewStatsUsageImpl creates a statstypes.StatsUsage.
@@ -53,6 +58,29 @@ func (u *statsUsageImpl) LoadColumnStatsUsage(loc *time.Location) (colStatsMap m
 	return
 }
 
+// LoadColumnStatsUsageForTables returns the usage information of the columns of the tables.
+func (u *statsUsageImpl) LoadColumnStatsUsageForTables(loc *time.Location, tableIDs []int64) (colStatsMap map[model.TableItemID]statstypes.ColStatsTimeInfo, err error) {
+	colStatsMap = make(map[model.TableItemID]statstypes.ColStatsTimeInfo)
+	err = utilstats.CallWithSCtx(u.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		for _, tableID := range tableIDs {
+			tableColStatsMap, err := predicatecolumn.LoadColumnStatsUsageForTable(sctx, loc, tableID)
+			if err != nil {
+				return err
+			}
+			maps.Copy(colStatsMap, tableColStatsMap)
+		}
+		return nil
+	})
+	return
+}
+
+// ResetColumnStatsUsage clears the predicate columns of the tables.
+func (u *statsUsageImpl) ResetColumnStatsUsage(tableIDs []int64) error {
+	return utilstats.CallWithSCtx(u.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		return predicatecolumn.ResetColumnStatsUsage(sctx, tableIDs)
+	}, utilstats.FlagWrapTxn)
+}
+
 // GetPredicateColumns returns IDs of predicate columns, which are the columns whose stats are used(needed) when generating query plans.
 func (u *statsUsageImpl) GetPredicateColumns(tableID int64) (columnIDs []int64, err error) {
 	err = utilstats.CallWithSCtx(u.statsHandle.SPool(), func(sctx sessionctx.Context) error {
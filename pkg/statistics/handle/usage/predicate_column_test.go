@@ -16,6 +16,7 @@ package usage_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/pingcap/tidb/pkg/parser/model"
@@ -299,3 +300,86 @@ func TestAnalyzeNoPredicateColumnsWithPrimaryKey(t *testing.T) {
 		testkit.Rows("t analyze table all indexes, columns a, b with 256 buckets, 100 topn, 1 samplerate"),
 	)
 }
+
+func TestPredicateColumnRetention(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3)")
+	tk.MustQuery("select * from t where a > 1").Check(testkit.Rows("2 2 2", "3 3 3"))
+	tk.MustQuery("select * from t where b > 1").Check(testkit.Rows("2 2 2", "3 3 3"))
+	h := dom.StatsHandle()
+	require.NoError(t, h.DumpColStatsUsageToKV())
+
+	is := dom.InfoSchema()
+	tbl, err := is.TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	tblInfo := tbl.Meta()
+	// Column b is not used for 10 days.
+	tk.MustExec("update mysql.column_stats_usage set last_used_at = date_sub(now(), interval 10 day) where table_id = ? and column_id = ?",
+		tblInfo.ID, tblInfo.Columns[1].ID)
+	columns, err := h.GetPredicateColumns(tblInfo.ID)
+	require.NoError(t, err)
+	require.Len(t, columns, 2)
+
+	tk.MustExec("set global tidb_predicate_column_retention_days = 7")
+	defer tk.MustExec("set global tidb_predicate_column_retention_days = default")
+	columns, err = h.GetPredicateColumns(tblInfo.ID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{tblInfo.Columns[0].ID}, columns)
+	tk.MustQuery("admin show column_stats_usage for table t").Sort().CheckAt([]int{3, 6}, testkit.Rows("a 1", "b 0"))
+
+	tk.MustExec("set global tidb_analyze_column_options = 'PREDICATE'")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select table_name, job_info from mysql.analyze_jobs order by id desc limit 1").Check(
+		testkit.Rows("t analyze table column a with 256 buckets, 100 topn, 1 samplerate"),
+	)
+
+	// Column b becomes a predicate column again once it's used.
+	tk.MustQuery("select * from t where b > 1").Check(testkit.Rows("2 2 2", "3 3 3"))
+	require.NoError(t, h.DumpColStatsUsageToKV())
+	columns, err = h.GetPredicateColumns(tblInfo.ID)
+	require.NoError(t, err)
+	require.Len(t, columns, 2)
+}
+
+func TestAdminResetColumnStatsUsage(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, c int) partition by hash(a) partitions 2")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3)")
+	tk.MustQuery("select * from t where a > 1").Sort().Check(testkit.Rows("2 2 2", "3 3 3"))
+	tk.MustExec("set global tidb_analyze_column_options = 'PREDICATE'")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select * from t where b > 1").Sort().Check(testkit.Rows("2 2 2", "3 3 3"))
+
+	// The usage collected in memory is dumped by ADMIN SHOW COLUMN_STATS_USAGE.
+	var predicateColumns []string
+	for _, row := range tk.MustQuery("admin show column_stats_usage for table t").Sort().Rows() {
+		if row[6] == "1" {
+			predicateColumns = append(predicateColumns, fmt.Sprintf("%v %v", row[2], row[3]))
+		}
+	}
+	require.Equal(t, []string{"global a", "global b"}, predicateColumns)
+
+	tk.MustExec("admin reset column_stats_usage for table t")
+	// The last analyzed time is kept while the predicate columns are cleared, and the records of the columns never
+	// analyzed are deleted.
+	rows := tk.MustQuery("admin show column_stats_usage for table t").Rows()
+	require.NotEmpty(t, rows)
+	for _, row := range rows {
+		require.Equal(t, "a", row[3])
+		require.Equal(t, "<nil>", row[4])
+		require.NotEqual(t, "<nil>", row[5])
+		require.Equal(t, "0", row[6])
+	}
+	tbl, err := dom.InfoSchema().TableByName(context.Background(), model.NewCIStr("test"), model.NewCIStr("t"))
+	require.NoError(t, err)
+	columns, err := dom.StatsHandle().GetPredicateColumns(tbl.Meta().ID)
+	require.NoError(t, err)
+	require.Empty(t, columns)
+}
@@ -23,8 +23,10 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/pkg/infoschema"
 	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics"
 	statstypes "github.com/pingcap/tidb/pkg/statistics/handle/types"
 	utilstats "github.com/pingcap/tidb/pkg/statistics/handle/util"
@@ -80,17 +82,20 @@ func LoadColumnStatsUsageForTable(sctx sessionctx.Context, loc *time.Location, t
 }
 
 // GetPredicateColumns returns IDs of predicate columns, which are the columns whose stats are used(needed) when generating query plans.
+// The columns not used for more than tidb_predicate_column_retention_days are not treated as predicate columns any more.
 func GetPredicateColumns(sctx sessionctx.Context, tableID int64) ([]int64, error) {
 	// Each time we retrieve the predicate columns, we also attempt to remove any column stats usage information whose column is dropped.
 	err := cleanupDroppedColumnStatsUsage(sctx, tableID)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	rows, _, err := utilstats.ExecRows(
-		sctx,
-		"SELECT column_id, CONVERT_TZ(last_used_at, @@TIME_ZONE, '+00:00') FROM mysql.column_stats_usage WHERE table_id = %? AND last_used_at IS NOT NULL",
-		tableID,
-	)
+	query := "SELECT column_id, CONVERT_TZ(last_used_at, @@TIME_ZONE, '+00:00') FROM mysql.column_stats_usage WHERE table_id = %? AND last_used_at IS NOT NULL"
+	args := []any{tableID}
+	if retentionDays := variable.PredicateColumnRetentionDays.Load(); retentionDays > 0 {
+		query += " AND last_used_at >= DATE_SUB(NOW(), INTERVAL %? DAY)"
+		args = append(args, retentionDays)
+	}
+	rows, _, err := utilstats.ExecRows(sctx, query, args...)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -107,6 +112,32 @@ func GetPredicateColumns(sctx sessionctx.Context, tableID int64) ([]int64, error
 	return columnIDs, nil
 }
 
+// ResetColumnStatsUsage clears the predicate columns of the tables, so that they are collected from scratch.
+// The last analyzed time of the columns is kept, and the records without it are deleted.
+func ResetColumnStatsUsage(sctx sessionctx.Context, tableIDs []int64) error {
+	if len(tableIDs) == 0 {
+		return nil
+	}
+	// Due to SQL limitations, table IDs must be converted to strings for proper escaping in the query.
+	ids := make([]string, 0, len(tableIDs))
+	for _, id := range tableIDs {
+		ids = append(ids, fmt.Sprintf("%d", id))
+	}
+	if _, _, err := utilstats.ExecRows(
+		sctx,
+		"DELETE FROM mysql.column_stats_usage WHERE table_id IN (%?) AND last_analyzed_at IS NULL",
+		ids,
+	); err != nil {
+		return errors.Trace(err)
+	}
+	_, _, err := utilstats.ExecRows(
+		sctx,
+		"UPDATE mysql.column_stats_usage SET last_used_at = NULL WHERE table_id IN (%?)",
+		ids,
+	)
+	return errors.Trace(err)
+}
+
 // cleanupDroppedColumnStatsUsage deletes the column stats usage information whose column is dropped.
 func cleanupDroppedColumnStatsUsage(sctx sessionctx.Context, tableID int64) error {
 	is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
@@ -162,8 +193,11 @@ func SaveColumnStatsUsageForTable(
 
 // CollectColumnsInExtendedStats returns IDs of the columns involved in extended stats.
 func CollectColumnsInExtendedStats(sctx sessionctx.Context, tableID int64) ([]int64, error) {
-	const sql = "SELECT name, type, column_ids FROM mysql.stats_extended WHERE table_id = %? and status in (%?, %?)"
-	rows, _, err := utilstats.ExecRows(sctx, sql, tableID, statistics.ExtendedStatsAnalyzed, statistics.ExtendedStatsInited)
+	// The JSON path stats, the trigram stats and the index prefix TopN are maintained by ANALYZE for the analyzed
+	// columns and the indexes, so they don't need to be collected.
+	const sql = "SELECT name, type, column_ids FROM mysql.stats_extended WHERE table_id = %? and status in (%?, %?) and type not in (%?, %?, %?)"
+	rows, _, err := utilstats.ExecRows(sctx, sql, tableID, statistics.ExtendedStatsAnalyzed, statistics.ExtendedStatsInited,
+		ast.StatsTypeJSONPath, ast.StatsTypeTrigram, ast.StatsTypeIndexPrefixTopN)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -28,10 +28,10 @@ import (
 	"github.com/pingcap/tidb/pkg/metrics"
 	"github.com/pingcap/tidb/pkg/sessionctx"
 	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/statistics"
 	"github.com/pingcap/tidb/pkg/statistics/handle/storage"
 	utilstats "github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/types"
-	"github.com/pingcap/tidb/pkg/util"
 	"github.com/pingcap/tidb/pkg/util/intest"
 	"github.com/pingcap/tidb/pkg/util/sqlescape"
 )
@@ -44,10 +44,15 @@ var (
 
 	// batchInsertSize is the batch size used by internal SQL to insert values to some system table.
 	batchInsertSize = 10
+
+	// dumpStatsDeltaBatchSize is the max number of tables and partitions whose deltas are dumped in one transaction.
+	dumpStatsDeltaBatchSize = 256
+	// dumpStatsDeltaHighVolume is the modify count of a dump above which the dump interval is shortened.
+	dumpStatsDeltaHighVolume int64 = 100000
 )
 
 // needDumpStatsDelta checks whether to dump stats delta.
-// 1. If the table doesn't exist or is a mem table or system table, then return false.
+// 1. If the table doesn't exist or is a mem table or system table not opted in to auto analyze, then return false.
 // 2. If the mode is DumpAll, then return true.
 // 3. If the stats delta haven't been dumped in the past hour, then return true.
 // 4. If the table stats is pseudo or empty or `Modify Count / Table Count` exceeds the threshold.
@@ -60,7 +65,7 @@ func (s *statsUsageImpl) needDumpStatsDelta(is infoschema.InfoSchema, dumpAll bo
 	if !ok {
 		return false
 	}
-	if util.IsMemOrSysDB(dbInfo.Name.L) {
+	if !utilstats.IsAutoAnalyzeTable(dbInfo.Name.L, tbl.Meta().Name.L) {
 		return false
 	}
 	if dumpAll {
@@ -83,6 +88,8 @@ func (s *statsUsageImpl) needDumpStatsDelta(is infoschema.InfoSchema, dumpAll bo
 
 // DumpStatsDeltaToKV sweeps the whole list and updates the global map, then we dumps every table that held in map to KV.
 // If the mode is `DumpDelta`, it will only dump that delta info that `Modify Count / Table Count` greater than a ratio.
+// The deltas are dumped in batches, and the deltas of the partitions of the same table are always dumped
+// in the same transaction, so that the global-stats only need to be updated once.
 func (s *statsUsageImpl) DumpStatsDeltaToKV(dumpAll bool) error {
 	start := time.Now()
 	defer func() {
@@ -91,6 +98,10 @@ func (s *statsUsageImpl) DumpStatsDeltaToKV(dumpAll bool) error {
 	}()
 	s.SweepSessionStatsList()
 	deltaMap := s.SessionTableDelta().GetDeltaAndReset()
+	if readOnly, _ := utilstats.StatsReadOnly(); readOnly {
+		// The stats read-only instance never writes the stats, so the delta is dropped.
+		return nil
+	}
 	defer func() {
 		s.SessionTableDelta().Merge(deltaMap)
 	}()
@@ -98,84 +109,104 @@ func (s *statsUsageImpl) DumpStatsDeltaToKV(dumpAll bool) error {
 	return utilstats.CallWithSCtx(s.statsHandle.SPool(), func(sctx sessionctx.Context) error {
 		is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
 		currentTime := time.Now()
+		parentIDs := make(map[int64]int64, len(deltaMap))
+		var dumpedCount int64
 		for id, item := range deltaMap {
 			if !s.needDumpStatsDelta(is, dumpAll, id, item, currentTime) {
 				continue
 			}
-			updated, err := s.dumpTableStatCountToKV(is, id, item)
-			if err != nil {
-				return errors.Trace(err)
-			}
-			if updated {
-				UpdateTableDeltaMap(deltaMap, id, -item.Delta, -item.Count, nil)
+			parentID := id
+			if tbl, _, _ := is.FindTableByPartitionID(id); tbl != nil {
+				parentID = tbl.Meta().ID
 			}
-			if err = storage.DumpTableStatColSizeToKV(sctx, id, item); err != nil {
-				delete(deltaMap, id)
+			parentIDs[id] = parentID
+			dumpedCount += item.Count
+		}
+		s.lastDumpedDeltaCount.Store(dumpedCount)
+		for _, batch := range groupStatsDeltaBatches(parentIDs, dumpStatsDeltaBatchSize) {
+			if err := s.dumpStatsDeltaBatchToKV(deltaMap, parentIDs, batch); err != nil {
 				return errors.Trace(err)
 			}
-			if updated {
-				delete(deltaMap, id)
-			} else {
-				m := deltaMap[id]
-				m.ColSize = nil
-				deltaMap[id] = m
-			}
 		}
 		return nil
 	})
 }
 
-// dumpTableStatDeltaToKV dumps a single delta with some table to KV and updates the version.
-// For a partitioned table, we will update its global-stats as well.
-func (s *statsUsageImpl) dumpTableStatCountToKV(is infoschema.InfoSchema, physicalTableID int64, delta variable.TableDelta) (updated bool, err error) {
-	statsVersion := uint64(0)
-	defer func() {
-		if err == nil && statsVersion != 0 {
-			s.statsHandle.RecordHistoricalStatsMeta(physicalTableID, statsVersion, "flush stats", false)
+// groupStatsDeltaBatches groups the physical table IDs into batches of at most batchSize IDs.
+// The IDs sharing the same parent table ID are never split into different batches.
+func groupStatsDeltaBatches(parentIDs map[int64]int64, batchSize int) [][]int64 {
+	groups := make(map[int64][]int64, len(parentIDs))
+	for id, parentID := range parentIDs {
+		groups[parentID] = append(groups[parentID], id)
+	}
+	parents := make([]int64, 0, len(groups))
+	for parentID, ids := range groups {
+		slices.Sort(ids)
+		parents = append(parents, parentID)
+	}
+	slices.Sort(parents)
+
+	var batches [][]int64
+	var batch []int64
+	for _, parentID := range parents {
+		ids := groups[parentID]
+		if len(batch) > 0 && len(batch)+len(ids) > batchSize {
+			batches = append(batches, batch)
+			batch = nil
 		}
-	}()
-	if delta.Count == 0 {
-		return true, nil
+		batch = append(batch, ids...)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
 	}
+	return batches
+}
 
-	err = utilstats.CallWithSCtx(s.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+// dumpStatsDeltaBatchToKV dumps the deltas of a batch of tables and partitions to KV in a single transaction.
+// For a partitioned table, the deltas of its partitions are coalesced to update its global-stats as well.
+// The dumped deltas are removed from the deltaMap once the transaction is committed.
+func (s *statsUsageImpl) dumpStatsDeltaBatchToKV(
+	deltaMap map[int64]variable.TableDelta,
+	parentIDs map[int64]int64,
+	physicalIDs []int64,
+) error {
+	statsVersion := uint64(0)
+	err := utilstats.CallWithSCtx(s.statsHandle.SPool(), func(sctx sessionctx.Context) error {
+		var err error
 		statsVersion, err = utilstats.GetStartTS(sctx)
 		if err != nil {
 			return errors.Trace(err)
 		}
 
-		tbl, _, _ := is.FindTableByPartitionID(physicalTableID)
-		// Check if the table and its partitions are locked.
-		tidAndPid := make([]int64, 0, 2)
-		if tbl != nil {
-			tidAndPid = append(tidAndPid, tbl.Meta().ID)
+		// Check if the tables and their partitions are locked.
+		tableIDs := make([]int64, 0, len(physicalIDs)*2)
+		for _, id := range physicalIDs {
+			tableIDs = append(tableIDs, id)
+			if parentIDs[id] != id {
+				tableIDs = append(tableIDs, parentIDs[id])
+			}
 		}
-		tidAndPid = append(tidAndPid, physicalTableID)
-		lockedTables, err := s.statsHandle.GetLockedTables(tidAndPid...)
+		lockedTables, err := s.statsHandle.GetLockedTables(tableIDs...)
 		if err != nil {
 			return err
 		}
 
-		var affectedRows uint64
-		// If it's a partitioned table and its global-stats exists,
-		// update its count and modify_count as well.
-		if tbl != nil {
-			// We need to check if the table and the partition are locked.
-			isTableLocked := false
-			isPartitionLocked := false
-			tableID := tbl.Meta().ID
-			if _, ok := lockedTables[tableID]; ok {
-				isTableLocked = true
-			}
-			if _, ok := lockedTables[physicalTableID]; ok {
-				isPartitionLocked = true
+		updates := make([]storage.DeltaUpdate, 0, len(physicalIDs))
+		globalUpdates := make(map[int64]int, len(physicalIDs))
+		for _, id := range physicalIDs {
+			delta := deltaMap[id]
+			if delta.Count == 0 {
+				continue
 			}
-			tableOrPartitionLocked := isTableLocked || isPartitionLocked
-			if err = storage.UpdateStatsMeta(utilstats.StatsCtx, sctx, statsVersion, delta,
-				physicalTableID, tableOrPartitionLocked); err != nil {
-				return err
+			tableID := parentIDs[id]
+			_, isPartitionLocked := lockedTables[id]
+			if tableID == id {
+				// This is a non-partitioned table.
+				updates = append(updates, storage.DeltaUpdate{Delta: delta, TableID: id, IsLocked: isPartitionLocked})
+				continue
 			}
-			affectedRows += sctx.GetSessionVars().StmtCtx.AffectedRows()
+			_, isTableLocked := lockedTables[tableID]
+			updates = append(updates, storage.DeltaUpdate{Delta: delta, TableID: id, IsLocked: isTableLocked || isPartitionLocked})
 			// If the partition is locked, we don't need to update the global-stats.
 			// We will update its global-stats when the partition is unlocked.
 			// 1. If table is locked and partition is locked, we only stash the delta in the partition's lock info.
@@ -187,37 +218,132 @@ func (s *statsUsageImpl) dumpTableStatCountToKV(is infoschema.InfoSchema, physic
 			//    we will update its global-stats when the partition is unlocked.
 			// 4. If table is not locked and partition is not locked, we update the global-stats.
 			// To sum up, we only need to update the global-stats when the table and the partition are not locked.
-			if !isTableLocked && !isPartitionLocked {
-				// If it's a partitioned table and its global-stats exists, update its count and modify_count as well.
-				if err = storage.UpdateStatsMeta(utilstats.StatsCtx, sctx, statsVersion, delta, tableID, isTableLocked); err != nil {
-					return err
-				}
-				affectedRows += sctx.GetSessionVars().StmtCtx.AffectedRows()
+			if isTableLocked || isPartitionLocked {
+				continue
 			}
-		} else {
-			// This is a non-partitioned table.
-			// Check if it's locked.
-			isTableLocked := false
-			if _, ok := lockedTables[physicalTableID]; ok {
-				isTableLocked = true
+			// Coalesce the deltas of the partitions into a single update of the global-stats.
+			if idx, ok := globalUpdates[tableID]; ok {
+				updates[idx].Delta.Delta += delta.Delta
+				updates[idx].Delta.Count += delta.Count
+				updates[idx].Delta.InsertCount += delta.InsertCount
+				updates[idx].Delta.DeleteCount += delta.DeleteCount
+				continue
 			}
-			if err = storage.UpdateStatsMeta(utilstats.StatsCtx, sctx, statsVersion, delta,
-				physicalTableID, isTableLocked); err != nil {
+			globalUpdates[tableID] = len(updates)
+			updates = append(updates, storage.DeltaUpdate{
+				Delta: variable.TableDelta{
+					Delta:       delta.Delta,
+					Count:       delta.Count,
+					InsertCount: delta.InsertCount,
+					DeleteCount: delta.DeleteCount,
+				},
+				TableID: tableID,
+			})
+		}
+		if len(updates) > 0 {
+			if err = storage.UpdateStatsMetaInBatch(utilstats.StatsCtx, sctx, statsVersion, updates...); err != nil {
+				return err
+			}
+		}
+		for _, id := range physicalIDs {
+			if err = storage.DumpTableStatColSizeToKV(sctx, id, deltaMap[id]); err != nil {
+				return err
+			}
+		}
+		is := sctx.GetDomainInfoSchema().(infoschema.InfoSchema)
+		for _, id := range physicalIDs {
+			if _, isLocked := lockedTables[id]; isLocked || parentIDs[id] != id || deltaMap[id].Count == 0 {
+				continue
+			}
+			if err = s.refreshTinyTableRowCount(sctx, is, statsVersion, id, deltaMap[id]); err != nil {
 				return err
 			}
-			affectedRows += sctx.GetSessionVars().StmtCtx.AffectedRows()
 		}
-
-		updated = affectedRows > 0
 		return nil
 	}, utilstats.FlagWrapTxn)
-	return
+	if err != nil {
+		return err
+	}
+
+	for _, id := range physicalIDs {
+		if deltaMap[id].Count != 0 {
+			s.statsHandle.RecordHistoricalStatsMeta(id, statsVersion, utilstats.StatsMetaHistorySourceFlushStats, false)
+		}
+		delete(deltaMap, id)
+	}
+	return nil
+}
+
+// refreshTinyTableRowCount is the row-count-only fast path of the tiny non-partitioned tables. If the table is still
+// tiny after the delta, i.e. its row count doesn't exceed tidb_stats_tiny_table_threshold, its row count is refreshed
+// by a count scan bounded by the threshold and its modify count is reset, so it's never analyzed automatically.
+func (s *statsUsageImpl) refreshTinyTableRowCount(
+	sctx sessionctx.Context,
+	is infoschema.InfoSchema,
+	statsVersion uint64,
+	id int64,
+	delta variable.TableDelta,
+) error {
+	threshold := variable.StatsTinyTableThreshold.Load()
+	if threshold <= 0 {
+		return nil
+	}
+	tbl, ok := s.statsHandle.TableInfoByID(is, id)
+	if !ok {
+		return nil
+	}
+	statsTbl := s.statsHandle.GetTableStats(tbl.Meta())
+	if statsTbl.Pseudo || !statistics.IsTinyTable(max(statsTbl.RealtimeCount+delta.Delta, 0)) {
+		return nil
+	}
+	dbInfo, ok := infoschema.SchemaByTable(is, tbl.Meta())
+	if !ok {
+		return nil
+	}
+	rows, _, err := utilstats.ExecRows(sctx, "select count(*) from (select 1 from %n.%n limit %?) tmp",
+		dbInfo.Name.O, tbl.Meta().Name.O, threshold+1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	count := rows[0].GetInt64(0)
+	if count > threshold {
+		// The row count in the stats is inaccurate, the table is left to auto analyze.
+		return nil
+	}
+	return storage.ResetStatsMetaRowCount(utilstats.StatsCtx, sctx, statsVersion, id, count)
+}
+
+// DumpStatsDeltaInterval returns the interval before the next stats delta dump.
+// The interval is adapted to the volume of the last dump, so that the deltas are
+// flushed more frequently when there are many modifications and less frequently when there are few.
+func (s *statsUsageImpl) DumpStatsDeltaInterval(baseInterval time.Duration) time.Duration {
+	return adaptDumpStatsDeltaInterval(baseInterval, s.lastDumpedDeltaCount.Load())
+}
+
+func adaptDumpStatsDeltaInterval(baseInterval time.Duration, dumpedCount int64) time.Duration {
+	interval := baseInterval
+	switch {
+	case dumpedCount == 0:
+		interval = baseInterval * 2
+	case dumpedCount >= dumpStatsDeltaHighVolume*4:
+		interval = baseInterval / 4
+	case dumpedCount >= dumpStatsDeltaHighVolume:
+		interval = baseInterval / 2
+	}
+	if interval <= 0 {
+		return baseInterval
+	}
+	return interval
 }
 
 // DumpColStatsUsageToKV sweeps the whole list, updates the column stats usage map and dumps it to KV.
 func (s *statsUsageImpl) DumpColStatsUsageToKV() error {
 	s.SweepSessionStatsList()
 	colMap := s.SessionStatsUsage().GetUsageAndReset()
+	if readOnly, _ := utilstats.StatsReadOnly(); readOnly {
+		// The stats read-only instance never writes the stats, so the column stats usage is dropped.
+		return nil
+	}
 	defer func() {
 		s.SessionStatsUsage().Merge(colMap)
 	}()
@@ -295,10 +421,10 @@ func (s *SessionStatsItem) Delete() {
 }
 
 // Update will updates the delta and count for one table id.
-func (s *SessionStatsItem) Update(id int64, delta int64, count int64, colSize *map[int64]int64) {
+func (s *SessionStatsItem) Update(id int64, item variable.TableDelta) {
 	s.Lock()
 	defer s.Unlock()
-	s.mapper.Update(id, delta, count, colSize)
+	s.mapper.Update(id, item)
 }
 
 // ClearForTest clears the mapper for test.
@@ -445,10 +571,10 @@ func (m *TableDelta) GetDeltaAndReset() map[int64]variable.TableDelta {
 }
 
 // Update updates the delta of the table.
-func (m *TableDelta) Update(id int64, delta int64, count int64, colSize *map[int64]int64) {
+func (m *TableDelta) Update(id int64, item variable.TableDelta) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	UpdateTableDeltaMap(m.delta, id, delta, count, colSize)
+	UpdateTableDeltaMap(m.delta, id, item)
 }
 
 // Merge merges the deltaMap into the TableDelta.
@@ -459,22 +585,22 @@ func (m *TableDelta) Merge(deltaMap map[int64]variable.TableDelta) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	for id, item := range deltaMap {
-		UpdateTableDeltaMap(m.delta, id, item.Delta, item.Count, &item.ColSize)
+		UpdateTableDeltaMap(m.delta, id, item)
 	}
 }
 
 // UpdateTableDeltaMap updates the delta of the table.
-func UpdateTableDeltaMap(m map[int64]variable.TableDelta, id int64, delta int64, count int64, colSize *map[int64]int64) {
+func UpdateTableDeltaMap(m map[int64]variable.TableDelta, id int64, delta variable.TableDelta) {
 	item := m[id]
-	item.Delta += delta
-	item.Count += count
+	item.Delta += delta.Delta
+	item.Count += delta.Count
+	item.InsertCount += delta.InsertCount
+	item.DeleteCount += delta.DeleteCount
 	if item.ColSize == nil {
 		item.ColSize = make(map[int64]int64)
 	}
-	if colSize != nil {
-		for key, val := range *colSize {
-			item.ColSize[key] += val
-		}
+	for key, val := range delta.ColSize {
+		item.ColSize[key] += val
 	}
 	m[id] = item
 }
@@ -16,6 +16,7 @@ package usage
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -41,3 +42,36 @@ func TestInsertAndDelete(t *testing.T) {
 	sl.SweepSessionStatsList()
 	require.Nil(t, sl.listHead.next)
 }
+
+func TestGroupStatsDeltaBatches(t *testing.T) {
+	// Partitions 11, 12 and 13 belong to table 10, partitions 21 and 22 belong to table 20.
+	parentIDs := map[int64]int64{
+		1:  1,
+		2:  2,
+		11: 10,
+		12: 10,
+		13: 10,
+		21: 20,
+		22: 20,
+	}
+	batches := groupStatsDeltaBatches(parentIDs, 3)
+	require.Equal(t, [][]int64{{1, 2}, {11, 12, 13}, {21, 22}}, batches)
+
+	// A table with more partitions than the batch size is not split.
+	batches = groupStatsDeltaBatches(parentIDs, 2)
+	require.Equal(t, [][]int64{{1, 2}, {11, 12, 13}, {21, 22}}, batches)
+
+	batches = groupStatsDeltaBatches(parentIDs, 10)
+	require.Equal(t, [][]int64{{1, 2, 11, 12, 13, 21, 22}}, batches)
+
+	require.Empty(t, groupStatsDeltaBatches(map[int64]int64{}, 10))
+}
+
+func TestAdaptDumpStatsDeltaInterval(t *testing.T) {
+	base := 20 * time.Second
+	require.Equal(t, 2*base, adaptDumpStatsDeltaInterval(base, 0))
+	require.Equal(t, base, adaptDumpStatsDeltaInterval(base, 1))
+	require.Equal(t, base/2, adaptDumpStatsDeltaInterval(base, dumpStatsDeltaHighVolume))
+	require.Equal(t, base/4, adaptDumpStatsDeltaInterval(base, dumpStatsDeltaHighVolume*4))
+	require.Equal(t, time.Duration(1), adaptDumpStatsDeltaInterval(1, dumpStatsDeltaHighVolume*4))
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import "sync"
+
+// GlobalAnalyzeLocks is used to coordinate the analyze of the same table,
+// so that a manual analyze and an auto analyze don't collect the samples of the same table concurrently.
+var GlobalAnalyzeLocks = newAnalyzeLocks()
+
+type analyzeLocks struct {
+	// holders maps the physical table ID to whether the lock is held by auto analyze.
+	holders map[int64]bool
+	mu      sync.Mutex
+}
+
+func newAnalyzeLocks() *analyzeLocks {
+	return &analyzeLocks{
+		holders: make(map[int64]bool),
+	}
+}
+
+// TryLock tries to lock the physical table for analyze.
+// If the table is being analyzed, it returns false and whether the table is being analyzed by auto analyze.
+func (l *analyzeLocks) TryLock(physicalID int64, autoAnalyze bool) (locked bool, heldByAutoAnalyze bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if holderIsAuto, ok := l.holders[physicalID]; ok {
+		return false, holderIsAuto
+	}
+	l.holders[physicalID] = autoAnalyze
+	return true, false
+}
+
+// Unlock unlocks the physical table after analyze.
+func (l *analyzeLocks) Unlock(physicalID int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.holders, physicalID)
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"sync/atomic"
+
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"github.com/pingcap/tidb/pkg/util/memory"
+	"go.uber.org/zap"
+)
+
+// AnalyzeSpillRatio is the ratio of tidb_mem_quota_analyze beyond which the memory used by an analyze task is
+// spilled, so the task doesn't exceed the quota and fail.
+const AnalyzeSpillRatio = 0.8
+
+// AnalyzeSpillAction marks the memory tracked by an analyze task to be spilled. The memory isn't spilled in Action,
+// which is called by the goroutine consuming the memory, but by the owners of the memory at the points where they
+// can release it safely, which check Triggered.
+type AnalyzeSpillAction struct {
+	memory.BaseOOMAction
+	triggered atomic.Bool
+}
+
+// SetAnalyzeSpillAction sets the limit of the tracker to AnalyzeSpillRatio of the quota, and returns the action
+// triggered once the tracker exceeds the limit. The action is never triggered if the quota is unlimited.
+func SetAnalyzeSpillAction(tracker *memory.Tracker, quota int64) *AnalyzeSpillAction {
+	action := &AnalyzeSpillAction{}
+	if quota > 0 {
+		tracker.SetBytesLimit(int64(float64(quota) * AnalyzeSpillRatio))
+		tracker.SetActionOnExceed(action)
+	}
+	return action
+}
+
+// Action implements the memory.ActionOnExceed interface.
+func (a *AnalyzeSpillAction) Action(t *memory.Tracker) {
+	if a.triggered.CompareAndSwap(false, true) {
+		logutil.BgLogger().Info("analyze memory exceeds the spill limit, spill it to disk", zap.String("category", "stats"),
+			zap.Int64("consumed", t.BytesConsumed()), zap.Int64("limit", t.GetBytesLimit()))
+	}
+	// The memory keeps being spilled once triggered, so the action is not needed anymore.
+	a.SetFinished()
+}
+
+// GetPriority implements the memory.ActionOnExceed interface.
+func (*AnalyzeSpillAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
+
+// Triggered returns whether the memory should be spilled.
+func (a *AnalyzeSpillAction) Triggered() bool {
+	return a != nil && a.triggered.Load()
+}
@@ -27,9 +27,12 @@ type Pool interface {
 	// GPool returns the goroutine pool.
 	GPool() *gp.Pool
 
-	// SPool returns the session pool.
+	// SPool returns the session pool for the stats operations of the normal priority.
 	SPool() util.SessionPool
 
+	// PrioritizedSPool returns the session pool for the stats operations of the priority class.
+	PrioritizedSPool(priority SessionPriority) util.SessionPool
+
 	// Close closes the goroutine pool.
 	Close()
 }
@@ -39,14 +42,14 @@ var _ Pool = (*pool)(nil)
 type pool struct {
 	// This gpool is used to reuse goroutine in the mergeGlobalStatsTopN.
 	gpool *gp.Pool
-	pool  util.SessionPool
+	pool  *PrioritizedSessionPool
 }
 
 // NewPool creates a new Pool.
 func NewPool(p util.SessionPool) Pool {
 	return &pool{
 		gpool: gp.New(math.MaxInt16, time.Minute),
-		pool:  p,
+		pool:  NewPrioritizedSessionPool(p),
 	}
 }
 
@@ -55,9 +58,14 @@ func (p *pool) GPool() *gp.Pool {
 	return p.gpool
 }
 
-// SPool returns the session pool.
+// SPool returns the session pool for the stats operations of the normal priority.
 func (p *pool) SPool() util.SessionPool {
-	return p.pool
+	return p.pool.WithPriority(SessionPriorityNormal)
+}
+
+// PrioritizedSPool returns the session pool for the stats operations of the priority class.
+func (p *pool) PrioritizedSPool(priority SessionPriority) util.SessionPool {
+	return p.pool.WithPriority(priority)
 }
 
 // Close close the goroutine pool.
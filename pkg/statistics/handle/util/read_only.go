@@ -0,0 +1,67 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/config"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+)
+
+// ErrStatsReadOnly is returned when the stats are going to be written on the stats read-only instance.
+var ErrStatsReadOnly = errors.New("the stats can't be written on the stats read-only instance, please see the stats-read-only config")
+
+// StatsReadOnly returns whether the instance is stats read-only and the staleness of the stats read by it.
+// The stats read-only instance only serves the stale reads, so it maintains the stats cache purely by loading the
+// stats at the stale read timestamp and never writes the stats system tables.
+func StatsReadOnly() (bool, time.Duration) {
+	perf := config.GetGlobalConfig().Performance
+	if !perf.StatsReadOnly {
+		return false, 0
+	}
+	// The config has been validated, so the error can be ignored.
+	staleness, _ := time.ParseDuration(perf.StatsReadStaleness)
+	return true, staleness
+}
+
+// SetStaleReadForStats makes the statements of the session read the stats at the stale read timestamp on the stats
+// read-only instance, which can be served by the followers without hammering the leaders of the stats system tables.
+// It returns the function to restore the session.
+func SetStaleReadForStats(sctx sessionctx.Context) (restore func()) {
+	readOnly, staleness := StatsReadOnly()
+	if !readOnly || staleness <= 0 {
+		return func() {}
+	}
+	sessVars := sctx.GetSessionVars()
+	prevStaleness := sessVars.ReadStaleness
+	sessVars.ReadStaleness = -staleness
+	return func() {
+		sessVars.ReadStaleness = prevStaleness
+	}
+}
+
+// BeginInitStatsTxnSQL returns the SQL to begin the transaction to initialize the stats cache. The transaction reads
+// the stats at the stale read timestamp on the stats read-only instance, so that the stats cache never contains the
+// stats newer than the ones loaded later.
+func BeginInitStatsTxnSQL() (string, []any) {
+	readOnly, staleness := StatsReadOnly()
+	if !readOnly || staleness <= 0 {
+		return "begin", nil
+	}
+	return "START TRANSACTION READ ONLY AS OF TIMESTAMP TIDB_BOUNDED_STALENESS(NOW(6) - INTERVAL %? MICROSECOND, NOW(6))",
+		[]any{staleness.Microseconds()}
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ngaut/pools"
+	"github.com/pingcap/tidb/pkg/metrics"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util"
+)
+
+// SessionPriority is the priority class of the stats operations taking the sessions from the stats session pool.
+type SessionPriority int
+
+const (
+	// SessionPriorityLow is for the auto analyze jobs, which hold the sessions for long.
+	SessionPriorityLow SessionPriority = iota
+	// SessionPriorityNormal is for the delta dumps and the other stats operations.
+	SessionPriorityNormal
+	// SessionPriorityHigh is for the sync loads, which block the optimization of the queries.
+	SessionPriorityHigh
+
+	numSessionPriorities = 3
+)
+
+// String implements the fmt.Stringer interface.
+func (p SessionPriority) String() string {
+	switch p {
+	case SessionPriorityLow:
+		return "low"
+	case SessionPriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+// maxSessionWaitTime bounds the time waiting for a session of the saturated pool. A session is taken beyond the bound
+// of the pool after that, since the stats operations may get a session while holding another one, e.g. the analysis
+// saves the stats in a new session, and they must not wait for each other forever.
+const maxSessionWaitTime = time.Second
+
+// PrioritizedSessionPool bounds the number of the sessions used by the stats operations concurrently, which is
+// tidb_stats_session_pool_size, and hands the released sessions to the waiting operations of the higher priority
+// classes first. A quarter of the sessions is reserved for each class above the lowest one, i.e. the auto analyze jobs
+// can use at most half of the sessions, and the delta dumps and the other operations three quarters of them, so the
+// long-running jobs can't block the sync loads.
+type PrioritizedSessionPool struct {
+	pool util.SessionPool
+	mu   struct {
+		sync.Mutex
+		inUse   [numSessionPriorities]int
+		total   int
+		waiters [numSessionPriorities][]chan struct{}
+	}
+}
+
+// NewPrioritizedSessionPool creates a PrioritizedSessionPool taking the sessions from the given pool.
+func NewPrioritizedSessionPool(pool util.SessionPool) *PrioritizedSessionPool {
+	return &PrioritizedSessionPool{pool: pool}
+}
+
+// WithPriority returns the session pool for the stats operations of the priority class.
+func (p *PrioritizedSessionPool) WithPriority(priority SessionPriority) util.SessionPool {
+	return &sessionPoolWithPriority{pool: p, priority: priority}
+}
+
+// limit returns the max number of the sessions which can be used when a session is taken by the priority class.
+func limit(priority SessionPriority) int {
+	size := int(variable.StatsSessionPoolSize.Load())
+	reserved := size / 4
+	return size - reserved*(int(SessionPriorityHigh)-int(priority))
+}
+
+// canAcquireWithoutLock returns whether the priority class can take a session without waiting, i.e. the pool is not
+// saturated for the class and no operation of the same or higher classes is waiting.
+// Note: Please hold the lock before calling this function.
+func (p *PrioritizedSessionPool) canAcquireWithoutLock(priority SessionPriority) bool {
+	if p.mu.total >= limit(priority) {
+		return false
+	}
+	for i := priority; i < numSessionPriorities; i++ {
+		if len(p.mu.waiters[i]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// acquireWithoutLock records a session taken by the priority class.
+// Note: Please hold the lock before calling this function.
+func (p *PrioritizedSessionPool) acquireWithoutLock(priority SessionPriority) {
+	p.mu.inUse[priority]++
+	p.mu.total++
+	metrics.StatsSessionPoolInUseGauge.WithLabelValues(priority.String()).Set(float64(p.mu.inUse[priority]))
+}
+
+// acquire waits until the priority class can take a session, or maxSessionWaitTime passes.
+func (p *PrioritizedSessionPool) acquire(priority SessionPriority) {
+	p.mu.Lock()
+	if p.canAcquireWithoutLock(priority) {
+		p.acquireWithoutLock(priority)
+		p.mu.Unlock()
+		return
+	}
+	ch := make(chan struct{}, 1)
+	p.mu.waiters[priority] = append(p.mu.waiters[priority], ch)
+	p.mu.Unlock()
+
+	label := priority.String()
+	waitingGauge := metrics.StatsSessionPoolWaitingGauge.WithLabelValues(label)
+	waitingGauge.Inc()
+	defer waitingGauge.Dec()
+	start := time.Now()
+	defer func() {
+		metrics.StatsSessionPoolWaitHistogram.WithLabelValues(label).Observe(time.Since(start).Seconds())
+	}()
+	timer := time.NewTimer(maxSessionWaitTime)
+	defer timer.Stop()
+	select {
+	case <-ch:
+		// The session is handed over by release.
+		return
+	case <-timer.C:
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	idx := slices.Index(p.mu.waiters[priority], ch)
+	if idx < 0 {
+		// The session is handed over by release just after the timeout.
+		return
+	}
+	p.mu.waiters[priority] = slices.Delete(p.mu.waiters[priority], idx, idx+1)
+	p.acquireWithoutLock(priority)
+	metrics.StatsSessionPoolOverflowCounter.WithLabelValues(label).Inc()
+}
+
+// release records a session released by the priority class, and hands the sessions to the waiting operations of the
+// highest classes.
+func (p *PrioritizedSessionPool) release(priority SessionPriority) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mu.inUse[priority]--
+	p.mu.total--
+	metrics.StatsSessionPoolInUseGauge.WithLabelValues(priority.String()).Set(float64(p.mu.inUse[priority]))
+	for i := SessionPriorityHigh; i >= SessionPriorityLow; i-- {
+		for len(p.mu.waiters[i]) > 0 && p.mu.total < limit(i) {
+			ch := p.mu.waiters[i][0]
+			p.mu.waiters[i] = p.mu.waiters[i][1:]
+			p.acquireWithoutLock(i)
+			ch <- struct{}{}
+		}
+		// The lower classes keep waiting until the higher ones are served.
+		if len(p.mu.waiters[i]) > 0 {
+			return
+		}
+	}
+}
+
+// DiscardSession releases a session taken from the pool which is not put back, e.g. the statements fail in it and it
+// may be in a bad state, so that the session doesn't occupy the stats session pool forever.
+func DiscardSession(pool util.SessionPool) {
+	if p, ok := pool.(*sessionPoolWithPriority); ok {
+		p.pool.release(p.priority)
+	}
+}
+
+// sessionPoolWithPriority takes the sessions from the PrioritizedSessionPool for a priority class.
+type sessionPoolWithPriority struct {
+	pool     *PrioritizedSessionPool
+	priority SessionPriority
+}
+
+// Get implements the util.SessionPool interface.
+func (p *sessionPoolWithPriority) Get() (pools.Resource, error) {
+	p.pool.acquire(p.priority)
+	resource, err := p.pool.pool.Get()
+	if err != nil {
+		p.pool.release(p.priority)
+		return nil, err
+	}
+	return resource, nil
+}
+
+// Put implements the util.SessionPool interface.
+func (p *sessionPoolWithPriority) Put(resource pools.Resource) {
+	p.pool.pool.Put(resource)
+	p.pool.release(p.priority)
+}
+
+// Close implements the util.SessionPool interface.
+func (p *sessionPoolWithPriority) Close() {
+	p.pool.pool.Close()
+}
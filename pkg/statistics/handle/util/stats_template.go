@@ -0,0 +1,157 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/tidb/pkg/infoschema"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util"
+)
+
+// A stats template is made up of the non-partitioned tables with the same name and the same structure
+// in different schemas, e.g. the per-tenant schemas of a SaaS application. Only the representative tables,
+// which are in the first tidb_stats_template_representatives schemas in name order, are auto analyzed,
+// and other tables of the template share the stats of the representative tables if they have no stats.
+// The schemas in tidb_stats_template_opt_out_schemas don't join any stats template.
+
+type statsTemplateKey struct {
+	tableName   string
+	fingerprint uint64
+}
+
+// statsTemplateCache caches the representative tables of the stats templates,
+// since finding them needs to go through all the schemas.
+var statsTemplateCache struct {
+	entries         map[statsTemplateKey][]*model.TableInfo
+	optOutSchemas   string
+	schemaVersion   int64
+	representatives int64
+	sync.Mutex
+}
+
+// GetStatsTemplateRepresentatives returns the representative tables of the stats template of the table,
+// and whether the table should share the stats of them, i.e. the table is not a representative and
+// its schema doesn't opt out. It returns nil and false if the stats template is disabled or the table
+// can't be a member of a stats template.
+func GetStatsTemplateRepresentatives(
+	is infoschema.InfoSchema,
+	tblInfo *model.TableInfo,
+) (representatives []*model.TableInfo, shared bool) {
+	count := variable.StatsTemplateRepresentatives.Load()
+	if count <= 0 || tblInfo.GetPartitionInfo() != nil || tblInfo.IsView() || tblInfo.IsSequence() ||
+		tblInfo.TempTableType != model.TempTableNone {
+		return nil, false
+	}
+	schema, ok := is.SchemaByID(tblInfo.DBID)
+	if !ok || util.IsMemOrSysDB(schema.Name.L) {
+		return nil, false
+	}
+	optOutSchemas := variable.StatsTemplateOptOutSchemas.Load()
+	representatives = getStatsTemplateRepresentatives(is, tblInfo, count, optOutSchemas)
+	if isStatsTemplateOptedOut(optOutSchemas, schema.Name.L) {
+		return representatives, false
+	}
+	for _, representative := range representatives {
+		if representative.ID == tblInfo.ID {
+			return representatives, false
+		}
+	}
+	return representatives, len(representatives) > 0
+}
+
+func getStatsTemplateRepresentatives(
+	is infoschema.InfoSchema,
+	tblInfo *model.TableInfo,
+	count int64,
+	optOutSchemas string,
+) []*model.TableInfo {
+	key := statsTemplateKey{tableName: tblInfo.Name.L, fingerprint: statsTemplateFingerprint(tblInfo)}
+	statsTemplateCache.Lock()
+	defer statsTemplateCache.Unlock()
+	if statsTemplateCache.schemaVersion != is.SchemaMetaVersion() ||
+		statsTemplateCache.representatives != count ||
+		statsTemplateCache.optOutSchemas != optOutSchemas {
+		statsTemplateCache.entries = make(map[statsTemplateKey][]*model.TableInfo)
+		statsTemplateCache.schemaVersion = is.SchemaMetaVersion()
+		statsTemplateCache.representatives = count
+		statsTemplateCache.optOutSchemas = optOutSchemas
+	}
+	if representatives, ok := statsTemplateCache.entries[key]; ok {
+		return representatives
+	}
+
+	schemas := is.AllSchemaNames()
+	slices.SortFunc(schemas, func(a, b pmodel.CIStr) int {
+		return strings.Compare(a.L, b.L)
+	})
+	representatives := make([]*model.TableInfo, 0, count)
+	for _, schema := range schemas {
+		if util.IsMemOrSysDB(schema.L) || isStatsTemplateOptedOut(optOutSchemas, schema.L) {
+			continue
+		}
+		candidate, err := is.TableInfoByName(schema, tblInfo.Name)
+		if err != nil || candidate.GetPartitionInfo() != nil || statsTemplateFingerprint(candidate) != key.fingerprint {
+			continue
+		}
+		representatives = append(representatives, candidate)
+		if int64(len(representatives)) >= count {
+			break
+		}
+	}
+	statsTemplateCache.entries[key] = representatives
+	return representatives
+}
+
+// statsTemplateFingerprint calculates the fingerprint of the table structure. The stats are shared by column IDs
+// and index IDs, so the tables must have the same columns and indexes with the same IDs.
+func statsTemplateFingerprint(tblInfo *model.TableInfo) uint64 {
+	h := fnv.New64a()
+	writeInt := func(v int64) {
+		_, _ = h.Write(binary.BigEndian.AppendUint64(nil, uint64(v)))
+	}
+	for _, col := range tblInfo.Columns {
+		writeInt(col.ID)
+		_, _ = h.Write([]byte(col.Name.L))
+		_, _ = h.Write([]byte(col.FieldType.String()))
+	}
+	for _, idx := range tblInfo.Indices {
+		writeInt(idx.ID)
+		_, _ = h.Write([]byte(idx.Name.L))
+		for _, col := range idx.Columns {
+			writeInt(int64(col.Offset))
+		}
+	}
+	return h.Sum64()
+}
+
+func isStatsTemplateOptedOut(optOutSchemas, schema string) bool {
+	if optOutSchemas == "" {
+		return false
+	}
+	for _, s := range strings.Split(optOutSchemas, ",") {
+		if strings.EqualFold(strings.TrimSpace(s), schema) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util"
+)
+
+// The tables in the memory schemas and the system schemas are not auto analyzed, and their stats deltas are not
+// dumped either, except the system tables opted in by tidb_auto_analyze_system_tables. The internal SQLs on the
+// large system tables, e.g. mysql.analyze_jobs, may get better plans with their stats.
+
+// IsAutoAnalyzeSchema returns whether any table in the schema may be auto analyzed. The schema name must be in
+// lower case.
+func IsAutoAnalyzeSchema(schema string) bool {
+	if util.IsMemDB(schema) {
+		return false
+	}
+	if !util.IsSysDB(schema) {
+		return true
+	}
+	for _, name := range autoAnalyzeSystemTables() {
+		if s, _, _ := strings.Cut(name, "."); s == schema {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAutoAnalyzeTable returns whether the table may be auto analyzed, i.e. it's not in the memory schemas or
+// the system schemas, or it's opted in by tidb_auto_analyze_system_tables. The names must be in lower case.
+func IsAutoAnalyzeTable(schema, table string) bool {
+	if util.IsMemDB(schema) {
+		return false
+	}
+	if !util.IsSysDB(schema) {
+		return true
+	}
+	fullName := schema + "." + table
+	for _, name := range autoAnalyzeSystemTables() {
+		if name == fullName {
+			return true
+		}
+	}
+	return false
+}
+
+// autoAnalyzeSystemTables returns the tables in tidb_auto_analyze_system_tables, which are already normalized
+// to 'schema.table' in lower case by the validation of the variable.
+func autoAnalyzeSystemTables() []string {
+	tables := variable.AutoAnalyzeSystemTables.Load()
+	if tables == "" {
+		return nil
+	}
+	return strings.Split(tables, ",")
+}
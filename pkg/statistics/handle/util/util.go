@@ -49,6 +49,12 @@ const (
 	StatsMetaHistorySourceSchemaChange = "schema change"
 	// StatsMetaHistorySourceExtendedStats indicates stats history meta source from extended stats
 	StatsMetaHistorySourceExtendedStats = "extended stats"
+	// StatsMetaHistorySourceInjectStats indicates stats history meta source from inject stats
+	StatsMetaHistorySourceInjectStats = "inject stats"
+	// StatsMetaHistorySourceLockStats indicates stats history meta source from lock stats
+	StatsMetaHistorySourceLockStats = "lock stats"
+	// StatsMetaHistorySourceUnlockStats indicates stats history meta source from unlock stats
+	StatsMetaHistorySourceUnlockStats = "unlock stats"
 
 	// TiDBGlobalStats represents the global-stats for a partitioned table.
 	TiDBGlobalStats = "global"
@@ -87,12 +93,15 @@ func CallWithSCtx(pool util.SessionPool, f func(sctx sessionctx.Context) error,
 	defer func() {
 		if err == nil { // only recycle when no error
 			pool.Put(se)
+		} else {
+			DiscardSession(pool)
 		}
 	}()
 	sctx := se.(sessionctx.Context)
 	if err := UpdateSCtxVarsForStats(sctx); err != nil { // update stats variables automatically
 		return err
 	}
+	defer SetStaleReadForStats(sctx)()
 
 	wrapTxn := false
 	for _, flag := range flags {
@@ -167,6 +176,37 @@ func UpdateSCtxVarsForStats(sctx sessionctx.Context) error {
 	}
 	sctx.GetSessionVars().AnalyzeSkipColumnTypes = variable.ParseAnalyzeSkipColumnTypes(val)
 
+	// adaptive topn limit
+	val, err = sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBAnalyzeAdaptiveTopNLimit)
+	if err != nil {
+		return err
+	}
+	sctx.GetSessionVars().AnalyzeAdaptiveTopNLimit = int(variable.TidbOptInt64(val, variable.DefTiDBAnalyzeAdaptiveTopNLimit))
+
+	// collect trigram stats
+	val, err = sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBAnalyzeCollectTrigramStats)
+	if err != nil {
+		return err
+	}
+	sctx.GetSessionVars().AnalyzeCollectTrigramStats = variable.TiDBOptOn(val)
+
+	// block sample rate
+	val, err = sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBAnalyzeBlockSampleRate)
+	if err != nil {
+		return err
+	}
+	sctx.GetSessionVars().AnalyzeBlockSampleRate, err = strconv.ParseFloat(val, 64)
+	if err != nil {
+		return err
+	}
+
+	// read from TiFlash automatically
+	val, err = sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBAnalyzeAutoTiFlash)
+	if err != nil {
+		return err
+	}
+	sctx.GetSessionVars().AnalyzeAutoTiFlash = variable.TiDBOptOn(val)
+
 	// skip missing partition stats
 	val, err = sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBSkipMissingPartitionStats)
 	if err != nil {
@@ -277,6 +317,7 @@ type JSONTable struct {
 	TableName         string                 `json:"table_name"`
 	ExtStats          []*JSONExtendedStats   `json:"ext_stats"`
 	PredicateColumns  []*JSONPredicateColumn `json:"predicate_columns"`
+	AnalyzeOptions    *JSONAnalyzeOptions    `json:"analyze_options,omitempty"`
 	Count             int64                  `json:"count"`
 	ModifyCount       int64                  `json:"modify_count"`
 	Version           uint64                 `json:"version"`
@@ -297,6 +338,18 @@ type JSONExtendedStats struct {
 	ColIDs     []int64 `json:"cols"`
 	ScalarVals float64 `json:"scalar_vals"`
 	Tp         uint8   `json:"type"`
+	// Inited means the extended stats is created but not analyzed yet, so only its definition is dumped.
+	Inited bool `json:"inited,omitempty"`
+}
+
+// JSONAnalyzeOptions is used for dumping the analyze options persisted in mysql.analyze_options.
+type JSONAnalyzeOptions struct {
+	ColumnChoice string  `json:"column_choice"`
+	ColumnIDs    []int64 `json:"column_ids"`
+	SampleNum    int64   `json:"sample_num"`
+	SampleRate   float64 `json:"sample_rate"`
+	Buckets      int64   `json:"buckets"`
+	TopN         int64   `json:"topn"`
 }
 
 // JSONColumn is used for dumping statistics.
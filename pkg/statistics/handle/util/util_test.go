@@ -16,9 +16,16 @@ package util_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/ngaut/pools"
+	"github.com/pingcap/tidb/pkg/config"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/statistics/handle/util"
 	"github.com/pingcap/tidb/pkg/testkit"
+	tidbutil "github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/memory"
 	"github.com/stretchr/testify/require"
 )
 
@@ -55,3 +62,168 @@ func TestIsSpecialGlobalIndex(t *testing.T) {
 	}
 	require.Equal(t, cnt, len(tblInfo.Indices))
 }
+
+func TestGetStatsTemplateRepresentatives(t *testing.T) {
+	store, dom := testkit.CreateMockStoreAndDomain(t)
+	tk := testkit.NewTestKit(t, store)
+	defer tk.MustExec("set @@global.tidb_stats_template_representatives = default")
+	defer tk.MustExec("set @@global.tidb_stats_template_opt_out_schemas = default")
+
+	for _, db := range []string{"tenant1", "tenant2", "tenant3", "tenant4"} {
+		tk.MustExec("create database " + db)
+		tk.MustExec("create table " + db + ".t(a int, b int, index ia(a))")
+	}
+	// tenant4 has a different schema, so it never shares the template.
+	tk.MustExec("alter table tenant4.t add column c int")
+
+	is := dom.InfoSchema()
+	tbl := func(db string) *model.TableInfo {
+		return dom.MustGetTableInfo(t, db, "t")
+	}
+
+	// Disabled by default.
+	_, shared := util.GetStatsTemplateRepresentatives(is, tbl("tenant3"))
+	require.False(t, shared)
+
+	tk.MustExec("set @@global.tidb_stats_template_representatives = 1")
+	reps, shared := util.GetStatsTemplateRepresentatives(is, tbl("tenant3"))
+	require.True(t, shared)
+	require.Len(t, reps, 1)
+	require.Equal(t, tbl("tenant1").ID, reps[0].ID)
+	_, shared = util.GetStatsTemplateRepresentatives(is, tbl("tenant1"))
+	require.False(t, shared)
+	_, shared = util.GetStatsTemplateRepresentatives(is, tbl("tenant4"))
+	require.False(t, shared)
+
+	tk.MustExec("set @@global.tidb_stats_template_opt_out_schemas = 'tenant1,tenant3'")
+	_, shared = util.GetStatsTemplateRepresentatives(is, tbl("tenant3"))
+	require.False(t, shared)
+	reps, shared = util.GetStatsTemplateRepresentatives(is, tbl("tenant2"))
+	require.False(t, shared)
+	require.Len(t, reps, 1)
+	require.Equal(t, tbl("tenant2").ID, reps[0].ID)
+}
+
+func TestAnalyzeSpillAction(t *testing.T) {
+	// The action is never triggered if the quota is unlimited.
+	tracker := memory.NewTracker(memory.LabelForAnalyzeMemory, -1)
+	action := util.SetAnalyzeSpillAction(tracker, 0)
+	tracker.Consume(1 << 30)
+	require.False(t, action.Triggered())
+
+	// The action is triggered once the memory exceeds the spill ratio of the quota.
+	tracker = memory.NewTracker(memory.LabelForAnalyzeMemory, -1)
+	action = util.SetAnalyzeSpillAction(tracker, 1000)
+	require.Equal(t, int64(1000*util.AnalyzeSpillRatio), tracker.GetBytesLimit())
+	tracker.Consume(700)
+	require.False(t, action.Triggered())
+	tracker.Consume(200)
+	require.True(t, action.Triggered())
+	// It keeps being triggered after the memory is released.
+	tracker.Release(900)
+	require.True(t, action.Triggered())
+}
+
+func TestSetStaleReadForStats(t *testing.T) {
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	sctx := tk.Session()
+	restore := config.RestoreFunc()
+	defer restore()
+
+	readOnly, _ := util.StatsReadOnly()
+	require.False(t, readOnly)
+	util.SetStaleReadForStats(sctx)()
+	require.Zero(t, sctx.GetSessionVars().ReadStaleness)
+	sql, args := util.BeginInitStatsTxnSQL()
+	require.Equal(t, "begin", sql)
+	require.Empty(t, args)
+
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Performance.StatsReadOnly = true
+	})
+	readOnly, staleness := util.StatsReadOnly()
+	require.True(t, readOnly)
+	require.Equal(t, 5*time.Second, staleness)
+	restoreSession := util.SetStaleReadForStats(sctx)
+	require.Equal(t, -5*time.Second, sctx.GetSessionVars().ReadStaleness)
+	restoreSession()
+	require.Zero(t, sctx.GetSessionVars().ReadStaleness)
+	sql, args = util.BeginInitStatsTxnSQL()
+	require.Contains(t, sql, "AS OF TIMESTAMP")
+	require.Equal(t, []any{int64(5000000)}, args)
+}
+
+type mockResource struct{}
+
+func (*mockResource) Close() {}
+
+type mockSessionPool struct{}
+
+func (*mockSessionPool) Get() (pools.Resource, error) { return &mockResource{}, nil }
+
+func (*mockSessionPool) Put(pools.Resource) {}
+
+func (*mockSessionPool) Close() {}
+
+func TestPrioritizedSessionPool(t *testing.T) {
+	origSize := variable.StatsSessionPoolSize.Load()
+	defer variable.StatsSessionPoolSize.Store(origSize)
+	// A session is reserved for each of the normal and the high classes.
+	variable.StatsSessionPoolSize.Store(4)
+	pool := util.NewPrioritizedSessionPool(&mockSessionPool{})
+	lowPool := pool.WithPriority(util.SessionPriorityLow)
+	normalPool := pool.WithPriority(util.SessionPriorityNormal)
+	highPool := pool.WithPriority(util.SessionPriorityHigh)
+
+	getAsync := func(p tidbutil.SessionPool) chan pools.Resource {
+		ch := make(chan pools.Resource, 1)
+		go func() {
+			res, err := p.Get()
+			require.NoError(t, err)
+			ch <- res
+		}()
+		return ch
+	}
+	requireWaiting := func(ch chan pools.Resource) {
+		select {
+		case <-ch:
+			require.FailNow(t, "the session should not be taken")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	low1, err := lowPool.Get()
+	require.NoError(t, err)
+	_, err = lowPool.Get()
+	require.NoError(t, err)
+	// The low class can use at most half of the sessions.
+	low3 := getAsync(lowPool)
+	requireWaiting(low3)
+	// The high class is not blocked by the waiting low class.
+	_, err = highPool.Get()
+	require.NoError(t, err)
+	normal1 := getAsync(normalPool)
+	requireWaiting(normal1)
+	// The released session is handed to the normal class first.
+	lowPool.Put(low1)
+	<-normal1
+	requireWaiting(low3)
+	// The low class takes a session beyond the bound of the pool after waiting for too long.
+	select {
+	case <-low3:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the session should be taken after the timeout")
+	}
+
+	// The discarded sessions release their slots as well.
+	variable.StatsSessionPoolSize.Store(1)
+	pool = util.NewPrioritizedSessionPool(&mockSessionPool{})
+	highPool = pool.WithPriority(util.SessionPriorityHigh)
+	_, err = highPool.Get()
+	require.NoError(t, err)
+	high2 := getAsync(highPool)
+	requireWaiting(high2)
+	util.DiscardSession(highPool)
+	<-high2
+}
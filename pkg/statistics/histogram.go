@@ -272,6 +272,38 @@ func ValueToString(vars *variable.SessionVars, value *types.Datum, idxCols int,
 	if idxCols == 0 {
 		return value.ToString()
 	}
+	return types.DatumsToString(decodeIndexValue(vars, value, idxCols, idxColumnTypes), true)
+}
+
+// ValueToDisplayString is like ValueToString, but the value is decoded by the field types of the columns. So the time
+// values of the index are decoded even if the stats of the columns are not loaded, and the collation keys of the
+// strings are decoded to the strings which have the same keys, or shown in hex if they can't be decoded.
+func ValueToDisplayString(vars *variable.SessionVars, value *types.Datum, idxCols int, fts []*types.FieldType) (string, error) {
+	if idxCols == 0 {
+		if len(fts) == 0 {
+			return value.ToString()
+		}
+		d := decodeCollationKey(*value, fts[0])
+		return d.ToString()
+	}
+	var idxColumnTypes []byte
+	if len(fts) > 0 {
+		idxColumnTypes = make([]byte, 0, len(fts))
+		for _, ft := range fts {
+			idxColumnTypes = append(idxColumnTypes, ft.GetType())
+		}
+	}
+	decodedVals := decodeIndexValue(vars, value, idxCols, idxColumnTypes)
+	for i := range decodedVals {
+		if i < len(fts) {
+			decodedVals[i] = decodeCollationKey(decodedVals[i], fts[i])
+		}
+	}
+	return types.DatumsToString(decodedVals, true)
+}
+
+// decodeIndexValue decodes the encoded value of an index to the values of the columns.
+func decodeIndexValue(vars *variable.SessionVars, value *types.Datum, idxCols int, idxColumnTypes []byte) []types.Datum {
 	var loc *time.Location
 	if vars != nil {
 		loc = vars.Location()
@@ -283,8 +315,18 @@ func ValueToString(vars *variable.SessionVars, value *types.Datum, idxCols int,
 	if len(remained) > 0 {
 		decodedVals = append(decodedVals, types.NewBytesDatum(remained))
 	}
-	str, err := types.DatumsToString(decodedVals, true)
-	return str, err
+	return decodedVals
+}
+
+// decodeCollationKey decodes the collation key of a string value, see collate.DecodeKey.
+func decodeCollationKey(d types.Datum, ft *types.FieldType) types.Datum {
+	if !types.IsStringKind(d.Kind()) || !types.IsString(ft.GetType()) {
+		return d
+	}
+	if str, ok := collate.DecodeKey(d.GetBytes(), ft.GetCollate()); ok {
+		return types.NewStringDatum(str)
+	}
+	return types.NewStringDatum(fmt.Sprintf("0x%X", d.GetBytes()))
 }
 
 // BucketToString change the given bucket to string format.
@@ -1058,6 +1100,13 @@ func (hg *Histogram) OutOfRangeRowCount(
 		return min(rowCount, upperBound)
 	}
 
+	if sctx.GetSessionVars().ExtrapolateMonotonicHistogram && hg.isMonotonicallyIncreasing() {
+		if extrapolated, ok := hg.extrapolateOutOfRangeRowCount(l, r, increaseFactor); ok {
+			rowCount = extrapolated
+			return min(rowCount, float64(modifyCount))
+		}
+	}
+
 	// If the modifyCount is large (compared to original table rows), then any out of range estimate is unreliable.
 	// Assume at least 1/NDV is returned
 	if float64(modifyCount) > hg.NotNullCount() && rowCount < upperBound {
@@ -1071,6 +1120,47 @@ func (hg *Histogram) OutOfRangeRowCount(
 	return min(rowCount, float64(modifyCount))
 }
 
+// monotonicCorrelationThreshold is the min correlation between the row ordering and the values of a column whose
+// values are considered monotonically increasing, e.g. the auto-increment IDs and the insertion time.
+const monotonicCorrelationThreshold = 0.9
+
+// isMonotonicallyIncreasing checks whether the values of the column are monotonically increasing along with the
+// insertion of the rows.
+func (hg *Histogram) isMonotonicallyIncreasing() bool {
+	tp := hg.Tp.GetType()
+	return hg.Correlation >= monotonicCorrelationThreshold && (mysql.IsIntegerType(tp) || types.IsTypeTime(tp))
+}
+
+// extrapolateOutOfRangeRowCount estimates the row count of the part of [l, r] beyond the upper bound of the histogram
+// of a monotonically increasing column. The rows inserted since the last analyze are assumed to be appended after the
+// upper bound with the same density as the last bucket, i.e. the last bucket and the max value are extended until
+// they cover all the inserted rows. There are no rows below the lower bound of the histogram.
+// It returns false if no rows are inserted since the last analyze.
+func (hg *Histogram) extrapolateOutOfRangeRowCount(l, r, increaseFactor float64) (float64, bool) {
+	insertedRows := hg.NotNullCount() * (increaseFactor - 1)
+	if insertedRows <= 0 {
+		return 0, false
+	}
+	last := hg.Len() - 1
+	histR := convertDatumToScalar(hg.GetUpper(last), 0)
+	bucketWidth := histR - convertDatumToScalar(hg.GetLower(last), 0)
+	bucketCount := float64(hg.BucketCount(last))
+	if bucketWidth <= 0 || bucketCount <= 0 {
+		// The last bucket only contains a single value, so the density of the whole histogram is used instead.
+		bucketWidth = histR - convertDatumToScalar(hg.GetLower(0), 0)
+		bucketCount = hg.NotNullCount()
+	}
+	if bucketWidth <= 0 || bucketCount <= 0 || math.IsInf(bucketWidth, 1) {
+		return 0, false
+	}
+	extendedWidth := insertedRows * bucketWidth / bucketCount
+	overlap := min(r, histR+extendedWidth) - max(l, histR)
+	if overlap <= 0 {
+		return 0, true
+	}
+	return insertedRows * overlap / extendedWidth, true
+}
+
 // Copy deep copies the histogram.
 func (hg *Histogram) Copy() *Histogram {
 	if hg == nil {
@@ -0,0 +1,250 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	// hllPrecision is the number of the hash bits used to choose the register.
+	hllPrecision = 14
+	// hllRegisterCount is the number of the registers in a HLL sketch.
+	hllRegisterCount = 1 << hllPrecision
+	// hllMaxRegisterValue is the max value of a register. It means the remaining bits of the hash value are all zero.
+	hllMaxRegisterValue = 64 - hllPrecision + 1
+
+	hllEncodeVersion = 1
+	hllEncodeSparse  = 0
+	hllEncodeDense   = 1
+	// hllHeaderSize is the size of the version, precision, level and format in the encoded sketch.
+	hllHeaderSize = 4
+)
+
+// HLLSketch is a HyperLogLog sketch used to estimate the NDV of a column or an index.
+// The registers are indexed by the highest bits of the hash value, and each register keeps the max number of the
+// trailing zeroes plus one of the hash values falling into it, just like HLL++ does.
+//
+// The coprocessor only returns the FMSketch of the sampled values, so the HLL sketch is built from the hash values
+// kept in an FMSketch. An FMSketch on level k only keeps the hash values with at least k trailing zeroes, so a register
+// without any of these hash values is only known to be at most k. The level of the sketch tracks this bound, and the
+// registers not greater than the level are treated as censored when estimating the NDV. This keeps the sketch exactly
+// mergeable: merging takes the max of the registers and the max of the levels.
+//
+// Compared with the FMSketch, the sketch is smaller (at most 16KB) and can be merged across partitions without
+// keeping all the hash values, so it's persisted in mysql.stats_histograms to merge the global NDV.
+type HLLSketch struct {
+	registers []uint8
+	// level is the bound of the censored registers. The registers are either 0 or greater than the level.
+	level uint8
+}
+
+// NewHLLSketchFromFMSketch builds a HLL sketch from the hash values kept in the FMSketch.
+func NewHLLSketchFromFMSketch(fms *FMSketch) *HLLSketch {
+	if fms == nil {
+		return nil
+	}
+	s := &HLLSketch{
+		registers: make([]uint8, hllRegisterCount),
+		level:     uint8(bits.OnesCount64(fms.mask)),
+	}
+	fms.hashset.Iter(func(hashVal uint64, _ bool) bool {
+		s.insertHashValue(hashVal)
+		return false
+	})
+	return s
+}
+
+func (s *HLLSketch) insertHashValue(hashVal uint64) {
+	idx := hashVal >> (64 - hllPrecision)
+	val := uint8(min(bits.TrailingZeros64(hashVal), hllMaxRegisterValue-1) + 1)
+	if val > s.level && val > s.registers[idx] {
+		s.registers[idx] = val
+	}
+}
+
+// MergeHLLSketch merges another HLL sketch into the current one.
+func (s *HLLSketch) MergeHLLSketch(rs *HLLSketch) {
+	if s == nil || rs == nil {
+		return
+	}
+	s.level = max(s.level, rs.level)
+	for i, val := range rs.registers {
+		s.registers[i] = max(s.registers[i], val)
+	}
+	// The registers which become censored carry no information any more.
+	for i, val := range s.registers {
+		if val <= s.level {
+			s.registers[i] = 0
+		}
+	}
+}
+
+// Covers returns whether merging the sketch into a merged one in place of rs gets the same result as merging rs into it
+// too, i.e. every register of rs is either censored by the level of the sketch or not greater than the register of the
+// sketch. It's true if the sketch is built from a superset of the hash values of rs, e.g. rs is the sketch of a
+// partition before some rows are appended to it. A nil sketch is covered by any sketch.
+func (s *HLLSketch) Covers(rs *HLLSketch) bool {
+	if rs == nil {
+		return true
+	}
+	if s == nil || s.level < rs.level {
+		return false
+	}
+	for i, val := range rs.registers {
+		if val > s.level && val > s.registers[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NDV returns the estimated number of distinct values in the sketch.
+// Each register is modeled to receive Poisson(λ) distinct values, so a register is at most k with the probability
+// exp(-λ/2^k). The λ is estimated by maximizing the likelihood of the registers, which also works for the censored
+// registers, and the NDV is λ times the number of the registers.
+func (s *HLLSketch) NDV() int64 {
+	if s == nil {
+		return 0
+	}
+	var counts [hllMaxRegisterValue + 1]int
+	censored, exact := 0, 0
+	for _, val := range s.registers {
+		if val <= s.level {
+			censored++
+			continue
+		}
+		counts[val]++
+		exact++
+	}
+	if exact == 0 {
+		return 0
+	}
+	logLikelihood := func(logLambda float64) float64 {
+		lambda := math.Exp(logLambda)
+		sum := -float64(censored) * math.Ldexp(lambda, -int(s.level))
+		for val := int(s.level) + 1; val <= hllMaxRegisterValue; val++ {
+			if counts[val] == 0 {
+				continue
+			}
+			if val == hllMaxRegisterValue {
+				// The max value means the register is at least hllMaxRegisterValue.
+				sum += float64(counts[val]) * math.Log(-math.Expm1(-math.Ldexp(lambda, 1-val)))
+				continue
+			}
+			a := math.Ldexp(lambda, -val)
+			sum += float64(counts[val]) * (math.Log(-math.Expm1(-a)) - a)
+		}
+		return sum
+	}
+	// The log likelihood is unimodal on log(λ), so use the golden section search to find the max.
+	lo, hi := math.Log(1e-6), 64*math.Ln2
+	ratio := (math.Sqrt(5) - 1) / 2
+	x1, x2 := hi-ratio*(hi-lo), lo+ratio*(hi-lo)
+	f1, f2 := logLikelihood(x1), logLikelihood(x2)
+	for i := 0; i < 100; i++ {
+		if f1 < f2 {
+			lo, x1, f1 = x1, x2, f2
+			x2 = lo + ratio*(hi-lo)
+			f2 = logLikelihood(x2)
+		} else {
+			hi, x2, f2 = x2, x1, f1
+			x1 = hi - ratio*(hi-lo)
+			f1 = logLikelihood(x1)
+		}
+	}
+	return int64(math.Round(hllRegisterCount * math.Exp((lo+hi)/2)))
+}
+
+// EncodeHLLSketch encodes the given HLLSketch to byte slice.
+// The non-zero registers are encoded as the delta of the register index and the value if it's smaller than
+// encoding all the registers.
+func EncodeHLLSketch(s *HLLSketch) []byte {
+	if s == nil {
+		return nil
+	}
+	nonZero := 0
+	for _, val := range s.registers {
+		if val != 0 {
+			nonZero++
+		}
+	}
+	data := make([]byte, hllHeaderSize, hllHeaderSize+min(nonZero*(binary.MaxVarintLen16+1), hllRegisterCount))
+	data[0], data[1], data[2] = hllEncodeVersion, hllPrecision, s.level
+	if nonZero*(binary.MaxVarintLen16+1) >= hllRegisterCount {
+		data[3] = hllEncodeDense
+		return append(data, s.registers...)
+	}
+	data[3] = hllEncodeSparse
+	data = binary.AppendUvarint(data, uint64(nonZero))
+	last := 0
+	for i, val := range s.registers {
+		if val == 0 {
+			continue
+		}
+		data = binary.AppendUvarint(data, uint64(i-last))
+		data = append(data, val)
+		last = i
+	}
+	return data
+}
+
+// DecodeHLLSketch decodes a HLLSketch from the given byte slice.
+func DecodeHLLSketch(data []byte) (*HLLSketch, error) {
+	if data == nil {
+		return nil, nil
+	}
+	if len(data) < hllHeaderSize || data[0] != hllEncodeVersion || data[1] != hllPrecision {
+		return nil, errors.Errorf("invalid HLL sketch")
+	}
+	s := &HLLSketch{
+		registers: make([]uint8, hllRegisterCount),
+		level:     data[2],
+	}
+	format, data := data[3], data[hllHeaderSize:]
+	switch format {
+	case hllEncodeDense:
+		if len(data) != hllRegisterCount {
+			return nil, errors.Errorf("invalid HLL sketch")
+		}
+		copy(s.registers, data)
+	case hllEncodeSparse:
+		nonZero, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errors.Errorf("invalid HLL sketch")
+		}
+		data = data[n:]
+		idx := uint64(0)
+		for i := uint64(0); i < nonZero; i++ {
+			delta, n := binary.Uvarint(data)
+			if n <= 0 || len(data) <= n {
+				return nil, errors.Errorf("invalid HLL sketch")
+			}
+			idx += delta
+			if idx >= hllRegisterCount {
+				return nil, errors.Errorf("invalid HLL sketch")
+			}
+			s.registers[idx] = data[n]
+			data = data[n+1:]
+		}
+	default:
+		return nil, errors.Errorf("invalid HLL sketch")
+	}
+	return s, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHLLSketch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	checkNDV := func(s *HLLSketch, expected int64) {
+		ndv := s.NDV()
+		require.LessOrEqual(t, math.Abs(float64(ndv-expected)), float64(expected)*0.05, "expected %d, got %d", expected, ndv)
+		decoded, err := DecodeHLLSketch(EncodeHLLSketch(s))
+		require.NoError(t, err)
+		require.Equal(t, s, decoded)
+	}
+	require.Equal(t, int64(0), NewHLLSketchFromFMSketch(NewFMSketch(MaxSketchSize)).NDV())
+	for _, ndv := range []int{10, 1000, 100000, 1000000} {
+		fms := NewFMSketch(MaxSketchSize)
+		for i := 0; i < ndv; i++ {
+			fms.insertHashValue(rng.Uint64())
+		}
+		checkNDV(NewHLLSketchFromFMSketch(fms), int64(ndv))
+	}
+
+	// Merge the sketches of the partitions, which have different levels and overlapped values.
+	values := make([]uint64, 600000)
+	for i := range values {
+		values[i] = rng.Uint64()
+	}
+	var merged *HLLSketch
+	for _, part := range [][]uint64{values[:1000], values[:300000], values[200000:]} {
+		fms := NewFMSketch(MaxSketchSize)
+		for _, val := range part {
+			fms.insertHashValue(val)
+		}
+		s := NewHLLSketchFromFMSketch(fms)
+		if merged == nil {
+			merged = s
+		} else {
+			merged.MergeHLLSketch(s)
+		}
+	}
+	checkNDV(merged, int64(len(values)))
+
+	_, err := DecodeHLLSketch([]byte{hllEncodeVersion, hllPrecision, 0, hllEncodeDense, 1})
+	require.Error(t, err)
+}
+
+func TestHLLSketchCovers(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	values := make([]uint64, 200000)
+	for i := range values {
+		values[i] = rng.Uint64()
+	}
+	buildSketch := func(values []uint64) *HLLSketch {
+		fms := NewFMSketch(MaxSketchSize)
+		for _, val := range values {
+			fms.insertHashValue(val)
+		}
+		return NewHLLSketchFromFMSketch(fms)
+	}
+	// The sketch of a partition before and after the rows are appended, which raises the level of the sketch.
+	oldSketch, newSketch := buildSketch(values[:1000]), buildSketch(values[:100000])
+	require.Greater(t, newSketch.level, oldSketch.level)
+	require.True(t, newSketch.Covers(oldSketch))
+	require.False(t, oldSketch.Covers(newSketch))
+	require.True(t, newSketch.Covers(nil))
+	require.False(t, (*HLLSketch)(nil).Covers(newSketch))
+
+	// Replacing the covered sketch of a partition in the merged sketch gets the same result as merging them again.
+	otherSketch := buildSketch(values[150000:])
+	merged := buildSketch(values[150000:])
+	merged.MergeHLLSketch(oldSketch)
+	merged.MergeHLLSketch(newSketch)
+	otherSketch.MergeHLLSketch(newSketch)
+	require.Equal(t, otherSketch, merged)
+
+	// The rows are deleted from the partition.
+	require.False(t, buildSketch(values[50000:100000]).Covers(newSketch))
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+)
+
+// maxIndexColCorrelationCols is the max number of the leading columns of a composite index whose pairwise
+// correlations are collected, which limits the pairs of the wide indexes.
+const maxIndexColCorrelationCols = 4
+
+const indexColCorrelationPrefix = "__idx_corr_"
+
+// IndexColCorrelationName returns the name of the correlation between two columns of the composite indexes in
+// mysql.stats_extended.
+func IndexColCorrelationName(colID1, colID2 int64) string {
+	return fmt.Sprintf("%s%d_%d", indexColCorrelationPrefix, colID1, colID2)
+}
+
+// IsIndexColCorrelation returns whether the extended stats of the name is a correlation collected for the composite
+// indexes rather than created by ALTER TABLE ADD STATS_EXTENDED.
+func IsIndexColCorrelation(name string) bool {
+	return strings.HasPrefix(name, indexColCorrelationPrefix)
+}
+
+// AppendIndexColCorrelations computes the pairwise correlations between the columns of the composite indexes from the
+// sampled rows, and appends them to the extended stats as StatsTypeCorrelation, which are used like the ones created
+// by ALTER TABLE ADD STATS_EXTENDED. cols are the analyzed columns, which the offsets of the index columns and the
+// columns of the samples refer to.
+func AppendIndexColCorrelations(sc *stmtctx.StatementContext, extStats *ExtendedStatsColl, cols []*model.ColumnInfo,
+	indexes []*model.IndexInfo, samples []*ReservoirRowSampleItem) (*ExtendedStatsColl, error) {
+	if len(samples) < 2 {
+		return extStats, nil
+	}
+	// The same pair may be shared by several indexes.
+	covered := make(map[[2]int64]struct{})
+	for _, idx := range indexes {
+		if idx.State != model.StatePublic || len(idx.Columns) < 2 {
+			continue
+		}
+		idxCols := idx.Columns[:min(len(idx.Columns), maxIndexColCorrelationCols)]
+		for i := range idxCols {
+			for j := i + 1; j < len(idxCols); j++ {
+				offsetX, offsetY := idxCols[i].Offset, idxCols[j].Offset
+				colX, colY := cols[offsetX], cols[offsetY]
+				// The stats of the virtual generated columns are not kept, so neither are their correlations.
+				if colX.IsVirtualGenerated() || colY.IsVirtualGenerated() {
+					continue
+				}
+				if _, ok := covered[[2]int64{colX.ID, colY.ID}]; ok {
+					continue
+				}
+				covered[[2]int64{colX.ID, colY.ID}] = struct{}{}
+				covered[[2]int64{colY.ID, colX.ID}] = struct{}{}
+				corr, ok, err := calcColPairCorrelation(sc, offsetX, offsetY, samples)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+				if extStats == nil {
+					extStats = NewExtendedStatsColl()
+				}
+				extStats.Stats[IndexColCorrelationName(colX.ID, colY.ID)] = &ExtendedStatsItem{
+					Tp:         ast.StatsTypeCorrelation,
+					ColIDs:     []int64{colX.ID, colY.ID},
+					ScalarVals: corr,
+				}
+			}
+		}
+	}
+	return extStats, nil
+}
+
+// calcColPairCorrelation computes the order correlation between two columns of the sampled rows, skipping the rows
+// with NULL in either column. It returns false if there are less than two such rows.
+func calcColPairCorrelation(sc *stmtctx.StatementContext, offsetX, offsetY int, samples []*ReservoirRowSampleItem) (float64, bool, error) {
+	samplesX := make([]*SampleItem, 0, len(samples))
+	samplesY := make([]*SampleItem, 0, len(samples))
+	for _, sample := range samples {
+		x, y := sample.Columns[offsetX], sample.Columns[offsetY]
+		if x.IsNull() || y.IsNull() {
+			continue
+		}
+		samplesX = append(samplesX, &SampleItem{Value: x, Ordinal: len(samplesX)})
+		samplesY = append(samplesY, &SampleItem{Value: y})
+	}
+	sampleNum := len(samplesX)
+	if sampleNum < 2 {
+		return 0, false, nil
+	}
+	if err := sortSampleItems(sc, samplesX); err != nil {
+		return 0, false, err
+	}
+	// The ordinal of the Y value is the position of its X value in the X order, so the correlation between the
+	// positions in the Y order and the ordinals is the correlation between the two columns.
+	for i, itemX := range samplesX {
+		samplesY[itemX.Ordinal].Ordinal = i
+	}
+	if err := sortSampleItems(sc, samplesY); err != nil {
+		return 0, false, err
+	}
+	var corrXYSum float64
+	for i, itemY := range samplesY {
+		corrXYSum += float64(i) * float64(itemY.Ordinal)
+	}
+	return calcCorrelation(int64(sampleNum), corrXYSum), true, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexColCorrelations(t *testing.T) {
+	sc := stmtctx.NewStmtCtxWithTimeZone(time.Local)
+	cols := make([]*model.ColumnInfo, 0, 4)
+	for i := 0; i < 4; i++ {
+		cols = append(cols, &model.ColumnInfo{ID: int64(i + 1), Offset: i, State: model.StatePublic})
+	}
+	// The column b is in the same order as a, c is in the reverse order, and d is NULL.
+	samples := make([]*ReservoirRowSampleItem, 0, 10)
+	for i := 0; i < 10; i++ {
+		row := types.MakeDatums(i, i*2, 10-i, nil)
+		samples = append(samples, &ReservoirRowSampleItem{Columns: row})
+	}
+	indexes := []*model.IndexInfo{
+		{
+			ID:    1,
+			Name:  pmodel.NewCIStr("idx_abc"),
+			State: model.StatePublic,
+			Columns: []*model.IndexColumn{
+				{Offset: 0, Length: types.UnspecifiedLength},
+				{Offset: 1, Length: types.UnspecifiedLength},
+				{Offset: 2, Length: types.UnspecifiedLength},
+			},
+		},
+		{
+			ID:    2,
+			Name:  pmodel.NewCIStr("idx_ad"),
+			State: model.StatePublic,
+			Columns: []*model.IndexColumn{
+				{Offset: 0, Length: types.UnspecifiedLength},
+				{Offset: 3, Length: types.UnspecifiedLength},
+			},
+		},
+		{
+			ID:      3,
+			Name:    pmodel.NewCIStr("idx_a"),
+			State:   model.StatePublic,
+			Columns: []*model.IndexColumn{{Offset: 0, Length: types.UnspecifiedLength}},
+		},
+	}
+	extStats, err := AppendIndexColCorrelations(sc, nil, cols, indexes, samples)
+	require.NoError(t, err)
+	require.NotNil(t, extStats)
+	// The pair (a, d) is skipped since all the values of d are NULL.
+	require.Len(t, extStats.Stats, 3)
+	expected := map[string]float64{
+		IndexColCorrelationName(1, 2): 1,
+		IndexColCorrelationName(1, 3): -1,
+		IndexColCorrelationName(2, 3): -1,
+	}
+	for name, corr := range expected {
+		item, ok := extStats.Stats[name]
+		require.True(t, ok, name)
+		require.True(t, IsIndexColCorrelation(name))
+		require.Equal(t, ast.StatsTypeCorrelation, item.Tp)
+		require.InDelta(t, corr, item.ScalarVals, 1e-9)
+	}
+	require.Equal(t, "__idx_corr_1_2", IndexColCorrelationName(1, 2))
+	require.False(t, IsIndexColCorrelation("s1"))
+
+	// No correlation is collected without the composite indexes.
+	extStats, err = AppendIndexColCorrelations(sc, nil, cols, indexes[2:], samples)
+	require.NoError(t, err)
+	require.Nil(t, extStats)
+}
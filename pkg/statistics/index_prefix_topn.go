@@ -0,0 +1,230 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+)
+
+const (
+	// maxIndexPrefixTopN is the max number of the most frequent values kept for a prefix of a composite index.
+	maxIndexPrefixTopN = 100
+	// maxIndexPrefixTopNCols is the max number of the leading columns of a composite index whose prefixes are
+	// collected, which limits the prefixes of the wide indexes.
+	maxIndexPrefixTopNCols = 4
+	// maxIndexPrefixTopNSize is the max size of the encoded index prefix TopN, it must fit in
+	// mysql.stats_extended.stats.
+	maxIndexPrefixTopNSize = 60 * 1024
+)
+
+// IndexPrefixTopN is the TopN of the prefixes of a composite index, i.e. the most frequent values of the concatenated
+// keys of its leading columns. The index stats only keep the TopN of the full keys, so the skewed values of a prefix
+// like (tenant_id, status) of the index (tenant_id, status, created_at) are spread over many distinct full keys and
+// severely underestimated. It's collected from the samples of ANALYZE and kept in mysql.stats_extended as the type
+// StatsTypeIndexPrefixTopN. All the counts are in the unit of sampled rows.
+type IndexPrefixTopN struct {
+	// SampleCount is the number of sampled rows.
+	SampleCount int64 `json:"sample_count"`
+	// Prefixes are the TopN of the prefixes from 2 columns to 1 column less than the index.
+	Prefixes []IndexPrefixTopNItem `json:"prefixes"`
+
+	// counts maps the number of the columns of a prefix to the counts of its values.
+	counts map[int]map[string]int64
+}
+
+// IndexPrefixTopNItem is the TopN of a prefix of the index.
+type IndexPrefixTopNItem struct {
+	// ColNum is the number of the leading columns of the prefix.
+	ColNum int `json:"col_num"`
+	// TopN are the most frequent values of the prefix sorted by Count in descending order.
+	TopN []IndexPrefixValue `json:"topn"`
+}
+
+// IndexPrefixValue is a value of a prefix and the number of sampled rows having it.
+type IndexPrefixValue struct {
+	// Encoded is the concatenated key of the values encoded by codec.EncodeKey, like the keys of the index TopN.
+	Encoded []byte `json:"encoded"`
+	Count   int64  `json:"count"`
+}
+
+// IndexPrefixTopNName returns the name of the prefix TopN of the index in mysql.stats_extended.
+func IndexPrefixTopNName(idxID int64) string {
+	return fmt.Sprintf("__idx_prefix_topn_%d", idxID)
+}
+
+// DecodeIndexPrefixTopN decodes the index prefix TopN from the stats of mysql.stats_extended.
+func DecodeIndexPrefixTopN(str string) (*IndexPrefixTopN, error) {
+	s := &IndexPrefixTopN{}
+	if err := json.Unmarshal([]byte(str), s); err != nil {
+		return nil, errors.Trace(err)
+	}
+	s.counts = make(map[int]map[string]int64, len(s.Prefixes))
+	for _, prefix := range s.Prefixes {
+		counts := make(map[string]int64, len(prefix.TopN))
+		for _, val := range prefix.TopN {
+			counts[string(val.Encoded)] = val.Count
+		}
+		s.counts[prefix.ColNum] = counts
+	}
+	return s, nil
+}
+
+// EqualSelectivity returns the fraction of rows whose first colNum index columns equal to the encoded values. It
+// returns false if the values are not in the TopN of the prefix, or the prefix is not collected.
+func (s *IndexPrefixTopN) EqualSelectivity(encoded []byte, colNum int) (float64, bool) {
+	if s == nil || s.SampleCount <= 0 {
+		return 0, false
+	}
+	cnt, ok := s.counts[colNum][string(encoded)]
+	if !ok {
+		return 0, false
+	}
+	return float64(cnt) / float64(s.SampleCount), true
+}
+
+// encodeIndexPrefixTopN encodes the index prefix TopN, the least frequent values are dropped if it's too large.
+func encodeIndexPrefixTopN(s *IndexPrefixTopN) (string, error) {
+	for {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if len(data) <= maxIndexPrefixTopNSize {
+			return string(data), nil
+		}
+		truncated := false
+		for i := range s.Prefixes {
+			if n := len(s.Prefixes[i].TopN); n > 0 {
+				s.Prefixes[i].TopN = s.Prefixes[i].TopN[:n/2]
+				truncated = true
+			}
+		}
+		if !truncated {
+			return string(data), nil
+		}
+	}
+}
+
+// AppendIndexPrefixTopNs builds the TopN of the prefixes of the composite indexes from the sampled rows of ANALYZE,
+// and appends them to the extended stats. The prefixes are encoded in the same way as the index samples, so they can
+// be compared with the encoded bounds of the index ranges. cols are the analyzed columns, which the offsets of the
+// index columns and the columns of the samples refer to.
+func AppendIndexPrefixTopNs(sc *stmtctx.StatementContext, extStats *ExtendedStatsColl, cols []*model.ColumnInfo,
+	indexes []*model.IndexInfo, samples []*ReservoirRowSampleItem) (*ExtendedStatsColl, error) {
+	if len(samples) == 0 {
+		return extStats, nil
+	}
+	for _, idx := range indexes {
+		// The TopN of the full keys are kept in the index stats, so only the indexes with at least 3 columns have
+		// the prefixes of multiple columns.
+		if idx.State != model.StatePublic || idx.MVIndex || len(idx.Columns) < 3 {
+			continue
+		}
+		maxColNum := min(len(idx.Columns)-1, maxIndexPrefixTopNCols)
+		stats, err := buildIndexPrefixTopN(sc, cols, idx.Columns[:maxColNum], samples)
+		if err != nil {
+			return nil, err
+		}
+		if len(stats.Prefixes) == 0 {
+			continue
+		}
+		str, err := encodeIndexPrefixTopN(stats)
+		if err != nil {
+			return nil, err
+		}
+		// Decode it again to build the index of the values.
+		stats, err = DecodeIndexPrefixTopN(str)
+		if err != nil {
+			return nil, err
+		}
+		colIDs := make([]int64, 0, maxColNum)
+		for _, idxCol := range idx.Columns[:maxColNum] {
+			colIDs = append(colIDs, cols[idxCol.Offset].ID)
+		}
+		if extStats == nil {
+			extStats = NewExtendedStatsColl()
+		}
+		extStats.Stats[IndexPrefixTopNName(idx.ID)] = &ExtendedStatsItem{
+			Tp:              ast.StatsTypeIndexPrefixTopN,
+			ColIDs:          colIDs,
+			StringVals:      str,
+			IndexPrefixTopN: stats,
+		}
+	}
+	return extStats, nil
+}
+
+// buildIndexPrefixTopN counts the values of the prefixes of the index columns in the sampled rows, and keeps the
+// most frequent ones of each prefix. The values appearing only once are not kept, since they are not skewed.
+func buildIndexPrefixTopN(sc *stmtctx.StatementContext, cols []*model.ColumnInfo, idxCols []*model.IndexColumn,
+	samples []*ReservoirRowSampleItem) (*IndexPrefixTopN, error) {
+	counts := make([]map[string]int64, len(idxCols)+1)
+	for colNum := 2; colNum <= len(idxCols); colNum++ {
+		counts[colNum] = make(map[string]int64)
+	}
+	var tmpDatum types.Datum
+	var err error
+sampleLoop:
+	for _, sample := range samples {
+		b := make([]byte, 0, 8)
+		for i, idxCol := range idxCols {
+			sample.Columns[idxCol.Offset].Copy(&tmpDatum)
+			if idxCol.Length != types.UnspecifiedLength {
+				ranger.CutDatumByPrefixLen(&tmpDatum, idxCol.Length, &cols[idxCol.Offset].FieldType)
+			}
+			// The long values are not sampled by the index stats either.
+			if len(tmpDatum.GetBytes()) > MaxSampleValueLength {
+				continue sampleLoop
+			}
+			b, err = codec.EncodeKey(sc.TimeZone(), b, tmpDatum)
+			err = sc.HandleError(err)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				counts[i+1][string(b)]++
+			}
+		}
+	}
+	s := &IndexPrefixTopN{SampleCount: int64(len(samples))}
+	for colNum := 2; colNum <= len(idxCols); colNum++ {
+		topN := make([]IndexPrefixValue, 0, len(counts[colNum]))
+		for encoded, cnt := range counts[colNum] {
+			if cnt > 1 {
+				topN = append(topN, IndexPrefixValue{Encoded: []byte(encoded), Count: cnt})
+			}
+		}
+		if len(topN) == 0 {
+			continue
+		}
+		slices.SortFunc(topN, func(a, b IndexPrefixValue) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), bytes.Compare(a.Encoded, b.Encoded))
+		})
+		s.Prefixes = append(s.Prefixes, IndexPrefixTopNItem{ColNum: colNum, TopN: topN[:min(len(topN), maxIndexPrefixTopN)]})
+	}
+	return s, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexPrefixTopN(t *testing.T) {
+	sc := stmtctx.NewStmtCtxWithTimeZone(time.Local)
+	cols := make([]*model.ColumnInfo, 0, 3)
+	for i := 0; i < 3; i++ {
+		cols = append(cols, &model.ColumnInfo{ID: int64(i + 1), Offset: i, State: model.StatePublic})
+	}
+	// The columns are (tenant_id, status, created_at). 60 rows are of the tenant 1 with the status 1, 20 rows are of
+	// the tenant 1 with the status 2, and the other rows are of the distinct tenants.
+	samples := make([]*ReservoirRowSampleItem, 0, 100)
+	for i := 0; i < 100; i++ {
+		var row []types.Datum
+		switch {
+		case i < 60:
+			row = types.MakeDatums(1, 1, i)
+		case i < 80:
+			row = types.MakeDatums(1, 2, i)
+		default:
+			row = types.MakeDatums(i, 1, i)
+		}
+		samples = append(samples, &ReservoirRowSampleItem{Columns: row})
+	}
+	indexes := []*model.IndexInfo{
+		{
+			ID:    1,
+			Name:  pmodel.NewCIStr("idx_abc"),
+			State: model.StatePublic,
+			Columns: []*model.IndexColumn{
+				{Offset: 0, Length: types.UnspecifiedLength},
+				{Offset: 1, Length: types.UnspecifiedLength},
+				{Offset: 2, Length: types.UnspecifiedLength},
+			},
+		},
+		{
+			ID:    2,
+			Name:  pmodel.NewCIStr("idx_ab"),
+			State: model.StatePublic,
+			Columns: []*model.IndexColumn{
+				{Offset: 0, Length: types.UnspecifiedLength},
+				{Offset: 1, Length: types.UnspecifiedLength},
+			},
+		},
+	}
+	extStats, err := AppendIndexPrefixTopNs(sc, nil, cols, indexes, samples)
+	require.NoError(t, err)
+	require.NotNil(t, extStats)
+	// The TopN of the full keys of idx_ab are kept in its index stats.
+	require.Len(t, extStats.Stats, 1)
+	item, ok := extStats.Stats[IndexPrefixTopNName(1)]
+	require.True(t, ok)
+	require.Equal(t, "__idx_prefix_topn_1", IndexPrefixTopNName(1))
+	require.Equal(t, ast.StatsTypeIndexPrefixTopN, item.Tp)
+	require.Equal(t, []int64{1, 2}, item.ColIDs)
+
+	// The stats are decoded from the storage.
+	topN, err := DecodeIndexPrefixTopN(item.StringVals)
+	require.NoError(t, err)
+	require.Len(t, topN.Prefixes, 1)
+	// The values of the distinct tenants appear only once, so they are not kept.
+	require.Len(t, topN.Prefixes[0].TopN, 2)
+	encode := func(vals ...any) []byte {
+		b, err := codec.EncodeKey(sc.TimeZone(), nil, types.MakeDatums(vals...)...)
+		require.NoError(t, err)
+		return b
+	}
+	sel, ok := topN.EqualSelectivity(encode(1, 1), 2)
+	require.True(t, ok)
+	require.InDelta(t, 0.6, sel, 1e-9)
+	sel, ok = topN.EqualSelectivity(encode(1, 2), 2)
+	require.True(t, ok)
+	require.InDelta(t, 0.2, sel, 1e-9)
+	_, ok = topN.EqualSelectivity(encode(90, 1), 2)
+	require.False(t, ok)
+	// Only the prefixes of multiple columns are collected.
+	_, ok = topN.EqualSelectivity(encode(1), 1)
+	require.False(t, ok)
+	var nilTopN *IndexPrefixTopN
+	_, ok = nilTopN.EqualSelectivity(encode(1, 1), 2)
+	require.False(t, ok)
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+// IndexStatsMeta is the meta of the stats of an index kept in mysql.stats_histograms, which is tracked separately from
+// its table. The analyses of a table may not cover all its indexes, e.g. the analyses of the specified indexes in the
+// stats version 1 and the analyses whose tasks of some indexes fail, so the stats of these indexes are staler than the
+// table.
+type IndexStatsMeta struct {
+	// ID is the ID of the index.
+	ID int64
+	// Version is the version of the index stats, which is the TSO of the last analysis of the index.
+	Version uint64
+	// StatsVer is the version of the stats format, Version0 means the index has never been analyzed.
+	StatsVer int64
+	// MissedModifyCount is the number of the modifications of the table missed by the index stats. The modify count of
+	// the table is reset by its analyses, so the modifications before the analyses which don't cover the index are
+	// carried over to the index.
+	MissedModifyCount int64
+}
+
+// IsAnalyzed returns whether the index has been analyzed.
+func (m *IndexStatsMeta) IsAnalyzed() bool {
+	return m.StatsVer != Version0
+}
+
+// ModifyCount returns the number of the modifications of the table since the last analysis of the index.
+func (m *IndexStatsMeta) ModifyCount(tbl *Table) int64 {
+	return m.MissedModifyCount + tbl.ModifyCount
+}
+
+// Healthy returns the stats healthy of the index in the same way as Table.GetStatsHealthy, but by the modifications
+// since the last analysis of the index.
+func (m *IndexStatsMeta) Healthy(tbl *Table) int64 {
+	if !m.IsAnalyzed() {
+		return 0
+	}
+	return tbl.statsHealthy(m.ModifyCount(tbl))
+}
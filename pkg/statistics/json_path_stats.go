@@ -0,0 +1,345 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+const (
+	// maxJSONPathStatsPaths is the max number of the most frequent paths kept for a JSON column.
+	maxJSONPathStatsPaths = 32
+	// maxJSONPathStatsTopN is the max number of the most frequent scalar values kept for a path.
+	maxJSONPathStatsTopN = 10
+	// maxJSONPathStatsDepth is the max depth of the paths collected from the JSON documents.
+	maxJSONPathStatsDepth = 8
+	// maxJSONPathCandidates is the max number of distinct paths tracked while collecting a JSON column.
+	maxJSONPathCandidates = 1024
+	// maxJSONPathValueCandidates is the max number of distinct values tracked while collecting a path.
+	maxJSONPathValueCandidates = 1024
+	// maxJSONPathTopNValueLen is the max length of the values kept in the TopN of a path.
+	maxJSONPathTopNValueLen = 64
+	// maxJSONPathStatsSize is the max size of the encoded JSON path stats, it must fit in mysql.stats_extended.stats.
+	maxJSONPathStatsSize = 60 * 1024
+)
+
+// JSONPathStats is the path-level statistics of a JSON column. It's collected from the samples of ANALYZE and
+// kept in mysql.stats_extended as the type StatsTypeJSONPath. All the counts are in the unit of sampled rows.
+type JSONPathStats struct {
+	// SampleCount is the number of sampled rows, including the rows whose JSON value is NULL.
+	SampleCount int64 `json:"sample_count"`
+	// Paths are the most frequent paths sorted by Count in descending order.
+	Paths []*JSONPathItem `json:"paths"`
+
+	pathMap map[string]*JSONPathItem
+}
+
+// JSONPathItem is the statistics of a path in a JSON column. The path is in the canonical form of the JSON path
+// expression, and the elements of the arrays are represented by `[*]`, such as `$.a.b` and `$.tags[*]`.
+type JSONPathItem struct {
+	Path string `json:"path"`
+	// Count is the number of sampled rows containing the path.
+	Count int64 `json:"count"`
+	// ValueCount is the number of the scalar values on the path, the same value is counted once in a row.
+	ValueCount int64 `json:"value_count"`
+	// NDV is the number of distinct scalar values on the path.
+	NDV int64 `json:"ndv"`
+	// TopN is the most frequent scalar values on the path.
+	TopN []JSONPathValue `json:"topn,omitempty"`
+}
+
+// JSONPathValue is a scalar value on a path and the number of sampled rows containing it.
+type JSONPathValue struct {
+	// Value is the JSON text of the scalar value, e.g. `"abc"`, `1` and `true`.
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+
+	bj types.BinaryJSON
+}
+
+// JSONPathStatsName returns the name of the JSON path stats of the column in mysql.stats_extended.
+func JSONPathStatsName(colID int64) string {
+	return fmt.Sprintf("__json_path_%d", colID)
+}
+
+// DecodeJSONPathStats decodes the JSON path stats from the stats of mysql.stats_extended.
+func DecodeJSONPathStats(str string) (*JSONPathStats, error) {
+	s := &JSONPathStats{}
+	if err := json.Unmarshal([]byte(str), s); err != nil {
+		return nil, errors.Trace(err)
+	}
+	s.pathMap = make(map[string]*JSONPathItem, len(s.Paths))
+	for _, item := range s.Paths {
+		for i := range item.TopN {
+			bj, err := types.ParseBinaryJSONFromString(item.TopN[i].Value)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			item.TopN[i].bj = bj
+		}
+		s.pathMap[item.Path] = item
+	}
+	return s, nil
+}
+
+// GetPath returns the statistics of the path, it returns nil if the path is not kept in the stats.
+func (s *JSONPathStats) GetPath(path string) *JSONPathItem {
+	return s.pathMap[path]
+}
+
+// PathSelectivity returns the fraction of rows containing the path.
+func (s *JSONPathStats) PathSelectivity(path string) (float64, bool) {
+	item := s.GetPath(path)
+	if item == nil || s.SampleCount <= 0 {
+		return 0, false
+	}
+	return float64(item.Count) / float64(s.SampleCount), true
+}
+
+// ValueSelectivity returns the fraction of rows containing a scalar value matched by the `match` function on the path.
+// The values in TopN are checked first, otherwise the value is assumed to be one of the remaining distinct values.
+func (s *JSONPathStats) ValueSelectivity(path string, match func(types.BinaryJSON) bool) (float64, bool) {
+	item := s.GetPath(path)
+	if item == nil || s.SampleCount <= 0 {
+		return 0, false
+	}
+	// There are only objects or arrays on the path.
+	if item.ValueCount == 0 {
+		return 0, true
+	}
+	var matched, topNCount int64
+	for _, v := range item.TopN {
+		topNCount += v.Count
+		if match(v.bj) {
+			matched += v.Count
+		}
+	}
+	if matched > 0 {
+		return min(float64(matched)/float64(s.SampleCount), 1), true
+	}
+	remainedNDV := item.NDV - int64(len(item.TopN))
+	remainedCount := item.ValueCount - topNCount
+	if remainedNDV <= 0 || remainedCount <= 0 {
+		// The value is not sampled, so it's assumed to appear in half a sampled row.
+		return 0.5 / float64(s.SampleCount), true
+	}
+	return float64(remainedCount) / float64(remainedNDV) / float64(s.SampleCount), true
+}
+
+type jsonPathCollector struct {
+	count      int64
+	valueCount int64
+	values     map[string]int64
+	// untracked is the count of the values which are not tracked since there are too many distinct values.
+	untracked int64
+}
+
+// jsonPathStatsCollector collects the JSON path stats of a JSON column from the sampled rows.
+type jsonPathStatsCollector struct {
+	sampleCount int64
+	paths       map[string]*jsonPathCollector
+	// childPaths caches the canonical paths of the object members, whose key is `parent path + \x00 + member key`.
+	childPaths map[string]string
+	// seenPaths and seenValues dedup the paths and values in a row.
+	seenPaths  map[string]struct{}
+	seenValues map[[2]string]struct{}
+}
+
+func newJSONPathStatsCollector() *jsonPathStatsCollector {
+	return &jsonPathStatsCollector{
+		paths:      make(map[string]*jsonPathCollector),
+		childPaths: make(map[string]string),
+		seenPaths:  make(map[string]struct{}),
+		seenValues: make(map[[2]string]struct{}),
+	}
+}
+
+func (c *jsonPathStatsCollector) collect(d *types.Datum) {
+	c.sampleCount++
+	if d.IsNull() || d.Kind() != types.KindMysqlJSON {
+		return
+	}
+	clear(c.seenPaths)
+	clear(c.seenValues)
+	c.walk("$", d.GetMysqlJSON(), 0)
+	for path := range c.seenPaths {
+		if p := c.getPath(path); p != nil {
+			p.count++
+		}
+	}
+	for pv := range c.seenValues {
+		p := c.getPath(pv[0])
+		if p == nil {
+			continue
+		}
+		p.valueCount++
+		if _, ok := p.values[pv[1]]; ok || len(p.values) < maxJSONPathValueCandidates {
+			p.values[pv[1]]++
+		} else {
+			p.untracked++
+		}
+	}
+}
+
+func (c *jsonPathStatsCollector) getPath(path string) *jsonPathCollector {
+	p, ok := c.paths[path]
+	if !ok {
+		if len(c.paths) >= maxJSONPathCandidates {
+			return nil
+		}
+		p = &jsonPathCollector{values: make(map[string]int64)}
+		c.paths[path] = p
+	}
+	return p
+}
+
+func (c *jsonPathStatsCollector) walk(path string, bj types.BinaryJSON, depth int) {
+	c.seenPaths[path] = struct{}{}
+	switch bj.TypeCode {
+	case types.JSONTypeCodeObject:
+		if depth >= maxJSONPathStatsDepth {
+			return
+		}
+		for i := 0; i < bj.GetElemCount(); i++ {
+			key, val := bj.ObjectGetKeyAndVal(i)
+			childPath, ok := c.childPath(path, key)
+			if !ok {
+				continue
+			}
+			c.walk(childPath, val, depth+1)
+		}
+	case types.JSONTypeCodeArray:
+		if depth >= maxJSONPathStatsDepth {
+			return
+		}
+		elemPath := path + "[*]"
+		for i := 0; i < bj.GetElemCount(); i++ {
+			c.walk(elemPath, bj.ArrayGetElem(i), depth+1)
+		}
+	default:
+		c.seenValues[[2]string{path, bj.String()}] = struct{}{}
+	}
+}
+
+// childPath returns the canonical path of the member `key` of the object on the path `parent`.
+func (c *jsonPathStatsCollector) childPath(parent string, key []byte) (string, bool) {
+	cacheKey := parent + "\x00" + string(key)
+	if path, ok := c.childPaths[cacheKey]; ok {
+		return path, path != ""
+	}
+	var path string
+	pe, err := types.ParseJSONPathExpr(parent + "." + types.CreateBinaryJSON(string(key)).String())
+	if err == nil {
+		path = pe.String()
+	}
+	c.childPaths[cacheKey] = path
+	return path, path != ""
+}
+
+func (c *jsonPathStatsCollector) build() *JSONPathStats {
+	items := make([]*JSONPathItem, 0, len(c.paths))
+	for path, p := range c.paths {
+		item := &JSONPathItem{
+			Path:       path,
+			Count:      p.count,
+			ValueCount: p.valueCount,
+			// The untracked values are assumed to be distinct.
+			NDV: int64(len(p.values)) + p.untracked,
+		}
+		topN := make([]JSONPathValue, 0, len(p.values))
+		for v, cnt := range p.values {
+			if len(v) <= maxJSONPathTopNValueLen {
+				topN = append(topN, JSONPathValue{Value: v, Count: cnt})
+			}
+		}
+		slices.SortFunc(topN, func(a, b JSONPathValue) int {
+			return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Value, b.Value))
+		})
+		if len(topN) > maxJSONPathStatsTopN {
+			topN = topN[:maxJSONPathStatsTopN]
+		}
+		if len(topN) > 0 {
+			item.TopN = topN
+		}
+		items = append(items, item)
+	}
+	slices.SortFunc(items, func(a, b *JSONPathItem) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Path, b.Path))
+	})
+	if len(items) > maxJSONPathStatsPaths {
+		items = items[:maxJSONPathStatsPaths]
+	}
+	return &JSONPathStats{SampleCount: c.sampleCount, Paths: items}
+}
+
+// encodeJSONPathStats encodes the JSON path stats, the least frequent paths are dropped if it's too large.
+func encodeJSONPathStats(s *JSONPathStats) (string, error) {
+	for {
+		bytes, err := json.Marshal(s)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if len(bytes) <= maxJSONPathStatsSize || len(s.Paths) == 0 {
+			return string(bytes), nil
+		}
+		s.Paths = s.Paths[:len(s.Paths)/2]
+	}
+}
+
+// AppendJSONPathStats builds the JSON path stats for the JSON columns from the sampled rows of ANALYZE, and appends
+// them to the extended stats.
+func AppendJSONPathStats(extStats *ExtendedStatsColl, cols []*model.ColumnInfo, samples []*ReservoirRowSampleItem) (*ExtendedStatsColl, error) {
+	for i, col := range cols {
+		if col.GetType() != mysql.TypeJSON {
+			continue
+		}
+		collector := newJSONPathStatsCollector()
+		for _, sample := range samples {
+			collector.collect(&sample.Columns[i])
+		}
+		stats := collector.build()
+		if len(stats.Paths) == 0 {
+			continue
+		}
+		str, err := encodeJSONPathStats(stats)
+		if err != nil {
+			return nil, err
+		}
+		// Decode it again to build the index of the paths and the TopN values.
+		stats, err = DecodeJSONPathStats(str)
+		if err != nil {
+			return nil, err
+		}
+		if extStats == nil {
+			extStats = NewExtendedStatsColl()
+		}
+		extStats.Stats[JSONPathStatsName(col.ID)] = &ExtendedStatsItem{
+			Tp:         ast.StatsTypeJSONPath,
+			ColIDs:     []int64{col.ID},
+			StringVals: str,
+			JSONPaths:  stats,
+		}
+	}
+	return extStats, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPathStats(t *testing.T) {
+	samples := make([]*ReservoirRowSampleItem, 0, 100)
+	for i := 0; i < 100; i++ {
+		var d types.Datum
+		switch {
+		case i < 10:
+			d.SetNull()
+		case i < 60:
+			bj, err := types.ParseBinaryJSONFromString(fmt.Sprintf(`{"a": "x", "b": {"c": %d}, "tags": [1, 2, 2], "my key": true}`, i))
+			require.NoError(t, err)
+			d.SetMysqlJSON(bj)
+		default:
+			bj, err := types.ParseBinaryJSONFromString(fmt.Sprintf(`{"a": "y%d", "tags": [3]}`, i%4))
+			require.NoError(t, err)
+			d.SetMysqlJSON(bj)
+		}
+		samples = append(samples, &ReservoirRowSampleItem{Columns: []types.Datum{types.NewIntDatum(int64(i)), d}})
+	}
+	cols := []*model.ColumnInfo{
+		{ID: 1, Name: pmodel.NewCIStr("id"), FieldType: *types.NewFieldType(mysql.TypeLonglong)},
+		{ID: 2, Name: pmodel.NewCIStr("j"), FieldType: *types.NewFieldType(mysql.TypeJSON)},
+	}
+	extStats, err := AppendJSONPathStats(nil, cols, samples)
+	require.NoError(t, err)
+	require.Len(t, extStats.Stats, 1)
+	item := extStats.Stats[JSONPathStatsName(2)]
+	require.Equal(t, []int64{2}, item.ColIDs)
+
+	// The stats can be decoded from the stored string.
+	s, err := DecodeJSONPathStats(item.StringVals)
+	require.NoError(t, err)
+	require.Equal(t, item.JSONPaths.Paths, s.Paths)
+	require.Equal(t, int64(100), s.SampleCount)
+	require.Equal(t, "$", s.Paths[0].Path)
+	require.Equal(t, int64(90), s.Paths[0].Count)
+
+	sel, ok := s.PathSelectivity("$.b.c")
+	require.True(t, ok)
+	require.Equal(t, 0.5, sel)
+	sel, ok = s.PathSelectivity(`$."my key"`)
+	require.True(t, ok)
+	require.Equal(t, 0.5, sel)
+	_, ok = s.PathSelectivity("$.d")
+	require.False(t, ok)
+
+	eq := func(str string) func(types.BinaryJSON) bool {
+		expected, err := types.ParseBinaryJSONFromString(str)
+		require.NoError(t, err)
+		return func(bj types.BinaryJSON) bool { return types.CompareBinaryJSON(bj, expected) == 0 }
+	}
+	sel, ok = s.ValueSelectivity("$.a", eq(`"x"`))
+	require.True(t, ok)
+	require.Equal(t, 0.5, sel)
+	sel, ok = s.ValueSelectivity("$.a", eq(`"y1"`))
+	require.True(t, ok)
+	require.Equal(t, 0.1, sel)
+	// The duplicated elements are counted once in a row.
+	sel, ok = s.ValueSelectivity("$.tags[*]", eq("2"))
+	require.True(t, ok)
+	require.Equal(t, 0.5, sel)
+	sel, ok = s.ValueSelectivity("$.tags[*]", eq("3"))
+	require.True(t, ok)
+	require.Equal(t, 0.4, sel)
+	// $.b.c has 50 distinct values, and only the top 10 values are kept in the TopN.
+	path := s.GetPath("$.b.c")
+	require.Equal(t, int64(50), path.NDV)
+	require.Len(t, path.TopN, maxJSONPathStatsTopN)
+	sel, ok = s.ValueSelectivity("$.b.c", eq("1000"))
+	require.True(t, ok)
+	require.Equal(t, 0.01, sel)
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+)
+
+// MergeTableStats merges the partial stats into the base stats of a table. The partial stats are built on the rows
+// which are not counted by the base stats, e.g. computed by an external job over the newly inserted rows, so the
+// merged stats describe the union of the rows, just like merging the stats of the partitions into the global stats.
+// The returned table only holds the columns and indices of the partial stats, whose stats are merged if the base
+// stats have them, or taken from the partial stats otherwise, and its row count is the sum of the row counts.
+// Only the stats of version 2 can be merged.
+func MergeTableStats(sc *stmtctx.StatementContext, base, partial *Table) (*Table, error) {
+	merged := &Table{
+		HistColl:              *NewHistColl(base.PhysicalID, true, base.RealtimeCount+partial.RealtimeCount, base.ModifyCount, partial.ColNum(), partial.IdxNum()),
+		ColAndIdxExistenceMap: NewColAndIndexExistenceMap(partial.ColNum(), partial.IdxNum()),
+	}
+	merged.StatsVer = partial.StatsVer
+	var err error
+	partial.ForEachColumnImmutable(func(id int64, col *Column) bool {
+		if col.GetStatsVer() != Version2 {
+			err = errors.Errorf("the stats of column %s are of version %d, only the stats of version 2 can be merged", col.Info.Name.O, col.GetStatsVer())
+			return true
+		}
+		mergedCol := col.Copy()
+		if baseCol := base.GetCol(id); baseCol != nil && baseCol.IsAnalyzed() {
+			if baseCol.GetStatsVer() != Version2 {
+				err = errors.Errorf("the stats of column %s in the table are of version %d, only the stats of version 2 can be merged", col.Info.Name.O, baseCol.GetStatsVer())
+				return true
+			}
+			var hist *Histogram
+			hist, mergedCol.TopN, err = mergeHistAndTopN(sc, &baseCol.Histogram, &col.Histogram, baseCol.TopN, col.TopN, false)
+			if err != nil {
+				return true
+			}
+			mergedCol.FMSketch = baseCol.FMSketch.Copy()
+			if mergedCol.FMSketch != nil && col.FMSketch != nil {
+				mergedCol.FMSketch.MergeFMSketch(col.FMSketch)
+				hist.NDV = min(mergedCol.FMSketch.NDV(), merged.RealtimeCount)
+			} else {
+				// The NDV of the union can't be known without the sketches, the larger one is the lower bound of it.
+				hist.NDV = max(baseCol.Histogram.NDV, col.Histogram.NDV)
+			}
+			hist.Correlation = mergeCorrelation(&baseCol.Histogram, &col.Histogram)
+			mergedCol.Histogram = *hist
+		}
+		merged.SetCol(id, mergedCol)
+		merged.ColAndIdxExistenceMap.InsertCol(id, true)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	partial.ForEachIndexImmutable(func(id int64, idx *Index) bool {
+		if idx.GetStatsVer() != Version2 {
+			err = errors.Errorf("the stats of index %s are of version %d, only the stats of version 2 can be merged", idx.Info.Name.O, idx.GetStatsVer())
+			return true
+		}
+		mergedIdx := idx.Copy()
+		if baseIdx := base.GetIdx(id); baseIdx != nil && baseIdx.IsAnalyzed() {
+			if baseIdx.GetStatsVer() != Version2 {
+				err = errors.Errorf("the stats of index %s in the table are of version %d, only the stats of version 2 can be merged", idx.Info.Name.O, baseIdx.GetStatsVer())
+				return true
+			}
+			var hist *Histogram
+			hist, mergedIdx.TopN, err = mergeHistAndTopN(sc, &baseIdx.Histogram, &idx.Histogram, baseIdx.TopN, idx.TopN, true)
+			if err != nil {
+				return true
+			}
+			hist.NDV = max(baseIdx.Histogram.NDV, idx.Histogram.NDV)
+			mergedIdx.Histogram = *hist
+		}
+		merged.SetIdx(id, mergedIdx)
+		merged.ColAndIdxExistenceMap.InsertIndex(id, true)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// mergeHistAndTopN merges the histograms and the TopNs built on the disjoint sets of rows. The sizes of the results
+// are the larger ones of the inputs.
+func mergeHistAndTopN(sc *stmtctx.StatementContext, lHist, rHist *Histogram, lTopN, rTopN *TopN, isIndex bool) (*Histogram, *TopN, error) {
+	// The TopN is merged before the histogram, since the values popped from the TopN are merged into the histogram.
+	topN, popped := MergeTopN([]*TopN{lTopN, rTopN}, uint32(max(lTopN.Num(), rTopN.Num())))
+	hist, err := MergePartitionHist2GlobalHist(sc, []*Histogram{lHist, rHist}, popped, int64(max(lHist.Len(), rHist.Len(), 1)), isIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The bucket NDVs tend to be underestimated after merging, so they are not used, see the merge of the global stats.
+	for i := range hist.Buckets {
+		hist.Buckets[i].NDV = 0
+	}
+	hist.LastUpdateVersion = max(lHist.LastUpdateVersion, rHist.LastUpdateVersion)
+	return hist, topN, nil
+}
+
+// mergeCorrelation returns the average of the correlations weighted by the row counts.
+func mergeCorrelation(lHist, rHist *Histogram) float64 {
+	lCount, rCount := lHist.TotalRowCount(), rHist.TotalRowCount()
+	if lCount+rCount == 0 {
+		return 0
+	}
+	return (lHist.Correlation*lCount + rHist.Correlation*rCount) / (lCount + rCount)
+}
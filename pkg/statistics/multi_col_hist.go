@@ -0,0 +1,300 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"slices"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+)
+
+// multiColHistPartitions is the number of the partitions on each column of the multi-column histogram, so the
+// histogram has at most multiColHistPartitions * multiColHistPartitions buckets.
+const multiColHistPartitions = 16
+
+// MultiColHistogram is the 2-dimensional histogram of the histogram extended stats, which tells the joint
+// distribution of 2 columns. It's built from the sampled rows whose values of both columns are not NULL: the rows are
+// split into equi-depth partitions on the first column, and each partition is split into equi-depth buckets on the
+// second column. Each bucket keeps the bounding box of its rows. All the counts are in the unit of sampled rows.
+type MultiColHistogram struct {
+	// SampleCount is the number of sampled rows, including the rows whose value of either column is NULL.
+	SampleCount int64            `json:"sample_count"`
+	Buckets     []MultiColBucket `json:"buckets"`
+}
+
+// MultiColBucket is a bucket of the multi-column histogram. Lower and Upper are the inclusive bounds of the values of
+// the 2 columns encoded by codec.EncodeKey, and NDV is the number of distinct values of each column in the bucket.
+type MultiColBucket struct {
+	Lower [2][]byte `json:"lower"`
+	Upper [2][]byte `json:"upper"`
+	NDV   [2]int64  `json:"ndv"`
+	Count int64     `json:"count"`
+}
+
+// DecodeMultiColHistogram decodes the multi-column histogram from the stats of mysql.stats_extended.
+func DecodeMultiColHistogram(str string) (*MultiColHistogram, error) {
+	h := &MultiColHistogram{}
+	if err := json.Unmarshal([]byte(str), h); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return h, nil
+}
+
+// Selectivity returns the fraction of rows satisfying both the ranges of the first column and the ones of the second
+// column. The values are assumed to be uniformly distributed and independent within each bucket.
+func (h *MultiColHistogram) Selectivity(sc *stmtctx.StatementContext, ranges0, ranges1 []*ranger.Range) (float64, error) {
+	if h.SampleCount <= 0 {
+		return 0, nil
+	}
+	encoded0, err := encodeMultiColHistRanges(sc, ranges0)
+	if err != nil {
+		return 0, err
+	}
+	encoded1, err := encodeMultiColHistRanges(sc, ranges1)
+	if err != nil {
+		return 0, err
+	}
+	var count float64
+	for i := range h.Buckets {
+		bkt := &h.Buckets[i]
+		frac0 := bkt.rangesFraction(0, encoded0)
+		if frac0 == 0 {
+			continue
+		}
+		count += float64(bkt.Count) * frac0 * bkt.rangesFraction(1, encoded1)
+	}
+	return min(count/float64(h.SampleCount), 1), nil
+}
+
+// multiColHistRange is a range whose bounds are encoded by codec.EncodeKey.
+type multiColHistRange struct {
+	low, high               []byte
+	lowExclude, highExclude bool
+	isPoint                 bool
+}
+
+func encodeMultiColHistRanges(sc *stmtctx.StatementContext, ranges []*ranger.Range) ([]multiColHistRange, error) {
+	ret := make([]multiColHistRange, 0, len(ranges))
+	for _, ran := range ranges {
+		if len(ran.LowVal) != 1 || len(ran.HighVal) != 1 {
+			return nil, errors.Errorf("the ranges of the multi-column histogram must be on a single column")
+		}
+		low, err := codec.EncodeKey(sc.TimeZone(), nil, ran.LowVal[0])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		high, err := codec.EncodeKey(sc.TimeZone(), nil, ran.HighVal[0])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ret = append(ret, multiColHistRange{
+			low:         low,
+			high:        high,
+			lowExclude:  ran.LowExclude,
+			highExclude: ran.HighExclude,
+			isPoint:     !ran.LowExclude && !ran.HighExclude && bytes.Equal(low, high),
+		})
+	}
+	return ret, nil
+}
+
+// rangesFraction returns the fraction of the rows of the bucket whose values of the column are in the ranges.
+func (b *MultiColBucket) rangesFraction(col int, ranges []multiColHistRange) float64 {
+	lower, upper := b.Lower[col], b.Upper[col]
+	var frac float64
+	for _, ran := range ranges {
+		if c := bytes.Compare(ran.high, lower); c < 0 || (c == 0 && ran.highExclude) {
+			continue
+		}
+		if c := bytes.Compare(ran.low, upper); c > 0 || (c == 0 && ran.lowExclude) {
+			continue
+		}
+		if ran.isPoint {
+			frac += 1 / float64(max(b.NDV[col], 1))
+			continue
+		}
+		if bytes.Compare(ran.low, lower) <= 0 && bytes.Compare(ran.high, upper) >= 0 {
+			frac += 1
+			continue
+		}
+		frac += partialMultiColBucketFraction(lower, upper, ran)
+	}
+	return min(frac, 1)
+}
+
+// partialMultiColBucketFraction estimates the fraction of the bucket covered by the range which partially overlaps
+// with the bucket by interpolating the values. It returns 0.5 if the values can't be interpolated.
+func partialMultiColBucketFraction(lower, upper []byte, ran multiColHistRange) float64 {
+	if bytes.Equal(lower, upper) {
+		return 1
+	}
+	lowerVal, ok1 := encodedScalar(lower)
+	upperVal, ok2 := encodedScalar(upper)
+	if !ok1 || !ok2 || upperVal <= lowerVal {
+		return 0.5
+	}
+	from, to := lowerVal, upperVal
+	if bytes.Compare(ran.low, lower) > 0 {
+		v, ok := encodedScalar(ran.low)
+		if !ok {
+			return 0.5
+		}
+		from = v
+	}
+	if bytes.Compare(ran.high, upper) < 0 {
+		v, ok := encodedScalar(ran.high)
+		if !ok {
+			return 0.5
+		}
+		to = v
+	}
+	return calcFraction(lowerVal, upperVal, to) - calcFraction(lowerVal, upperVal, from)
+}
+
+// encodedScalar converts the numeric or temporal value encoded by codec.EncodeKey to a float64 keeping the order.
+func encodedScalar(b []byte) (float64, bool) {
+	_, d, err := codec.DecodeOne(b)
+	if err != nil {
+		return 0, false
+	}
+	switch d.Kind() {
+	case types.KindInt64, types.KindUint64, types.KindFloat32, types.KindFloat64, types.KindMysqlDecimal:
+		v := convertDatumToScalar(&d, 0)
+		return v, !math.IsNaN(v) && !math.IsInf(v, 0)
+	}
+	return 0, false
+}
+
+type multiColHistPair struct {
+	keys [2][]byte
+}
+
+// buildMultiColHistogram builds the multi-column histogram of the 2 columns from their sample collectors.
+func buildMultiColHistogram(sctx sessionctx.Context, collectors [2]*SampleCollector) (*MultiColHistogram, error) {
+	tz := sctx.GetSessionVars().StmtCtx.TimeZone()
+	// The NULL values are not sampled, so the samples of the two columns are joined by their ordinals.
+	keys1 := make(map[int][]byte, len(collectors[1].Samples))
+	for _, sample := range collectors[1].Samples {
+		key, err := codec.EncodeKey(tz, nil, sample.Value)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		keys1[sample.Ordinal] = key
+	}
+	pairs := make([]multiColHistPair, 0, len(collectors[0].Samples))
+	for _, sample := range collectors[0].Samples {
+		key1, ok := keys1[sample.Ordinal]
+		if !ok {
+			continue
+		}
+		key0, err := codec.EncodeKey(tz, nil, sample.Value)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		pairs = append(pairs, multiColHistPair{keys: [2][]byte{key0, key1}})
+	}
+	// The sampled rows include the ones whose value of the first column is NULL, which are not sampled.
+	sampleCount := int64(len(collectors[0].Samples))
+	if collectors[0].Count > 0 {
+		sampleCount = int64(math.Round(float64(sampleCount) * float64(collectors[0].Count+collectors[0].NullCount) / float64(collectors[0].Count)))
+	}
+	h := &MultiColHistogram{SampleCount: max(sampleCount, int64(len(pairs)))}
+	if len(pairs) == 0 {
+		return h, nil
+	}
+	sortPairs := func(pairs []multiColHistPair, col int) {
+		slices.SortFunc(pairs, func(a, b multiColHistPair) int {
+			return bytes.Compare(a.keys[col], b.keys[col])
+		})
+	}
+	sortPairs(pairs, 0)
+	for _, partition := range splitMultiColHistPairs(pairs, 0) {
+		sortPairs(partition, 1)
+		for _, bucketPairs := range splitMultiColHistPairs(partition, 1) {
+			h.Buckets = append(h.Buckets, newMultiColBucket(bucketPairs))
+		}
+	}
+	return h, nil
+}
+
+// splitMultiColHistPairs splits the pairs sorted by the column into equi-depth groups, the pairs with the same value
+// of the column are kept in the same group.
+func splitMultiColHistPairs(pairs []multiColHistPair, col int) [][]multiColHistPair {
+	depth := max((len(pairs)+multiColHistPartitions-1)/multiColHistPartitions, 1)
+	groups := make([][]multiColHistPair, 0, multiColHistPartitions)
+	start := 0
+	for start < len(pairs) {
+		end := min(start+depth, len(pairs))
+		for end < len(pairs) && bytes.Equal(pairs[end].keys[col], pairs[end-1].keys[col]) {
+			end++
+		}
+		groups = append(groups, pairs[start:end])
+		start = end
+	}
+	return groups
+}
+
+func newMultiColBucket(pairs []multiColHistPair) MultiColBucket {
+	bkt := MultiColBucket{Count: int64(len(pairs))}
+	for col := 0; col < 2; col++ {
+		keys := make([][]byte, 0, len(pairs))
+		for _, p := range pairs {
+			keys = append(keys, p.keys[col])
+		}
+		slices.SortFunc(keys, bytes.Compare)
+		keys = slices.CompactFunc(keys, bytes.Equal)
+		bkt.Lower[col], bkt.Upper[col] = keys[0], keys[len(keys)-1]
+		bkt.NDV[col] = int64(len(keys))
+	}
+	return bkt
+}
+
+// fillExtStatsHistVals builds the multi-column histogram of the histogram extended stats.
+func fillExtStatsHistVals(sctx sessionctx.Context, item *ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*SampleCollector) *ExtendedStatsItem {
+	var histCollectors [2]*SampleCollector
+	found := 0
+	for i, id := range item.ColIDs {
+		for j, col := range cols {
+			if col.ID == id && i < 2 {
+				histCollectors[i] = collectors[j]
+				found++
+				break
+			}
+		}
+	}
+	if len(item.ColIDs) != 2 || found != 2 {
+		return nil
+	}
+	h, err := buildMultiColHistogram(sctx, histCollectors)
+	if err != nil {
+		return nil
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil
+	}
+	item.StringVals = string(data)
+	item.MultiColHist = h
+	return item
+}
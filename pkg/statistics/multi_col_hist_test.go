@@ -0,0 +1,84 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/mock"
+	"github.com/pingcap/tidb/pkg/util/ranger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiColHistogram(t *testing.T) {
+	ctx := mock.NewContext()
+	sc := ctx.GetSessionVars().StmtCtx
+	// b is always a + 10, like the start time and the end time of the tasks of the same duration.
+	var collectors [2]*SampleCollector
+	for i := range collectors {
+		collectors[i] = &SampleCollector{}
+	}
+	for i := 0; i < 1000; i++ {
+		collectors[0].Samples = append(collectors[0].Samples, &SampleItem{Value: types.NewIntDatum(int64(i)), Ordinal: i})
+		collectors[1].Samples = append(collectors[1].Samples, &SampleItem{Value: types.NewIntDatum(int64(i + 10)), Ordinal: i})
+	}
+	// The rows whose value of b is NULL are not sampled for b.
+	for i := 1000; i < 1100; i++ {
+		collectors[0].Samples = append(collectors[0].Samples, &SampleItem{Value: types.NewIntDatum(int64(i)), Ordinal: i})
+	}
+	collectors[0].Count, collectors[1].Count, collectors[1].NullCount = 1100, 1000, 100
+	h, err := buildMultiColHistogram(ctx, collectors)
+	require.NoError(t, err)
+	require.Equal(t, int64(1100), h.SampleCount)
+	require.LessOrEqual(t, len(h.Buckets), multiColHistPartitions*multiColHistPartitions)
+	var count int64
+	for _, bkt := range h.Buckets {
+		count += bkt.Count
+	}
+	require.Equal(t, int64(1000), count)
+
+	// The histogram can be decoded from the stored string.
+	data, err := json.Marshal(h)
+	require.NoError(t, err)
+	h, err = DecodeMultiColHistogram(string(data))
+	require.NoError(t, err)
+
+	lessThan := func(v int64) []*ranger.Range {
+		return []*ranger.Range{{LowVal: []types.Datum{types.MinNotNullDatum()}, HighVal: []types.Datum{types.NewIntDatum(v)}, HighExclude: true}}
+	}
+	greaterThan := func(v int64) []*ranger.Range {
+		return []*ranger.Range{{LowVal: []types.Datum{types.NewIntDatum(v)}, HighVal: []types.Datum{types.MaxValueDatum()}, LowExclude: true}}
+	}
+	// a < 100 and b > 900 matches no rows, which is 0.1 * 0.1 if the columns were independent.
+	sel, err := h.Selectivity(sc, lessThan(100), greaterThan(900))
+	require.NoError(t, err)
+	require.Equal(t, 0.0, sel)
+	// a < 100 and b < 110 matches the same rows as a < 100.
+	sel, err = h.Selectivity(sc, lessThan(100), lessThan(110))
+	require.NoError(t, err)
+	require.InDelta(t, 100.0/1100, sel, 0.01)
+	// The point ranges are estimated by the NDVs of the buckets.
+	point := func(v int64) []*ranger.Range {
+		return []*ranger.Range{{LowVal: []types.Datum{types.NewIntDatum(v)}, HighVal: []types.Datum{types.NewIntDatum(v)}}}
+	}
+	sel, err = h.Selectivity(sc, point(500), point(510))
+	require.NoError(t, err)
+	require.InDelta(t, 1.0/1100, sel, 0.001)
+	sel, err = h.Selectivity(sc, point(500), point(600))
+	require.NoError(t, err)
+	require.Equal(t, 0.0, sel)
+}
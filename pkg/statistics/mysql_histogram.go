@@ -0,0 +1,169 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/pingcap/tidb/pkg/util/codec"
+)
+
+// mysqlHistogramTimeFormat is the format of the "last-updated" of the MySQL histograms.
+const mysqlHistogramTimeFormat = "2006-01-02 15:04:05.000000"
+
+type mysqlHistogramBucket struct {
+	encoded      []byte
+	lower, upper types.Datum
+	count        float64
+	ndv          int64
+}
+
+// MySQLHistogram converts the stats of the column to the JSON histogram of MySQL 8.0, which is shown in
+// INFORMATION_SCHEMA.COLUMN_STATISTICS. It's an equi-height histogram, whose buckets are the TopN values as the buckets
+// of single values and the buckets of the histogram, ordered by their values. As in MySQL, the cumulative frequencies
+// of the buckets and the frequency of NULLs are the fractions of all the rows.
+func (c *Column) MySQLHistogram(loc *time.Location, lastUpdated time.Time, samplingRate float64, bucketsSpecified int64) (map[string]any, error) {
+	tp := c.Histogram.Tp
+	buckets := make([]mysqlHistogramBucket, 0, c.Histogram.Len())
+	// The TopN values are kept out of the histogram only since the version 2.
+	if c.StatsVer >= Version2 && c.TopN != nil {
+		for _, meta := range c.TopN.TopN {
+			vals, _, err := codec.DecodeRange(meta.Encoded, 1, []byte{tp.GetType()}, loc)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			buckets = append(buckets, mysqlHistogramBucket{
+				encoded: meta.Encoded,
+				lower:   vals[0],
+				upper:   vals[0],
+				count:   float64(meta.Count),
+				ndv:     1,
+			})
+		}
+	}
+	for i := 0; i < c.Histogram.Len(); i++ {
+		lower, upper := *c.Histogram.GetLower(i), *c.Histogram.GetUpper(i)
+		encoded, err := codec.EncodeKey(loc, nil, lower)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		buckets = append(buckets, mysqlHistogramBucket{
+			encoded: encoded,
+			lower:   lower,
+			upper:   upper,
+			count:   float64(c.Histogram.BucketCount(i)),
+			ndv:     c.Histogram.Buckets[i].NDV,
+		})
+	}
+	slices.SortStableFunc(buckets, func(a, b mysqlHistogramBucket) int {
+		return bytes.Compare(a.encoded, b.encoded)
+	})
+
+	total := c.TotalRowCount()
+	jsonBuckets := make([]any, 0, len(buckets))
+	var cumCount, nullValues float64
+	if total > 0 {
+		for _, bkt := range buckets {
+			cumCount += bkt.count
+			jsonBuckets = append(jsonBuckets, []any{
+				mysqlHistogramValue(&bkt.lower, tp),
+				mysqlHistogramValue(&bkt.upper, tp),
+				cumCount / total,
+				bkt.ndv,
+			})
+		}
+		nullValues = float64(c.Histogram.NullCount) / total
+	}
+	var collationID int64
+	if c.Info != nil {
+		collationID = int64(mysql.CollationNames[c.Info.GetCollate()])
+	}
+	return map[string]any{
+		"buckets":                     jsonBuckets,
+		"data-type":                   mysqlHistogramDataType(tp),
+		"null-values":                 nullValues,
+		"collation-id":                collationID,
+		"last-updated":                lastUpdated.UTC().Format(mysqlHistogramTimeFormat),
+		"sampling-rate":               samplingRate,
+		"histogram-type":              "equi-height",
+		"number-of-buckets-specified": bucketsSpecified,
+	}, nil
+}
+
+// mysqlHistogramDataType returns the data type of the values in the MySQL histograms.
+func mysqlHistogramDataType(tp *types.FieldType) string {
+	switch tp.GetType() {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear, mysql.TypeBit:
+		if mysql.HasUnsignedFlag(tp.GetFlag()) {
+			return "uint"
+		}
+		return "int"
+	case mysql.TypeFloat, mysql.TypeDouble:
+		return "double"
+	case mysql.TypeNewDecimal:
+		return "decimal"
+	case mysql.TypeDate:
+		return "date"
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		return "datetime"
+	case mysql.TypeDuration:
+		return "time"
+	case mysql.TypeEnum:
+		return "enum"
+	case mysql.TypeSet:
+		return "set"
+	}
+	return "string"
+}
+
+// mysqlHistogramValue returns the value in the MySQL histograms, where the strings are encoded in base64 with the
+// type of them.
+func mysqlHistogramValue(d *types.Datum, tp *types.FieldType) any {
+	switch d.Kind() {
+	case types.KindInt64:
+		return d.GetInt64()
+	case types.KindUint64:
+		return d.GetUint64()
+	case types.KindFloat32, types.KindFloat64:
+		return d.GetFloat64()
+	case types.KindMysqlDecimal:
+		f, err := d.GetMysqlDecimal().ToFloat64()
+		if err == nil {
+			return f
+		}
+	case types.KindMysqlEnum:
+		return d.GetMysqlEnum().Value
+	case types.KindMysqlSet:
+		return d.GetMysqlSet().Value
+	case types.KindString, types.KindBytes:
+		strTp := mysql.TypeString
+		if types.IsTypeBlob(tp.GetType()) {
+			strTp = mysql.TypeBlob
+		}
+		return fmt.Sprintf("base64:type%d:%s", strTp, base64.StdEncoding.EncodeToString(d.GetBytes()))
+	}
+	str, err := d.ToString()
+	if err != nil {
+		return nil
+	}
+	return str
+}
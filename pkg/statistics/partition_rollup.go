@@ -0,0 +1,106 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+)
+
+// PartitionRollup is the compact stats summary of a partition of a range partitioned table. Unlike the histograms,
+// it's maintained by the DML deltas without analyze, so it's still fresh for the older partitions which are no longer
+// analyzed automatically, see tidb_stats_rollup_recent_partitions.
+type PartitionRollup struct {
+	Name string
+	// LowerBound and UpperBound are the range [LowerBound, UpperBound) of the partition in its definition, e.g. the
+	// time range of the partition of a time-series table. The LowerBound of the first partition is "MINVALUE".
+	LowerBound  string
+	UpperBound  string
+	PhysicalID  int64
+	RowCount    int64
+	ModifyCount int64
+	// Size is the total size of the columns in bytes.
+	Size int64
+	// FullStats indicates whether the partition keeps the full stats refreshed by auto analyze, i.e. it's one of the
+	// recent partitions.
+	FullStats bool
+}
+
+// RecentRangePartitions returns the IDs of the most recent partitions of the table, i.e. the ones with the largest
+// ranges, which keep the full stats under tidb_stats_rollup_recent_partitions. It returns false if the stats of the
+// older partitions are not rolled up, i.e. the rollups are disabled, the table isn't range partitioned or it doesn't
+// have more partitions than the recent ones.
+func RecentRangePartitions(tblInfo *model.TableInfo) (map[int64]struct{}, bool) {
+	n := int(variable.StatsRollupRecentPartitions.Load())
+	pi := tblInfo.GetPartitionInfo()
+	if n <= 0 || pi == nil || pi.Type != pmodel.PartitionTypeRange || len(pi.Definitions) <= n {
+		return nil, false
+	}
+	// The range partitions are defined in the ascending order of their ranges.
+	recent := make(map[int64]struct{}, n)
+	for _, def := range pi.Definitions[len(pi.Definitions)-n:] {
+		recent[def.ID] = struct{}{}
+	}
+	return recent, true
+}
+
+// IsRolledUpPartition returns whether the stats of the partition are rolled up, i.e. the partition is not one of the
+// recent partitions of the range partitioned table.
+func IsRolledUpPartition(tblInfo *model.TableInfo, physicalID int64) bool {
+	recent, ok := RecentRangePartitions(tblInfo)
+	if !ok || physicalID == tblInfo.ID {
+		return false
+	}
+	_, isRecent := recent[physicalID]
+	return !isRecent
+}
+
+// BuildPartitionRollups builds the rollups of the partitions of the range partitioned table from the stats of the
+// partitions returned by getStats. It returns nil if the table isn't range partitioned.
+func BuildPartitionRollups(tblInfo *model.TableInfo, getStats func(physicalID int64) *Table) []PartitionRollup {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil || pi.Type != pmodel.PartitionTypeRange {
+		return nil
+	}
+	recent, rolledUp := RecentRangePartitions(tblInfo)
+	rollups := make([]PartitionRollup, 0, len(pi.Definitions))
+	lowerBound := "MINVALUE"
+	for _, def := range pi.Definitions {
+		upperBound := strings.Join(def.LessThan, ",")
+		rollup := PartitionRollup{
+			Name:       def.Name.O,
+			LowerBound: lowerBound,
+			UpperBound: upperBound,
+			PhysicalID: def.ID,
+			FullStats:  true,
+		}
+		lowerBound = upperBound
+		if rolledUp {
+			_, rollup.FullStats = recent[def.ID]
+		}
+		if stats := getStats(def.ID); stats != nil && !stats.Pseudo {
+			rollup.RowCount, rollup.ModifyCount = stats.RealtimeCount, stats.ModifyCount
+			stats.ForEachColumnImmutable(func(_ int64, col *Column) bool {
+				rollup.Size += max(col.TotColSize, 0)
+				return false
+			})
+		}
+		rollups = append(rollups, rollup)
+	}
+	return rollups
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionRollup(t *testing.T) {
+	origin := variable.StatsRollupRecentPartitions.Load()
+	defer variable.StatsRollupRecentPartitions.Store(origin)
+	tblInfo := &model.TableInfo{
+		ID: 100,
+		Partition: &model.PartitionInfo{
+			Type: pmodel.PartitionTypeRange,
+			Definitions: []model.PartitionDefinition{
+				{ID: 101, Name: pmodel.NewCIStr("p0"), LessThan: []string{"'2026-01-01'"}},
+				{ID: 102, Name: pmodel.NewCIStr("p1"), LessThan: []string{"'2026-02-01'"}},
+				{ID: 103, Name: pmodel.NewCIStr("p2"), LessThan: []string{"MAXVALUE"}},
+			},
+		},
+	}
+	getStats := func(physicalID int64) *Table {
+		if physicalID == 102 {
+			return nil
+		}
+		stats := &Table{HistColl: *NewHistColl(physicalID, true, physicalID*10, 5, 2, 0)}
+		stats.SetCol(1, &Column{Histogram: Histogram{TotColSize: 80}})
+		stats.SetCol(2, &Column{Histogram: Histogram{TotColSize: 20}})
+		return stats
+	}
+
+	// The rollups are disabled.
+	variable.StatsRollupRecentPartitions.Store(0)
+	_, ok := RecentRangePartitions(tblInfo)
+	require.False(t, ok)
+	require.False(t, IsRolledUpPartition(tblInfo, 101))
+	rollups := BuildPartitionRollups(tblInfo, getStats)
+	require.Len(t, rollups, 3)
+	for _, rollup := range rollups {
+		require.True(t, rollup.FullStats)
+	}
+
+	// Only the last partition keeps the full stats.
+	variable.StatsRollupRecentPartitions.Store(1)
+	recent, ok := RecentRangePartitions(tblInfo)
+	require.True(t, ok)
+	require.Equal(t, map[int64]struct{}{103: {}}, recent)
+	require.True(t, IsRolledUpPartition(tblInfo, 101))
+	require.False(t, IsRolledUpPartition(tblInfo, 103))
+	require.False(t, IsRolledUpPartition(tblInfo, tblInfo.ID))
+	rollups = BuildPartitionRollups(tblInfo, getStats)
+	require.Equal(t, []PartitionRollup{
+		{Name: "p0", LowerBound: "MINVALUE", UpperBound: "'2026-01-01'", PhysicalID: 101, RowCount: 1010, ModifyCount: 5, Size: 100},
+		{Name: "p1", LowerBound: "'2026-01-01'", UpperBound: "'2026-02-01'", PhysicalID: 102},
+		{Name: "p2", LowerBound: "'2026-02-01'", UpperBound: "MAXVALUE", PhysicalID: 103, RowCount: 1030, ModifyCount: 5, Size: 100, FullStats: true},
+	}, rollups)
+
+	// The table doesn't have more partitions than the recent ones.
+	variable.StatsRollupRecentPartitions.Store(3)
+	_, ok = RecentRangePartitions(tblInfo)
+	require.False(t, ok)
+
+	// Only the range partitioned tables have the rollups.
+	variable.StatsRollupRecentPartitions.Store(1)
+	tblInfo.Partition.Type = pmodel.PartitionTypeHash
+	_, ok = RecentRangePartitions(tblInfo)
+	require.False(t, ok)
+	require.Nil(t, BuildPartitionRollups(tblInfo, getStats))
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import "sync"
+
+// maxPseudoStatsUsageTables is the max number of the tables whose pseudo stats usage is kept in memory.
+// The usage of the new tables is dropped when the limit is reached.
+const maxPseudoStatsUsageTables = 10000
+
+type pseudoStatsUsage struct {
+	tables map[int64]int64
+	mu     sync.Mutex
+}
+
+// PseudoStatsUsage collects the number of the executed statements which are estimated by the pseudo stats of the
+// tables, so that the tables which are never analyzed but already queried, e.g. the newly created ones, can be
+// analyzed by the auto analyze as soon as possible.
+var PseudoStatsUsage = &pseudoStatsUsage{tables: make(map[int64]int64)}
+
+// Record records a statement estimated by the pseudo stats of the table.
+func (u *pseudoStatsUsage) Record(physicalID int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.tables[physicalID]; !ok && len(u.tables) >= maxPseudoStatsUsageTables {
+		return
+	}
+	u.tables[physicalID]++
+}
+
+// Get returns the number of the statements estimated by the pseudo stats of the table.
+func (u *pseudoStatsUsage) Get(physicalID int64) int64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.tables[physicalID]
+}
+
+// Delete drops the usage of the table, e.g. after the table is analyzed.
+func (u *pseudoStatsUsage) Delete(physicalID int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.tables, physicalID)
+}
+
+// Reset drops all the usage.
+func (u *pseudoStatsUsage) Reset() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.tables = make(map[int64]int64)
+}
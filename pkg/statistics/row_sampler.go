@@ -38,6 +38,8 @@ type RowSampleCollector interface {
 	sampleRow(row []types.Datum, rng *rand.Rand)
 	Base() *baseCollector
 	DestroyAndPutToPool()
+	// Finalize builds the final samples after all the sub-collectors are merged.
+	Finalize()
 }
 
 type baseCollector struct {
@@ -400,6 +402,9 @@ func (s *ReservoirRowSampleCollector) DestroyAndPutToPool() {
 	s.baseCollector.destroyAndPutToPool()
 }
 
+// Finalize implements the interface RowSampleCollector. The samples are merged in place.
+func (*ReservoirRowSampleCollector) Finalize() {}
+
 // RowSamplesToProto converts the samp slice to the pb struct.
 func RowSamplesToProto(samples WeightedRowSampleHeap) []*tipb.RowSample {
 	if len(samples) == 0 {
@@ -488,3 +493,6 @@ func (s *BernoulliRowSampleCollector) Base() *baseCollector {
 func (s *BernoulliRowSampleCollector) DestroyAndPutToPool() {
 	s.baseCollector.destroyAndPutToPool()
 }
+
+// Finalize implements the interface RowSampleCollector. The samples are merged in place.
+func (*BernoulliRowSampleCollector) Finalize() {}
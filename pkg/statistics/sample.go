@@ -87,6 +87,9 @@ type SampleCollector struct {
 	TotalSize     int64 // TotalSize is the total size of column.
 	MemSize       int64 // major memory size of this sample collector.
 	IsMerger      bool
+	// BlockSampleFraction is the fraction of the regions scanned by the block sampling. The FMSketch only sees the
+	// values in the scanned regions, so the NDV is scaled by it when building the histogram.
+	BlockSampleFraction float64
 }
 
 // MergeSampleCollector merges two sample collectors.
@@ -100,7 +103,7 @@ func (c *SampleCollector) MergeSampleCollector(sc *stmtctx.StatementContext, rc
 		terror.Log(errors.Trace(err))
 	}
 	for _, item := range rc.Samples {
-		err := c.collect(sc, item.Value)
+		err := c.Collect(sc, item.Value)
 		terror.Log(errors.Trace(err))
 	}
 }
@@ -146,7 +149,8 @@ func SampleCollectorFromProto(collector *tipb.SampleCollector) *SampleCollector
 	return s
 }
 
-func (c *SampleCollector) collect(sc *stmtctx.StatementContext, d types.Datum) error {
+// Collect adds the datum to the sample collector. The datum is sampled by the reservoir sampling.
+func (c *SampleCollector) Collect(sc *stmtctx.StatementContext, d types.Datum) error {
 	if !c.IsMerger {
 		if d.IsNull() {
 			c.NullCount++
@@ -263,7 +267,7 @@ func (s SampleBuilder) CollectColumnStats() ([]*SampleCollector, *SortedBuilder,
 					}
 					val.SetBytes(encodedKey)
 				}
-				err = collectors[i].collect(s.Sc, val)
+				err = collectors[i].Collect(s.Sc, val)
 				if err != nil {
 					return nil, nil, errors.Trace(err)
 				}
@@ -193,6 +193,107 @@ func TestBuildStatsOnRowSample(t *testing.T) {
 		"num: 196 lower_bound: 805 upper_bound: 1000 repeats: 1 ndv: 0", hist.ToString(0))
 }
 
+func TestBuildAdaptiveTopN(t *testing.T) {
+	ctx := mock.NewContext()
+	buildCollector := func(counts map[int64]int) *SampleCollector {
+		sketch := NewFMSketch(1000)
+		data := make([]*SampleItem, 0, 5000)
+		for v, cnt := range counts {
+			d := types.NewIntDatum(v)
+			require.NoError(t, sketch.InsertValue(ctx.GetSessionVars().StmtCtx, d))
+			for i := 0; i < cnt; i++ {
+				data = append(data, &SampleItem{Value: d})
+			}
+		}
+		return &SampleCollector{
+			Samples:   data,
+			Count:     int64(len(data)),
+			FMSketch:  sketch,
+			TotalSize: int64(len(data)) * 8,
+		}
+	}
+	// 150 values appear 20 times and 2000 values appear once.
+	skewed := make(map[int64]int, 2150)
+	// 500 values appear 10 times.
+	uniform := make(map[int64]int, 500)
+	for i := int64(0); i < 2150; i++ {
+		skewed[i] = 1
+		if i < 150 {
+			skewed[i] = 20
+		}
+		if i < 500 {
+			uniform[i] = 10
+		}
+	}
+	tp := types.NewFieldType(mysql.TypeLonglong)
+
+	// The TopN size is fixed without the adaptive TopN.
+	_, topN, err := BuildHistAndTopN(ctx, 256, 100, 1, buildCollector(skewed), tp, true, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, 100, topN.Num())
+
+	ctx.GetSessionVars().AnalyzeAdaptiveTopNLimit = 1000
+	_, topN, err = BuildHistAndTopN(ctx, 256, 100, 1, buildCollector(skewed), tp, true, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, 150, topN.Num())
+	_, topN, err = BuildHistAndTopN(ctx, 256, 100, 1, buildCollector(uniform), tp, true, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, 0, topN.Num())
+	// The limit bounds the TopN size.
+	ctx.GetSessionVars().AnalyzeAdaptiveTopNLimit = 120
+	_, topN, err = BuildHistAndTopN(ctx, 256, 100, 1, buildCollector(skewed), tp, true, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, 120, topN.Num())
+	// The TopN size specified by the user is honored.
+	_, topN, err = BuildHistAndTopN(ctx, 256, 10, 1, buildCollector(skewed), tp, true, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, 10, topN.Num())
+}
+
+func TestBuildAdaptiveBuckets(t *testing.T) {
+	ctx := mock.NewContext()
+	buildCollector := func(ndv int64) *SampleCollector {
+		sketch := NewFMSketch(1000)
+		data := make([]*SampleItem, 0, ndv)
+		for i := int64(0); i < ndv; i++ {
+			d := types.NewIntDatum(i)
+			require.NoError(t, sketch.InsertValue(ctx.GetSessionVars().StmtCtx, d))
+			data = append(data, &SampleItem{Value: d})
+		}
+		return &SampleCollector{
+			Samples:   data,
+			Count:     int64(len(data)),
+			FMSketch:  sketch,
+			TotalSize: int64(len(data)) * 8,
+		}
+	}
+	tp := types.NewFieldType(mysql.TypeLonglong)
+
+	// The number of the buckets is fixed without the adaptive buckets.
+	hist, _, err := BuildHistAndTopN(ctx, 256, 100, 1, buildCollector(2000), tp, true, nil, false)
+	require.NoError(t, err)
+	require.Greater(t, hist.Len(), 200)
+	hist, _, err = BuildHistAndTopN(ctx, 256, 100, 1, buildCollector(20000), tp, true, nil, false)
+	require.NoError(t, err)
+	require.LessOrEqual(t, hist.Len(), 256)
+
+	ctx.GetSessionVars().AnalyzeAdaptiveBucketLimit = 1000
+	// Few samples only need few buckets.
+	hist, _, err = BuildHistAndTopN(ctx, 256, 100, 1, buildCollector(2000), tp, true, nil, false)
+	require.NoError(t, err)
+	require.LessOrEqual(t, hist.Len(), 100)
+	// Wide ranges get more buckets, up to the limit.
+	hist, _, err = BuildHistAndTopN(ctx, 256, 100, 1, buildCollector(20000), tp, true, nil, false)
+	require.NoError(t, err)
+	require.Greater(t, hist.Len(), 800)
+	require.LessOrEqual(t, hist.Len(), 1000)
+	// The number of the buckets specified by the user bounds it.
+	hist, _, err = BuildHistAndTopN(ctx, 500, 100, 1, buildCollector(20000), tp, true, nil, false)
+	require.NoError(t, err)
+	require.Greater(t, hist.Len(), 400)
+	require.LessOrEqual(t, hist.Len(), 500)
+}
+
 type testSampleSuite struct {
 	count int
 	rs    sqlexec.RecordSet
@@ -0,0 +1,317 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+// SamplingStrategy decides how the row samples collected from the regions by ANALYZE are merged into the final
+// samples, which the histograms, TopN and the other stats are built from. The regions are always sampled by the
+// coprocessor either by the reservoir sampling of a sample number or by the bernoulli sampling of a sample rate, and
+// the strategy works on the samples of each region. It's selected by the analyze option `WITH '<name>' SAMPLING`.
+type SamplingStrategy interface {
+	// Name returns the name of the strategy in the analyze option.
+	Name() string
+	// CheckOptions checks whether the strategy works with the analyze options. sampleNum is 0 if the regions are
+	// sampled by the sample rate, and sampleRate is negative if it's adjusted automatically by the table size.
+	CheckOptions(sampleNum uint64, sampleRate float64) error
+	// NewCollector creates the collector which the samples of the regions are merged into. The collector must be
+	// finalized after all the samples are merged.
+	NewCollector(maxSampleSize int, sampleRate float64, totalLen int) RowSampleCollector
+}
+
+// DefaultSamplingStrategyID is the ID of the default sampling strategy, which uses the reservoir sampling if the
+// sample number is set and the bernoulli sampling otherwise.
+const DefaultSamplingStrategyID = 0
+
+var samplingStrategies struct {
+	sync.RWMutex
+	// strategies are the registered strategies, whose IDs are their indexes.
+	strategies []SamplingStrategy
+}
+
+// RegisterSamplingStrategy registers the sampling strategy, and returns its ID used in the analyze options. The
+// strategies must be registered in the init functions.
+func RegisterSamplingStrategy(strategy SamplingStrategy) uint64 {
+	samplingStrategies.Lock()
+	defer samplingStrategies.Unlock()
+	for _, s := range samplingStrategies.strategies {
+		if strings.EqualFold(s.Name(), strategy.Name()) {
+			panic("duplicated sampling strategy " + strategy.Name())
+		}
+	}
+	samplingStrategies.strategies = append(samplingStrategies.strategies, strategy)
+	return uint64(len(samplingStrategies.strategies) - 1)
+}
+
+// SamplingStrategyIDByName returns the ID of the sampling strategy of the name, which is case-insensitive.
+func SamplingStrategyIDByName(name string) (uint64, bool) {
+	samplingStrategies.RLock()
+	defer samplingStrategies.RUnlock()
+	for id, s := range samplingStrategies.strategies {
+		if strings.EqualFold(s.Name(), name) {
+			return uint64(id), true
+		}
+	}
+	return 0, false
+}
+
+// GetSamplingStrategy returns the sampling strategy of the ID, or the default one if the ID is unknown.
+func GetSamplingStrategy(id uint64) SamplingStrategy {
+	samplingStrategies.RLock()
+	defer samplingStrategies.RUnlock()
+	if id >= uint64(len(samplingStrategies.strategies)) {
+		return samplingStrategies.strategies[DefaultSamplingStrategyID]
+	}
+	return samplingStrategies.strategies[id]
+}
+
+func init() {
+	RegisterSamplingStrategy(defaultSamplingStrategy{})
+	RegisterSamplingStrategy(reservoirSamplingStrategy{})
+	RegisterSamplingStrategy(bernoulliSamplingStrategy{})
+	RegisterSamplingStrategy(stratifiedSamplingStrategy{})
+}
+
+// defaultSamplingStrategy merges the samples by the weighted reservoir sampling if the sample number is set, and
+// keeps all the samples of the bernoulli sampling otherwise.
+type defaultSamplingStrategy struct{}
+
+// Name implements the SamplingStrategy interface.
+func (defaultSamplingStrategy) Name() string {
+	return "default"
+}
+
+// CheckOptions implements the SamplingStrategy interface.
+func (defaultSamplingStrategy) CheckOptions(uint64, float64) error {
+	return nil
+}
+
+// NewCollector implements the SamplingStrategy interface.
+func (defaultSamplingStrategy) NewCollector(maxSampleSize int, sampleRate float64, totalLen int) RowSampleCollector {
+	return NewRowSampleCollector(maxSampleSize, sampleRate, totalLen)
+}
+
+// reservoirSamplingStrategy merges the samples by the weighted reservoir sampling, it requires the sample number.
+type reservoirSamplingStrategy struct{}
+
+// Name implements the SamplingStrategy interface.
+func (reservoirSamplingStrategy) Name() string {
+	return "reservoir"
+}
+
+// CheckOptions implements the SamplingStrategy interface.
+func (reservoirSamplingStrategy) CheckOptions(sampleNum uint64, _ float64) error {
+	if sampleNum == 0 {
+		return errors.Errorf("The reservoir sampling requires the SAMPLES option")
+	}
+	return nil
+}
+
+// NewCollector implements the SamplingStrategy interface.
+func (reservoirSamplingStrategy) NewCollector(maxSampleSize int, _ float64, totalLen int) RowSampleCollector {
+	return NewReservoirRowSampleCollector(maxSampleSize, totalLen)
+}
+
+// bernoulliSamplingStrategy keeps all the samples of the uniform bernoulli sampling, it requires the sample rate.
+type bernoulliSamplingStrategy struct{}
+
+// Name implements the SamplingStrategy interface.
+func (bernoulliSamplingStrategy) Name() string {
+	return "bernoulli"
+}
+
+// CheckOptions implements the SamplingStrategy interface.
+func (bernoulliSamplingStrategy) CheckOptions(sampleNum uint64, _ float64) error {
+	if sampleNum > 0 {
+		return errors.Errorf("The bernoulli sampling doesn't support the SAMPLES option")
+	}
+	return nil
+}
+
+// NewCollector implements the SamplingStrategy interface.
+func (bernoulliSamplingStrategy) NewCollector(_ int, sampleRate float64, totalLen int) RowSampleCollector {
+	return NewBernoulliRowSampleCollector(sampleRate, totalLen)
+}
+
+// stratifiedSamplingStrategy takes the regions as the strata, and allocates the samples to them in proportion to
+// their row counts.
+type stratifiedSamplingStrategy struct{}
+
+// Name implements the SamplingStrategy interface.
+func (stratifiedSamplingStrategy) Name() string {
+	return "stratified"
+}
+
+// CheckOptions implements the SamplingStrategy interface.
+func (stratifiedSamplingStrategy) CheckOptions(uint64, float64) error {
+	return nil
+}
+
+// NewCollector implements the SamplingStrategy interface.
+func (stratifiedSamplingStrategy) NewCollector(maxSampleSize int, _ float64, totalLen int) RowSampleCollector {
+	return NewStratifiedRowSampleCollector(maxSampleSize, totalLen)
+}
+
+// sampleStratum is the samples of a region and its row count.
+type sampleStratum struct {
+	count   int64
+	samples []*ReservoirRowSampleItem
+}
+
+// StratifiedRowSampleCollector merges the samples of the regions by the stratified sampling. The merge of the
+// weighted reservoir sampling keeps a uniform sample of the whole table, but the number of the samples of a region
+// varies a lot from its share of the rows, so the histograms are unstable on the tables whose regions are skewed in
+// size. The collector keeps the samples of each region as a stratum, and allocates the final samples to the strata
+// in proportion to their row counts when it's finalized. The samples are kept until then, so it takes more memory
+// than the reservoir sampling.
+type StratifiedRowSampleCollector struct {
+	*baseCollector
+	// MaxSampleSize is the number of the final samples. 0 means the regions are sampled by the sample rate, and the
+	// number of the final samples is the total number of the samples of the regions.
+	MaxSampleSize int
+	strata        []sampleStratum
+}
+
+// NewStratifiedRowSampleCollector creates the new collector by the given inputs.
+func NewStratifiedRowSampleCollector(maxSampleSize int, totalLen int) *StratifiedRowSampleCollector {
+	base := &baseCollector{
+		Samples:    make(WeightedRowSampleHeap, 0, 8),
+		NullCount:  make([]int64, totalLen),
+		FMSketches: make([]*FMSketch, 0, totalLen),
+		TotalSizes: make([]int64, totalLen),
+	}
+	return &StratifiedRowSampleCollector{
+		baseCollector: base,
+		MaxSampleSize: maxSampleSize,
+	}
+}
+
+func (*StratifiedRowSampleCollector) sampleRow([]types.Datum, *rand.Rand) {
+	panic("the stratified sampling only merges the samples of the regions")
+}
+
+// MergeCollector merges the collectors to a final one. The samples of the sub-collector are kept as a stratum, or the
+// strata of it are kept if it's also a StratifiedRowSampleCollector.
+func (s *StratifiedRowSampleCollector) MergeCollector(subCollector RowSampleCollector) {
+	s.Count += subCollector.Base().Count
+	for i := range subCollector.Base().FMSketches {
+		s.FMSketches[i].MergeFMSketch(subCollector.Base().FMSketches[i])
+	}
+	for i := range subCollector.Base().NullCount {
+		s.NullCount[i] += subCollector.Base().NullCount[i]
+	}
+	for i := range subCollector.Base().TotalSizes {
+		s.TotalSizes[i] += subCollector.Base().TotalSizes[i]
+	}
+	s.MemSize += subCollector.Base().MemSize
+	if sub, ok := subCollector.(*StratifiedRowSampleCollector); ok {
+		s.strata = append(s.strata, sub.strata...)
+		return
+	}
+	if subCollector.Base().Count > 0 {
+		s.strata = append(s.strata, sampleStratum{
+			count:   subCollector.Base().Count,
+			samples: subCollector.Base().Samples,
+		})
+	}
+}
+
+// Finalize implements the RowSampleCollector interface. It allocates the final samples to the strata by the largest
+// remainder method, and the samples of a stratum are picked by their weights. The samples of the bernoulli sampling
+// are already in proportion to the row counts, so all of them are kept.
+func (s *StratifiedRowSampleCollector) Finalize() {
+	strata := s.strata
+	s.strata = nil
+	total := 0
+	for _, stratum := range strata {
+		total += len(stratum.samples)
+	}
+	target := total
+	if s.MaxSampleSize > 0 {
+		target = min(s.MaxSampleSize, total)
+	}
+	if target == 0 || s.Count == 0 {
+		return
+	}
+	quotas := allocateStratumQuotas(strata, s.Count, target)
+	samples := make(WeightedRowSampleHeap, 0, target)
+	for i, stratum := range strata {
+		if quotas[i] < len(stratum.samples) {
+			slices.SortFunc(stratum.samples, func(a, b *ReservoirRowSampleItem) int {
+				return cmp.Compare(b.Weight, a.Weight)
+			})
+		}
+		samples = append(samples, stratum.samples[:quotas[i]]...)
+	}
+	s.Samples = samples
+	s.MemSize = s.MemSize * int64(len(samples)) / int64(total)
+}
+
+// allocateStratumQuotas allocates the target number of samples to the strata in proportion to their row counts. The
+// quota of a stratum never exceeds its samples, and the shortfall is allocated to the other strata.
+func allocateStratumQuotas(strata []sampleStratum, totalCount int64, target int) []int {
+	quotas := make([]int, len(strata))
+	remainders := make([]float64, len(strata))
+	allocated := 0
+	for i, stratum := range strata {
+		exact := float64(target) * float64(stratum.count) / float64(totalCount)
+		quotas[i] = min(int(exact), len(stratum.samples))
+		remainders[i] = exact - float64(quotas[i])
+		allocated += quotas[i]
+	}
+	order := make([]int, len(strata))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(remainders[b], remainders[a])
+	})
+	for allocated < target {
+		progressed := false
+		for _, i := range order {
+			if allocated == target {
+				break
+			}
+			if quotas[i] < len(strata[i].samples) {
+				quotas[i]++
+				allocated++
+				progressed = true
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	return quotas
+}
+
+// Base implements the RowSampleCollector interface.
+func (s *StratifiedRowSampleCollector) Base() *baseCollector {
+	return s.baseCollector
+}
+
+// DestroyAndPutToPool implements the RowSampleCollector interface.
+func (s *StratifiedRowSampleCollector) DestroyAndPutToPool() {
+	s.baseCollector.destroyAndPutToPool()
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+// regionCollector mocks the samples of a region whose rows are all `region`.
+func regionCollector(region int64, count int64, sampleNum int) RowSampleCollector {
+	c := NewReservoirRowSampleCollector(sampleNum, 1)
+	c.Count = count
+	for i := 0; i < sampleNum; i++ {
+		c.Samples = append(c.Samples, &ReservoirRowSampleItem{
+			Columns: []types.Datum{types.NewIntDatum(region)},
+			Weight:  int64(i),
+		})
+	}
+	return c
+}
+
+func TestSamplingStrategy(t *testing.T) {
+	for _, name := range []string{"default", "reservoir", "bernoulli", "stratified"} {
+		id, ok := SamplingStrategyIDByName(name)
+		require.True(t, ok)
+		require.Equal(t, name, GetSamplingStrategy(id).Name())
+	}
+	id, ok := SamplingStrategyIDByName("STRATIFIED")
+	require.True(t, ok)
+	require.Equal(t, "stratified", GetSamplingStrategy(id).Name())
+	_, ok = SamplingStrategyIDByName("unknown")
+	require.False(t, ok)
+	require.Equal(t, "default", GetSamplingStrategy(DefaultSamplingStrategyID).Name())
+	require.Equal(t, "default", GetSamplingStrategy(100).Name())
+
+	id, _ = SamplingStrategyIDByName("reservoir")
+	require.NoError(t, GetSamplingStrategy(id).CheckOptions(10000, 0))
+	require.ErrorContains(t, GetSamplingStrategy(id).CheckOptions(0, 0.1), "requires the SAMPLES option")
+	id, _ = SamplingStrategyIDByName("bernoulli")
+	require.NoError(t, GetSamplingStrategy(id).CheckOptions(0, -1))
+	require.ErrorContains(t, GetSamplingStrategy(id).CheckOptions(10000, 0), "doesn't support the SAMPLES option")
+}
+
+func TestStratifiedRowSampleCollector(t *testing.T) {
+	countRegions := func(samples WeightedRowSampleHeap) map[int64]int {
+		counts := make(map[int64]int)
+		for _, sample := range samples {
+			counts[sample.Columns[0].GetInt64()]++
+		}
+		return counts
+	}
+
+	// The samples are allocated in proportion to the row counts of the regions, although each region has the same
+	// number of samples. The regions are merged by the workers first, and then by the root collector.
+	worker := NewStratifiedRowSampleCollector(100, 1)
+	worker.MergeCollector(regionCollector(1, 7000, 100))
+	worker.MergeCollector(regionCollector(2, 2000, 100))
+	root := NewStratifiedRowSampleCollector(100, 1)
+	root.MergeCollector(worker)
+	root.MergeCollector(regionCollector(3, 1000, 100))
+	root.MergeCollector(regionCollector(4, 0, 0))
+	root.Finalize()
+	require.Equal(t, int64(10000), root.Count)
+	require.Len(t, root.Samples, 100)
+	require.Equal(t, map[int64]int{1: 70, 2: 20, 3: 10}, countRegions(root.Samples))
+	// The samples of the largest weights are kept.
+	for _, sample := range root.Samples {
+		require.GreaterOrEqual(t, sample.Weight, int64(30))
+	}
+
+	// The quota of a region never exceeds its samples, and the shortfall is allocated to the other regions.
+	root = NewStratifiedRowSampleCollector(100, 1)
+	root.MergeCollector(regionCollector(1, 9000, 50))
+	root.MergeCollector(regionCollector(2, 500, 100))
+	root.MergeCollector(regionCollector(3, 500, 100))
+	root.Finalize()
+	require.Equal(t, map[int64]int{1: 50, 2: 25, 3: 25}, countRegions(root.Samples))
+
+	// All the samples of the bernoulli sampling are kept.
+	root = NewStratifiedRowSampleCollector(0, 1)
+	root.MergeCollector(regionCollector(1, 1000, 30))
+	root.MergeCollector(regionCollector(2, 1000, 10))
+	root.Finalize()
+	require.Equal(t, map[int64]int{1: 30, 2: 10}, countRegions(root.Samples))
+
+	// The largest remainders get the rest of the samples.
+	require.Equal(t, []int{4, 3, 3}, allocateStratumQuotas([]sampleStratum{
+		{count: 35, samples: make([]*ReservoirRowSampleItem, 10)},
+		{count: 33, samples: make([]*ReservoirRowSampleItem, 10)},
+		{count: 32, samples: make([]*ReservoirRowSampleItem, 10)},
+	}, 100, 10))
+}
@@ -703,3 +703,29 @@ func TestPruneTopN(t *testing.T) {
 	topnOut = pruneTopNItem(topnIn, totalNDV, nullCnt, sampleRows, totalRows)
 	require.Equal(t, topnPruned, topnOut)
 }
+
+func TestClassifyWorkload(t *testing.T) {
+	newTable := func(realtimeCount, modifyCount, analyzeRowCount int64) *Table {
+		coll := NewHistColl(1, false, realtimeCount, modifyCount, 1, 0)
+		coll.SetCol(1, &Column{
+			Histogram:         *NewHistogram(1, 0, analyzeRowCount, 0, types.NewFieldType(mysql.TypeLonglong), 0, 0),
+			StatsLoadedStatus: NewStatsFullLoadStatus(),
+			StatsVer:          Version1,
+		})
+		return &Table{HistColl: *coll, LastAnalyzeVersion: 1}
+	}
+
+	require.Equal(t, WorkloadClassGeneral, ClassifyWorkload(nil))
+	unanalyzed := newTable(1_000_000, 0, 1_000_000)
+	unanalyzed.LastAnalyzeVersion = 0
+	require.Equal(t, WorkloadClassGeneral, ClassifyWorkload(unanalyzed))
+	require.Equal(t, WorkloadClassSmallDimension, ClassifyWorkload(newTable(1000, 500, 500)))
+	require.Equal(t, WorkloadClassHugeFact, ClassifyWorkload(newTable(200_000_000, 1000, 200_000_000)))
+	require.Equal(t, WorkloadClassGeneral, ClassifyWorkload(newTable(1_000_000, 0, 1_000_000)))
+	// All the modifications are inserts.
+	require.Equal(t, WorkloadClassAppendOnly, ClassifyWorkload(newTable(1_500_000, 500_000, 1_000_000)))
+	// All the modifications are updates.
+	require.Equal(t, WorkloadClassUpdateHeavy, ClassifyWorkload(newTable(1_000_000, 500_000, 1_000_000)))
+	// Half of the modifications are inserts.
+	require.Equal(t, WorkloadClassGeneral, ClassifyWorkload(newTable(1_250_000, 500_000, 1_000_000)))
+}
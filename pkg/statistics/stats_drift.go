@@ -0,0 +1,110 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"sync"
+
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+// maxStatsDriftTables is the max number of the tables whose verified drift is kept in memory.
+// The drift of the new tables is dropped when the limit is reached.
+const maxStatsDriftTables = 10000
+
+type statsDrift struct {
+	// drift is in [0, 1], the larger the more the stats drift from the data.
+	drift float64
+	// version is the last analyze version of the stats which the drift is verified against.
+	version uint64
+}
+
+type verifiedStatsDrift struct {
+	tables map[int64]statsDrift
+	mu     sync.Mutex
+}
+
+// VerifiedStatsDrift collects the drift of the stats verified by the stats probe, which compares the stats with the
+// results of the aggregate queries on the tables. Unlike the modify count, it tells the stats are really stale.
+var VerifiedStatsDrift = &verifiedStatsDrift{tables: make(map[int64]statsDrift)}
+
+// Record records the drift of the stats of the table, which is verified against the given last analyze version.
+func (d *verifiedStatsDrift) Record(physicalID int64, drift float64, version uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.tables[physicalID]; !ok && len(d.tables) >= maxStatsDriftTables {
+		return
+	}
+	d.tables[physicalID] = statsDrift{drift: drift, version: version}
+}
+
+// Get returns the verified drift of the table. The drift verified against the stats before the last analysis is
+// stale, so it's dropped.
+func (d *verifiedStatsDrift) Get(physicalID int64, version uint64) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s, ok := d.tables[physicalID]
+	if !ok {
+		return 0
+	}
+	if s.version != version {
+		delete(d.tables, physicalID)
+		return 0
+	}
+	return s.drift
+}
+
+// Reset drops all the drift.
+func (d *verifiedStatsDrift) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tables = make(map[int64]statsDrift)
+}
+
+// RangeDrift returns how much the observed range [minVal, maxVal] of the column drifts from the range of the
+// histogram, i.e. the width of the parts in only one of the ranges divided by the width of the histogram, which is
+// capped at 1. It's 0 if the histogram is empty or the values can't be converted to scalars, e.g. the strings whose
+// bounds are stored as the collation keys.
+func (hg *Histogram) RangeDrift(minVal, maxVal *types.Datum) float64 {
+	if hg.Len() == 0 || minVal.IsNull() || maxVal.IsNull() {
+		return 0
+	}
+	lower, upper := hg.GetLower(0), hg.GetUpper(hg.Len()-1)
+	for _, d := range []*types.Datum{lower, upper, minVal, maxVal} {
+		if !isNumericScalarKind(d.Kind()) || d.Kind() != lower.Kind() {
+			return 0
+		}
+	}
+	l, u := convertDatumToScalar(lower, 0), convertDatumToScalar(upper, 0)
+	lo, hi := convertDatumToScalar(minVal, 0), convertDatumToScalar(maxVal, 0)
+	diff := math.Abs(lo-l) + math.Abs(hi-u)
+	if diff == 0 {
+		return 0
+	}
+	if u <= l {
+		return 1
+	}
+	return min(diff/(u-l), 1)
+}
+
+func isNumericScalarKind(kind byte) bool {
+	switch kind {
+	case types.KindInt64, types.KindUint64, types.KindFloat32, types.KindFloat64, types.KindMysqlDecimal,
+		types.KindMysqlDuration, types.KindMysqlTime:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifiedStatsDrift(t *testing.T) {
+	VerifiedStatsDrift.Reset()
+	defer VerifiedStatsDrift.Reset()
+	require.Equal(t, 0.0, VerifiedStatsDrift.Get(1, 100))
+
+	VerifiedStatsDrift.Record(1, 0.5, 100)
+	require.Equal(t, 0.5, VerifiedStatsDrift.Get(1, 100))
+	// The drift verified against the stats before the last analysis is dropped.
+	require.Equal(t, 0.0, VerifiedStatsDrift.Get(1, 200))
+	require.Equal(t, 0.0, VerifiedStatsDrift.Get(1, 100))
+}
+
+func TestHistogramRangeDrift(t *testing.T) {
+	hist := NewHistogram(0, 0, 0, 0, types.NewFieldType(mysql.TypeLonglong), 2, 0)
+	low, high := types.NewIntDatum(0), types.NewIntDatum(50)
+	hist.AppendBucket(&low, &high, 10, 1)
+	low, high = types.NewIntDatum(51), types.NewIntDatum(100)
+	hist.AppendBucket(&low, &high, 20, 1)
+
+	minVal, maxVal := types.NewIntDatum(0), types.NewIntDatum(100)
+	require.Equal(t, 0.0, hist.RangeDrift(&minVal, &maxVal))
+	// The new values are beyond the upper bound.
+	maxVal = types.NewIntDatum(150)
+	require.InDelta(t, 0.5, hist.RangeDrift(&minVal, &maxVal), 1e-9)
+	// The old values are deleted.
+	minVal, maxVal = types.NewIntDatum(20), types.NewIntDatum(100)
+	require.InDelta(t, 0.2, hist.RangeDrift(&minVal, &maxVal), 1e-9)
+	// The drift is capped.
+	maxVal = types.NewIntDatum(1000)
+	require.Equal(t, 1.0, hist.RangeDrift(&minVal, &maxVal))
+	// The values which can't be converted to scalars are ignored.
+	minVal, maxVal = types.NewStringDatum("a"), types.NewStringDatum("z")
+	require.Equal(t, 0.0, hist.RangeDrift(&minVal, &maxVal))
+	minVal.SetNull()
+	require.Equal(t, 0.0, hist.RangeDrift(&minVal, &maxVal))
+}
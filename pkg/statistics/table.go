@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/pkg/meta/model"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/planner/planctx"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
 	"github.com/pingcap/tidb/pkg/types"
 	"github.com/pingcap/tidb/pkg/util/ranger"
 	"go.uber.org/atomic"
@@ -78,6 +79,10 @@ type Table struct {
 	// and the schema of the table does not change, we don't need to load the stats for this
 	// table again.
 	TblInfoUpdateTS uint64
+	// InsertCount and DeleteCount are the numbers of the rows inserted and deleted since the last analysis,
+	// which come from mysql.stats_meta. Unlike ModifyCount, they tell whether the table is growing or shrinking.
+	InsertCount int64
+	DeleteCount int64
 
 	IsPkIsHandle bool
 }
@@ -183,10 +188,22 @@ func ColAndIdxExistenceMapIsEqual(m1, m2 *ColAndIdxExistenceMap) bool {
 
 // ExtendedStatsItem is the cached item of a mysql.stats_extended record.
 type ExtendedStatsItem struct {
-	StringVals string
-	ColIDs     []int64
-	ScalarVals float64
-	Tp         uint8
+	// JSONPaths is decoded from StringVals for the JSON path stats.
+	JSONPaths *JSONPathStats
+	// ForeignKey is decoded from StringVals for the foreign key stats.
+	ForeignKey *ForeignKeyStats
+	// Vector is decoded from StringVals for the vector stats.
+	Vector *VectorStats
+	// Trigrams is decoded from StringVals for the trigram stats.
+	Trigrams *TrigramStats
+	// MultiColHist is decoded from StringVals for the histogram extended stats.
+	MultiColHist *MultiColHistogram
+	// IndexPrefixTopN is decoded from StringVals for the index prefix TopN.
+	IndexPrefixTopN *IndexPrefixTopN
+	StringVals      string
+	ColIDs          []int64
+	ScalarVals      float64
+	Tp              uint8
 }
 
 // ExtendedStatsColl is a collection of cached items for mysql.stats_extended records.
@@ -244,6 +261,50 @@ type HistColl struct {
 	// For normal index, the column id is enough, as we already have in Idx2ColUniqueIDs. But currently, mv index needs more
 	// information to match the filter against the mv index columns, and we need this map to provide this information.
 	MVIdx2Columns map[int64][]*expression.Column
+	// FuncDeps is the functional dependencies between columns detected by the dependency extended stats, whose column
+	// IDs are UniqueIDs. It's used to calculate the selectivity in planner.
+	FuncDeps []FuncDependency
+	// ColCorrelations is the correlations between columns from the correlation extended stats, whose column IDs are
+	// UniqueIDs. It's used to estimate the size of the intersection of the index merge in planner.
+	ColCorrelations []ColCorrelation
+	// JSONPathStats is the JSON path stats of the JSON columns, whose keys are the UniqueIDs of the columns.
+	// It's used to calculate the selectivity of the predicates on the JSON paths in planner.
+	JSONPathStats map[int64]*JSONPathStats
+	// TrigramStats is the trigram stats of the string columns, whose keys are the UniqueIDs of the columns.
+	// It's used to calculate the selectivity of LIKE and REGEXP in planner.
+	TrigramStats map[int64]*TrigramStats
+	// MultiColHists is the multi-column histograms from the histogram extended stats, which are used to calculate the
+	// selectivity of the range conditions on the correlated columns in planner.
+	MultiColHists []MultiColHist
+	// IndexPrefixTopNs is the TopN of the prefixes of the composite indexes, whose keys are the index IDs.
+	// It's used to estimate the row count of the equal conditions on the index prefixes in planner.
+	IndexPrefixTopNs map[int64]*IndexPrefixTopN
+}
+
+// MultiColHist is a multi-column histogram of the columns of a HistColl.
+type MultiColHist struct {
+	// Name is the name of the extended stats.
+	Name string
+	// Cols are the UniqueIDs of the columns, and ColNames are the names of them.
+	Cols     [2]int64
+	ColNames [2]string
+	Hist     *MultiColHistogram
+}
+
+// FuncDependency is a functional dependency from the Determinant column to the Dependent column.
+type FuncDependency struct {
+	Determinant int64
+	Dependent   int64
+	// Degree is the fraction of rows whose Dependent value is determined by the Determinant value.
+	Degree float64
+}
+
+// ColCorrelation is the correlation between the orders of two columns.
+type ColCorrelation struct {
+	Col1 int64
+	Col2 int64
+	// Corr is the correlation coefficient, which is in [-1, 1].
+	Corr float64
 }
 
 // NewHistColl creates a new HistColl.
@@ -600,6 +661,8 @@ func (t *Table) Copy() *Table {
 		Version:            t.Version,
 		TblInfoUpdateTS:    t.TblInfoUpdateTS,
 		LastAnalyzeVersion: t.LastAnalyzeVersion,
+		InsertCount:        t.InsertCount,
+		DeleteCount:        t.DeleteCount,
 	}
 	if t.ExtendedStats != nil {
 		newExtStatsColl := &ExtendedStatsColl{
@@ -638,6 +701,8 @@ func (t *Table) ShallowCopy() *Table {
 		ExtendedStats:         t.ExtendedStats,
 		ColAndIdxExistenceMap: t.ColAndIdxExistenceMap,
 		LastAnalyzeVersion:    t.LastAnalyzeVersion,
+		InsertCount:           t.InsertCount,
+		DeleteCount:           t.DeleteCount,
 	}
 	return nt
 }
@@ -733,6 +798,13 @@ func (t *Table) IsEligibleForAnalysis() bool {
 	return true
 }
 
+// IsTinyTable returns whether a non-partitioned table of the row count is refreshed by the row-count-only fast path
+// of the stats delta dump rather than auto analyze, see tidb_stats_tiny_table_threshold.
+func IsTinyTable(realtimeCount int64) bool {
+	threshold := variable.StatsTinyTableThreshold.Load()
+	return threshold > 0 && realtimeCount <= threshold
+}
+
 // GetAnalyzeRowCount tries to get the row count of a column or an index if possible.
 // This method is useful because this row count doesn't consider the modify count.
 func (coll *HistColl) GetAnalyzeRowCount() float64 {
@@ -797,17 +869,90 @@ func (t *Table) GetStatsHealthy() (int64, bool) {
 	if !t.IsAnalyzed() {
 		return 0, true
 	}
+	return t.statsHealthy(t.ModifyCount), true
+}
+
+// statsHealthy calculates the stats healthy of the table, or an index of it, given the modify count since the
+// analysis.
+func (t *Table) statsHealthy(modifyCount int64) int64 {
 	var healthy int64
 	count := float64(t.RealtimeCount)
 	if histCount := t.GetAnalyzeRowCount(); histCount > 0 {
 		count = histCount
 	}
-	if float64(t.ModifyCount) < count {
-		healthy = int64((1.0 - float64(t.ModifyCount)/count) * 100.0)
-	} else if t.ModifyCount == 0 {
+	if float64(modifyCount) < count {
+		healthy = int64((1.0 - float64(modifyCount)/count) * 100.0)
+	} else if modifyCount == 0 {
 		healthy = 100
 	}
-	return healthy, true
+	return healthy
+}
+
+// The reasons why the stats of a table are considered unusable, see GetStatsUnusableReason.
+const (
+	// StatsUnusableNeverAnalyzed means the table has never been analyzed.
+	StatsUnusableNeverAnalyzed = "never_analyzed"
+	// StatsUnusableTooStale means the modify ratio of the table exceeds tidb_pseudo_estimate_ratio.
+	StatsUnusableTooStale = "too_stale"
+	// StatsUnusableMixedVersion means the columns and indexes are analyzed with different stats versions.
+	StatsUnusableMixedVersion = "mixed_version"
+	// StatsUnusablePartialColumns means some public columns have no analyzed stats.
+	StatsUnusablePartialColumns = "partial_columns"
+)
+
+// GetModifyRatio returns the ratio of the modify count to the row count of the last analysis.
+func (t *Table) GetModifyRatio() float64 {
+	count := float64(t.RealtimeCount)
+	if histCount := t.GetAnalyzeRowCount(); histCount > 0 {
+		count = histCount
+	}
+	if count <= 0 {
+		return 0
+	}
+	return float64(t.ModifyCount) / count
+}
+
+// GetStatsUnusableReason returns the reason why the stats of the table are considered unusable,
+// it returns an empty string if the stats are usable.
+// Only the stats versions of the loaded columns and indexes are checked for StatsUnusableMixedVersion.
+func (t *Table) GetStatsUnusableReason(tblInfo *model.TableInfo) string {
+	if t == nil || t.Pseudo || !t.IsAnalyzed() {
+		return StatsUnusableNeverAnalyzed
+	}
+	if t.IsOutdated() {
+		return StatsUnusableTooStale
+	}
+	var statsVer int64 = Version0
+	mixed := false
+	checkVer := func(ver int64) bool {
+		if ver == Version0 {
+			return false
+		}
+		if statsVer == Version0 {
+			statsVer = ver
+		}
+		mixed = ver != statsVer
+		return mixed
+	}
+	t.ForEachColumnImmutable(func(_ int64, col *Column) bool {
+		return checkVer(col.GetStatsVer())
+	})
+	if !mixed {
+		t.ForEachIndexImmutable(func(_ int64, idx *Index) bool {
+			return checkVer(idx.GetStatsVer())
+		})
+	}
+	if mixed {
+		return StatsUnusableMixedVersion
+	}
+	if t.ColAndIdxExistenceMap != nil && tblInfo != nil {
+		for _, col := range tblInfo.Columns {
+			if col.State == model.StatePublic && !t.ColAndIdxExistenceMap.HasAnalyzed(col.ID, false) {
+				return StatsUnusablePartialColumns
+			}
+		}
+	}
+	return ""
 }
 
 // ColumnIsLoadNeeded checks whether the column needs trigger the async/sync load.
@@ -905,6 +1050,7 @@ func (t *Table) ReleaseAndPutToPool() {
 }
 
 // ID2UniqueID generates a new HistColl whose `Columns` is built from UniqueID of given columns.
+// The indices are still keyed by their IDs, which are used to look up the index stats of the physical index scans.
 func (coll *HistColl) ID2UniqueID(columns []*expression.Column) *HistColl {
 	cols := make(map[int64]*Column)
 	for _, col := range columns {
@@ -920,6 +1066,7 @@ func (coll *HistColl) ID2UniqueID(columns []*expression.Column) *HistColl {
 		RealtimeCount:  coll.RealtimeCount,
 		ModifyCount:    coll.ModifyCount,
 		columns:        cols,
+		indices:        coll.indices,
 	}
 	return newColl
 }
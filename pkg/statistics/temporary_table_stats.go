@@ -0,0 +1,118 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+)
+
+// TemporaryTableStats is the lightweight statistics of a temporary table. The data of the temporary tables is only
+// visible to the session, so their stats are kept in the session memory instead of the stats cache. The row count is
+// maintained by the writes of the session, and the NDVs and the NULL counts are collected by ANALYZE in the session.
+type TemporaryTableStats struct {
+	// ColNDVs and NullCounts are the NDVs and the NULL counts of the columns collected by ANALYZE, keyed by column ID.
+	ColNDVs    map[int64]int64
+	NullCounts map[int64]int64
+	// RowCount is the row count of the table, excluding the changes of the current transaction since StartTS.
+	RowCount int64
+	// AnalyzeCount is the row count when the NDVs are collected, which is used to scale them to the current row count.
+	AnalyzeCount int64
+	// StartTS is the start ts of the transaction in which the stats are collected, and TxnDelta is the row count delta
+	// of that transaction at that time. The uncommitted rows are counted by ANALYZE, so they are not counted twice.
+	StartTS  uint64
+	TxnDelta int64
+}
+
+// RealtimeRowCount returns the row count of the table, given the row count delta of the current transaction.
+func (s *TemporaryTableStats) RealtimeRowCount(startTS uint64, txnDelta int64) int64 {
+	if s.StartTS == startTS {
+		txnDelta -= s.TxnDelta
+	}
+	return max(s.RowCount+txnDelta, 0)
+}
+
+// ColumnNDV returns the NDV of the column scaled to the row count. It returns false if the column is not analyzed,
+// or the table was empty when it was analyzed.
+func (s *TemporaryTableStats) ColumnNDV(colID int64, rowCount float64) (float64, bool) {
+	ndv, ok := s.ColNDVs[colID]
+	if !ok || s.AnalyzeCount <= 0 {
+		return 0, false
+	}
+	return min(float64(ndv)*rowCount/float64(s.AnalyzeCount), rowCount), true
+}
+
+// GetTemporaryTableStats returns the stats of the temporary table kept in the session, and its row count including
+// the uncommitted changes of the current transaction. It returns false if the session has neither written nor
+// analyzed the table.
+func GetTemporaryTableStats(vars *variable.SessionVars, tblInfo *model.TableInfo) (*TemporaryTableStats, int64, bool) {
+	txnCtx := vars.TxnCtx
+	var (
+		startTS  uint64
+		txnDelta variable.TableDelta
+	)
+	if txnCtx != nil {
+		startTS = txnCtx.StartTS
+		txnDelta = txnCtx.GetDeltaForTable(tblInfo.ID)
+	}
+	s, ok := vars.TemporaryTableStats[tblInfo.ID].(*TemporaryTableStats)
+	// The rows of the global temporary tables are deleted when the transaction ends.
+	if ok && tblInfo.TempTableType == model.TempTableGlobal && s.StartTS != startTS {
+		ok = false
+	}
+	if !ok {
+		if txnDelta.Count == 0 {
+			return nil, 0, false
+		}
+		return &TemporaryTableStats{}, max(txnDelta.Delta, 0), true
+	}
+	return s, s.RealtimeRowCount(startTS, txnDelta.Delta), true
+}
+
+// SetTemporaryTableStats keeps the stats of the temporary table in the session.
+func SetTemporaryTableStats(vars *variable.SessionVars, tblID int64, s *TemporaryTableStats) {
+	if vars.TemporaryTableStats == nil {
+		vars.TemporaryTableStats = make(map[int64]any)
+	}
+	vars.TemporaryTableStats[tblID] = s
+}
+
+// DropTemporaryTableStats drops the stats of the temporary table kept in the session.
+func DropTemporaryTableStats(vars *variable.SessionVars, tblID int64) {
+	delete(vars.TemporaryTableStats, tblID)
+}
+
+// CommitTemporaryTableStats applies the changes of the committed transaction to the stats of the temporary tables
+// kept in the session. The rows of the global temporary tables are deleted on commit, so their stats are dropped.
+func CommitTemporaryTableStats(vars *variable.SessionVars) {
+	txnCtx := vars.TxnCtx
+	for tblID, tbl := range txnCtx.TemporaryTables {
+		if tbl.GetMeta().TempTableType != model.TempTableLocal {
+			DropTemporaryTableStats(vars, tblID)
+			continue
+		}
+		delta := txnCtx.GetDeltaForTable(tblID)
+		s, ok := vars.TemporaryTableStats[tblID].(*TemporaryTableStats)
+		if !ok {
+			if delta.Count == 0 {
+				continue
+			}
+			s = &TemporaryTableStats{}
+			SetTemporaryTableStats(vars, tblID, s)
+		}
+		s.RowCount = s.RealtimeRowCount(txnCtx.StartTS, delta.Delta)
+		s.StartTS, s.TxnDelta = 0, 0
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util/tableutil"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTempTable struct {
+	tableutil.TempTable
+	meta *model.TableInfo
+}
+
+func (t *mockTempTable) GetMeta() *model.TableInfo {
+	return t.meta
+}
+
+func TestTemporaryTableStats(t *testing.T) {
+	s := &TemporaryTableStats{
+		ColNDVs:      map[int64]int64{1: 50},
+		RowCount:     100,
+		AnalyzeCount: 100,
+		StartTS:      10,
+		TxnDelta:     20,
+	}
+	// The rows written before ANALYZE in the same transaction are not counted twice.
+	require.Equal(t, int64(110), s.RealtimeRowCount(10, 30))
+	require.Equal(t, int64(130), s.RealtimeRowCount(11, 30))
+	require.Equal(t, int64(0), s.RealtimeRowCount(11, -200))
+	ndv, ok := s.ColumnNDV(1, 200)
+	require.True(t, ok)
+	require.Equal(t, 100.0, ndv)
+	ndv, ok = s.ColumnNDV(1, 20)
+	require.True(t, ok)
+	require.Equal(t, 10.0, ndv)
+	_, ok = s.ColumnNDV(2, 200)
+	require.False(t, ok)
+}
+
+func TestTemporaryTableStatsInSession(t *testing.T) {
+	local := &model.TableInfo{ID: 1, TempTableType: model.TempTableLocal}
+	global := &model.TableInfo{ID: 2, TempTableType: model.TempTableGlobal}
+	vars := variable.NewSessionVars(nil)
+	vars.TxnCtx = &variable.TransactionContext{}
+	vars.TxnCtx.StartTS = 10
+
+	_, _, ok := GetTemporaryTableStats(vars, local)
+	require.False(t, ok)
+	vars.TxnCtx.UpdateDeltaForTable(local.ID, 5, 5, nil)
+	vars.TxnCtx.UpdateDeltaForTable(global.ID, 3, 3, nil)
+	_, rowCount, ok := GetTemporaryTableStats(vars, local)
+	require.True(t, ok)
+	require.Equal(t, int64(5), rowCount)
+	SetTemporaryTableStats(vars, global.ID, &TemporaryTableStats{RowCount: 3, StartTS: 10, TxnDelta: 3})
+	_, rowCount, ok = GetTemporaryTableStats(vars, global)
+	require.True(t, ok)
+	require.Equal(t, int64(3), rowCount)
+
+	// The rows of the local temporary table are kept after commit, while the global one is emptied.
+	vars.TxnCtx.TemporaryTables = map[int64]tableutil.TempTable{
+		local.ID:  &mockTempTable{meta: local},
+		global.ID: &mockTempTable{meta: global},
+	}
+	CommitTemporaryTableStats(vars)
+	vars.TxnCtx = &variable.TransactionContext{}
+	vars.TxnCtx.StartTS = 20
+	_, rowCount, ok = GetTemporaryTableStats(vars, local)
+	require.True(t, ok)
+	require.Equal(t, int64(5), rowCount)
+	_, _, ok = GetTemporaryTableStats(vars, global)
+	require.False(t, ok)
+
+	DropTemporaryTableStats(vars, local.ID)
+	_, _, ok = GetTemporaryTableStats(vars, local)
+	require.False(t, ok)
+}
@@ -0,0 +1,261 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/meta/model"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+)
+
+const (
+	// maxTrigramStatsTrigrams is the max number of the most frequent trigrams kept for a string column.
+	maxTrigramStatsTrigrams = 1024
+	// maxTrigramCandidates is the max number of distinct trigrams tracked while collecting a string column.
+	maxTrigramCandidates = 65536
+	// maxTrigramValueLen is the max length in bytes of the prefix of a value whose trigrams are collected.
+	maxTrigramValueLen = 1024
+	// maxTrigramStatsSize is the max size of the encoded trigram stats, it must fit in mysql.stats_extended.stats.
+	maxTrigramStatsSize = 60 * 1024
+)
+
+// TrigramStats is the trigram statistics of a string column, which tells the fraction of rows containing a
+// substring. It's collected from the samples of ANALYZE if tidb_analyze_collect_trigram_stats is on, and kept in
+// mysql.stats_extended as the type StatsTypeTrigram. All the counts are in the unit of sampled rows.
+// The trigrams are the sequences of 3 characters of the lower-cased values, so they can't tell the cases apart.
+type TrigramStats struct {
+	// SampleCount is the number of sampled rows, including the rows whose value is NULL.
+	SampleCount int64 `json:"sample_count"`
+	NullCount   int64 `json:"null_count"`
+	// Trigrams are the most frequent trigrams sorted by Count in descending order.
+	Trigrams []TrigramItem `json:"trigrams"`
+	// RemainedNDV and RemainedCount are the number of the distinct trigrams which are not kept in Trigrams and the
+	// sum of their counts.
+	RemainedNDV   int64 `json:"remained_ndv"`
+	RemainedCount int64 `json:"remained_count"`
+
+	gramMap map[string]int64
+}
+
+// TrigramItem is a trigram and the number of sampled rows containing it.
+type TrigramItem struct {
+	Gram  string `json:"gram"`
+	Count int64  `json:"count"`
+}
+
+// TrigramStatsName returns the name of the trigram stats of the column in mysql.stats_extended.
+func TrigramStatsName(colID int64) string {
+	return fmt.Sprintf("__trigram_%d", colID)
+}
+
+// DecodeTrigramStats decodes the trigram stats from the stats of mysql.stats_extended.
+func DecodeTrigramStats(str string) (*TrigramStats, error) {
+	s := &TrigramStats{}
+	if err := json.Unmarshal([]byte(str), s); err != nil {
+		return nil, errors.Trace(err)
+	}
+	s.gramMap = make(map[string]int64, len(s.Trigrams))
+	for _, item := range s.Trigrams {
+		s.gramMap[item.Gram] = item.Count
+	}
+	return s, nil
+}
+
+// SubstringSelectivity returns the fraction of rows containing all the substrings, which are the literal parts of
+// a pattern like `%abc%def%`. Only the substrings of at least 3 characters can be estimated, and the fraction is
+// bounded by the least frequent trigram of them. It returns false if none of the substrings can be estimated.
+func (s *TrigramStats) SubstringSelectivity(substrs []string) (float64, bool) {
+	if s.SampleCount <= 0 {
+		return 0, false
+	}
+	minCount, found := float64(s.SampleCount), false
+	for _, substr := range substrs {
+		forEachTrigram(strings.ToLower(substr), func(gram string) {
+			found = true
+			minCount = min(minCount, s.gramCount(gram))
+		})
+	}
+	if !found {
+		return 0, false
+	}
+	return minCount / float64(s.SampleCount), true
+}
+
+// gramCount returns the number of sampled rows containing the trigram. The trigrams which are not kept are assumed
+// to be one of the remaining distinct trigrams.
+func (s *TrigramStats) gramCount(gram string) float64 {
+	if cnt, ok := s.gramMap[gram]; ok {
+		return float64(cnt)
+	}
+	if s.RemainedNDV <= 0 || s.RemainedCount <= 0 {
+		// The trigram is not sampled, so it's assumed to appear in half a sampled row.
+		return 0.5
+	}
+	return float64(s.RemainedCount) / float64(s.RemainedNDV)
+}
+
+// NullSelectivity returns the fraction of rows whose value is NULL.
+func (s *TrigramStats) NullSelectivity() float64 {
+	if s.SampleCount <= 0 {
+		return 0
+	}
+	return float64(s.NullCount) / float64(s.SampleCount)
+}
+
+// forEachTrigram calls f for each trigram of the string, the same trigram may be passed more than once.
+func forEachTrigram(str string, f func(gram string)) {
+	// The start offsets of the last 3 characters.
+	var offsets [3]int
+	n := 0
+	for i := range str {
+		offsets[0], offsets[1], offsets[2] = offsets[1], offsets[2], i
+		n++
+		if n >= 3 {
+			_, size := utf8.DecodeRuneInString(str[i:])
+			f(str[offsets[0] : i+size])
+		}
+	}
+}
+
+// trigramStatsCollector collects the trigram stats of a string column from the sampled rows.
+type trigramStatsCollector struct {
+	sampleCount int64
+	nullCount   int64
+	grams       map[string]int64
+	// untracked is the number of the trigrams which are not tracked since there are too many distinct trigrams.
+	untracked int64
+	// seen dedups the trigrams in a row.
+	seen map[string]struct{}
+}
+
+func newTrigramStatsCollector() *trigramStatsCollector {
+	return &trigramStatsCollector{
+		grams: make(map[string]int64),
+		seen:  make(map[string]struct{}),
+	}
+}
+
+func (c *trigramStatsCollector) collect(d *types.Datum) {
+	c.sampleCount++
+	if d.IsNull() {
+		c.nullCount++
+		return
+	}
+	if d.Kind() != types.KindString && d.Kind() != types.KindBytes {
+		return
+	}
+	str := d.GetString()
+	if len(str) > maxTrigramValueLen {
+		str = str[:maxTrigramValueLen]
+	}
+	clear(c.seen)
+	forEachTrigram(strings.ToLower(str), func(gram string) {
+		c.seen[gram] = struct{}{}
+	})
+	for gram := range c.seen {
+		if _, ok := c.grams[gram]; ok || len(c.grams) < maxTrigramCandidates {
+			c.grams[gram]++
+		} else {
+			c.untracked++
+		}
+	}
+}
+
+func (c *trigramStatsCollector) build() *TrigramStats {
+	items := make([]TrigramItem, 0, len(c.grams))
+	for gram, cnt := range c.grams {
+		items = append(items, TrigramItem{Gram: gram, Count: cnt})
+	}
+	slices.SortFunc(items, func(a, b TrigramItem) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Gram, b.Gram))
+	})
+	s := &TrigramStats{SampleCount: c.sampleCount, NullCount: c.nullCount, Trigrams: items}
+	// The untracked trigrams are assumed to be distinct.
+	s.RemainedNDV, s.RemainedCount = c.untracked, c.untracked
+	truncateTrigramStats(s, maxTrigramStatsTrigrams)
+	return s
+}
+
+// truncateTrigramStats keeps the n most frequent trigrams, and merges the others into the remaining ones.
+func truncateTrigramStats(s *TrigramStats, n int) {
+	if len(s.Trigrams) <= n {
+		return
+	}
+	for _, item := range s.Trigrams[n:] {
+		s.RemainedNDV++
+		s.RemainedCount += item.Count
+	}
+	s.Trigrams = s.Trigrams[:n]
+}
+
+// encodeTrigramStats encodes the trigram stats, the least frequent trigrams are dropped if it's too large.
+func encodeTrigramStats(s *TrigramStats) (string, error) {
+	for {
+		bytes, err := json.Marshal(s)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if len(bytes) <= maxTrigramStatsSize || len(s.Trigrams) == 0 {
+			return string(bytes), nil
+		}
+		truncateTrigramStats(s, len(s.Trigrams)/2)
+	}
+}
+
+// AppendTrigramStats builds the trigram stats for the string columns from the sampled rows of ANALYZE, and appends
+// them to the extended stats.
+func AppendTrigramStats(extStats *ExtendedStatsColl, cols []*model.ColumnInfo, samples []*ReservoirRowSampleItem) (*ExtendedStatsColl, error) {
+	for i, col := range cols {
+		if !types.IsString(col.GetType()) || col.GetType() == mysql.TypeUnspecified {
+			continue
+		}
+		collector := newTrigramStatsCollector()
+		for _, sample := range samples {
+			collector.collect(&sample.Columns[i])
+		}
+		stats := collector.build()
+		if len(stats.Trigrams) == 0 {
+			continue
+		}
+		str, err := encodeTrigramStats(stats)
+		if err != nil {
+			return nil, err
+		}
+		// Decode it again to build the index of the trigrams.
+		stats, err = DecodeTrigramStats(str)
+		if err != nil {
+			return nil, err
+		}
+		if extStats == nil {
+			extStats = NewExtendedStatsColl()
+		}
+		extStats.Stats[TrigramStatsName(col.ID)] = &ExtendedStatsItem{
+			Tp:         ast.StatsTypeTrigram,
+			ColIDs:     []int64{col.ID},
+			StringVals: str,
+			Trigrams:   stats,
+		}
+	}
+	return extStats, nil
+}
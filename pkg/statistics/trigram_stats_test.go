@@ -0,0 +1,83 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/meta/model"
+	pmodel "github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrigramStats(t *testing.T) {
+	var grams []string
+	forEachTrigram("ab数据库x", func(gram string) { grams = append(grams, gram) })
+	require.Equal(t, []string{"ab数", "b数据", "数据库", "据库x"}, grams)
+
+	samples := make([]*ReservoirRowSampleItem, 0, 100)
+	for i := 0; i < 100; i++ {
+		var d types.Datum
+		switch {
+		case i < 10:
+			d.SetNull()
+		case i < 30:
+			d.SetString(fmt.Sprintf("Error: disk %d is full", i), mysql.DefaultCollationName)
+		default:
+			d.SetString(fmt.Sprintf("info: request %d done", i), mysql.DefaultCollationName)
+		}
+		samples = append(samples, &ReservoirRowSampleItem{Columns: []types.Datum{types.NewIntDatum(int64(i)), d}})
+	}
+	cols := []*model.ColumnInfo{
+		{ID: 1, Name: pmodel.NewCIStr("id"), FieldType: *types.NewFieldType(mysql.TypeLonglong)},
+		{ID: 2, Name: pmodel.NewCIStr("msg"), FieldType: *types.NewFieldType(mysql.TypeVarchar)},
+	}
+	extStats, err := AppendTrigramStats(nil, cols, samples)
+	require.NoError(t, err)
+	require.Len(t, extStats.Stats, 1)
+	item := extStats.Stats[TrigramStatsName(2)]
+	require.Equal(t, []int64{2}, item.ColIDs)
+
+	// The stats can be decoded from the stored string.
+	s, err := DecodeTrigramStats(item.StringVals)
+	require.NoError(t, err)
+	require.Equal(t, item.Trigrams.Trigrams, s.Trigrams)
+	require.Equal(t, int64(100), s.SampleCount)
+	require.Equal(t, 0.1, s.NullSelectivity())
+
+	sel, ok := s.SubstringSelectivity([]string{"error"})
+	require.True(t, ok)
+	require.Equal(t, 0.2, sel)
+	// The trigrams are case-insensitive, and the least frequent trigram bounds the selectivity.
+	sel, ok = s.SubstringSelectivity([]string{"INFO", "full"})
+	require.True(t, ok)
+	require.Equal(t, 0.2, sel)
+	// The trigrams which are not sampled appear in half a sampled row.
+	sel, ok = s.SubstringSelectivity([]string{"xyz"})
+	require.True(t, ok)
+	require.Equal(t, 0.005, sel)
+	_, ok = s.SubstringSelectivity([]string{"ab", "c"})
+	require.False(t, ok)
+
+	// The least frequent trigrams are merged into the remaining ones.
+	truncateTrigramStats(s, 1)
+	require.Len(t, s.Trigrams, 1)
+	require.Equal(t, int64(70), s.Trigrams[0].Count)
+	require.Positive(t, s.RemainedNDV)
+	require.Positive(t, s.RemainedCount)
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+)
+
+// VectorStats is the statistics of a vector column and its vector indexes. The histograms of the vector columns are
+// meaningless, so the vector columns are not sampled by ANALYZE. Instead, VectorStats is collected by ANALYZE and kept
+// in mysql.stats_extended as the type StatsTypeVector, whose column ID is the vector column.
+type VectorStats struct {
+	// RowCount is the row count of the table when the stats are collected.
+	RowCount int64 `json:"row_count"`
+	// NullCount is the number of the NULL vectors.
+	NullCount int64 `json:"null_count"`
+	// MinDimension and MaxDimension are the min and max dimensions of the not NULL vectors. They are both the declared
+	// dimension if the column has a fixed dimension.
+	MinDimension int64 `json:"min_dimension"`
+	MaxDimension int64 `json:"max_dimension"`
+	// IndexCoverage maps the IDs of the vector indexes on the column to the fractions of the rows covered by them.
+	// The vector indexes are built by TiFlash asynchronously, and the rows not covered yet are searched by brute force.
+	IndexCoverage map[int64]float64 `json:"index_coverage,omitempty"`
+}
+
+// VectorStatsName returns the name of the vector stats in mysql.stats_extended.
+func VectorStatsName(colID int64) string {
+	return fmt.Sprintf("__vec_%d", colID)
+}
+
+// DecodeVectorStats decodes the vector stats from the stats of mysql.stats_extended.
+func DecodeVectorStats(str string) (*VectorStats, error) {
+	s := &VectorStats{}
+	if err := json.Unmarshal([]byte(str), s); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s, nil
+}
+
+// NewVectorStatsItem encodes the vector stats into an extended stats item.
+func NewVectorStatsItem(colID int64, s *VectorStats) (*ExtendedStatsItem, error) {
+	bytes, err := json.Marshal(s)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ExtendedStatsItem{
+		Tp:         ast.StatsTypeVector,
+		ColIDs:     []int64{colID},
+		StringVals: string(bytes),
+		Vector:     s,
+	}, nil
+}
+
+// NullRatio returns the fraction of the NULL vectors.
+func (s *VectorStats) NullRatio() float64 {
+	if s.RowCount <= 0 {
+		return 0
+	}
+	return min(float64(s.NullCount)/float64(s.RowCount), 1)
+}
+
+// IsDimensionUniform returns whether all the not NULL vectors have the same dimension.
+func (s *VectorStats) IsDimensionUniform() bool {
+	return s.MinDimension == s.MaxDimension
+}
+
+// UnindexedRowCount estimates the number of the not NULL vectors which are not covered by the vector index yet, given
+// the current row count of the table. An ANN search through the index has to search these rows by brute force.
+// The index is regarded as fully covered if its coverage is unknown.
+func (s *VectorStats) UnindexedRowCount(indexID int64, rowCount float64) float64 {
+	coverage, ok := s.IndexCoverage[indexID]
+	if !ok {
+		return 0
+	}
+	return rowCount * (1 - s.NullRatio()) * (1 - min(max(coverage, 0), 1))
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVectorStats(t *testing.T) {
+	s := &VectorStats{
+		RowCount:      1000,
+		NullCount:     200,
+		MinDimension:  3,
+		MaxDimension:  3,
+		IndexCoverage: map[int64]float64{1: 0.75},
+	}
+	require.InDelta(t, 0.2, s.NullRatio(), 1e-9)
+	require.True(t, s.IsDimensionUniform())
+	// A quarter of the 1600 not NULL vectors are not covered by the index.
+	require.InDelta(t, 400, s.UnindexedRowCount(1, 2000), 1e-9)
+	// The coverage of the index 2 is unknown.
+	require.Zero(t, s.UnindexedRowCount(2, 2000))
+
+	item, err := NewVectorStatsItem(5, s)
+	require.NoError(t, err)
+	require.Equal(t, `{"row_count":1000,"null_count":200,"min_dimension":3,"max_dimension":3,"index_coverage":{"1":0.75}}`, item.StringVals)
+	require.Equal(t, []int64{5}, item.ColIDs)
+	vec, err := DecodeVectorStats(item.StringVals)
+	require.NoError(t, err)
+	require.Equal(t, item.Vector, vec)
+	require.Equal(t, "__vec_5", VectorStatsName(5))
+
+	s = &VectorStats{MinDimension: 3, MaxDimension: 5}
+	require.Zero(t, s.NullRatio())
+	require.False(t, s.IsDimensionUniform())
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+// WorkloadClass is the class of a table classified by its size and data change pattern.
+// It is used to choose the default analyze options for the table.
+type WorkloadClass string
+
+const (
+	// WorkloadClassGeneral is the class of the tables that do not fit into other classes.
+	WorkloadClassGeneral WorkloadClass = "general"
+	// WorkloadClassAppendOnly is the class of the tables whose changes are mostly inserts.
+	WorkloadClassAppendOnly WorkloadClass = "append-only"
+	// WorkloadClassUpdateHeavy is the class of the tables whose changes are mostly updates and deletes.
+	WorkloadClassUpdateHeavy WorkloadClass = "update-heavy"
+	// WorkloadClassSmallDimension is the class of the small tables, which are usually dimension tables.
+	WorkloadClassSmallDimension WorkloadClass = "small-dimension"
+	// WorkloadClassHugeFact is the class of the huge tables, which are usually fact tables.
+	WorkloadClassHugeFact WorkloadClass = "huge-fact"
+)
+
+var (
+	// SmallDimensionMaxRowCount is the max row count of the small dimension tables.
+	SmallDimensionMaxRowCount int64 = 100_000
+	// HugeFactMinRowCount is the min row count of the huge fact tables.
+	HugeFactMinRowCount int64 = 100_000_000
+)
+
+// ClassifyWorkload classifies the table by its size and the changes since the last analysis.
+// The table is classified by the size first. Otherwise, if nearly all the modifications increase
+// the row count, it is append-only; if few of them do, it is update-heavy.
+func ClassifyWorkload(t *Table) WorkloadClass {
+	if t == nil || t.Pseudo || !t.IsAnalyzed() {
+		return WorkloadClassGeneral
+	}
+	if t.RealtimeCount <= SmallDimensionMaxRowCount {
+		return WorkloadClassSmallDimension
+	}
+	if t.RealtimeCount >= HugeFactMinRowCount {
+		return WorkloadClassHugeFact
+	}
+	analyzeRowCount := t.GetAnalyzeRowCount()
+	if t.ModifyCount == 0 || analyzeRowCount < 0 {
+		return WorkloadClassGeneral
+	}
+	// Each insert adds one to both the row count and the modify count,
+	// while an update adds nothing to the row count and a delete decreases it.
+	growth := float64(t.RealtimeCount) - analyzeRowCount
+	modifyCount := float64(t.ModifyCount)
+	switch {
+	case growth >= 0.9*modifyCount:
+		return WorkloadClassAppendOnly
+	case growth <= 0.1*modifyCount:
+		return WorkloadClassUpdateHeavy
+	default:
+		return WorkloadClassGeneral
+	}
+}
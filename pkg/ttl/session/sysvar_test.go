@@ -123,3 +123,27 @@ func TestSysVarTTLScanDeleteLimit(t *testing.T) {
 	tk.MustQuery("select @@global.tidb_ttl_delete_rate_limit").Check(testkit.Rows("0"))
 	tk.MustQuery("select @@tidb_ttl_delete_rate_limit").Check(testkit.Rows("0"))
 }
+
+func TestSysVarTTLStatsRefreshRatio(t *testing.T) {
+	origRatio := variable.TTLStatsRefreshRatio.Load()
+	defer func() {
+		variable.TTLStatsRefreshRatio.Store(origRatio)
+	}()
+
+	store := testkit.CreateMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select @@global.tidb_ttl_stats_refresh_ratio").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@global.tidb_ttl_stats_refresh_ratio=0.3")
+	require.Equal(t, 0.3, variable.TTLStatsRefreshRatio.Load())
+	tk.MustQuery("select @@global.tidb_ttl_stats_refresh_ratio").Check(testkit.Rows("0.3"))
+	tk.MustQuery("select @@tidb_ttl_stats_refresh_ratio").Check(testkit.Rows("0.3"))
+
+	tk.MustExec("set @@global.tidb_ttl_stats_refresh_ratio=2")
+	require.Equal(t, 1.0, variable.TTLStatsRefreshRatio.Load())
+	tk.MustQuery("select @@global.tidb_ttl_stats_refresh_ratio").Check(testkit.Rows("1"))
+
+	tk.MustExec("set @@global.tidb_ttl_stats_refresh_ratio=-1")
+	require.Equal(t, 0.0, variable.TTLStatsRefreshRatio.Load())
+	tk.MustQuery("select @@global.tidb_ttl_stats_refresh_ratio").Check(testkit.Rows("0"))
+}
@@ -571,11 +571,53 @@ j:
 				continue
 			}
 			m.removeJob(job)
+			m.refreshStatsIfPurged(se, job, summary)
 		}
 		cancel()
 	}
 }
 
+// refreshStatsIfPurged refreshes the stats of the table if the finished job deleted a large part of it,
+// see tidb_ttl_stats_refresh_ratio.
+func (m *JobManager) refreshStatsIfPurged(se session.Session, job *ttlJob, summary *TTLSummary) {
+	ratio := variable.TTLStatsRefreshRatio.Load()
+	if ratio <= 0 || summary == nil || summary.SuccessRows == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, ttlInternalSQLTimeout)
+	defer cancel()
+	rows, err := se.ExecuteSQL(ctx, "SELECT count FROM mysql.stats_meta WHERE table_id = %?", job.tbl.ID)
+	if err != nil {
+		logutil.Logger(m.ctx).Warn("fail to read the row count of the table", zap.Int64("tableID", job.tbl.ID), zap.Error(err))
+		return
+	}
+	var rowCount int64
+	if len(rows) > 0 {
+		rowCount = rows[0].GetInt64(0)
+	}
+	if !isHeavilyPurged(summary.SuccessRows, rowCount, ratio) {
+		return
+	}
+
+	if err = RefreshStats(job.tbl.ID); err != nil {
+		logutil.Logger(m.ctx).Warn("fail to refresh the stats of the table purged by TTL job",
+			zap.String("jobID", job.id), zap.Int64("tableID", job.tbl.ID), zap.Error(err))
+		return
+	}
+	logutil.Logger(m.ctx).Info("refresh the stats of the table purged by TTL job",
+		zap.String("jobID", job.id), zap.Int64("tableID", job.tbl.ID),
+		zap.Uint64("deletedRows", summary.SuccessRows), zap.Int64("rowCount", rowCount))
+}
+
+// isHeavilyPurged checks whether the ratio of the deleted rows to the row count before the TTL job exceeds the ratio.
+// The row count in the stats meta may not have counted all the deleted rows yet, so the deleted rows are added back to
+// it, which underestimates the ratio in the worst case.
+func isHeavilyPurged(deletedRows uint64, rowCount int64, ratio float64) bool {
+	total := float64(max(rowCount, 0)) + float64(deletedRows)
+	return total > 0 && float64(deletedRows)/total > ratio
+}
+
 func (m *JobManager) rescheduleJobs(se session.Session, now time.Time) {
 	tz, err := se.GlobalTimeZone(m.ctx)
 	if err != nil {
@@ -665,3 +665,14 @@ func TestLocalJobs(t *testing.T) {
 	assert.Len(t, m.localJobs(), 1)
 	assert.Equal(t, m.localJobs()[0].id, "1")
 }
+
+func TestIsHeavilyPurged(t *testing.T) {
+	// The row count in the stats meta hasn't counted the deleted rows.
+	assert.False(t, isHeavilyPurged(100, 1000, 0.1))
+	// The row count in the stats meta has counted the deleted rows.
+	assert.True(t, isHeavilyPurged(500, 500, 0.3))
+	assert.False(t, isHeavilyPurged(500, 500, 0.5))
+	// All the rows are deleted.
+	assert.True(t, isHeavilyPurged(100, 0, 0.9))
+	assert.False(t, isHeavilyPurged(0, 0, 0.1))
+}
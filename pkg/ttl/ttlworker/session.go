@@ -56,6 +56,13 @@ var DetachStatsCollector = func(s sqlexec.SQLExecutor) sqlexec.SQLExecutor {
 	return s
 }
 
+// RefreshStats flushes the stats deltas of the current instance, so that the rows deleted by TTL are counted in
+// the stats meta explicitly, and pushes the table into the auto analyze priority queue.
+// this function is registered in BootstrapSession in /session/session.go
+var RefreshStats = func(physicalID int64) error {
+	return nil
+}
+
 var allIsolationReadEngines = map[kv.StoreType]struct{}{
 	kv.TiKV:    {},
 	kv.TiFlash: {},
@@ -292,6 +292,11 @@ func (bj BinaryJSON) ArrayGetElem(idx int) BinaryJSON {
 	return bj.valEntryGet(headerSize + idx*valEntrySize)
 }
 
+// ObjectGetKeyAndVal gets the key and the value of the member at the index `i` of the object.
+func (bj BinaryJSON) ObjectGetKeyAndVal(i int) ([]byte, BinaryJSON) {
+	return bj.objectGetKey(i), bj.objectGetVal(i)
+}
+
 func (bj BinaryJSON) objectGetKey(i int) []byte {
 	keyOff := int(jsonEndian.Uint32(bj.Value[headerSize+i*keyEntrySize:]))
 	keyLen := int(jsonEndian.Uint16(bj.Value[headerSize+i*keyEntrySize+keyLenOff:]))
@@ -371,6 +371,19 @@ func IsBinCollation(collate string) bool {
 	// TODO: define a constant to reference collations
 }
 
+// DecodeKey converts the sort key of the collation back to a string which has the same sort key. For the case-insensitive
+// collations, it's the representative of the strings equal to each other, e.g. 'ABC' for 'abc' and 'Abc'. It returns
+// false if the sort keys of the collation can't be decoded.
+func DecodeKey(key []byte, collate string) (string, bool) {
+	if !NewCollationEnabled() || IsBinCollation(collate) {
+		return string(key), true
+	}
+	if _, ok := GetCollator(collate).(*generalCICollator); ok {
+		return decodeGeneralCIKey(key)
+	}
+	return "", false
+}
+
 // IsPadSpaceCollation returns whether the collation is a PAD SPACE collation.
 func IsPadSpaceCollation(collation string) bool {
 	return collation != charset.CollationBin && collation != "utf8mb4_0900_ai_ci" && collation != "utf8mb4_0900_bin"
@@ -217,3 +217,27 @@ func TestGetCollator(t *testing.T) {
 	require.IsType(t, &gbkBinCollator{}, GetCollator("gbk_bin"))
 	require.IsType(t, &gbkBinCollator{}, GetCollatorByID(87))
 }
+
+func TestDecodeKey(t *testing.T) {
+	SetNewCollationEnabledForTest(true)
+	defer SetNewCollationEnabledForTest(false)
+	for _, collation := range []string{"binary", "utf8mb4_bin", "utf8mb4_general_ci", "utf8_general_ci"} {
+		str, ok := DecodeKey(GetCollator(collation).Key("aBc"), collation)
+		require.True(t, ok)
+		if IsBinCollation(collation) {
+			require.Equal(t, "aBc", str)
+		} else {
+			require.Equal(t, "ABC", str)
+		}
+	}
+	str, ok := DecodeKey(GetCollator("utf8mb4_general_ci").Key("Ää 中文"), "utf8mb4_general_ci")
+	require.True(t, ok)
+	require.Equal(t, "AA 中文", str)
+	_, ok = DecodeKey(GetCollator("utf8mb4_unicode_ci").Key("abc"), "utf8mb4_unicode_ci")
+	require.False(t, ok)
+
+	SetNewCollationEnabledForTest(false)
+	str, ok = DecodeKey([]byte("aBc"), "utf8mb4_general_ci")
+	require.True(t, ok)
+	require.Equal(t, "aBc", str)
+}
@@ -15,6 +15,8 @@
 package collate
 
 import (
+	"strings"
+
 	"github.com/pingcap/tidb/pkg/util/stringutil"
 )
 
@@ -57,6 +59,19 @@ func (*generalCICollator) KeyWithoutTrimRightSpace(str string) []byte {
 	return buf
 }
 
+// decodeGeneralCIKey converts each weight in the key back to the rune which has the weight.
+func decodeGeneralCIKey(key []byte) (string, bool) {
+	if len(key)%2 != 0 {
+		return "", false
+	}
+	var sb strings.Builder
+	sb.Grow(len(key) / 2)
+	for i := 0; i < len(key); i += 2 {
+		sb.WriteRune(rune(key[i])<<8 | rune(key[i+1]))
+	}
+	return sb.String(), true
+}
+
 // Pattern implements Collator interface.
 func (*generalCICollator) Pattern() WildcardPattern {
 	return &ciPattern{}
@@ -503,6 +503,11 @@ var (
 	// ErrWarnGlobalIndexNeedManuallyAnalyze is used for global indexes,
 	// which cannot trigger automatic analysis when it contains prefix columns or virtual generated columns.
 	ErrWarnGlobalIndexNeedManuallyAnalyze = ClassDDL.NewStd(mysql.ErrWarnGlobalIndexNeedManuallyAnalyze)
+	// ErrObjectHasDependents is returned when dropping an object referenced by other objects with RESTRICT,
+	// and is reported as a warning without RESTRICT or CASCADE.
+	ErrObjectHasDependents = ClassDDL.NewStd(mysql.ErrObjectHasDependents)
+	// ErrUnsupportedDropRange is returned when the rows of DROP RANGE can't be deleted by the key ranges safely.
+	ErrUnsupportedDropRange = ClassDDL.NewStd(mysql.ErrUnsupportedDropRange)
 )
 
 // ReorgRetryableErrCodes are the error codes that are retryable for reorganization.
@@ -30,13 +30,14 @@ import (
 
 // Handle is the handler for expensive query.
 type Handle struct {
-	exitCh chan struct{}
-	sm     atomic.Value
+	exitCh  chan struct{}
+	sm      atomic.Value
+	longTxn *longTxnMonitor
 }
 
 // NewExpensiveQueryHandle builds a new expensive query handler.
 func NewExpensiveQueryHandle(exitCh chan struct{}) *Handle {
-	return &Handle{exitCh: exitCh}
+	return &Handle{exitCh: exitCh, longTxn: newLongTxnMonitor()}
 }
 
 // SetSessionManager sets the SessionManager which is used to fetching the info
@@ -114,6 +115,7 @@ func (eqh *Handle) Run() {
 					}
 				}
 			}
+			eqh.longTxn.check(sm, processInfo, time.Now())
 			threshold = atomic.LoadUint64(&variable.ExpensiveQueryTimeThreshold)
 			txnThreshold = atomic.LoadUint64(&variable.ExpensiveTxnTimeThreshold)
 		case <-eqh.exitCh:
@@ -122,6 +124,11 @@ func (eqh *Handle) Run() {
 	}
 }
 
+// LongTxnEvents returns the recent events of the transactions violating tidb_long_txn_policy in time order.
+func (eqh *Handle) LongTxnEvents() []LongTxnEvent {
+	return eqh.longTxn.events()
+}
+
 // LogOnQueryExceedMemQuota prints a log when memory usage of connID is out of memory quota.
 func (eqh *Handle) LogOnQueryExceedMemQuota(connID uint64) {
 	if log.GetLevel() > zapcore.WarnLevel {
@@ -0,0 +1,183 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expensivequery
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/metrics"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	"go.uber.org/zap"
+)
+
+// The violations of tidb_long_txn_policy.
+const (
+	// LongTxnViolationIdle means the transaction is idle for too long.
+	LongTxnViolationIdle = "IDLE"
+	// LongTxnViolationOpen means the transaction is open for too long.
+	LongTxnViolationOpen = "OPEN"
+)
+
+// maxLongTxnEvents is the max number of the long transaction events kept in memory.
+const maxLongTxnEvents = 1024
+
+// LongTxnEvent is an event that a transaction violates tidb_long_txn_policy.
+type LongTxnEvent struct {
+	Time          time.Time
+	TxnStartTime  time.Time
+	User          string
+	Host          string
+	DB            string
+	ResourceGroup string
+	Violation     string
+	Action        string
+	ConnID        uint64
+	TxnStartTS    uint64
+	Duration      time.Duration
+	Threshold     time.Duration
+}
+
+type longTxnKey struct {
+	connID     uint64
+	txnStartTS uint64
+	violation  string
+}
+
+// longTxnMonitor applies tidb_long_txn_policy to the transactions of the sessions.
+type longTxnMonitor struct {
+	mu struct {
+		sync.Mutex
+		// events is a ring buffer of the recent events.
+		events []LongTxnEvent
+		next   int
+	}
+	// handled records the violations which have been handled, so each of them is only handled once.
+	handled map[longTxnKey]struct{}
+}
+
+func newLongTxnMonitor() *longTxnMonitor {
+	return &longTxnMonitor{handled: make(map[longTxnKey]struct{})}
+}
+
+func (m *longTxnMonitor) check(sm util.SessionManager, processInfo map[uint64]*util.ProcessInfo, now time.Time) {
+	policy := variable.LongTxnPolicyValue.Load()
+	if policy == nil {
+		clear(m.handled)
+		return
+	}
+	var exemptUsers []string
+	if users := variable.LongTxnPolicyExemptUsers.Load(); users != "" {
+		exemptUsers = strings.Split(users, ",")
+		for i := range exemptUsers {
+			exemptUsers[i] = strings.TrimSpace(exemptUsers[i])
+		}
+	}
+	active := make(map[longTxnKey]struct{}, len(m.handled))
+	for _, info := range processInfo {
+		// The start ts is also set for the stale reads, whose create time is zero.
+		if info.CurTxnStartTS == 0 || info.CurTxnCreateTime.IsZero() || (info.StmtCtx != nil && info.StmtCtx.InRestrictedSQL) {
+			continue
+		}
+		rule := policy.Rule(info.ResourceGroupName)
+		if rule == nil || slices.Contains(exemptUsers, info.User) {
+			continue
+		}
+		if rule.IdleTimeout > 0 && info.Command == mysql.ComSleep {
+			if idle := now.Sub(info.Time); idle >= rule.IdleTimeout {
+				m.handleViolation(sm, info, LongTxnViolationIdle, idle, rule.IdleTimeout, rule.Action, now, active)
+			}
+		}
+		if rule.OpenTimeout > 0 {
+			if open := now.Sub(info.CurTxnCreateTime); open >= rule.OpenTimeout {
+				m.handleViolation(sm, info, LongTxnViolationOpen, open, rule.OpenTimeout, rule.Action, now, active)
+			}
+		}
+	}
+	// Forget the transactions which have finished.
+	for key := range m.handled {
+		if _, ok := active[key]; !ok {
+			delete(m.handled, key)
+		}
+	}
+}
+
+func (m *longTxnMonitor) handleViolation(sm util.SessionManager, info *util.ProcessInfo, violation string,
+	duration, threshold time.Duration, action string, now time.Time, active map[longTxnKey]struct{}) {
+	key := longTxnKey{connID: info.ID, txnStartTS: info.CurTxnStartTS, violation: violation}
+	active[key] = struct{}{}
+	if _, ok := m.handled[key]; ok {
+		return
+	}
+	m.handled[key] = struct{}{}
+	m.addEvent(LongTxnEvent{
+		Time:          now,
+		TxnStartTime:  info.CurTxnCreateTime,
+		User:          info.User,
+		Host:          info.Host,
+		DB:            info.DB,
+		ResourceGroup: info.ResourceGroupName,
+		Violation:     violation,
+		Action:        action,
+		ConnID:        info.ID,
+		TxnStartTS:    info.CurTxnStartTS,
+		Duration:      duration,
+		Threshold:     threshold,
+	})
+	if action == variable.LongTxnActionWarn {
+		return
+	}
+	metrics.LongTxnPolicyCounter.WithLabelValues(violation, info.ResourceGroupName, action).Inc()
+	fields := []zap.Field{
+		zap.Uint64("conn", info.ID),
+		zap.String("user", info.User),
+		zap.String("resourceGroup", info.ResourceGroupName),
+		zap.Uint64("txnStartTS", info.CurTxnStartTS),
+		zap.String("violation", violation),
+		zap.Duration("duration", duration),
+		zap.Duration("threshold", threshold),
+	}
+	if action == variable.LongTxnActionKill {
+		logutil.BgLogger().Warn("long transaction violates the policy, kill it", fields...)
+		sm.Kill(info.ID, false, false, false)
+		return
+	}
+	logutil.BgLogger().Warn("long transaction violates the policy", fields...)
+}
+
+func (m *longTxnMonitor) addEvent(event LongTxnEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.mu.events) < maxLongTxnEvents {
+		m.mu.events = append(m.mu.events, event)
+		return
+	}
+	m.mu.events[m.mu.next] = event
+	m.mu.next = (m.mu.next + 1) % maxLongTxnEvents
+}
+
+// events returns the recent events in time order.
+func (m *longTxnMonitor) events() []LongTxnEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	events := make([]LongTxnEvent, 0, len(m.mu.events))
+	events = append(events, m.mu.events[m.mu.next:]...)
+	return append(events, m.mu.events[:m.mu.next]...)
+}
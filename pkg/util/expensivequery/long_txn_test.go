@@ -0,0 +1,97 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expensivequery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/sessionctx/variable"
+	"github.com/pingcap/tidb/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+type killRecorder struct {
+	util.SessionManager
+	killed []uint64
+}
+
+func (r *killRecorder) Kill(connID uint64, _, _, _ bool) {
+	r.killed = append(r.killed, connID)
+}
+
+func TestLongTxnMonitor(t *testing.T) {
+	policy, err := variable.ParseLongTxnPolicy("idle=1m,open=1h; rg1: open=10m,action=kill; rg2: action=warn")
+	require.NoError(t, err)
+	variable.LongTxnPolicyValue.Store(policy)
+	variable.LongTxnPolicyExemptUsers.Store("admin, batch")
+	defer func() {
+		variable.LongTxnPolicyValue.Store(nil)
+		variable.LongTxnPolicyExemptUsers.Store(variable.DefTiDBLongTxnPolicyExemptUsers)
+	}()
+
+	now := time.Now()
+	processInfo := map[uint64]*util.ProcessInfo{
+		// Idle in the transaction for 5 minutes.
+		1: {ID: 1, User: "u1", Command: mysql.ComSleep, Time: now.Add(-5 * time.Minute), CurTxnStartTS: 100, CurTxnCreateTime: now.Add(-6 * time.Minute)},
+		// Open for 20 minutes in rg1.
+		2: {ID: 2, User: "u2", Command: mysql.ComQuery, Time: now, ResourceGroupName: "rg1", CurTxnStartTS: 200, CurTxnCreateTime: now.Add(-20 * time.Minute)},
+		// Exempted by the user.
+		3: {ID: 3, User: "admin", Command: mysql.ComSleep, Time: now.Add(-5 * time.Minute), CurTxnStartTS: 300, CurTxnCreateTime: now.Add(-2 * time.Hour)},
+		// Exempted by the resource group.
+		4: {ID: 4, User: "u4", Command: mysql.ComSleep, Time: now.Add(-5 * time.Minute), ResourceGroupName: "RG2", CurTxnStartTS: 400, CurTxnCreateTime: now.Add(-2 * time.Hour)},
+		// Not in a transaction.
+		5: {ID: 5, User: "u5", Command: mysql.ComSleep, Time: now.Add(-5 * time.Hour)},
+		// Idle for a short time.
+		6: {ID: 6, User: "u6", Command: mysql.ComSleep, Time: now.Add(-time.Second), CurTxnStartTS: 600, CurTxnCreateTime: now.Add(-time.Minute)},
+	}
+	sm := &killRecorder{}
+	m := newLongTxnMonitor()
+	for i := 0; i < 2; i++ {
+		// Each violation is only handled once.
+		m.check(sm, processInfo, now)
+		require.Equal(t, []uint64{2}, sm.killed)
+		events := m.events()
+		require.Len(t, events, 2)
+		byConn := make(map[uint64]LongTxnEvent, len(events))
+		for _, e := range events {
+			byConn[e.ConnID] = e
+		}
+		require.Equal(t, LongTxnViolationIdle, byConn[1].Violation)
+		require.Equal(t, variable.LongTxnActionWarn, byConn[1].Action)
+		require.Equal(t, 5*time.Minute, byConn[1].Duration)
+		require.Equal(t, time.Minute, byConn[1].Threshold)
+		require.Equal(t, LongTxnViolationOpen, byConn[2].Violation)
+		require.Equal(t, variable.LongTxnActionKill, byConn[2].Action)
+		require.Equal(t, uint64(200), byConn[2].TxnStartTS)
+	}
+
+	// The connection 2 starts a new transaction after being killed, which is handled again.
+	processInfo[2].CurTxnStartTS = 201
+	m.check(sm, processInfo, now)
+	require.Equal(t, []uint64{2, 2}, sm.killed)
+	require.Len(t, m.events(), 3)
+	require.Len(t, m.handled, 2)
+
+	// The events are kept in a ring buffer.
+	for i := 0; i < maxLongTxnEvents+10; i++ {
+		m.addEvent(LongTxnEvent{ConnID: uint64(i)})
+	}
+	events := m.events()
+	require.Len(t, events, maxLongTxnEvents)
+	require.Equal(t, uint64(10), events[0].ConnID)
+	require.Equal(t, uint64(maxLongTxnEvents+9), events[maxLongTxnEvents-1].ConnID)
+}
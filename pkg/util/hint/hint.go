@@ -115,6 +115,8 @@ const (
 	HintSemiJoinRewrite = "semi_join_rewrite"
 	// HintNoDecorrelate indicates a LogicalApply not to be decorrelated.
 	HintNoDecorrelate = "no_decorrelate"
+	// HintIgnoreStats is a hint to enforce using the pseudo stats for some tables.
+	HintIgnoreStats = "ignore_stats"
 
 	// HintMemoryQuota sets the memory limit for a query
 	HintMemoryQuota = "memory_quota"
@@ -541,6 +543,7 @@ type PlanHints struct {
 	LeadingJoinOrder   []HintedTable  // leading
 	HJBuild            []HintedTable  // hash_join_build
 	HJProbe            []HintedTable  // hash_join_probe
+	IgnoreStatsTables  []HintedTable  // ignore_stats
 
 	// Hints belows are not associated with any particular table.
 	PreferAggType    uint // hash_agg, merge_agg, agg_to_cop and so on
@@ -645,6 +648,17 @@ func (pHints *PlanHints) IfPreferHJProbe(tableNames ...*HintedTable) bool {
 	return pHints.MatchTableName(tableNames, pHints.HJProbe)
 }
 
+// IfIgnoreStats checks whether the table is hinted to use the pseudo stats.
+func (pHints *PlanHints) IfIgnoreStats(tableName *HintedTable) bool {
+	for i, tbl := range pHints.IgnoreStatsTables {
+		if tableName.DBName.L == tbl.DBName.L && tableName.TblName.L == tbl.TblName.L && tbl.SelectOffset == tableName.SelectOffset {
+			pHints.IgnoreStatsTables[i].Matched = true
+			return true
+		}
+	}
+	return false
+}
+
 // IfPreferINLJ checks whether the join hint is index nested loop join.
 func (pHints *PlanHints) IfPreferINLJ(tableNames ...*HintedTable) bool {
 	return pHints.MatchTableName(tableNames, pHints.IndexJoin.INLJTables)
@@ -745,6 +759,7 @@ func ParsePlanHints(hints []*ast.TableOptimizerHint,
 		cteMerge                                                                        bool
 		leadingJoinOrder                                                                []HintedTable
 		hjBuildTables, hjProbeTables                                                    []HintedTable
+		ignoreStatsTables                                                               []HintedTable
 		leadingHintCnt                                                                  int
 	)
 	for _, hint := range hints {
@@ -752,7 +767,7 @@ func ParsePlanHints(hints []*ast.TableOptimizerHint,
 		switch hint.HintName.L {
 		case TiDBMergeJoin, HintSMJ, TiDBIndexNestedLoopJoin, HintINLJ, HintINLHJ, HintINLMJ,
 			HintNoHashJoin, HintNoMergeJoin, TiDBHashJoin, HintHJ, HintUseIndex, HintIgnoreIndex,
-			HintForceIndex, HintOrderIndex, HintNoOrderIndex, HintIndexMerge, HintLeading:
+			HintForceIndex, HintOrderIndex, HintNoOrderIndex, HintIndexMerge, HintLeading, HintIgnoreStats:
 			if len(hint.Tables) == 0 {
 				var sb strings.Builder
 				ctx := format.NewRestoreCtx(0, &sb)
@@ -801,6 +816,8 @@ func ParsePlanHints(hints []*ast.TableOptimizerHint,
 			hjBuildTables = append(hjBuildTables, tableNames2HintTableInfo(currentDB, hint.HintName.L, hint.Tables, hintProcessor, currentLevel, warnHandler)...)
 		case HintHashJoinProbe:
 			hjProbeTables = append(hjProbeTables, tableNames2HintTableInfo(currentDB, hint.HintName.L, hint.Tables, hintProcessor, currentLevel, warnHandler)...)
+		case HintIgnoreStats:
+			ignoreStatsTables = append(ignoreStatsTables, tableNames2HintTableInfo(currentDB, hint.HintName.L, hint.Tables, hintProcessor, currentLevel, warnHandler)...)
 		case HintHashAgg:
 			preferAggType |= PreferHashAgg
 		case HintStreamAgg:
@@ -918,6 +935,7 @@ func ParsePlanHints(hints []*ast.TableOptimizerHint,
 		LeadingJoinOrder:   leadingJoinOrder,
 		HJBuild:            hjBuildTables,
 		HJProbe:            hjProbeTables,
+		IgnoreStatsTables:  ignoreStatsTables,
 	}, subQueryHintFlags, nil
 }
 
@@ -1077,6 +1095,7 @@ func CollectUnmatchedHintWarnings(hintInfo *PlanHints) (warnings []string) {
 	warnings = append(warnings, collectUnmatchedJoinHintWarning(HintHashJoinBuild, "", hintInfo.HJBuild)...)
 	warnings = append(warnings, collectUnmatchedJoinHintWarning(HintHashJoinProbe, "", hintInfo.HJProbe)...)
 	warnings = append(warnings, collectUnmatchedJoinHintWarning(HintLeading, "", hintInfo.LeadingJoinOrder)...)
+	warnings = append(warnings, collectUnmatchedJoinHintWarning(HintIgnoreStats, "", hintInfo.IgnoreStatsTables)...)
 	warnings = append(warnings, collectUnmatchedStorageHintWarning(hintInfo.TiFlashTables, hintInfo.TiKVTables)...)
 	return warnings
 }
@@ -566,6 +566,11 @@ func (*Context) DecodeSessionStates(context.Context, sessionctx.Context, *sessio
 	return errors.Errorf("Not Supported")
 }
 
+// ResetSessionStates implements sessionctx.Context ResetSessionStates interface.
+func (*Context) ResetSessionStates(context.Context) error {
+	return errors.Errorf("Not Supported")
+}
+
 // GetExtensions returns the `*extension.SessionExtensions` object
 func (*Context) GetExtensions() *extension.SessionExtensions {
 	return nil
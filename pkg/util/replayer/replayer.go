@@ -33,6 +33,20 @@ type PlanReplayerTaskKey struct {
 	PlanDigest string
 }
 
+// PlanReplayerCaptureThresholds are the thresholds of a plan replayer capture task, an execution of the digests of the
+// task is captured only if it exceeds any of them. The execution is always captured if none of them is set.
+type PlanReplayerCaptureThresholds struct {
+	// EstimationError is the threshold of the max ratio between the estimated rows and the actual rows of the operators.
+	EstimationError float64
+	// Latency is the threshold of the latency of the execution.
+	Latency time.Duration
+}
+
+// IsSet returns whether any of the thresholds is set.
+func (t PlanReplayerCaptureThresholds) IsSet() bool {
+	return t.EstimationError > 0 || t.Latency > 0
+}
+
 // GeneratePlanReplayerFile generates plan replayer file
 func GeneratePlanReplayerFile(isCapture, isContinuesCapture, enableHistoricalStatsForCapture bool) (*os.File, string, error) {
 	path := GetPlanReplayerDirName()
@@ -85,3 +99,10 @@ func GetPlanReplayerDirName() string {
 	tidbLogDir := filepath.Dir(config.GetGlobalConfig().Log.File.Filename)
 	return filepath.Join(tidbLogDir, "replayer")
 }
+
+// GetPlanReplayerSnapshotDirName returns the local directory of the plan replayer snapshots. It's beside the plan
+// replayer directory, so the snapshots are rotated by their count instead of being collected by the dump file GC.
+func GetPlanReplayerSnapshotDirName() string {
+	tidbLogDir := filepath.Dir(config.GetGlobalConfig().Log.File.Filename)
+	return filepath.Join(tidbLogDir, "replayer_snapshot")
+}
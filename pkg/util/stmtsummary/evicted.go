@@ -356,6 +356,16 @@ func addInfo(addTo *stmtSummaryByDigestElement, addWith *stmtSummaryByDigestElem
 	// plan cache
 	addTo.planCacheHits += addWith.planCacheHits
 
+	// index merge
+	addTo.indexMergeIntersectionCount += addWith.indexMergeIntersectionCount
+	addTo.sumIndexMergeIntersectionEstRows += addWith.sumIndexMergeIntersectionEstRows
+	addTo.sumIndexMergeIntersectionActRows += addWith.sumIndexMergeIntersectionActRows
+
+	// stats quality
+	addTo.pseudoStatsCount += addWith.pseudoStatsCount
+	addTo.outdatedStatsCount += addWith.outdatedStatsCount
+	addTo.maxEstimationError = max(addTo.maxEstimationError, addWith.maxEstimationError)
+
 	// other
 	addTo.sumAffectedRows += addWith.sumAffectedRows
 	addTo.sumMem += addWith.sumMem
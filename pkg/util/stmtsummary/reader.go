@@ -308,6 +308,12 @@ const (
 	PlanCacheHitsStr                  = "PLAN_CACHE_HITS"
 	PlanCacheUnqualifiedStr           = "PLAN_CACHE_UNQUALIFIED"
 	PlanCacheUnqualifiedLastReasonStr = "PLAN_CACHE_UNQUALIFIED_LAST_REASON"
+	AvgIdxMergeIntersectEstRowsStr    = "AVG_INDEX_MERGE_INTERSECTION_EST_ROWS"
+	AvgIdxMergeIntersectActRowsStr    = "AVG_INDEX_MERGE_INTERSECTION_ACT_ROWS"
+	PseudoStatsCountStr               = "PSEUDO_STATS_COUNT"
+	OutdatedStatsCountStr             = "OUTDATED_STATS_COUNT"
+	MaxEstimationErrorStr             = "MAX_ESTIMATION_ERROR"
+	StatsVersionsStr                  = "STATS_VERSIONS"
 	PlanInBindingStr                  = "PLAN_IN_BINDING"
 	QuerySampleTextStr                = "QUERY_SAMPLE_TEXT"
 	PrevSampleTextStr                 = "PREV_SAMPLE_TEXT"
@@ -676,4 +682,22 @@ var columnValueFactoryMap = map[string]columnValueFactory{
 	PlanCacheUnqualifiedLastReasonStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) any {
 		return ssElement.lastPlanCacheUnqualified
 	},
+	AvgIdxMergeIntersectEstRowsStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) any {
+		return avgSumFloat(ssElement.sumIndexMergeIntersectionEstRows, ssElement.indexMergeIntersectionCount)
+	},
+	AvgIdxMergeIntersectActRowsStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) any {
+		return avgFloat(ssElement.sumIndexMergeIntersectionActRows, ssElement.indexMergeIntersectionCount)
+	},
+	PseudoStatsCountStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) any {
+		return ssElement.pseudoStatsCount
+	},
+	OutdatedStatsCountStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) any {
+		return ssElement.outdatedStatsCount
+	},
+	MaxEstimationErrorStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) any {
+		return ssElement.maxEstimationError
+	},
+	StatsVersionsStr: func(_ *stmtSummaryReader, ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) any {
+		return ssElement.lastStatsVersions
+	},
 }
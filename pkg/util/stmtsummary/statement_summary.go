@@ -223,6 +223,17 @@ type stmtSummaryByDigestElement struct {
 
 	planCacheUnqualifiedCount int64
 	lastPlanCacheUnqualified  string // the reason why this query is unqualified for the plan cache
+
+	// the estimated and actual rows of the intersections of the index merge readers
+	indexMergeIntersectionCount      int64
+	sumIndexMergeIntersectionEstRows float64
+	sumIndexMergeIntersectionActRows int64
+
+	// the quality of the stats used by the statements
+	pseudoStatsCount   int64
+	outdatedStatsCount int64
+	maxEstimationError float64
+	lastStatsVersions  string
 }
 
 // StmtExecInfo records execution information of each statement.
@@ -266,6 +277,21 @@ type StmtExecInfo struct {
 	CPUUsages         ppcpuusage.CPUUsages
 
 	PlanCacheUnqualified string
+	// IndexMergeIntersection indicates whether the plan contains any intersection type index merge reader. If so,
+	// IndexMergeIntersectionEstRows and IndexMergeIntersectionActRows are the estimated and actual rows of the
+	// intersections, which are the feedback of the estimation of the intersection size.
+	IndexMergeIntersection        bool
+	IndexMergeIntersectionEstRows float64
+	IndexMergeIntersectionActRows int64
+	// UsedPseudoStats and UsedOutdatedStats indicate whether any table is estimated by the pseudo stats or the
+	// outdated stats, see stmtctx.UsedStatsInfoForTable.
+	UsedPseudoStats   bool
+	UsedOutdatedStats bool
+	// MaxEstimationError is the max q-error of the row count estimations of the operators, which is 0 if the
+	// estimations are not comparable with the actual rows.
+	MaxEstimationError float64
+	// StatsVersions is the versions of the stats of the tables, in the same format as the Stats field of the slow log.
+	StatsVersions string
 }
 
 // newStmtSummaryByDigestMap creates an empty stmtSummaryByDigestMap.
@@ -398,6 +424,13 @@ type BindableStmt struct {
 	Charset   string
 	Collation string
 	Users     map[string]struct{} // which users have processed this stmt
+
+	// The fields below are used to find the regressed plans of the same statement.
+	SQLDigest  string
+	PlanDigest string
+	ExecCount  int64
+	AvgLatency time.Duration
+	LastSeen   time.Time
 }
 
 // GetMoreThanCntBindableStmt gets users' select/update/delete SQLs that occurred more than the specified count.
@@ -420,12 +453,19 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanCntBindableStmt(cnt int64) []*Bi
 					// Empty auth users means that it is an internal queries.
 					if len(ssElement.authUsers) > 0 && (int64(ssbd.history.Len()) > cnt || ssElement.execCount > cnt) {
 						stmt := &BindableStmt{
-							Schema:    ssbd.schemaName,
-							Query:     ssElement.sampleSQL,
-							PlanHint:  ssElement.planHint,
-							Charset:   ssElement.charset,
-							Collation: ssElement.collation,
-							Users:     maps.Clone(ssElement.authUsers),
+							Schema:     ssbd.schemaName,
+							Query:      ssElement.sampleSQL,
+							PlanHint:   ssElement.planHint,
+							Charset:    ssElement.charset,
+							Collation:  ssElement.collation,
+							Users:      maps.Clone(ssElement.authUsers),
+							SQLDigest:  ssbd.digest,
+							PlanDigest: ssbd.planDigest,
+							ExecCount:  ssElement.execCount,
+							LastSeen:   ssElement.lastSeen,
+						}
+						if ssElement.execCount > 0 {
+							stmt.AvgLatency = ssElement.sumLatency / time.Duration(ssElement.execCount)
 						}
 						// If it is SQL command prepare / execute, the ssElement.sampleSQL is `execute ...`, we should get the original select query.
 						// If it is binary protocol prepare / execute, ssbd.normalizedSQL should be same as ssElement.sampleSQL.
@@ -874,6 +914,25 @@ func (ssElement *stmtSummaryByDigestElement) add(sei *StmtExecInfo, intervalSeco
 		ssElement.lastPlanCacheUnqualified = sei.PlanCacheUnqualified
 	}
 
+	// index merge
+	if sei.IndexMergeIntersection {
+		ssElement.indexMergeIntersectionCount++
+		ssElement.sumIndexMergeIntersectionEstRows += sei.IndexMergeIntersectionEstRows
+		ssElement.sumIndexMergeIntersectionActRows += sei.IndexMergeIntersectionActRows
+	}
+
+	// stats quality
+	if sei.UsedPseudoStats {
+		ssElement.pseudoStatsCount++
+	}
+	if sei.UsedOutdatedStats {
+		ssElement.outdatedStatsCount++
+	}
+	ssElement.maxEstimationError = max(ssElement.maxEstimationError, sei.MaxEstimationError)
+	if sei.StatsVersions != "" {
+		ssElement.lastStatsVersions = sei.StatsVersions
+	}
+
 	// SPM
 	if sei.PlanInBinding {
 		ssElement.planInBinding = true
@@ -123,6 +123,12 @@ const (
 	PlanCacheHitsStr                  = "PLAN_CACHE_HITS"
 	PlanCacheUnqualifiedStr           = "PLAN_CACHE_UNQUALIFIED"
 	PlanCacheUnqualifiedLastReasonStr = "PLAN_CACHE_UNQUALIFIED_LAST_REASON"
+	AvgIdxMergeIntersectEstRowsStr    = "AVG_INDEX_MERGE_INTERSECTION_EST_ROWS"
+	AvgIdxMergeIntersectActRowsStr    = "AVG_INDEX_MERGE_INTERSECTION_ACT_ROWS"
+	PseudoStatsCountStr               = "PSEUDO_STATS_COUNT"
+	OutdatedStatsCountStr             = "OUTDATED_STATS_COUNT"
+	MaxEstimationErrorStr             = "MAX_ESTIMATION_ERROR"
+	StatsVersionsStr                  = "STATS_VERSIONS"
 	PlanInBindingStr                  = "PLAN_IN_BINDING"
 	QuerySampleTextStr                = "QUERY_SAMPLE_TEXT"
 	PrevSampleTextStr                 = "PREV_SAMPLE_TEXT"
@@ -498,6 +504,24 @@ var columnFactoryMap = map[string]columnFactory{
 	PlanCacheUnqualifiedLastReasonStr: func(_ columnInfo, record *StmtRecord) any {
 		return record.PlanCacheUnqualifiedLastReason
 	},
+	AvgIdxMergeIntersectEstRowsStr: func(_ columnInfo, record *StmtRecord) any {
+		return avgSumFloat(record.SumIndexMergeIntersectionEstRows, record.IndexMergeIntersectionCount)
+	},
+	AvgIdxMergeIntersectActRowsStr: func(_ columnInfo, record *StmtRecord) any {
+		return avgFloat(record.SumIndexMergeIntersectionActRows, record.IndexMergeIntersectionCount)
+	},
+	PseudoStatsCountStr: func(_ columnInfo, record *StmtRecord) any {
+		return record.PseudoStatsCount
+	},
+	OutdatedStatsCountStr: func(_ columnInfo, record *StmtRecord) any {
+		return record.OutdatedStatsCount
+	},
+	MaxEstimationErrorStr: func(_ columnInfo, record *StmtRecord) any {
+		return record.MaxEstimationError
+	},
+	StatsVersionsStr: func(_ columnInfo, record *StmtRecord) any {
+		return record.LastStatsVersions
+	},
 }
 
 func makeColumnFactories(columns []*model.ColumnInfo) []columnFactory {
@@ -157,6 +157,17 @@ type StmtRecord struct {
 
 	PlanCacheUnqualifiedCount      int64  `json:"plan_cache_unqualified_count"`
 	PlanCacheUnqualifiedLastReason string `json:"plan_cache_unqualified_last_reason"` // the reason why this query is unqualified for the plan cache
+
+	// the estimated and actual rows of the intersections of the index merge readers
+	IndexMergeIntersectionCount      int64   `json:"index_merge_intersection_count"`
+	SumIndexMergeIntersectionEstRows float64 `json:"sum_index_merge_intersection_est_rows"`
+	SumIndexMergeIntersectionActRows int64   `json:"sum_index_merge_intersection_act_rows"`
+
+	// the quality of the stats used by the statements
+	PseudoStatsCount   int64   `json:"pseudo_stats_count"`
+	OutdatedStatsCount int64   `json:"outdated_stats_count"`
+	MaxEstimationError float64 `json:"max_estimation_error"`
+	LastStatsVersions  string  `json:"last_stats_versions"`
 }
 
 // NewStmtRecord creates a new StmtRecord from StmtExecInfo.
@@ -379,6 +390,23 @@ func (r *StmtRecord) Add(info *stmtsummary.StmtExecInfo) {
 		r.PlanCacheUnqualifiedCount++
 		r.PlanCacheUnqualifiedLastReason = info.PlanCacheUnqualified
 	}
+	// Index merge
+	if info.IndexMergeIntersection {
+		r.IndexMergeIntersectionCount++
+		r.SumIndexMergeIntersectionEstRows += info.IndexMergeIntersectionEstRows
+		r.SumIndexMergeIntersectionActRows += info.IndexMergeIntersectionActRows
+	}
+	// Stats quality
+	if info.UsedPseudoStats {
+		r.PseudoStatsCount++
+	}
+	if info.UsedOutdatedStats {
+		r.OutdatedStatsCount++
+	}
+	r.MaxEstimationError = max(r.MaxEstimationError, info.MaxEstimationError)
+	if info.StatsVersions != "" {
+		r.LastStatsVersions = info.StatsVersions
+	}
 	// SPM
 	if info.PlanInBinding {
 		r.PlanInBinding = true
@@ -559,6 +587,17 @@ func (r *StmtRecord) Merge(other *StmtRecord) {
 	if other.PlanCacheUnqualifiedLastReason != "" {
 		r.PlanCacheUnqualifiedLastReason = other.PlanCacheUnqualifiedLastReason
 	}
+	// Index merge
+	r.IndexMergeIntersectionCount += other.IndexMergeIntersectionCount
+	r.SumIndexMergeIntersectionEstRows += other.SumIndexMergeIntersectionEstRows
+	r.SumIndexMergeIntersectionActRows += other.SumIndexMergeIntersectionActRows
+	// Stats quality
+	r.PseudoStatsCount += other.PseudoStatsCount
+	r.OutdatedStatsCount += other.OutdatedStatsCount
+	r.MaxEstimationError = max(r.MaxEstimationError, other.MaxEstimationError)
+	if other.LastStatsVersions != "" {
+		r.LastStatsVersions = other.LastStatsVersions
+	}
 	// Other
 	r.SumAffectedRows += other.SumAffectedRows
 	r.SumMem += other.SumMem
@@ -354,12 +354,17 @@ func (s *StmtSummary) GetMoreThanCntBindableStmt(cnt int64) []*stmtsummary.Binda
 				record.StmtType == "Replace" {
 				if len(record.AuthUsers) > 0 && record.ExecCount > cnt {
 					stmt := &stmtsummary.BindableStmt{
-						Schema:    record.SchemaName,
-						Query:     record.SampleSQL,
-						PlanHint:  record.PlanHint,
-						Charset:   record.Charset,
-						Collation: record.Collation,
-						Users:     maps.Clone(record.AuthUsers),
+						Schema:     record.SchemaName,
+						Query:      record.SampleSQL,
+						PlanHint:   record.PlanHint,
+						Charset:    record.Charset,
+						Collation:  record.Collation,
+						Users:      maps.Clone(record.AuthUsers),
+						SQLDigest:  record.Digest,
+						PlanDigest: record.PlanDigest,
+						ExecCount:  record.ExecCount,
+						AvgLatency: record.SumLatency / time.Duration(record.ExecCount),
+						LastSeen:   record.LastSeen,
 					}
 
 					// If it is SQL command prepare / execute, the ssElement.sampleSQL
@@ -55,6 +55,25 @@ func TestStmtSummaryIndexAdvisor(t *testing.T) {
 			"b \"Column [b] appear in Equal or Range Predicate clause(s) in query: select `b` from `test` . `t` where `b` = ?\""))
 }
 
+func TestStmtSummaryIndexAdvisorForDigest(t *testing.T) {
+	setupStmtSummary()
+	defer closeStmtSummary()
+	store := testkit.CreateMockStore(t)
+	tk := newTestKitWithRoot(t, store)
+	tk.MustExec(`use test`)
+	tk.MustExec(`create table t (a int, b int, c int)`)
+
+	tk.MustQueryToErr(`recommend index run for digest 'unknown'`)
+
+	tk.MustQuery(`select a from t where a=1`)
+	tk.MustQuery(`select b from t where b=1`)
+	digest := tk.MustQuery(`select digest from information_schema.statements_summary where digest_text like 'select ` + "`b`" + `%'`).Rows()[0][0].(string)
+	rs := tk.MustQuery(`recommend index run for digest '` + digest + `'`).Rows()
+	require.Len(t, rs, 1)
+	require.Equal(t, "idx_b", rs[0][2])
+	require.Equal(t, "CREATE INDEX `idx_b` ON `test`.`t`(`b`)", rs[0][8])
+}
+
 func TestStmtSummaryIndexAdvisorNullSchema(t *testing.T) {
 	setupStmtSummary()
 	defer closeStmtSummary()
@@ -643,6 +662,31 @@ func TestPlanCacheUnqualified2(t *testing.T) {
 		"select ? from `t_system_unqualified_test` , `information_schema` . `tables` 1 1 PhysicalMemTable plan is un-cacheable"))
 }
 
+func TestStmtSummaryStatsQuality(t *testing.T) {
+	setupStmtSummary()
+	defer closeStmtSummary()
+
+	store := testkit.CreateMockStore(t)
+	tk := newTestKitWithRoot(t, store)
+	tk.MustExec(`use test`)
+	tk.MustExec(`create table t_stats_quality (a int)`)
+	tk.MustExec("set global tidb_enable_stmt_summary = 0")
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+
+	// The table has no stats, so it's estimated by the pseudo stats.
+	tk.MustQuery(`select * from t_stats_quality where a = 1`).Check(testkit.Rows())
+	tk.MustQuery("select exec_count, pseudo_stats_count, outdated_stats_count, stats_versions " +
+		"from information_schema.statements_summary where digest_text like 'select * from `t_stats_quality`%'").Check(testkit.Rows(
+		"1 1 0 t_stats_quality:pseudo"))
+
+	tk.MustExec(`insert into t_stats_quality values (1), (2), (3)`)
+	tk.MustExec(`analyze table t_stats_quality`)
+	tk.MustQuery(`select * from t_stats_quality where a = 2`).Check(testkit.Rows("2"))
+	tk.MustQuery("select exec_count, pseudo_stats_count, outdated_stats_count, stats_versions != 't_stats_quality:pseudo', max_estimation_error >= 1 " +
+		"from information_schema.statements_summary where digest_text like 'select * from `t_stats_quality`%'").Check(testkit.Rows(
+		"2 1 0 1 1"))
+}
+
 func TestPlanCacheUnqualified(t *testing.T) {
 	setupStmtSummary()
 	defer closeStmtSummary()
@@ -0,0 +1,269 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/pkg/util/logutil"
+	topsqlstate "github.com/pingcap/tidb/pkg/util/topsql/state"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+const (
+	// LocalDataSinkWindowSeconds is the length of the time window which the CPU time is aggregated into.
+	LocalDataSinkWindowSeconds = 60
+	// LocalDataSinkMaxWindows is the max number of time windows kept in memory.
+	LocalDataSinkMaxWindows = 60
+	// localDataSinkMaxSQLMetas is the max number of SQL metas kept in memory.
+	localDataSinkMaxSQLMetas = 10000
+)
+
+// CPUUsageRecord is the CPU time consumed by a SQL digest and a plan digest in a time window.
+type CPUUsageRecord struct {
+	WindowStart   time.Time
+	WindowEnd     time.Time
+	SQLDigest     []byte
+	PlanDigest    []byte
+	NormalizedSQL string
+	IsInternal    bool
+	CPUTimeMs     uint64
+	ExecCount     uint64
+}
+
+type cpuUsageKey struct {
+	sqlDigest  string
+	planDigest string
+}
+
+type cpuUsageValue struct {
+	cpuTimeMs uint64
+	execCount uint64
+}
+
+type localSQLMeta struct {
+	normalizedSQL string
+	isInternal    bool
+}
+
+// LocalDataSink aggregates the reported data into time windows and keeps them in memory,
+// so that the CPU usage of SQL digests can be queried from the system table.
+type LocalDataSink struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	registerer DataSinkRegisterer
+	registered *atomic.Bool
+
+	mu struct {
+		sync.RWMutex
+		// windows maps the start unix time of a window to the usage of each digest.
+		windows  map[uint64]map[cpuUsageKey]*cpuUsageValue
+		sqlMetas map[string]localSQLMeta
+	}
+}
+
+// NewLocalDataSink returns a new LocalDataSink.
+func NewLocalDataSink(registerer DataSinkRegisterer) *LocalDataSink {
+	ctx, cancel := context.WithCancel(context.Background())
+	ds := &LocalDataSink{
+		ctx:        ctx,
+		cancel:     cancel,
+		registerer: registerer,
+		registered: atomic.NewBool(false),
+	}
+	ds.mu.windows = make(map[uint64]map[cpuUsageKey]*cpuUsageValue)
+	ds.mu.sqlMetas = make(map[string]localSQLMeta)
+	return ds
+}
+
+// Start starts to run LocalDataSink.
+func (ds *LocalDataSink) Start() {
+	if err := ds.trySwitchRegistration(topsqlstate.GlobalState.LocalStorageEnable.Load()); err != nil {
+		logutil.BgLogger().Warn("failed to register local datasink", zap.String("category", "top-sql"), zap.Error(err))
+	}
+	go ds.run()
+}
+
+func (ds *LocalDataSink) run() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ds.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ds.trySwitchRegistration(topsqlstate.GlobalState.LocalStorageEnable.Load()); err != nil {
+				logutil.BgLogger().Warn("failed to register local datasink", zap.String("category", "top-sql"), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (ds *LocalDataSink) trySwitchRegistration(enable bool) error {
+	if !enable && ds.registered.Load() {
+		ds.registerer.Deregister(ds)
+		ds.registered.Store(false)
+		ds.reset()
+		return nil
+	}
+	if enable && !ds.registered.Load() {
+		if err := ds.registerer.Register(ds); err != nil {
+			return err
+		}
+		ds.registered.Store(true)
+	}
+	return nil
+}
+
+var _ DataSink = &LocalDataSink{}
+
+// TrySend implements the DataSink interface.
+func (ds *LocalDataSink) TrySend(data *ReportData, _ time.Time) error {
+	select {
+	case <-ds.ctx.Done():
+		return ds.ctx.Err()
+	default:
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	for _, meta := range data.SQLMetas {
+		if len(ds.mu.sqlMetas) >= localDataSinkMaxSQLMetas {
+			break
+		}
+		ds.mu.sqlMetas[string(meta.SqlDigest)] = localSQLMeta{
+			normalizedSQL: meta.NormalizedSql,
+			isInternal:    meta.IsInternalSql,
+		}
+	}
+	for _, record := range data.DataRecords {
+		key := cpuUsageKey{sqlDigest: string(record.SqlDigest), planDigest: string(record.PlanDigest)}
+		for _, item := range record.Items {
+			if item == nil {
+				continue
+			}
+			start := item.TimestampSec / LocalDataSinkWindowSeconds * LocalDataSinkWindowSeconds
+			window, ok := ds.mu.windows[start]
+			if !ok {
+				window = make(map[cpuUsageKey]*cpuUsageValue)
+				ds.mu.windows[start] = window
+			}
+			value, ok := window[key]
+			if !ok {
+				value = &cpuUsageValue{}
+				window[key] = value
+			}
+			value.cpuTimeMs += uint64(item.CpuTimeMs)
+			value.execCount += item.StmtExecCount
+		}
+	}
+	ds.evictWindows()
+	return nil
+}
+
+// evictWindows removes the oldest windows if there are too many. It must be called with the lock held.
+func (ds *LocalDataSink) evictWindows() {
+	if len(ds.mu.windows) <= LocalDataSinkMaxWindows {
+		return
+	}
+	starts := make([]uint64, 0, len(ds.mu.windows))
+	for start := range ds.mu.windows {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+	for _, start := range starts[:len(starts)-LocalDataSinkMaxWindows] {
+		delete(ds.mu.windows, start)
+	}
+	if len(ds.mu.sqlMetas) >= localDataSinkMaxSQLMetas {
+		ds.gcSQLMetas()
+	}
+}
+
+// gcSQLMetas removes the SQL metas which are not referenced by any window. It must be called with the lock held.
+func (ds *LocalDataSink) gcSQLMetas() {
+	inUse := make(map[string]struct{}, len(ds.mu.sqlMetas))
+	for _, window := range ds.mu.windows {
+		for key := range window {
+			inUse[key.sqlDigest] = struct{}{}
+		}
+	}
+	for digest := range ds.mu.sqlMetas {
+		if _, ok := inUse[digest]; !ok {
+			delete(ds.mu.sqlMetas, digest)
+		}
+	}
+}
+
+func (ds *LocalDataSink) reset() {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.mu.windows = make(map[uint64]map[cpuUsageKey]*cpuUsageValue)
+	ds.mu.sqlMetas = make(map[string]localSQLMeta)
+}
+
+// CPUUsageRecords returns the aggregated CPU usage records, ordered by the window start time
+// and then by the CPU time in descending order.
+func (ds *LocalDataSink) CPUUsageRecords() []CPUUsageRecord {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	records := make([]CPUUsageRecord, 0)
+	for start, window := range ds.mu.windows {
+		for key, value := range window {
+			record := CPUUsageRecord{
+				WindowStart: time.Unix(int64(start), 0),
+				WindowEnd:   time.Unix(int64(start+LocalDataSinkWindowSeconds), 0),
+				CPUTimeMs:   value.cpuTimeMs,
+				ExecCount:   value.execCount,
+			}
+			if len(key.sqlDigest) > 0 {
+				record.SQLDigest = []byte(key.sqlDigest)
+			}
+			if len(key.planDigest) > 0 {
+				record.PlanDigest = []byte(key.planDigest)
+			}
+			if meta, ok := ds.mu.sqlMetas[key.sqlDigest]; ok {
+				record.NormalizedSQL = meta.normalizedSQL
+				record.IsInternal = meta.isInternal
+			}
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].WindowStart.Equal(records[j].WindowStart) {
+			return records[i].WindowStart.Before(records[j].WindowStart)
+		}
+		return records[i].CPUTimeMs > records[j].CPUTimeMs
+	})
+	return records
+}
+
+// OnReporterClosing implements the DataSink interface.
+func (ds *LocalDataSink) OnReporterClosing() {
+	ds.cancel()
+}
+
+// Close stops the LocalDataSink and deregisters it.
+func (ds *LocalDataSink) Close() {
+	ds.cancel()
+
+	if ds.registered.Load() {
+		ds.registerer.Deregister(ds)
+		ds.registered.Store(false)
+	}
+}
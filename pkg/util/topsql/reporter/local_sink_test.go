@@ -0,0 +1,89 @@
+// Copyright 2024 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tipb/go-tipb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalDataSink(t *testing.T) {
+	ds := NewLocalDataSink(&mockSingleTargetDataSinkRegisterer{})
+	defer ds.Close()
+
+	require.NoError(t, ds.trySwitchRegistration(true))
+	require.True(t, ds.registered.Load())
+
+	err := ds.TrySend(&ReportData{
+		DataRecords: []tipb.TopSQLRecord{{
+			SqlDigest:  []byte("S1"),
+			PlanDigest: []byte("P1"),
+			Items: []*tipb.TopSQLRecordItem{
+				{TimestampSec: 60, CpuTimeMs: 10, StmtExecCount: 1},
+				{TimestampSec: 119, CpuTimeMs: 20, StmtExecCount: 2},
+				{TimestampSec: 120, CpuTimeMs: 5, StmtExecCount: 1},
+			},
+		}, {
+			SqlDigest: []byte("S2"),
+			Items: []*tipb.TopSQLRecordItem{
+				{TimestampSec: 61, CpuTimeMs: 100, StmtExecCount: 3},
+			},
+		}},
+		SQLMetas: []tipb.SQLMeta{
+			{SqlDigest: []byte("S1"), NormalizedSql: "SQL-1"},
+			{SqlDigest: []byte("S2"), NormalizedSql: "SQL-2", IsInternalSql: true},
+		},
+	}, time.Now())
+	require.NoError(t, err)
+
+	records := ds.CPUUsageRecords()
+	require.Len(t, records, 3)
+	require.Equal(t, int64(60), records[0].WindowStart.Unix())
+	require.Equal(t, int64(120), records[0].WindowEnd.Unix())
+	require.Equal(t, []byte("S2"), records[0].SQLDigest)
+	require.Nil(t, records[0].PlanDigest)
+	require.Equal(t, "SQL-2", records[0].NormalizedSQL)
+	require.True(t, records[0].IsInternal)
+	require.Equal(t, uint64(100), records[0].CPUTimeMs)
+	require.Equal(t, uint64(3), records[0].ExecCount)
+	require.Equal(t, []byte("S1"), records[1].SQLDigest)
+	require.Equal(t, []byte("P1"), records[1].PlanDigest)
+	require.Equal(t, "SQL-1", records[1].NormalizedSQL)
+	require.Equal(t, uint64(30), records[1].CPUTimeMs)
+	require.Equal(t, uint64(3), records[1].ExecCount)
+	require.Equal(t, int64(120), records[2].WindowStart.Unix())
+	require.Equal(t, uint64(5), records[2].CPUTimeMs)
+
+	// Only the latest windows are kept.
+	items := make([]*tipb.TopSQLRecordItem, 0, LocalDataSinkMaxWindows)
+	for i := 0; i < LocalDataSinkMaxWindows; i++ {
+		items = append(items, &tipb.TopSQLRecordItem{TimestampSec: uint64(180 + i*LocalDataSinkWindowSeconds), CpuTimeMs: 1})
+	}
+	err = ds.TrySend(&ReportData{
+		DataRecords: []tipb.TopSQLRecord{{SqlDigest: []byte("S1"), Items: items}},
+	}, time.Now())
+	require.NoError(t, err)
+	records = ds.CPUUsageRecords()
+	require.Len(t, records, LocalDataSinkMaxWindows)
+	require.Equal(t, int64(180), records[0].WindowStart.Unix())
+
+	// Disabling the local storage drops the collected data.
+	require.NoError(t, ds.trySwitchRegistration(false))
+	require.False(t, ds.registered.Load())
+	require.Len(t, ds.CPUUsageRecords(), 0)
+}
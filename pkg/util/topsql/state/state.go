@@ -23,6 +23,7 @@ const (
 	DefTiDBTopSQLMaxTimeSeriesCount    = 100
 	DefTiDBTopSQLMaxMetaCount          = 5000
 	DefTiDBTopSQLReportIntervalSeconds = 60
+	DefTiDBTopSQLLocalStorageEnable    = false
 )
 
 // GlobalState is the global Top-SQL state.
@@ -32,6 +33,7 @@ var GlobalState = State{
 	MaxStatementCount:     atomic.NewInt64(DefTiDBTopSQLMaxTimeSeriesCount),
 	MaxCollect:            atomic.NewInt64(DefTiDBTopSQLMaxMetaCount),
 	ReportIntervalSeconds: atomic.NewInt64(DefTiDBTopSQLReportIntervalSeconds),
+	LocalStorageEnable:    atomic.NewBool(DefTiDBTopSQLLocalStorageEnable),
 }
 
 // State is the state for control top sql feature.
@@ -46,6 +48,8 @@ type State struct {
 	MaxCollect *atomic.Int64
 	// The report data interval of top-sql.
 	ReportIntervalSeconds *atomic.Int64
+	// Whether to keep the per-digest CPU usage in the local system table.
+	LocalStorageEnable *atomic.Bool
 }
 
 // EnableTopSQL enables the top SQL feature.
@@ -42,12 +42,14 @@ const (
 var (
 	globalTopSQLReport   reporter.TopSQLReporter
 	singleTargetDataSink *reporter.SingleTargetDataSink
+	localDataSink        *reporter.LocalDataSink
 )
 
 func init() {
 	remoteReporter := reporter.NewRemoteTopSQLReporter(plancodec.DecodeNormalizedPlan, plancodec.Compress)
 	globalTopSQLReport = remoteReporter
 	singleTargetDataSink = reporter.NewSingleTargetDataSink(remoteReporter)
+	localDataSink = reporter.NewLocalDataSink(remoteReporter)
 }
 
 // SetupTopSQL sets up the top-sql worker.
@@ -55,6 +57,7 @@ func SetupTopSQL(updater collector.ProcessCPUTimeUpdater) {
 	globalTopSQLReport.BindProcessCPUTimeUpdater(updater)
 	globalTopSQLReport.Start()
 	singleTargetDataSink.Start()
+	localDataSink.Start()
 
 	stmtstats.RegisterCollector(globalTopSQLReport)
 	stmtstats.SetupAggregator()
@@ -73,9 +76,15 @@ func RegisterPubSubServer(s *grpc.Server) {
 	}
 }
 
+// CPUUsageRecords returns the CPU usage of SQL digests kept by the local data sink.
+func CPUUsageRecords() []reporter.CPUUsageRecord {
+	return localDataSink.CPUUsageRecords()
+}
+
 // Close uses to close and release the top sql resource.
 func Close() {
 	singleTargetDataSink.Close()
+	localDataSink.Close()
 	globalTopSQLReport.Close()
 	stmtstats.CloseAggregator()
 }
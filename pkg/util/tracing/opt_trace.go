@@ -232,10 +232,25 @@ type OptimizeTracer struct {
 	Physical *PhysicalOptimizeTracer `json:"physical"`
 	// FinalPlan indicates the plan after post optimize
 	FinalPlan []*PlanTrace `json:"final"`
+	// Selectivity indicates the selectivity computations on the columns and the indexes
+	Selectivity []*SelectivityTraceRecord `json:"selectivity"`
 	// IsFastPlan indicates whether the plan is generated by fast plan
 	IsFastPlan bool `json:"isFastPlan"`
 }
 
+// SelectivityTraceRecord records a selectivity computation of a predicate, the method used to estimate it and the
+// result.
+type SelectivityTraceRecord struct {
+	TableName   string   `json:"table_name"`
+	Columns     []string `json:"columns,omitempty"`
+	Index       string   `json:"index,omitempty"`
+	Predicate   string   `json:"predicate"`
+	Method      string   `json:"method"`
+	TableID     int64    `json:"-"`
+	RowCount    float64  `json:"row_count"`
+	Selectivity float64  `json:"selectivity"`
+}
+
 // SetFastPlan sets fast plan
 func (tracer *OptimizeTracer) SetFastPlan(final *PlanTrace) {
 	tracer.FinalPlan = toFlattenPlanTrace(final)
@@ -697,3 +697,35 @@ func TestIssue55808(t *testing.T) {
 	err := tk.ExecToErr("alter table t add index idx(a);")
 	require.ErrorContains(t, err, "injected error")
 }
+
+func TestAddIndexIngestBuildsIndexStats(t *testing.T) {
+	store := realtikvtest.CreateMockStoreAndSetup(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("drop database if exists addindexlit;")
+	tk.MustExec("create database addindexlit;")
+	tk.MustExec("use addindexlit;")
+	tk.MustExec(`set global tidb_ddl_enable_fast_reorg=on;`)
+	tk.MustExec("set global tidb_enable_dist_task = off;")
+
+	tk.MustExec("create table t (a int, b varchar(20));")
+	for i := 0; i < 10; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, 'b%d'), (%d, 'b%d'), (null, null);", i, i%5, i, i%5))
+	}
+	tk.MustExec("analyze table t;")
+	tk.MustExec("alter table t add index idx_a(a);")
+	tk.MustExec("alter table t add index idx_ab(a, b(1));")
+
+	rows := tk.MustQuery("admin show ddl jobs 1;").Rows()
+	require.Len(t, rows, 1)
+	jobTp := rows[0][12].(string)
+	require.True(t, strings.Contains(jobTp, "ingest"), jobTp)
+
+	// The stats of the indexes are built by the backfill, the row count of the table is kept.
+	tk.MustQuery("select distinct_count, null_count, stats_ver from mysql.stats_histograms h " +
+		"join information_schema.tidb_indexes i on h.hist_id = i.index_id " +
+		"where h.is_index = 1 and h.table_id = (select tidb_table_id from information_schema.tables where table_schema = 'addindexlit' and table_name = 't') " +
+		"and i.table_schema = 'addindexlit' and i.table_name = 't' and i.seq_in_index = 1 order by i.key_name").
+		Check(testkit.Rows("10 10 2", "11 0 2"))
+	tk.MustQuery("select count from mysql.stats_meta where table_id = (select tidb_table_id from information_schema.tables where table_schema = 'addindexlit' and table_name = 't')").
+		Check(testkit.Rows("30"))
+}
@@ -151,7 +151,7 @@ func TestBackfillOperators(t *testing.T) {
 		reorgMeta := ddl.NewDDLReorgMeta(tk.Session())
 		ingestOp := ddl.NewIndexIngestOperator(
 			opCtx, copCtx, mockBackendCtx, sessPool, pTbl, []table.Index{index}, []ingest.Engine{mockEngine},
-			srcChkPool, 3, reorgMeta, nil, &ddl.EmptyRowCntListener{})
+			srcChkPool, 3, reorgMeta, nil, &ddl.EmptyRowCntListener{}, nil)
 		sink := testutil.NewOperatorTestSink[ddl.IndexWriteResult]()
 
 		operator.Compose[ddl.IndexRecordChunk](src, ingestOp)
@@ -206,6 +206,7 @@ func TestBackfillOperatorPipeline(t *testing.T) {
 		2,
 		nil,
 		&ddl.EmptyRowCntListener{},
+		nil,
 	)
 	require.NoError(t, err)
 	err = pipeline.Execute()
@@ -301,6 +302,7 @@ func TestBackfillOperatorPipelineException(t *testing.T) {
 				2,
 				nil,
 				&ddl.EmptyRowCntListener{},
+				nil,
 			)
 			require.NoError(t, err)
 			err = pipeline.Execute()
@@ -408,7 +410,7 @@ func TestTuneWorkerPoolSize(t *testing.T) {
 		mockEngine := ingest.NewMockEngineInfo(nil)
 		ingestOp := ddl.NewIndexIngestOperator(opCtx, copCtx, mockBackendCtx, sessPool, pTbl, []table.Index{index},
 			[]ingest.Engine{mockEngine}, nil, 2, nil,
-			nil, &ddl.EmptyRowCntListener{})
+			nil, &ddl.EmptyRowCntListener{}, nil)
 
 		ingestOp.Open()
 		require.Equal(t, ingestOp.GetWorkerPoolSize(), int32(2))
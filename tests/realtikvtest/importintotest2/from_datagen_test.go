@@ -0,0 +1,33 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importintotest
+
+import (
+	"github.com/pingcap/tidb/pkg/testkit"
+)
+
+func (s *mockGCSSuite) TestImportFromDatagen() {
+	s.prepareAndUseDB("from_datagen")
+	s.tk.MustExec("create table t(id bigint primary key, a int, b varchar(64))")
+	s.tk.MustExec(`import into t FROM DATAGEN(ROWS = 1000, id = 'seq', a = 'uniform(1, 10)')`)
+	s.Equal(uint64(1000), s.tk.Session().GetSessionVars().StmtCtx.AffectedRows())
+	s.Contains(s.tk.Session().LastMessage(), "Records: 1000,")
+	s.tk.MustQuery("select count(*), count(distinct id), min(a) >= 1, max(a) <= 10, count(b) from t").
+		Check(testkit.Rows("1000 1000 1 1 1000"))
+
+	// CREATE TABLE ... AS DATAGEN is the same as IMPORT INTO ... FROM DATAGEN.
+	s.tk.MustExec(`create table t2(id bigint primary key, a int) AS DATAGEN(ROWS = 100, id = 'seq')`)
+	s.tk.MustQuery("select count(*), count(distinct id) from t2").Check(testkit.Rows("100 100"))
+}